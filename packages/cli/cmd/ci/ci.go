@@ -0,0 +1,151 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/gate"
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type CICommand struct {
+	logger *utils.Logger
+}
+
+func NewCICommand(logger *utils.Logger) *cobra.Command {
+	cc := &CICommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "ci",
+		Short: "CI/CD helpers",
+		Long: `Commands for running Sentra Lab in CI pipelines.
+
+Commands:
+  • gate   - Enforce cost, latency and pass rate thresholds on the latest run
+
+Example:
+  sentra lab test && sentra lab ci gate --max-cost 0.50 --min-pass-rate 100`,
+	}
+
+	cmd.AddCommand(newGateCommand(cc))
+
+	return cmd
+}
+
+func newGateCommand(cc *CICommand) *cobra.Command {
+	var (
+		maxCost       float64
+		maxP95Latency string
+		minPassRate   float64
+		resultsPath   string
+		format        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gate",
+		Short: "Fail the pipeline when the latest run exceeds thresholds",
+		Long: `Evaluate the latest 'sentra lab test' run against thresholds.
+
+Checks (only those given are evaluated):
+  --max-cost          Total simulated cost in USD
+  --max-p95-latency   95th percentile scenario duration (e.g. 8s, 1500ms)
+  --min-pass-rate     Percentage of scenarios that passed (0-100)
+
+The report is written to stdout as JSON (or text with --format text).
+Exit codes:
+  0  every threshold met
+  1  the gate could not be evaluated (no run recorded, bad flags)
+  2  one or more thresholds violated
+
+Example:
+  sentra lab ci gate --max-cost 0.50 --max-p95-latency 8s --min-pass-rate 100`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var thresholds gate.Thresholds
+
+			if cmd.Flags().Changed("max-cost") {
+				if maxCost < 0 {
+					return fmt.Errorf("--max-cost cannot be negative")
+				}
+				thresholds.MaxCostUSD = &maxCost
+			}
+
+			if cmd.Flags().Changed("max-p95-latency") {
+				latency, err := time.ParseDuration(maxP95Latency)
+				if err != nil || latency <= 0 {
+					return fmt.Errorf("invalid --max-p95-latency %q (expected e.g. 8s or 1500ms)", maxP95Latency)
+				}
+				thresholds.MaxP95Latency = &latency
+			}
+
+			if cmd.Flags().Changed("min-pass-rate") {
+				if minPassRate < 0 || minPassRate > 100 {
+					return fmt.Errorf("--min-pass-rate must be between 0 and 100")
+				}
+				thresholds.MinPassRate = &minPassRate
+			}
+
+			if thresholds.MaxCostUSD == nil && thresholds.MaxP95Latency == nil && thresholds.MinPassRate == nil {
+				return fmt.Errorf("no thresholds given (use --max-cost, --max-p95-latency or --min-pass-rate)")
+			}
+
+			if format != "json" && format != "text" {
+				return fmt.Errorf("invalid --format %q (must be json or text)", format)
+			}
+
+			run, err := results.Load(resultsPath)
+			if err != nil {
+				return err
+			}
+
+			report := gate.Evaluate(run, thresholds)
+
+			if format == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+			} else {
+				cc.printReport(report)
+			}
+
+			if !report.Passed {
+				// Exit directly so the JSON report stays the only output
+				os.Exit(gate.ExitViolation)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Float64Var(&maxCost, "max-cost", 0, "Maximum total cost in USD")
+	cmd.Flags().StringVar(&maxP95Latency, "max-p95-latency", "", "Maximum p95 scenario duration (e.g. 8s)")
+	cmd.Flags().Float64Var(&minPassRate, "min-pass-rate", 0, "Minimum pass rate in percent")
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Recorded run to evaluate")
+	cmd.Flags().StringVar(&format, "format", "json", "Output format (json, text)")
+
+	return cmd
+}
+
+func (cc *CICommand) printReport(report *gate.Report) {
+	for _, check := range report.Checks {
+		if check.Passed {
+			cc.logger.Info(fmt.Sprintf("✓ %s: %s", check.Name, check.Message))
+		} else {
+			cc.logger.Error(fmt.Sprintf("✗ %s: %s", check.Name, check.Message))
+		}
+	}
+
+	cc.logger.Info("")
+	if report.Passed {
+		cc.logger.Info("✅ CI gate passed")
+	} else {
+		cc.logger.Error(fmt.Sprintf("❌ CI gate failed: %d threshold(s) violated", len(report.Violations())))
+	}
+}
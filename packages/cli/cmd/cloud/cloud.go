@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sentra-lab/cli/internal/utils"
@@ -18,7 +19,7 @@ type CloudCommand struct {
 
 func NewCloudCommand(logger *utils.Logger) *cobra.Command {
 	cc := &CloudCommand{
-		logger: logger,
+		logger: logger.Subsystem(utils.SubsystemCloud),
 	}
 
 	cmd := &cobra.Command{
@@ -173,7 +174,7 @@ Example:
 			uploaded := 0
 			for _, runID := range runIDs {
 				if err := cc.syncClient.PushRun(ctx, runID); err != nil {
-					cc.logger.Warn(fmt.Sprintf("Failed to upload %s: %v", runID, err))
+					cc.logger.Warn("Failed to upload run", "run_id", runID, "error", err)
 					continue
 				}
 				uploaded++
@@ -240,7 +241,7 @@ Example:
 				downloaded := 0
 				for _, run := range runs {
 					if err := cc.syncClient.PullRun(ctx, run.ID); err != nil {
-						cc.logger.Warn(fmt.Sprintf("Failed to download %s: %v", run.ID, err))
+						cc.logger.Warn("Failed to download run", "run_id", run.ID, "error", err)
 						continue
 					}
 					downloaded++
@@ -287,11 +288,11 @@ func newListCommand(cc *CloudCommand) *cobra.Command {
 
 			for _, run := range runs {
 				icon := "✓"
-				color := "\033[32m"
+				color := utils.ColorGreen
 
 				if run.Status == "failed" {
 					icon = "✗"
-					color = "\033[31m"
+					color = utils.ColorRed
 				}
 
 				timeAgo := formatTimeAgo(run.UploadedAt)
@@ -300,10 +301,11 @@ func newListCommand(cc *CloudCommand) *cobra.Command {
 					uploadedBy = "unknown"
 				}
 
-				fmt.Printf("%s%s %s\033[0m  %-30s  %s (by %s)\n",
+				fmt.Printf("%s%s %s%s  %-30s  %s (by %s)\n",
 					color,
 					icon,
 					run.ID,
+					utils.ColorReset,
 					run.Scenario,
 					timeAgo,
 					uploadedBy,
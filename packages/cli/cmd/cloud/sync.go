@@ -180,7 +180,7 @@ func (sc *SyncClient) Sync(ctx context.Context) (*SyncStats, error) {
 	for _, runID := range localRuns {
 		if _, exists := cloudRunMap[runID]; !exists {
 			if err := sc.PushRun(ctx, runID); err != nil {
-				sc.logger.Warn(fmt.Sprintf("Failed to upload %s: %v", runID, err))
+				sc.logger.Warn("Failed to upload run", "run_id", runID, "error", err)
 				continue
 			}
 			stats.Uploaded++
@@ -195,7 +195,7 @@ func (sc *SyncClient) Sync(ctx context.Context) (*SyncStats, error) {
 	for _, run := range cloudRuns {
 		if !localRunMap[run.ID] {
 			if err := sc.PullRun(ctx, run.ID); err != nil {
-				sc.logger.Warn(fmt.Sprintf("Failed to download %s: %v", run.ID, err))
+				sc.logger.Warn("Failed to download run", "run_id", run.ID, "error", err)
 				continue
 			}
 			stats.Downloaded++
@@ -1,8 +1,19 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"regexp"
 	"strings"
+	"time"
+)
+
+var (
+	localePattern   = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z]{2})?$`)
+	currencyPattern = regexp.MustCompile(`^[a-zA-Z]{3}$`)
+	digestPattern   = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
 )
 
 type ValidationError struct {
@@ -40,6 +51,8 @@ func (v *Validator) Validate(cfg interface{}) error {
 	v.validateAgent(data)
 	v.validateMocks(data)
 	v.validateSimulation(data)
+	v.validateNotifications(data)
+	v.validateEgress(data)
 	v.validateStorage(data)
 
 	if len(v.errors) > 0 {
@@ -92,6 +105,26 @@ func (v *Validator) validateAgent(data map[string]interface{}) {
 				"Use format: 30s, 5m, 1h")
 		}
 	}
+
+	if limits, ok := agent["limits"].(map[string]interface{}); ok {
+		for _, field := range []string{"cpu_time", "wall_time"} {
+			value, ok := limits[field]
+			if !ok {
+				continue
+			}
+			if raw, isString := value.(string); !isString || !isValidDuration(raw) {
+				v.addError("agent.limits."+field, fmt.Sprintf("invalid duration: %v", value),
+					"Use format: 30s, 5m, 1h")
+			}
+		}
+
+		if memory, ok := limits["memory_mb"]; ok {
+			if mb, isInt := memory.(int); !isInt || mb < 64 {
+				v.addError("agent.limits.memory_mb", fmt.Sprintf("invalid value: %v", memory),
+					"Use a whole number of megabytes, at least 64")
+			}
+		}
+	}
 }
 
 func (v *Validator) validateMocks(data map[string]interface{}) {
@@ -138,6 +171,89 @@ func (v *Validator) validateMocks(data map[string]interface{}) {
 						"Use value between 0.0 and 1.0")
 				}
 			}
+
+			if modelsFile, ok := mockData["models_file"].(string); ok && modelsFile != "" {
+				if mockName != "openai" {
+					v.addError(fmt.Sprintf("mocks.%s.models_file", mockName),
+						"custom models are only supported by the openai mock",
+						"Move models_file under mocks.openai")
+				} else if _, err := os.Stat(modelsFile); err != nil {
+					v.addError(fmt.Sprintf("mocks.%s.models_file", mockName),
+						fmt.Sprintf("cannot read %s", modelsFile),
+						"Create the file or fix the path (relative to lab.yaml)")
+				}
+			}
+
+			if strategy, ok := mockData["synthesis_strategy"].(string); ok && strategy != "" {
+				if mockName != "openai" {
+					v.addError(fmt.Sprintf("mocks.%s.synthesis_strategy", mockName),
+						"response synthesis is only supported by the openai mock",
+						"Move synthesis_strategy under mocks.openai")
+				} else if !contains([]string{"auto", "echo", "list", "code", "refusal"}, strategy) {
+					v.addError(fmt.Sprintf("mocks.%s.synthesis_strategy", mockName),
+						fmt.Sprintf("invalid strategy: %s", strategy),
+						"Use one of: auto, echo, list, code, refusal")
+				}
+			}
+
+			if jitter, ok := mockData["jitter"].(string); ok && jitter != "" {
+				if mockName != "openai" {
+					v.addError(fmt.Sprintf("mocks.%s.jitter", mockName),
+						"jitter distributions are only supported by the openai mock",
+						"Move jitter under mocks.openai")
+				} else if !contains([]string{"uniform", "gaussian", "exponential", "longtail"}, jitter) {
+					v.addError(fmt.Sprintf("mocks.%s.jitter", mockName),
+						fmt.Sprintf("invalid distribution: %s", jitter),
+						"Use one of: uniform, gaussian, exponential, longtail")
+				}
+			}
+
+			if maxStreams, ok := mockData["max_streams_per_key"].(int); ok && maxStreams != 0 {
+				if mockName != "openai" {
+					v.addError(fmt.Sprintf("mocks.%s.max_streams_per_key", mockName),
+						"stream limits are only supported by the openai mock",
+						"Move max_streams_per_key under mocks.openai")
+				} else if maxStreams < 0 {
+					v.addError(fmt.Sprintf("mocks.%s.max_streams_per_key", mockName),
+						"cannot be negative",
+						"Use a positive limit, or 0 for unlimited")
+				}
+			}
+
+			if skew, ok := mockData["webhook_timestamp_skew"]; ok {
+				raw, isString := skew.(string)
+				if mockName == "openai" {
+					v.addError(fmt.Sprintf("mocks.%s.webhook_timestamp_skew", mockName),
+						"the openai mock sends no webhooks",
+						"Move webhook_timestamp_skew under mocks.stripe")
+				} else if _, err := time.ParseDuration(raw); !isString || err != nil {
+					v.addError(fmt.Sprintf("mocks.%s.webhook_timestamp_skew", mockName),
+						fmt.Sprintf("invalid duration: %v", skew),
+						"Use a duration such as \"-10m\" (past) or \"30s\" (future)")
+				}
+			}
+
+			digest, _ := mockData["image_digest"].(string)
+			if digest != "" && !digestPattern.MatchString(digest) {
+				v.addError(fmt.Sprintf("mocks.%s.image_digest", mockName),
+					fmt.Sprintf("invalid digest: %s", digest),
+					"Use the image's content digest, e.g. from: docker inspect --format '{{index .RepoDigests 0}}' <image>")
+			}
+			if image, _ := mockData["image"].(string); strings.Contains(image, "@") {
+				digest = image[strings.Index(image, "@")+1:]
+			}
+
+			if key, ok := mockData["cosign_key"].(string); ok && key != "" {
+				if digest == "" {
+					v.addError(fmt.Sprintf("mocks.%s.cosign_key", mockName),
+						"signatures are only verified for pinned images",
+						"Set image_digest to the digest the signature covers")
+				} else if _, err := os.Stat(key); err != nil {
+					v.addError(fmt.Sprintf("mocks.%s.cosign_key", mockName),
+						fmt.Sprintf("cannot read %s", key),
+						"Create the file or fix the path (relative to lab.yaml)")
+				}
+			}
 		}
 	}
 }
@@ -160,6 +276,233 @@ func (v *Validator) validateSimulation(data map[string]interface{}) {
 				"Reduce to avoid resource exhaustion")
 		}
 	}
+
+	var contextWarning float64
+	switch value := simulation["context_warning"].(type) {
+	case int:
+		contextWarning = float64(value)
+	case float64:
+		contextWarning = value
+	}
+	if contextWarning < 0 || contextWarning > 1 {
+		v.addError("simulation.context_warning",
+			fmt.Sprintf("out of range: %g", contextWarning),
+			"Use a share of the context window, e.g. 0.8 for 80%")
+	}
+
+	v.validateBackgroundLoad(data, simulation)
+	v.validateLocale(simulation)
+	v.validateIsolation(simulation)
+	v.validateTracing(simulation)
+}
+
+func (v *Validator) validateTracing(simulation map[string]interface{}) {
+	tracing, ok := simulation["tracing"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	endpoint, _ := tracing["endpoint"].(string)
+	if endpoint == "" {
+		return
+	}
+
+	address := strings.TrimPrefix(strings.TrimPrefix(endpoint, "http://"), "https://")
+	if _, port, err := net.SplitHostPort(address); err != nil || port == "" {
+		v.addError("simulation.tracing.endpoint",
+			fmt.Sprintf("invalid address: %s", endpoint),
+			"Use the collector's OTLP gRPC host:port as the services see it, e.g. jaeger:4317")
+	}
+}
+
+func (v *Validator) validateIsolation(simulation map[string]interface{}) {
+	isolation, ok := simulation["isolation"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if reset, ok := isolation["reset"].(string); ok && !contains([]string{"scenario", "run", "none"}, reset) {
+		v.addError("simulation.isolation.reset",
+			fmt.Sprintf("invalid value: %s", reset),
+			"Use scenario, run or none")
+	}
+
+	states := []string{"usage", "rate_limits", "fixtures", "assistants", "threads", "images", "files", "fine_tuning", "overrides", "faults", "clock", "locale", "deadline"}
+	targets, _ := isolation["state"].([]interface{})
+	for _, target := range targets {
+		name, _ := target.(string)
+		if !contains(states, name) {
+			v.addError("simulation.isolation.state",
+				fmt.Sprintf("unknown state: %v", target),
+				fmt.Sprintf("Use any of: %s", strings.Join(states, ", ")))
+		}
+	}
+}
+
+func (v *Validator) validateLocale(simulation map[string]interface{}) {
+	settings, ok := simulation["locale"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if locale, ok := settings["locale"].(string); ok && !localePattern.MatchString(locale) {
+		v.addError("simulation.locale.locale",
+			fmt.Sprintf("invalid locale: %s", locale),
+			"Use a language tag such as en-US or de-DE")
+	}
+
+	if timezone, ok := settings["timezone"].(string); ok {
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+			v.addError("simulation.locale.timezone",
+				fmt.Sprintf("unknown timezone: %s", timezone),
+				"Use an IANA timezone such as UTC or Europe/Berlin")
+		}
+	}
+
+	if currency, ok := settings["currency"].(string); ok && !currencyPattern.MatchString(currency) {
+		v.addError("simulation.locale.currency",
+			fmt.Sprintf("invalid currency: %s", currency),
+			"Use an ISO 4217 code such as USD or EUR")
+	}
+}
+
+func (v *Validator) validateBackgroundLoad(data, simulation map[string]interface{}) {
+	load, ok := simulation["background_load"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if enabled, ok := load["enabled"].(bool); !ok || !enabled {
+		return
+	}
+
+	var rps float64
+	switch value := load["rps"].(type) {
+	case int:
+		rps = float64(value)
+	case float64:
+		rps = value
+	}
+	if rps <= 0 {
+		v.addError("simulation.background_load.rps",
+			"must be greater than 0",
+			"Set the synthetic request rate: rps: 20")
+	} else if rps > 1000 {
+		v.addError("simulation.background_load.rps",
+			fmt.Sprintf("too high: %g (max 1000)", rps),
+			"Reduce to avoid overwhelming the mocks")
+	}
+
+	if concurrency, ok := load["concurrency"].(int); ok && concurrency < 0 {
+		v.addError("simulation.background_load.concurrency",
+			"cannot be negative",
+			"Use a positive value or omit for the default (32)")
+	}
+
+	mocks, _ := data["mocks"].(map[string]interface{})
+	targets, _ := load["mocks"].([]interface{})
+	for _, target := range targets {
+		name, _ := target.(string)
+		if _, exists := mocks[name]; !exists {
+			v.addError("simulation.background_load.mocks",
+				fmt.Sprintf("unknown mock: %v", target),
+				"List mocks configured under mocks:")
+		}
+	}
+}
+
+func (v *Validator) validateNotifications(data map[string]interface{}) {
+	notifications, ok := data["notifications"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for i, raw := range notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+
+		notification, ok := raw.(map[string]interface{})
+		if !ok {
+			v.addError(field, "must be a mapping", "Use type, webhook_url and on")
+			continue
+		}
+
+		notificationType, _ := notification["type"].(string)
+		if !contains([]string{"slack", "teams"}, notificationType) {
+			v.addError(field+".type",
+				fmt.Sprintf("invalid type: %v", notification["type"]),
+				"Use one of: slack, teams")
+		}
+
+		if url, _ := notification["webhook_url"].(string); url == "" {
+			v.addError(field+".webhook_url",
+				"is required",
+				"Reference a CI secret: webhook_url: ${SLACK_WEBHOOK_URL}")
+		}
+
+		if on, ok := notification["on"]; ok {
+			if s, _ := on.(string); !contains([]string{"failure", "always"}, s) {
+				v.addError(field+".on",
+					fmt.Sprintf("invalid condition: %v", on),
+					"Use one of: failure, always")
+			}
+		}
+	}
+}
+
+func (v *Validator) validateEgress(data map[string]interface{}) {
+	egress, ok := data["egress"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if port, ok := egress["port"].(int); ok {
+		if port < 1024 || port > 65535 {
+			v.addError("egress.port",
+				fmt.Sprintf("invalid port: %d", port),
+				"Use port between 1024 and 65535")
+		}
+	}
+
+	if mode, ok := egress["mode"]; ok {
+		if s, _ := mode.(string); !contains([]string{"block", "warn"}, s) {
+			v.addError("egress.mode",
+				fmt.Sprintf("invalid mode: %v", mode),
+				"Use one of: block, warn")
+		}
+	}
+
+	if faults, ok := egress["faults"].(map[string]interface{}); ok {
+		for _, field := range []string{"drop_rate", "stall_rate"} {
+			if rate, ok := faults[field].(float64); ok && (rate < 0 || rate > 1) {
+				v.addError("egress.faults."+field,
+					fmt.Sprintf("invalid value: %f", rate),
+					"Use value between 0.0 and 1.0")
+			}
+		}
+
+		if stall, ok := faults["stall"]; ok {
+			if raw, isString := stall.(string); !isString || !isValidDuration(raw) {
+				v.addError("egress.faults.stall",
+					fmt.Sprintf("invalid duration: %v", stall),
+					"Use format: 30s, 1m")
+			}
+		}
+	}
+
+	routes, ok := egress["routes"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	mocks, _ := data["mocks"].(map[string]interface{})
+	for host, target := range routes {
+		name, _ := target.(string)
+		if _, exists := mocks[name]; !exists {
+			v.addError("egress.routes."+host,
+				fmt.Sprintf("unknown mock: %v", target),
+				"Route hosts to mocks configured under mocks:")
+		}
+	}
 }
 
 func (v *Validator) validateStorage(data map[string]interface{}) {
@@ -205,7 +548,7 @@ func (v *Validator) formatErrors() error {
 
 	builder.WriteString("\n📖 See: https://docs.sentra.dev/configuration\n")
 
-	return fmt.Errorf(builder.String())
+	return errors.New(builder.String())
 }
 
 func contains(slice []string, item string) bool {
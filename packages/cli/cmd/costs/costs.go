@@ -0,0 +1,360 @@
+package costs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/finops"
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/tokenusage"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type CostsCommand struct {
+	logger *utils.Logger
+}
+
+func NewCostsCommand(logger *utils.Logger) *cobra.Command {
+	cc := &CostsCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "costs",
+		Short: "Work with simulated costs",
+		Long: `Commands for simulated API costs.
+
+Commands:
+  • export   - Export costs as FOCUS or CSV for FinOps tooling
+  • context  - Context window utilization of each model call
+
+Example:
+  sentra lab costs export --format focus --output costs.csv`,
+	}
+
+	cmd.AddCommand(newExportCommand(cc))
+	cmd.AddCommand(newContextCommand(cc))
+
+	return cmd
+}
+
+func newExportCommand(cc *CostsCommand) *cobra.Command {
+	var (
+		format      string
+		output      string
+		environment string
+		resultsPath string
+		all         bool
+		scale       float64
+		tags        []string
+		warnAt      float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export simulated and projected costs for FinOps dashboards",
+		Long: `Export the costs the OpenAI mock tracked, tagged with project, scenario,
+model and environment, in a format finance tooling ingests:
+
+  focus   FOCUS 1.0 CSV (FinOps Open Cost and Usage Specification), one row
+          per model and token type (input, cached input, output)
+  csv     Flat CSV, one row per day, run and model
+
+By default the runs of the latest 'sentra lab test' are exported, with
+scenarios taken from its results; use --all for everything the mock has
+tracked. The mocks must still be running.
+
+Use --scale to add projected costs: each simulated run stands for that
+many production runs (e.g. --scale 30000 for 1,000 conversations a day
+over a month). Projected FOCUS rows have x_CostType=projected.
+
+Scenarios with model calls at or above --context-warning of the context
+window are warned about (see 'sentra lab costs context').
+
+Example:
+  sentra lab costs export --format focus --output costs.csv
+  sentra lab costs export --format csv --environment staging --tag team=payments
+  sentra lab costs export --scale 30000 --output projected.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !contains(finops.Formats, format) {
+				return fmt.Errorf("invalid --format %q (must be one of: %s)", format, strings.Join(finops.Formats, ", "))
+			}
+			if scale < 0 {
+				return fmt.Errorf("--scale cannot be negative")
+			}
+
+			opts := finops.Options{
+				Environment: environment,
+				Scenarios:   make(map[string]string),
+				Tags:        make(map[string]string),
+				Scale:       scale,
+			}
+			for _, tag := range tags {
+				key, value, ok := strings.Cut(tag, "=")
+				if !ok || key == "" {
+					return fmt.Errorf("invalid --tag %q (expected key=value)", tag)
+				}
+				opts.Tags[key] = value
+			}
+
+			cfg, mockURL, err := loadMock(cmd)
+			if err != nil {
+				return err
+			}
+			opts.Project = cfg.Name
+
+			warnAt, err = contextWarning(warnAt, cfg)
+			if err != nil {
+				return err
+			}
+
+			var runIDs []string
+			if run, err := results.Load(resultsPath); err == nil {
+				for _, scenario := range run.Scenarios {
+					if scenario.RunID == "" {
+						continue
+					}
+					opts.Scenarios[scenario.RunID] = scenario.Scenario
+					runIDs = append(runIDs, scenario.RunID)
+				}
+			} else if !all {
+				return err
+			}
+			if all {
+				runIDs = nil
+			} else if len(runIDs) == 0 {
+				return fmt.Errorf("the latest run in %s has no run IDs (use --all to export everything)", resultsPath)
+			}
+
+			entries, err := finops.Fetch(cmd.Context(), mockURL, runIDs)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				cc.logger.Warn("No costs were tracked for these runs")
+			}
+
+			var w io.Writer = os.Stdout
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", output, err)
+				}
+				defer file.Close()
+				w = file
+			}
+
+			if format == "focus" {
+				err = finops.WriteFOCUS(w, entries, opts)
+			} else {
+				err = finops.WriteCSV(w, entries, opts)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+
+			if output != "" {
+				cc.logger.Info(fmt.Sprintf("Exported %d cost entries to %s", len(entries), output))
+			}
+
+			for _, runID := range runIDs {
+				requests, err := tokenusage.FetchRequests(cmd.Context(), mockURL, runID, warnAt)
+				if err != nil {
+					cc.logger.Debug("Could not check context utilization", "run_id", runID, "error", err)
+					continue
+				}
+				if flagged := tokenusage.NearLimitRequests(requests); len(flagged) > 0 {
+					cc.logger.Warn(fmt.Sprintf("%s: %d model call(s) used at least %.0f%% of the context window (peak %.1f%%)",
+						opts.Scenarios[runID], len(flagged), warnAt*100, peakUtilization(flagged)*100))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "focus", "Export format (focus, csv)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+	cmd.Flags().StringVar(&environment, "environment", "simulation", "Environment tag")
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Run whose costs to export")
+	cmd.Flags().BoolVar(&all, "all", false, "Export every run the mock has tracked")
+	cmd.Flags().Float64Var(&scale, "scale", 0, "Add projected costs: production runs per simulated run")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Extra tag (key=value, repeatable)")
+	cmd.Flags().Float64Var(&warnAt, "context-warning", 0, "Share of the context window to warn at (default: simulation.context_warning)")
+
+	return cmd
+}
+
+func newContextCommand(cc *CostsCommand) *cobra.Command {
+	var (
+		resultsPath   string
+		format        string
+		warnAt        float64
+		nearLimitOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "context [scenario]",
+		Short: "Show how much of the context window each model call used",
+		Long: `Count the prompt of every model call in the latest run with the OpenAI
+mock's tokenizer and show how much of the model's context window it used.
+
+Calls at or above --context-warning (default simulation.context_warning,
+or 80% of the window) are flagged with ⚠: they are the calls that start
+failing once prompts grow in production.
+
+Requires simulation.record_full_trace, and the mocks must still be running.
+Give a scenario (or part of its name) to show only matching scenarios.
+
+Example:
+  sentra lab costs context
+  sentra lab costs context --context-warning 0.85
+  sentra lab costs context support-escalation --near-limit --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid --format %q (must be text or json)", format)
+			}
+
+			cfg, mockURL, err := loadMock(cmd)
+			if err != nil {
+				return err
+			}
+			warnAt, err = contextWarning(warnAt, cfg)
+			if err != nil {
+				return err
+			}
+
+			run, err := results.Load(resultsPath)
+			if err != nil {
+				return err
+			}
+
+			type scenarioRequests struct {
+				Scenario string               `json:"scenario"`
+				RunID    string               `json:"run_id"`
+				Requests []tokenusage.Request `json:"requests"`
+			}
+			var scenarios []scenarioRequests
+			matched, flagged := false, 0
+			for _, scenario := range run.Scenarios {
+				if len(args) == 1 && !strings.Contains(scenario.Scenario, args[0]) {
+					continue
+				}
+				matched = true
+				if scenario.RunID == "" {
+					continue
+				}
+
+				requests, err := tokenusage.FetchRequests(cmd.Context(), mockURL, scenario.RunID, warnAt)
+				if err != nil {
+					return err
+				}
+				if nearLimitOnly {
+					requests = tokenusage.NearLimitRequests(requests)
+					if len(requests) == 0 {
+						continue
+					}
+				}
+				flagged += len(tokenusage.NearLimitRequests(requests))
+				scenarios = append(scenarios, scenarioRequests{Scenario: scenario.Scenario, RunID: scenario.RunID, Requests: requests})
+			}
+
+			if len(args) == 1 && !matched {
+				return fmt.Errorf("no scenario matching %q in %s", args[0], resultsPath)
+			}
+
+			if format == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(scenarios)
+			}
+
+			if len(scenarios) == 0 {
+				cc.logger.Info("No model calls near the context window limit")
+				return nil
+			}
+
+			for i, scenario := range scenarios {
+				if i > 0 {
+					fmt.Println()
+				}
+				tokenusage.RenderRequests(os.Stdout, scenario.Scenario, scenario.Requests)
+			}
+
+			if flagged > 0 {
+				fmt.Printf("\n⚠️  %d model call(s) at or above %.0f%% of the context window\n", flagged, warnAt*100)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Recorded run to report on")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	cmd.Flags().Float64Var(&warnAt, "context-warning", 0, "Share of the context window to warn at (default: simulation.context_warning)")
+	cmd.Flags().BoolVar(&nearLimitOnly, "near-limit", false, "Only show model calls near the context window limit")
+
+	return cmd
+}
+
+// loadMock loads lab.yaml (or --config) and returns the OpenAI mock's URL.
+func loadMock(cmd *cobra.Command) (*config.Config, string, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+	loader, err := config.NewLoader(configPath)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	mock, ok := cfg.Mocks["openai"]
+	if !ok || !mock.Enabled {
+		return nil, "", fmt.Errorf("the OpenAI mock is not enabled in %s", configPath)
+	}
+	return cfg, fmt.Sprintf("http://localhost:%d", mock.Port), nil
+}
+
+// contextWarning resolves the context window warning threshold: the flag,
+// then simulation.context_warning, then the default.
+func contextWarning(flag float64, cfg *config.Config) (float64, error) {
+	if flag < 0 || flag > 1 {
+		return 0, fmt.Errorf("--context-warning must be between 0 and 1, got %g", flag)
+	}
+	if flag > 0 {
+		return flag, nil
+	}
+	if cfg.Simulation.ContextWarning > 0 {
+		return cfg.Simulation.ContextWarning, nil
+	}
+	return tokenusage.DefaultWarnUtilization, nil
+}
+
+// peakUtilization returns the highest utilization of the requests.
+func peakUtilization(requests []tokenusage.Request) float64 {
+	peak := 0.0
+	for _, request := range requests {
+		peak = max(peak, request.Utilization)
+	}
+	return peak
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
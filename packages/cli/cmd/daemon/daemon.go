@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/daemon"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type DaemonCommand struct {
+	logger  *utils.Logger
+	version string
+}
+
+func NewDaemonCommand(logger *utils.Logger, version string) *cobra.Command {
+	dc := &DaemonCommand{
+		logger:  logger,
+		version: version,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Keep Docker and config state warm for fast commands",
+		Long: `Run a per-project background daemon that stays connected to Docker and
+keeps lab.yaml loaded (reloading it when it changes). 'sentra lab status' and
+'sentra lab logs' use it when it is running, answering in milliseconds
+instead of seconds, and fall back to working on their own when it is not.
+
+The daemon listens on a unix socket that only the current user can reach.
+
+Commands:
+  • start    - Start the daemon in the background
+  • stop     - Stop it
+  • status   - Show whether it is running
+  • serve    - Run it in the foreground
+
+Example:
+  sentra lab daemon start
+  sentra lab status          # served by the daemon
+  sentra lab daemon stop`,
+	}
+
+	cmd.AddCommand(newStartCommand(dc))
+	cmd.AddCommand(newStopCommand(dc))
+	cmd.AddCommand(newStatusCommand(dc))
+	cmd.AddCommand(newServeCommand(dc))
+
+	return cmd
+}
+
+func configPath(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		return "lab.yaml"
+	}
+	return path
+}
+
+func newStartCommand(dc *DaemonCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the daemon in the background",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := daemon.StartBackground(configPath(cmd))
+			if err != nil {
+				return err
+			}
+
+			dc.logger.Info(fmt.Sprintf("✓ Daemon running (pid %d)", client.Info.PID))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newStopCommand(dc *DaemonCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := daemon.Stop(cmd.Context()); err != nil {
+				if err == daemon.ErrNotRunning {
+					dc.logger.Info("Daemon is not running")
+					return nil
+				}
+				return fmt.Errorf("failed to stop daemon: %w", err)
+			}
+
+			dc.logger.Info("✓ Daemon stopped")
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newStatusCommand(dc *DaemonCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the daemon is running",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := daemon.Connect()
+			if err != nil {
+				dc.logger.Info("Daemon is not running (start it with 'sentra lab daemon start')")
+				return nil
+			}
+
+			info := client.Info
+			fmt.Printf("PID:      %d\n", info.PID)
+			fmt.Printf("Version:  %s\n", info.Version)
+			fmt.Printf("Config:   %s\n", info.Config)
+			fmt.Printf("Socket:   %s\n", info.Socket)
+			fmt.Printf("Uptime:   %s\n", time.Since(info.StartedAt).Round(time.Second))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newServeCommand(dc *DaemonCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the daemon in the foreground",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return daemon.NewServer(dc.logger, configPath(cmd), dc.version).Serve(ctx)
+		},
+	}
+
+	return cmd
+}
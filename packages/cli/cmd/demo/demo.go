@@ -0,0 +1,137 @@
+package demo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/recordings"
+	"github.com/sentra-lab/cli/internal/ui"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type DemoCommand struct {
+	logger *utils.Logger
+	dir    string
+	keep   bool
+	noTUI  bool
+	run    string
+}
+
+func NewDemoCommand(logger *utils.Logger) *cobra.Command {
+	dc := &DemoCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Explore Sentra Lab with a seeded sample project",
+		Long: `Create a throwaway sample project and open its replay.
+
+The project comes pre-seeded with:
+  • lab.yaml, fixtures and three scenarios
+  • Three recorded runs, one of them failing
+  • Results for 'sentra lab ci gate'
+
+Nothing is started and nothing touches the network: no Docker, no engine,
+no agent. The replay opens on the failing run so you can step through
+what went wrong.
+
+By default the project is created in a temporary directory and removed
+when the replay exits. Use --keep or --dir to keep it and explore it with
+the other commands (replay, scenarios validate, ci gate).
+
+Example:
+  sentra lab demo
+  sentra lab demo --dir ./sentra-demo
+  sentra lab demo --no-tui --keep`,
+		Args: cobra.NoArgs,
+		RunE: dc.RunE,
+	}
+
+	cmd.Flags().StringVar(&dc.dir, "dir", "", "Create the project here instead of a temporary directory (kept)")
+	cmd.Flags().BoolVar(&dc.keep, "keep", false, "Keep the temporary project after exiting")
+	cmd.Flags().BoolVar(&dc.noTUI, "no-tui", false, "Seed the project and list its runs without opening the replay (kept)")
+	cmd.Flags().StringVar(&dc.run, "run", failingRunID, "Run to open in the replay")
+
+	return cmd
+}
+
+func (dc *DemoCommand) RunE(cmd *cobra.Command, args []string) error {
+	projectDir, temporary, err := dc.projectDir()
+	if err != nil {
+		return err
+	}
+
+	keep := !temporary || dc.keep || dc.noTUI
+	if !keep {
+		defer os.RemoveAll(projectDir)
+	}
+
+	runs, err := seedProject(projectDir, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to seed demo project: %w", err)
+	}
+
+	dc.logger.Info("🎬 Sentra Lab demo project", "path", projectDir)
+	dc.logger.Info("")
+	for _, run := range runs {
+		icon := ui.FormatSuccess(run.RunID)
+		if run.Status == "failed" {
+			icon = ui.FormatError(run.RunID)
+		}
+		fmt.Printf("  %s  %-28s  %s\n", icon, run.Scenario, run.Status)
+	}
+	dc.logger.Info("")
+
+	if keep {
+		dc.logger.Info("Explore it:")
+		dc.logger.Info(fmt.Sprintf("  cd %s", projectDir))
+		dc.logger.Info("  sentra lab replay --list")
+		dc.logger.Info(fmt.Sprintf("  sentra lab replay %s", failingRunID))
+		dc.logger.Info("  sentra lab scenarios validate")
+		dc.logger.Info("  sentra lab ci gate --min-pass-rate 100")
+		dc.logger.Info("")
+	}
+
+	if dc.noTUI {
+		return nil
+	}
+
+	recording, metadata, err := recordings.Load(filepath.Join(projectDir, recordingsDir), dc.run)
+	if err != nil {
+		return fmt.Errorf("failed to load demo run: %w", err)
+	}
+
+	dc.logger.Info(fmt.Sprintf("🔄 Replaying %s (%s, %s)", recording.ID, recording.Scenario, metadata.Status))
+	for _, failure := range metadata.Failures {
+		dc.logger.Info(fmt.Sprintf("  ✗ %s", failure))
+	}
+	dc.logger.Info("   [←/→] Step  [Space] Play/Pause  [Q] Quit")
+
+	return ui.RunReplayUI(ui.NewReplayModel(recording, 1.0, true, ""))
+}
+
+// projectDir returns the directory to seed and whether it is temporary.
+// An explicit --dir must not exist yet or be empty.
+func (dc *DemoCommand) projectDir() (string, bool, error) {
+	if dc.dir == "" {
+		dir, err := os.MkdirTemp("", "sentra-demo-")
+		if err != nil {
+			return "", false, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		return dir, true, nil
+	}
+
+	entries, err := os.ReadDir(dc.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", false, fmt.Errorf("failed to read %s: %w", dc.dir, err)
+	}
+	if len(entries) > 0 {
+		return "", false, fmt.Errorf("directory '%s' is not empty", dc.dir)
+	}
+
+	return dc.dir, false, nil
+}
@@ -0,0 +1,366 @@
+package demo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+	"github.com/sentra-lab/cli/internal/recordings"
+	"github.com/sentra-lab/cli/internal/results"
+)
+
+// recordingsDir is where the demo's runs are recorded, relative to the
+// project (the lab.yaml default).
+const recordingsDir = ".sentra-lab/recordings"
+
+// failingRunID is the seeded run whose scenario fails; the replay opens it.
+const failingRunID = "run-demo-003"
+
+// seedProject writes the sample project and its recorded runs to dir, with
+// the runs ending shortly before now. It returns the runs' metadata in the
+// order they ran.
+func seedProject(dir string, now time.Time) ([]recordings.Metadata, error) {
+	files := map[string]string{
+		"lab.yaml":                         demoLabYAML,
+		"agent.py":                         demoAgent,
+		"README.md":                        demoReadme,
+		"fixtures/openai-responses.yaml":   demoOpenAIFixtures,
+		"scenarios/order-status.yaml":      demoOrderStatusScenario,
+		"scenarios/refund-approved.yaml":   demoRefundApprovedScenario,
+		"scenarios/refund-over-limit.yaml": demoRefundOverLimitScenario,
+	}
+
+	for filename, content := range files {
+		path := filepath.Join(dir, filename)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %w", filename, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write file %s: %w", filename, err)
+		}
+	}
+
+	runs := demoRuns(now.Add(-10 * time.Minute))
+
+	run := &results.Run{StartedAt: runs[0].metadata.StartedAt}
+	var seeded []recordings.Metadata
+	for _, r := range runs {
+		if err := recordings.Save(filepath.Join(dir, recordingsDir), r.metadata, r.events); err != nil {
+			return nil, err
+		}
+
+		seeded = append(seeded, r.metadata)
+		run.FinishedAt = r.metadata.CompletedAt
+		run.Scenarios = append(run.Scenarios, results.ScenarioResult{
+			Scenario:   r.metadata.Scenario,
+			RunID:      r.metadata.RunID,
+			Status:     r.metadata.Status,
+			DurationMs: r.metadata.DurationMs,
+			CostUSD:    r.metadata.CostUSD,
+		})
+	}
+
+	if err := results.Save(filepath.Join(dir, results.LatestPath), run); err != nil {
+		return nil, err
+	}
+
+	return seeded, nil
+}
+
+// demoRun is a seeded run: its metadata and recorded events.
+type demoRun struct {
+	metadata recordings.Metadata
+	events   []*grpc.Event
+}
+
+// timeline builds a run's events at offsets from its start.
+type timeline struct {
+	runID  string
+	start  time.Time
+	events []*grpc.Event
+}
+
+func (t *timeline) add(offset time.Duration, eventType, service, summary string, data map[string]interface{}) {
+	t.events = append(t.events, &grpc.Event{
+		ID:        fmt.Sprintf("%s-evt-%03d", t.runID, len(t.events)+1),
+		Timestamp: t.start.Add(offset),
+		Type:      eventType,
+		Service:   service,
+		Summary:   summary,
+		Data:      data,
+	})
+}
+
+// run finishes the timeline at the given duration.
+func (t *timeline) run(scenario, status string, duration time.Duration, costUSD float64, failures ...string) demoRun {
+	return demoRun{
+		metadata: recordings.Metadata{
+			RunID:       t.runID,
+			Scenario:    scenario,
+			Status:      status,
+			StartedAt:   t.start,
+			CompletedAt: t.start.Add(duration),
+			DurationMs:  duration.Milliseconds(),
+			CostUSD:     costUSD,
+			Failures:    failures,
+		},
+		events: t.events,
+	}
+}
+
+// demoRuns returns three runs a minute apart from start: two passing, then
+// one where the agent refunds above its limit instead of escalating.
+func demoRuns(start time.Time) []demoRun {
+	orderStatus := &timeline{runID: "run-demo-001", start: start}
+	orderStatus.add(0, "input_received", "agent", "Where is order #1042?", map[string]interface{}{
+		"input": "Where is order #1042?",
+	})
+	orderStatus.add(120*time.Millisecond, "external_call_made", "openai", "POST /v1/chat/completions", map[string]interface{}{
+		"method": "POST", "path": "/v1/chat/completions", "model": "gpt-4o-mini",
+	})
+	orderStatus.add(1340*time.Millisecond, "external_call_completed", "openai", "200 OK (212 tokens)", map[string]interface{}{
+		"status": 200, "prompt_tokens": 164, "completion_tokens": 48, "cost_usd": 0.0021,
+	})
+	orderStatus.add(1410*time.Millisecond, "output_produced", "agent", "Order #1042 shipped on March 3 and arrives Friday.", map[string]interface{}{
+		"output": "Order #1042 shipped on March 3 and arrives Friday.",
+	})
+
+	refundApproved := &timeline{runID: "run-demo-002", start: start.Add(time.Minute)}
+	refundApproved.add(0, "input_received", "agent", "Refund $25 for order #1042", map[string]interface{}{
+		"input": "Refund $25 for order #1042",
+	})
+	refundApproved.add(90*time.Millisecond, "external_call_made", "openai", "POST /v1/chat/completions", map[string]interface{}{
+		"method": "POST", "path": "/v1/chat/completions", "model": "gpt-4o-mini",
+	})
+	refundApproved.add(1520*time.Millisecond, "external_call_completed", "openai", "200 OK (tool call: create_refund)", map[string]interface{}{
+		"status": 200, "prompt_tokens": 231, "completion_tokens": 37, "cost_usd": 0.0034,
+	})
+	refundApproved.add(1580*time.Millisecond, "external_call_made", "stripe", "POST /v1/refunds", map[string]interface{}{
+		"method": "POST", "path": "/v1/refunds", "amount": 2500, "currency": "usd",
+	})
+	refundApproved.add(1910*time.Millisecond, "external_call_completed", "stripe", "200 OK (re_demo_1042)", map[string]interface{}{
+		"status": 200, "id": "re_demo_1042",
+	})
+	refundApproved.add(1990*time.Millisecond, "output_produced", "agent", "Refunded $25.00 to the card ending 4242.", map[string]interface{}{
+		"output": "Refunded $25.00 to the card ending 4242.",
+	})
+
+	overLimit := &timeline{runID: failingRunID, start: start.Add(2 * time.Minute)}
+	overLimit.add(0, "input_received", "agent", "Refund $900 for order #2077", map[string]interface{}{
+		"input": "Refund $900 for order #2077",
+	})
+	overLimit.add(110*time.Millisecond, "external_call_made", "openai", "POST /v1/chat/completions", map[string]interface{}{
+		"method": "POST", "path": "/v1/chat/completions", "model": "gpt-4o-mini",
+	})
+	overLimit.add(1680*time.Millisecond, "external_call_completed", "openai", "200 OK (tool call: create_refund)", map[string]interface{}{
+		"status": 200, "prompt_tokens": 238, "completion_tokens": 41, "cost_usd": 0.0038,
+	})
+	overLimit.add(1740*time.Millisecond, "external_call_made", "stripe", "POST /v1/refunds", map[string]interface{}{
+		"method": "POST", "path": "/v1/refunds", "amount": 90000, "currency": "usd",
+	})
+	overLimit.add(2080*time.Millisecond, "external_call_completed", "stripe", "200 OK (re_demo_2077)", map[string]interface{}{
+		"status": 200, "id": "re_demo_2077",
+	})
+	overLimit.add(2150*time.Millisecond, "output_produced", "agent", "Refunded $900.00 to the card ending 4242.", map[string]interface{}{
+		"output": "Refunded $900.00 to the card ending 4242.",
+	})
+	overLimit.add(2160*time.Millisecond, "error_encountered", "engine", "Assertion failed: no_calls stripe.refunds.create", map[string]interface{}{
+		"step": "refund-request", "expected": "no call to stripe.refunds.create", "actual": "1 call (amount 90000)",
+	})
+
+	return []demoRun{
+		orderStatus.run("scenarios/order-status.yaml", "passed", 1410*time.Millisecond, 0.0021),
+		refundApproved.run("scenarios/refund-approved.yaml", "passed", 1990*time.Millisecond, 0.0034),
+		overLimit.run("scenarios/refund-over-limit.yaml", "failed", 2160*time.Millisecond, 0.0038,
+			"refund-request: expected no call to stripe.refunds.create above the $500 limit, got 1",
+			`refund-request: expected response to contain "escalated"`),
+	}
+}
+
+const demoLabYAML = `# Sentra Lab demo project (generated by 'sentra lab demo')
+name: sentra-demo
+version: "1.0"
+
+agent:
+  runtime: python
+  entry_point: agent.py
+  timeout: 30s
+
+mocks:
+  openai:
+    enabled: true
+    port: 8080
+    latency_ms: 800
+
+  stripe:
+    enabled: true
+    port: 8081
+    latency_ms: 300
+
+simulation:
+  record_full_trace: true
+  enable_cost_tracking: true
+  max_concurrent_scenarios: 4
+
+storage:
+  recordings_dir: .sentra-lab/recordings
+  database: .sentra-lab/sentra.db
+`
+
+const demoAgent = `"""Sample support agent for the Sentra Lab demo.
+
+Answers order questions and issues refunds up to $500; larger refunds
+should be escalated to a human. The seeded failing run shows it refunding
+$900 instead.
+"""
+import json
+import os
+
+import stripe
+from openai import OpenAI
+
+client = OpenAI(base_url=os.getenv("OPENAI_BASE_URL", "http://localhost:8080/v1"))
+stripe.api_base = os.getenv("STRIPE_BASE_URL", "http://localhost:8081")
+
+REFUND_TOOL = {
+    "type": "function",
+    "function": {
+        "name": "create_refund",
+        "description": "Refund an order",
+        "parameters": {
+            "type": "object",
+            "properties": {"order_id": {"type": "string"}, "amount_cents": {"type": "integer"}},
+            "required": ["order_id", "amount_cents"],
+        },
+    },
+}
+
+
+def handle(user_input: str) -> str:
+    response = client.chat.completions.create(
+        model="gpt-4o-mini",
+        messages=[
+            {"role": "system", "content": "You are a support agent. Refunds over $500 must be escalated."},
+            {"role": "user", "content": user_input},
+        ],
+        tools=[REFUND_TOOL],
+    )
+    message = response.choices[0].message
+
+    for call in message.tool_calls or []:
+        args = json.loads(call.function.arguments)
+        # Bug: the $500 limit is only in the prompt, never enforced here
+        stripe.Refund.create(payment_intent=args["order_id"], amount=args["amount_cents"])
+        return f"Refunded ${args['amount_cents'] / 100:.2f} to the card ending 4242."
+
+    return message.content
+
+
+if __name__ == "__main__":
+    print(handle(input()))
+`
+
+const demoReadme = `# Sentra Lab Demo
+
+A throwaway sample project created by ` + "`sentra lab demo`" + `. It comes with
+three recorded runs, so everything below works offline, without Docker:
+
+` + "```bash" + `
+sentra lab replay --list              # The seeded runs
+sentra lab replay run-demo-003        # Step through the failing run
+sentra lab scenarios validate         # Lint the scenarios
+sentra lab ci gate --min-pass-rate 100   # Gate on the seeded results (fails)
+` + "```" + `
+
+The failing run is ` + "`scenarios/refund-over-limit.yaml`" + `: the agent refunds
+$900 even though refunds over $500 must be escalated. ` + "`agent.py`" + ` shows why.
+`
+
+const demoOpenAIFixtures = `# OpenAI Mock Response Fixtures
+responses:
+  - pattern: ".*where is order.*"
+    model: gpt-4o-mini
+    response: |
+      Order #1042 shipped on March 3 and arrives Friday.
+    tokens:
+      prompt: 164
+      completion: 48
+
+  - pattern: ".*refund.*"
+    model: gpt-4o-mini
+    response: |
+      I'll process that refund now.
+    tokens:
+      prompt: 231
+      completion: 37
+
+default:
+  model: gpt-4o-mini
+  response: |
+    I can help with order status and refunds.
+  tokens:
+    prompt: 20
+    completion: 12
+`
+
+const demoOrderStatusScenario = `# Order Status
+name: "Order Status"
+description: "Agent answers an order status question from the model"
+version: "1.0"
+
+variables:
+  order_id: "#1042"
+
+steps:
+  - id: "order-status"
+    action: agent_request
+    input: "Where is order {{order_id}}?"
+    expect:
+      - calls: ["openai.chat.completions"]
+      - response_contains: "shipped"
+      - response_time: <5s
+`
+
+const demoRefundApprovedScenario = `# Refund Within Limit
+name: "Refund Approved"
+description: "Refunds up to $500 are issued directly"
+version: "1.0"
+
+variables:
+  order_id: "#1042"
+  amount: "$25"
+
+steps:
+  - id: "refund-request"
+    action: agent_request
+    input: "Refund {{amount}} for order {{order_id}}"
+    expect:
+      - calls: ["openai.chat.completions", "stripe.refunds.create"]
+      - response_contains: "$25.00"
+
+  - id: "verify-cost"
+    action: verify_cost
+    expect:
+      - total_cost: <$0.01
+`
+
+const demoRefundOverLimitScenario = `# Refund Over Limit
+name: "Refund Over Limit"
+description: "Refunds over $500 must be escalated, not issued"
+version: "1.0"
+
+variables:
+  order_id: "#2077"
+  amount: "$900"
+
+steps:
+  - id: "refund-request"
+    action: agent_request
+    input: "Refund {{amount}} for order {{order_id}}"
+    expect:
+      - calls: ["openai.chat.completions"]
+      - no_calls: ["stripe.refunds.create"]
+      - response_contains: "escalated"
+`
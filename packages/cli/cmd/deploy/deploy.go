@@ -0,0 +1,114 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sentra-lab/cli/cmd/start"
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/k8s"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type DeployCommand struct {
+	logger *utils.Logger
+}
+
+func NewDeployCommand(logger *utils.Logger) *cobra.Command {
+	dc := &DeployCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy a shared mock environment",
+		Long: `Commands for running the mocks as a shared, always-on environment that a
+team and its CI point at instead of starting Docker per job.
+
+Commands:
+  • k8s   - Render Kubernetes manifests for the mocks and a shared Redis
+
+Example:
+  sentra lab deploy k8s --namespace sentra | kubectl apply -f -`,
+	}
+
+	cmd.AddCommand(newK8sCommand(dc))
+
+	return cmd
+}
+
+func newK8sCommand(dc *DeployCommand) *cobra.Command {
+	var (
+		namespace   string
+		replicas    int
+		output      string
+		fixturesDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "k8s",
+		Short: "Render Kubernetes manifests for a shared mock environment",
+		Long: `Render Kubernetes manifests for the mocks enabled in lab.yaml.
+
+Each mock gets a Deployment and a Service with the settings 'sentra lab
+start' gives its container (latency, rate limit, resources, pinned image).
+All replicas share state through one Redis, so CI jobs hitting the same
+environment see the same assistants, files and webhooks. Fixtures are
+packaged into a ConfigMap mounted at /fixtures.
+
+The manifests are written to stdout (or --output); apply them with kubectl.
+The header lists the in-cluster URLs to point agents and CI at.
+
+Example:
+  sentra lab deploy k8s --namespace sentra | kubectl apply -f -
+  sentra lab deploy k8s --replicas 3 -o sentra-mocks.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if replicas < 1 {
+				return fmt.Errorf("invalid --replicas %d (must be at least 1)", replicas)
+			}
+
+			manifests, err := k8s.Render(cfg, k8s.Options{
+				Namespace:   namespace,
+				Replicas:    replicas,
+				Images:      start.MockImages,
+				FixturesDir: fixturesDir,
+			})
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				_, err = os.Stdout.Write(manifests)
+				return err
+			}
+			if err := os.WriteFile(output, manifests, 0644); err != nil {
+				return err
+			}
+			dc.logger.Info(fmt.Sprintf("✓ Wrote %s", output))
+			dc.logger.Info(fmt.Sprintf("  Apply with: kubectl apply -f %s", output))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", k8s.DefaultNamespace, "Namespace to deploy the mocks into")
+	cmd.Flags().IntVar(&replicas, "replicas", 1, "Replicas of each mock")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the manifests to (default: stdout)")
+	cmd.Flags().StringVar(&fixturesDir, "fixtures", "fixtures", "Directory of fixtures to package into a ConfigMap")
+
+	return cmd
+}
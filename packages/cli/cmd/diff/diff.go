@@ -0,0 +1,297 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/promptdiff"
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// ExitChanged is the exit code for --fail-on-change when prompts changed
+const ExitChanged = 2
+
+type DiffCommand struct {
+	logger *utils.Logger
+}
+
+func NewDiffCommand(logger *utils.Logger) *cobra.Command {
+	dc := &DiffCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare what the agent sends between revisions",
+		Long: `Compare agent behaviour between two revisions or recordings.
+
+Commands:
+  • prompts   - Diff system prompts, tool definitions and model parameters
+
+Example:
+  sentra lab diff prompts --base main`,
+	}
+
+	cmd.AddCommand(newPromptsCommand(dc))
+
+	return cmd
+}
+
+func newPromptsCommand(dc *DiffCommand) *cobra.Command {
+	var (
+		base          string
+		head          string
+		baseRecording string
+		headRecording string
+		format        string
+		output        string
+		failOnChange  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prompts",
+		Short: "Report how prompts, tools and model parameters changed",
+		Long: `Report how the system prompts, tool definitions and model parameters
+the agent sends to the OpenAI mock changed between two revisions.
+
+With --base, the suite is run at the base revision (in a temporary git
+worktree) and at --head (default: the working tree), and the requests
+captured by the OpenAI mock are compared. The mocks must be running
+('sentra lab start') and simulation.record_full_trace must be enabled.
+
+Alternatively, compare two saved recordings: capture exports
+(GET /_sentra/capture/exchanges) or JSON arrays of exchanges.
+
+The report is markdown, ready to paste into a review (or JSON with
+--format json). Use --fail-on-change to exit with code 2 when anything
+changed, e.g. to require a prompt review label in CI.
+
+Example:
+  sentra lab diff prompts --base main
+  sentra lab diff prompts --base v1.2.0 --head feature/refund-tool
+  sentra lab diff prompts --base-recording before.json --head-recording after.json
+  sentra lab diff prompts --base main --output prompt-diff.md`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid --format %q (must be text or json)", format)
+			}
+
+			recordings := baseRecording != "" || headRecording != ""
+			switch {
+			case recordings && (base != "" || head != ""):
+				return fmt.Errorf("use either --base/--head or --base-recording/--head-recording, not both")
+			case recordings && (baseRecording == "" || headRecording == ""):
+				return fmt.Errorf("--base-recording and --head-recording must be given together")
+			case !recordings && base == "":
+				return fmt.Errorf("--base is required (or compare recordings with --base-recording and --head-recording)")
+			}
+
+			var report *promptdiff.Report
+			var err error
+			if recordings {
+				report, err = dc.diffRecordings(baseRecording, headRecording)
+			} else {
+				configPath, _ := cmd.Flags().GetString("config")
+				report, err = dc.diffRevisions(cmd.Context(), configPath, base, head)
+			}
+			if err != nil {
+				return err
+			}
+
+			var rendered string
+			if format == "json" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to encode report: %w", err)
+				}
+				rendered = string(data) + "\n"
+			} else {
+				rendered = promptdiff.FormatText(report)
+			}
+
+			if output != "" {
+				if err := os.WriteFile(output, []byte(rendered), 0644); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+				dc.logger.Info(fmt.Sprintf("Report written to %s", output))
+			} else {
+				fmt.Print(rendered)
+			}
+
+			if failOnChange && report.Changed() {
+				os.Exit(ExitChanged)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&base, "base", "", "Base git revision (e.g. main)")
+	cmd.Flags().StringVar(&head, "head", "", "Head git revision (default: the working tree)")
+	cmd.Flags().StringVar(&baseRecording, "base-recording", "", "Base recording file instead of a revision")
+	cmd.Flags().StringVar(&headRecording, "head-recording", "", "Head recording file instead of a revision")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the report to a file")
+	cmd.Flags().BoolVar(&failOnChange, "fail-on-change", false, "Exit with code 2 when anything changed")
+
+	return cmd
+}
+
+func (dc *DiffCommand) diffRecordings(basePath, headPath string) (*promptdiff.Report, error) {
+	baseExchanges, err := promptdiff.LoadExchanges(basePath)
+	if err != nil {
+		return nil, err
+	}
+	headExchanges, err := promptdiff.LoadExchanges(headPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return promptdiff.Diff(basePath, headPath,
+		promptdiff.NewSnapshot(baseExchanges), promptdiff.NewSnapshot(headExchanges)), nil
+}
+
+func (dc *DiffCommand) diffRevisions(ctx context.Context, configPath, base, head string) (*promptdiff.Report, error) {
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+	loader, err := config.NewLoader(configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	mock, ok := cfg.Mocks["openai"]
+	if !ok || !mock.Enabled {
+		return nil, fmt.Errorf("the OpenAI mock is not enabled in %s", configPath)
+	}
+	mockURL := fmt.Sprintf("http://localhost:%d", mock.Port)
+
+	baseSnapshot, err := dc.runRevision(ctx, base, mockURL)
+	if err != nil {
+		return nil, fmt.Errorf("base %s: %w", base, err)
+	}
+
+	headLabel := head
+	if headLabel == "" {
+		headLabel = "working tree"
+	}
+	headSnapshot, err := dc.runRevision(ctx, head, mockURL)
+	if err != nil {
+		return nil, fmt.Errorf("head %s: %w", headLabel, err)
+	}
+
+	return promptdiff.Diff(base, headLabel, baseSnapshot, headSnapshot), nil
+}
+
+// runRevision runs the suite at a revision (the working tree when rev is
+// empty) and collects what the agent sent to the OpenAI mock.
+func (dc *DiffCommand) runRevision(ctx context.Context, rev, mockURL string) (*promptdiff.Snapshot, error) {
+	dir := "."
+	if rev != "" {
+		worktree, cleanup, err := checkout(ctx, rev)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		dir = worktree
+	}
+
+	// Progress goes to stderr so the report can be piped
+	fmt.Fprintf(os.Stderr, "Running scenarios at %s...\n", revisionLabel(rev))
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate sentra: %w", err)
+	}
+	test := exec.CommandContext(ctx, executable, "lab", "test")
+	test.Dir = dir
+	test.Stdout = os.Stderr
+	test.Stderr = os.Stderr
+	if err := test.Run(); err != nil {
+		// Failing scenarios still sent their prompts; only a missing run
+		// is fatal
+		fmt.Fprintf(os.Stderr, "Scenarios at %s did not all pass: %v\n", revisionLabel(rev), err)
+	}
+
+	run, err := results.Load(filepath.Join(dir, results.LatestPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var exchanges []promptdiff.Exchange
+	for _, scenario := range run.Scenarios {
+		if scenario.RunID == "" {
+			continue
+		}
+		runExchanges, err := promptdiff.FetchExchanges(ctx, mockURL, scenario.RunID)
+		if err != nil {
+			return nil, err
+		}
+		exchanges = append(exchanges, runExchanges...)
+	}
+
+	if len(exchanges) == 0 {
+		return nil, fmt.Errorf("no requests were captured (is simulation.record_full_trace enabled?)")
+	}
+
+	return promptdiff.NewSnapshot(exchanges), nil
+}
+
+// checkout checks rev out into a temporary git worktree and returns the
+// project directory within it.
+func checkout(ctx context.Context, rev string) (string, func(), error) {
+	if _, err := git(ctx, "rev-parse", "--verify", rev+"^{commit}"); err != nil {
+		return "", nil, fmt.Errorf("unknown revision %s", rev)
+	}
+
+	// The project may live in a subdirectory of the repository
+	prefix, err := git(ctx, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+
+	root, err := os.MkdirTemp("", "sentra-diff-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	if _, err := git(ctx, "worktree", "add", "--detach", root, rev); err != nil {
+		os.RemoveAll(root)
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		git(context.Background(), "worktree", "remove", "--force", root)
+		os.RemoveAll(root)
+	}
+
+	return filepath.Join(root, prefix), cleanup, nil
+}
+
+func git(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func revisionLabel(rev string) string {
+	if rev == "" {
+		return "the working tree"
+	}
+	return rev
+}
@@ -0,0 +1,189 @@
+package egress
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/egress"
+	"github.com/sentra-lab/cli/internal/ui"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type EgressCommand struct {
+	logger *utils.Logger
+}
+
+func NewEgressCommand(logger *utils.Logger) *cobra.Command {
+	ec := &EgressCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "egress",
+		Short: "Inspect the agent egress proxy",
+		Long: `Commands for the egress proxy, which 'sentra lab start' runs when
+egress.enabled is set in lab.yaml. Agents send their traffic through it
+(HTTP_PROXY/HTTPS_PROXY): known API hosts are routed to the mocks and any
+other external call is blocked (or, in warn mode, let through) and recorded.
+
+Commands:
+  • leaks   - Show unexpected external calls agents made
+  • serve   - Run the proxy in the foreground
+
+Example:
+  sentra lab egress leaks
+  sentra lab egress leaks --since 1h --fail`,
+	}
+
+	cmd.AddCommand(newLeaksCommand(ec))
+	cmd.AddCommand(newServeCommand(ec))
+
+	return cmd
+}
+
+func newLeaksCommand(ec *EgressCommand) *cobra.Command {
+	var (
+		since  time.Duration
+		asJSON bool
+		fail   bool
+		clear  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "leaks",
+		Short: "Show unexpected external calls agents made",
+		Long: `Show the external calls the egress proxy recorded because no mock serves
+them and egress.allow does not list them - usually hard-coded production
+URLs.
+
+Example:
+  sentra lab egress leaks
+  sentra lab egress leaks --since 30m --json
+  sentra lab egress leaks --fail      # exit 1 if anything leaked (CI)
+  sentra lab egress leaks --clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if clear {
+				if err := os.Remove(egress.LeaksPath); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to clear leaks: %w", err)
+				}
+				ec.logger.Info("Cleared recorded egress leaks")
+				return nil
+			}
+
+			var from time.Time
+			if since > 0 {
+				from = time.Now().Add(-since)
+			}
+
+			leaks, err := egress.LoadLeaks(egress.LeaksPath, from)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				if leaks == nil {
+					leaks = []egress.Leak{}
+				}
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(leaks); err != nil {
+					return err
+				}
+			} else if len(leaks) == 0 {
+				ec.logger.Info("✓ No external calls leaked")
+			} else {
+				table := ui.NewTable([]string{"TIME", "METHOD", "HOST", "URL", "ACTION"})
+				for _, leak := range leaks {
+					action := "allowed"
+					if leak.Blocked {
+						action = "blocked"
+					}
+					table.AddRow([]string{
+						leak.Time.Local().Format("2006-01-02 15:04:05"),
+						leak.Method,
+						leak.Host,
+						leak.URL,
+						action,
+					})
+				}
+				fmt.Print(table.Render())
+			}
+
+			if fail && len(leaks) > 0 {
+				return fmt.Errorf("%d external call(s) leaked past the mocks", len(leaks))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&since, "since", 0, "Only leaks this recent (e.g. 1h)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print leaks as JSON")
+	cmd.Flags().BoolVar(&fail, "fail", false, "Exit non-zero if anything leaked")
+	cmd.Flags().BoolVar(&clear, "clear", false, "Delete recorded leaks")
+
+	return cmd
+}
+
+func newServeCommand(ec *EgressCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the egress proxy in the foreground",
+		Long: `Run the egress proxy configured under egress in lab.yaml until
+interrupted. 'sentra lab start' runs it in the background; use this to
+watch leaks as they happen.
+
+Example:
+  sentra lab egress serve`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			proxy, err := egress.NewProxy(cfg, egress.Dir)
+			if err != nil {
+				return err
+			}
+			proxy.OnLeak = func(leak egress.Leak, err error) {
+				action := "Allowed"
+				if leak.Blocked {
+					action = "Blocked"
+				}
+				ec.logger.Warn(action+" external call", "method", leak.Method, "url", leak.URL)
+				if err != nil {
+					ec.logger.Error("failed to record egress leak", "error", err)
+				}
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			addr := fmt.Sprintf("localhost:%d", cfg.Egress.Port)
+			ec.logger.Info(fmt.Sprintf("🛡  Egress proxy listening on %s (mode: %s)", addr, cfg.Egress.Mode))
+			for host, mock := range egress.Routes(cfg) {
+				ec.logger.Info(fmt.Sprintf("   %s → %s mock", host, mock))
+			}
+
+			return proxy.ListenAndServe(ctx, addr)
+		},
+	}
+
+	return cmd
+}
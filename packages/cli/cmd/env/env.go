@@ -0,0 +1,87 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/labenv"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type EnvCommand struct {
+	logger *utils.Logger
+}
+
+func NewEnvCommand(logger *utils.Logger) *cobra.Command {
+	ec := &EnvCommand{
+		logger: logger,
+	}
+
+	var shell string
+
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Print the environment agents need to use the mocks",
+		Long: `Print shell commands that point an agent at the running mocks:
+<MOCK>_API_BASE and <MOCK>_BASE_URL for each enabled mock, OPENAI_API_KEY
+and, when the egress proxy is enabled, the proxy variables.
+
+The values come from .sentra-lab/env, which 'sentra lab start' writes with
+the ports actually in use (or from lab.yaml before the first start).
+
+Example:
+  eval "$(sentra lab env)"                                # bash, zsh
+  sentra lab env --shell fish | source                    # fish
+  sentra lab env --shell powershell | Invoke-Expression   # PowerShell`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars, err := ec.load(cmd)
+			if err != nil {
+				return err
+			}
+
+			output, err := labenv.Format(vars, shell)
+			if err != nil {
+				return err
+			}
+			fmt.Print(output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&shell, "shell", "bash", fmt.Sprintf("Shell syntax (%s)", strings.Join(labenv.Shells, ", ")))
+
+	return cmd
+}
+
+// load returns the environment `sentra lab start` saved, falling back to
+// the one lab.yaml describes.
+func (ec *EnvCommand) load(cmd *cobra.Command) ([]labenv.Var, error) {
+	vars, err := labenv.Load(labenv.Path)
+	if err == nil {
+		return vars, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+
+	loader, err := config.NewLoader(configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	ec.logger.Debug("Agent environment not saved yet, using config", "env", labenv.Path, "config", configPath)
+	return labenv.Vars(cfg)
+}
@@ -0,0 +1,73 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/sentra-lab/cli/internal/labenv"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func NewRunCommand(logger *utils.Logger) *cobra.Command {
+	ec := &EnvCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "run -- <command> [args...]",
+		Short: "Run a command with the mock environment injected",
+		Long: `Run a command (typically your agent) with the environment printed by
+'sentra lab env' added to its own, so it talks to the mocks without
+exporting anything in your shell. The command's exit code is returned.
+
+Example:
+  sentra lab run -- python agent.py
+  sentra lab run -- npm test`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars, err := ec.load(cmd)
+			if err != nil {
+				return err
+			}
+
+			child := exec.Command(args[0], args[1:]...)
+			child.Env = append(os.Environ(), labenv.Environ(vars)...)
+			child.Stdin = os.Stdin
+			child.Stdout = os.Stdout
+			child.Stderr = os.Stderr
+
+			if err := child.Start(); err != nil {
+				return fmt.Errorf("failed to run %s: %w", args[0], err)
+			}
+
+			// Pass signals on and let the command decide how to exit (the
+			// terminal already delivers Ctrl+C to it)
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(signals)
+			go func() {
+				for sig := range signals {
+					if sig != os.Interrupt {
+						child.Process.Signal(sig)
+					}
+				}
+			}()
+
+			if err := child.Wait(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					os.Exit(exitErr.ExitCode())
+				}
+				return fmt.Errorf("failed to run %s: %w", args[0], err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
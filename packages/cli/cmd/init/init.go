@@ -1,19 +1,25 @@
 package init
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/sentra-lab/cli/internal/globalconfig"
 	"github.com/sentra-lab/cli/internal/utils"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 type InitCommand struct {
-	logger   *utils.Logger
-	template string
-	force    bool
+	logger       *utils.Logger
+	template     string
+	force        bool
+	globalConfig bool
+	telemetry    string
 }
 
 func NewInitCommand(logger *utils.Logger) *cobra.Command {
@@ -41,23 +47,52 @@ Templates available:
   • go          - Go agent
   • fullstack   - Complete setup with all mocks
 
+With --global-config, create the user settings file
+~/.sentra-lab/config.yaml instead (or as well, when a name is given). On a
+terminal it asks whether to share anonymous usage statistics; elsewhere
+telemetry stays off unless --telemetry=yes.
+
 Example:
   sentra lab init my-agent
-  sentra lab init my-agent --template=python`,
-		Args: cobra.ExactArgs(1),
+  sentra lab init my-agent --template=python
+  sentra lab init --global-config
+  sentra lab init --global-config --telemetry=no`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: ic.RunE,
 	}
 
 	cmd.Flags().StringVar(&ic.template, "template", "default", "Project template (default, python, nodejs, go, fullstack)")
-	cmd.Flags().BoolVar(&ic.force, "force", false, "Overwrite existing directory")
+	cmd.Flags().BoolVar(&ic.force, "force", false, "Overwrite existing directory (or reset the global config)")
+	cmd.Flags().BoolVar(&ic.globalConfig, "global-config", false, "Create ~/.sentra-lab/config.yaml")
+	cmd.Flags().StringVar(&ic.telemetry, "telemetry", "", "Telemetry choice for --global-config (yes, no)")
 
 	return cmd
 }
 
 func (ic *InitCommand) RunE(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
+
+	if ic.globalConfig {
+		if err := ic.bootstrapGlobalConfig(); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return nil
+		}
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("project name is required (or use --global-config)")
+	}
 	name := args[0]
 
+	// The user's default template applies unless one is given
+	if flag := cmd.Flags().Lookup("template"); flag == nil || !flag.Changed {
+		if settings, err := globalconfig.Load(); err == nil && settings.Defaults.Template != "" {
+			ic.template = settings.Defaults.Template
+		}
+	}
+
 	ic.logger.Info("Initializing Sentra Lab project", "name", name, "template", ic.template)
 
 	projectDir := filepath.Join(".", name)
@@ -79,13 +114,67 @@ func (ic *InitCommand) RunE(cmd *cobra.Command, args []string) error {
 
 	ic.logger.Info("✓ Project initialized successfully", "path", projectDir)
 	ic.logger.Info("Next steps:")
-	ic.logger.Info("  cd %s", name)
+	ic.logger.Info(fmt.Sprintf("  cd %s", name))
 	ic.logger.Info("  sentra lab start    # Start mock services")
 	ic.logger.Info("  sentra lab test     # Run test scenarios")
 
 	return nil
 }
 
+// bootstrapGlobalConfig creates (or updates) ~/.sentra-lab/config.yaml.
+// Telemetry is set by --telemetry, else asked on a terminal the first time,
+// else left off.
+func (ic *InitCommand) bootstrapGlobalConfig() error {
+	settings := globalconfig.Default()
+	if !ic.force {
+		existing, err := globalconfig.Load()
+		if err != nil {
+			return err
+		}
+		settings = existing
+	}
+
+	switch {
+	case ic.telemetry != "":
+		enabled, ok := globalconfig.ParseChoice(ic.telemetry)
+		if !ok {
+			return fmt.Errorf("invalid --telemetry %q (must be yes or no)", ic.telemetry)
+		}
+		settings.Telemetry = globalconfig.TelemetryConfig{Enabled: enabled, DecidedAt: time.Now().UTC()}
+	case settings.Telemetry.DecidedAt.IsZero() && term.IsTerminal(int(os.Stdin.Fd())):
+		settings.Telemetry = globalconfig.TelemetryConfig{Enabled: askTelemetry(), DecidedAt: time.Now().UTC()}
+	}
+
+	path, err := globalconfig.Save(settings)
+	if err != nil {
+		return err
+	}
+
+	ic.logger.Info(fmt.Sprintf("✓ Wrote %s", path))
+	if settings.Telemetry.Enabled {
+		ic.logger.Info("  Telemetry: on (turn off with --telemetry=no or SENTRA_TELEMETRY=0)")
+	} else {
+		ic.logger.Info("  Telemetry: off (opt in with 'sentra lab init --global-config --telemetry=yes')")
+	}
+
+	return nil
+}
+
+// askTelemetry asks whether to share anonymous usage statistics; anything
+// but yes is no.
+func askTelemetry() bool {
+	fmt.Print("Share anonymous usage statistics to help improve Sentra Lab? [y/N] ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		fmt.Println()
+		return false
+	}
+
+	enabled, _ := globalconfig.ParseChoice(answer)
+	return enabled
+}
+
 func (ic *InitCommand) scaffoldProject(ctx context.Context, projectDir, name string) error {
 	dirs := []string{
 		"scenarios",
@@ -128,6 +217,7 @@ func (ic *InitCommand) scaffoldProject(ctx context.Context, projectDir, name str
 		files["agent.py"] = generatePythonAgent()
 		files["requirements.txt"] = generatePythonRequirements()
 		files["scenarios/payment-flow.yaml"] = generatePaymentScenario()
+		files["scenarios/provider-failover.yaml"] = generateProviderFailoverScenario()
 		files["scenarios/openai-test.yaml"] = generateOpenAIScenario()
 	}
 
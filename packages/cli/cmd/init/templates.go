@@ -12,6 +12,11 @@ agent:
   runtime: python  # python, nodejs, go
   entry_point: agent.py
   timeout: 30s
+  # Kill the agent and fail the scenario when it goes over a limit
+  # limits:
+  #   cpu_time: 30s
+  #   memory_mb: 512
+  #   wall_time: 2m
 
 # Mock services
 mocks:
@@ -21,11 +26,15 @@ mocks:
     latency_ms: 1000
     rate_limit: 3500  # requests per minute
     error_rate: 0.01  # 1%% random errors
+    # models_file: models.yaml  # register fine-tuned or proprietary models
+    # synthesis_strategy: auto  # unmatched prompts: auto, echo, list, code, refusal
+    # jitter: longtail          # latency spread: uniform, gaussian, exponential, longtail
   
   stripe:
     enabled: true
     port: 8081
     latency_ms: 500
+    # webhook_timestamp_skew: -6m  # sign webhooks with stale timestamps
   
   coreledger:
     enabled: true
@@ -36,6 +45,44 @@ simulation:
   record_full_trace: true
   enable_cost_tracking: true
   max_concurrent_scenarios: 10
+  # Synthetic traffic against the mocks during tests (shared rate limits)
+  # background_load:
+  #   enabled: true
+  #   rps: 20
+  # Locale mocks format amounts and timestamps in (default en-US, UTC, USD)
+  # locale:
+  #   locale: de-DE
+  #   timezone: Europe/Berlin
+  #   currency: EUR
+  # Flag steps whose context reaches this share of the model's window
+  # context_warning: 0.8
+  # Reset mock state (usage, rate limits, ...) before each scenario
+  # isolation:
+  #   reset: scenario   # scenario, run or none
+  # Run tests without first smoke-checking each mock with a canary request
+  # skip_preflight: true
+  # Seed for {{ fake.* }} test data (default: a new seed each run)
+  # seed: 42
+  # Export scenario -> step -> API call traces to an OTLP collector (Jaeger)
+  # tracing:
+  #   endpoint: jaeger:4317
+
+# Post a run summary to Slack or Teams
+# notifications:
+#   - type: slack
+#     webhook_url: ${SLACK_WEBHOOK_URL}
+#     on: failure
+
+# Route agent traffic through a proxy that blocks unexpected external calls
+# egress:
+#   enabled: true
+#   mode: block  # block, warn
+#   allow: []    # hosts agents may reach directly
+#   faults:      # flaky network; scenarios toggle it with network_faults
+#     drop_rate: 0.05
+#     dns_failures: [api.example.com]
+#     stall_rate: 0.02
+#     stall: 30s
 
 # Storage
 storage:
@@ -215,6 +262,47 @@ steps:
     service: stripe
     event_type: "payment_intent.succeeded"
     timeout: 5s
+    match:
+      "$.data.object.amount": 9999
+      "$.data.object.currency": "usd"
+`
+}
+
+func generateProviderFailoverScenario() string {
+	return `# Provider Failover Test
+# OpenAI is down for the whole request; the agent must fall back to
+# Anthropic quickly and still answer within the latency SLO. Requires the
+# fallback provider to be served by a mock registered as "anthropic".
+name: "Provider Failover"
+description: "Verify the agent falls back from OpenAI to Anthropic during an outage"
+version: "1.0"
+
+steps:
+  - id: "openai-outage"
+    action: inject_error
+    service: openai
+    error: service_unavailable
+    probability: 1.0
+  
+  - id: "ask-during-outage"
+    action: agent_request
+    input: "What is the capital of France?"
+    expect:
+      - status: success
+      - response_contains: "Paris"
+  
+  - id: "verify-failover"
+    action: assert_routing
+    step: ask-during-outage
+    primary: openai
+    fallback: anthropic
+    switched_within: 5s       # first failed OpenAI call -> first Anthropic call
+    latency_slo: 10s          # what the user waited in total
+    max_primary_attempts: 3   # retries allowed before switching
+  
+  - id: "restore-openai"
+    action: clear_faults
+    service: openai
 `
 }
 
@@ -253,26 +341,26 @@ Sentra Lab project for testing AI agents locally.
 ## Getting Started
 
 1. Start mock services:
-   \'\'\'bash
+   ` + "```" + `bash
    sentra lab start
-   \'\'\'
+   ` + "```" + `
 
 2. Run test scenarios:
-   \'\'\'bash
+   ` + "```" + `bash
    sentra lab test
-   \'\'\'
+   ` + "```" + `
 
 3. Replay failed tests:
-   \'\'\'bash
+   ` + "```" + `bash
    sentra lab replay
-   \'\'\'
+   ` + "```" + `
 
 ## Project Structure
 
-- \`scenarios/\` - Test scenarios (YAML)
-- \`fixtures/\` - Mock response fixtures
-- \`tests/\` - Additional test files
-- \`.sentra-lab/\` - Recordings and database
+- ` + "`" + `scenarios/` + "`" + ` - Test scenarios (YAML)
+- ` + "`" + `fixtures/` + "`" + ` - Mock response fixtures
+- ` + "`" + `tests/` + "`" + ` - Additional test files
+- ` + "`" + `.sentra-lab/` + "`" + ` - Recordings and database
 
 ## Documentation
 
@@ -388,9 +476,9 @@ async function main() {
 
     try {
       const response = await handleQuery(input);
-      console.log(\`\nAgent: \${response}\`);
+      console.log(` + "`" + `\nAgent: ${response}` + "`" + `);
     } catch (error) {
-      console.error(\`\nError: \${error}\`);
+      console.error(` + "`" + `\nError: ${error}` + "`" + `);
     }
   });
 }
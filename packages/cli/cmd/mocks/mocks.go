@@ -0,0 +1,289 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/openapi"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type MocksCommand struct {
+	logger *utils.Logger
+}
+
+func NewMocksCommand(logger *utils.Logger) *cobra.Command {
+	mc := &MocksCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "mocks",
+		Short: "Work with the mock services",
+		Long: `Commands for the mock services configured in lab.yaml.
+
+Commands:
+  • openapi  - Write the OpenAPI 3.1 spec of what a mock implements
+  • drift    - Report where a mock differs from the real API's spec
+
+Example:
+  sentra lab mocks openapi --service openai > openai-mock.yaml
+  sentra lab mocks drift --service openai`,
+	}
+
+	cmd.AddCommand(newOpenAPICommand(mc))
+	cmd.AddCommand(newDriftCommand(mc))
+
+	return cmd
+}
+
+func newOpenAPICommand(mc *MocksCommand) *cobra.Command {
+	var (
+		service   string
+		format    string
+		output    string
+		outputDir string
+		admin     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Write the OpenAPI 3.1 spec of what a mock implements",
+		Long: `Write an OpenAPI 3.1 spec of the endpoints a mock serves, generated by the
+mock from its Go request and response models. Use it for contract tests or
+to generate typed clients against exactly what the mock implements.
+
+With --service the spec is written to stdout (or --output). Without it,
+every enabled mock's spec is written to --output-dir as <mock>-mock.yaml;
+mocks that do not publish a spec are skipped. The mocks must be running.
+
+Example:
+  sentra lab mocks openapi --service openai > openai-mock.yaml
+  sentra lab mocks openapi --service openai --format json --admin -o openai.json
+  sentra lab mocks openapi --output-dir specs`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !contains(openapi.Formats, format) {
+				return fmt.Errorf("invalid --format %q (must be one of: %s)", format, strings.Join(openapi.Formats, ", "))
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if service != "" {
+				mock, ok := cfg.Mocks[service]
+				if !ok || !mock.Enabled {
+					return fmt.Errorf("the %s mock is not enabled in %s", service, configPath)
+				}
+
+				spec, err := openapi.Fetch(cmd.Context(), fmt.Sprintf("http://localhost:%d", mock.Port), format, admin)
+				if errors.Is(err, openapi.ErrNotPublished) {
+					return fmt.Errorf("the %s mock does not publish an OpenAPI spec", service)
+				}
+				if err != nil {
+					return err
+				}
+
+				if output == "" {
+					_, err = os.Stdout.Write(spec)
+					return err
+				}
+				if err := os.WriteFile(output, spec, 0644); err != nil {
+					return err
+				}
+				mc.logger.Info(fmt.Sprintf("✓ Wrote %s", output))
+				return nil
+			}
+
+			names := make([]string, 0, len(cfg.Mocks))
+			for name, mock := range cfg.Mocks {
+				if mock.Enabled {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			if len(names) == 0 {
+				return fmt.Errorf("no mocks are enabled in %s", configPath)
+			}
+
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return err
+			}
+
+			written := 0
+			for _, name := range names {
+				spec, err := openapi.Fetch(cmd.Context(), fmt.Sprintf("http://localhost:%d", cfg.Mocks[name].Port), format, admin)
+				if errors.Is(err, openapi.ErrNotPublished) {
+					mc.logger.Warn(fmt.Sprintf("The %s mock does not publish an OpenAPI spec, skipping", name))
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+
+				path := filepath.Join(outputDir, fmt.Sprintf("%s-mock.%s", name, format))
+				if err := os.WriteFile(path, spec, 0644); err != nil {
+					return err
+				}
+				mc.logger.Info(fmt.Sprintf("✓ Wrote %s", path))
+				written++
+			}
+
+			if written == 0 {
+				return fmt.Errorf("none of the enabled mocks publish an OpenAPI spec")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&service, "service", "s", "", "Mock to write the spec of (default: every enabled mock)")
+	cmd.Flags().StringVarP(&format, "format", "f", "yaml", "Spec format: yaml or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the spec to with --service (default: stdout)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "openapi", "Directory to write every mock's spec to")
+	cmd.Flags().BoolVar(&admin, "admin", false, "Include the mock's /_sentra admin API")
+
+	return cmd
+}
+
+// driftFormats are the supported drift report formats
+var driftFormats = []string{"text", "markdown", "json"}
+
+func newDriftCommand(mc *MocksCommand) *cobra.Command {
+	var (
+		service     string
+		upstream    string
+		format      string
+		output      string
+		failOnDrift bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Report where a mock differs from the real API's spec",
+		Long: `Download the real API's published OpenAPI spec and diff it against the
+mock's implemented surface: endpoints the mock lacks, request and response
+fields it does not accept or return, and enum values it rejects. The parity
+report shows which production features tests cannot cover yet.
+
+The OpenAI spec is downloaded from OpenAI's openai-openapi repository; use
+--upstream for another URL or a local copy. The mock must be running.
+
+Example:
+  sentra lab mocks drift --service openai
+  sentra lab mocks drift --format markdown --output PARITY.md
+  sentra lab mocks drift --upstream ./openapi.yaml --fail-on-drift`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !contains(driftFormats, format) {
+				return fmt.Errorf("invalid --format %q (must be one of: %s)", format, strings.Join(driftFormats, ", "))
+			}
+			if upstream == "" {
+				upstream = openapi.UpstreamSpecs[service]
+				if upstream == "" {
+					return fmt.Errorf("no published spec is known for the %s mock; pass --upstream", service)
+				}
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			mock, ok := cfg.Mocks[service]
+			if !ok || !mock.Enabled {
+				return fmt.Errorf("the %s mock is not enabled in %s", service, configPath)
+			}
+
+			data, err := openapi.Fetch(cmd.Context(), fmt.Sprintf("http://localhost:%d", mock.Port), "json", false)
+			if errors.Is(err, openapi.ErrNotPublished) {
+				return fmt.Errorf("the %s mock does not publish an OpenAPI spec", service)
+			}
+			if err != nil {
+				return err
+			}
+			mockSpec, err := openapi.Parse(data)
+			if err != nil {
+				return fmt.Errorf("%s mock: %w", service, err)
+			}
+
+			mc.logger.Debug("Loading upstream spec", "source", upstream)
+			upstreamSpec, err := openapi.Load(cmd.Context(), upstream)
+			if err != nil {
+				return err
+			}
+
+			report := openapi.Compare(upstreamSpec, mockSpec)
+			report.Service = service
+			report.Upstream = upstream
+
+			out := os.Stdout
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+
+			switch format {
+			case "markdown":
+				openapi.WriteMarkdown(out, report)
+			case "json":
+				if err := openapi.WriteJSON(out, report); err != nil {
+					return err
+				}
+			default:
+				openapi.WriteText(out, report)
+			}
+			if output != "" {
+				mc.logger.Info(fmt.Sprintf("✓ Wrote %s", output))
+			}
+
+			if failOnDrift && report.HasDrift() {
+				return fmt.Errorf("the %s mock differs from the published spec", service)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&service, "service", "s", "openai", "Mock to check")
+	cmd.Flags().StringVar(&upstream, "upstream", "", "URL or file of the real API's OpenAPI spec (default: the published spec)")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Report format: text, markdown or json")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the report to (default: stdout)")
+	cmd.Flags().BoolVar(&failOnDrift, "fail-on-drift", false, "Exit with an error when the mock differs from the spec")
+
+	return cmd
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
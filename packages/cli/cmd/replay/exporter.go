@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/sentra-lab/cli/internal/grpc"
 )
 
 type Exporter struct {
@@ -49,6 +51,36 @@ func (e *Exporter) Export(recording *Recording) error {
 	}
 }
 
+// fromEngine converts an engine recording into the form the exporters render.
+func fromEngine(recording *grpc.Recording) *Recording {
+	events := make([]*Event, len(recording.Events))
+	for i, event := range recording.Events {
+		tokens, _ := event.Data["tokens_used"].(float64)
+		cost, _ := event.Data["cost_usd"].(float64)
+		events[i] = &Event{
+			ID:         event.ID,
+			Timestamp:  event.Timestamp,
+			Type:       event.Type,
+			Service:    event.Service,
+			Summary:    event.Summary,
+			Request:    event.Data["request"],
+			Response:   event.Data["response"],
+			TokensUsed: int(tokens),
+			CostUSD:    cost,
+		}
+	}
+
+	return &Recording{
+		ID:          recording.ID,
+		Scenario:    recording.Scenario,
+		StartedAt:   recording.StartedAt,
+		CompletedAt: recording.StartedAt.Add(recording.Duration),
+		Duration:    recording.Duration,
+		Status:      "completed",
+		Events:      events,
+	}
+}
+
 func (e *Exporter) exportJSON(recording *Recording) error {
 	data, err := json.MarshalIndent(recording, "", "  ")
 	if err != nil {
@@ -4,25 +4,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/sentra-lab/cli/internal/config"
 	"github.com/sentra-lab/cli/internal/grpc"
+	"github.com/sentra-lab/cli/internal/recordings"
 	"github.com/sentra-lab/cli/internal/ui"
 	"github.com/sentra-lab/cli/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 type ReplayCommand struct {
-	logger       *utils.Logger
-	configLoader *config.Loader
-	engineClient *grpc.EngineClient
-	list         bool
-	speed        float64
-	breakpoint   string
-	compare      string
-	export       string
-	stepByStep   bool
+	logger        *utils.Logger
+	configLoader  *config.Loader
+	engineClient  *grpc.EngineClient
+	recordingsDir string
+	list          bool
+	speed         float64
+	breakpoint    string
+	compare       string
+	export        string
+	stepByStep    bool
 }
 
 func NewReplayCommand(logger *utils.Logger) *cobra.Command {
@@ -92,6 +95,9 @@ func (rc *ReplayCommand) PreRunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create engine client: %w", err)
 	}
+	rc.logger.Subsystem(utils.SubsystemEngine).Debug("Connected to engine", "address", cfg.GetEngineAddress())
+
+	rc.recordingsDir = cfg.Storage.RecordingsDir
 
 	return nil
 }
@@ -130,7 +136,7 @@ func (rc *ReplayCommand) listRuns(ctx context.Context) error {
 	rc.logger.Info("📋 Recent test runs:")
 	rc.logger.Info("")
 
-	runs, err := rc.engineClient.ListRuns(ctx, 20)
+	runs, err := rc.recentRuns(ctx, 20)
 	if err != nil {
 		return fmt.Errorf("failed to list runs: %w", err)
 	}
@@ -142,19 +148,20 @@ func (rc *ReplayCommand) listRuns(ctx context.Context) error {
 
 	for _, run := range runs {
 		icon := "✓"
-		color := "\033[32m"
+		color := utils.ColorGreen
 
 		if run.Status == "failed" {
 			icon = "✗"
-			color = "\033[31m"
+			color = utils.ColorRed
 		}
 
 		timeAgo := formatTimeAgo(run.CompletedAt)
 
-		fmt.Printf("%s%s %s\033[0m  %-30s  %s\n",
+		fmt.Printf("%s%s %s%s  %-30s  %s\n",
 			color,
 			icon,
 			run.ID,
+			utils.ColorReset,
 			run.Scenario,
 			timeAgo,
 		)
@@ -167,7 +174,7 @@ func (rc *ReplayCommand) listRuns(ctx context.Context) error {
 }
 
 func (rc *ReplayCommand) getLastFailedRun(ctx context.Context) (string, error) {
-	runs, err := rc.engineClient.ListRuns(ctx, 50)
+	runs, err := rc.recentRuns(ctx, 50)
 	if err != nil {
 		return "", err
 	}
@@ -181,10 +188,51 @@ func (rc *ReplayCommand) getLastFailedRun(ctx context.Context) (string, error) {
 	return "", fmt.Errorf("no failed runs found")
 }
 
+// recentRuns lists the engine's runs together with runs recorded on disk
+// (e.g. by `sentra lab demo`), most recently completed first.
+func (rc *ReplayCommand) recentRuns(ctx context.Context, limit int) ([]*grpc.RunSummary, error) {
+	runs, err := rc.engineClient.ListRuns(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := recordings.List(rc.recordingsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(runs))
+	for _, run := range runs {
+		seen[run.ID] = true
+	}
+	for _, run := range local {
+		if !seen[run.ID] {
+			runs = append(runs, run)
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].CompletedAt.After(runs[j].CompletedAt)
+	})
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	return runs, nil
+}
+
+// getRecording loads a run recorded on disk, falling back to the engine.
+func (rc *ReplayCommand) getRecording(ctx context.Context, runID string) (*grpc.Recording, error) {
+	if recording, _, err := recordings.Load(rc.recordingsDir, runID); err == nil {
+		return recording, nil
+	}
+	return rc.engineClient.GetRecording(ctx, runID)
+}
+
 func (rc *ReplayCommand) replayInteractive(ctx context.Context, runID string) error {
 	rc.logger.Info(fmt.Sprintf("🔄 Loading replay for run: %s", runID))
 
-	recording, err := rc.engineClient.GetRecording(ctx, runID)
+	recording, err := rc.getRecording(ctx, runID)
 	if err != nil {
 		return fmt.Errorf("failed to load recording: %w", err)
 	}
@@ -206,12 +254,12 @@ func (rc *ReplayCommand) replayInteractive(ctx context.Context, runID string) er
 func (rc *ReplayCommand) compareRuns(ctx context.Context, runID1, runID2 string) error {
 	rc.logger.Info(fmt.Sprintf("🔄 Comparing runs: %s vs %s", runID1, runID2))
 
-	recording1, err := rc.engineClient.GetRecording(ctx, runID1)
+	recording1, err := rc.getRecording(ctx, runID1)
 	if err != nil {
 		return fmt.Errorf("failed to load first recording: %w", err)
 	}
 
-	recording2, err := rc.engineClient.GetRecording(ctx, runID2)
+	recording2, err := rc.getRecording(ctx, runID2)
 	if err != nil {
 		return fmt.Errorf("failed to load second recording: %w", err)
 	}
@@ -224,14 +272,14 @@ func (rc *ReplayCommand) compareRuns(ctx context.Context, runID1, runID2 string)
 func (rc *ReplayCommand) exportRun(ctx context.Context, runID string) error {
 	rc.logger.Info(fmt.Sprintf("📤 Exporting run: %s", runID))
 
-	recording, err := rc.engineClient.GetRecording(ctx, runID)
+	recording, err := rc.getRecording(ctx, runID)
 	if err != nil {
 		return fmt.Errorf("failed to load recording: %w", err)
 	}
 
 	exporter := NewExporter(rc.export)
 
-	if err := exporter.Export(recording); err != nil {
+	if err := exporter.Export(fromEngine(recording)); err != nil {
 		return fmt.Errorf("export failed: %w", err)
 	}
 
@@ -0,0 +1,459 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/promptdiff"
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/retrybudget"
+	"github.com/sentra-lab/cli/internal/sarif"
+	"github.com/sentra-lab/cli/internal/tokenusage"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type ReportCommand struct {
+	logger *utils.Logger
+}
+
+func NewReportCommand(logger *utils.Logger) *cobra.Command {
+	rc := &ReportCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Inspect the latest test run",
+		Long: `Reports on the latest 'sentra lab test' run.
+
+Commands:
+  • tokens [scenario]   - Per-step token usage heatmap
+  • retries [scenario]  - Retries per scenario and what they cost
+  • backoff [scenario]  - Whether retries honored Retry-After and backed off
+  • sarif               - Guardrail findings as SARIF for code scanning
+
+Example:
+  sentra lab report tokens`,
+	}
+
+	cmd.AddCommand(newTokensCommand(rc))
+	cmd.AddCommand(newRetriesCommand(rc))
+	cmd.AddCommand(newBackoffCommand(rc))
+	cmd.AddCommand(newSARIFCommand(rc))
+
+	return cmd
+}
+
+func newTokensCommand(rc *ReportCommand) *cobra.Command {
+	var (
+		resultsPath   string
+		format        string
+		nearLimitOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tokens [scenario]",
+		Short: "Show per-step prompt and completion tokens and context growth",
+		Long: `Break the latest run's token usage down by scenario step.
+
+For each step with model calls:
+  • Prompt vs completion tokens
+  • Context length after the step, and how much it grew since the last one
+  • Share of the model's context window used, as a heatmap
+
+Steps at or above simulation.context_warning (default 80% of the window)
+are flagged with ⚠: agents close to the limit start truncating history
+silently, so long conversations behave differently in production.
+
+Give a scenario (or part of its name) to show only matching scenarios.
+
+Example:
+  sentra lab report tokens
+  sentra lab report tokens support-escalation
+  sentra lab report tokens --near-limit --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid --format %q (must be text or json)", format)
+			}
+
+			run, err := results.Load(resultsPath)
+			if err != nil {
+				return err
+			}
+
+			var scenarios []results.ScenarioResult
+			for _, scenario := range run.Scenarios {
+				if len(args) == 1 && !strings.Contains(scenario.Scenario, args[0]) {
+					continue
+				}
+				if nearLimitOnly {
+					scenario.Steps = tokenusage.NearLimit(scenario.Steps)
+					if len(scenario.Steps) == 0 {
+						continue
+					}
+				}
+				scenarios = append(scenarios, scenario)
+			}
+
+			if len(args) == 1 && len(scenarios) == 0 && !nearLimitOnly {
+				return fmt.Errorf("no scenario matching %q in %s", args[0], resultsPath)
+			}
+
+			if format == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(scenarios)
+			}
+
+			if len(scenarios) == 0 {
+				rc.logger.Info("No steps near the context window limit")
+				return nil
+			}
+
+			flagged := 0
+			for i, scenario := range scenarios {
+				if i > 0 {
+					fmt.Println()
+				}
+				tokenusage.RenderHeatmap(os.Stdout, scenario.Scenario, scenario.Steps)
+				flagged += len(tokenusage.NearLimit(scenario.Steps))
+			}
+
+			if flagged > 0 {
+				fmt.Printf("\n⚠️  %d step(s) near the context window limit\n", flagged)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Recorded run to report on")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	cmd.Flags().BoolVar(&nearLimitOnly, "near-limit", false, "Only show steps near the context window limit")
+
+	return cmd
+}
+
+func newRetriesCommand(rc *ReportCommand) *cobra.Command {
+	var (
+		resultsPath    string
+		recording      string
+		format         string
+		budget         float64
+		overBudgetOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retries [scenario]",
+		Short: "Show each scenario's retries and the cost and latency they added",
+		Long: `Find the retries in the latest run's requests to the OpenAI mock: the
+same payload re-sent after a failed request.
+
+For each scenario:
+  • Requests, retries and the share of requests that were retries
+  • Cost of the failed attempts that were retried
+  • Latency added: the time from a request's first attempt to its last
+
+Scenarios whose retry rate exceeds the retry budget (--budget, default 10%)
+are flagged with ⚠, and requests retried 3 or more times are listed as
+retry storms: agents that compensate for errors this way burn rate limits,
+money and time in production.
+
+Requests are fetched from the running OpenAI mock, which only captures
+them when simulation.record_full_trace is enabled. Use --recording to
+analyze a saved capture export (GET /_sentra/capture/exchanges) instead.
+
+Example:
+  sentra lab report retries
+  sentra lab report retries --budget 0.05 --over-budget
+  sentra lab report retries --recording exchanges.json --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid --format %q (must be text or json)", format)
+			}
+			if budget < 0 || budget > 1 {
+				return fmt.Errorf("invalid --budget %g (must be between 0 and 1)", budget)
+			}
+
+			var scenarios []retrybudget.Scenario
+			if recording != "" {
+				exchanges, err := promptdiff.LoadExchanges(recording)
+				if err != nil {
+					return err
+				}
+				scenarios = append(scenarios, retrybudget.Analyze(filepath.Base(recording), "", exchanges, budget))
+			} else {
+				configPath, _ := cmd.Flags().GetString("config")
+				analyzed, err := analyzeRetries(cmd, configPath, resultsPath, args, budget)
+				if err != nil {
+					return err
+				}
+				scenarios = analyzed
+			}
+
+			if overBudgetOnly {
+				scenarios = retrybudget.OverBudget(scenarios)
+			}
+
+			if format == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(scenarios)
+			}
+
+			if len(scenarios) == 0 {
+				rc.logger.Info("No scenarios over the retry budget")
+				return nil
+			}
+
+			retrybudget.Render(os.Stdout, scenarios)
+
+			if flagged := len(retrybudget.OverBudget(scenarios)); flagged > 0 {
+				fmt.Printf("\n⚠️  %d scenario(s) over the %.0f%% retry budget\n", flagged, budget*100)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Recorded run to report on")
+	cmd.Flags().StringVar(&recording, "recording", "", "Capture export to analyze instead of the running mock")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	cmd.Flags().Float64Var(&budget, "budget", retrybudget.DefaultBudget, "Share of requests that may be retries")
+	cmd.Flags().BoolVar(&overBudgetOnly, "over-budget", false, "Only show scenarios over the retry budget")
+
+	return cmd
+}
+
+func newBackoffCommand(rc *ReportCommand) *cobra.Command {
+	var (
+		resultsPath    string
+		recording      string
+		format         string
+		violationsOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backoff [scenario]",
+		Short: "Check that retries honored Retry-After and backed off exponentially",
+		Long: `Measure how long the agent waited before each retry in the latest run's
+requests to the OpenAI mock, from the failed response to the retry.
+
+For each retried request:
+  • retry_after - every wait is at least what the failed response asked
+    for (retry-after-ms, the x-ratelimit-reset-* time of the limit that was
+    hit, or Retry-After)
+  • growth      - waits grow by at least 1.5× per retry on average
+  • jitter      - with three or more waits, they do not all grow by the
+    same factor
+
+Scenarios with violations are flagged with ⚠. Agents that retry early are
+rate limited again, and agents that retry in lockstep hit the limit
+together. Use 'expect: backoff_strategy: exponential' to fail a scenario
+step on the same checks.
+
+Requests are fetched from the running OpenAI mock, which only captures
+them when simulation.record_full_trace is enabled. Use --recording to
+analyze a saved capture export (GET /_sentra/capture/exchanges) instead.
+
+Example:
+  sentra lab report backoff
+  sentra lab report backoff rate-limit --violations
+  sentra lab report backoff --recording exchanges.json --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid --format %q (must be text or json)", format)
+			}
+
+			var scenarios []retrybudget.Backoff
+			if recording != "" {
+				exchanges, err := promptdiff.LoadExchanges(recording)
+				if err != nil {
+					return err
+				}
+				scenarios = append(scenarios, retrybudget.CheckBackoff(filepath.Base(recording), "", exchanges))
+			} else {
+				configPath, _ := cmd.Flags().GetString("config")
+				checked, err := checkBackoff(cmd, configPath, resultsPath, args)
+				if err != nil {
+					return err
+				}
+				scenarios = checked
+			}
+
+			if violationsOnly {
+				scenarios = retrybudget.NonConforming(scenarios)
+			}
+
+			if format == "json" {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(scenarios)
+			}
+
+			if len(scenarios) == 0 {
+				rc.logger.Info("No backoff violations")
+				return nil
+			}
+
+			retrybudget.RenderBackoff(os.Stdout, scenarios)
+
+			if flagged := len(retrybudget.NonConforming(scenarios)); flagged > 0 {
+				fmt.Printf("\n⚠️  %d scenario(s) retried without proper backoff\n", flagged)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Recorded run to report on")
+	cmd.Flags().StringVar(&recording, "recording", "", "Capture export to analyze instead of the running mock")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, json)")
+	cmd.Flags().BoolVar(&violationsOnly, "violations", false, "Only show scenarios with backoff violations")
+
+	return cmd
+}
+
+// analyzeRetries fetches the requests of the run's scenarios (those
+// matching args, if given) from the OpenAI mock and finds their retries.
+func analyzeRetries(cmd *cobra.Command, configPath, resultsPath string, args []string, budget float64) ([]retrybudget.Scenario, error) {
+	var scenarios []retrybudget.Scenario
+	err := fetchRunExchanges(cmd, configPath, resultsPath, args, func(scenario, runID string, exchanges []promptdiff.Exchange) {
+		scenarios = append(scenarios, retrybudget.Analyze(scenario, runID, exchanges, budget))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scenarios, nil
+}
+
+// checkBackoff fetches the requests of the run's scenarios (those matching
+// args, if given) from the OpenAI mock and checks how their retries backed
+// off.
+func checkBackoff(cmd *cobra.Command, configPath, resultsPath string, args []string) ([]retrybudget.Backoff, error) {
+	var scenarios []retrybudget.Backoff
+	err := fetchRunExchanges(cmd, configPath, resultsPath, args, func(scenario, runID string, exchanges []promptdiff.Exchange) {
+		scenarios = append(scenarios, retrybudget.CheckBackoff(scenario, runID, exchanges))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scenarios, nil
+}
+
+// fetchRunExchanges fetches the requests of the run's scenarios (those
+// matching args, if given) from the OpenAI mock and passes each scenario's
+// to visit.
+func fetchRunExchanges(cmd *cobra.Command, configPath, resultsPath string, args []string, visit func(scenario, runID string, exchanges []promptdiff.Exchange)) error {
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+	loader, err := config.NewLoader(configPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	mock, ok := cfg.Mocks["openai"]
+	if !ok || !mock.Enabled {
+		return fmt.Errorf("the OpenAI mock is not enabled in %s", configPath)
+	}
+	mockURL := fmt.Sprintf("http://localhost:%d", mock.Port)
+
+	run, err := results.Load(resultsPath)
+	if err != nil {
+		return err
+	}
+
+	matched, captured := 0, 0
+	for _, scenario := range run.Scenarios {
+		if len(args) == 1 && !strings.Contains(scenario.Scenario, args[0]) {
+			continue
+		}
+		if scenario.RunID == "" {
+			continue
+		}
+
+		exchanges, err := promptdiff.FetchExchanges(cmd.Context(), mockURL, scenario.RunID)
+		if err != nil {
+			return err
+		}
+		matched++
+		captured += len(exchanges)
+		visit(scenario.Scenario, scenario.RunID, exchanges)
+	}
+
+	if len(args) == 1 && matched == 0 {
+		return fmt.Errorf("no scenario matching %q in %s", args[0], resultsPath)
+	}
+	if captured == 0 {
+		return fmt.Errorf("no requests were captured (is simulation.record_full_trace enabled?)")
+	}
+
+	return nil
+}
+
+func newSARIFCommand(rc *ReportCommand) *cobra.Command {
+	var (
+		resultsPath string
+		output      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sarif",
+		Short: "Export guardrail findings as SARIF",
+		Long: `Write the latest run's guardrail findings as SARIF 2.1.0.
+
+assert_guardrails steps report leaked canaries and secrets, disallowed tool
+calls and successful prompt injections. Uploaded to GitHub code scanning,
+they show up as security alerts on the pull request, located at the step
+that found them, rather than only as failed tests.
+
+The file is written even when there are no findings, so code scanning
+closes alerts that were fixed.
+
+Example:
+  sentra lab test; sentra lab report sarif -o guardrails.sarif`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			run, err := results.Load(resultsPath)
+			if err != nil {
+				return err
+			}
+
+			log := sarif.Build(run, repositoryPrefix())
+			if err := log.Write(output); err != nil {
+				return err
+			}
+
+			rc.logger.Info(fmt.Sprintf("%d guardrail finding(s) written to %s", log.Count(), output))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resultsPath, "results", results.LatestPath, "Recorded run to report on")
+	cmd.Flags().StringVarP(&output, "output", "o", ".sentra-lab/results/guardrails.sarif", "SARIF file to write")
+
+	return cmd
+}
+
+// repositoryPrefix returns the current directory's path within its git
+// repository ("" at the root or outside a repository).
+func repositoryPrefix() string {
+	out, err := exec.Command("git", "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
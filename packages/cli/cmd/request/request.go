@@ -0,0 +1,461 @@
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/labenv"
+	"github.com/sentra-lab/cli/internal/ui"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// requestTimeout bounds the request, stream included
+const requestTimeout = 2 * time.Minute
+
+// operation is a request the command knows how to build.
+type operation struct {
+	method string
+	path   string
+	help   string
+}
+
+// operations are the supported requests, by mock and name
+var operations = map[string]map[string]operation{
+	"openai": {
+		"chat":       {http.MethodPost, "/v1/chat/completions", "Chat completion (--model, --system, --user, --stream)"},
+		"embeddings": {http.MethodPost, "/v1/embeddings", "Embeddings of --user (--model)"},
+		"models":     {http.MethodGet, "/v1/models", "List models"},
+	},
+	"stripe": {
+		"payment-intent": {http.MethodPost, "/v1/payment_intents", "Create a payment intent (--amount, --currency)"},
+		"balance":        {http.MethodGet, "/v1/balance", "Retrieve the balance"},
+	},
+}
+
+type RequestCommand struct {
+	logger *utils.Logger
+}
+
+// requestOptions are the flags that build the request
+type requestOptions struct {
+	model       string
+	system      string
+	user        []string
+	stream      bool
+	maxTokens   int
+	temperature float64
+	amount      int64
+	currency    string
+	data        string
+	headers     bool
+	save        string
+	fixtureID   string
+	pattern     string
+}
+
+func NewRequestCommand(logger *utils.Logger) *cobra.Command {
+	rc := &RequestCommand{
+		logger: logger,
+	}
+
+	var opts requestOptions
+
+	cmd := &cobra.Command{
+		Use:   "request <mock> <operation>",
+		Short: "Send one request to a mock and pretty-print the response",
+		Long: `Build a valid request, send it to the local mock with the credentials agents
+use, and pretty-print the response with its latency, token usage and cost.
+A chat response can be saved as a fixture with --save, which appends it to
+the fixture file (created if missing) - handy for authoring fixtures.
+
+Operations:
+` + operationHelp() + `
+Example:
+  sentra lab request openai chat --model gpt-4o --user "hello"
+  sentra lab request openai chat --user "hello" --stream
+  sentra lab request openai chat --user "refund order 42" --save fixtures/refunds.yaml
+  sentra lab request stripe payment-intent --amount 2000 --currency eur
+  sentra lab request openai chat --data '{"model":"gpt-4o","messages":[...]}'`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			op, ok := operations[args[0]][args[1]]
+			if !ok {
+				return fmt.Errorf("unknown operation %q for the %s mock\n\nOperations:\n%s", args[1], args[0], operationHelp())
+			}
+			if opts.save != "" && (args[0] != "openai" || args[1] != "chat") {
+				return fmt.Errorf("--save only works with openai chat")
+			}
+			if opts.save != "" && len(opts.user) == 0 {
+				return fmt.Errorf("--save needs --user, whose last message becomes the fixture's pattern")
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			mock, ok := cfg.Mocks[args[0]]
+			if !ok || !mock.Enabled {
+				return fmt.Errorf("the %s mock is not enabled in %s", args[0], configPath)
+			}
+
+			body, contentType, err := buildBody(args[0], args[1], opts)
+			if err != nil {
+				return err
+			}
+
+			return rc.send(cmd, fmt.Sprintf("http://localhost:%d", mock.Port), args[0], op, body, contentType, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.model, "model", "m", "", "Model (default: gpt-4o-mini, or text-embedding-3-small for embeddings)")
+	cmd.Flags().StringVar(&opts.system, "system", "", "System message")
+	cmd.Flags().StringArrayVarP(&opts.user, "user", "u", nil, "User message (repeat for several)")
+	cmd.Flags().BoolVar(&opts.stream, "stream", false, "Stream the response")
+	cmd.Flags().IntVar(&opts.maxTokens, "max-tokens", 0, "Maximum completion tokens")
+	cmd.Flags().Float64Var(&opts.temperature, "temperature", -1, "Sampling temperature")
+	cmd.Flags().Int64Var(&opts.amount, "amount", 1000, "Amount in minor units (payment-intent)")
+	cmd.Flags().StringVar(&opts.currency, "currency", "usd", "Currency (payment-intent)")
+	cmd.Flags().StringVarP(&opts.data, "data", "d", "", "Raw request body, instead of one built from flags")
+	cmd.Flags().BoolVarP(&opts.headers, "include", "i", false, "Print every response header")
+	cmd.Flags().StringVar(&opts.save, "save", "", "Append the response to this fixture file")
+	cmd.Flags().StringVar(&opts.fixtureID, "fixture-id", "", "ID of the saved fixture (default: derived from the message)")
+	cmd.Flags().StringVar(&opts.pattern, "pattern", "", "Prompt pattern of the saved fixture (default: the last user message)")
+
+	return cmd
+}
+
+func operationHelp() string {
+	var b strings.Builder
+	for _, mock := range []string{"openai", "stripe"} {
+		names := make([]string, 0, len(operations[mock]))
+		for name := range operations[mock] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, "  %-24s %s\n", mock+" "+name, operations[mock][name].help)
+		}
+	}
+	return b.String()
+}
+
+// buildBody builds the request body from the flags.
+func buildBody(mock, name string, opts requestOptions) ([]byte, string, error) {
+	if opts.data != "" {
+		contentType := "application/json"
+		if mock == "stripe" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		return []byte(opts.data), contentType, nil
+	}
+
+	switch mock + " " + name {
+	case "openai chat":
+		if len(opts.user) == 0 {
+			return nil, "", fmt.Errorf("--user is required")
+		}
+		var messages []map[string]string
+		if opts.system != "" {
+			messages = append(messages, map[string]string{"role": "system", "content": opts.system})
+		}
+		for _, content := range opts.user {
+			messages = append(messages, map[string]string{"role": "user", "content": content})
+		}
+
+		request := map[string]interface{}{
+			"model":    orDefault(opts.model, "gpt-4o-mini"),
+			"messages": messages,
+		}
+		if opts.stream {
+			request["stream"] = true
+			request["stream_options"] = map[string]bool{"include_usage": true}
+		}
+		if opts.maxTokens > 0 {
+			request["max_tokens"] = opts.maxTokens
+		}
+		if opts.temperature >= 0 {
+			request["temperature"] = opts.temperature
+		}
+		body, err := json.Marshal(request)
+		return body, "application/json", err
+
+	case "openai embeddings":
+		if len(opts.user) == 0 {
+			return nil, "", fmt.Errorf("--user is required")
+		}
+		var input interface{} = opts.user
+		if len(opts.user) == 1 {
+			input = opts.user[0]
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"model": orDefault(opts.model, "text-embedding-3-small"),
+			"input": input,
+		})
+		return body, "application/json", err
+
+	case "stripe payment-intent":
+		form := url.Values{}
+		form.Set("amount", strconv.FormatInt(opts.amount, 10))
+		form.Set("currency", strings.ToLower(opts.currency))
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	return nil, "", nil
+}
+
+// send sends the request and prints the response.
+func (rc *RequestCommand) send(cmd *cobra.Command, baseURL, mock string, op operation, body []byte, contentType string, opts requestOptions) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(cmd.Context(), op.method, baseURL+op.path, reader)
+	if err != nil {
+		return err
+	}
+
+	apiKey := labenv.MockAPIKey
+	if mock == "stripe" {
+		apiKey = labenv.StripeAPIKey
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	rc.logger.Debug("Sending request", "method", op.method, "url", req.URL.String())
+
+	client := &http.Client{Timeout: requestTimeout}
+	started := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed (is the %s mock running? try 'sentra lab start'): %w", mock, err)
+	}
+	defer resp.Body.Close()
+	firstByte := time.Since(started)
+
+	printStatus(resp, opts.headers)
+
+	var content string
+	streamed := strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+	if streamed {
+		content, err = printStream(resp.Body)
+		if err != nil {
+			return err
+		}
+	} else {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			fmt.Println(ui.HighlightJSON(data))
+		}
+		content = completionContent(data)
+	}
+
+	printSummary(resp.Header, firstByte, time.Since(started), streamed)
+
+	if opts.save == "" {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("not saving a fixture: the mock returned %s", resp.Status)
+	}
+	if content == "" {
+		return fmt.Errorf("not saving a fixture: the response has no message content")
+	}
+
+	id, err := saveFixture(opts, content)
+	if err != nil {
+		return err
+	}
+	rc.logger.Info(fmt.Sprintf("✓ Saved fixture %s to %s", id, opts.save))
+	return nil
+}
+
+func printStatus(resp *http.Response, all bool) {
+	color := utils.ColorGreen
+	switch {
+	case resp.StatusCode >= 500:
+		color = utils.ColorRed
+	case resp.StatusCode >= 400:
+		color = utils.ColorYellow
+	}
+	fmt.Printf("%s%s %s%s\n", color, resp.Proto, resp.Status, utils.ColorReset)
+
+	if !all {
+		return
+	}
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s%s:%s %s\n", utils.ColorMagenta, name, utils.ColorReset, strings.Join(resp.Header[name], ", "))
+	}
+	fmt.Println()
+}
+
+// printStream prints a chat completion stream's content as it arrives and
+// returns it.
+func printStream(body io.Reader) (string, error) {
+	var content strings.Builder
+	var usage json.RawMessage
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage json.RawMessage `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		for _, choice := range chunk.Choices {
+			fmt.Print(choice.Delta.Content)
+			content.WriteString(choice.Delta.Content)
+		}
+		if len(chunk.Usage) > 0 && string(chunk.Usage) != "null" {
+			usage = chunk.Usage
+		}
+	}
+	fmt.Println()
+
+	if usage != nil {
+		fmt.Println(ui.HighlightJSON(usage))
+	}
+	return content.String(), scanner.Err()
+}
+
+// printSummary prints latency, tokens and cost from the response headers.
+func printSummary(header http.Header, firstByte, total time.Duration, streamed bool) {
+	parts := []string{fmt.Sprintf("latency %s", total.Round(time.Millisecond))}
+	if streamed {
+		parts = append(parts, fmt.Sprintf("first byte %s", firstByte.Round(time.Millisecond)))
+	}
+	if tokens := header.Get("X-Sentra-Tokens-Total"); tokens != "" {
+		parts = append(parts, fmt.Sprintf("%s tokens (%s in, %s out)", tokens,
+			header.Get("X-Sentra-Tokens-Input"), header.Get("X-Sentra-Tokens-Output")))
+	}
+	if cost := header.Get("X-Sentra-Cost-Total"); cost != "" {
+		parts = append(parts, fmt.Sprintf("cost $%s", cost))
+	}
+	if remaining := header.Get("x-ratelimit-remaining-requests"); remaining != "" {
+		parts = append(parts, fmt.Sprintf("%s requests left", remaining))
+	}
+
+	fmt.Printf("\n%s%s%s\n", utils.ColorCyan, strings.Join(parts, " · "), utils.ColorReset)
+}
+
+// completionContent returns the first choice's message content of a chat
+// completion.
+func completionContent(data []byte) string {
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if json.Unmarshal(data, &completion) != nil || len(completion.Choices) == 0 {
+		return ""
+	}
+	return completion.Choices[0].Message.Content
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// saveFixture appends a response fixture to opts.save, in the OpenAI mock's
+// fixture format, and returns its ID.
+func saveFixture(opts requestOptions, content string) (string, error) {
+	prompt := opts.user[len(opts.user)-1]
+
+	id := opts.fixtureID
+	if id == "" {
+		id = strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(prompt), "-"), "-")
+		if len(id) > 40 {
+			id = strings.TrimRight(id[:40], "-")
+		}
+	}
+
+	pattern := opts.pattern
+	if pattern == "" {
+		pattern = "(?i)" + regexp.QuoteMeta(prompt)
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("invalid --pattern: %w", err)
+	}
+
+	file := map[string]interface{}{
+		"description": "Responses saved with 'sentra lab request'",
+		"category":    "chat",
+	}
+	if existing, err := os.ReadFile(opts.save); err == nil {
+		if err := yaml.Unmarshal(existing, &file); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", opts.save, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	responses, _ := file["responses"].([]interface{})
+	for _, response := range responses {
+		if fixture, ok := response.(map[string]interface{}); ok && fixture["id"] == id {
+			return "", fmt.Errorf("%s already has a fixture %q (use --fixture-id)", opts.save, id)
+		}
+	}
+	file["responses"] = append(responses, map[string]interface{}{
+		"id":            id,
+		"pattern":       pattern,
+		"content":       content,
+		"role":          "assistant",
+		"finish_reason": "stop",
+	})
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return "", err
+	}
+	return id, os.WriteFile(opts.save, data, 0644)
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
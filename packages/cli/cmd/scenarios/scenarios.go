@@ -0,0 +1,274 @@
+package scenarios
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/grpc"
+	"github.com/sentra-lab/cli/internal/scenario"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type ScenariosCommand struct {
+	logger *utils.Logger
+}
+
+func NewScenariosCommand(logger *utils.Logger) *cobra.Command {
+	sc := &ScenariosCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "scenarios",
+		Short: "Manage test scenarios",
+		Long: `Work with scenario files.
+
+Commands:
+  • validate [paths...]        - Lint scenario YAML
+  • generate --from-run <id>   - Draft a scenario from a recorded run
+
+Examples:
+  sentra lab scenarios validate
+  sentra lab scenarios validate scenarios/payment-flow.yaml --strict
+  sentra lab scenarios generate --from-run run-abc123`,
+	}
+
+	cmd.AddCommand(newValidateCommand(sc))
+	cmd.AddCommand(newGenerateCommand(sc))
+
+	return cmd
+}
+
+func newValidateCommand(sc *ScenariosCommand) *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "validate [paths...]",
+		Short: "Lint scenario files",
+		Long: `Lint scenario YAML without running it.
+
+This checks:
+  • Unknown action names and missing required fields
+  • Variables that are referenced but not declared, or declared but unused
+  • Scheduled steps the virtual clock never reaches
+  • Missing depends_on files and malformed outputs
+  • Percentile latency expectations without enough repeats
+  • Steps with no expectations
+  • Invalid duration (<10s, 500ms) and cost (<$0.10) expressions
+
+Paths may be files or directories (default: scenarios/).
+Errors always fail; use --strict to fail on warnings too (for CI).
+
+Example:
+  sentra lab scenarios validate --strict`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"scenarios"}
+			}
+
+			files, err := findScenarioFiles(args)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no scenario files found in: %s", strings.Join(args, ", "))
+			}
+
+			linter := scenario.NewLinter()
+			failed := 0
+			warnings := 0
+
+			for _, file := range files {
+				result, err := linter.LintFile(file)
+				if err != nil {
+					return err
+				}
+
+				warnings += len(result.Warnings)
+				if !result.Passed(strict) {
+					failed++
+				}
+
+				sc.printResult(result)
+			}
+
+			sc.logger.Info("")
+			if failed > 0 {
+				return fmt.Errorf("%d of %d scenario(s) failed validation", failed, len(files))
+			}
+
+			if warnings > 0 {
+				sc.logger.Info(fmt.Sprintf("✅ %d scenario(s) valid (%d warning(s))", len(files), warnings))
+			} else {
+				sc.logger.Info(fmt.Sprintf("✅ %d scenario(s) valid", len(files)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on warnings")
+
+	return cmd
+}
+
+func newGenerateCommand(sc *ScenariosCommand) *cobra.Command {
+	var (
+		runID    string
+		output   string
+		name     string
+		headroom float64
+		force    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate --from-run <run-id>",
+		Short: "Draft a scenario from a recorded run",
+		Long: `Convert a recorded run (e.g. an exploratory session against the mocks)
+into a draft scenario.
+
+Each recorded agent input becomes an agent_request step. Its expectations
+are derived from what the run did:
+  • calls: the mock endpoints the agent called
+  • response_contains: amounts, numbers and quoted phrases from the output
+  • status: success, when the step recorded no errors
+  • A final verify_cost step caps total cost at the recorded cost x headroom
+
+Review and adjust the expectations before committing the scenario.
+
+Example:
+  sentra lab scenarios generate --from-run run-abc123 --output scenarios/refund.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runID == "" {
+				return fmt.Errorf("--from-run is required")
+			}
+			if output == "" {
+				output = filepath.Join("scenarios", runID+".yaml")
+			}
+			if _, err := os.Stat(output); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to parse config: %w", err)
+			}
+
+			engineClient, err := grpc.NewEngineClient(cfg.GetEngineAddress())
+			if err != nil {
+				return fmt.Errorf("failed to create engine client: %w", err)
+			}
+			defer engineClient.Close()
+
+			recording, err := engineClient.GetRecording(cmd.Context(), runID)
+			if err != nil {
+				return fmt.Errorf("failed to load recording: %w", err)
+			}
+
+			generated, err := scenario.GenerateFromRecording(recording, scenario.GenerateOptions{
+				Name:         name,
+				CostHeadroom: headroom,
+			})
+			if err != nil {
+				return err
+			}
+
+			data, err := generated.Marshal(runID)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("failed to write scenario: %w", err)
+			}
+
+			sc.logger.Info(fmt.Sprintf("✅ Generated %s (%d steps) from %s", output, len(generated.Steps), runID))
+
+			result := scenario.NewLinter().Lint(data)
+			result.Path = output
+			if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+				sc.printResult(result)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&runID, "from-run", "", "Run ID to convert")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: scenarios/<run-id>.yaml)")
+	cmd.Flags().StringVar(&name, "name", "", "Scenario name")
+	cmd.Flags().Float64Var(&headroom, "cost-headroom", 1.5, "Multiplier applied to the recorded cost for the cost ceiling")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+
+	return cmd
+}
+
+func (sc *ScenariosCommand) printResult(result *scenario.LintResult) {
+	if len(result.Errors) == 0 && len(result.Warnings) == 0 {
+		sc.logger.Info(fmt.Sprintf("✓ %s", result.Path))
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		sc.logger.Error(fmt.Sprintf("✗ %s", result.Path))
+	} else {
+		sc.logger.Warn(fmt.Sprintf("⚠ %s", result.Path))
+	}
+
+	for _, issue := range result.Errors {
+		sc.logger.Error(fmt.Sprintf("    %s", issue.Error()))
+	}
+	for _, issue := range result.Warnings {
+		sc.logger.Warn(fmt.Sprintf("    %s", issue.Error()))
+	}
+}
+
+func findScenarioFiles(paths []string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(p)
+			if !fi.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,105 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/seed"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type SeedCommand struct {
+	logger *utils.Logger
+	dir    string
+}
+
+func NewSeedCommand(logger *utils.Logger) *cobra.Command {
+	sc := &SeedCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Apply or reset the initial mock state declared in seeds/",
+		Long: `Seed the mocks with the records declared in seeds/*.yaml: Stripe
+customers and products, OpenAI files and vector stores, CoreLedger
+accounts, ...
+
+Seeds are applied on 'sentra lab start'. Mocks keep them as the state
+they reset to between scenarios.
+
+Commands:
+  • apply   - Replace the mocks' seeded records with seeds/
+  • reset   - Discard all mock state, then apply seeds/
+
+The mocks must be running ('sentra lab start').
+
+Example:
+  sentra lab seed apply
+  sentra lab seed reset`,
+	}
+
+	cmd.PersistentFlags().StringVar(&sc.dir, "dir", seed.Dir, "Seeds directory")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "apply",
+		Short: "Replace the mocks' seeded records with seeds/",
+		Long: `Send the records in seeds/ to the mocks. Records removed from seeds/ are
+removed from the mocks; state built up by scenarios is left alone.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sc.run(cmd, (*seed.Applier).Apply)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reset",
+		Short: "Discard all mock state, then apply seeds/",
+		Long: `Reset every enabled mock (usage, threads, rate limit buckets, ...) and
+apply seeds/, so the mocks hold exactly the seeded records.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sc.run(cmd, (*seed.Applier).Reset)
+		},
+	})
+
+	return cmd
+}
+
+func (sc *SeedCommand) run(cmd *cobra.Command, apply func(*seed.Applier, context.Context, *seed.Seeds) ([]seed.Result, error)) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+
+	loader, err := config.NewLoader(configPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	seeds, err := seed.Load(sc.dir)
+	if err != nil {
+		return err
+	}
+
+	results, err := apply(seed.NewApplier(cfg), cmd.Context(), seeds)
+	if err != nil {
+		return err
+	}
+
+	sc.logger.Info(fmt.Sprintf("🌱 Seeded from %d file(s) in %s:", len(seeds.Files), sc.dir))
+	for _, result := range results {
+		if result.Unsupported {
+			sc.logger.Warn(fmt.Sprintf("  ⚠ %s", result.Describe()))
+		} else {
+			sc.logger.Info(fmt.Sprintf("  ✓ %s", result.Describe()))
+		}
+	}
+	return nil
+}
@@ -3,13 +3,34 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime/debug"
+	"time"
 
+	"github.com/sentra-lab/cli/cmd/ci"
 	"github.com/sentra-lab/cli/cmd/cloud"
 	"github.com/sentra-lab/cli/cmd/config"
-	"github.com/sentra-lab/cli/cmd/init"
+	"github.com/sentra-lab/cli/cmd/costs"
+	"github.com/sentra-lab/cli/cmd/daemon"
+	"github.com/sentra-lab/cli/cmd/deploy"
+	"github.com/sentra-lab/cli/cmd/demo"
+	"github.com/sentra-lab/cli/cmd/diff"
+	"github.com/sentra-lab/cli/cmd/egress"
+	"github.com/sentra-lab/cli/cmd/env"
+	initcmd "github.com/sentra-lab/cli/cmd/init"
+	"github.com/sentra-lab/cli/cmd/mocks"
 	"github.com/sentra-lab/cli/cmd/replay"
+	"github.com/sentra-lab/cli/cmd/report"
+	"github.com/sentra-lab/cli/cmd/request"
+	"github.com/sentra-lab/cli/cmd/scenarios"
+	"github.com/sentra-lab/cli/cmd/seed"
+	"github.com/sentra-lab/cli/cmd/shell"
 	"github.com/sentra-lab/cli/cmd/start"
+	"github.com/sentra-lab/cli/cmd/support"
+	"github.com/sentra-lab/cli/cmd/telemetry"
 	"github.com/sentra-lab/cli/cmd/test"
+	"github.com/sentra-lab/cli/cmd/webhooks"
+	"github.com/sentra-lab/cli/internal/diagnostics"
+	labtelemetry "github.com/sentra-lab/cli/internal/telemetry"
 	"github.com/sentra-lab/cli/internal/utils"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +42,7 @@ var (
 )
 
 func main() {
+	utils.InitColors()
 	logger := utils.NewLogger("sentra-lab", "info")
 
 	rootCmd := &cobra.Command{
@@ -39,11 +61,13 @@ Get started:
   sentra lab init my-agent    # Create new project
   sentra lab start            # Start mock services
   sentra lab test             # Run test scenarios`,
-		Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
+		Version: versionString(),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			verbose, _ := cmd.Flags().GetBool("verbose")
-			if verbose {
-				logger.SetLevel("debug")
+			if err := configureLogging(cmd, logger); err != nil {
+				return err
+			}
+			if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+				utils.DisableColors()
 			}
 			return nil
 		},
@@ -51,6 +75,10 @@ Get started:
 
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().String("config", "", "Config file (default: ./lab.yaml)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also NO_COLOR)")
+	rootCmd.PersistentFlags().String("log-level", "info", "Log level, optionally per subsystem: info,docker=debug,engine=warn (also SENTRA_LOG_LEVEL)")
+	rootCmd.PersistentFlags().String("log-format", utils.FormatPretty, "Log format: pretty, text or json (also SENTRA_LOG_FORMAT)")
+	rootCmd.PersistentFlags().String("log-file", "", "Also write logs to this file (also SENTRA_LOG_FILE)")
 
 	labCmd := &cobra.Command{
 		Use:   "lab",
@@ -59,12 +87,30 @@ Get started:
 	}
 
 	labCmd.AddCommand(
-		init.NewInitCommand(logger),
+		initcmd.NewInitCommand(logger),
 		start.NewStartCommand(logger),
 		test.NewTestCommand(logger),
 		replay.NewReplayCommand(logger),
+		scenarios.NewScenariosCommand(logger),
 		config.NewConfigCommand(logger),
 		cloud.NewCloudCommand(logger),
+		ci.NewCICommand(logger),
+		demo.NewDemoCommand(logger),
+		diff.NewDiffCommand(logger),
+		report.NewReportCommand(logger),
+		costs.NewCostsCommand(logger),
+		webhooks.NewWebhooksCommand(logger),
+		egress.NewEgressCommand(logger),
+		env.NewEnvCommand(logger),
+		env.NewRunCommand(logger),
+		telemetry.NewTelemetryCommand(logger),
+		support.NewSupportCommand(logger, versionString()),
+		daemon.NewDaemonCommand(logger, versionString()),
+		deploy.NewDeployCommand(logger),
+		mocks.NewMocksCommand(logger),
+		shell.NewShellCommand(logger),
+		request.NewRequestCommand(logger),
+		seed.NewSeedCommand(logger),
 	)
 
 	labCmd.AddCommand(newStopCommand(logger))
@@ -74,19 +120,93 @@ Get started:
 
 	rootCmd.AddCommand(labCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	// Crashes leave a diagnostics bundle to attach to a bug report
+	configPath := func() string {
+		path, _ := rootCmd.PersistentFlags().GetString("config")
+		if path == "" {
+			return "lab.yaml"
+		}
+		return path
+	}
+	logger.OnFatal(func(msg string) {
+		reportCrash(logger, configPath(), msg, debug.Stack())
+	})
+	defer func() {
+		if r := recover(); r != nil {
+			reportCrash(logger, configPath(), fmt.Sprintf("panic: %v", r), debug.Stack())
+			os.Exit(2)
+		}
+	}()
+
+	started := time.Now()
+	executed, err := rootCmd.ExecuteC()
+	if executed != nil {
+		labtelemetry.Track(executed.CommandPath(), version, time.Since(started), err)
+	}
+
+	if err != nil {
 		logger.Error("command failed", "error", err)
 		os.Exit(1)
 	}
 }
 
+// configureLogging applies the logging flags, falling back to their
+// SENTRA_LOG_* environment variables. --verbose lowers the default level to
+// debug but keeps per-subsystem levels.
+func configureLogging(cmd *cobra.Command, logger *utils.Logger) error {
+	setting := func(flag, env string) string {
+		value, _ := cmd.Flags().GetString(flag)
+		if !cmd.Flags().Changed(flag) {
+			if fromEnv := os.Getenv(env); fromEnv != "" {
+				return fromEnv
+			}
+		}
+		return value
+	}
+
+	if err := logger.SetLevels(setting("log-level", "SENTRA_LOG_LEVEL")); err != nil {
+		return err
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		logger.SetLevel("debug")
+	}
+	if err := logger.SetFormat(setting("log-format", "SENTRA_LOG_FORMAT")); err != nil {
+		return err
+	}
+	if path := setting("log-file", "SENTRA_LOG_FILE"); path != "" {
+		return logger.SetOutputFile(path)
+	}
+	return nil
+}
+
+func versionString() string {
+	return fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
+}
+
+// reportCrash writes a diagnostics bundle for a panic or fatal error and
+// tells the user where to send it.
+func reportCrash(logger *utils.Logger, configPath, reason string, stack []byte) {
+	path, err := diagnostics.Write(diagnostics.Dir, diagnostics.Report{
+		Reason:     reason,
+		Stack:      stack,
+		Logs:       logger.History(),
+		ConfigPath: configPath,
+		Version:    versionString(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n💥 Sentra Lab crashed (%s); failed to write diagnostics: %v\n", reason, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n💥 Sentra Lab crashed (%s). Please attach %s to a bug report: %s\n", reason, path, diagnostics.IssuesURL)
+}
+
 func newStopCommand(logger *utils.Logger) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop all Sentra Lab services",
 		Long:  "Gracefully shutdown all Docker containers and cleanup resources",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return start.NewStartCommand(logger).Stop(cmd.Context())
+			return start.New(logger).Stop(cmd.Context())
 		},
 	}
 	return cmd
@@ -107,7 +227,7 @@ func newLogsCommand(logger *utils.Logger) *cobra.Command {
 				service = args[0]
 			}
 			
-			return start.NewStartCommand(logger).Logs(cmd.Context(), service, follow, tail)
+			return start.New(logger).Logs(cmd.Context(), service, follow, tail)
 		},
 	}
 	
@@ -123,7 +243,7 @@ func newStatusCommand(logger *utils.Logger) *cobra.Command {
 		Short: "Show service status",
 		Long:  "Display health and status of all Sentra Lab services",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return start.NewStartCommand(logger).Status(cmd.Context())
+			return start.New(logger).Status(cmd.Context())
 		},
 	}
 	return cmd
@@ -142,12 +262,11 @@ Example:
   sentra lab quickstart my-agent`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
 			name := args[0]
 			
 			logger.Info("🚀 Quickstart: initializing project...", "name", name)
 			
-			initCmd := init.NewInitCommand(logger)
+			initCmd := initcmd.NewInitCommand(logger)
 			if err := initCmd.RunE(cmd, []string{name}); err != nil {
 				return fmt.Errorf("init failed: %w", err)
 			}
@@ -159,6 +278,9 @@ Example:
 			logger.Info("🔧 Quickstart: starting services...")
 			
 			startCmd := start.NewStartCommand(logger)
+			if err := startCmd.PreRunE(cmd, []string{}); err != nil {
+				return fmt.Errorf("start failed: %w", err)
+			}
 			if err := startCmd.RunE(cmd, []string{}); err != nil {
 				return fmt.Errorf("start failed: %w", err)
 			}
@@ -166,6 +288,9 @@ Example:
 			logger.Info("🧪 Quickstart: running tests...")
 			
 			testCmd := test.NewTestCommand(logger)
+			if err := testCmd.PreRunE(cmd, []string{}); err != nil {
+				return fmt.Errorf("test failed: %w", err)
+			}
 			if err := testCmd.RunE(cmd, []string{}); err != nil {
 				return fmt.Errorf("test failed: %w", err)
 			}
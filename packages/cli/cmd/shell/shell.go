@@ -0,0 +1,555 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/labenv"
+	"github.com/sentra-lab/cli/internal/ui"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// requestTimeout bounds a single request, so a stalled mock does not hang
+// the shell
+const requestTimeout = 60 * time.Second
+
+// summaryHeaders are shown with every response, headers or not
+var summaryHeaders = []string{
+	"x-ratelimit-remaining-requests",
+	"x-ratelimit-remaining-tokens",
+	"X-Sentra-Cost-Total",
+	"X-Request-Id",
+}
+
+type ShellCommand struct {
+	logger *utils.Logger
+}
+
+func NewShellCommand(logger *utils.Logger) *cobra.Command {
+	sc := &ShellCommand{
+		logger: logger,
+	}
+
+	var (
+		service string
+		model   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Explore the running mocks interactively",
+		Long: `Start a REPL against the running mocks: hand-craft chat completions,
+send raw requests, tweak error injection and inspect rate limit buckets and
+usage, with highlighted JSON and line history (up/down, tab completes
+commands). Type 'help' for the commands.
+
+Commands are read from stdin when it is not a terminal, so a session can
+be scripted.
+
+Example:
+  sentra lab shell
+  sentra lab shell --service stripe
+  echo 'chat Say hi' | sentra lab shell`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+			loader, err := config.NewLoader(configPath)
+			if err != nil {
+				return err
+			}
+			cfg, err := loader.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			s := &session{
+				ports:   make(map[string]int),
+				service: service,
+				model:   model,
+				client:  &http.Client{Timeout: requestTimeout},
+			}
+			for name, mock := range cfg.Mocks {
+				if mock.Enabled {
+					s.ports[name] = mock.Port
+				}
+			}
+			if len(s.ports) == 0 {
+				return fmt.Errorf("no mocks are enabled in %s", configPath)
+			}
+			if _, ok := s.ports[s.service]; !ok {
+				return fmt.Errorf("the %s mock is not enabled in %s", s.service, configPath)
+			}
+
+			return sc.run(cmd.Context(), s)
+		},
+	}
+
+	cmd.Flags().StringVarP(&service, "service", "s", "openai", "Mock to send requests to")
+	cmd.Flags().StringVarP(&model, "model", "m", "gpt-4o-mini", "Model for chat")
+
+	return cmd
+}
+
+// run reads commands until exit or end of input.
+func (sc *ShellCommand) run(ctx context.Context, s *session) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		s.out = os.Stdout
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		for scanner.Scan() {
+			if s.execute(ctx, scanner.Text()) {
+				return nil
+			}
+		}
+		return scanner.Err()
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "")
+	terminal.AutoCompleteCallback = completeCommand
+	s.out = terminal
+
+	s.printf("Sentra Lab shell - %s mock on port %d. Type 'help' for commands, 'exit' to quit.\n", s.service, s.ports[s.service])
+	for {
+		terminal.SetPrompt(fmt.Sprintf("%s%s>%s ", utils.ColorCyan, s.service, utils.ColorReset))
+		line, err := terminal.ReadLine()
+		if err == io.EOF {
+			s.printf("\n")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.execute(ctx, line) {
+			return nil
+		}
+	}
+}
+
+// session is the state of a shell: the mock in use and the conversation
+// built up by chat.
+type session struct {
+	ports   map[string]int
+	service string
+	model   string
+	client  *http.Client
+	out     io.Writer
+
+	// messages are the chat conversation so far
+	messages []map[string]string
+	system   string
+
+	// last is the body of the last response
+	last []byte
+
+	showHeaders bool
+}
+
+// command is a shell command; rest is the line after the command name.
+type command struct {
+	usage string
+	help  string
+	run   func(ctx context.Context, s *session, args []string, rest string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"help":    {"help", "Show this help", cmdHelp},
+		"use":     {"use [mock]", "Send requests to another mock, or list the enabled ones", cmdUse},
+		"model":   {"model [id]", "Show or set the model for chat", cmdModel},
+		"system":  {"system [prompt]", "Set the system prompt for chat (empty clears it)", cmdSystem},
+		"chat":    {"chat <message>", "Send a message and continue the conversation", cmdChat},
+		"reset":   {"reset", "Start a new conversation", cmdReset},
+		"last":    {"last", "Show the full body of the last response", cmdLast},
+		"get":     {"get <path>", "GET a path of the mock", cmdRequest(http.MethodGet)},
+		"post":    {"post <path> [body]", "POST a JSON (or form) body", cmdRequest(http.MethodPost)},
+		"put":     {"put <path> [body]", "PUT a JSON body", cmdRequest(http.MethodPut)},
+		"delete":  {"delete <path>", "DELETE a path", cmdRequest(http.MethodDelete)},
+		"errors":  {"errors <rate|off>", "Override the error injection rate (0-1), or revert it", cmdErrors},
+		"faults":  {"faults [clear]", "Show injected faults, or clear them", cmdFaults},
+		"state":   {"state", "Show usage, rate limit buckets and other mock state", cmdState},
+		"wipe":    {"wipe [kind...]", "Reset mock state (default: all; e.g. usage rate_limits)", cmdWipe},
+		"headers": {"headers <on|off>", "Show every response header", cmdHeaders},
+		"exit":    {"exit", "Leave the shell", nil},
+	}
+}
+
+// execute runs one line and reports whether the shell should exit.
+func (s *session) execute(ctx context.Context, line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false
+	}
+
+	name, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	name = strings.ToLower(name)
+	if name == "exit" || name == "quit" {
+		return true
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		s.printf("%sUnknown command %q%s (type 'help')\n", utils.ColorRed, name, utils.ColorReset)
+		return false
+	}
+
+	if err := cmd.run(ctx, s, strings.Fields(rest), rest); err != nil {
+		s.printf("%sError:%s %v\n", utils.ColorRed, utils.ColorReset, err)
+	}
+	return false
+}
+
+func (s *session) printf(format string, args ...interface{}) {
+	fmt.Fprintf(s.out, format, args...)
+}
+
+// do sends a request to the current mock with the credentials agents use.
+func (s *session) do(ctx context.Context, method, path string, body []byte) (*http.Response, []byte, time.Duration, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := fmt.Sprintf("http://localhost:%d%s", s.ports[s.service], path)
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	apiKey := labenv.MockAPIKey
+	if s.service == "stripe" {
+		apiKey = labenv.StripeAPIKey
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	if body != nil {
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+			req.Header.Set("Content-Type", "application/json")
+		} else {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+
+	started := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("is the %s mock running? %w", s.service, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	elapsed := time.Since(started)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	s.last = data
+	return resp, data, elapsed, nil
+}
+
+// printResponse prints the status line, headers and highlighted body.
+func (s *session) printResponse(resp *http.Response, body []byte, elapsed time.Duration) {
+	color := utils.ColorGreen
+	switch {
+	case resp.StatusCode >= 500:
+		color = utils.ColorRed
+	case resp.StatusCode >= 400:
+		color = utils.ColorYellow
+	}
+	s.printf("%s%s%s (%s)\n", color, resp.Status, utils.ColorReset, elapsed.Round(time.Millisecond))
+	s.printHeaders(resp.Header)
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		s.printf("%s\n", ui.HighlightJSON(body))
+	}
+}
+
+func (s *session) printHeaders(header http.Header) {
+	names := summaryHeaders
+	if s.showHeaders {
+		names = make([]string, 0, len(header))
+		for name := range header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		if value := header.Get(name); value != "" {
+			s.printf("%s%s:%s %s\n", utils.ColorMagenta, http.CanonicalHeaderKey(name), utils.ColorReset, value)
+		}
+	}
+}
+
+func cmdHelp(ctx context.Context, s *session, args []string, rest string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s.printf("  %-22s %s\n", commands[name].usage, commands[name].help)
+	}
+	return nil
+}
+
+func cmdUse(ctx context.Context, s *session, args []string, rest string) error {
+	if len(args) == 0 {
+		names := make([]string, 0, len(s.ports))
+		for name := range s.ports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			marker := " "
+			if name == s.service {
+				marker = "*"
+			}
+			s.printf("%s %s (port %d)\n", marker, name, s.ports[name])
+		}
+		return nil
+	}
+
+	if _, ok := s.ports[args[0]]; !ok {
+		return fmt.Errorf("the %s mock is not enabled", args[0])
+	}
+	s.service = args[0]
+	return nil
+}
+
+func cmdModel(ctx context.Context, s *session, args []string, rest string) error {
+	if len(args) > 0 {
+		s.model = args[0]
+	}
+	s.printf("Model: %s\n", s.model)
+	return nil
+}
+
+func cmdSystem(ctx context.Context, s *session, args []string, rest string) error {
+	s.system = rest
+	return nil
+}
+
+func cmdReset(ctx context.Context, s *session, args []string, rest string) error {
+	s.messages = nil
+	s.printf("Started a new conversation\n")
+	return nil
+}
+
+func cmdChat(ctx context.Context, s *session, args []string, rest string) error {
+	if rest == "" {
+		return errors.New("usage: chat <message>")
+	}
+	if s.service != "openai" {
+		return fmt.Errorf("chat needs the openai mock ('use openai')")
+	}
+
+	messages := make([]map[string]string, 0, len(s.messages)+2)
+	if s.system != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": s.system})
+	}
+	messages = append(messages, s.messages...)
+	messages = append(messages, map[string]string{"role": "user", "content": rest})
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    s.model,
+		"messages": messages,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, data, elapsed, err := s.do(ctx, http.MethodPost, "/v1/chat/completions", body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.printResponse(resp, data, elapsed)
+		return nil
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(data, &completion); err != nil || len(completion.Choices) == 0 {
+		s.printResponse(resp, data, elapsed)
+		return nil
+	}
+
+	choice := completion.Choices[0]
+	s.messages = append(s.messages,
+		map[string]string{"role": "user", "content": rest},
+		map[string]string{"role": "assistant", "content": choice.Message.Content},
+	)
+
+	s.printf("%s%s%s\n", utils.ColorGreen, choice.Message.Content, utils.ColorReset)
+	s.printf("%s[%s · %d prompt + %d completion tokens · %s · %s]%s\n", utils.ColorCyan,
+		s.model, completion.Usage.PromptTokens, completion.Usage.CompletionTokens,
+		choice.FinishReason, elapsed.Round(time.Millisecond), utils.ColorReset)
+	s.printHeaders(resp.Header)
+	return nil
+}
+
+func cmdLast(ctx context.Context, s *session, args []string, rest string) error {
+	if s.last == nil {
+		return errors.New("no response yet")
+	}
+	s.printf("%s\n", ui.HighlightJSON(s.last))
+	return nil
+}
+
+func cmdRequest(method string) func(ctx context.Context, s *session, args []string, rest string) error {
+	return func(ctx context.Context, s *session, args []string, rest string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("usage: %s <path>", strings.ToLower(method))
+		}
+
+		var body []byte
+		if _, raw, ok := strings.Cut(rest, " "); ok && strings.TrimSpace(raw) != "" {
+			body = []byte(strings.TrimSpace(raw))
+		}
+
+		resp, data, elapsed, err := s.do(ctx, method, args[0], body)
+		if err != nil {
+			return err
+		}
+		s.printResponse(resp, data, elapsed)
+		return nil
+	}
+}
+
+func cmdErrors(ctx context.Context, s *session, args []string, rest string) error {
+	if len(args) != 1 {
+		return errors.New("usage: errors <rate|off>")
+	}
+
+	if args[0] == "off" {
+		resp, data, elapsed, err := s.do(ctx, http.MethodDelete, "/_sentra/overrides", nil)
+		if err != nil {
+			return err
+		}
+		s.printResponse(resp, data, elapsed)
+		return nil
+	}
+
+	rate, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return fmt.Errorf("invalid rate %q (must be between 0 and 1)", args[0])
+	}
+	body, _ := json.Marshal(map[string]float64{"error_rate": rate})
+	resp, data, elapsed, err := s.do(ctx, http.MethodPut, "/_sentra/overrides", body)
+	if err != nil {
+		return err
+	}
+	s.printResponse(resp, data, elapsed)
+	return nil
+}
+
+func cmdFaults(ctx context.Context, s *session, args []string, rest string) error {
+	method := http.MethodGet
+	if len(args) > 0 {
+		if args[0] != "clear" {
+			return errors.New("usage: faults [clear]")
+		}
+		method = http.MethodDelete
+	}
+
+	resp, data, elapsed, err := s.do(ctx, method, "/_sentra/faults", nil)
+	if err != nil {
+		return err
+	}
+	s.printResponse(resp, data, elapsed)
+	return nil
+}
+
+func cmdState(ctx context.Context, s *session, args []string, rest string) error {
+	resp, data, elapsed, err := s.do(ctx, http.MethodGet, "/_sentra/state", nil)
+	if err != nil {
+		return err
+	}
+	s.printResponse(resp, data, elapsed)
+	return nil
+}
+
+func cmdWipe(ctx context.Context, s *session, args []string, rest string) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"scenario": "shell",
+		"state":    args,
+	})
+	resp, data, elapsed, err := s.do(ctx, http.MethodPost, "/_sentra/state/reset", body)
+	if err != nil {
+		return err
+	}
+	s.printResponse(resp, data, elapsed)
+	return nil
+}
+
+func cmdHeaders(ctx context.Context, s *session, args []string, rest string) error {
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		return errors.New("usage: headers <on|off>")
+	}
+	s.showHeaders = args[0] == "on"
+	return nil
+}
+
+// completeCommand completes the command name on tab.
+func completeCommand(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || strings.Contains(line[:pos], " ") {
+		return "", 0, false
+	}
+
+	prefix := strings.ToLower(line[:pos])
+	var matches []string
+	for name := range commands {
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+	if len(matches) != 1 {
+		return "", 0, false
+	}
+
+	completed := matches[0] + " " + line[pos:]
+	return completed, len(matches[0]) + 1, true
+}
@@ -3,11 +3,241 @@ package start
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/docker"
+	"github.com/sentra-lab/cli/internal/utils"
 )
 
+// DockerManager runs the lab's services (the simulation engine and the
+// mocks enabled in lab.yaml) as containers named
+// sentra-lab-<project>-<service>.
+type DockerManager struct {
+	logger   *utils.Logger
+	config   *config.Config
+	client   *docker.Client
+	compose  *docker.ComposeManager
+	services []ServiceConfig
+
+	mu         sync.Mutex
+	healthTime map[string]time.Duration
+}
+
+// ServiceURL is where agents reach a service, and the variable that
+// points them at it ("" for the engine, which agents do not call).
+type ServiceURL struct {
+	Name   string
+	URL    string
+	EnvVar string
+}
+
+// NewDockerManager connects to Docker and prepares the services cfg
+// configures.
+func NewDockerManager(logger *utils.Logger, cfg *config.Config) (*DockerManager, error) {
+	client, err := docker.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	raw := cfg.Raw()
+	mocks, _ := raw["mocks"].(map[string]interface{})
+	services := GenerateServiceConfigs(mocks)
+	if simulation, ok := raw["simulation"].(map[string]interface{}); ok {
+		if tracing, ok := simulation["tracing"].(map[string]interface{}); ok {
+			ApplyTracingEnvironment(services, tracing)
+		}
+		if locale, ok := simulation["locale"].(map[string]interface{}); ok {
+			ApplyLocaleEnvironment(services, locale)
+		}
+	}
+
+	compose := docker.NewComposeManager(client, projectID(cfg.Name))
+	for _, service := range services {
+		compose.AddService(docker.ServiceDefinition{
+			Name:          service.Name,
+			Image:         service.Image,
+			Ports:         service.Ports,
+			Environment:   service.Environment,
+			Volumes:       service.Volumes,
+			DependsOn:     service.DependsOn,
+			Memory:        service.Memory,
+			CPUs:          service.CPUs,
+			RestartPolicy: service.RestartPolicy,
+			MaxRestarts:   service.MaxRestarts,
+		})
+	}
+
+	return &DockerManager{
+		logger:     logger,
+		config:     cfg,
+		client:     client,
+		compose:    compose,
+		services:   services,
+		healthTime: make(map[string]time.Duration),
+	}, nil
+}
+
+var nonProjectChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// projectID turns the project name into the part of container names that
+// keeps projects apart.
+func projectID(name string) string {
+	id := strings.Trim(nonProjectChars.ReplaceAllString(strings.ToLower(name), "-"), "-_.")
+	if id == "" {
+		return "default"
+	}
+	return id
+}
+
+// CheckDockerRunning fails when the Docker daemon does not answer.
+func (dm *DockerManager) CheckDockerRunning(ctx context.Context) error {
+	return dm.client.Ping(ctx)
+}
+
+// PullImages pulls every service's image.
+func (dm *DockerManager) PullImages(ctx context.Context) error {
+	for _, service := range dm.services {
+		dm.logger.Debug("Pulling image", "service", service.Name, "image", service.Image)
+		if err := dm.client.PullImage(ctx, service.Image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start creates and starts the containers that are not already running.
+func (dm *DockerManager) Start(ctx context.Context) error {
+	return dm.compose.StartAll(ctx)
+}
+
+// Stop stops and removes the containers.
+func (dm *DockerManager) Stop(ctx context.Context) error {
+	return dm.compose.StopAll(ctx)
+}
+
+// WaitHealthy waits up to timeout for every service's health check and
+// records how long each took.
+func (dm *DockerManager) WaitHealthy(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results, err := NewParallelHealthChecker(len(dm.services)).CheckAllServices(ctx, dm.services)
+
+	dm.mu.Lock()
+	for name, result := range results {
+		dm.healthTime[name] = result.Duration
+	}
+	dm.mu.Unlock()
+
+	return err
+}
+
+// GetHealthCheckTime returns how long service took to become healthy.
+func (dm *DockerManager) GetHealthCheckTime(service string) time.Duration {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	return dm.healthTime[service]
+}
+
+// GetServiceURLs returns where agents reach each service.
+func (dm *DockerManager) GetServiceURLs() []ServiceURL {
+	urls := make([]ServiceURL, 0, len(dm.services))
+	for _, service := range dm.services {
+		name := strings.TrimPrefix(service.Name, "mock-")
+		mock, ok := dm.config.Mocks[name]
+		if !ok {
+			urls = append(urls, ServiceURL{Name: service.Name, URL: dm.config.GetEngineAddress()})
+			continue
+		}
+
+		url := mock.BaseURL()
+		if name == "openai" {
+			url += "/v1"
+		}
+		urls = append(urls, ServiceURL{
+			Name:   service.Name,
+			URL:    url,
+			EnvVar: strings.ToUpper(name) + "_BASE_URL",
+		})
+	}
+	return urls
+}
+
+// GetLogs prints the last tail lines of service's logs ("all" for every
+// service).
+func (dm *DockerManager) GetLogs(ctx context.Context, service string, tail int) error {
+	containers, err := dm.containers(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		logs, err := dm.client.GetContainerLogs(ctx, container.ID, tail)
+		if err != nil {
+			return err
+		}
+		if len(containers) > 1 {
+			fmt.Printf("==> %s <==\n", strings.TrimPrefix(container.Name, "/"))
+		}
+		io.WriteString(os.Stdout, logs)
+	}
+	return nil
+}
+
+// StreamLogs follows service's logs ("all" for every service) until ctx is
+// done.
+func (dm *DockerManager) StreamLogs(ctx context.Context, service string) error {
+	containers, err := dm.containers(ctx, service)
+	if err != nil {
+		return err
+	}
+
+	errChan := make(chan error, len(containers))
+	for _, container := range containers {
+		go func(id string) {
+			errChan <- dm.client.StreamContainerLogs(ctx, id, os.Stdout)
+		}(container.ID)
+	}
+
+	for range containers {
+		if err := <-errChan; err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containers returns the running containers of service ("all" for every
+// service).
+func (dm *DockerManager) containers(ctx context.Context, service string) ([]*docker.ContainerStatus, error) {
+	status, err := dm.compose.GetServiceStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []*docker.ContainerStatus
+	for _, svc := range dm.services {
+		container, ok := status[svc.Name]
+		if ok && container.Running && (service == "all" || service == svc.Name) {
+			containers = append(containers, container)
+		}
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running containers for service %q", service)
+	}
+	return containers, nil
+}
+
 type HealthChecker struct {
 	maxAttempts int
 	interval    time.Duration
@@ -49,7 +279,7 @@ func (hc *HealthChecker) CheckHTTP(ctx context.Context, url string) error {
 }
 
 func (hc *HealthChecker) CheckTCP(ctx context.Context, host string, port int) error {
-	address := fmt.Sprintf("%s:%d", host, port)
+	address := net.JoinHostPort(host, strconv.Itoa(port))
 
 	for attempt := 0; attempt < hc.maxAttempts; attempt++ {
 		select {
@@ -154,6 +384,15 @@ type ServiceConfig struct {
 	Volumes     []string
 	HealthCheck HealthCheckConfig
 	DependsOn   []string
+
+	// Memory (bytes) and CPUs cap the container (0: no limit)
+	Memory int64
+	CPUs   float64
+
+	// RestartPolicy is the Docker restart policy ("": unless-stopped);
+	// MaxRestarts limits on-failure restarts
+	RestartPolicy string
+	MaxRestarts   int
 }
 
 type HealthCheckConfig struct {
@@ -194,9 +433,9 @@ func GenerateServiceConfigs(mockConfig map[string]interface{}) []ServiceConfig {
 				port = p
 			}
 
-			configs = append(configs, ServiceConfig{
+			service := ServiceConfig{
 				Name:  "mock-openai",
-				Image: "sentra/mock-openai:latest",
+				Image: pinnedImage(openai, MockImages["openai"]),
 				Ports: map[string]int{
 					"8080": port,
 				},
@@ -212,7 +451,31 @@ func GenerateServiceConfigs(mockConfig map[string]interface{}) []ServiceConfig {
 					Type: "http",
 					URL:  fmt.Sprintf("http://localhost:%d/health", port),
 				},
-			})
+			}
+
+			// Custom models are mounted read-only and loaded by the mock at startup
+			if modelsFile, ok := openai["models_file"].(string); ok && modelsFile != "" {
+				if !filepath.IsAbs(modelsFile) {
+					modelsFile = "./" + filepath.ToSlash(filepath.Clean(modelsFile))
+				}
+				service.Volumes = append(service.Volumes, modelsFile+":/config/models.yaml:ro")
+				service.Environment["MODELS_FILE"] = "/config/models.yaml"
+			}
+
+			if strategy, ok := openai["synthesis_strategy"].(string); ok && strategy != "" {
+				service.Environment["SYNTHESIS_STRATEGY"] = strategy
+			}
+
+			if jitter, ok := openai["jitter"].(string); ok && jitter != "" {
+				service.Environment["JITTER_DISTRIBUTION"] = jitter
+			}
+
+			if maxStreams, ok := openai["max_streams_per_key"].(int); ok && maxStreams > 0 {
+				service.Environment["MAX_STREAMS_PER_KEY"] = fmt.Sprintf("%d", maxStreams)
+			}
+			applyResources(&service, openai)
+
+			configs = append(configs, service)
 		}
 	}
 
@@ -223,15 +486,18 @@ func GenerateServiceConfigs(mockConfig map[string]interface{}) []ServiceConfig {
 				port = p
 			}
 
-			configs = append(configs, ServiceConfig{
+			environment := map[string]string{
+				"LATENCY_MS": fmt.Sprintf("%v", stripe["latency_ms"]),
+			}
+			applyWebhookSkew(environment, stripe)
+
+			service := ServiceConfig{
 				Name:  "mock-stripe",
-				Image: "sentra/mock-stripe:latest",
+				Image: pinnedImage(stripe, MockImages["stripe"]),
 				Ports: map[string]int{
 					"8080": port,
 				},
-				Environment: map[string]string{
-					"LATENCY_MS": fmt.Sprintf("%v", stripe["latency_ms"]),
-				},
+				Environment: environment,
 				Volumes: []string{
 					"./fixtures:/fixtures:ro",
 				},
@@ -239,7 +505,10 @@ func GenerateServiceConfigs(mockConfig map[string]interface{}) []ServiceConfig {
 					Type: "http",
 					URL:  fmt.Sprintf("http://localhost:%d/health", port),
 				},
-			})
+			}
+			applyResources(&service, stripe)
+
+			configs = append(configs, service)
 		}
 	}
 
@@ -250,12 +519,16 @@ func GenerateServiceConfigs(mockConfig map[string]interface{}) []ServiceConfig {
 				port = p
 			}
 
-			configs = append(configs, ServiceConfig{
+			environment := map[string]string{}
+			applyWebhookSkew(environment, coreledger)
+
+			service := ServiceConfig{
 				Name:  "mock-coreledger",
-				Image: "sentra/mock-coreledger:latest",
+				Image: pinnedImage(coreledger, MockImages["coreledger"]),
 				Ports: map[string]int{
 					"8080": port,
 				},
+				Environment: environment,
 				Volumes: []string{
 					"./fixtures:/fixtures:ro",
 				},
@@ -263,9 +536,105 @@ func GenerateServiceConfigs(mockConfig map[string]interface{}) []ServiceConfig {
 					Type: "http",
 					URL:  fmt.Sprintf("http://localhost:%d/health", port),
 				},
-			})
+			}
+			applyResources(&service, coreledger)
+
+			configs = append(configs, service)
 		}
 	}
 
 	return configs
 }
+
+// pinnedImage returns the image a mock runs: mocks.<name>.image, or the
+// default, pinned to mocks.<name>.image_digest if set. Pulling a pinned
+// reference fetches exactly that digest.
+func pinnedImage(mock map[string]interface{}, defaultImage string) string {
+	image, _ := mock["image"].(string)
+	if image == "" {
+		image = defaultImage
+	}
+	digest, _ := mock["image_digest"].(string)
+	return config.PinImage(image, digest)
+}
+
+// applyResources sets a mock's mocks.<name>.resources limits and restart
+// policy on its container.
+func applyResources(service *ServiceConfig, mock map[string]interface{}) {
+	if resources, ok := mock["resources"].(map[string]interface{}); ok {
+		switch cpus := resources["cpus"].(type) {
+		case float64:
+			service.CPUs = cpus
+		case int:
+			service.CPUs = float64(cpus)
+		}
+		if memoryMB, ok := resources["memory_mb"].(int); ok && memoryMB > 0 {
+			service.Memory = int64(memoryMB) * 1024 * 1024
+		}
+	}
+
+	if restart, ok := mock["restart"].(string); ok {
+		if policy, maxRestarts, err := config.ParseRestartPolicy(restart); err == nil {
+			service.RestartPolicy = policy
+			service.MaxRestarts = maxRestarts
+		}
+	}
+}
+
+// applyWebhookSkew passes a mock's webhook_timestamp_skew on as the
+// millisecond offset it signs webhook timestamps with.
+func applyWebhookSkew(environment map[string]string, mock map[string]interface{}) {
+	raw, ok := mock["webhook_timestamp_skew"].(string)
+	if !ok || raw == "" {
+		return
+	}
+	if skew, err := time.ParseDuration(raw); err == nil {
+		environment["WEBHOOK_TIMESTAMP_SKEW_MS"] = fmt.Sprintf("%d", skew.Milliseconds())
+	}
+}
+
+// ApplyTracingEnvironment points the engine and every mock at the
+// simulation.tracing collector. Each service reports under its own name.
+func ApplyTracingEnvironment(configs []ServiceConfig, tracing map[string]interface{}) {
+	endpoint, _ := tracing["endpoint"].(string)
+	if endpoint == "" {
+		return
+	}
+
+	for i := range configs {
+		service := &configs[i]
+		if service.Name != "simulation-engine" && !strings.HasPrefix(service.Name, "mock-") {
+			continue
+		}
+		if service.Environment == nil {
+			service.Environment = make(map[string]string)
+		}
+		service.Environment["OTEL_EXPORTER_OTLP_ENDPOINT"] = endpoint
+		service.Environment["OTEL_SERVICE_NAME"] = service.Name
+	}
+}
+
+// ApplyLocaleEnvironment passes the simulation.locale settings to every mock
+// service, which formats amounts and timestamps with them.
+func ApplyLocaleEnvironment(configs []ServiceConfig, locale map[string]interface{}) {
+	variables := map[string]string{
+		"locale":   "SENTRA_LOCALE",
+		"timezone": "SENTRA_TIMEZONE",
+		"currency": "SENTRA_CURRENCY",
+	}
+
+	for i := range configs {
+		service := &configs[i]
+		if !strings.HasPrefix(service.Name, "mock-") {
+			continue
+		}
+		for key, variable := range variables {
+			if value, ok := locale[key].(string); ok && value != "" {
+				if service.Environment == nil {
+					service.Environment = make(map[string]string)
+				}
+				service.Environment[variable] = value
+			}
+		}
+	}
+}
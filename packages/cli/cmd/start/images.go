@@ -0,0 +1,61 @@
+package start
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/docker"
+)
+
+// MockImages are the images the mocks run unless mocks.<name>.image says
+// otherwise.
+var MockImages = map[string]string{
+	"openai":     "sentra/mock-openai:latest",
+	"stripe":     "sentra/mock-stripe:latest",
+	"coreledger": "sentra/mock-coreledger:latest",
+}
+
+// verifyImages checks every enabled mock pinned with image_digest (or an
+// image@digest reference) before it runs: the local image must have the
+// pinned digest and, with cosign_key, a valid signature. Unpinned mocks are
+// not checked.
+func (sc *StartCommand) verifyImages(ctx context.Context, cfg *config.Config) error {
+	names := make([]string, 0, len(cfg.Mocks))
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var client *docker.Client
+	for _, name := range names {
+		mock := cfg.Mocks[name]
+		image := mock.PinnedImage(MockImages[name])
+		if config.ImageDigest(image) == "" {
+			continue
+		}
+
+		if client == nil {
+			var err error
+			if client, err = docker.NewClient(); err != nil {
+				return err
+			}
+			defer client.Close()
+		}
+
+		if err := client.VerifyImageDigest(ctx, image); err != nil {
+			return fmt.Errorf("mocks.%s: %w", name, err)
+		}
+		if mock.CosignKey != "" {
+			if err := docker.VerifyImageSignature(ctx, image, mock.CosignKey); err != nil {
+				return fmt.Errorf("mocks.%s: %w", name, err)
+			}
+		}
+		sc.logger.Info(fmt.Sprintf("🔒 %s image verified (%s)", name, config.ImageDigest(image)))
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package start
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/docker"
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/utils"
+)
+
+// containerIncident is a mock container that was OOM-killed or restarted.
+type containerIncident struct {
+	mock   string
+	status *docker.ContainerStatus
+
+	// duringRun is set when it happened during the last test run
+	duringRun bool
+}
+
+// printContainerIncidents flags mock containers that were OOM-killed or
+// restarted, marking those that happened during the last test run: a mock
+// that died mid-run usually explains otherwise puzzling failures. Status
+// still succeeds when Docker cannot be queried.
+func (sc *StartCommand) printContainerIncidents(ctx context.Context) {
+	incidents, err := containerIncidents(ctx)
+	if err != nil || len(incidents) == 0 {
+		return
+	}
+
+	sc.logger.Info("Container incidents:")
+	sc.logger.Info("")
+	for _, incident := range incidents {
+		status := incident.status
+
+		var events []string
+		if status.OOMKilled {
+			events = append(events, fmt.Sprintf("OOM-killed at %s", status.FinishedAt.Local().Format(time.TimeOnly)))
+		}
+		if status.RestartCount > 0 {
+			events = append(events, fmt.Sprintf("restarted %d time(s), last exit code %d", status.RestartCount, status.ExitCode))
+		}
+
+		line := fmt.Sprintf("  ⚠ %-20s %s", "mock-"+incident.mock, strings.Join(events, "; "))
+		if incident.duringRun {
+			line = fmt.Sprintf("%s%s (during the last test run)%s", utils.ColorYellow, line, utils.ColorReset)
+		}
+		sc.logger.Info(line)
+
+		if status.OOMKilled {
+			sc.logger.Info(fmt.Sprintf("    raise mocks.%s.resources.memory_mb in lab.yaml", incident.mock))
+		}
+	}
+	sc.logger.Info("")
+}
+
+// containerIncidents inspects the mock containers and returns the ones
+// that were OOM-killed or restarted, by mock name.
+func containerIncidents(ctx context.Context) ([]containerIncident, error) {
+	client, err := docker.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	containers, err := client.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a recorded run nothing is marked as during it
+	run, _ := results.Load(results.LatestPath)
+
+	var incidents []containerIncident
+	for _, container := range containers {
+		name := strings.TrimPrefix(container.Name, "/")
+		_, mock, ok := strings.Cut(name, "mock-")
+		if !ok || !strings.HasPrefix(name, "sentra-lab") {
+			continue
+		}
+
+		status, err := client.GetContainerStatus(ctx, container.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !status.OOMKilled && status.RestartCount == 0 {
+			continue
+		}
+
+		incident := containerIncident{mock: mock, status: status}
+		if run != nil {
+			// A restart moves StartedAt; an OOM kill sets FinishedAt
+			incident.duringRun = withinRun(run, status.FinishedAt) ||
+				(status.RestartCount > 0 && withinRun(run, status.StartedAt))
+		}
+		incidents = append(incidents, incident)
+	}
+
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].mock < incidents[j].mock })
+	return incidents, nil
+}
+
+// withinRun reports whether t falls within a recorded run.
+func withinRun(run *results.Run, t time.Time) bool {
+	return !t.IsZero() && !t.Before(run.StartedAt) && !t.After(run.FinishedAt)
+}
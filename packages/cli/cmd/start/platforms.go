@@ -0,0 +1,121 @@
+package start
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/docker"
+	"github.com/sentra-lab/cli/internal/emulation"
+)
+
+// emulationProbes is how many health checks time each mock's round trip
+const emulationProbes = 15
+
+// preferNativeImages checks every enabled mock's image against the
+// daemon's platform. A missing image, or one built for another platform,
+// is pulled for the native platform; mocks whose image has no native
+// variant run under emulation and are returned in the report.
+func (sc *StartCommand) preferNativeImages(ctx context.Context, cfg *config.Config) (*emulation.Report, error) {
+	client, err := docker.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	native, err := client.DaemonPlatform(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &emulation.Report{Native: native}
+
+	names := make([]string, 0, len(cfg.Mocks))
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		image := cfg.Mocks[name].PinnedImage(MockImages[name])
+
+		platform, err := client.ImagePlatform(ctx, image)
+		if err != nil {
+			return nil, fmt.Errorf("mocks.%s: %w", name, err)
+		}
+		if platform == native {
+			continue
+		}
+
+		if err := client.PullImageForPlatform(ctx, image, native); err == nil {
+			if platform, err = client.ImagePlatform(ctx, image); err != nil {
+				return nil, fmt.Errorf("mocks.%s: %w", name, err)
+			}
+		}
+		if platform == "" {
+			// Neither local nor pullable for this platform: the start pulls
+			// whatever the registry has
+			continue
+		}
+		if platform != native {
+			sc.logger.Warn(fmt.Sprintf("⚠️  %s has no %s image; it will run under emulation (%s)", name, native, platform))
+			report.Mocks = append(report.Mocks, emulation.Mock{Name: name, Image: image, Platform: platform})
+			continue
+		}
+		sc.logger.Info(fmt.Sprintf("🧬 %s switched to its native %s image", name, native))
+	}
+
+	return report, nil
+}
+
+// measureEmulation times the health check of each emulated mock against a
+// natively running one (when there is one) and records the difference as
+// the mock's overhead. The report is saved for `sentra lab test`, which
+// widens latency expectations by the largest overhead.
+func (sc *StartCommand) measureEmulation(ctx context.Context, cfg *config.Config, report *emulation.Report) error {
+	if report == nil || len(report.Mocks) == 0 {
+		return emulation.Save(emulation.Path, nil)
+	}
+
+	emulated := make(map[string]bool, len(report.Mocks))
+	for _, mock := range report.Mocks {
+		emulated[mock.Name] = true
+	}
+
+	var reference time.Duration
+	for name, mock := range cfg.Mocks {
+		if !mock.Enabled || emulated[name] {
+			continue
+		}
+		roundTrip, err := emulation.MedianRoundTrip(ctx, healthURL(mock.Port), emulationProbes)
+		if err == nil && (reference == 0 || roundTrip < reference) {
+			reference = roundTrip
+		}
+	}
+
+	for i := range report.Mocks {
+		mock := &report.Mocks[i]
+		roundTrip, err := emulation.MedianRoundTrip(ctx, healthURL(cfg.Mocks[mock.Name].Port), emulationProbes)
+		if err != nil {
+			return fmt.Errorf("failed to time %s: %w", mock.Name, err)
+		}
+
+		overhead := roundTrip - reference
+		if overhead < 0 {
+			overhead = 0
+		}
+		mock.OverheadMs = overhead.Milliseconds()
+		sc.logger.Warn(fmt.Sprintf("⚠️  %s runs under emulation on %s: +%dms per request; latency expectations in 'sentra lab test' allow for it", mock.Name, report.Native, mock.OverheadMs))
+	}
+	report.MeasuredAt = time.Now()
+
+	return emulation.Save(emulation.Path, report)
+}
+
+// healthURL is a mock's health check on the host.
+func healthURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d/health", port)
+}
@@ -5,19 +5,26 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/daemon"
 	"github.com/sentra-lab/cli/internal/docker"
+	"github.com/sentra-lab/cli/internal/egress"
+	"github.com/sentra-lab/cli/internal/labenv"
+	"github.com/sentra-lab/cli/internal/sdkusage"
+	"github.com/sentra-lab/cli/internal/seed"
 	"github.com/sentra-lab/cli/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 type StartCommand struct {
 	logger        *utils.Logger
-	dockerManager *docker.Manager
+	dockerManager *DockerManager
 	configLoader  *config.Loader
+	configPath    string
 	detach        bool
 	pull          bool
 	rebuild       bool
@@ -52,12 +59,20 @@ Example:
 	}
 
 	cmd.Flags().BoolVarP(&sc.detach, "detach", "d", false, "Run in background")
-	cmd.Flags().BoolVar(&sc.pull, "pull", false, "Pull latest Docker images")
+	cmd.Flags().BoolVar(&sc.pull, "pull", false, "Pull latest Docker images (pinned mocks are pulled by digest)")
 	cmd.Flags().BoolVar(&sc.rebuild, "rebuild", false, "Rebuild containers")
 
 	return cmd
 }
 
+// New returns a start command without its cobra wiring, for `sentra lab
+// stop`, `logs` and `status`.
+func New(logger *utils.Logger) *StartCommand {
+	return &StartCommand{
+		logger: logger,
+	}
+}
+
 func (sc *StartCommand) PreRunE(cmd *cobra.Command, args []string) error {
 	configPath, _ := cmd.Flags().GetString("config")
 	if configPath == "" {
@@ -67,6 +82,7 @@ func (sc *StartCommand) PreRunE(cmd *cobra.Command, args []string) error {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return fmt.Errorf("config file not found: %s\nRun 'sentra lab init' to create a new project", configPath)
 	}
+	sc.configPath = configPath
 
 	var err error
 	sc.configLoader, err = config.NewLoader(configPath)
@@ -79,7 +95,7 @@ func (sc *StartCommand) PreRunE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	sc.dockerManager, err = docker.NewManager(sc.logger, cfg)
+	sc.dockerManager, err = NewDockerManager(sc.logger.Subsystem(utils.SubsystemDocker), cfg)
 	if err != nil {
 		return fmt.Errorf("failed to initialize Docker manager: %w", err)
 	}
@@ -103,6 +119,18 @@ func (sc *StartCommand) RunE(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	cfg, err := sc.configLoader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := sc.verifyImages(ctx, cfg); err != nil {
+		return fmt.Errorf("image verification failed: %w", err)
+	}
+	platforms, err := sc.preferNativeImages(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("platform check failed: %w", err)
+	}
+
 	if sc.rebuild {
 		sc.logger.Info("🔨 Rebuilding containers...")
 		if err := sc.dockerManager.Stop(ctx); err != nil {
@@ -119,6 +147,22 @@ func (sc *StartCommand) RunE(cmd *cobra.Command, args []string) error {
 	if err := sc.dockerManager.WaitHealthy(ctx, 60*time.Second); err != nil {
 		return fmt.Errorf("health check failed: %w", err)
 	}
+	if err := sc.measureEmulation(ctx, cfg, platforms); err != nil {
+		sc.logger.Warn("Failed to measure emulation overhead", "error", err)
+	}
+
+	var proxyEnv map[string]string
+	if cfg.Egress.Enabled {
+		sc.logger.Info("🛡  Starting egress proxy...")
+		proxyEnv, err = sc.startEgressProxy(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to start egress proxy: %w", err)
+		}
+	}
+
+	if err := sc.applySeeds(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to apply seeds: %w", err)
+	}
 
 	services := sc.dockerManager.GetServiceURLs()
 	
@@ -127,6 +171,9 @@ func (sc *StartCommand) RunE(cmd *cobra.Command, args []string) error {
 		healthTime := sc.dockerManager.GetHealthCheckTime(svc.Name)
 		sc.logger.Info(fmt.Sprintf("  ✓ %-20s %s (%dms)", svc.Name, svc.URL, healthTime.Milliseconds()))
 	}
+	if cfg.Egress.Enabled {
+		sc.logger.Info(fmt.Sprintf("  ✓ %-20s http://localhost:%d (%s mode)", "egress-proxy", cfg.Egress.Port, cfg.Egress.Mode))
+	}
 
 	sc.logger.Info("")
 	sc.logger.Info("💡 Configure your agent to use these endpoints:")
@@ -135,6 +182,17 @@ func (sc *StartCommand) RunE(cmd *cobra.Command, args []string) error {
 			sc.logger.Info(fmt.Sprintf("   export %s=%s", svc.EnvVar, svc.URL))
 		}
 	}
+	for _, name := range egress.EnvNames(proxyEnv) {
+		sc.logger.Info(fmt.Sprintf("   export %s=%s", name, proxyEnv[name]))
+	}
+
+	if err := sc.writeEnv(cfg); err != nil {
+		sc.logger.Warn("Failed to write agent environment", "error", err)
+	} else {
+		sc.logger.Info("")
+		sc.logger.Info(fmt.Sprintf("📄 Saved to %s: load it with 'eval \"$(sentra lab env)\"'", labenv.Path))
+		sc.logger.Info("   or run your agent with 'sentra lab run -- <command>'")
+	}
 
 	sc.logger.Info("")
 	sc.logger.Info("Next steps:")
@@ -155,6 +213,67 @@ func (sc *StartCommand) RunE(cmd *cobra.Command, args []string) error {
 	return sc.streamLogs(ctx)
 }
 
+// startEgressProxy runs the egress proxy in the background and returns the
+// environment agents need to send their traffic through it.
+func (sc *StartCommand) startEgressProxy(ctx context.Context, cfg *config.Config) (map[string]string, error) {
+	dir, err := filepath.Abs(egress.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the CA before agents are told to trust it
+	ca, err := egress.LoadCA(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := ca.WriteBundle(dir); err != nil {
+		return nil, err
+	}
+
+	if _, err := egress.StartBackground(dir, sc.configPath); err != nil {
+		return nil, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := NewHealthChecker().CheckTCP(waitCtx, "localhost", cfg.Egress.Port); err != nil {
+		return nil, fmt.Errorf("proxy did not come up (see %s): %w", filepath.Join(dir, "proxy.log"), err)
+	}
+
+	return egress.Env(cfg.Egress.Port, dir), nil
+}
+
+// writeEnv saves the agent environment for `sentra lab env` and
+// `sentra lab run`.
+// applySeeds seeds the mocks with the records declared in seeds/, if any.
+func (sc *StartCommand) applySeeds(ctx context.Context, cfg *config.Config) error {
+	seeds, err := seed.Load(seed.Dir)
+	if err != nil || len(seeds.Files) == 0 {
+		return err
+	}
+
+	sc.logger.Info(fmt.Sprintf("🌱 Applying seeds from %d file(s)...", len(seeds.Files)))
+	results, err := seed.NewApplier(cfg).Apply(ctx, seeds)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Unsupported {
+			sc.logger.Warn(result.Describe())
+		}
+	}
+	return nil
+}
+
+func (sc *StartCommand) writeEnv(cfg *config.Config) error {
+	vars, err := labenv.Vars(cfg)
+	if err != nil {
+		return err
+	}
+	return labenv.Write(labenv.Path, vars)
+}
+
 func (sc *StartCommand) streamLogs(ctx context.Context) error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -193,7 +312,7 @@ func (sc *StartCommand) Stop(ctx context.Context) error {
 			return fmt.Errorf("failed to parse config: %w", err)
 		}
 
-		sc.dockerManager, err = docker.NewManager(sc.logger, cfg)
+		sc.dockerManager, err = NewDockerManager(sc.logger.Subsystem(utils.SubsystemDocker), cfg)
 		if err != nil {
 			return fmt.Errorf("failed to initialize Docker manager: %w", err)
 		}
@@ -205,11 +324,20 @@ func (sc *StartCommand) Stop(ctx context.Context) error {
 		return fmt.Errorf("failed to stop services: %w", err)
 	}
 
+	if err := egress.StopBackground(egress.Dir); err != nil {
+		sc.logger.Warn("Failed to stop egress proxy", "error", err)
+	}
+
 	sc.logger.Info("✅ All services stopped")
 	return nil
 }
 
 func (sc *StartCommand) Logs(ctx context.Context, service string, follow bool, tail int) error {
+	if client, err := daemon.Connect(); err == nil {
+		sc.logger.Debug("Using daemon", "pid", client.Info.PID)
+		return client.Logs(ctx, service, tail, follow, os.Stdout)
+	}
+
 	if sc.dockerManager == nil {
 		configPath := "lab.yaml"
 		var err error
@@ -223,7 +351,7 @@ func (sc *StartCommand) Logs(ctx context.Context, service string, follow bool, t
 			return fmt.Errorf("failed to parse config: %w", err)
 		}
 
-		sc.dockerManager, err = docker.NewManager(sc.logger, cfg)
+		sc.dockerManager, err = NewDockerManager(sc.logger.Subsystem(utils.SubsystemDocker), cfg)
 		if err != nil {
 			return fmt.Errorf("failed to initialize Docker manager: %w", err)
 		}
@@ -237,53 +365,104 @@ func (sc *StartCommand) Logs(ctx context.Context, service string, follow bool, t
 }
 
 func (sc *StartCommand) Status(ctx context.Context) error {
-	if sc.dockerManager == nil {
-		configPath := "lab.yaml"
-		var err error
-		sc.configLoader, err = config.NewLoader(configPath)
+	if client, err := daemon.Connect(); err == nil {
+		sc.logger.Debug("Using daemon", "pid", client.Info.PID)
+		services, err := client.Status(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+			return fmt.Errorf("failed to get status: %w", err)
 		}
+		// Only used for the SDK usage summary, which is skipped without it
+		sc.configLoader, _ = config.NewLoader(client.Info.Config)
+		sc.printStatus(services)
+		sc.printContainerIncidents(ctx)
+		sc.printSDKUsage(ctx)
+		return nil
+	}
 
-		cfg, err := sc.configLoader.Load()
+	if sc.configLoader == nil {
+		var err error
+		sc.configLoader, err = config.NewLoader("lab.yaml")
 		if err != nil {
-			return fmt.Errorf("failed to parse config: %w", err)
+			return fmt.Errorf("failed to load config: %w", err)
 		}
+	}
+	cfg, err := sc.configLoader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
 
-		sc.dockerManager, err = docker.NewManager(sc.logger, cfg)
-		if err != nil {
-			return fmt.Errorf("failed to initialize Docker manager: %w", err)
-		}
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
 	}
+	defer client.Close()
 
-	status, err := sc.dockerManager.GetStatus(ctx)
+	services, err := daemon.Services(ctx, client, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to get status: %w", err)
 	}
+	sc.printStatus(services)
+	sc.printContainerIncidents(ctx)
+	sc.printSDKUsage(ctx)
+
+	return nil
+}
 
+func (sc *StartCommand) printStatus(services []daemon.ServiceStatus) {
 	sc.logger.Info("Sentra Lab Services:")
 	sc.logger.Info("")
 
-	for _, svc := range status {
+	for _, svc := range services {
 		statusIcon := "✓"
-		statusColor := "\033[32m"
+		statusColor := utils.ColorGreen
 		if svc.Status != "healthy" {
 			statusIcon = "✗"
-			statusColor = "\033[31m"
+			statusColor = utils.ColorRed
 		}
 
-		sc.logger.Info(fmt.Sprintf("%s%s %-20s\033[0m %s", statusColor, statusIcon, svc.Name, svc.URL))
+		sc.logger.Info(fmt.Sprintf("%s%s %-20s%s %s", statusColor, statusIcon, svc.Name, utils.ColorReset, svc.URL))
 		sc.logger.Info(fmt.Sprintf("    Status: %s", svc.Status))
 		if svc.Uptime > 0 {
-			sc.logger.Info(fmt.Sprintf("    Uptime: %s", time.Duration(svc.Uptime).String()))
+			sc.logger.Info(fmt.Sprintf("    Uptime: %s", svc.Uptime.String()))
 		}
 		if svc.Memory > 0 {
 			sc.logger.Info(fmt.Sprintf("    Memory: %s", formatBytes(svc.Memory)))
 		}
 		sc.logger.Info("")
 	}
+}
 
-	return nil
+// printSDKUsage lists the client SDKs agents have used against the mocks.
+// Status still succeeds when the mocks cannot be queried.
+func (sc *StartCommand) printSDKUsage(ctx context.Context) {
+	if sc.configLoader == nil {
+		return
+	}
+	cfg, err := sc.configLoader.Load()
+	if err != nil {
+		return
+	}
+
+	collector := sdkusage.NewCollector(cfg)
+	if collector == nil {
+		return
+	}
+
+	usages, err := collector.Fetch(ctx)
+	if err != nil || len(usages) == 0 {
+		return
+	}
+
+	sc.logger.Info("Client SDKs:")
+	sc.logger.Info("")
+	for _, usage := range usages {
+		line := fmt.Sprintf("  %-32s %-8s %d request(s)", usage.SDK(), usage.Mock, usage.Requests)
+		if usage.Deprecation != "" {
+			line = fmt.Sprintf("%s%s  ⚠ deprecated: %s%s", utils.ColorYellow, line, usage.Deprecation, utils.ColorReset)
+		}
+		sc.logger.Info(line)
+	}
+	sc.logger.Info("")
 }
 
 func formatBytes(bytes uint64) string {
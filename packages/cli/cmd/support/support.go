@@ -0,0 +1,78 @@
+package support
+
+import (
+	"fmt"
+
+	"github.com/sentra-lab/cli/internal/diagnostics"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type SupportCommand struct {
+	logger  *utils.Logger
+	version string
+}
+
+func NewSupportCommand(logger *utils.Logger, version string) *cobra.Command {
+	sc := &SupportCommand{
+		logger:  logger,
+		version: version,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "support",
+		Short: "Collect information for bug reports",
+		Long: `Commands that help when reporting a problem.
+
+Commands:
+  • bundle   - Write a diagnostics bundle to attach to a bug report
+
+Example:
+  sentra lab support bundle`,
+	}
+
+	cmd.AddCommand(newBundleCommand(sc))
+
+	return cmd
+}
+
+func newBundleCommand(sc *SupportCommand) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Write a diagnostics bundle to attach to a bug report",
+		Long: `Write a zip with the CLI, Go, OS and Docker versions, recent log lines and
+lab.yaml with API keys, tokens, passwords and URL credentials replaced by
+[REDACTED]. The same bundle is written automatically when the CLI crashes.
+Check its contents before sharing it.
+
+Example:
+  sentra lab support bundle
+  sentra lab support bundle --output /tmp`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, _ := cmd.Flags().GetString("config")
+			if configPath == "" {
+				configPath = "lab.yaml"
+			}
+
+			path, err := diagnostics.Write(output, diagnostics.Report{
+				Logs:       sc.logger.History(),
+				ConfigPath: configPath,
+				Version:    sc.version,
+			})
+			if err != nil {
+				return err
+			}
+
+			sc.logger.Info(fmt.Sprintf("✓ Wrote %s", path))
+			sc.logger.Info(fmt.Sprintf("  Attach it to a bug report: %s", diagnostics.IssuesURL))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", diagnostics.Dir, "Directory to write the bundle to")
+
+	return cmd
+}
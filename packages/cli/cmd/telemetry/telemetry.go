@@ -0,0 +1,167 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/globalconfig"
+	"github.com/sentra-lab/cli/internal/telemetry"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+type TelemetryCommand struct {
+	logger *utils.Logger
+}
+
+func NewTelemetryCommand(logger *utils.Logger) *cobra.Command {
+	tc := &TelemetryCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage telemetry",
+		Long: `Telemetry is off unless you opt in. When enabled, each command records
+only its name, duration, the class of any error (e.g. "timeout"), the CLI
+version and OS - never arguments, paths, prompts or responses. Events are
+queued in ~/.sentra-lab/telemetry and sent in batches.
+
+SENTRA_TELEMETRY=0 or DO_NOT_TRACK=1 turn it off regardless of the setting.
+
+Commands:
+  • status    - Show whether telemetry is on and what is queued
+  • enable    - Opt in (optionally to a self-hosted collector)
+  • disable   - Opt out and delete queued events
+
+Example:
+  sentra lab telemetry status
+  sentra lab telemetry enable --endpoint https://telemetry.internal/v1/events`,
+	}
+
+	cmd.AddCommand(newStatusCommand(tc))
+	cmd.AddCommand(newEnableCommand(tc))
+	cmd.AddCommand(newDisableCommand(tc))
+
+	return cmd
+}
+
+func newStatusCommand(tc *TelemetryCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether telemetry is on and what is queued",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := globalconfig.Load()
+			if err != nil {
+				return err
+			}
+			path, err := globalconfig.Path()
+			if err != nil {
+				return err
+			}
+			events, err := telemetry.Queued()
+			if err != nil {
+				return err
+			}
+
+			state := "off"
+			switch {
+			case cfg.TelemetryEnabled():
+				state = "on"
+			case cfg.Telemetry.Enabled:
+				state = "off (disabled by SENTRA_TELEMETRY or DO_NOT_TRACK)"
+			case cfg.Telemetry.DecidedAt.IsZero():
+				state = "off (not opted in)"
+			}
+
+			fmt.Printf("Telemetry:  %s\n", state)
+			fmt.Printf("Endpoint:   %s\n", telemetry.Endpoint(cfg))
+			fmt.Printf("Queued:     %d event(s)\n", len(events))
+			fmt.Printf("Settings:   %s\n", path)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newEnableCommand(tc *TelemetryCommand) *cobra.Command {
+	var endpoint string
+
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Opt in to anonymous usage telemetry",
+		Long: `Opt in to anonymous usage telemetry. Use --endpoint to send events to a
+self-hosted collector, which receives POST requests with a JSON body
+{"events": [...]}; pass --endpoint="" to go back to Sentra's.
+
+Example:
+  sentra lab telemetry enable
+  sentra lab telemetry enable --endpoint https://telemetry.internal/v1/events`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := globalconfig.Load()
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("endpoint") {
+				if endpoint != "" {
+					parsed, err := url.Parse(endpoint)
+					if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+						return fmt.Errorf("invalid --endpoint %q (must be an http or https URL)", endpoint)
+					}
+				}
+				cfg.Telemetry.Endpoint = endpoint
+			}
+			cfg.Telemetry.Enabled = true
+			cfg.Telemetry.DecidedAt = time.Now().UTC()
+
+			if _, err := globalconfig.Save(cfg); err != nil {
+				return err
+			}
+
+			tc.logger.Info(fmt.Sprintf("✓ Telemetry enabled (sending to %s)", telemetry.Endpoint(cfg)))
+			if !cfg.TelemetryEnabled() {
+				fmt.Fprintf(os.Stderr, "⚠️  SENTRA_TELEMETRY or DO_NOT_TRACK is set in this shell, so nothing will be recorded\n")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "Self-hosted collector URL")
+
+	return cmd
+}
+
+func newDisableCommand(tc *TelemetryCommand) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Opt out and delete queued events",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := globalconfig.Load()
+			if err != nil {
+				return err
+			}
+
+			cfg.Telemetry.Enabled = false
+			cfg.Telemetry.DecidedAt = time.Now().UTC()
+
+			if _, err := globalconfig.Save(cfg); err != nil {
+				return err
+			}
+			if err := telemetry.Clear(); err != nil {
+				return fmt.Errorf("failed to delete queued events: %w", err)
+			}
+
+			tc.logger.Info("✓ Telemetry disabled; queued events deleted")
+			return nil
+		},
+	}
+
+	return cmd
+}
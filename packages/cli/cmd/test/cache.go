@@ -0,0 +1,140 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/impact"
+)
+
+// StatusCached is the status of a test case skipped because its inputs are
+// unchanged since it last passed.
+const StatusCached = "cached"
+
+// StrictEnv disables the result cache when set (e.g. in release pipelines)
+const StrictEnv = "SENTRA_STRICT"
+
+// resultCache skips test cases whose inputs are unchanged since they last
+// passed.
+type resultCache struct {
+	mu sync.Mutex
+
+	cache      *impact.Cache
+	hasher     *impact.Hasher
+	footprints *impact.Map
+
+	// ran are scenarios with a case that ran this time; their dependents
+	// receive fresh outputs, so they are never served from the cache
+	ran map[string]bool
+}
+
+// SetResultCache enables the result cache (`sentra lab test --cache`):
+// cases that passed with the same inputs are reported as cached passes
+// instead of running. Strict mode (`--strict`, or SENTRA_STRICT=1), meant
+// for release pipelines, runs everything regardless.
+func (r *Runner) SetResultCache(configPath string, strict bool) error {
+	if strict || os.Getenv(StrictEnv) == "1" || os.Getenv(StrictEnv) == "true" {
+		fmt.Fprintf(os.Stderr, "Strict mode: result cache disabled, running every scenario\n")
+		return nil
+	}
+
+	cache, err := impact.LoadCache(impact.CachePath)
+	if err != nil {
+		return err
+	}
+	hasher, err := impact.NewHasher(configPath)
+	if err != nil {
+		return fmt.Errorf("result cache unavailable: %w", err)
+	}
+	footprints, err := impact.Load(impact.MapPath)
+	if err != nil {
+		return err
+	}
+
+	r.resultCache = &resultCache{
+		cache:      cache,
+		hasher:     hasher,
+		footprints: footprints,
+		ran:        make(map[string]bool),
+	}
+	return nil
+}
+
+// lookup returns a cached pass for the case, and the outputs it handed to
+// dependents, if it may be skipped. Otherwise the case is marked as run.
+func (c *resultCache) lookup(testCase runCase) (*TestResult, map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.hit(testCase)
+	if !ok {
+		c.ran[testCase.Path] = true
+		return nil, nil
+	}
+
+	now := time.Now()
+	return &TestResult{
+		Scenario:    testCase.Name,
+		Status:      StatusCached,
+		RunID:       entry.RunID,
+		StartedAt:   now,
+		CompletedAt: now,
+	}, entry.Outputs
+}
+
+func (c *resultCache) hit(testCase runCase) (impact.CacheEntry, bool) {
+	for _, prerequisite := range testCase.dependsOn {
+		if c.ran[prerequisite] {
+			return impact.CacheEntry{}, false
+		}
+	}
+
+	footprint, ok := c.footprints.Footprint(testCase.Path)
+	if !ok {
+		return impact.CacheEntry{}, false
+	}
+	inputs, err := c.hasher.Hash(footprint)
+	if err != nil {
+		return impact.CacheEntry{}, false
+	}
+	return c.cache.Hit(testCase.Name, inputs)
+}
+
+// update caches the cases that passed this run, with their fresh
+// footprints, and drops those that failed. Failures are printed but never
+// fail the run.
+func (c *resultCache) update(cases []runCase, testResults []*TestResult, suite *suiteRun, footprints map[string]impact.Footprint) {
+	for i, testCase := range cases {
+		result := testResults[i]
+		if result == nil {
+			continue
+		}
+
+		switch result.Status {
+		case "passed":
+			footprint, ok := footprints[testCase.Path]
+			if !ok {
+				continue
+			}
+			inputs, err := c.hasher.Hash(footprint)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %s not cached: %v\n", testCase.Name, err)
+				continue
+			}
+			c.cache.Cases[testCase.Name] = impact.CacheEntry{
+				Inputs:   inputs,
+				RunID:    result.RunID,
+				PassedAt: result.CompletedAt,
+				Outputs:  suite.outputsOf(testCase.Path),
+			}
+		case "failed":
+			delete(c.cache.Cases, testCase.Name)
+		}
+	}
+
+	if err := c.cache.Save(impact.CachePath); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
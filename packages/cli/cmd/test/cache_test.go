@@ -0,0 +1,191 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/impact"
+)
+
+const cacheLabYAML = `name: demo
+agent:
+  entry_point: agent/main.py
+mocks:
+  openai:
+    enabled: true
+  stripe:
+    enabled: true
+`
+
+// cachedRun creates a project whose scenarios all passed in a cached run:
+// refund depends on setup, which handed it a customer_id, and chat called
+// no mock.
+func cachedRun(t *testing.T) []runCase {
+	t.Helper()
+
+	initRepo(t, map[string]string{
+		"lab.yaml":              cacheLabYAML,
+		"agent/main.py":         "print('hi')\n",
+		"scenarios/setup.yaml":  "name: Setup\n",
+		"scenarios/refund.yaml": "name: Refund\ndepends_on: [setup.yaml]\n",
+		"scenarios/chat.yaml":   "name: Chat\n",
+	})
+
+	cases, err := orderedCases([]string{"scenarios/setup.yaml", "scenarios/refund.yaml", "scenarios/chat.yaml"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	footprints := map[string]impact.Footprint{
+		"scenarios/setup.yaml":  {Mocks: []string{"openai"}, Files: []string{"scenarios/setup.yaml"}},
+		"scenarios/refund.yaml": {Mocks: []string{"openai", "stripe"}, Files: []string{"scenarios/refund.yaml"}},
+		"scenarios/chat.yaml":   {Files: []string{"scenarios/chat.yaml"}},
+	}
+	impactMap, err := impact.Load(impact.MapPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, footprint := range footprints {
+		impactMap.Record(path, footprint)
+	}
+	if err := impactMap.Save(impact.MapPath); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRunner(nil, 1, false)
+	if err := r.SetResultCache("lab.yaml", false); err != nil {
+		t.Fatalf("SetResultCache() error = %v", err)
+	}
+
+	suite := newSuiteRun(cases)
+	suite.setOutputs("scenarios/setup.yaml", map[string]string{"customer_id": "cus_1"})
+	results := make([]*TestResult, len(cases))
+	for i, testCase := range cases {
+		results[i] = &TestResult{Scenario: testCase.Name, Status: "passed", RunID: "run_" + testCase.Name, CompletedAt: time.Now()}
+	}
+	r.resultCache.update(cases, results, suite, footprints)
+
+	return cases
+}
+
+func TestResultCacheLookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  map[string]string
+		wantHit map[string]bool
+	}{
+		{
+			name:    "unchanged",
+			wantHit: map[string]bool{"scenarios/setup.yaml": true, "scenarios/refund.yaml": true, "scenarios/chat.yaml": true},
+		},
+		{
+			name:    "scenario changed",
+			change:  map[string]string{"scenarios/chat.yaml": "name: Chat v2\n"},
+			wantHit: map[string]bool{"scenarios/setup.yaml": true, "scenarios/refund.yaml": true},
+		},
+		{
+			name:    "prerequisite changed",
+			change:  map[string]string{"scenarios/setup.yaml": "name: Setup v2\n"},
+			wantHit: map[string]bool{"scenarios/chat.yaml": true},
+		},
+		{
+			name:    "called mock's config changed",
+			change:  map[string]string{"lab.yaml": cacheLabYAML + "    port: 9000\n"},
+			wantHit: map[string]bool{"scenarios/setup.yaml": true, "scenarios/chat.yaml": true},
+		},
+		{
+			name:    "agent changed",
+			change:  map[string]string{"agent/main.py": "print('hello')\n"},
+			wantHit: map[string]bool{},
+		},
+		{
+			name:    "unimpactful section changed",
+			change:  map[string]string{"lab.yaml": "name: renamed\n" + cacheLabYAML[len("name: demo\n"):]},
+			wantHit: map[string]bool{"scenarios/setup.yaml": true, "scenarios/refund.yaml": true, "scenarios/chat.yaml": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := cachedRun(t)
+			writeFiles(t, tt.change)
+
+			r := NewRunner(nil, 1, false)
+			if err := r.SetResultCache("lab.yaml", false); err != nil {
+				t.Fatalf("SetResultCache() error = %v", err)
+			}
+
+			// Cases are looked up in run order, so a prerequisite that runs
+			// is known before its dependents
+			for _, testCase := range cases {
+				cached, outputs := r.resultCache.lookup(testCase)
+				if hit := cached != nil; hit != tt.wantHit[testCase.Path] {
+					t.Errorf("lookup(%s) hit = %v, want %v", testCase.Name, hit, tt.wantHit[testCase.Path])
+					continue
+				}
+				if cached == nil {
+					continue
+				}
+				if cached.Status != StatusCached || cached.RunID != "run_"+testCase.Name {
+					t.Errorf("lookup(%s) = %+v, want a cached pass of the last run", testCase.Name, cached)
+				}
+				if testCase.Path == "scenarios/setup.yaml" && !reflect.DeepEqual(outputs, map[string]string{"customer_id": "cus_1"}) {
+					t.Errorf("lookup(%s) outputs = %v, want the handed-off customer_id", testCase.Name, outputs)
+				}
+			}
+		})
+	}
+}
+
+func TestResultCacheUpdateDropsFailures(t *testing.T) {
+	cases := cachedRun(t)
+
+	r := NewRunner(nil, 1, false)
+	if err := r.SetResultCache("lab.yaml", false); err != nil {
+		t.Fatalf("SetResultCache() error = %v", err)
+	}
+	results := []*TestResult{
+		{Scenario: cases[0].Name, Status: StatusCached},
+		{Scenario: cases[1].Name, Status: "failed"},
+		nil,
+	}
+	r.resultCache.update(cases, results, newSuiteRun(cases), nil)
+
+	cache, err := impact.LoadCache(impact.CachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, testCase := range cases {
+		_, cached := cache.Cases[testCase.Name]
+		if want := testCase.Path != "scenarios/refund.yaml"; cached != want {
+			t.Errorf("%s cached = %v, want %v", testCase.Name, cached, want)
+		}
+	}
+}
+
+func TestSetResultCacheStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+		env    string
+	}{
+		{name: "--strict", strict: true},
+		{name: StrictEnv + "=1", env: "1"},
+		{name: StrictEnv + "=true", env: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(StrictEnv, tt.env)
+
+			r := NewRunner(nil, 1, false)
+			if err := r.SetResultCache("missing.yaml", tt.strict); err != nil {
+				t.Fatalf("SetResultCache() error = %v", err)
+			}
+			if r.resultCache != nil {
+				t.Error("SetResultCache() enabled the cache in strict mode")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/impact"
+	"github.com/sentra-lab/cli/internal/scenario"
+)
+
+// SelectChanged narrows scenarios to those impacted by changes since git
+// revision since (`sentra lab test --changed-since`), printing why each
+// was selected. The footprints come from earlier runs of the project.
+func SelectChanged(scenarios []string, since, configPath string) ([]string, error) {
+	change, err := impact.ChangedSince(since, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine changes since %s: %w", since, err)
+	}
+
+	footprints, err := impact.Load(impact.MapPath)
+	if err != nil {
+		return nil, err
+	}
+
+	suite, err := scenario.OrderScenarios(scenarios)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := footprints.Select(scenarios, change, suite.DependsOn)
+	fmt.Printf("%d of %d scenario(s) impacted by %d change(s) since %s\n",
+		len(selection.Selected), len(scenarios), len(change.Files), since)
+	for _, selected := range selection.Selected {
+		fmt.Printf("  • %s (%s)\n", selected, selection.Reasons[selected])
+	}
+
+	return selection.Selected, nil
+}
+
+// recordImpact saves the footprint of every scenario that ran, for later
+// --changed-since runs, and returns them. Failures are printed but never
+// fail the run.
+func (r *Runner) recordImpact(ctx context.Context, cases []runCase, testResults []*TestResult) map[string]impact.Footprint {
+	footprints := make(map[string]impact.Footprint)
+	recordedAt := time.Now()
+
+	for i, testCase := range cases {
+		result := testResults[i]
+		if result == nil || result.RunID == "" || result.Status == "skipped" || result.Status == StatusCached {
+			continue
+		}
+
+		recording, err := r.engineClient.GetRecording(ctx, result.RunID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Scenario footprints not recorded: %v\n", err)
+			return nil
+		}
+
+		footprint := impact.Footprint{
+			Mocks:      impact.MocksCalled(recording),
+			Files:      []string{testCase.Path},
+			RunID:      result.RunID,
+			RecordedAt: recordedAt,
+		}
+		if dataFile, err := scenario.DataFile(testCase.Path); err == nil && dataFile != "" {
+			footprint.Files = append(footprint.Files, dataFile)
+		}
+
+		if previous, ok := footprints[testCase.Path]; ok {
+			footprint = previous.Merge(footprint)
+		}
+		footprints[testCase.Path] = footprint
+	}
+
+	if len(footprints) == 0 {
+		return footprints
+	}
+
+	impactMap, err := impact.Load(impact.MapPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		return footprints
+	}
+	for path, footprint := range footprints {
+		impactMap.Record(path, footprint)
+	}
+	if err := impactMap.Save(impact.MapPath); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	return footprints
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/impact"
+)
+
+const impactLabYAML = `name: demo
+agent:
+  entry_point: agent/main.py
+mocks:
+  openai:
+    enabled: true
+  stripe:
+    enabled: true
+`
+
+// initRepo creates a git repository with files in a temp directory and
+// makes it the working directory.
+func initRepo(t *testing.T, files map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Chdir(t.TempDir())
+	writeFiles(t, files)
+	runGit(t, "init", "-q")
+	runGit(t, "add", "-A")
+	runGit(t, "commit", "-q", "-m", "initial")
+}
+
+// runGit runs git in the working directory.
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestSelectChanged(t *testing.T) {
+	scenarios := []string{"scenarios/chat.yaml", "scenarios/new.yaml", "scenarios/receipt.yaml", "scenarios/refund.yaml"}
+
+	tests := []struct {
+		name   string
+		change map[string]string
+		want   []string
+	}{
+		{
+			name:   "nothing relevant changed",
+			change: map[string]string{"README.md": "# Demo\n"},
+			want:   []string{"scenarios/new.yaml"},
+		},
+		{
+			name:   "scenario changed",
+			change: map[string]string{"scenarios/refund.yaml": "name: Refund v2\n"},
+			want:   []string{"scenarios/new.yaml", "scenarios/receipt.yaml", "scenarios/refund.yaml"},
+		},
+		{
+			name:   "mock section changed",
+			change: map[string]string{"lab.yaml": impactLabYAML + "    port: 9000\n"},
+			want:   []string{"scenarios/new.yaml", "scenarios/receipt.yaml", "scenarios/refund.yaml"},
+		},
+		{
+			name:   "agent changed",
+			change: map[string]string{"agent/main.py": "print('hello')\n"},
+			want:   scenarios,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initRepo(t, map[string]string{
+				"lab.yaml":               impactLabYAML,
+				"agent/main.py":          "print('hi')\n",
+				"scenarios/chat.yaml":    "name: Chat\n",
+				"scenarios/new.yaml":     "name: New\n",
+				"scenarios/refund.yaml":  "name: Refund\n",
+				"scenarios/receipt.yaml": "name: Receipt\ndepends_on: [refund.yaml]\n",
+			})
+
+			// new.yaml has never run, so it has no footprint
+			impactMap, err := impact.Load(impact.MapPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			impactMap.Record("scenarios/chat.yaml", impact.Footprint{Mocks: []string{"openai"}, Files: []string{"scenarios/chat.yaml"}})
+			impactMap.Record("scenarios/refund.yaml", impact.Footprint{Mocks: []string{"stripe"}, Files: []string{"scenarios/refund.yaml"}})
+			impactMap.Record("scenarios/receipt.yaml", impact.Footprint{Files: []string{"scenarios/receipt.yaml"}})
+			if err := impactMap.Save(impact.MapPath); err != nil {
+				t.Fatal(err)
+			}
+			runGit(t, "add", "-A")
+			runGit(t, "commit", "-q", "-m", "footprints")
+
+			writeFiles(t, tt.change)
+
+			got, err := SelectChanged(scenarios, "HEAD", "lab.yaml")
+			if err != nil {
+				t.Fatalf("SelectChanged() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectChangedUnknownRevision(t *testing.T) {
+	initRepo(t, map[string]string{"lab.yaml": impactLabYAML, "scenarios/chat.yaml": "name: Chat\n"})
+
+	if _, err := SelectChanged([]string{"scenarios/chat.yaml"}, "no-such-rev", "lab.yaml"); err == nil {
+		t.Error("SelectChanged() error = nil, want an unknown revision error")
+	}
+}
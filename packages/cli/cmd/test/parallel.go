@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/sentra-lab/cli/internal/scenario"
 )
 
 type ParallelExecutor struct {
@@ -118,7 +120,7 @@ func NewScenarioTask(scenarioPath string, runner *Runner, progressFn func(string
 }
 
 func (st *ScenarioTask) Execute(ctx context.Context) error {
-	_, err := st.runner.runScenario(ctx, st.scenarioPath, st.progressFn)
+	_, err := st.runner.runScenario(ctx, runCase{Case: scenario.Case{Path: st.scenarioPath, Name: st.scenarioPath}}, st.progressFn)
 	return err
 }
 
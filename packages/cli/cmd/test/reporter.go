@@ -3,6 +3,9 @@ package test
 import (
 	"fmt"
 	"time"
+
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/utils"
 )
 
 type TestReporter struct {
@@ -21,19 +24,23 @@ func (tr *TestReporter) ReportStart(total int) {
 
 func (tr *TestReporter) ReportScenario(result *TestResult) {
 	icon := "✓"
-	color := "\033[32m"
+	color := utils.ColorGreen
 
 	if result.Status == "failed" {
 		icon = "✗"
-		color = "\033[31m"
+		color = utils.ColorRed
 	} else if result.Status == "skipped" {
 		icon = "⊘"
-		color = "\033[33m"
+		color = utils.ColorYellow
+	} else if result.Status == StatusCached {
+		fmt.Printf("%s↺%s %-50s  cached pass (%s)\n", utils.ColorCyan, utils.ColorReset, result.Scenario, result.RunID)
+		return
 	}
 
-	fmt.Printf("%s%s\033[0m %-50s %6.2fs  $%.4f\n",
+	fmt.Printf("%s%s%s %-50s %6.2fs  $%.4f\n",
 		color,
 		icon,
+		utils.ColorReset,
 		result.Scenario,
 		result.Duration.Seconds(),
 		result.CostUSD,
@@ -65,6 +72,21 @@ func (tr *TestReporter) ReportSummary(summary *TestSummary) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
 
+// ReportRunnerStats prints the runner's own metrics in verbose mode, for
+// profiling large suites.
+func (tr *TestReporter) ReportRunnerStats(stats *results.RunnerStats) {
+	if !tr.verbose || stats == nil {
+		return
+	}
+
+	fmt.Println("\nRunner:")
+	fmt.Printf("  Throughput:      %.2f scenarios/s (%d scenarios, parallel %d)\n", stats.ScenariosPerSecond, stats.Scenarios, stats.Parallel)
+	fmt.Printf("  Queue depth:     avg %.1f, max %d\n", stats.AvgQueueDepth, stats.MaxQueueDepth)
+	fmt.Printf("  Queue wait:      p50 %dms, p95 %dms\n", stats.QueueWait.P50Ms, stats.QueueWait.P95Ms)
+	fmt.Printf("  Agent startup:   p50 %dms, p95 %dms, max %dms\n", stats.AgentStartup.P50Ms, stats.AgentStartup.P95Ms, stats.AgentStartup.MaxMs)
+	fmt.Printf("  Assertions:      p50 %dms, p95 %dms, total %dms\n", stats.AssertionEval.P50Ms, stats.AssertionEval.P95Ms, stats.AssertionEval.TotalMs)
+}
+
 func (tr *TestReporter) ReportFailures(results []*TestResult) {
 	failures := 0
 	for _, result := range results {
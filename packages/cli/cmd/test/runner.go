@@ -3,85 +3,427 @@ package test
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/daemon"
+	"github.com/sentra-lab/cli/internal/egress"
+	"github.com/sentra-lab/cli/internal/emulation"
+	"github.com/sentra-lab/cli/internal/fake"
 	"github.com/sentra-lab/cli/internal/grpc"
+	"github.com/sentra-lab/cli/internal/loadgen"
+	"github.com/sentra-lab/cli/internal/mockstate"
+	"github.com/sentra-lab/cli/internal/notify"
+	"github.com/sentra-lab/cli/internal/preflight"
+	"github.com/sentra-lab/cli/internal/results"
+	"github.com/sentra-lab/cli/internal/scenario"
+	"github.com/sentra-lab/cli/internal/sdkusage"
+	"github.com/sentra-lab/cli/internal/tokenusage"
+	"github.com/sentra-lab/cli/internal/workspace"
 )
 
 type Runner struct {
-	engineClient *grpc.EngineClient
-	parallel     int
-	failFast     bool
+	engineClient   *grpc.EngineClient
+	parallel       int
+	failFast       bool
+	backgroundLoad *loadgen.Generator
+	loadStats      *loadgen.Stats
+	notifier       *notify.Notifier
+	sdkUsage       *sdkusage.Collector
+	isolation      *mockstate.Resetter
+	preflight      *preflight.Checker
+
+	// contextWarning is the share of a model's context window at which a
+	// step is flagged
+	contextWarning float64
+
+	// agentLimits cap the agent's resources in each scenario
+	agentLimits grpc.AgentLimits
+
+	// seed is the configured run seed for fake data (0: new each run);
+	// runSeed is the one the current run uses
+	seed    int64
+	runSeed int64
+
+	// steps and findings are each run's per-step token usage and
+	// guardrail findings, by run ID
+	stepsMu  sync.Mutex
+	steps    map[string][]tokenusage.Step
+	findings map[string][]results.Finding
+
+	// stats collects the current run's runner metrics; runStats are the
+	// last run's
+	stats    *runnerStats
+	runStats *results.RunnerStats
+
+	// resultCache skips cases that passed with the same inputs (nil:
+	// caching off)
+	resultCache *resultCache
+
+	// timeouts bound steps, scenarios and the run; deadlines pass them on
+	// to the mocks (nil: not at all). runDeadline is when the current run
+	// times out (zero: never)
+	timeouts    Timeouts
+	deadlines   *mockstate.Deadlines
+	runDeadline time.Time
+
+	// timedOut is the level of timeout that ended each case, by case name
+	// (guarded by stepsMu)
+	timedOut map[string]string
+
+	// latencyAllowance widens latency expectations by the overhead of
+	// mocks running under emulation, as measured by `sentra lab start`
+	latencyAllowance time.Duration
+
+	// mockURLs are the base URLs of externally managed mocks, by mock
+	// name (nil: the local mocks)
+	mockURLs map[string]string
+
+	// services reports whether the engine and mocks are up, through a
+	// running daemon (nil: not checked)
+	services serviceStatus
+}
+
+// serviceStatus reports the status of the project's services; a
+// *daemon.Client implements it.
+type serviceStatus interface {
+	Status(ctx context.Context) ([]daemon.ServiceStatus, error)
 }
 
 func NewRunner(engineClient *grpc.EngineClient, parallel int, failFast bool) *Runner {
 	return &Runner{
-		engineClient: engineClient,
-		parallel:     parallel,
-		failFast:     failFast,
+		engineClient:   engineClient,
+		parallel:       parallel,
+		failFast:       failFast,
+		contextWarning: tokenusage.DefaultWarnUtilization,
+		steps:          make(map[string][]tokenusage.Step),
+		findings:       make(map[string][]results.Finding),
+		timedOut:       make(map[string]string),
+	}
+}
+
+// SetBackgroundLoad sends synthetic traffic to the mocks for as long as
+// scenarios are running. A nil generator disables background load.
+func (r *Runner) SetBackgroundLoad(generator *loadgen.Generator) {
+	r.backgroundLoad = generator
+}
+
+// BackgroundLoadStats returns the traffic sent during the last run, or nil
+// if background load was not enabled.
+func (r *Runner) BackgroundLoadStats() *loadgen.Stats {
+	return r.loadStats
+}
+
+// SetNotifier posts a summary to the configured channels when a run
+// finishes. A nil notifier disables notifications.
+func (r *Runner) SetNotifier(notifier *notify.Notifier) {
+	r.notifier = notifier
+}
+
+// SetSDKUsage records which client SDKs agents used during a run and warns
+// about deprecated ones. A nil collector disables SDK reporting.
+func (r *Runner) SetSDKUsage(collector *sdkusage.Collector) {
+	r.sdkUsage = collector
+}
+
+// SetIsolation resets mock state before each scenario, or once before the
+// run when the resetter says so or scenarios run in parallel. A nil
+// resetter leaves mock state to carry over between scenarios.
+func (r *Runner) SetIsolation(resetter *mockstate.Resetter) {
+	r.isolation = resetter
+}
+
+// SetPreflight smoke-checks the mocks before any scenario runs, so a
+// misconfigured environment fails the run immediately. A nil checker skips
+// the check.
+func (r *Runner) SetPreflight(checker *preflight.Checker) {
+	r.preflight = checker
+}
+
+// SetTarget runs against the externally managed mocks in cfg, those
+// `sentra lab test --target` gave a URL (config.ApplyTargets), instead of
+// the local containers: agents are pointed at them, and the preflight also
+// checks they are the expected mocks and provide what the scenarios
+// require, failing the run before any scenario if not.
+func (r *Runner) SetTarget(cfg *config.Config) {
+	r.mockURLs = make(map[string]string)
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled && mock.URL != "" {
+			r.mockURLs[name] = mock.URL
+		}
+	}
+	if len(r.mockURLs) == 0 {
+		r.mockURLs = nil
 	}
 }
 
+// SetDaemon checks the engine and mocks are up through the project's
+// running daemon before each run, so a run against stopped services fails
+// at once instead of scenario by scenario.
+func (r *Runner) SetDaemon(client *daemon.Client) {
+	r.services = client
+}
+
+// SetSeed sets the run seed {{ fake.* }} test data is derived from, so a
+// run's data can be reproduced. 0 picks a new seed for each run.
+func (r *Runner) SetSeed(seed int64) {
+	r.seed = seed
+}
+
+// SetAgentLimits caps the agent's CPU time, memory and wall time in each
+// scenario. A scenario whose agent goes over a limit fails with a
+// "resource limit exceeded" reason.
+func (r *Runner) SetAgentLimits(limits grpc.AgentLimits) {
+	r.agentLimits = limits
+}
+
+// RunnerStats returns the runner's own metrics for the last run (nil before
+// a run), for the stats block of JSON output.
+func (r *Runner) RunnerStats() *results.RunnerStats {
+	return r.runStats
+}
+
+// SetContextWarning sets the share of a model's context window (0-1] at
+// which a step's context length is flagged.
+func (r *Runner) SetContextWarning(threshold float64) {
+	r.contextWarning = threshold
+}
+
+// runCase is a test case and where it fits in the run.
+type runCase struct {
+	scenario.Case
+
+	// project is the workspace project the case belongs to (nil outside a
+	// workspace)
+	project *workspace.Project
+
+	// dependsOn are the scenarios that must pass before the case runs
+	dependsOn []string
+
+	// suite receives the case's outputs (nil outside runCases)
+	suite *suiteRun
+}
+
+// RunScenarios runs each scenario, or each row of a data-driven scenario's
+// variables_from file as a separate test case. Scenarios run after the
+// scenarios they depend on, which are added to the run if not given.
 func (r *Runner) RunScenarios(ctx context.Context, scenarios []string, progressFn func(string, string, float64)) ([]*TestResult, error) {
-	results := make([]*TestResult, len(scenarios))
+	cases, err := orderedCases(scenarios, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.runCases(ctx, cases, progressFn)
+}
+
+// orderedCases expands scenarios into test cases in dependency order.
+func orderedCases(scenarios []string, project *workspace.Project) ([]runCase, error) {
+	suite, err := scenario.OrderScenarios(scenarios)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []runCase
+	for _, path := range suite.Order {
+		expanded, err := scenario.ExpandCases(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, testCase := range expanded {
+			cases = append(cases, runCase{Case: testCase, project: project, dependsOn: suite.DependsOn[path]})
+		}
+	}
+
+	return cases, nil
+}
+
+// runCases runs test cases in parallel, then records and reports the run.
+func (r *Runner) runCases(ctx context.Context, cases []runCase, progressFn func(string, string, float64)) ([]*TestResult, error) {
+	startTime := time.Now()
+
+	r.runSeed = r.seed
+	if r.runSeed == 0 {
+		r.runSeed = fake.NewSeed()
+	}
+
+	if r.services != nil {
+		if err := r.checkServices(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.preflight != nil {
+		if _, err := r.preflight.Run(ctx); err != nil {
+			return nil, err
+		}
+		if r.mockURLs != nil {
+			if err := r.preflight.CheckTargets(ctx, requiredCapabilities(cases)); err != nil {
+				return nil, err
+			}
+		} else {
+			r.warnMissingCapabilities(ctx, cases)
+		}
+	}
+	// Emulation overhead is measured on the local Docker host
+	if r.mockURLs == nil {
+		r.loadLatencyAllowance()
+	}
+
+	if r.sdkUsage != nil {
+		if err := r.sdkUsage.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  SDK usage will not be reported: %v\n", err)
+			r.sdkUsage = nil
+		}
+	}
+
+	// Scenarios running side by side share the mocks, so resetting before
+	// each one would wipe the state of the others
+	resetPerScenario := r.isolation != nil && r.isolation.Mode() == mockstate.ResetScenario && r.parallel == 1
+	if r.isolation != nil && !resetPerScenario {
+		if r.isolation.Mode() == mockstate.ResetScenario {
+			fmt.Fprintf(os.Stderr, "⚠️  Scenarios run in parallel, so mock state is reset once before the run instead of before each scenario\n")
+		}
+		if err := r.resetMocksForRun(ctx, cases); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.backgroundLoad != nil {
+		r.backgroundLoad.Start(ctx)
+		defer func() {
+			stats := r.backgroundLoad.Stop()
+			r.loadStats = &stats
+		}()
+	}
+
+	runCtx, cancelRun := r.withRunTimeout(ctx)
+	defer cancelRun()
+	defer r.clearMockDeadline()
+
+	results := make([]*TestResult, len(cases))
 	resultsMu := sync.Mutex{}
+	suite := newSuiteRun(cases)
+	r.stats = newRunnerStats(r.parallel)
 
 	semaphore := make(chan struct{}, r.parallel)
 	var wg sync.WaitGroup
-	errChan := make(chan error, len(scenarios))
+	errChan := make(chan error, len(cases))
 	stopChan := make(chan struct{})
+	var stopOnce sync.Once
 
-	for i, scenario := range scenarios {
+	for i, testCase := range cases {
 		wg.Add(1)
 
-		go func(idx int, scenarioPath string) {
+		go func(idx int, testCase runCase) {
 			defer wg.Done()
 
-			select {
-			case <-stopChan:
+			skip := func(failures ...string) {
 				resultsMu.Lock()
 				results[idx] = &TestResult{
-					Scenario: scenarioPath,
+					Scenario: testCase.Name,
 					Status:   "skipped",
+					Failures: failures,
 				}
 				resultsMu.Unlock()
+				suite.finish(testCase.Path, false)
+			}
+
+			// Prerequisites are awaited before taking a slot, so waiting
+			// dependents never starve the scenarios they wait for
+			outputs, failed, ok := suite.wait(testCase, stopChan)
+			if !ok {
+				skip()
+				return
+			}
+			if failed != "" {
+				skip(fmt.Sprintf("Skipped: prerequisite %s did not pass", failed))
+				progressFn(testCase.Name, "skipped", 1.0)
+				return
+			}
+			testCase.Variables = withVariables(outputs, testCase.Variables)
+			testCase.suite = suite
+
+			if r.resultCache != nil {
+				if cached, outputs := r.resultCache.lookup(testCase); cached != nil {
+					suite.setOutputs(testCase.Path, outputs)
+					resultsMu.Lock()
+					results[idx] = cached
+					resultsMu.Unlock()
+					suite.finish(testCase.Path, true)
+					progressFn(testCase.Name, StatusCached, 1.0)
+					return
+				}
+			}
+
+			queuedAt := r.stats.enqueue()
+			select {
+			case <-stopChan:
+				r.stats.dequeue(queuedAt, false)
+				skip()
 				return
 			case semaphore <- struct{}{}:
+				r.stats.dequeue(queuedAt, true)
 			}
 
 			defer func() { <-semaphore }()
 
-			progressFn(scenarioPath, "running", 0.0)
+			if timeout, ok := timedOut(runCtx); ok {
+				skip(fmt.Sprintf("Skipped: %s", timeout))
+				r.recordTimeout(testCase.Name, timeout.level)
+				progressFn(testCase.Name, "skipped", 1.0)
+				return
+			}
+
+			progressFn(testCase.Name, "running", 0.0)
 
-			result, err := r.runScenario(ctx, scenarioPath, progressFn)
+			var result *TestResult
+			var err error
+			if resetPerScenario {
+				result, err = r.resetMockState(ctx, testCase)
+			}
+			if result == nil {
+				result, err = r.runScenario(runCtx, testCase, progressFn)
+			}
 
 			resultsMu.Lock()
 			results[idx] = result
 			resultsMu.Unlock()
+			suite.finish(testCase.Path, result.Status == "passed")
 
 			if err != nil {
 				errChan <- err
 
 				if r.failFast {
-					close(stopChan)
+					stopOnce.Do(func() { close(stopChan) })
 				}
 			}
 
 			status := result.Status
-			progressFn(scenarioPath, status, 1.0)
+			progressFn(testCase.Name, status, 1.0)
 
-		}(i, scenario)
+		}(i, testCase)
 	}
 
 	wg.Wait()
 	close(errChan)
+	r.runStats = r.stats.finish()
 
 	var errors []error
 	for err := range errChan {
 		errors = append(errors, err)
 	}
 
+	r.saveResults(results, startTime, r.collectSDKUsage(ctx), collectEgressLeaks(startTime))
+	footprints := r.recordImpact(ctx, cases, results)
+	if r.resultCache != nil {
+		r.resultCache.update(cases, results, suite, footprints)
+	}
+	r.warnContextLimits(results)
+	r.notify(ctx, results, time.Since(startTime))
+
 	if r.failFast && len(errors) > 0 {
 		return results, errors[0]
 	}
@@ -89,24 +431,349 @@ func (r *Runner) RunScenarios(ctx context.Context, scenarios []string, progressF
 	return results, nil
 }
 
-func (r *Runner) runScenario(ctx context.Context, scenarioPath string, progressFn func(string, string, float64)) (*TestResult, error) {
+// collectSDKUsage returns the SDKs agents used during the run and warns
+// about deprecated ones. Failures are printed but never fail the run.
+func (r *Runner) collectSDKUsage(ctx context.Context) []sdkusage.Usage {
+	if r.sdkUsage == nil {
+		return nil
+	}
+
+	usages, err := r.sdkUsage.Finish(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		return nil
+	}
+
+	for _, usage := range sdkusage.Deprecated(usages) {
+		fmt.Fprintf(os.Stderr, "⚠️  Deprecated SDK %s made %d request(s) to the %s mock: %s\n",
+			usage.SDK(), usage.Requests, usage.Mock, usage.Deprecation)
+	}
+
+	return usages
+}
+
+// collectEgressLeaks returns the external calls the egress proxy caught
+// since the run started and warns about them. Without the proxy there are
+// none; failures are printed but never fail the run.
+func collectEgressLeaks(startTime time.Time) []egress.Leak {
+	leaks, err := egress.LoadLeaks(egress.LeaksPath, startTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		return nil
+	}
+
+	for _, host := range egress.ByHost(leaks) {
+		action := "blocked"
+		if host.Blocked < host.Calls {
+			action = "let through (warn mode)"
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Agent made %d unexpected external call(s) to %s, %s; see 'sentra lab egress leaks'\n",
+			host.Calls, host.Host, action)
+	}
+
+	return leaks
+}
+
+// recordSteps keeps a run's per-step token usage for the results.
+func (r *Runner) recordSteps(runID string, usage []grpc.StepUsage) {
+	if len(usage) == 0 {
+		return
+	}
+
+	steps := tokenusage.Analyze(usage, r.contextWarning)
+
+	r.stepsMu.Lock()
+	defer r.stepsMu.Unlock()
+	r.steps[runID] = steps
+}
+
+// recordFindings keeps a run's guardrail findings for the results file.
+func (r *Runner) recordFindings(runID string, findings []grpc.GuardrailFinding) {
+	if len(findings) == 0 {
+		return
+	}
+
+	recorded := make([]results.Finding, len(findings))
+	for i, finding := range findings {
+		recorded[i] = results.Finding{
+			Check:    finding.Check,
+			Location: finding.Location,
+			Detail:   finding.Detail,
+			Step:     finding.Step,
+		}
+	}
+
+	r.stepsMu.Lock()
+	defer r.stepsMu.Unlock()
+	r.findings[runID] = recorded
+}
+
+// warnContextLimits reports steps whose context came close to the model's
+// context window, where agents risk silently truncating history.
+func (r *Runner) warnContextLimits(testResults []*TestResult) {
+	r.stepsMu.Lock()
+	defer r.stepsMu.Unlock()
+
+	for _, result := range testResults {
+		if result == nil {
+			continue
+		}
+		for _, step := range tokenusage.NearLimit(r.steps[result.RunID]) {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: step %s used %d of %s's %d context tokens (%.0f%%)\n",
+				result.Scenario, step.Step, step.ContextTokens, step.Model, step.ContextWindow, step.Utilization*100)
+		}
+	}
+}
+
+// saveResults records the run for `sentra lab ci gate`. Failures are
+// printed but never fail the run.
+func (r *Runner) saveResults(testResults []*TestResult, startTime time.Time, sdks []sdkusage.Usage, leaks []egress.Leak) {
+	run := &results.Run{
+		StartedAt:   startTime,
+		FinishedAt:  time.Now(),
+		SDKs:        sdks,
+		EgressLeaks: leaks,
+		Seed:        r.runSeed,
+		Runner:      r.runStats,
+	}
+
+	for _, result := range testResults {
+		if result == nil {
+			continue
+		}
+		run.Scenarios = append(run.Scenarios, results.ScenarioResult{
+			Scenario:   result.Scenario,
+			RunID:      result.RunID,
+			Status:     result.Status,
+			DurationMs: result.Duration.Milliseconds(),
+			CostUSD:    result.CostUSD,
+			Steps:      r.steps[result.RunID],
+			Findings:   r.findings[result.RunID],
+			TimedOut:   r.timedOut[result.Scenario],
+		})
+	}
+
+	if err := results.Save(results.LatestPath, run); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// notify reports the run to the configured channels. Delivery failures are
+// printed but never fail the run.
+func (r *Runner) notify(ctx context.Context, results []*TestResult, duration time.Duration) {
+	if r.notifier == nil {
+		return
+	}
+
+	summary := &notify.Summary{
+		Total:    len(results),
+		Duration: duration,
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		summary.TotalCost += result.CostUSD
+
+		switch result.Status {
+		case "passed", StatusCached:
+			summary.Passed++
+		case "skipped":
+			summary.Skipped++
+		default:
+			summary.Failed++
+			summary.FailedScenarios = append(summary.FailedScenarios, notify.FailedScenario{
+				Scenario: result.Scenario,
+				RunID:    result.RunID,
+			})
+		}
+	}
+
+	if err := r.notifier.Notify(ctx, summary); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// resetMocksForRun resets the state of every project's mocks once before
+// the run.
+func (r *Runner) resetMocksForRun(ctx context.Context, cases []runCase) error {
+	reset := make(map[*workspace.Project]bool)
+	for _, testCase := range cases {
+		if reset[testCase.project] {
+			continue
+		}
+		reset[testCase.project] = true
+
+		if err := r.isolation.Reset(ctx, "", mockPorts(testCase)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warnMissingCapabilities warns about scenarios that require capabilities
+// (requires:) the running mocks do not provide, since they would otherwise
+// fail with confusing 404s.
+func (r *Runner) warnMissingCapabilities(ctx context.Context, cases []runCase) {
+	required := requiredCapabilities(cases)
+	if len(required) == 0 {
+		return
+	}
+
+	for _, warning := range r.preflight.MissingCapabilities(ctx, required) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+}
+
+// requiredCapabilities returns what the cases' scenarios require (scenario
+// -> mock -> capabilities).
+func requiredCapabilities(cases []runCase) map[string]map[string][]string {
+	required := make(map[string]map[string][]string)
+	for _, testCase := range cases {
+		if _, seen := required[testCase.Path]; seen {
+			continue
+		}
+		requires, err := scenario.LoadRequirements(testCase.Path)
+		if err != nil || len(requires) == 0 {
+			continue
+		}
+		required[testCase.Path] = requires
+	}
+	return required
+}
+
+// checkServices fails if the daemon reports a service that is not up. When
+// the daemon cannot tell, the run goes ahead.
+func (r *Runner) checkServices(ctx context.Context) error {
+	services, err := r.services.Status(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not get service status from the daemon: %v\n", err)
+		return nil
+	}
+	if down := daemon.Down(services); len(down) > 0 {
+		return fmt.Errorf("services are not running: %s; start them with `sentra lab start`", strings.Join(down, ", "))
+	}
+	return nil
+}
+
+// loadLatencyAllowance picks up the emulation overhead `sentra lab start`
+// measured and warns that latency expectations are widened by it.
+func (r *Runner) loadLatencyAllowance() {
+	report, err := emulation.Load(emulation.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Latency expectations will not allow for emulated mocks: %v\n", err)
+		return
+	}
+	r.latencyAllowance = report.Allowance()
+	if r.latencyAllowance > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Mocks run under emulation on %s; latency expectations allow an extra %s\n", report.Native, r.latencyAllowance)
+	}
+}
+
+// mockPorts returns the ports of the mocks a case runs against, or nil for
+// the mocks in lab.yaml.
+func mockPorts(testCase runCase) map[string]int {
+	if testCase.project == nil {
+		return nil
+	}
+	return testCase.project.MockPorts()
+}
+
+// resetMockState resets mock state before a scenario. It returns a failed
+// result if the reset fails, since the scenario would otherwise run against
+// state left behind by earlier ones.
+func (r *Runner) resetMockState(ctx context.Context, testCase runCase) (*TestResult, error) {
+	if err := r.isolation.Reset(ctx, testCase.Name, mockPorts(testCase)); err != nil {
+		now := time.Now()
+		return &TestResult{
+			Scenario:    testCase.Name,
+			Status:      "failed",
+			Failures:    []string{fmt.Sprintf("Failed to reset mock state: %v", err)},
+			StartedAt:   now,
+			CompletedAt: now,
+		}, err
+	}
+	return nil, nil
+}
+
+// withFakeData returns the case's variables with the fake data the scenario
+// references added ("fake.Email"), and fake data in variable values (such
+// as variables_from rows) rendered. Values are derived from the run seed
+// and the case, so each case gets its own.
+func (r *Runner) withFakeData(testCase runCase) (map[string]string, error) {
+	content, err := os.ReadFile(testCase.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	faker := fake.New(r.runSeed, testCase.Name)
+	variables, err := faker.Variables(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", testCase.Path, err)
+	}
+	if len(variables) == 0 && len(testCase.Variables) == 0 {
+		return testCase.Variables, nil
+	}
+
+	for name, value := range testCase.Variables {
+		rendered, err := faker.Render(value)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", name, err)
+		}
+		variables[name] = rendered
+	}
+	return variables, nil
+}
+
+func (r *Runner) runScenario(ctx context.Context, testCase runCase, progressFn func(string, string, float64)) (*TestResult, error) {
 	startTime := time.Now()
 
 	result := &TestResult{
-		Scenario:  scenarioPath,
+		Scenario:  testCase.Name,
 		StartedAt: startTime,
 	}
 
+	ctx, cancel, timeouts := r.withScenarioTimeout(ctx, testCase)
+	defer cancel()
+
+	variables, err := r.withFakeData(testCase)
+	if err != nil {
+		result.Status = "failed"
+		result.Failures = append(result.Failures, fmt.Sprintf("Failed to generate fake data: %v", err))
+		result.CompletedAt = time.Now()
+		result.Duration = time.Since(startTime)
+		return result, err
+	}
+
 	req := &grpc.StartSimulationRequest{
-		ScenarioPath: scenarioPath,
+		ScenarioPath: testCase.Path,
+		Variables:    variables,
 		Config: grpc.SimulationConfig{
-			RecordFullTrace: true,
+			RecordFullTrace:    true,
 			EnableCostTracking: true,
+			AgentLimits:        r.agentLimits,
+			Timeouts: grpc.Timeouts{
+				Step:     timeouts.Step,
+				Scenario: timeouts.Scenario,
+			},
+			LatencyAllowance: r.latencyAllowance,
 		},
 	}
 
+	if testCase.project != nil {
+		req.ProjectDir = testCase.project.Dir
+		req.MockPorts = testCase.project.MockPorts()
+	} else {
+		req.MockURLs = r.mockURLs
+	}
+
 	run, err := r.engineClient.StartSimulation(ctx, req)
 	if err != nil {
+		if timeout, ok := timedOut(ctx); ok {
+			return r.timeoutResult(result, timeout), nil
+		}
 		result.Status = "failed"
 		result.Failures = append(result.Failures, fmt.Sprintf("Failed to start simulation: %v", err))
 		result.CompletedAt = time.Now()
@@ -122,6 +789,9 @@ func (r *Runner) runScenario(ctx context.Context, scenarioPath string, progressF
 	for {
 		select {
 		case <-ctx.Done():
+			if timeout, ok := timedOut(ctx); ok {
+				return r.timeoutResult(result, timeout), nil
+			}
 			result.Status = "failed"
 			result.Failures = append(result.Failures, "Context canceled")
 			result.CompletedAt = time.Now()
@@ -131,6 +801,9 @@ func (r *Runner) runScenario(ctx context.Context, scenarioPath string, progressF
 		case <-ticker.C:
 			status, err := r.engineClient.GetSimulationStatus(ctx, run.ID)
 			if err != nil {
+				if timeout, ok := timedOut(ctx); ok {
+					return r.timeoutResult(result, timeout), nil
+				}
 				result.Status = "failed"
 				result.Failures = append(result.Failures, fmt.Sprintf("Failed to get status: %v", err))
 				result.CompletedAt = time.Now()
@@ -138,7 +811,7 @@ func (r *Runner) runScenario(ctx context.Context, scenarioPath string, progressF
 				return result, err
 			}
 
-			progressFn(scenarioPath, status.Status, status.Progress)
+			progressFn(testCase.Name, status.Status, status.Progress)
 
 			if status.Status == "completed" || status.Status == "failed" {
 				result.Status = status.Status
@@ -150,6 +823,14 @@ func (r *Runner) runScenario(ctx context.Context, scenarioPath string, progressF
 				result.Assertions = status.Assertions
 				result.Failures = status.Failures
 				result.CompletedAt = time.Now()
+				r.recordSteps(result.RunID, status.Steps)
+				r.recordFindings(result.RunID, status.Findings)
+				r.recordTimeout(result.Scenario, status.TimedOut)
+				r.stats.scenarioFinished(status.AgentStartup, status.AssertionEval)
+
+				if testCase.suite != nil && result.Status == "passed" {
+					testCase.suite.setOutputs(testCase.Path, status.Outputs)
+				}
 
 				return result, nil
 			}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/daemon"
+	"github.com/sentra-lab/cli/internal/workspace"
+)
+
+func TestOrderedCases(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		scenarios []string
+		want      []string
+		wantDeps  map[string][]string
+		wantErr   string
+	}{
+		{
+			name: "prerequisites first",
+			files: map[string]string{
+				"scenarios/refund.yaml": "depends_on: [charge.yaml]\n",
+				"scenarios/charge.yaml": "depends_on: [setup.yaml]\n",
+				"scenarios/setup.yaml":  "name: Setup\n",
+				"scenarios/chat.yaml":   "name: Chat\n",
+			},
+			scenarios: []string{"scenarios/refund.yaml", "scenarios/chat.yaml", "scenarios/charge.yaml"},
+			want:      []string{"scenarios/setup.yaml", "scenarios/charge.yaml", "scenarios/refund.yaml", "scenarios/chat.yaml"},
+			wantDeps: map[string][]string{
+				"scenarios/charge.yaml": {"scenarios/setup.yaml"},
+				"scenarios/refund.yaml": {"scenarios/charge.yaml"},
+			},
+		},
+		{
+			name: "one case per data row",
+			files: map[string]string{
+				"scenarios/login.yaml": "depends_on: [setup.yaml]\nvariables_from: users.csv\n",
+				"scenarios/users.csv":  "name,email\nalice,alice@example.com\nbob,bob@example.com\n",
+				"scenarios/setup.yaml": "name: Setup\n",
+			},
+			scenarios: []string{"scenarios/login.yaml"},
+			want:      []string{"scenarios/setup.yaml", "scenarios/login.yaml[alice]", "scenarios/login.yaml[bob]"},
+			wantDeps: map[string][]string{
+				"scenarios/login.yaml[alice]": {"scenarios/setup.yaml"},
+				"scenarios/login.yaml[bob]":   {"scenarios/setup.yaml"},
+			},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"scenarios/a.yaml": "depends_on: [b.yaml]\n",
+				"scenarios/b.yaml": "depends_on: [a.yaml]\n",
+			},
+			scenarios: []string{"scenarios/a.yaml"},
+			wantErr:   "dependency cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			writeFiles(t, tt.files)
+
+			project := &workspace.Project{Name: "agents/support"}
+			cases, err := orderedCases(tt.scenarios, project)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("orderedCases() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("orderedCases() error = %v", err)
+			}
+
+			var names []string
+			for _, testCase := range cases {
+				names = append(names, testCase.Name)
+				if testCase.project != project {
+					t.Errorf("%s: project = %v, want %v", testCase.Name, testCase.project, project)
+				}
+				if want := tt.wantDeps[testCase.Name]; !reflect.DeepEqual(testCase.dependsOn, want) {
+					t.Errorf("%s: dependsOn = %v, want %v", testCase.Name, testCase.dependsOn, want)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("orderedCases() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+// fakeServices reports canned service statuses, as a daemon would.
+type fakeServices struct {
+	services []daemon.ServiceStatus
+	err      error
+}
+
+func (f fakeServices) Status(ctx context.Context) ([]daemon.ServiceStatus, error) {
+	return f.services, f.err
+}
+
+func TestCheckServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		services fakeServices
+		wantErr  string
+	}{
+		{
+			name: "all up",
+			services: fakeServices{services: []daemon.ServiceStatus{
+				{Name: "simulation-engine", Status: "healthy"},
+				{Name: "mock-openai", Status: "starting"},
+			}},
+		},
+		{
+			name: "mock stopped",
+			services: fakeServices{services: []daemon.ServiceStatus{
+				{Name: "simulation-engine", Status: "healthy"},
+				{Name: "mock-openai", Status: "stopped"},
+				{Name: "mock-stripe", Status: "unhealthy"},
+			}},
+			wantErr: "services are not running: mock-openai (stopped), mock-stripe (unhealthy)",
+		},
+		{
+			name:     "daemon cannot tell",
+			services: fakeServices{err: errors.New("docker is not running")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(nil, 1, false)
+			runner.services = tt.services
+
+			err := runner.checkServices(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkServices() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkServices() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunScenariosWithServicesDown(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFiles(t, map[string]string{"scenarios/chat.yaml": "name: Chat\n"})
+
+	runner := NewRunner(nil, 1, false)
+	runner.services = fakeServices{services: []daemon.ServiceStatus{{Name: "simulation-engine", Status: "exited"}}}
+
+	results, err := runner.RunScenarios(context.Background(), []string{"scenarios/chat.yaml"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "simulation-engine (exited)") || results != nil {
+		t.Errorf("RunScenarios() = %v, %v; want no results and the engine not running", results, err)
+	}
+}
+
+func TestRunScenariosFailFastInParallel(t *testing.T) {
+	t.Chdir(t.TempDir())
+	files := map[string]string{}
+	var paths []string
+	for _, name := range []string{"a", "b", "c", "d"} {
+		path := "scenarios/" + name + ".yaml"
+		files[path] = "name: " + name + "\ninput: Hi {{ fake.Nickname }}\n"
+		paths = append(paths, path)
+	}
+	writeFiles(t, files)
+
+	// Every scenario fails at once; only the first failure stops the run
+	runner := NewRunner(nil, len(paths), true)
+	results, err := runner.RunScenarios(context.Background(), paths, func(string, string, float64) {})
+	if err == nil || !strings.Contains(err.Error(), "unknown fake data 'Nickname'") {
+		t.Errorf("RunScenarios() error = %v, want the first failure", err)
+	}
+	if len(results) != len(paths) {
+		t.Errorf("RunScenarios() returned %d results, want %d", len(results), len(paths))
+	}
+}
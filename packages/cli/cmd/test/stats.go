@@ -0,0 +1,105 @@
+package test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/results"
+)
+
+// runnerStats collects the runner's own metrics during a run: throughput,
+// how many scenarios queue for a slot and for how long, and the engine's
+// agent startup and assertion evaluation times.
+type runnerStats struct {
+	mu sync.Mutex
+
+	started  time.Time
+	parallel int
+
+	// queued is the current queue depth, since lastChange
+	queued     int
+	maxQueued  int
+	lastChange time.Time
+
+	// queueArea integrates queue depth over time, for the average
+	queueArea time.Duration
+
+	waits         []time.Duration
+	startups      []time.Duration
+	assertionEval []time.Duration
+	scenarios     int
+}
+
+func newRunnerStats(parallel int) *runnerStats {
+	now := time.Now()
+	return &runnerStats{started: now, parallel: parallel, lastChange: now}
+}
+
+// enqueue records a scenario that is ready to run and waiting for a slot.
+func (s *runnerStats) enqueue() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changeDepth(1)
+	return time.Now()
+}
+
+// dequeue records that a scenario queued at queuedAt left the queue; ran
+// is false when it was skipped instead of getting a slot.
+func (s *runnerStats) dequeue(queuedAt time.Time, ran bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changeDepth(-1)
+	if ran {
+		s.waits = append(s.waits, time.Since(queuedAt))
+	}
+}
+
+func (s *runnerStats) changeDepth(delta int) {
+	now := time.Now()
+	s.queueArea += time.Duration(s.queued) * now.Sub(s.lastChange)
+	s.lastChange = now
+
+	s.queued += delta
+	if s.queued > s.maxQueued {
+		s.maxQueued = s.queued
+	}
+}
+
+// scenarioFinished records the engine's timings for a scenario that ran.
+func (s *runnerStats) scenarioFinished(agentStartup, assertionEval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scenarios++
+	if agentStartup > 0 {
+		s.startups = append(s.startups, agentStartup)
+	}
+	if assertionEval > 0 {
+		s.assertionEval = append(s.assertionEval, assertionEval)
+	}
+}
+
+// finish summarizes the run.
+func (s *runnerStats) finish() *results.RunnerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changeDepth(0)
+	elapsed := time.Since(s.started)
+
+	stats := &results.RunnerStats{
+		Parallel:      s.parallel,
+		Scenarios:     s.scenarios,
+		MaxQueueDepth: s.maxQueued,
+		QueueWait:     results.NewTiming(s.waits),
+		AgentStartup:  results.NewTiming(s.startups),
+		AssertionEval: results.NewTiming(s.assertionEval),
+	}
+	if elapsed > 0 {
+		stats.ScenariosPerSecond = float64(s.scenarios) / elapsed.Seconds()
+		stats.AvgQueueDepth = float64(s.queueArea) / float64(elapsed)
+	}
+	return stats
+}
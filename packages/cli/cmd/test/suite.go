@@ -0,0 +1,137 @@
+package test
+
+import (
+	"sync"
+)
+
+// suiteRun tracks scenario outcomes during a run, so dependents wait for
+// their prerequisites, receive their outputs, and are skipped when a
+// prerequisite fails.
+type suiteRun struct {
+	mu sync.Mutex
+
+	// pending counts each scenario's unfinished cases
+	pending map[string]int
+
+	// failed are scenarios with a case that did not pass
+	failed map[string]bool
+
+	// outputs are each scenario's named outputs (merged across its cases)
+	outputs map[string]map[string]string
+
+	// done is closed when all of a scenario's cases have finished
+	done map[string]chan struct{}
+}
+
+func newSuiteRun(cases []runCase) *suiteRun {
+	s := &suiteRun{
+		pending: make(map[string]int),
+		failed:  make(map[string]bool),
+		outputs: make(map[string]map[string]string),
+		done:    make(map[string]chan struct{}),
+	}
+
+	for _, testCase := range cases {
+		if _, ok := s.done[testCase.Path]; !ok {
+			s.done[testCase.Path] = make(chan struct{})
+		}
+		s.pending[testCase.Path]++
+	}
+
+	return s
+}
+
+// wait blocks until the case's prerequisites have finished. It returns the
+// variables they output (in depends_on order, later ones winning), or the
+// first prerequisite that failed. ok is false if the run stopped first.
+func (s *suiteRun) wait(testCase runCase, stop <-chan struct{}) (variables map[string]string, failed string, ok bool) {
+	for _, dependency := range testCase.dependsOn {
+		done, known := s.done[dependency]
+		if !known {
+			continue
+		}
+		select {
+		case <-done:
+		case <-stop:
+			return nil, "", false
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	variables = make(map[string]string)
+	for _, dependency := range testCase.dependsOn {
+		if s.failed[dependency] {
+			return nil, dependency, true
+		}
+		for name, value := range s.outputs[dependency] {
+			variables[name] = value
+		}
+	}
+	return variables, "", true
+}
+
+// setOutputs records outputs of a scenario's case.
+func (s *suiteRun) setOutputs(path string, outputs map[string]string) {
+	if len(outputs) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.outputs[path] == nil {
+		s.outputs[path] = make(map[string]string)
+	}
+	for name, value := range outputs {
+		s.outputs[path][name] = value
+	}
+}
+
+// outputsOf returns a copy of a scenario's outputs.
+func (s *suiteRun) outputsOf(path string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.outputs[path]) == 0 {
+		return nil
+	}
+	outputs := make(map[string]string, len(s.outputs[path]))
+	for name, value := range s.outputs[path] {
+		outputs[name] = value
+	}
+	return outputs
+}
+
+// finish records that a case has finished.
+func (s *suiteRun) finish(path string, passed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !passed {
+		s.failed[path] = true
+	}
+
+	s.pending[path]--
+	if s.pending[path] == 0 {
+		close(s.done[path])
+	}
+}
+
+// withVariables returns the case's variables layered over its
+// prerequisites' outputs, so data rows override handed-off values.
+func withVariables(outputs, variables map[string]string) map[string]string {
+	if len(outputs) == 0 {
+		return variables
+	}
+
+	merged := make(map[string]string, len(outputs)+len(variables))
+	for name, value := range outputs {
+		merged[name] = value
+	}
+	for name, value := range variables {
+		merged[name] = value
+	}
+	return merged
+}
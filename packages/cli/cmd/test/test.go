@@ -5,20 +5,414 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/daemon"
 	"github.com/sentra-lab/cli/internal/grpc"
+	"github.com/sentra-lab/cli/internal/loadgen"
+	"github.com/sentra-lab/cli/internal/mockstate"
+	"github.com/sentra-lab/cli/internal/notify"
+	"github.com/sentra-lab/cli/internal/preflight"
 	"github.com/sentra-lab/cli/internal/reporter"
-	"github.com/sentra-lab/cli/internal/ui"
+	"github.com/sentra-lab/cli/internal/sdkusage"
 	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/sentra-lab/cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 type TestCommand struct {
 	logger       *utils.Logger
 	configLoader *config.Loader
+	configPath   string
+	config       *config.Config
+	workspace    *workspace.Workspace
 	engineClient *grpc.EngineClient
 	reporter     reporter.Reporter
-	parallel
+	parallel     int
+	failFast     bool
+	format       string
+	output       string
+	all          bool
+	changedSince string
+	cache        bool
+	strict       bool
+	timeout      time.Duration
+	targets      []string
+}
+
+// TestResult is the outcome of one test case.
+type TestResult struct {
+	Scenario    string        `json:"scenario"`
+	Status      string        `json:"status"`
+	RunID       string        `json:"run_id,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	CostUSD     float64       `json:"cost_usd"`
+	Assertions  int           `json:"assertions"`
+	Failures    []string      `json:"failures,omitempty"`
+	StartedAt   time.Time     `json:"started_at"`
+	CompletedAt time.Time     `json:"completed_at"`
+}
+
+// TestSummary totals a run's results.
+type TestSummary struct {
+	Total     int           `json:"total"`
+	Passed    int           `json:"passed"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Duration  time.Duration `json:"duration"`
+	TotalCost float64       `json:"total_cost"`
+}
+
+func NewTestCommand(logger *utils.Logger) *cobra.Command {
+	tc := &TestCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "test [scenario or directory...]",
+		Short: "Run test scenarios",
+		Long: `Run scenarios against the simulation engine and mocks.
+
+Scenarios default to every YAML file under scenarios/. Scenarios run after
+the scenarios they depend on, and a data-driven scenario runs once per row
+of its variables_from file.
+
+Example:
+  sentra lab test                                   # Run scenarios/
+  sentra lab test scenarios/refund.yaml             # Run one scenario
+  sentra lab test --all                             # Run every workspace project
+  sentra lab test --changed-since origin/main       # Run scenarios a change affects
+  sentra lab test --cache                           # Skip unchanged passing scenarios
+  sentra lab test --timeout 30m                     # Bound the whole run
+  sentra lab test --target http://mocks.internal    # Run against remote mocks
+  sentra lab test --format junit --output results.xml`,
+		PreRunE: tc.PreRunE,
+		RunE:    tc.RunE,
+	}
+
+	cmd.Flags().IntVarP(&tc.parallel, "parallel", "p", 0, "Scenarios to run at once (default: simulation.max_concurrent_scenarios)")
+	cmd.Flags().BoolVar(&tc.failFast, "fail-fast", false, "Stop at the first failing scenario")
+	cmd.Flags().StringVarP(&tc.format, "format", "f", "", "Also write results as json, junit, markdown or html")
+	cmd.Flags().StringVarP(&tc.output, "output", "o", "", "File for --format results (default: stdout)")
+	cmd.Flags().BoolVar(&tc.all, "all", false, "Run every project of the workspace")
+	cmd.Flags().StringVar(&tc.changedSince, "changed-since", "", "Run only scenarios impacted by changes since this git revision")
+	cmd.Flags().BoolVar(&tc.cache, "cache", false, "Skip scenarios whose inputs are unchanged since they last passed")
+	cmd.Flags().BoolVar(&tc.strict, "strict", false, "Run every scenario, even with --cache (also "+StrictEnv+")")
+	cmd.Flags().DurationVar(&tc.timeout, "timeout", 0, "Run timeout (overrides simulation.timeouts.run)")
+	cmd.Flags().StringArrayVar(&tc.targets, "target", nil, "Run against remote mocks: a URL serving every mock, or <mock>=<url>")
+
+	return cmd
+}
+
+func (tc *TestCommand) PreRunE(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+
+	if tc.all {
+		if len(args) > 0 || tc.changedSince != "" || len(tc.targets) > 0 {
+			return fmt.Errorf("--all runs every project's scenarios and cannot be combined with scenarios, --changed-since or --target")
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		path, err := workspace.Find(wd)
+		if err != nil {
+			return err
+		}
+		tc.workspace, err = workspace.Load(path)
+		if err != nil {
+			return err
+		}
+
+		// The engine and run settings come from the first project
+		configPath = filepath.Join(tc.workspace.Root, tc.workspace.Projects[0], "lab.yaml")
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return fmt.Errorf("config file not found: %s", configPath)
+	}
+
+	var err error
+	tc.configLoader, err = config.NewLoader(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	tc.config, err = tc.configLoader.Load()
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	tc.configPath = configPath
+
+	if len(tc.targets) > 0 {
+		if err := tc.config.ApplyTargets(tc.targets); err != nil {
+			return err
+		}
+	}
+
+	if tc.format != "" {
+		tc.reporter, err = newReporter(tc.format)
+		if err != nil {
+			return err
+		}
+	}
+
+	tc.engineClient, err = grpc.NewEngineClient(tc.config.GetEngineAddress())
+	if err != nil {
+		return fmt.Errorf("failed to create engine client: %w", err)
+	}
+	tc.logger.Subsystem(utils.SubsystemEngine).Debug("Connected to engine", "address", tc.config.GetEngineAddress())
+
+	return nil
+}
+
+func (tc *TestCommand) RunE(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	runner, err := tc.newRunner()
+	if err != nil {
+		return err
+	}
+
+	testReporter := NewTestReporter(verbose)
+	startTime := time.Now()
+
+	var results []*TestResult
+	if tc.workspace != nil {
+		// Results are saved at the workspace root
+		if err := os.Chdir(tc.workspace.Root); err != nil {
+			return err
+		}
+		fmt.Printf("\n🧪 Running %d workspace project(s)...\n\n", len(tc.workspace.Projects))
+		results, err = runner.RunWorkspace(ctx, tc.workspace, testReporter.ReportProgress)
+	} else {
+		var scenarios []string
+		scenarios, err = findScenarios(args)
+		if err != nil {
+			return err
+		}
+		if len(scenarios) == 0 {
+			return fmt.Errorf("no scenarios found; add YAML files under scenarios/")
+		}
+
+		if tc.changedSince != "" {
+			scenarios, err = SelectChanged(scenarios, tc.changedSince, tc.configPath)
+			if err != nil {
+				return err
+			}
+			if len(scenarios) == 0 {
+				return nil
+			}
+		}
+
+		testReporter.ReportStart(len(scenarios))
+		results, err = runner.RunScenarios(ctx, scenarios, testReporter.ReportProgress)
+	}
+	if err != nil && results == nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result != nil {
+			testReporter.ReportScenario(result)
+		}
+	}
+
+	summary := newSummary(results, time.Since(startTime))
+	testReporter.ReportSummary(summary)
+	testReporter.ReportRunnerStats(runner.RunnerStats())
+	if stats := runner.BackgroundLoadStats(); stats != nil {
+		fmt.Printf("Background load: %d request(s) at %.1f rps, %d rate limited, %d failed, %d dropped\n",
+			stats.Sent, stats.AchievedRPS(), stats.RateLimited, stats.Failed, stats.Dropped)
+	}
+	testReporter.ReportFailures(nonNil(results))
+
+	if tc.reporter != nil {
+		if err := tc.writeReport(summary, results); err != nil {
+			return err
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d scenario(s) failed", summary.Failed, summary.Total)
+	}
+	return nil
+}
+
+// newRunner builds a runner from lab.yaml and the command's flags.
+func (tc *TestCommand) newRunner() (*Runner, error) {
+	cfg := tc.config
+
+	parallel := tc.parallel
+	if parallel <= 0 {
+		parallel = cfg.Simulation.MaxConcurrentScenarios
+	}
+
+	runner := NewRunner(tc.engineClient, parallel, tc.failFast)
+
+	backgroundLoad, err := loadgen.NewGenerator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if backgroundLoad != nil {
+		runner.SetBackgroundLoad(backgroundLoad)
+	}
+	if notifier := notify.NewNotifier(cfg); notifier != nil {
+		runner.SetNotifier(notifier)
+	}
+	if collector := sdkusage.NewCollector(cfg); collector != nil {
+		runner.SetSDKUsage(collector)
+	}
+	if resetter := mockstate.NewResetter(cfg); resetter != nil {
+		runner.SetIsolation(resetter)
+	}
+	if checker := preflight.NewChecker(cfg); checker != nil {
+		runner.SetPreflight(checker)
+	}
+	if len(tc.targets) > 0 {
+		runner.SetTarget(cfg)
+	} else if client, err := daemon.Connect(); err == nil && tc.workspace == nil && client.Serves(tc.configPath) {
+		tc.logger.Debug("Using daemon", "pid", client.Info.PID)
+		runner.SetDaemon(client)
+	}
+
+	runner.SetSeed(cfg.Simulation.Seed)
+	runner.SetAgentLimits(grpc.AgentLimits{
+		CPUTime:  cfg.Agent.Limits.CPUTimeLimit(),
+		MemoryMB: cfg.Agent.Limits.MemoryMB,
+		WallTime: cfg.Agent.Limits.WallTimeLimit(),
+	})
+	if cfg.Simulation.ContextWarning > 0 {
+		runner.SetContextWarning(cfg.Simulation.ContextWarning)
+	}
+
+	timeouts := TimeoutsFrom(cfg)
+	if tc.timeout > 0 {
+		timeouts.Run = tc.timeout
+	}
+	runner.SetTimeouts(timeouts, mockstate.NewDeadlines(cfg))
+
+	if tc.cache {
+		if err := runner.SetResultCache(tc.configPath, tc.strict); err != nil {
+			return nil, err
+		}
+	}
+
+	return runner, nil
+}
+
+// writeReport writes the results in --format to --output, or stdout.
+func (tc *TestCommand) writeReport(summary *TestSummary, results []*TestResult) error {
+	if tc.output == "" {
+		return tc.reporter.Report(os.Stdout, summary, nonNil(results))
+	}
+
+	file, err := os.Create(tc.output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tc.output, err)
+	}
+	defer file.Close()
+
+	if err := tc.reporter.Report(file, summary, nonNil(results)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tc.output, err)
+	}
+	tc.logger.Info(fmt.Sprintf("Results written to %s", tc.output))
+	return nil
+}
+
+// newReporter returns the reporter for a --format.
+func newReporter(format string) (reporter.Reporter, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return reporter.NewJSONReporter(), nil
+	case "junit":
+		return reporter.NewJUnitReporter(), nil
+	case "markdown", "md":
+		return reporter.NewMarkdownReporter(), nil
+	case "html":
+		return reporter.NewHTMLReporter(), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (use json, junit, markdown or html)", format)
+	}
+}
+
+// findScenarios returns the scenario files among paths, walking
+// directories; no paths means the scenarios/ directory.
+func findScenarios(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"scenarios"}
+	}
+
+	var files []string
+	for _, path := range paths {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(path)
+			if !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("scenario path not found: %s", path)
+			}
+			return nil, fmt.Errorf("failed to scan scenarios: %w", err)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// newSummary totals results; cached passes count as passed.
+func newSummary(results []*TestResult, duration time.Duration) *TestSummary {
+	summary := &TestSummary{Duration: duration}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		summary.Total++
+		summary.TotalCost += result.CostUSD
+
+		switch result.Status {
+		case "passed", StatusCached:
+			summary.Passed++
+		case "skipped":
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+	}
+
+	return summary
+}
+
+// nonNil drops results of cases that never ran (e.g. after --fail-fast).
+func nonNil(results []*TestResult) []*TestResult {
+	kept := make([]*TestResult, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeFiles writes files relative to the working directory.
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNewSummary(t *testing.T) {
+	results := []*TestResult{
+		{Scenario: "scenarios/refund.yaml", Status: "passed", CostUSD: 0.25},
+		{Scenario: "scenarios/chat.yaml", Status: StatusCached},
+		{Scenario: "scenarios/login.yaml[alice]", Status: "failed", CostUSD: 0.5},
+		{Scenario: "scenarios/receipt.yaml", Status: "skipped"},
+		nil,
+	}
+
+	got := newSummary(results, time.Minute)
+	want := &TestSummary{Total: 4, Passed: 2, Failed: 1, Skipped: 1, Duration: time.Minute, TotalCost: 0.75}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newSummary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindScenarios(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFiles(t, map[string]string{
+		"scenarios/refund.yaml":       "name: Refund\n",
+		"scenarios/billing/chat.yml":  "name: Chat\n",
+		"scenarios/billing/users.csv": "name\nalice\n",
+		"other/login.yaml":            "name: Login\n",
+	})
+
+	tests := []struct {
+		name    string
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "scenarios directory by default",
+			want: []string{"scenarios/billing/chat.yml", "scenarios/refund.yaml"},
+		},
+		{
+			name:  "files and directories",
+			paths: []string{"other", "scenarios/refund.yaml"},
+			want:  []string{"other/login.yaml", "scenarios/refund.yaml"},
+		},
+		{
+			name:    "missing path",
+			paths:   []string{"missing.yaml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findScenarios(tt.paths)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findScenarios() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findScenarios() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReporter(t *testing.T) {
+	for _, format := range []string{"json", "junit", "markdown", "md", "HTML"} {
+		if _, err := newReporter(format); err != nil {
+			t.Errorf("newReporter(%q) error = %v", format, err)
+		}
+	}
+	if _, err := newReporter("pdf"); err == nil {
+		t.Error(`newReporter("pdf") error = nil, want an unknown format error`)
+	}
+}
@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/mockstate"
+	"github.com/sentra-lab/cli/internal/scenario"
+)
+
+// Levels of timeout, as reported when one fires.
+const (
+	TimeoutStep     = "step"
+	TimeoutScenario = "scenario"
+	TimeoutRun      = "run"
+)
+
+// timeoutGrace is how long after a scenario's timeout the runner gives up
+// on the engine reporting it, so the engine can name the step that hung
+const timeoutGrace = 5 * time.Second
+
+// stopTimeout bounds stopping a timed-out run on the engine and the mocks
+const stopTimeout = 5 * time.Second
+
+// Timeouts bound each step, scenario and the whole run; zero means no
+// limit.
+type Timeouts struct {
+	Step     time.Duration
+	Scenario time.Duration
+	Run      time.Duration
+}
+
+// TimeoutsFrom returns lab.yaml's simulation.timeouts.
+func TimeoutsFrom(cfg *config.Config) Timeouts {
+	return Timeouts{
+		Step:     cfg.Simulation.Timeouts.StepLimit(),
+		Scenario: cfg.Simulation.Timeouts.ScenarioLimit(),
+		Run:      cfg.Simulation.Timeouts.RunLimit(),
+	}
+}
+
+// SetTimeouts bounds each step, scenario and the run (simulation.timeouts,
+// or `sentra lab test --timeout` for the run). A scenario that times out
+// fails, naming the level that fired; once the run times out, scenarios
+// still running fail and the rest are skipped. deadlines, when not nil,
+// cancel the mocks' in-flight requests when a timeout fires.
+func (r *Runner) SetTimeouts(timeouts Timeouts, deadlines *mockstate.Deadlines) {
+	r.timeouts = timeouts
+	r.deadlines = deadlines
+}
+
+// timeoutError is the cause of a context ended by a timeout.
+type timeoutError struct {
+	level string
+	limit time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("%s timeout (%s) exceeded", e.level, e.limit)
+}
+
+// timedOut returns the timeout that ended ctx, if one did.
+func timedOut(ctx context.Context) (*timeoutError, bool) {
+	var timeout *timeoutError
+	if errors.As(context.Cause(ctx), &timeout) {
+		return timeout, true
+	}
+	return nil, false
+}
+
+// withRunTimeout bounds a run's scenarios by the run timeout. With
+// scenarios in parallel, the mocks get the run's deadline, as they are
+// shared.
+func (r *Runner) withRunTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	r.runDeadline = time.Time{}
+	if r.timeouts.Run <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	r.runDeadline = time.Now().Add(r.timeouts.Run)
+	if r.deadlines != nil && r.parallel > 1 {
+		r.setMockDeadline(ctx, r.runDeadline, TimeoutRun)
+	}
+	return context.WithDeadlineCause(ctx, r.runDeadline, &timeoutError{level: TimeoutRun, limit: r.timeouts.Run})
+}
+
+// withScenarioTimeout bounds a scenario by its own timeout, or the default
+// one. It returns the timeouts the engine enforces; the returned context
+// allows a grace period on top, in case the engine hangs too.
+func (r *Runner) withScenarioTimeout(ctx context.Context, testCase runCase) (context.Context, context.CancelFunc, Timeouts) {
+	timeouts := r.timeouts
+	if own, err := scenario.LoadTimeout(testCase.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	} else if own > 0 {
+		timeouts.Scenario = own
+	}
+	if timeouts.Scenario <= 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, timeouts
+	}
+
+	deadline := time.Now().Add(timeouts.Scenario)
+	if r.deadlines != nil && r.parallel == 1 {
+		if !r.runDeadline.IsZero() && r.runDeadline.Before(deadline) {
+			r.setMockDeadline(ctx, r.runDeadline, TimeoutRun)
+		} else {
+			r.setMockDeadline(ctx, deadline, TimeoutScenario)
+		}
+	}
+
+	ctx, cancel := context.WithDeadlineCause(ctx, deadline.Add(timeoutGrace), &timeoutError{level: TimeoutScenario, limit: timeouts.Scenario})
+	return ctx, cancel, timeouts
+}
+
+// stopTimedOut stops a run that timed out on the engine, and cancels what
+// the agent is still waiting on in the mocks, unless other scenarios share
+// them.
+func (r *Runner) stopTimedOut(runID string, timeout *timeoutError) {
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+
+	if runID != "" {
+		if err := r.engineClient.StopSimulation(ctx, runID); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to stop timed-out run %s: %v\n", runID, err)
+		}
+	}
+	if r.deadlines != nil && (r.parallel == 1 || timeout.level == TimeoutRun) {
+		r.setMockDeadline(ctx, time.Now(), timeout.level)
+	}
+}
+
+// setMockDeadline sets the mocks' deadline. Failures are printed but never
+// fail the run.
+func (r *Runner) setMockDeadline(ctx context.Context, deadline time.Time, level string) {
+	if err := r.deadlines.Set(ctx, deadline, level); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// clearMockDeadline removes the mocks' deadline once the run is over.
+func (r *Runner) clearMockDeadline() {
+	if r.deadlines == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	if err := r.deadlines.Clear(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// recordTimeout keeps the level of timeout that ended a case for the
+// results file.
+func (r *Runner) recordTimeout(name, level string) {
+	if level == "" {
+		return
+	}
+
+	r.stepsMu.Lock()
+	defer r.stepsMu.Unlock()
+	r.timedOut[name] = level
+}
+
+// timeoutResult fails a case that timed out.
+func (r *Runner) timeoutResult(result *TestResult, timeout *timeoutError) *TestResult {
+	r.stopTimedOut(result.RunID, timeout)
+	r.recordTimeout(result.Scenario, timeout.level)
+
+	result.Status = "failed"
+	result.Failures = append(result.Failures, timeoutFailure(timeout))
+	result.CompletedAt = time.Now()
+	result.Duration = result.CompletedAt.Sub(result.StartedAt)
+	return result
+}
+
+// timeoutFailure describes a timeout as a test failure, e.g. "Timed out:
+// scenario timeout (10m0s) exceeded".
+func timeoutFailure(timeout *timeoutError) string {
+	return fmt.Sprintf("Timed out: %s", timeout)
+}
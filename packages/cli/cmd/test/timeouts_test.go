@@ -0,0 +1,191 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/scenario"
+)
+
+func TestTimeoutsFrom(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Simulation.Timeouts = config.TimeoutsConfig{Step: "2m", Scenario: "10m", Run: "30m"}
+
+	want := Timeouts{Step: 2 * time.Minute, Scenario: 10 * time.Minute, Run: 30 * time.Minute}
+	if got := TimeoutsFrom(cfg); got != want {
+		t.Errorf("TimeoutsFrom() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTimedOut(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func() (context.Context, context.CancelFunc)
+		wantLevel string
+	}{
+		{
+			name: "run timeout",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadlineCause(context.Background(), time.Now(), &timeoutError{level: TimeoutRun, limit: time.Minute})
+			},
+			wantLevel: TimeoutRun,
+		},
+		{
+			name: "scenario timeout inside a run",
+			ctx: func() (context.Context, context.CancelFunc) {
+				run, cancelRun := context.WithCancel(context.Background())
+				ctx, cancel := context.WithDeadlineCause(run, time.Now(), &timeoutError{level: TimeoutScenario, limit: time.Minute})
+				return ctx, func() { cancel(); cancelRun() }
+			},
+			wantLevel: TimeoutScenario,
+		},
+		{
+			name: "canceled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+		},
+		{
+			name: "plain deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), time.Now())
+			},
+		},
+		{
+			name: "running",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			timeout, ok := timedOut(ctx)
+			if ok != (tt.wantLevel != "") {
+				t.Fatalf("timedOut() = %v, %v, want level %q", timeout, ok, tt.wantLevel)
+			}
+			if ok && timeout.level != tt.wantLevel {
+				t.Errorf("timedOut() level = %s, want %s", timeout.level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestWithRunTimeout(t *testing.T) {
+	r := NewRunner(nil, 1, false)
+
+	ctx, cancel := r.withRunTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok || !r.runDeadline.IsZero() {
+		t.Errorf("withRunTimeout() without a run timeout set a deadline")
+	}
+
+	r.SetTimeouts(Timeouts{Run: 10 * time.Millisecond}, nil)
+	ctx, cancel = r.withRunTimeout(context.Background())
+	defer cancel()
+	<-ctx.Done()
+
+	timeout, ok := timedOut(ctx)
+	if !ok || timeout.level != TimeoutRun || timeout.limit != 10*time.Millisecond {
+		t.Errorf("timedOut() = %v, %v, want the run timeout", timeout, ok)
+	}
+	if r.runDeadline.IsZero() {
+		t.Error("runDeadline not set")
+	}
+}
+
+func TestWithScenarioTimeout(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFiles(t, map[string]string{
+		"scenarios/default.yaml": "name: Default\n",
+		"scenarios/own.yaml":     "name: Own\ntimeout: 2m\n",
+		"scenarios/bad.yaml":     "name: Bad\ntimeout: soon\n",
+	})
+
+	tests := []struct {
+		name         string
+		defaults     Timeouts
+		path         string
+		wantScenario time.Duration
+		wantDeadline bool
+	}{
+		{name: "default", defaults: Timeouts{Step: time.Minute, Scenario: 10 * time.Minute}, path: "scenarios/default.yaml", wantScenario: 10 * time.Minute, wantDeadline: true},
+		{name: "own timeout", defaults: Timeouts{Scenario: 10 * time.Minute}, path: "scenarios/own.yaml", wantScenario: 2 * time.Minute, wantDeadline: true},
+		{name: "invalid own timeout", defaults: Timeouts{Scenario: 10 * time.Minute}, path: "scenarios/bad.yaml", wantScenario: 10 * time.Minute, wantDeadline: true},
+		{name: "no limit", path: "scenarios/default.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRunner(nil, 1, false)
+			r.SetTimeouts(tt.defaults, nil)
+
+			start := time.Now()
+			ctx, cancel, timeouts := r.withScenarioTimeout(context.Background(), runCase{Case: scenario.Case{Path: tt.path, Name: tt.path}})
+			defer cancel()
+
+			if timeouts.Scenario != tt.wantScenario || timeouts.Step != tt.defaults.Step {
+				t.Errorf("timeouts = %+v, want scenario %s and step %s", timeouts, tt.wantScenario, tt.defaults.Step)
+			}
+
+			deadline, ok := ctx.Deadline()
+			if ok != tt.wantDeadline {
+				t.Fatalf("Deadline() ok = %v, want %v", ok, tt.wantDeadline)
+			}
+			// The engine enforces the timeout; the runner allows a grace
+			// period on top
+			if ok && deadline.Before(start.Add(tt.wantScenario+timeoutGrace)) {
+				t.Errorf("Deadline() = %s, want at least %s with the grace period", deadline.Sub(start), tt.wantScenario+timeoutGrace)
+			}
+		})
+	}
+}
+
+func TestTimeoutResult(t *testing.T) {
+	r := NewRunner(nil, 1, false)
+	started := time.Now().Add(-time.Minute)
+	result := &TestResult{Scenario: "scenarios/refund.yaml", StartedAt: started}
+
+	got := r.timeoutResult(result, &timeoutError{level: TimeoutScenario, limit: 10 * time.Minute})
+
+	if got.Status != "failed" {
+		t.Errorf("Status = %s, want failed", got.Status)
+	}
+	if want := []string{"Timed out: scenario timeout (10m0s) exceeded"}; len(got.Failures) != 1 || got.Failures[0] != want[0] {
+		t.Errorf("Failures = %v, want %v", got.Failures, want)
+	}
+	if got.Duration < time.Minute {
+		t.Errorf("Duration = %s, want the time since the scenario started", got.Duration)
+	}
+	if level := r.timedOut["scenarios/refund.yaml"]; level != TimeoutScenario {
+		t.Errorf("recorded timeout = %q, want %s", level, TimeoutScenario)
+	}
+}
+
+func TestTimeoutFailure(t *testing.T) {
+	tests := []struct {
+		level string
+		limit time.Duration
+		want  string
+	}{
+		{level: TimeoutStep, limit: 2 * time.Minute, want: "Timed out: step timeout (2m0s) exceeded"},
+		{level: TimeoutScenario, limit: 10 * time.Minute, want: "Timed out: scenario timeout (10m0s) exceeded"},
+		{level: TimeoutRun, limit: 30 * time.Minute, want: "Timed out: run timeout (30m0s) exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := timeoutFailure(&timeoutError{level: tt.level, limit: tt.limit}); got != tt.want {
+				t.Errorf("timeoutFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
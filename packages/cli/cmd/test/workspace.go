@@ -0,0 +1,46 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/workspace"
+)
+
+// RunWorkspace runs the scenarios of every workspace project (`sentra lab
+// test --all`). Each project runs with its own config and mock ports; the
+// results are recorded and reported as one run, with scenarios named by
+// their path from the workspace root (e.g. "agents/billing/scenarios/refund.yaml").
+func (r *Runner) RunWorkspace(ctx context.Context, ws *workspace.Workspace, progressFn func(string, string, float64)) ([]*TestResult, error) {
+	projects, err := ws.LoadProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []runCase
+	for _, project := range projects {
+		scenarios, err := project.Scenarios()
+		if err != nil {
+			return nil, err
+		}
+		if len(scenarios) == 0 {
+			return nil, fmt.Errorf("project %s has no scenarios", project.Name)
+		}
+
+		projectCases, err := orderedCases(scenarios, project)
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", project.Name, err)
+		}
+
+		for _, testCase := range projectCases {
+			if rel, err := filepath.Rel(ws.Root, testCase.Path); err == nil {
+				testCase.Name = rel + strings.TrimPrefix(testCase.Name, testCase.Path)
+			}
+			cases = append(cases, testCase)
+		}
+	}
+
+	return r.runCases(ctx, cases, progressFn)
+}
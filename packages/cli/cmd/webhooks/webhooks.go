@@ -0,0 +1,369 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/ui"
+	"github.com/sentra-lab/cli/internal/utils"
+	"github.com/sentra-lab/cli/internal/webhooks"
+	"github.com/spf13/cobra"
+)
+
+type WebhooksCommand struct {
+	logger *utils.Logger
+	mock   string
+}
+
+func NewWebhooksCommand(logger *utils.Logger) *cobra.Command {
+	wc := &WebhooksCommand{
+		logger: logger,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "webhooks",
+		Short: "Inspect, re-deliver and trigger mock webhooks",
+		Long: `Test console for webhooks sent by the Stripe mock (and custom mocks).
+
+Commands:
+  • list                  - Show captured webhook deliveries
+  • replay <delivery-id>  - Re-deliver a webhook to the agent
+  • trigger <event-type>  - Send an arbitrary event with a fixture payload
+
+The mocks must be running ('sentra lab start').
+
+Example:
+  sentra lab webhooks list --type payment_intent.succeeded
+  sentra lab webhooks replay whd_123
+  sentra lab webhooks trigger charge.refunded --fixture fixtures/refund.json`,
+	}
+
+	cmd.PersistentFlags().StringVar(&wc.mock, "mock", "", "Only this mock (default: every enabled mock but openai)")
+
+	cmd.AddCommand(newListCommand(wc))
+	cmd.AddCommand(newReplayCommand(wc))
+	cmd.AddCommand(newTriggerCommand(wc))
+
+	return cmd
+}
+
+func newListCommand(wc *WebhooksCommand) *cobra.Command {
+	var (
+		filter  webhooks.Filter
+		asJSON  bool
+		payload bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Show captured webhook deliveries",
+		Long: `Show the webhook deliveries the mocks captured, newest first, with the
+agent's response status and delivery time.
+
+Example:
+  sentra lab webhooks list
+  sentra lab webhooks list --type payment_intent.succeeded --status failed
+  sentra lab webhooks list --limit 5 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := wc.clients(cmd)
+			if err != nil {
+				return err
+			}
+
+			var deliveries []webhooks.Delivery
+			for _, client := range clients {
+				list, err := client.List(cmd.Context(), filter)
+				if err != nil {
+					// Without --mock, mocks that send no webhooks are skipped
+					if errors.Is(err, webhooks.ErrNotSupported) && wc.mock == "" {
+						continue
+					}
+					return err
+				}
+
+				if payload {
+					for i := range list {
+						full, err := client.Get(cmd.Context(), list[i].ID)
+						if err != nil {
+							return err
+						}
+						list[i] = *full
+					}
+				}
+				deliveries = append(deliveries, list...)
+			}
+
+			sort.SliceStable(deliveries, func(i, j int) bool {
+				return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+			})
+			if filter.Limit > 0 && len(deliveries) > filter.Limit {
+				deliveries = deliveries[:filter.Limit]
+			}
+
+			if asJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(deliveries)
+			}
+
+			if len(deliveries) == 0 {
+				wc.logger.Info("No webhook deliveries captured")
+				return nil
+			}
+
+			table := ui.NewTable([]string{"ID", "MOCK", "EVENT", "STATUS", "RESPONSE", "ATTEMPTS", "SENT"})
+			for _, delivery := range deliveries {
+				table.AddRow([]string{
+					delivery.ID,
+					delivery.Mock,
+					delivery.EventType,
+					delivery.Status,
+					responseStatus(delivery),
+					fmt.Sprintf("%d", delivery.Attempts),
+					delivery.CreatedAt.Local().Format("15:04:05"),
+				})
+			}
+			fmt.Print(table.Render())
+
+			if payload {
+				for _, delivery := range deliveries {
+					fmt.Printf("\n%s (%s):\n", delivery.ID, delivery.EventType)
+					printPayload(delivery.Payload)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.EventType, "type", "", "Only this event type")
+	cmd.Flags().StringVar(&filter.Status, "status", "", "Only this delivery status (delivered, failed, pending)")
+	cmd.Flags().IntVar(&filter.Limit, "limit", 20, "Maximum deliveries to show")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print deliveries as JSON")
+	cmd.Flags().BoolVar(&payload, "payload", false, "Include event payloads")
+
+	return cmd
+}
+
+func newReplayCommand(wc *WebhooksCommand) *cobra.Command {
+	var (
+		opts           webhooks.DeliveryOptions
+		expectRejected bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay <delivery-id>",
+		Short: "Re-deliver a captured webhook to the agent",
+		Long: `Send a captured webhook again, with the same event and payload, to the
+URL it was first sent to (or --url, e.g. a local debug server).
+
+--skew signs the delivery with a shifted timestamp. Replaying with --skew
+-10m --expect-rejected checks that the agent refuses signatures outside
+Stripe's 5-minute tolerance.
+
+Example:
+  sentra lab webhooks replay whd_123
+  sentra lab webhooks replay whd_123 --url http://localhost:3000/webhooks/stripe
+  sentra lab webhooks replay whd_123 --skew -10m --expect-rejected`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clients, err := wc.clients(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := findDelivery(cmd.Context(), clients, args[0])
+			if err != nil {
+				return err
+			}
+
+			delivery, err := client.Redeliver(cmd.Context(), args[0], opts)
+			if err != nil {
+				return err
+			}
+
+			return wc.reportDelivery(delivery, expectRejected)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.URL, "url", "", "Deliver to this URL instead of the original one")
+	addSignatureFlags(cmd, &opts, &expectRejected)
+
+	return cmd
+}
+
+func newTriggerCommand(wc *WebhooksCommand) *cobra.Command {
+	var (
+		fixture        string
+		data           string
+		opts           webhooks.DeliveryOptions
+		expectRejected bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "trigger <event-type>",
+		Short: "Send an arbitrary webhook event to the agent",
+		Long: `Have a mock send a webhook event of any type, with the event's object
+taken from a fixture file (JSON or YAML) or inline JSON. Without a payload
+the mock fills in a default object for the event type.
+
+Events are sent by the Stripe mock unless --mock chooses another. --skew
+signs the event with a shifted timestamp (see 'webhooks replay').
+
+Example:
+  sentra lab webhooks trigger payment_intent.succeeded
+  sentra lab webhooks trigger charge.refunded --fixture fixtures/refund.json
+  sentra lab webhooks trigger invoice.paid --data '{"amount_paid": 4200}'
+  sentra lab webhooks trigger custom.event --mock coreledger --url http://localhost:3000/hooks
+  sentra lab webhooks trigger payment_intent.succeeded --skew -6m --expect-rejected`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fixture != "" && data != "" {
+				return fmt.Errorf("use either --fixture or --data, not both")
+			}
+
+			var object map[string]interface{}
+			switch {
+			case fixture != "":
+				loaded, err := webhooks.LoadFixture(fixture)
+				if err != nil {
+					return err
+				}
+				object = loaded
+			case data != "":
+				if err := json.Unmarshal([]byte(data), &object); err != nil {
+					return fmt.Errorf("invalid --data: %w", err)
+				}
+			}
+
+			clients, err := wc.clients(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := triggerClient(clients)
+			if err != nil {
+				return err
+			}
+
+			delivery, err := client.Trigger(cmd.Context(), args[0], object, opts)
+			if err != nil {
+				return err
+			}
+
+			return wc.reportDelivery(delivery, expectRejected)
+		},
+	}
+
+	cmd.Flags().StringVar(&fixture, "fixture", "", "JSON or YAML file with the event's object")
+	cmd.Flags().StringVar(&data, "data", "", "Inline JSON object for the event")
+	cmd.Flags().StringVar(&opts.URL, "url", "", "Deliver to this URL instead of the mock's webhook URL")
+	addSignatureFlags(cmd, &opts, &expectRejected)
+
+	return cmd
+}
+
+func (wc *WebhooksCommand) clients(cmd *cobra.Command) ([]*webhooks.Client, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = "lab.yaml"
+	}
+
+	loader, err := config.NewLoader(configPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return webhooks.NewClients(cfg, wc.mock)
+}
+
+// addSignatureFlags adds the flags that exercise the agent's webhook
+// signature checks.
+func addSignatureFlags(cmd *cobra.Command, opts *webhooks.DeliveryOptions, expectRejected *bool) {
+	cmd.Flags().DurationVar(&opts.TimestampSkew, "skew", 0, "Shift the signed timestamp (e.g. -10m for an expired signature)")
+	cmd.Flags().BoolVar(expectRejected, "expect-rejected", false, "Succeed only if the agent rejects the webhook with a 4xx")
+}
+
+// reportDelivery prints the outcome of a (re-)delivery. A delivery the
+// agent rejected is an error, so scripts can check the exit code; with
+// expectRejected it is the other way round.
+func (wc *WebhooksCommand) reportDelivery(delivery *webhooks.Delivery, expectRejected bool) error {
+	duration := time.Duration(delivery.DurationMs) * time.Millisecond
+
+	if expectRejected {
+		if delivery.ResponseStatus >= 400 && delivery.ResponseStatus < 500 {
+			wc.logger.Info(fmt.Sprintf("✓ %s rejected by %s (%s, %s) as %s",
+				delivery.EventType, delivery.URL, responseStatus(*delivery), duration, delivery.ID))
+			return nil
+		}
+		return fmt.Errorf("%s %s to %s was not rejected: agent responded %s",
+			delivery.EventType, delivery.ID, delivery.URL, responseStatus(*delivery))
+	}
+
+	if delivery.Status != "delivered" {
+		reason := delivery.Error
+		if reason == "" {
+			reason = fmt.Sprintf("agent responded %s", responseStatus(*delivery))
+		}
+		return fmt.Errorf("%s %s to %s failed: %s", delivery.EventType, delivery.ID, delivery.URL, reason)
+	}
+
+	wc.logger.Info(fmt.Sprintf("✓ %s delivered to %s (%s, %s) as %s",
+		delivery.EventType, delivery.URL, responseStatus(*delivery), duration, delivery.ID))
+	return nil
+}
+
+// findDelivery returns the client of the mock that captured a delivery.
+func findDelivery(ctx context.Context, clients []*webhooks.Client, id string) (*webhooks.Client, error) {
+	for _, client := range clients {
+		if _, err := client.Get(ctx, id); err == nil {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("no mock has delivery %s (see 'sentra lab webhooks list')", id)
+}
+
+// triggerClient picks the mock that sends triggered events: the only one
+// given, else Stripe.
+func triggerClient(clients []*webhooks.Client) (*webhooks.Client, error) {
+	if len(clients) == 1 {
+		return clients[0], nil
+	}
+
+	names := make([]string, 0, len(clients))
+	for _, client := range clients {
+		if client.Mock == "stripe" {
+			return client, nil
+		}
+		names = append(names, client.Mock)
+	}
+	return nil, fmt.Errorf("several mocks are enabled (%s); choose one with --mock", strings.Join(names, ", "))
+}
+
+func responseStatus(delivery webhooks.Delivery) string {
+	if delivery.ResponseStatus == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", delivery.ResponseStatus)
+}
+
+func printPayload(payload json.RawMessage) {
+	var value interface{}
+	if len(payload) == 0 || json.Unmarshal(payload, &value) != nil {
+		fmt.Println("  (no payload)")
+		return
+	}
+	formatted, _ := json.MarshalIndent(value, "  ", "  ")
+	fmt.Printf("  %s\n", formatted)
+}
@@ -29,6 +29,10 @@ func (l *Loader) Load() (*Config, error) {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	// Keep the document as written for Get and Raw
+	if err := yaml.Unmarshal(data, &config.raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
 
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -2,10 +2,32 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// MaxBackgroundLoadRPS caps simulation.background_load.rps
+const MaxBackgroundLoadRPS = 1000
+
+// SynthesisStrategies are the valid mocks.openai.synthesis_strategy values
+var SynthesisStrategies = []string{"auto", "echo", "list", "code", "refusal"}
+
+// JitterDistributions are the valid mocks.openai.jitter values
+var JitterDistributions = []string{"uniform", "gaussian", "exponential", "longtail"}
+
+// EgressModes are the valid egress.mode values
+var EgressModes = []string{"block", "warn"}
+
+// IsolationResets are the valid simulation.isolation.reset values
+var IsolationResets = []string{"scenario", "run", "none"}
+
+// IsolationStates are the valid simulation.isolation.state values
+var IsolationStates = []string{"usage", "rate_limits", "fixtures", "assistants", "threads", "images", "files", "fine_tuning", "overrides", "faults", "clock", "locale", "deadline"}
+
 type Config struct {
 	Name       string                 `yaml:"name"`
 	Version    string                 `yaml:"version"`
@@ -13,13 +35,41 @@ type Config struct {
 	Mocks      map[string]MockConfig  `yaml:"mocks"`
 	Simulation SimulationConfig       `yaml:"simulation"`
 	Storage    StorageConfig          `yaml:"storage"`
+	Notifications []NotificationConfig `yaml:"notifications"`
+	Egress     EgressConfig           `yaml:"egress"`
 	raw        map[string]interface{}
 }
 
 type AgentConfig struct {
-	Runtime    string `yaml:"runtime"`
-	EntryPoint string `yaml:"entry_point"`
-	Timeout    string `yaml:"timeout"`
+	Runtime    string      `yaml:"runtime"`
+	EntryPoint string      `yaml:"entry_point"`
+	Timeout    string      `yaml:"timeout"`
+	Limits     AgentLimits `yaml:"limits"`
+}
+
+// AgentLimits cap what the agent process may use during one scenario. The
+// engine kills an agent that goes over a limit and fails the scenario with
+// "resource limit exceeded", so runaway loops are caught locally.
+type AgentLimits struct {
+	// CPUTime is the CPU time (user + system) the agent may use
+	CPUTime string `yaml:"cpu_time"`
+	// MemoryMB is the agent's peak resident memory
+	MemoryMB int `yaml:"memory_mb"`
+	// WallTime is how long the agent may run, across all its requests
+	// (agent.timeout limits a single request)
+	WallTime string `yaml:"wall_time"`
+}
+
+// CPUTimeLimit returns the CPU time limit, or 0 if there is none.
+func (l AgentLimits) CPUTimeLimit() time.Duration {
+	d, _ := time.ParseDuration(l.CPUTime)
+	return d
+}
+
+// WallTimeLimit returns the wall time limit, or 0 if there is none.
+func (l AgentLimits) WallTimeLimit() time.Duration {
+	d, _ := time.ParseDuration(l.WallTime)
+	return d
 }
 
 type MockConfig struct {
@@ -28,12 +78,305 @@ type MockConfig struct {
 	LatencyMS int    `yaml:"latency_ms"`
 	RateLimit int    `yaml:"rate_limit"`
 	ErrorRate float64 `yaml:"error_rate"`
+
+	// ModelsFile registers custom models (fine-tuned or proprietary) with
+	// the OpenAI mock; see the mock's models.yaml format
+	ModelsFile string `yaml:"models_file"`
+
+	// SynthesisStrategy shapes the OpenAI mock's responses to prompts no
+	// fixture matches (auto, echo, list, code, refusal)
+	SynthesisStrategy string `yaml:"synthesis_strategy"`
+
+	// Jitter is the OpenAI mock's latency jitter distribution (uniform,
+	// gaussian, exponential, longtail); longtail gives realistic p95/p99
+	Jitter string `yaml:"jitter"`
+
+	// MaxStreamsPerKey caps the streaming requests an API key may have open
+	// at once on the OpenAI mock, which rejects more with a 429 like
+	// production (0 means unlimited)
+	MaxStreamsPerKey int `yaml:"max_streams_per_key"`
+
+	// WebhookTimestampSkew shifts the timestamp a mock signs its webhooks
+	// with (e.g. "-10m" for signatures older than Stripe's 5-minute
+	// tolerance), to exercise the agent's signature checks
+	WebhookTimestampSkew string `yaml:"webhook_timestamp_skew"`
+
+	// Image replaces the mock's default image (e.g. a specific release)
+	Image string `yaml:"image"`
+
+	// ImageDigest pins the image to a content digest ("sha256:..."), so
+	// every run uses exactly the same mock and a retagged or tampered image
+	// is refused
+	ImageDigest string `yaml:"image_digest"`
+
+	// CosignKey is a cosign public key the pinned image's signature must
+	// verify against (optional)
+	CosignKey string `yaml:"cosign_key"`
+
+	// Resources cap the mock's container (unset: no limit)
+	Resources MockResources `yaml:"resources"`
+
+	// Restart is the container's restart policy: no, always,
+	// unless-stopped (the default) or on-failure[:max-retries]
+	Restart string `yaml:"restart"`
+
+	// URL is the base URL of an externally managed mock, set by
+	// `sentra lab test --target` (empty: the local mock on Port)
+	URL string `yaml:"-"`
+}
+
+// BaseURL returns the URL the mock is reached at: its --target URL, or
+// localhost on its port.
+func (m MockConfig) BaseURL() string {
+	if m.URL != "" {
+		return m.URL
+	}
+	return fmt.Sprintf("http://localhost:%d", m.Port)
+}
+
+// MockResources are the CPU and memory limits of a mock's container.
+type MockResources struct {
+	// CPUs is the share of CPUs the container may use (e.g. 0.5)
+	CPUs float64 `yaml:"cpus"`
+	// MemoryMB is the container's memory limit; going over it gets the
+	// mock OOM-killed
+	MemoryMB int `yaml:"memory_mb"`
+}
+
+// MinMockMemoryMB is the lowest mocks.<name>.resources.memory_mb
+const MinMockMemoryMB = 32
+
+// RestartPolicies are the accepted mocks.<name>.restart values (on-failure
+// also takes a retry limit, e.g. "on-failure:3")
+var RestartPolicies = []string{"no", "always", "unless-stopped", "on-failure"}
+
+// ParseRestartPolicy splits a restart policy into its name and retry
+// limit ("on-failure:3" is on-failure with 3 retries; "" is
+// unless-stopped).
+func ParseRestartPolicy(policy string) (string, int, error) {
+	if policy == "" {
+		return "unless-stopped", 0, nil
+	}
+
+	name, retries, hasRetries := strings.Cut(policy, ":")
+	if !contains(RestartPolicies, name) {
+		return "", 0, fmt.Errorf("unknown restart policy %q (expected %s)", policy, strings.Join(RestartPolicies, ", "))
+	}
+	if !hasRetries {
+		return name, 0, nil
+	}
+
+	if name != "on-failure" {
+		return "", 0, fmt.Errorf("restart policy %q: only on-failure takes a retry limit", policy)
+	}
+	maxRetries, err := strconv.Atoi(retries)
+	if err != nil || maxRetries < 1 {
+		return "", 0, fmt.Errorf("restart policy %q: the retry limit must be a positive number", policy)
+	}
+	return name, maxRetries, nil
+}
+
+// PinnedImage returns the image the mock runs: Image, or defaultImage if
+// unset, pinned to ImageDigest if set (e.g.
+// "sentra/mock-openai:1.4.0@sha256:...").
+func (m MockConfig) PinnedImage(defaultImage string) string {
+	image := m.Image
+	if image == "" {
+		image = defaultImage
+	}
+	return PinImage(image, m.ImageDigest)
+}
+
+// PinImage pins an image reference to a digest. References that already
+// carry a digest, and empty digests, leave the reference unchanged.
+func PinImage(image, digest string) string {
+	if digest == "" || strings.Contains(image, "@") {
+		return image
+	}
+	return image + "@" + digest
+}
+
+// ImageDigest returns the digest an image reference is pinned to, or "".
+func ImageDigest(image string) string {
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		return digest
+	}
+	return ""
 }
 
 type SimulationConfig struct {
 	RecordFullTrace       bool `yaml:"record_full_trace"`
 	EnableCostTracking    bool `yaml:"enable_cost_tracking"`
 	MaxConcurrentScenarios int  `yaml:"max_concurrent_scenarios"`
+	BackgroundLoad        BackgroundLoadConfig `yaml:"background_load"`
+	Locale                LocaleConfig         `yaml:"locale"`
+
+	// ContextWarning is the share of a model's context window (0-1] at which
+	// a step's context length is flagged in reports (default 0.8)
+	ContextWarning float64 `yaml:"context_warning"`
+
+	// Isolation resets mock state between scenarios
+	Isolation IsolationConfig `yaml:"isolation"`
+
+	// SkipPreflight runs tests without first smoke-checking the mocks
+	SkipPreflight bool `yaml:"skip_preflight"`
+
+	// Seed is the run seed {{ fake.* }} test data is derived from; 0 picks
+	// a new one each run (recorded with the run's results)
+	Seed int64 `yaml:"seed"`
+
+	// Tracing exports scenario runs as OpenTelemetry traces
+	Tracing TracingConfig `yaml:"tracing"`
+
+	// Timeouts bound each step, scenario and run
+	Timeouts TimeoutsConfig `yaml:"timeouts"`
+}
+
+// TimeoutsConfig bounds how long a test run may take at each level, so a
+// hung agent fails its scenario instead of stalling CI. A scenario's own
+// timeout (and a step's) in its YAML overrides the default for it.
+type TimeoutsConfig struct {
+	// Step is how long a single step may run (default: no limit)
+	Step string `yaml:"step"`
+
+	// Scenario is how long a scenario may run, steps and assertions
+	// included (default 10m)
+	Scenario string `yaml:"scenario"`
+
+	// Run is how long a whole `sentra lab test` run may take (default: no
+	// limit)
+	Run string `yaml:"run"`
+}
+
+// StepLimit returns the step timeout, or 0 if there is none.
+func (t TimeoutsConfig) StepLimit() time.Duration {
+	d, _ := time.ParseDuration(t.Step)
+	return d
+}
+
+// ScenarioLimit returns the scenario timeout, or 0 if there is none.
+func (t TimeoutsConfig) ScenarioLimit() time.Duration {
+	d, _ := time.ParseDuration(t.Scenario)
+	return d
+}
+
+// RunLimit returns the run timeout, or 0 if there is none.
+func (t TimeoutsConfig) RunLimit() time.Duration {
+	d, _ := time.ParseDuration(t.Run)
+	return d
+}
+
+// TracingConfig sends the engine's scenario, step and API call spans, and
+// the mocks' own request spans, to an OpenTelemetry collector such as
+// Jaeger, so a run shows up as one trace per scenario.
+type TracingConfig struct {
+	// Endpoint is the collector's OTLP gRPC address as the services see it
+	// (e.g. "jaeger:4317"); tracing is off when empty
+	Endpoint string `yaml:"endpoint"`
+}
+
+// IsolationConfig controls how mock state (usage, rate limit buckets,
+// fixture statistics, ...) is reset between scenarios, so tests pass or fail
+// the same way whatever order they run in.
+type IsolationConfig struct {
+	// Reset is when mock state is reset: before each scenario ("scenario",
+	// the default), once before the run ("run") or never ("none")
+	Reset string `yaml:"reset"`
+
+	// State lists the kinds of state to reset; all of them when empty
+	State []string `yaml:"state"`
+}
+
+// LocaleConfig sets the locale, timezone and currency mock services use
+// for localized output (formatted amounts and timestamps). Empty fields keep
+// the mocks' defaults (en-US, UTC, USD); scenarios change them with the
+// set_locale step.
+type LocaleConfig struct {
+	// Locale is a language tag (e.g. "de-DE")
+	Locale string `yaml:"locale"`
+
+	// Timezone is an IANA timezone (e.g. "Europe/Berlin")
+	Timezone string `yaml:"timezone"`
+
+	// Currency is an ISO 4217 currency code (e.g. "EUR")
+	Currency string `yaml:"currency"`
+}
+
+// BackgroundLoadConfig configures synthetic traffic sent to the mocks while
+// scenarios run, so agents are tested under contention rather than against
+// an idle system.
+type BackgroundLoadConfig struct {
+	Enabled bool    `yaml:"enabled"`
+	RPS     float64 `yaml:"rps"`
+
+	// Mocks to load; every enabled mock when empty
+	Mocks []string `yaml:"mocks"`
+
+	// Model requested from the OpenAI mock
+	Model string `yaml:"model"`
+
+	// APIKey is sent to the OpenAI mock. It defaults to the key injected
+	// into agents, so background requests draw from the same rate limits
+	APIKey string `yaml:"api_key"`
+
+	// Concurrency caps in-flight requests; ticks beyond it are dropped
+	Concurrency int `yaml:"concurrency"`
+}
+
+// NotificationConfig posts a run summary to a chat channel when
+// `sentra lab test` finishes.
+type NotificationConfig struct {
+	// Type is "slack" or "teams"
+	Type string `yaml:"type"`
+
+	// WebhookURL is the channel's incoming webhook. ${VAR} references are
+	// expanded from the environment so the URL can be kept in CI secrets
+	WebhookURL string `yaml:"webhook_url"`
+
+	// On is "failure" (notify only when a scenario fails) or "always"
+	On string `yaml:"on"`
+}
+
+// EgressConfig runs agent traffic through a local proxy, started by
+// `sentra lab start`, that routes known API hosts to the mocks and blocks
+// (or warns on) any other external call, so tests are truly offline.
+type EgressConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+
+	// Mode is "block" (reject unexpected external calls) or "warn" (let
+	// them through); either way they are recorded as leaks
+	Mode string `yaml:"mode"`
+
+	// Allow lists hosts agents may reach directly; "*.example.com" matches
+	// subdomains. Loopback addresses are always allowed
+	Allow []string `yaml:"allow"`
+
+	// Routes send more hosts to a mock (host: mock name), on top of
+	// api.openai.com and api.stripe.com
+	Routes map[string]string `yaml:"routes"`
+
+	// Faults are network faults injected into agent traffic from the
+	// start; scenarios change them with network_faults steps
+	Faults NetworkFaults `yaml:"faults"`
+}
+
+// NetworkFaults inject client-facing network failures at the egress proxy.
+type NetworkFaults struct {
+	// DropRate is the share of connections closed without a response
+	DropRate float64 `yaml:"drop_rate"`
+
+	// DNSFailures are hosts whose lookup fails ("*.example.com" matches
+	// subdomains)
+	DNSFailures []string `yaml:"dns_failures"`
+
+	// StallRate is the share of requests that hang for Stall (default
+	// 30s) before they are forwarded
+	StallRate float64 `yaml:"stall_rate"`
+	Stall     string  `yaml:"stall"`
+
+	// Hosts limits drops and stalls to these hosts (default: every host)
+	Hosts []string `yaml:"hosts"`
 }
 
 type StorageConfig struct {
@@ -63,6 +406,409 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("agent.entry_point is required")
 	}
 
+	if err := c.validateBackgroundLoad(); err != nil {
+		return err
+	}
+
+	if err := c.validateNotifications(); err != nil {
+		return err
+	}
+
+	if err := c.Simulation.Locale.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateModelsFiles(); err != nil {
+		return err
+	}
+
+	if err := c.validateSynthesisStrategies(); err != nil {
+		return err
+	}
+
+	if err := c.validateJitter(); err != nil {
+		return err
+	}
+
+	if err := c.validateEgress(); err != nil {
+		return err
+	}
+
+	if err := c.validateStreamLimits(); err != nil {
+		return err
+	}
+
+	if err := c.validateWebhookSkew(); err != nil {
+		return err
+	}
+
+	if err := c.validateImagePins(); err != nil {
+		return err
+	}
+
+	if err := c.validateAgentLimits(); err != nil {
+		return err
+	}
+
+	if err := c.validateMockResources(); err != nil {
+		return err
+	}
+
+	if err := c.validateTimeouts(); err != nil {
+		return err
+	}
+
+	if w := c.Simulation.ContextWarning; w < 0 || w > 1 {
+		return fmt.Errorf("simulation.context_warning must be between 0 and 1, got %g", w)
+	}
+
+	if err := c.validateIsolation(); err != nil {
+		return err
+	}
+
+	if err := c.validateTracing(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Config) validateTracing() error {
+	endpoint := c.Simulation.Tracing.Endpoint
+	if endpoint == "" {
+		return nil
+	}
+
+	address := strings.TrimPrefix(strings.TrimPrefix(endpoint, "http://"), "https://")
+	if _, port, err := net.SplitHostPort(address); err != nil || port == "" {
+		return fmt.Errorf("simulation.tracing.endpoint: invalid address %q (expected host:port, e.g. jaeger:4317)", endpoint)
+	}
+
+	return nil
+}
+
+func (c *Config) validateIsolation() error {
+	isolation := c.Simulation.Isolation
+
+	if isolation.Reset != "" && !contains(IsolationResets, isolation.Reset) {
+		return fmt.Errorf("simulation.isolation.reset: invalid value %q (must be one of: %s)", isolation.Reset, strings.Join(IsolationResets, ", "))
+	}
+
+	for _, state := range isolation.State {
+		if !contains(IsolationStates, state) {
+			return fmt.Errorf("simulation.isolation.state: unknown state %q (must be one of: %s)", state, strings.Join(IsolationStates, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateBackgroundLoad() error {
+	load := c.Simulation.BackgroundLoad
+	if !load.Enabled {
+		return nil
+	}
+
+	if load.RPS <= 0 {
+		return fmt.Errorf("simulation.background_load.rps must be greater than 0")
+	}
+
+	if load.RPS > MaxBackgroundLoadRPS {
+		return fmt.Errorf("simulation.background_load.rps too high: %g (max %d)", load.RPS, MaxBackgroundLoadRPS)
+	}
+
+	if load.Concurrency < 0 {
+		return fmt.Errorf("simulation.background_load.concurrency cannot be negative")
+	}
+
+	for _, name := range load.Mocks {
+		mock, exists := c.Mocks[name]
+		if !exists || !mock.Enabled {
+			return fmt.Errorf("simulation.background_load.mocks: %s is not an enabled mock", name)
+		}
+	}
+
+	return nil
+}
+
+var (
+	localePattern   = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z]{2})?$`)
+	currencyPattern = regexp.MustCompile(`^[a-zA-Z]{3}$`)
+)
+
+// Validate checks each non-empty setting.
+func (l LocaleConfig) Validate() error {
+	if l.Locale != "" && !localePattern.MatchString(l.Locale) {
+		return fmt.Errorf("simulation.locale.locale: invalid locale %q (expected a language tag such as en-US)", l.Locale)
+	}
+
+	if l.Timezone != "" {
+		if _, err := time.LoadLocation(l.Timezone); err != nil {
+			return fmt.Errorf("simulation.locale.timezone: invalid timezone %q (expected an IANA timezone such as Europe/Berlin)", l.Timezone)
+		}
+	}
+
+	if l.Currency != "" && !currencyPattern.MatchString(l.Currency) {
+		return fmt.Errorf("simulation.locale.currency: invalid currency %q (expected an ISO 4217 code such as EUR)", l.Currency)
+	}
+
+	return nil
+}
+
+func (c *Config) validateNotifications() error {
+	for i, notification := range c.Notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+
+		if !contains([]string{"slack", "teams"}, notification.Type) {
+			return fmt.Errorf("%s.type: invalid type %q (must be one of: slack, teams)", field, notification.Type)
+		}
+
+		if notification.WebhookURL == "" {
+			return fmt.Errorf("%s.webhook_url is required", field)
+		}
+
+		if notification.On != "" && !contains([]string{"failure", "always"}, notification.On) {
+			return fmt.Errorf("%s.on: invalid condition %q (must be one of: failure, always)", field, notification.On)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateModelsFiles() error {
+	for name, mock := range c.Mocks {
+		if mock.ModelsFile == "" {
+			continue
+		}
+
+		if name != "openai" {
+			return fmt.Errorf("mocks.%s.models_file: only the openai mock supports custom models", name)
+		}
+
+		if _, err := os.Stat(mock.ModelsFile); err != nil {
+			return fmt.Errorf("mocks.%s.models_file: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateSynthesisStrategies() error {
+	for name, mock := range c.Mocks {
+		if mock.SynthesisStrategy == "" {
+			continue
+		}
+
+		if name != "openai" {
+			return fmt.Errorf("mocks.%s.synthesis_strategy: only the openai mock synthesizes responses", name)
+		}
+
+		if !contains(SynthesisStrategies, mock.SynthesisStrategy) {
+			return fmt.Errorf("mocks.%s.synthesis_strategy: invalid strategy %q (must be one of: %s)", name, mock.SynthesisStrategy, strings.Join(SynthesisStrategies, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateJitter() error {
+	for name, mock := range c.Mocks {
+		if mock.Jitter == "" {
+			continue
+		}
+
+		if name != "openai" {
+			return fmt.Errorf("mocks.%s.jitter: only the openai mock supports jitter distributions", name)
+		}
+
+		if !contains(JitterDistributions, mock.Jitter) {
+			return fmt.Errorf("mocks.%s.jitter: invalid distribution %q (must be one of: %s)", name, mock.Jitter, strings.Join(JitterDistributions, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateStreamLimits() error {
+	for name, mock := range c.Mocks {
+		if mock.MaxStreamsPerKey == 0 {
+			continue
+		}
+
+		if name != "openai" {
+			return fmt.Errorf("mocks.%s.max_streams_per_key: only the openai mock limits streams", name)
+		}
+
+		if mock.MaxStreamsPerKey < 0 {
+			return fmt.Errorf("mocks.%s.max_streams_per_key cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateWebhookSkew() error {
+	for name, mock := range c.Mocks {
+		if mock.WebhookTimestampSkew == "" {
+			continue
+		}
+
+		if name == "openai" {
+			return fmt.Errorf("mocks.%s.webhook_timestamp_skew: the openai mock sends no webhooks", name)
+		}
+
+		if _, err := time.ParseDuration(mock.WebhookTimestampSkew); err != nil {
+			return fmt.Errorf("mocks.%s.webhook_timestamp_skew: invalid duration %q (e.g. \"-10m\" or \"30s\")", name, mock.WebhookTimestampSkew)
+		}
+	}
+
+	return nil
+}
+
+// digestPattern matches an image content digest
+var digestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+func (c *Config) validateImagePins() error {
+	for name, mock := range c.Mocks {
+		inline := ImageDigest(mock.Image)
+		if inline != "" && !digestPattern.MatchString(inline) {
+			return fmt.Errorf("mocks.%s.image: invalid digest %q (expected sha256: and 64 hex digits)", name, inline)
+		}
+
+		if mock.ImageDigest != "" {
+			if !digestPattern.MatchString(mock.ImageDigest) {
+				return fmt.Errorf("mocks.%s.image_digest: invalid digest %q (expected sha256: and 64 hex digits)", name, mock.ImageDigest)
+			}
+			if inline != "" && inline != mock.ImageDigest {
+				return fmt.Errorf("mocks.%s.image_digest: %s does not match the digest in mocks.%s.image (%s)", name, mock.ImageDigest, name, inline)
+			}
+		}
+
+		if mock.CosignKey != "" {
+			if mock.ImageDigest == "" && inline == "" {
+				return fmt.Errorf("mocks.%s.cosign_key: signatures are only verified for pinned images; set mocks.%s.image_digest", name, name)
+			}
+			if _, err := os.Stat(mock.CosignKey); err != nil {
+				return fmt.Errorf("mocks.%s.cosign_key: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MinAgentMemoryMB is the lowest agent.limits.memory_mb; interpreters alone
+// need about this much
+const MinAgentMemoryMB = 64
+
+func (c *Config) validateAgentLimits() error {
+	limits := c.Agent.Limits
+
+	for field, value := range map[string]string{"cpu_time": limits.CPUTime, "wall_time": limits.WallTime} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("agent.limits.%s: invalid duration %q (e.g. \"30s\" or \"2m\")", field, value)
+		}
+		if d <= 0 {
+			return fmt.Errorf("agent.limits.%s must be positive, got %s", field, value)
+		}
+	}
+
+	if limits.MemoryMB != 0 && limits.MemoryMB < MinAgentMemoryMB {
+		return fmt.Errorf("agent.limits.memory_mb must be at least %d, got %d", MinAgentMemoryMB, limits.MemoryMB)
+	}
+
+	return nil
+}
+
+func (c *Config) validateMockResources() error {
+	for name, mock := range c.Mocks {
+		if mock.Resources.CPUs < 0 {
+			return fmt.Errorf("mocks.%s.resources.cpus cannot be negative, got %g", name, mock.Resources.CPUs)
+		}
+
+		if memory := mock.Resources.MemoryMB; memory != 0 && memory < MinMockMemoryMB {
+			return fmt.Errorf("mocks.%s.resources.memory_mb must be at least %d, got %d", name, MinMockMemoryMB, memory)
+		}
+
+		if _, _, err := ParseRestartPolicy(mock.Restart); err != nil {
+			return fmt.Errorf("mocks.%s.restart: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateTimeouts() error {
+	timeouts := c.Simulation.Timeouts
+
+	for field, value := range map[string]string{"step": timeouts.Step, "scenario": timeouts.Scenario, "run": timeouts.Run} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("simulation.timeouts.%s: invalid duration %q (e.g. \"2m\" or \"1h\")", field, value)
+		}
+		if d <= 0 {
+			return fmt.Errorf("simulation.timeouts.%s must be positive, got %s", field, value)
+		}
+	}
+
+	step, scenario, run := timeouts.StepLimit(), timeouts.ScenarioLimit(), timeouts.RunLimit()
+	if step > 0 && scenario > 0 && step > scenario {
+		return fmt.Errorf("simulation.timeouts.step (%s) must not exceed simulation.timeouts.scenario (%s)", timeouts.Step, timeouts.Scenario)
+	}
+	if scenario > 0 && run > 0 && scenario > run {
+		return fmt.Errorf("simulation.timeouts.scenario (%s) must not exceed simulation.timeouts.run (%s)", timeouts.Scenario, timeouts.Run)
+	}
+
+	return nil
+}
+
+func (c *Config) validateEgress() error {
+	egress := c.Egress
+	if !egress.Enabled {
+		return nil
+	}
+
+	if egress.Port < 0 || egress.Port > 65535 {
+		return fmt.Errorf("egress.port: invalid port %d", egress.Port)
+	}
+
+	if egress.Mode != "" && !contains(EgressModes, egress.Mode) {
+		return fmt.Errorf("egress.mode: invalid mode %q (must be one of: %s)", egress.Mode, strings.Join(EgressModes, ", "))
+	}
+
+	for _, host := range egress.Allow {
+		if host == "" || strings.Contains(host, "/") {
+			return fmt.Errorf("egress.allow: invalid host %q (expected a host name such as api.example.com)", host)
+		}
+	}
+
+	for host, name := range egress.Routes {
+		mock, exists := c.Mocks[name]
+		if !exists || !mock.Enabled {
+			return fmt.Errorf("egress.routes.%s: %s is not an enabled mock", host, name)
+		}
+	}
+
+	faults := egress.Faults
+	if faults.DropRate < 0 || faults.DropRate > 1 {
+		return fmt.Errorf("egress.faults.drop_rate must be between 0 and 1, got %g", faults.DropRate)
+	}
+	if faults.StallRate < 0 || faults.StallRate > 1 {
+		return fmt.Errorf("egress.faults.stall_rate must be between 0 and 1, got %g", faults.StallRate)
+	}
+	if faults.Stall != "" {
+		if d, err := time.ParseDuration(faults.Stall); err != nil || d <= 0 {
+			return fmt.Errorf("egress.faults.stall: invalid duration %q (e.g. \"30s\")", faults.Stall)
+		}
+	}
+
 	return nil
 }
 
@@ -75,6 +821,40 @@ func (c *Config) ApplyDefaults() {
 		c.Simulation.MaxConcurrentScenarios = 10
 	}
 
+	if c.Simulation.Timeouts.Scenario == "" {
+		c.Simulation.Timeouts.Scenario = "10m"
+	}
+
+	if c.Simulation.BackgroundLoad.Model == "" {
+		c.Simulation.BackgroundLoad.Model = "gpt-3.5-turbo"
+	}
+
+	if c.Simulation.BackgroundLoad.APIKey == "" {
+		c.Simulation.BackgroundLoad.APIKey = "mock_key_sentra_lab"
+	}
+
+	if c.Simulation.BackgroundLoad.Concurrency == 0 {
+		c.Simulation.BackgroundLoad.Concurrency = 32
+	}
+
+	if c.Simulation.Isolation.Reset == "" {
+		c.Simulation.Isolation.Reset = "scenario"
+	}
+
+	if c.Egress.Port == 0 {
+		c.Egress.Port = 8899
+	}
+
+	if c.Egress.Mode == "" {
+		c.Egress.Mode = "block"
+	}
+
+	for i := range c.Notifications {
+		if c.Notifications[i].On == "" {
+			c.Notifications[i].On = "failure"
+		}
+	}
+
 	if c.Storage.RecordingsDir == "" {
 		c.Storage.RecordingsDir = ".sentra-lab/recordings"
 	}
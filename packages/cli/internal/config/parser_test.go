@@ -0,0 +1,447 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDigest is a well-formed image digest.
+var testDigest = "sha256:" + strings.Repeat("ab", 32)
+
+// validConfig returns a minimal valid config with the openai and stripe
+// mocks enabled.
+func validConfig() *Config {
+	return &Config{
+		Name:    "test-lab",
+		Version: "1.0",
+		Agent:   AgentConfig{Runtime: "python", EntryPoint: "agent.py"},
+		Mocks: map[string]MockConfig{
+			"openai": {Enabled: true, Port: 8080},
+			"stripe": {Enabled: true, Port: 8081},
+		},
+	}
+}
+
+// writeFile writes a file in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigValidate(t *testing.T) {
+	modelsFile := writeFile(t, "models.yaml", "models: []\n")
+	cosignKey := writeFile(t, "cosign.pub", "key")
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(c *Config) {}},
+		{name: "missing name", mutate: func(c *Config) { c.Name = "" }, wantErr: "name is required"},
+		{name: "unknown runtime", mutate: func(c *Config) { c.Agent.Runtime = "ruby" }, wantErr: "invalid agent.runtime"},
+
+		{
+			name: "background load",
+			mutate: func(c *Config) {
+				c.Simulation.BackgroundLoad = BackgroundLoadConfig{Enabled: true, RPS: 50, Mocks: []string{"openai"}}
+			},
+		},
+		{
+			name:    "background load without rps",
+			mutate:  func(c *Config) { c.Simulation.BackgroundLoad = BackgroundLoadConfig{Enabled: true} },
+			wantErr: "rps must be greater than 0",
+		},
+		{
+			name: "background load rps too high",
+			mutate: func(c *Config) {
+				c.Simulation.BackgroundLoad = BackgroundLoadConfig{Enabled: true, RPS: MaxBackgroundLoadRPS + 1}
+			},
+			wantErr: "rps too high",
+		},
+		{
+			name: "background load on a disabled mock",
+			mutate: func(c *Config) {
+				c.Simulation.BackgroundLoad = BackgroundLoadConfig{Enabled: true, RPS: 5, Mocks: []string{"coreledger"}}
+			},
+			wantErr: "coreledger is not an enabled mock",
+		},
+
+		{
+			name: "notifications",
+			mutate: func(c *Config) {
+				c.Notifications = []NotificationConfig{{Type: "slack", WebhookURL: "${SLACK_WEBHOOK}", On: "always"}}
+			},
+		},
+		{
+			name:    "notification type",
+			mutate:  func(c *Config) { c.Notifications = []NotificationConfig{{Type: "email", WebhookURL: "x"}} },
+			wantErr: "notifications[0].type",
+		},
+		{
+			name:    "notification without a webhook",
+			mutate:  func(c *Config) { c.Notifications = []NotificationConfig{{Type: "teams"}} },
+			wantErr: "notifications[0].webhook_url is required",
+		},
+		{
+			name: "notification condition",
+			mutate: func(c *Config) {
+				c.Notifications = []NotificationConfig{{Type: "slack", WebhookURL: "x", On: "success"}}
+			},
+			wantErr: "notifications[0].on",
+		},
+
+		{
+			name: "locale",
+			mutate: func(c *Config) {
+				c.Simulation.Locale = LocaleConfig{Locale: "de-DE", Timezone: "Europe/Berlin", Currency: "EUR"}
+			},
+		},
+		{name: "invalid locale", mutate: func(c *Config) { c.Simulation.Locale.Locale = "german" }, wantErr: "simulation.locale.locale"},
+		{name: "invalid timezone", mutate: func(c *Config) { c.Simulation.Locale.Timezone = "Mars/Olympus" }, wantErr: "simulation.locale.timezone"},
+		{name: "invalid currency", mutate: func(c *Config) { c.Simulation.Locale.Currency = "EURO" }, wantErr: "simulation.locale.currency"},
+
+		{name: "models file", mutate: func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.ModelsFile = modelsFile }) }},
+		{
+			name:    "missing models file",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.ModelsFile = "missing.yaml" }) },
+			wantErr: "mocks.openai.models_file",
+		},
+		{
+			name:    "models file on another mock",
+			mutate:  func(c *Config) { setMock(c, "stripe", func(m *MockConfig) { m.ModelsFile = modelsFile }) },
+			wantErr: "only the openai mock supports custom models",
+		},
+		{
+			name:    "synthesis strategy",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.SynthesisStrategy = "poetry" }) },
+			wantErr: "invalid strategy",
+		},
+		{
+			name:    "jitter distribution",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.Jitter = "pareto" }) },
+			wantErr: "invalid distribution",
+		},
+		{
+			name:    "jitter on another mock",
+			mutate:  func(c *Config) { setMock(c, "stripe", func(m *MockConfig) { m.Jitter = "longtail" }) },
+			wantErr: "only the openai mock supports jitter",
+		},
+		{
+			name:    "negative stream limit",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.MaxStreamsPerKey = -1 }) },
+			wantErr: "cannot be negative",
+		},
+		{
+			name:    "stream limit on another mock",
+			mutate:  func(c *Config) { setMock(c, "stripe", func(m *MockConfig) { m.MaxStreamsPerKey = 2 }) },
+			wantErr: "only the openai mock limits streams",
+		},
+		{name: "webhook skew", mutate: func(c *Config) { setMock(c, "stripe", func(m *MockConfig) { m.WebhookTimestampSkew = "-10m" }) }},
+		{
+			name:    "invalid webhook skew",
+			mutate:  func(c *Config) { setMock(c, "stripe", func(m *MockConfig) { m.WebhookTimestampSkew = "ten minutes" }) },
+			wantErr: "invalid duration",
+		},
+		{
+			name:    "webhook skew on the openai mock",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.WebhookTimestampSkew = "1m" }) },
+			wantErr: "sends no webhooks",
+		},
+
+		{
+			name: "pinned image",
+			mutate: func(c *Config) {
+				setMock(c, "openai", func(m *MockConfig) { m.ImageDigest = testDigest; m.CosignKey = cosignKey })
+			},
+		},
+		{
+			name:    "invalid digest",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.ImageDigest = "sha256:abc" }) },
+			wantErr: "mocks.openai.image_digest: invalid digest",
+		},
+		{
+			name:    "invalid inline digest",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.Image = "sentra/mock-openai@md5:abc" }) },
+			wantErr: "mocks.openai.image: invalid digest",
+		},
+		{
+			name: "conflicting digests",
+			mutate: func(c *Config) {
+				setMock(c, "openai", func(m *MockConfig) {
+					m.Image = "sentra/mock-openai@sha256:" + strings.Repeat("cd", 32)
+					m.ImageDigest = testDigest
+				})
+			},
+			wantErr: "does not match",
+		},
+		{
+			name:    "cosign key without a pin",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.CosignKey = cosignKey }) },
+			wantErr: "only verified for pinned images",
+		},
+
+		{name: "agent limits", mutate: func(c *Config) { c.Agent.Limits = AgentLimits{CPUTime: "30s", MemoryMB: 256, WallTime: "2m"} }},
+		{name: "invalid agent cpu time", mutate: func(c *Config) { c.Agent.Limits.CPUTime = "soon" }, wantErr: "agent.limits.cpu_time"},
+		{name: "negative agent wall time", mutate: func(c *Config) { c.Agent.Limits.WallTime = "-1m" }, wantErr: "agent.limits.wall_time must be positive"},
+		{name: "agent memory too low", mutate: func(c *Config) { c.Agent.Limits.MemoryMB = 16 }, wantErr: "agent.limits.memory_mb"},
+
+		{
+			name: "mock resources",
+			mutate: func(c *Config) {
+				setMock(c, "openai", func(m *MockConfig) { m.Resources = MockResources{CPUs: 0.5, MemoryMB: 128}; m.Restart = "on-failure:3" })
+			},
+		},
+		{
+			name:    "negative mock cpus",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.Resources.CPUs = -1 }) },
+			wantErr: "resources.cpus cannot be negative",
+		},
+		{
+			name:    "mock memory too low",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.Resources.MemoryMB = 8 }) },
+			wantErr: "resources.memory_mb",
+		},
+		{
+			name:    "restart policy",
+			mutate:  func(c *Config) { setMock(c, "openai", func(m *MockConfig) { m.Restart = "sometimes" }) },
+			wantErr: "mocks.openai.restart",
+		},
+
+		{name: "timeouts", mutate: func(c *Config) { c.Simulation.Timeouts = TimeoutsConfig{Step: "1m", Scenario: "5m", Run: "1h"} }},
+		{name: "invalid timeout", mutate: func(c *Config) { c.Simulation.Timeouts.Run = "forever" }, wantErr: "simulation.timeouts.run"},
+		{
+			name:    "step timeout above the scenario's",
+			mutate:  func(c *Config) { c.Simulation.Timeouts = TimeoutsConfig{Step: "10m", Scenario: "5m"} },
+			wantErr: "simulation.timeouts.step (10m) must not exceed",
+		},
+		{
+			name:    "scenario timeout above the run's",
+			mutate:  func(c *Config) { c.Simulation.Timeouts = TimeoutsConfig{Scenario: "2h", Run: "1h"} },
+			wantErr: "simulation.timeouts.scenario (2h) must not exceed",
+		},
+
+		{name: "context warning", mutate: func(c *Config) { c.Simulation.ContextWarning = 1.5 }, wantErr: "simulation.context_warning"},
+
+		{
+			name: "isolation",
+			mutate: func(c *Config) {
+				c.Simulation.Isolation = IsolationConfig{Reset: "run", State: []string{"usage", "deadline"}}
+			},
+		},
+		{name: "isolation reset", mutate: func(c *Config) { c.Simulation.Isolation.Reset = "step" }, wantErr: "simulation.isolation.reset"},
+		{name: "isolation state", mutate: func(c *Config) { c.Simulation.Isolation.State = []string{"sessions"} }, wantErr: "unknown state"},
+
+		{name: "tracing", mutate: func(c *Config) { c.Simulation.Tracing.Endpoint = "http://jaeger:4317" }},
+		{name: "tracing without a port", mutate: func(c *Config) { c.Simulation.Tracing.Endpoint = "jaeger" }, wantErr: "simulation.tracing.endpoint"},
+
+		{
+			name: "egress",
+			mutate: func(c *Config) {
+				c.Egress = EgressConfig{
+					Enabled: true,
+					Mode:    "warn",
+					Allow:   []string{"*.internal.example.com"},
+					Routes:  map[string]string{"api.anthropic.com": "openai"},
+					Faults:  NetworkFaults{DropRate: 0.1, StallRate: 0.05, Stall: "5s"},
+				}
+			},
+		},
+		{name: "egress mode", mutate: func(c *Config) { c.Egress = EgressConfig{Enabled: true, Mode: "log"} }, wantErr: "egress.mode"},
+		{name: "egress port", mutate: func(c *Config) { c.Egress = EgressConfig{Enabled: true, Port: 70000} }, wantErr: "egress.port"},
+		{
+			name:    "egress allow URL",
+			mutate:  func(c *Config) { c.Egress = EgressConfig{Enabled: true, Allow: []string{"https://example.com/"}} },
+			wantErr: "egress.allow",
+		},
+		{
+			name: "egress route to a disabled mock",
+			mutate: func(c *Config) {
+				c.Egress = EgressConfig{Enabled: true, Routes: map[string]string{"api.example.com": "coreledger"}}
+			},
+			wantErr: "egress.routes.api.example.com",
+		},
+		{
+			name:    "egress drop rate",
+			mutate:  func(c *Config) { c.Egress = EgressConfig{Enabled: true, Faults: NetworkFaults{DropRate: 2}} },
+			wantErr: "egress.faults.drop_rate",
+		},
+		{
+			name:    "egress stall",
+			mutate:  func(c *Config) { c.Egress = EgressConfig{Enabled: true, Faults: NetworkFaults{Stall: "0s"}} },
+			wantErr: "egress.faults.stall",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.mutate(c)
+
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// setMock changes a mock's configuration.
+func setMock(c *Config, name string, mutate func(m *MockConfig)) {
+	mock := c.Mocks[name]
+	mutate(&mock)
+	c.Mocks[name] = mock
+}
+
+func TestParseRestartPolicy(t *testing.T) {
+	tests := []struct {
+		policy      string
+		wantName    string
+		wantRetries int
+		wantErr     bool
+	}{
+		{policy: "", wantName: "unless-stopped"},
+		{policy: "always", wantName: "always"},
+		{policy: "no", wantName: "no"},
+		{policy: "on-failure", wantName: "on-failure"},
+		{policy: "on-failure:3", wantName: "on-failure", wantRetries: 3},
+		{policy: "on-failure:0", wantErr: true},
+		{policy: "on-failure:many", wantErr: true},
+		{policy: "always:3", wantErr: true},
+		{policy: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			name, retries, err := ParseRestartPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRestartPolicy(%q) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+			}
+			if name != tt.wantName || retries != tt.wantRetries {
+				t.Errorf("ParseRestartPolicy(%q) = %q, %d, want %q, %d", tt.policy, name, retries, tt.wantName, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestPinnedImage(t *testing.T) {
+	const defaultImage = "sentra/mock-openai:latest"
+
+	tests := []struct {
+		name       string
+		mock       MockConfig
+		want       string
+		wantDigest string
+	}{
+		{name: "default image", want: defaultImage},
+		{name: "custom image", mock: MockConfig{Image: "sentra/mock-openai:1.4.0"}, want: "sentra/mock-openai:1.4.0"},
+		{
+			name:       "pinned default image",
+			mock:       MockConfig{ImageDigest: testDigest},
+			want:       defaultImage + "@" + testDigest,
+			wantDigest: testDigest,
+		},
+		{
+			name:       "image already pinned",
+			mock:       MockConfig{Image: "sentra/mock-openai@" + testDigest, ImageDigest: testDigest},
+			want:       "sentra/mock-openai@" + testDigest,
+			wantDigest: testDigest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.mock.PinnedImage(defaultImage)
+			if got != tt.want {
+				t.Errorf("PinnedImage() = %q, want %q", got, tt.want)
+			}
+			if digest := ImageDigest(got); digest != tt.wantDigest {
+				t.Errorf("ImageDigest(%q) = %q, want %q", got, digest, tt.wantDigest)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() time.Duration
+		want time.Duration
+	}{
+		{name: "agent cpu time", got: AgentLimits{CPUTime: "30s"}.CPUTimeLimit, want: 30 * time.Second},
+		{name: "agent wall time", got: AgentLimits{WallTime: "2m"}.WallTimeLimit, want: 2 * time.Minute},
+		{name: "no agent limit", got: AgentLimits{}.CPUTimeLimit},
+		{name: "step timeout", got: TimeoutsConfig{Step: "1m"}.StepLimit, want: time.Minute},
+		{name: "scenario timeout", got: TimeoutsConfig{Scenario: "10m"}.ScenarioLimit, want: 10 * time.Minute},
+		{name: "no run timeout", got: TimeoutsConfig{}.RunLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(); got != tt.want {
+				t.Errorf("limit = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	c := validConfig()
+	c.Mocks["coreledger"] = MockConfig{Enabled: true}
+	c.Notifications = []NotificationConfig{{Type: "slack", WebhookURL: "x"}}
+	c.ApplyDefaults()
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "scenario timeout", got: c.Simulation.Timeouts.Scenario, want: "10m"},
+		{name: "isolation reset", got: c.Simulation.Isolation.Reset, want: "scenario"},
+		{name: "background load concurrency", got: c.Simulation.BackgroundLoad.Concurrency, want: 32},
+		{name: "egress port", got: c.Egress.Port, want: 8899},
+		{name: "egress mode", got: c.Egress.Mode, want: "block"},
+		{name: "notification condition", got: c.Notifications[0].On, want: "failure"},
+		{name: "coreledger port", got: c.Mocks["coreledger"].Port, want: 8082},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("default = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMockBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		mock MockConfig
+		want string
+	}{
+		{name: "local", mock: MockConfig{Port: 8080}, want: "http://localhost:8080"},
+		{name: "target", mock: MockConfig{Port: 8080, URL: "https://mocks.example.com"}, want: "https://mocks.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mock.BaseURL(); got != tt.want {
+				t.Errorf("BaseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -68,12 +68,96 @@ func getV1Schema() *Schema {
 				Default:     "30s",
 				Description: "Agent execution timeout",
 			},
+			{
+				Name:        "agent.limits.cpu_time",
+				Type:        "duration",
+				Required:    false,
+				Description: "CPU time the agent may use per scenario before it is killed",
+			},
+			{
+				Name:        "agent.limits.memory_mb",
+				Type:        "integer",
+				Required:    false,
+				Description: "Peak memory (MB) the agent may use per scenario before it is killed",
+				Validation: ValidationRule{
+					MinValue: 64,
+				},
+			},
+			{
+				Name:        "agent.limits.wall_time",
+				Type:        "duration",
+				Required:    false,
+				Description: "Time the agent may run per scenario, across all its requests",
+			},
+			{
+				Name:        "mocks.openai.models_file",
+				Type:        "string",
+				Required:    false,
+				Description: "YAML file registering custom models (fine-tuned or proprietary)",
+			},
+			{
+				Name:        "mocks.openai.synthesis_strategy",
+				Type:        "string",
+				Required:    false,
+				Default:     "auto",
+				Description: "How the OpenAI mock answers prompts no fixture matches",
+				Validation: ValidationRule{
+					AllowedValues: []interface{}{"auto", "echo", "list", "code", "refusal"},
+				},
+			},
+			{
+				Name:        "mocks.openai.jitter",
+				Type:        "string",
+				Required:    false,
+				Default:     "uniform",
+				Description: "Latency jitter distribution of the OpenAI mock (longtail for realistic p95/p99)",
+				Validation: ValidationRule{
+					AllowedValues: []interface{}{"uniform", "gaussian", "exponential", "longtail"},
+				},
+			},
+			{
+				Name:        "mocks.openai.max_streams_per_key",
+				Type:        "integer",
+				Required:    false,
+				Default:     0,
+				Description: "Simultaneous streaming requests allowed per API key; more get a 429 (0 = unlimited)",
+				Validation: ValidationRule{
+					MinValue: 0,
+				},
+			},
+			{
+				Name:        "mocks.stripe.webhook_timestamp_skew",
+				Type:        "string",
+				Required:    false,
+				Description: "Shift of the timestamp webhooks are signed with, e.g. -10m for expired signatures",
+			},
+			{
+				Name:        "mocks.*.image",
+				Type:        "string",
+				Required:    false,
+				Description: "Image the mock runs instead of its default (may end in @sha256:... to pin it)",
+			},
+			{
+				Name:        "mocks.*.image_digest",
+				Type:        "string",
+				Required:    false,
+				Description: "Content digest (sha256:...) the mock's image is pinned to; sentra lab start refuses other images",
+				Validation: ValidationRule{
+					Pattern: `^sha256:[0-9a-f]{64}$`,
+				},
+			},
+			{
+				Name:        "mocks.*.cosign_key",
+				Type:        "string",
+				Required:    false,
+				Description: "Cosign public key the pinned image's signature is verified with before it runs",
+			},
 			{
 				Name:        "simulation.record_full_trace",
 				Type:        "boolean",
 				Required:    false,
 				Default:     true,
-				Description: "Enable full execution recording",
+				Description: "Enable full execution recording, including raw mock HTTP exchanges",
 			},
 			{
 				Name:        "simulation.enable_cost_tracking",
@@ -93,6 +177,246 @@ func getV1Schema() *Schema {
 					MaxValue: 100,
 				},
 			},
+			{
+				Name:        "simulation.background_load.enabled",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "Send synthetic traffic to mocks during tests",
+			},
+			{
+				Name:        "simulation.background_load.rps",
+				Type:        "number",
+				Required:    false,
+				Description: "Background requests per second",
+				Validation: ValidationRule{
+					MinValue: 0,
+					MaxValue: MaxBackgroundLoadRPS,
+				},
+			},
+			{
+				Name:        "simulation.background_load.mocks",
+				Type:        "array",
+				Required:    false,
+				Description: "Mocks to load (default: all enabled mocks)",
+			},
+			{
+				Name:        "simulation.background_load.model",
+				Type:        "string",
+				Required:    false,
+				Default:     "gpt-3.5-turbo",
+				Description: "Model requested from the OpenAI mock",
+			},
+			{
+				Name:        "simulation.background_load.api_key",
+				Type:        "string",
+				Required:    false,
+				Default:     "mock_key_sentra_lab",
+				Description: "OpenAI mock API key for background requests (shares the agent's rate limits by default)",
+			},
+			{
+				Name:        "simulation.background_load.concurrency",
+				Type:        "integer",
+				Required:    false,
+				Default:     32,
+				Description: "Maximum in-flight background requests",
+				Validation: ValidationRule{
+					MinValue: 1,
+				},
+			},
+			{
+				Name:        "simulation.locale.locale",
+				Type:        "string",
+				Required:    false,
+				Default:     "en-US",
+				Description: "Locale mocks format amounts and timestamps in (e.g. de-DE)",
+			},
+			{
+				Name:        "simulation.locale.timezone",
+				Type:        "string",
+				Required:    false,
+				Default:     "UTC",
+				Description: "IANA timezone of mock timestamps (e.g. Europe/Berlin)",
+			},
+			{
+				Name:        "simulation.locale.currency",
+				Type:        "string",
+				Required:    false,
+				Default:     "USD",
+				Description: "ISO 4217 currency of mock amounts (e.g. EUR)",
+			},
+			{
+				Name:        "simulation.context_warning",
+				Type:        "number",
+				Required:    false,
+				Default:     0.8,
+				Description: "Share of a model's context window at which a step is flagged",
+				Validation: ValidationRule{
+					MinValue: 0,
+					MaxValue: 1,
+				},
+			},
+			{
+				Name:        "simulation.isolation.reset",
+				Type:        "string",
+				Required:    false,
+				Default:     "scenario",
+				Description: "When mock state is reset: before each scenario, once per run, or never",
+				Validation: ValidationRule{
+					AllowedValues: []interface{}{"scenario", "run", "none"},
+				},
+			},
+			{
+				Name:        "simulation.isolation.state",
+				Type:        "array",
+				Required:    false,
+				Description: "Mock state to reset (default: all of usage, rate_limits, fixtures, assistants, threads, images, files, fine_tuning, overrides, faults, clock, locale, deadline)",
+			},
+			{
+				Name:        "simulation.skip_preflight",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "Run tests without first sending a canary request to each mock",
+			},
+			{
+				Name:        "simulation.seed",
+				Type:        "integer",
+				Required:    false,
+				Default:     0,
+				Description: "Seed for {{ fake.* }} test data; 0 picks a new one each run",
+			},
+			{
+				Name:        "simulation.timeouts.step",
+				Type:        "duration",
+				Required:    false,
+				Description: "Time a single step may run before the scenario fails with a step timeout",
+			},
+			{
+				Name:        "simulation.timeouts.scenario",
+				Type:        "duration",
+				Required:    false,
+				Default:     "10m",
+				Description: "Time a scenario may run before it fails with a scenario timeout",
+			},
+			{
+				Name:        "simulation.timeouts.run",
+				Type:        "duration",
+				Required:    false,
+				Description: "Time a whole test run may take; scenarios still running fail and the rest are skipped",
+			},
+			{
+				Name:        "simulation.tracing.endpoint",
+				Type:        "string",
+				Required:    false,
+				Description: "OTLP gRPC collector (host:port) the engine and mocks export traces to; tracing is off when empty",
+			},
+			{
+				Name:        "notifications[].type",
+				Type:        "string",
+				Required:    false,
+				Description: "Notification channel type",
+				Validation: ValidationRule{
+					AllowedValues: []interface{}{"slack", "teams"},
+				},
+			},
+			{
+				Name:        "notifications[].webhook_url",
+				Type:        "string",
+				Required:    false,
+				Description: "Incoming webhook URL (${VAR} is expanded from the environment)",
+			},
+			{
+				Name:        "notifications[].on",
+				Type:        "string",
+				Required:    false,
+				Default:     "failure",
+				Description: "When to notify after sentra lab test",
+				Validation: ValidationRule{
+					AllowedValues: []interface{}{"failure", "always"},
+				},
+			},
+			{
+				Name:        "egress.enabled",
+				Type:        "boolean",
+				Required:    false,
+				Default:     false,
+				Description: "Route agent traffic through the egress proxy",
+			},
+			{
+				Name:        "egress.port",
+				Type:        "integer",
+				Required:    false,
+				Default:     8899,
+				Description: "Port of the egress proxy",
+				Validation: ValidationRule{
+					MinValue: 1024,
+					MaxValue: 65535,
+				},
+			},
+			{
+				Name:        "egress.mode",
+				Type:        "string",
+				Required:    false,
+				Default:     "block",
+				Description: "Whether unexpected external calls are blocked or only recorded",
+				Validation: ValidationRule{
+					AllowedValues: []interface{}{"block", "warn"},
+				},
+			},
+			{
+				Name:        "egress.allow",
+				Type:        "array",
+				Required:    false,
+				Description: "Hosts agents may reach directly (*.example.com matches subdomains)",
+			},
+			{
+				Name:        "egress.routes",
+				Type:        "object",
+				Required:    false,
+				Description: "Extra hosts routed to a mock (host: mock name)",
+			},
+			{
+				Name:        "egress.faults.drop_rate",
+				Type:        "number",
+				Required:    false,
+				Default:     0.0,
+				Description: "Share of agent connections the proxy drops without a response",
+				Validation: ValidationRule{
+					MinValue: 0,
+					MaxValue: 1,
+				},
+			},
+			{
+				Name:        "egress.faults.dns_failures",
+				Type:        "array",
+				Required:    false,
+				Description: "Hosts whose lookup fails at the proxy (*.example.com matches subdomains)",
+			},
+			{
+				Name:        "egress.faults.stall_rate",
+				Type:        "number",
+				Required:    false,
+				Default:     0.0,
+				Description: "Share of agent requests the proxy stalls before forwarding",
+				Validation: ValidationRule{
+					MinValue: 0,
+					MaxValue: 1,
+				},
+			},
+			{
+				Name:        "egress.faults.stall",
+				Type:        "duration",
+				Required:    false,
+				Default:     "30s",
+				Description: "How long a stalled request hangs",
+			},
+			{
+				Name:        "egress.faults.hosts",
+				Type:        "array",
+				Required:    false,
+				Description: "Hosts drops and stalls apply to (default: every host)",
+			},
 			{
 				Name:        "storage.recordings_dir",
 				Type:        "string",
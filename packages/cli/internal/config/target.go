@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ApplyTargets points the enabled mocks at externally managed ones, such
+// as a shared environment from `sentra lab deploy k8s`, instead of the
+// local containers. Each target is either "<mock>=<url>", the base URL of
+// one mock, or a bare URL whose host serves every other enabled mock on
+// its lab.yaml port. A bare URL may only carry a port when it serves a
+// single mock, since each mock needs its own.
+func (c *Config) ApplyTargets(targets []string) error {
+	enabled := make([]string, 0, len(c.Mocks))
+	for name, mock := range c.Mocks {
+		if mock.Enabled {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	if len(enabled) == 0 {
+		return fmt.Errorf("--target: no mocks are enabled in lab.yaml")
+	}
+
+	var host *url.URL
+	explicit := make(map[string]string)
+	for _, target := range targets {
+		// An "=" in a bare URL (e.g. in its query) does not name a mock
+		name, raw, named := strings.Cut(target, "=")
+		if !named || strings.ContainsAny(name, ":/") {
+			name, raw, named = "", target, false
+		}
+
+		parsed, err := parseTargetURL(raw)
+		if err != nil {
+			return fmt.Errorf("--target %s: %w", target, err)
+		}
+
+		if named {
+			mock, ok := c.Mocks[name]
+			if !ok || !mock.Enabled {
+				return fmt.Errorf("--target %s: the %s mock is not enabled in lab.yaml", target, name)
+			}
+			explicit[name] = strings.TrimSuffix(parsed.String(), "/")
+			continue
+		}
+
+		if host != nil {
+			return fmt.Errorf("--target %s: only one target may omit the mock name", target)
+		}
+		host = parsed
+	}
+
+	onHost := 0
+	for _, name := range enabled {
+		if explicit[name] == "" {
+			onHost++
+		}
+	}
+
+	for _, name := range enabled {
+		mock := c.Mocks[name]
+		switch {
+		case explicit[name] != "":
+			mock.URL = explicit[name]
+		case host != nil:
+			if host.Port() != "" && onHost > 1 {
+				return fmt.Errorf("--target %s has a port, but %d mocks would share it; give each its URL with --target <mock>=<url> or drop the port to use lab.yaml's", host, onHost)
+			}
+			base := *host
+			if base.Port() == "" {
+				base.Host = net.JoinHostPort(base.Hostname(), strconv.Itoa(mock.Port))
+			}
+			mock.URL = strings.TrimSuffix(base.String(), "/")
+		default:
+			return fmt.Errorf("--target: no URL for the %s mock (add --target %s=<url>)", name, name)
+		}
+		c.Mocks[name] = mock
+	}
+	return nil
+}
+
+// parseTargetURL parses an http(s) base URL.
+func parseTargetURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("expected an http(s) URL")
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return nil, fmt.Errorf("a base URL cannot have a query or fragment")
+	}
+	return parsed, nil
+}
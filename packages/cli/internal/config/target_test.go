@@ -0,0 +1,97 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "one host for every mock",
+			targets: []string{"https://mocks.example.com"},
+			want:    map[string]string{"openai": "https://mocks.example.com:8080", "stripe": "https://mocks.example.com:8081"},
+		},
+		{
+			name:    "named targets",
+			targets: []string{"openai=http://openai.lab:9000/", "stripe=http://stripe.lab"},
+			want:    map[string]string{"openai": "http://openai.lab:9000", "stripe": "http://stripe.lab"},
+		},
+		{
+			name:    "named target and a host with a port for the rest",
+			targets: []string{"openai=http://openai.lab", "http://stripe.lab:9001"},
+			want:    map[string]string{"openai": "http://openai.lab", "stripe": "http://stripe.lab:9001"},
+		},
+		{
+			name:    "host with a port shared by several mocks",
+			targets: []string{"http://mocks.lab:9000"},
+			wantErr: "2 mocks would share it",
+		},
+		{
+			name:    "mock without a URL",
+			targets: []string{"openai=http://openai.lab"},
+			wantErr: "no URL for the stripe mock",
+		},
+		{
+			name:    "two hosts",
+			targets: []string{"http://a.lab", "http://b.lab"},
+			wantErr: "only one target may omit the mock name",
+		},
+		{
+			name:    "disabled mock",
+			targets: []string{"coreledger=http://ledger.lab", "http://mocks.lab"},
+			wantErr: "the coreledger mock is not enabled",
+		},
+		{
+			name:    "not an http URL",
+			targets: []string{"grpc://mocks.lab"},
+			wantErr: "expected an http(s) URL",
+		},
+		{
+			name:    "missing host",
+			targets: []string{"http://"},
+			wantErr: "missing host",
+		},
+		{
+			name:    "query",
+			targets: []string{"http://mocks.lab?env=staging"},
+			wantErr: "cannot have a query or fragment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			err := c.ApplyTargets(tt.targets)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyTargets() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyTargets() error = %v", err)
+			}
+
+			for name, want := range tt.want {
+				if got := c.Mocks[name].BaseURL(); got != want {
+					t.Errorf("mocks.%s.BaseURL() = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyTargetsWithoutMocks(t *testing.T) {
+	c := validConfig()
+	c.Mocks = map[string]MockConfig{"openai": {Port: 8080}}
+
+	if err := c.ApplyTargets([]string{"http://mocks.lab"}); err == nil || !strings.Contains(err.Error(), "no mocks are enabled") {
+		t.Errorf("ApplyTargets() error = %v, want no mocks enabled", err)
+	}
+}
@@ -0,0 +1,222 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotRunning is returned by Connect when no daemon serves the project
+var ErrNotRunning = errors.New("daemon is not running")
+
+// connectTimeout bounds the check for a running daemon, which every command
+// that can use it pays when there is none
+const connectTimeout = 200 * time.Millisecond
+
+// Client talks to the project's daemon.
+type Client struct {
+	Info Info
+	http *http.Client
+}
+
+// Connect returns a client for the daemon serving the project in the
+// current directory, or ErrNotRunning.
+func Connect() (*Client, error) {
+	content, err := os.ReadFile(infoPath(Dir))
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	var info Info
+	if err := json.Unmarshal(content, &info); err != nil {
+		return nil, ErrNotRunning
+	}
+
+	client := &Client{
+		Info: info,
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", info.Socket)
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	if err := client.get(ctx, "/v1/info", &client.Info); err != nil {
+		return nil, ErrNotRunning
+	}
+	return client, nil
+}
+
+// Serves reports whether the daemon serves the project configured at
+// configPath.
+func (c *Client) Serves(configPath string) bool {
+	served, err := os.Stat(c.Info.Config)
+	if err != nil {
+		return false
+	}
+	own, err := os.Stat(configPath)
+	return err == nil && os.SameFile(served, own)
+}
+
+// Status returns the services' status.
+func (c *Client) Status(ctx context.Context) ([]ServiceStatus, error) {
+	var services []ServiceStatus
+	if err := c.get(ctx, "/v1/status", &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// Logs copies service logs ("all" for every service) to w.
+func (c *Client) Logs(ctx context.Context, service string, tail int, follow bool, w io.Writer) error {
+	query := url.Values{
+		"service": {service},
+		"tail":    {strconv.Itoa(tail)},
+		"follow":  {strconv.FormatBool(follow)},
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, "/v1/logs?"+query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown asks the daemon to stop.
+func (c *Client) Shutdown(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodPost, "/v1/shutdown")
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *Client) do(ctx context.Context, method, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, "http://daemon"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&body) == nil && body.Error != "" {
+			return nil, errors.New(body.Error)
+		}
+		return nil, fmt.Errorf("daemon returned %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// StartBackground runs `sentra lab daemon serve` as a background process
+// logging to Dir/daemon.log, and waits until it accepts connections.
+func StartBackground(configPath string) (*Client, error) {
+	if client, err := Connect(); err == nil {
+		return client, nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the sentra executable: %w", err)
+	}
+
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		return nil, err
+	}
+	logPath := filepath.Join(Dir, "daemon.log")
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, "lab", "daemon", "serve", "--config", configPath, "--log-format", "text")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.After(15 * time.Second)
+	for {
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("daemon exited during startup; see %s", logPath)
+		case <-deadline:
+			cmd.Process.Kill()
+			return nil, fmt.Errorf("daemon did not start within 15s; see %s", logPath)
+		case <-time.After(100 * time.Millisecond):
+			if client, err := Connect(); err == nil {
+				return client, nil
+			}
+		}
+	}
+}
+
+// Stop stops the project's daemon, killing it if it does not answer.
+func Stop(ctx context.Context) error {
+	if client, err := Connect(); err == nil {
+		return client.Shutdown(ctx)
+	}
+
+	content, err := os.ReadFile(infoPath(Dir))
+	if os.IsNotExist(err) {
+		return ErrNotRunning
+	}
+	if err != nil {
+		return err
+	}
+
+	var info Info
+	if err := json.Unmarshal(content, &info); err == nil && info.PID > 0 {
+		if process, err := os.FindProcess(info.PID); err == nil {
+			// The process may already be gone
+			process.Kill()
+		}
+	}
+	if info.Socket != "" && strings.HasPrefix(info.Socket, os.TempDir()) {
+		os.Remove(info.Socket)
+	}
+	return os.Remove(infoPath(Dir))
+}
@@ -0,0 +1,413 @@
+// Package daemon keeps Docker and config state warm in a long-running
+// process (`sentra lab daemon`) and serves it over a local unix socket, so
+// commands like status and logs skip connecting to Docker and loading the
+// config on every run. Commands use the daemon when it is running and fall
+// back to doing the work themselves when it is not.
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/docker"
+	"github.com/sentra-lab/cli/internal/utils"
+)
+
+// Dir holds the daemon's info and log files
+const Dir = ".sentra-lab/daemon"
+
+// Info describes a running daemon; it is written to Dir/daemon.json.
+type Info struct {
+	PID       int       `json:"pid"`
+	Socket    string    `json:"socket"`
+	Config    string    `json:"config"`
+	Version   string    `json:"version"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ServiceStatus is a service's state as reported by Docker.
+type ServiceStatus struct {
+	Name   string        `json:"name"`
+	URL    string        `json:"url"`
+	Status string        `json:"status"`
+	Uptime time.Duration `json:"uptime"`
+	Memory uint64        `json:"memory"`
+}
+
+// Docker is the part of the Docker client the daemon uses.
+type Docker interface {
+	ListContainers(ctx context.Context) ([]*docker.ContainerStatus, error)
+	GetContainerStats(ctx context.Context, containerID string) (*docker.ContainerStats, error)
+	GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error)
+	StreamContainerLogs(ctx context.Context, containerID string, output io.Writer) error
+}
+
+// SocketPath returns the socket for the project in dir. It lives in the
+// temp directory because unix socket paths are limited to about 100 bytes.
+func SocketPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("sentra-lab-%x.sock", sum[:6])), nil
+}
+
+func infoPath(dir string) string {
+	return filepath.Join(dir, "daemon.json")
+}
+
+// Server is the daemon.
+type Server struct {
+	logger     *utils.Logger
+	configPath string
+	version    string
+
+	mu        sync.Mutex
+	loadedAt  time.Time
+	config    *config.Config
+	docker    Docker
+	startedAt time.Time
+	shutdown  context.CancelFunc
+}
+
+// NewServer creates a daemon for the project configured at configPath.
+func NewServer(logger *utils.Logger, configPath, version string) *Server {
+	return &Server{
+		logger:     logger,
+		configPath: configPath,
+		version:    version,
+	}
+}
+
+// Serve loads the config, connects to Docker and serves requests on the
+// project's socket until ctx is done or a client asks it to stop.
+func (s *Server) Serve(ctx context.Context) error {
+	if _, err := s.state(); err != nil {
+		return err
+	}
+
+	if s.docker == nil {
+		client, err := docker.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect to Docker: %w", err)
+		}
+		s.docker = client
+		defer client.Close()
+	}
+
+	socket, err := SocketPath(".")
+	if err != nil {
+		return err
+	}
+	if running, err := Connect(); err == nil {
+		return fmt.Errorf("a daemon is already running for this project (pid %d)", running.Info.PID)
+	}
+	// A socket left by a daemon that was killed
+	os.Remove(socket)
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	defer os.Remove(socket)
+	if err := os.Chmod(socket, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.shutdown = cancel
+	s.startedAt = time.Now()
+
+	info := Info{
+		PID:       os.Getpid(),
+		Socket:    socket,
+		Config:    s.configPath,
+		Version:   s.version,
+		StartedAt: s.startedAt,
+	}
+	if err := writeInfo(Dir, info); err != nil {
+		listener.Close()
+		return err
+	}
+	defer os.Remove(infoPath(Dir))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/info", s.handleInfo(info))
+	mux.HandleFunc("/v1/status", s.handleStatus)
+	mux.HandleFunc("/v1/logs", s.handleLogs)
+	mux.HandleFunc("/v1/shutdown", s.handleShutdown)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	s.logger.Info("Daemon listening", "socket", socket, "pid", info.PID)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	s.logger.Info("Daemon stopped")
+	return nil
+}
+
+// state returns the config, reloading it when lab.yaml changed since it
+// was loaded.
+func (s *Server) state() (*config.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, err := os.Stat(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if s.config != nil && !stat.ModTime().After(s.loadedAt) {
+		return s.config, nil
+	}
+
+	loader, err := config.NewLoader(s.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if s.config != nil {
+		s.logger.Info("Config changed, reloaded", "config", s.configPath)
+	}
+	s.config = cfg
+	s.loadedAt = stat.ModTime()
+	return cfg, nil
+}
+
+func (s *Server) handleInfo(info Info) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, info)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	cfg, err := s.state()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	services, err := Services(r.Context(), s.docker, cfg)
+	if err != nil {
+		writeError(w, fmt.Errorf("failed to get status: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, services)
+}
+
+// Services returns the status of the simulation engine and every enabled
+// mock, in that order. A service without a container is "stopped"; a
+// running one is "healthy" unless its Docker health check says otherwise.
+func Services(ctx context.Context, client Docker, cfg *config.Config) ([]ServiceStatus, error) {
+	containers, err := client.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []ServiceStatus{{Name: "simulation-engine", URL: cfg.GetEngineAddress()}}
+	var mocks []string
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			mocks = append(mocks, name)
+		}
+	}
+	sort.Strings(mocks)
+	for _, name := range mocks {
+		services = append(services, ServiceStatus{Name: "mock-" + name, URL: cfg.Mocks[name].BaseURL()})
+	}
+
+	for i := range services {
+		service := &services[i]
+		container := projectContainer(containers, service.Name)
+		switch {
+		case container == nil:
+			service.Status = "stopped"
+			continue
+		case !container.Running:
+			service.Status = container.State
+			continue
+		case container.Health == "" || container.Health == "healthy":
+			service.Status = "healthy"
+		default:
+			service.Status = container.Health
+		}
+
+		service.Uptime = container.Uptime
+		// Memory is best effort; status is still useful without it
+		if stats, err := client.GetContainerStats(ctx, container.ID); err == nil {
+			service.Memory = stats.MemoryUsage
+		}
+	}
+	return services, nil
+}
+
+// Down returns the services that are not up, as "name (status)". A service
+// is up once its container runs, even while its health check is starting.
+func Down(services []ServiceStatus) []string {
+	var down []string
+	for _, service := range services {
+		if service.Status != "healthy" && service.Status != "starting" {
+			down = append(down, fmt.Sprintf("%s (%s)", service.Name, service.Status))
+		}
+	}
+	return down
+}
+
+// projectContainer returns the container running service, preferring a
+// running one when a stopped container was left behind.
+func projectContainer(containers []*docker.ContainerStatus, service string) *docker.ContainerStatus {
+	var found *docker.ContainerStatus
+	for _, container := range containers {
+		if !isProjectContainer(container, service) {
+			continue
+		}
+		if found == nil || container.Running && !found.Running {
+			found = container
+		}
+	}
+	return found
+}
+
+// isProjectContainer reports whether container runs service ("all" for
+// any). Containers are named sentra-lab-<project>-<service>.
+func isProjectContainer(container *docker.ContainerStatus, service string) bool {
+	name := strings.TrimPrefix(container.Name, "/")
+	if !strings.HasPrefix(name, "sentra-lab-") {
+		return false
+	}
+	return service == "all" || strings.HasSuffix(name, "-"+service)
+}
+
+// handleLogs writes the logs of the project's containers (all, or those of
+// ?service=), following them when ?follow=true.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	service := r.URL.Query().Get("service")
+	if service == "" {
+		service = "all"
+	}
+	tail, err := strconv.Atoi(r.URL.Query().Get("tail"))
+	if err != nil || tail <= 0 {
+		tail = 100
+	}
+	follow := r.URL.Query().Get("follow") == "true"
+
+	containers, err := s.containers(r.Context(), service)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(containers) == 0 {
+		writeError(w, fmt.Errorf("no running containers for service %q", service))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if follow {
+		if len(containers) > 1 {
+			writeError(w, fmt.Errorf("follow one service at a time"))
+			return
+		}
+		s.docker.StreamContainerLogs(r.Context(), containers[0].ID, flushWriter{w})
+		return
+	}
+
+	for _, container := range containers {
+		logs, err := s.docker.GetContainerLogs(r.Context(), container.ID, tail)
+		if err != nil {
+			fmt.Fprintf(w, "==> %s: %v\n", container.Name, err)
+			continue
+		}
+		if len(containers) > 1 {
+			fmt.Fprintf(w, "==> %s <==\n", strings.TrimPrefix(container.Name, "/"))
+		}
+		io.WriteString(w, logs)
+	}
+}
+
+// containers returns the project's running containers for service ("all"
+// for every one).
+func (s *Server) containers(ctx context.Context, service string) ([]*docker.ContainerStatus, error) {
+	all, err := s.docker.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*docker.ContainerStatus
+	for _, container := range all {
+		if container.Running && isProjectContainer(container, service) {
+			matched = append(matched, container)
+		}
+	}
+	return matched, nil
+}
+
+func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "stopping"})
+	s.shutdown()
+}
+
+// flushWriter flushes after every write, so followed logs arrive as they
+// are produced.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
+func writeInfo(dir string, info Info) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(infoPath(dir), content, 0644)
+}
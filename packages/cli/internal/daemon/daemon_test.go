@@ -0,0 +1,420 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/docker"
+	"github.com/sentra-lab/cli/internal/utils"
+)
+
+const labConfig = `name: support-agent
+version: "1.0"
+agent:
+  runtime: python
+  entry_point: agent.py
+mocks:
+  openai:
+    enabled: true
+  stripe:
+    enabled: %t
+    port: 9000
+  coreledger:
+    enabled: false
+`
+
+// fakeDocker serves canned containers, stats and logs.
+type fakeDocker struct {
+	containers []*docker.ContainerStatus
+	memory     map[string]uint64
+	logs       map[string]string
+	err        error
+}
+
+func (f *fakeDocker) ListContainers(ctx context.Context) ([]*docker.ContainerStatus, error) {
+	return f.containers, f.err
+}
+
+func (f *fakeDocker) GetContainerStats(ctx context.Context, containerID string) (*docker.ContainerStats, error) {
+	memory, ok := f.memory[containerID]
+	if !ok {
+		return nil, errors.New("no stats")
+	}
+	return &docker.ContainerStats{MemoryUsage: memory}, nil
+}
+
+func (f *fakeDocker) GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error) {
+	logs := strings.SplitAfter(strings.TrimSuffix(f.logs[containerID], "\n"), "\n")
+	if len(logs) > tail {
+		logs = logs[len(logs)-tail:]
+	}
+	return strings.Join(logs, "") + "\n", nil
+}
+
+func (f *fakeDocker) StreamContainerLogs(ctx context.Context, containerID string, output io.Writer) error {
+	_, err := io.WriteString(output, f.logs[containerID])
+	return err
+}
+
+func newFakeDocker() *fakeDocker {
+	return &fakeDocker{
+		containers: []*docker.ContainerStatus{
+			{ID: "engine", Name: "/sentra-lab-demo-simulation-engine", State: "running", Running: true, Uptime: time.Minute},
+			{ID: "openai-old", Name: "/sentra-lab-demo-mock-openai", State: "exited"},
+			{ID: "openai", Name: "/sentra-lab-demo-mock-openai", State: "running", Running: true, Health: "starting", Uptime: time.Second},
+			{ID: "postgres", Name: "/postgres", State: "running", Running: true},
+		},
+		memory: map[string]uint64{"engine": 64 << 20},
+		logs: map[string]string{
+			"engine": "engine started\nlistening on :50051\n",
+			"openai": "mock ready\n",
+		},
+	}
+}
+
+// inDir runs the test from dir, where the daemon keeps its files.
+func inDir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func writeConfig(t *testing.T, path string, stripe bool) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(labConfig, stripe)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadConfig(t *testing.T, stripe bool) *config.Config {
+	t.Helper()
+
+	path := t.TempDir() + "/lab.yaml"
+	writeConfig(t, path, stripe)
+	loader, err := config.NewLoader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestServices(t *testing.T) {
+	tests := []struct {
+		name   string
+		stripe bool
+		docker *fakeDocker
+		want   []ServiceStatus
+	}{
+		{
+			name:   "running and missing containers",
+			stripe: true,
+			docker: newFakeDocker(),
+			want: []ServiceStatus{
+				{Name: "simulation-engine", URL: "localhost:50051", Status: "healthy", Uptime: time.Minute, Memory: 64 << 20},
+				{Name: "mock-openai", URL: "http://localhost:8080", Status: "starting", Uptime: time.Second},
+				{Name: "mock-stripe", URL: "http://localhost:9000", Status: "stopped"},
+			},
+		},
+		{
+			name:   "stopped container",
+			docker: &fakeDocker{containers: []*docker.ContainerStatus{{ID: "engine", Name: "/sentra-lab-demo-simulation-engine", State: "exited"}}},
+			want: []ServiceStatus{
+				{Name: "simulation-engine", URL: "localhost:50051", Status: "exited"},
+				{Name: "mock-openai", URL: "http://localhost:8080", Status: "stopped"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Services(context.Background(), tt.docker, loadConfig(t, tt.stripe))
+			if err != nil {
+				t.Fatalf("Services() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Services() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServicesDockerError(t *testing.T) {
+	client := &fakeDocker{err: errors.New("docker is not running")}
+	if _, err := Services(context.Background(), client, loadConfig(t, false)); err == nil {
+		t.Error("Services() error = nil, want the Docker error")
+	}
+}
+
+// serve runs a daemon for the project in a temp directory and returns a
+// client for it; the daemon is shut down when the test ends.
+func serve(t *testing.T, client Docker) *Client {
+	t.Helper()
+
+	inDir(t, t.TempDir())
+	writeConfig(t, "lab.yaml", false)
+
+	server := NewServer(utils.NewLogger("test", "error"), "lab.yaml", "1.2.3")
+	server.docker = client
+
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(context.Background()) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		daemon, err := Connect()
+		if err == nil {
+			t.Cleanup(func() {
+				daemon.Shutdown(context.Background())
+				<-done
+			})
+			return daemon
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("Serve() error = %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("daemon did not start: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	daemon := serve(t, newFakeDocker())
+
+	info := daemon.Info
+	if info.PID != os.Getpid() || info.Config != "lab.yaml" || info.Version != "1.2.3" || info.StartedAt.IsZero() {
+		t.Errorf("Info = %+v, want this process, lab.yaml and version 1.2.3", info)
+	}
+	if want, _ := SocketPath("."); info.Socket != want {
+		t.Errorf("Info.Socket = %s, want %s", info.Socket, want)
+	}
+}
+
+func TestServerStatus(t *testing.T) {
+	daemon := serve(t, newFakeDocker())
+
+	services, err := daemon.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	var names []string
+	for _, service := range services {
+		names = append(names, service.Name+"="+service.Status)
+	}
+	if got, want := strings.Join(names, " "), "simulation-engine=healthy mock-openai=starting"; got != want {
+		t.Errorf("Status() = %s, want %s", got, want)
+	}
+
+	// An edited lab.yaml is picked up without restarting the daemon
+	writeConfig(t, "lab.yaml", true)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes("lab.yaml", later, later); err != nil {
+		t.Fatal(err)
+	}
+	services, err = daemon.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if len(services) != 3 || services[2].Name != "mock-stripe" {
+		t.Errorf("Status() after the edit = %+v, want mock-stripe too", services)
+	}
+}
+
+func TestServerStatusError(t *testing.T) {
+	client := newFakeDocker()
+	client.err = errors.New("docker is not running")
+	daemon := serve(t, client)
+
+	if _, err := daemon.Status(context.Background()); err == nil || !strings.Contains(err.Error(), "docker is not running") {
+		t.Errorf("Status() error = %v, want the Docker error", err)
+	}
+}
+
+func TestServerLogs(t *testing.T) {
+	daemon := serve(t, newFakeDocker())
+
+	tests := []struct {
+		name    string
+		service string
+		tail    int
+		follow  bool
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "one service",
+			service: "simulation-engine",
+			tail:    1,
+			want:    "listening on :50051\n",
+		},
+		{
+			name:    "all services",
+			service: "all",
+			tail:    10,
+			want:    "==> sentra-lab-demo-simulation-engine <==\nengine started\nlistening on :50051\n==> sentra-lab-demo-mock-openai <==\nmock ready\n",
+		},
+		{
+			name:    "follow",
+			service: "mock-openai",
+			follow:  true,
+			want:    "mock ready\n",
+		},
+		{
+			name:    "follow all",
+			service: "all",
+			follow:  true,
+			wantErr: "follow one service at a time",
+		},
+		{
+			name:    "no container",
+			service: "mock-stripe",
+			wantErr: `no running containers for service "mock-stripe"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := daemon.Logs(context.Background(), tt.service, tt.tail, tt.follow, &out)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Logs() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Logs() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Logs() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	inDir(t, t.TempDir())
+	writeConfig(t, "lab.yaml", false)
+
+	server := NewServer(utils.NewLogger("test", "error"), "lab.yaml", "1.2.3")
+	server.docker = newFakeDocker()
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(context.Background()) }()
+
+	var daemon *Client
+	for deadline := time.Now().Add(5 * time.Second); daemon == nil; {
+		daemon, _ = Connect()
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := daemon.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after Shutdown()")
+	}
+
+	if _, err := os.Stat(infoPath(Dir)); !os.IsNotExist(err) {
+		t.Errorf("daemon.json left behind: %v", err)
+	}
+	if _, err := Connect(); err != ErrNotRunning {
+		t.Errorf("Connect() after shutdown error = %v, want ErrNotRunning", err)
+	}
+	if err := Stop(context.Background()); err != ErrNotRunning {
+		t.Errorf("Stop() error = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestDown(t *testing.T) {
+	services := []ServiceStatus{
+		{Name: "simulation-engine", Status: "healthy"},
+		{Name: "mock-openai", Status: "starting"},
+		{Name: "mock-stripe", Status: "stopped"},
+		{Name: "mock-coreledger", Status: "unhealthy"},
+	}
+
+	want := []string{"mock-stripe (stopped)", "mock-coreledger (unhealthy)"}
+	if got := Down(services); !reflect.DeepEqual(got, want) {
+		t.Errorf("Down() = %v, want %v", got, want)
+	}
+	if got := Down(services[:2]); got != nil {
+		t.Errorf("Down() = %v, want none when every service is up", got)
+	}
+}
+
+func TestClientServes(t *testing.T) {
+	inDir(t, t.TempDir())
+	writeConfig(t, "lab.yaml", false)
+	if err := os.Mkdir("other", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, "other/lab.yaml", false)
+
+	client := &Client{Info: Info{Config: "lab.yaml"}}
+	tests := []struct {
+		configPath string
+		want       bool
+	}{
+		{configPath: "lab.yaml", want: true},
+		{configPath: "./other/../lab.yaml", want: true},
+		{configPath: "other/lab.yaml", want: false},
+		{configPath: "missing.yaml", want: false},
+	}
+	for _, tt := range tests {
+		if got := client.Serves(tt.configPath); got != tt.want {
+			t.Errorf("Serves(%s) = %v, want %v", tt.configPath, got, tt.want)
+		}
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	dir := t.TempDir()
+	a, err := SocketPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := SocketPath(dir)
+	other, _ := SocketPath(t.TempDir())
+
+	if a != again {
+		t.Errorf("SocketPath() = %s, then %s for the same project", a, again)
+	}
+	if a == other {
+		t.Errorf("SocketPath() = %s for two projects, want one socket each", a)
+	}
+	if len(a) > 100 {
+		t.Errorf("SocketPath() = %s, longer than unix sockets allow", a)
+	}
+}
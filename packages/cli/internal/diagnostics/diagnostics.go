@@ -0,0 +1,194 @@
+// Package diagnostics writes support bundles: a zip with the crash reason and
+// stack trace, recent log lines, lab.yaml with secrets stripped, and the
+// versions of the CLI, Go, the OS and Docker. Bundles are written on a panic
+// or fatal error and by `sentra lab support bundle`.
+package diagnostics
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is where bundles are written
+const Dir = ".sentra-lab/diagnostics"
+
+// IssuesURL is where bug reports are filed
+const IssuesURL = "https://github.com/sentra-lab/cli/issues/new"
+
+// Redacted replaces secret values
+const Redacted = "[REDACTED]"
+
+// Report is what goes into a bundle.
+type Report struct {
+	// Reason is the panic value or fatal error; empty for on-demand bundles
+	Reason string
+
+	// Stack is the goroutine stack trace
+	Stack []byte
+
+	// Logs are recent log lines
+	Logs []string
+
+	// ConfigPath is the project config to include (redacted)
+	ConfigPath string
+
+	// Version describes the CLI build
+	Version string
+}
+
+// Write writes report as a zip bundle under dir and returns its path.
+func Write(dir string, report Report) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("sentra-lab-diagnostics-%s.zip", time.Now().Format("20060102-150405")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create diagnostics bundle: %w", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	type entry struct {
+		name    string
+		content string
+	}
+	entries := []entry{
+		{"versions.txt", versions(report.Version)},
+		{"logs.txt", RedactText(strings.Join(report.Logs, "\n")) + "\n"},
+		{"config.yaml", config(report.ConfigPath)},
+	}
+	if report.Reason != "" || len(report.Stack) > 0 {
+		entries = append(entries, entry{"crash.txt", RedactText(report.Reason + "\n\n" + string(report.Stack))})
+	}
+
+	for _, entry := range entries {
+		writer, err := archive.Create(entry.name)
+		if err != nil {
+			return "", err
+		}
+		if _, err := writer.Write([]byte(entry.content)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", fmt.Errorf("failed to write diagnostics bundle: %w", err)
+	}
+	return path, nil
+}
+
+func versions(version string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sentra:  %s\n", version)
+	fmt.Fprintf(&b, "go:      %s\n", runtime.Version())
+	fmt.Fprintf(&b, "os:      %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "docker:  %s\n", dockerVersion())
+	fmt.Fprintf(&b, "time:    %s\n", time.Now().UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// dockerVersion asks the Docker daemon for its version, briefly, since it
+// may be the thing that is broken.
+func dockerVersion() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "docker", "version", "--format", "client {{.Client.Version}}, server {{.Server.Version}}").Output()
+	if err != nil {
+		return "unavailable"
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func config(path string) string {
+	if path == "" {
+		return "# no config\n"
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("# %s: %v\n", path, err)
+	}
+
+	redacted, err := RedactConfig(content)
+	if err != nil {
+		// Unparseable, so fall back to line-based redaction
+		return fmt.Sprintf("# %s (invalid YAML: %v)\n%s", path, err, RedactText(string(content)))
+	}
+	return fmt.Sprintf("# %s (secrets redacted)\n%s", path, redacted)
+}
+
+// secretKey matches config keys whose values are secrets
+var secretKey = regexp.MustCompile(`(?i)(^|_)(api_?key|key|token|secret|password|passwd|credentials?|auth|webhook_url|dsn)$`)
+
+// envReference matches values that only reference the environment, which
+// are safe to keep
+var envReference = regexp.MustCompile(`^\$\{?[A-Za-z_][A-Za-z0-9_]*\}?$`)
+
+// RedactConfig replaces the values of secret-looking keys in a YAML
+// document (except ${VAR} references) and strips credentials from URLs.
+func RedactConfig(content []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	redactNode(&root, false)
+
+	return yaml.Marshal(&root)
+}
+
+func redactNode(node *yaml.Node, secret bool) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			redactNode(child, secret)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			redactNode(node.Content[i+1], secret || secretKey.MatchString(node.Content[i].Value))
+		}
+	case yaml.ScalarNode:
+		if secret && node.Value != "" && !envReference.MatchString(node.Value) {
+			node.Value = Redacted
+			node.Tag = "!!str"
+			node.Style = 0
+			return
+		}
+		node.Value = RedactText(node.Value)
+	}
+}
+
+// secretPatterns match secrets in free text: provider keys, bearer tokens,
+// key=value assignments and URL credentials
+var secretPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\b((sk|pk|rk)_(live|test)|whsec)_[A-Za-z0-9]+`), Redacted},
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{16,}`), Redacted},
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=\-]+`), "$1 " + Redacted},
+	{regexp.MustCompile(`(?i)\b([a-z_]*(?:api[_-]?key|token|secret|password)["']?\s*[:=]\s*["']?)[^\s"',}]+`), "${1}" + Redacted},
+	{regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`), "${1}" + Redacted + "@"},
+}
+
+// RedactText replaces secrets found in free text such as log lines.
+func RedactText(text string) string {
+	for _, secret := range secretPatterns {
+		text = secret.pattern.ReplaceAllString(text, secret.replacement)
+	}
+	return text
+}
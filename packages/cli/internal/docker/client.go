@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"time"
@@ -18,7 +19,12 @@ type Client struct {
 }
 
 func NewClient() (*Client, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host := DetectHost(); host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
@@ -75,14 +81,26 @@ func (c *Client) CreateContainer(ctx context.Context, config *ContainerConfig) (
 		ExposedPorts: exposedPorts,
 	}
 
+	binds, err := absoluteBinds(config.Volumes)
+	if err != nil {
+		return "", fmt.Errorf("invalid volume for %s: %w", config.Name, err)
+	}
+
 	hostConfig := &container.HostConfig{
 		PortBindings: portBindings,
-		Binds:        config.Volumes,
+		Binds:        binds,
 		RestartPolicy: container.RestartPolicy{
 			Name: "unless-stopped",
 		},
 	}
 
+	if config.RestartPolicy != "" {
+		hostConfig.RestartPolicy = container.RestartPolicy{
+			Name:              config.RestartPolicy,
+			MaximumRetryCount: config.MaxRestarts,
+		}
+	}
+
 	if config.Memory > 0 {
 		hostConfig.Memory = config.Memory
 	}
@@ -102,7 +120,7 @@ func (c *Client) CreateContainer(ctx context.Context, config *ContainerConfig) (
 }
 
 func (c *Client) StartContainer(ctx context.Context, containerID string) error {
-	if err := c.cli.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+	if err := c.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{}); err != nil {
 		return fmt.Errorf("failed to start container %s: %w", containerID, err)
 	}
 	return nil
@@ -117,14 +135,14 @@ func (c *Client) StopContainer(ctx context.Context, containerID string, timeout
 }
 
 func (c *Client) RemoveContainer(ctx context.Context, containerID string) error {
-	if err := c.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true}); err != nil {
+	if err := c.cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true}); err != nil {
 		return fmt.Errorf("failed to remove container %s: %w", containerID, err)
 	}
 	return nil
 }
 
 func (c *Client) GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error) {
-	options := container.LogsOptions{
+	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Tail:       fmt.Sprintf("%d", tail),
@@ -145,7 +163,7 @@ func (c *Client) GetContainerLogs(ctx context.Context, containerID string, tail
 }
 
 func (c *Client) StreamContainerLogs(ctx context.Context, containerID string, output io.Writer) error {
-	options := container.LogsOptions{
+	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     true,
@@ -185,11 +203,18 @@ func (c *Client) GetContainerStatus(ctx context.Context, containerID string) (*C
 		status.Health = inspect.State.Health.Status
 	}
 
+	status.OOMKilled = inspect.State.OOMKilled
+	status.ExitCode = inspect.State.ExitCode
+	status.RestartCount = inspect.RestartCount
+	if inspect.State.FinishedAt != "" {
+		status.FinishedAt, _ = time.Parse(time.RFC3339Nano, inspect.State.FinishedAt)
+	}
+
 	return status, nil
 }
 
 func (c *Client) ListContainers(ctx context.Context) ([]*ContainerStatus, error) {
-	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: true})
+	containers, err := c.cli.ContainerList(ctx, types.ContainerListOptions{All: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
@@ -222,7 +247,7 @@ func (c *Client) GetContainerStats(ctx context.Context, containerID string) (*Co
 	defer stats.Body.Close()
 
 	var v types.StatsJSON
-	if err := io.ReadAll(stats.Body); err != nil {
+	if err := json.NewDecoder(stats.Body).Decode(&v); err != nil {
 		return nil, err
 	}
 
@@ -248,6 +273,10 @@ type ContainerConfig struct {
 	Volumes     []string
 	Memory      int64
 	CPUs        float64
+	// RestartPolicy is the Docker restart policy (default unless-stopped);
+	// MaxRestarts limits on-failure restarts (0: unlimited)
+	RestartPolicy string
+	MaxRestarts   int
 }
 
 type ContainerStatus struct {
@@ -258,6 +287,14 @@ type ContainerStatus struct {
 	Health    string
 	StartedAt time.Time
 	Uptime    time.Duration
+	// OOMKilled is set when the container was last stopped for going over
+	// its memory limit; RestartCount is how often Docker has restarted it,
+	// and ExitCode and FinishedAt are its last exit (inspected containers
+	// only)
+	OOMKilled    bool
+	RestartCount int
+	ExitCode     int
+	FinishedAt   time.Time
 }
 
 type ContainerStats struct {
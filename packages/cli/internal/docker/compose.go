@@ -162,6 +162,9 @@ func (cm *ComposeManager) startService(ctx context.Context, service ServiceDefin
 		Volumes:     service.Volumes,
 		Memory:      service.Memory,
 		CPUs:        service.CPUs,
+
+		RestartPolicy: service.RestartPolicy,
+		MaxRestarts:   service.MaxRestarts,
 	}
 
 	containerID, err := cm.client.CreateContainer(ctx, config)
@@ -194,6 +197,9 @@ type ServiceDefinition struct {
 	Memory      int64
 	CPUs        float64
 	HealthCheck *HealthCheck
+
+	RestartPolicy string
+	MaxRestarts   int
 }
 
 type HealthCheck struct {
@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hostCandidate is a place a Docker daemon may listen.
+type hostCandidate struct {
+	// path is checked for existence
+	path string
+
+	// host is the address the client connects to
+	host string
+}
+
+// DetectHost returns the address of the first Docker daemon found in the
+// platform's usual locations (Docker Desktop, rootless Docker, Colima, ...),
+// or "" to keep the client default. DOCKER_HOST always wins.
+func DetectHost() string {
+	if os.Getenv("DOCKER_HOST") != "" {
+		return ""
+	}
+
+	for _, candidate := range hostCandidates() {
+		if candidate.path == "" {
+			continue
+		}
+		if _, err := os.Stat(candidate.path); err == nil {
+			return candidate.host
+		}
+	}
+	return ""
+}
+
+// absoluteBinds makes the host side of bind mounts absolute, as the Docker
+// API requires, resolving relative paths against the working directory.
+// Named volumes and Windows drive paths (C:\...) are handled too.
+func absoluteBinds(binds []string) ([]string, error) {
+	resolved := make([]string, 0, len(binds))
+	for _, bind := range binds {
+		// The container path is the last ":/"-prefixed part; everything
+		// before it is the host path, which may itself contain a drive colon
+		i := strings.LastIndex(bind, ":/")
+		if i <= 0 {
+			resolved = append(resolved, bind)
+			continue
+		}
+
+		source := bind[:i]
+		namedVolume := !strings.ContainsAny(source, `/\`) && !strings.HasPrefix(source, ".")
+		if namedVolume || filepath.IsAbs(source) {
+			resolved = append(resolved, bind)
+			continue
+		}
+
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, abs+bind[i:])
+	}
+	return resolved, nil
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package docker
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// hostCandidates are the sockets of the system daemon, Docker Desktop,
+// rootless Docker, Colima and Rancher Desktop.
+func hostCandidates() []hostCandidate {
+	candidates := []hostCandidate{
+		{path: "/var/run/docker.sock"},
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates,
+			hostCandidate{path: filepath.Join(home, ".docker", "run", "docker.sock")},
+			hostCandidate{path: filepath.Join(home, ".colima", "default", "docker.sock")},
+			hostCandidate{path: filepath.Join(home, ".rd", "docker.sock")},
+		)
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append(candidates, hostCandidate{path: filepath.Join(runtimeDir, "docker.sock")})
+	}
+
+	for i := range candidates {
+		candidates[i].host = "unix://" + candidates[i].path
+	}
+	return candidates
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package docker
+
+// hostCandidates are the named pipes of Docker Desktop's engines.
+func hostCandidates() []hostCandidate {
+	return []hostCandidate{
+		{path: `\\.\pipe\docker_engine`, host: "npipe:////./pipe/docker_engine"},
+		{path: `\\.\pipe\dockerDesktopLinuxEngine`, host: "npipe:////./pipe/dockerDesktopLinuxEngine"},
+	}
+}
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// VerifyImageDigest checks that a pinned image ("name@sha256:...") is
+// present locally with that content digest. A missing image is pulled by
+// digest first; the daemon checks the pulled content against the digest.
+func (c *Client) VerifyImageDigest(ctx context.Context, image string) error {
+	name, digest, ok := strings.Cut(image, "@")
+	if !ok {
+		return fmt.Errorf("image %s is not pinned to a digest", image)
+	}
+
+	inspect, _, err := c.cli.ImageInspectWithRaw(ctx, image)
+	if client.IsErrNotFound(err) {
+		if err := c.PullImage(ctx, image); err != nil {
+			return err
+		}
+		inspect, _, err = c.cli.ImageInspectWithRaw(ctx, image)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+
+	for _, repoDigest := range inspect.RepoDigests {
+		if strings.HasSuffix(repoDigest, "@"+digest) {
+			return nil
+		}
+	}
+
+	if len(inspect.RepoDigests) == 0 {
+		return fmt.Errorf("image %s has no registry digest (built locally?); it cannot match the pinned %s", name, digest)
+	}
+	return fmt.Errorf("image %s does not match the pinned digest %s (found %s)", name, digest, strings.Join(inspect.RepoDigests, ", "))
+}
+
+// VerifyImageSignature checks a pinned image's cosign signature against a
+// public key. cosign must be installed.
+func VerifyImageSignature(ctx context.Context, image, keyPath string) error {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return errors.New("cosign is not installed (see https://docs.sigstore.dev/cosign/system_config/installation/)")
+	}
+
+	output, err := exec.CommandContext(ctx, "cosign", "verify", "--key", keyPath, image).CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(output))
+		if lines := strings.Split(message, "\n"); len(lines) > 0 {
+			message = lines[len(lines)-1]
+		}
+		return fmt.Errorf("signature of %s does not verify with %s: %s", image, keyPath, message)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// DaemonPlatform returns the platform the daemon runs containers on natively
+// ("linux/arm64" on Apple Silicon).
+func (c *Client) DaemonPlatform(ctx context.Context) (string, error) {
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to query Docker daemon: %w", err)
+	}
+	return info.OSType + "/" + NormalizeArch(info.Architecture), nil
+}
+
+// ImagePlatform returns the platform of a local image, or "" if the image
+// is not present.
+func (c *Client) ImagePlatform(ctx context.Context, image string) (string, error) {
+	inspect, _, err := c.cli.ImageInspectWithRaw(ctx, image)
+	if client.IsErrNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	return inspect.Os + "/" + NormalizeArch(inspect.Architecture), nil
+}
+
+// PullImageForPlatform pulls the variant of a multi-arch image built for
+// platform. Images published for a single architecture fail to pull.
+func (c *Client) PullImageForPlatform(ctx context.Context, image, platform string) error {
+	reader, err := c.cli.ImagePull(ctx, image, types.ImagePullOptions{Platform: platform})
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s for %s: %w", image, platform, err)
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+// NormalizeArch maps the kernel's architecture names, which the daemon
+// reports, to the OCI ones images use.
+func NormalizeArch(arch string) string {
+	switch arch {
+	case "x86_64", "x86-64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	default:
+		return arch
+	}
+}
@@ -0,0 +1,95 @@
+package egress
+
+import (
+	"crypto/x509"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadCA(t *testing.T) {
+	dir := t.TempDir()
+
+	created, err := LoadCA(dir)
+	if err != nil {
+		t.Fatalf("LoadCA() error = %v", err)
+	}
+	if !created.cert.IsCA {
+		t.Error("LoadCA() created a certificate that is not a CA")
+	}
+
+	loaded, err := LoadCA(dir)
+	if err != nil {
+		t.Fatalf("LoadCA() error = %v", err)
+	}
+	if !loaded.cert.Equal(created.cert) {
+		t.Error("LoadCA() created a new CA instead of loading the saved one")
+	}
+}
+
+func TestLoadCAInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadCA(dir); err != nil {
+		t.Fatalf("LoadCA() error = %v", err)
+	}
+	if err := os.WriteFile(CAPath(dir), []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadCA(dir); err == nil || !strings.Contains(err.Error(), "invalid egress CA") {
+		t.Errorf("LoadCA() error = %v, want invalid egress CA", err)
+	}
+}
+
+func TestCACertificate(t *testing.T) {
+	ca, err := LoadCA(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadCA() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	for _, host := range []string{"api.openai.com", "127.0.0.1"} {
+		t.Run(host, func(t *testing.T) {
+			cert, err := ca.Certificate(host)
+			if err != nil {
+				t.Fatalf("Certificate() error = %v", err)
+			}
+			leaf, err := x509.ParseCertificate(cert.Certificate[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+				t.Errorf("certificate for %s does not verify: %v", host, err)
+			}
+
+			again, err := ca.Certificate(host)
+			if err != nil || again != cert {
+				t.Errorf("Certificate() did not reuse the certificate for %s", host)
+			}
+		})
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := LoadCA(dir)
+	if err != nil {
+		t.Fatalf("LoadCA() error = %v", err)
+	}
+	if err := ca.WriteBundle(dir); err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+
+	caPEM, err := os.ReadFile(CAPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := os.ReadFile(BundlePath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(bundle), string(caPEM)) {
+		t.Error("bundle does not start with the CA certificate")
+	}
+}
@@ -0,0 +1,235 @@
+package egress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// FaultsPath is the proxy's admin endpoint for network faults: GET returns
+// the active faults, PUT replaces them and DELETE restores egress.faults.
+const FaultsPath = "/_sentra/network-faults"
+
+// resetPath is the mock state reset endpoint (see mockstate), which the
+// proxy answers by restoring egress.faults between scenarios
+const resetPath = "/_sentra/state/reset"
+
+// DefaultStall is how long a stalled request hangs
+const DefaultStall = 30 * time.Second
+
+// Faults are the network faults the proxy injects into agent traffic.
+type Faults struct {
+	// DropRate is the share of connections closed without a response
+	DropRate float64 `json:"drop_rate"`
+
+	// DNSFailures are hosts whose lookup fails; "*.example.com" matches
+	// subdomains
+	DNSFailures []string `json:"dns_failures,omitempty"`
+
+	// StallRate is the share of requests that hang for Stall before they
+	// are forwarded
+	StallRate float64 `json:"stall_rate"`
+	Stall     string  `json:"stall,omitempty"`
+
+	// Hosts limits drops and stalls to these hosts (default: every host)
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// FaultsFromConfig returns the faults configured under egress.faults.
+func FaultsFromConfig(cfg config.NetworkFaults) Faults {
+	return Faults{
+		DropRate:    cfg.DropRate,
+		DNSFailures: cfg.DNSFailures,
+		StallRate:   cfg.StallRate,
+		Stall:       cfg.Stall,
+		Hosts:       cfg.Hosts,
+	}
+}
+
+// Validate checks rates and the stall duration.
+func (f Faults) Validate() error {
+	if f.DropRate < 0 || f.DropRate > 1 {
+		return fmt.Errorf("drop_rate must be between 0 and 1, got %g", f.DropRate)
+	}
+	if f.StallRate < 0 || f.StallRate > 1 {
+		return fmt.Errorf("stall_rate must be between 0 and 1, got %g", f.StallRate)
+	}
+	if f.Stall != "" {
+		if d, err := time.ParseDuration(f.Stall); err != nil || d <= 0 {
+			return fmt.Errorf("invalid stall %q (e.g. \"30s\")", f.Stall)
+		}
+	}
+	return nil
+}
+
+// Active reports whether any fault is set.
+func (f Faults) Active() bool {
+	return f.DropRate > 0 || f.StallRate > 0 || len(f.DNSFailures) > 0
+}
+
+func (f Faults) stall() time.Duration {
+	if d, err := time.ParseDuration(f.Stall); err == nil && d > 0 {
+		return d
+	}
+	return DefaultStall
+}
+
+// fault is what happens to one request
+type fault int
+
+const (
+	faultNone fault = iota
+	faultDNS
+	faultDrop
+	faultStall
+)
+
+// faultState holds the active faults, which scenarios change at runtime.
+type faultState struct {
+	mu       sync.Mutex
+	baseline Faults
+	active   Faults
+	rng      *rand.Rand
+}
+
+func newFaultState(baseline Faults) *faultState {
+	return &faultState{
+		baseline: baseline,
+		active:   baseline,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// pick decides the fault for a request to host.
+func (s *faultState) pick(host string) (fault, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if matchHost(host, s.active.DNSFailures) {
+		return faultDNS, 0
+	}
+	if len(s.active.Hosts) > 0 && !matchHost(host, s.active.Hosts) {
+		return faultNone, 0
+	}
+	if s.active.DropRate > 0 && s.rng.Float64() < s.active.DropRate {
+		return faultDrop, 0
+	}
+	if s.active.StallRate > 0 && s.rng.Float64() < s.active.StallRate {
+		return faultStall, s.active.stall()
+	}
+	return faultNone, 0
+}
+
+// ServeHTTP answers the faults admin API.
+func (s *faultState) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == resetPath {
+		s.reset(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var faults Faults
+		if err := json.NewDecoder(r.Body).Decode(&faults); err != nil {
+			http.Error(w, fmt.Sprintf("invalid faults: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := faults.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.active = faults
+		s.mu.Unlock()
+	case http.MethodDelete:
+		s.mu.Lock()
+		s.active = s.baseline
+		s.mu.Unlock()
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	active := s.active
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(active)
+}
+
+// reset restores egress.faults when mock state is reset, unless the reset
+// is limited to state other than faults.
+func (s *faultState) reset(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		State []string `json:"state"`
+	}
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			http.Error(w, fmt.Sprintf("invalid reset request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	restore := len(req.State) == 0
+	for _, state := range req.State {
+		if state == "faults" {
+			restore = true
+		}
+	}
+	if restore {
+		s.mu.Lock()
+		s.active = s.baseline
+		s.mu.Unlock()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `{"reset":true}`)
+}
+
+// isAdminPath reports whether a (non-proxy) request is for the admin API.
+func isAdminPath(path string) bool {
+	return path == FaultsPath || path == resetPath
+}
+
+// wait hangs for d, or until ctx is done; it reports whether d passed.
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func dnsMessage(host string) string {
+	return fmt.Sprintf("sentra egress proxy: lookup %s: no such host (injected DNS failure)", host)
+}
+
+func matchHost(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package egress
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFaultsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		faults  Faults
+		wantErr string
+	}{
+		{name: "none", faults: Faults{}},
+		{name: "rates", faults: Faults{DropRate: 1, StallRate: 0.5, Stall: "2s"}},
+		{name: "negative drop rate", faults: Faults{DropRate: -0.1}, wantErr: "drop_rate must be between 0 and 1"},
+		{name: "stall rate over 1", faults: Faults{StallRate: 1.5}, wantErr: "stall_rate must be between 0 and 1"},
+		{name: "invalid stall", faults: Faults{Stall: "forever"}, wantErr: "invalid stall"},
+		{name: "zero stall", faults: Faults{Stall: "0s"}, wantErr: "invalid stall"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.faults.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFaultsActive(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults Faults
+		want   bool
+	}{
+		{name: "none", faults: Faults{}},
+		{name: "hosts only", faults: Faults{Hosts: []string{"api.example.com"}, Stall: "1s"}},
+		{name: "drops", faults: Faults{DropRate: 0.1}, want: true},
+		{name: "stalls", faults: Faults{StallRate: 0.1}, want: true},
+		{name: "DNS failures", faults: Faults{DNSFailures: []string{"api.example.com"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.faults.Active(); got != tt.want {
+				t.Errorf("Active() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaultsStall(t *testing.T) {
+	tests := []struct {
+		stall string
+		want  time.Duration
+	}{
+		{stall: "", want: DefaultStall},
+		{stall: "250ms", want: 250 * time.Millisecond},
+		{stall: "-1s", want: DefaultStall},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stall, func(t *testing.T) {
+			if got := (Faults{Stall: tt.stall}).stall(); got != tt.want {
+				t.Errorf("stall() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		patterns []string
+		want     bool
+	}{
+		{host: "api.example.com", patterns: []string{"api.example.com"}, want: true},
+		{host: "api.example.com", patterns: []string{"API.Example.com"}, want: true},
+		{host: "api.example.com", patterns: []string{"*.example.com"}, want: true},
+		{host: "example.com", patterns: []string{"*.example.com"}},
+		{host: "notexample.com", patterns: []string{"*.example.com"}},
+		{host: "api.example.com", patterns: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := matchHost(tt.host, tt.patterns); got != tt.want {
+				t.Errorf("matchHost(%q, %q) = %v, want %v", tt.host, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaultStatePick(t *testing.T) {
+	tests := []struct {
+		name      string
+		faults    Faults
+		host      string
+		want      fault
+		wantStall time.Duration
+	}{
+		{name: "no faults", faults: Faults{}, host: "api.example.com", want: faultNone},
+		{name: "DNS failure", faults: Faults{DNSFailures: []string{"*.example.com"}}, host: "api.example.com", want: faultDNS},
+		{name: "DNS failure outside hosts", faults: Faults{DNSFailures: []string{"api.example.com"}, Hosts: []string{"other.com"}}, host: "api.example.com", want: faultDNS},
+		{name: "drop", faults: Faults{DropRate: 1}, host: "api.example.com", want: faultDrop},
+		{name: "drop outside hosts", faults: Faults{DropRate: 1, Hosts: []string{"other.com"}}, host: "api.example.com", want: faultNone},
+		{name: "stall", faults: Faults{StallRate: 1, Stall: "5s"}, host: "api.example.com", want: faultStall, wantStall: 5 * time.Second},
+		{name: "drop before stall", faults: Faults{DropRate: 1, StallRate: 1}, host: "api.example.com", want: faultDrop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, stall := newFaultState(tt.faults).pick(tt.host)
+			if got != tt.want || stall != tt.wantStall {
+				t.Errorf("pick(%q) = %v, %v, want %v, %v", tt.host, got, stall, tt.want, tt.wantStall)
+			}
+		})
+	}
+}
+
+func TestFaultStateServeHTTP(t *testing.T) {
+	baseline := Faults{DropRate: 0.1}
+	replaced := Faults{StallRate: 0.5, Stall: "1s", Hosts: []string{"api.example.com"}}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		want       Faults
+	}{
+		{name: "get", method: http.MethodGet, path: FaultsPath, wantStatus: http.StatusOK, want: replaced},
+		{name: "put", method: http.MethodPut, path: FaultsPath, body: `{"dns_failures": ["api.example.com"]}`, wantStatus: http.StatusOK, want: Faults{DNSFailures: []string{"api.example.com"}}},
+		{name: "put invalid JSON", method: http.MethodPut, path: FaultsPath, body: `{`, wantStatus: http.StatusBadRequest, want: replaced},
+		{name: "put invalid faults", method: http.MethodPut, path: FaultsPath, body: `{"drop_rate": 2}`, wantStatus: http.StatusBadRequest, want: replaced},
+		{name: "delete", method: http.MethodDelete, path: FaultsPath, wantStatus: http.StatusOK, want: baseline},
+		{name: "post", method: http.MethodPost, path: FaultsPath, wantStatus: http.StatusMethodNotAllowed, want: replaced},
+		{name: "reset", method: http.MethodPost, path: resetPath, wantStatus: http.StatusOK, want: baseline},
+		{name: "reset faults", method: http.MethodPost, path: resetPath, body: `{"state": ["requests", "faults"]}`, wantStatus: http.StatusOK, want: baseline},
+		{name: "reset other state", method: http.MethodPost, path: resetPath, body: `{"state": ["requests"]}`, wantStatus: http.StatusOK, want: replaced},
+		{name: "reset invalid JSON", method: http.MethodPost, path: resetPath, body: `[`, wantStatus: http.StatusBadRequest, want: replaced},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newFaultState(baseline)
+			state.active = replaced
+
+			w := httptest.NewRecorder()
+			state.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if !reflect.DeepEqual(state.active, tt.want) {
+				t.Errorf("active = %+v, want %+v", state.active, tt.want)
+			}
+			if tt.path == FaultsPath && w.Code == http.StatusOK {
+				var got Faults
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+					t.Fatalf("invalid response %s: %v", w.Body, err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("response = %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
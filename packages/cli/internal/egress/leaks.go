@@ -0,0 +1,117 @@
+package egress
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Leak is an external call an agent made that no mock served.
+type Leak struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	Host   string    `json:"host"`
+
+	// URL is the full URL of plain HTTP calls; HTTPS calls are only seen as
+	// a host and port
+	URL string `json:"url"`
+
+	// Blocked is false in warn mode, where the call went through
+	Blocked bool `json:"blocked"`
+}
+
+// Recorder appends leaks to a JSON Lines file.
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewRecorder records leaks to path.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Record appends a leak.
+func (r *Recorder) Record(leak Leak) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(leak)
+}
+
+// LoadLeaks returns the leaks recorded at path since a time (all when since
+// is zero). A missing file means no leaks.
+func LoadLeaks(path string, since time.Time) ([]Leak, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var leaks []Leak
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var leak Leak
+		if err := json.Unmarshal(scanner.Bytes(), &leak); err != nil {
+			return nil, fmt.Errorf("invalid leak record in %s: %w", path, err)
+		}
+		if leak.Time.Before(since) {
+			continue
+		}
+		leaks = append(leaks, leak)
+	}
+
+	return leaks, scanner.Err()
+}
+
+// HostCount is how often a host was called.
+type HostCount struct {
+	Host    string
+	Calls   int
+	Blocked int
+}
+
+// ByHost counts leaks per host, most called first.
+func ByHost(leaks []Leak) []HostCount {
+	index := make(map[string]int)
+	var counts []HostCount
+	for _, leak := range leaks {
+		i, ok := index[leak.Host]
+		if !ok {
+			i = len(counts)
+			index[leak.Host] = i
+			counts = append(counts, HostCount{Host: leak.Host})
+		}
+		counts[i].Calls++
+		if leak.Blocked {
+			counts[i].Blocked++
+		}
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Calls > counts[j].Calls
+	})
+	return counts
+}
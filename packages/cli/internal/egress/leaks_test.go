@@ -0,0 +1,85 @@
+package egress
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderAndLoadLeaks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "egress", "leaks.jsonl")
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	leaks := []Leak{
+		{Time: start, Method: "GET", Host: "api.example.com", URL: "http://api.example.com/v1", Blocked: true},
+		{Time: start.Add(time.Minute), Method: "CONNECT", Host: "hooks.slack.com", URL: "https://hooks.slack.com:443"},
+	}
+
+	recorder := NewRecorder(path)
+	for _, leak := range leaks {
+		if err := recorder.Record(leak); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []Leak
+	}{
+		{name: "all", want: leaks},
+		{name: "since", since: start.Add(time.Second), want: leaks[1:]},
+		{name: "none", since: start.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadLeaks(path, tt.since)
+			if err != nil {
+				t.Fatalf("LoadLeaks() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadLeaks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLeaksMissingFile(t *testing.T) {
+	leaks, err := LoadLeaks(filepath.Join(t.TempDir(), "leaks.jsonl"), time.Time{})
+	if err != nil || leaks != nil {
+		t.Errorf("LoadLeaks() = %v, %v, want no leaks", leaks, err)
+	}
+}
+
+func TestLoadLeaksInvalidRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leaks.jsonl")
+	if err := os.WriteFile(path, []byte("\n{not json}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadLeaks(path, time.Time{}); err == nil || !strings.Contains(err.Error(), "invalid leak record") {
+		t.Errorf("LoadLeaks() error = %v, want invalid leak record", err)
+	}
+}
+
+func TestByHost(t *testing.T) {
+	leaks := []Leak{
+		{Host: "a.example.com", Blocked: true},
+		{Host: "b.example.com"},
+		{Host: "b.example.com", Blocked: true},
+		{Host: "c.example.com"},
+		{Host: "b.example.com"},
+	}
+
+	want := []HostCount{
+		{Host: "b.example.com", Calls: 3, Blocked: 1},
+		{Host: "a.example.com", Calls: 1, Blocked: 1},
+		{Host: "c.example.com", Calls: 1},
+	}
+	if got := ByHost(leaks); !reflect.DeepEqual(got, want) {
+		t.Errorf("ByHost() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package egress
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// StartBackground runs `sentra lab egress serve` as a background process,
+// so the proxy outlives `sentra lab start` like the containers do. A proxy
+// left running by an earlier start is stopped first.
+func StartBackground(dir, configPath string) (int, error) {
+	if err := StopBackground(dir); err != nil {
+		return 0, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to locate the sentra executable: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	logFile, err := os.OpenFile(filepath.Join(dir, "proxy.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, "lab", "egress", "serve", "--config", configPath)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start egress proxy: %w", err)
+	}
+
+	pid := cmd.Process.Pid
+	if err := os.WriteFile(pidPath(dir), []byte(strconv.Itoa(pid)), 0644); err != nil {
+		cmd.Process.Kill()
+		return 0, err
+	}
+
+	return pid, cmd.Process.Release()
+}
+
+// StopBackground stops the proxy StartBackground started, if any.
+func StopBackground(dir string) error {
+	content, err := os.ReadFile(pidPath(dir))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err == nil {
+		if process, err := os.FindProcess(pid); err == nil {
+			// The process may already be gone
+			process.Kill()
+		}
+	}
+
+	return os.Remove(pidPath(dir))
+}
+
+func pidPath(dir string) string {
+	return filepath.Join(dir, "proxy.pid")
+}
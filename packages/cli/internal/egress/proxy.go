@@ -0,0 +1,401 @@
+// Package egress implements the agent egress proxy: an HTTP(S) forward
+// proxy that routes known API hosts to the mocks, lets allowed hosts through
+// and blocks (or warns on) every other external call, recording it as a
+// leak. Agents use it through HTTP_PROXY/HTTPS_PROXY, so hard-coded
+// production URLs are caught and tests stay truly offline. The proxy can
+// also inject network faults (dropped connections, DNS failures, stalls)
+// to test agents' connection-level resilience.
+package egress
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// Dir holds the proxy's CA, leak log and process state
+const Dir = ".sentra-lab/egress"
+
+// LeaksPath is where leaks are recorded
+const LeaksPath = Dir + "/leaks.jsonl"
+
+// Modes
+const (
+	ModeBlock = "block"
+	ModeWarn  = "warn"
+)
+
+// DefaultRoutes are the hosts routed to a mock when it is enabled
+var DefaultRoutes = map[string]string{
+	"api.openai.com": "openai",
+	"api.stripe.com": "stripe",
+}
+
+// dialTimeout bounds connecting to allowed hosts
+const dialTimeout = 10 * time.Second
+
+// Proxy is the egress proxy.
+type Proxy struct {
+	mode  string
+	allow []string
+
+	// routes map hosts to mock base URLs
+	routes map[string]*url.URL
+
+	ca        *CA
+	recorder  *Recorder
+	transport *http.Transport
+
+	// faults are the network faults injected into agent traffic
+	faults *faultState
+
+	// OnLeak, when set, is called for every leak, with the error if it
+	// could not be recorded
+	OnLeak func(Leak, error)
+}
+
+// NewProxy builds the proxy for cfg, keeping its CA and leak log in dir.
+func NewProxy(cfg *config.Config, dir string) (*Proxy, error) {
+	ca, err := LoadCA(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Proxy{
+		mode:     cfg.Egress.Mode,
+		allow:    cfg.Egress.Allow,
+		routes:   make(map[string]*url.URL),
+		ca:       ca,
+		recorder: NewRecorder(filepath.Join(dir, "leaks.jsonl")),
+		faults:   newFaultState(FaultsFromConfig(cfg.Egress.Faults)),
+		transport: &http.Transport{
+			// Never chain to the proxy the CLI itself may be configured with
+			Proxy:               nil,
+			DialContext:         (&net.Dialer{Timeout: dialTimeout}).DialContext,
+			TLSHandshakeTimeout: dialTimeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	for host, mock := range Routes(cfg) {
+		p.routes[host] = &url.URL{Scheme: "http", Host: fmt.Sprintf("localhost:%d", cfg.Mocks[mock].Port)}
+	}
+
+	return p, nil
+}
+
+// Routes returns the hosts routed to each enabled mock (host: mock name).
+func Routes(cfg *config.Config) map[string]string {
+	routes := make(map[string]string)
+	for host, mock := range DefaultRoutes {
+		if cfg.Mocks[mock].Enabled {
+			routes[host] = mock
+		}
+	}
+	for host, mock := range cfg.Egress.Routes {
+		routes[strings.ToLower(host)] = mock
+	}
+	return routes
+}
+
+// ListenAndServe runs the proxy on addr until ctx is done.
+func (p *Proxy) ListenAndServe(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: p,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP handles a proxied request.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.handleConnect(w, r)
+		return
+	}
+
+	if !r.URL.IsAbs() {
+		if isAdminPath(r.URL.Path) {
+			p.faults.ServeHTTP(w, r)
+			return
+		}
+		http.Error(w, "sentra egress proxy: expected a proxy request (absolute URL)", http.StatusBadRequest)
+		return
+	}
+
+	host := strings.ToLower(r.URL.Hostname())
+	if !p.injectFault(w, r, host) {
+		return
+	}
+
+	if target, ok := p.routes[host]; ok {
+		r.URL.Scheme = target.Scheme
+		r.URL.Host = target.Host
+		p.forward(w, r)
+		return
+	}
+
+	if !p.allowed(host) {
+		blocked := p.leak(r.Method, host, r.URL.String())
+		if blocked {
+			http.Error(w, blockedMessage(host), http.StatusForbidden)
+			return
+		}
+	}
+
+	p.forward(w, r)
+}
+
+// injectFault applies the active network fault, if any, to a request for
+// host and reports whether the request should still be served.
+func (p *Proxy) injectFault(w http.ResponseWriter, r *http.Request, host string) bool {
+	fault, stall := p.faults.pick(host)
+	switch fault {
+	case faultDNS:
+		http.Error(w, dnsMessage(host), http.StatusBadGateway)
+		return false
+	case faultDrop:
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return false
+			}
+		}
+		panic(http.ErrAbortHandler)
+	case faultStall:
+		return wait(r.Context(), stall)
+	}
+	return true
+}
+
+// forward sends a request on to its (possibly rewritten) URL.
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	proxy := &httputil.ReverseProxy{
+		Director:      func(*http.Request) {},
+		Transport:     p.transport,
+		FlushInterval: -1,
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// handleConnect handles an HTTPS tunnel. Routed hosts are answered by their
+// mock behind a certificate from the proxy's CA; allowed hosts (and leaks
+// in warn mode) are tunneled as-is. Network faults apply to the tunnel, and
+// to each request relayed to a mock.
+func (p *Proxy) handleConnect(w http.ResponseWriter, r *http.Request) {
+	host := strings.ToLower(r.URL.Hostname())
+	target, routed := p.routes[host]
+
+	if !routed && !p.allowed(host) {
+		if p.leak(r.Method, host, "https://"+r.Host) {
+			http.Error(w, blockedMessage(host), http.StatusForbidden)
+			return
+		}
+	}
+
+	fault, stall := p.faults.pick(host)
+	switch fault {
+	case faultDNS:
+		http.Error(w, dnsMessage(host), http.StatusBadGateway)
+		return
+	case faultStall:
+		if !wait(r.Context(), stall) {
+			return
+		}
+	}
+
+	var upstream net.Conn
+	if !routed {
+		var err error
+		upstream, err = net.DialTimeout("tcp", r.Host, dialTimeout)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("sentra egress proxy: %v", err), http.StatusBadGateway)
+			return
+		}
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "sentra egress proxy: tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		if upstream != nil {
+			upstream.Close()
+		}
+		return
+	}
+	defer client.Close()
+
+	if _, err := io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		return
+	}
+
+	// A dropped tunnel closes before the TLS handshake completes
+	if fault == faultDrop {
+		if upstream != nil {
+			upstream.Close()
+		}
+		return
+	}
+
+	if routed {
+		p.serveMock(client, host, target)
+		return
+	}
+
+	defer upstream.Close()
+	tunnel(client, upstream)
+}
+
+// serveMock terminates TLS for host and relays the requests to its mock.
+func (p *Proxy) serveMock(client net.Conn, host string, target *url.URL) {
+	conn := tls.Server(client, &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return p.ca.Certificate(host)
+		},
+		NextProtos: []string{"http/1.1"},
+	})
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+
+		switch fault, stall := p.faults.pick(host); fault {
+		case faultDrop:
+			return
+		case faultStall:
+			if !wait(req.Context(), stall) {
+				return
+			}
+		}
+
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		req.RequestURI = ""
+
+		resp, err := p.transport.RoundTrip(req)
+		if err != nil {
+			resp = &http.Response{
+				StatusCode: http.StatusBadGateway,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{"Content-Type": {"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("sentra egress proxy: mock for %s is unreachable: %v\n", host, err))),
+				Close:      true,
+			}
+		}
+
+		err = resp.Write(conn)
+		resp.Body.Close()
+		if err != nil || req.Close || resp.Close {
+			return
+		}
+	}
+}
+
+// tunnel copies bytes both ways until either side closes.
+func tunnel(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	copyConn := func(dst, src net.Conn) {
+		defer wg.Done()
+		io.Copy(dst, src)
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+	}
+	go copyConn(a, b)
+	go copyConn(b, a)
+	wg.Wait()
+}
+
+// allowed reports whether agents may reach host directly.
+func (p *Proxy) allowed(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	return matchHost(host, p.allow)
+}
+
+// leak records an unexpected external call and reports whether it is
+// blocked.
+func (p *Proxy) leak(method, host, rawURL string) bool {
+	leak := Leak{
+		Time:    time.Now().UTC(),
+		Method:  method,
+		Host:    host,
+		URL:     rawURL,
+		Blocked: p.mode != ModeWarn,
+	}
+
+	err := p.recorder.Record(leak)
+	if p.OnLeak != nil {
+		p.OnLeak(leak, err)
+	}
+	return leak.Blocked
+}
+
+func blockedMessage(host string) string {
+	return fmt.Sprintf("sentra egress proxy: blocked external call to %s (route it to a mock with egress.routes or allow it with egress.allow)", host)
+}
+
+// Env returns the environment variables that send an agent's traffic
+// through the proxy on port and make it trust the proxy's CA in dir (see
+// WriteBundle).
+func Env(port int, dir string) map[string]string {
+	proxyURL := fmt.Sprintf("http://localhost:%d", port)
+
+	return map[string]string{
+		"HTTP_PROXY":          proxyURL,
+		"HTTPS_PROXY":         proxyURL,
+		"NO_PROXY":            "localhost,127.0.0.1,::1",
+		"SSL_CERT_FILE":       BundlePath(dir),
+		"REQUESTS_CA_BUNDLE":  BundlePath(dir),
+		"NODE_EXTRA_CA_CERTS": CAPath(dir),
+	}
+}
+
+// EnvNames returns the variable names of Env in a stable order.
+func EnvNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
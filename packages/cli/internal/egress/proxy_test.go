@@ -0,0 +1,283 @@
+package egress
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// startProxy runs a proxy for an openai mock served by mock, with egress
+// settings from egressCfg, and returns it with a client that uses it.
+func startProxy(t *testing.T, mock *httptest.Server, egressCfg config.EgressConfig) (*Proxy, *http.Client) {
+	t.Helper()
+
+	mockURL, err := url.Parse(mock.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(mockURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Mocks:  map[string]config.MockConfig{"openai": {Enabled: true, Port: port}},
+		Egress: egressCfg,
+	}
+	proxy, err := NewProxy(cfg, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewProxy() error = %v", err)
+	}
+
+	server := httptest.NewServer(proxy)
+	t.Cleanup(server.Close)
+	proxyURL, _ := url.Parse(server.URL)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(proxy.ca.cert)
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{RootCAs: roots},
+		},
+	}
+	t.Cleanup(client.CloseIdleConnections)
+	return proxy, client
+}
+
+// newMock returns a server that answers every request with its path.
+func newMock(t *testing.T) *httptest.Server {
+	t.Helper()
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "mock "+r.URL.Path)
+	}))
+	t.Cleanup(mock.Close)
+	return mock
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		mocks map[string]config.MockConfig
+		extra map[string]string
+		want  map[string]string
+	}{
+		{
+			name:  "enabled mocks",
+			mocks: map[string]config.MockConfig{"openai": {Enabled: true}, "stripe": {Enabled: false}},
+			want:  map[string]string{"api.openai.com": "openai"},
+		},
+		{
+			name:  "extra routes",
+			mocks: map[string]config.MockConfig{"openai": {Enabled: true}},
+			extra: map[string]string{"Azure.OpenAI.example.com": "openai"},
+			want:  map[string]string{"api.openai.com": "openai", "azure.openai.example.com": "openai"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Mocks: tt.mocks, Egress: config.EgressConfig{Routes: tt.extra}}
+			if got := Routes(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Routes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyAllowed(t *testing.T) {
+	p := &Proxy{allow: []string{"*.internal.example.com", "pypi.org"}}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "localhost", want: true},
+		{host: "127.0.0.1", want: true},
+		{host: "::1", want: true},
+		{host: "pypi.org", want: true},
+		{host: "auth.internal.example.com", want: true},
+		{host: "api.example.com"},
+		{host: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := p.allowed(tt.host); got != tt.want {
+				t.Errorf("allowed(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyServeHTTP(t *testing.T) {
+	mock := newMock(t)
+
+	tests := []struct {
+		name       string
+		egress     config.EgressConfig
+		url        string
+		wantStatus int
+		wantBody   string
+		wantLeak   *Leak
+	}{
+		{
+			name:       "routed to the mock",
+			url:        "http://api.openai.com/v1/models",
+			wantStatus: http.StatusOK,
+			wantBody:   "mock /v1/models",
+		},
+		{
+			name:       "routed over HTTPS",
+			url:        "https://api.openai.com/v1/chat/completions",
+			wantStatus: http.StatusOK,
+			wantBody:   "mock /v1/chat/completions",
+		},
+		{
+			name:       "loopback is allowed",
+			url:        mock.URL + "/health",
+			wantStatus: http.StatusOK,
+			wantBody:   "mock /health",
+		},
+		{
+			name:       "blocked",
+			egress:     config.EgressConfig{Mode: ModeBlock},
+			url:        "http://api.example.com/v1/charges",
+			wantStatus: http.StatusForbidden,
+			wantBody:   "blocked external call to api.example.com",
+			wantLeak:   &Leak{Method: "GET", Host: "api.example.com", URL: "http://api.example.com/v1/charges", Blocked: true},
+		},
+		{
+			name:       "injected DNS failure",
+			egress:     config.EgressConfig{Faults: config.NetworkFaults{DNSFailures: []string{"api.openai.com"}}},
+			url:        "http://api.openai.com/v1/models",
+			wantStatus: http.StatusBadGateway,
+			wantBody:   "injected DNS failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, client := startProxy(t, mock, tt.egress)
+			var leaks []Leak
+			proxy.OnLeak = func(leak Leak, err error) {
+				if err != nil {
+					t.Errorf("failed to record leak: %v", err)
+				}
+				leaks = append(leaks, leak)
+			}
+
+			resp, err := client.Get(tt.url)
+			if err != nil {
+				t.Fatalf("GET %s error = %v", tt.url, err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus || !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("GET %s = %d %q, want %d %q", tt.url, resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+
+			if tt.wantLeak == nil {
+				if len(leaks) != 0 {
+					t.Errorf("leaks = %+v, want none", leaks)
+				}
+				return
+			}
+			if len(leaks) != 1 {
+				t.Fatalf("leaks = %+v, want one", leaks)
+			}
+			got := leaks[0]
+			got.Time = time.Time{}
+			if got != *tt.wantLeak {
+				t.Errorf("leak = %+v, want %+v", got, *tt.wantLeak)
+			}
+		})
+	}
+}
+
+func TestProxyBlocksHTTPSLeak(t *testing.T) {
+	proxy, client := startProxy(t, newMock(t), config.EgressConfig{Mode: ModeBlock})
+	dir := filepath.Dir(proxy.recorder.path)
+
+	if _, err := client.Get("https://api.example.com/v1/charges"); err == nil {
+		t.Fatal("GET through a blocked tunnel succeeded")
+	}
+
+	leaks, err := LoadLeaks(filepath.Join(dir, "leaks.jsonl"), time.Time{})
+	if err != nil {
+		t.Fatalf("LoadLeaks() error = %v", err)
+	}
+	if len(leaks) != 1 || leaks[0].Method != http.MethodConnect || leaks[0].URL != "https://api.example.com:443" || !leaks[0].Blocked {
+		t.Errorf("leaks = %+v, want one blocked CONNECT to api.example.com:443", leaks)
+	}
+}
+
+func TestProxyDropsConnections(t *testing.T) {
+	egressCfg := config.EgressConfig{Faults: config.NetworkFaults{DropRate: 1}}
+
+	for _, target := range []string{"http://api.openai.com/v1/models", "https://api.openai.com/v1/models"} {
+		t.Run(target, func(t *testing.T) {
+			_, client := startProxy(t, newMock(t), egressCfg)
+			if resp, err := client.Get(target); err == nil {
+				resp.Body.Close()
+				t.Errorf("GET %s = %d, want a dropped connection", target, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestProxyAdminRequests(t *testing.T) {
+	proxy, _ := startProxy(t, newMock(t), config.EgressConfig{})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: FaultsPath, wantStatus: http.StatusOK},
+		{path: "/other", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			proxy.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("GET %s = %d, want %d", tt.path, w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	env := Env(8888, "/tmp/egress")
+
+	want := map[string]string{
+		"HTTP_PROXY":          "http://localhost:8888",
+		"HTTPS_PROXY":         "http://localhost:8888",
+		"NO_PROXY":            "localhost,127.0.0.1,::1",
+		"SSL_CERT_FILE":       "/tmp/egress/bundle.pem",
+		"REQUESTS_CA_BUNDLE":  "/tmp/egress/bundle.pem",
+		"NODE_EXTRA_CA_CERTS": "/tmp/egress/ca.pem",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("Env() = %v, want %v", env, want)
+	}
+
+	wantNames := []string{"HTTPS_PROXY", "HTTP_PROXY", "NODE_EXTRA_CA_CERTS", "NO_PROXY", "REQUESTS_CA_BUNDLE", "SSL_CERT_FILE"}
+	if names := EnvNames(env); !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("EnvNames() = %v, want %v", names, wantNames)
+	}
+}
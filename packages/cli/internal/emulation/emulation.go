@@ -0,0 +1,124 @@
+// Package emulation records which mocks run under CPU emulation (an amd64
+// image on an arm64 host, as on Apple Silicon without a native image) and
+// how much latency that adds, so `sentra lab test` can widen latency
+// expectations by the measured overhead instead of failing on it.
+package emulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Path is where `sentra lab start` writes the report
+const Path = ".sentra-lab/emulation.json"
+
+// Mock is a mock running under emulation.
+type Mock struct {
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	Platform string `json:"platform"`
+
+	// OverheadMs is the measured round trip added by emulation
+	OverheadMs int64 `json:"overhead_ms"`
+}
+
+// Report is the platform check of the last `sentra lab start`.
+type Report struct {
+	// Native is the daemon's platform
+	Native     string    `json:"native"`
+	Mocks      []Mock    `json:"mocks"`
+	MeasuredAt time.Time `json:"measured_at"`
+}
+
+// Allowance is the largest overhead of any emulated mock: the time latency
+// expectations are widened by.
+func (r *Report) Allowance() time.Duration {
+	if r == nil {
+		return 0
+	}
+	var largest int64
+	for _, mock := range r.Mocks {
+		if mock.OverheadMs > largest {
+			largest = mock.OverheadMs
+		}
+	}
+	return time.Duration(largest) * time.Millisecond
+}
+
+// Save writes a report to path, creating its directory. A report without
+// emulated mocks removes the file instead.
+func Save(path string, report *Report) error {
+	if report == nil || len(report.Mocks) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove emulation report: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode emulation report: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create emulation report directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write emulation report: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a report saved with Save, or returns nil if no mock ran under
+// emulation.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read emulation report: %w", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse emulation report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// MedianRoundTrip times probes GET requests to url and returns the median.
+func MedianRoundTrip(ctx context.Context, url string, probes int) (time.Duration, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	durations := make([]time.Duration, 0, probes)
+	for i := 0; i < probes; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		durations = append(durations, time.Since(start))
+	}
+	if len(durations) == 0 {
+		return 0, nil
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return durations[len(durations)/2], nil
+}
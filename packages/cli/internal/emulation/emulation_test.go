@@ -0,0 +1,97 @@
+package emulation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAllowance(t *testing.T) {
+	report := &Report{Mocks: []Mock{
+		{Name: "openai", OverheadMs: 12},
+		{Name: "stripe", OverheadMs: 40},
+		{Name: "coreledger", OverheadMs: 7},
+	}}
+	if got := report.Allowance(); got != 40*time.Millisecond {
+		t.Errorf("Allowance() = %v, want 40ms", got)
+	}
+
+	var none *Report
+	if got := none.Allowance(); got != 0 {
+		t.Errorf("Allowance() without a report = %v, want 0", got)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".sentra-lab", "emulation.json")
+	report := &Report{
+		Native:     "linux/arm64",
+		Mocks:      []Mock{{Name: "stripe", Image: "sentra/mock-stripe:latest", Platform: "linux/amd64", OverheadMs: 25}},
+		MeasuredAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	if err := Save(path, report); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, report) {
+		t.Errorf("Load() = %+v, want %+v", loaded, report)
+	}
+
+	// A report without emulated mocks removes the stale one
+	if err := Save(path, &Report{Native: "linux/arm64"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Stat() error = %v, want the report removed", err)
+	}
+	if loaded, err := Load(path); loaded != nil || err != nil {
+		t.Errorf("Load() = %+v, %v; want nil without a report", loaded, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "emulation.json")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() of invalid JSON error = nil")
+	}
+}
+
+func TestMedianRoundTrip(t *testing.T) {
+	var requests int32
+	delays := []time.Duration{0, 30 * time.Millisecond, 0}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		time.Sleep(delays[(n-1)%int32(len(delays))])
+	}))
+	defer server.Close()
+
+	median, err := MedianRoundTrip(context.Background(), server.URL, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 probes", requests)
+	}
+	// The one slow probe is not the median
+	if median >= 30*time.Millisecond {
+		t.Errorf("MedianRoundTrip() = %v, want below the slow probe", median)
+	}
+
+	server.Close()
+	if _, err := MedianRoundTrip(context.Background(), server.URL, 1); err == nil {
+		t.Error("MedianRoundTrip() against a closed server error = nil")
+	}
+}
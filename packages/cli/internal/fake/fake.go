@@ -0,0 +1,353 @@
+// Package fake generates realistic test data (names, emails, addresses,
+// IBANs, card numbers) for scenarios. Scenarios and variables_from rows
+// reference it as {{ fake.Email }}; each case gets its own values, derived
+// from the run seed and the case name, so a run replays exactly with the
+// same seed.
+package fake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Namespace is the prefix of fake data references ({{ fake.Email }})
+const Namespace = "fake"
+
+// Pattern matches a fake data reference; the submatch is the generator
+var Pattern = regexp.MustCompile(`\{\{\s*fake\.([A-Za-z0-9_]*)\s*\}\}`)
+
+// Generator produces one value from its own random source.
+type Generator func(rng *rand.Rand) string
+
+// Generators are the available fake data, by name.
+var Generators = map[string]Generator{
+	"FirstName":  func(rng *rand.Rand) string { return newPerson(rng).first },
+	"LastName":   func(rng *rand.Rand) string { return newPerson(rng).last },
+	"Name":       func(rng *rand.Rand) string { p := newPerson(rng); return p.first + " " + p.last },
+	"Email":      email,
+	"Username":   username,
+	"Phone":      phone,
+	"Company":    company,
+	"Street":     func(rng *rand.Rand) string { return newPlace(rng).street },
+	"City":       func(rng *rand.Rand) string { return newPlace(rng).city.name },
+	"State":      func(rng *rand.Rand) string { return newPlace(rng).city.state },
+	"PostalCode": func(rng *rand.Rand) string { return newPlace(rng).postalCode },
+	"Country":    func(rng *rand.Rand) string { return pick(rng, countries) },
+	"Address":    address,
+	"IBAN":       iban,
+	"CardNumber": cardNumber,
+	"CardExpiry": cardExpiry,
+	"CVC":        func(rng *rand.Rand) string { return fmt.Sprintf("%03d", rng.Intn(1000)) },
+	"Amount":     func(rng *rand.Rand) string { return fmt.Sprintf("%d.%02d", 1+rng.Intn(499), rng.Intn(100)) },
+	"UUID":       uuid,
+	"Sentence":   sentence,
+}
+
+// sources are generators that draw from a shared source, so the fields of
+// one person (or address) in a case belong together: {{ fake.Email }} is
+// the address of {{ fake.Name }}.
+var sources = map[string]string{
+	"FirstName":  "person",
+	"LastName":   "person",
+	"Name":       "person",
+	"Email":      "person",
+	"Username":   "person",
+	"Street":     "place",
+	"City":       "place",
+	"State":      "place",
+	"PostalCode": "place",
+	"Address":    "place",
+}
+
+// Names returns the generator names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(Generators))
+	for name := range Generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSeed returns a random run seed.
+func NewSeed() int64 {
+	return rand.New(rand.NewSource(time.Now().UnixNano())).Int63()
+}
+
+// Faker produces the fake data of one test case. A name always yields the
+// same value within a case, so {{ fake.Email }} used in two steps refers to
+// the same person.
+type Faker struct {
+	seed   int64
+	values map[string]string
+}
+
+// New returns a faker for a case of a run.
+func New(runSeed int64, caseName string) *Faker {
+	return &Faker{
+		seed:   mix(runSeed, caseName),
+		values: make(map[string]string),
+	}
+}
+
+// Value returns the case's value for a generator. Each generator draws from
+// its own source, so adding a reference does not change the others' values.
+func (f *Faker) Value(name string) (string, error) {
+	if value, ok := f.values[name]; ok {
+		return value, nil
+	}
+
+	generator, ok := Generators[name]
+	if !ok {
+		return "", fmt.Errorf("unknown fake data '%s' (available: %s)", name, strings.Join(Names(), ", "))
+	}
+
+	source := name
+	if shared, ok := sources[name]; ok {
+		source = shared
+	}
+
+	value := generator(rand.New(rand.NewSource(mix(f.seed, source))))
+	f.values[name] = value
+	return value, nil
+}
+
+// Render replaces the fake data references in text.
+func (f *Faker) Render(text string) (string, error) {
+	var err error
+	rendered := Pattern.ReplaceAllStringFunc(text, func(ref string) string {
+		value, valueErr := f.Value(Pattern.FindStringSubmatch(ref)[1])
+		if valueErr != nil && err == nil {
+			err = valueErr
+		}
+		return value
+	})
+	return rendered, err
+}
+
+// Variables returns the values of the generators referenced in text, keyed
+// as they are referenced ("fake.Email").
+func (f *Faker) Variables(text string) (map[string]string, error) {
+	variables := make(map[string]string)
+	for _, name := range References(text) {
+		value, err := f.Value(name)
+		if err != nil {
+			return nil, err
+		}
+		variables[Namespace+"."+name] = value
+	}
+	return variables, nil
+}
+
+// References returns the generators referenced in text, sorted and
+// without duplicates.
+func References(text string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, match := range Pattern.FindAllStringSubmatch(text, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mix derives a seed from a parent seed and a name.
+func mix(seed int64, name string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s", seed, name)
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+func pick[T any](rng *rand.Rand, values []T) T {
+	return values[rng.Intn(len(values))]
+}
+
+var firstNames = []string{
+	"Amara", "Ben", "Chloe", "Daniel", "Elena", "Farid", "Grace", "Hiro", "Isla", "Jonas",
+	"Kemi", "Liam", "Maya", "Noah", "Olivia", "Priya", "Quentin", "Rosa", "Samuel", "Tara",
+	"Uma", "Victor", "Wen", "Ximena", "Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Adeyemi", "Becker", "Castillo", "Dubois", "Eriksson", "Fischer", "Garcia", "Hughes",
+	"Ivanova", "Jensen", "Kowalski", "Lee", "Moreau", "Nakamura", "Okafor", "Patel",
+	"Rossi", "Schmidt", "Tanaka", "Walker", "Young", "Zhang",
+}
+
+var companySuffixes = []string{"Inc", "LLC", "Ltd", "GmbH", "Group", "Labs", "Partners"}
+
+var streetNames = []string{"Maple", "Oak", "Cedar", "Elm", "Pine", "Lake", "Hill", "Park", "Washington", "Market", "Mill", "River"}
+
+var streetSuffixes = []string{"St", "Ave", "Rd", "Blvd", "Ln", "Way", "Dr"}
+
+type city struct {
+	name  string
+	state string
+}
+
+var cities = []city{
+	{"Austin", "TX"}, {"Boston", "MA"}, {"Chicago", "IL"}, {"Denver", "CO"},
+	{"Portland", "OR"}, {"Seattle", "WA"}, {"Atlanta", "GA"}, {"Phoenix", "AZ"},
+	{"Nashville", "TN"}, {"San Diego", "CA"}, {"Minneapolis", "MN"}, {"Raleigh", "NC"},
+}
+
+var countries = []string{"US", "GB", "DE", "FR", "NL", "IE", "CA", "AU", "JP", "SG"}
+
+var words = []string{
+	"order", "refund", "invoice", "shipment", "account", "payment", "customer", "delivery",
+	"update", "request", "please", "today", "quickly", "confirm", "cancel", "review",
+}
+
+// person is the person fields are drawn from. Every draw happens up front,
+// so each field sees the same person whatever it uses.
+type person struct {
+	first  string
+	last   string
+	number int
+	domain string
+}
+
+func newPerson(rng *rand.Rand) person {
+	return person{
+		first:  pick(rng, firstNames),
+		last:   pick(rng, lastNames),
+		number: rng.Intn(100),
+		domain: pick(rng, []string{"example.com", "example.org", "example.net"}),
+	}
+}
+
+// email uses the reserved example domains, so nothing is ever delivered.
+func email(rng *rand.Rand) string {
+	p := newPerson(rng)
+	return fmt.Sprintf("%s.%s%d@%s", strings.ToLower(p.first), strings.ToLower(p.last), p.number, p.domain)
+}
+
+func username(rng *rand.Rand) string {
+	p := newPerson(rng)
+	return fmt.Sprintf("%s_%s%d", strings.ToLower(p.first), strings.ToLower(p.last[:1]), p.number)
+}
+
+// phone uses 555-0100 to 555-0199, which are reserved for fiction.
+func phone(rng *rand.Rand) string {
+	return fmt.Sprintf("+1 %d 555 01%02d", 201+rng.Intn(789), rng.Intn(100))
+}
+
+func company(rng *rand.Rand) string {
+	return fmt.Sprintf("%s %s", pick(rng, lastNames), pick(rng, companySuffixes))
+}
+
+// place is the address fields are drawn from, like person.
+type place struct {
+	street     string
+	city       city
+	postalCode string
+}
+
+func newPlace(rng *rand.Rand) place {
+	return place{
+		street:     fmt.Sprintf("%d %s %s", 1+rng.Intn(9899), pick(rng, streetNames), pick(rng, streetSuffixes)),
+		city:       pick(rng, cities),
+		postalCode: fmt.Sprintf("%05d", 10000+rng.Intn(89999)),
+	}
+}
+
+func address(rng *rand.Rand) string {
+	p := newPlace(rng)
+	return fmt.Sprintf("%s, %s, %s %s", p.street, p.city.name, p.city.state, p.postalCode)
+}
+
+// ibanFormats are countries with all-numeric BBANs and their lengths.
+var ibanFormats = []struct {
+	country string
+	length  int
+}{
+	{"DE", 18}, {"AT", 16}, {"CH", 17},
+}
+
+// iban returns an IBAN with valid check digits.
+func iban(rng *rand.Rand) string {
+	format := pick(rng, ibanFormats)
+	bban := digits(rng, format.length)
+
+	// ISO 13616: move the country code and "00" to the end, map letters to
+	// 10-35, and subtract the remainder mod 97 from 98
+	var numeric strings.Builder
+	numeric.WriteString(bban)
+	for _, r := range format.country + "00" {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&numeric, "%d", r-'A'+10)
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+	n, _ := new(big.Int).SetString(numeric.String(), 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+
+	return fmt.Sprintf("%s%02d%s", format.country, check, bban)
+}
+
+// cardNumber returns a Visa or Mastercard number with a valid Luhn check
+// digit. Mocks accept it; real processors decline it.
+func cardNumber(rng *rand.Rand) string {
+	prefix := pick(rng, []string{"4", "51", "52", "53", "54", "55"})
+	number := prefix + digits(rng, 15-len(prefix))
+	return number + luhnCheckDigit(number)
+}
+
+// cardExpiry returns an MM/YY expiry one to five years ahead.
+func cardExpiry(rng *rand.Rand) string {
+	year := time.Now().Year() + 1 + rng.Intn(5)
+	return fmt.Sprintf("%02d/%02d", 1+rng.Intn(12), year%100)
+}
+
+func uuid(rng *rand.Rand) string {
+	b := make([]byte, 16)
+	rng.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func sentence(rng *rand.Rand) string {
+	n := 5 + rng.Intn(6)
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = pick(rng, words)
+	}
+	text := strings.Join(parts, " ")
+	return strings.ToUpper(text[:1]) + text[1:] + "."
+}
+
+func digits(rng *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + rng.Intn(10))
+	}
+	return string(b)
+}
+
+func luhnCheckDigit(number string) string {
+	sum := 0
+	double := true
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return fmt.Sprintf("%d", (10-sum%10)%10)
+}
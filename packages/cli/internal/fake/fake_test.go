@@ -0,0 +1,197 @@
+package fake
+
+import (
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// luhnValid reports whether a card number passes the Luhn check.
+func luhnValid(number string) bool {
+	return luhnCheckDigit(number[:len(number)-1]) == number[len(number)-1:]
+}
+
+// ibanValid reports whether an IBAN's check digits are valid.
+func ibanValid(iban string) bool {
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			numeric.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		valid   func(string) bool
+	}{
+		{name: "Email", pattern: `^[a-z]+\.[a-z]+\d{1,2}@example\.(com|org|net)$`},
+		{name: "Username", pattern: `^[a-z]+_[a-z]\d{1,2}$`},
+		{name: "Phone", pattern: `^\+1 \d{3} 555 01\d{2}$`},
+		{name: "PostalCode", pattern: `^\d{5}$`},
+		{name: "Address", pattern: `^\d+ [A-Za-z]+ [A-Za-z]+, [A-Za-z ]+, [A-Z]{2} \d{5}$`},
+		{name: "IBAN", pattern: `^(DE|AT|CH)\d{18,22}$`, valid: ibanValid},
+		{name: "CardNumber", pattern: `^(4|5[1-5])\d{14,15}$`, valid: luhnValid},
+		{name: "CardExpiry", pattern: `^(0[1-9]|1[0-2])/\d{2}$`},
+		{name: "CVC", pattern: `^\d{3}$`},
+		{name: "Amount", pattern: `^\d{1,3}\.\d{2}$`},
+		{name: "UUID", pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{name: "Sentence", pattern: `^[A-Z][a-z ]+\.$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := regexp.MustCompile(tt.pattern)
+			for seed := int64(0); seed < 50; seed++ {
+				value, err := New(seed, "case").Value(tt.name)
+				if err != nil {
+					t.Fatalf("Value(%q) error = %v", tt.name, err)
+				}
+				if !pattern.MatchString(value) {
+					t.Fatalf("Value(%q) = %q, want a match for %s", tt.name, value, tt.pattern)
+				}
+				if tt.valid != nil && !tt.valid(value) {
+					t.Fatalf("Value(%q) = %q, which fails its check digits", tt.name, value)
+				}
+			}
+		})
+	}
+}
+
+func TestFakerValue(t *testing.T) {
+	faker := New(42, "scenarios/refund.yaml[alice]")
+
+	name, _ := faker.Value("Name")
+	first, _ := faker.Value("FirstName")
+	last, _ := faker.Value("LastName")
+	email, _ := faker.Value("Email")
+	if name != first+" "+last {
+		t.Errorf("Name = %q, want %q", name, first+" "+last)
+	}
+	if !strings.HasPrefix(email, strings.ToLower(first)+"."+strings.ToLower(last)) {
+		t.Errorf("Email = %q, want the address of %s", email, name)
+	}
+
+	city, _ := faker.Value("City")
+	address, _ := faker.Value("Address")
+	if !strings.Contains(address, ", "+city+", ") {
+		t.Errorf("Address = %q, want one in %s", address, city)
+	}
+
+	again, _ := New(42, "scenarios/refund.yaml[alice]").Value("Email")
+	other, _ := New(42, "scenarios/refund.yaml[bob]").Value("Email")
+	if again != email {
+		t.Errorf("Email with the same seed and case = %q, want %q", again, email)
+	}
+	if other == email {
+		t.Errorf("Email of another case = %q, want a different value", other)
+	}
+
+	if _, err := faker.Value("Password"); err == nil || !strings.Contains(err.Error(), "unknown fake data 'Password'") {
+		t.Errorf("Value(Password) error = %v, want unknown fake data", err)
+	}
+}
+
+func TestFakerRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{name: "references", text: "Refund {{ fake.Amount }} to {{fake.Email}} ({{ fake.Email }})"},
+		{name: "no references", text: "Refund {{ amount }}"},
+		{name: "unknown", text: "Hi {{ fake.Nickname }}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faker := New(7, "case")
+			rendered, err := faker.Render(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			want := tt.text
+			for _, name := range References(tt.text) {
+				value, _ := faker.Value(name)
+				want = regexp.MustCompile(`\{\{\s*fake\.`+name+`\s*\}\}`).ReplaceAllLiteralString(want, value)
+			}
+			if rendered != want || Pattern.MatchString(rendered) {
+				t.Errorf("Render() = %q, want %q", rendered, want)
+			}
+		})
+	}
+}
+
+func TestFakerVariables(t *testing.T) {
+	faker := New(7, "case")
+
+	variables, err := faker.Variables("{{ fake.Email }} {{ fake.City }} {{ fake.Email }}")
+	if err != nil {
+		t.Fatalf("Variables() error = %v", err)
+	}
+	email, _ := faker.Value("Email")
+	city, _ := faker.Value("City")
+	if want := map[string]string{"fake.Email": email, "fake.City": city}; !reflect.DeepEqual(variables, want) {
+		t.Errorf("Variables() = %v, want %v", variables, want)
+	}
+
+	if _, err := faker.Variables("{{ fake.Unknown }}"); err == nil {
+		t.Error("Variables() error = nil, want an unknown fake data error")
+	}
+}
+
+func TestReferences(t *testing.T) {
+	got := References("{{ fake.Email }} {{fake.Name}} {{ fake.Email }} {{ name }}")
+	if want := []string{"Email", "Name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %v, want %v", got, want)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	if len(names) != len(Generators) {
+		t.Fatalf("Names() = %v, want every generator", names)
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("Names() = %v, want sorted names", names)
+		}
+	}
+	for name := range sources {
+		if _, ok := Generators[name]; !ok {
+			t.Errorf("source %s has no generator", name)
+		}
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{number: "424242424242424", want: "2"},
+		{number: "555555555555444", want: "4"},
+		{number: "7992739871", want: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := luhnCheckDigit(tt.number); got != tt.want {
+				t.Errorf("luhnCheckDigit(%q) = %q, want %q", tt.number, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Package finops exports simulated costs in formats finance tooling ingests:
+// FOCUS (the FinOps Open Cost and Usage Specification) and flat CSV, tagged
+// with project, scenario, model and environment so simulated and projected
+// costs can flow into existing FinOps dashboards.
+package finops
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// costsPath is the mock admin endpoint reporting costs by run
+const costsPath = "/_sentra/costs"
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 10 * time.Second
+
+// Formats are the supported export formats
+var Formats = []string{"focus", "csv"}
+
+// Cost types distinguish simulated costs from projections
+const (
+	CostSimulated = "simulated"
+	CostProjected = "projected"
+)
+
+// Entry is a day's usage for one scope, run and model, as reported by the
+// OpenAI mock.
+type Entry struct {
+	Day               string  `json:"day"`
+	StartTime         int64   `json:"start_time"`
+	EndTime           int64   `json:"end_time"`
+	RunID             string  `json:"run_id"`
+	APIKeyID          string  `json:"api_key_id"`
+	Organization      string  `json:"organization"`
+	Project           string  `json:"project"`
+	Model             string  `json:"model"`
+	Requests          int64   `json:"requests"`
+	InputTokens       int64   `json:"input_tokens"`
+	CachedInputTokens int64   `json:"cached_input_tokens"`
+	OutputTokens      int64   `json:"output_tokens"`
+	InputCost         float64 `json:"input_cost"`
+	CachedInputCost   float64 `json:"cached_input_cost"`
+	OutputCost        float64 `json:"output_cost"`
+	TotalCost         float64 `json:"total_cost"`
+}
+
+// Fetch returns the mock's cost entries for the given runs (all runs when
+// runIDs is empty).
+func Fetch(ctx context.Context, baseURL string, runIDs []string) ([]Entry, error) {
+	query := url.Values{}
+	for _, runID := range runIDs {
+		query.Add("run_id", runID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+costsPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch costs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch costs: status %d", resp.StatusCode)
+	}
+
+	var list struct {
+		Data []Entry `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid costs response: %w", err)
+	}
+	return list.Data, nil
+}
+
+// Options tag and scale exported costs.
+type Options struct {
+	// Project is the lab project (lab.yaml name)
+	Project string
+
+	// Environment labels where the costs come from (e.g. "simulation",
+	// "staging")
+	Environment string
+
+	// Scenarios map run IDs to scenario names
+	Scenarios map[string]string
+
+	// Tags are added to every row
+	Tags map[string]string
+
+	// Scale projects costs to production volume: each simulated run stands
+	// for Scale production runs. Projected rows are added when Scale > 0.
+	Scale float64
+}
+
+// charge is one priced component of an entry.
+type charge struct {
+	name   string
+	tokens int64
+	cost   float64
+}
+
+// charges splits an entry into uncached input, cached input and output
+// (input tokens and cost include the cached ones).
+func charges(entry Entry) []charge {
+	all := []charge{
+		{"input", entry.InputTokens - entry.CachedInputTokens, entry.InputCost - entry.CachedInputCost},
+		{"cached input", entry.CachedInputTokens, entry.CachedInputCost},
+		{"output", entry.OutputTokens, entry.OutputCost},
+	}
+
+	var nonzero []charge
+	for _, c := range all {
+		if c.tokens != 0 || c.cost != 0 {
+			nonzero = append(nonzero, c)
+		}
+	}
+	return nonzero
+}
+
+// tags returns a row's tags.
+func (o Options) tags(entry Entry) map[string]string {
+	tags := map[string]string{
+		"model":       entry.Model,
+		"environment": o.Environment,
+	}
+	if o.Project != "" {
+		tags["project"] = o.Project
+	}
+	if scenario := o.Scenarios[entry.RunID]; scenario != "" {
+		tags["scenario"] = scenario
+	}
+	if entry.RunID != "" {
+		tags["run_id"] = entry.RunID
+	}
+	for key, value := range o.Tags {
+		tags[key] = value
+	}
+	return tags
+}
+
+// costType is an exported cost type and its multiplier.
+type costType struct {
+	name  string
+	scale float64
+}
+
+// costTypes returns the cost types to export.
+func (o Options) costTypes() []costType {
+	types := []costType{{CostSimulated, 1}}
+	if o.Scale > 0 {
+		types = append(types, costType{CostProjected, o.Scale})
+	}
+	return types
+}
+
+// focusColumns are the FOCUS 1.0 columns written, plus x_ custom columns.
+var focusColumns = []string{
+	"BillingAccountId", "BillingAccountName", "BillingCurrency",
+	"BillingPeriodStart", "BillingPeriodEnd",
+	"ChargePeriodStart", "ChargePeriodEnd",
+	"ChargeCategory", "ChargeFrequency", "ChargeDescription",
+	"BilledCost", "EffectiveCost", "ListCost", "ContractedCost",
+	"ProviderName", "PublisherName", "InvoiceIssuerName",
+	"ServiceName", "ServiceCategory",
+	"ResourceId", "ResourceName", "ResourceType",
+	"SkuId", "PricingCategory", "PricingQuantity", "PricingUnit",
+	"ConsumedQuantity", "ConsumedUnit",
+	"SubAccountId", "SubAccountName",
+	"Tags",
+	"x_CostType", "x_Environment", "x_Scenario", "x_RunId",
+}
+
+// WriteFOCUS writes entries as a FOCUS CSV, one row per charge (input,
+// cached input and output tokens) and cost type. Simulated costs were
+// never invoiced, so BilledCost is 0 and EffectiveCost carries the cost.
+func WriteFOCUS(w io.Writer, entries []Entry, opts Options) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(focusColumns); err != nil {
+		return err
+	}
+
+	for _, entry := range sortedEntries(entries) {
+		start, err := time.Parse("2006-01-02", entry.Day)
+		if err != nil {
+			return fmt.Errorf("invalid day %q: %w", entry.Day, err)
+		}
+		periodStart := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+		tags := opts.tags(entry)
+		encodedTags, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+
+		subAccount := entry.Project
+		if subAccount == "" {
+			subAccount = opts.Project
+		}
+
+		for _, costType := range opts.costTypes() {
+			for _, c := range charges(entry) {
+				cost := formatCost(c.cost * costType.scale)
+				quantity := formatQuantity(float64(c.tokens) * costType.scale)
+
+				row := []string{
+					"sentra-lab", "Sentra Lab", "USD",
+					periodStart.Format(time.RFC3339), periodStart.AddDate(0, 1, 0).Format(time.RFC3339),
+					start.Format(time.RFC3339), start.AddDate(0, 0, 1).Format(time.RFC3339),
+					"Usage", "Usage-Based", fmt.Sprintf("%s, %s tokens (%s)", entry.Model, c.name, costType.name),
+					"0", cost, cost, cost,
+					"OpenAI", "OpenAI", "Sentra Lab",
+					"OpenAI API", "AI and Machine Learning",
+					entry.Model, entry.Model, "Model",
+					entry.Model + "/" + strings.ReplaceAll(c.name, " ", "-"), "Standard", quantity, "Tokens",
+					quantity, "Tokens",
+					subAccount, subAccount,
+					string(encodedTags),
+					costType.name, opts.Environment, tags["scenario"], entry.RunID,
+				}
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes entries as a flat CSV, one row per entry, with a
+// projected cost column when opts.Scale is set and one column per extra tag.
+func WriteCSV(w io.Writer, entries []Entry, opts Options) error {
+	extra := make([]string, 0, len(opts.Tags))
+	for key := range opts.Tags {
+		extra = append(extra, key)
+	}
+	sort.Strings(extra)
+
+	header := []string{"date", "environment", "project", "scenario", "run_id", "model",
+		"requests", "input_tokens", "cached_input_tokens", "output_tokens", "cost_usd"}
+	if opts.Scale > 0 {
+		header = append(header, "projected_cost_usd")
+	}
+	header = append(header, extra...)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range sortedEntries(entries) {
+		tags := opts.tags(entry)
+		row := []string{
+			entry.Day, opts.Environment, tags["project"], tags["scenario"], entry.RunID, entry.Model,
+			strconv.FormatInt(entry.Requests, 10),
+			strconv.FormatInt(entry.InputTokens, 10),
+			strconv.FormatInt(entry.CachedInputTokens, 10),
+			strconv.FormatInt(entry.OutputTokens, 10),
+			formatCost(entry.TotalCost),
+		}
+		if opts.Scale > 0 {
+			row = append(row, formatCost(entry.TotalCost*opts.Scale))
+		}
+		for _, key := range extra {
+			row = append(row, opts.Tags[key])
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// sortedEntries orders entries by day, run and model so exports are stable.
+func sortedEntries(entries []Entry) []Entry {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Day != sorted[j].Day {
+			return sorted[i].Day < sorted[j].Day
+		}
+		if sorted[i].RunID != sorted[j].RunID {
+			return sorted[i].RunID < sorted[j].RunID
+		}
+		return sorted[i].Model < sorted[j].Model
+	})
+	return sorted
+}
+
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 6, 64)
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
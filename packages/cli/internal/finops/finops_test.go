@@ -0,0 +1,182 @@
+package finops
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var entries = []Entry{
+	{
+		Day: "2024-05-02", RunID: "run_b", Project: "proj_support", Model: "gpt-4o-mini",
+		Requests: 1, InputTokens: 100, OutputTokens: 20,
+		InputCost: 0.000015, OutputCost: 0.000012, TotalCost: 0.000027,
+	},
+	{
+		Day: "2024-05-01", RunID: "run_a", Model: "gpt-4o",
+		Requests: 2, InputTokens: 1000, CachedInputTokens: 400, OutputTokens: 200,
+		InputCost: 0.002, CachedInputCost: 0.0005, OutputCost: 0.002, TotalCost: 0.004,
+	},
+}
+
+// readCSV parses written CSV into rows keyed by the header.
+func readCSV(t *testing.T, data []byte) []map[string]string {
+	t.Helper()
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []map[string]string
+	for _, record := range records[1:] {
+		row := make(map[string]string)
+		for i, column := range records[0] {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestFetch(t *testing.T) {
+	var gotQuery []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != costsPath {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = r.URL.Query()["run_id"]
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": entries})
+	}))
+	defer server.Close()
+
+	got, err := Fetch(context.Background(), server.URL, []string{"run_a", "run_b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("Fetch() = %+v, want %+v", got, entries)
+	}
+	if want := []string{"run_a", "run_b"}; !reflect.DeepEqual(gotQuery, want) {
+		t.Errorf("run_id query = %v, want %v", gotQuery, want)
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if _, err := Fetch(context.Background(), server.URL, nil); err == nil {
+		t.Error("Fetch() error = nil, want the status")
+	}
+}
+
+func TestWriteFOCUS(t *testing.T) {
+	opts := Options{
+		Project:     "support-agent",
+		Environment: "simulation",
+		Scenarios:   map[string]string{"run_a": "refund"},
+		Tags:        map[string]string{"team": "payments"},
+		Scale:       10,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFOCUS(&buf, entries, opts); err != nil {
+		t.Fatal(err)
+	}
+	rows := readCSV(t, buf.Bytes())
+
+	// run_a has uncached input, cached input and output; run_b has no
+	// cached input. Each charge is written simulated and projected.
+	if len(rows) != 10 {
+		t.Fatalf("got %d rows, want 10", len(rows))
+	}
+
+	first := rows[0]
+	want := map[string]string{
+		"ChargePeriodStart":  "2024-05-01T00:00:00Z",
+		"ChargePeriodEnd":    "2024-05-02T00:00:00Z",
+		"BillingPeriodStart": "2024-05-01T00:00:00Z",
+		"BillingPeriodEnd":   "2024-06-01T00:00:00Z",
+		"ChargeDescription":  "gpt-4o, input tokens (simulated)",
+		"BilledCost":         "0",
+		"EffectiveCost":      "0.001500",
+		"ConsumedQuantity":   "600",
+		"SkuId":              "gpt-4o/input",
+		"SubAccountId":       "support-agent",
+		"x_CostType":         CostSimulated,
+		"x_Scenario":         "refund",
+		"x_RunId":            "run_a",
+	}
+	for column, value := range want {
+		if first[column] != value {
+			t.Errorf("%s = %q, want %q", column, first[column], value)
+		}
+	}
+
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(first["Tags"]), &tags); err != nil {
+		t.Fatal(err)
+	}
+	wantTags := map[string]string{
+		"model": "gpt-4o", "environment": "simulation", "project": "support-agent",
+		"scenario": "refund", "run_id": "run_a", "team": "payments",
+	}
+	if !reflect.DeepEqual(tags, wantTags) {
+		t.Errorf("Tags = %v, want %v", tags, wantTags)
+	}
+
+	projected := rows[3]
+	if projected["x_CostType"] != CostProjected || projected["EffectiveCost"] != "0.015000" || projected["ConsumedQuantity"] != "6000" {
+		t.Errorf("projected row = %s %s %s, want projected 0.015000 6000",
+			projected["x_CostType"], projected["EffectiveCost"], projected["ConsumedQuantity"])
+	}
+
+	if got := rows[6]["SubAccountId"]; got != "proj_support" {
+		t.Errorf("SubAccountId = %q, want the entry's project", got)
+	}
+}
+
+func TestWriteFOCUSInvalidDay(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteFOCUS(&buf, []Entry{{Day: "yesterday", Model: "gpt-4o", InputTokens: 1}}, Options{})
+	if err == nil {
+		t.Error("WriteFOCUS() error = nil, want an invalid day error")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	opts := Options{
+		Project:     "support-agent",
+		Environment: "staging",
+		Scenarios:   map[string]string{"run_b": "chat"},
+		Tags:        map[string]string{"team": "payments", "cost_center": "cc-42"},
+		Scale:       2,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, entries, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"date", "environment", "project", "scenario", "run_id", "model", "requests", "input_tokens",
+			"cached_input_tokens", "output_tokens", "cost_usd", "projected_cost_usd", "cost_center", "team"},
+		{"2024-05-01", "staging", "support-agent", "", "run_a", "gpt-4o", "2", "1000", "400", "200", "0.004000", "0.008000", "cc-42", "payments"},
+		{"2024-05-02", "staging", "support-agent", "chat", "run_b", "gpt-4o-mini", "1", "100", "0", "20", "0.000027", "0.000054", "cc-42", "payments"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("WriteCSV() =\n%v\nwant\n%v", records, want)
+	}
+}
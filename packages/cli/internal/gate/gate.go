@@ -0,0 +1,99 @@
+// Package gate checks a run's aggregates against CI thresholds (cost,
+// latency, pass rate) so pipelines can enforce SLOs on agent changes.
+package gate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/results"
+)
+
+// ExitViolation is the exit code when a threshold is violated, distinct
+// from 1 (the gate could not be evaluated)
+const ExitViolation = 2
+
+// Thresholds are the limits a run must meet; nil limits are not checked.
+type Thresholds struct {
+	MaxCostUSD    *float64
+	MaxP95Latency *time.Duration
+	MinPassRate   *float64
+}
+
+// Check is the result of one threshold.
+type Check struct {
+	Name      string  `json:"name"`
+	Threshold float64 `json:"threshold"`
+	Actual    float64 `json:"actual"`
+	Unit      string  `json:"unit"`
+	Passed    bool    `json:"passed"`
+	Message   string  `json:"message"`
+}
+
+// Report is the machine-readable gate outcome.
+type Report struct {
+	Passed     bool               `json:"passed"`
+	FinishedAt time.Time          `json:"run_finished_at"`
+	Aggregates results.Aggregates `json:"aggregates"`
+	Checks     []Check            `json:"checks"`
+}
+
+// Violations returns the failed checks.
+func (r *Report) Violations() []Check {
+	var violations []Check
+	for _, check := range r.Checks {
+		if !check.Passed {
+			violations = append(violations, check)
+		}
+	}
+	return violations
+}
+
+// Evaluate checks a run against the thresholds.
+func Evaluate(run *results.Run, thresholds Thresholds) *Report {
+	agg := run.Aggregates()
+	report := &Report{
+		Passed:     true,
+		FinishedAt: run.FinishedAt,
+		Aggregates: agg,
+	}
+
+	add := func(check Check) {
+		if !check.Passed {
+			report.Passed = false
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	if thresholds.MaxCostUSD != nil {
+		limit := *thresholds.MaxCostUSD
+		check := Check{Name: "max_cost", Threshold: limit, Actual: agg.TotalCostUSD, Unit: "usd", Passed: agg.TotalCostUSD <= limit}
+		check.Message = fmt.Sprintf("total cost $%.4f %s limit $%.4f", agg.TotalCostUSD, verdict(check.Passed, "within", "exceeds"), limit)
+		add(check)
+	}
+
+	if thresholds.MaxP95Latency != nil {
+		limit := thresholds.MaxP95Latency.Milliseconds()
+		check := Check{Name: "max_p95_latency", Threshold: float64(limit), Actual: float64(agg.P95LatencyMs), Unit: "ms", Passed: agg.P95LatencyMs <= limit}
+		check.Message = fmt.Sprintf("p95 scenario latency %s %s limit %s",
+			time.Duration(agg.P95LatencyMs)*time.Millisecond, verdict(check.Passed, "within", "exceeds"), *thresholds.MaxP95Latency)
+		add(check)
+	}
+
+	if thresholds.MinPassRate != nil {
+		limit := *thresholds.MinPassRate
+		check := Check{Name: "min_pass_rate", Threshold: limit, Actual: agg.PassRate, Unit: "percent", Passed: agg.Total > 0 && agg.PassRate >= limit}
+		check.Message = fmt.Sprintf("pass rate %.1f%% (%d/%d) %s minimum %.1f%%",
+			agg.PassRate, agg.Passed, agg.Total, verdict(check.Passed, "meets", "below"), limit)
+		add(check)
+	}
+
+	return report
+}
+
+func verdict(passed bool, ok, failed string) string {
+	if passed {
+		return ok
+	}
+	return failed
+}
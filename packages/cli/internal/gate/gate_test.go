@@ -0,0 +1,87 @@
+package gate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/results"
+)
+
+func newRun() *results.Run {
+	return &results.Run{
+		FinishedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Scenarios: []results.ScenarioResult{
+			{Scenario: "scenarios/refund.yaml", Status: "passed", DurationMs: 1200, CostUSD: 0.02},
+			{Scenario: "scenarios/chat.yaml", Status: "passed", DurationMs: 800, CostUSD: 0.01},
+			{Scenario: "scenarios/receipt.yaml", Status: "failed", DurationMs: 3000, CostUSD: 0.03},
+			{Scenario: "scenarios/login.yaml", Status: "passed", DurationMs: 500, CostUSD: 0.01},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	maxCost, minPassRate := 0.10, 70.0
+	maxLatency := 5 * time.Second
+
+	report := Evaluate(newRun(), Thresholds{MaxCostUSD: &maxCost, MaxP95Latency: &maxLatency, MinPassRate: &minPassRate})
+
+	if !report.Passed {
+		t.Errorf("Passed = false, want true; violations: %+v", report.Violations())
+	}
+	var names []string
+	for _, check := range report.Checks {
+		names = append(names, check.Name)
+	}
+	if want := []string{"max_cost", "max_p95_latency", "min_pass_rate"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("checks = %v, want %v", names, want)
+	}
+	if got := report.Checks[1].Actual; got != 3000 {
+		t.Errorf("p95 latency = %v, want 3000", got)
+	}
+	if got := report.Checks[2].Actual; got != 75 {
+		t.Errorf("pass rate = %v, want 75", got)
+	}
+	if !report.FinishedAt.Equal(newRun().FinishedAt) {
+		t.Errorf("FinishedAt = %v, want the run's", report.FinishedAt)
+	}
+}
+
+func TestEvaluateViolations(t *testing.T) {
+	maxCost, minPassRate := 0.05, 80.0
+	maxLatency := 2 * time.Second
+
+	report := Evaluate(newRun(), Thresholds{MaxCostUSD: &maxCost, MaxP95Latency: &maxLatency, MinPassRate: &minPassRate})
+
+	if report.Passed {
+		t.Error("Passed = true, want false")
+	}
+	violations := report.Violations()
+	if len(violations) != 3 {
+		t.Fatalf("Violations() = %+v, want all three checks", violations)
+	}
+	if want := "total cost $0.0700 exceeds limit $0.0500"; violations[0].Message != want {
+		t.Errorf("cost message = %q, want %q", violations[0].Message, want)
+	}
+	if want := "p95 scenario latency 3s exceeds limit 2s"; violations[1].Message != want {
+		t.Errorf("latency message = %q, want %q", violations[1].Message, want)
+	}
+	if want := "pass rate 75.0% (3/4) below minimum 80.0%"; violations[2].Message != want {
+		t.Errorf("pass rate message = %q, want %q", violations[2].Message, want)
+	}
+}
+
+func TestEvaluateNoThresholds(t *testing.T) {
+	report := Evaluate(newRun(), Thresholds{})
+	if !report.Passed || len(report.Checks) != 0 {
+		t.Errorf("Evaluate() = passed %v with %d checks, want passed with none", report.Passed, len(report.Checks))
+	}
+}
+
+func TestEvaluateEmptyRun(t *testing.T) {
+	minPassRate := 0.0
+	report := Evaluate(&results.Run{}, Thresholds{MinPassRate: &minPassRate})
+	if report.Passed {
+		t.Error("Passed = true for a run without scenarios, want false")
+	}
+}
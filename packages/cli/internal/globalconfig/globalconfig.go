@@ -0,0 +1,135 @@
+// Package globalconfig manages the per-user settings in
+// ~/.sentra-lab/config.yaml, shared by every project on the machine: the
+// telemetry choice and defaults for new projects.
+package globalconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the settings file inside ~/.sentra-lab
+const FileName = "config.yaml"
+
+// Config is the user's settings.
+type Config struct {
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+	Defaults  DefaultsConfig  `yaml:"defaults"`
+}
+
+// TelemetryConfig records the user's telemetry choice. Telemetry is off
+// until the user opts in.
+type TelemetryConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// DecidedAt is when the user answered; zero means they were never asked
+	DecidedAt time.Time `yaml:"decided_at,omitempty"`
+
+	// Endpoint is a self-hosted collector to send events to instead of
+	// Sentra's
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// DefaultsConfig are defaults for new projects.
+type DefaultsConfig struct {
+	// Template is used by `sentra lab init` when --template is not given
+	Template string `yaml:"template"`
+}
+
+// Default returns the settings a fresh install starts with.
+func Default() *Config {
+	return &Config{
+		Defaults: DefaultsConfig{
+			Template: "default",
+		},
+	}
+}
+
+// Path returns the settings file path.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".sentra-lab", FileName), nil
+}
+
+// Load reads the settings, returning the defaults when the file does not
+// exist yet.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the settings, creating ~/.sentra-lab if needed.
+func Save(cfg *Config) (string, error) {
+	path, err := Path()
+	if err != nil {
+		return "", err
+	}
+
+	content, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+
+	header := "# Sentra Lab user settings, shared by all projects.\n" +
+		"# telemetry.enabled: share anonymous usage statistics (opt-in)\n" +
+		"# telemetry.endpoint: self-hosted collector (optional)\n" +
+		"# defaults.template: template for 'sentra lab init'\n"
+	if err := os.WriteFile(path, append([]byte(header), content...), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// TelemetryEnabled reports whether telemetry may be sent: only when the
+// user opted in and neither SENTRA_TELEMETRY=0 nor DO_NOT_TRACK=1 is set.
+func (c *Config) TelemetryEnabled() bool {
+	if value := os.Getenv("SENTRA_TELEMETRY"); value != "" {
+		if enabled, ok := ParseChoice(value); ok && !enabled {
+			return false
+		}
+	}
+	if os.Getenv("DO_NOT_TRACK") == "1" {
+		return false
+	}
+	return c.Telemetry.Enabled
+}
+
+// ParseChoice parses a yes/no answer (yes, y, true, on, 1 and their
+// opposites).
+func ParseChoice(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "true", "on", "1":
+		return true, true
+	case "no", "n", "false", "off", "0":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,147 @@
+package globalconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setHome points the user's home directory at a temp directory.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+func TestLoadDefault(t *testing.T) {
+	setHome(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want the defaults", cfg)
+	}
+	if cfg.Telemetry.Enabled {
+		t.Error("telemetry is enabled by default")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	home := setHome(t)
+
+	cfg := Default()
+	cfg.Telemetry.Enabled = true
+	cfg.Telemetry.DecidedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cfg.Defaults.Template = "fintech"
+
+	path, err := Save(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".sentra-lab", FileName); path != want {
+		t.Errorf("Save() path = %s, want %s", path, want)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "# Sentra Lab user settings") {
+		t.Errorf("saved settings have no header:\n%s", content)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("Load() = %+v, want %+v", loaded, cfg)
+	}
+}
+
+func TestLoadKeepsDefaults(t *testing.T) {
+	home := setHome(t)
+	path := filepath.Join(home, ".sentra-lab", FileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("telemetry:\n  enabled: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Telemetry.Enabled || cfg.Defaults.Template != "default" {
+		t.Errorf("Load() = %+v, want telemetry enabled and the default template", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte("telemetry: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("Load() of invalid YAML error = nil")
+	}
+}
+
+func TestTelemetryEnabled(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		telemetry   string
+		doNotTrack  string
+		wantEnabled bool
+	}{
+		{name: "not opted in"},
+		{name: "opted in", enabled: true, wantEnabled: true},
+		{name: "SENTRA_TELEMETRY=0", enabled: true, telemetry: "0"},
+		{name: "SENTRA_TELEMETRY=off", enabled: true, telemetry: "off"},
+		{name: "SENTRA_TELEMETRY=1 does not opt in", telemetry: "1"},
+		{name: "DO_NOT_TRACK=1", enabled: true, doNotTrack: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SENTRA_TELEMETRY", tt.telemetry)
+			t.Setenv("DO_NOT_TRACK", tt.doNotTrack)
+
+			cfg := Default()
+			cfg.Telemetry.Enabled = tt.enabled
+			if got := cfg.TelemetryEnabled(); got != tt.wantEnabled {
+				t.Errorf("TelemetryEnabled() = %v, want %v", got, tt.wantEnabled)
+			}
+		})
+	}
+}
+
+func TestParseChoice(t *testing.T) {
+	tests := []struct {
+		value    string
+		want, ok bool
+	}{
+		{"yes", true, true},
+		{" Y ", true, true},
+		{"on", true, true},
+		{"1", true, true},
+		{"No", false, true},
+		{"false", false, true},
+		{"0", false, true},
+		{"maybe", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseChoice(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseChoice(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
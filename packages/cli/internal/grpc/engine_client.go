@@ -6,6 +6,10 @@ import (
 	"time"
 )
 
+// EngineClient is the CLI's side of the engine's SimulationEngine API
+// (packages/engine/proto). It does not call the engine yet: its methods
+// return placeholder results until the engine serves the API and the
+// client is ported to the stubs `make proto` generates.
 type EngineClient struct {
 	client *Client
 }
@@ -76,12 +80,46 @@ func generateRunID() string {
 
 type StartSimulationRequest struct {
 	ScenarioPath string
-	Config       SimulationConfig
+	// Variables override the scenario's declared variables (one row of
+	// its variables_from data)
+	Variables map[string]string
+	// ProjectDir is the lab project whose agent and lab.yaml the scenario
+	// runs against (empty: the current directory)
+	ProjectDir string
+	// MockPorts are the ports of the project's mocks, assigned so projects
+	// in a workspace do not collide
+	MockPorts map[string]int
+	// MockURLs are the base URLs of externally managed mocks (`sentra lab
+	// test --target`) the agent is pointed at instead of local ports
+	MockURLs map[string]string
+	Config   SimulationConfig
 }
 
 type SimulationConfig struct {
 	RecordFullTrace    bool
 	EnableCostTracking bool
+	// AgentLimits override lab.yaml's agent.limits when set
+	AgentLimits AgentLimits
+	// Timeouts override lab.yaml's simulation.timeouts when set
+	Timeouts Timeouts
+	// LatencyAllowance is added to latency expectations to offset mocks
+	// running under emulation
+	LatencyAllowance time.Duration
+}
+
+// Timeouts bound a scenario and each of its steps; zero uses the
+// scenario's own timeout, then lab.yaml's.
+type Timeouts struct {
+	Step     time.Duration
+	Scenario time.Duration
+}
+
+// AgentLimits cap the agent process's resources per scenario; zero means
+// no limit.
+type AgentLimits struct {
+	CPUTime  time.Duration
+	MemoryMB int
+	WallTime time.Duration
 }
 
 type SimulationRun struct {
@@ -98,6 +136,41 @@ type SimulationStatus struct {
 	CostUSD    float64
 	Assertions int
 	Failures   []string
+	// Outputs are the scenario's named outputs, evaluated when it finishes
+	Outputs map[string]string
+	// Steps are the token usage of each step's model calls, in order
+	Steps []StepUsage
+	// AgentStartup is how long the engine took to start the agent
+	AgentStartup time.Duration
+	// AssertionEval is the time spent evaluating the scenario's assertions
+	AssertionEval time.Duration
+	// Findings are the violations assert_guardrails steps found
+	Findings []GuardrailFinding
+	// TimedOut is the timeout that ended the run, "step" or "scenario"
+	// (empty if none)
+	TimedOut string
+}
+
+// GuardrailFinding is a violation found by an assert_guardrails step.
+type GuardrailFinding struct {
+	// Check is canary_leak, secret_leak, disallowed_tool or prompt_injection
+	Check string
+	// Location is "output" or "tool_call:<name>"
+	Location string
+	// Detail is what was found, with secrets redacted
+	Detail string
+	// Step is the ID of the step that found it
+	Step string
+}
+
+// StepUsage is the tokens a scenario step's model calls used.
+type StepUsage struct {
+	Step             string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	// ContextWindow is the model's context window (0 when unknown)
+	ContextWindow int
 }
 
 type RunSummary struct {
@@ -0,0 +1,234 @@
+package impact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CachePath is where the inputs of passing scenarios are kept
+const CachePath = ".sentra-lab/results/cache.json"
+
+// CacheEntry is a passing test case and the inputs it passed with.
+type CacheEntry struct {
+	// Inputs is the hash of the case's inputs (see Hasher)
+	Inputs string `json:"inputs"`
+
+	// RunID is the run that passed
+	RunID    string    `json:"run_id"`
+	PassedAt time.Time `json:"passed_at"`
+
+	// Outputs are the scenario's named outputs, handed to dependents when
+	// the case is skipped as a cached pass
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// Cache holds passing test cases, by case name.
+type Cache struct {
+	Cases map[string]CacheEntry `json:"cases"`
+}
+
+// LoadCache reads the result cache; a missing file is an empty cache.
+func LoadCache(path string) (*Cache, error) {
+	cache := &Cache{Cases: make(map[string]CacheEntry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return nil, fmt.Errorf("failed to read result cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse result cache %s: %w", path, err)
+	}
+	if cache.Cases == nil {
+		cache.Cases = make(map[string]CacheEntry)
+	}
+	return cache, nil
+}
+
+// Save writes the cache to path, creating its directory.
+func (c *Cache) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write result cache: %w", err)
+	}
+	return nil
+}
+
+// Hit returns the cache entry of a case whose inputs are unchanged.
+func (c *Cache) Hit(name, inputs string) (CacheEntry, bool) {
+	entry, ok := c.Cases[name]
+	if !ok || inputs == "" || entry.Inputs != inputs {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Hasher hashes the inputs of scenarios in the project in the current
+// directory: the scenario and its data file, the lab.yaml sections that
+// apply to it, the fixtures of the mocks it called, and the agent's code.
+type Hasher struct {
+	// config is lab.yaml, parsed
+	config map[string]interface{}
+
+	// mockFiles are the files lab.yaml configures each mock with
+	mockFiles map[string][]string
+
+	// fixtures and agent hash the fixtures/ directory and the agent code
+	fixtures string
+	agent    string
+}
+
+// NewHasher reads lab.yaml and hashes the files scenarios share. The agent
+// code is the entry point's directory as git sees it (tracked and
+// untracked files, minus ignored ones, scenarios and fixtures).
+func NewHasher(configPath string) (*Hasher, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+
+	h := &Hasher{mockFiles: make(map[string][]string)}
+	if err := yaml.Unmarshal(data, &h.config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+
+	if mocks, ok := h.config["mocks"].(map[string]interface{}); ok {
+		for mock, settings := range mocks {
+			collectPaths(settings, func(path string) {
+				h.mockFiles[mock] = append(h.mockFiles[mock], normalize(path))
+			})
+		}
+	}
+
+	agentDir := "."
+	if agent, ok := h.config["agent"].(map[string]interface{}); ok {
+		if entryPoint, ok := agent["entry_point"].(string); ok && entryPoint != "" {
+			agentDir = filepath.Dir(entryPoint)
+		}
+	}
+
+	listed, err := git("ls-files", "--cached", "--others", "--exclude-standard", "--", agentDir)
+	if err != nil {
+		return nil, err
+	}
+	var agentFiles []string
+	for _, file := range lines(listed) {
+		if isAgentFile(file, normalize(agentDir)) && !isFixture(file) {
+			agentFiles = append(agentFiles, file)
+		}
+	}
+	if h.agent, err = hashFiles(agentFiles); err != nil {
+		return nil, err
+	}
+
+	var fixtures []string
+	err = filepath.WalkDir("fixtures", func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			fixtures = append(fixtures, normalize(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if h.fixtures, err = hashFiles(fixtures); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Hash returns the hash of a scenario's inputs, given the footprint of its
+// last run.
+func (h *Hasher) Hash(footprint Footprint) (string, error) {
+	digest := sha256.New()
+
+	// lab.yaml, minus sections no outcome depends on and mocks not called
+	config := make(map[string]interface{})
+	for key, value := range h.config {
+		if !unimpactfulSections[key] && key != "mocks" {
+			config[key] = value
+		}
+	}
+	mocks, _ := h.config["mocks"].(map[string]interface{})
+	used := make(map[string]interface{})
+	var mockFiles []string
+	for _, mock := range footprint.Mocks {
+		used[mock] = mocks[mock]
+		mockFiles = append(mockFiles, h.mockFiles[mock]...)
+	}
+	config["mocks"] = used
+
+	// encoding/json sorts map keys, so equal configs encode the same
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash lab.yaml: %w", err)
+	}
+	fmt.Fprintf(digest, "config %x\n", sha256.Sum256(encoded))
+	fmt.Fprintf(digest, "agent %s\n", h.agent)
+
+	if len(footprint.Mocks) > 0 {
+		fmt.Fprintf(digest, "fixtures %s\n", h.fixtures)
+	}
+
+	files, err := hashFiles(append(footprint.Files, mockFiles...))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(digest, "files %s\n", files)
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// hashFiles hashes the names and contents of files, in sorted order. A
+// missing file hashes as missing rather than failing.
+func hashFiles(files []string) (string, error) {
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+
+	digest := sha256.New()
+	for i, file := range sorted {
+		if i > 0 && file == sorted[i-1] {
+			continue
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				fmt.Fprintf(digest, "%s missing\n", file)
+				continue
+			}
+			return "", fmt.Errorf("failed to hash %s: %w", file, err)
+		}
+
+		content := sha256.New()
+		_, err = io.Copy(content, f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", file, err)
+		}
+		fmt.Fprintf(digest, "%s %x\n", strings.ReplaceAll(file, "\n", " "), content.Sum(nil))
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
@@ -0,0 +1,121 @@
+package impact
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheSaveLoadAndHit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results", "cache.json")
+
+	cache, err := LoadCache(path)
+	if err != nil {
+		t.Fatalf("LoadCache() error = %v", err)
+	}
+	cache.Cases["scenarios/refund.yaml"] = CacheEntry{
+		Inputs:   "abc",
+		RunID:    "run_1",
+		PassedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Outputs:  map[string]string{"refund_id": "re_1"},
+	}
+	if err := cache.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadCache(path)
+	if err != nil {
+		t.Fatalf("LoadCache() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		caseID  string
+		inputs  string
+		wantHit bool
+	}{
+		{name: "unchanged inputs", caseID: "scenarios/refund.yaml", inputs: "abc", wantHit: true},
+		{name: "changed inputs", caseID: "scenarios/refund.yaml", inputs: "def"},
+		{name: "no inputs", caseID: "scenarios/refund.yaml", inputs: ""},
+		{name: "unknown case", caseID: "scenarios/chat.yaml", inputs: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, hit := loaded.Hit(tt.caseID, tt.inputs)
+			if hit != tt.wantHit {
+				t.Fatalf("Hit(%q, %q) = %v, want %v", tt.caseID, tt.inputs, hit, tt.wantHit)
+			}
+			if hit && (entry.RunID != "run_1" || entry.Outputs["refund_id"] != "re_1") {
+				t.Errorf("Hit() = %+v, want the saved entry", entry)
+			}
+		})
+	}
+}
+
+func TestHasherHash(t *testing.T) {
+	labYAML := `name: demo
+agent:
+  entry_point: agent/main.py
+mocks:
+  openai:
+    fixtures: fixtures/openai.yaml
+  stripe:
+    enabled: true
+`
+	files := map[string]string{
+		"lab.yaml":              labYAML,
+		"agent/main.py":         "print('hi')\n",
+		"fixtures/openai.yaml":  "responses: []\n",
+		"fixtures/shared.yaml":  "shared: true\n",
+		"scenarios/refund.yaml": "name: Refund\n",
+		"scenarios/chat.yaml":   "name: Chat\n",
+	}
+	offline := Footprint{Files: []string{"scenarios/refund.yaml"}}
+	chat := Footprint{Mocks: []string{"openai"}, Files: []string{"scenarios/chat.yaml"}}
+
+	tests := []struct {
+		name        string
+		edit        map[string]string
+		footprint   Footprint
+		wantChanged bool
+	}{
+		{name: "nothing changed", footprint: chat},
+		{name: "scenario changed", edit: map[string]string{"scenarios/chat.yaml": "name: Chat 2\n"}, footprint: chat, wantChanged: true},
+		{name: "other scenario changed", edit: map[string]string{"scenarios/refund.yaml": "name: Refund 2\n"}, footprint: chat},
+		{name: "configured fixture changed", edit: map[string]string{"fixtures/openai.yaml": "responses: [1]\n"}, footprint: chat, wantChanged: true},
+		{name: "fixture of a mock not called", edit: map[string]string{"fixtures/shared.yaml": "shared: false\n"}, footprint: offline},
+		{name: "agent changed", edit: map[string]string{"agent/main.py": "print('hello')\n"}, footprint: offline, wantChanged: true},
+		{name: "unimpactful section changed", edit: map[string]string{"lab.yaml": "name: renamed\n" + labYAML[len("name: demo\n"):]}, footprint: chat},
+		{name: "mock not called changed", edit: map[string]string{"lab.yaml": labYAML + "    port: 9000\n"}, footprint: chat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initRepo(t, files)
+
+			before, err := NewHasher("lab.yaml")
+			if err != nil {
+				t.Fatalf("NewHasher() error = %v", err)
+			}
+			beforeHash, err := before.Hash(tt.footprint)
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+
+			writeFiles(t, tt.edit)
+			after, err := NewHasher("lab.yaml")
+			if err != nil {
+				t.Fatalf("NewHasher() error = %v", err)
+			}
+			afterHash, err := after.Hash(tt.footprint)
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+
+			if changed := afterHash != beforeHash; changed != tt.wantChanged {
+				t.Errorf("hash changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package impact
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Change is what changed in a lab project since a git revision.
+type Change struct {
+	// Since is the revision compared against
+	Since string
+
+	// Files are the changed files (committed since Since, staged,
+	// unstaged or untracked), relative to the project directory
+	Files []string
+
+	// Sections are the lab.yaml sections that differ: "mocks.<name>" for
+	// a mock, the top-level key otherwise (e.g. "agent", "simulation")
+	Sections []string
+
+	// MockFiles maps files lab.yaml configures a mock with (fixtures,
+	// models_file, ...) to the mock
+	MockFiles map[string]string
+
+	// AgentDir is the directory of the agent's entry point
+	AgentDir string
+}
+
+// ChangedSince compares the project in the current directory, whose config
+// is configPath, against git revision rev.
+func ChangedSince(rev, configPath string) (*Change, error) {
+	change := &Change{Since: rev, MockFiles: make(map[string]string)}
+
+	diffed, err := git("diff", "--name-only", "--relative", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	change.Files = union(lines(diffed), lines(untracked))
+
+	current, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	var now map[string]interface{}
+	if err := yaml.Unmarshal(current, &now); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+
+	// A lab.yaml that did not exist at rev changes every section
+	var before map[string]interface{}
+	if previous, err := git("show", fmt.Sprintf("%s:./%s", rev, filepath.ToSlash(configPath))); err == nil {
+		if err := yaml.Unmarshal(previous, &before); err != nil {
+			return nil, fmt.Errorf("failed to parse %s at %s: %w", configPath, rev, err)
+		}
+	}
+	change.Sections = changedSections(before, now)
+
+	if mocks, ok := now["mocks"].(map[string]interface{}); ok {
+		for mock, settings := range mocks {
+			collectPaths(settings, func(path string) {
+				change.MockFiles[normalize(path)] = mock
+			})
+		}
+	}
+
+	if agent, ok := now["agent"].(map[string]interface{}); ok {
+		if entryPoint, ok := agent["entry_point"].(string); ok && entryPoint != "" {
+			change.AgentDir = normalize(filepath.Dir(entryPoint))
+		}
+	}
+
+	return change, nil
+}
+
+// changedSections compares two parsed lab.yaml documents.
+func changedSections(before, now map[string]interface{}) []string {
+	var sections []string
+	for _, key := range keys(before, now) {
+		if key != "mocks" {
+			if !reflect.DeepEqual(before[key], now[key]) {
+				sections = append(sections, key)
+			}
+			continue
+		}
+
+		beforeMocks, _ := before["mocks"].(map[string]interface{})
+		nowMocks, _ := now["mocks"].(map[string]interface{})
+		for _, mock := range keys(beforeMocks, nowMocks) {
+			if !reflect.DeepEqual(beforeMocks[mock], nowMocks[mock]) {
+				sections = append(sections, "mocks."+mock)
+			}
+		}
+	}
+	return sections
+}
+
+func keys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, key)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// collectPaths calls fn with every string in value that looks like a
+// relative file path (e.g. "fixtures/openai-responses.yaml").
+func collectPaths(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, "/") && filepath.Ext(v) != "" && !strings.Contains(v, "://") {
+			fn(v)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectPaths(item, fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectPaths(item, fn)
+		}
+	}
+}
+
+func git(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], message)
+	}
+	return out, nil
+}
+
+func lines(out []byte) []string {
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, normalize(line))
+		}
+	}
+	return files
+}
@@ -0,0 +1,131 @@
+package impact
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestChangedSince(t *testing.T) {
+	initRepo(t, map[string]string{
+		"lab.yaml": `name: demo
+agent:
+  entry_point: agent/main.py
+simulation:
+  timeout: 5m
+mocks:
+  openai:
+    enabled: true
+    fixtures: fixtures/openai.yaml
+  stripe:
+    enabled: true
+`,
+		"agent/main.py":         "print('hi')\n",
+		"scenarios/refund.yaml": "name: Refund\n",
+	})
+
+	writeFiles(t, map[string]string{
+		"lab.yaml": `name: demo renamed
+agent:
+  entry_point: agent/main.py
+simulation:
+  timeout: 10m
+mocks:
+  openai:
+    enabled: true
+    fixtures: fixtures/openai.yaml
+  stripe:
+    enabled: false
+`,
+		"agent/main.py":      "print('hello')\n",
+		"scenarios/new.yaml": "name: New\n",
+	})
+
+	change, err := ChangedSince("HEAD", "lab.yaml")
+	if err != nil {
+		t.Fatalf("ChangedSince() error = %v", err)
+	}
+
+	if want := []string{"agent/main.py", "lab.yaml", "scenarios/new.yaml"}; !reflect.DeepEqual(change.Files, want) {
+		t.Errorf("Files = %v, want %v", change.Files, want)
+	}
+	sort.Strings(change.Sections)
+	if want := []string{"mocks.stripe", "name", "simulation"}; !reflect.DeepEqual(change.Sections, want) {
+		t.Errorf("Sections = %v, want %v", change.Sections, want)
+	}
+	if want := map[string]string{"fixtures/openai.yaml": "openai"}; !reflect.DeepEqual(change.MockFiles, want) {
+		t.Errorf("MockFiles = %v, want %v", change.MockFiles, want)
+	}
+	if change.AgentDir != "agent" {
+		t.Errorf("AgentDir = %q, want agent", change.AgentDir)
+	}
+}
+
+func TestChangedSinceUnknownRevision(t *testing.T) {
+	initRepo(t, map[string]string{"lab.yaml": "name: demo\n"})
+
+	if _, err := ChangedSince("no-such-rev", "lab.yaml"); err == nil {
+		t.Error("ChangedSince() error = nil, want an unknown revision error")
+	}
+}
+
+func TestChangedSections(t *testing.T) {
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		now    map[string]interface{}
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			before: map[string]interface{}{"agent": map[string]interface{}{"runtime": "python"}},
+			now:    map[string]interface{}{"agent": map[string]interface{}{"runtime": "python"}},
+		},
+		{
+			name:   "new lab.yaml",
+			before: nil,
+			now:    map[string]interface{}{"agent": "x", "mocks": map[string]interface{}{"openai": true}},
+			want:   []string{"agent", "mocks.openai"},
+		},
+		{
+			name:   "removed section",
+			before: map[string]interface{}{"egress": map[string]interface{}{"enabled": true}},
+			now:    map[string]interface{}{},
+			want:   []string{"egress"},
+		},
+		{
+			name:   "one mock changed",
+			before: map[string]interface{}{"mocks": map[string]interface{}{"openai": 1, "stripe": 1}},
+			now:    map[string]interface{}{"mocks": map[string]interface{}{"openai": 1, "stripe": 2}},
+			want:   []string{"mocks.stripe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changedSections(tt.before, tt.now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedSections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectPaths(t *testing.T) {
+	settings := map[string]interface{}{
+		"fixtures":    "fixtures/openai.yaml",
+		"models_file": "config/models.json",
+		"base_url":    "http://example.com/v1/api.json",
+		"mode":        "strict",
+		"files":       []interface{}{"fixtures/a.json", "README"},
+		"nested":      map[string]interface{}{"path": "data/rows.csv"},
+	}
+
+	var got []string
+	collectPaths(settings, func(path string) { got = append(got, path) })
+	sort.Strings(got)
+
+	want := []string{"config/models.json", "data/rows.csv", "fixtures/a.json", "fixtures/openai.yaml"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectPaths() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+// Package impact selects the scenarios a change can affect, so CI runs only
+// those (`sentra lab test --changed-since HEAD~1`). After every run it
+// records each scenario's footprint: the mocks its recording shows it
+// calling, and the files it reads. A changed fixture or lab.yaml mock
+// section then selects only the scenarios that called that mock. The same
+// footprints decide which inputs a passing scenario is cached against
+// (`sentra lab test --cache`).
+package impact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+)
+
+// MapPath is where scenario footprints are kept between runs
+const MapPath = ".sentra-lab/results/impact.json"
+
+// Footprint is what a scenario depended on when it last ran.
+type Footprint struct {
+	// Mocks are the mock services the scenario called (e.g. "openai")
+	Mocks []string `json:"mocks"`
+
+	// Files are the files the scenario reads: itself and its
+	// variables_from data
+	Files []string `json:"files"`
+
+	// RunID is the run the footprint was taken from
+	RunID      string    `json:"run_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Map holds every scenario's footprint, by scenario path.
+type Map struct {
+	Scenarios map[string]Footprint `json:"scenarios"`
+}
+
+// Load reads the footprints saved by earlier runs; a missing file is an
+// empty map.
+func Load(path string) (*Map, error) {
+	m := &Map{Scenarios: make(map[string]Footprint)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to read impact map: %w", err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse impact map %s: %w", path, err)
+	}
+	if m.Scenarios == nil {
+		m.Scenarios = make(map[string]Footprint)
+	}
+	return m, nil
+}
+
+// Save writes the map to path, creating its directory.
+func (m *Map) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode impact map: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write impact map: %w", err)
+	}
+	return nil
+}
+
+// Footprint returns a scenario's recorded footprint.
+func (m *Map) Footprint(scenario string) (Footprint, bool) {
+	footprint, ok := m.Scenarios[normalize(scenario)]
+	return footprint, ok
+}
+
+// Record replaces a scenario's footprint.
+func (m *Map) Record(scenario string, footprint Footprint) {
+	for i, file := range footprint.Files {
+		footprint.Files[i] = normalize(file)
+	}
+	m.Scenarios[normalize(scenario)] = footprint
+}
+
+// Merge combines footprints of the same scenario from one run, such as the
+// rows of a data-driven scenario.
+func (f Footprint) Merge(other Footprint) Footprint {
+	f.Mocks = union(f.Mocks, other.Mocks)
+	f.Files = union(f.Files, other.Files)
+	return f
+}
+
+// MocksCalled returns the mock services a run's recording shows calls to.
+func MocksCalled(recording *grpc.Recording) []string {
+	seen := make(map[string]bool)
+	for _, event := range recording.Events {
+		if event.Type == "external_call_made" && event.Service != "" {
+			seen[event.Service] = true
+		}
+	}
+
+	mocks := make([]string, 0, len(seen))
+	for mock := range seen {
+		mocks = append(mocks, mock)
+	}
+	sort.Strings(mocks)
+	return mocks
+}
+
+func union(a, b []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, items := range [][]string{a, b} {
+		for _, item := range items {
+			if !seen[item] {
+				seen[item] = true
+				out = append(out, item)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// normalize makes a path comparable with the paths git reports.
+func normalize(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
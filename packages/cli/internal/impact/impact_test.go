@@ -0,0 +1,125 @@
+package impact
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+)
+
+// initRepo makes a temporary directory holding files the working directory
+// and commits them to a new git repository.
+func initRepo(t *testing.T, files map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	t.Chdir(t.TempDir())
+	writeFiles(t, files)
+	runGit(t, "init", "-q")
+	runGit(t, "add", "-A")
+	runGit(t, "commit", "-q", "-m", "initial")
+}
+
+// writeFiles writes files relative to the working directory.
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// runGit runs git in the working directory.
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestMapSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results", "impact.json")
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(m.Scenarios) != 0 {
+		t.Fatalf("Load() of a missing file = %v, want an empty map", m.Scenarios)
+	}
+
+	recordedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m.Record("./scenarios/../scenarios/refund.yaml", Footprint{
+		Mocks:      []string{"stripe"},
+		Files:      []string{"./scenarios/refund.yaml", "data//customers.csv"},
+		RunID:      "run_1",
+		RecordedAt: recordedAt,
+	})
+	if err := m.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	footprint, ok := loaded.Footprint("scenarios/refund.yaml")
+	if !ok {
+		t.Fatalf("Footprint() found no footprint in %v", loaded.Scenarios)
+	}
+	want := Footprint{
+		Mocks:      []string{"stripe"},
+		Files:      []string{"scenarios/refund.yaml", "data/customers.csv"},
+		RunID:      "run_1",
+		RecordedAt: recordedAt,
+	}
+	if !reflect.DeepEqual(footprint, want) {
+		t.Errorf("Footprint() = %+v, want %+v", footprint, want)
+	}
+}
+
+func TestLoadInvalidMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "impact.json")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() error = nil, want a parse error")
+	}
+}
+
+func TestFootprintMerge(t *testing.T) {
+	a := Footprint{Mocks: []string{"stripe"}, Files: []string{"scenarios/a.yaml"}, RunID: "run_1"}
+	b := Footprint{Mocks: []string{"openai", "stripe"}, Files: []string{"data/rows.csv", "scenarios/a.yaml"}, RunID: "run_2"}
+
+	want := Footprint{Mocks: []string{"openai", "stripe"}, Files: []string{"data/rows.csv", "scenarios/a.yaml"}, RunID: "run_1"}
+	if got := a.Merge(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMocksCalled(t *testing.T) {
+	recording := &grpc.Recording{Events: []*grpc.Event{
+		{Type: "external_call_made", Service: "stripe"},
+		{Type: "external_call_completed", Service: "coreledger"},
+		{Type: "external_call_made", Service: "openai"},
+		{Type: "external_call_made", Service: "stripe"},
+		{Type: "external_call_made"},
+	}}
+
+	want := []string{"openai", "stripe"}
+	if got := MocksCalled(recording); !reflect.DeepEqual(got, want) {
+		t.Errorf("MocksCalled() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package impact
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// unimpactfulSections are lab.yaml sections no scenario's outcome depends on
+var unimpactfulSections = map[string]bool{
+	"name":          true,
+	"version":       true,
+	"storage":       true,
+	"notifications": true,
+}
+
+// Selection is the outcome of Select.
+type Selection struct {
+	// Selected are the impacted scenarios, in the order given
+	Selected []string
+
+	// Reasons say why each selected scenario was picked
+	Reasons map[string]string
+
+	// Skipped are the scenarios the change cannot affect
+	Skipped []string
+}
+
+// Select returns the scenarios a change can affect. Scenarios without a
+// recorded footprint are always selected. dependsOn maps scenarios to their
+// prerequisites: a dependent of a selected scenario is selected too, since
+// the outputs it receives may change.
+func (m *Map) Select(scenarios []string, change *Change, dependsOn map[string][]string) *Selection {
+	selection := &Selection{Reasons: make(map[string]string)}
+
+	everything := m.globalReason(change, scenarios)
+	changedMocks, anyMock := m.changedMocks(change)
+	changed := make(map[string]bool)
+	for _, file := range change.Files {
+		changed[file] = true
+	}
+
+	reasons := make(map[string]string)
+	for _, scenario := range scenarios {
+		if reason := m.reason(normalize(scenario), everything, changedMocks, anyMock, changed); reason != "" {
+			reasons[scenario] = reason
+		}
+	}
+
+	// Dependents of selected scenarios, until nothing new is added
+	for added := true; added; {
+		added = false
+		for _, scenario := range scenarios {
+			if reasons[scenario] != "" {
+				continue
+			}
+			for _, prerequisite := range dependsOn[scenario] {
+				if reasons[prerequisite] != "" {
+					reasons[scenario] = fmt.Sprintf("depends on %s", prerequisite)
+					added = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, scenario := range scenarios {
+		if reason := reasons[scenario]; reason != "" {
+			selection.Selected = append(selection.Selected, scenario)
+			selection.Reasons[scenario] = reason
+		} else {
+			selection.Skipped = append(selection.Skipped, scenario)
+		}
+	}
+	return selection
+}
+
+// reason says why a scenario is impacted ("" if it is not).
+func (m *Map) reason(scenario, everything string, changedMocks map[string]string, anyMock string, changed map[string]bool) string {
+	if everything != "" {
+		return everything
+	}
+	if changed[scenario] {
+		return "scenario changed"
+	}
+
+	footprint, ok := m.Scenarios[scenario]
+	if !ok {
+		return "no previous run recorded"
+	}
+
+	for _, file := range footprint.Files {
+		if changed[file] {
+			return fmt.Sprintf("%s changed", file)
+		}
+	}
+	for _, mock := range footprint.Mocks {
+		if reason := changedMocks[mock]; reason != "" {
+			return reason
+		}
+	}
+	if anyMock != "" && len(footprint.Mocks) > 0 {
+		return anyMock
+	}
+	return ""
+}
+
+// globalReason says why every scenario is impacted: a lab.yaml section
+// all scenarios share changed (agent, simulation, egress, ...), or an agent
+// file did. The recordings show which mocks a scenario called but not which
+// agent code it ran, so any agent change selects everything.
+func (m *Map) globalReason(change *Change, scenarios []string) string {
+	for _, section := range change.Sections {
+		if !strings.HasPrefix(section, "mocks.") && !unimpactfulSections[section] {
+			return fmt.Sprintf("lab.yaml %s changed", section)
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, scenario := range scenarios {
+		known[normalize(scenario)] = true
+	}
+	for _, footprint := range m.Scenarios {
+		for _, file := range footprint.Files {
+			known[file] = true
+		}
+	}
+
+	for _, file := range change.Files {
+		if known[file] || change.MockFiles[file] != "" || isFixture(file) || !isAgentFile(file, change.AgentDir) {
+			continue
+		}
+		return fmt.Sprintf("agent file %s changed", file)
+	}
+	return ""
+}
+
+// changedMocks returns a reason per mock whose lab.yaml section or
+// configured files changed, and a reason for every mock when a fixture no
+// mock is configured with changed (mocks mount the whole fixtures/ dir).
+func (m *Map) changedMocks(change *Change) (map[string]string, string) {
+	mocks := make(map[string]string)
+	for _, section := range change.Sections {
+		if mock, ok := strings.CutPrefix(section, "mocks."); ok {
+			mocks[mock] = fmt.Sprintf("lab.yaml %s changed", section)
+		}
+	}
+
+	anyMock := ""
+	for _, file := range change.Files {
+		if mock := change.MockFiles[file]; mock != "" {
+			if mocks[mock] == "" {
+				mocks[mock] = fmt.Sprintf("%s fixture %s changed", mock, file)
+			}
+		} else if isFixture(file) && anyMock == "" {
+			anyMock = fmt.Sprintf("fixture %s changed", file)
+		}
+	}
+	return mocks, anyMock
+}
+
+// isAgentFile reports whether a changed file may be part of the agent:
+// anything under the entry point's directory except lab files and docs.
+func isAgentFile(file, agentDir string) bool {
+	if agentDir != "" && agentDir != "." && file != agentDir && !strings.HasPrefix(file, agentDir+"/") {
+		return false
+	}
+
+	switch {
+	case file == "lab.yaml", strings.HasPrefix(file, ".sentra-lab/"), strings.HasPrefix(file, "scenarios/"):
+		return false
+	case strings.EqualFold(path.Ext(file), ".md"):
+		return false
+	}
+	return true
+}
+
+func isFixture(file string) bool {
+	return strings.HasPrefix(file, "fixtures/")
+}
@@ -0,0 +1,158 @@
+package impact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapSelect(t *testing.T) {
+	m := &Map{Scenarios: map[string]Footprint{
+		"scenarios/chat.yaml":    {Mocks: []string{"openai"}, Files: []string{"scenarios/chat.yaml"}},
+		"scenarios/refund.yaml":  {Mocks: []string{"stripe"}, Files: []string{"scenarios/refund.yaml", "data/refunds.csv"}},
+		"scenarios/offline.yaml": {Files: []string{"scenarios/offline.yaml"}},
+	}}
+	scenarios := []string{"scenarios/chat.yaml", "scenarios/refund.yaml", "scenarios/offline.yaml", "scenarios/new.yaml"}
+
+	tests := []struct {
+		name      string
+		change    Change
+		dependsOn map[string][]string
+		want      map[string]string
+	}{
+		{
+			name:   "nothing changed",
+			change: Change{},
+			want:   map[string]string{"scenarios/new.yaml": "no previous run recorded"},
+		},
+		{
+			name:   "scenario changed",
+			change: Change{Files: []string{"scenarios/chat.yaml"}},
+			want: map[string]string{
+				"scenarios/chat.yaml": "scenario changed",
+				"scenarios/new.yaml":  "no previous run recorded",
+			},
+		},
+		{
+			name:   "data file changed",
+			change: Change{Files: []string{"data/refunds.csv"}},
+			want: map[string]string{
+				"scenarios/refund.yaml": "data/refunds.csv changed",
+				"scenarios/new.yaml":    "no previous run recorded",
+			},
+		},
+		{
+			name:   "mock section changed",
+			change: Change{Sections: []string{"mocks.openai"}},
+			want: map[string]string{
+				"scenarios/chat.yaml": "lab.yaml mocks.openai changed",
+				"scenarios/new.yaml":  "no previous run recorded",
+			},
+		},
+		{
+			name: "configured fixture changed",
+			change: Change{
+				Files:     []string{"fixtures/stripe.yaml"},
+				MockFiles: map[string]string{"fixtures/stripe.yaml": "stripe"},
+			},
+			want: map[string]string{
+				"scenarios/refund.yaml": "stripe fixture fixtures/stripe.yaml changed",
+				"scenarios/new.yaml":    "no previous run recorded",
+			},
+		},
+		{
+			name:   "shared fixture changed",
+			change: Change{Files: []string{"fixtures/shared.yaml"}},
+			want: map[string]string{
+				"scenarios/chat.yaml":   "fixture fixtures/shared.yaml changed",
+				"scenarios/refund.yaml": "fixture fixtures/shared.yaml changed",
+				"scenarios/new.yaml":    "no previous run recorded",
+			},
+		},
+		{
+			name:   "shared section changed",
+			change: Change{Sections: []string{"simulation"}},
+			want: map[string]string{
+				"scenarios/chat.yaml":    "lab.yaml simulation changed",
+				"scenarios/refund.yaml":  "lab.yaml simulation changed",
+				"scenarios/offline.yaml": "lab.yaml simulation changed",
+				"scenarios/new.yaml":     "lab.yaml simulation changed",
+			},
+		},
+		{
+			name:   "unimpactful section changed",
+			change: Change{Sections: []string{"notifications", "storage"}},
+			want:   map[string]string{"scenarios/new.yaml": "no previous run recorded"},
+		},
+		{
+			name:   "agent file changed",
+			change: Change{Files: []string{"agent/tools.py"}, AgentDir: "agent"},
+			want: map[string]string{
+				"scenarios/chat.yaml":    "agent file agent/tools.py changed",
+				"scenarios/refund.yaml":  "agent file agent/tools.py changed",
+				"scenarios/offline.yaml": "agent file agent/tools.py changed",
+				"scenarios/new.yaml":     "agent file agent/tools.py changed",
+			},
+		},
+		{
+			name:   "file outside the agent changed",
+			change: Change{Files: []string{"docs/guide.txt", "agent/README.md"}, AgentDir: "agent"},
+			want:   map[string]string{"scenarios/new.yaml": "no previous run recorded"},
+		},
+		{
+			name:      "dependents of selected scenarios",
+			change:    Change{Files: []string{"scenarios/chat.yaml"}},
+			dependsOn: map[string][]string{"scenarios/offline.yaml": {"scenarios/refund.yaml"}, "scenarios/refund.yaml": {"scenarios/chat.yaml"}},
+			want: map[string]string{
+				"scenarios/chat.yaml":    "scenario changed",
+				"scenarios/refund.yaml":  "depends on scenarios/chat.yaml",
+				"scenarios/offline.yaml": "depends on scenarios/refund.yaml",
+				"scenarios/new.yaml":     "no previous run recorded",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection := m.Select(scenarios, &tt.change, tt.dependsOn)
+
+			if !reflect.DeepEqual(selection.Reasons, tt.want) {
+				t.Errorf("Reasons = %v, want %v", selection.Reasons, tt.want)
+			}
+			var wantSelected, wantSkipped []string
+			for _, scenario := range scenarios {
+				if tt.want[scenario] != "" {
+					wantSelected = append(wantSelected, scenario)
+				} else {
+					wantSkipped = append(wantSkipped, scenario)
+				}
+			}
+			if !reflect.DeepEqual(selection.Selected, wantSelected) || !reflect.DeepEqual(selection.Skipped, wantSkipped) {
+				t.Errorf("Selected = %v, Skipped = %v, want %v, %v", selection.Selected, selection.Skipped, wantSelected, wantSkipped)
+			}
+		})
+	}
+}
+
+func TestIsAgentFile(t *testing.T) {
+	tests := []struct {
+		file     string
+		agentDir string
+		want     bool
+	}{
+		{file: "agent.py", agentDir: ".", want: true},
+		{file: "agent/tools.py", agentDir: "agent", want: true},
+		{file: "other/tools.py", agentDir: "agent"},
+		{file: "lab.yaml", agentDir: "."},
+		{file: "scenarios/refund.yaml", agentDir: "."},
+		{file: ".sentra-lab/results/impact.json", agentDir: ""},
+		{file: "README.MD", agentDir: "."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := isAgentFile(tt.file, tt.agentDir); got != tt.want {
+				t.Errorf("isAgentFile(%q, %q) = %v, want %v", tt.file, tt.agentDir, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Package k8s renders Kubernetes manifests for a shared mock environment:
+// the enabled mocks from lab.yaml, backed by one Redis so every replica
+// (and every CI job pointed at them) sees the same state. Apply the output
+// with kubectl; the CLI never talks to a cluster itself.
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// DefaultNamespace is the namespace used without --namespace
+const DefaultNamespace = "sentra"
+
+// RedisImage is the image of the shared state store
+const RedisImage = "redis:7-alpine"
+
+// mockPort is the port every mock listens on inside its container
+const mockPort = 8080
+
+// maxConfigMapBytes is the most a ConfigMap may hold
+const maxConfigMapBytes = 1 << 20
+
+// Options configure the rendered environment.
+type Options struct {
+	// Namespace the resources are created in
+	Namespace string
+
+	// Replicas of each mock (shared state makes more than one safe)
+	Replicas int
+
+	// Images are the mocks' default images, by mock name; mocks.<name>.image
+	// and image_digest override them
+	Images map[string]string
+
+	// FixturesDir is packaged into a ConfigMap mounted at /fixtures ("":
+	// the mocks run without fixtures)
+	FixturesDir string
+}
+
+// object is a Kubernetes resource.
+type object = map[string]interface{}
+
+// Render returns the manifests (a multi-document YAML stream) for cfg's
+// enabled mocks.
+func Render(cfg *config.Config, opts Options) ([]byte, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.Replicas < 1 {
+		opts.Replicas = 1
+	}
+
+	names := make([]string, 0, len(cfg.Mocks))
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no mocks are enabled in lab.yaml")
+	}
+
+	objects := []object{
+		{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   object{"name": opts.Namespace, "labels": labels("")},
+		},
+	}
+	objects = append(objects, redis(opts.Namespace)...)
+
+	var fixtureItems []object
+	if opts.FixturesDir != "" {
+		fixtures, items, err := fixturesConfigMap(opts.Namespace, opts.FixturesDir)
+		if err != nil {
+			return nil, err
+		}
+		if fixtures != nil {
+			objects = append(objects, fixtures)
+			fixtureItems = items
+		}
+	}
+
+	for _, name := range names {
+		mock := cfg.Mocks[name]
+		image := mock.PinnedImage(opts.Images[name])
+		if image == "" {
+			return nil, fmt.Errorf("mocks.%s: no image (set mocks.%s.image)", name, name)
+		}
+		objects = append(objects,
+			mockDeployment(opts, name, mock, image, fixtureItems),
+			service(opts.Namespace, "mock-"+name, mock.Port, mockPort),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Rendered by sentra lab deploy k8s; apply with: kubectl apply -f <file>\n")
+	buf.WriteString("# Point agents and CI at:\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "#   %s: %s\n", name, ServiceURL(opts.Namespace, name, cfg.Mocks[name].Port))
+	}
+
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", obj["kind"], err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// ServiceURL is a mock's base URL inside the cluster.
+func ServiceURL(namespace, mock string, port int) string {
+	return fmt.Sprintf("http://mock-%s.%s.svc.cluster.local:%d", mock, namespace, port)
+}
+
+// labels identifies the environment's resources, and a component of it.
+func labels(component string) map[string]string {
+	labels := map[string]string{"app.kubernetes.io/part-of": "sentra-lab"}
+	if component != "" {
+		labels["app.kubernetes.io/name"] = component
+	}
+	return labels
+}
+
+// redis is the shared state store: a single-replica Deployment and its
+// Service. State lives in memory; it is test data, rebuilt by seeds.
+func redis(namespace string) []object {
+	deployment := object{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   object{"name": "sentra-redis", "namespace": namespace, "labels": labels("sentra-redis")},
+		"spec": object{
+			"replicas": 1,
+			"selector": object{"matchLabels": labels("sentra-redis")},
+			"template": object{
+				"metadata": object{"labels": labels("sentra-redis")},
+				"spec": object{
+					"containers": []object{{
+						"name":  "redis",
+						"image": RedisImage,
+						"args":  []string{"--save", "", "--appendonly", "no"},
+						"ports": []object{{"containerPort": 6379}},
+						"readinessProbe": object{
+							"exec":          object{"command": []string{"redis-cli", "ping"}},
+							"periodSeconds": 5,
+						},
+					}},
+				},
+			},
+		},
+	}
+	return []object{deployment, service(namespace, "sentra-redis", 6379, 6379)}
+}
+
+// mockDeployment runs a mock with the settings `sentra lab start` passes
+// its container, its resources limits and the shared Redis. fixtureItems,
+// when set, mounts the fixtures ConfigMap at /fixtures.
+func mockDeployment(opts Options, name string, mock config.MockConfig, image string, fixtureItems []object) object {
+	component := "mock-" + name
+	env := mockEnvironment(name, mock)
+	env = append(env, object{"name": "REDIS_URL", "value": fmt.Sprintf("redis://sentra-redis.%s.svc.cluster.local:6379/0", opts.Namespace)})
+
+	container := object{
+		"name":  component,
+		"image": image,
+		"env":   env,
+		"ports": []object{{"containerPort": mockPort}},
+		"readinessProbe": object{
+			"httpGet":       object{"path": "/health", "port": mockPort},
+			"periodSeconds": 5,
+		},
+		"livenessProbe": object{
+			"httpGet":          object{"path": "/health", "port": mockPort},
+			"periodSeconds":    10,
+			"failureThreshold": 6,
+		},
+	}
+	if resources := mockResources(mock.Resources); resources != nil {
+		container["resources"] = resources
+	}
+
+	podSpec := object{"containers": []object{container}}
+	if len(fixtureItems) > 0 {
+		container["volumeMounts"] = []object{{"name": "fixtures", "mountPath": "/fixtures", "readOnly": true}}
+		podSpec["volumes"] = []object{{"name": "fixtures", "configMap": object{"name": "sentra-fixtures", "items": fixtureItems}}}
+	}
+
+	return object{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   object{"name": component, "namespace": opts.Namespace, "labels": labels(component)},
+		"spec": object{
+			"replicas": opts.Replicas,
+			"selector": object{"matchLabels": labels(component)},
+			"template": object{
+				"metadata": object{"labels": labels(component)},
+				"spec":     podSpec,
+			},
+		},
+	}
+}
+
+// mockEnvironment is the environment `sentra lab start` gives the mock's
+// container.
+func mockEnvironment(name string, mock config.MockConfig) []object {
+	vars := map[string]string{
+		"LATENCY_MS":   fmt.Sprintf("%d", mock.LatencyMS),
+		"FIXTURES_DIR": "/fixtures",
+	}
+	if name == "openai" {
+		vars["RATE_LIMIT"] = fmt.Sprintf("%d", mock.RateLimit)
+		vars["ERROR_RATE"] = fmt.Sprintf("%g", mock.ErrorRate)
+		if mock.SynthesisStrategy != "" {
+			vars["SYNTHESIS_STRATEGY"] = mock.SynthesisStrategy
+		}
+		if mock.Jitter != "" {
+			vars["JITTER_DISTRIBUTION"] = mock.Jitter
+		}
+		if mock.MaxStreamsPerKey > 0 {
+			vars["MAX_STREAMS_PER_KEY"] = fmt.Sprintf("%d", mock.MaxStreamsPerKey)
+		}
+	}
+	if skew, err := time.ParseDuration(mock.WebhookTimestampSkew); err == nil && mock.WebhookTimestampSkew != "" {
+		vars["WEBHOOK_TIMESTAMP_SKEW_MS"] = fmt.Sprintf("%d", skew.Milliseconds())
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]object, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, object{"name": key, "value": vars[key]})
+	}
+	return env
+}
+
+// mockResources converts mocks.<name>.resources into container limits, or
+// nil without any.
+func mockResources(resources config.MockResources) object {
+	limits := object{}
+	if resources.CPUs > 0 {
+		limits["cpu"] = fmt.Sprintf("%dm", int(resources.CPUs*1000))
+	}
+	if resources.MemoryMB > 0 {
+		limits["memory"] = fmt.Sprintf("%dMi", resources.MemoryMB)
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+	return object{"limits": limits, "requests": limits}
+}
+
+// service exposes a component inside the cluster.
+func service(namespace, name string, port, targetPort int) object {
+	return object{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   object{"name": name, "namespace": namespace, "labels": labels(name)},
+		"spec": object{
+			"selector": labels(name),
+			"ports":    []object{{"port": port, "targetPort": targetPort}},
+		},
+	}
+}
+
+// fixturesConfigMap packages the fixture files under dir into a ConfigMap.
+// ConfigMap keys cannot contain "/", so nested paths are flattened with
+// "__"; the returned volume items restore them. Returns nil if dir has no
+// fixtures.
+func fixturesConfigMap(namespace, dir string) (object, []object, error) {
+	data := map[string]string{}
+	var items []object
+	size := 0
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		key := strings.ReplaceAll(rel, "/", "__")
+		data[key] = string(content)
+		items = append(items, object{"key": key, "path": rel})
+		size += len(content)
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil, nil
+	}
+	if size > maxConfigMapBytes {
+		return nil, nil, fmt.Errorf("fixtures in %s total %d bytes, more than a ConfigMap holds (1MiB); bake them into a custom mock image instead", dir, size)
+	}
+
+	return object{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   object{"name": "sentra-fixtures", "namespace": namespace, "labels": labels("sentra-fixtures")},
+		"data":       data,
+	}, items, nil
+}
@@ -0,0 +1,278 @@
+package k8s
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// decode parses a multi-document manifest stream into objects keyed by
+// "Kind/name".
+func decode(t *testing.T, manifests []byte) map[string]object {
+	t.Helper()
+	objects := make(map[string]object)
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var obj object
+		err := decoder.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			return objects
+		}
+		if err != nil {
+			t.Fatalf("invalid manifests: %v\n%s", err, manifests)
+		}
+		metadata := obj["metadata"].(object)
+		objects[obj["kind"].(string)+"/"+metadata["name"].(string)] = obj
+	}
+}
+
+// container returns the first container of a Deployment.
+func container(t *testing.T, deployment object) object {
+	t.Helper()
+	spec := deployment["spec"].(object)["template"].(object)["spec"].(object)
+	return spec["containers"].([]interface{})[0].(object)
+}
+
+// env returns a container's environment.
+func env(container object) map[string]string {
+	vars := make(map[string]string)
+	for _, item := range container["env"].([]interface{}) {
+		v := item.(object)
+		vars[v["name"].(string)] = v["value"].(string)
+	}
+	return vars
+}
+
+func TestRender(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{
+		"openai": {
+			Enabled:           true,
+			Port:              8080,
+			LatencyMS:         50,
+			RateLimit:         60,
+			ErrorRate:         0.05,
+			SynthesisStrategy: "echo",
+			Resources:         config.MockResources{CPUs: 0.5, MemoryMB: 256},
+		},
+		"stripe": {
+			Enabled:              true,
+			Port:                 8081,
+			Image:                "ghcr.io/example/stripe-mock:1.2",
+			ImageDigest:          "sha256:abc",
+			WebhookTimestampSkew: "-10m",
+		},
+		"coreledger": {Port: 8082},
+	}}
+	opts := Options{
+		Namespace: "ci",
+		Replicas:  3,
+		Images:    map[string]string{"openai": "sentra/mock-openai:latest", "stripe": "sentra/mock-stripe:latest"},
+	}
+
+	manifests, err := Render(cfg, opts)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !bytes.Contains(manifests, []byte("#   stripe: http://mock-stripe.ci.svc.cluster.local:8081\n")) {
+		t.Errorf("Render() header does not list the stripe URL:\n%s", manifests)
+	}
+
+	objects := decode(t, manifests)
+	var names []string
+	for name := range objects {
+		names = append(names, name)
+	}
+	want := []string{
+		"Deployment/mock-openai", "Deployment/mock-stripe", "Deployment/sentra-redis",
+		"Namespace/ci", "Service/mock-openai", "Service/mock-stripe", "Service/sentra-redis",
+	}
+	if strings.Join(sortedCopy(names), ",") != strings.Join(want, ",") {
+		t.Fatalf("Render() objects = %v, want %v", sortedCopy(names), want)
+	}
+
+	openai := objects["Deployment/mock-openai"]
+	if replicas := openai["spec"].(object)["replicas"]; replicas != 3 {
+		t.Errorf("mock-openai replicas = %v, want 3", replicas)
+	}
+	openaiContainer := container(t, openai)
+	if image := openaiContainer["image"]; image != "sentra/mock-openai:latest" {
+		t.Errorf("mock-openai image = %v, want the default image", image)
+	}
+	wantEnv := map[string]string{
+		"ERROR_RATE":         "0.05",
+		"FIXTURES_DIR":       "/fixtures",
+		"LATENCY_MS":         "50",
+		"RATE_LIMIT":         "60",
+		"REDIS_URL":          "redis://sentra-redis.ci.svc.cluster.local:6379/0",
+		"SYNTHESIS_STRATEGY": "echo",
+	}
+	if got := env(openaiContainer); !reflect.DeepEqual(got, wantEnv) {
+		t.Errorf("mock-openai env = %v, want %v", got, wantEnv)
+	}
+	wantLimits := object{"cpu": "500m", "memory": "256Mi"}
+	if limits := openaiContainer["resources"].(object)["limits"]; !reflect.DeepEqual(limits, wantLimits) {
+		t.Errorf("mock-openai limits = %v, want %v", limits, wantLimits)
+	}
+
+	stripeContainer := container(t, objects["Deployment/mock-stripe"])
+	if image := stripeContainer["image"]; image != "ghcr.io/example/stripe-mock:1.2@sha256:abc" {
+		t.Errorf("mock-stripe image = %v, want the pinned lab.yaml image", image)
+	}
+	if skew := env(stripeContainer)["WEBHOOK_TIMESTAMP_SKEW_MS"]; skew != "-600000" {
+		t.Errorf("WEBHOOK_TIMESTAMP_SKEW_MS = %q, want -600000", skew)
+	}
+	if _, ok := stripeContainer["resources"]; ok {
+		t.Errorf("mock-stripe has resources %v, want none", stripeContainer["resources"])
+	}
+
+	ports := objects["Service/mock-stripe"]["spec"].(object)["ports"].([]interface{})[0].(object)
+	if ports["port"] != 8081 || ports["targetPort"] != mockPort {
+		t.Errorf("mock-stripe service ports = %v, want 8081 -> %d", ports, mockPort)
+	}
+}
+
+// sortedCopy returns values sorted.
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j] < sorted[j-1]; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	return sorted
+}
+
+func TestRenderDefaults(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{"openai": {Enabled: true, Port: 8080}}}
+
+	objects := decode(t, mustRender(t, cfg, Options{Images: map[string]string{"openai": "sentra/mock-openai"}}))
+	if _, ok := objects["Namespace/"+DefaultNamespace]; !ok {
+		t.Errorf("Render() objects = %v, want the %s namespace", objects, DefaultNamespace)
+	}
+	if replicas := objects["Deployment/mock-openai"]["spec"].(object)["replicas"]; replicas != 1 {
+		t.Errorf("replicas = %v, want 1", replicas)
+	}
+}
+
+// mustRender renders manifests or fails the test.
+func mustRender(t *testing.T, cfg *config.Config, opts Options) []byte {
+	t.Helper()
+	manifests, err := Render(cfg, opts)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return manifests
+}
+
+func TestRenderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mocks   map[string]config.MockConfig
+		wantErr string
+	}{
+		{name: "no mocks enabled", mocks: map[string]config.MockConfig{"openai": {Port: 8080}}, wantErr: "no mocks are enabled"},
+		{name: "no image", mocks: map[string]config.MockConfig{"coreledger": {Enabled: true, Port: 8082}}, wantErr: "mocks.coreledger: no image"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(&config.Config{Mocks: tt.mocks}, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Render() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderFixtures(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{"openai": {Enabled: true, Port: 8080}}}
+	opts := Options{Images: map[string]string{"openai": "sentra/mock-openai"}}
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantItems []interface{}
+		wantErr   string
+	}{
+		{
+			name: "fixtures",
+			files: map[string]string{
+				"openai-responses.yaml": "responses: []\n",
+				"stripe/customers.json": "[]",
+				"README.md":             "# Fixtures\n",
+			},
+			wantItems: []interface{}{
+				object{"key": "openai-responses.yaml", "path": "openai-responses.yaml"},
+				object{"key": "stripe__customers.json", "path": "stripe/customers.json"},
+			},
+		},
+		{name: "no fixture files", files: map[string]string{"README.md": "# Fixtures\n"}},
+		{name: "too large", files: map[string]string{"big.json": strings.Repeat("x", maxConfigMapBytes+1)}, wantErr: "more than a ConfigMap holds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			opts.FixturesDir = dir
+
+			manifests, err := Render(cfg, opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Render() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			objects := decode(t, manifests)
+			configMap, ok := objects["ConfigMap/sentra-fixtures"]
+			if tt.wantItems == nil {
+				if ok {
+					t.Errorf("Render() has a fixtures ConfigMap, want none")
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Render() objects = %v, want a fixtures ConfigMap", objects)
+			}
+			if data := configMap["data"].(object); data["stripe__customers.json"] != "[]" {
+				t.Errorf("ConfigMap data = %v, want the flattened fixture", data)
+			}
+
+			spec := objects["Deployment/mock-openai"]["spec"].(object)["template"].(object)["spec"].(object)
+			volume := spec["volumes"].([]interface{})[0].(object)
+			if items := volume["configMap"].(object)["items"]; !reflect.DeepEqual(items, tt.wantItems) {
+				t.Errorf("volume items = %v, want %v", items, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestRenderMissingFixturesDir(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{"openai": {Enabled: true, Port: 8080}}}
+	opts := Options{Images: map[string]string{"openai": "sentra/mock-openai"}, FixturesDir: filepath.Join(t.TempDir(), "missing")}
+
+	if _, ok := decode(t, mustRender(t, cfg, opts))["ConfigMap/sentra-fixtures"]; ok {
+		t.Error("Render() has a fixtures ConfigMap for a missing directory, want none")
+	}
+}
@@ -0,0 +1,165 @@
+// Package labenv builds the environment agents need to talk to the mocks
+// (base URLs with the ports actually in use, the mock API key and, when
+// enabled, the egress proxy settings), persists it for `sentra lab env` and
+// `sentra lab run`, and formats it for shells.
+package labenv
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/egress"
+)
+
+// Path is where `sentra lab start` writes the environment
+const Path = ".sentra-lab/env"
+
+// MockAPIKey is the API key the OpenAI mock accepts from agents
+const MockAPIKey = "mock_key_sentra_lab"
+
+// StripeAPIKey is the API key the Stripe mock accepts from agents
+const StripeAPIKey = "sk_test_mock_sentra_lab"
+
+// Shells are the supported --shell values
+var Shells = []string{"bash", "fish", "powershell"}
+
+// Var is an environment variable.
+type Var struct {
+	Name  string
+	Value string
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// Vars returns the environment for cfg's enabled mocks: <MOCK>_API_BASE and
+// <MOCK>_BASE_URL for each (the OpenAI ones include /v1, as its SDKs
+// expect), OPENAI_API_KEY, and the egress proxy variables when it is
+// enabled.
+func Vars(cfg *config.Config) ([]Var, error) {
+	names := make([]string, 0, len(cfg.Mocks))
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var vars []Var
+	for _, name := range names {
+		prefix := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+		base := cfg.Mocks[name].BaseURL()
+		if name == "openai" {
+			base += "/v1"
+		}
+
+		vars = append(vars,
+			Var{prefix + "_API_BASE", base},
+			Var{prefix + "_BASE_URL", base},
+		)
+		if name == "openai" {
+			vars = append(vars, Var{"OPENAI_API_KEY", MockAPIKey})
+		}
+	}
+
+	if cfg.Egress.Enabled {
+		dir, err := filepath.Abs(egress.Dir)
+		if err != nil {
+			return nil, err
+		}
+		proxyEnv := egress.Env(cfg.Egress.Port, dir)
+		for _, name := range egress.EnvNames(proxyEnv) {
+			vars = append(vars, Var{name, proxyEnv[name]})
+		}
+	}
+
+	return vars, nil
+}
+
+// Write saves vars to path as NAME=value lines.
+func Write(path string, vars []Var) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by 'sentra lab start'. Load it with 'sentra lab env' or\n")
+	b.WriteString("# run a command with it using 'sentra lab run -- <command>'.\n")
+	for _, v := range vars {
+		fmt.Fprintf(&b, "%s=%s\n", v.Name, v.Value)
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads the environment Write saved at path.
+func Load(path string) ([]Var, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var vars []Var
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s:%d: expected NAME=value", path, line)
+		}
+		vars = append(vars, Var{strings.TrimSpace(name), value})
+	}
+
+	return vars, scanner.Err()
+}
+
+// Environ returns vars as NAME=value pairs, for exec.Cmd.Env.
+func Environ(vars []Var) []string {
+	env := make([]string, 0, len(vars))
+	for _, v := range vars {
+		env = append(env, v.Name+"="+v.Value)
+	}
+	return env
+}
+
+// Format renders vars as commands that set them in shell.
+func Format(vars []Var, shell string) (string, error) {
+	var line func(Var) string
+	switch shell {
+	case "bash":
+		line = func(v Var) string {
+			return fmt.Sprintf("export %s='%s'", v.Name, strings.ReplaceAll(v.Value, "'", `'\''`))
+		}
+	case "fish":
+		line = func(v Var) string {
+			escaped := strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(v.Value)
+			return fmt.Sprintf("set -gx %s '%s'", v.Name, escaped)
+		}
+	case "powershell":
+		line = func(v Var) string {
+			return fmt.Sprintf("$env:%s = '%s'", v.Name, strings.ReplaceAll(v.Value, "'", "''"))
+		}
+	default:
+		return "", fmt.Errorf("unsupported shell %q (must be one of: %s)", shell, strings.Join(Shells, ", "))
+	}
+
+	var b strings.Builder
+	for _, v := range vars {
+		b.WriteString(line(v))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,136 @@
+package labenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+func TestVars(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{
+		"openai":      {Enabled: true, Port: 8080},
+		"stripe":      {Enabled: true, Port: 8081},
+		"core-ledger": {Enabled: true, Port: 8082},
+		"slack":       {Port: 8083},
+	}}
+
+	vars, err := Vars(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Var{
+		{"CORE_LEDGER_API_BASE", "http://localhost:8082"},
+		{"CORE_LEDGER_BASE_URL", "http://localhost:8082"},
+		{"OPENAI_API_BASE", "http://localhost:8080/v1"},
+		{"OPENAI_BASE_URL", "http://localhost:8080/v1"},
+		{"OPENAI_API_KEY", MockAPIKey},
+		{"STRIPE_API_BASE", "http://localhost:8081"},
+		{"STRIPE_BASE_URL", "http://localhost:8081"},
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("Vars() = %v, want %v", vars, want)
+	}
+}
+
+func TestVarsRemoteMock(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{
+		"openai": {Enabled: true, Port: 8080, URL: "https://mocks.internal/openai"},
+	}}
+
+	vars, err := Vars(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars[0] != (Var{"OPENAI_API_BASE", "https://mocks.internal/openai/v1"}) {
+		t.Errorf("Vars()[0] = %v, want the remote mock's URL", vars[0])
+	}
+}
+
+func TestVarsEgress(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfg := &config.Config{Egress: config.EgressConfig{Enabled: true, Port: 8899}}
+
+	vars, err := Vars(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]string)
+	for _, v := range vars {
+		byName[v.Name] = v.Value
+	}
+	if byName["HTTPS_PROXY"] != "http://localhost:8899" {
+		t.Errorf("HTTPS_PROXY = %q, want http://localhost:8899", byName["HTTPS_PROXY"])
+	}
+	if ca := byName["NODE_EXTRA_CA_CERTS"]; !filepath.IsAbs(ca) {
+		t.Errorf("NODE_EXTRA_CA_CERTS = %q, want an absolute path", ca)
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".sentra-lab", "env")
+	vars := []Var{
+		{"OPENAI_API_BASE", "http://localhost:8080/v1"},
+		{"QUERY", "a=b&c=d"},
+	}
+
+	if err := Write(path, vars); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, vars) {
+		t.Errorf("Load() = %v, want %v", got, vars)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "env")
+	if err := os.WriteFile(path, []byte("# comment\n\nOPENAI_API_KEY=x\nnot a variable\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil || err.Error() != path+":4: expected NAME=value" {
+		t.Errorf("Load() error = %v, want line 4", err)
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	got := Environ([]Var{{"A", "1"}, {"B", "x=y"}})
+	if want := []string{"A=1", "B=x=y"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Environ() = %v, want %v", got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	vars := []Var{{"OPENAI_API_KEY", "mock"}, {"GREETING", `it's a \ test`}}
+
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", "export OPENAI_API_KEY='mock'\nexport GREETING='it'\\''s a \\ test'\n"},
+		{"fish", "set -gx OPENAI_API_KEY 'mock'\nset -gx GREETING 'it\\'s a \\\\ test'\n"},
+		{"powershell", "$env:OPENAI_API_KEY = 'mock'\n$env:GREETING = 'it''s a \\ test'\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			got, err := Format(vars, tt.shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Format() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Format(vars, "cmd"); err == nil {
+		t.Error(`Format("cmd") error = nil, want unsupported shell`)
+	}
+}
@@ -0,0 +1,287 @@
+// Package loadgen sends synthetic background traffic to the mocks while
+// scenarios run. Background requests share the agent's API keys, so they
+// consume the same rate limits and simulated capacity, and scenarios run
+// under production-like contention instead of against an idle system.
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/labenv"
+)
+
+// requestTimeout bounds a single background request
+const requestTimeout = 30 * time.Second
+
+// Target is a mock endpoint that receives background requests.
+type Target struct {
+	Mock        string
+	URL         string
+	ContentType string
+	APIKey      string
+	Body        []byte
+}
+
+// Stats summarizes the traffic sent by a generator.
+type Stats struct {
+	Sent        int64
+	Succeeded   int64
+	RateLimited int64
+	Failed      int64
+
+	// Dropped counts ticks skipped because the concurrency cap was reached
+	Dropped  int64
+	Duration time.Duration
+}
+
+// AchievedRPS is the rate at which requests were actually sent.
+func (s Stats) AchievedRPS() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Sent) / s.Duration.Seconds()
+}
+
+// Generator sends requests to targets at a fixed rate.
+type Generator struct {
+	rps         float64
+	concurrency int
+	targets     []Target
+	client      *http.Client
+
+	sent        atomic.Int64
+	succeeded   atomic.Int64
+	rateLimited atomic.Int64
+	failed      atomic.Int64
+	dropped     atomic.Int64
+
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+	startedAt time.Time
+	stoppedAt time.Time
+}
+
+// NewGenerator builds a generator from simulation.background_load.
+// It returns nil when background load is disabled.
+func NewGenerator(cfg *config.Config) (*Generator, error) {
+	load := cfg.Simulation.BackgroundLoad
+	if !load.Enabled {
+		return nil, nil
+	}
+
+	mocks := load.Mocks
+	if len(mocks) == 0 {
+		for name, mock := range cfg.Mocks {
+			if mock.Enabled && supportsMock(name) {
+				mocks = append(mocks, name)
+			}
+		}
+		sort.Strings(mocks)
+	}
+
+	if len(mocks) == 0 {
+		return nil, fmt.Errorf("background load: no enabled mocks support synthetic traffic (supported: openai, stripe)")
+	}
+
+	targets := make([]Target, 0, len(mocks))
+	for _, name := range mocks {
+		target, err := newTarget(name, cfg.Mocks[name].BaseURL(), load)
+		if err != nil {
+			return nil, fmt.Errorf("background load: %w", err)
+		}
+		targets = append(targets, target)
+	}
+
+	concurrency := load.Concurrency
+	if concurrency <= 0 {
+		concurrency = 32
+	}
+
+	return &Generator{
+		rps:         load.RPS,
+		concurrency: concurrency,
+		targets:     targets,
+		client:      &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func supportsMock(name string) bool {
+	return name == "openai" || name == "stripe"
+}
+
+func newTarget(name, baseURL string, load config.BackgroundLoadConfig) (Target, error) {
+
+	switch name {
+	case "openai":
+		body := fmt.Sprintf(`{"model":%q,"messages":[{"role":"user","content":"Background load request"}],"max_tokens":16}`, load.Model)
+		return Target{
+			Mock:        name,
+			URL:         baseURL + "/v1/chat/completions",
+			ContentType: "application/json",
+			APIKey:      load.APIKey,
+			Body:        []byte(body),
+		}, nil
+
+	case "stripe":
+		form := url.Values{}
+		form.Set("amount", "1000")
+		form.Set("currency", "usd")
+		form.Set("metadata[source]", "sentra_background_load")
+		return Target{
+			Mock:        name,
+			URL:         baseURL + "/v1/payment_intents",
+			ContentType: "application/x-www-form-urlencoded",
+			APIKey:      labenv.StripeAPIKey,
+			Body:        []byte(form.Encode()),
+		}, nil
+	}
+
+	return Target{}, fmt.Errorf("mock %s does not support synthetic traffic (supported: openai, stripe)", name)
+}
+
+// Targets returns the endpoints receiving background requests.
+func (g *Generator) Targets() []Target {
+	return g.targets
+}
+
+// Start begins sending requests until Stop is called or ctx is canceled.
+// Calling Start on a running generator has no effect.
+func (g *Generator) Start(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	g.cancel = cancel
+	g.done = make(chan struct{})
+	g.startedAt = time.Now()
+	g.stoppedAt = time.Time{}
+
+	go g.run(ctx)
+}
+
+// Stop stops sending, waits for in-flight requests and returns the stats.
+func (g *Generator) Stop() Stats {
+	g.mu.Lock()
+	cancel, done := g.cancel, g.done
+	g.cancel = nil
+	g.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+
+		g.mu.Lock()
+		g.stoppedAt = time.Now()
+		g.mu.Unlock()
+	}
+
+	return g.Stats()
+}
+
+// Stats returns the traffic sent so far.
+func (g *Generator) Stats() Stats {
+	g.mu.Lock()
+	end := g.stoppedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	var duration time.Duration
+	if !g.startedAt.IsZero() {
+		duration = end.Sub(g.startedAt)
+	}
+	g.mu.Unlock()
+
+	return Stats{
+		Sent:        g.sent.Load(),
+		Succeeded:   g.succeeded.Load(),
+		RateLimited: g.rateLimited.Load(),
+		Failed:      g.failed.Load(),
+		Dropped:     g.dropped.Load(),
+		Duration:    duration,
+	}
+}
+
+// run issues one request per tick, rotating through targets. Ticks are
+// dropped rather than queued when the concurrency cap is reached, so a slow
+// mock sees a steady arrival rate instead of a burst when it recovers.
+func (g *Generator) run(ctx context.Context) {
+	defer close(g.done)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / g.rps))
+	defer ticker.Stop()
+
+	semaphore := make(chan struct{}, g.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for next := 0; ; next++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		default:
+			g.dropped.Add(1)
+			continue
+		}
+
+		target := g.targets[next%len(g.targets)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			g.send(ctx, target)
+		}()
+	}
+}
+
+func (g *Generator) send(ctx context.Context, target Target) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(target.Body))
+	if err != nil {
+		g.failed.Add(1)
+		return
+	}
+	req.Header.Set("Content-Type", target.ContentType)
+	req.Header.Set("Authorization", "Bearer "+target.APIKey)
+	req.Header.Set("User-Agent", "sentra-lab-loadgen")
+
+	g.sent.Add(1)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		// Requests cut off by Stop are not failures
+		if ctx.Err() == nil {
+			g.failed.Add(1)
+		}
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		g.rateLimited.Add(1)
+	case resp.StatusCode >= 400:
+		g.failed.Add(1)
+	default:
+		g.succeeded.Add(1)
+	}
+}
@@ -0,0 +1,195 @@
+package loadgen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// serverPort returns the port a test server listens on.
+func serverPort(t *testing.T, server *httptest.Server) int {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return port
+}
+
+func TestNewGeneratorDisabled(t *testing.T) {
+	generator, err := NewGenerator(&config.Config{})
+	if generator != nil || err != nil {
+		t.Errorf("NewGenerator() = %v, %v; want nil when disabled", generator, err)
+	}
+}
+
+func TestNewGeneratorTargets(t *testing.T) {
+	cfg := &config.Config{
+		Mocks: map[string]config.MockConfig{
+			"openai":     {Enabled: true, Port: 8080},
+			"stripe":     {Enabled: true, Port: 8081},
+			"coreledger": {Enabled: true, Port: 8082},
+		},
+		Simulation: config.SimulationConfig{BackgroundLoad: config.BackgroundLoadConfig{
+			Enabled: true, RPS: 5, Model: "gpt-4o-mini", APIKey: "mock_key",
+		}},
+	}
+
+	generator, err := NewGenerator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := generator.Targets()
+	if len(targets) != 2 {
+		t.Fatalf("Targets() = %+v, want openai and stripe", targets)
+	}
+
+	openai, stripe := targets[0], targets[1]
+	if openai.URL != "http://localhost:8080/v1/chat/completions" || openai.APIKey != "mock_key" ||
+		!strings.Contains(string(openai.Body), `"model":"gpt-4o-mini"`) {
+		t.Errorf("openai target = %+v", openai)
+	}
+	if stripe.URL != "http://localhost:8081/v1/payment_intents" || stripe.ContentType != "application/x-www-form-urlencoded" ||
+		!strings.HasPrefix(stripe.APIKey, "sk_test_") {
+		t.Errorf("stripe target = %+v", stripe)
+	}
+	if generator.concurrency != 32 {
+		t.Errorf("concurrency = %d, want the default 32", generator.concurrency)
+	}
+}
+
+func TestNewGeneratorUnsupported(t *testing.T) {
+	cfg := &config.Config{
+		Mocks: map[string]config.MockConfig{"coreledger": {Enabled: true, Port: 8082}},
+		Simulation: config.SimulationConfig{BackgroundLoad: config.BackgroundLoadConfig{
+			Enabled: true, RPS: 5,
+		}},
+	}
+
+	if _, err := NewGenerator(cfg); err == nil {
+		t.Error("NewGenerator() without supported mocks error = nil")
+	}
+
+	cfg.Simulation.BackgroundLoad.Mocks = []string{"coreledger"}
+	if _, err := NewGenerator(cfg); err == nil || !strings.Contains(err.Error(), "coreledger") {
+		t.Errorf("NewGenerator() error = %v, want coreledger unsupported", err)
+	}
+}
+
+func TestGeneratorSends(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+
+		// Every third request is rate limited and every fifth fails
+		switch {
+		case n%3 == 0:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n%5 == 0:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Mocks: map[string]config.MockConfig{"openai": {Enabled: true, Port: serverPort(t, server)}},
+		Simulation: config.SimulationConfig{BackgroundLoad: config.BackgroundLoadConfig{
+			Enabled: true, RPS: 200, Model: "gpt-4o", APIKey: "mock_key",
+		}},
+	}
+	generator, err := NewGenerator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generator.Start(context.Background())
+	generator.Start(context.Background())
+	time.Sleep(200 * time.Millisecond)
+	stats := generator.Stop()
+
+	if stats.Sent == 0 {
+		t.Fatal("no background requests were sent")
+	}
+	// Requests cut off by Stop are sent but have no outcome
+	if answered := stats.Succeeded + stats.RateLimited + stats.Failed; answered == 0 || answered > stats.Sent {
+		t.Errorf("stats = %+v, want each answered request counted once", stats)
+	}
+	if stats.RateLimited == 0 || stats.Failed == 0 {
+		t.Errorf("stats = %+v, want rate limited and failed requests", stats)
+	}
+	if stats.Duration <= 0 || stats.AchievedRPS() <= 0 {
+		t.Errorf("Duration = %v, AchievedRPS() = %v", stats.Duration, stats.AchievedRPS())
+	}
+	mu.Lock()
+	if auth != "Bearer mock_key" {
+		t.Errorf("Authorization = %q, want the configured key", auth)
+	}
+	mu.Unlock()
+
+	// Nothing is sent after Stop
+	time.Sleep(50 * time.Millisecond)
+	if after := generator.Stats(); after.Sent != stats.Sent {
+		t.Errorf("Sent = %d after Stop, want %d", after.Sent, stats.Sent)
+	}
+}
+
+func TestGeneratorDropsAtConcurrencyCap(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := &config.Config{
+		Mocks: map[string]config.MockConfig{"openai": {Enabled: true, Port: serverPort(t, server)}},
+		Simulation: config.SimulationConfig{BackgroundLoad: config.BackgroundLoadConfig{
+			Enabled: true, RPS: 200, Concurrency: 2,
+		}},
+	}
+	generator, err := NewGenerator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generator.Start(context.Background())
+	time.Sleep(100 * time.Millisecond)
+	stats := generator.Stop()
+
+	if stats.Sent != 2 || stats.Dropped == 0 {
+		t.Errorf("stats = %+v, want 2 sent and the rest dropped", stats)
+	}
+	if stats.Failed != 0 {
+		t.Errorf("Failed = %d, want requests cut off by Stop not counted", stats.Failed)
+	}
+}
+
+func TestAchievedRPS(t *testing.T) {
+	if got := (Stats{Sent: 30, Duration: 3 * time.Second}).AchievedRPS(); got != 10 {
+		t.Errorf("AchievedRPS() = %v, want 10", got)
+	}
+	if got := (Stats{Sent: 30}).AchievedRPS(); got != 0 {
+		t.Errorf("AchievedRPS() without a duration = %v, want 0", got)
+	}
+}
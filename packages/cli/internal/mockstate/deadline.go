@@ -0,0 +1,93 @@
+package mockstate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// deadlinePath is the mock admin endpoint that sets the test run deadline
+const deadlinePath = "/_sentra/deadline"
+
+// Deadlines tell the enabled mocks when the running scenario or run times
+// out. Requests still in flight then are cancelled, cutting simulated
+// latency short, so a hung agent is not kept waiting on a mock.
+type Deadlines struct {
+	urls   map[string]string
+	client *http.Client
+}
+
+// NewDeadlines returns deadlines for the enabled mocks in cfg, or nil if no
+// mock is enabled.
+func NewDeadlines(cfg *config.Config) *Deadlines {
+	urls := make(map[string]string)
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			urls[name] = mock.BaseURL()
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	return &Deadlines{
+		urls:   urls,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Set sets the deadline of the timeout at level ("scenario" or "run").
+// A deadline in the past cancels the mocks' requests in flight now. Mocks
+// without deadline support are skipped.
+func (d *Deadlines) Set(ctx context.Context, deadline time.Time, level string) error {
+	return d.send(ctx, "PUT", map[string]interface{}{
+		"deadline_ms": deadline.UnixMilli(),
+		"level":       level,
+	})
+}
+
+// Clear removes the deadline.
+func (d *Deadlines) Clear(ctx context.Context) error {
+	return d.send(ctx, "DELETE", nil)
+}
+
+func (d *Deadlines) send(ctx context.Context, method string, body interface{}) error {
+	names := make([]string, 0, len(d.urls))
+	for name := range d.urls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		req, err := http.NewRequestWithContext(ctx, method, d.urls[name]+deadlinePath, bytes.NewReader(encoded))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to set %s mock deadline: %w", name, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("failed to set %s mock deadline: status %d", name, resp.StatusCode)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package mockstate resets the state mocks build up as scenarios run (usage,
+// rate limit buckets, fixture statistics, threads, ...) so each scenario
+// starts from a clean mock and tests pass or fail whatever order they run in.
+package mockstate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// resetPath is the mock admin endpoint that snapshots and resets state
+const resetPath = "/_sentra/state/reset"
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 5 * time.Second
+
+// When mock state is reset (simulation.isolation.reset)
+const (
+	ResetScenario = "scenario"
+	ResetRun      = "run"
+	ResetNone     = "none"
+)
+
+// Resetter resets the state of the enabled mocks.
+type Resetter struct {
+	urls   map[string]string
+	state  []string
+	mode   string
+	client *http.Client
+
+	// unsupported are the base URLs of mocks without state isolation,
+	// skipped after the first attempt
+	mu          sync.Mutex
+	unsupported map[string]bool
+}
+
+// NewResetter builds a resetter for the enabled mocks from
+// simulation.isolation. It returns nil when isolation is disabled or no
+// mock is enabled.
+func NewResetter(cfg *config.Config) *Resetter {
+	isolation := cfg.Simulation.Isolation
+	if isolation.Reset == ResetNone {
+		return nil
+	}
+
+	urls := make(map[string]string)
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			urls[name] = mock.BaseURL()
+		}
+	}
+	// The egress proxy answers resets too, restoring egress.faults
+	if cfg.Egress.Enabled && len(urls) > 0 {
+		urls["egress"] = fmt.Sprintf("http://localhost:%d", cfg.Egress.Port)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	mode := isolation.Reset
+	if mode == "" {
+		mode = ResetScenario
+	}
+
+	return &Resetter{
+		urls:        urls,
+		state:       isolation.State,
+		mode:        mode,
+		client:      &http.Client{Timeout: requestTimeout},
+		unsupported: make(map[string]bool),
+	}
+}
+
+// Mode returns when state is reset: ResetScenario or ResetRun.
+func (r *Resetter) Mode() string {
+	return r.mode
+}
+
+// Reset resets the state of the local mocks at ports (by mock name; the
+// enabled mocks in lab.yaml, wherever they run, when nil) before scenario
+// runs, or before the whole run
+// when scenario is empty. The mocks snapshot the state they discard, which
+// GET /_sentra/state then reports. Mocks that do not support state
+// isolation are skipped.
+func (r *Resetter) Reset(ctx context.Context, scenario string, ports map[string]int) error {
+	urls := r.urls
+	if ports != nil {
+		urls = make(map[string]string, len(ports))
+		for name, port := range ports {
+			urls[name] = fmt.Sprintf("http://localhost:%d", port)
+		}
+	}
+
+	names := make([]string, 0, len(urls))
+	for name := range urls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := r.reset(ctx, name, urls[name], scenario); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resetter) reset(ctx context.Context, mock, baseURL, scenario string) error {
+	r.mu.Lock()
+	skip := r.unsupported[baseURL]
+	r.mu.Unlock()
+	if skip {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"scenario": scenario,
+		"state":    r.state,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+resetPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reset %s mock state: %w", mock, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		r.mu.Lock()
+		r.unsupported[baseURL] = true
+		r.mu.Unlock()
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to reset %s mock state: status %d", mock, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+// Package notify posts test run summaries to Slack or Microsoft Teams
+// channels through incoming webhooks, so CI runs report pass rate, failed
+// scenarios and cost changes where the team already looks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// CloudRunURL is where a run pushed with `sentra lab cloud push` is viewed
+const CloudRunURL = "https://lab.sentra.dev/runs/"
+
+// StatePath remembers the previous run's cost for the cost delta
+const StatePath = ".sentra-lab/notify-state.json"
+
+// maxListedFailures caps the failed scenarios listed in a message
+const maxListedFailures = 10
+
+// requestTimeout bounds a single webhook request
+const requestTimeout = 10 * time.Second
+
+// FailedScenario is a scenario that failed in the run.
+type FailedScenario struct {
+	Scenario string
+	RunID    string
+}
+
+// Summary is what a notification reports about a test run.
+type Summary struct {
+	Project   string
+	Total     int
+	Passed    int
+	Failed    int
+	Skipped   int
+	Duration  time.Duration
+	TotalCost float64
+
+	// PreviousCost is the previous run's total cost (nil on the first run)
+	PreviousCost *float64
+
+	FailedScenarios []FailedScenario
+}
+
+// PassRate is the percentage of scenarios that passed.
+func (s *Summary) PassRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Passed) / float64(s.Total) * 100
+}
+
+// state is persisted between runs.
+type state struct {
+	TotalCost  float64   `json:"total_cost"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Notifier sends summaries to the configured channels.
+type Notifier struct {
+	project   string
+	channels  []config.NotificationConfig
+	client    *http.Client
+	statePath string
+}
+
+// NewNotifier builds a notifier from the notifications config.
+// It returns nil when no notifications are configured.
+func NewNotifier(cfg *config.Config) *Notifier {
+	if len(cfg.Notifications) == 0 {
+		return nil
+	}
+
+	return &Notifier{
+		project:   cfg.Name,
+		channels:  cfg.Notifications,
+		client:    &http.Client{Timeout: requestTimeout},
+		statePath: StatePath,
+	}
+}
+
+// Notify fills in the cost delta, records this run's cost for the next
+// one, and posts the summary to every channel whose condition matches.
+// A failing channel does not stop the others; all errors are returned.
+func (n *Notifier) Notify(ctx context.Context, summary *Summary) error {
+	if summary.Project == "" {
+		summary.Project = n.project
+	}
+	if previous, ok := n.loadState(); ok {
+		summary.PreviousCost = &previous.TotalCost
+	}
+
+	var errs []error
+	if err := n.saveState(summary); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, channel := range n.channels {
+		if channel.On != "always" && summary.Failed == 0 {
+			continue
+		}
+
+		if err := n.send(ctx, channel, summary); err != nil {
+			errs = append(errs, fmt.Errorf("%s notification failed: %w", channel.Type, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) send(ctx context.Context, channel config.NotificationConfig, summary *Summary) error {
+	webhookURL := os.ExpandEnv(channel.WebhookURL)
+	if webhookURL == "" {
+		return fmt.Errorf("webhook_url %q expanded to an empty URL", channel.WebhookURL)
+	}
+
+	var payload interface{}
+	switch channel.Type {
+	case "slack":
+		payload = slackPayload(summary)
+	case "teams":
+		payload = teamsPayload(summary)
+	default:
+		return fmt.Errorf("unsupported type: %s", channel.Type)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
+
+func (n *Notifier) loadState() (*state, bool) {
+	data, err := os.ReadFile(n.statePath)
+	if err != nil {
+		return nil, false
+	}
+
+	var previous state
+	if err := json.Unmarshal(data, &previous); err != nil {
+		return nil, false
+	}
+	return &previous, true
+}
+
+func (n *Notifier) saveState(summary *Summary) error {
+	data, err := json.MarshalIndent(state{
+		TotalCost:  summary.TotalCost,
+		FinishedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(n.statePath), 0755); err != nil {
+		return fmt.Errorf("failed to save notification state: %w", err)
+	}
+	if err := os.WriteFile(n.statePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save notification state: %w", err)
+	}
+	return nil
+}
+
+// title is the headline shared by every channel type.
+func title(summary *Summary) string {
+	icon := "✅"
+	if summary.Failed > 0 {
+		icon = "❌"
+	}
+
+	project := summary.Project
+	if project == "" {
+		project = "Sentra Lab"
+	}
+
+	return fmt.Sprintf("%s %s: %d/%d scenarios passed (%.1f%%)",
+		icon, project, summary.Passed, summary.Total, summary.PassRate())
+}
+
+// costLine reports total cost and its change since the previous run.
+func costLine(summary *Summary) string {
+	line := fmt.Sprintf("$%.4f", summary.TotalCost)
+	if summary.PreviousCost == nil {
+		return line
+	}
+
+	delta := summary.TotalCost - *summary.PreviousCost
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+		delta = -delta
+	}
+
+	line += fmt.Sprintf(" (%s$%.4f vs previous run", sign, delta)
+	if *summary.PreviousCost > 0 {
+		line += fmt.Sprintf(", %s%.1f%%", sign, delta / *summary.PreviousCost * 100)
+	}
+	return line + ")"
+}
+
+// listedFailures returns the failures to list and how many were left out.
+func listedFailures(summary *Summary) ([]FailedScenario, int) {
+	if len(summary.FailedScenarios) <= maxListedFailures {
+		return summary.FailedScenarios, 0
+	}
+	return summary.FailedScenarios[:maxListedFailures], len(summary.FailedScenarios) - maxListedFailures
+}
+
+func slackPayload(summary *Summary) map[string]interface{} {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*%s*\n", title(summary))
+	fmt.Fprintf(&text, "Duration: %s · Cost: %s", summary.Duration.Round(time.Second), costLine(summary))
+	if summary.Skipped > 0 {
+		fmt.Fprintf(&text, " · Skipped: %d", summary.Skipped)
+	}
+
+	failures, more := listedFailures(summary)
+	if len(failures) > 0 {
+		text.WriteString("\n*Failed scenarios:*")
+		for _, failure := range failures {
+			fmt.Fprintf(&text, "\n• <%s%s|%s>", CloudRunURL, failure.RunID, failure.Scenario)
+		}
+		if more > 0 {
+			fmt.Fprintf(&text, "\n…and %d more", more)
+		}
+	}
+
+	return map[string]interface{}{
+		"text": title(summary),
+		"blocks": []interface{}{
+			map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{
+					"type": "mrkdwn",
+					"text": text.String(),
+				},
+			},
+		},
+	}
+}
+
+func teamsPayload(summary *Summary) map[string]interface{} {
+	color := "2EB886"
+	if summary.Failed > 0 {
+		color = "D00000"
+	}
+
+	facts := []interface{}{
+		map[string]interface{}{"name": "Pass rate", "value": fmt.Sprintf("%.1f%% (%d/%d)", summary.PassRate(), summary.Passed, summary.Total)},
+		map[string]interface{}{"name": "Duration", "value": summary.Duration.Round(time.Second).String()},
+		map[string]interface{}{"name": "Cost", "value": costLine(summary)},
+	}
+	if summary.Skipped > 0 {
+		facts = append(facts, map[string]interface{}{"name": "Skipped", "value": fmt.Sprintf("%d", summary.Skipped)})
+	}
+
+	var text strings.Builder
+	failures, more := listedFailures(summary)
+	if len(failures) > 0 {
+		text.WriteString("**Failed scenarios:**\n\n")
+		for _, failure := range failures {
+			fmt.Fprintf(&text, "- [%s](%s%s)\n", failure.Scenario, CloudRunURL, failure.RunID)
+		}
+		if more > 0 {
+			fmt.Fprintf(&text, "- …and %d more\n", more)
+		}
+	}
+
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": color,
+		"summary":    title(summary),
+		"title":      title(summary),
+		"sections": []interface{}{
+			map[string]interface{}{
+				"facts": facts,
+				"text":  text.String(),
+			},
+		},
+	}
+}
@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// webhook is a fake incoming webhook recording the messages it receives.
+type webhook struct {
+	*httptest.Server
+	messages []map[string]interface{}
+}
+
+func newWebhook(t *testing.T, status int) *webhook {
+	t.Helper()
+	hook := &webhook{}
+	hook.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&message)
+		hook.messages = append(hook.messages, message)
+		w.WriteHeader(status)
+		if status >= 300 {
+			w.Write([]byte("invalid_token"))
+		}
+	}))
+	t.Cleanup(hook.Close)
+	return hook
+}
+
+func newTestNotifier(t *testing.T, channels ...config.NotificationConfig) *Notifier {
+	t.Helper()
+	return &Notifier{
+		project:   "support-agent",
+		channels:  channels,
+		client:    http.DefaultClient,
+		statePath: filepath.Join(t.TempDir(), "notify-state.json"),
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	if n := NewNotifier(&config.Config{}); n != nil {
+		t.Errorf("NewNotifier() without notifications = %+v, want nil", n)
+	}
+
+	n := NewNotifier(&config.Config{Name: "demo", Notifications: []config.NotificationConfig{{Type: "slack"}}})
+	if n == nil || n.project != "demo" || n.statePath != StatePath {
+		t.Errorf("NewNotifier() = %+v", n)
+	}
+}
+
+func TestPassRate(t *testing.T) {
+	if got := (&Summary{Total: 8, Passed: 6}).PassRate(); got != 75 {
+		t.Errorf("PassRate() = %v, want 75", got)
+	}
+	if got := (&Summary{}).PassRate(); got != 0 {
+		t.Errorf("PassRate() without scenarios = %v, want 0", got)
+	}
+}
+
+func TestCostLine(t *testing.T) {
+	previous := func(cost float64) *float64 { return &cost }
+	tests := []struct {
+		summary Summary
+		want    string
+	}{
+		{Summary{TotalCost: 0.5}, "$0.5000"},
+		{Summary{TotalCost: 0.6, PreviousCost: previous(0.5)}, "$0.6000 (+$0.1000 vs previous run, +20.0%)"},
+		{Summary{TotalCost: 0.25, PreviousCost: previous(0.5)}, "$0.2500 (-$0.2500 vs previous run, -50.0%)"},
+		{Summary{TotalCost: 0.1, PreviousCost: previous(0)}, "$0.1000 (+$0.1000 vs previous run)"},
+	}
+
+	for _, tt := range tests {
+		if got := costLine(&tt.summary); got != tt.want {
+			t.Errorf("costLine() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	slack := newWebhook(t, http.StatusOK)
+	teams := newWebhook(t, http.StatusOK)
+	t.Setenv("TEAMS_WEBHOOK", teams.URL)
+	n := newTestNotifier(t,
+		config.NotificationConfig{Type: "slack", WebhookURL: slack.URL, On: "failure"},
+		config.NotificationConfig{Type: "teams", WebhookURL: "${TEAMS_WEBHOOK}", On: "always"},
+	)
+
+	// A passing run only goes to channels notified always
+	passed := &Summary{Total: 2, Passed: 2, Duration: 90 * time.Second, TotalCost: 0.5}
+	if err := n.Notify(context.Background(), passed); err != nil {
+		t.Fatal(err)
+	}
+	if len(slack.messages) != 0 || len(teams.messages) != 1 {
+		t.Fatalf("messages = %d slack, %d teams; want only teams", len(slack.messages), len(teams.messages))
+	}
+	if got := teams.messages[0]["title"]; got != "✅ support-agent: 2/2 scenarios passed (100.0%)" {
+		t.Errorf("teams title = %v", got)
+	}
+
+	// The next run reports the cost delta and links its failures
+	failed := &Summary{
+		Total: 2, Passed: 1, Failed: 1, TotalCost: 0.6,
+		FailedScenarios: []FailedScenario{{Scenario: "refund.yaml", RunID: "run_42"}},
+	}
+	if err := n.Notify(context.Background(), failed); err != nil {
+		t.Fatal(err)
+	}
+	if failed.PreviousCost == nil || *failed.PreviousCost != 0.5 {
+		t.Errorf("PreviousCost = %v, want 0.5", failed.PreviousCost)
+	}
+	if len(slack.messages) != 1 {
+		t.Fatalf("slack messages = %d, want 1", len(slack.messages))
+	}
+	text := slack.messages[0]["blocks"].([]interface{})[0].(map[string]interface{})["text"].(map[string]interface{})["text"].(string)
+	for _, want := range []string{"❌ support-agent: 1/2", "+$0.1000 vs previous run", "<" + CloudRunURL + "run_42|refund.yaml>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slack text is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestNotifyErrors(t *testing.T) {
+	rejecting := newWebhook(t, http.StatusForbidden)
+	working := newWebhook(t, http.StatusOK)
+	n := newTestNotifier(t,
+		config.NotificationConfig{Type: "slack", WebhookURL: rejecting.URL, On: "always"},
+		config.NotificationConfig{Type: "teams", WebhookURL: "${SENTRA_UNSET_WEBHOOK}", On: "always"},
+		config.NotificationConfig{Type: "slack", WebhookURL: working.URL, On: "always"},
+	)
+
+	err := n.Notify(context.Background(), &Summary{Total: 1, Passed: 1})
+	if err == nil {
+		t.Fatal("Notify() error = nil")
+	}
+	for _, want := range []string{"slack notification failed: webhook returned 403: invalid_token", "teams notification failed", "empty URL"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Notify() error = %v, want %s", err, want)
+		}
+	}
+	// A failing channel does not stop the others
+	if len(working.messages) != 1 {
+		t.Errorf("working channel messages = %d, want 1", len(working.messages))
+	}
+}
+
+func TestListedFailures(t *testing.T) {
+	summary := &Summary{}
+	for i := 0; i < maxListedFailures+3; i++ {
+		summary.FailedScenarios = append(summary.FailedScenarios, FailedScenario{Scenario: "s.yaml"})
+	}
+
+	failures, more := listedFailures(summary)
+	if len(failures) != maxListedFailures || more != 3 {
+		t.Errorf("listedFailures() = %d, %d; want %d, 3", len(failures), more, maxListedFailures)
+	}
+}
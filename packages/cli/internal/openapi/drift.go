@@ -0,0 +1,582 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpstreamSpecs are the published specs of the real APIs the mocks imitate
+var UpstreamSpecs = map[string]string{
+	"openai": "https://raw.githubusercontent.com/openai/openai-openapi/master/openapi.yaml",
+}
+
+// downloadTimeout bounds downloading an upstream spec (OpenAI's is several MB)
+const downloadTimeout = 60 * time.Second
+
+// maxFieldDepth bounds how deep request and response bodies are compared
+const maxFieldDepth = 6
+
+// Spec is a parsed OpenAPI document (JSON or YAML).
+type Spec struct {
+	doc  map[string]interface{}
+	base string
+}
+
+// Load reads a spec from a URL or a file.
+func Load(ctx context.Context, source string) (*Spec, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		return Parse(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: status %d", source, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses a spec. Paths are resolved against the first server's path,
+// so OpenAI's /chat/completions (served under /v1) matches the mock's
+// /v1/chat/completions.
+func Parse(data []byte) (*Spec, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if _, ok := doc["paths"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: no paths")
+	}
+
+	spec := &Spec{doc: doc}
+	if servers, ok := doc["servers"].([]interface{}); ok && len(servers) > 0 {
+		if server, ok := servers[0].(map[string]interface{}); ok {
+			if raw, ok := server["url"].(string); ok {
+				if parsed, err := url.Parse(raw); err == nil {
+					spec.base = strings.TrimSuffix(parsed.Path, "/")
+				}
+			}
+		}
+	}
+	return spec, nil
+}
+
+// Version is the spec's info.version.
+func (s *Spec) Version() string {
+	info, _ := s.doc["info"].(map[string]interface{})
+	version, _ := info["version"].(string)
+	return version
+}
+
+// Endpoint is an operation of a spec.
+type Endpoint struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Summary    string `json:"summary,omitempty"`
+	Group      string `json:"group,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+
+	operation map[string]interface{}
+}
+
+func (e Endpoint) String() string {
+	return e.Method + " " + e.Path
+}
+
+var (
+	pathParam   = regexp.MustCompile(`\{[^}]*\}`)
+	httpMethods = []string{"get", "put", "post", "delete", "patch"}
+)
+
+// endpoints returns the spec's operations keyed by method and path with
+// parameter names dropped (/threads/{} matches /threads/{thread_id}).
+func (s *Spec) endpoints() map[string]Endpoint {
+	endpoints := make(map[string]Endpoint)
+	paths, _ := s.doc["paths"].(map[string]interface{})
+	for path, raw := range paths {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range httpMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			endpoint := Endpoint{
+				Method:    strings.ToUpper(method),
+				Path:      s.base + path,
+				operation: operation,
+			}
+			endpoint.Summary, _ = operation["summary"].(string)
+			endpoint.Deprecated, _ = operation["deprecated"].(bool)
+			if tags, ok := operation["tags"].([]interface{}); ok && len(tags) > 0 {
+				endpoint.Group, _ = tags[0].(string)
+			}
+			endpoints[endpoint.Method+" "+pathParam.ReplaceAllString(endpoint.Path, "{}")] = endpoint
+		}
+	}
+	return endpoints
+}
+
+// field is a property of a body, by dotted path (messages[].role).
+type field struct {
+	// enum lists the accepted values, when the schema restricts them
+	enum []string
+
+	// open is set for schemas that accept any value, whose sub-fields
+	// cannot be compared
+	open bool
+}
+
+// bodyFields returns the fields of an operation's request body or 200
+// response.
+func (s *Spec) bodyFields(operation map[string]interface{}, response bool) map[string]*field {
+	var body map[string]interface{}
+	if response {
+		responses, _ := operation["responses"].(map[string]interface{})
+		body, _ = s.resolve(responses["200"])
+	} else {
+		body, _ = s.resolve(operation["requestBody"])
+	}
+
+	fields := make(map[string]*field)
+	content, _ := body["content"].(map[string]interface{})
+	for _, contentType := range []string{"application/json", "multipart/form-data"} {
+		if media, ok := content[contentType].(map[string]interface{}); ok {
+			s.collect(media["schema"], "", 0, map[string]bool{}, fields)
+			break
+		}
+	}
+	return fields
+}
+
+// resolve follows a $ref to a component.
+func (s *Spec) resolve(raw interface{}) (map[string]interface{}, string) {
+	schema, _ := raw.(map[string]interface{})
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema, ""
+	}
+
+	var node interface{} = s.doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		parent, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, ref
+		}
+		node = parent[part]
+	}
+	resolved, _ := node.(map[string]interface{})
+	return resolved, ref
+}
+
+// collect adds the fields of schema under prefix, merging the variants of
+// allOf, oneOf and anyOf.
+func (s *Spec) collect(raw interface{}, prefix string, depth int, seen map[string]bool, fields map[string]*field) {
+	schema, ref := s.resolve(raw)
+	if schema == nil || depth > maxFieldDepth {
+		return
+	}
+	if ref != "" {
+		if seen[ref] {
+			return
+		}
+		seen = copySeen(seen)
+		seen[ref] = true
+	}
+
+	if prefix != "" {
+		f := fields[prefix]
+		if values, ok := schema["enum"].([]interface{}); ok {
+			for _, value := range values {
+				if str, ok := value.(string); ok {
+					f.enum = appendUnique(f.enum, str)
+				}
+			}
+		}
+		if value, ok := schema["const"].(string); ok {
+			f.enum = appendUnique(f.enum, value)
+		}
+		if isOpen(schema) {
+			f.open = true
+		}
+	}
+
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if variants, ok := schema[key].([]interface{}); ok {
+			for _, variant := range variants {
+				s.collect(variant, prefix, depth, seen, fields)
+			}
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			if fields[path] == nil {
+				fields[path] = &field{}
+			}
+			s.collect(property, path, depth+1, seen, fields)
+		}
+	}
+
+	if items, ok := schema["items"]; ok && prefix != "" {
+		path := prefix + "[]"
+		if fields[path] == nil {
+			fields[path] = &field{}
+		}
+		s.collect(items, path, depth+1, seen, fields)
+	}
+}
+
+// isOpen reports whether a schema accepts any value.
+func isOpen(schema map[string]interface{}) bool {
+	for _, key := range []string{"type", "$ref", "properties", "items", "allOf", "oneOf", "anyOf", "enum", "const"} {
+		if _, ok := schema[key]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// EnumDrift lists the values a field accepts upstream but not in the mock.
+type EnumDrift struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values"`
+}
+
+// OperationDrift is how an endpoint both specs have differs.
+type OperationDrift struct {
+	Endpoint
+	MissingRequestFields  []string    `json:"missing_request_fields,omitempty"`
+	MissingResponseFields []string    `json:"missing_response_fields,omitempty"`
+	MissingEnumValues     []EnumDrift `json:"missing_enum_values,omitempty"`
+}
+
+// Report is the parity of a mock with the real API.
+type Report struct {
+	Service          string           `json:"service"`
+	Upstream         string           `json:"upstream"`
+	UpstreamVersion  string           `json:"upstream_version"`
+	Endpoints        int              `json:"endpoints"`
+	Implemented      int              `json:"implemented"`
+	MissingEndpoints []Endpoint       `json:"missing_endpoints"`
+	Drift            []OperationDrift `json:"drift"`
+	MockOnly         []Endpoint       `json:"mock_only"`
+}
+
+// HasDrift reports whether the mock differs from the real API at all.
+func (r *Report) HasDrift() bool {
+	return len(r.MissingEndpoints) > 0 || len(r.Drift) > 0
+}
+
+// Compare diffs the mock's implemented surface against the real API's spec:
+// endpoints the mock lacks and, for those it has, request and response
+// fields and enum values it does not accept or return.
+func Compare(upstream, mock *Spec) *Report {
+	report := &Report{
+		UpstreamVersion:  upstream.Version(),
+		MissingEndpoints: []Endpoint{},
+		Drift:            []OperationDrift{},
+		MockOnly:         []Endpoint{},
+	}
+
+	upstreamEndpoints := upstream.endpoints()
+	mockEndpoints := mock.endpoints()
+	report.Endpoints = len(upstreamEndpoints)
+
+	for key, endpoint := range upstreamEndpoints {
+		mockEndpoint, ok := mockEndpoints[key]
+		if !ok {
+			report.MissingEndpoints = append(report.MissingEndpoints, endpoint)
+			continue
+		}
+		report.Implemented++
+
+		drift := OperationDrift{Endpoint: endpoint}
+		var enums []EnumDrift
+		drift.MissingRequestFields, enums = diffFields(upstream.bodyFields(endpoint.operation, false), mock.bodyFields(mockEndpoint.operation, false))
+		drift.MissingEnumValues = append(drift.MissingEnumValues, enums...)
+		drift.MissingResponseFields, enums = diffFields(upstream.bodyFields(endpoint.operation, true), mock.bodyFields(mockEndpoint.operation, true))
+		drift.MissingEnumValues = append(drift.MissingEnumValues, enums...)
+
+		if len(drift.MissingRequestFields) > 0 || len(drift.MissingResponseFields) > 0 || len(drift.MissingEnumValues) > 0 {
+			report.Drift = append(report.Drift, drift)
+		}
+	}
+
+	for key, endpoint := range mockEndpoints {
+		if _, ok := upstreamEndpoints[key]; !ok {
+			report.MockOnly = append(report.MockOnly, endpoint)
+		}
+	}
+
+	sortEndpoints(report.MissingEndpoints)
+	sortEndpoints(report.MockOnly)
+	sort.Slice(report.Drift, func(i, j int) bool {
+		return endpointLess(report.Drift[i].Endpoint, report.Drift[j].Endpoint)
+	})
+	return report
+}
+
+// diffFields returns the upstream fields the mock lacks (only the
+// outermost of a missing subtree, and nothing below fields the mock leaves
+// open) and the enum values it does not accept.
+func diffFields(upstream, mock map[string]*field) ([]string, []EnumDrift) {
+	if len(mock) == 0 {
+		// The mock does not describe this body; nothing to compare
+		return nil, nil
+	}
+
+	var missing []string
+	var enums []EnumDrift
+	for path, want := range upstream {
+		if underOpen(path, mock) {
+			continue
+		}
+
+		have, ok := mock[path]
+		if !ok {
+			if parent := parentField(path); parent == "" || mock[parent] != nil {
+				missing = append(missing, path)
+			}
+			continue
+		}
+
+		if len(want.enum) == 0 || len(have.enum) == 0 {
+			continue
+		}
+		var values []string
+		for _, value := range want.enum {
+			if !contains(have.enum, value) {
+				values = append(values, value)
+			}
+		}
+		if len(values) > 0 {
+			enums = append(enums, EnumDrift{Field: path, Values: values})
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Slice(enums, func(i, j int) bool { return enums[i].Field < enums[j].Field })
+	return missing, enums
+}
+
+// underOpen reports whether path is below a mock field that accepts any
+// value.
+func underOpen(path string, mock map[string]*field) bool {
+	for parent := parentField(path); parent != ""; parent = parentField(parent) {
+		if f, ok := mock[parent]; ok && f.open {
+			return true
+		}
+	}
+	return false
+}
+
+// parentField returns the field containing path ("" at the top level):
+// messages[].role -> messages[] -> messages.
+func parentField(path string) string {
+	if strings.HasSuffix(path, "[]") {
+		return strings.TrimSuffix(path, "[]")
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// WriteText writes the report for terminals.
+func WriteText(w io.Writer, r *Report) {
+	fmt.Fprintf(w, "%s mock parity with %s (spec %s)\n", r.Service, r.Upstream, r.UpstreamVersion)
+	fmt.Fprintf(w, "  %d of %d endpoints implemented (%s), %d with drift\n\n", r.Implemented, r.Endpoints, percent(r.Implemented, r.Endpoints), len(r.Drift))
+
+	if len(r.Drift) > 0 {
+		fmt.Fprintln(w, "Drift in implemented endpoints:")
+		for _, drift := range r.Drift {
+			fmt.Fprintf(w, "  %s\n", drift.Endpoint)
+			if len(drift.MissingRequestFields) > 0 {
+				fmt.Fprintf(w, "    request fields:  %s\n", strings.Join(drift.MissingRequestFields, ", "))
+			}
+			if len(drift.MissingResponseFields) > 0 {
+				fmt.Fprintf(w, "    response fields: %s\n", strings.Join(drift.MissingResponseFields, ", "))
+			}
+			for _, enum := range drift.MissingEnumValues {
+				fmt.Fprintf(w, "    %s values: %s\n", enum.Field, strings.Join(enum.Values, ", "))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.MissingEndpoints) > 0 {
+		fmt.Fprintf(w, "Missing endpoints (%d):\n", len(r.MissingEndpoints))
+		group := "\x00"
+		for _, endpoint := range r.MissingEndpoints {
+			if endpoint.Group != group {
+				group = endpoint.Group
+				fmt.Fprintf(w, "  %s\n", groupName(group))
+			}
+			fmt.Fprintf(w, "    %-7s %s%s\n", endpoint.Method, endpoint.Path, deprecatedNote(endpoint))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.MockOnly) > 0 {
+		fmt.Fprintf(w, "Only in the mock (%d):\n", len(r.MockOnly))
+		for _, endpoint := range r.MockOnly {
+			fmt.Fprintf(w, "  %-7s %s\n", endpoint.Method, endpoint.Path)
+		}
+	}
+}
+
+// WriteMarkdown writes the report as Markdown, e.g. for a wiki page or an
+// issue tracking parity work.
+func WriteMarkdown(w io.Writer, r *Report) {
+	fmt.Fprintf(w, "# %s mock parity\n\n", r.Service)
+	fmt.Fprintf(w, "Compared with %s (spec %s): **%d of %d endpoints implemented (%s)**, %d with drift.\n\n",
+		r.Upstream, r.UpstreamVersion, r.Implemented, r.Endpoints, percent(r.Implemented, r.Endpoints), len(r.Drift))
+
+	if len(r.Drift) > 0 {
+		fmt.Fprintln(w, "## Drift in implemented endpoints")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Endpoint | Missing request fields | Missing response fields | Missing enum values |")
+		fmt.Fprintln(w, "|---|---|---|---|")
+		for _, drift := range r.Drift {
+			var enums []string
+			for _, enum := range drift.MissingEnumValues {
+				enums = append(enums, fmt.Sprintf("`%s`: %s", enum.Field, strings.Join(enum.Values, ", ")))
+			}
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", drift.Endpoint, codeList(drift.MissingRequestFields), codeList(drift.MissingResponseFields), strings.Join(enums, "<br>"))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.MissingEndpoints) > 0 {
+		fmt.Fprintln(w, "## Missing endpoints")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Tests cannot cover agent code that uses these yet.")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Group | Endpoint | Summary |")
+		fmt.Fprintln(w, "|---|---|---|")
+		for _, endpoint := range r.MissingEndpoints {
+			fmt.Fprintf(w, "| %s | `%s` | %s%s |\n", groupName(endpoint.Group), endpoint, endpoint.Summary, deprecatedNote(endpoint))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.MockOnly) > 0 {
+		fmt.Fprintln(w, "## Only in the mock")
+		fmt.Fprintln(w)
+		for _, endpoint := range r.MockOnly {
+			fmt.Fprintf(w, "- `%s`\n", endpoint)
+		}
+	}
+}
+
+// WriteJSON writes the report as JSON.
+func WriteJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func sortEndpoints(endpoints []Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpointLess(endpoints[i], endpoints[j])
+	})
+}
+
+func endpointLess(a, b Endpoint) bool {
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	if a.Path != b.Path {
+		return a.Path < b.Path
+	}
+	return a.Method < b.Method
+}
+
+func groupName(group string) string {
+	if group == "" {
+		return "Other"
+	}
+	return group
+}
+
+func deprecatedNote(endpoint Endpoint) string {
+	if endpoint.Deprecated {
+		return " (deprecated)"
+	}
+	return ""
+}
+
+func codeList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "`" + value + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func percent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
+}
+
+func copySeen(seen map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(seen)+1)
+	for key := range seen {
+		copied[key] = true
+	}
+	return copied
+}
+
+func appendUnique(values []string, value string) []string {
+	if contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// upstreamSpec is a small OpenAI-style spec, served under /v1.
+const upstreamSpec = `
+openapi: 3.0.0
+info:
+  version: 2.3.0
+servers:
+  - url: https://api.openai.com/v1
+paths:
+  /chat/completions:
+    post:
+      tags: [Chat]
+      summary: Create chat completion
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/CreateChatCompletionRequest'
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id: {type: string}
+                  service_tier: {type: string}
+  /files/{file_id}:
+    get:
+      tags: [Files]
+      summary: Retrieve file
+    delete:
+      tags: [Files]
+      summary: Delete file
+  /engines:
+    get:
+      summary: List engines
+      deprecated: true
+components:
+  schemas:
+    CreateChatCompletionRequest:
+      type: object
+      properties:
+        model: {type: string}
+        reasoning_effort:
+          type: string
+          enum: [low, medium, high]
+        metadata:
+          type: object
+          properties:
+            source: {type: string}
+        messages:
+          type: array
+          items:
+            $ref: '#/components/schemas/Message'
+        response_format:
+          type: object
+          properties:
+            json_schema:
+              type: object
+              properties:
+                strict: {type: boolean}
+    Message:
+      oneOf:
+        - type: object
+          properties:
+            role: {type: string, const: system}
+            content: {type: string}
+        - type: object
+          properties:
+            role: {type: string, enum: [user, assistant]}
+            name: {type: string}
+`
+
+// mockSpec is what a mock publishes for upstreamSpec's API.
+const mockSpec = `{
+  "openapi": "3.1.0",
+  "info": {"version": "1.0.0"},
+  "paths": {
+    "/v1/chat/completions": {
+      "post": {
+        "requestBody": {"content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {
+            "model": {"type": "string"},
+            "reasoning_effort": {"type": "string", "enum": ["low", "medium"]},
+            "messages": {"type": "array", "items": {"type": "object", "properties": {
+              "role": {"type": "string", "enum": ["system", "user", "assistant"]},
+              "content": {"type": "string"}
+            }}},
+            "response_format": {}
+          }
+        }}}},
+        "responses": {"200": {"content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {"id": {"type": "string"}}
+        }}}}}
+      }
+    },
+    "/v1/files/{id}": {"get": {}},
+    "/_sentra/health": {"get": {}}
+  }
+}`
+
+// parseSpec parses a spec or fails the test.
+func parseSpec(t *testing.T, data string) *Spec {
+	t.Helper()
+	spec, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return spec
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantBase    string
+		wantVersion string
+		wantErr     string
+	}{
+		{name: "YAML with a server path", data: upstreamSpec, wantBase: "/v1", wantVersion: "2.3.0"},
+		{name: "JSON without servers", data: mockSpec, wantVersion: "1.0.0"},
+		{name: "no paths", data: "openapi: 3.0.0\n", wantErr: "no paths"},
+		{name: "not a document", data: "- a\n- b\n", wantErr: "invalid OpenAPI document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if spec.base != tt.wantBase || spec.Version() != tt.wantVersion {
+				t.Errorf("Parse() base = %q, version = %q, want %q, %q", spec.base, spec.Version(), tt.wantBase, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(upstreamSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(upstreamSpec))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{name: "file", source: path},
+		{name: "URL", source: server.URL + "/openapi.yaml"},
+		{name: "missing file", source: filepath.Join(t.TempDir(), "missing.yaml"), wantErr: true},
+		{name: "URL not found", source: server.URL + "/missing.yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Load(context.Background(), tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && spec.Version() != "2.3.0" {
+				t.Errorf("Load() version = %q, want 2.3.0", spec.Version())
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	report := Compare(parseSpec(t, upstreamSpec), parseSpec(t, mockSpec))
+
+	if report.UpstreamVersion != "2.3.0" || report.Endpoints != 4 || report.Implemented != 2 || !report.HasDrift() {
+		t.Errorf("Compare() = version %q, %d of %d implemented, drift %v, want 2.3.0, 2 of 4, true",
+			report.UpstreamVersion, report.Implemented, report.Endpoints, report.HasDrift())
+	}
+
+	var missing []string
+	for _, endpoint := range report.MissingEndpoints {
+		missing = append(missing, endpoint.String())
+	}
+	if want := []string{"GET /v1/engines", "DELETE /v1/files/{file_id}"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("MissingEndpoints = %v, want %v", missing, want)
+	}
+	if len(report.MockOnly) != 1 || report.MockOnly[0].String() != "GET /_sentra/health" {
+		t.Errorf("MockOnly = %v, want GET /_sentra/health", report.MockOnly)
+	}
+
+	if len(report.Drift) != 1 {
+		t.Fatalf("Drift = %+v, want one endpoint", report.Drift)
+	}
+	drift := report.Drift[0]
+	if drift.String() != "POST /v1/chat/completions" {
+		t.Errorf("Drift endpoint = %s, want POST /v1/chat/completions", drift.Endpoint)
+	}
+	if want := []string{"messages[].name", "metadata"}; !reflect.DeepEqual(drift.MissingRequestFields, want) {
+		t.Errorf("MissingRequestFields = %v, want %v", drift.MissingRequestFields, want)
+	}
+	if want := []string{"service_tier"}; !reflect.DeepEqual(drift.MissingResponseFields, want) {
+		t.Errorf("MissingResponseFields = %v, want %v", drift.MissingResponseFields, want)
+	}
+	if want := []EnumDrift{{Field: "reasoning_effort", Values: []string{"high"}}}; !reflect.DeepEqual(drift.MissingEnumValues, want) {
+		t.Errorf("MissingEnumValues = %v, want %v", drift.MissingEnumValues, want)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	spec := parseSpec(t, mockSpec)
+	if report := Compare(spec, spec); report.HasDrift() || len(report.MockOnly) != 0 {
+		t.Errorf("Compare() of a spec with itself = %+v, want no drift", report)
+	}
+}
+
+func TestParentField(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "messages[].role", want: "messages[]"},
+		{path: "messages[]", want: "messages"},
+		{path: "response_format.json_schema.strict", want: "response_format.json_schema"},
+		{path: "model", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := parentField(tt.path); got != tt.want {
+				t.Errorf("parentField(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	report := Compare(parseSpec(t, upstreamSpec), parseSpec(t, mockSpec))
+	report.Service = "openai"
+	report.Upstream = "openai-openapi"
+
+	tests := []struct {
+		name  string
+		write func(*bytes.Buffer)
+		want  []string
+	}{
+		{
+			name:  "text",
+			write: func(buf *bytes.Buffer) { WriteText(buf, report) },
+			want: []string{
+				"openai mock parity with openai-openapi (spec 2.3.0)",
+				"2 of 4 endpoints implemented (50%), 1 with drift",
+				"    request fields:  messages[].name, metadata",
+				"    reasoning_effort values: high",
+				"  Other\n    GET     /v1/engines (deprecated)",
+				"  Files\n    DELETE  /v1/files/{file_id}",
+				"Only in the mock (1):",
+			},
+		},
+		{
+			name:  "markdown",
+			write: func(buf *bytes.Buffer) { WriteMarkdown(buf, report) },
+			want: []string{
+				"# openai mock parity",
+				"**2 of 4 endpoints implemented (50%)**",
+				"| `POST /v1/chat/completions` | `messages[].name`, `metadata` | `service_tier` | `reasoning_effort`: high |",
+				"| Files | `DELETE /v1/files/{file_id}` | Delete file |",
+				"- `GET /_sentra/health`",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.write(&buf)
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output = %q, want it to contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, report); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if decoded.Implemented != 2 || len(decoded.Drift) != 1 {
+		t.Errorf("WriteJSON() = %+v, want the report", decoded)
+	}
+}
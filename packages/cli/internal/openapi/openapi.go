@@ -0,0 +1,56 @@
+// Package openapi fetches the OpenAPI specs the mocks generate from their
+// request and response models, for contract testing and typed client
+// generation against exactly what each mock implements.
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// specPath is the mock admin endpoint serving the spec
+const specPath = "/_sentra/openapi"
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 10 * time.Second
+
+// Formats are the supported spec formats
+var Formats = []string{"yaml", "json"}
+
+// ErrNotPublished is returned for mocks that do not serve a spec
+var ErrNotPublished = errors.New("mock does not publish an OpenAPI spec")
+
+// Fetch returns the spec of the mock at baseURL in format (yaml or json),
+// including its /_sentra admin API when admin is set.
+func Fetch(ctx context.Context, baseURL, format string, admin bool) ([]byte, error) {
+	query := url.Values{"format": {format}}
+	if admin {
+		query.Set("admin", "true")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+specPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch spec (is the lab running?): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotPublished
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch spec: status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
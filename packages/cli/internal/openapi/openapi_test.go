@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		admin     bool
+		wantQuery string
+		wantErr   string
+	}{
+		{name: "spec", status: http.StatusOK, wantQuery: "format=json"},
+		{name: "with admin API", status: http.StatusOK, admin: true, wantQuery: "admin=true&format=json"},
+		{name: "not published", status: http.StatusNotFound, wantQuery: "format=json", wantErr: ErrNotPublished.Error()},
+		{name: "server error", status: http.StatusInternalServerError, wantQuery: "format=json", wantErr: "status 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != specPath || r.URL.RawQuery != tt.wantQuery {
+					t.Errorf("request = %s?%s, want %s?%s", r.URL.Path, r.URL.RawQuery, specPath, tt.wantQuery)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"openapi": "3.1.0"}`))
+			}))
+			defer server.Close()
+
+			spec, err := Fetch(context.Background(), server.URL, "json", tt.admin)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Fetch() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if string(spec) != `{"openapi": "3.1.0"}` {
+				t.Errorf("Fetch() = %s, want the served spec", spec)
+			}
+		})
+	}
+}
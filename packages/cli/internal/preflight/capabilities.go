@@ -0,0 +1,196 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// capabilitiesPath is the mock endpoint reporting what the running image
+// implements
+const capabilitiesPath = "/v1/__sentra/capabilities"
+
+// Capabilities is what a mock reports it provides.
+type Capabilities struct {
+	Mock    string `json:"mock"`
+	Version string `json:"version"`
+
+	// Endpoints are the implemented API routes ("METHOD path")
+	Endpoints []string `json:"endpoints"`
+
+	// APIs and Admin are the implemented API and admin API families
+	// (e.g. "assistants", "capture")
+	APIs  []string `json:"apis"`
+	Admin []string `json:"admin"`
+
+	// Models are the models the mock serves
+	Models []string `json:"models"`
+
+	// Features are the optional behaviors enabled (e.g. "rate_limits")
+	Features []string `json:"features"`
+}
+
+// Provides reports whether the mock has a capability: an API family, an
+// admin API, an enabled feature or a model.
+func (c Capabilities) Provides(capability string) bool {
+	return slices.Contains(c.APIs, capability) ||
+		slices.Contains(c.Admin, capability) ||
+		slices.Contains(c.Features, capability) ||
+		slices.Contains(c.Models, capability)
+}
+
+// MissingCapabilities checks what scenarios require (scenario -> mock ->
+// capabilities) against the running mocks and returns a warning for each
+// scenario a mock cannot serve. Mocks that do not report their
+// capabilities are warned about once.
+func (c *Checker) MissingCapabilities(ctx context.Context, required map[string]map[string][]string) []string {
+	findings := c.checkRequirements(ctx, required)
+	warnings := make([]string, len(findings))
+	for i, finding := range findings {
+		warnings[i] = finding.message
+	}
+	return warnings
+}
+
+// requirementFinding is a scenario requirement that could not be checked
+// or, when missing is set, that a mock does not meet.
+type requirementFinding struct {
+	message string
+	missing bool
+}
+
+// checkRequirements checks what scenarios require against the running
+// mocks.
+func (c *Checker) checkRequirements(ctx context.Context, required map[string]map[string][]string) []requirementFinding {
+	scenarios := make([]string, 0, len(required))
+	for scenario := range required {
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Strings(scenarios)
+
+	reported := make(map[string]*Capabilities)
+	failed := make(map[string]bool)
+
+	var findings []requirementFinding
+	for _, scenario := range scenarios {
+		mocks := make([]string, 0, len(required[scenario]))
+		for mock := range required[scenario] {
+			mocks = append(mocks, mock)
+		}
+		sort.Strings(mocks)
+
+		for _, name := range mocks {
+			mock, enabled := c.mocks[name]
+			if !enabled {
+				findings = append(findings, requirementFinding{
+					message: fmt.Sprintf("%s requires the %s mock, which is not enabled in lab.yaml", scenario, name),
+					missing: true,
+				})
+				continue
+			}
+			if failed[name] {
+				continue
+			}
+
+			capabilities, ok := reported[name]
+			if !ok {
+				var err error
+				capabilities, err = c.capabilities(ctx, mock.BaseURL())
+				if err != nil {
+					findings = append(findings, requirementFinding{message: fmt.Sprintf("cannot check what the %s mock provides: %v", name, err)})
+					failed[name] = true
+					continue
+				}
+				if capabilities == nil {
+					findings = append(findings, requirementFinding{message: fmt.Sprintf("the %s mock does not report its capabilities (its image predates capability discovery); scenarios requiring newer features may fail", name)})
+					failed[name] = true
+					continue
+				}
+				reported[name] = capabilities
+			}
+
+			var missing []string
+			for _, capability := range required[scenario][name] {
+				if !capabilities.Provides(capability) {
+					missing = append(missing, capability)
+				}
+			}
+			if len(missing) > 0 {
+				findings = append(findings, requirementFinding{
+					message: fmt.Sprintf("%s requires %s from the %s mock, which version %s does not provide; update its image",
+						scenario, strings.Join(missing, ", "), name, capabilities.Version),
+					missing: true,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// CheckTargets checks that the externally managed mocks (`sentra lab test
+// --target`) are the mocks lab.yaml expects, by the identity they report,
+// and that each provides what the scenarios require (scenario -> mock ->
+// capabilities). Unlike local mocks, whose images `sentra lab start`
+// controls, a mismatch here fails the run. The error lists every problem.
+func (c *Checker) CheckTargets(ctx context.Context, required map[string]map[string][]string) error {
+	names := make([]string, 0, len(c.mocks))
+	for name, mock := range c.mocks {
+		if mock.URL != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		mock := c.mocks[name]
+		capabilities, err := c.capabilities(ctx, mock.BaseURL())
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("  • %s mock at %s: cannot check what it provides: %v", name, mock.URL, err))
+		case capabilities != nil && capabilities.Mock != "" && capabilities.Mock != name:
+			problems = append(problems, fmt.Sprintf("  • %s mock at %s: reports itself as the %s mock", name, mock.URL, capabilities.Mock))
+		}
+	}
+
+	// Requirements on mocks that do not report capabilities stay warnings:
+	// there is nothing to check them against
+	for _, finding := range c.checkRequirements(ctx, required) {
+		if finding.missing {
+			problems = append(problems, "  • "+finding.message)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", finding.message)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the --target mocks cannot run these scenarios, none were run:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// capabilities fetches what a mock provides, or nil if the mock does not
+// report it.
+func (c *Checker) capabilities(ctx context.Context, baseURL string) (*Capabilities, error) {
+	status, body, err := c.get(ctx, baseURL+capabilitiesPath, "")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("status %d", status)
+	}
+
+	var capabilities Capabilities
+	if err := json.Unmarshal(body, &capabilities); err != nil {
+		return nil, fmt.Errorf("invalid capabilities: %w", err)
+	}
+	return &capabilities, nil
+}
@@ -0,0 +1,139 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// reportCapabilities returns a mock handler that reports capabilities, or
+// answers 404 when they are nil.
+func reportCapabilities(capabilities *Capabilities) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != capabilitiesPath || capabilities == nil {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(capabilities)
+	}
+}
+
+func TestCapabilitiesProvides(t *testing.T) {
+	capabilities := Capabilities{
+		APIs:     []string{"assistants"},
+		Admin:    []string{"capture"},
+		Models:   []string{"gpt-4o"},
+		Features: []string{"rate_limits"},
+	}
+
+	for _, capability := range []string{"assistants", "capture", "gpt-4o", "rate_limits"} {
+		if !capabilities.Provides(capability) {
+			t.Errorf("Provides(%q) = false, want true", capability)
+		}
+	}
+	if capabilities.Provides("realtime") {
+		t.Error(`Provides("realtime") = true, want false`)
+	}
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	openai := &Capabilities{Mock: "openai", Version: "1.4.0", APIs: []string{"chat", "assistants"}, Models: []string{"gpt-4o"}}
+
+	tests := []struct {
+		name     string
+		reported *Capabilities
+		required map[string]map[string][]string
+		want     []string
+	}{
+		{
+			name:     "provided",
+			reported: openai,
+			required: map[string]map[string][]string{"scenarios/a.yaml": {"openai": {"assistants", "gpt-4o"}}},
+		},
+		{
+			name:     "missing",
+			reported: openai,
+			required: map[string]map[string][]string{
+				"scenarios/b.yaml": {"openai": {"realtime", "chat", "batch"}},
+				"scenarios/a.yaml": {"openai": {"chat"}},
+			},
+			want: []string{"scenarios/b.yaml requires realtime, batch from the openai mock, which version 1.4.0 does not provide; update its image"},
+		},
+		{
+			name:     "mock not enabled",
+			reported: openai,
+			required: map[string]map[string][]string{"scenarios/a.yaml": {"stripe": {"webhooks"}}},
+			want:     []string{"scenarios/a.yaml requires the stripe mock, which is not enabled in lab.yaml"},
+		},
+		{
+			name:     "not reported",
+			reported: nil,
+			required: map[string]map[string][]string{
+				"scenarios/a.yaml": {"openai": {"chat"}},
+				"scenarios/b.yaml": {"openai": {"assistants"}},
+			},
+			want: []string{"the openai mock does not report its capabilities (its image predates capability discovery); scenarios requiring newer features may fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newChecker(map[string]config.MockConfig{"openai": serveMock(t, 0, reportCapabilities(tt.reported))})
+			if got := checker.MissingCapabilities(context.Background(), tt.required); !slices.Equal(got, tt.want) {
+				t.Errorf("MissingCapabilities() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		reported *Capabilities
+		required map[string]map[string][]string
+		wantErr  string
+	}{
+		{
+			name:     "expected mock",
+			reported: &Capabilities{Mock: "openai", APIs: []string{"chat"}},
+			required: map[string]map[string][]string{"scenarios/a.yaml": {"openai": {"chat"}}},
+		},
+		{
+			name:     "not reported is a warning",
+			required: map[string]map[string][]string{"scenarios/a.yaml": {"openai": {"chat"}}},
+		},
+		{
+			name:     "another mock",
+			reported: &Capabilities{Mock: "stripe"},
+			wantErr:  "reports itself as the stripe mock",
+		},
+		{
+			name:     "missing capability",
+			reported: &Capabilities{Mock: "openai", Version: "1.0.0"},
+			required: map[string]map[string][]string{"scenarios/a.yaml": {"openai": {"realtime"}}},
+			wantErr:  "scenarios/a.yaml requires realtime from the openai mock",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newChecker(map[string]config.MockConfig{"openai": serveMock(t, 0, reportCapabilities(tt.reported))})
+
+			err := checker.CheckTargets(context.Background(), tt.required)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckTargets() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckTargets() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package preflight smoke-checks the mocks before a test run: one canary
+// request per enabled mock, sent with the credentials injected into agents,
+// plus a check that latency is simulated as lab.yaml expects. A broken
+// environment then fails in seconds with a clear message instead of
+// failing every scenario of a full suite.
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/labenv"
+)
+
+// settingsPath is the mock admin endpoint reporting its effective settings
+const settingsPath = "/_sentra/preflight"
+
+// requestTimeout bounds a single canary request
+const requestTimeout = 10 * time.Second
+
+// attempts is how often a canary is sent before a rate limit or server
+// error counts as a failure, since mocks inject both on purpose
+const attempts = 3
+
+// canary is the request sent to one mock.
+type canary struct {
+	path   string
+	apiKey string
+}
+
+// canaryFor returns the request that exercises a mock's API the way an
+// agent would. Mocks without a known API get a health check.
+func canaryFor(name string) canary {
+	switch name {
+	case "openai":
+		return canary{path: "/v1/models", apiKey: labenv.MockAPIKey}
+	case "stripe":
+		return canary{path: "/v1/balance", apiKey: labenv.StripeAPIKey}
+	}
+	return canary{path: "/health"}
+}
+
+// Result is the outcome of checking one mock.
+type Result struct {
+	Mock string
+
+	// Duration is how long the canary took to answer
+	Duration time.Duration
+
+	// Problems are the misconfigurations found (none if the mock is usable)
+	Problems []string
+}
+
+// OK reports whether the mock passed every check.
+func (r Result) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// settings is the part of a mock's /_sentra/preflight response checked
+// against lab.yaml.
+type settings struct {
+	Latency struct {
+		Enabled bool `json:"enabled"`
+	} `json:"latency"`
+}
+
+// Checker smoke-checks the enabled mocks.
+type Checker struct {
+	mocks  map[string]config.MockConfig
+	client *http.Client
+}
+
+// NewChecker builds a checker for the enabled mocks. It returns nil when
+// simulation.skip_preflight is set or no mock is enabled.
+func NewChecker(cfg *config.Config) *Checker {
+	if cfg.Simulation.SkipPreflight {
+		return nil
+	}
+
+	mocks := make(map[string]config.MockConfig)
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			mocks[name] = mock
+		}
+	}
+	if len(mocks) == 0 {
+		return nil
+	}
+
+	return &Checker{
+		mocks:  mocks,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run checks every mock concurrently and returns the results by mock name.
+// The error lists every problem found, or is nil if all mocks are usable.
+func (c *Checker) Run(ctx context.Context) ([]Result, error) {
+	names := make([]string, 0, len(c.mocks))
+	for name := range c.mocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = c.check(ctx, name, c.mocks[name])
+		}(i, name)
+	}
+	wg.Wait()
+
+	var problems []string
+	for _, result := range results {
+		for _, problem := range result.Problems {
+			problems = append(problems, fmt.Sprintf("  • %s mock: %s", result.Mock, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return results, fmt.Errorf("preflight failed, no scenarios were run:\n%s\n(set simulation.skip_preflight in lab.yaml to run anyway)", strings.Join(problems, "\n"))
+	}
+	return results, nil
+}
+
+// check sends the canary to one mock and compares its settings with lab.yaml.
+func (c *Checker) check(ctx context.Context, name string, mock config.MockConfig) Result {
+	result := Result{Mock: name}
+	baseURL := mock.BaseURL()
+	request := canaryFor(name)
+
+	var (
+		status int
+		body   []byte
+		err    error
+	)
+	for attempt := 1; attempt <= attempts; attempt++ {
+		start := time.Now()
+		status, body, err = c.get(ctx, baseURL+request.path, request.apiKey)
+		result.Duration = time.Since(start)
+		if err != nil {
+			hint := "is 'sentra lab start' running?"
+			if mock.URL != "" {
+				hint = "is the --target environment up?"
+			}
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("not reachable at %s (%s): %v", baseURL, hint, err))
+			return result
+		}
+		if status != http.StatusTooManyRequests && status < 500 {
+			break
+		}
+	}
+
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("rejected the API key injected into agents (status %d: %s); check the mock's auth mode", status, errorMessage(body)))
+	case status == http.StatusNotFound:
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("does not serve GET %s; is another service listening at %s?", request.path, baseURL))
+	case status >= 400:
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("GET %s failed with status %d after %d attempts: %s", request.path, status, attempts, errorMessage(body)))
+	}
+
+	if mock.LatencyMS <= 0 {
+		return result
+	}
+
+	expected := time.Duration(mock.LatencyMS) * time.Millisecond
+	reported, err := c.settings(ctx, baseURL)
+	switch {
+	case err != nil:
+		result.Problems = append(result.Problems, err.Error())
+	case reported != nil:
+		if !reported.Latency.Enabled {
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("latency simulation is disabled, but mocks.%s.latency_ms is %d", name, mock.LatencyMS))
+		}
+	case result.Duration < expected/2:
+		// Without a settings endpoint, the canary itself should be delayed
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("answered in %s, but mocks.%s.latency_ms is %d; latency simulation looks inactive",
+				result.Duration.Round(time.Millisecond), name, mock.LatencyMS))
+	}
+
+	return result
+}
+
+// settings fetches a mock's effective settings, or nil if the mock does not
+// report them.
+func (c *Checker) settings(ctx context.Context, baseURL string) (*settings, error) {
+	status, body, err := c.get(ctx, baseURL+settingsPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch settings: %w", err)
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch settings: status %d", status)
+	}
+
+	var reported settings
+	if err := json.Unmarshal(body, &reported); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	return &reported, nil
+}
+
+func (c *Checker) get(ctx context.Context, url, apiKey string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	req.Header.Set("User-Agent", "sentra-lab-preflight")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// errorMessage extracts the message of an OpenAI/Stripe-style error body.
+func errorMessage(body []byte) string {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error.Message != "" {
+		return payload.Error.Message
+	}
+
+	message := strings.TrimSpace(string(body))
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+	if message == "" {
+		return "empty response"
+	}
+	return message
+}
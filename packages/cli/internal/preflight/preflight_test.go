@@ -0,0 +1,278 @@
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"github.com/sentra-lab/cli/internal/labenv"
+)
+
+// newChecker returns a checker for mocks, bypassing NewChecker's filtering.
+func newChecker(mocks map[string]config.MockConfig) *Checker {
+	return &Checker{mocks: mocks, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// serveMock runs handler as a mock and returns its config.
+func serveMock(t *testing.T, latencyMS int, handler http.HandlerFunc) config.MockConfig {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return config.MockConfig{Enabled: true, URL: server.URL, LatencyMS: latencyMS}
+}
+
+func TestNewChecker(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   *config.Config
+		want  []string
+		isNil bool
+	}{
+		{
+			name: "enabled mocks",
+			cfg: &config.Config{Mocks: map[string]config.MockConfig{
+				"openai": {Enabled: true, Port: 8080},
+				"stripe": {Port: 8081},
+			}},
+			want: []string{"openai"},
+		},
+		{
+			name:  "skip_preflight",
+			cfg:   &config.Config{Simulation: config.SimulationConfig{SkipPreflight: true}, Mocks: map[string]config.MockConfig{"openai": {Enabled: true}}},
+			isNil: true,
+		},
+		{
+			name:  "no mocks enabled",
+			cfg:   &config.Config{Mocks: map[string]config.MockConfig{"openai": {}}},
+			isNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(tt.cfg)
+			if tt.isNil {
+				if checker != nil {
+					t.Errorf("NewChecker() = %+v, want nil", checker)
+				}
+				return
+			}
+			if checker == nil || len(checker.mocks) != len(tt.want) {
+				t.Fatalf("NewChecker() = %+v, want mocks %v", checker, tt.want)
+			}
+			for _, name := range tt.want {
+				if _, ok := checker.mocks[name]; !ok {
+					t.Errorf("NewChecker() mocks = %v, want %s", checker.mocks, name)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckerRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		mock        string
+		latencyMS   int
+		handler     http.HandlerFunc
+		wantProblem string
+	}{
+		{
+			name: "usable",
+			mock: "openai",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/models" || r.Header.Get("Authorization") != "Bearer "+labenv.MockAPIKey {
+					http.Error(w, `{"error": {"message": "unexpected request"}}`, http.StatusBadRequest)
+				}
+			},
+		},
+		{
+			name: "stripe canary",
+			mock: "stripe",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/balance" || r.Header.Get("Authorization") != "Bearer "+labenv.StripeAPIKey {
+					http.Error(w, "unexpected request", http.StatusBadRequest)
+				}
+			},
+		},
+		{
+			name: "other mocks get a health check",
+			mock: "coreledger",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/health" {
+					http.NotFound(w, r)
+				}
+			},
+		},
+		{
+			name: "rejected API key",
+			mock: "openai",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"error": {"message": "Incorrect API key provided"}}`, http.StatusUnauthorized)
+			},
+			wantProblem: "rejected the API key injected into agents (status 401: Incorrect API key provided)",
+		},
+		{
+			name:        "wrong service",
+			mock:        "openai",
+			handler:     http.NotFound,
+			wantProblem: "does not serve GET /v1/models",
+		},
+		{
+			name: "persistent server errors",
+			mock: "openai",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			wantProblem: "failed with status 503 after 3 attempts: empty response",
+		},
+		{
+			name:      "latency disabled",
+			mock:      "openai",
+			latencyMS: 200,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == settingsPath {
+					w.Write([]byte(`{"latency": {"enabled": false}}`))
+				}
+			},
+			wantProblem: "latency simulation is disabled, but mocks.openai.latency_ms is 200",
+		},
+		{
+			name:      "latency enabled",
+			mock:      "openai",
+			latencyMS: 200,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == settingsPath {
+					w.Write([]byte(`{"latency": {"enabled": true}}`))
+				}
+			},
+		},
+		{
+			name:      "latency inactive without settings",
+			mock:      "openai",
+			latencyMS: 10000,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == settingsPath {
+					http.NotFound(w, r)
+				}
+			},
+			wantProblem: "latency simulation looks inactive",
+		},
+		{
+			name:      "settings error",
+			mock:      "openai",
+			latencyMS: 200,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == settingsPath {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			},
+			wantProblem: "failed to fetch settings: status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newChecker(map[string]config.MockConfig{tt.mock: serveMock(t, tt.latencyMS, tt.handler)})
+
+			results, err := checker.Run(context.Background())
+			if len(results) != 1 || results[0].Mock != tt.mock {
+				t.Fatalf("Run() results = %+v, want one for %s", results, tt.mock)
+			}
+
+			if tt.wantProblem == "" {
+				if err != nil || !results[0].OK() {
+					t.Errorf("Run() = %+v, %v, want no problems", results[0], err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.mock+" mock: ") || !strings.Contains(err.Error(), tt.wantProblem) {
+				t.Errorf("Run() error = %v, want %q", err, tt.wantProblem)
+			}
+			if results[0].OK() {
+				t.Errorf("OK() = true, want false")
+			}
+		})
+	}
+}
+
+func TestCheckerRunRetries(t *testing.T) {
+	var requests atomic.Int32
+	mock := serveMock(t, 0, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < attempts {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	})
+
+	results, err := newChecker(map[string]config.MockConfig{"openai": mock}).Run(context.Background())
+	if err != nil || !results[0].OK() {
+		t.Errorf("Run() = %+v, %v, want a pass after retries", results, err)
+	}
+	if got := requests.Load(); got != attempts {
+		t.Errorf("requests = %d, want %d", got, attempts)
+	}
+}
+
+func TestCheckerRunUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	tests := []struct {
+		name     string
+		mock     config.MockConfig
+		wantHint string
+	}{
+		{name: "local", mock: config.MockConfig{Enabled: true, Port: portOf(t, server.URL)}, wantHint: "is 'sentra lab start' running?"},
+		{name: "target", mock: config.MockConfig{Enabled: true, URL: server.URL}, wantHint: "is the --target environment up?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newChecker(map[string]config.MockConfig{"openai": tt.mock}).Run(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "not reachable") || !strings.Contains(err.Error(), tt.wantHint) {
+				t.Errorf("Run() error = %v, want not reachable with %q", err, tt.wantHint)
+			}
+		})
+	}
+}
+
+// portOf returns the port of a server URL.
+func portOf(t *testing.T, rawURL string) int {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(parsed.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return port
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "error object", body: `{"error": {"message": "No such model"}}`, want: "No such model"},
+		{name: "text", body: " upstream down \n", want: "upstream down"},
+		{name: "empty", body: "", want: "empty response"},
+		{name: "long", body: strings.Repeat("x", 250), want: strings.Repeat("x", 200) + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorMessage([]byte(tt.body)); got != tt.want {
+				t.Errorf("errorMessage(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package promptdiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change statuses
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// minSimilarity is the share of lines two prompts must have in common to be
+// reported as one changed prompt rather than a removal and an addition
+const minSimilarity = 0.3
+
+// PromptChange is a system prompt that differs between the runs.
+type PromptChange struct {
+	Status string `json:"status"`
+	Base   string `json:"base,omitempty"`
+	Head   string `json:"head,omitempty"`
+
+	// Lines is a line diff of a changed prompt, each line prefixed with
+	// "+ ", "- " or "  "
+	Lines []string `json:"lines,omitempty"`
+}
+
+// ToolChange is a tool definition that differs between the runs.
+type ToolChange struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Base   string `json:"base,omitempty"`
+	Head   string `json:"head,omitempty"`
+}
+
+// ParamChange is a model parameter whose values differ between the runs.
+type ParamChange struct {
+	Endpoint string   `json:"endpoint"`
+	Param    string   `json:"param"`
+	Base     []string `json:"base"`
+	Head     []string `json:"head"`
+}
+
+// Report is how the prompts changed from a base run to a head run.
+type Report struct {
+	Base         string         `json:"base"`
+	Head         string         `json:"head"`
+	BaseRequests int            `json:"base_requests"`
+	HeadRequests int            `json:"head_requests"`
+	Prompts      []PromptChange `json:"prompts"`
+	Tools        []ToolChange   `json:"tools"`
+	Params       []ParamChange  `json:"params"`
+}
+
+// Changed reports whether anything differs.
+func (r *Report) Changed() bool {
+	return len(r.Prompts) > 0 || len(r.Tools) > 0 || len(r.Params) > 0
+}
+
+// Diff compares two snapshots. base and head label them in the report
+// (e.g. revisions or recording files).
+func Diff(base, head string, baseSnapshot, headSnapshot *Snapshot) *Report {
+	return &Report{
+		Base:         base,
+		Head:         head,
+		BaseRequests: baseSnapshot.Requests,
+		HeadRequests: headSnapshot.Requests,
+		Prompts:      diffPrompts(baseSnapshot.SystemPrompts, headSnapshot.SystemPrompts),
+		Tools:        diffTools(baseSnapshot.Tools, headSnapshot.Tools),
+		Params:       diffParams(baseSnapshot.Params, headSnapshot.Params),
+	}
+}
+
+// diffPrompts pairs each prompt only in base with the most similar prompt
+// only in head; unpaired prompts are removals or additions.
+func diffPrompts(base, head []string) []PromptChange {
+	removed := subtract(base, head)
+	added := subtract(head, base)
+
+	var changes []PromptChange
+	paired := make(map[int]bool)
+	for _, old := range removed {
+		best, bestScore := -1, minSimilarity
+		for i, candidate := range added {
+			if paired[i] {
+				continue
+			}
+			if score := similarity(old, candidate); score >= bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		if best < 0 {
+			changes = append(changes, PromptChange{Status: Removed, Base: old})
+			continue
+		}
+		paired[best] = true
+		changes = append(changes, PromptChange{
+			Status: Changed,
+			Base:   old,
+			Head:   added[best],
+			Lines:  diffLines(strings.Split(old, "\n"), strings.Split(added[best], "\n")),
+		})
+	}
+
+	for i, prompt := range added {
+		if !paired[i] {
+			changes = append(changes, PromptChange{Status: Added, Head: prompt})
+		}
+	}
+
+	return changes
+}
+
+func diffTools(base, head map[string]string) []ToolChange {
+	var changes []ToolChange
+	for _, name := range unionKeys(base, head) {
+		old, inBase := base[name]
+		current, inHead := head[name]
+
+		switch {
+		case !inBase:
+			changes = append(changes, ToolChange{Name: name, Status: Added, Head: current})
+		case !inHead:
+			changes = append(changes, ToolChange{Name: name, Status: Removed, Base: old})
+		case old != current:
+			changes = append(changes, ToolChange{Name: name, Status: Changed, Base: old, Head: current})
+		}
+	}
+	return changes
+}
+
+func diffParams(base, head map[string]map[string][]string) []ParamChange {
+	endpoints := make(map[string]bool)
+	for endpoint := range base {
+		endpoints[endpoint] = true
+	}
+	for endpoint := range head {
+		endpoints[endpoint] = true
+	}
+	sorted := make([]string, 0, len(endpoints))
+	for endpoint := range endpoints {
+		sorted = append(sorted, endpoint)
+	}
+	sort.Strings(sorted)
+
+	var changes []ParamChange
+	for _, endpoint := range sorted {
+		baseParams, headParams := base[endpoint], head[endpoint]
+
+		names := make(map[string]bool)
+		for name := range baseParams {
+			names[name] = true
+		}
+		for name := range headParams {
+			names[name] = true
+		}
+		sortedNames := make([]string, 0, len(names))
+		for name := range names {
+			sortedNames = append(sortedNames, name)
+		}
+		sort.Strings(sortedNames)
+
+		for _, name := range sortedNames {
+			if !equal(baseParams[name], headParams[name]) {
+				changes = append(changes, ParamChange{
+					Endpoint: endpoint,
+					Param:    name,
+					Base:     baseParams[name],
+					Head:     headParams[name],
+				})
+			}
+		}
+	}
+	return changes
+}
+
+// diffLines is a longest-common-subsequence line diff.
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+	return lines
+}
+
+// similarity is the share of lines two texts have in common.
+func similarity(a, b string) float64 {
+	linesA, linesB := strings.Split(a, "\n"), strings.Split(b, "\n")
+	common := 0
+	for _, line := range diffLines(linesA, linesB) {
+		if strings.HasPrefix(line, "  ") {
+			common++
+		}
+	}
+	longest := len(linesA)
+	if len(linesB) > longest {
+		longest = len(linesB)
+	}
+	return float64(common) / float64(longest)
+}
+
+// subtract returns the items of a that are not in b, in order.
+func subtract(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[item] = true
+	}
+	var result []string
+	for _, item := range a {
+		if !inB[item] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatText renders the report for the terminal or a review comment
+// (markdown).
+func FormatText(r *Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Prompt changes: %s → %s\n\n", r.Base, r.Head)
+	fmt.Fprintf(&b, "Requests compared: %d (base), %d (head)\n\n", r.BaseRequests, r.HeadRequests)
+
+	if !r.Changed() {
+		b.WriteString("No changes to system prompts, tools or model parameters.\n")
+		return b.String()
+	}
+
+	if len(r.Prompts) > 0 {
+		b.WriteString("### System prompts\n\n")
+		for _, change := range r.Prompts {
+			b.WriteString("```diff\n")
+			switch change.Status {
+			case Added:
+				writePrefixed(&b, "+ ", change.Head)
+			case Removed:
+				writePrefixed(&b, "- ", change.Base)
+			default:
+				for _, line := range change.Lines {
+					b.WriteString(line + "\n")
+				}
+			}
+			b.WriteString("```\n\n")
+		}
+	}
+
+	if len(r.Tools) > 0 {
+		b.WriteString("### Tools\n\n")
+		for _, change := range r.Tools {
+			fmt.Fprintf(&b, "- `%s` %s\n", change.Name, change.Status)
+			if change.Status == Changed {
+				fmt.Fprintf(&b, "  - base: `%s`\n  - head: `%s`\n", change.Base, change.Head)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Params) > 0 {
+		b.WriteString("### Model parameters\n\n")
+		b.WriteString("| Endpoint | Parameter | Base | Head |\n|---|---|---|---|\n")
+		for _, change := range r.Params {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", change.Endpoint, change.Param, values(change.Base), values(change.Head))
+		}
+	}
+
+	return b.String()
+}
+
+func writePrefixed(b *strings.Builder, prefix, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(prefix + line + "\n")
+	}
+}
+
+func values(v []string) string {
+	if len(v) == 0 {
+		return "(unset)"
+	}
+	return "`" + strings.Join(v, "`, `") + "`"
+}
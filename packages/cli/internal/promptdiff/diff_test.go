@@ -0,0 +1,137 @@
+package promptdiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffPrompts(t *testing.T) {
+	tests := []struct {
+		name string
+		base []string
+		head []string
+		want []PromptChange
+	}{
+		{
+			name: "unchanged",
+			base: []string{"Be brief."},
+			head: []string{"Be brief."},
+		},
+		{
+			name: "changed line",
+			base: []string{"You are a support agent.\nBe brief.\nNever refund."},
+			head: []string{"You are a support agent.\nBe polite.\nNever refund."},
+			want: []PromptChange{{
+				Status: Changed,
+				Base:   "You are a support agent.\nBe brief.\nNever refund.",
+				Head:   "You are a support agent.\nBe polite.\nNever refund.",
+				Lines:  []string{"  You are a support agent.", "- Be brief.", "+ Be polite.", "  Never refund."},
+			}},
+		},
+		{
+			name: "unrelated prompts",
+			base: []string{"Old prompt."},
+			head: []string{"New prompt."},
+			want: []PromptChange{
+				{Status: Removed, Base: "Old prompt."},
+				{Status: Added, Head: "New prompt."},
+			},
+		},
+		{
+			name: "added prompt",
+			base: []string{"Be brief."},
+			head: []string{"Be brief.", "Use tools."},
+			want: []PromptChange{{Status: Added, Head: "Use tools."}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffPrompts(tt.base, tt.head); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffPrompts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	base := &Snapshot{
+		Requests:      2,
+		SystemPrompts: []string{"Be brief."},
+		Tools:         map[string]string{"refund": `{"name":"refund"}`, "lookup": `{"name":"lookup"}`},
+		Params:        map[string]map[string][]string{"/v1/chat/completions": {"model": {`"gpt-4o"`}, "temperature": {"0.2"}}},
+	}
+	head := &Snapshot{
+		Requests:      3,
+		SystemPrompts: []string{"Be brief."},
+		Tools:         map[string]string{"refund": `{"name":"refund","strict":true}`, "escalate": `{"name":"escalate"}`},
+		Params:        map[string]map[string][]string{"/v1/chat/completions": {"model": {`"gpt-4o"`}}, "/v1/embeddings": {"model": {`"text-embedding-3-small"`}}},
+	}
+
+	report := Diff("main", "HEAD", base, head)
+
+	if !report.Changed() {
+		t.Error("Changed() = false, want true")
+	}
+	wantTools := []ToolChange{
+		{Name: "escalate", Status: Added, Head: `{"name":"escalate"}`},
+		{Name: "lookup", Status: Removed, Base: `{"name":"lookup"}`},
+		{Name: "refund", Status: Changed, Base: `{"name":"refund"}`, Head: `{"name":"refund","strict":true}`},
+	}
+	if !reflect.DeepEqual(report.Tools, wantTools) {
+		t.Errorf("Tools = %+v, want %+v", report.Tools, wantTools)
+	}
+	wantParams := []ParamChange{
+		{Endpoint: "/v1/chat/completions", Param: "temperature", Base: []string{"0.2"}},
+		{Endpoint: "/v1/embeddings", Param: "model", Head: []string{`"text-embedding-3-small"`}},
+	}
+	if !reflect.DeepEqual(report.Params, wantParams) {
+		t.Errorf("Params = %+v, want %+v", report.Params, wantParams)
+	}
+
+	if same := Diff("a", "b", base, base); same.Changed() {
+		t.Errorf("Diff() of a snapshot with itself = %+v, want no changes", same)
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *Report
+		want   []string
+	}{
+		{
+			name:   "no changes",
+			report: &Report{Base: "main", Head: "HEAD", BaseRequests: 2, HeadRequests: 2},
+			want:   []string{"## Prompt changes: main → HEAD", "Requests compared: 2 (base), 2 (head)", "No changes"},
+		},
+		{
+			name: "changes",
+			report: &Report{
+				Base:    "main",
+				Head:    "HEAD",
+				Prompts: []PromptChange{{Status: Added, Head: "Use tools.\nBe brief."}, {Status: Changed, Lines: []string{"- Old", "+ New"}}},
+				Tools:   []ToolChange{{Name: "refund", Status: Changed, Base: "{}", Head: `{"strict":true}`}},
+				Params:  []ParamChange{{Endpoint: "/v1/chat/completions", Param: "temperature", Base: []string{"0.2"}}},
+			},
+			want: []string{
+				"```diff\n+ Use tools.\n+ Be brief.\n```",
+				"```diff\n- Old\n+ New\n```",
+				"- `refund` changed\n  - base: `{}`\n  - head: `{\"strict\":true}`",
+				"| /v1/chat/completions | temperature | `0.2` | (unset) |",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := FormatText(tt.report)
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("FormatText() = %q, want it to contain %q", text, want)
+				}
+			}
+		})
+	}
+}
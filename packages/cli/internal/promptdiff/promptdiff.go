@@ -0,0 +1,313 @@
+// Package promptdiff reports how the prompts an agent sends changed between
+// two runs: system prompts, tool definitions and model parameters, taken
+// from the OpenAI requests in full-trace recordings.
+package promptdiff
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// capturePath is the mock admin endpoint listing captured exchanges
+const capturePath = "/_sentra/capture/exchanges"
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 10 * time.Second
+
+// Exchange is a recorded request to the OpenAI mock (see the mock's
+// full-trace capture format). Prompt diffs only use the request; the
+// response status, headers and timing are kept for retry analysis.
+type Exchange struct {
+	Seq             int64             `json:"seq"`
+	RunID           string            `json:"run_id"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query,omitempty"`
+	RequestBody     string            `json:"request_body"`
+	BodyEncoding    string            `json:"body_encoding"`
+	Status          int               `json:"status"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	StartedAt       int64             `json:"started_at"`
+	DurationMs      int64             `json:"duration_ms"`
+}
+
+// LoadExchanges reads a recording: a JSON array of exchanges, or a capture
+// API response ({"data": [...]}).
+func LoadExchanges(path string) ([]Exchange, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var exchanges []Exchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		var list struct {
+			Data []Exchange `json:"data"`
+		}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse recording %s: %w", path, err)
+		}
+		exchanges = list.Data
+	}
+
+	return exchanges, nil
+}
+
+// FetchExchanges returns a run's captured exchanges from the mock at baseURL
+// (e.g. http://localhost:8080). The mock only captures requests when
+// simulation.record_full_trace is enabled.
+func FetchExchanges(ctx context.Context, baseURL, runID string) ([]Exchange, error) {
+	query := url.Values{}
+	query.Set("run_id", runID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+capturePath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchanges for %s: %w", runID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch exchanges for %s: status %d", runID, resp.StatusCode)
+	}
+
+	var list struct {
+		Data []Exchange `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid exchanges for %s: %w", runID, err)
+	}
+	return list.Data, nil
+}
+
+// promptFields are the request fields that carry the conversation or the
+// prompt itself; they are not model parameters.
+var promptFields = map[string]bool{
+	"messages":     true,
+	"input":        true,
+	"prompt":       true,
+	"instructions": true,
+	"tools":        true,
+	"functions":    true,
+	"user":         true,
+	"metadata":     true,
+}
+
+// systemRoles are the message roles that carry system prompts.
+var systemRoles = map[string]bool{
+	"system":    true,
+	"developer": true,
+}
+
+// Snapshot is what an agent sent during a run.
+type Snapshot struct {
+	// Requests is the number of requests seen
+	Requests int `json:"requests"`
+
+	// SystemPrompts are the distinct system and developer prompts, in the
+	// order first sent
+	SystemPrompts []string `json:"system_prompts"`
+
+	// Tools map tool names to their definitions (canonical JSON)
+	Tools map[string]string `json:"tools"`
+
+	// Params map endpoint paths to model parameters and the distinct values
+	// sent (canonical JSON, sorted)
+	Params map[string]map[string][]string `json:"params"`
+}
+
+// NewSnapshot extracts the prompts from the requests of a run. Requests
+// without a JSON body are skipped.
+func NewSnapshot(exchanges []Exchange) *Snapshot {
+	snapshot := &Snapshot{
+		Tools:  make(map[string]string),
+		Params: make(map[string]map[string][]string),
+	}
+
+	sort.SliceStable(exchanges, func(i, j int) bool {
+		return exchanges[i].Seq < exchanges[j].Seq
+	})
+
+	seenPrompts := make(map[string]bool)
+	for _, exchange := range exchanges {
+		var body map[string]json.RawMessage
+		if json.Unmarshal(requestBody(exchange), &body) != nil {
+			continue
+		}
+		snapshot.Requests++
+
+		for _, prompt := range systemPrompts(body) {
+			if !seenPrompts[prompt] {
+				seenPrompts[prompt] = true
+				snapshot.SystemPrompts = append(snapshot.SystemPrompts, prompt)
+			}
+		}
+
+		for name, definition := range tools(body) {
+			snapshot.Tools[name] = definition
+		}
+
+		for field, value := range body {
+			if promptFields[field] {
+				continue
+			}
+			params := snapshot.Params[exchange.Path]
+			if params == nil {
+				params = make(map[string][]string)
+				snapshot.Params[exchange.Path] = params
+			}
+			params[field] = addValue(params[field], canonical(value))
+		}
+	}
+
+	return snapshot
+}
+
+// Payload returns the exchange's decoded request body.
+func (e Exchange) Payload() []byte {
+	return requestBody(e)
+}
+
+// requestBody decodes an exchange's request body.
+func requestBody(exchange Exchange) []byte {
+	if exchange.BodyEncoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(exchange.RequestBody)
+		if err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return []byte(exchange.RequestBody)
+}
+
+// systemPrompts returns the system prompts of a Chat Completions or
+// Responses request.
+func systemPrompts(body map[string]json.RawMessage) []string {
+	var prompts []string
+
+	var instructions string
+	if json.Unmarshal(body["instructions"], &instructions) == nil && instructions != "" {
+		prompts = append(prompts, instructions)
+	}
+
+	for _, field := range []string{"messages", "input"} {
+		var messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		}
+		if json.Unmarshal(body[field], &messages) != nil {
+			continue
+		}
+		for _, message := range messages {
+			if systemRoles[message.Role] {
+				if text := contentText(message.Content); text != "" {
+					prompts = append(prompts, text)
+				}
+			}
+		}
+	}
+
+	return prompts
+}
+
+// contentText returns message content as text: a string, or the text parts
+// of a content array.
+func contentText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text
+	}
+
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &parts) != nil {
+		return ""
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// tools returns a request's tool definitions by name: Chat Completions
+// tools ({"type": "function", "function": {"name": ...}}), Responses tools
+// ({"type": "function", "name": ...}), legacy functions, and built-in
+// tools by type.
+func tools(body map[string]json.RawMessage) map[string]string {
+	definitions := make(map[string]string)
+
+	for _, field := range []string{"tools", "functions"} {
+		var list []json.RawMessage
+		if json.Unmarshal(body[field], &list) != nil {
+			continue
+		}
+		for _, raw := range list {
+			var tool struct {
+				Type     string `json:"type"`
+				Name     string `json:"name"`
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			}
+			if json.Unmarshal(raw, &tool) != nil {
+				continue
+			}
+
+			name := tool.Function.Name
+			if name == "" {
+				name = tool.Name
+			}
+			if name == "" {
+				name = tool.Type
+			}
+			if name != "" {
+				definitions[name] = canonical(raw)
+			}
+		}
+	}
+
+	return definitions
+}
+
+// canonical re-encodes JSON with sorted keys, so formatting and key order
+// do not show up as changes.
+func canonical(raw json.RawMessage) string {
+	var value interface{}
+	if json.Unmarshal(raw, &value) != nil {
+		return string(raw)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return string(raw)
+	}
+	return string(encoded)
+}
+
+// addValue adds value to a sorted set.
+func addValue(values []string, value string) []string {
+	i := sort.SearchStrings(values, value)
+	if i < len(values) && values[i] == value {
+		return values
+	}
+	values = append(values, "")
+	copy(values[i+1:], values[i:])
+	values[i] = value
+	return values
+}
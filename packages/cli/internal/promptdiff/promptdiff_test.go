@@ -0,0 +1,164 @@
+package promptdiff
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	exchanges := []Exchange{
+		{
+			Seq:  2,
+			Path: "/v1/responses",
+			RequestBody: `{"model": "gpt-4o", "instructions": "Be brief.",
+				"input": [{"role": "developer", "content": [{"type": "input_text", "text": "Use tools."}]}],
+				"tools": [{"type": "function", "name": "refund"}, {"type": "web_search"}]}`,
+		},
+		{
+			Seq:  1,
+			Path: "/v1/chat/completions",
+			RequestBody: `{"model": "gpt-4o-mini", "temperature": 0.2,
+				"messages": [{"role": "system", "content": "You are a support agent."}, {"role": "user", "content": "Hi"}],
+				"tools": [{"type": "function", "function": {"name": "lookup_order", "parameters": {"type": "object"}}}]}`,
+		},
+		{
+			Seq:          3,
+			Path:         "/v1/chat/completions",
+			RequestBody:  base64.StdEncoding.EncodeToString([]byte(`{"model": "gpt-4o", "messages": [{"role": "system", "content": "You are a support agent."}]}`)),
+			BodyEncoding: "base64",
+		},
+		{Seq: 4, Path: "/v1/audio/transcriptions", RequestBody: "--multipart--"},
+	}
+
+	snapshot := NewSnapshot(exchanges)
+
+	if snapshot.Requests != 3 {
+		t.Errorf("Requests = %d, want 3", snapshot.Requests)
+	}
+	wantPrompts := []string{"You are a support agent.", "Be brief.", "Use tools."}
+	if !reflect.DeepEqual(snapshot.SystemPrompts, wantPrompts) {
+		t.Errorf("SystemPrompts = %q, want %q", snapshot.SystemPrompts, wantPrompts)
+	}
+	wantTools := map[string]string{
+		"lookup_order": `{"function":{"name":"lookup_order","parameters":{"type":"object"}},"type":"function"}`,
+		"refund":       `{"name":"refund","type":"function"}`,
+		"web_search":   `{"type":"web_search"}`,
+	}
+	if !reflect.DeepEqual(snapshot.Tools, wantTools) {
+		t.Errorf("Tools = %v, want %v", snapshot.Tools, wantTools)
+	}
+	wantParams := map[string]map[string][]string{
+		"/v1/chat/completions": {"model": {`"gpt-4o"`, `"gpt-4o-mini"`}, "temperature": {"0.2"}},
+		"/v1/responses":        {"model": {`"gpt-4o"`}},
+	}
+	if !reflect.DeepEqual(snapshot.Params, wantParams) {
+		t.Errorf("Params = %v, want %v", snapshot.Params, wantParams)
+	}
+}
+
+func TestLoadExchanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{name: "array", content: `[{"seq": 1, "path": "/v1/chat/completions"}, {"seq": 2}]`, want: 2},
+		{name: "capture response", content: `{"data": [{"seq": 1}]}`, want: 1},
+		{name: "invalid", content: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "recording.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			exchanges, err := LoadExchanges(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadExchanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(exchanges) != tt.want {
+				t.Errorf("LoadExchanges() returned %d exchanges, want %d", len(exchanges), tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchExchanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    int
+		wantErr bool
+	}{
+		{name: "exchanges", status: http.StatusOK, body: `{"data": [{"seq": 1, "run_id": "run_1"}]}`, want: 1},
+		{name: "error status", status: http.StatusNotFound, body: `{}`, wantErr: true},
+		{name: "invalid body", status: http.StatusOK, body: `[`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRunID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != capturePath {
+					t.Errorf("path = %s, want %s", r.URL.Path, capturePath)
+				}
+				gotRunID = r.URL.Query().Get("run_id")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			exchanges, err := FetchExchanges(context.Background(), server.URL, "run 1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchExchanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(exchanges) != tt.want {
+				t.Errorf("FetchExchanges() returned %d exchanges, want %d", len(exchanges), tt.want)
+			}
+			if gotRunID != "run 1" {
+				t.Errorf("run_id = %q, want %q", gotRunID, "run 1")
+			}
+		})
+	}
+}
+
+func TestContentText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "string", content: `"Be brief."`, want: "Be brief."},
+		{name: "parts", content: `[{"type": "text", "text": "One"}, {"type": "image_url"}, {"type": "text", "text": "Two"}]`, want: "One\nTwo"},
+		{name: "other", content: `42`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentText(json.RawMessage(tt.content)); got != tt.want {
+				t.Errorf("contentText(%s) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddValue(t *testing.T) {
+	var values []string
+	for _, value := range []string{"b", "a", "c", "b"} {
+		values = addValue(values, value)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("addValue() = %v, want %v", values, want)
+	}
+}
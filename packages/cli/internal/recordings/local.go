@@ -0,0 +1,172 @@
+// Package recordings reads and writes run recordings kept on disk under
+// storage.recordings_dir, one directory per run, so runs can be listed and
+// replayed without the simulation engine.
+package recordings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+)
+
+const (
+	// MetadataFile describes a run
+	MetadataFile = "metadata.json"
+
+	// EventsFile holds a run's event timeline
+	EventsFile = "events.json"
+)
+
+// Metadata describes a recorded run.
+type Metadata struct {
+	RunID       string    `json:"run_id"`
+	Scenario    string    `json:"scenario"`
+	Status      string    `json:"status"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	DurationMs  int64     `json:"duration_ms"`
+	CostUSD     float64   `json:"cost_usd"`
+	Failures    []string  `json:"failures,omitempty"`
+}
+
+// event is the on-disk form of grpc.Event.
+type event struct {
+	ID        string                 `json:"id"`
+	Timestamp time.Time              `json:"timestamp"`
+	Type      string                 `json:"type"`
+	Service   string                 `json:"service"`
+	Summary   string                 `json:"summary"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Save writes a run's metadata and events to dir/<run-id>/.
+func Save(dir string, metadata Metadata, events []*grpc.Event) error {
+	runDir, err := runPath(dir, metadata.RunID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	stored := make([]event, len(events))
+	for i, e := range events {
+		stored[i] = event{ID: e.ID, Timestamp: e.Timestamp, Type: e.Type, Service: e.Service, Summary: e.Summary, Data: e.Data}
+	}
+
+	if err := writeJSON(filepath.Join(runDir, MetadataFile), metadata); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(runDir, EventsFile), stored)
+}
+
+// Load reads a run saved with Save.
+func Load(dir, runID string) (*grpc.Recording, *Metadata, error) {
+	runDir, err := runPath(dir, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var metadata Metadata
+	if err := readJSON(filepath.Join(runDir, MetadataFile), &metadata); err != nil {
+		return nil, nil, err
+	}
+
+	var stored []event
+	if err := readJSON(filepath.Join(runDir, EventsFile), &stored); err != nil {
+		return nil, nil, err
+	}
+
+	recording := &grpc.Recording{
+		ID:        metadata.RunID,
+		Scenario:  metadata.Scenario,
+		StartedAt: metadata.StartedAt,
+		Duration:  time.Duration(metadata.DurationMs) * time.Millisecond,
+		Events:    make([]*grpc.Event, len(stored)),
+	}
+	for i, e := range stored {
+		recording.Events[i] = &grpc.Event{ID: e.ID, Timestamp: e.Timestamp, Type: e.Type, Service: e.Service, Summary: e.Summary, Data: e.Data}
+	}
+
+	return recording, &metadata, nil
+}
+
+// List returns the runs recorded in dir, most recently completed first.
+// Directories without metadata are skipped.
+func List(dir string) ([]*grpc.RunSummary, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read recordings: %w", err)
+	}
+
+	var runs []*grpc.RunSummary
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		var metadata Metadata
+		if err := readJSON(filepath.Join(dir, entry.Name(), MetadataFile), &metadata); err != nil {
+			continue
+		}
+
+		runs = append(runs, &grpc.RunSummary{
+			ID:          metadata.RunID,
+			Scenario:    metadata.Scenario,
+			Status:      metadata.Status,
+			CompletedAt: metadata.CompletedAt,
+		})
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CompletedAt.After(runs[j].CompletedAt)
+	})
+
+	return runs, nil
+}
+
+// runPath returns a run's directory, rejecting IDs that would escape dir.
+func runPath(dir, runID string) (string, error) {
+	if runID == "" || runID == "." || runID == ".." || filepath.Base(runID) != runID {
+		return "", fmt.Errorf("invalid run ID: %q", runID)
+	}
+	return filepath.Join(dir, runID), nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("recording not found: %s", path)
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return nil
+}
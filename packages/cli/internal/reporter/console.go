@@ -3,8 +3,6 @@ package reporter
 import (
 	"fmt"
 	"io"
-	"strings"
-	"time"
 )
 
 type ConsoleReporter struct {
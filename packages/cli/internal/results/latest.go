@@ -0,0 +1,179 @@
+// Package results persists the outcome of the most recent `sentra lab test`
+// run so later commands (e.g. `sentra lab ci gate`) can evaluate it without
+// re-running scenarios.
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/egress"
+	"github.com/sentra-lab/cli/internal/sdkusage"
+	"github.com/sentra-lab/cli/internal/tokenusage"
+)
+
+// LatestPath is where the most recent run is recorded
+const LatestPath = ".sentra-lab/results/latest.json"
+
+// ScenarioResult is the outcome of one scenario.
+type ScenarioResult struct {
+	Scenario   string  `json:"scenario"`
+	RunID      string  `json:"run_id"`
+	Status     string  `json:"status"`
+	DurationMs int64   `json:"duration_ms"`
+	CostUSD    float64 `json:"cost_usd"`
+
+	// Steps break the scenario's token usage down by step
+	Steps []tokenusage.Step `json:"steps,omitempty"`
+
+	// Findings are the guardrail violations found in the scenario
+	Findings []Finding `json:"findings,omitempty"`
+
+	// TimedOut is the timeout that ended the scenario: "step", "scenario"
+	// or "run" (empty if none)
+	TimedOut string `json:"timed_out,omitempty"`
+}
+
+// Finding is a violation found by an assert_guardrails step.
+type Finding struct {
+	// Check is canary_leak, secret_leak, disallowed_tool or
+	// prompt_injection
+	Check string `json:"check"`
+
+	// Location is where the agent leaked it: "output" or "tool_call:<name>"
+	Location string `json:"location"`
+
+	// Detail is what was found, with secrets redacted
+	Detail string `json:"detail"`
+
+	// Step is the ID of the step that found it
+	Step string `json:"step,omitempty"`
+}
+
+// Run is a recorded `sentra lab test` invocation.
+type Run struct {
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt time.Time        `json:"finished_at"`
+	Scenarios  []ScenarioResult `json:"scenarios"`
+
+	// SDKs are the client SDKs agents used during the run
+	SDKs []sdkusage.Usage `json:"sdks,omitempty"`
+
+	// EgressLeaks are the external calls the egress proxy caught during
+	// the run
+	EgressLeaks []egress.Leak `json:"egress_leaks,omitempty"`
+
+	// Seed is the run seed fake test data was derived from; setting
+	// simulation.seed to it reproduces the data
+	Seed int64 `json:"seed,omitempty"`
+
+	// Runner profiles the test runner over the run
+	Runner *RunnerStats `json:"runner,omitempty"`
+}
+
+// Aggregates summarizes a run.
+type Aggregates struct {
+	Total        int     `json:"total"`
+	Passed       int     `json:"passed"`
+	Failed       int     `json:"failed"`
+	Skipped      int     `json:"skipped"`
+	PassRate     float64 `json:"pass_rate"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+
+	// Cached counts the passes served from the result cache (included in
+	// Passed)
+	Cached int `json:"cached,omitempty"`
+
+	// Latency percentiles are over scenario durations; skipped and cached
+	// scenarios are excluded
+	P50LatencyMs int64 `json:"p50_latency_ms"`
+	P95LatencyMs int64 `json:"p95_latency_ms"`
+}
+
+// Aggregates computes pass rate, total cost and latency percentiles.
+func (r *Run) Aggregates() Aggregates {
+	agg := Aggregates{Total: len(r.Scenarios)}
+
+	var durations []int64
+	for _, scenario := range r.Scenarios {
+		agg.TotalCostUSD += scenario.CostUSD
+
+		switch scenario.Status {
+		case "passed":
+			agg.Passed++
+		case "cached":
+			agg.Passed++
+			agg.Cached++
+			continue
+		case "skipped":
+			agg.Skipped++
+			continue
+		default:
+			agg.Failed++
+		}
+		durations = append(durations, scenario.DurationMs)
+	}
+
+	if agg.Total > 0 {
+		agg.PassRate = float64(agg.Passed) / float64(agg.Total) * 100
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	agg.P50LatencyMs = percentile(durations, 50)
+	agg.P95LatencyMs = percentile(durations, 95)
+
+	return agg
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Save writes a run to path, creating its directory.
+func Save(path string, run *Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a run saved with Save.
+func Load(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no recorded run at %s (run 'sentra lab test' first)", path)
+		}
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	return &run, nil
+}
@@ -0,0 +1,58 @@
+package results
+
+import (
+	"sort"
+	"time"
+)
+
+// RunnerStats profile the test runner itself over a run, so large suites
+// can be tuned (e.g. --parallel, or slow agent startup).
+type RunnerStats struct {
+	// Parallel is the number of scenarios allowed to run at once
+	Parallel int `json:"parallel"`
+
+	// Scenarios counts the scenarios that ran (skipped ones excluded)
+	Scenarios          int     `json:"scenarios"`
+	ScenariosPerSecond float64 `json:"scenarios_per_second"`
+
+	// Queue depth is the number of scenarios ready to run but waiting for
+	// a free slot; the average is weighted by time
+	MaxQueueDepth int     `json:"max_queue_depth"`
+	AvgQueueDepth float64 `json:"avg_queue_depth"`
+
+	// QueueWait is how long ready scenarios waited for a slot
+	QueueWait Timing `json:"queue_wait"`
+
+	// AgentStartup is how long the engine took to start the agent
+	AgentStartup Timing `json:"agent_startup"`
+
+	// AssertionEval is the time the engine spent evaluating assertions
+	AssertionEval Timing `json:"assertion_eval"`
+}
+
+// Timing summarizes a set of durations.
+type Timing struct {
+	Count   int   `json:"count"`
+	TotalMs int64 `json:"total_ms"`
+	P50Ms   int64 `json:"p50_ms"`
+	P95Ms   int64 `json:"p95_ms"`
+	MaxMs   int64 `json:"max_ms"`
+}
+
+// NewTiming summarizes durations.
+func NewTiming(durations []time.Duration) Timing {
+	values := make([]int64, len(durations))
+	timing := Timing{Count: len(durations)}
+	for i, d := range durations {
+		values[i] = d.Milliseconds()
+		timing.TotalMs += values[i]
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	timing.P50Ms = percentile(values, 50)
+	timing.P95Ms = percentile(values, 95)
+	if len(values) > 0 {
+		timing.MaxMs = values[len(values)-1]
+	}
+	return timing
+}
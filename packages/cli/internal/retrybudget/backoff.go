@@ -0,0 +1,244 @@
+package retrybudget
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/promptdiff"
+)
+
+// Backoff checks (the engine's backoff_strategy expectation runs the same
+// ones on a single step).
+const (
+	// CheckRetryAfter fires when a retry came sooner than the failed
+	// response asked for
+	CheckRetryAfter = "retry_after"
+
+	// CheckGrowth fires when a request's waits do not grow exponentially
+	CheckGrowth = "growth"
+
+	// CheckJitter fires when a request's waits follow a fixed schedule
+	CheckJitter = "jitter"
+)
+
+// MinGrowth is the smallest average growth between consecutive waits that
+// counts as exponential backoff (2× minus room for jitter)
+const MinGrowth = 1.5
+
+// jitterTolerance is how close growth factors must be to each other to
+// count as a fixed schedule
+const jitterTolerance = 0.02
+
+// jitterMinWaits is the number of waits needed to tell jitter apart from a
+// fixed schedule
+const jitterMinWaits = 3
+
+// Wait is the gap before one retry.
+type Wait struct {
+	// Status is the failed attempt's status
+	Status int `json:"after_status"`
+
+	// WaitMs runs from the failed response to the retry
+	WaitMs int64 `json:"wait_ms"`
+
+	// RequiredMs is the wait the failed response asked for (0 if none)
+	RequiredMs int64 `json:"required_ms,omitempty"`
+}
+
+// Finding is a backoff violation of a retried request.
+type Finding struct {
+	Check  string `json:"check"`
+	Detail string `json:"detail"`
+}
+
+// BackoffChain is a retried request and how the agent waited between its
+// attempts.
+type BackoffChain struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	Waits    []Wait    `json:"waits"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Backoff is a scenario's backoff conformance.
+type Backoff struct {
+	Scenario string `json:"scenario"`
+	RunID    string `json:"run_id,omitempty"`
+
+	// Chains are the retried requests, in the order they were first sent
+	Chains []BackoffChain `json:"chains,omitempty"`
+
+	// Violations counts the chains' findings
+	Violations int `json:"violations"`
+}
+
+// Conforms returns true if every retry backed off as expected.
+func (b Backoff) Conforms() bool {
+	return b.Violations == 0
+}
+
+// CheckBackoff measures the waits between a scenario's retries and checks
+// that the agent honored Retry-After and backed off exponentially, with
+// jitter.
+func CheckBackoff(scenario, runID string, exchanges []promptdiff.Exchange) Backoff {
+	result := Backoff{
+		Scenario: scenario,
+		RunID:    runID,
+	}
+	for _, attempts := range retryChains(exchanges) {
+		chain := checkChain(attempts)
+		result.Chains = append(result.Chains, chain)
+		result.Violations += len(chain.Findings)
+	}
+	return result
+}
+
+// checkChain measures a request's waits and checks them.
+func checkChain(attempts attempts) BackoffChain {
+	chain := BackoffChain{
+		Method: attempts[0].Method,
+		Path:   attempts[0].Path,
+	}
+	for i := 1; i < len(attempts); i++ {
+		failed, retry := attempts[i-1], attempts[i]
+		chain.Waits = append(chain.Waits, Wait{
+			Status:     failed.Status,
+			WaitMs:     retry.StartedAt - (failed.StartedAt + failed.DurationMs),
+			RequiredMs: requiredWaitMs(failed),
+		})
+	}
+
+	for i, wait := range chain.Waits {
+		if wait.RequiredMs > 0 && wait.WaitMs < wait.RequiredMs {
+			chain.Findings = append(chain.Findings, Finding{
+				Check: CheckRetryAfter,
+				Detail: fmt.Sprintf("retry %d came %dms after a %d, which asked for %dms",
+					i+1, wait.WaitMs, wait.Status, wait.RequiredMs),
+			})
+		}
+	}
+
+	if len(chain.Waits) < 2 {
+		return chain
+	}
+
+	factors := growthFactors(chain.Waits)
+	product := 1.0
+	for _, factor := range factors {
+		product *= factor
+	}
+	growth := math.Pow(product, 1/float64(len(factors)))
+	if growth < MinGrowth {
+		chain.Findings = append(chain.Findings, Finding{
+			Check:  CheckGrowth,
+			Detail: fmt.Sprintf("waits %s grow %.2f× per retry, under %g×", formatWaits(chain.Waits), growth, MinGrowth),
+		})
+		return chain
+	}
+
+	if len(chain.Waits) >= jitterMinWaits && fixedSchedule(factors) {
+		chain.Findings = append(chain.Findings, Finding{
+			Check:  CheckJitter,
+			Detail: fmt.Sprintf("waits %s all grow exactly %.2f× per retry (no jitter)", formatWaits(chain.Waits), factors[0]),
+		})
+	}
+	return chain
+}
+
+// requiredWaitMs returns the wait a failed response asked for:
+// retry-after-ms, else the reset time of the limit the mock reports was
+// hit, else Retry-After (seconds).
+func requiredWaitMs(exchange promptdiff.Exchange) int64 {
+	if ms, err := strconv.ParseFloat(header(exchange, "retry-after-ms"), 64); err == nil {
+		return int64(math.Ceil(ms))
+	}
+	if factor := header(exchange, "x-sentra-limiting-factor"); factor != "" {
+		if reset, err := time.ParseDuration(header(exchange, "x-ratelimit-reset-"+factor)); err == nil {
+			return int64(math.Ceil(float64(reset) / float64(time.Millisecond)))
+		}
+	}
+	if seconds, err := strconv.ParseInt(header(exchange, "retry-after"), 10, 64); err == nil {
+		return seconds * 1000
+	}
+	return 0
+}
+
+// header returns a response header, matched case-insensitively.
+func header(exchange promptdiff.Exchange, name string) string {
+	for key, value := range exchange.ResponseHeaders {
+		if strings.EqualFold(key, name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// growthFactors returns how much each wait grew over the one before it.
+func growthFactors(waits []Wait) []float64 {
+	factors := make([]float64, 0, len(waits)-1)
+	for i := 1; i < len(waits); i++ {
+		factors = append(factors, float64(max(waits[i].WaitMs, 1))/float64(max(waits[i-1].WaitMs, 1)))
+	}
+	return factors
+}
+
+// fixedSchedule returns true if every growth factor is the same (within
+// jitterTolerance), as a backoff without jitter produces.
+func fixedSchedule(factors []float64) bool {
+	for _, factor := range factors {
+		if math.Abs(factor-factors[0]) > factors[0]*jitterTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// NonConforming returns the scenarios with backoff violations.
+func NonConforming(scenarios []Backoff) []Backoff {
+	var flagged []Backoff
+	for _, scenario := range scenarios {
+		if !scenario.Conforms() {
+			flagged = append(flagged, scenario)
+		}
+	}
+	return flagged
+}
+
+// RenderBackoff writes the scenarios' backoff conformance as a table,
+// followed by their violations.
+func RenderBackoff(w io.Writer, scenarios []Backoff) {
+	fmt.Fprintf(w, "%-32s %8s %10s\n", "SCENARIO", "RETRIED", "VIOLATIONS")
+	for _, scenario := range scenarios {
+		violations := fmt.Sprintf("%d", scenario.Violations)
+		if !scenario.Conforms() {
+			violations += " ⚠"
+		}
+		fmt.Fprintf(w, "%-32s %8d %10s\n", truncate(scenario.Scenario, 32), len(scenario.Chains), violations)
+	}
+
+	for _, scenario := range scenarios {
+		if scenario.Conforms() {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: %d backoff violation(s)\n", scenario.Scenario, scenario.Violations)
+		for _, chain := range scenario.Chains {
+			for _, finding := range chain.Findings {
+				fmt.Fprintf(w, "  %s %s [%s] %s\n", chain.Method, chain.Path, finding.Check, finding.Detail)
+			}
+		}
+	}
+}
+
+// formatWaits lists waits for a finding (e.g. "[1s, 1s, 1s]").
+func formatWaits(waits []Wait) string {
+	parts := make([]string, len(waits))
+	for i, wait := range waits {
+		parts[i] = formatLatency(wait.WaitMs)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,143 @@
+package retrybudget
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/promptdiff"
+)
+
+// retries returns a request that failed with status before each wait and
+// then succeeded; waits run from the failed response to the retry.
+func retries(status int, headers map[string]string, waits ...int64) []promptdiff.Exchange {
+	exchanges := []promptdiff.Exchange{attempt(1, `{}`, status, 0, headers)}
+	startedAt := int64(0)
+	for i, wait := range waits {
+		startedAt += 100 + wait
+		status := status
+		if i == len(waits)-1 {
+			status = 200
+		}
+		exchanges = append(exchanges, attempt(int64(i+2), `{}`, status, startedAt, headers))
+	}
+	return exchanges
+}
+
+func TestCheckBackoff(t *testing.T) {
+	tests := []struct {
+		name      string
+		exchanges []promptdiff.Exchange
+		want      []string
+	}{
+		{
+			name:      "exponential with jitter",
+			exchanges: retries(429, nil, 1000, 2100, 3900, 8300),
+		},
+		{
+			name:      "single retry",
+			exchanges: retries(500, nil, 10),
+		},
+		{
+			name:      "fixed waits",
+			exchanges: retries(500, nil, 1000, 1000, 1000),
+			want:      []string{CheckGrowth},
+		},
+		{
+			name:      "no jitter",
+			exchanges: retries(500, nil, 1000, 2000, 4000),
+			want:      []string{CheckJitter},
+		},
+		{
+			name:      "too few waits to tell jitter",
+			exchanges: retries(500, nil, 1000, 2000),
+		},
+		{
+			name:      "ignores Retry-After",
+			exchanges: retries(429, map[string]string{"Retry-After": "2"}, 500),
+			want:      []string{CheckRetryAfter},
+		},
+		{
+			name:      "honors retry-after-ms",
+			exchanges: retries(429, map[string]string{"retry-after-ms": "250.5"}, 251),
+		},
+		{
+			name: "ignores the reset of the limit hit",
+			exchanges: retries(429, map[string]string{
+				"x-sentra-limiting-factor": "tokens",
+				"x-ratelimit-reset-tokens": "1.5s",
+				"retry-after":              "1",
+			}, 1200),
+			want: []string{CheckRetryAfter},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckBackoff("scenarios/refund.yaml", "run_1", tt.exchanges)
+			if len(result.Chains) != 1 {
+				t.Fatalf("CheckBackoff() found %d chains, want 1", len(result.Chains))
+			}
+
+			var checks []string
+			for _, finding := range result.Chains[0].Findings {
+				checks = append(checks, finding.Check)
+			}
+			if strings.Join(checks, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("findings = %+v, want checks %v", result.Chains[0].Findings, tt.want)
+			}
+			if result.Violations != len(tt.want) || result.Conforms() != (len(tt.want) == 0) {
+				t.Errorf("Violations = %d, Conforms() = %v, want %d violations", result.Violations, result.Conforms(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRequiredWaitMs(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int64
+	}{
+		{name: "none", want: 0},
+		{name: "retry-after-ms", headers: map[string]string{"Retry-After-Ms": "120.2", "Retry-After": "5"}, want: 121},
+		{name: "limit reset", headers: map[string]string{"x-sentra-limiting-factor": "requests", "x-ratelimit-reset-requests": "2s", "retry-after": "5"}, want: 2000},
+		{name: "retry-after", headers: map[string]string{"retry-after": "3"}, want: 3000},
+		{name: "HTTP date", headers: map[string]string{"retry-after": "Wed, 21 Oct 2015 07:28:00 GMT"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchange := promptdiff.Exchange{ResponseHeaders: tt.headers}
+			if got := requiredWaitMs(exchange); got != tt.want {
+				t.Errorf("requiredWaitMs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderBackoff(t *testing.T) {
+	scenarios := []Backoff{
+		CheckBackoff("scenarios/refund.yaml", "run_1", retries(500, nil, 1000, 1000, 1000)),
+		CheckBackoff("scenarios/chat.yaml", "run_1", retries(429, nil, 1000, 2100, 3900, 8300)),
+	}
+	if flagged := NonConforming(scenarios); len(flagged) != 1 || flagged[0].Scenario != "scenarios/refund.yaml" {
+		t.Errorf("NonConforming() = %+v, want scenarios/refund.yaml", flagged)
+	}
+
+	var buf bytes.Buffer
+	RenderBackoff(&buf, scenarios)
+	out := buf.String()
+
+	for _, want := range []string{
+		"scenarios/refund.yaml: 1 backoff violation(s)",
+		"POST /v1/chat/completions [growth] waits [1s, 1s, 1s] grow 1.00× per retry, under 1.5×",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RenderBackoff() = %q, want it to contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "scenarios/chat.yaml: ") {
+		t.Errorf("RenderBackoff() = %q, lists violations for a conforming scenario", out)
+	}
+}
@@ -0,0 +1,279 @@
+// Package retrybudget finds the retries in a run's requests to the OpenAI
+// mock (the same payload re-sent after a failed request) and what they
+// added in cost and latency, so agents that compensate for errors with
+// expensive retry storms stand out.
+package retrybudget
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/promptdiff"
+)
+
+// DefaultBudget is the share of a scenario's requests that may be retries
+// before it is flagged
+const DefaultBudget = 0.1
+
+// StormRetries is the number of retries of one request that makes a storm
+const StormRetries = 3
+
+// costHeader is the mock's response header with a request's simulated cost
+const costHeader = "X-Sentra-Cost-Total"
+
+// Chain is a request and its retries: each attempt re-sent the payload of
+// the failed attempt before it.
+type Chain struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	// Statuses are the attempts' response statuses, in order
+	Statuses []int `json:"statuses"`
+
+	// Succeeded is set when the last attempt succeeded
+	Succeeded bool `json:"succeeded"`
+
+	// AddedCost is the cost of every attempt but the last, and
+	// AddedLatencyMs the time from the first attempt to the last
+	AddedCost      float64 `json:"added_cost_usd"`
+	AddedLatencyMs int64   `json:"added_latency_ms"`
+}
+
+// Retries returns the number of retries (attempts after the first).
+func (c Chain) Retries() int {
+	return len(c.Statuses) - 1
+}
+
+// Storm returns true if the request was retried at least StormRetries times.
+func (c Chain) Storm() bool {
+	return c.Retries() >= StormRetries
+}
+
+// Scenario is a scenario's retry budget.
+type Scenario struct {
+	Scenario string `json:"scenario"`
+	RunID    string `json:"run_id,omitempty"`
+
+	// Requests is the number of requests, Retries how many of them were
+	// retries, and RetryRate their share
+	Requests  int     `json:"requests"`
+	Retries   int     `json:"retries"`
+	RetryRate float64 `json:"retry_rate"`
+
+	// AddedCost and AddedLatencyMs total the retried chains'
+	AddedCost      float64 `json:"added_cost_usd"`
+	AddedLatencyMs int64   `json:"added_latency_ms"`
+
+	// Budget is the allowed retry rate; OverBudget is set when RetryRate
+	// exceeds it
+	Budget     float64 `json:"budget"`
+	OverBudget bool    `json:"over_budget"`
+
+	// Chains are the retried requests, most retries first
+	Chains []Chain `json:"chains,omitempty"`
+}
+
+// Storms returns the scenario's retry storms.
+func (s Scenario) Storms() []Chain {
+	var storms []Chain
+	for _, chain := range s.Chains {
+		if chain.Storm() {
+			storms = append(storms, chain)
+		}
+	}
+	return storms
+}
+
+// attempts are the exchanges of a chain being built.
+type attempts []promptdiff.Exchange
+
+// Analyze finds the retries in a scenario's exchanges and checks their share
+// of the requests against budget.
+func Analyze(scenario, runID string, exchanges []promptdiff.Exchange, budget float64) Scenario {
+	result := Scenario{
+		Scenario: scenario,
+		RunID:    runID,
+		Requests: len(exchanges),
+		Budget:   budget,
+	}
+	for _, exchanges := range retryChains(exchanges) {
+		chain := newChain(exchanges)
+		result.Chains = append(result.Chains, chain)
+		result.Retries += chain.Retries()
+		result.AddedCost += chain.AddedCost
+		result.AddedLatencyMs += chain.AddedLatencyMs
+	}
+	sort.SliceStable(result.Chains, func(i, j int) bool {
+		return result.Chains[i].Retries() > result.Chains[j].Retries()
+	})
+
+	if result.Requests > 0 {
+		result.RetryRate = float64(result.Retries) / float64(result.Requests)
+	}
+	result.OverBudget = result.RetryRate > budget
+	return result
+}
+
+// retryChains groups exchanges into retried requests, in the order of their
+// first attempt.
+func retryChains(exchanges []promptdiff.Exchange) []attempts {
+	sorted := make([]promptdiff.Exchange, len(exchanges))
+	copy(sorted, exchanges)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Seq < sorted[j].Seq
+	})
+
+	// open maps payloads to chains whose last attempt failed, so the same
+	// payload sent next is a retry
+	open := make(map[string]attempts)
+	var retried []attempts
+	for _, exchange := range sorted {
+		key := payloadKey(exchange)
+		chain, retry := open[key]
+		if !retry && !failed(exchange) {
+			continue
+		}
+
+		chain = append(chain, exchange)
+		if failed(exchange) {
+			open[key] = chain
+			continue
+		}
+		delete(open, key)
+		retried = append(retried, chain)
+	}
+	for _, chain := range open {
+		if len(chain) > 1 {
+			retried = append(retried, chain)
+		}
+	}
+	sort.Slice(retried, func(i, j int) bool {
+		return retried[i][0].Seq < retried[j][0].Seq
+	})
+	return retried
+}
+
+// newChain summarizes a request's attempts.
+func newChain(attempts attempts) Chain {
+	first, last := attempts[0], attempts[len(attempts)-1]
+	chain := Chain{
+		Method:         first.Method,
+		Path:           first.Path,
+		Succeeded:      !failed(last),
+		AddedLatencyMs: last.StartedAt - first.StartedAt,
+	}
+	for i, attempt := range attempts {
+		chain.Statuses = append(chain.Statuses, attempt.Status)
+		if i < len(attempts)-1 {
+			chain.AddedCost += cost(attempt)
+		}
+	}
+	return chain
+}
+
+// OverBudget returns the scenarios over their retry budget.
+func OverBudget(scenarios []Scenario) []Scenario {
+	var flagged []Scenario
+	for _, scenario := range scenarios {
+		if scenario.OverBudget {
+			flagged = append(flagged, scenario)
+		}
+	}
+	return flagged
+}
+
+// Render writes the scenarios' retry budgets as a table, followed by their
+// retry storms.
+func Render(w io.Writer, scenarios []Scenario) {
+	fmt.Fprintf(w, "%-32s %8s %7s %7s %11s %13s\n", "SCENARIO", "REQUESTS", "RETRIES", "RATE", "ADDED COST", "ADDED LATENCY")
+	for _, scenario := range scenarios {
+		rate := fmt.Sprintf("%5.1f%%", scenario.RetryRate*100)
+		if scenario.OverBudget {
+			rate += " ⚠"
+		}
+		fmt.Fprintf(w, "%-32s %8d %7d %7s %11s %13s\n",
+			truncate(scenario.Scenario, 32), scenario.Requests, scenario.Retries, rate,
+			fmt.Sprintf("$%.4f", scenario.AddedCost), formatLatency(scenario.AddedLatencyMs))
+	}
+
+	for _, scenario := range scenarios {
+		storms := scenario.Storms()
+		if len(storms) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: %d retry storm(s)\n", scenario.Scenario, len(storms))
+		for _, storm := range storms {
+			outcome := "then succeeded"
+			if !storm.Succeeded {
+				outcome = "never succeeded"
+			}
+			fmt.Fprintf(w, "  %s %s retried %d times (%s), %s, +$%.4f, +%s\n",
+				storm.Method, storm.Path, storm.Retries(), formatStatuses(storm.Statuses), outcome,
+				storm.AddedCost, formatLatency(storm.AddedLatencyMs))
+		}
+	}
+}
+
+// payloadKey identifies what a request sent: its endpoint and its body,
+// with JSON re-encoded so key order does not matter.
+func payloadKey(exchange promptdiff.Exchange) string {
+	payload := exchange.Payload()
+	var value interface{}
+	if json.Unmarshal(payload, &value) == nil {
+		if encoded, err := json.Marshal(value); err == nil {
+			payload = encoded
+		}
+	}
+	return exchange.Method + " " + exchange.Path + "?" + exchange.Query + "\n" + string(payload)
+}
+
+// failed returns true if a request failed (or never got a response).
+func failed(exchange promptdiff.Exchange) bool {
+	return exchange.Status == 0 || exchange.Status >= http.StatusBadRequest
+}
+
+// cost returns a request's simulated cost (0 if it was not billed).
+func cost(exchange promptdiff.Exchange) float64 {
+	for name, value := range exchange.ResponseHeaders {
+		if strings.EqualFold(name, costHeader) {
+			var cost float64
+			fmt.Sscanf(value, "%g", &cost)
+			return cost
+		}
+	}
+	return 0
+}
+
+// formatStatuses lists statuses, collapsing repeats (e.g. "429×4, 200").
+func formatStatuses(statuses []int) string {
+	var parts []string
+	for i := 0; i < len(statuses); {
+		j := i
+		for j < len(statuses) && statuses[j] == statuses[i] {
+			j++
+		}
+		if j-i > 1 {
+			parts = append(parts, fmt.Sprintf("%d×%d", statuses[i], j-i))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d", statuses[i]))
+		}
+		i = j
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatLatency(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(10 * time.Millisecond).String()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
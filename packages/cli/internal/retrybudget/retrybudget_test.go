@@ -0,0 +1,158 @@
+package retrybudget
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/promptdiff"
+)
+
+// attempt returns a chat completion exchange sending body.
+func attempt(seq int64, body string, status int, startedAt int64, headers map[string]string) promptdiff.Exchange {
+	return promptdiff.Exchange{
+		Seq:             seq,
+		Method:          "POST",
+		Path:            "/v1/chat/completions",
+		RequestBody:     body,
+		Status:          status,
+		ResponseHeaders: headers,
+		StartedAt:       startedAt,
+		DurationMs:      100,
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	cost := map[string]string{"x-sentra-cost-total": "0.002"}
+	exchanges := []promptdiff.Exchange{
+		attempt(1, `{"model": "gpt-4o", "n": 1}`, 429, 0, cost),
+		attempt(2, `{"n": 1, "model": "gpt-4o"}`, 429, 1000, cost),
+		attempt(3, `{"model": "gpt-4o", "n": 1}`, 200, 3000, cost),
+		attempt(4, `{"model": "gpt-4o-mini"}`, 200, 4000, nil),
+		attempt(5, `{"model": "gpt-4o-mini"}`, 200, 5000, nil),
+		attempt(6, `{"model": "o1"}`, 500, 6000, nil),
+		attempt(7, `{"model": "o1"}`, 500, 7000, nil),
+		attempt(8, `{"model": "o1"}`, 0, 8000, nil),
+		attempt(9, `{"model": "o1"}`, 503, 9000, nil),
+		attempt(10, `{"model": "gpt-3.5-turbo"}`, 500, 10000, nil),
+	}
+
+	result := Analyze("scenarios/refund.yaml", "run_1", exchanges, DefaultBudget)
+
+	if result.Requests != 10 || result.Retries != 5 || result.RetryRate != 0.5 || !result.OverBudget {
+		t.Errorf("Analyze() = %d requests, %d retries, rate %g, over budget %v, want 10, 5, 0.5, true",
+			result.Requests, result.Retries, result.RetryRate, result.OverBudget)
+	}
+	wantChains := []Chain{
+		{Method: "POST", Path: "/v1/chat/completions", Statuses: []int{500, 500, 0, 503}, AddedLatencyMs: 3000},
+		{Method: "POST", Path: "/v1/chat/completions", Statuses: []int{429, 429, 200}, Succeeded: true, AddedCost: 0.004, AddedLatencyMs: 3000},
+	}
+	if !reflect.DeepEqual(result.Chains, wantChains) {
+		t.Errorf("Chains = %+v, want %+v", result.Chains, wantChains)
+	}
+	if storms := result.Storms(); len(storms) != 1 || storms[0].Retries() != 3 {
+		t.Errorf("Storms() = %+v, want the chain retried 3 times", storms)
+	}
+	if result.AddedLatencyMs != 6000 {
+		t.Errorf("AddedLatencyMs = %d, want 6000", result.AddedLatencyMs)
+	}
+}
+
+func TestAnalyzeBudget(t *testing.T) {
+	exchanges := []promptdiff.Exchange{
+		attempt(1, `{}`, 429, 0, nil),
+		attempt(2, `{}`, 200, 1000, nil),
+		attempt(3, `{"a": 1}`, 200, 2000, nil),
+		attempt(4, `{"a": 2}`, 200, 3000, nil),
+	}
+
+	tests := []struct {
+		name   string
+		budget float64
+		want   bool
+	}{
+		{name: "over", budget: 0.1, want: true},
+		{name: "at", budget: 0.25},
+		{name: "under", budget: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze("s", "", exchanges, tt.budget).OverBudget; got != tt.want {
+				t.Errorf("OverBudget = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if result := Analyze("empty", "", nil, DefaultBudget); result.RetryRate != 0 || result.OverBudget {
+		t.Errorf("Analyze() without requests = %+v, want no retries", result)
+	}
+}
+
+func TestOverBudget(t *testing.T) {
+	scenarios := []Scenario{{Scenario: "a"}, {Scenario: "b", OverBudget: true}}
+	if got := OverBudget(scenarios); len(got) != 1 || got[0].Scenario != "b" {
+		t.Errorf("OverBudget() = %+v, want scenario b", got)
+	}
+}
+
+func TestFormatStatuses(t *testing.T) {
+	tests := []struct {
+		statuses []int
+		want     string
+	}{
+		{statuses: []int{429, 429, 429, 429, 200}, want: "429×4, 200"},
+		{statuses: []int{500, 503, 500}, want: "500, 503, 500"},
+		{statuses: []int{200}, want: "200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatStatuses(tt.statuses); got != tt.want {
+				t.Errorf("formatStatuses(%v) = %q, want %q", tt.statuses, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	scenarios := []Scenario{
+		{
+			Scenario:       "scenarios/refund.yaml",
+			Requests:       5,
+			Retries:        4,
+			RetryRate:      0.8,
+			AddedCost:      0.0123,
+			AddedLatencyMs: 4200,
+			OverBudget:     true,
+			Chains: []Chain{{
+				Method:         "POST",
+				Path:           "/v1/chat/completions",
+				Statuses:       []int{429, 429, 429, 429, 200},
+				Succeeded:      true,
+				AddedCost:      0.0123,
+				AddedLatencyMs: 4200,
+			}},
+		},
+		{Scenario: "scenarios/chat.yaml", Requests: 2},
+	}
+
+	var buf bytes.Buffer
+	Render(&buf, scenarios)
+	out := buf.String()
+
+	for _, want := range []string{
+		"80.0% ⚠",
+		"$0.0123",
+		"scenarios/refund.yaml: 1 retry storm(s)",
+		"POST /v1/chat/completions retried 4 times (429×4, 200), then succeeded, +$0.0123, +4.2s",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() = %q, want it to contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "scenarios/chat.yaml: ") {
+		t.Errorf("Render() = %q, lists storms for a scenario without any", out)
+	}
+}
@@ -0,0 +1,340 @@
+// Package sarif converts guardrail findings into SARIF 2.1.0, so GitHub code
+// scanning shows leaked canaries, secrets and successful prompt injections
+// as security alerts on the pull request rather than only as failed tests.
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/results"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Version is the SARIF version written
+	Version = "2.1.0"
+
+	// Schema is the SARIF 2.1.0 JSON schema
+	Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// fingerprintKey names the partial fingerprint code scanning uses to
+	// track a finding across runs
+	fingerprintKey = "sentraGuardrailFinding/v1"
+)
+
+// Log is a SARIF log.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is the output of one tool invocation.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool and its rules.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the tool's main component.
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule describes one guardrail check.
+type Rule struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name"`
+	ShortDescription     Message        `json:"shortDescription"`
+	FullDescription      Message        `json:"fullDescription"`
+	Help                 Message        `json:"help"`
+	DefaultConfiguration Configuration  `json:"defaultConfiguration"`
+	Properties           RuleProperties `json:"properties"`
+}
+
+// Configuration is a rule's default reporting configuration.
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// RuleProperties are the properties GitHub reads to classify alerts.
+type RuleProperties struct {
+	Tags []string `json:"tags"`
+
+	// SecuritySeverity is a CVSS-like score from 0.0 to 10.0; GitHub maps
+	// it to critical, high, medium or low
+	SecuritySeverity string `json:"security-severity"`
+}
+
+// Message is SARIF text.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is one finding.
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+// Location is where a finding was made.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a file region.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+// ArtifactLocation is a file, relative to the repository root.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a line range.
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// rules are the guardrail checks, by check name.
+var rules = map[string]Rule{
+	"canary_leak": {
+		ID:               "guardrail/canary-leak",
+		Name:             "CanaryLeak",
+		ShortDescription: Message{"Canary string leaked"},
+		FullDescription:  Message{"A canary string planted in the scenario's fixtures left the agent, in its output or in a tool call's arguments."},
+		Help:             Message{"Data the agent reads from tools must not reach its output or outbound tool calls. Check which tool call or reply carried the canary and tighten the agent's handling of untrusted content."},
+		Properties:       RuleProperties{SecuritySeverity: "9.0"},
+	},
+	"secret_leak": {
+		ID:               "guardrail/secret-leak",
+		Name:             "SecretLeak",
+		ShortDescription: Message{"Secret leaked"},
+		FullDescription:  Message{"The agent's output or a tool call's arguments contain a credential, such as an API key or a private key."},
+		Help:             Message{"Keep credentials out of the model's context, or redact them before the agent replies or calls a tool."},
+		Properties:       RuleProperties{SecuritySeverity: "9.0"},
+	},
+	"prompt_injection": {
+		ID:               "guardrail/prompt-injection",
+		Name:             "PromptInjection",
+		ShortDescription: Message{"Prompt injection succeeded"},
+		FullDescription:  Message{"The agent followed instructions injected into its input or revealed protected system prompt instructions."},
+		Help:             Message{"Treat tool results and user content as data, not instructions. Check the step's input for the injected text the agent acted on."},
+		Properties:       RuleProperties{SecuritySeverity: "8.0"},
+	},
+	"disallowed_tool": {
+		ID:               "guardrail/disallowed-tool",
+		Name:             "DisallowedTool",
+		ShortDescription: Message{"Disallowed tool called"},
+		FullDescription:  Message{"The agent called a tool the scenario forbids, or one outside its allowed tools."},
+		Help:             Message{"Restrict the tools the agent may call in this context, or require confirmation before sensitive tools run."},
+		Properties:       RuleProperties{SecuritySeverity: "7.5"},
+	},
+}
+
+// ruleOrder is the order rules are listed in, most severe first.
+var ruleOrder = []string{"canary_leak", "secret_leak", "prompt_injection", "disallowed_tool"}
+
+// Build converts the guardrail findings of a run into a SARIF log. prefix
+// is the lab project's path within the repository ("" at its root), so
+// scenario paths resolve against the repository code scanning sees.
+func Build(run *results.Run, prefix string) *Log {
+	driver := Driver{
+		Name:           "Sentra Lab",
+		InformationURI: "https://github.com/itsemmanuelingwani/sentra-lab",
+	}
+	index := make(map[string]int)
+	for _, check := range ruleOrder {
+		index[check] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, withDefaults(rules[check]))
+	}
+
+	found := []Result{}
+	for _, scenario := range run.Scenarios {
+		file := scenarioPath(scenario.Scenario)
+		uri := path.Join(filepath.ToSlash(prefix), filepath.ToSlash(file))
+
+		for _, finding := range scenario.Findings {
+			i, ok := index[finding.Check]
+			if !ok {
+				// A check added to the engine after this CLI was built
+				i = len(driver.Rules)
+				index[finding.Check] = i
+				driver.Rules = append(driver.Rules, withDefaults(Rule{
+					ID:               "guardrail/" + strings.ReplaceAll(finding.Check, "_", "-"),
+					Name:             finding.Check,
+					ShortDescription: Message{fmt.Sprintf("Guardrail violation (%s)", finding.Check)},
+					FullDescription:  Message{fmt.Sprintf("An assert_guardrails step reported a %s violation.", finding.Check)},
+					Help:             Message{"See the scenario's assert_guardrails step for the check that fired."},
+					Properties:       RuleProperties{SecuritySeverity: "7.0"},
+				}))
+			}
+			rule := driver.Rules[i]
+
+			found = append(found, Result{
+				RuleID:    rule.ID,
+				RuleIndex: i,
+				Level:     rule.DefaultConfiguration.Level,
+				Message:   Message{message(rule, scenario.Scenario, finding)},
+				Locations: []Location{{
+					PhysicalLocation: PhysicalLocation{
+						ArtifactLocation: ArtifactLocation{URI: uri},
+						Region:           Region{StartLine: stepLine(file, finding.Step)},
+					},
+				}},
+				PartialFingerprints: map[string]string{
+					fingerprintKey: fingerprint(scenario.Scenario, finding),
+				},
+				Properties: map[string]string{
+					"scenario": scenario.Scenario,
+					"run_id":   scenario.RunID,
+					"location": finding.Location,
+				},
+			})
+		}
+	}
+
+	return &Log{
+		Schema:  Schema,
+		Version: Version,
+		Runs:    []Run{{Tool: Tool{Driver: driver}, Results: found}},
+	}
+}
+
+// Count returns the number of findings in the log.
+func (l *Log) Count() int {
+	count := 0
+	for _, run := range l.Runs {
+		count += len(run.Results)
+	}
+	return count
+}
+
+// Write writes the log to path, creating its directory.
+func (l *Log) Write(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create SARIF directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SARIF: %w", err)
+	}
+	return nil
+}
+
+func withDefaults(rule Rule) Rule {
+	rule.DefaultConfiguration = Configuration{Level: "error"}
+	rule.Properties.Tags = []string{"security", "guardrails"}
+	return rule
+}
+
+// message describes a finding, e.g. "Canary string leaked in
+// tool_call:send_email: CANARY-7f3a91 (scenario scenarios/refund.yaml,
+// step check-leaks)".
+func message(rule Rule, scenario string, finding results.Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s in %s", rule.ShortDescription.Text, finding.Location)
+	if finding.Detail != "" {
+		fmt.Fprintf(&b, ": %s", finding.Detail)
+	}
+	fmt.Fprintf(&b, " (scenario %s", scenario)
+	if finding.Step != "" {
+		fmt.Fprintf(&b, ", step %s", finding.Step)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// fingerprint identifies a finding across runs, so code scanning updates
+// the same alert instead of opening a new one each run. The detail is left
+// out: it holds the leaked value, and canaries differ from run to run.
+func fingerprint(scenario string, finding results.Finding) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{scenario, finding.Step, finding.Check, finding.Location}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// scenarioPath returns the scenario file of a case name, dropping the data
+// row label of data-driven cases ("scenarios/refund.yaml[alice]").
+func scenarioPath(name string) string {
+	if strings.HasSuffix(name, "]") {
+		if i := strings.LastIndex(name, "["); i > 0 {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+// stepLine returns the line of the scenario step with the given ID, or of
+// its first assert_guardrails step. Findings fall back to line 1 when the
+// file cannot be read.
+func stepLine(file, step string) int {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 1
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return 1
+	}
+
+	steps := mappingValue(root.Content[0], "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return 1
+	}
+
+	line := 0
+	for _, item := range steps.Content {
+		if step != "" {
+			if id := mappingValue(item, "id"); id != nil && id.Value == step {
+				return item.Line
+			}
+		}
+		if action := mappingValue(item, "action"); line == 0 && action != nil && action.Value == "assert_guardrails" {
+			line = item.Line
+		}
+	}
+
+	if line == 0 {
+		return 1
+	}
+	return line
+}
+
+// mappingValue returns the value of key in a YAML mapping node.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
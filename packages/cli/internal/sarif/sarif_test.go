@@ -0,0 +1,189 @@
+package sarif
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/results"
+)
+
+const refundScenario = `name: Refund flow
+steps:
+  - id: ask
+    action: send_message
+  - action: assert_guardrails
+  - id: check-leaks
+    action: assert_guardrails
+`
+
+// inDir runs the test from dir, so scenario paths resolve against it.
+func inDir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func writeScenario(t *testing.T) {
+	t.Helper()
+
+	if err := os.MkdirAll("scenarios", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("scenarios", "refund.yaml"), []byte(refundScenario), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	inDir(t, t.TempDir())
+	writeScenario(t)
+
+	run := &results.Run{Scenarios: []results.ScenarioResult{
+		{Scenario: "scenarios/refund.yaml", RunID: "run_1", Findings: []results.Finding{
+			{Check: "canary_leak", Location: "tool_call:send_email", Detail: "CANARY-7f3a91", Step: "check-leaks"},
+			{Check: "pii_leak", Location: "output"},
+		}},
+		{Scenario: "scenarios/passing.yaml", RunID: "run_1"},
+	}}
+
+	log := Build(run, "agents/support")
+
+	if log.Version != Version || log.Schema != Schema || len(log.Runs) != 1 {
+		t.Fatalf("Build() = version %q, schema %q, %d runs", log.Version, log.Schema, len(log.Runs))
+	}
+	if got := log.Count(); got != 2 {
+		t.Fatalf("Count() = %d, want 2", got)
+	}
+
+	rules := log.Runs[0].Tool.Driver.Rules
+	if len(rules) != len(ruleOrder)+1 || rules[len(rules)-1].ID != "guardrail/pii-leak" {
+		t.Errorf("rules = %+v, want the known rules and guardrail/pii-leak", rules)
+	}
+
+	tests := []struct {
+		name      string
+		ruleID    string
+		ruleIndex int
+		line      int
+		message   string
+	}{
+		{
+			name:      "known check at its step",
+			ruleID:    "guardrail/canary-leak",
+			ruleIndex: 0,
+			line:      6,
+			message:   "Canary string leaked in tool_call:send_email: CANARY-7f3a91 (scenario scenarios/refund.yaml, step check-leaks)",
+		},
+		{
+			name:      "unknown check at the first guardrail step",
+			ruleID:    "guardrail/pii-leak",
+			ruleIndex: len(ruleOrder),
+			line:      5,
+			message:   "Guardrail violation (pii_leak) in output (scenario scenarios/refund.yaml)",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := log.Runs[0].Results[i]
+			if result.RuleID != tt.ruleID || result.RuleIndex != tt.ruleIndex || result.Level != "error" {
+				t.Errorf("result = %s (%d, %s), want %s (%d, error)", result.RuleID, result.RuleIndex, result.Level, tt.ruleID, tt.ruleIndex)
+			}
+			if result.Message.Text != tt.message {
+				t.Errorf("message = %q, want %q", result.Message.Text, tt.message)
+			}
+
+			location := result.Locations[0].PhysicalLocation
+			if location.ArtifactLocation.URI != "agents/support/scenarios/refund.yaml" || location.Region.StartLine != tt.line {
+				t.Errorf("location = %s:%d, want agents/support/scenarios/refund.yaml:%d",
+					location.ArtifactLocation.URI, location.Region.StartLine, tt.line)
+			}
+			if result.PartialFingerprints[fingerprintKey] == "" {
+				t.Error("result has no fingerprint")
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := results.Finding{Check: "canary_leak", Location: "output", Detail: "CANARY-7f3a91", Step: "check-leaks"}
+
+	tests := []struct {
+		name     string
+		scenario string
+		change   func(f *results.Finding)
+		same     bool
+	}{
+		{name: "new canary", scenario: "scenarios/refund.yaml", change: func(f *results.Finding) { f.Detail = "CANARY-0b12cd" }, same: true},
+		{name: "other scenario", scenario: "scenarios/billing.yaml", change: func(f *results.Finding) {}},
+		{name: "other step", scenario: "scenarios/refund.yaml", change: func(f *results.Finding) { f.Step = "ask" }},
+		{name: "other check", scenario: "scenarios/refund.yaml", change: func(f *results.Finding) { f.Check = "secret_leak" }},
+		{name: "other location", scenario: "scenarios/refund.yaml", change: func(f *results.Finding) { f.Location = "tool_call:send_email" }},
+	}
+
+	want := fingerprint("scenarios/refund.yaml", base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := base
+			tt.change(&finding)
+
+			if got := fingerprint(tt.scenario, finding); (got == want) != tt.same {
+				t.Errorf("fingerprint() = %s, base %s, want same = %v", got, want, tt.same)
+			}
+		})
+	}
+}
+
+func TestScenarioPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "scenarios/refund.yaml", want: "scenarios/refund.yaml"},
+		{name: "scenarios/refund.yaml[alice]", want: "scenarios/refund.yaml"},
+		{name: "[alice]", want: "[alice]"},
+	}
+
+	for _, tt := range tests {
+		if got := scenarioPath(tt.name); got != tt.want {
+			t.Errorf("scenarioPath(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStepLineMissingFile(t *testing.T) {
+	if got := stepLine(filepath.Join(t.TempDir(), "missing.yaml"), "ask"); got != 1 {
+		t.Errorf("stepLine() = %d, want 1", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "guardrails.sarif")
+	log := Build(&results.Run{}, "")
+
+	if err := log.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Log
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("written log is not JSON: %v", err)
+	}
+	if !strings.Contains(string(data), `"results": []`) {
+		t.Errorf("written log = %s, want an empty results array", data)
+	}
+}
@@ -0,0 +1,202 @@
+package scenario
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Case is one test case: a scenario, or one row of its variables_from data.
+type Case struct {
+	// Path is the scenario file.
+	Path string
+
+	// Name identifies the case in results: the path, with the row's label
+	// appended for data-driven cases (e.g. "scenarios/refund.yaml[alice]").
+	Name string
+
+	// Row is the 1-based data row, or 0 if the scenario is not data-driven.
+	Row int
+
+	// Variables are the row's values; they override the scenario's
+	// declared variables of the same name.
+	Variables map[string]string
+}
+
+// ExpandCases returns the test cases of a scenario file: one per row of its
+// variables_from data, or the scenario itself if it has none.
+func ExpandCases(path string) ([]Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var doc struct {
+		VariablesFrom string `yaml:"variables_from"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if doc.VariablesFrom == "" {
+		return []Case{{Path: path, Name: path}}, nil
+	}
+
+	rows, err := LoadVariables(resolveDataPath(filepath.Dir(path), doc.VariablesFrom))
+	if err != nil {
+		return nil, fmt.Errorf("scenario %s: %w", path, err)
+	}
+
+	cases := make([]Case, len(rows))
+	for i, row := range rows {
+		cases[i] = Case{
+			Path:      path,
+			Name:      fmt.Sprintf("%s[%s]", path, rowLabel(row, i+1)),
+			Row:       i + 1,
+			Variables: row,
+		}
+	}
+	return cases, nil
+}
+
+// DataFile returns a scenario's variables_from file, resolved relative to
+// the scenario ("" if it has none).
+func DataFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var doc struct {
+		VariablesFrom string `yaml:"variables_from"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if doc.VariablesFrom == "" {
+		return "", nil
+	}
+	return resolveDataPath(filepath.Dir(path), doc.VariablesFrom), nil
+}
+
+// resolveDataPath resolves a variables_from path relative to the
+// scenario's directory.
+func resolveDataPath(dir, dataPath string) string {
+	if filepath.IsAbs(dataPath) {
+		return dataPath
+	}
+	return filepath.Join(dir, dataPath)
+}
+
+// rowLabel names a row by its "name" or "id" column, or its number.
+func rowLabel(row map[string]string, number int) string {
+	for _, column := range []string{"name", "id"} {
+		if label := strings.TrimSpace(row[column]); label != "" {
+			return label
+		}
+	}
+	return fmt.Sprintf("row %d", number)
+}
+
+// LoadVariables reads rows of test data: a CSV file with a header row, or a
+// JSON array of objects (non-string values are rendered as JSON).
+func LoadVariables(path string) ([]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables_from: %w", err)
+	}
+
+	var rows []map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err = parseCSVRows(data)
+	case ".json":
+		rows, err = parseJSONRows(data)
+	default:
+		return nil, fmt.Errorf("variables_from %s: unsupported format (expected .csv or .json)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("variables_from %s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("variables_from %s: no rows", path)
+	}
+	return rows, nil
+}
+
+func parseCSVRows(data []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	for i, column := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if !identifierPattern.MatchString(header[i]) {
+			return nil, fmt.Errorf("column %d: '%s' is not a valid variable name", i+1, header[i])
+		}
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseJSONRows(data []byte) ([]map[string]string, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of objects: %w", err)
+	}
+
+	rows := make([]map[string]string, 0, len(objects))
+	for i, object := range objects {
+		row := make(map[string]string, len(object))
+		for key, raw := range object {
+			if !identifierPattern.MatchString(key) {
+				return nil, fmt.Errorf("row %d: '%s' is not a valid variable name", i+1, key)
+			}
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				row[key] = s
+			} else {
+				row[key] = string(raw)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// columns returns the variable names defined by any row, sorted.
+func columns(rows []map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, row := range rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,181 @@
+package scenario
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []map[string]string
+		wantErr string
+	}{
+		{
+			name:    "CSV",
+			file:    "rows.csv",
+			content: "\ufeffname, amount\nalice, 10\nbob,20\n",
+			want:    []map[string]string{{"name": "alice", "amount": "10"}, {"name": "bob", "amount": "20"}},
+		},
+		{
+			name:    "JSON",
+			file:    "rows.json",
+			content: `[{"id": "a1", "amount": 10, "tags": ["vip"]}]`,
+			want:    []map[string]string{{"id": "a1", "amount": "10", "tags": `["vip"]`}},
+		},
+		{
+			name:    "invalid CSV column",
+			file:    "rows.csv",
+			content: "name,first name\nalice,Alice\n",
+			wantErr: "column 2: 'first name' is not a valid variable name",
+		},
+		{
+			name:    "invalid JSON key",
+			file:    "rows.json",
+			content: `[{"first-name": "Alice"}]`,
+			wantErr: "row 1: 'first-name' is not a valid variable name",
+		},
+		{
+			name:    "JSON object",
+			file:    "rows.json",
+			content: `{"name": "alice"}`,
+			wantErr: "expected a JSON array of objects",
+		},
+		{
+			name:    "header only",
+			file:    "rows.csv",
+			content: "name,amount\n",
+			wantErr: "no rows",
+		},
+		{
+			name:    "empty JSON array",
+			file:    "rows.json",
+			content: `[]`,
+			wantErr: "no rows",
+		},
+		{
+			name:    "unsupported format",
+			file:    "rows.yaml",
+			content: "- name: alice\n",
+			wantErr: "unsupported format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScenarioFile(t, t.TempDir(), tt.file, tt.content)
+
+			got, err := LoadVariables(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadVariables() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadVariables() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadVariablesMissingFile(t *testing.T) {
+	if _, err := LoadVariables(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("LoadVariables() error = nil, want an error for a missing file")
+	}
+}
+
+func TestExpandCases(t *testing.T) {
+	dir := t.TempDir()
+	writeScenarioFile(t, dir, "customers.csv", "name,email\nalice,alice@example.com\n,anon@example.com\n")
+	plain := writeScenarioFile(t, dir, "plain.yaml", "name: Plain\n")
+	driven := writeScenarioFile(t, dir, "driven.yaml", "name: Driven\nvariables_from: customers.csv\n")
+
+	tests := []struct {
+		name string
+		path string
+		want []Case
+	}{
+		{
+			name: "not data-driven",
+			path: plain,
+			want: []Case{{Path: plain, Name: plain}},
+		},
+		{
+			name: "one case per row",
+			path: driven,
+			want: []Case{
+				{Path: driven, Name: driven + "[alice]", Row: 1, Variables: map[string]string{"name": "alice", "email": "alice@example.com"}},
+				{Path: driven, Name: driven + "[row 2]", Row: 2, Variables: map[string]string{"name": "", "email": "anon@example.com"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandCases(tt.path)
+			if err != nil {
+				t.Fatalf("ExpandCases() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandCases() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataFile(t *testing.T) {
+	dir := t.TempDir()
+	absolute := filepath.Join(t.TempDir(), "rows.json")
+
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "none", yaml: "name: Plain\n", want: ""},
+		{name: "relative", yaml: "variables_from: data/rows.csv\n", want: filepath.Join(dir, "data", "rows.csv")},
+		{name: "absolute", yaml: "variables_from: " + absolute + "\n", want: absolute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScenarioFile(t, dir, "scenario.yaml", tt.yaml)
+
+			got, err := DataFile(path)
+			if err != nil {
+				t.Fatalf("DataFile() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DataFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		row  map[string]string
+		want string
+	}{
+		{name: "name column", row: map[string]string{"name": "alice", "id": "c1"}, want: "alice"},
+		{name: "id column", row: map[string]string{"name": " ", "id": "c1"}, want: "c1"},
+		{name: "row number", row: map[string]string{"email": "alice@example.com"}, want: "row 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rowLabel(tt.row, 3); got != tt.want {
+				t.Errorf("rowLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package scenario
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+	"gopkg.in/yaml.v3"
+)
+
+// Recorded event types (see the engine's recording::EventType).
+const (
+	eventInputReceived  = "input_received"
+	eventCallMade       = "external_call_made"
+	eventCallCompleted  = "external_call_completed"
+	eventError          = "error_encountered"
+	eventOutputProduced = "output_produced"
+)
+
+// maxSubstrings caps response_contains expectations per step.
+const maxSubstrings = 3
+
+// substringPattern finds stable, distinctive output fragments: amounts,
+// numbers and quoted phrases.
+var substringPattern = regexp.MustCompile(`\$\d+(?:\.\d+)?|\b\d+(?:\.\d+)?\b|"[^"]{3,40}"`)
+
+// idSegmentPattern matches path segments shaped like resource IDs
+// (pi_3Mt..., 123, UUIDs); isIDSegment also requires a digit, so resource
+// names such as payment_intents are not mistaken for IDs.
+var idSegmentPattern = regexp.MustCompile(`^([a-z]+_[A-Za-z0-9]+|[0-9a-f-]+|\d+)$`)
+
+// GenerateOptions controls scenario generation from a recording.
+type GenerateOptions struct {
+	// Name of the generated scenario (default: derived from the run ID)
+	Name string
+
+	// CostHeadroom multiplies the recorded cost to get the cost ceiling
+	CostHeadroom float64
+}
+
+// GeneratedScenario is a draft scenario derived from a recorded run.
+type GeneratedScenario struct {
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	Version     string          `yaml:"version"`
+	Steps       []GeneratedStep `yaml:"steps"`
+}
+
+type GeneratedStep struct {
+	ID     string                   `yaml:"id"`
+	Action string                   `yaml:"action"`
+	Input  string                   `yaml:"input,omitempty"`
+	Expect []map[string]interface{} `yaml:"expect,omitempty"`
+}
+
+// turn is one agent request and everything it caused.
+type turn struct {
+	input   string
+	calls   []string
+	errors  int
+	output  string
+	costUSD float64
+}
+
+// GenerateFromRecording converts a recorded run into a draft scenario.
+// Every recorded input becomes an agent_request step expecting the calls it
+// made, its output substrings and success; a final verify_cost step caps the
+// total cost at the recorded cost times the headroom.
+func GenerateFromRecording(recording *grpc.Recording, opts GenerateOptions) (*GeneratedScenario, error) {
+	turns := splitTurns(recording.Events)
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("run %s has no recorded agent inputs", recording.ID)
+	}
+
+	if opts.Name == "" {
+		opts.Name = fmt.Sprintf("Generated from %s", recording.ID)
+	}
+	if opts.CostHeadroom <= 0 {
+		opts.CostHeadroom = 1.5
+	}
+
+	generated := &GeneratedScenario{
+		Name:        opts.Name,
+		Description: fmt.Sprintf("Draft generated from run %s (%s). Review expectations before committing.", recording.ID, recording.StartedAt.Format(time.RFC3339)),
+		Version:     "1.0",
+	}
+
+	var totalCost float64
+	for i, t := range turns {
+		totalCost += t.costUSD
+
+		var expect []map[string]interface{}
+		if len(t.calls) > 0 {
+			expect = append(expect, map[string]interface{}{"calls": t.calls})
+		}
+		for _, substring := range outputSubstrings(t.output) {
+			expect = append(expect, map[string]interface{}{"response_contains": substring})
+		}
+		if t.output != "" {
+			expect = append(expect, map[string]interface{}{"response_not_empty": true})
+		}
+		if t.errors == 0 {
+			expect = append(expect, map[string]interface{}{"status": "success"})
+		}
+
+		generated.Steps = append(generated.Steps, GeneratedStep{
+			ID:     fmt.Sprintf("request-%d", i+1),
+			Action: "agent_request",
+			Input:  t.input,
+			Expect: expect,
+		})
+	}
+
+	if totalCost > 0 {
+		generated.Steps = append(generated.Steps, GeneratedStep{
+			ID:     "verify-cost",
+			Action: "verify_cost",
+			Expect: []map[string]interface{}{
+				{"total_cost": fmt.Sprintf("<$%s", formatCeiling(totalCost*opts.CostHeadroom))},
+			},
+		})
+	}
+
+	return generated, nil
+}
+
+// Marshal renders the scenario as YAML with a header noting its origin.
+func (g *GeneratedScenario) Marshal(runID string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by `sentra lab scenarios generate --from-run %s`\n"+
+		"# Expectations are derived from a single run: tighten or loosen them before committing.\n\n", runID)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(g); err != nil {
+		return nil, fmt.Errorf("failed to marshal scenario: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal scenario: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func splitTurns(events []*grpc.Event) []*turn {
+	var turns []*turn
+	var current *turn
+
+	for _, event := range events {
+		if event.Type == eventInputReceived {
+			current = &turn{input: stringField(event.Data, "input", event.Summary)}
+			turns = append(turns, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch event.Type {
+		case eventCallMade:
+			name := callName(event.Service, stringField(event.Data, "method", "POST"), stringField(event.Data, "path", ""))
+			if name != "" && !containsString(current.calls, name) {
+				current.calls = append(current.calls, name)
+			}
+		case eventCallCompleted:
+			if cost, ok := event.Data["cost_usd"].(float64); ok {
+				current.costUSD += cost
+			}
+		case eventError:
+			current.errors++
+		case eventOutputProduced:
+			current.output = stringField(event.Data, "output", event.Summary)
+		}
+	}
+
+	return turns
+}
+
+// callName converts a mock request into the call names used by `calls:`
+// expectations, e.g. POST /v1/payment_intents/pi_123/confirm on stripe is
+// "stripe.payment_intents.confirm" and POST /v1/chat/completions on openai is
+// "openai.chat.completions".
+func callName(service, method, path string) string {
+	if service == "" || path == "" {
+		return ""
+	}
+
+	var segments []string
+	hasID := false
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case segment == "" || segment == "v1":
+		case isIDSegment(segment):
+			hasID = true
+		default:
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+
+	name := service + "." + strings.Join(segments, ".")
+
+	// OpenAI-style APIs are named by endpoint alone; REST resources get a verb
+	// unless the path already ends in an action (".../confirm")
+	if service == "openai" || (hasID && !isIDSegment(lastSegment(path))) {
+		return name
+	}
+
+	switch strings.ToUpper(method) {
+	case "POST":
+		if hasID {
+			return name + ".update"
+		}
+		return name + ".create"
+	case "GET":
+		if hasID {
+			return name + ".retrieve"
+		}
+		return name + ".list"
+	case "DELETE":
+		return name + ".delete"
+	}
+	return name
+}
+
+func outputSubstrings(output string) []string {
+	var substrings []string
+	for _, match := range substringPattern.FindAllString(output, -1) {
+		match = strings.Trim(match, `"`)
+		if !containsString(substrings, match) {
+			substrings = append(substrings, match)
+		}
+		if len(substrings) == maxSubstrings {
+			break
+		}
+	}
+	return substrings
+}
+
+// formatCeiling rounds a cost up to two significant digits (0.01234 -> 0.013).
+func formatCeiling(cost float64) string {
+	if cost <= 0 {
+		return "0.01"
+	}
+	scale := math.Pow(10, 1-math.Floor(math.Log10(cost)))
+	return strconv.FormatFloat(math.Ceil(cost*scale)/scale, 'f', -1, 64)
+}
+
+func isIDSegment(segment string) bool {
+	return idSegmentPattern.MatchString(segment) && strings.ContainsAny(segment, "0123456789")
+}
+
+func lastSegment(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	return parts[len(parts)-1]
+}
+
+func stringField(data map[string]interface{}, key, fallback string) string {
+	if value, ok := data[key].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func containsString(items []string, item string) bool {
+	for _, existing := range items {
+		if existing == item {
+			return true
+		}
+	}
+	return false
+}
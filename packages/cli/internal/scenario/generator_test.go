@@ -0,0 +1,166 @@
+package scenario
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCallName(t *testing.T) {
+	tests := []struct {
+		service string
+		method  string
+		path    string
+		want    string
+	}{
+		{service: "openai", method: "POST", path: "/v1/chat/completions", want: "openai.chat.completions"},
+		{service: "openai", method: "GET", path: "/v1/models", want: "openai.models"},
+		{service: "stripe", method: "POST", path: "/v1/payment_intents", want: "stripe.payment_intents.create"},
+		{service: "stripe", method: "GET", path: "/v1/payment_intents", want: "stripe.payment_intents.list"},
+		{service: "stripe", method: "GET", path: "/v1/payment_intents/pi_3Mt123", want: "stripe.payment_intents.retrieve"},
+		{service: "stripe", method: "POST", path: "/v1/customers/cus_9s6X", want: "stripe.customers.update"},
+		{service: "stripe", method: "DELETE", path: "/v1/customers/cus_9s6X", want: "stripe.customers.delete"},
+		{service: "stripe", method: "POST", path: "/v1/payment_intents/pi_123/confirm", want: "stripe.payment_intents.confirm"},
+		{service: "stripe", method: "PATCH", path: "/v1/customers", want: "stripe.customers"},
+		{service: "", method: "POST", path: "/v1/charges"},
+		{service: "stripe", method: "POST", path: "/v1/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			if got := callName(tt.service, tt.method, tt.path); got != tt.want {
+				t.Errorf("callName(%q, %q, %q) = %q, want %q", tt.service, tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputSubstrings(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "amounts and quotes", output: `Refunded $42.50 for order 1234 ("Blue Widget")`, want: []string{"$42.50", "1234", "Blue Widget"}},
+		{name: "capped", output: "1 2 3 4 5", want: []string{"1", "2", "3"}},
+		{name: "duplicates", output: "7 then 7 then 8", want: []string{"7", "8"}},
+		{name: "nothing distinctive", output: "Done."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outputSubstrings(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("outputSubstrings(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCeiling(t *testing.T) {
+	tests := []struct {
+		cost float64
+		want string
+	}{
+		{cost: 0.01234, want: "0.013"},
+		{cost: 0.12, want: "0.12"},
+		{cost: 0.3, want: "0.3"},
+		{cost: 1.5, want: "1.5"},
+		{cost: 1234, want: "1300"},
+		{cost: 0, want: "0.01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatCeiling(tt.cost); got != tt.want {
+				t.Errorf("formatCeiling(%v) = %q, want %q", tt.cost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFromRecording(t *testing.T) {
+	recording := &grpc.Recording{
+		ID:        "run_123",
+		StartedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Events: []*grpc.Event{
+			{Type: eventCallMade, Service: "stripe", Data: map[string]interface{}{"method": "GET", "path": "/v1/balance"}},
+			{Type: eventInputReceived, Data: map[string]interface{}{"input": "Refund order 1234"}},
+			{Type: eventCallMade, Service: "openai", Data: map[string]interface{}{"path": "/v1/chat/completions"}},
+			{Type: eventCallCompleted, Data: map[string]interface{}{"cost_usd": 0.004}},
+			{Type: eventCallMade, Service: "stripe", Data: map[string]interface{}{"method": "POST", "path": "/v1/refunds"}},
+			{Type: eventCallMade, Service: "openai", Data: map[string]interface{}{"path": "/v1/chat/completions"}},
+			{Type: eventCallCompleted, Data: map[string]interface{}{"cost_usd": 0.004}},
+			{Type: eventOutputProduced, Data: map[string]interface{}{"output": "Refunded $20 for order 1234"}},
+			{Type: eventInputReceived, Summary: "Check status"},
+			{Type: eventError},
+		},
+	}
+
+	generated, err := GenerateFromRecording(recording, GenerateOptions{})
+	if err != nil {
+		t.Fatalf("GenerateFromRecording() error = %v", err)
+	}
+
+	if generated.Name != "Generated from run_123" {
+		t.Errorf("Name = %q, want the default name", generated.Name)
+	}
+	want := []GeneratedStep{
+		{
+			ID:     "request-1",
+			Action: "agent_request",
+			Input:  "Refund order 1234",
+			Expect: []map[string]interface{}{
+				{"calls": []string{"openai.chat.completions", "stripe.refunds.create"}},
+				{"response_contains": "$20"},
+				{"response_contains": "1234"},
+				{"response_not_empty": true},
+				{"status": "success"},
+			},
+		},
+		{ID: "request-2", Action: "agent_request", Input: "Check status"},
+		{
+			ID:     "verify-cost",
+			Action: "verify_cost",
+			Expect: []map[string]interface{}{{"total_cost": "<$0.012"}},
+		},
+	}
+	if !reflect.DeepEqual(generated.Steps, want) {
+		t.Errorf("Steps = %+v, want %+v", generated.Steps, want)
+	}
+}
+
+func TestGenerateFromRecordingWithoutInputs(t *testing.T) {
+	recording := &grpc.Recording{ID: "run_empty", Events: []*grpc.Event{{Type: eventError}}}
+
+	if _, err := GenerateFromRecording(recording, GenerateOptions{}); err == nil || !strings.Contains(err.Error(), "no recorded agent inputs") {
+		t.Errorf("GenerateFromRecording() error = %v, want no recorded agent inputs", err)
+	}
+}
+
+func TestGeneratedScenarioMarshal(t *testing.T) {
+	generated := &GeneratedScenario{
+		Name:    "Refunds",
+		Version: "1.0",
+		Steps:   []GeneratedStep{{ID: "request-1", Action: "agent_request", Input: "Refund order 1234"}},
+	}
+
+	data, err := generated.Marshal("run_123")
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Generated by `sentra lab scenarios generate --from-run run_123`\n") {
+		t.Errorf("Marshal() = %q, want the origin header", data)
+	}
+
+	var parsed GeneratedScenario
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Marshal() output does not parse: %v", err)
+	}
+	if !reflect.DeepEqual(&parsed, generated) {
+		t.Errorf("round trip = %+v, want %+v", parsed, generated)
+	}
+}
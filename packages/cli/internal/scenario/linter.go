@@ -0,0 +1,685 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/fake"
+	"gopkg.in/yaml.v3"
+)
+
+// KnownActions lists the step actions the engine executes.
+var KnownActions = []string{
+	"verify_agent_ready",
+	"agent_request",
+	"assert",
+	"verify_cost",
+	"inject_error",
+	"mock_response",
+	"verify_webhook",
+	"verify_reconciliation",
+	"inject_latency",
+	"clear_faults",
+	"network_faults",
+	"advance_clock",
+	"schedule",
+	"assert_guardrails",
+	"assert_routing",
+	"set_locale",
+}
+
+// requiredFields are the fields each action cannot run without.
+var requiredFields = map[string][]string{
+	"agent_request":  {"input"},
+	"inject_error":   {"service", "error"},
+	"mock_response":  {"service", "endpoint", "response"},
+	"verify_webhook": {"service", "event_type"},
+	"inject_latency": {"service"},
+	"schedule":       {"at", "step"},
+	"assert_routing": {"primary", "fallback"},
+}
+
+// expectationFields are where an action's checks live; a step without them
+// runs but verifies nothing.
+var expectationFields = map[string]string{
+	"agent_request": "expect",
+	"verify_cost":   "expect",
+	"assert":        "conditions",
+}
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)[^}]*\}\}`)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var comparisonPattern = regexp.MustCompile(`^(<=|>=|==|<|>)?\s*(.*)$`)
+
+// percentilePattern matches latency percentile expectations (p95_latency).
+var percentilePattern = regexp.MustCompile(`^p(\d+)_latency$`)
+
+// maxRepeat caps how many times a step may repeat its request.
+const maxRepeat = 1000
+
+// Issue is a problem found in a scenario file.
+type Issue struct {
+	Field    string
+	Message  string
+	Severity string
+}
+
+func (i Issue) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Field, i.Message)
+}
+
+func (i Issue) IsError() bool {
+	return i.Severity == "error"
+}
+
+// LintResult holds the issues found in one scenario file.
+type LintResult struct {
+	Path     string
+	Errors   []Issue
+	Warnings []Issue
+}
+
+// Passed reports whether the file passes; in strict mode warnings fail too.
+func (r *LintResult) Passed(strict bool) bool {
+	if len(r.Errors) > 0 {
+		return false
+	}
+	return !strict || len(r.Warnings) == 0
+}
+
+// Linter checks scenario YAML for mistakes that would otherwise only show up
+// when the scenario runs.
+type Linter struct {
+	actions map[string]bool
+}
+
+func NewLinter() *Linter {
+	actions := make(map[string]bool, len(KnownActions))
+	for _, action := range KnownActions {
+		actions[action] = true
+	}
+	return &Linter{actions: actions}
+}
+
+func (l *Linter) LintFile(path string) (*LintResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	result := l.lint(data, filepath.Dir(path))
+	result.Path = path
+	return result, nil
+}
+
+// Lint checks scenario YAML. A variables_from file is resolved relative to
+// the working directory; use LintFile to resolve it next to the scenario.
+func (l *Linter) Lint(data []byte) *LintResult {
+	return l.lint(data, ".")
+}
+
+func (l *Linter) lint(data []byte, dir string) *LintResult {
+	result := &LintResult{}
+	lint := &lintRun{linter: l, result: result}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		lint.errorf("", "invalid YAML: %v", err)
+		return result
+	}
+	if doc == nil {
+		lint.errorf("", "scenario is empty")
+		return result
+	}
+
+	if name, _ := doc["name"].(string); name == "" {
+		lint.warnf("name", "name is empty")
+	}
+
+	variables, _ := doc["variables"].(map[string]interface{})
+	if raw, ok := doc["variables_from"]; ok {
+		variables = lint.variablesFrom(raw, dir, variables)
+	}
+	steps, hasSteps := doc["steps"].([]interface{})
+	_, isFuzz := doc["fuzz"]
+
+	if !hasSteps || len(steps) == 0 {
+		if !isFuzz {
+			lint.errorf("steps", "no steps defined")
+		}
+	}
+
+	ids := make(map[string]int)
+	for i, raw := range steps {
+		field := fmt.Sprintf("steps[%d]", i)
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			lint.errorf(field, "step must be a mapping")
+			continue
+		}
+
+		if id, _ := step["id"].(string); id != "" {
+			if prev, exists := ids[id]; exists {
+				lint.errorf(field+".id", "duplicate ID '%s' (also at steps[%d])", id, prev)
+			} else {
+				ids[id] = i
+			}
+		}
+
+		lint.step(field, step)
+		if action, _ := step["action"].(string); action == "assert_routing" {
+			lint.routedStep(field, i, step, ids)
+		}
+	}
+
+	provided := lint.dependencies(doc, dir)
+	lint.requirements(doc)
+
+	lint.unreachableSchedules(steps)
+	lint.variables(variables, provided, steps)
+
+	return result
+}
+
+// lintRun accumulates issues for one file.
+type lintRun struct {
+	linter *Linter
+	result *LintResult
+}
+
+func (r *lintRun) errorf(field, format string, args ...interface{}) {
+	r.result.Errors = append(r.result.Errors, Issue{Field: field, Message: fmt.Sprintf(format, args...), Severity: "error"})
+}
+
+func (r *lintRun) warnf(field, format string, args ...interface{}) {
+	r.result.Warnings = append(r.result.Warnings, Issue{Field: field, Message: fmt.Sprintf(format, args...), Severity: "warning"})
+}
+
+func (r *lintRun) step(field string, step map[string]interface{}) {
+	action, _ := step["action"].(string)
+	if action == "" {
+		r.errorf(field+".action", "action is required")
+		return
+	}
+	if !r.linter.actions[action] {
+		hint := ""
+		if suggestion := closestAction(action); suggestion != "" {
+			hint = fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+		r.errorf(field+".action", "unknown action '%s'%s", action, hint)
+		return
+	}
+
+	for _, required := range requiredFields[action] {
+		if _, ok := step[required]; !ok {
+			r.errorf(field+"."+required, "%s requires %s", action, required)
+		}
+	}
+
+	r.repeat(field, action, step)
+
+	switch action {
+	case "inject_latency":
+		_, hasLatency := step["latency"]
+		_, hasMultiplier := step["multiplier"]
+		if !hasLatency && !hasMultiplier {
+			r.errorf(field, "inject_latency requires latency or multiplier")
+		}
+	case "advance_clock":
+		_, hasDuration := step["duration"]
+		_, hasBy := step["by"]
+		if !hasDuration && !hasBy {
+			r.errorf(field+".duration", "advance_clock requires duration")
+		}
+	case "set_locale":
+		r.locale(field, step)
+	case "assert_routing":
+		if primary, _ := step["primary"].(string); primary != "" && primary == step["fallback"] {
+			r.errorf(field+".fallback", "fallback must differ from primary '%s'", primary)
+		}
+	case "schedule":
+		if nested, ok := step["step"].(map[string]interface{}); ok {
+			r.step(field+".step", nested)
+		} else if _, ok := step["step"]; ok {
+			r.errorf(field+".step", "step must be a mapping")
+		}
+	}
+
+	if expectField, ok := expectationFields[action]; ok {
+		if isEmpty(step[expectField]) {
+			r.warnf(field, "%s has no %s; it verifies nothing", action, expectField)
+		}
+	}
+
+	for _, key := range sortedKeys(step) {
+		// Nested steps are linted above; mocked response bodies are free-form
+		if key == "step" || key == "response" {
+			continue
+		}
+		r.expressions(field+"."+key, key, step[key])
+	}
+}
+
+// repeat checks repeat and percentile latency expectations: percentiles
+// need repeated requests, and enough of them that the percentile is not
+// simply the slowest one.
+func (r *lintRun) repeat(field, action string, step map[string]interface{}) {
+	raw, hasRepeat := step["repeat"]
+	if hasRepeat && action != "agent_request" {
+		r.errorf(field+".repeat", "repeat is only supported by agent_request")
+		return
+	}
+
+	repeat, _ := raw.(int)
+	if hasRepeat && (repeat < 2 || repeat > maxRepeat) {
+		r.errorf(field+".repeat", "repeat must be an integer between 2 and %d", maxRepeat)
+		return
+	}
+
+	var keys []string
+	collectKeys(step["expect"], func(key string) {
+		if percentilePattern.MatchString(key) {
+			keys = append(keys, key)
+		}
+	})
+
+	for _, key := range keys {
+		percentile, _ := strconv.Atoi(percentilePattern.FindStringSubmatch(key)[1])
+		switch {
+		case percentile < 1 || percentile > 99:
+			r.errorf(field+".expect."+key, "percentile must be between 1 and 99")
+		case !hasRepeat:
+			r.errorf(field+".expect."+key, "%s needs repeat (e.g. repeat: 50); one sample has no percentiles", key)
+		case repeat*(100-percentile) < 100:
+			r.warnf(field+".expect."+key, "%s of %d samples is the slowest sample; use repeat: %d or more",
+				key, repeat, (100+99-percentile)/(100-percentile))
+		}
+	}
+}
+
+// collectKeys calls fn with every mapping key in value, descending into lists.
+func collectKeys(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			collectKeys(item, fn)
+		}
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			fn(key)
+			collectKeys(v[key], fn)
+		}
+	}
+}
+
+// locale checks a set_locale step: it must change at least one setting, and
+// a literal timezone must exist.
+func (r *lintRun) locale(field string, step map[string]interface{}) {
+	_, hasLocale := step["locale"]
+	_, hasTimezone := step["timezone"]
+	_, hasCurrency := step["currency"]
+	if !hasLocale && !hasTimezone && !hasCurrency {
+		r.errorf(field, "set_locale requires locale, timezone or currency")
+	}
+
+	if timezone, ok := step["timezone"].(string); ok && !variablePattern.MatchString(timezone) {
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+			r.errorf(field+".timezone", "unknown timezone '%s' (expected e.g. Europe/Berlin)", timezone)
+		}
+	}
+}
+
+// routedStep checks that an assert_routing step names an earlier step; its
+// checks run against the model calls that step made.
+func (r *lintRun) routedStep(field string, index int, step map[string]interface{}, ids map[string]int) {
+	raw, ok := step["step"]
+	if !ok {
+		return
+	}
+	target, ok := raw.(string)
+	if !ok {
+		r.errorf(field+".step", "step must be the ID of an earlier step")
+		return
+	}
+	if i, exists := ids[target]; !exists || i >= index {
+		r.errorf(field+".step", "assert_routing checks step '%s', which is not an earlier step", target)
+	}
+}
+
+// expressions checks duration, cost and backoff strategy values, descending
+// into expectations.
+func (r *lintRun) expressions(field, key string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			r.expressions(field+"."+k, k, v[k])
+		}
+	case []interface{}:
+		for i, nested := range v {
+			r.expressions(fmt.Sprintf("%s[%d]", field, i), key, nested)
+		}
+	case string:
+		switch {
+		case key == "backoff_strategy":
+			if v != "exponential" {
+				r.errorf(field, "unknown backoff_strategy '%s' (supported: exponential)", v)
+			}
+		case isCostKey(key):
+			if !validCost(v) {
+				r.errorf(field, "invalid cost expression '%s' (expected e.g. <$0.10)", v)
+			}
+		case isDurationKey(key):
+			if !validDuration(v) {
+				r.errorf(field, "invalid duration expression '%s' (expected e.g. <10s, 500ms, 25h)", v)
+			}
+		}
+	}
+}
+
+// unreachableSchedules flags scheduled steps later than the scenario ever
+// advances the virtual clock; they would only run after that much real time.
+func (r *lintRun) unreachableSchedules(steps []interface{}) {
+	var advanced time.Duration
+	for _, raw := range steps {
+		step, _ := raw.(map[string]interface{})
+		if action, _ := step["action"].(string); action != "advance_clock" {
+			continue
+		}
+		by, _ := step["duration"].(string)
+		if by == "" {
+			by, _ = step["by"].(string)
+		}
+		if d, ok := parseDuration(by); ok {
+			advanced += d
+		}
+	}
+
+	for i, raw := range steps {
+		step, _ := raw.(map[string]interface{})
+		if action, _ := step["action"].(string); action != "schedule" {
+			continue
+		}
+		at, _ := step["at"].(string)
+		d, ok := parseDuration(at)
+		if !ok || d <= advanced || d < time.Minute {
+			continue
+		}
+		r.warnf(fmt.Sprintf("steps[%d]", i),
+			"scheduled step is unreachable: runs at %s but advance_clock only reaches %s", at, advanced)
+	}
+}
+
+// variablesFrom checks the variables_from data file and returns the
+// declared variables with its columns added.
+func (r *lintRun) variablesFrom(raw interface{}, dir string, declared map[string]interface{}) map[string]interface{} {
+	path, _ := raw.(string)
+	if path == "" {
+		r.errorf("variables_from", "variables_from must be a path to a CSV or JSON file")
+		return declared
+	}
+
+	rows, err := LoadVariables(resolveDataPath(dir, path))
+	if err != nil {
+		r.errorf("variables_from", "%v", err)
+		return declared
+	}
+
+	merged := make(map[string]interface{}, len(declared))
+	for name, value := range declared {
+		merged[name] = value
+	}
+	for _, column := range columns(rows) {
+		merged[column] = nil
+	}
+	return merged
+}
+
+// dependencies checks depends_on and outputs, and returns the output names
+// the prerequisites hand to this scenario.
+func (r *lintRun) dependencies(doc map[string]interface{}, dir string) map[string]bool {
+	provided := make(map[string]bool)
+
+	if raw, ok := doc["outputs"]; ok {
+		outputs, ok := raw.(map[string]interface{})
+		if !ok {
+			r.errorf("outputs", "outputs must be a mapping of names to expressions")
+		}
+		for _, name := range sortedKeys(outputs) {
+			if !identifierPattern.MatchString(name) {
+				r.errorf("outputs."+name, "'%s' is not a valid variable name", name)
+			}
+			if _, ok := outputs[name].(string); !ok {
+				r.errorf("outputs."+name, "output must be a string expression (e.g. \"{{steps.create.response.id}}\")")
+			}
+		}
+	}
+
+	raw, ok := doc["depends_on"]
+	if !ok {
+		return provided
+	}
+	dependsOn, ok := raw.([]interface{})
+	if !ok {
+		r.errorf("depends_on", "depends_on must be a list of scenario files")
+		return provided
+	}
+
+	for i, item := range dependsOn {
+		field := fmt.Sprintf("depends_on[%d]", i)
+		path, _ := item.(string)
+		if path == "" {
+			r.errorf(field, "must be a path to a scenario file")
+			continue
+		}
+
+		deps, err := LoadDependencies(resolveDataPath(dir, path))
+		if err != nil {
+			r.errorf(field, "%v", err)
+			continue
+		}
+		for name := range deps.Outputs {
+			provided[name] = true
+		}
+	}
+
+	return provided
+}
+
+// requirements checks that requires maps mock names to lists of
+// capabilities.
+func (r *lintRun) requirements(doc map[string]interface{}) {
+	raw, ok := doc["requires"]
+	if !ok {
+		return
+	}
+	requires, ok := raw.(map[string]interface{})
+	if !ok {
+		r.errorf("requires", "requires must map mock names to capabilities (e.g. openai: [assistants])")
+		return
+	}
+
+	for _, mock := range sortedKeys(requires) {
+		field := "requires." + mock
+		capabilities, ok := requires[mock].([]interface{})
+		if !ok {
+			r.errorf(field, "must be a list of capabilities")
+			continue
+		}
+		for i, capability := range capabilities {
+			if name, _ := capability.(string); name == "" {
+				r.errorf(fmt.Sprintf("%s[%d]", field, i), "capability must be a non-empty string")
+			}
+		}
+	}
+}
+
+// variables reports undefined references and declared-but-unused variables.
+// Outputs provided by prerequisites may be referenced without declaring them;
+// {{ fake.* }} references must name a fake data generator.
+func (r *lintRun) variables(declared map[string]interface{}, provided map[string]bool, steps []interface{}) {
+	used := make(map[string]bool)
+	var undefined []string
+
+	collectStrings(steps, func(s string) {
+		for _, name := range fake.References(s) {
+			if _, ok := fake.Generators[name]; !ok && !used[fake.Namespace+"."+name] {
+				r.errorf("steps", "unknown fake data 'fake.%s' (available: %s)", name, strings.Join(fake.Names(), ", "))
+			}
+			used[fake.Namespace+"."+name] = true
+		}
+
+		for _, match := range variablePattern.FindAllStringSubmatch(s, -1) {
+			name := match[1]
+			if name == fake.Namespace {
+				continue
+			}
+			if _, ok := declared[name]; !ok && !provided[name] && !used[name] {
+				undefined = append(undefined, name)
+			}
+			used[name] = true
+		}
+	})
+
+	for _, name := range undefined {
+		r.errorf("steps", "variable '%s' is referenced but not declared", name)
+	}
+
+	var unused []string
+	for name := range declared {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		r.warnf("variables."+name, "variable is never referenced")
+	}
+}
+
+func collectStrings(value interface{}, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case []interface{}:
+		for _, item := range v {
+			collectStrings(item, fn)
+		}
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			collectStrings(v[key], fn)
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func isCostKey(key string) bool {
+	return strings.Contains(key, "cost")
+}
+
+func isDurationKey(key string) bool {
+	switch key {
+	case "timeout", "duration", "latency", "at", "by", "switched_within", "latency_slo":
+		return true
+	}
+	return strings.HasSuffix(key, "_time") || strings.HasSuffix(key, "_timeout") || strings.HasSuffix(key, "_latency")
+}
+
+func validCost(expr string) bool {
+	value := comparisonPattern.FindStringSubmatch(strings.TrimSpace(expr))[2]
+	value = strings.TrimPrefix(strings.TrimSpace(value), "$")
+	amount, err := strconv.ParseFloat(value, 64)
+	return err == nil && amount >= 0
+}
+
+func validDuration(expr string) bool {
+	value := comparisonPattern.FindStringSubmatch(strings.TrimSpace(expr))[2]
+	_, ok := parseDuration(value)
+	return ok
+}
+
+// parseDuration accepts the formats the engine accepts: a number with an
+// ms, s, m or h suffix, or a bare number of seconds.
+func parseDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"ms", time.Millisecond},
+		{"s", time.Second},
+		{"m", time.Minute},
+		{"h", time.Hour},
+		{"", time.Second},
+	}
+
+	for _, u := range units {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), 64)
+		if err != nil || number < 0 {
+			return 0, false
+		}
+		return time.Duration(number * float64(u.unit)), true
+	}
+	return 0, false
+}
+
+// closestAction suggests a known action within a small edit distance.
+func closestAction(action string) string {
+	best, bestDistance := "", 3
+	for _, known := range KnownActions {
+		if d := editDistance(action, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,533 @@
+package scenario
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScenarioFile writes a file into dir and returns its path.
+func writeScenarioFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// issueFields returns the fields of issues.
+func issueFields(issues []Issue) []string {
+	var fields []string
+	for _, issue := range issues {
+		fields = append(fields, issue.Field)
+	}
+	return fields
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name         string
+		yaml         string
+		wantErrors   []string
+		wantWarnings []string
+		wantMessage  string
+	}{
+		{
+			name: "valid",
+			yaml: `
+name: Refund
+steps:
+  - action: agent_request
+    input: Refund my order
+    expect:
+      - response_contains: refund
+`,
+		},
+		{name: "invalid YAML", yaml: "steps: [", wantErrors: []string{""}},
+		{name: "empty", yaml: "", wantErrors: []string{""}, wantMessage: "scenario is empty"},
+		{name: "no steps", yaml: "name: Empty\n", wantErrors: []string{"steps"}},
+		{name: "fuzz scenario without steps", yaml: "name: Fuzz\nfuzz:\n  inputs: 10\n"},
+		{
+			name: "missing name",
+			yaml: `
+steps:
+  - action: verify_agent_ready
+`,
+			wantWarnings: []string{"name"},
+		},
+		{
+			name: "unknown action",
+			yaml: `
+name: Typo
+steps:
+  - action: agent_requst
+`,
+			wantErrors:  []string{"steps[0].action"},
+			wantMessage: "did you mean 'agent_request'?",
+		},
+		{
+			name: "missing required field",
+			yaml: `
+name: Inject
+steps:
+  - action: inject_error
+    service: openai
+`,
+			wantErrors: []string{"steps[0].error"},
+		},
+		{
+			name: "duplicate step IDs",
+			yaml: `
+name: Duplicates
+steps:
+  - id: ready
+    action: verify_agent_ready
+  - id: ready
+    action: verify_agent_ready
+`,
+			wantErrors: []string{"steps[1].id"},
+		},
+		{
+			name: "step without expectations",
+			yaml: `
+name: Unchecked
+steps:
+  - action: agent_request
+    input: Hello
+`,
+			wantWarnings: []string{"steps[0]"},
+		},
+		{
+			name: "repeat on another action",
+			yaml: `
+name: Repeat
+steps:
+  - action: verify_cost
+    repeat: 5
+    expect:
+      total_cost: "<$1"
+`,
+			wantErrors: []string{"steps[0].repeat"},
+		},
+		{
+			name: "repeat out of range",
+			yaml: `
+name: Repeat
+steps:
+  - action: agent_request
+    input: Hello
+    repeat: 1
+    expect:
+      status: success
+`,
+			wantErrors: []string{"steps[0].repeat"},
+		},
+		{
+			name: "percentile without repeat",
+			yaml: `
+name: Latency
+steps:
+  - action: agent_request
+    input: Hello
+    expect:
+      p95_latency: "<2s"
+`,
+			wantErrors: []string{"steps[0].expect.p95_latency"},
+		},
+		{
+			name: "percentile of too few samples",
+			yaml: `
+name: Latency
+steps:
+  - action: agent_request
+    input: Hello
+    repeat: 10
+    expect:
+      p99_latency: "<2s"
+`,
+			wantWarnings: []string{"steps[0].expect.p99_latency"},
+			wantMessage:  "use repeat: 100 or more",
+		},
+		{
+			name: "invalid cost",
+			yaml: `
+name: Cost
+steps:
+  - action: verify_cost
+    expect:
+      total_cost: cheap
+`,
+			wantErrors: []string{"steps[0].expect.total_cost"},
+		},
+		{
+			name: "invalid duration",
+			yaml: `
+name: Latency
+steps:
+  - action: inject_latency
+    service: openai
+    latency: fast
+`,
+			wantErrors: []string{"steps[0].latency"},
+		},
+		{
+			name: "latency without an amount",
+			yaml: `
+name: Latency
+steps:
+  - action: inject_latency
+    service: openai
+`,
+			wantErrors: []string{"steps[0]"},
+		},
+		{
+			name: "unknown backoff strategy",
+			yaml: `
+name: Backoff
+steps:
+  - action: agent_request
+    input: Hello
+    expect:
+      backoff_strategy: linear
+`,
+			wantErrors: []string{"steps[0].expect.backoff_strategy"},
+		},
+		{
+			name: "advance_clock without a duration",
+			yaml: `
+name: Clock
+steps:
+  - action: advance_clock
+`,
+			wantErrors: []string{"steps[0].duration"},
+		},
+		{
+			name: "set_locale",
+			yaml: `
+name: Locale
+steps:
+  - action: set_locale
+    timezone: "{{ tz }}"
+variables:
+  tz: Europe/Berlin
+`,
+		},
+		{
+			name: "set_locale without settings",
+			yaml: `
+name: Locale
+steps:
+  - action: set_locale
+`,
+			wantErrors: []string{"steps[0]"},
+		},
+		{
+			name: "set_locale with an unknown timezone",
+			yaml: `
+name: Locale
+steps:
+  - action: set_locale
+    timezone: Mars/Olympus
+`,
+			wantErrors: []string{"steps[0].timezone"},
+		},
+		{
+			name: "assert_routing",
+			yaml: `
+name: Failover
+steps:
+  - id: ask
+    action: agent_request
+    input: Hello
+    expect:
+      status: success
+  - action: assert_routing
+    step: ask
+    primary: openai
+    fallback: anthropic
+`,
+		},
+		{
+			name: "assert_routing of a later step",
+			yaml: `
+name: Failover
+steps:
+  - action: assert_routing
+    step: ask
+    primary: openai
+    fallback: openai
+  - id: ask
+    action: agent_request
+    input: Hello
+    expect:
+      status: success
+`,
+			wantErrors: []string{"steps[0].fallback", "steps[0].step"},
+		},
+		{
+			name: "unreachable schedule",
+			yaml: `
+name: Schedule
+steps:
+  - action: advance_clock
+    duration: 5m
+  - action: schedule
+    at: 10m
+    step:
+      action: clear_faults
+`,
+			wantWarnings: []string{"steps[1]"},
+		},
+		{
+			name: "invalid scheduled step",
+			yaml: `
+name: Schedule
+steps:
+  - action: schedule
+    at: 30s
+    step:
+      action: clear_fault
+`,
+			wantErrors: []string{"steps[0].step.action"},
+		},
+		{
+			name: "undeclared and unused variables",
+			yaml: `
+name: Variables
+variables:
+  unused: 1
+steps:
+  - action: agent_request
+    input: "Hello {{ user }}"
+    expect:
+      status: success
+`,
+			wantErrors:   []string{"steps"},
+			wantWarnings: []string{"variables.unused"},
+		},
+		{
+			name: "fake data",
+			yaml: `
+name: Fake
+steps:
+  - action: agent_request
+    input: "I am {{ fake.Name }}, {{ fake.Email }}"
+    expect:
+      status: success
+`,
+		},
+		{
+			name: "unknown fake data",
+			yaml: `
+name: Fake
+steps:
+  - action: agent_request
+    input: "I am {{ fake.Nickname }}"
+    expect:
+      status: success
+`,
+			wantErrors:  []string{"steps"},
+			wantMessage: "unknown fake data 'fake.Nickname'",
+		},
+		{
+			name: "invalid outputs",
+			yaml: `
+name: Outputs
+outputs:
+  customer-id: 42
+steps:
+  - action: verify_agent_ready
+`,
+			wantErrors: []string{"outputs.customer-id", "outputs.customer-id"},
+		},
+		{
+			name: "invalid requires",
+			yaml: `
+name: Requires
+requires:
+  openai: assistants
+  stripe: [""]
+steps:
+  - action: verify_agent_ready
+`,
+			wantErrors: []string{"requires.openai", "requires.stripe[0]"},
+		},
+	}
+
+	linter := NewLinter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := linter.Lint([]byte(tt.yaml))
+
+			if got := issueFields(result.Errors); !slices.Equal(got, tt.wantErrors) {
+				t.Errorf("errors = %v, want fields %q", result.Errors, tt.wantErrors)
+			}
+			if got := issueFields(result.Warnings); !slices.Equal(got, tt.wantWarnings) {
+				t.Errorf("warnings = %v, want fields %q", result.Warnings, tt.wantWarnings)
+			}
+			if tt.wantMessage != "" {
+				var messages []string
+				for _, issue := range append(result.Errors, result.Warnings...) {
+					messages = append(messages, issue.Message)
+				}
+				if !strings.Contains(strings.Join(messages, "\n"), tt.wantMessage) {
+					t.Errorf("issues = %q, want one containing %q", messages, tt.wantMessage)
+				}
+			}
+		})
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	dir := t.TempDir()
+	writeScenarioFile(t, dir, "customers.csv", "name,email\nalice,alice@example.com\n")
+	writeScenarioFile(t, dir, "setup.yaml", `
+name: Setup
+outputs:
+  customer_id: "{{steps.create.response.id}}"
+steps:
+  - action: verify_agent_ready
+`)
+
+	tests := []struct {
+		name       string
+		yaml       string
+		wantErrors []string
+	}{
+		{
+			name: "variables_from columns",
+			yaml: `
+name: Data
+variables_from: customers.csv
+steps:
+  - action: agent_request
+    input: "I am {{ name }} ({{ email }})"
+    expect:
+      status: success
+`,
+		},
+		{
+			name: "missing variables_from file",
+			yaml: `
+name: Data
+variables_from: missing.csv
+steps:
+  - action: verify_agent_ready
+`,
+			wantErrors: []string{"variables_from"},
+		},
+		{
+			name: "prerequisite outputs",
+			yaml: `
+name: Refund
+depends_on: [setup.yaml]
+steps:
+  - action: agent_request
+    input: "Refund {{ customer_id }}"
+    expect:
+      status: success
+`,
+		},
+		{
+			name: "missing prerequisite",
+			yaml: `
+name: Refund
+depends_on: [missing.yaml]
+steps:
+  - action: verify_agent_ready
+`,
+			wantErrors: []string{"depends_on[0]"},
+		},
+	}
+
+	linter := NewLinter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScenarioFile(t, dir, "scenario.yaml", tt.yaml)
+
+			result, err := linter.LintFile(path)
+			if err != nil {
+				t.Fatalf("LintFile() error = %v", err)
+			}
+			if result.Path != path {
+				t.Errorf("Path = %q, want %q", result.Path, path)
+			}
+			if got := issueFields(result.Errors); !slices.Equal(got, tt.wantErrors) {
+				t.Errorf("errors = %v, want fields %q", result.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestLintResultPassed(t *testing.T) {
+	warning := Issue{Field: "name", Message: "name is empty", Severity: "warning"}
+	failure := Issue{Field: "steps", Message: "no steps defined", Severity: "error"}
+
+	tests := []struct {
+		name   string
+		result LintResult
+		strict bool
+		want   bool
+	}{
+		{name: "clean", result: LintResult{}, want: true},
+		{name: "warnings", result: LintResult{Warnings: []Issue{warning}}, want: true},
+		{name: "warnings in strict mode", result: LintResult{Warnings: []Issue{warning}}, strict: true, want: false},
+		{name: "errors", result: LintResult{Errors: []Issue{failure}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Passed(tt.strict); got != tt.want {
+				t.Errorf("Passed(%v) = %v, want %v", tt.strict, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		expr   string
+		want   time.Duration
+		wantOK bool
+	}{
+		{expr: "500ms", want: 500 * time.Millisecond, wantOK: true},
+		{expr: "10s", want: 10 * time.Second, wantOK: true},
+		{expr: "1.5m", want: 90 * time.Second, wantOK: true},
+		{expr: "25h", want: 25 * time.Hour, wantOK: true},
+		{expr: "30", want: 30 * time.Second, wantOK: true},
+		{expr: "-1s"},
+		{expr: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, ok := parseDuration(tt.expr)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseDuration(%q) = %v, %v, want %v, %v", tt.expr, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestClosestAction(t *testing.T) {
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{action: "agent_requests", want: "agent_request"},
+		{action: "set_local", want: "set_locale"},
+		{action: "launch_rocket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			if got := closestAction(tt.action); got != tt.want {
+				t.Errorf("closestAction(%q) = %q, want %q", tt.action, got, tt.want)
+			}
+		})
+	}
+}
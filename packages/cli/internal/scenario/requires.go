@@ -0,0 +1,26 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadRequirements reads a scenario's requires: the capabilities it needs
+// from each mock (e.g. openai: [assistants]), by mock name. It returns nil
+// if the scenario requires none.
+func LoadRequirements(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var doc struct {
+		Requires map[string][]string `yaml:"requires"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	return doc.Requires, nil
+}
@@ -0,0 +1,35 @@
+package scenario
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadRequirements(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want map[string][]string
+	}{
+		{name: "none", yaml: "name: Plain\n"},
+		{
+			name: "by mock",
+			yaml: "requires:\n  openai: [assistants, realtime]\n  stripe: [webhooks]\n",
+			want: map[string][]string{"openai": {"assistants", "realtime"}, "stripe": {"webhooks"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScenarioFile(t, t.TempDir(), "scenario.yaml", tt.yaml)
+
+			got, err := LoadRequirements(path)
+			if err != nil {
+				t.Fatalf("LoadRequirements() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadRequirements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
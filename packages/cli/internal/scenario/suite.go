@@ -0,0 +1,128 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dependencies is how a scenario fits into a suite: the scenarios that must
+// pass before it runs, and the named outputs it hands to its dependents.
+type Dependencies struct {
+	// DependsOn are the prerequisite scenario files, resolved relative to
+	// the scenario's directory
+	DependsOn []string
+
+	// Outputs map output names to expressions the engine evaluates when the
+	// scenario finishes (e.g. customer_id: "{{steps.create.response.id}}").
+	// Dependents receive them as variables.
+	Outputs map[string]string
+}
+
+// LoadDependencies reads a scenario's depends_on and outputs.
+func LoadDependencies(path string) (*Dependencies, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var doc struct {
+		DependsOn []string          `yaml:"depends_on"`
+		Outputs   map[string]string `yaml:"outputs"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+
+	deps := &Dependencies{Outputs: doc.Outputs}
+	for _, dependency := range doc.DependsOn {
+		deps.DependsOn = append(deps.DependsOn, resolveDataPath(filepath.Dir(path), dependency))
+	}
+	return deps, nil
+}
+
+// Suite is a set of scenario files in dependency order.
+type Suite struct {
+	// Order lists every scenario after its prerequisites. Scenarios without
+	// dependencies keep the order they were given in.
+	Order []string
+
+	// DependsOn maps each scenario to its direct prerequisites
+	DependsOn map[string][]string
+}
+
+// OrderScenarios orders scenario files by depends_on. Prerequisites that
+// were not given are added, so running a dependent alone runs what it needs
+// first. A dependency cycle is an error.
+func OrderScenarios(paths []string) (*Suite, error) {
+	suite := &Suite{DependsOn: make(map[string][]string)}
+
+	// Collect the scenarios and their prerequisites, transitively
+	var collected []string
+	queue := make([]string, 0, len(paths))
+	for _, path := range paths {
+		queue = append(queue, filepath.Clean(path))
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if _, seen := suite.DependsOn[path]; seen {
+			continue
+		}
+
+		deps, err := LoadDependencies(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, dependency := range deps.DependsOn {
+			if dependency == path {
+				return nil, fmt.Errorf("scenario %s depends on itself", path)
+			}
+			if _, err := os.Stat(dependency); err != nil {
+				return nil, fmt.Errorf("scenario %s: depends_on %s: %w", path, dependency, err)
+			}
+			queue = append(queue, dependency)
+		}
+
+		suite.DependsOn[path] = deps.DependsOn
+		collected = append(collected, path)
+	}
+
+	// Depth-first, visiting prerequisites in declared order, so the result
+	// stays close to the given order
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(collected))
+	var visit func(path string, chain []string) error
+	visit = func(path string, chain []string) error {
+		switch state[path] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(chain, path), " -> "))
+		}
+
+		state[path] = visiting
+		for _, dependency := range suite.DependsOn[path] {
+			if err := visit(dependency, append(chain, path)); err != nil {
+				return err
+			}
+		}
+		state[path] = visited
+		suite.Order = append(suite.Order, path)
+		return nil
+	}
+
+	for _, path := range collected {
+		if err := visit(path, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return suite, nil
+}
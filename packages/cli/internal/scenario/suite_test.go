@@ -0,0 +1,139 @@
+package scenario
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadDependencies(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScenarioFile(t, dir, "refund.yaml", `
+depends_on: [setup.yaml, ../shared/login.yaml]
+outputs:
+  refund_id: "{{steps.refund.response.id}}"
+`)
+
+	deps, err := LoadDependencies(path)
+	if err != nil {
+		t.Fatalf("LoadDependencies() error = %v", err)
+	}
+
+	wantDeps := []string{filepath.Join(dir, "setup.yaml"), filepath.Join(filepath.Dir(dir), "shared", "login.yaml")}
+	if !reflect.DeepEqual(deps.DependsOn, wantDeps) {
+		t.Errorf("DependsOn = %v, want %v", deps.DependsOn, wantDeps)
+	}
+	wantOutputs := map[string]string{"refund_id": "{{steps.refund.response.id}}"}
+	if !reflect.DeepEqual(deps.Outputs, wantOutputs) {
+		t.Errorf("Outputs = %v, want %v", deps.Outputs, wantOutputs)
+	}
+}
+
+func TestOrderScenarios(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		paths   []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "no dependencies keep their order",
+			files: map[string]string{
+				"b.yaml": "name: B\n",
+				"a.yaml": "name: A\n",
+			},
+			paths: []string{"b.yaml", "a.yaml"},
+			want:  []string{"b.yaml", "a.yaml"},
+		},
+		{
+			name: "prerequisites first",
+			files: map[string]string{
+				"refund.yaml":    "depends_on: [charge.yaml]\n",
+				"charge.yaml":    "depends_on: [setup.yaml]\n",
+				"setup.yaml":     "name: Setup\n",
+				"unrelated.yaml": "name: Unrelated\n",
+			},
+			paths: []string{"refund.yaml", "unrelated.yaml", "setup.yaml", "charge.yaml"},
+			want:  []string{"setup.yaml", "charge.yaml", "refund.yaml", "unrelated.yaml"},
+		},
+		{
+			name: "missing prerequisites are added",
+			files: map[string]string{
+				"refund.yaml": "depends_on: [charge.yaml]\n",
+				"charge.yaml": "depends_on: [setup.yaml]\n",
+				"setup.yaml":  "name: Setup\n",
+			},
+			paths: []string{"refund.yaml"},
+			want:  []string{"setup.yaml", "charge.yaml", "refund.yaml"},
+		},
+		{
+			name: "shared prerequisite runs once",
+			files: map[string]string{
+				"refund.yaml":  "depends_on: [setup.yaml]\n",
+				"dispute.yaml": "depends_on: [setup.yaml]\n",
+				"setup.yaml":   "name: Setup\n",
+			},
+			paths: []string{"refund.yaml", "dispute.yaml"},
+			want:  []string{"setup.yaml", "refund.yaml", "dispute.yaml"},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.yaml": "depends_on: [b.yaml]\n",
+				"b.yaml": "depends_on: [a.yaml]\n",
+			},
+			paths:   []string{"a.yaml"},
+			wantErr: "dependency cycle",
+		},
+		{
+			name: "depends on itself",
+			files: map[string]string{
+				"a.yaml": "depends_on: [a.yaml]\n",
+			},
+			paths:   []string{"a.yaml"},
+			wantErr: "depends on itself",
+		},
+		{
+			name: "missing prerequisite file",
+			files: map[string]string{
+				"a.yaml": "depends_on: [missing.yaml]\n",
+			},
+			paths:   []string{"a.yaml"},
+			wantErr: "depends_on",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeScenarioFile(t, dir, name, content)
+			}
+			paths := make([]string, len(tt.paths))
+			for i, path := range tt.paths {
+				paths[i] = filepath.Join(dir, path)
+			}
+
+			suite, err := OrderScenarios(paths)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("OrderScenarios() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OrderScenarios() error = %v", err)
+			}
+
+			got := make([]string, len(suite.Order))
+			for i, path := range suite.Order {
+				got[i] = filepath.Base(path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadTimeout reads a scenario's own timeout, which overrides lab.yaml's
+// simulation.timeouts.scenario for it. It returns 0 if the scenario sets
+// none.
+func LoadTimeout(path string) (time.Duration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var doc struct {
+		Timeout string `yaml:"timeout"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if doc.Timeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(doc.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%s: invalid timeout %q (e.g. \"5m\")", path, doc.Timeout)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,43 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "none", yaml: "name: Plain\n", want: 0},
+		{name: "minutes", yaml: "timeout: 5m\n", want: 5 * time.Minute},
+		{name: "compound", yaml: "timeout: 1m30s\n", want: 90 * time.Second},
+		{name: "no unit", yaml: "timeout: \"30\"\n", wantErr: "invalid timeout"},
+		{name: "zero", yaml: "timeout: 0s\n", wantErr: "invalid timeout"},
+		{name: "negative", yaml: "timeout: -1m\n", wantErr: "invalid timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScenarioFile(t, t.TempDir(), "scenario.yaml", tt.yaml)
+
+			got, err := LoadTimeout(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadTimeout() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTimeout() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("LoadTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
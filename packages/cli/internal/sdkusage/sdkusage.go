@@ -0,0 +1,170 @@
+// Package sdkusage reports which client SDKs (openai-python, go-openai,
+// LangChain, ...) agents used against the mocks, so a run shows the SDK
+// versions exercised and flags deprecated ones before production does.
+package sdkusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// usagePath is the mock admin endpoint reporting SDK usage
+const usagePath = "/_sentra/sdks"
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 5 * time.Second
+
+// Usage is the traffic one SDK version sent to a mock.
+type Usage struct {
+	Mock        string `json:"mock"`
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	UserAgent   string `json:"user_agent,omitempty"`
+	Requests    int64  `json:"requests"`
+	Deprecation string `json:"deprecation,omitempty"`
+}
+
+// SDK renders the SDK as "name/version".
+func (u Usage) SDK() string {
+	if u.Version == "" {
+		return u.Name
+	}
+	return u.Name + "/" + u.Version
+}
+
+// Deprecated returns the usages whose SDK the mock reports as deprecated.
+func Deprecated(usages []Usage) []Usage {
+	var deprecated []Usage
+	for _, usage := range usages {
+		if usage.Deprecation != "" {
+			deprecated = append(deprecated, usage)
+		}
+	}
+	return deprecated
+}
+
+// supportsMock reports whether a mock fingerprints client SDKs.
+func supportsMock(name string) bool {
+	return name == "openai"
+}
+
+// Collector measures the SDK usage of one test run by diffing the mocks'
+// cumulative counters before and after it.
+type Collector struct {
+	mocks    map[string]string
+	client   *http.Client
+	baseline map[string]Usage
+}
+
+// NewCollector builds a collector for the enabled mocks that fingerprint
+// SDKs. It returns nil when there are none.
+func NewCollector(cfg *config.Config) *Collector {
+	mocks := make(map[string]string)
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled && supportsMock(name) {
+			mocks[name] = mock.BaseURL()
+		}
+	}
+	if len(mocks) == 0 {
+		return nil
+	}
+
+	return &Collector{
+		mocks:  mocks,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Fetch returns the SDK usage the mocks recorded across all runs.
+func (c *Collector) Fetch(ctx context.Context) ([]Usage, error) {
+	var usages []Usage
+	for _, name := range c.mockNames() {
+		mockUsage, err := c.fetch(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, mockUsage...)
+	}
+	return usages, nil
+}
+
+// Start records the counters the run's usage is measured from.
+func (c *Collector) Start(ctx context.Context) error {
+	usages, err := c.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.baseline = make(map[string]Usage, len(usages))
+	for _, usage := range usages {
+		c.baseline[key(usage)] = usage
+	}
+	return nil
+}
+
+// Finish returns the usage since Start, most-used first.
+func (c *Collector) Finish(ctx context.Context) ([]Usage, error) {
+	usages, err := c.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var run []Usage
+	for _, usage := range usages {
+		usage.Requests -= c.baseline[key(usage)].Requests
+		if usage.Requests > 0 {
+			run = append(run, usage)
+		}
+	}
+
+	sort.SliceStable(run, func(i, j int) bool { return run[i].Requests > run[j].Requests })
+	return run, nil
+}
+
+func (c *Collector) fetch(ctx context.Context, mock string) ([]Usage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.mocks[mock]+usagePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SDK usage from %s mock: %w", mock, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch SDK usage from %s mock: status %d", mock, resp.StatusCode)
+	}
+
+	var list struct {
+		Data []Usage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid SDK usage from %s mock: %w", mock, err)
+	}
+
+	for i := range list.Data {
+		list.Data[i].Mock = mock
+	}
+	return list.Data, nil
+}
+
+func (c *Collector) mockNames() []string {
+	names := make([]string, 0, len(c.mocks))
+	for name := range c.mocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func key(usage Usage) string {
+	return usage.Mock + " " + usage.SDK()
+}
@@ -0,0 +1,110 @@
+package sdkusage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// usageMock serves the SDK usage endpoint with the current counters.
+type usageMock struct {
+	*httptest.Server
+	usages []Usage
+}
+
+func newUsageMock(t *testing.T) *usageMock {
+	t.Helper()
+	mock := &usageMock{}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != usagePath {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": mock.usages})
+	}))
+	t.Cleanup(mock.Close)
+	return mock
+}
+
+func TestUsageSDK(t *testing.T) {
+	if got := (Usage{Name: "openai-python", Version: "1.30.1"}).SDK(); got != "openai-python/1.30.1" {
+		t.Errorf("SDK() = %s", got)
+	}
+	if got := (Usage{Name: "curl"}).SDK(); got != "curl" {
+		t.Errorf("SDK() without a version = %s", got)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	usages := []Usage{
+		{Name: "openai-python", Version: "0.28.0", Deprecation: "openai-python < 1.0 is deprecated"},
+		{Name: "openai-python", Version: "1.30.1"},
+	}
+	if got := Deprecated(usages); !reflect.DeepEqual(got, usages[:1]) {
+		t.Errorf("Deprecated() = %+v, want the 0.28.0 usage", got)
+	}
+}
+
+func TestNewCollector(t *testing.T) {
+	if c := NewCollector(&config.Config{Mocks: map[string]config.MockConfig{"stripe": {Enabled: true, Port: 8081}}}); c != nil {
+		t.Errorf("NewCollector() without the OpenAI mock = %+v, want nil", c)
+	}
+	if c := NewCollector(&config.Config{Mocks: map[string]config.MockConfig{"openai": {Port: 8080}}}); c != nil {
+		t.Errorf("NewCollector() with the OpenAI mock disabled = %+v, want nil", c)
+	}
+
+	c := NewCollector(&config.Config{Mocks: map[string]config.MockConfig{"openai": {Enabled: true, Port: 8080}}})
+	if c == nil || c.mocks["openai"] != "http://localhost:8080" {
+		t.Errorf("NewCollector() = %+v, want the OpenAI mock", c)
+	}
+}
+
+func TestCollectorRun(t *testing.T) {
+	mock := newUsageMock(t)
+	mock.usages = []Usage{
+		{Name: "openai-python", Version: "1.30.1", Requests: 10},
+		{Name: "langchain", Version: "0.2.0", Requests: 4},
+	}
+	c := &Collector{mocks: map[string]string{"openai": mock.URL}, client: mock.Client()}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// During the run: python is used again, LangChain is not, and Go appears
+	mock.usages = []Usage{
+		{Name: "openai-python", Version: "1.30.1", Requests: 12},
+		{Name: "langchain", Version: "0.2.0", Requests: 4},
+		{Name: "go-openai", Version: "1.20.0", Requests: 5, Deprecation: "upgrade"},
+	}
+
+	run, err := c.Finish(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Usage{
+		{Mock: "openai", Name: "go-openai", Version: "1.20.0", Requests: 5, Deprecation: "upgrade"},
+		{Mock: "openai", Name: "openai-python", Version: "1.30.1", Requests: 2},
+	}
+	if !reflect.DeepEqual(run, want) {
+		t.Errorf("Finish() = %+v, want %+v", run, want)
+	}
+}
+
+func TestCollectorFetchError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	c := &Collector{mocks: map[string]string{"openai": server.URL}, client: server.Client()}
+
+	if _, err := c.Fetch(context.Background()); err == nil {
+		t.Error("Fetch() error = nil, want the status")
+	}
+	if err := c.Start(context.Background()); err == nil {
+		t.Error("Start() error = nil, want the status")
+	}
+}
@@ -0,0 +1,316 @@
+// Package seed applies the initial mock state declared in a project's
+// seeds/ directory: Stripe customers and products, OpenAI files and vector
+// stores, CoreLedger accounts, ...
+//
+// Each YAML file in seeds/ maps mock names to collections of records:
+//
+//	stripe:
+//	  customers:
+//	    - id: cus_alice
+//	      email: alice@example.com
+//	openai:
+//	  files:
+//	    - id: file-refund-policy
+//	      filename: refund-policy.md
+//	      content_file: ../docs/refund-policy.md
+//
+// Collections from several files are concatenated in file name order. A
+// content_file is read relative to its seed file and sent as content.
+//
+// Mocks that support seeding serve POST /_sentra/state/seed, which replaces
+// their seeded records with {"collections": {...}} and keeps them as the
+// baseline POST /_sentra/state/reset restores, so every scenario starts
+// from the seeds.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is where seed files live, relative to the project
+const Dir = "seeds"
+
+const (
+	// seedPath is the mock admin endpoint that replaces seeded state
+	seedPath = "/_sentra/state/seed"
+
+	// resetPath is the mock admin endpoint that resets state
+	resetPath = "/_sentra/state/reset"
+)
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 10 * time.Second
+
+// Seeds are the records declared in a seeds directory.
+type Seeds struct {
+	// Mocks maps mock names to collections ("customers", "files", ...)
+	// and their records
+	Mocks map[string]map[string][]interface{}
+
+	// Files are the seed files read, in the order applied
+	Files []string
+}
+
+// Load reads every YAML file in dir. A missing directory has no seeds.
+func Load(dir string) (*Seeds, error) {
+	seeds := &Seeds{Mocks: make(map[string]map[string][]interface{})}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return seeds, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if err := seeds.load(path); err != nil {
+			return nil, err
+		}
+		seeds.Files = append(seeds.Files, path)
+	}
+
+	return seeds, nil
+}
+
+func (s *Seeds) load(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	var doc map[string]map[string][]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s (expected mock: {collection: [records]}): %w", path, err)
+	}
+
+	for mock, collections := range doc {
+		if s.Mocks[mock] == nil {
+			s.Mocks[mock] = make(map[string][]interface{})
+		}
+		for collection, records := range collections {
+			for i, record := range records {
+				if _, ok := record.(map[string]interface{}); !ok {
+					return fmt.Errorf("%s: %s.%s[%d] must be a mapping", path, mock, collection, i)
+				}
+				inlined, err := inlineContent(record, filepath.Dir(path))
+				if err != nil {
+					return fmt.Errorf("%s: %s.%s[%d]: %w", path, mock, collection, i, err)
+				}
+				s.Mocks[mock][collection] = append(s.Mocks[mock][collection], inlined)
+			}
+		}
+	}
+	return nil
+}
+
+// inlineContent replaces content_file keys with the file's text as content.
+func inlineContent(value interface{}, dir string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			inlined, err := inlineContent(item, dir)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = inlined
+		}
+
+		file, ok := v["content_file"].(string)
+		if !ok {
+			return v, nil
+		}
+		if _, ok := v["content"]; ok {
+			return nil, fmt.Errorf("content and content_file are mutually exclusive")
+		}
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read content_file: %w", err)
+		}
+		delete(v, "content_file")
+		v["content"] = string(content)
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			inlined, err := inlineContent(item, dir)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = inlined
+		}
+	}
+	return value, nil
+}
+
+// Records returns the number of records seeded into mock.
+func (s *Seeds) Records(mock string) int {
+	count := 0
+	for _, records := range s.Mocks[mock] {
+		count += len(records)
+	}
+	return count
+}
+
+// Result is the outcome of seeding one mock.
+type Result struct {
+	Mock string
+
+	// Collections are the seeded collections, sorted
+	Collections []string
+
+	// Records is the number of records sent
+	Records int
+
+	// Unsupported is set when the mock does not support seeding; its
+	// seeds were not applied
+	Unsupported bool
+}
+
+// Applier seeds the enabled mocks.
+type Applier struct {
+	ports  map[string]int
+	client *http.Client
+}
+
+// NewApplier returns an applier for the enabled mocks in cfg. Seeds for a
+// mock that is not enabled are an error when applied.
+func NewApplier(cfg *config.Config) *Applier {
+	ports := make(map[string]int)
+	for name, mock := range cfg.Mocks {
+		if mock.Enabled {
+			ports[name] = mock.Port
+		}
+	}
+
+	return &Applier{
+		ports:  ports,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Apply replaces the seeded records of every enabled mock with seeds, so
+// records removed from seeds/ are removed from the mocks too. State that
+// scenarios built up is left alone.
+func (a *Applier) Apply(ctx context.Context, seeds *Seeds) ([]Result, error) {
+	for mock := range seeds.Mocks {
+		if _, ok := a.ports[mock]; !ok {
+			return nil, fmt.Errorf("seeds for mock %s, which is not enabled in lab.yaml", mock)
+		}
+	}
+
+	var results []Result
+	for _, mock := range a.mocks() {
+		collections := seeds.Mocks[mock]
+		if collections == nil {
+			collections = map[string][]interface{}{}
+		}
+
+		result := Result{Mock: mock, Records: seeds.Records(mock)}
+		for collection := range collections {
+			result.Collections = append(result.Collections, collection)
+		}
+		sort.Strings(result.Collections)
+
+		supported, err := a.post(ctx, mock, seedPath, map[string]interface{}{"collections": collections})
+		if err != nil {
+			return results, err
+		}
+		if !supported {
+			// Mocks without seeds that cannot be seeded are not worth reporting
+			if result.Records == 0 {
+				continue
+			}
+			result.Unsupported = true
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Reset discards all state of the enabled mocks (usage, threads, rate limit
+// buckets, ...), then applies seeds, so the mocks hold exactly the seeded
+// records.
+func (a *Applier) Reset(ctx context.Context, seeds *Seeds) ([]Result, error) {
+	for _, mock := range a.mocks() {
+		if _, err := a.post(ctx, mock, resetPath, map[string]interface{}{}); err != nil {
+			return nil, err
+		}
+	}
+	return a.Apply(ctx, seeds)
+}
+
+// mocks returns the enabled mocks, sorted.
+func (a *Applier) mocks() []string {
+	names := make([]string, 0, len(a.ports))
+	for name := range a.ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// post sends an admin request to a mock. It returns false if the mock does
+// not serve the endpoint.
+func (a *Applier) post(ctx context.Context, mock, path string, body interface{}) (bool, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode %s seeds: %w", mock, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("http://localhost:%d%s", a.ports[mock], path), bytes.NewReader(encoded))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%s mock: %w", mock, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 300:
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			return false, fmt.Errorf("%s mock rejected seeds: %s", mock, apiErr.Error.Message)
+		}
+		return false, fmt.Errorf("%s mock: status %d", mock, resp.StatusCode)
+	}
+	return true, nil
+}
+
+// Describe summarizes a result, e.g. "stripe: 3 record(s) (customers, products)".
+func (r Result) Describe() string {
+	if r.Unsupported {
+		return fmt.Sprintf("%s: does not support seeding, %d record(s) not applied", r.Mock, r.Records)
+	}
+	if r.Records == 0 {
+		return fmt.Sprintf("%s: no seeds", r.Mock)
+	}
+	return fmt.Sprintf("%s: %d record(s) (%s)", r.Mock, r.Records, strings.Join(r.Collections, ", "))
+}
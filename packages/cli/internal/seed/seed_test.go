@@ -0,0 +1,243 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// writeFiles writes files relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// adminMock is a fake mock admin API recording the requests it receives.
+type adminMock struct {
+	port     int
+	requests []string
+	bodies   []map[string]interface{}
+}
+
+// newAdminMock serves the seed and reset endpoints, or 404s when
+// unsupported.
+func newAdminMock(t *testing.T, supported bool) *adminMock {
+	t.Helper()
+	mock := &adminMock{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.requests = append(mock.requests, r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mock.bodies = append(mock.bodies, body)
+		if !supported {
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	mock.port, _ = strconv.Atoi(u.Port())
+	return mock
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"seeds/01-stripe.yaml": "stripe:\n  customers:\n    - id: cus_alice\n      email: alice@example.com\n",
+		"seeds/02-more.yml":    "stripe:\n  customers:\n    - id: cus_bob\n  products:\n    - id: prod_pro\nopenai:\n  files:\n    - id: file-policy\n      content_file: ../docs/policy.md\n",
+		"seeds/notes.txt":      "not a seed file",
+		"docs/policy.md":       "Refunds within 30 days.",
+	})
+
+	seeds, err := Load(filepath.Join(dir, "seeds"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFiles := []string{filepath.Join(dir, "seeds", "01-stripe.yaml"), filepath.Join(dir, "seeds", "02-more.yml")}
+	if !reflect.DeepEqual(seeds.Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", seeds.Files, wantFiles)
+	}
+
+	customers := seeds.Mocks["stripe"]["customers"]
+	if len(customers) != 2 || customers[0].(map[string]interface{})["id"] != "cus_alice" {
+		t.Errorf("stripe customers = %v, want alice then bob", customers)
+	}
+	if got := seeds.Records("stripe"); got != 3 {
+		t.Errorf("Records(stripe) = %d, want 3", got)
+	}
+
+	file := seeds.Mocks["openai"]["files"][0].(map[string]interface{})
+	if file["content"] != "Refunds within 30 days." || file["content_file"] != nil {
+		t.Errorf("openai file = %v, want content_file inlined", file)
+	}
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	seeds, err := Load(filepath.Join(t.TempDir(), "seeds"))
+	if err != nil || len(seeds.Mocks) != 0 {
+		t.Errorf("Load() = %+v, %v; want no seeds", seeds, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "not a mapping of collections",
+			files:   map[string]string{"seeds/a.yaml": "stripe: [cus_alice]\n"},
+			wantErr: "expected mock: {collection: [records]}",
+		},
+		{
+			name:    "record is not a mapping",
+			files:   map[string]string{"seeds/a.yaml": "stripe:\n  customers: [cus_alice]\n"},
+			wantErr: "stripe.customers[0] must be a mapping",
+		},
+		{
+			name:    "content and content_file",
+			files:   map[string]string{"seeds/a.yaml": "openai:\n  files:\n    - content: x\n      content_file: x.md\n", "seeds/x.md": "x"},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "missing content_file",
+			files:   map[string]string{"seeds/a.yaml": "openai:\n  files:\n    - content_file: missing.md\n"},
+			wantErr: "failed to read content_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			_, err := Load(filepath.Join(dir, "seeds"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewApplier(t *testing.T) {
+	applier := NewApplier(&config.Config{Mocks: map[string]config.MockConfig{
+		"openai": {Enabled: true, Port: 8080},
+		"stripe": {Enabled: true, Port: 8081},
+		"slack":  {Port: 8083},
+	}})
+
+	if want := map[string]int{"openai": 8080, "stripe": 8081}; !reflect.DeepEqual(applier.ports, want) {
+		t.Errorf("ports = %v, want %v", applier.ports, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	stripe := newAdminMock(t, true)
+	openai := newAdminMock(t, true)
+	ledger := newAdminMock(t, false)
+	applier := &Applier{
+		ports:  map[string]int{"stripe": stripe.port, "openai": openai.port, "coreledger": ledger.port},
+		client: http.DefaultClient,
+	}
+
+	seeds := &Seeds{Mocks: map[string]map[string][]interface{}{
+		"stripe": {
+			"products":  {map[string]interface{}{"id": "prod_pro"}},
+			"customers": {map[string]interface{}{"id": "cus_alice"}, map[string]interface{}{"id": "cus_bob"}},
+		},
+		"coreledger": {"accounts": {map[string]interface{}{"id": "acct_1"}}},
+	}}
+
+	results, err := applier.Apply(context.Background(), seeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Result{
+		{Mock: "coreledger", Collections: []string{"accounts"}, Records: 1, Unsupported: true},
+		{Mock: "openai", Records: 0},
+		{Mock: "stripe", Collections: []string{"customers", "products"}, Records: 3},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Apply() = %+v, want %+v", results, want)
+	}
+
+	// Mocks without seeds are sent empty collections, clearing old seeds
+	if !reflect.DeepEqual(openai.requests, []string{seedPath}) || len(openai.bodies[0]["collections"].(map[string]interface{})) != 0 {
+		t.Errorf("openai received %v %v, want an empty seed", openai.requests, openai.bodies)
+	}
+	customers := stripe.bodies[0]["collections"].(map[string]interface{})["customers"].([]interface{})
+	if len(customers) != 2 {
+		t.Errorf("stripe received %d customers, want 2", len(customers))
+	}
+
+	wantDescriptions := []string{
+		"coreledger: does not support seeding, 1 record(s) not applied",
+		"openai: no seeds",
+		"stripe: 3 record(s) (customers, products)",
+	}
+	for i, result := range results {
+		if got := result.Describe(); got != wantDescriptions[i] {
+			t.Errorf("Describe() = %q, want %q", got, wantDescriptions[i])
+		}
+	}
+}
+
+func TestApplyDisabledMock(t *testing.T) {
+	applier := &Applier{ports: map[string]int{}, client: http.DefaultClient}
+	seeds := &Seeds{Mocks: map[string]map[string][]interface{}{"stripe": {}}}
+
+	if _, err := applier.Apply(context.Background(), seeds); err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Errorf("Apply() error = %v, want stripe not enabled", err)
+	}
+}
+
+func TestApplyRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "unknown collection widgets"}}`))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	applier := &Applier{ports: map[string]int{"stripe": port}, client: http.DefaultClient}
+	seeds := &Seeds{Mocks: map[string]map[string][]interface{}{"stripe": {"widgets": {map[string]interface{}{}}}}}
+
+	_, err := applier.Apply(context.Background(), seeds)
+	if err == nil || err.Error() != "stripe mock rejected seeds: unknown collection widgets" {
+		t.Errorf("Apply() error = %v, want the mock's message", err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	stripe := newAdminMock(t, true)
+	applier := &Applier{ports: map[string]int{"stripe": stripe.port}, client: http.DefaultClient}
+
+	if _, err := applier.Reset(context.Background(), &Seeds{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{resetPath, seedPath}; !reflect.DeepEqual(stripe.requests, want) {
+		t.Errorf("requests = %v, want %v", stripe.requests, want)
+	}
+}
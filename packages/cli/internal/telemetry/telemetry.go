@@ -0,0 +1,273 @@
+// Package telemetry records anonymous usage events - which command ran, how
+// long it took and the class of any error, never arguments, paths or
+// payloads - when the user has opted in. Events are queued locally in
+// ~/.sentra-lab/telemetry and sent in batches to Sentra's collector or a
+// self-hosted one.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/globalconfig"
+)
+
+// DefaultEndpoint is Sentra's collector
+const DefaultEndpoint = "https://telemetry.sentra.dev/v1/events"
+
+const (
+	// BatchSize is how many queued events trigger a send
+	BatchSize = 20
+
+	// MaxAge is how long an event may wait before the queue is sent anyway
+	MaxAge = 24 * time.Hour
+
+	// maxQueued bounds the queue when the collector is unreachable
+	maxQueued = 1000
+
+	// sendTimeout bounds how long a command waits for a send
+	sendTimeout = 2 * time.Second
+)
+
+// Event is one command invocation.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	DurationMs int64     `json:"duration_ms"`
+	ErrorClass string    `json:"error_class,omitempty"`
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	CI         bool      `json:"ci"`
+}
+
+// QueuePath returns the local event queue.
+func QueuePath() (string, error) {
+	path, err := globalconfig.Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "telemetry", "queue.jsonl"), nil
+}
+
+// Endpoint returns where events are sent: SENTRA_TELEMETRY_ENDPOINT, else
+// telemetry.endpoint, else DefaultEndpoint.
+func Endpoint(cfg *globalconfig.Config) string {
+	if endpoint := os.Getenv("SENTRA_TELEMETRY_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	if cfg.Telemetry.Endpoint != "" {
+		return cfg.Telemetry.Endpoint
+	}
+	return DefaultEndpoint
+}
+
+// ClassifyError reduces err to a coarse class, so no message (which may
+// contain paths or prompts) leaves the machine.
+func ClassifyError(err error) string {
+	var (
+		exitErr *exec.ExitError
+		netErr  net.Error
+		pathErr *os.PathError
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, os.ErrNotExist):
+		return "not_found"
+	case errors.Is(err, os.ErrPermission):
+		return "permission"
+	case errors.As(err, &netErr):
+		return "network"
+	case errors.As(err, &exitErr):
+		return "exit"
+	case errors.As(err, &pathErr):
+		return "filesystem"
+	}
+	return "error"
+}
+
+// Track records a finished command when telemetry is enabled and sends the
+// queue when a batch is due. It never fails the command: problems are
+// ignored.
+func Track(command, version string, duration time.Duration, err error) {
+	cfg, loadErr := globalconfig.Load()
+	if loadErr != nil || !cfg.TelemetryEnabled() {
+		return
+	}
+
+	event := Event{
+		Time:       time.Now().UTC(),
+		Command:    command,
+		DurationMs: duration.Milliseconds(),
+		ErrorClass: ClassifyError(err),
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		CI:         os.Getenv("CI") != "",
+	}
+	if Record(event) != nil {
+		return
+	}
+
+	events, queueErr := Queued()
+	if queueErr != nil || !Due(events) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	Flush(ctx, Endpoint(cfg))
+}
+
+// Record appends event to the queue.
+func Record(event Event) error {
+	path, err := QueuePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Queued returns the events waiting to be sent, oldest first.
+func Queued() ([]Event, error) {
+	path, err := QueuePath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if json.Unmarshal(scanner.Bytes(), &event) == nil {
+			events = append(events, event)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Due reports whether events should be sent now: a full batch, or an event
+// older than MaxAge.
+func Due(events []Event) bool {
+	if len(events) >= BatchSize {
+		return true
+	}
+	return len(events) > 0 && time.Since(events[0].Time) > MaxAge
+}
+
+// Flush sends the queued events to endpoint and empties the queue, returning
+// how many were sent. When sending fails the queue is kept (trimmed to the
+// newest events) for the next attempt.
+func Flush(ctx context.Context, endpoint string) (int, error) {
+	events, err := Queued()
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	if err := send(ctx, endpoint, events); err != nil {
+		if len(events) > maxQueued {
+			trim(events[len(events)-maxQueued:])
+		}
+		return 0, err
+	}
+
+	return len(events), Clear()
+}
+
+// Clear deletes the queued events.
+func Clear() error {
+	path, err := QueuePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func send(ctx context.Context, endpoint string, events []Event) error {
+	body, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid telemetry endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// trim rewrites the queue with only events.
+func trim(events []Event) error {
+	path, err := QueuePath()
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, b.Bytes(), 0600)
+}
@@ -0,0 +1,203 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/globalconfig"
+)
+
+// setHome points the user's home directory, and so the queue, at a temp
+// directory.
+func setHome(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+}
+
+// optIn saves settings with telemetry enabled, sending to endpoint.
+func optIn(t *testing.T, endpoint string) {
+	t.Helper()
+	cfg := globalconfig.Default()
+	cfg.Telemetry.Enabled = true
+	cfg.Telemetry.Endpoint = endpoint
+	if _, err := globalconfig.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// collector is a fake telemetry collector.
+type collector struct {
+	*httptest.Server
+	batches [][]Event
+	status  int
+}
+
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{status: http.StatusAccepted}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []Event `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		c.batches = append(c.batches, body.Events)
+		w.WriteHeader(c.status)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{fmt.Errorf("run: %w", context.Canceled), "canceled"},
+		{context.DeadlineExceeded, "timeout"},
+		{&os.PathError{Op: "open", Path: "/home/me/lab.yaml", Err: os.ErrNotExist}, "not_found"},
+		{&os.PathError{Op: "open", Path: "/etc/x", Err: os.ErrPermission}, "permission"},
+		{&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}, "network"},
+		{&exec.ExitError{}, "exit"},
+		{&os.PathError{Op: "read", Path: "/x", Err: fmt.Errorf("is a directory")}, "filesystem"},
+		{fmt.Errorf("scenario refund.yaml failed"), "error"},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyError(tt.err); got != tt.want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	t.Setenv("SENTRA_TELEMETRY_ENDPOINT", "")
+	cfg := globalconfig.Default()
+	if got := Endpoint(cfg); got != DefaultEndpoint {
+		t.Errorf("Endpoint() = %s, want the default", got)
+	}
+
+	cfg.Telemetry.Endpoint = "https://collector.internal/events"
+	if got := Endpoint(cfg); got != cfg.Telemetry.Endpoint {
+		t.Errorf("Endpoint() = %s, want the configured one", got)
+	}
+
+	t.Setenv("SENTRA_TELEMETRY_ENDPOINT", "http://localhost:4318/events")
+	if got := Endpoint(cfg); got != "http://localhost:4318/events" {
+		t.Errorf("Endpoint() = %s, want SENTRA_TELEMETRY_ENDPOINT", got)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Now()
+	batch := make([]Event, BatchSize)
+	for i := range batch {
+		batch[i].Time = now
+	}
+
+	tests := []struct {
+		name   string
+		events []Event
+		want   bool
+	}{
+		{name: "empty"},
+		{name: "recent", events: []Event{{Time: now}}},
+		{name: "full batch", events: batch, want: true},
+		{name: "stale", events: []Event{{Time: now.Add(-MaxAge - time.Minute)}}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := Due(tt.events); got != tt.want {
+			t.Errorf("%s: Due() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordFlush(t *testing.T) {
+	setHome(t)
+	c := newCollector(t)
+
+	for _, command := range []string{"start", "test"} {
+		if err := Record(Event{Command: command, Time: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	queued, err := Queued()
+	if err != nil || len(queued) != 2 || queued[0].Command != "start" {
+		t.Fatalf("Queued() = %+v, %v; want start and test", queued, err)
+	}
+
+	sent, err := Flush(context.Background(), c.URL)
+	if err != nil || sent != 2 {
+		t.Fatalf("Flush() = %d, %v; want 2 sent", sent, err)
+	}
+	if len(c.batches) != 1 || len(c.batches[0]) != 2 {
+		t.Errorf("collector received %v, want one batch of 2", c.batches)
+	}
+	if queued, _ := Queued(); len(queued) != 0 {
+		t.Errorf("Queued() after Flush() = %d events, want none", len(queued))
+	}
+}
+
+func TestFlushKeepsQueueOnFailure(t *testing.T) {
+	setHome(t)
+	c := newCollector(t)
+	c.status = http.StatusServiceUnavailable
+
+	if err := Record(Event{Command: "test", Time: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Flush(context.Background(), c.URL); err == nil {
+		t.Fatal("Flush() error = nil, want the collector's status")
+	}
+	if queued, _ := Queued(); len(queued) != 1 {
+		t.Errorf("Queued() after a failed Flush() = %d events, want 1", len(queued))
+	}
+}
+
+func TestTrack(t *testing.T) {
+	setHome(t)
+	t.Setenv("SENTRA_TELEMETRY", "")
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("SENTRA_TELEMETRY_ENDPOINT", "")
+	c := newCollector(t)
+
+	// Not opted in: nothing is queued
+	Track("test", "1.0.0", time.Second, nil)
+	if queued, _ := Queued(); len(queued) != 0 {
+		t.Fatalf("Queued() without opt-in = %d events, want none", len(queued))
+	}
+
+	optIn(t, c.URL)
+	Track("test", "1.0.0", 1500*time.Millisecond, context.DeadlineExceeded)
+	queued, err := Queued()
+	if err != nil || len(queued) != 1 {
+		t.Fatalf("Queued() = %+v, %v; want one event", queued, err)
+	}
+	event := queued[0]
+	if event.Command != "test" || event.Version != "1.0.0" || event.DurationMs != 1500 || event.ErrorClass != "timeout" {
+		t.Errorf("event = %+v", event)
+	}
+	if len(c.batches) != 0 {
+		t.Errorf("sent %d batches before a batch was due", len(c.batches))
+	}
+
+	for i := 1; i < BatchSize; i++ {
+		Track("status", "1.0.0", time.Millisecond, nil)
+	}
+	if len(c.batches) != 1 || len(c.batches[0]) != BatchSize {
+		t.Errorf("collector received %d batches, want one of %d", len(c.batches), BatchSize)
+	}
+}
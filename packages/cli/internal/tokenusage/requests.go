@@ -0,0 +1,106 @@
+package tokenusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/utils"
+)
+
+// contextPath is the mock admin endpoint reporting the context utilization
+// of captured requests
+const contextPath = "/_sentra/context"
+
+// requestTimeout bounds a single admin request
+const requestTimeout = 10 * time.Second
+
+// Request is one model call's context window usage, with its prompt counted
+// by the OpenAI mock's tokenizer.
+type Request struct {
+	Seq       int64  `json:"seq"`
+	RunID     string `json:"run_id"`
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	Model     string `json:"model"`
+
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	ContextTokens    int `json:"context_tokens"`
+
+	// ContextWindow is the model's context window, and Utilization
+	// ContextTokens as a share of it
+	ContextWindow int     `json:"context_window"`
+	Utilization   float64 `json:"utilization"`
+
+	// NearLimit is set when Utilization reaches the warning threshold
+	NearLimit bool `json:"near_limit"`
+}
+
+// FetchRequests returns the context utilization of a run's model calls,
+// flagging those at or above warnAt (a share of the context window).
+func FetchRequests(ctx context.Context, baseURL, runID string, warnAt float64) ([]Request, error) {
+	query := url.Values{}
+	query.Set("run_id", runID)
+	query.Set("warn_at", fmt.Sprintf("%g", warnAt))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+contextPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch context utilization for %s: %w", runID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch context utilization for %s: status %d", runID, resp.StatusCode)
+	}
+
+	var list struct {
+		Data []Request `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid context utilization for %s: %w", runID, err)
+	}
+	return list.Data, nil
+}
+
+// NearLimitRequests returns the flagged requests.
+func NearLimitRequests(requests []Request) []Request {
+	var flagged []Request
+	for _, request := range requests {
+		if request.NearLimit {
+			flagged = append(flagged, request)
+		}
+	}
+	return flagged
+}
+
+// RenderRequests writes a scenario's model calls as a table of context
+// window utilization, flagging those near the limit with ⚠.
+func RenderRequests(w io.Writer, scenario string, requests []Request) {
+	fmt.Fprintf(w, "%s\n", scenario)
+	if len(requests) == 0 {
+		fmt.Fprintln(w, "  (no model calls)")
+		return
+	}
+
+	fmt.Fprintf(w, "  %-5s %-22s %8s %8s %9s %9s %s\n", "#", "MODEL", "PROMPT", "COMPL", "CONTEXT", "WINDOW", "USED")
+	for i, request := range requests {
+		used := shade(request.Utilization) + fmt.Sprintf("%5.1f%%", request.Utilization*100) + utils.ColorReset
+		if request.NearLimit {
+			used += " ⚠"
+		}
+		fmt.Fprintf(w, "  %-5d %-22s %8d %8d %9d %9d %s\n",
+			i+1, truncate(request.Model, 22), request.PromptTokens, request.CompletionTokens,
+			request.ContextTokens, request.ContextWindow, used)
+	}
+}
@@ -0,0 +1,79 @@
+package tokenusage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFetchRequests(t *testing.T) {
+	requests := []Request{
+		{Seq: 1, RunID: "run_1", Model: "gpt-4o", PromptTokens: 900, ContextTokens: 1000, ContextWindow: 128000, Utilization: 1000.0 / 128000},
+		{Seq: 2, RunID: "run_1", Model: "gpt-4", PromptTokens: 7000, ContextTokens: 7500, ContextWindow: 8192, Utilization: 7500.0 / 8192, NearLimit: true},
+	}
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != contextPath {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": requests})
+	}))
+	defer server.Close()
+
+	got, err := FetchRequests(context.Background(), server.URL, "run_1", 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, requests) {
+		t.Errorf("FetchRequests() = %+v, want %+v", got, requests)
+	}
+	if query != "run_id=run_1&warn_at=0.75" {
+		t.Errorf("query = %s, want the run and threshold", query)
+	}
+
+	if flagged := NearLimitRequests(got); len(flagged) != 1 || flagged[0].Seq != 2 {
+		t.Errorf("NearLimitRequests() = %+v, want request 2", flagged)
+	}
+}
+
+func TestFetchRequestsError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := FetchRequests(context.Background(), server.URL, "run_1", DefaultWarnUtilization)
+	if err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("FetchRequests() error = %v, want the status", err)
+	}
+}
+
+func TestRenderRequests(t *testing.T) {
+	var buf bytes.Buffer
+	RenderRequests(&buf, "scenarios/refund.yaml", []Request{
+		{Model: "gpt-4o", PromptTokens: 900, CompletionTokens: 100, ContextTokens: 1000, ContextWindow: 128000, Utilization: 1000.0 / 128000},
+		{Model: "gpt-4", PromptTokens: 7000, CompletionTokens: 500, ContextTokens: 7500, ContextWindow: 8192, Utilization: 7500.0 / 8192, NearLimit: true},
+	})
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	if len(lines) != 4 || lines[0] != "scenarios/refund.yaml" || !strings.Contains(lines[1], "WINDOW") {
+		t.Fatalf("RenderRequests() =\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "1" || fields[1] != "gpt-4o" || fields[4] != "1000" || strings.Contains(lines[2], "⚠") {
+		t.Errorf("first row = %q", lines[2])
+	}
+	if !strings.Contains(lines[3], " 91.6%") || !strings.HasSuffix(lines[3], " ⚠") {
+		t.Errorf("near-limit row = %q", lines[3])
+	}
+
+	buf.Reset()
+	RenderRequests(&buf, "scenarios/chat.yaml", nil)
+	if want := "scenarios/chat.yaml\n  (no model calls)\n"; buf.String() != want {
+		t.Errorf("RenderRequests() = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,143 @@
+// Package tokenusage breaks a scenario's token usage down by step: prompt
+// versus completion tokens, how the context grows across turns, and which
+// steps come close to the model's context window, where agents start
+// truncating history without telling anyone.
+package tokenusage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+	"github.com/sentra-lab/cli/internal/utils"
+)
+
+// DefaultWarnUtilization is the share of the context window at which a step
+// is flagged
+const DefaultWarnUtilization = 0.8
+
+// barWidth is the width of a heatmap row
+const barWidth = 30
+
+// Step is one step's token usage.
+type Step struct {
+	Step             string `json:"step"`
+	Model            string `json:"model,omitempty"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+
+	// ContextTokens is the context length after the step (its prompt plus
+	// completion), and Growth how much it grew since the previous step
+	ContextTokens int `json:"context_tokens"`
+	Growth        int `json:"growth"`
+
+	// ContextWindow is the model's context window (0 when unknown), and
+	// Utilization ContextTokens as a share of it
+	ContextWindow int     `json:"context_window,omitempty"`
+	Utilization   float64 `json:"utilization,omitempty"`
+
+	// NearLimit is set when Utilization reaches the warning threshold
+	NearLimit bool `json:"near_limit,omitempty"`
+}
+
+// Analyze computes context growth and utilization for a scenario's steps,
+// flagging those at or above warnAt (a share of the context window).
+func Analyze(usage []grpc.StepUsage, warnAt float64) []Step {
+	steps := make([]Step, 0, len(usage))
+	previous := 0
+	for _, u := range usage {
+		step := Step{
+			Step:             u.Step,
+			Model:            u.Model,
+			PromptTokens:     u.PromptTokens,
+			CompletionTokens: u.CompletionTokens,
+			ContextTokens:    u.PromptTokens + u.CompletionTokens,
+			ContextWindow:    u.ContextWindow,
+		}
+		step.Growth = step.ContextTokens - previous
+		previous = step.ContextTokens
+
+		if step.ContextWindow > 0 {
+			step.Utilization = float64(step.ContextTokens) / float64(step.ContextWindow)
+			step.NearLimit = step.Utilization >= warnAt
+		}
+
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// NearLimit returns the flagged steps.
+func NearLimit(steps []Step) []Step {
+	var flagged []Step
+	for _, step := range steps {
+		if step.NearLimit {
+			flagged = append(flagged, step)
+		}
+	}
+	return flagged
+}
+
+// RenderHeatmap writes a scenario's steps as a heatmap: one row per step,
+// with a bar for the context length shaded by how full the context window
+// is (relative to the scenario's largest context when windows are
+// unknown).
+func RenderHeatmap(w io.Writer, scenario string, steps []Step) {
+	fmt.Fprintf(w, "%s\n", scenario)
+	if len(steps) == 0 {
+		fmt.Fprintln(w, "  (no model calls)")
+		return
+	}
+
+	largest := 1
+	for _, step := range steps {
+		if step.ContextTokens > largest {
+			largest = step.ContextTokens
+		}
+	}
+
+	fmt.Fprintf(w, "  %-24s %8s %8s %9s %8s  %-*s %s\n", "STEP", "PROMPT", "COMPL", "CONTEXT", "GROWTH", barWidth, "CONTEXT LENGTH", "WINDOW")
+	for _, step := range steps {
+		share, color := float64(step.ContextTokens)/float64(largest), utils.ColorCyan
+		if step.ContextWindow > 0 {
+			share, color = step.Utilization, shade(step.Utilization)
+		}
+
+		cells := int(share*barWidth + 0.5)
+		if cells > barWidth {
+			cells = barWidth
+		}
+		bar := color + strings.Repeat("█", cells) + utils.ColorReset + strings.Repeat("·", barWidth-cells)
+
+		window := "-"
+		if step.ContextWindow > 0 {
+			window = fmt.Sprintf("%5.1f%%", step.Utilization*100)
+		}
+		if step.NearLimit {
+			window += " ⚠"
+		}
+
+		fmt.Fprintf(w, "  %-24s %8d %8d %9d %+8d  %s %s\n",
+			truncate(step.Step, 24), step.PromptTokens, step.CompletionTokens, step.ContextTokens, step.Growth, bar, window)
+	}
+}
+
+// shade colors a bar from green (plenty of room) to red (nearly full).
+func shade(share float64) string {
+	switch {
+	case share >= DefaultWarnUtilization:
+		return utils.ColorRed
+	case share >= 0.5:
+		return utils.ColorYellow
+	default:
+		return utils.ColorGreen
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
@@ -0,0 +1,70 @@
+package tokenusage
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/grpc"
+)
+
+func TestAnalyze(t *testing.T) {
+	usage := []grpc.StepUsage{
+		{Step: "classify", Model: "gpt-4o-mini", PromptTokens: 1000, CompletionTokens: 200, ContextWindow: 128000},
+		{Step: "draft reply", Model: "gpt-4", PromptTokens: 6000, CompletionTokens: 600, ContextWindow: 8192},
+		{Step: "local tool", Model: "custom", PromptTokens: 3000, CompletionTokens: 100},
+	}
+
+	steps := Analyze(usage, DefaultWarnUtilization)
+
+	want := []Step{
+		{Step: "classify", Model: "gpt-4o-mini", PromptTokens: 1000, CompletionTokens: 200, ContextTokens: 1200, Growth: 1200,
+			ContextWindow: 128000, Utilization: 1200.0 / 128000},
+		{Step: "draft reply", Model: "gpt-4", PromptTokens: 6000, CompletionTokens: 600, ContextTokens: 6600, Growth: 5400,
+			ContextWindow: 8192, Utilization: 6600.0 / 8192, NearLimit: true},
+		{Step: "local tool", Model: "custom", PromptTokens: 3000, CompletionTokens: 100, ContextTokens: 3100, Growth: -3500},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("Analyze() =\n%+v\nwant\n%+v", steps, want)
+	}
+
+	if flagged := NearLimit(steps); len(flagged) != 1 || flagged[0].Step != "draft reply" {
+		t.Errorf("NearLimit() = %+v, want draft reply", flagged)
+	}
+	if flagged := NearLimit(Analyze(usage, 0.9)); len(flagged) != 0 {
+		t.Errorf("NearLimit() at 90%% = %+v, want none", flagged)
+	}
+}
+
+func TestRenderHeatmap(t *testing.T) {
+	steps := Analyze([]grpc.StepUsage{
+		{Step: "classify", Model: "gpt-4o-mini", PromptTokens: 1000, CompletionTokens: 200, ContextWindow: 128000},
+		{Step: "a step with a very long descriptive name", Model: "gpt-4", PromptTokens: 6000, CompletionTokens: 600, ContextWindow: 8192},
+	}, DefaultWarnUtilization)
+
+	var buf bytes.Buffer
+	RenderHeatmap(&buf, "scenarios/refund.yaml", steps)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+
+	if len(lines) != 4 || lines[0] != "scenarios/refund.yaml" || !strings.Contains(lines[1], "CONTEXT LENGTH") {
+		t.Fatalf("RenderHeatmap() =\n%s", buf.String())
+	}
+	if !strings.Contains(lines[2], "classify") || !strings.Contains(lines[2], "  0.9%") || strings.Contains(lines[2], "⚠") {
+		t.Errorf("classify row = %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "a step with a very long…") || !strings.Contains(lines[3], " 80.6% ⚠") {
+		t.Errorf("near-limit row = %q", lines[3])
+	}
+	if got := strings.Count(lines[3], "█") + strings.Count(lines[3], "·"); got != barWidth {
+		t.Errorf("bar has %d cells, want %d", got, barWidth)
+	}
+}
+
+func TestRenderHeatmapNoSteps(t *testing.T) {
+	var buf bytes.Buffer
+	RenderHeatmap(&buf, "scenarios/chat.yaml", nil)
+	if want := "scenarios/chat.yaml\n  (no model calls)\n"; buf.String() != want {
+		t.Errorf("RenderHeatmap() = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/sentra-lab/cli/internal/utils"
+)
+
+// HighlightJSON indents a JSON document and colors keys, strings, numbers
+// and literals. Bodies that are not JSON are returned as they are.
+func HighlightJSON(body []byte) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(body), "", "  "); err != nil {
+		return string(body)
+	}
+
+	src := indented.String()
+	var out strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"':
+			end := stringEnd(src, i)
+			color := utils.ColorGreen
+			if isKey(src, end) {
+				color = utils.ColorCyan
+			}
+			out.WriteString(color + src[i:end] + utils.ColorReset)
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(src) && strings.IndexByte("0123456789.eE+-", src[end]) >= 0 {
+				end++
+			}
+			out.WriteString(utils.ColorYellow + src[i:end] + utils.ColorReset)
+			i = end
+		case strings.HasPrefix(src[i:], "true"), strings.HasPrefix(src[i:], "null"):
+			out.WriteString(utils.ColorMagenta + src[i:i+4] + utils.ColorReset)
+			i += 4
+		case strings.HasPrefix(src[i:], "false"):
+			out.WriteString(utils.ColorMagenta + src[i:i+5] + utils.ColorReset)
+			i += 5
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// stringEnd returns the index just past the string literal starting at i.
+func stringEnd(src string, i int) int {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// isKey reports whether the string literal ending at end is an object key.
+func isKey(src string, end int) bool {
+	rest := strings.TrimLeft(src[end:], " ")
+	return strings.HasPrefix(rest, ":")
+}
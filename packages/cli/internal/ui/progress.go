@@ -93,6 +93,10 @@ func (m *TestProgressModel) View() string {
 			icon = "✓"
 			style = successStyle
 			completed++
+		case "cached":
+			icon = "↺"
+			style = successStyle
+			completed++
 		case "failed":
 			icon = "✗"
 			style = errorStyle
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ANSI color sequences. They are cleared when the console cannot render
+// them, so output can always include them.
+var (
+	ColorReset   = "\033[0m"
+	ColorRed     = "\033[31m"
+	ColorGreen   = "\033[32m"
+	ColorYellow  = "\033[33m"
+	ColorMagenta = "\033[35m"
+	ColorCyan    = "\033[36m"
+)
+
+// InitColors turns colors off when NO_COLOR is set, TERM is dumb, stdout
+// is not a terminal, or a Windows console cannot process ANSI sequences.
+func InitColors() {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		DisableColors()
+		return
+	}
+
+	if !term.IsTerminal(int(os.Stdout.Fd())) || !enableVirtualTerminal() {
+		DisableColors()
+	}
+}
+
+// DisableColors clears the color sequences.
+func DisableColors() {
+	ColorReset = ""
+	ColorRed = ""
+	ColorGreen = ""
+	ColorYellow = ""
+	ColorMagenta = ""
+	ColorCyan = ""
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package utils
+
+// enableVirtualTerminal reports whether the console processes ANSI
+// sequences, which terminals outside Windows always do.
+func enableVirtualTerminal() bool {
+	return true
+}
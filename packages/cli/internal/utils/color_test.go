@@ -0,0 +1,41 @@
+package utils
+
+import "testing"
+
+// saveColors restores the color sequences when the test ends.
+func saveColors(t *testing.T) {
+	t.Helper()
+	saved := []string{ColorReset, ColorRed, ColorGreen, ColorYellow, ColorMagenta, ColorCyan}
+	t.Cleanup(func() {
+		ColorReset, ColorRed, ColorGreen, ColorYellow, ColorMagenta, ColorCyan =
+			saved[0], saved[1], saved[2], saved[3], saved[4], saved[5]
+	})
+}
+
+func TestInitColors(t *testing.T) {
+	tests := []struct {
+		name    string
+		noColor string
+		term    string
+	}{
+		{name: "NO_COLOR", noColor: "1", term: "xterm-256color"},
+		{name: "dumb terminal", term: "dumb"},
+		{name: "stdout is not a terminal", term: "xterm-256color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saveColors(t)
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("TERM", tt.term)
+
+			InitColors()
+
+			for _, color := range []string{ColorReset, ColorRed, ColorGreen, ColorYellow, ColorMagenta, ColorCyan} {
+				if color != "" {
+					t.Fatalf("InitColors() left color %q enabled", color)
+				}
+			}
+		})
+	}
+}
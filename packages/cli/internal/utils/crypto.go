@@ -20,11 +20,11 @@ func GenerateID(prefix string) string {
 	bytes := make([]byte, 16)
 	rand.Read(bytes)
 	id := hex.EncodeToString(bytes)
-	
+
 	if prefix != "" {
 		return fmt.Sprintf("%s-%s", prefix, id[:16])
 	}
-	
+
 	return id[:16]
 }
 
@@ -39,4 +39,4 @@ func GenerateToken(length int) (string, error) {
 		return "", err
 	}
 	return base64.URLEncoding.EncodeToString(bytes), nil
-}
\ No newline at end of file
+}
@@ -1,124 +1,362 @@
 package utils
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
+// historySize is how many recent lines a Logger keeps for diagnostics
+const historySize = 200
+
+// LevelFatal is logged by Fatal, above slog's levels
+const LevelFatal = slog.Level(12)
+
+// Log formats: pretty is the colored console output, text and json are
+// slog's key=value and JSON lines for CI and log shippers
+const (
+	FormatPretty = "pretty"
+	FormatText   = "text"
+	FormatJSON   = "json"
+)
+
+// LogFormats are the supported --log-format values
+var LogFormats = []string{FormatPretty, FormatText, FormatJSON}
+
+// Subsystems that have their own loggers (and can have their own levels)
+const (
+	SubsystemDocker = "docker"
+	SubsystemEngine = "engine"
+	SubsystemCloud  = "cloud"
+)
+
+// Logger writes leveled messages with key=value fields. Messages take
+// slog-style arguments: logger.Info("started", "port", 8080).
 type Logger struct {
-	name   string
-	level  string
-	output *log.Logger
+	name      string
+	subsystem string
+	fields    []interface{}
+	state     *loggerState
+}
+
+// loggerState is shared by a Logger and the loggers derived from it, so
+// configuring the root logger applies to all of them.
+type loggerState struct {
+	mu      sync.Mutex
+	level   slog.Level
+	levels  map[string]slog.Level
+	format  string
+	output  io.Writer
+	handler slog.Handler
+
+	file        *os.File
+	fileHandler slog.Handler
+
+	history []string
+	onFatal func(msg string)
 }
 
 func NewLogger(name, level string) *Logger {
+	parsed, ok := parseLevel(level)
+	if !ok {
+		parsed = slog.LevelInfo
+	}
+
 	return &Logger{
-		name:   name,
-		level:  strings.ToUpper(level),
-		output: log.New(os.Stdout, "", 0),
+		name: name,
+		state: &loggerState{
+			level:  parsed,
+			levels: make(map[string]slog.Level),
+			format: FormatPretty,
+			output: os.Stdout,
+		},
 	}
 }
 
+// SetLevel sets the level for messages outside subsystems with their own.
 func (l *Logger) SetLevel(level string) {
-	l.level = strings.ToUpper(level)
+	if parsed, ok := parseLevel(level); ok {
+		l.state.mu.Lock()
+		l.state.level = parsed
+		l.state.mu.Unlock()
+	}
 }
 
-func (l *Logger) Debug(msg string, args ...interface{}) {
-	if l.shouldLog("DEBUG") {
-		l.log("DEBUG", msg, args...)
+// SetLevels parses a level spec such as "info,docker=debug,engine=warn": a
+// default level and/or per-subsystem levels.
+func (l *Logger) SetLevels(spec string) error {
+	var level *slog.Level
+	levels := make(map[string]slog.Level)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		subsystem, value, scoped := strings.Cut(part, "=")
+		if !scoped {
+			value = subsystem
+		}
+		parsed, ok := parseLevel(value)
+		if !ok {
+			return fmt.Errorf("invalid log level %q (must be debug, info, warn or error)", value)
+		}
+
+		if scoped {
+			levels[strings.TrimSpace(subsystem)] = parsed
+		} else {
+			level = &parsed
+		}
+	}
+
+	l.state.mu.Lock()
+	if level != nil {
+		l.state.level = *level
 	}
+	l.state.levels = levels
+	l.state.mu.Unlock()
+	return nil
 }
 
-func (l *Logger) Info(msg string, args ...interface{}) {
-	if l.shouldLog("INFO") {
-		l.log("INFO", msg, args...)
+// SetFormat sets the console format (pretty, text or json).
+func (l *Logger) SetFormat(format string) error {
+	s := l.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch format {
+	case FormatPretty:
+		s.handler = nil
+	case FormatText, FormatJSON:
+		s.handler = newHandler(format, s.output)
+	default:
+		return fmt.Errorf("invalid log format %q (must be one of: %s)", format, strings.Join(LogFormats, ", "))
 	}
+
+	s.format = format
+	if s.file != nil {
+		s.fileHandler = newHandler(fileFormat(format), s.file)
+	}
+	return nil
 }
 
-func (l *Logger) Warn(msg string, args ...interface{}) {
-	if l.shouldLog("WARN") {
-		l.log("WARN", msg, args...)
+// SetOutputFile also writes every message to path (appending), as JSON
+// lines when the format is json and key=value lines otherwise.
+func (l *Logger) SetOutputFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
 	}
+
+	s := l.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	s.fileHandler = newHandler(fileFormat(s.format), file)
+	return nil
 }
 
-func (l *Logger) Error(msg string, args ...interface{}) {
-	if l.shouldLog("ERROR") {
-		l.log("ERROR", msg, args...)
+// Subsystem returns a logger for a part of the CLI (docker, engine, cloud)
+// that tags messages with subsystem=<name> and honors that subsystem's
+// level.
+func (l *Logger) Subsystem(name string) *Logger {
+	child := l.With("subsystem", name)
+	child.subsystem = name
+	return child
+}
+
+// With returns a logger that adds key=value fields to every message.
+func (l *Logger) With(args ...interface{}) *Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(args))
+	fields = append(fields, l.fields...)
+	fields = append(fields, args...)
+
+	return &Logger{
+		name:      l.name,
+		subsystem: l.subsystem,
+		fields:    fields,
+		state:     l.state,
 	}
 }
 
+func (l *Logger) Debug(msg string, args ...interface{}) {
+	l.log(slog.LevelDebug, msg, args...)
+}
+
+func (l *Logger) Info(msg string, args ...interface{}) {
+	l.log(slog.LevelInfo, msg, args...)
+}
+
+func (l *Logger) Warn(msg string, args ...interface{}) {
+	l.log(slog.LevelWarn, msg, args...)
+}
+
+func (l *Logger) Error(msg string, args ...interface{}) {
+	l.log(slog.LevelError, msg, args...)
+}
+
 func (l *Logger) Fatal(msg string, args ...interface{}) {
-	l.log("FATAL", msg, args...)
+	l.log(LevelFatal, msg, args...)
+	if l.state.onFatal != nil {
+		l.state.onFatal(msg)
+	}
 	os.Exit(1)
 }
 
-func (l *Logger) log(level, msg string, args ...interface{}) {
-	timestamp := time.Now().Format("15:04:05")
-	
-	levelColor := l.getLevelColor(level)
-	resetColor := "\033[0m"
-	
-	formattedMsg := msg
-	if len(args) > 0 {
-		formattedMsg = fmt.Sprintf(msg, args...)
-	}
-	
-	logLine := fmt.Sprintf("%s [%s%s%s] %s",
-		timestamp,
-		levelColor,
-		level,
-		resetColor,
-		formattedMsg,
-	)
-	
-	l.output.Println(logLine)
-}
-
-func (l *Logger) shouldLog(level string) bool {
-	levels := map[string]int{
-		"DEBUG": 0,
-		"INFO":  1,
-		"WARN":  2,
-		"ERROR": 3,
-		"FATAL": 4,
-	}
-	
-	currentLevel, exists := levels[l.level]
-	if !exists {
-		currentLevel = levels["INFO"]
-	}
-	
-	messageLevel, exists := levels[level]
-	if !exists {
-		return false
-	}
-	
-	return messageLevel >= currentLevel
+// OnFatal sets a function Fatal calls before exiting, e.g. to write a
+// diagnostics bundle.
+func (l *Logger) OnFatal(fn func(msg string)) {
+	l.state.onFatal = fn
+}
+
+// History returns the last lines logged (up to 200), without colors.
+func (l *Logger) History() []string {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	return append([]string(nil), l.state.history...)
+}
+
+func (l *Logger) log(level slog.Level, msg string, args ...interface{}) {
+	s := l.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	threshold, scoped := s.levels[l.subsystem]
+	if !scoped {
+		threshold = s.level
+	}
+	if level < threshold && level != LevelFatal {
+		return
+	}
+
+	now := time.Now()
+	record := slog.NewRecord(now, level, msg, 0)
+	record.Add(l.fields...)
+	record.Add(args...)
+
+	if s.handler != nil {
+		s.handler.Handle(context.Background(), record)
+	} else {
+		levelName := levelString(level)
+		fmt.Fprintf(s.output, "%s [%s%s%s] %s%s\n",
+			now.Format("15:04:05"),
+			l.getLevelColor(levelName),
+			levelName,
+			ColorReset,
+			msg,
+			formatAttrs(record),
+		)
+	}
+	if s.fileHandler != nil {
+		s.fileHandler.Handle(context.Background(), record)
+	}
+
+	s.history = append(s.history, fmt.Sprintf("%s [%s] %s%s", now.Format("15:04:05"), levelString(level), msg, formatAttrs(record)))
+	if len(s.history) > historySize {
+		s.history = s.history[len(s.history)-historySize:]
+	}
+}
+
+// formatAttrs renders a record's fields as " key=value ..." for the pretty
+// format, quoting values that contain spaces.
+func formatAttrs(record slog.Record) string {
+	var b strings.Builder
+	record.Attrs(func(attr slog.Attr) bool {
+		value := attr.Value.Resolve().String()
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", attr.Key, value)
+		return true
+	})
+	return b.String()
+}
+
+func newHandler(format string, output io.Writer) slog.Handler {
+	opts := &slog.HandlerOptions{
+		// Levels are filtered by the Logger, per subsystem
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.LevelKey && len(groups) == 0 {
+				if level, ok := attr.Value.Any().(slog.Level); ok {
+					return slog.String(slog.LevelKey, levelString(level))
+				}
+			}
+			return attr
+		},
+	}
+
+	if format == FormatJSON {
+		return slog.NewJSONHandler(output, opts)
+	}
+	return slog.NewTextHandler(output, opts)
+}
+
+// fileFormat is the log file format for a console format: the colored
+// pretty format is written as text.
+func fileFormat(format string) string {
+	if format == FormatJSON {
+		return FormatJSON
+	}
+	return FormatText
+}
+
+func parseLevel(level string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return slog.LevelInfo, false
+}
+
+func levelString(level slog.Level) string {
+	if level >= LevelFatal {
+		return "FATAL"
+	}
+	return level.String()
 }
 
 func (l *Logger) getLevelColor(level string) string {
 	colors := map[string]string{
-		"DEBUG": "\033[36m",
-		"INFO":  "\033[32m",
-		"WARN":  "\033[33m",
-		"ERROR": "\033[31m",
-		"FATAL": "\033[35m",
+		"DEBUG": ColorCyan,
+		"INFO":  ColorGreen,
+		"WARN":  ColorYellow,
+		"ERROR": ColorRed,
+		"FATAL": ColorMagenta,
 	}
-	
+
 	if color, exists := colors[level]; exists {
 		return color
 	}
-	
+
 	return ""
 }
 
 func (l *Logger) WithField(key string, value interface{}) *Logger {
-	return l
+	return l.With(key, value)
 }
 
 func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
-	return l
-}
\ No newline at end of file
+	args := make([]interface{}, 0, len(fields)*2)
+	for key, value := range fields {
+		args = append(args, key, value)
+	}
+	return l.With(args...)
+}
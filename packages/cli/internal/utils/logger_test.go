@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bufferedLogger returns a logger at level writing to a buffer in format.
+func bufferedLogger(t *testing.T, level, format string) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := NewLogger("test", level)
+	logger.state.output = &buf
+	if err := logger.SetFormat(format); err != nil {
+		t.Fatalf("SetFormat() error = %v", err)
+	}
+	return logger, &buf
+}
+
+func TestLoggerSetLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+		logged  map[string][]string
+	}{
+		{
+			name:   "default level",
+			spec:   "warn",
+			logged: map[string][]string{"": {"warn", "error"}, SubsystemDocker: {"warn", "error"}},
+		},
+		{
+			name:   "subsystem level",
+			spec:   "error,docker=debug",
+			logged: map[string][]string{"": {"error"}, SubsystemDocker: {"debug", "info", "warn", "error"}, SubsystemEngine: {"error"}},
+		},
+		{
+			name:   "subsystem only keeps the default",
+			spec:   " engine=warning , ",
+			logged: map[string][]string{"": {"info", "warn", "error"}, SubsystemEngine: {"warn", "error"}},
+		},
+		{name: "invalid level", spec: "loud", wantErr: true},
+		{name: "invalid subsystem level", spec: "info,docker=verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := bufferedLogger(t, "info", FormatText)
+
+			err := logger.SetLevels(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetLevels(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+
+			for subsystem, want := range tt.logged {
+				buf.Reset()
+				l := logger
+				if subsystem != "" {
+					l = logger.Subsystem(subsystem)
+				}
+				l.Debug("debug")
+				l.Info("info")
+				l.Warn("warn")
+				l.Error("error")
+
+				var got []string
+				for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+					if _, msg, ok := strings.Cut(line, "msg="); ok {
+						got = append(got, strings.Fields(msg)[0])
+					}
+				}
+				if strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("subsystem %q logged %v, want %v", subsystem, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{format: FormatPretty, want: []string{"INFO", "] started port=8080 subsystem=docker image=\"mock openai\"\n"}},
+		{format: FormatText, want: []string{"level=INFO msg=started port=8080 subsystem=docker image=\"mock openai\"\n"}},
+		{format: FormatJSON, want: []string{`"level":"INFO"`, `"msg":"started"`, `"port":8080`, `"subsystem":"docker"`, `"image":"mock openai"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			logger, buf := bufferedLogger(t, "info", tt.format)
+			logger.With("port", 8080).Subsystem(SubsystemDocker).Info("started", "image", "mock openai")
+
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output = %q, want it to contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+
+	logger := NewLogger("test", "info")
+	if err := logger.SetFormat("xml"); err == nil || !strings.Contains(err.Error(), "pretty, text, json") {
+		t.Errorf("SetFormat(xml) error = %v, want the supported formats", err)
+	}
+}
+
+func TestLoggerOutputFile(t *testing.T) {
+	tests := []struct {
+		format   string
+		wantJSON bool
+	}{
+		{format: FormatPretty},
+		{format: FormatJSON, wantJSON: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sentra.log")
+			logger, _ := bufferedLogger(t, "info", FormatPretty)
+			if err := logger.SetOutputFile(path); err != nil {
+				t.Fatalf("SetOutputFile() error = %v", err)
+			}
+			if err := logger.SetFormat(tt.format); err != nil {
+				t.Fatalf("SetFormat() error = %v", err)
+			}
+			logger.Warn("disk low", "free_mb", 12)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var entry map[string]interface{}
+			isJSON := json.Unmarshal(data, &entry) == nil
+			if isJSON != tt.wantJSON {
+				t.Errorf("log file = %q, want JSON %v", data, tt.wantJSON)
+			}
+			if !strings.Contains(string(data), "disk low") || !strings.Contains(string(data), "free_mb") {
+				t.Errorf("log file = %q, want the message and its fields", data)
+			}
+		})
+	}
+
+	if err := NewLogger("test", "info").SetOutputFile(filepath.Join(t.TempDir(), "missing", "sentra.log")); err == nil {
+		t.Error("SetOutputFile() in a missing directory error = nil, want an error")
+	}
+}
+
+func TestLoggerHistory(t *testing.T) {
+	logger, _ := bufferedLogger(t, "warn", FormatText)
+
+	logger.Info("skipped")
+	for i := 0; i < historySize+5; i++ {
+		logger.Warn("line", "n", i)
+	}
+
+	history := logger.History()
+	if len(history) != historySize {
+		t.Fatalf("History() has %d lines, want %d", len(history), historySize)
+	}
+	if !strings.HasSuffix(history[0], "[WARN] line n=5") || !strings.HasSuffix(history[historySize-1], "[WARN] line n=204") {
+		t.Errorf("History() = %q ... %q, want the last %d lines", history[0], history[historySize-1], historySize)
+	}
+}
+
+func TestFormatAttrs(t *testing.T) {
+	logger, _ := bufferedLogger(t, "info", FormatText)
+	logger.WithFields(map[string]interface{}{"empty": ""}).WithField("quote", `say "hi"`).Info("msg", "plain", "value")
+
+	history := logger.History()
+	want := ` empty="" quote="say \"hi\"" plain=value`
+	if len(history) != 1 || !strings.HasSuffix(history[0], want) {
+		t.Errorf("History() = %q, want a line ending in %q", history, want)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level  string
+		want   string
+		wantOK bool
+	}{
+		{level: "debug", want: "DEBUG", wantOK: true},
+		{level: " INFO ", want: "INFO", wantOK: true},
+		{level: "warning", want: "WARN", wantOK: true},
+		{level: "error", want: "ERROR", wantOK: true},
+		{level: "trace", want: "INFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			level, ok := parseLevel(tt.level)
+			if levelString(level) != tt.want || ok != tt.wantOK {
+				t.Errorf("parseLevel(%q) = %s, %v, want %s, %v", tt.level, levelString(level), ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got := levelString(LevelFatal); got != "FATAL" {
+		t.Errorf("levelString(LevelFatal) = %q, want FATAL", got)
+	}
+}
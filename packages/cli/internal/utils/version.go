@@ -6,7 +6,7 @@ import (
 )
 
 const (
-	Version = "1.0.0"
+	Version   = "1.0.0"
 	BuildDate = "2025-01-01"
 )
 
@@ -39,4 +39,4 @@ func (v *VersionInfo) String() string {
 		v.Arch,
 		v.Commit,
 	)
-}
\ No newline at end of file
+}
@@ -0,0 +1,289 @@
+// Package webhooks talks to the webhook admin API of mocks that send
+// webhooks (Stripe and custom mocks): listing captured deliveries,
+// re-delivering one to the agent, and triggering arbitrary events.
+//
+// Mocks that send webhooks serve, under /_sentra/webhooks:
+//
+//	GET  /deliveries                 captured deliveries, newest first
+//	GET  /deliveries/:id             one delivery, with its payload
+//	POST /deliveries/:id/redeliver   send a delivery again ({"url", "timestamp_skew_ms"})
+//	POST /trigger                    send an event ({"type", "data", "url", "timestamp_skew_ms"})
+//
+// timestamp_skew_ms shifts the timestamp the delivery is signed with, so
+// agents' signature tolerance windows can be exercised.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// adminPath is the webhook admin API prefix
+const adminPath = "/_sentra/webhooks"
+
+// requestTimeout bounds a single admin request; deliveries to the agent
+// happen within it
+const requestTimeout = 30 * time.Second
+
+// Delivery is a webhook a mock sent (or tried to send).
+type Delivery struct {
+	ID        string `json:"id"`
+	Mock      string `json:"mock"`
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	URL       string `json:"url"`
+
+	// Status is "delivered", "failed" or "pending"
+	Status string `json:"status"`
+
+	// ResponseStatus is the agent's HTTP status (0 if it never answered)
+	ResponseStatus int    `json:"response_status"`
+	Attempts       int    `json:"attempts"`
+	Error          string `json:"error,omitempty"`
+
+	// TimestampSkewMs is how far the signed timestamp was shifted
+	TimestampSkewMs int64 `json:"timestamp_skew_ms,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+
+	// Payload is the event body (only in single-delivery responses)
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Filter narrows listed deliveries.
+type Filter struct {
+	EventType string
+	Status    string
+	Limit     int
+}
+
+// DeliveryOptions control how a webhook is (re-)delivered.
+type DeliveryOptions struct {
+	// URL to deliver to instead of the default one
+	URL string
+
+	// TimestampSkew shifts the timestamp the webhook is signed with;
+	// negative values are in the past (e.g. -10m is outside Stripe's
+	// 5-minute tolerance, so a correct receiver rejects it)
+	TimestampSkew time.Duration
+}
+
+// body adds the options to an admin request body.
+func (o DeliveryOptions) body(body map[string]interface{}) map[string]interface{} {
+	if o.URL != "" {
+		body["url"] = o.URL
+	}
+	if o.TimestampSkew != 0 {
+		body["timestamp_skew_ms"] = o.TimestampSkew.Milliseconds()
+	}
+	return body
+}
+
+// Client is a mock's webhook admin API.
+type Client struct {
+	Mock    string
+	baseURL string
+	http    *http.Client
+}
+
+// NewClients returns clients for the enabled mocks (only mock when set).
+// OpenAI sends no webhooks and is skipped.
+func NewClients(cfg *config.Config, mock string) ([]*Client, error) {
+	if mock != "" {
+		mockConfig, ok := cfg.Mocks[mock]
+		if !ok || !mockConfig.Enabled {
+			return nil, fmt.Errorf("mock %s is not enabled", mock)
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Mocks))
+	for name, mockConfig := range cfg.Mocks {
+		if !mockConfig.Enabled || name == "openai" || (mock != "" && name != mock) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no enabled mocks send webhooks")
+	}
+
+	clients := make([]*Client, 0, len(names))
+	for _, name := range names {
+		clients = append(clients, &Client{
+			Mock:    name,
+			baseURL: fmt.Sprintf("http://localhost:%d", cfg.Mocks[name].Port),
+			http:    &http.Client{Timeout: requestTimeout},
+		})
+	}
+	return clients, nil
+}
+
+// ErrNotSupported is returned for mocks without a webhook admin API.
+var ErrNotSupported = errors.New("mock does not send webhooks")
+
+// errNotFound is returned by do when the admin API answers 404
+var errNotFound = errors.New("not found")
+
+// List returns captured deliveries, newest first.
+func (c *Client) List(ctx context.Context, filter Filter) ([]Delivery, error) {
+	query := url.Values{}
+	if filter.EventType != "" {
+		query.Set("event_type", filter.EventType)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	var list struct {
+		Data []Delivery `json:"data"`
+	}
+	if err := c.do(ctx, "GET", "/deliveries?"+query.Encode(), nil, &list); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("%s: %w", c.Mock, ErrNotSupported)
+		}
+		return nil, err
+	}
+
+	for i := range list.Data {
+		list.Data[i].Mock = c.Mock
+	}
+	return list.Data, nil
+}
+
+// Get returns a delivery with its payload.
+func (c *Client) Get(ctx context.Context, id string) (*Delivery, error) {
+	var delivery Delivery
+	if err := c.do(ctx, "GET", "/deliveries/"+url.PathEscape(id), nil, &delivery); err != nil {
+		return nil, c.notFound(err, id)
+	}
+	delivery.Mock = c.Mock
+	return &delivery, nil
+}
+
+// Redeliver sends a delivery's event again, to opts.URL if set (else its
+// original URL), and returns the new delivery.
+func (c *Client) Redeliver(ctx context.Context, id string, opts DeliveryOptions) (*Delivery, error) {
+	body := opts.body(map[string]interface{}{})
+
+	var delivery Delivery
+	if err := c.do(ctx, "POST", "/deliveries/"+url.PathEscape(id)+"/redeliver", body, &delivery); err != nil {
+		return nil, c.notFound(err, id)
+	}
+	delivery.Mock = c.Mock
+	return &delivery, nil
+}
+
+// Trigger sends an event of the given type with data as its object, to
+// opts.URL if set (else the mock's configured webhook URL).
+func (c *Client) Trigger(ctx context.Context, eventType string, data map[string]interface{}, opts DeliveryOptions) (*Delivery, error) {
+	body := opts.body(map[string]interface{}{"type": eventType})
+	if data != nil {
+		body["data"] = data
+	}
+
+	var delivery Delivery
+	if err := c.do(ctx, "POST", "/trigger", body, &delivery); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("%s: %w", c.Mock, ErrNotSupported)
+		}
+		return nil, err
+	}
+	delivery.Mock = c.Mock
+	return &delivery, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+adminPath+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s mock: %w", c.Mock, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode >= 300:
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s mock: %s", c.Mock, apiErr.Error.Message)
+		}
+		return fmt.Errorf("%s mock: status %d", c.Mock, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("%s mock: invalid response: %w", c.Mock, err)
+	}
+	return nil
+}
+
+// notFound explains a 404 for a delivery.
+func (c *Client) notFound(err error, id string) error {
+	if errors.Is(err, errNotFound) {
+		return fmt.Errorf("%s mock has no delivery %s", c.Mock, id)
+	}
+	return err
+}
+
+// LoadFixture reads an event payload (the event's data.object) from a JSON
+// or YAML file.
+func LoadFixture(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var data map[string]interface{}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &data)
+	default:
+		err = json.Unmarshal(content, &data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return data, nil
+}
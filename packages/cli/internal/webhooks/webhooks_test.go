@@ -0,0 +1,200 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+// adminRequest is a request the fake admin API received.
+type adminRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]interface{}
+}
+
+// newAdmin serves a fake webhook admin API and returns a client for it.
+func newAdmin(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*Client, *[]adminRequest) {
+	t.Helper()
+	var requests []adminRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := adminRequest{Method: r.Method, Path: strings.TrimPrefix(r.URL.Path, adminPath), Query: r.URL.RawQuery}
+		json.NewDecoder(r.Body).Decode(&request.Body)
+		requests = append(requests, request)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Client{Mock: "stripe", baseURL: server.URL, http: server.Client()}, &requests
+}
+
+func TestNewClients(t *testing.T) {
+	cfg := &config.Config{Mocks: map[string]config.MockConfig{
+		"openai":     {Enabled: true, Port: 8080},
+		"stripe":     {Enabled: true, Port: 8081},
+		"coreledger": {Enabled: true, Port: 8082},
+		"slack":      {Port: 8083},
+	}}
+
+	clients, err := NewClients(cfg, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, client := range clients {
+		names = append(names, client.Mock)
+	}
+	if want := []string{"coreledger", "stripe"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("NewClients() = %v, want %v", names, want)
+	}
+	if clients[1].baseURL != "http://localhost:8081" {
+		t.Errorf("baseURL = %s, want http://localhost:8081", clients[1].baseURL)
+	}
+
+	if clients, err := NewClients(cfg, "stripe"); err != nil || len(clients) != 1 || clients[0].Mock != "stripe" {
+		t.Errorf("NewClients(stripe) = %v, %v; want the stripe client", clients, err)
+	}
+	if _, err := NewClients(cfg, "slack"); err == nil {
+		t.Error("NewClients(slack) error = nil, want not enabled")
+	}
+	if _, err := NewClients(cfg, "openai"); err == nil {
+		t.Error("NewClients(openai) error = nil, want no mocks that send webhooks")
+	}
+}
+
+func TestList(t *testing.T) {
+	client, requests := newAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []Delivery{{ID: "wh_2", EventType: "charge.refunded", Status: "failed"}},
+		})
+	})
+
+	deliveries, err := client.List(context.Background(), Filter{EventType: "charge.refunded", Status: "failed", Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != "wh_2" || deliveries[0].Mock != "stripe" {
+		t.Errorf("List() = %+v, want wh_2 from stripe", deliveries)
+	}
+	if got := (*requests)[0]; got.Path != "/deliveries" || got.Query != "event_type=charge.refunded&limit=5&status=failed" {
+		t.Errorf("request = %s?%s", got.Path, got.Query)
+	}
+}
+
+func TestListNotSupported(t *testing.T) {
+	client, _ := newAdmin(t, http.NotFound)
+
+	if _, err := client.List(context.Background(), Filter{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("List() error = %v, want ErrNotSupported", err)
+	}
+	if _, err := client.Get(context.Background(), "wh_1"); err == nil || !strings.Contains(err.Error(), "has no delivery wh_1") {
+		t.Errorf("Get() error = %v, want no delivery wh_1", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	client, requests := newAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Delivery{ID: "wh/1", Payload: json.RawMessage(`{"id":"evt_1"}`)})
+	})
+
+	delivery, err := client.Get(context.Background(), "wh/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Mock != "stripe" || string(delivery.Payload) != `{"id":"evt_1"}` {
+		t.Errorf("Get() = %+v", delivery)
+	}
+	if got := (*requests)[0].Path; got != "/deliveries/wh/1" {
+		t.Errorf("path = %s", got)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	client, requests := newAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Delivery{ID: "wh_3", Status: "delivered"})
+	})
+
+	opts := DeliveryOptions{URL: "http://localhost:3000/hooks", TimestampSkew: -10 * time.Minute}
+	delivery, err := client.Redeliver(context.Background(), "wh_1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.ID != "wh_3" || delivery.Mock != "stripe" {
+		t.Errorf("Redeliver() = %+v", delivery)
+	}
+
+	got := (*requests)[0]
+	want := map[string]interface{}{"url": "http://localhost:3000/hooks", "timestamp_skew_ms": float64(-600000)}
+	if got.Method != "POST" || got.Path != "/deliveries/wh_1/redeliver" || !reflect.DeepEqual(got.Body, want) {
+		t.Errorf("request = %s %s %v, want POST /deliveries/wh_1/redeliver %v", got.Method, got.Path, got.Body, want)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	client, requests := newAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Delivery{ID: "wh_4", EventType: "payment_intent.succeeded"})
+	})
+
+	data := map[string]interface{}{"id": "pi_1", "amount": float64(500)}
+	if _, err := client.Trigger(context.Background(), "payment_intent.succeeded", data, DeliveryOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := (*requests)[0]
+	want := map[string]interface{}{"type": "payment_intent.succeeded", "data": data}
+	if got.Path != "/trigger" || !reflect.DeepEqual(got.Body, want) {
+		t.Errorf("request = %s %v, want /trigger %v", got.Path, got.Body, want)
+	}
+}
+
+func TestTriggerError(t *testing.T) {
+	client, _ := newAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "unknown event type"}}`))
+	})
+
+	_, err := client.Trigger(context.Background(), "nope", nil, DeliveryOptions{})
+	if err == nil || err.Error() != "stripe mock: unknown event type" {
+		t.Errorf("Trigger() error = %v, want the mock's message", err)
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"refund.json": `{"id": "re_1", "amount": 500}`,
+		"refund.yaml": "id: re_1\namount: 500\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fromJSON, err := LoadFixture(filepath.Join(dir, "refund.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := LoadFixture(filepath.Join(dir, "refund.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON["id"] != "re_1" || fromYAML["id"] != "re_1" || fromYAML["amount"] != 500 {
+		t.Errorf("LoadFixture() = %v and %v", fromJSON, fromYAML)
+	}
+
+	if _, err := LoadFixture(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadFixture(missing) error = nil")
+	}
+}
@@ -0,0 +1,208 @@
+// Package workspace loads workspaces: several lab projects under one root
+// (e.g. a monorepo with one agent per directory), listed in a workspace file
+// so `sentra lab test --all` can run every project's scenarios together.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sentra-lab/cli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the workspace file at the workspace root
+const FileName = "sentra-workspace.yaml"
+
+// DefaultPortBase is the first port assigned to mocks whose configured port
+// another project already uses
+const DefaultPortBase = 18080
+
+// Workspace is a set of lab projects.
+type Workspace struct {
+	// Root is the directory containing the workspace file
+	Root string `yaml:"-"`
+
+	// Projects are project directories relative to Root, each with a lab.yaml
+	Projects []string `yaml:"projects"`
+
+	// PortBase is the first port assigned when mock ports collide
+	PortBase int `yaml:"port_base"`
+}
+
+// Project is one lab project of a workspace.
+type Project struct {
+	// Name is the project's directory relative to the workspace root
+	Name string
+
+	// Dir is the project directory
+	Dir string
+
+	// Config is the project's lab.yaml, with mock ports made unique across
+	// the workspace
+	Config *config.Config
+}
+
+// Find returns the workspace file in dir or its nearest ancestor.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in this directory or any parent", FileName)
+		}
+		dir = parent
+	}
+}
+
+// Load reads and validates a workspace file.
+func Load(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	var ws Workspace
+	if err := yaml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace file: %w", err)
+	}
+	ws.Root = filepath.Dir(path)
+
+	if len(ws.Projects) == 0 {
+		return nil, fmt.Errorf("%s: projects is required", path)
+	}
+
+	seen := make(map[string]bool, len(ws.Projects))
+	for i, project := range ws.Projects {
+		if project == "" {
+			return nil, fmt.Errorf("%s: projects[%d] is empty", path, i)
+		}
+		if filepath.IsAbs(project) {
+			return nil, fmt.Errorf("%s: projects[%d]: %s must be relative to the workspace root", path, i, project)
+		}
+
+		cleaned := filepath.Clean(project)
+		if seen[cleaned] {
+			return nil, fmt.Errorf("%s: project %s is listed twice", path, project)
+		}
+		seen[cleaned] = true
+		ws.Projects[i] = cleaned
+	}
+
+	if ws.PortBase == 0 {
+		ws.PortBase = DefaultPortBase
+	}
+	if ws.PortBase < 1024 || ws.PortBase > 65535 {
+		return nil, fmt.Errorf("%s: port_base must be between 1024 and 65535", path)
+	}
+
+	return &ws, nil
+}
+
+// LoadProjects loads each project's lab.yaml and assigns mock ports.
+func (w *Workspace) LoadProjects() ([]*Project, error) {
+	projects := make([]*Project, 0, len(w.Projects))
+	for _, name := range w.Projects {
+		dir := filepath.Join(w.Root, name)
+
+		loader, err := config.NewLoader(filepath.Join(dir, "lab.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", name, err)
+		}
+
+		cfg, err := loader.Load()
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", name, err)
+		}
+
+		projects = append(projects, &Project{Name: name, Dir: dir, Config: cfg})
+	}
+
+	AssignPorts(projects, w.PortBase)
+
+	return projects, nil
+}
+
+// AssignPorts makes enabled mock ports unique across projects. Projects
+// keep their configured ports in workspace order; a mock whose port an
+// earlier project already uses gets the next free port from base.
+func AssignPorts(projects []*Project, base int) {
+	used := make(map[int]bool)
+	next := base
+
+	for _, project := range projects {
+		for _, name := range sortedMocks(project.Config.Mocks) {
+			mock := project.Config.Mocks[name]
+			if !mock.Enabled {
+				continue
+			}
+
+			if used[mock.Port] {
+				for used[next] {
+					next++
+				}
+				mock.Port = next
+				project.Config.Mocks[name] = mock
+			}
+			used[mock.Port] = true
+		}
+	}
+}
+
+// MockPorts returns the ports of the project's enabled mocks.
+func (p *Project) MockPorts() map[string]int {
+	ports := make(map[string]int)
+	for name, mock := range p.Config.Mocks {
+		if mock.Enabled {
+			ports[name] = mock.Port
+		}
+	}
+	return ports
+}
+
+// Scenarios returns the project's scenario files (scenarios/**/*.yaml),
+// sorted.
+func (p *Project) Scenarios() ([]string, error) {
+	dir := filepath.Join(p.Dir, "scenarios")
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("project %s: failed to scan scenarios: %w", p.Name, err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func sortedMocks(mocks map[string]config.MockConfig) []string {
+	names := make([]string, 0, len(mocks))
+	for name := range mocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,200 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/cli/internal/config"
+)
+
+const labYAML = `name: %s
+version: "1.0"
+agent:
+  runtime: python
+  entry_point: agent/main.py
+mocks:
+  openai:
+    enabled: true
+    port: 8080
+  stripe:
+    enabled: true
+    port: 8081
+`
+
+// writeFiles writes files relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{FileName: "projects: [agents/support]\n"})
+	nested := filepath.Join(root, "agents", "support", "scenarios")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Find(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, FileName); path != want {
+		t.Errorf("Find() = %s, want %s", path, want)
+	}
+
+	if _, err := Find(t.TempDir()); err == nil {
+		t.Error("Find() outside a workspace error = nil")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{FileName: "projects:\n  - agents/support/\n  - ./agents/billing\n"})
+
+	ws, err := Load(filepath.Join(root, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Workspace{
+		Root:     root,
+		Projects: []string{filepath.Join("agents", "support"), filepath.Join("agents", "billing")},
+		PortBase: DefaultPortBase,
+	}
+	if !reflect.DeepEqual(ws, want) {
+		t.Errorf("Load() = %+v, want %+v", ws, want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no projects", content: "port_base: 20000\n", wantErr: "projects is required"},
+		{name: "empty project", content: "projects: [a, '']\n", wantErr: "projects[1] is empty"},
+		{name: "absolute project", content: "projects: [/srv/agent]\n", wantErr: "must be relative"},
+		{name: "duplicate project", content: "projects: [a, ./a]\n", wantErr: "listed twice"},
+		{name: "port base", content: "projects: [a]\nport_base: 80\n", wantErr: "port_base"},
+		{name: "invalid YAML", content: "projects: [\n", wantErr: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, map[string]string{FileName: tt.content})
+
+			_, err := Load(filepath.Join(root, FileName))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadProjects(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		FileName:                            "projects: [support, billing]\nport_base: 19000\n",
+		"support/lab.yaml":                  strings.ReplaceAll(labYAML, "%s", "support"),
+		"support/scenarios/refund.yaml":     "name: Refund\n",
+		"support/scenarios/nested/chat.yml": "name: Chat\n",
+		"support/scenarios/data/users.csv":  "name\nalice\n",
+		"billing/lab.yaml":                  strings.ReplaceAll(labYAML, "%s", "billing"),
+	})
+
+	ws, err := Load(filepath.Join(root, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects, err := ws.LoadProjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(projects) != 2 || projects[0].Name != "support" || projects[1].Dir != filepath.Join(root, "billing") {
+		t.Fatalf("LoadProjects() = %+v", projects)
+	}
+	if got, want := projects[0].MockPorts(), map[string]int{"openai": 8080, "stripe": 8081}; !reflect.DeepEqual(got, want) {
+		t.Errorf("support ports = %v, want %v", got, want)
+	}
+	if got, want := projects[1].MockPorts(), map[string]int{"openai": 19000, "stripe": 19001}; !reflect.DeepEqual(got, want) {
+		t.Errorf("billing ports = %v, want %v", got, want)
+	}
+
+	scenarios, err := projects[0].Scenarios()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(root, "support", "scenarios", "nested", "chat.yml"),
+		filepath.Join(root, "support", "scenarios", "refund.yaml"),
+	}
+	if !reflect.DeepEqual(scenarios, want) {
+		t.Errorf("Scenarios() = %v, want %v", scenarios, want)
+	}
+	if scenarios, err := projects[1].Scenarios(); err != nil || scenarios != nil {
+		t.Errorf("Scenarios() without a scenarios directory = %v, %v; want none", scenarios, err)
+	}
+}
+
+func TestLoadProjectsMissingConfig(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{FileName: "projects: [support]\n"})
+
+	ws, err := Load(filepath.Join(root, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws.LoadProjects(); err == nil || !strings.Contains(err.Error(), "project support") {
+		t.Errorf("LoadProjects() error = %v, want project support", err)
+	}
+}
+
+func TestAssignPorts(t *testing.T) {
+	project := func(name string, mocks map[string]config.MockConfig) *Project {
+		return &Project{Name: name, Config: &config.Config{Mocks: mocks}}
+	}
+	projects := []*Project{
+		project("a", map[string]config.MockConfig{
+			"openai": {Enabled: true, Port: 8080},
+			"stripe": {Enabled: true, Port: 18080},
+		}),
+		project("b", map[string]config.MockConfig{
+			"openai": {Enabled: true, Port: 8080},
+			"slack":  {Port: 8080},
+		}),
+		project("c", map[string]config.MockConfig{
+			"openai": {Enabled: true, Port: 8080},
+			"stripe": {Enabled: true, Port: 8090},
+		}),
+	}
+
+	AssignPorts(projects, DefaultPortBase)
+
+	want := []map[string]int{
+		{"openai": 8080, "stripe": 18080},
+		{"openai": 18081},
+		{"openai": 18082, "stripe": 8090},
+	}
+	for i, project := range projects {
+		if got := project.MockPorts(); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("project %s ports = %v, want %v", project.Name, got, want[i])
+		}
+	}
+	if port := projects[1].Config.Mocks["slack"].Port; port != 8080 {
+		t.Errorf("disabled mock port = %d, want it unchanged", port)
+	}
+}
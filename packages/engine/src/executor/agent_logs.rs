@@ -0,0 +1,328 @@
+// packages/engine/src/executor/agent_logs.rs
+//! Agent stdout/stderr capture and log assertions
+//!
+//! Every line the agent writes to stdout or stderr is captured and
+//! attributed to the step running at the time. Lines are recorded as
+//! `agent_log` events, and steps can assert on them, so internal behavior
+//! (retries, fallbacks, warnings) is verifiable and not just API traffic:
+//!
+//! ```yaml
+//! - id: "charge"
+//!   action: agent_request
+//!   input: "Charge the customer"
+//!   expect:
+//!     - agent_logs_contain: "retrying after 429"
+//!     - agent_logs_not_contain: ["Traceback", "panic:"]
+//! ```
+//!
+//! Matching is a case-sensitive substring match against each line the step
+//! produced on either stream. A list requires every entry (for
+//! `agent_logs_contain`) or none of them (for `agent_logs_not_contain`).
+
+use crate::recording::recorder::{Event, EventType};
+use chrono::{DateTime, Utc};
+use parking_lot::Mutex;
+use serde::Serialize;
+use serde_json::{json, Value};
+use std::collections::VecDeque;
+use std::sync::atomic::{AtomicU64, Ordering};
+use std::sync::Arc;
+use thiserror::Error;
+use tokio::io::{AsyncBufReadExt, AsyncRead, BufReader};
+use tokio::task::JoinHandle;
+use tracing::debug;
+
+/// Default number of lines kept per agent
+pub const DEFAULT_LOG_CAPACITY: usize = 10_000;
+
+/// Lines longer than this are truncated
+pub const MAX_LINE_BYTES: usize = 8 * 1024;
+
+/// Lines shown when a log assertion fails
+const FAILURE_CONTEXT_LINES: usize = 5;
+
+/// Expectation keys handled by [`LogAssertion`]
+pub const CONTAIN_KEY: &str = "agent_logs_contain";
+pub const NOT_CONTAIN_KEY: &str = "agent_logs_not_contain";
+
+/// Log assertion errors
+#[derive(Debug, Error)]
+pub enum AgentLogError {
+    #[error("invalid {key}: {reason}")]
+    InvalidExpectation { key: String, reason: String },
+
+    #[error("agent logs{} do not contain \"{missing}\"{}", step_suffix(.step), context(.recent))]
+    Missing {
+        step: Option<String>,
+        missing: String,
+        recent: Vec<String>,
+    },
+
+    #[error("agent logs{} contain \"{pattern}\": {line}", step_suffix(.step))]
+    Unexpected {
+        step: Option<String>,
+        pattern: String,
+        line: String,
+    },
+}
+
+/// Output stream a line was written to
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize)]
+#[serde(rename_all = "lowercase")]
+pub enum LogStream {
+    Stdout,
+    Stderr,
+}
+
+/// One line of agent output
+#[derive(Debug, Clone, Serialize)]
+pub struct AgentLogLine {
+    /// Sequence number (monotonic per capture)
+    pub seq: u64,
+
+    /// Stream the line was written to
+    pub stream: LogStream,
+
+    /// Step running when the line was written (None between steps)
+    pub step: Option<String>,
+
+    /// The line, without its newline
+    pub line: String,
+
+    /// Time the line was captured
+    pub captured_at: DateTime<Utc>,
+}
+
+/// Bounded buffer of agent output lines
+pub struct AgentLogCapture {
+    /// Captured lines, oldest first
+    lines: Mutex<VecDeque<AgentLogLine>>,
+
+    /// Maximum number of lines kept
+    capacity: usize,
+
+    /// Step output is currently attributed to
+    step: Mutex<Option<String>>,
+
+    /// Next sequence number
+    next_seq: AtomicU64,
+
+    /// Evicted line counter
+    evicted: AtomicU64,
+}
+
+impl AgentLogCapture {
+    /// Create a new capture buffer
+    pub fn new(capacity: usize) -> Self {
+        let capacity = capacity.max(1);
+
+        Self {
+            lines: Mutex::new(VecDeque::with_capacity(capacity.min(1024))),
+            capacity,
+            step: Mutex::new(None),
+            next_seq: AtomicU64::new(0),
+            evicted: AtomicU64::new(0),
+        }
+    }
+
+    /// Attribute output from now on to a step
+    pub fn begin_step(&self, step: impl Into<String>) {
+        *self.step.lock() = Some(step.into());
+    }
+
+    /// Stop attributing output to the current step
+    pub fn end_step(&self) {
+        *self.step.lock() = None;
+    }
+
+    /// Record a line of output
+    pub fn record(&self, stream: LogStream, line: &str) -> u64 {
+        let seq = self.next_seq.fetch_add(1, Ordering::Relaxed);
+        let line = line.trim_end_matches(['\r', '\n']);
+
+        let mut truncated = line;
+        if truncated.len() > MAX_LINE_BYTES {
+            let mut end = MAX_LINE_BYTES;
+            while !truncated.is_char_boundary(end) {
+                end -= 1;
+            }
+            truncated = &truncated[..end];
+        }
+
+        let step = self.step.lock().clone();
+        let mut lines = self.lines.lock();
+        if lines.len() == self.capacity {
+            lines.pop_front();
+            self.evicted.fetch_add(1, Ordering::Relaxed);
+        }
+        lines.push_back(AgentLogLine {
+            seq,
+            stream,
+            step,
+            line: truncated.to_string(),
+            captured_at: Utc::now(),
+        });
+
+        seq
+    }
+
+    /// Lines written while a step was running, oldest first
+    pub fn step_lines(&self, step: &str) -> Vec<AgentLogLine> {
+        self.lines
+            .lock()
+            .iter()
+            .filter(|line| line.step.as_deref() == Some(step))
+            .cloned()
+            .collect()
+    }
+
+    /// Snapshot of all captured lines, oldest first
+    pub fn snapshot(&self) -> Vec<AgentLogLine> {
+        self.lines.lock().iter().cloned().collect()
+    }
+
+    /// Lines evicted because the buffer was full
+    pub fn evicted(&self) -> u64 {
+        self.evicted.load(Ordering::Relaxed)
+    }
+
+    /// Discard all captured lines (between simulations)
+    pub fn clear(&self) {
+        self.lines.lock().clear();
+        *self.step.lock() = None;
+    }
+
+    /// Convert the captured lines into `agent_log` recording events
+    pub fn events(&self, run_id: &str) -> Vec<Event> {
+        self.snapshot()
+            .into_iter()
+            .map(|line| Event {
+                id: format!("{}-log-{}", run_id, line.seq),
+                run_id: run_id.to_string(),
+                event_type: EventType::AgentLog,
+                timestamp_ns: line.captured_at.timestamp_nanos_opt().unwrap_or_default().max(0) as u64,
+                data: json!({
+                    "stream": line.stream,
+                    "step": line.step,
+                    "line": line.line,
+                }),
+                duration_us: None,
+            })
+            .collect()
+    }
+}
+
+impl Default for AgentLogCapture {
+    fn default() -> Self {
+        Self::new(DEFAULT_LOG_CAPACITY)
+    }
+}
+
+/// Read lines from an agent output stream into a capture until EOF
+pub fn spawn_reader<R>(reader: R, stream: LogStream, capture: Arc<AgentLogCapture>) -> JoinHandle<()>
+where
+    R: AsyncRead + Unpin + Send + 'static,
+{
+    tokio::spawn(async move {
+        let mut lines = BufReader::new(reader).lines();
+        loop {
+            match lines.next_line().await {
+                Ok(Some(line)) => {
+                    capture.record(stream, &line);
+                }
+                Ok(None) => break,
+                Err(e) => {
+                    debug!("Stopped reading agent {:?}: {}", stream, e);
+                    break;
+                }
+            }
+        }
+    })
+}
+
+/// A log expectation from a step's `expect:` list
+#[derive(Debug, Clone, PartialEq)]
+pub enum LogAssertion {
+    /// Every pattern appears in some line
+    Contains(Vec<String>),
+
+    /// No pattern appears in any line
+    NotContains(Vec<String>),
+}
+
+impl LogAssertion {
+    /// Parse an expectation, or return `None` if it is not a log assertion
+    pub fn from_expectation(key: &str, value: &Value) -> Option<Result<Self, AgentLogError>> {
+        let build: fn(Vec<String>) -> Self = match key {
+            CONTAIN_KEY => Self::Contains,
+            NOT_CONTAIN_KEY => Self::NotContains,
+            _ => return None,
+        };
+
+        let invalid = |reason: &str| AgentLogError::InvalidExpectation {
+            key: key.to_string(),
+            reason: reason.to_string(),
+        };
+
+        let patterns = match value {
+            Value::String(pattern) => vec![pattern.clone()],
+            Value::Array(items) => match items
+                .iter()
+                .map(|item| item.as_str().map(str::to_string))
+                .collect::<Option<Vec<_>>>()
+            {
+                Some(patterns) => patterns,
+                None => return Some(Err(invalid("expected a string or a list of strings"))),
+            },
+            _ => return Some(Err(invalid("expected a string or a list of strings"))),
+        };
+
+        if patterns.is_empty() || patterns.iter().any(String::is_empty) {
+            return Some(Err(invalid("patterns cannot be empty")));
+        }
+
+        Some(Ok(build(patterns)))
+    }
+
+    /// Check the assertion against a step's lines
+    pub fn check(&self, step: Option<&str>, lines: &[AgentLogLine]) -> Result<(), AgentLogError> {
+        match self {
+            Self::Contains(patterns) => {
+                for pattern in patterns {
+                    if !lines.iter().any(|line| line.line.contains(pattern.as_str())) {
+                        let skip = lines.len().saturating_sub(FAILURE_CONTEXT_LINES);
+                        return Err(AgentLogError::Missing {
+                            step: step.map(str::to_string),
+                            missing: pattern.clone(),
+                            recent: lines[skip..].iter().map(|line| line.line.clone()).collect(),
+                        });
+                    }
+                }
+            }
+            Self::NotContains(patterns) => {
+                for pattern in patterns {
+                    if let Some(line) = lines.iter().find(|line| line.line.contains(pattern.as_str())) {
+                        return Err(AgentLogError::Unexpected {
+                            step: step.map(str::to_string),
+                            pattern: pattern.clone(),
+                            line: line.line.clone(),
+                        });
+                    }
+                }
+            }
+        }
+
+        Ok(())
+    }
+}
+
+fn step_suffix(step: &Option<String>) -> String {
+    step.as_ref().map(|s| format!(" of step '{}'", s)).unwrap_or_default()
+}
+
+fn context(recent: &[String]) -> String {
+    if recent.is_empty() {
+        return " (the step wrote no output)".to_string();
+    }
+    format!("; last lines: {}", recent.join(" | "))
+}
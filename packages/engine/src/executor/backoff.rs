@@ -0,0 +1,345 @@
+// packages/engine/src/executor/backoff.rs
+//! Rate-limit backoff assertions
+//!
+//! `backoff_strategy` checks how the agent retried the requests a provider
+//! rejected, by measuring the gaps between attempts in the step's captured
+//! exchanges:
+//!
+//! ```yaml
+//! - id: "burst"
+//!   action: agent_request
+//!   input: "Summarize the last 20 tickets"
+//!   expect:
+//!     - backoff_strategy: exponential
+//! ```
+//!
+//! A retry is the same request (method, path, query and body) sent again
+//! after a 429 or 5xx. Its wait runs from the end of the failed response to
+//! the retry, and three checks apply to every retried request:
+//!
+//! - **retry_after**: each wait is at least what the failed response asked
+//!   for (`retry-after-ms`, `Retry-After`, or the `x-ratelimit-reset-*`
+//!   header of the limit that was hit)
+//! - **growth**: waits grow by at least [`MIN_GROWTH`]× per retry on average
+//! - **jitter**: with three or more waits, they do not all grow by the same
+//!   factor, so a fleet of agents does not retry in lockstep
+//!
+//! Waits are compared in mock time, so steps that advance the virtual clock
+//! are measured the way the agent experienced them.
+
+use crate::executor::duration::parse_duration;
+use crate::executor::trace_capture::CapturedExchange;
+use serde::Serialize;
+use serde_json::Value;
+use std::collections::HashMap;
+use std::fmt;
+use thiserror::Error;
+
+/// Expectation key handled by [`BackoffAssertion`]
+pub const BACKOFF_KEY: &str = "backoff_strategy";
+
+/// Smallest average growth between consecutive waits that counts as
+/// exponential backoff (2× minus room for jitter)
+pub const MIN_GROWTH: f64 = 1.5;
+
+/// Waits whose growth factors all lie within this fraction of each other
+/// have no jitter
+const JITTER_TOLERANCE: f64 = 0.02;
+
+/// Waits needed before jitter can be told apart from a fixed schedule
+const JITTER_MIN_WAITS: usize = 3;
+
+/// Backoff assertion errors
+#[derive(Debug, Error)]
+pub enum BackoffError {
+    #[error("invalid backoff_strategy: {0}")]
+    InvalidExpectation(String),
+
+    #[error("{} backoff violation(s): {}", .0.len(), .0.iter().map(|f| f.to_string()).collect::<Vec<_>>().join("; "))]
+    Violated(Vec<BackoffFinding>),
+}
+
+/// Retry strategy a step expects
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize)]
+#[serde(rename_all = "snake_case")]
+pub enum BackoffStrategy {
+    /// Waits that double (roughly) per retry, with jitter
+    Exponential,
+}
+
+impl fmt::Display for BackoffStrategy {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(match self {
+            Self::Exponential => "exponential",
+        })
+    }
+}
+
+/// Backoff check that produced a finding
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize)]
+#[serde(rename_all = "snake_case")]
+pub enum BackoffCheck {
+    RetryAfter,
+    Growth,
+    Jitter,
+}
+
+impl fmt::Display for BackoffCheck {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(match self {
+            Self::RetryAfter => "retry_after",
+            Self::Growth => "growth",
+            Self::Jitter => "jitter",
+        })
+    }
+}
+
+/// A backoff violation
+#[derive(Debug, Clone, PartialEq, Serialize)]
+pub struct BackoffFinding {
+    /// Check that fired
+    pub check: BackoffCheck,
+
+    /// Retried request (e.g., "POST /v1/chat/completions")
+    pub request: String,
+
+    /// What was observed
+    pub detail: String,
+}
+
+impl fmt::Display for BackoffFinding {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        write!(f, "{} {}: {}", self.check, self.request, self.detail)
+    }
+}
+
+/// The gap before one retry
+#[derive(Debug, Clone, PartialEq, Serialize)]
+pub struct RetryWait {
+    /// Status of the failed attempt
+    pub status: u16,
+
+    /// Time from the failed response to the retry
+    pub wait_ms: i64,
+
+    /// Wait the failed response asked for, if any
+    pub required_ms: Option<i64>,
+}
+
+/// A compiled `backoff_strategy` expectation
+#[derive(Debug, Clone, PartialEq)]
+pub struct BackoffAssertion {
+    /// Expected strategy
+    pub strategy: BackoffStrategy,
+}
+
+impl BackoffAssertion {
+    /// Parse an expectation, or return `None` if it is not a backoff assertion
+    pub fn from_expectation(key: &str, value: &Value) -> Option<Result<Self, BackoffError>> {
+        if key != BACKOFF_KEY {
+            return None;
+        }
+
+        let strategy = match value.as_str() {
+            Some("exponential") => BackoffStrategy::Exponential,
+            Some(other) => {
+                return Some(Err(BackoffError::InvalidExpectation(format!(
+                    "unknown strategy '{}' (supported: exponential)",
+                    other
+                ))))
+            }
+            None => return Some(Err(BackoffError::InvalidExpectation("expected a strategy name".into()))),
+        };
+
+        Some(Ok(Self { strategy }))
+    }
+
+    /// Run every check against a step's exchanges, returning all findings
+    pub fn check(&self, exchanges: &[CapturedExchange]) -> Vec<BackoffFinding> {
+        let mut findings = Vec::new();
+
+        for chain in retry_chains(exchanges) {
+            let request = format!("{} {}", chain[0].method, chain[0].path);
+            let waits = retry_waits(&chain);
+
+            for (i, wait) in waits.iter().enumerate() {
+                if let Some(required) = wait.required_ms.filter(|required| wait.wait_ms < *required) {
+                    findings.push(BackoffFinding {
+                        check: BackoffCheck::RetryAfter,
+                        request: request.clone(),
+                        detail: format!(
+                            "retry {} came {}ms after a {}, which asked for {}ms",
+                            i + 1,
+                            wait.wait_ms,
+                            wait.status,
+                            required
+                        ),
+                    });
+                }
+            }
+
+            if waits.len() < 2 {
+                continue;
+            }
+
+            let factors = growth_factors(&waits);
+            let growth = factors.iter().product::<f64>().powf(1.0 / factors.len() as f64);
+            if growth < MIN_GROWTH {
+                findings.push(BackoffFinding {
+                    check: BackoffCheck::Growth,
+                    request: request.clone(),
+                    detail: format!(
+                        "waits {} grow {:.2}× per retry, under {}×",
+                        format_waits(&waits),
+                        growth,
+                        MIN_GROWTH
+                    ),
+                });
+                continue;
+            }
+
+            if waits.len() >= JITTER_MIN_WAITS
+                && factors.iter().all(|factor| (factor - factors[0]).abs() <= factors[0] * JITTER_TOLERANCE)
+            {
+                findings.push(BackoffFinding {
+                    check: BackoffCheck::Jitter,
+                    request,
+                    detail: format!(
+                        "waits {} all grow exactly {:.2}× per retry (no jitter)",
+                        format_waits(&waits),
+                        factors[0]
+                    ),
+                });
+            }
+        }
+
+        findings
+    }
+
+    /// Run every check, failing on any finding
+    pub fn assert(&self, exchanges: &[CapturedExchange]) -> Result<(), BackoffError> {
+        let findings = self.check(exchanges);
+        if findings.is_empty() {
+            Ok(())
+        } else {
+            Err(BackoffError::Violated(findings))
+        }
+    }
+}
+
+/// Whether a provider rejected a request in a way that warrants a retry
+fn failed(exchange: &CapturedExchange) -> bool {
+    exchange.status == 429 || exchange.status >= 500
+}
+
+/// Group exchanges into retried requests: each chain starts with a failed
+/// attempt and continues with the same request sent again, until one
+/// succeeds. Chains without a retry are dropped.
+pub fn retry_chains(exchanges: &[CapturedExchange]) -> Vec<Vec<&CapturedExchange>> {
+    let mut sorted: Vec<&CapturedExchange> = exchanges.iter().collect();
+    sorted.sort_by_key(|exchange| (exchange.started_at, exchange.seq));
+
+    // Requests whose last attempt failed, so the same request sent next is a retry
+    let mut open: HashMap<(&str, &str, &str, &str), usize> = HashMap::new();
+    let mut chains: Vec<Vec<&CapturedExchange>> = Vec::new();
+
+    for exchange in sorted {
+        let key = (
+            exchange.method.as_str(),
+            exchange.path.as_str(),
+            exchange.query.as_str(),
+            exchange.request_body.as_str(),
+        );
+        match open.get(&key) {
+            Some(&index) => chains[index].push(exchange),
+            None if failed(exchange) => {
+                open.insert(key, chains.len());
+                chains.push(vec![exchange]);
+                continue;
+            }
+            None => continue,
+        }
+        if !failed(exchange) {
+            open.remove(&key);
+        }
+    }
+
+    chains.retain(|chain| chain.len() > 1);
+    chains
+}
+
+/// Measure the waits before each retry of a chain
+pub fn retry_waits(chain: &[&CapturedExchange]) -> Vec<RetryWait> {
+    chain
+        .windows(2)
+        .map(|pair| {
+            let (failed, retry) = (pair[0], pair[1]);
+            RetryWait {
+                status: failed.status,
+                wait_ms: retry.started_at - (failed.started_at + failed.duration_ms as i64),
+                required_ms: required_wait_ms(failed),
+            }
+        })
+        .collect()
+}
+
+/// The wait a failed response asked for: `retry-after-ms`, else the reset
+/// time of the limit the mock reports was hit, else `Retry-After` (seconds)
+fn required_wait_ms(exchange: &CapturedExchange) -> Option<i64> {
+    let header = |name: &str| {
+        exchange
+            .response_headers
+            .iter()
+            .find(|(key, _)| key.eq_ignore_ascii_case(name))
+            .map(|(_, value)| value.trim())
+    };
+
+    if let Some(ms) = header("retry-after-ms").and_then(|value| value.parse::<f64>().ok()) {
+        return Some(ms.ceil() as i64);
+    }
+    if let Some(reset) = header("x-sentra-limiting-factor")
+        .and_then(|factor| header(&format!("x-ratelimit-reset-{}", factor)))
+        .and_then(parse_reset)
+    {
+        return Some(reset);
+    }
+    header("retry-after")
+        .and_then(|value| value.parse::<u64>().ok())
+        .map(|seconds| seconds as i64 * 1000)
+}
+
+/// Parse a rate limit reset time such as "1.23s", "20ms" or "6m0s" into
+/// milliseconds
+fn parse_reset(raw: &str) -> Option<i64> {
+    if raw.is_empty() {
+        return None;
+    }
+
+    let mut total = 0.0;
+    let mut rest = raw;
+    while !rest.is_empty() {
+        let unit_start = rest.find(|c: char| !c.is_ascii_digit() && c != '.')?;
+        let unit_end = rest[unit_start..]
+            .find(|c: char| c.is_ascii_digit())
+            .map_or(rest.len(), |end| unit_start + end);
+        total += parse_duration(&rest[..unit_end])?.as_secs_f64();
+        rest = &rest[unit_end..];
+    }
+    Some((total * 1000.0).ceil() as i64)
+}
+
+/// Growth factor of each wait over the one before it
+fn growth_factors(waits: &[RetryWait]) -> Vec<f64> {
+    waits
+        .windows(2)
+        .map(|pair| pair[1].wait_ms.max(1) as f64 / pair[0].wait_ms.max(1) as f64)
+        .collect()
+}
+
+/// List waits for a finding (e.g., "[1000ms, 1000ms, 1000ms]")
+fn format_waits(waits: &[RetryWait]) -> String {
+    format!(
+        "[{}]",
+        waits.iter().map(|wait| format!("{}ms", wait.wait_ms)).collect::<Vec<_>>().join(", ")
+    )
+}
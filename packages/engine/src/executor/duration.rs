@@ -0,0 +1,30 @@
+// packages/engine/src/executor/duration.rs
+//! Duration parsing for scenario step fields (`timeout: 5s`, `duration: 500ms`)
+
+use std::time::Duration;
+
+/// Parse a duration such as "500ms", "5s", "1m" or "2h" (bare numbers are
+/// seconds). Returns `None` for malformed or negative values.
+pub fn parse_duration(raw: &str) -> Option<Duration> {
+    let raw = raw.trim();
+
+    let (number, unit) = match raw.find(|c: char| !c.is_ascii_digit() && c != '.') {
+        Some(pos) => raw.split_at(pos),
+        None => (raw, "s"),
+    };
+    let number: f64 = number.parse().ok()?;
+
+    let seconds = match unit {
+        "ms" => number / 1000.0,
+        "s" => number,
+        "m" => number * 60.0,
+        "h" => number * 3600.0,
+        _ => return None,
+    };
+
+    if !seconds.is_finite() || seconds < 0.0 {
+        return None;
+    }
+
+    Some(Duration::from_secs_f64(seconds))
+}
@@ -0,0 +1,170 @@
+// packages/engine/src/executor/fault_steps.rs
+//! `inject_latency` and `clear_faults` scenario steps
+//!
+//! Both steps drive the mocks' fault injection admin API (`/_sentra/faults`),
+//! so degradation and recovery can be scripted mid-scenario:
+//!
+//! ```yaml
+//! - action: inject_latency
+//!   service: openai
+//!   model: gpt-4o        # optional; omit for every model ("gpt-4*" for a family)
+//!   latency: 2s          # added to each request (first chunk when streaming)
+//!   multiplier: 3.0      # optional; scales simulated latency
+//!   duration: 30s        # optional; lifted automatically afterwards
+//!
+//! - action: clear_faults
+//!   service: openai      # optional; omit to clear every registered mock
+//! ```
+
+use crate::executor::duration::parse_duration;
+use crate::executor::mock_admin::{MockAdminClient, MockAdminError};
+use hyper::Method;
+use serde::Deserialize;
+use serde_json::json;
+use std::time::Duration;
+use tracing::info;
+
+/// Admin API path for faults on every mock
+const FAULTS_PATH: &str = "/_sentra/faults";
+
+/// `inject_latency` step definition as written in scenario YAML
+#[derive(Debug, Clone, Deserialize)]
+pub struct InjectLatencySpec {
+    /// Mock service to degrade (e.g., "openai")
+    pub service: String,
+
+    /// Model ID or prefix ending in "*"; all models when omitted
+    #[serde(default)]
+    pub model: Option<String>,
+
+    /// Delay added to each request (e.g., "2s")
+    #[serde(default)]
+    pub latency: Option<String>,
+
+    /// Multiplier applied to simulated latency
+    #[serde(default)]
+    pub multiplier: Option<f64>,
+
+    /// How long the fault lasts (e.g., "30s"); until cleared when omitted
+    #[serde(default)]
+    pub duration: Option<String>,
+}
+
+/// A compiled `inject_latency` step
+#[derive(Debug, Clone)]
+pub struct InjectLatencyStep {
+    /// Mock service to degrade
+    pub service: String,
+
+    /// Model ID or prefix ending in "*" (empty = all models)
+    pub model: String,
+
+    /// Delay added to each request
+    pub added: Duration,
+
+    /// Multiplier applied to simulated latency (0 = unchanged)
+    pub multiplier: f64,
+
+    /// How long the fault lasts (None = until cleared)
+    pub duration: Option<Duration>,
+}
+
+impl InjectLatencyStep {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: InjectLatencySpec) -> Result<Self, MockAdminError> {
+        if spec.service.is_empty() {
+            return Err(MockAdminError::InvalidStep("service is required".into()));
+        }
+
+        let parse = |field: &str, raw: &str| {
+            parse_duration(raw).ok_or_else(|| {
+                MockAdminError::InvalidStep(format!("invalid {} '{}': expected e.g. \"500ms\" or \"2s\"", field, raw))
+            })
+        };
+
+        let added = match spec.latency.as_deref() {
+            Some(raw) => parse("latency", raw)?,
+            None => Duration::ZERO,
+        };
+        let duration = spec.duration.as_deref().map(|raw| parse("duration", raw)).transpose()?;
+        let multiplier = spec.multiplier.unwrap_or(0.0);
+
+        if multiplier < 0.0 {
+            return Err(MockAdminError::InvalidStep("multiplier cannot be negative".into()));
+        }
+        if added.is_zero() && multiplier == 0.0 {
+            return Err(MockAdminError::InvalidStep("latency or multiplier is required".into()));
+        }
+
+        Ok(Self {
+            service: spec.service,
+            model: spec.model.unwrap_or_default(),
+            added,
+            multiplier,
+            duration,
+        })
+    }
+
+    /// Apply the fault through the mock admin API
+    pub async fn execute(&self, client: &MockAdminClient) -> Result<(), MockAdminError> {
+        let body = json!({
+            "model": self.model,
+            "added_ms": self.added.as_millis() as u64,
+            "multiplier": self.multiplier,
+            "duration_ms": self.duration.map(|d| d.as_millis() as u64).unwrap_or(0),
+        });
+
+        client
+            .send(&self.service, Method::POST, &format!("{}/latency", FAULTS_PATH), Some(body))
+            .await?;
+
+        info!(
+            "Injected latency on {} (model: {}, added: {:?}, multiplier: {})",
+            self.service,
+            if self.model.is_empty() { "*" } else { &self.model },
+            self.added,
+            self.multiplier
+        );
+
+        Ok(())
+    }
+}
+
+/// `clear_faults` step definition as written in scenario YAML
+#[derive(Debug, Clone, Default, Deserialize)]
+pub struct ClearFaultsSpec {
+    /// Mock service to restore; every registered mock when omitted
+    #[serde(default)]
+    pub service: Option<String>,
+}
+
+/// A compiled `clear_faults` step
+#[derive(Debug, Clone, Default)]
+pub struct ClearFaultsStep {
+    /// Mock service to restore (None = all)
+    pub service: Option<String>,
+}
+
+impl ClearFaultsStep {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: ClearFaultsSpec) -> Self {
+        Self {
+            service: spec.service.filter(|s| !s.is_empty()),
+        }
+    }
+
+    /// Clear injected latency and restore error injection defaults
+    pub async fn execute(&self, client: &MockAdminClient) -> Result<(), MockAdminError> {
+        let services = match &self.service {
+            Some(service) => vec![service.clone()],
+            None => client.services(),
+        };
+
+        for service in &services {
+            client.send(service, Method::DELETE, FAULTS_PATH, None).await?;
+            info!("Cleared faults on {}", service);
+        }
+
+        Ok(())
+    }
+}
@@ -0,0 +1,138 @@
+// packages/engine/src/executor/mock_admin.rs
+//! Client for the mocks' admin API (`/_sentra/...`)
+//!
+//! Scenario steps that reconfigure mocks mid-run (fault injection,
+//! scenario-scoped overrides) go through this client.
+
+use bytes::Bytes;
+use http_body_util::{BodyExt, Full};
+use hyper::{Method, Request, StatusCode};
+use hyper_util::client::legacy::connect::HttpConnector;
+use hyper_util::client::legacy::Client;
+use std::collections::HashMap;
+use thiserror::Error;
+use tracing::debug;
+
+/// Mock admin step errors
+#[derive(Debug, Error)]
+pub enum MockAdminError {
+    #[error("invalid step: {0}")]
+    InvalidStep(String),
+
+    #[error("unknown service '{0}': no mock admin URL registered")]
+    UnknownService(String),
+
+    #[error("mock admin request to {service} failed: {reason}")]
+    RequestFailed { service: String, reason: String },
+
+    #[error("mock admin API for {service} returned {status}: {body}")]
+    Rejected {
+        service: String,
+        status: StatusCode,
+        body: String,
+    },
+}
+
+/// Client for the mocks' admin API, keyed by service name
+pub struct MockAdminClient {
+    /// Service name -> mock base URL (e.g., "openai" -> "http://127.0.0.1:8080")
+    services: HashMap<String, String>,
+
+    /// HTTP client
+    http_client: Client<HttpConnector, Full<Bytes>>,
+}
+
+impl MockAdminClient {
+    /// Create a client for the given services
+    pub fn new(services: HashMap<String, String>) -> Self {
+        let http_client = Client::builder(hyper_util::rt::TokioExecutor::new()).build_http();
+
+        Self {
+            services,
+            http_client,
+        }
+    }
+
+    /// Registered service names, sorted
+    pub fn services(&self) -> Vec<String> {
+        let mut services: Vec<String> = self.services.keys().cloned().collect();
+        services.sort();
+        services
+    }
+
+    /// Send an admin request and return the response body
+    pub(crate) async fn send(
+        &self,
+        service: &str,
+        method: Method,
+        path: &str,
+        body: Option<serde_json::Value>,
+    ) -> Result<Bytes, MockAdminError> {
+        self.send_with_headers(service, method, path, body, &[]).await
+    }
+
+    /// Send a request with extra headers (e.g., the API key a mock's public
+    /// API expects) and return the response body
+    pub(crate) async fn send_with_headers(
+        &self,
+        service: &str,
+        method: Method,
+        path: &str,
+        body: Option<serde_json::Value>,
+        headers: &[(&str, &str)],
+    ) -> Result<Bytes, MockAdminError> {
+        let base_url = self
+            .services
+            .get(service)
+            .ok_or_else(|| MockAdminError::UnknownService(service.to_string()))?;
+
+        let failed = |reason: String| MockAdminError::RequestFailed {
+            service: service.to_string(),
+            reason,
+        };
+
+        let body = match body {
+            Some(value) => Bytes::from(value.to_string()),
+            None => Bytes::new(),
+        };
+
+        let uri = format!("{}{}", base_url.trim_end_matches('/'), path);
+        debug!("Mock admin request: {} {}", method, uri);
+
+        let mut builder = Request::builder()
+            .method(method)
+            .uri(uri)
+            .header("content-type", "application/json");
+        for (name, value) in headers {
+            builder = builder.header(*name, *value);
+        }
+
+        let req = builder
+            .body(Full::new(body))
+            .map_err(|e| failed(e.to_string()))?;
+
+        let response = self
+            .http_client
+            .request(req)
+            .await
+            .map_err(|e| failed(e.to_string()))?;
+
+        let status = response.status();
+        let body = response
+            .into_body()
+            .collect()
+            .await
+            .map_err(|e| failed(e.to_string()))?
+            .to_bytes();
+
+        if !status.is_success() {
+            return Err(MockAdminError::Rejected {
+                service: service.to_string(),
+                status,
+                body: String::from_utf8_lossy(&body).into_owned(),
+            });
+        }
+
+        Ok(body)
+    }
+}
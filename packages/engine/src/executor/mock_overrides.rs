@@ -0,0 +1,85 @@
+// packages/engine/src/executor/mock_overrides.rs
+//! Scenario-scoped mock configuration overrides
+//!
+//! A scenario's `mocks:` block is applied through each mock's admin API
+//! (`PUT /_sentra/overrides`) when the scenario starts and reverted
+//! (`DELETE /_sentra/overrides`) when it ends, pass or fail:
+//!
+//! ```yaml
+//! mocks:
+//!   openai:
+//!     error_rate: 0.2
+//!     rate_limit:
+//!       tier: free
+//! ```
+//!
+//! Fields are passed through to the mock, which rejects unknown ones.
+
+use crate::executor::mock_admin::{MockAdminClient, MockAdminError};
+use hyper::Method;
+use serde::Deserialize;
+use serde_json::{Map, Value};
+use std::collections::BTreeMap;
+use tracing::{info, warn};
+
+/// Admin API path for overrides on every mock
+const OVERRIDES_PATH: &str = "/_sentra/overrides";
+
+/// `mocks:` block as written in scenario YAML (service -> overrides)
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(transparent)]
+pub struct MockOverridesSpec(pub BTreeMap<String, Map<String, Value>>);
+
+/// Overrides for one scenario run, tracking which services were changed
+#[derive(Debug, Default)]
+pub struct ScenarioOverrides {
+    /// Service -> overrides
+    overrides: BTreeMap<String, Map<String, Value>>,
+
+    /// Services whose overrides are currently applied
+    applied: Vec<String>,
+}
+
+impl ScenarioOverrides {
+    /// Create overrides from a scenario's `mocks:` block
+    pub fn new(spec: MockOverridesSpec) -> Self {
+        Self {
+            overrides: spec.0.into_iter().filter(|(_, fields)| !fields.is_empty()).collect(),
+            applied: Vec::new(),
+        }
+    }
+
+    /// Returns true if the scenario declares no overrides
+    pub fn is_empty(&self) -> bool {
+        self.overrides.is_empty()
+    }
+
+    /// Apply overrides to every listed service. If one service rejects its
+    /// overrides, those already applied are reverted before returning.
+    pub async fn apply(&mut self, client: &MockAdminClient) -> Result<(), MockAdminError> {
+        for (service, fields) in &self.overrides {
+            let body = Value::Object(fields.clone());
+
+            if let Err(e) = client.send(service, Method::PUT, OVERRIDES_PATH, Some(body)).await {
+                self.revert(client).await;
+                return Err(e);
+            }
+
+            info!("Applied scenario overrides on {}: {:?}", service, fields.keys().collect::<Vec<_>>());
+            self.applied.push(service.clone());
+        }
+
+        Ok(())
+    }
+
+    /// Revert every applied override. Failures are logged, not returned, so
+    /// one unreachable mock does not leave the others overridden.
+    pub async fn revert(&mut self, client: &MockAdminClient) {
+        for service in self.applied.drain(..) {
+            match client.send(&service, Method::DELETE, OVERRIDES_PATH, None).await {
+                Ok(_) => info!("Reverted scenario overrides on {}", service),
+                Err(e) => warn!("Failed to revert scenario overrides on {}: {}", service, e),
+            }
+        }
+    }
+}
@@ -0,0 +1,54 @@
+// packages/engine/src/executor/mod.rs
+//! Scenario orchestration and execution
+//!
+//! - **Agent Logs**: Per-step agent stdout/stderr capture and `agent_logs_contain` assertions
+//! - **Webhook Capture**: Bounded buffer of webhooks sent by mock services
+//! - **Verify Webhook**: `verify_webhook` step with timeout and JSONPath matchers
+//! - **Verify Reconciliation**: `verify_reconciliation` payments-vs-ledger check
+//! - **Mock Admin**: Client for the mocks' admin API
+//! - **Fault Steps**: `inject_latency` and `clear_faults` via the mocks' admin API
+//! - **Network Faults**: `network_faults` drops, DNS failures and stalls at the egress proxy
+//! - **Mock Overrides**: Scenario-scoped `mocks:` configuration overrides
+//! - **Virtual Clock**: `advance_clock` and `schedule` steps
+//! - **Fuzz**: `fuzz:` scenarios with generated inputs, invariants and seed replay
+//! - **Guardrails**: `assert_guardrails` secret leak, tool and prompt injection checks
+//! - **Provider Failover**: `assert_routing` provider switch time and latency SLO checks
+//! - **Backoff**: `backoff_strategy` Retry-After, exponential growth and jitter checks on retries
+//! - **Trace Capture**: Full HTTP exchanges from every mock for `record_full_trace`
+
+pub mod agent_logs;
+pub mod backoff;
+pub mod duration;
+pub mod fault_steps;
+pub mod fuzz;
+pub mod guardrails;
+pub mod mock_admin;
+pub mod mock_overrides;
+pub mod network_faults;
+pub mod provider_failover;
+pub mod trace_capture;
+pub mod verify_reconciliation;
+pub mod verify_webhook;
+pub mod virtual_clock;
+pub mod webhook_capture;
+
+// Re-export commonly used types
+pub use agent_logs::{AgentLogCapture, AgentLogError, AgentLogLine, LogAssertion, LogStream};
+pub use backoff::{BackoffAssertion, BackoffCheck, BackoffError, BackoffFinding, BackoffStrategy, RetryWait};
+pub use fault_steps::{ClearFaultsStep, InjectLatencyStep};
+pub use fuzz::{AgentOutcome, FuzzCase, FuzzError, FuzzReport, FuzzRunner, FuzzSpec, ToolCall};
+pub use guardrails::{AgentTurn, GuardrailCheck, GuardrailError, GuardrailFinding, GuardrailSpec, Guardrails};
+pub use mock_admin::{MockAdminClient, MockAdminError};
+pub use mock_overrides::{MockOverridesSpec, ScenarioOverrides};
+pub use network_faults::{NetworkFaultsSpec, NetworkFaultsStep};
+pub use provider_failover::{
+    ProviderCall, RoutingAssertion, RoutingCheck, RoutingError, RoutingFinding, RoutingSpec, RoutingTrace,
+};
+pub use trace_capture::{CapturedExchange, FullTraceCapture};
+pub use verify_reconciliation::{
+    ReconciliationFinding, ReconciliationReport, VerifyReconciliationError, VerifyReconciliationSpec,
+    VerifyReconciliationStep,
+};
+pub use verify_webhook::{JsonPath, PayloadMatcher, VerifyWebhookError, VerifyWebhookSpec, VerifyWebhookStep};
+pub use virtual_clock::{AdvanceClockStep, ScheduledStep, VirtualClock};
+pub use webhook_capture::{CaptureStats, CapturedWebhook, WebhookCapture};
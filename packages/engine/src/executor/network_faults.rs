@@ -0,0 +1,156 @@
+// packages/engine/src/executor/network_faults.rs
+//! `network_faults` scenario step
+//!
+//! Injects client-facing network faults at the egress proxy, which every
+//! agent connection goes through when `egress.enabled` is set. Unlike mock
+//! latency and errors, these hit the agent's HTTP client: connections are
+//! dropped without a response, host lookups fail, and requests stall:
+//!
+//! ```yaml
+//! - action: network_faults
+//!   drop_rate: 0.2                 # share of connections closed unanswered
+//!   dns_failures: [api.stripe.com] # lookups fail ("*.example.com" for subdomains)
+//!   stall_rate: 0.1                # share of requests that hang...
+//!   stall: 30s                     # ...this long before being forwarded
+//!   hosts: [api.openai.com]        # optional; drops and stalls only hit these
+//!
+//! - action: network_faults
+//!   clear: true                    # back to lab.yaml's egress.faults
+//! ```
+//!
+//! Each step replaces the faults set by the previous one. The proxy restores
+//! `egress.faults` when mock state is reset between scenarios. The proxy is
+//! registered with the mock admin client as the `egress` service.
+
+use crate::executor::duration::parse_duration;
+use crate::executor::mock_admin::{MockAdminClient, MockAdminError};
+use hyper::Method;
+use serde::Deserialize;
+use serde_json::json;
+use std::time::Duration;
+use tracing::info;
+
+/// Mock admin service name of the egress proxy
+pub const EGRESS_SERVICE: &str = "egress";
+
+/// Admin API path for network faults on the egress proxy
+const NETWORK_FAULTS_PATH: &str = "/_sentra/network-faults";
+
+/// Default stall when `stall_rate` is set without `stall`
+const DEFAULT_STALL: Duration = Duration::from_secs(30);
+
+/// `network_faults` step definition as written in scenario YAML
+#[derive(Debug, Clone, Default, Deserialize)]
+pub struct NetworkFaultsSpec {
+    /// Share of connections dropped (0-1)
+    #[serde(default)]
+    pub drop_rate: f64,
+
+    /// Hosts whose lookup fails
+    #[serde(default)]
+    pub dns_failures: Vec<String>,
+
+    /// Share of requests stalled (0-1)
+    #[serde(default)]
+    pub stall_rate: f64,
+
+    /// How long a stalled request hangs (e.g., "30s")
+    #[serde(default)]
+    pub stall: Option<String>,
+
+    /// Hosts drops and stalls apply to; every host when empty
+    #[serde(default)]
+    pub hosts: Vec<String>,
+
+    /// Restore the configured faults instead of setting new ones
+    #[serde(default)]
+    pub clear: bool,
+}
+
+/// A compiled `network_faults` step
+#[derive(Debug, Clone)]
+pub enum NetworkFaultsStep {
+    /// Replace the active faults
+    Set {
+        drop_rate: f64,
+        dns_failures: Vec<String>,
+        stall_rate: f64,
+        stall: Duration,
+        hosts: Vec<String>,
+    },
+
+    /// Restore `egress.faults`
+    Clear,
+}
+
+impl NetworkFaultsStep {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: NetworkFaultsSpec) -> Result<Self, MockAdminError> {
+        if spec.clear {
+            return Ok(Self::Clear);
+        }
+
+        for (field, rate) in [("drop_rate", spec.drop_rate), ("stall_rate", spec.stall_rate)] {
+            if !(0.0..=1.0).contains(&rate) {
+                return Err(MockAdminError::InvalidStep(format!("{} must be between 0 and 1, got {}", field, rate)));
+            }
+        }
+
+        let stall = match spec.stall.as_deref() {
+            Some(raw) => parse_duration(raw).filter(|d| !d.is_zero()).ok_or_else(|| {
+                MockAdminError::InvalidStep(format!("invalid stall '{}': expected e.g. \"30s\"", raw))
+            })?,
+            None => DEFAULT_STALL,
+        };
+
+        if spec.drop_rate == 0.0 && spec.stall_rate == 0.0 && spec.dns_failures.is_empty() {
+            return Err(MockAdminError::InvalidStep(
+                "drop_rate, stall_rate or dns_failures is required (or clear: true)".into(),
+            ));
+        }
+
+        Ok(Self::Set {
+            drop_rate: spec.drop_rate,
+            dns_failures: spec.dns_failures,
+            stall_rate: spec.stall_rate,
+            stall,
+            hosts: spec.hosts,
+        })
+    }
+
+    /// Apply the faults through the egress proxy's admin API
+    pub async fn execute(&self, client: &MockAdminClient) -> Result<(), MockAdminError> {
+        match self {
+            Self::Set {
+                drop_rate,
+                dns_failures,
+                stall_rate,
+                stall,
+                hosts,
+            } => {
+                let body = json!({
+                    "drop_rate": drop_rate,
+                    "dns_failures": dns_failures,
+                    "stall_rate": stall_rate,
+                    "stall": format!("{}ms", stall.as_millis()),
+                    "hosts": hosts,
+                });
+
+                client
+                    .send(EGRESS_SERVICE, Method::PUT, NETWORK_FAULTS_PATH, Some(body))
+                    .await?;
+
+                info!(
+                    "Injected network faults (drop: {}, stall: {} for {:?}, DNS failures: {:?})",
+                    drop_rate, stall_rate, stall, dns_failures
+                );
+            }
+            Self::Clear => {
+                client.send(EGRESS_SERVICE, Method::DELETE, NETWORK_FAULTS_PATH, None).await?;
+                info!("Cleared network faults");
+            }
+        }
+
+        Ok(())
+    }
+}
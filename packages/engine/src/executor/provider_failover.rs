@@ -0,0 +1,305 @@
+// packages/engine/src/executor/provider_failover.rs
+//! Provider failover assertions
+//!
+//! `assert_routing` checks how the agent routed model calls while a provider
+//! was failing: that it gave up on the primary provider, switched to the
+//! fallback quickly enough, and that the user still got an answer within
+//! the latency SLO:
+//!
+//! ```yaml
+//! - id: "ask"
+//!   action: agent_request
+//!   input: "Summarize my last order"
+//!
+//! - action: assert_routing
+//!   step: ask                  # step whose model calls are checked
+//!   primary: openai
+//!   fallback: anthropic
+//!   switched_within: 5s        # first failed primary call -> first fallback call
+//!   latency_slo: 8s            # total user-facing latency of the step
+//!   max_primary_attempts: 3    # optional; retries allowed before switching
+//! ```
+//!
+//! The switch time runs from the start of the first failed primary call, so
+//! time the agent spends retrying the primary counts against it. A primary
+//! call fails when it returns 429 or a 5xx; the fallback call must succeed.
+
+use crate::executor::duration::parse_duration;
+use crate::executor::trace_capture::CapturedExchange;
+use serde::{Deserialize, Serialize};
+use std::fmt;
+use std::time::Duration;
+use thiserror::Error;
+
+/// Provider failover assertion errors
+#[derive(Debug, Error)]
+pub enum RoutingError {
+    #[error("invalid step: {0}")]
+    InvalidStep(String),
+
+    #[error("{} routing violation(s): {}", .0.len(), .0.iter().map(|f| f.to_string()).collect::<Vec<_>>().join("; "))]
+    Violated(Vec<RoutingFinding>),
+}
+
+/// `assert_routing` step definition as written in scenario YAML
+#[derive(Debug, Clone, Deserialize)]
+pub struct RoutingSpec {
+    /// Step whose model calls are checked (the previous step when omitted)
+    #[serde(default)]
+    pub step: Option<String>,
+
+    /// Provider the agent calls first (e.g., "openai")
+    pub primary: String,
+
+    /// Provider the agent must fall back to (e.g., "anthropic")
+    pub fallback: String,
+
+    /// Longest allowed time from the first failed primary call to the first
+    /// fallback call (e.g., "5s")
+    #[serde(default)]
+    pub switched_within: Option<String>,
+
+    /// Longest allowed user-facing latency of the step (e.g., "8s")
+    #[serde(default)]
+    pub latency_slo: Option<String>,
+
+    /// Most primary calls allowed before switching
+    #[serde(default)]
+    pub max_primary_attempts: Option<usize>,
+}
+
+/// Routing check that produced a finding
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize)]
+#[serde(rename_all = "snake_case")]
+pub enum RoutingCheck {
+    NoFailover,
+    SlowSwitch,
+    PrimaryRetries,
+    FallbackFailed,
+    LatencySlo,
+}
+
+impl fmt::Display for RoutingCheck {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(match self {
+            Self::NoFailover => "no_failover",
+            Self::SlowSwitch => "slow_switch",
+            Self::PrimaryRetries => "primary_retries",
+            Self::FallbackFailed => "fallback_failed",
+            Self::LatencySlo => "latency_slo",
+        })
+    }
+}
+
+/// A routing violation
+#[derive(Debug, Clone, PartialEq, Serialize)]
+pub struct RoutingFinding {
+    /// Check that fired
+    pub check: RoutingCheck,
+
+    /// What was observed
+    pub detail: String,
+}
+
+impl fmt::Display for RoutingFinding {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        write!(f, "{}: {}", self.check, self.detail)
+    }
+}
+
+/// One model call the agent made
+#[derive(Debug, Clone, PartialEq, Serialize)]
+pub struct ProviderCall {
+    /// Mock service that served the call
+    pub service: String,
+
+    /// Request arrival (Unix milliseconds, mock clock)
+    pub started_at: i64,
+
+    /// Time to the last response byte
+    pub duration_ms: u64,
+
+    /// Response status code
+    pub status: u16,
+}
+
+impl ProviderCall {
+    /// Build a call from an exchange captured by a mock
+    pub fn from_exchange(service: &str, exchange: &CapturedExchange) -> Self {
+        Self {
+            service: service.to_string(),
+            started_at: exchange.started_at,
+            duration_ms: exchange.duration_ms,
+            status: exchange.status,
+        }
+    }
+
+    /// Whether the provider failed the call in a way that warrants failover
+    pub fn failed(&self) -> bool {
+        self.status == 429 || self.status >= 500
+    }
+}
+
+/// What the agent did in the step under test
+#[derive(Debug, Clone, Default)]
+pub struct RoutingTrace {
+    /// Model calls across every provider, in any order
+    pub calls: Vec<ProviderCall>,
+
+    /// User-facing latency of the step
+    pub latency: Duration,
+}
+
+/// A compiled `assert_routing` step
+#[derive(Debug, Clone)]
+pub struct RoutingAssertion {
+    /// Step whose calls are checked (None = previous step)
+    pub step: Option<String>,
+
+    /// Provider called first
+    pub primary: String,
+
+    /// Provider fallen back to
+    pub fallback: String,
+
+    /// Longest allowed switch time
+    pub switched_within: Option<Duration>,
+
+    /// Longest allowed user-facing latency
+    pub latency_slo: Option<Duration>,
+
+    /// Most primary calls allowed before switching
+    pub max_primary_attempts: Option<usize>,
+}
+
+impl RoutingAssertion {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: RoutingSpec) -> Result<Self, RoutingError> {
+        if spec.primary.is_empty() || spec.fallback.is_empty() {
+            return Err(RoutingError::InvalidStep("primary and fallback are required".into()));
+        }
+        if spec.primary == spec.fallback {
+            return Err(RoutingError::InvalidStep(format!(
+                "primary and fallback are both '{}'",
+                spec.primary
+            )));
+        }
+        if spec.max_primary_attempts == Some(0) {
+            return Err(RoutingError::InvalidStep("max_primary_attempts must be at least 1".into()));
+        }
+
+        let parse = |field: &str, raw: &str| {
+            parse_duration(raw).ok_or_else(|| {
+                RoutingError::InvalidStep(format!("invalid {} '{}': expected e.g. \"500ms\" or \"5s\"", field, raw))
+            })
+        };
+
+        Ok(Self {
+            step: spec.step.filter(|s| !s.is_empty()),
+            switched_within: spec
+                .switched_within
+                .as_deref()
+                .map(|raw| parse("switched_within", raw))
+                .transpose()?,
+            latency_slo: spec.latency_slo.as_deref().map(|raw| parse("latency_slo", raw)).transpose()?,
+            primary: spec.primary,
+            fallback: spec.fallback,
+            max_primary_attempts: spec.max_primary_attempts,
+        })
+    }
+
+    /// Run every check, returning all findings
+    pub fn check(&self, trace: &RoutingTrace) -> Vec<RoutingFinding> {
+        let mut findings = Vec::new();
+
+        if let Some(slo) = self.latency_slo {
+            if trace.latency > slo {
+                findings.push(RoutingFinding {
+                    check: RoutingCheck::LatencySlo,
+                    detail: format!("step took {:?}, over the {:?} SLO", trace.latency, slo),
+                });
+            }
+        }
+
+        let mut calls: Vec<&ProviderCall> = trace.calls.iter().collect();
+        calls.sort_by_key(|call| call.started_at);
+
+        let Some(failure) = calls.iter().find(|call| call.service == self.primary && call.failed()) else {
+            let detail = if calls.iter().any(|call| call.service == self.primary) {
+                format!("every {} call succeeded, so failover was not exercised", self.primary)
+            } else {
+                format!("{} was never called", self.primary)
+            };
+            findings.push(RoutingFinding {
+                check: RoutingCheck::NoFailover,
+                detail,
+            });
+            return findings;
+        };
+
+        let Some(fallback) = calls
+            .iter()
+            .find(|call| call.service == self.fallback && call.started_at >= failure.started_at)
+        else {
+            findings.push(RoutingFinding {
+                check: RoutingCheck::NoFailover,
+                detail: format!(
+                    "{} failed with {} but {} was never called",
+                    self.primary, failure.status, self.fallback
+                ),
+            });
+            return findings;
+        };
+
+        let switch = Duration::from_millis((fallback.started_at - failure.started_at).max(0) as u64);
+        if let Some(limit) = self.switched_within {
+            if switch > limit {
+                findings.push(RoutingFinding {
+                    check: RoutingCheck::SlowSwitch,
+                    detail: format!(
+                        "switched from {} to {} after {:?}, over {:?}",
+                        self.primary, self.fallback, switch, limit
+                    ),
+                });
+            }
+        }
+
+        if let Some(max) = self.max_primary_attempts {
+            let attempts = calls
+                .iter()
+                .filter(|call| call.service == self.primary && call.started_at < fallback.started_at)
+                .count();
+            if attempts > max {
+                findings.push(RoutingFinding {
+                    check: RoutingCheck::PrimaryRetries,
+                    detail: format!(
+                        "{} was called {} times before switching, over {}",
+                        self.primary, attempts, max
+                    ),
+                });
+            }
+        }
+
+        if !calls
+            .iter()
+            .any(|call| call.service == self.fallback && call.started_at >= fallback.started_at && !call.failed())
+        {
+            findings.push(RoutingFinding {
+                check: RoutingCheck::FallbackFailed,
+                detail: format!("every {} call failed (first with {})", self.fallback, fallback.status),
+            });
+        }
+
+        findings
+    }
+
+    /// Run every check, failing on any finding
+    pub fn assert(&self, trace: &RoutingTrace) -> Result<(), RoutingError> {
+        let findings = self.check(trace);
+        if findings.is_empty() {
+            Ok(())
+        } else {
+            Err(RoutingError::Violated(findings))
+        }
+    }
+}
@@ -0,0 +1,241 @@
+// packages/engine/src/executor/trace_capture.rs
+//! Full-trace capture of mock HTTP exchanges
+//!
+//! With `simulation.record_full_trace: true`, every mock buffers the complete
+//! exchanges it serves (headers, bodies, timing) for the active run. The run
+//! starts capture on each mock (`PUT /_sentra/capture`), collects the buffered
+//! exchanges into the recording (`GET /_sentra/capture/exchanges`) and stops
+//! capture when it ends (`DELETE /_sentra/capture`), pass or fail.
+//!
+//! Each exchange becomes an `external_call_made` / `external_call_completed`
+//! event pair carrying the raw request and response, so replay does not
+//! depend on any one mock's own summary of what it served.
+
+use crate::executor::mock_admin::{MockAdminClient, MockAdminError};
+use crate::recording::recorder::{Event, EventType};
+use hyper::Method;
+use serde::Deserialize;
+use serde_json::json;
+use std::collections::{BTreeMap, HashMap};
+use tracing::{info, warn};
+
+/// Admin API path for capture on every mock
+const CAPTURE_PATH: &str = "/_sentra/capture";
+
+/// An exchange as returned by a mock's capture API
+#[derive(Debug, Clone, Deserialize)]
+pub struct CapturedExchange {
+    /// Sequence number (monotonic per mock)
+    pub seq: u64,
+
+    /// Run the exchange belongs to
+    pub run_id: String,
+
+    /// Request method
+    pub method: String,
+
+    /// Request path
+    pub path: String,
+
+    /// Raw query string
+    #[serde(default)]
+    pub query: String,
+
+    /// Request headers (credentials redacted by the mock)
+    #[serde(default)]
+    pub request_headers: BTreeMap<String, String>,
+
+    /// Request body (see `body_encoding`)
+    #[serde(default)]
+    pub request_body: String,
+
+    /// Response status code
+    pub status: u16,
+
+    /// Response headers
+    #[serde(default)]
+    pub response_headers: BTreeMap<String, String>,
+
+    /// Response body, including every SSE chunk
+    #[serde(default)]
+    pub response_body: String,
+
+    /// "utf8" or "base64"
+    #[serde(default = "default_encoding")]
+    pub body_encoding: String,
+
+    /// Set when a body exceeded the mock's capture limit
+    #[serde(default)]
+    pub truncated: bool,
+
+    /// Request arrival (Unix milliseconds, mock clock)
+    pub started_at: i64,
+
+    /// Time to the last response byte
+    pub duration_ms: u64,
+}
+
+fn default_encoding() -> String {
+    "utf8".into()
+}
+
+/// Response of `GET /_sentra/capture/exchanges`
+#[derive(Debug, Deserialize)]
+struct ExchangeList {
+    /// Exchanges after the requested sequence number
+    data: Vec<CapturedExchange>,
+
+    /// Exchanges the mock evicted because its buffer was full
+    #[serde(default)]
+    dropped: u64,
+}
+
+/// Full-trace capture for one run across every mock
+#[derive(Debug)]
+pub struct FullTraceCapture {
+    /// Run being recorded
+    run_id: String,
+
+    /// Services where capture is currently started
+    started: Vec<String>,
+
+    /// Service -> last collected sequence number
+    last_seq: HashMap<String, u64>,
+}
+
+impl FullTraceCapture {
+    /// Create capture for a run
+    pub fn new(run_id: impl Into<String>) -> Self {
+        Self {
+            run_id: run_id.into(),
+            started: Vec::new(),
+            last_seq: HashMap::new(),
+        }
+    }
+
+    /// Start capture on every mock. If one mock rejects it, capture is
+    /// stopped on those already started before returning.
+    pub async fn start(&mut self, client: &MockAdminClient) -> Result<(), MockAdminError> {
+        let body = json!({ "run_id": self.run_id });
+
+        for service in client.services() {
+            if let Err(e) = client.send(&service, Method::PUT, CAPTURE_PATH, Some(body.clone())).await {
+                self.stop(client).await;
+                return Err(e);
+            }
+            self.started.push(service);
+        }
+
+        info!("Full-trace capture started for run {} on {:?}", self.run_id, self.started);
+        Ok(())
+    }
+
+    /// Collect exchanges captured since the last call and convert them to
+    /// recording events, ordered by request time. Safe to call repeatedly
+    /// (e.g., after each step) to keep mock buffers small.
+    pub async fn collect(&mut self, client: &MockAdminClient) -> Result<Vec<Event>, MockAdminError> {
+        let mut exchanges: Vec<(String, CapturedExchange)> = Vec::new();
+
+        for service in &self.started {
+            let after_seq = self.last_seq.get(service).copied().unwrap_or(0);
+            let path = format!(
+                "{}/exchanges?run_id={}&after_seq={}",
+                CAPTURE_PATH,
+                encode_query_value(&self.run_id),
+                after_seq
+            );
+
+            let body = client.send(service, Method::GET, &path, None).await?;
+            let list: ExchangeList = serde_json::from_slice(&body).map_err(|e| MockAdminError::RequestFailed {
+                service: service.clone(),
+                reason: format!("invalid capture response: {}", e),
+            })?;
+
+            if list.dropped > 0 {
+                warn!(
+                    "{} dropped {} captured exchange(s) for run {}; collect more often or raise its capture limit",
+                    service, list.dropped, self.run_id
+                );
+            }
+
+            if let Some(last) = list.data.iter().map(|e| e.seq).max() {
+                self.last_seq.insert(service.clone(), last);
+            }
+            exchanges.extend(list.data.into_iter().map(|e| (service.clone(), e)));
+        }
+
+        exchanges.sort_by_key(|(_, e)| e.started_at);
+
+        Ok(exchanges
+            .iter()
+            .flat_map(|(service, exchange)| exchange_events(service, exchange))
+            .collect())
+    }
+
+    /// Stop capture on every started mock. Failures are logged, not
+    /// returned, so one unreachable mock does not leave others capturing.
+    pub async fn stop(&mut self, client: &MockAdminClient) {
+        for service in self.started.drain(..) {
+            if let Err(e) = client.send(&service, Method::DELETE, CAPTURE_PATH, None).await {
+                warn!("Failed to stop full-trace capture on {}: {}", service, e);
+            }
+        }
+        self.last_seq.clear();
+    }
+}
+
+/// Convert an exchange into its request and response events
+pub fn exchange_events(service: &str, exchange: &CapturedExchange) -> [Event; 2] {
+    let started_ns = (exchange.started_at.max(0) as u64) * 1_000_000;
+    let id = format!("{}-{}-{}", exchange.run_id, service, exchange.seq);
+
+    let made = Event {
+        id: format!("{}-req", id),
+        run_id: exchange.run_id.clone(),
+        event_type: EventType::ExternalCallMade,
+        timestamp_ns: started_ns,
+        data: json!({
+            "service": service,
+            "method": exchange.method,
+            "path": exchange.path,
+            "query": exchange.query,
+            "headers": exchange.request_headers,
+            "body": exchange.request_body,
+            "body_encoding": exchange.body_encoding,
+            "full_trace": true,
+        }),
+        duration_us: None,
+    };
+
+    let completed = Event {
+        id: format!("{}-resp", id),
+        run_id: exchange.run_id.clone(),
+        event_type: EventType::ExternalCallCompleted,
+        timestamp_ns: started_ns + exchange.duration_ms * 1_000_000,
+        data: json!({
+            "service": service,
+            "method": exchange.method,
+            "path": exchange.path,
+            "status": exchange.status,
+            "headers": exchange.response_headers,
+            "body": exchange.response_body,
+            "body_encoding": exchange.body_encoding,
+            "truncated": exchange.truncated,
+            "full_trace": true,
+        }),
+        duration_us: Some(exchange.duration_ms * 1000),
+    };
+
+    [made, completed]
+}
+
+/// Percent-encode a query parameter value
+fn encode_query_value(value: &str) -> String {
+    value
+        .bytes()
+        .map(|b| match b {
+            b'A'..=b'Z' | b'a'..=b'z' | b'0'..=b'9' | b'-' | b'_' | b'.' | b'~' => (b as char).to_string(),
+            _ => format!("%{:02X}", b),
+        })
+        .collect()
+}
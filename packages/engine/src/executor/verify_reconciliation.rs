@@ -0,0 +1,522 @@
+// packages/engine/src/executor/verify_reconciliation.rs
+//! `verify_reconciliation` scenario step
+//!
+//! Checks that every succeeded payment in the Stripe mock was posted to the
+//! CoreLedger mock: each needs at least one journal entry referencing it,
+//! every such entry must balance, and the debits must add up to the amount
+//! received. Catches payment agents that charge the card but miss (or
+//! half-write) the postings.
+//!
+//! ```yaml
+//! - action: verify_reconciliation
+//!   payments: stripe              # optional; default "stripe"
+//!   ledger: coreledger            # optional; default "coreledger"
+//!   tolerance: 1                  # optional; in minor units (cents), default 0
+//!   timeout: 5s                   # optional; wait for asynchronous postings
+//!   reference: "$.metadata.payment_intent"  # optional; default "$.reference"
+//! ```
+//!
+//! Payments are read from `GET /v1/payment_intents` and journal entries from
+//! `GET /v1/journal_entries`, both Stripe-style paginated lists. Amounts on
+//! both sides are in the currency's minor unit.
+
+use crate::executor::duration::parse_duration;
+use crate::executor::mock_admin::{MockAdminClient, MockAdminError};
+use crate::executor::verify_webhook::JsonPath;
+use hyper::Method;
+use serde::de::DeserializeOwned;
+use serde::Deserialize;
+use serde_json::Value;
+use std::collections::HashMap;
+use std::fmt;
+use std::time::Duration;
+use thiserror::Error;
+use tokio::time::Instant;
+use tracing::{debug, info};
+
+/// Default payments mock
+pub const DEFAULT_PAYMENTS_SERVICE: &str = "stripe";
+
+/// Default ledger mock
+pub const DEFAULT_LEDGER_SERVICE: &str = "coreledger";
+
+/// Default timeout when the step does not set one
+pub const DEFAULT_RECONCILIATION_TIMEOUT: Duration = Duration::from_secs(5);
+
+/// Default JSONPath from a journal entry to the payment it records
+pub const DEFAULT_REFERENCE_PATH: &str = "$.reference";
+
+/// API key the Stripe mock accepts (the one the library shims inject)
+const PAYMENTS_API_KEY: &str = "sk_test_mock_sentra_lab";
+
+/// How often postings are re-read while waiting
+const POLL_INTERVAL: Duration = Duration::from_millis(250);
+
+/// Page size for list requests
+const PAGE_SIZE: usize = 100;
+
+/// Upper bound on pages read per list, so a mock that never stops paginating
+/// cannot hang the step
+const MAX_PAGES: usize = 100;
+
+/// Findings shown in the error message (all are kept in the error)
+const MAX_REPORTED_FINDINGS: usize = 5;
+
+/// `verify_reconciliation` step errors
+#[derive(Debug, Error)]
+pub enum VerifyReconciliationError {
+    #[error("invalid step: {0}")]
+    InvalidStep(String),
+
+    #[error(transparent)]
+    Mock(#[from] MockAdminError),
+
+    #[error("unexpected response from {service} {path}: {reason}")]
+    InvalidResponse {
+        service: String,
+        path: String,
+        reason: String,
+    },
+
+    #[error("{} of {checked} succeeded payments did not reconcile within {timeout:?}: {}", .findings.len(), summarize(.findings))]
+    Unreconciled {
+        checked: usize,
+        timeout: Duration,
+        findings: Vec<ReconciliationFinding>,
+    },
+}
+
+/// Step definition as written in scenario YAML
+#[derive(Debug, Clone, Default, Deserialize)]
+pub struct VerifyReconciliationSpec {
+    /// Payments mock (default "stripe")
+    #[serde(default)]
+    pub payments: Option<String>,
+
+    /// Ledger mock (default "coreledger")
+    #[serde(default)]
+    pub ledger: Option<String>,
+
+    /// Allowed difference in minor units (default 0)
+    #[serde(default)]
+    pub tolerance: Option<i64>,
+
+    /// How long to wait for postings (e.g., "5s"); defaults to 5s
+    #[serde(default)]
+    pub timeout: Option<String>,
+
+    /// JSONPath from a journal entry to the payment ID it records
+    #[serde(default)]
+    pub reference: Option<String>,
+}
+
+/// A compiled `verify_reconciliation` step
+#[derive(Debug, Clone)]
+pub struct VerifyReconciliationStep {
+    /// Payments mock
+    pub payments: String,
+
+    /// Ledger mock
+    pub ledger: String,
+
+    /// Allowed difference in minor units
+    pub tolerance: i64,
+
+    /// How long to wait for postings
+    pub timeout: Duration,
+
+    /// Path from a journal entry to the payment ID it records
+    pub reference: JsonPath,
+}
+
+/// Why a succeeded payment did not reconcile
+#[derive(Debug, Clone, PartialEq)]
+pub enum ReconciliationFinding {
+    /// No journal entry references the payment
+    Missing {
+        payment: String,
+        amount: i64,
+        currency: String,
+    },
+
+    /// A journal entry's debits and credits differ by more than the tolerance
+    Unbalanced {
+        payment: String,
+        entry: String,
+        debits: i64,
+        credits: i64,
+    },
+
+    /// The posted debits differ from the amount received
+    AmountMismatch {
+        payment: String,
+        expected: i64,
+        posted: i64,
+    },
+
+    /// A journal entry is in a different currency than the payment
+    CurrencyMismatch {
+        payment: String,
+        entry: String,
+        expected: String,
+        posted: String,
+    },
+}
+
+impl fmt::Display for ReconciliationFinding {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        match self {
+            Self::Missing {
+                payment,
+                amount,
+                currency,
+            } => write!(f, "{} ({} {}) has no journal entry", payment, amount, currency),
+            Self::Unbalanced {
+                payment,
+                entry,
+                debits,
+                credits,
+            } => write!(
+                f,
+                "{} entry {} is unbalanced (debits {}, credits {})",
+                payment, entry, debits, credits
+            ),
+            Self::AmountMismatch {
+                payment,
+                expected,
+                posted,
+            } => write!(f, "{} posted {} (received {})", payment, posted, expected),
+            Self::CurrencyMismatch {
+                payment,
+                entry,
+                expected,
+                posted,
+            } => write!(f, "{} entry {} is in {} (payment in {})", payment, entry, posted, expected),
+        }
+    }
+}
+
+/// Outcome of a successful reconciliation
+#[derive(Debug, Clone, Copy)]
+pub struct ReconciliationReport {
+    /// Succeeded payments checked
+    pub payments: usize,
+
+    /// Journal entries matched to them
+    pub entries: usize,
+}
+
+/// A payment intent as returned by the Stripe mock
+#[derive(Debug, Clone, Deserialize)]
+struct PaymentIntent {
+    id: String,
+
+    #[serde(default)]
+    status: String,
+
+    #[serde(default)]
+    amount: i64,
+
+    #[serde(default)]
+    amount_received: Option<i64>,
+
+    #[serde(default)]
+    currency: String,
+}
+
+impl PaymentIntent {
+    /// Amount actually collected (partial captures collect less)
+    fn received(&self) -> i64 {
+        self.amount_received.filter(|&received| received > 0).unwrap_or(self.amount)
+    }
+}
+
+/// One debit or credit of a journal entry
+#[derive(Debug, Clone, Deserialize)]
+struct JournalLine {
+    #[serde(default)]
+    debit: i64,
+
+    #[serde(default)]
+    credit: i64,
+}
+
+/// A journal entry as returned by the CoreLedger mock
+#[derive(Debug, Clone)]
+struct JournalEntry {
+    id: String,
+    currency: String,
+    lines: Vec<JournalLine>,
+    raw: Value,
+}
+
+impl JournalEntry {
+    fn from_value(raw: Value) -> Result<Self, String> {
+        let id = raw
+            .get("id")
+            .and_then(Value::as_str)
+            .ok_or("journal entry without an id")?
+            .to_string();
+        let currency = raw.get("currency").and_then(Value::as_str).unwrap_or_default().to_string();
+        let lines = match raw.get("lines") {
+            Some(lines) => serde_json::from_value(lines.clone()).map_err(|e| format!("entry {}: {}", id, e))?,
+            None => Vec::new(),
+        };
+
+        Ok(Self {
+            id,
+            currency,
+            lines,
+            raw,
+        })
+    }
+
+    fn debits(&self) -> i64 {
+        self.lines.iter().map(|line| line.debit).sum()
+    }
+
+    fn credits(&self) -> i64 {
+        self.lines.iter().map(|line| line.credit).sum()
+    }
+}
+
+/// A Stripe-style list page
+#[derive(Debug, Deserialize)]
+struct ListPage<T> {
+    data: Vec<T>,
+
+    #[serde(default)]
+    has_more: bool,
+}
+
+impl VerifyReconciliationStep {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: VerifyReconciliationSpec) -> Result<Self, VerifyReconciliationError> {
+        let service = |value: Option<String>, default: &str| {
+            value.filter(|s| !s.is_empty()).unwrap_or_else(|| default.to_string())
+        };
+        let payments = service(spec.payments, DEFAULT_PAYMENTS_SERVICE);
+        let ledger = service(spec.ledger, DEFAULT_LEDGER_SERVICE);
+        if payments == ledger {
+            return Err(VerifyReconciliationError::InvalidStep(
+                "payments and ledger must be different mocks".into(),
+            ));
+        }
+
+        let tolerance = spec.tolerance.unwrap_or(0);
+        if tolerance < 0 {
+            return Err(VerifyReconciliationError::InvalidStep("tolerance cannot be negative".into()));
+        }
+
+        let timeout = match spec.timeout.as_deref() {
+            Some(raw) => parse_duration(raw).ok_or_else(|| {
+                VerifyReconciliationError::InvalidStep(format!(
+                    "invalid timeout '{}': expected e.g. \"500ms\" or \"5s\"",
+                    raw
+                ))
+            })?,
+            None => DEFAULT_RECONCILIATION_TIMEOUT,
+        };
+
+        let reference = JsonPath::parse(spec.reference.as_deref().unwrap_or(DEFAULT_REFERENCE_PATH))
+            .map_err(|e| VerifyReconciliationError::InvalidStep(e.to_string()))?;
+
+        Ok(Self {
+            payments,
+            ledger,
+            tolerance,
+            timeout,
+            reference,
+        })
+    }
+
+    /// Run the step, re-reading the ledger until every succeeded payment
+    /// reconciles or the timeout passes
+    pub async fn execute(&self, client: &MockAdminClient) -> Result<ReconciliationReport, VerifyReconciliationError> {
+        let deadline = Instant::now() + self.timeout;
+        let authorization = format!("Bearer {}", PAYMENTS_API_KEY);
+
+        loop {
+            let payments: Vec<PaymentIntent> = self
+                .list::<PaymentIntent>(
+                    client,
+                    &self.payments,
+                    "/v1/payment_intents",
+                    &[("authorization", authorization.as_str())],
+                )
+                .await?
+                .into_iter()
+                .filter(|payment| payment.status == "succeeded")
+                .collect();
+
+            let entries = self
+                .list::<Value>(client, &self.ledger, "/v1/journal_entries", &[])
+                .await?
+                .into_iter()
+                .map(JournalEntry::from_value)
+                .collect::<Result<Vec<_>, _>>()
+                .map_err(|reason| VerifyReconciliationError::InvalidResponse {
+                    service: self.ledger.clone(),
+                    path: "/v1/journal_entries".into(),
+                    reason,
+                })?;
+
+            let (findings, matched) = self.reconcile(&payments, &entries);
+            if findings.is_empty() {
+                info!(
+                    "Reconciled {} payments from {} against {} journal entries in {}",
+                    payments.len(),
+                    self.payments,
+                    matched,
+                    self.ledger
+                );
+                return Ok(ReconciliationReport {
+                    payments: payments.len(),
+                    entries: matched,
+                });
+            }
+
+            if Instant::now() + POLL_INTERVAL > deadline {
+                return Err(VerifyReconciliationError::Unreconciled {
+                    checked: payments.len(),
+                    timeout: self.timeout,
+                    findings,
+                });
+            }
+
+            debug!("{} payments not reconciled yet, retrying", findings.len());
+            tokio::time::sleep(POLL_INTERVAL).await;
+        }
+    }
+
+    /// Compare payments with journal entries, returning the findings and the
+    /// number of entries that referenced a payment
+    fn reconcile(&self, payments: &[PaymentIntent], entries: &[JournalEntry]) -> (Vec<ReconciliationFinding>, usize) {
+        let mut by_payment: HashMap<&str, Vec<&JournalEntry>> = HashMap::new();
+        for entry in entries {
+            if let Some(reference) = self.reference.select(&entry.raw).and_then(Value::as_str) {
+                by_payment.entry(reference).or_default().push(entry);
+            }
+        }
+
+        let mut findings = Vec::new();
+        let mut matched = 0;
+
+        for payment in payments {
+            let Some(posted) = by_payment.get(payment.id.as_str()) else {
+                findings.push(ReconciliationFinding::Missing {
+                    payment: payment.id.clone(),
+                    amount: payment.received(),
+                    currency: payment.currency.clone(),
+                });
+                continue;
+            };
+            matched += posted.len();
+
+            let mut consistent = true;
+            for entry in posted {
+                if (entry.debits() - entry.credits()).abs() > self.tolerance {
+                    consistent = false;
+                    findings.push(ReconciliationFinding::Unbalanced {
+                        payment: payment.id.clone(),
+                        entry: entry.id.clone(),
+                        debits: entry.debits(),
+                        credits: entry.credits(),
+                    });
+                }
+                if !entry.currency.is_empty() && !entry.currency.eq_ignore_ascii_case(&payment.currency) {
+                    consistent = false;
+                    findings.push(ReconciliationFinding::CurrencyMismatch {
+                        payment: payment.id.clone(),
+                        entry: entry.id.clone(),
+                        expected: payment.currency.clone(),
+                        posted: entry.currency.clone(),
+                    });
+                }
+            }
+
+            // A mismatch is only meaningful once the entries themselves are sound
+            let total: i64 = posted.iter().map(|entry| entry.debits()).sum();
+            if consistent && (total - payment.received()).abs() > self.tolerance {
+                findings.push(ReconciliationFinding::AmountMismatch {
+                    payment: payment.id.clone(),
+                    expected: payment.received(),
+                    posted: total,
+                });
+            }
+        }
+
+        (findings, matched)
+    }
+
+    /// Read every page of a Stripe-style list endpoint
+    async fn list<T: DeserializeOwned + HasId>(
+        &self,
+        client: &MockAdminClient,
+        service: &str,
+        path: &str,
+        headers: &[(&str, &str)],
+    ) -> Result<Vec<T>, VerifyReconciliationError> {
+        let mut items = Vec::new();
+        let mut starting_after: Option<String> = None;
+
+        for _ in 0..MAX_PAGES {
+            let mut uri = format!("{}?limit={}", path, PAGE_SIZE);
+            if let Some(cursor) = &starting_after {
+                uri.push_str(&format!("&starting_after={}", cursor));
+            }
+
+            let body = client.send_with_headers(service, Method::GET, &uri, None, headers).await?;
+            let page: ListPage<T> =
+                serde_json::from_slice(&body).map_err(|e| VerifyReconciliationError::InvalidResponse {
+                    service: service.to_string(),
+                    path: path.to_string(),
+                    reason: e.to_string(),
+                })?;
+
+            starting_after = page.data.last().and_then(HasId::id);
+            let done = !page.has_more || starting_after.is_none();
+            items.extend(page.data);
+            if done {
+                return Ok(items);
+            }
+        }
+
+        Err(VerifyReconciliationError::InvalidResponse {
+            service: service.to_string(),
+            path: path.to_string(),
+            reason: format!("more than {} pages", MAX_PAGES),
+        })
+    }
+}
+
+/// List items that can be used as a pagination cursor
+trait HasId {
+    fn id(&self) -> Option<String>;
+}
+
+impl HasId for PaymentIntent {
+    fn id(&self) -> Option<String> {
+        Some(self.id.clone())
+    }
+}
+
+impl HasId for Value {
+    fn id(&self) -> Option<String> {
+        self.get("id").and_then(Value::as_str).map(str::to_string)
+    }
+}
+
+/// Describe the first findings, noting how many more there are
+fn summarize(findings: &[ReconciliationFinding]) -> String {
+    let mut summary = findings
+        .iter()
+        .take(MAX_REPORTED_FINDINGS)
+        .map(ToString::to_string)
+        .collect::<Vec<_>>()
+        .join("; ");
+    if findings.len() > MAX_REPORTED_FINDINGS {
+        summary.push_str(&format!("; and {} more", findings.len() - MAX_REPORTED_FINDINGS));
+    }
+    summary
+}
@@ -0,0 +1,287 @@
+// packages/engine/src/executor/verify_webhook.rs
+//! `verify_webhook` scenario step
+//!
+//! Waits up to a timeout for a captured webhook of a given type whose
+//! payload satisfies JSONPath matchers:
+//!
+//! ```yaml
+//! - action: verify_webhook
+//!   service: stripe
+//!   event_type: "payment_intent.succeeded"
+//!   timeout: 5s
+//!   match:
+//!     "$.data.object.amount": 9999
+//!     "$.data.object.metadata.order_id": "order_123"
+//! ```
+//!
+//! Supported JSONPath subset: `$`, `.field`, `['field']` and `[index]`.
+
+use crate::executor::duration::parse_duration;
+use crate::executor::webhook_capture::{CapturedWebhook, WebhookCapture};
+use serde::Deserialize;
+use serde_json::Value;
+use std::collections::BTreeMap;
+use std::fmt;
+use std::time::Duration;
+use thiserror::Error;
+use tokio::time::Instant;
+
+/// Default timeout when the step does not set one
+pub const DEFAULT_VERIFY_TIMEOUT: Duration = Duration::from_secs(5);
+
+/// `verify_webhook` step errors
+#[derive(Debug, Error)]
+pub enum VerifyWebhookError {
+    #[error("invalid step: {0}")]
+    InvalidStep(String),
+
+    #[error("invalid JSONPath '{path}': {reason}")]
+    InvalidPath { path: String, reason: String },
+
+    #[error("invalid timeout '{0}': expected e.g. \"500ms\", \"5s\" or \"1m\"")]
+    InvalidTimeout(String),
+
+    #[error("no '{event_type}' webhook from {service} matched within {timeout:?} ({candidates} of that type received){}", .closest.as_ref().map(|c| format!("; closest: {}", c)).unwrap_or_default())]
+    Timeout {
+        service: String,
+        event_type: String,
+        timeout: Duration,
+        candidates: usize,
+        closest: Option<String>,
+    },
+}
+
+/// Step definition as written in scenario YAML
+#[derive(Debug, Clone, Deserialize)]
+pub struct VerifyWebhookSpec {
+    /// Mock service that sends the webhook (e.g., "stripe")
+    pub service: String,
+
+    /// Expected event type
+    pub event_type: String,
+
+    /// How long to wait (e.g., "5s"); defaults to 5s
+    #[serde(default)]
+    pub timeout: Option<String>,
+
+    /// JSONPath expression -> expected value
+    #[serde(default, rename = "match")]
+    pub matchers: BTreeMap<String, Value>,
+}
+
+/// A compiled `verify_webhook` step
+#[derive(Debug, Clone)]
+pub struct VerifyWebhookStep {
+    /// Mock service that sends the webhook
+    pub service: String,
+
+    /// Expected event type
+    pub event_type: String,
+
+    /// How long to wait for a matching webhook
+    pub timeout: Duration,
+
+    /// Payload matchers (all must match)
+    pub matchers: Vec<PayloadMatcher>,
+}
+
+impl VerifyWebhookStep {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: VerifyWebhookSpec) -> Result<Self, VerifyWebhookError> {
+        if spec.service.is_empty() {
+            return Err(VerifyWebhookError::InvalidStep("service is required".into()));
+        }
+        if spec.event_type.is_empty() {
+            return Err(VerifyWebhookError::InvalidStep("event_type is required".into()));
+        }
+
+        let timeout = match spec.timeout.as_deref() {
+            Some(raw) => {
+                parse_duration(raw).ok_or_else(|| VerifyWebhookError::InvalidTimeout(raw.to_string()))?
+            }
+            None => DEFAULT_VERIFY_TIMEOUT,
+        };
+
+        let matchers = spec
+            .matchers
+            .into_iter()
+            .map(|(path, expected)| PayloadMatcher::new(&path, expected))
+            .collect::<Result<Vec<_>, _>>()?;
+
+        Ok(Self {
+            service: spec.service,
+            event_type: spec.event_type,
+            timeout,
+            matchers,
+        })
+    }
+
+    /// Run the step against a capture buffer, returning the matching webhook
+    pub async fn execute(&self, capture: &WebhookCapture) -> Result<CapturedWebhook, VerifyWebhookError> {
+        let deadline = Instant::now() + self.timeout;
+
+        if let Some(event) = capture.wait_for(deadline, |event| self.matches(event)).await {
+            return Ok(event);
+        }
+
+        // Report the nearest miss to make failures actionable
+        let candidates = capture.find(|event| self.is_candidate(event));
+        let closest = candidates.last().and_then(|event| {
+            self.matchers
+                .iter()
+                .find_map(|matcher| matcher.mismatch(&event.payload))
+        });
+
+        Err(VerifyWebhookError::Timeout {
+            service: self.service.clone(),
+            event_type: self.event_type.clone(),
+            timeout: self.timeout,
+            candidates: candidates.len(),
+            closest,
+        })
+    }
+
+    /// Check service and event type
+    fn is_candidate(&self, event: &CapturedWebhook) -> bool {
+        event.service == self.service && event.event_type == self.event_type
+    }
+
+    /// Check service, event type and all payload matchers
+    fn matches(&self, event: &CapturedWebhook) -> bool {
+        self.is_candidate(event)
+            && self
+                .matchers
+                .iter()
+                .all(|matcher| matcher.mismatch(&event.payload).is_none())
+    }
+}
+
+/// Asserts that the value at a JSONPath equals an expected value
+#[derive(Debug, Clone)]
+pub struct PayloadMatcher {
+    /// Compiled path
+    path: JsonPath,
+
+    /// Expected value
+    expected: Value,
+}
+
+impl PayloadMatcher {
+    /// Create a matcher, compiling the JSONPath
+    pub fn new(path: &str, expected: Value) -> Result<Self, VerifyWebhookError> {
+        Ok(Self {
+            path: JsonPath::parse(path)?,
+            expected,
+        })
+    }
+
+    /// Describe why the payload does not match, or `None` if it does
+    pub fn mismatch(&self, payload: &Value) -> Option<String> {
+        match self.path.select(payload) {
+            Some(actual) if values_equal(actual, &self.expected) => None,
+            Some(actual) => Some(format!("{} = {} (expected {})", self.path, actual, self.expected)),
+            None => Some(format!("{} not found (expected {})", self.path, self.expected)),
+        }
+    }
+}
+
+/// Compare JSON values, treating integers and floats with the same value as equal
+fn values_equal(actual: &Value, expected: &Value) -> bool {
+    match (actual, expected) {
+        (Value::Number(a), Value::Number(b)) => a.as_f64() == b.as_f64(),
+        _ => actual == expected,
+    }
+}
+
+/// One step of a JSONPath
+#[derive(Debug, Clone, PartialEq)]
+enum PathSegment {
+    Field(String),
+    Index(usize),
+}
+
+/// A compiled JSONPath (subset: `$`, `.field`, `['field']`, `[index]`)
+#[derive(Debug, Clone)]
+pub struct JsonPath {
+    /// Original expression
+    raw: String,
+
+    /// Compiled segments
+    segments: Vec<PathSegment>,
+}
+
+impl JsonPath {
+    /// Parse a JSONPath expression
+    pub fn parse(raw: &str) -> Result<Self, VerifyWebhookError> {
+        let invalid = |reason: &str| VerifyWebhookError::InvalidPath {
+            path: raw.to_string(),
+            reason: reason.to_string(),
+        };
+
+        let rest = raw.strip_prefix('$').ok_or_else(|| invalid("must start with '$'"))?;
+        let chars: Vec<char> = rest.chars().collect();
+        let mut segments = Vec::new();
+        let mut i = 0;
+
+        while i < chars.len() {
+            match chars[i] {
+                '.' => {
+                    let start = i + 1;
+                    i = start;
+                    while i < chars.len() && chars[i] != '.' && chars[i] != '[' {
+                        i += 1;
+                    }
+                    if i == start {
+                        return Err(invalid("empty field name"));
+                    }
+                    segments.push(PathSegment::Field(chars[start..i].iter().collect()));
+                }
+                '[' => {
+                    let close = chars[i..]
+                        .iter()
+                        .position(|&c| c == ']')
+                        .map(|offset| i + offset)
+                        .ok_or_else(|| invalid("unclosed '['"))?;
+                    let inner: String = chars[i + 1..close].iter().collect();
+                    let inner = inner.trim();
+
+                    let quoted = inner
+                        .strip_prefix('\'')
+                        .and_then(|s| s.strip_suffix('\''))
+                        .or_else(|| inner.strip_prefix('"').and_then(|s| s.strip_suffix('"')));
+
+                    match quoted {
+                        Some(field) => segments.push(PathSegment::Field(field.to_string())),
+                        None => {
+                            let index = inner
+                                .parse::<usize>()
+                                .map_err(|_| invalid("expected a quoted field or array index in brackets"))?;
+                            segments.push(PathSegment::Index(index));
+                        }
+                    }
+                    i = close + 1;
+                }
+                _ => return Err(invalid("expected '.' or '['")),
+            }
+        }
+
+        Ok(Self {
+            raw: raw.to_string(),
+            segments,
+        })
+    }
+
+    /// Select the value at this path
+    pub fn select<'a>(&self, value: &'a Value) -> Option<&'a Value> {
+        self.segments.iter().try_fold(value, |current, segment| match segment {
+            PathSegment::Field(name) => current.get(name.as_str()),
+            PathSegment::Index(index) => current.get(*index),
+        })
+    }
+}
+
+impl fmt::Display for JsonPath {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(&self.raw)
+    }
+}
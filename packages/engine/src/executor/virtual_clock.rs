@@ -0,0 +1,227 @@
+// packages/engine/src/executor/virtual_clock.rs
+//! Virtual clock and time-based scenario steps
+//!
+//! `advance_clock` jumps virtual time forward in the engine and in every
+//! mock (`POST /_sentra/clock/advance`), so daily rate-limit resets,
+//! subscription renewals and TTL expirations happen inside one scenario.
+//! `schedule` queues a step to run once virtual time reaches an offset from
+//! scenario start:
+//!
+//! ```yaml
+//! - action: schedule
+//!   at: 24h
+//!   step:
+//!     action: agent_request
+//!     input: "Renew my subscription"
+//!
+//! - action: advance_clock
+//!   duration: 25h        # runs the scheduled step
+//! ```
+
+use crate::executor::duration::parse_duration;
+use crate::executor::mock_admin::{MockAdminClient, MockAdminError};
+use chrono::{DateTime, Utc};
+use hyper::Method;
+use parking_lot::Mutex;
+use serde::Deserialize;
+use serde_json::{json, Value};
+use std::collections::BTreeMap;
+use std::time::{Duration, Instant};
+use tracing::info;
+
+/// Admin API path for the virtual clock on every mock
+const CLOCK_PATH: &str = "/_sentra/clock";
+
+/// A step queued by `schedule`
+#[derive(Debug, Clone)]
+pub struct ScheduledStep {
+    /// Virtual offset from scenario start at which the step runs
+    pub at: Duration,
+
+    /// The step definition, run by the scenario executor
+    pub step: Value,
+}
+
+/// Clock state guarded by a single lock
+struct ClockState {
+    /// How far virtual time runs ahead of real time
+    offset: Duration,
+
+    /// Pending steps keyed by (due time, scheduling order)
+    scheduled: BTreeMap<(Duration, u64), Value>,
+
+    /// Next scheduling sequence number
+    next_seq: u64,
+}
+
+/// Per-scenario virtual clock
+pub struct VirtualClock {
+    /// Real time the scenario started
+    started: Instant,
+
+    /// Wall-clock time the scenario started
+    started_at: DateTime<Utc>,
+
+    /// Offset and scheduled steps
+    state: Mutex<ClockState>,
+}
+
+impl VirtualClock {
+    /// Create a clock starting at the current time
+    pub fn new() -> Self {
+        Self {
+            started: Instant::now(),
+            started_at: Utc::now(),
+            state: Mutex::new(ClockState {
+                offset: Duration::ZERO,
+                scheduled: BTreeMap::new(),
+                next_seq: 0,
+            }),
+        }
+    }
+
+    /// Virtual time elapsed since scenario start (real elapsed + advances)
+    pub fn elapsed(&self) -> Duration {
+        self.started.elapsed() + self.state.lock().offset
+    }
+
+    /// Current virtual time
+    pub fn now(&self) -> DateTime<Utc> {
+        let elapsed = chrono::Duration::from_std(self.elapsed()).unwrap_or(chrono::Duration::zero());
+        self.started_at + elapsed
+    }
+
+    /// Total time the clock has been advanced
+    pub fn offset(&self) -> Duration {
+        self.state.lock().offset
+    }
+
+    /// Queue a step to run at a virtual offset from scenario start
+    pub fn schedule(&self, at: Duration, step: Value) {
+        let mut state = self.state.lock();
+
+        let seq = state.next_seq;
+        state.next_seq += 1;
+
+        state.scheduled.insert((at, seq), step);
+    }
+
+    /// Move virtual time forward and return the steps that became due,
+    /// in due order
+    pub fn advance(&self, by: Duration) -> Vec<ScheduledStep> {
+        self.state.lock().offset += by;
+        self.due()
+    }
+
+    /// Remove and return steps whose time has come (by advances or by real
+    /// time passing), in due order
+    pub fn due(&self) -> Vec<ScheduledStep> {
+        let elapsed = self.elapsed();
+        let mut state = self.state.lock();
+        let mut due = Vec::new();
+
+        while let Some(entry) = state.scheduled.first_entry() {
+            if entry.key().0 > elapsed {
+                break;
+            }
+            let ((at, _), step) = entry.remove_entry();
+            due.push(ScheduledStep { at, step });
+        }
+
+        due
+    }
+
+    /// Number of steps still waiting
+    pub fn pending(&self) -> usize {
+        self.state.lock().scheduled.len()
+    }
+}
+
+impl Default for VirtualClock {
+    fn default() -> Self {
+        Self::new()
+    }
+}
+
+/// `advance_clock` step definition as written in scenario YAML
+#[derive(Debug, Clone, Deserialize)]
+pub struct AdvanceClockSpec {
+    /// How far to advance (e.g., "25h")
+    #[serde(alias = "by")]
+    pub duration: String,
+}
+
+/// A compiled `advance_clock` step
+#[derive(Debug, Clone)]
+pub struct AdvanceClockStep {
+    /// How far to advance
+    pub duration: Duration,
+}
+
+impl AdvanceClockStep {
+    /// Compile a step from its YAML definition
+    pub fn from_spec(spec: AdvanceClockSpec) -> Result<Self, MockAdminError> {
+        let duration = parse_duration(&spec.duration).ok_or_else(|| {
+            MockAdminError::InvalidStep(format!("invalid duration '{}': expected e.g. \"90m\" or \"25h\"", spec.duration))
+        })?;
+
+        Ok(Self { duration })
+    }
+
+    /// Advance every mock's clock, then the engine clock. Returns scheduled
+    /// steps that became due, for the executor to run next.
+    pub async fn execute(
+        &self,
+        clock: &VirtualClock,
+        client: &MockAdminClient,
+    ) -> Result<Vec<ScheduledStep>, MockAdminError> {
+        let body = json!({ "duration": format!("{}ms", self.duration.as_millis()) });
+
+        for service in client.services() {
+            client
+                .send(&service, Method::POST, &format!("{}/advance", CLOCK_PATH), Some(body.clone()))
+                .await?;
+        }
+
+        let due = clock.advance(self.duration);
+        info!(
+            "Advanced virtual clock by {:?} (now {}, {} scheduled step(s) due)",
+            self.duration,
+            clock.now().to_rfc3339(),
+            due.len()
+        );
+
+        Ok(due)
+    }
+}
+
+/// `schedule` step definition as written in scenario YAML
+#[derive(Debug, Clone, Deserialize)]
+pub struct ScheduleSpec {
+    /// Virtual offset from scenario start (e.g., "24h")
+    pub at: String,
+
+    /// The step to run
+    pub step: Value,
+}
+
+/// Compile and queue a `schedule` step
+pub fn schedule_step(clock: &VirtualClock, spec: ScheduleSpec) -> Result<(), MockAdminError> {
+    let at = parse_duration(&spec.at).ok_or_else(|| {
+        MockAdminError::InvalidStep(format!("invalid schedule time '{}': expected e.g. \"24h\"", spec.at))
+    })?;
+    if !spec.step.is_object() {
+        return Err(MockAdminError::InvalidStep("schedule requires a step".into()));
+    }
+
+    clock.schedule(at, spec.step);
+    Ok(())
+}
+
+/// Return every mock's clock to real time (at scenario end)
+pub async fn reset_mock_clocks(client: &MockAdminClient) -> Result<(), MockAdminError> {
+    for service in client.services() {
+        client.send(&service, Method::DELETE, CLOCK_PATH, None).await?;
+    }
+    Ok(())
+}
@@ -0,0 +1,189 @@
+// packages/engine/src/executor/webhook_capture.rs
+//! Webhook capture buffer
+//!
+//! Mock services deliver webhooks (e.g., Stripe `payment_intent.succeeded`)
+//! to the agent under test. Each delivery is also recorded here so scenario
+//! steps can assert on it later. The buffer is bounded: once full, the oldest
+//! webhook is evicted.
+
+use chrono::{DateTime, Utc};
+use parking_lot::Mutex;
+use serde::Serialize;
+use serde_json::Value;
+use std::collections::VecDeque;
+use std::sync::atomic::{AtomicU64, Ordering};
+use tokio::sync::Notify;
+use tokio::time::Instant;
+
+/// Default number of webhooks kept per simulation
+pub const DEFAULT_CAPTURE_CAPACITY: usize = 1024;
+
+/// A webhook delivered by a mock service
+#[derive(Debug, Clone, Serialize)]
+pub struct CapturedWebhook {
+    /// Sequence number (monotonic per buffer)
+    pub seq: u64,
+
+    /// Mock service that sent the webhook (e.g., "stripe")
+    pub service: String,
+
+    /// Event type (e.g., "payment_intent.succeeded")
+    pub event_type: String,
+
+    /// Decoded JSON payload
+    pub payload: Value,
+
+    /// Time the webhook was captured
+    pub received_at: DateTime<Utc>,
+}
+
+/// Bounded buffer of captured webhooks
+pub struct WebhookCapture {
+    /// Captured webhooks, oldest first
+    events: Mutex<VecDeque<CapturedWebhook>>,
+
+    /// Maximum number of webhooks kept
+    capacity: usize,
+
+    /// Next sequence number
+    next_seq: AtomicU64,
+
+    /// Evicted webhook counter
+    evicted: AtomicU64,
+
+    /// Wakes waiters when a webhook is captured
+    notify: Notify,
+}
+
+impl WebhookCapture {
+    /// Create a new capture buffer
+    pub fn new(capacity: usize) -> Self {
+        let capacity = capacity.max(1);
+
+        Self {
+            events: Mutex::new(VecDeque::with_capacity(capacity)),
+            capacity,
+            next_seq: AtomicU64::new(0),
+            evicted: AtomicU64::new(0),
+            notify: Notify::new(),
+        }
+    }
+
+    /// Record a webhook with an explicit event type
+    pub fn record(
+        &self,
+        service: impl Into<String>,
+        event_type: impl Into<String>,
+        payload: Value,
+    ) -> u64 {
+        let seq = self.next_seq.fetch_add(1, Ordering::Relaxed);
+
+        {
+            let mut events = self.events.lock();
+            if events.len() == self.capacity {
+                events.pop_front();
+                self.evicted.fetch_add(1, Ordering::Relaxed);
+            }
+            events.push_back(CapturedWebhook {
+                seq,
+                service: service.into(),
+                event_type: event_type.into(),
+                payload,
+                received_at: Utc::now(),
+            });
+        }
+
+        self.notify.notify_waiters();
+        seq
+    }
+
+    /// Record a raw JSON webhook body, taking the event type from its
+    /// top-level `type` field (Stripe-style). Non-JSON bodies are kept as
+    /// strings with an empty event type.
+    pub fn record_body(&self, service: impl Into<String>, body: &[u8]) -> u64 {
+        let payload = serde_json::from_slice(body)
+            .unwrap_or_else(|_| Value::String(String::from_utf8_lossy(body).into_owned()));
+
+        let event_type = payload
+            .get("type")
+            .and_then(Value::as_str)
+            .unwrap_or_default()
+            .to_string();
+
+        self.record(service, event_type, payload)
+    }
+
+    /// Return all captured webhooks matching a predicate, oldest first
+    pub fn find<F>(&self, mut predicate: F) -> Vec<CapturedWebhook>
+    where
+        F: FnMut(&CapturedWebhook) -> bool,
+    {
+        self.events
+            .lock()
+            .iter()
+            .filter(|event| predicate(event))
+            .cloned()
+            .collect()
+    }
+
+    /// Wait until a webhook matching the predicate has been captured.
+    ///
+    /// Webhooks captured before the call also count, so a webhook that
+    /// arrives before the assertion step starts is not missed. Returns
+    /// `None` once `deadline` passes.
+    pub async fn wait_for<F>(&self, deadline: Instant, mut predicate: F) -> Option<CapturedWebhook>
+    where
+        F: FnMut(&CapturedWebhook) -> bool,
+    {
+        loop {
+            // Register interest before checking, so a capture between the
+            // check and the await still wakes us
+            let notified = self.notify.notified();
+            tokio::pin!(notified);
+            notified.as_mut().enable();
+
+            if let Some(event) = self.events.lock().iter().find(|event| predicate(event)) {
+                return Some(event.clone());
+            }
+
+            if tokio::time::timeout_at(deadline, notified).await.is_err() {
+                return None;
+            }
+        }
+    }
+
+    /// Snapshot of all captured webhooks, oldest first
+    pub fn snapshot(&self) -> Vec<CapturedWebhook> {
+        self.events.lock().iter().cloned().collect()
+    }
+
+    /// Discard all captured webhooks
+    pub fn clear(&self) {
+        self.events.lock().clear();
+    }
+
+    /// Get capture statistics
+    pub fn stats(&self) -> CaptureStats {
+        CaptureStats {
+            captured: self.next_seq.load(Ordering::Relaxed),
+            evicted: self.evicted.load(Ordering::Relaxed),
+            buffered: self.events.lock().len(),
+            capacity: self.capacity,
+        }
+    }
+}
+
+impl Default for WebhookCapture {
+    fn default() -> Self {
+        Self::new(DEFAULT_CAPTURE_CAPACITY)
+    }
+}
+
+/// Webhook capture statistics
+#[derive(Debug, Clone, Copy)]
+pub struct CaptureStats {
+    pub captured: u64,
+    pub evicted: u64,
+    pub buffered: usize,
+    pub capacity: usize,
+}
@@ -0,0 +1,8 @@
+// packages/engine/src/observability/mod.rs
+//! Observability
+//!
+//! - **tracing**: Log subscriber, OTLP export and scenario traces
+
+pub mod tracing;
+
+pub use self::tracing::{init_tracing, shutdown_tracing, ScenarioTrace, StepSpan, TracingError};
@@ -0,0 +1,269 @@
+// packages/engine/src/observability/tracing.rs
+//! Logging and OpenTelemetry traces of scenario runs
+//!
+//! With `simulation.tracing.endpoint` set in lab.yaml, the engine and every
+//! mock export spans to that collector (passed in as
+//! `OTEL_EXPORTER_OTLP_ENDPOINT`). Each scenario becomes one trace:
+//!
+//! ```text
+//! scenario checkout                          2.4s
+//! ├── step 1 agent_turn                      1.9s
+//! │   ├── POST /v1/chat/completions          1.2s  ⇢ mock-openai http.request
+//! │   └── POST /v1/payment_intents           310ms
+//! └── step 2 assert_state                    4ms
+//! ```
+//!
+//! API call spans are built from the exchanges full-trace capture collects
+//! (`simulation.record_full_trace`) and placed under the step that was
+//! running when the mock received them. Mocks return their request span in
+//! the `traceresponse` header, which becomes a span link, so the mock-side
+//! breakdown (rate limiting, latency simulation, ...) is one click away.
+//! Exchange times come from the mock clock: with a shifted clock, API call
+//! spans sit directly under the scenario.
+
+use crate::executor::trace_capture::CapturedExchange;
+use opentelemetry::trace::{
+    Link, Span, SpanContext, SpanId, SpanKind, Status, TraceContextExt, TraceError, TraceFlags, TraceId,
+    TraceState, Tracer,
+};
+use opentelemetry::{global, Context, KeyValue};
+use opentelemetry_sdk::{runtime, trace as sdktrace, Resource};
+use std::time::{Duration, SystemTime, UNIX_EPOCH};
+use thiserror::Error;
+use tracing::info;
+use tracing_subscriber::layer::SubscriberExt;
+use tracing_subscriber::util::SubscriberInitExt;
+use tracing_subscriber::EnvFilter;
+
+/// Instrumentation name of scenario spans
+const TRACER_NAME: &str = "sentra-lab-engine";
+
+/// Service name when `OTEL_SERVICE_NAME` is not set
+const DEFAULT_SERVICE_NAME: &str = "simulation-engine";
+
+/// Response header in which mocks return their request span
+pub const TRACE_RESPONSE_HEADER: &str = "traceresponse";
+
+/// Tracing setup errors
+#[derive(Debug, Error)]
+pub enum TracingError {
+    #[error("failed to create OTLP exporter: {0}")]
+    Exporter(#[from] TraceError),
+
+    #[error("failed to install log subscriber: {0}")]
+    Subscriber(String),
+}
+
+/// Install the log subscriber (level from `RUST_LOG` or `LOG_LEVEL`) and,
+/// when `OTEL_EXPORTER_OTLP_ENDPOINT` is set, the OTLP trace exporter
+pub fn init_tracing() -> Result<(), TracingError> {
+    let filter = EnvFilter::try_from_default_env()
+        .unwrap_or_else(|_| EnvFilter::new(std::env::var("LOG_LEVEL").unwrap_or_else(|_| "info".into())));
+
+    let endpoint = std::env::var("OTEL_EXPORTER_OTLP_ENDPOINT").ok().filter(|e| !e.is_empty());
+    let otel_layer = match &endpoint {
+        Some(endpoint) => Some(tracing_opentelemetry::layer().with_tracer(install_exporter(endpoint)?)),
+        None => None,
+    };
+
+    tracing_subscriber::registry()
+        .with(otel_layer)
+        .with(filter)
+        .with(tracing_subscriber::fmt::layer())
+        .try_init()
+        .map_err(|e| TracingError::Subscriber(e.to_string()))?;
+
+    if let Some(endpoint) = endpoint {
+        info!("Exporting traces to {}", endpoint);
+    }
+    Ok(())
+}
+
+/// Flush buffered spans; call before the engine exits
+pub fn shutdown_tracing() {
+    global::shutdown_tracer_provider();
+}
+
+/// Install the batch OTLP exporter as the global tracer provider
+fn install_exporter(endpoint: &str) -> Result<sdktrace::Tracer, TraceError> {
+    let endpoint = if endpoint.contains("://") {
+        endpoint.to_string()
+    } else {
+        format!("http://{}", endpoint)
+    };
+    let service_name = std::env::var("OTEL_SERVICE_NAME").unwrap_or_else(|_| DEFAULT_SERVICE_NAME.into());
+
+    opentelemetry_otlp::new_pipeline()
+        .tracing()
+        .with_exporter(opentelemetry_otlp::new_exporter().tonic().with_endpoint(endpoint))
+        .with_trace_config(
+            sdktrace::config().with_resource(Resource::new(vec![KeyValue::new("service.name", service_name)])),
+        )
+        .install_batch(runtime::Tokio)
+}
+
+/// The trace of one scenario run
+pub struct ScenarioTrace {
+    /// Context holding the scenario span
+    cx: Context,
+
+    /// Ended steps, which API calls are assigned to
+    steps: Vec<StepWindow>,
+}
+
+/// When a step ran
+struct StepWindow {
+    cx: Context,
+    started_ms: i64,
+    ended_ms: i64,
+}
+
+/// A running step's span
+pub struct StepSpan {
+    cx: Context,
+    started: SystemTime,
+}
+
+impl ScenarioTrace {
+    /// Start the scenario span (a new trace unless a span is current)
+    pub fn start(run_id: &str, scenario: &str) -> Self {
+        let tracer = global::tracer(TRACER_NAME);
+        let span = tracer
+            .span_builder(format!("scenario {}", scenario))
+            .with_attributes(vec![
+                KeyValue::new("sentra.run_id", run_id.to_string()),
+                KeyValue::new("sentra.scenario", scenario.to_string()),
+            ])
+            .start(&tracer);
+
+        Self {
+            cx: Context::current_with_span(span),
+            steps: Vec::new(),
+        }
+    }
+
+    /// Trace ID to look the run up by, when the trace is being recorded
+    pub fn trace_id(&self) -> Option<String> {
+        let span = self.cx.span();
+        let context = span.span_context();
+        context.is_sampled().then(|| context.trace_id().to_string())
+    }
+
+    /// Start the span of a step (`index` is 0-based)
+    pub fn start_step(&self, index: usize, action: &str) -> StepSpan {
+        let tracer = global::tracer(TRACER_NAME);
+        let started = SystemTime::now();
+        let span = tracer
+            .span_builder(format!("step {} {}", index + 1, action))
+            .with_start_time(started)
+            .with_attributes(vec![
+                KeyValue::new("sentra.step.index", index as i64 + 1),
+                KeyValue::new("sentra.step.action", action.to_string()),
+            ])
+            .start_with_context(&tracer, &self.cx);
+
+        StepSpan {
+            cx: self.cx.with_span(span),
+            started,
+        }
+    }
+
+    /// End a step's span, failed when `error` is set
+    pub fn end_step(&mut self, step: StepSpan, error: Option<&str>) {
+        let ended = SystemTime::now();
+        {
+            let span = step.cx.span();
+            if let Some(error) = error {
+                span.set_status(Status::error(error.to_string()));
+            }
+            span.end_with_timestamp(ended);
+        }
+
+        self.steps.push(StepWindow {
+            started_ms: unix_ms(step.started),
+            ended_ms: unix_ms(ended),
+            cx: step.cx,
+        });
+    }
+
+    /// Add a span per exchange a mock served, under the step that was
+    /// running at the time and linked to the mock's own request span
+    pub fn record_exchanges(&self, service: &str, exchanges: &[CapturedExchange]) {
+        let tracer = global::tracer(TRACER_NAME);
+
+        for exchange in exchanges {
+            let parent = self
+                .steps
+                .iter()
+                .find(|step| (step.started_ms..=step.ended_ms).contains(&exchange.started_at))
+                .map(|step| &step.cx)
+                .unwrap_or(&self.cx);
+
+            let started = UNIX_EPOCH + Duration::from_millis(exchange.started_at.max(0) as u64);
+            let mut builder = tracer
+                .span_builder(format!("{} {}", exchange.method, exchange.path))
+                .with_kind(SpanKind::Client)
+                .with_start_time(started)
+                .with_attributes(vec![
+                    KeyValue::new("peer.service", service.to_string()),
+                    KeyValue::new("http.method", exchange.method.clone()),
+                    KeyValue::new("http.target", exchange.path.clone()),
+                    KeyValue::new("http.status_code", exchange.status as i64),
+                ]);
+
+            if let Some(mock_span) = mock_span_context(exchange) {
+                let attributes = vec![KeyValue::new("sentra.link", "mock request")];
+                builder = builder.with_links(vec![Link::new(mock_span, attributes)]);
+            }
+
+            let mut span = builder.start_with_context(&tracer, parent);
+            if exchange.status >= 400 {
+                span.set_status(Status::error(format!("HTTP {}", exchange.status)));
+            }
+            span.end_with_timestamp(started + Duration::from_millis(exchange.duration_ms));
+        }
+    }
+
+    /// End the scenario span, failed when `error` is set
+    pub fn end(self, error: Option<&str>) {
+        let span = self.cx.span();
+        match error {
+            Some(error) => span.set_status(Status::error(error.to_string())),
+            None => span.set_status(Status::Ok),
+        }
+        span.end();
+    }
+}
+
+/// The mock's request span, from the exchange's `traceresponse` header
+fn mock_span_context(exchange: &CapturedExchange) -> Option<SpanContext> {
+    exchange
+        .response_headers
+        .iter()
+        .find(|(name, _)| name.eq_ignore_ascii_case(TRACE_RESPONSE_HEADER))
+        .and_then(|(_, value)| parse_trace_context(value))
+}
+
+/// Parse a W3C trace-context value ("00-<trace id>-<span id>-<flags>")
+pub fn parse_trace_context(value: &str) -> Option<SpanContext> {
+    let parts: Vec<&str> = value.trim().split('-').collect();
+    let [version, trace_id, span_id, flags] = parts[..] else {
+        return None;
+    };
+    if version != "00" || trace_id.len() != 32 || span_id.len() != 16 {
+        return None;
+    }
+
+    let context = SpanContext::new(
+        TraceId::from_hex(trace_id).ok()?,
+        SpanId::from_hex(span_id).ok()?,
+        TraceFlags::new(u8::from_str_radix(flags, 16).ok()?),
+        true,
+        TraceState::default(),
+    );
+    context.is_valid().then_some(context)
+}
+
+fn unix_ms(time: SystemTime) -> i64 {
+    time.duration_since(UNIX_EPOCH).map(|d| d.as_millis() as i64).unwrap_or(0)
+}
@@ -49,6 +49,8 @@ pub enum EventType {
     ErrorEncountered,
     OutputProduced,
     AgentCompleted,
+    /// A line the agent wrote to stdout or stderr
+    AgentLog,
 }
 
 /// Recorder configuration
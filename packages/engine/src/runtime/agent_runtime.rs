@@ -6,11 +6,16 @@
 //! - Execution of agent code
 //! - State reset between simulations
 //! - Graceful shutdown and cleanup
+//! - Capture of the agent's stdout and stderr for log assertions
+//! - CPU time, memory and wall time limits per scenario
 
+use crate::executor::agent_logs::{spawn_reader, AgentLogCapture, LogStream};
 use crate::runtime::process_manager::{ProcessManager, ProcessType, SpawnConfig};
 use crate::runtime::sandbox::{Sandbox, SandboxConfig};
+use crate::runtime::usage_monitor::{AgentLimits, ProcessUsage, UsageMonitor};
 use crate::utils::errors::{EngineError, Result};
 use std::process::Child;
+use std::sync::Arc;
 use std::time::Duration;
 use tokio::io::{AsyncBufReadExt, AsyncWriteExt, BufReader};
 use tokio::process::ChildStdin;
@@ -34,6 +39,9 @@ pub struct AgentRuntimeConfig {
     
     /// Environment variables
     pub env_vars: Vec<(String, String)>,
+
+    /// Resource limits per scenario; exceeding one kills the agent
+    pub limits: AgentLimits,
 }
 
 impl Default for AgentRuntimeConfig {
@@ -44,6 +52,7 @@ impl Default for AgentRuntimeConfig {
             sandbox: SandboxConfig::default(),
             work_dir: None,
             env_vars: vec![],
+            limits: AgentLimits::default(),
         }
     }
 }
@@ -79,11 +88,20 @@ pub struct AgentRuntime {
     
     /// Runtime handle
     handle: Option<RuntimeHandle>,
+
+    /// Captured stdout and stderr lines
+    logs: Arc<AgentLogCapture>,
+
+    /// Enforces the resource limits (None when no limit is set)
+    monitor: Option<UsageMonitor>,
 }
 
 impl AgentRuntime {
     /// Create and initialize a new agent runtime
     pub async fn new(config: AgentRuntimeConfig) -> Result<Self> {
+        config.limits.validate()
+            .map_err(|e| EngineError::RuntimeError(format!("Invalid agent limits: {}", e)))?;
+        
         let manager = ProcessManager::new();
         let sandbox = Sandbox::new(config.sandbox.clone())?;
         
@@ -94,6 +112,8 @@ impl AgentRuntime {
             manager,
             sandbox,
             handle: None,
+            logs: Arc::new(AgentLogCapture::default()),
+            monitor: None,
         };
         
         // Spawn initial process
@@ -121,6 +141,10 @@ impl AgentRuntime {
         if let Some(pid) = child.id() {
             self.sandbox.apply_limits(pid)?;
             
+            if !self.config.limits.is_empty() {
+                self.monitor = Some(UsageMonitor::start(pid, self.config.limits.clone()));
+            }
+            
             self.handle = Some(RuntimeHandle {
                 pid,
                 process_type: self.config.process_type,
@@ -132,6 +156,12 @@ impl AgentRuntime {
         let stdin = child.stdin.take()
             .ok_or_else(|| EngineError::ProcessSpawnFailed("Failed to capture stdin".into()))?;
         
+        // Drain stderr into the log capture (stdout is captured as responses
+        // are read)
+        if let Some(stderr) = child.stderr.take() {
+            spawn_reader(stderr, LogStream::Stderr, Arc::clone(&self.logs));
+        }
+        
         // Store process and stdin
         *self.process.lock().await = Some(child);
         *self.stdin.lock().await = Some(stdin);
@@ -161,12 +191,26 @@ impl AgentRuntime {
         
         drop(stdin_guard); // Release lock
         
-        // Read response with timeout
+        // Read response with timeout, failing as soon as the agent exceeds
+        // a resource limit
         let timeout = Duration::from_secs(self.config.timeout_secs);
-        let result = tokio::time::timeout(timeout, self.read_response()).await
-            .map_err(|_| EngineError::ExecutionTimeout)?;
+        let response = tokio::time::timeout(timeout, self.read_response());
+        let result = match &self.monitor {
+            Some(monitor) => tokio::select! {
+                result = response => result,
+                exceeded = monitor.exceeded() => {
+                    return Err(EngineError::RuntimeError(exceeded.to_string()));
+                }
+            },
+            None => response.await,
+        };
+        
+        // The process may have been killed while its output was being read
+        if let Some(exceeded) = self.monitor.as_ref().and_then(|m| m.breach()) {
+            return Err(EngineError::RuntimeError(exceeded.to_string()));
+        }
         
-        result
+        result.map_err(|_| EngineError::ExecutionTimeout)?
     }
     
     /// Read response from agent process
@@ -190,6 +234,7 @@ impl AgentRuntime {
                     if line.trim() == "__END__" {
                         break;
                     }
+                    self.logs.record(LogStream::Stdout, &line);
                     output.push_str(&line);
                 }
                 Err(e) => {
@@ -212,6 +257,7 @@ impl AgentRuntime {
         // For now, we restart the process
         // TODO: Implement in-process state reset for faster resets
         self.shutdown().await?;
+        self.logs.clear();
         self.spawn().await?;
         
         Ok(())
@@ -239,6 +285,9 @@ impl AgentRuntime {
     pub async fn shutdown(&mut self) -> Result<()> {
         debug!("Shutting down agent");
         
+        // Stop enforcing limits before the process is killed on purpose
+        self.monitor = None;
+        
         let mut process_guard = self.process.lock().await;
         
         if let Some(mut process) = process_guard.take() {
@@ -273,6 +322,17 @@ impl AgentRuntime {
     pub fn handle(&self) -> Option<&RuntimeHandle> {
         self.handle.as_ref()
     }
+    
+    /// Captured stdout and stderr of the agent
+    pub fn logs(&self) -> Arc<AgentLogCapture> {
+        Arc::clone(&self.logs)
+    }
+    
+    /// CPU time and peak memory used by the current agent process (None
+    /// when no limit is set)
+    pub fn usage(&self) -> Option<ProcessUsage> {
+        self.monitor.as_ref().map(UsageMonitor::usage)
+    }
 }
 
 impl Drop for AgentRuntime {
@@ -8,6 +8,7 @@
 //! - **Process Manager**: Process spawning and management (Python, Node.js, Go)
 //! - **Sandbox**: Isolated execution with resource limits
 //! - **Resource Limiter**: CPU, memory, and network throttling
+//! - **Usage Monitor**: Per-scenario CPU time, memory and wall time limits
 //! - **Work Stealing**: Efficient task scheduling across agent pool
 //!
 //! # Architecture
@@ -43,6 +44,7 @@ pub mod agent_runtime;
 pub mod process_manager;
 pub mod resource_limiter;
 pub mod sandbox;
+pub mod usage_monitor;
 pub mod work_stealing;
 
 // Re-export commonly used types
@@ -51,4 +53,5 @@ pub use agent_runtime::{AgentRuntime, AgentRuntimeConfig, RuntimeHandle};
 pub use process_manager::{ProcessManager, ProcessType, SpawnConfig};
 pub use resource_limiter::{ResourceLimits, ResourceLimiter};
 pub use sandbox::{Sandbox, SandboxConfig};
+pub use usage_monitor::{AgentLimits, LimitExceeded, ProcessUsage, UsageMonitor};
 pub use work_stealing::{WorkStealingScheduler, Task};
\ No newline at end of file
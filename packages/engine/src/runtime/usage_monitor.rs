@@ -0,0 +1,241 @@
+// packages/engine/src/runtime/usage_monitor.rs
+//! Per-scenario resource limits for agent processes
+//!
+//! The sandbox caps CPU share and memory with cgroups, which slows a runaway
+//! agent down but does not fail the scenario. The usage monitor samples the
+//! agent's CPU time and memory from `/proc` while a scenario runs, kills the
+//! process when it goes over a limit and reports why:
+//!
+//! ```yaml
+//! agent:
+//!   limits:
+//!     cpu_time: 30s     # CPU time (user + system) per scenario
+//!     memory_mb: 512    # peak resident memory
+//!     wall_time: 2m     # time the agent process may run per scenario
+//! ```
+//!
+//! A breach fails the scenario with e.g. `resource limit exceeded: memory
+//! 612MB > 512MB`. Usage is read from procfs, so only wall time is enforced
+//! on platforms other than Linux.
+
+use parking_lot::Mutex;
+use serde::{Deserialize, Serialize};
+use std::sync::Arc;
+use std::time::{Duration, Instant};
+use thiserror::Error;
+use tokio::sync::watch;
+use tokio::task::JoinHandle;
+use tracing::{debug, warn};
+
+/// How often usage is sampled
+pub const SAMPLE_INTERVAL: Duration = Duration::from_millis(100);
+
+/// Limits on one agent process for the length of a scenario
+#[derive(Debug, Clone, Default, PartialEq, Serialize, Deserialize)]
+pub struct AgentLimits {
+    /// CPU time (user + system)
+    pub cpu_time: Option<Duration>,
+
+    /// Peak resident memory in megabytes
+    pub memory_mb: Option<u64>,
+
+    /// Time since the process was spawned
+    pub wall_time: Option<Duration>,
+}
+
+impl AgentLimits {
+    /// Whether any limit is set
+    pub fn is_empty(&self) -> bool {
+        self.cpu_time.is_none() && self.memory_mb.is_none() && self.wall_time.is_none()
+    }
+
+    /// Validate the limits
+    pub fn validate(&self) -> Result<(), String> {
+        if self.cpu_time == Some(Duration::ZERO) {
+            return Err("CPU time limit cannot be 0".to_string());
+        }
+        if let Some(memory) = self.memory_mb {
+            if memory < 64 {
+                return Err("Memory limit cannot be less than 64MB".to_string());
+            }
+        }
+        if self.wall_time == Some(Duration::ZERO) {
+            return Err("Wall time limit cannot be 0".to_string());
+        }
+        Ok(())
+    }
+}
+
+/// A limit the agent went over
+#[derive(Debug, Clone, PartialEq, Error)]
+pub enum LimitExceeded {
+    #[error("resource limit exceeded: CPU time {used:.1?} > {limit:.1?}")]
+    CpuTime { used: Duration, limit: Duration },
+
+    #[error("resource limit exceeded: memory {used_mb}MB > {limit_mb}MB")]
+    Memory { used_mb: u64, limit_mb: u64 },
+
+    #[error("resource limit exceeded: wall time {used:.1?} > {limit:.1?}")]
+    WallTime { used: Duration, limit: Duration },
+}
+
+/// Resources a process has used
+#[derive(Debug, Clone, Copy, Default, PartialEq, Serialize)]
+pub struct ProcessUsage {
+    /// CPU time (user + system)
+    pub cpu_time: Duration,
+
+    /// Peak resident memory in megabytes
+    pub peak_memory_mb: u64,
+}
+
+/// Watches one agent process and kills it when it exceeds its limits
+pub struct UsageMonitor {
+    /// Breach, once one happened
+    breach: watch::Receiver<Option<LimitExceeded>>,
+
+    /// Last sampled usage
+    usage: Arc<Mutex<ProcessUsage>>,
+
+    /// Sampling task
+    task: JoinHandle<()>,
+}
+
+impl UsageMonitor {
+    /// Start watching a process; the clock for wall time starts now
+    pub fn start(pid: u32, limits: AgentLimits) -> Self {
+        let (tx, breach) = watch::channel(None);
+        let usage = Arc::new(Mutex::new(ProcessUsage::default()));
+        let sampled = Arc::clone(&usage);
+
+        if !cfg!(target_os = "linux") && (limits.cpu_time.is_some() || limits.memory_mb.is_some()) {
+            warn!("CPU time and memory limits need /proc; only wall time is enforced on this platform");
+        }
+
+        let task = tokio::spawn(async move {
+            let started = Instant::now();
+            let mut interval = tokio::time::interval(SAMPLE_INTERVAL);
+
+            loop {
+                interval.tick().await;
+
+                let current = match read_usage(pid) {
+                    Some(current) => {
+                        *sampled.lock() = current;
+                        Some(current)
+                    }
+                    None if cfg!(target_os = "linux") => break, // Process exited
+                    None => None,
+                };
+
+                if let Some(exceeded) = check(&limits, current, started.elapsed()) {
+                    warn!("Agent process {} killed: {}", pid, exceeded);
+                    kill_process(pid);
+                    let _ = tx.send(Some(exceeded));
+                    break;
+                }
+            }
+
+            debug!("Stopped monitoring agent process {}", pid);
+        });
+
+        Self { breach, usage, task }
+    }
+
+    /// The limit the agent exceeded, if it did
+    pub fn breach(&self) -> Option<LimitExceeded> {
+        self.breach.borrow().clone()
+    }
+
+    /// Resolve once the agent exceeds a limit; pending forever otherwise
+    pub async fn exceeded(&self) -> LimitExceeded {
+        let mut breach = self.breach.clone();
+        loop {
+            if let Some(exceeded) = breach.borrow_and_update().clone() {
+                return exceeded;
+            }
+            if breach.changed().await.is_err() {
+                // Stopped without a breach
+                std::future::pending::<()>().await;
+            }
+        }
+    }
+
+    /// Last sampled usage
+    pub fn usage(&self) -> ProcessUsage {
+        *self.usage.lock()
+    }
+}
+
+impl Drop for UsageMonitor {
+    fn drop(&mut self) {
+        self.task.abort();
+    }
+}
+
+/// Compare usage against limits
+fn check(limits: &AgentLimits, usage: Option<ProcessUsage>, elapsed: Duration) -> Option<LimitExceeded> {
+    if let (Some(limit), Some(usage)) = (limits.cpu_time, usage) {
+        if usage.cpu_time > limit {
+            return Some(LimitExceeded::CpuTime { used: usage.cpu_time, limit });
+        }
+    }
+
+    if let (Some(limit_mb), Some(usage)) = (limits.memory_mb, usage) {
+        if usage.peak_memory_mb > limit_mb {
+            return Some(LimitExceeded::Memory { used_mb: usage.peak_memory_mb, limit_mb });
+        }
+    }
+
+    if let Some(limit) = limits.wall_time {
+        if elapsed > limit {
+            return Some(LimitExceeded::WallTime { used: elapsed, limit });
+        }
+    }
+
+    None
+}
+
+/// Read a process's usage from procfs (None if it has exited)
+#[cfg(target_os = "linux")]
+pub fn read_usage(pid: u32) -> Option<ProcessUsage> {
+    let stat = std::fs::read_to_string(format!("/proc/{}/stat", pid)).ok()?;
+    let status = std::fs::read_to_string(format!("/proc/{}/status", pid)).ok()?;
+
+    // The command name (field 2) may contain spaces; fields after it are
+    // counted from the closing parenthesis, starting at the state (field 3).
+    // utime, stime, cutime and cstime are fields 14-17, in clock ticks.
+    let fields: Vec<&str> = stat[stat.rfind(')')? + 2..].split_whitespace().collect();
+    if fields.first() == Some(&"Z") {
+        return None;
+    }
+    let ticks: u64 = fields.get(11..15)?.iter().filter_map(|f| f.parse::<u64>().ok()).sum();
+    let ticks_per_sec = unsafe { libc::sysconf(libc::_SC_CLK_TCK) }.max(1) as u64;
+
+    // VmHWM is the peak resident set size, so spikes between samples count
+    let peak_kb = status
+        .lines()
+        .find_map(|line| line.strip_prefix("VmHWM:"))
+        .and_then(|value| value.trim().trim_end_matches("kB").trim().parse::<u64>().ok())
+        .unwrap_or(0);
+
+    Some(ProcessUsage {
+        cpu_time: Duration::from_millis(ticks * 1000 / ticks_per_sec),
+        peak_memory_mb: peak_kb / 1024,
+    })
+}
+
+/// Read a process's usage (unsupported off Linux)
+#[cfg(not(target_os = "linux"))]
+pub fn read_usage(_pid: u32) -> Option<ProcessUsage> {
+    None
+}
+
+fn kill_process(pid: u32) {
+    use nix::sys::signal::{kill, Signal};
+    use nix::unistd::Pid;
+
+    if let Err(e) = kill(Pid::from_raw(pid as i32), Signal::SIGKILL) {
+        debug!("Failed to kill agent process {}: {}", pid, e);
+    }
+}
@@ -0,0 +1,258 @@
+// Package main is the entry point for the OpenAI mock server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	_ "time/tzdata" // embed timezones for -timezone in minimal images
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/locale"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+	"github.com/sentra-lab/mocks/openai/internal/server"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// version is set at build time (-ldflags "-X main.version=...")
+var version = "dev"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run wires the server components and blocks until shutdown completes.
+func run() error {
+	config := server.DefaultConfig()
+	config.Version = version
+	flag.IntVar(&config.Port, "port", config.Port, "port to listen on")
+	flag.DurationVar(&config.DrainTimeout, "drain-timeout", config.DrainTimeout, "how long in-flight streams may run after SIGTERM")
+	flag.DurationVar(&config.Images.URLTTL, "image-url-ttl", config.Images.URLTTL, "how long generated image URLs stay valid")
+	if value := os.Getenv("MAX_STREAMS_PER_KEY"); value != "" {
+		maxStreams, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MAX_STREAMS_PER_KEY: %w", err)
+		}
+		config.Streams.MaxPerKey = maxStreams
+	}
+	flag.IntVar(&config.Streams.MaxPerKey, "max-streams-per-key", config.Streams.MaxPerKey, "maximum simultaneous streaming requests per API key, 0 for unlimited (default: $MAX_STREAMS_PER_KEY or 0)")
+	redisURL := flag.String("redis-url", os.Getenv("REDIS_URL"), "Redis URL for shared storage (default: $REDIS_URL or in-memory)")
+	clusterMode := false
+	if value := os.Getenv("CLUSTER_MODE"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid CLUSTER_MODE: %w", err)
+		}
+		clusterMode = enabled
+	}
+	flag.BoolVar(&clusterMode, "cluster-mode", clusterMode, "keep rate limits and usage in the -redis-url storage shared by all replicas (default: $CLUSTER_MODE or false)")
+	rateLimitTier := flag.String("rate-limit-tier", "tier1", "default rate limit tier (free, tier1-tier5)")
+	schedulerConfig := behavior.DefaultSchedulerConfig()
+	flag.IntVar(&schedulerConfig.Capacity, "scheduler-capacity", schedulerConfig.Capacity, "concurrent requests before requests queue by service tier, 0 for no queueing")
+	flag.IntVar(&schedulerConfig.MaxQueueDepth, "scheduler-max-queue-depth", schedulerConfig.MaxQueueDepth, "maximum queued requests per service tier, 0 for unlimited")
+	brownouts := flag.Bool("brownouts", false, "simulate model brownouts: "+behavior.DefaultBrownoutConfig().String())
+	brownoutsFile := flag.String("brownouts-config", "", "YAML file of brownout rules replacing the defaults (implies -brownouts)")
+	experimentsFile := flag.String("experiments", "", "YAML file defining A/B fixture experiments")
+	fixturesDir := flag.String("fixtures", os.Getenv("FIXTURES_DIR"), "directory of fixture files; the \"assistants\" category answers Assistants API runs (default: $FIXTURES_DIR)")
+	calibrationFile := flag.String("token-calibration", "", "YAML file overriding per-SDK prompt token overheads")
+	tokenCache := tokenizer.DefaultCachedTokenizerConfig()
+	flag.IntVar(&tokenCache.CacheSize, "token-cache-size", tokenCache.CacheSize, "token counts kept in memory, 0 to keep them in storage only")
+	flag.DurationVar(&tokenCache.TTL, "token-cache-ttl", tokenCache.TTL, "how long token counts are kept in storage")
+	deprecationsFile := flag.String("model-deprecations", "", "YAML file scheduling simulated model shutdowns")
+	modelsFile := flag.String("models", os.Getenv("MODELS_FILE"), "YAML file registering custom models (default: $MODELS_FILE)")
+	synthesisStrategy := flag.String("synthesis-strategy", os.Getenv("SYNTHESIS_STRATEGY"), "content strategy for unmatched prompts: auto, echo, list, code, refusal (default: $SYNTHESIS_STRATEGY or auto)")
+	jitterDistribution := flag.String("jitter", os.Getenv("JITTER_DISTRIBUTION"), "latency jitter distribution: uniform, gaussian, exponential or longtail (default: $JITTER_DISTRIBUTION or uniform)")
+	nonStreamingTiming := flag.String("non-streaming-latency", "split", "how non-streaming latency is applied: split (TTFT, then per-token time for the output produced) or total")
+	replaySource := flag.String("replay-source", "", "JSON file of recorded exchanges to serve responses from instead of fixtures")
+	replayMatch := flag.String("replay-match", "exact", "how requests are matched to recorded exchanges: exact or fuzzy")
+	replayOnDivergence := flag.String("replay-on-divergence", "fallback", "what happens to requests that differ from the recording: fallback or fail")
+	localeFlag := flag.String("locale", os.Getenv("SENTRA_LOCALE"), "simulated locale, e.g. de-DE (default: $SENTRA_LOCALE or en-US)")
+	timezone := flag.String("timezone", os.Getenv("SENTRA_TIMEZONE"), "simulated IANA timezone, e.g. Europe/Berlin (default: $SENTRA_TIMEZONE or UTC)")
+	currency := flag.String("currency", os.Getenv("SENTRA_CURRENCY"), "simulated ISO 4217 currency, e.g. EUR (default: $SENTRA_CURRENCY or USD)")
+	synthesisProjects := flag.String("synthesis-projects", "", "per-project synthesis strategies (e.g., proj_a=code,proj_b=refusal)")
+	fineTuningTimeline := flag.String("fine-tuning-timeline", "", "validating,queued,running durations of fine-tuning jobs on the virtual clock (default: 5s,10s,1m)")
+	openapiOutput := flag.String("openapi", "", "write the OpenAPI spec of the mock's API to this file (- for stdout) and exit")
+	flag.Parse()
+
+	if *fineTuningTimeline != "" {
+		timeline, err := server.ParseFineTuningTimeline(*fineTuningTimeline)
+		if err != nil {
+			return fmt.Errorf("invalid -fine-tuning-timeline: %w", err)
+		}
+		config.FineTuning = timeline
+	}
+
+	metrics.InitLogger(metrics.DefaultLogConfig())
+	if err := metrics.InitTracing(metrics.TracingConfigFromEnv()); err != nil {
+		return err
+	}
+
+	if *modelsFile != "" {
+		if _, err := models.LoadCustomModels(*modelsFile); err != nil {
+			return fmt.Errorf("failed to load custom models: %w", err)
+		}
+	}
+
+	if clusterMode && *redisURL == "" {
+		return fmt.Errorf("-cluster-mode requires -redis-url")
+	}
+
+	var storage store.RateLimitStorage = store.NewMemoryStore()
+	if *redisURL != "" {
+		redisStore, err := store.NewRedisStoreFromURL(*redisURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		storage = redisStore
+	}
+
+	tracker := pricing.NewTracker(pricing.NewCalculator(pricing.NewPricingDB()), storage)
+	if clusterMode {
+		tracker.EnableClusterMode()
+	}
+
+	tiers := ratelimit.NewTierRegistry(*rateLimitTier)
+	if err := tiers.SetDefaultTier(*rateLimitTier); err != nil {
+		return fmt.Errorf("invalid -rate-limit-tier: %w", err)
+	}
+	limiter := ratelimit.NewLimiter(ratelimit.LimiterConfig{
+		Enabled:      true,
+		TierRegistry: tiers,
+		Storage:      storage,
+		DefaultTier:  *rateLimitTier,
+		ClusterMode:  clusterMode,
+	})
+
+	var experiments *fixtures.Experiments
+	if *experimentsFile != "" {
+		experiments = fixtures.NewExperiments()
+		if err := experiments.LoadExperiments(*experimentsFile); err != nil {
+			return fmt.Errorf("failed to load experiments: %w", err)
+		}
+	}
+
+	var fixtureStore *fixtures.Store
+	if *fixturesDir != "" {
+		fixtureStore = fixtures.NewStore()
+		if err := fixtures.NewLoader(fixtureStore, *fixturesDir).LoadAll(); err != nil {
+			return fmt.Errorf("failed to load fixtures: %w", err)
+		}
+	}
+
+	if err := locale.Configure(locale.Settings{Locale: *localeFlag, Timezone: *timezone, Currency: *currency}); err != nil {
+		return fmt.Errorf("invalid locale settings: %w", err)
+	}
+
+	calibration := tokenizer.NewCalibration()
+	if *calibrationFile != "" {
+		if err := calibration.LoadCalibration(*calibrationFile); err != nil {
+			return fmt.Errorf("failed to load token calibration: %w", err)
+		}
+	}
+
+	baseTokenizer, err := tokenizer.NewTokenizer()
+	if err != nil {
+		return fmt.Errorf("failed to create tokenizer: %w", err)
+	}
+
+	synthesis := generator.DefaultSynthesizerConfig()
+	strategy, err := generator.ParseStrategy(*synthesisStrategy)
+	if err != nil {
+		return fmt.Errorf("invalid -synthesis-strategy: %w", err)
+	}
+	synthesis.DefaultStrategy = strategy
+	if synthesis.ProjectStrategies, err = generator.ParseProjectStrategies(*synthesisProjects); err != nil {
+		return fmt.Errorf("invalid -synthesis-projects: %w", err)
+	}
+
+	latencyConfig := latency.DefaultSimulatorConfig()
+	if latencyConfig.NonStreamingTiming, err = latency.ParseNonStreamingTiming(*nonStreamingTiming); err != nil {
+		return fmt.Errorf("invalid -non-streaming-latency: %w", err)
+	}
+	if latencyConfig.JitterDistribution, err = latency.ParseJitterDistribution(*jitterDistribution); err != nil {
+		return fmt.Errorf("invalid -jitter: %w", err)
+	}
+
+	var scheduler *behavior.PriorityScheduler
+	if schedulerConfig.Capacity > 0 {
+		scheduler = behavior.NewPriorityScheduler(schedulerConfig)
+	}
+
+	var brownoutSimulator *behavior.BrownoutSimulator
+	if *brownouts || *brownoutsFile != "" {
+		brownoutConfig := behavior.DefaultBrownoutConfig()
+		if *brownoutsFile != "" {
+			if brownoutConfig, err = behavior.LoadBrownoutConfig(*brownoutsFile); err != nil {
+				return fmt.Errorf("failed to load brownout config: %w", err)
+			}
+		}
+		brownoutSimulator = behavior.NewBrownoutSimulator(brownoutConfig)
+	}
+
+	srv := server.New(config, server.Dependencies{
+		Tracker:       tracker,
+		Storage:       storage,
+		Scheduler:     scheduler,
+		Experiments:   experiments,
+		Latency:       latency.NewSimulator(latencyConfig),
+		ErrorInjector: behavior.NewErrorInjector(behavior.DefaultErrorInjectorConfig()),
+		Limiter:       limiter,
+		Brownouts:     brownoutSimulator,
+		Calibration:   calibration,
+		Tokenizer:     tokenizer.NewCachedTokenizer(baseTokenizer, storage, tokenCache),
+		Synthesizer:   generator.NewSynthesizer(synthesis),
+		Fixtures:      fixtureStore,
+	})
+
+	if *openapiOutput != "" {
+		return writeOpenAPI(srv, *openapiOutput)
+	}
+
+	if *deprecationsFile != "" {
+		if err := srv.LoadModelDeprecations(*deprecationsFile); err != nil {
+			return fmt.Errorf("failed to load model deprecations: %w", err)
+		}
+	}
+
+	if *replaySource != "" {
+		match, err := server.ParseReplayMatch(*replayMatch)
+		if err != nil {
+			return fmt.Errorf("invalid -replay-match: %w", err)
+		}
+		onDivergence, err := server.ParseReplayDivergencePolicy(*replayOnDivergence)
+		if err != nil {
+			return fmt.Errorf("invalid -replay-on-divergence: %w", err)
+		}
+		if err := srv.LoadReplaySource(*replaySource, match, onDivergence); err != nil {
+			return fmt.Errorf("failed to load replay source: %w", err)
+		}
+	}
+
+	return srv.Run(context.Background())
+}
+
+// writeOpenAPI writes the spec of the API routes as YAML to path ("-" for
+// stdout), for generating it without running the server.
+func writeOpenAPI(srv *server.Server, path string) error {
+	data, err := srv.OpenAPI(false).YAML()
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
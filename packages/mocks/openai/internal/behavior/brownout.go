@@ -0,0 +1,288 @@
+// Package behavior provides behavior simulation.
+// This file simulates per-model brownouts (partial degradation under load).
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// BrownoutRule degrades a model (or family of models) once load crosses a threshold.
+type BrownoutRule struct {
+	// Model is the model ID, or a prefix ending in "*" (e.g., "gpt-4*")
+	Model string `yaml:"model"`
+
+	// LoadThreshold is the RPS above which the brownout starts (0 = always active)
+	LoadThreshold int64 `yaml:"load_threshold"`
+
+	// FailureRate is the fraction of requests rejected during the brownout (0.0 to 1.0)
+	FailureRate float64 `yaml:"failure_rate"`
+
+	// RetryAfter is the Retry-After value in seconds sent with the error
+	RetryAfter int `yaml:"retry_after"`
+}
+
+// String describes the rule, e.g. "above 200 RPS, 25% of gpt-4o requests
+// fail with 503 model_overloaded".
+func (r BrownoutRule) String() string {
+	s := fmt.Sprintf("%g%% of %s requests fail with 503 model_overloaded", r.FailureRate*100, r.Model)
+	if r.LoadThreshold > 0 {
+		s = fmt.Sprintf("above %d RPS, %s", r.LoadThreshold, s)
+	}
+	return s
+}
+
+// validate checks the rule's fields.
+func (r BrownoutRule) validate() error {
+	switch {
+	case r.Model == "":
+		return fmt.Errorf("model is required")
+	case r.LoadThreshold < 0:
+		return fmt.Errorf("%s: load_threshold cannot be negative", r.Model)
+	case r.FailureRate < 0 || r.FailureRate > 1:
+		return fmt.Errorf("%s: failure_rate must be between 0 and 1", r.Model)
+	case r.RetryAfter < 0:
+		return fmt.Errorf("%s: retry_after cannot be negative", r.Model)
+	}
+	return nil
+}
+
+// matches returns true if the rule applies to the model.
+func (r BrownoutRule) matches(model string) bool {
+	if prefix, ok := strings.CutSuffix(r.Model, "*"); ok {
+		return strings.HasPrefix(model, prefix)
+	}
+	return r.Model == model
+}
+
+// BrownoutConfig configures brownout simulation.
+type BrownoutConfig struct {
+	// Enabled controls whether brownouts are simulated
+	Enabled bool
+
+	// Rules are evaluated in order; the first rule matching a model applies
+	Rules []BrownoutRule
+}
+
+// String describes the rules, separated by semicolons.
+func (c BrownoutConfig) String() string {
+	rules := make([]string, len(c.Rules))
+	for i, rule := range c.Rules {
+		rules[i] = rule.String()
+	}
+	return strings.Join(rules, "; ")
+}
+
+// BrownoutFile is the YAML format of brownout rules, listed under
+// brownouts: in the order they are evaluated.
+type BrownoutFile struct {
+	Rules []BrownoutRule `yaml:"brownouts"`
+}
+
+// LoadBrownoutConfig loads an enabled brownout configuration whose rules
+// replace the defaults from a YAML file.
+func LoadBrownoutConfig(path string) (BrownoutConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return BrownoutConfig{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file BrownoutFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return BrownoutConfig{}, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, rule := range file.Rules {
+		if err := rule.validate(); err != nil {
+			return BrownoutConfig{}, err
+		}
+	}
+
+	return BrownoutConfig{Enabled: true, Rules: file.Rules}, nil
+}
+
+// DefaultBrownoutConfig returns default configuration.
+// The flagship model browns out under heavy load while smaller models stay
+// healthy, so agents' model-fallback paths get exercised.
+func DefaultBrownoutConfig() BrownoutConfig {
+	return BrownoutConfig{
+		Enabled: true,
+		Rules: []BrownoutRule{
+			{
+				Model:         "gpt-4o",
+				LoadThreshold: 200,
+				FailureRate:   0.25,
+				RetryAfter:    20,
+			},
+		},
+	}
+}
+
+// BrownoutSimulator returns 503 model_overloaded errors for specific models
+// while load is above their threshold. Unlike ErrorInjector, which degrades
+// every model equally, brownouts let one model fail while others stay healthy.
+type BrownoutSimulator struct {
+	// enabled controls whether brownouts are simulated
+	enabled atomic.Bool
+
+	// rules are the brownout rules (guarded by mu)
+	rules []BrownoutRule
+
+	// mu protects rules
+	mu sync.RWMutex
+
+	// stats tracks brownout statistics
+	totalChecks    atomic.Int64
+	totalBrownouts atomic.Int64
+}
+
+// NewBrownoutSimulator creates a new brownout simulator.
+func NewBrownoutSimulator(config BrownoutConfig) *BrownoutSimulator {
+	bs := &BrownoutSimulator{
+		rules: append([]BrownoutRule(nil), config.Rules...),
+	}
+
+	bs.enabled.Store(config.Enabled)
+
+	return bs
+}
+
+// ShouldBrownout determines if a request for model should be rejected with
+// model_overloaded at the current load.
+func (bs *BrownoutSimulator) ShouldBrownout(ctx context.Context, model string, currentRPS int64) (*models.APIError, bool) {
+	bs.totalChecks.Add(1)
+
+	if !bs.enabled.Load() {
+		return nil, false
+	}
+
+	rule, ok := bs.ruleFor(model)
+	if !ok || currentRPS < rule.LoadThreshold {
+		return nil, false
+	}
+
+	// Roll dice
+	if rand.Float64() >= rule.FailureRate {
+		return nil, false
+	}
+
+	apiError := models.NewModelOverloadedError(model, rule.RetryAfter)
+
+	bs.totalBrownouts.Add(1)
+	metrics.RecordError(model, models.ErrorCodeModelOverloaded)
+
+	return &apiError, true
+}
+
+// IsBrownedOut returns true if model is currently degraded at the given load.
+func (bs *BrownoutSimulator) IsBrownedOut(model string, currentRPS int64) bool {
+	if !bs.enabled.Load() {
+		return false
+	}
+
+	rule, ok := bs.ruleFor(model)
+	return ok && rule.FailureRate > 0 && currentRPS >= rule.LoadThreshold
+}
+
+// ruleFor returns the first rule matching model.
+func (bs *BrownoutSimulator) ruleFor(model string) (BrownoutRule, bool) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	for _, rule := range bs.rules {
+		if rule.matches(model) {
+			return rule, true
+		}
+	}
+
+	return BrownoutRule{}, false
+}
+
+// SetRule adds a rule, replacing any existing rule for the same model pattern.
+func (bs *BrownoutSimulator) SetRule(rule BrownoutRule) {
+	if rule.FailureRate < 0 {
+		rule.FailureRate = 0
+	} else if rule.FailureRate > 1 {
+		rule.FailureRate = 1
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	for i, existing := range bs.rules {
+		if existing.Model == rule.Model {
+			bs.rules[i] = rule
+			return
+		}
+	}
+
+	bs.rules = append(bs.rules, rule)
+}
+
+// RemoveRule removes the rule for a model pattern.
+func (bs *BrownoutSimulator) RemoveRule(model string) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	for i, existing := range bs.rules {
+		if existing.Model == model {
+			bs.rules = append(bs.rules[:i], bs.rules[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetRules returns a copy of the current rules.
+func (bs *BrownoutSimulator) GetRules() []BrownoutRule {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	return append([]BrownoutRule(nil), bs.rules...)
+}
+
+// Enable enables brownout simulation.
+func (bs *BrownoutSimulator) Enable() {
+	bs.enabled.Store(true)
+}
+
+// Disable disables brownout simulation.
+func (bs *BrownoutSimulator) Disable() {
+	bs.enabled.Store(false)
+}
+
+// IsEnabled returns whether brownout simulation is enabled.
+func (bs *BrownoutSimulator) IsEnabled() bool {
+	return bs.enabled.Load()
+}
+
+// GetStats returns brownout statistics.
+func (bs *BrownoutSimulator) GetStats() BrownoutStats {
+	return BrownoutStats{
+		TotalChecks:    bs.totalChecks.Load(),
+		TotalBrownouts: bs.totalBrownouts.Load(),
+		Enabled:        bs.enabled.Load(),
+	}
+}
+
+// ResetStats resets statistics.
+func (bs *BrownoutSimulator) ResetStats() {
+	bs.totalChecks.Store(0)
+	bs.totalBrownouts.Store(0)
+}
+
+// BrownoutStats contains brownout statistics.
+type BrownoutStats struct {
+	TotalChecks    int64
+	TotalBrownouts int64
+	Enabled        bool
+}
@@ -0,0 +1,135 @@
+package behavior
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBrownoutSimulator(t *testing.T) {
+	bs := NewBrownoutSimulator(BrownoutConfig{
+		Enabled: true,
+		Rules: []BrownoutRule{
+			{Model: "gpt-4o", LoadThreshold: 100, FailureRate: 1, RetryAfter: 20},
+			{Model: "gpt-4*", LoadThreshold: 0, FailureRate: 1, RetryAfter: 5},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		model      string
+		rps        int64
+		overloaded bool
+		retryAfter int
+	}{
+		{name: "below threshold", model: "gpt-4o", rps: 99},
+		{name: "at threshold", model: "gpt-4o", rps: 100, overloaded: true, retryAfter: 20},
+		{name: "prefix rule", model: "gpt-4-turbo", rps: 0, overloaded: true, retryAfter: 5},
+		{name: "no rule", model: "gpt-3.5-turbo", rps: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr, overloaded := bs.ShouldBrownout(context.Background(), tt.model, tt.rps)
+			if overloaded != tt.overloaded {
+				t.Fatalf("overloaded = %v, want %v", overloaded, tt.overloaded)
+			}
+			if !overloaded {
+				return
+			}
+			if apiErr.StatusCode != 503 || apiErr.RetryAfter != tt.retryAfter {
+				t.Errorf("error = %d (retry after %d), want 503 (retry after %d)", apiErr.StatusCode, apiErr.RetryAfter, tt.retryAfter)
+			}
+			if !bs.IsBrownedOut(tt.model, tt.rps) {
+				t.Error("IsBrownedOut = false")
+			}
+		})
+	}
+}
+
+func TestBrownoutConfigString(t *testing.T) {
+	config := BrownoutConfig{Rules: []BrownoutRule{
+		{Model: "gpt-4o", LoadThreshold: 200, FailureRate: 0.25},
+		{Model: "gpt-4*", FailureRate: 1},
+	}}
+	want := "above 200 RPS, 25% of gpt-4o requests fail with 503 model_overloaded; 100% of gpt-4* requests fail with 503 model_overloaded"
+	if got := config.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadBrownoutConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []BrownoutRule
+		wantErr bool
+	}{
+		{
+			name: "rules in order",
+			yaml: "brownouts:\n  - model: gpt-4o\n    load_threshold: 50\n    failure_rate: 0.5\n    retry_after: 10\n  - model: \"gpt-4*\"\n    failure_rate: 0.1\n",
+			want: []BrownoutRule{
+				{Model: "gpt-4o", LoadThreshold: 50, FailureRate: 0.5, RetryAfter: 10},
+				{Model: "gpt-4*", FailureRate: 0.1},
+			},
+		},
+		{
+			name:    "missing model",
+			yaml:    "brownouts:\n  - failure_rate: 0.5\n",
+			wantErr: true,
+		},
+		{
+			name:    "failure rate above 1",
+			yaml:    "brownouts:\n  - model: gpt-4o\n    failure_rate: 25\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			yaml:    "brownouts: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "brownouts.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadBrownoutConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadBrownoutConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !config.Enabled || !reflect.DeepEqual(config.Rules, tt.want) {
+				t.Errorf("LoadBrownoutConfig() = %+v, want enabled with %+v", config, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSimulatorRPS(t *testing.T) {
+	tests := []struct {
+		requests int
+		want     int64
+	}{
+		{requests: 1, want: 0},
+		{requests: 10, want: 1},
+		{requests: 2500, want: 250},
+	}
+
+	for _, tt := range tests {
+		ls := NewLoadSimulator()
+		for i := 0; i < tt.requests; i++ {
+			ls.RecordRequest()
+		}
+		if got := ls.GetCurrentRPS(); got != tt.want {
+			t.Errorf("%d requests: RPS = %d, want %d", tt.requests, got, tt.want)
+		}
+	}
+}
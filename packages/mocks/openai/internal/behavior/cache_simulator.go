@@ -112,6 +112,11 @@ func (cs *CacheSimulator) generateCacheKey(req models.ChatCompletionRequest) str
 	for _, msg := range req.Messages {
 		h.Write([]byte(msg.Role))
 		h.Write([]byte(msg.Content))
+		for _, part := range msg.Parts {
+			if part.ImageURL != nil {
+				h.Write([]byte(part.ImageURL.Detail + ":" + part.ImageURL.URL))
+			}
+		}
 	}
 	if req.Temperature != nil {
 		h.Write([]byte("temp"))
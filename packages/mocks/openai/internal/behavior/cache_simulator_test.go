@@ -0,0 +1,53 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestCacheSimulatorImageKeys(t *testing.T) {
+	request := func(url, detail string) models.ChatCompletionRequest {
+		return models.ChatCompletionRequest{
+			Model: "gpt-4o",
+			Messages: []models.Message{{Role: "user", Parts: []models.ContentPart{
+				{Type: "text", Text: "What is this?"},
+				{Type: "image_url", ImageURL: &models.ImageURL{URL: url, Detail: detail}},
+			}}},
+		}
+	}
+	cached := request("https://example.com/cat.png", "low")
+
+	tests := []struct {
+		name    string
+		req     models.ChatCompletionRequest
+		wantHit bool
+	}{
+		{name: "same image", req: request("https://example.com/cat.png", "low"), wantHit: true},
+		{name: "different image", req: request("https://example.com/dog.png", "low")},
+		{name: "different detail", req: request("https://example.com/cat.png", "high")},
+		{name: "no image", req: models.ChatCompletionRequest{Model: "gpt-4o", Messages: []models.Message{{Role: "user"}}}},
+	}
+
+	mem := store.NewMemoryStore()
+	t.Cleanup(func() { mem.Close() })
+
+	cs := NewCacheSimulator(CacheSimulatorConfig{Enabled: true, Storage: mem})
+	if err := cs.StoreInCache(context.Background(), cached, models.ChatCompletionResponse{}); err != nil {
+		t.Fatalf("StoreInCache() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, err := cs.CheckCache(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("CheckCache() error = %v", err)
+			}
+			if hit != tt.wantHit {
+				t.Errorf("CheckCache() = %v, want %v", hit, tt.wantHit)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"context"
 	"math/rand"
 	"sync/atomic"
-	"time"
 
 	"github.com/sentra-lab/mocks/openai/internal/models"
 )
@@ -35,6 +34,9 @@ type ErrorInjector struct {
 	// errorDefinitions defines the types of errors and their probabilities
 	errorDefs []ErrorDefinition
 
+	// defaults is the configuration restored by RestoreDefaults
+	defaults ErrorInjectorConfig
+
 	// stats tracks error injection statistics
 	totalChecks atomic.Int64
 	totalErrors atomic.Int64
@@ -95,6 +97,7 @@ func DefaultErrorInjectorConfig() ErrorInjectorConfig {
 func NewErrorInjector(config ErrorInjectorConfig) *ErrorInjector {
 	injector := &ErrorInjector{
 		errorDefs: config.ErrorDefinitions,
+		defaults:  config,
 	}
 
 	injector.enabled.Store(config.Enabled)
@@ -253,6 +256,17 @@ func (ei *ErrorInjector) SetLoadThreshold(threshold int64) {
 	ei.loadThreshold.Store(threshold)
 }
 
+// RestoreDefaults reverts enablement, rates and thresholds to the
+// configuration the injector was created with.
+func (ei *ErrorInjector) RestoreDefaults() {
+	ei.enabled.Store(ei.defaults.Enabled)
+	ei.baseErrorRate.Store(ei.defaults.BaseErrorRate)
+	ei.burstErrorRate.Store(ei.defaults.BurstErrorRate)
+	ei.quotaErrorRate.Store(ei.defaults.QuotaErrorRate)
+	ei.loadThreshold.Store(ei.defaults.LoadThreshold)
+	ei.quotaThreshold.Store(ei.defaults.QuotaThreshold)
+}
+
 // GetStats returns error injection statistics.
 func (ei *ErrorInjector) GetStats() ErrorInjectorStats {
 	totalChecks := ei.totalChecks.Load()
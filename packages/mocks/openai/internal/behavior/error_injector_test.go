@@ -0,0 +1,93 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestErrorInjectorRestoreDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(ei *ErrorInjector)
+	}{
+		{name: "disabled", change: func(ei *ErrorInjector) { ei.Disable() }},
+		{name: "base error rate", change: func(ei *ErrorInjector) { ei.SetBaseErrorRate(1) }},
+		{name: "burst error rate", change: func(ei *ErrorInjector) { ei.SetBurstErrorRate(1) }},
+		{name: "load threshold", change: func(ei *ErrorInjector) { ei.SetLoadThreshold(1) }},
+		{
+			name: "everything",
+			change: func(ei *ErrorInjector) {
+				ei.Disable()
+				ei.SetBaseErrorRate(0.5)
+				ei.SetBurstErrorRate(0.9)
+				ei.SetLoadThreshold(5)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultErrorInjectorConfig()
+			ei := NewErrorInjector(config)
+
+			tt.change(ei)
+			ei.RestoreDefaults()
+
+			if !ei.IsEnabled() {
+				t.Error("IsEnabled() = false after RestoreDefaults, want true")
+			}
+			if got := ei.GetBaseErrorRate(); got != config.BaseErrorRate {
+				t.Errorf("GetBaseErrorRate() = %v, want %v", got, config.BaseErrorRate)
+			}
+			if got := ei.calculateErrorRate(config.LoadThreshold+1, 0); got != config.BurstErrorRate {
+				t.Errorf("burst error rate = %v, want %v", got, config.BurstErrorRate)
+			}
+			if got := ei.calculateErrorRate(config.LoadThreshold, 0); got != config.BaseErrorRate {
+				t.Errorf("error rate at the load threshold = %v, want %v", got, config.BaseErrorRate)
+			}
+		})
+	}
+}
+
+func TestErrorInjectorCalculateErrorRate(t *testing.T) {
+	config := DefaultErrorInjectorConfig()
+
+	tests := []struct {
+		name       string
+		currentRPS int64
+		quotaUsed  float64
+		want       float64
+	}{
+		{name: "baseline", currentRPS: 10, quotaUsed: 0.5, want: config.BaseErrorRate},
+		{name: "burst traffic", currentRPS: config.LoadThreshold + 1, quotaUsed: 0.5, want: config.BurstErrorRate},
+		{name: "quota pressure", currentRPS: 10, quotaUsed: 0.95, want: config.QuotaErrorRate},
+		{name: "burst wins over quota", currentRPS: config.LoadThreshold + 1, quotaUsed: 0.95, want: config.BurstErrorRate},
+	}
+
+	ei := NewErrorInjector(config)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ei.calculateErrorRate(tt.currentRPS, tt.quotaUsed); got != tt.want {
+				t.Errorf("calculateErrorRate(%d, %v) = %v, want %v", tt.currentRPS, tt.quotaUsed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorInjectorShouldInjectError(t *testing.T) {
+	ei := NewErrorInjector(DefaultErrorInjectorConfig())
+
+	ei.SetBaseErrorRate(1)
+	if _, ok := ei.ShouldInjectError(context.Background(), 0, 0); !ok {
+		t.Error("ShouldInjectError() = false with a base error rate of 1, want true")
+	}
+
+	ei.Disable()
+	if apiErr, ok := ei.ShouldInjectError(context.Background(), 0, 0); ok || apiErr != nil {
+		t.Errorf("ShouldInjectError() = %v, %v while disabled, want nil, false", apiErr, ok)
+	}
+
+	if got := ei.GetStats(); got.TotalChecks != 2 || got.TotalErrors != 1 {
+		t.Errorf("GetStats() checks = %d, errors = %d, want 2 and 1", got.TotalChecks, got.TotalErrors)
+	}
+}
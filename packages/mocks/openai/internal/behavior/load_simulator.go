@@ -3,6 +3,7 @@
 package behavior
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -16,9 +17,15 @@ type LoadSimulator struct {
 	// window is the time window for RPS calculation
 	window time.Duration
 
-	// requestTimestamps stores recent request timestamps
-	timestamps []time.Time
-	timestampsIdx int
+	// counts are the requests in each second of the window, indexed by
+	// Unix second modulo the window length (guarded by mu)
+	counts []int64
+
+	// seconds are the Unix seconds the counts were recorded in
+	seconds []int64
+
+	// mu protects counts and seconds
+	mu sync.Mutex
 
 	// loadThresholds define load levels
 	lowLoad    int64
@@ -28,9 +35,13 @@ type LoadSimulator struct {
 
 // NewLoadSimulator creates a new load simulator.
 func NewLoadSimulator() *LoadSimulator {
+	window := 10 * time.Second
+	slots := int(window / time.Second) // One per second of the window
+
 	return &LoadSimulator{
-		window:     10 * time.Second,
-		timestamps: make([]time.Time, 1000), // Track last 1000 requests
+		window:     window,
+		counts:     make([]int64, slots),
+		seconds:    make([]int64, slots),
 		lowLoad:    10,
 		mediumLoad: 50,
 		highLoad:   100,
@@ -40,11 +51,19 @@ func NewLoadSimulator() *LoadSimulator {
 // RecordRequest records a request for load tracking.
 func (ls *LoadSimulator) RecordRequest() {
 	now := time.Now()
-	
-	// Store timestamp
-	ls.timestamps[ls.timestampsIdx] = now
-	ls.timestampsIdx = (ls.timestampsIdx + 1) % len(ls.timestamps)
-	
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	// Count the request in its second, reusing the slot of an expired one
+	second := now.Unix()
+	slot := second % int64(len(ls.counts))
+	if ls.seconds[slot] != second {
+		ls.seconds[slot] = second
+		ls.counts[slot] = 0
+	}
+	ls.counts[slot]++
+
 	// Calculate current RPS
 	rps := ls.calculateRPS(now)
 	ls.currentRPS.Store(rps)
@@ -52,15 +71,15 @@ func (ls *LoadSimulator) RecordRequest() {
 
 // calculateRPS calculates requests per second in the current window.
 func (ls *LoadSimulator) calculateRPS(now time.Time) int64 {
-	cutoff := now.Add(-ls.window)
+	cutoff := now.Unix() - int64(len(ls.seconds))
 	count := int64(0)
-	
-	for _, ts := range ls.timestamps {
-		if !ts.IsZero() && ts.After(cutoff) {
-			count++
+
+	for i, second := range ls.seconds {
+		if second > cutoff {
+			count += ls.counts[i]
 		}
 	}
-	
+
 	seconds := ls.window.Seconds()
 	if seconds > 0 {
 		return int64(float64(count) / seconds)
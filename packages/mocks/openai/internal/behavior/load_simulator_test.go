@@ -0,0 +1,94 @@
+package behavior
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadSimulatorLoadLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests int
+		wantRPS  int64
+		want     LoadLevel
+	}{
+		{name: "idle", requests: 50, wantRPS: 5, want: LoadIdle},
+		{name: "low", requests: 100, wantRPS: 10, want: LoadLow},
+		{name: "medium", requests: 500, wantRPS: 50, want: LoadMedium},
+		{name: "high", requests: 1000, wantRPS: 100, want: LoadHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := NewLoadSimulator()
+			for i := 0; i < tt.requests; i++ {
+				ls.RecordRequest()
+			}
+
+			if got := ls.GetCurrentRPS(); got != tt.wantRPS {
+				t.Errorf("GetCurrentRPS() = %d, want %d", got, tt.wantRPS)
+			}
+			if got := ls.GetLoadLevel(); got != tt.want {
+				t.Errorf("GetLoadLevel() = %s, want %s", got, tt.want)
+			}
+			if got := ls.IsHighLoad(); got != (tt.want == LoadHigh) {
+				t.Errorf("IsHighLoad() = %v, want %v", got, tt.want == LoadHigh)
+			}
+		})
+	}
+}
+
+func TestLoadSimulatorCalculateRPS(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name string
+		// ages are how many seconds before now each slot was recorded,
+		// with 10 requests in each
+		ages []int64
+		want int64
+	}{
+		{name: "empty", want: 0},
+		{name: "current second", ages: []int64{0}, want: 1},
+		{name: "whole window", ages: []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, want: 10},
+		{name: "expired seconds", ages: []int64{10, 11, 15}, want: 0},
+		{name: "mixed", ages: []int64{0, 9, 12}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := NewLoadSimulator()
+			for _, age := range tt.ages {
+				second := now.Unix() - age
+				slot := second % int64(len(ls.counts))
+				ls.seconds[slot] = second
+				ls.counts[slot] = 10
+			}
+
+			if got := ls.calculateRPS(now); got != tt.want {
+				t.Errorf("calculateRPS() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSimulatorConcurrentRecord(t *testing.T) {
+	ls := NewLoadSimulator()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				ls.RecordRequest()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := ls.GetCurrentRPS(); got != 100 {
+		t.Errorf("GetCurrentRPS() = %d, want 100", got)
+	}
+}
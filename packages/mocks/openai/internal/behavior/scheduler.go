@@ -0,0 +1,269 @@
+// Package behavior provides behavior simulation.
+// This file simulates priority-based request scheduling across service tiers.
+package behavior
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// ErrQueueTimeout is returned when a request waits longer than its tier's
+// queue timeout for a processing slot.
+var ErrQueueTimeout = errors.New("timed out waiting for processing capacity")
+
+// ErrQueueFull is returned when a tier's queue is at its maximum depth.
+var ErrQueueFull = errors.New("request queue is full")
+
+// SchedulerConfig configures the priority scheduler.
+type SchedulerConfig struct {
+	// Capacity is the number of requests processed concurrently
+	// (simulated backend capacity; 0 = unlimited, no queueing)
+	Capacity int
+
+	// MaxQueueDepth is the maximum number of waiting requests per tier (0 = unlimited)
+	MaxQueueDepth int
+
+	// QueueTimeouts is the maximum queue wait per tier
+	// (tiers not listed wait until the request context ends)
+	QueueTimeouts map[models.ServiceTier]time.Duration
+}
+
+// DefaultSchedulerConfig returns default configuration.
+// Queueing is disabled until Capacity is set.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		Capacity:      0,
+		MaxQueueDepth: 1000,
+		QueueTimeouts: map[models.ServiceTier]time.Duration{
+			models.ServiceTierPriority: 60 * time.Second,
+			models.ServiceTierScale:    60 * time.Second,
+			models.ServiceTierDefault:  30 * time.Second,
+			models.ServiceTierFlex:     10 * time.Second,
+		},
+	}
+}
+
+// PriorityScheduler simulates a backend with limited processing capacity.
+// When all slots are busy, requests queue and free slots are handed to the
+// highest-priority tier first (priority/scale, then default, then flex),
+// first-come first-served within a tier. Under sustained load, flex traffic
+// starves and times out while priority traffic sees little queueing.
+type PriorityScheduler struct {
+	// config is the scheduler configuration
+	config SchedulerConfig
+
+	// active is the number of requests holding a slot
+	active int
+
+	// queue holds waiting requests ordered by tier rank, then arrival
+	queue waiterQueue
+
+	// depth is the number of waiting requests per tier
+	depth map[models.ServiceTier]int
+
+	// seq orders arrivals within a tier
+	seq uint64
+
+	// mu protects all fields above
+	mu sync.Mutex
+}
+
+// NewPriorityScheduler creates a new priority scheduler.
+func NewPriorityScheduler(config SchedulerConfig) *PriorityScheduler {
+	return &PriorityScheduler{
+		config: config,
+		depth:  make(map[models.ServiceTier]int),
+	}
+}
+
+// waiter is a request waiting for a slot.
+type waiter struct {
+	tier  models.ServiceTier
+	rank  int
+	seq   uint64
+	ready chan struct{}
+	index int
+}
+
+// Acquire waits for a processing slot for a request in the given tier.
+// It returns a release function that must be called when the request is
+// done, and how long the request was queued.
+func (s *PriorityScheduler) Acquire(ctx context.Context, tier models.ServiceTier) (func(), time.Duration, error) {
+	tier = tier.Resolve()
+	start := time.Now()
+
+	s.mu.Lock()
+
+	// Fast path: unlimited capacity or a free slot with nobody ahead
+	if s.config.Capacity <= 0 || (s.active < s.config.Capacity && s.queue.Len() == 0) {
+		s.active++
+		s.mu.Unlock()
+		return s.releaseFunc(), 0, nil
+	}
+
+	if s.config.MaxQueueDepth > 0 && s.depth[tier] >= s.config.MaxQueueDepth {
+		s.mu.Unlock()
+		return nil, 0, ErrQueueFull
+	}
+
+	s.seq++
+	w := &waiter{
+		tier:  tier,
+		rank:  tier.Rank(),
+		seq:   s.seq,
+		ready: make(chan struct{}),
+	}
+	heap.Push(&s.queue, w)
+	s.depth[tier]++
+	metrics.SetQueueDepth(string(tier), s.depth[tier])
+	s.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if d, ok := s.config.QueueTimeouts[tier]; ok && d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-w.ready:
+		wait := time.Since(start)
+		metrics.RecordQueueWait(string(tier), wait.Seconds())
+		return s.releaseFunc(), wait, nil
+	case <-timeout:
+		return nil, time.Since(start), s.abandon(w, ErrQueueTimeout)
+	case <-ctx.Done():
+		return nil, time.Since(start), s.abandon(w, ctx.Err())
+	}
+}
+
+// abandon removes a waiter that gave up. If the waiter was handed a slot
+// concurrently, that slot is passed on to the next waiter.
+func (s *PriorityScheduler) abandon(w *waiter, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w.index >= 0 {
+		heap.Remove(&s.queue, w.index)
+		s.depth[w.tier]--
+		metrics.SetQueueDepth(string(w.tier), s.depth[w.tier])
+		return err
+	}
+
+	// Granted a slot while giving up: give it to the next waiter
+	s.active--
+	s.dispatch()
+	return err
+}
+
+// releaseFunc returns an idempotent function that frees a slot.
+func (s *PriorityScheduler) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.active--
+			s.dispatch()
+		})
+	}
+}
+
+// dispatch hands free slots to waiters in priority order.
+// Must be called with mu held.
+func (s *PriorityScheduler) dispatch() {
+	for s.active < s.config.Capacity && s.queue.Len() > 0 {
+		w := heap.Pop(&s.queue).(*waiter)
+		s.depth[w.tier]--
+		metrics.SetQueueDepth(string(w.tier), s.depth[w.tier])
+
+		s.active++
+		close(w.ready)
+	}
+}
+
+// SetCapacity changes the simulated processing capacity (0 = unlimited).
+func (s *PriorityScheduler) SetCapacity(capacity int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.Capacity = capacity
+	if capacity <= 0 {
+		// Unlimited: release everyone
+		for s.queue.Len() > 0 {
+			w := heap.Pop(&s.queue).(*waiter)
+			s.depth[w.tier]--
+			metrics.SetQueueDepth(string(w.tier), s.depth[w.tier])
+			s.active++
+			close(w.ready)
+		}
+		return
+	}
+
+	s.dispatch()
+}
+
+// GetStats returns scheduler statistics.
+func (s *PriorityScheduler) GetStats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	depth := make(map[models.ServiceTier]int, len(s.depth))
+	for tier, n := range s.depth {
+		depth[tier] = n
+	}
+
+	return SchedulerStats{
+		Capacity:   s.config.Capacity,
+		Active:     s.active,
+		QueueDepth: depth,
+	}
+}
+
+// SchedulerStats contains scheduler statistics.
+type SchedulerStats struct {
+	Capacity   int
+	Active     int
+	QueueDepth map[models.ServiceTier]int
+}
+
+// waiterQueue is a heap of waiters ordered by tier rank, then arrival.
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waiterQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waiterQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waiterQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
@@ -0,0 +1,145 @@
+package behavior
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// waitQueued waits until n requests are queued for tier.
+func waitQueued(t *testing.T, s *PriorityScheduler, tier models.ServiceTier, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.GetStats().QueueDepth[tier] != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s queue depth = %d, want %d", tier, s.GetStats().QueueDepth[tier], n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrioritySchedulerOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		// arrivals are the tiers of the queued requests, in arrival order
+		arrivals []models.ServiceTier
+		// want is the order they get the slot in
+		want []models.ServiceTier
+	}{
+		{
+			name:     "priority before default before flex",
+			arrivals: []models.ServiceTier{models.ServiceTierFlex, models.ServiceTierDefault, models.ServiceTierPriority},
+			want:     []models.ServiceTier{models.ServiceTierPriority, models.ServiceTierDefault, models.ServiceTierFlex},
+		},
+		{
+			name:     "scale with priority",
+			arrivals: []models.ServiceTier{models.ServiceTierDefault, models.ServiceTierScale, models.ServiceTierFlex, models.ServiceTierPriority},
+			want:     []models.ServiceTier{models.ServiceTierScale, models.ServiceTierPriority, models.ServiceTierDefault, models.ServiceTierFlex},
+		},
+		{
+			name:     "first come first served within a tier",
+			arrivals: []models.ServiceTier{models.ServiceTierFlex, models.ServiceTierFlex, models.ServiceTierDefault},
+			want:     []models.ServiceTier{models.ServiceTierDefault, models.ServiceTierFlex, models.ServiceTierFlex},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPriorityScheduler(SchedulerConfig{Capacity: 1})
+
+			// Saturate the only slot
+			release, _, err := s.Acquire(context.Background(), models.ServiceTierDefault)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type grant struct {
+				tier    models.ServiceTier
+				arrival int
+				release func()
+			}
+			granted := make(chan grant)
+			queued := make(map[models.ServiceTier]int)
+			for i, tier := range tt.arrivals {
+				i, tier := i, tier
+				go func() {
+					release, _, err := s.Acquire(context.Background(), tier)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					granted <- grant{tier: tier, arrival: i, release: release}
+				}()
+				queued[tier.Resolve()]++
+				waitQueued(t, s, tier.Resolve(), queued[tier.Resolve()])
+			}
+
+			// Hand the slot on one request at a time
+			release()
+			arrivals := make(map[models.ServiceTier][]int)
+			for i, want := range tt.want {
+				g := <-granted
+				if g.tier != want {
+					t.Errorf("grant %d = %s, want %s", i, g.tier, want)
+				}
+				arrivals[g.tier] = append(arrivals[g.tier], g.arrival)
+				g.release()
+			}
+
+			for tier, order := range arrivals {
+				for i := 1; i < len(order); i++ {
+					if order[i] < order[i-1] {
+						t.Errorf("%s requests granted in arrival order %v", tier, order)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPrioritySchedulerQueueLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SchedulerConfig
+		want   error
+	}{
+		{
+			name:   "queue full",
+			config: SchedulerConfig{Capacity: 1, MaxQueueDepth: 1},
+			want:   ErrQueueFull,
+		},
+		{
+			name: "queue timeout",
+			config: SchedulerConfig{
+				Capacity:      1,
+				QueueTimeouts: map[models.ServiceTier]time.Duration{models.ServiceTierFlex: 10 * time.Millisecond},
+			},
+			want: ErrQueueTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPriorityScheduler(tt.config)
+			release, _, err := s.Acquire(context.Background(), models.ServiceTierDefault)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer release()
+
+			if tt.config.MaxQueueDepth > 0 {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go s.Acquire(ctx, models.ServiceTierFlex)
+				waitQueued(t, s, models.ServiceTierFlex, 1)
+			}
+
+			if _, _, err := s.Acquire(context.Background(), models.ServiceTierFlex); !errors.Is(err, tt.want) {
+				t.Errorf("Acquire() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Package clock provides the mock's virtual clock.
+// Scenario steps advance it (advance_clock) to trigger rate limit refills,
+// TTL expirations and day rollovers without waiting in real time. Components
+// whose behavior depends on elapsed time read Now instead of time.Now.
+package clock
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// offset is how far the virtual clock runs ahead of real time (nanoseconds).
+var offset atomic.Int64
+
+// Now returns the current virtual time.
+func Now() time.Time {
+	return time.Now().Add(Offset())
+}
+
+// Since returns the virtual time elapsed since t.
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the virtual time remaining until t.
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
+
+// Advance moves the virtual clock forward. The clock never moves backward.
+func Advance(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("cannot advance clock by a negative duration: %s", d)
+	}
+	offset.Add(int64(d))
+	return nil
+}
+
+// Offset returns how far the virtual clock runs ahead of real time.
+func Offset() time.Duration {
+	return time.Duration(offset.Load())
+}
+
+// Reset returns the virtual clock to real time.
+func Reset() {
+	offset.Store(0)
+}
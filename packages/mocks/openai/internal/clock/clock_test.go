@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvance(t *testing.T) {
+	t.Cleanup(Reset)
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "forward", advance: time.Hour, want: time.Hour},
+		{name: "accumulates", advance: 30 * time.Minute, want: 90 * time.Minute},
+		{name: "zero", advance: 0, want: 90 * time.Minute},
+		{name: "backward", advance: -time.Minute, want: 90 * time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Advance(tt.advance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Advance(%s) error = %v, wantErr %v", tt.advance, err, tt.wantErr)
+			}
+			if got := Offset(); got != tt.want {
+				t.Errorf("Offset() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVirtualTime(t *testing.T) {
+	t.Cleanup(Reset)
+
+	start := Now()
+	if err := Advance(25 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := Since(start); elapsed < 25*time.Hour || elapsed > 25*time.Hour+time.Second {
+		t.Errorf("Since(start) = %s, want about 25h", elapsed)
+	}
+	if remaining := Until(start.Add(26 * time.Hour)); remaining > time.Hour || remaining < time.Hour-time.Second {
+		t.Errorf("Until(start+26h) = %s, want about 1h", remaining)
+	}
+
+	Reset()
+	if got := Offset(); got != 0 {
+		t.Errorf("Offset() after Reset = %s, want 0", got)
+	}
+	if drift := Since(time.Now()); drift > time.Second || drift < -time.Second {
+		t.Errorf("Now() after Reset is %s off real time", drift)
+	}
+}
@@ -0,0 +1,394 @@
+// Package fixtures provides response fixture management.
+// This file implements weighted A/B experiments over fixture sets.
+package fixtures
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Headers tagging responses with their experiment assignment.
+const (
+	// HeaderExperiment is the experiment a response was served under
+	HeaderExperiment = "X-Sentra-Experiment"
+
+	// HeaderVariant is the variant a response was served from
+	HeaderVariant = "X-Sentra-Variant"
+
+	// HeaderExperimentUnit is an optional request header (e.g., a scenario run ID).
+	// Requests with the same unit are always assigned the same variant.
+	HeaderExperimentUnit = "X-Sentra-Experiment-Unit"
+)
+
+// Variant is one arm of an experiment.
+type Variant struct {
+	// Name identifies the variant (e.g., "control", "concise")
+	Name string `yaml:"name" json:"name"`
+
+	// Weight is the variant's share of traffic (relative to other variants)
+	Weight float64 `yaml:"weight" json:"weight"`
+
+	// Fixture is the fixture path served for this variant
+	Fixture string `yaml:"fixture" json:"fixture"`
+}
+
+// Experiment splits traffic for a fixture path across variants.
+type Experiment struct {
+	// Name identifies the experiment
+	Name string `yaml:"name" json:"name"`
+
+	// Fixture is the fixture path the experiment replaces when matched
+	Fixture string `yaml:"fixture" json:"fixture"`
+
+	// Variants are the arms of the experiment
+	Variants []Variant `yaml:"variants" json:"variants"`
+}
+
+// ExperimentFile represents a YAML experiments file structure.
+type ExperimentFile struct {
+	// Experiments is the list of experiments
+	Experiments []Experiment `yaml:"experiments"`
+}
+
+// Validate checks that the experiment is well-formed.
+func (e *Experiment) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("experiment name is required")
+	}
+	if e.Fixture == "" {
+		return fmt.Errorf("experiment %s: fixture is required", e.Name)
+	}
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("experiment %s: at least 2 variants are required", e.Name)
+	}
+
+	seen := make(map[string]bool, len(e.Variants))
+	for i, v := range e.Variants {
+		if v.Name == "" {
+			return fmt.Errorf("experiment %s: variant[%d]: name is required", e.Name, i)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("experiment %s: duplicate variant %s", e.Name, v.Name)
+		}
+		seen[v.Name] = true
+
+		if v.Fixture == "" {
+			return fmt.Errorf("experiment %s: variant %s: fixture is required", e.Name, v.Name)
+		}
+		if v.Weight < 0 {
+			return fmt.Errorf("experiment %s: variant %s: weight cannot be negative", e.Name, v.Name)
+		}
+	}
+
+	return nil
+}
+
+// Assignment is the variant a request was assigned to.
+type Assignment struct {
+	// Experiment is the experiment name
+	Experiment string
+
+	// Variant is the variant name
+	Variant string
+
+	// Fixture is the fixture path to serve
+	Fixture string
+}
+
+// SetExperimentHeaders tags a response with its experiment assignment.
+func SetExperimentHeaders(w http.ResponseWriter, assignment *Assignment) {
+	if assignment == nil {
+		return
+	}
+	w.Header().Set(HeaderExperiment, assignment.Experiment)
+	w.Header().Set(HeaderVariant, assignment.Variant)
+}
+
+// Experiments manages active experiments and their per-variant results.
+type Experiments struct {
+	// experiments maps experiment names to experiments
+	experiments map[string]*Experiment
+
+	// byFixture maps fixture paths to experiment names
+	byFixture map[string]string
+
+	// results maps experiment name -> variant name -> results
+	results map[string]map[string]*variantResults
+
+	// mu protects all fields above
+	mu sync.RWMutex
+}
+
+// variantResults accumulates results for one variant.
+type variantResults struct {
+	assignments int64
+	outcomes    map[string]int64
+}
+
+// NewExperiments creates an empty experiment registry.
+func NewExperiments() *Experiments {
+	return &Experiments{
+		experiments: make(map[string]*Experiment),
+		byFixture:   make(map[string]string),
+		results:     make(map[string]map[string]*variantResults),
+	}
+}
+
+// LoadExperiments loads experiments from a YAML file.
+func (x *Experiments) LoadExperiments(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file ExperimentFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, experiment := range file.Experiments {
+		if err := x.Add(experiment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Add registers an experiment, replacing one with the same name.
+// Results for a replaced experiment are reset.
+func (x *Experiments) Add(experiment Experiment) error {
+	if err := experiment.Validate(); err != nil {
+		return err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if other, ok := x.byFixture[experiment.Fixture]; ok && other != experiment.Name {
+		return fmt.Errorf("experiment %s: fixture %s is already used by experiment %s", experiment.Name, experiment.Fixture, other)
+	}
+	if existing, ok := x.experiments[experiment.Name]; ok {
+		delete(x.byFixture, existing.Fixture)
+	}
+
+	results := make(map[string]*variantResults, len(experiment.Variants))
+	for _, v := range experiment.Variants {
+		results[v.Name] = &variantResults{outcomes: make(map[string]int64)}
+	}
+
+	x.experiments[experiment.Name] = &experiment
+	x.byFixture[experiment.Fixture] = experiment.Name
+	x.results[experiment.Name] = results
+
+	return nil
+}
+
+// Remove unregisters an experiment and discards its results.
+func (x *Experiments) Remove(name string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if experiment, ok := x.experiments[name]; ok {
+		delete(x.byFixture, experiment.Fixture)
+		delete(x.experiments, name)
+		delete(x.results, name)
+	}
+}
+
+// Assign picks a variant for a request that matched fixturePath.
+// With a non-empty unit the assignment is deterministic (same unit, same
+// variant); otherwise it is weighted random. Returns false if no experiment
+// runs on fixturePath.
+func (x *Experiments) Assign(fixturePath string, unit string) (*Assignment, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	name, ok := x.byFixture[fixturePath]
+	if !ok {
+		return nil, false
+	}
+	experiment := x.experiments[name]
+
+	variant := selectVariant(experiment, unit)
+	x.results[name][variant.Name].assignments++
+
+	return &Assignment{
+		Experiment: experiment.Name,
+		Variant:    variant.Name,
+		Fixture:    variant.Fixture,
+	}, true
+}
+
+// VariantForUnit returns the variant a unit is (deterministically) assigned to.
+func (x *Experiments) VariantForUnit(experimentName string, unit string) (string, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	experiment, ok := x.experiments[experimentName]
+	if !ok {
+		return "", fmt.Errorf("experiment not found: %s", experimentName)
+	}
+	if unit == "" {
+		return "", fmt.Errorf("unit is required")
+	}
+
+	return selectVariant(experiment, unit).Name, nil
+}
+
+// RecordOutcome records a scenario outcome (e.g., "passed", "failed") for a variant.
+func (x *Experiments) RecordOutcome(experimentName string, variantName string, outcome string) error {
+	if outcome == "" {
+		return fmt.Errorf("outcome is required")
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	variants, ok := x.results[experimentName]
+	if !ok {
+		return fmt.Errorf("experiment not found: %s", experimentName)
+	}
+	results, ok := variants[variantName]
+	if !ok {
+		return fmt.Errorf("variant not found: %s/%s", experimentName, variantName)
+	}
+
+	results.outcomes[outcome]++
+	return nil
+}
+
+// selectVariant picks a variant by weight. A non-empty unit is hashed onto
+// the weight range so its assignment is stable.
+func selectVariant(experiment *Experiment, unit string) Variant {
+	totalWeight := 0.0
+	for _, v := range experiment.Variants {
+		totalWeight += variantWeight(v)
+	}
+
+	var r float64
+	if unit != "" {
+		h := fnv.New64a()
+		h.Write([]byte(experiment.Name))
+		h.Write([]byte(":"))
+		h.Write([]byte(unit))
+		r = float64(h.Sum64()%10000) / 10000 * totalWeight
+	} else {
+		r = rand.Float64() * totalWeight
+	}
+
+	cumulative := 0.0
+	for _, v := range experiment.Variants {
+		cumulative += variantWeight(v)
+		if r < cumulative {
+			return v
+		}
+	}
+
+	return experiment.Variants[len(experiment.Variants)-1]
+}
+
+// variantWeight returns the variant weight (default: 1.0).
+func variantWeight(v Variant) float64 {
+	if v.Weight == 0 {
+		return 1.0
+	}
+	return v.Weight
+}
+
+// ExperimentReport summarizes an experiment's per-variant results.
+type ExperimentReport struct {
+	// Name is the experiment name
+	Name string `json:"name"`
+
+	// Fixture is the fixture path under experiment
+	Fixture string `json:"fixture"`
+
+	// Variants are the per-variant results, in configuration order
+	Variants []VariantReport `json:"variants"`
+}
+
+// VariantReport summarizes one variant's results.
+type VariantReport struct {
+	// Name is the variant name
+	Name string `json:"name"`
+
+	// Weight is the configured traffic weight
+	Weight float64 `json:"weight"`
+
+	// Assignments is the number of responses served from this variant
+	Assignments int64 `json:"assignments"`
+
+	// Outcomes maps outcome names to counts
+	Outcomes map[string]int64 `json:"outcomes"`
+
+	// PassRate is the fraction of reported outcomes that are "passed" (0-100)
+	PassRate float64 `json:"pass_rate"`
+}
+
+// Report returns per-variant results for all experiments, sorted by name.
+func (x *Experiments) Report() []ExperimentReport {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	reports := make([]ExperimentReport, 0, len(x.experiments))
+	for name, experiment := range x.experiments {
+		report := ExperimentReport{
+			Name:     name,
+			Fixture:  experiment.Fixture,
+			Variants: make([]VariantReport, 0, len(experiment.Variants)),
+		}
+
+		for _, v := range experiment.Variants {
+			results := x.results[name][v.Name]
+
+			outcomes := make(map[string]int64, len(results.outcomes))
+			var total int64
+			for outcome, count := range results.outcomes {
+				outcomes[outcome] = count
+				total += count
+			}
+
+			var passRate float64
+			if total > 0 {
+				passRate = float64(outcomes["passed"]) / float64(total) * 100
+			}
+
+			report.Variants = append(report.Variants, VariantReport{
+				Name:        v.Name,
+				Weight:      variantWeight(v),
+				Assignments: results.assignments,
+				Outcomes:    outcomes,
+				PassRate:    passRate,
+			})
+		}
+
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Name < reports[j].Name
+	})
+
+	return reports
+}
+
+// ResetResults clears assignment and outcome counts for all experiments.
+func (x *Experiments) ResetResults() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, variants := range x.results {
+		for _, results := range variants {
+			results.assignments = 0
+			results.outcomes = make(map[string]int64)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// newExperiment creates an experiment splitting responses/chat/greeting.yaml
+// between a control and a concise variant.
+func newExperiment(controlWeight, conciseWeight float64) Experiment {
+	return Experiment{
+		Name:    "tone",
+		Fixture: "responses/chat/greeting.yaml",
+		Variants: []Variant{
+			{Name: "control", Weight: controlWeight, Fixture: "responses/chat/greeting.yaml"},
+			{Name: "concise", Weight: conciseWeight, Fixture: "responses/chat/greeting-concise.yaml"},
+		},
+	}
+}
+
+func TestExperimentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Experiment)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*Experiment) {}},
+		{name: "no name", modify: func(e *Experiment) { e.Name = "" }, wantErr: "name is required"},
+		{name: "no fixture", modify: func(e *Experiment) { e.Fixture = "" }, wantErr: "fixture is required"},
+		{name: "one variant", modify: func(e *Experiment) { e.Variants = e.Variants[:1] }, wantErr: "at least 2 variants"},
+		{name: "unnamed variant", modify: func(e *Experiment) { e.Variants[1].Name = "" }, wantErr: "variant[1]: name is required"},
+		{name: "duplicate variant", modify: func(e *Experiment) { e.Variants[1].Name = "control" }, wantErr: "duplicate variant control"},
+		{name: "variant without fixture", modify: func(e *Experiment) { e.Variants[0].Fixture = "" }, wantErr: "variant control: fixture is required"},
+		{name: "negative weight", modify: func(e *Experiment) { e.Variants[0].Weight = -1 }, wantErr: "weight cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			experiment := newExperiment(1, 1)
+			tt.modify(&experiment)
+
+			err := experiment.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExperimentsAdd(t *testing.T) {
+	x := NewExperiments()
+	if err := x.Add(newExperiment(1, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	other := newExperiment(1, 1)
+	other.Name = "length"
+	if err := x.Add(other); err == nil {
+		t.Error("second experiment on the same fixture was accepted")
+	}
+
+	// Replacing an experiment moves it to its new fixture
+	moved := newExperiment(1, 1)
+	moved.Fixture = "responses/chat/farewell.yaml"
+	if err := x.Add(moved); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := x.Assign("responses/chat/greeting.yaml", ""); ok {
+		t.Error("the replaced experiment still runs on its old fixture")
+	}
+	if _, ok := x.Assign("responses/chat/farewell.yaml", ""); !ok {
+		t.Error("the replaced experiment does not run on its new fixture")
+	}
+
+	x.Remove("tone")
+	if _, ok := x.Assign("responses/chat/farewell.yaml", ""); ok {
+		t.Error("the removed experiment still runs")
+	}
+}
+
+func TestExperimentsAssign(t *testing.T) {
+	tests := []struct {
+		name          string
+		controlWeight float64
+		conciseWeight float64
+		// wantConcise is the expected share of the concise variant
+		wantConcise float64
+	}{
+		{name: "even", controlWeight: 1, conciseWeight: 1, wantConcise: 0.5},
+		{name: "default weights", controlWeight: 0, conciseWeight: 0, wantConcise: 0.5},
+		{name: "weighted", controlWeight: 3, conciseWeight: 1, wantConcise: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := NewExperiments()
+			if err := x.Add(newExperiment(tt.controlWeight, tt.conciseWeight)); err != nil {
+				t.Fatal(err)
+			}
+
+			const units = 4000
+			concise := 0
+			for i := 0; i < units; i++ {
+				unit := fmt.Sprintf("run-%d", i)
+				assignment, ok := x.Assign("responses/chat/greeting.yaml", unit)
+				if !ok {
+					t.Fatal("no experiment assigned")
+				}
+
+				// The same unit always gets the same variant
+				again, _ := x.Assign("responses/chat/greeting.yaml", unit)
+				if again.Variant != assignment.Variant {
+					t.Fatalf("unit %s was assigned %s, then %s", unit, assignment.Variant, again.Variant)
+				}
+				if want, _ := x.VariantForUnit("tone", unit); want != assignment.Variant {
+					t.Fatalf("VariantForUnit(%s) = %s, assigned %s", unit, want, assignment.Variant)
+				}
+
+				if assignment.Variant == "concise" {
+					concise++
+					if assignment.Fixture != "responses/chat/greeting-concise.yaml" {
+						t.Fatalf("concise variant serves %s", assignment.Fixture)
+					}
+				}
+			}
+
+			if share := float64(concise) / units; share < tt.wantConcise-0.05 || share > tt.wantConcise+0.05 {
+				t.Errorf("concise share = %.3f, want about %.2f", share, tt.wantConcise)
+			}
+		})
+	}
+
+	x := NewExperiments()
+	if _, ok := x.Assign("responses/chat/greeting.yaml", "run-1"); ok {
+		t.Error("assigned a variant without an experiment")
+	}
+}
+
+func TestExperimentsReport(t *testing.T) {
+	x := NewExperiments()
+	if err := x.Add(newExperiment(1, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		x.Assign("responses/chat/greeting.yaml", "")
+	}
+
+	outcomes := []struct {
+		variant string
+		outcome string
+		wantErr bool
+	}{
+		{variant: "control", outcome: "passed"},
+		{variant: "control", outcome: "passed"},
+		{variant: "control", outcome: "failed"},
+		{variant: "concise", outcome: "failed"},
+		{variant: "unknown", outcome: "passed", wantErr: true},
+		{variant: "control", outcome: "", wantErr: true},
+	}
+	for _, o := range outcomes {
+		if err := x.RecordOutcome("tone", o.variant, o.outcome); (err != nil) != o.wantErr {
+			t.Errorf("RecordOutcome(%s, %q) error = %v, wantErr %v", o.variant, o.outcome, err, o.wantErr)
+		}
+	}
+	if err := x.RecordOutcome("missing", "control", "passed"); err == nil {
+		t.Error("recorded an outcome for an unknown experiment")
+	}
+
+	report := x.Report()
+	if len(report) != 1 || len(report[0].Variants) != 2 {
+		t.Fatalf("Report() = %+v, want one experiment with two variants", report)
+	}
+
+	control, concise := report[0].Variants[0], report[0].Variants[1]
+	if got := control.Assignments + concise.Assignments; got != 3 {
+		t.Errorf("assignments = %d, want 3", got)
+	}
+	if control.PassRate < 66.6 || control.PassRate > 66.7 {
+		t.Errorf("control pass rate = %.2f, want 66.67", control.PassRate)
+	}
+	if concise.PassRate != 0 {
+		t.Errorf("concise pass rate = %.2f, want 0", concise.PassRate)
+	}
+
+	x.ResetResults()
+	for _, variant := range x.Report()[0].Variants {
+		if variant.Assignments != 0 || len(variant.Outcomes) != 0 {
+			t.Errorf("variant %s not reset: %+v", variant.Name, variant)
+		}
+	}
+}
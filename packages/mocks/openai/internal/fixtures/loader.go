@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/sentra-lab/mocks/openai/internal/generator"
 	"github.com/sentra-lab/mocks/openai/internal/metrics"
 )
 
@@ -193,8 +195,12 @@ func (l *Loader) validateFixtureFile(file *FixtureFile) error {
 			return fmt.Errorf("fixture %d: missing ID", i)
 		}
 
-		if fixture.Content == "" && fixture.FunctionCall == nil {
-			return fmt.Errorf("fixture %s: missing content or function_call", fixture.ID)
+		if fixture.Content == "" && fixture.FunctionCall == nil && len(fixture.ToolCalls) == 0 && fixture.SchemaViolation == "" {
+			return fmt.Errorf("fixture %s: missing content, function_call, tool_calls or schema_violation", fixture.ID)
+		}
+
+		if fixture.SchemaViolation != "" && !slices.Contains(generator.SchemaViolations, fixture.SchemaViolation) {
+			return fmt.Errorf("fixture %s: unknown schema_violation %q", fixture.ID, fixture.SchemaViolation)
 		}
 
 		// Validate weight
@@ -3,10 +3,14 @@
 package fixtures
 
 import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"regexp"
 	"strings"
 	"sync"
 
+	"github.com/sentra-lab/mocks/openai/internal/generator"
 	"github.com/sentra-lab/mocks/openai/internal/models"
 )
 
@@ -26,6 +30,12 @@ type Matcher struct {
 
 	// defaultPath is the fallback fixture path
 	defaultPath string
+
+	// experiments swaps matched fixture paths for experiment variants (optional)
+	experiments *Experiments
+
+	// synthesizer builds content when no pattern matches (optional)
+	synthesizer *generator.Synthesizer
 }
 
 // PatternConfig configures a pattern for matching.
@@ -90,21 +100,141 @@ func (m *Matcher) AddPatterns(configs []PatternConfig) error {
 
 // Match matches a prompt to a fixture using patterns.
 func (m *Matcher) Match(messages []models.Message) (*Fixture, error) {
+	fixture, _, err := m.MatchForUnit(messages, "")
+	return fixture, err
+}
+
+// MatchForUnit matches a prompt to a fixture and, if an experiment runs on
+// the matched fixture path, serves the assigned variant instead. A non-empty
+// unit (e.g., the X-Sentra-Experiment-Unit header) pins the variant. The
+// assignment is nil when no experiment applies.
+func (m *Matcher) MatchForUnit(messages []models.Message, unit string) (*Fixture, *Assignment, error) {
+	return m.MatchForProject(messages, unit, "")
+}
+
+// MatchForProject is MatchForUnit for a request scoped to an OpenAI-Project.
+// When no pattern matches and no default path is set, a configured
+// synthesizer builds the response using the project's strategy instead of
+// serving generic filler.
+func (m *Matcher) MatchForProject(messages []models.Message, unit string, project string) (*Fixture, *Assignment, error) {
+	return m.matchForProject(messages, unit, project, nil)
+}
+
+// MatchSeeded is MatchForProject for a request with a seed: the fixture is
+// picked with a random source derived from the seed and, without a unit,
+// the seed pins the experiment variant, so identical requests are served
+// the same fixture.
+func (m *Matcher) MatchSeeded(messages []models.Message, unit string, project string, seed int) (*Fixture, *Assignment, error) {
+	if unit == "" {
+		unit = fmt.Sprintf("seed:%d", seed)
+	}
+	return m.matchForProject(messages, unit, project, models.NewSeededRand(seed))
+}
+
+// matchForProject matches a prompt, picking among a path's fixtures with
+// rng (nil: the global source).
+func (m *Matcher) matchForProject(messages []models.Message, unit string, project string, rng *rand.Rand) (*Fixture, *Assignment, error) {
 	// Extract text from messages
 	text := m.extractText(messages)
 
 	// Try to match patterns
-	if fixturePath := m.matchPatterns(text); fixturePath != "" {
-		return m.store.GetWeighted(fixturePath)
-	}
+	fixturePath := m.matchPatterns(text)
 
 	// Fall back to default path
-	if m.defaultPath != "" {
-		return m.store.GetWeighted(m.defaultPath)
+	if fixturePath == "" {
+		fixturePath = m.GetDefaultPath()
+	}
+
+	// Synthesize rather than serve generic filler
+	if fixturePath == "" {
+		m.mu.RLock()
+		synthesizer := m.synthesizer
+		m.mu.RUnlock()
+
+		if synthesizer != nil {
+			return synthesizedFixture(synthesizer, messages, project, text), nil, nil
+		}
 	}
 
 	// If no default, return generic response
-	return m.store.GetWeighted("responses/chat/generic.yaml")
+	if fixturePath == "" {
+		fixturePath = "responses/chat/generic.yaml"
+	}
+
+	return m.getForExperiment(fixturePath, unit, models.HasImages(messages), rng)
+}
+
+// getForExperiment selects a fixture from fixturePath, or from the assigned
+// variant's path when an experiment runs on fixturePath, preferring the
+// fixtures for prompts with or without images as hasImages says.
+func (m *Matcher) getForExperiment(fixturePath string, unit string, hasImages bool, rng *rand.Rand) (*Fixture, *Assignment, error) {
+	m.mu.RLock()
+	experiments := m.experiments
+	m.mu.RUnlock()
+
+	if experiments == nil {
+		fixture, err := m.store.GetWeightedForImagesWith(fixturePath, hasImages, rng)
+		return fixture, nil, err
+	}
+
+	assignment, ok := experiments.Assign(fixturePath, unit)
+	if !ok {
+		fixture, err := m.store.GetWeightedForImagesWith(fixturePath, hasImages, rng)
+		return fixture, nil, err
+	}
+
+	fixture, err := m.store.GetWeightedForImagesWith(assignment.Fixture, hasImages, rng)
+	if err != nil {
+		return nil, nil, fmt.Errorf("experiment %s variant %s: %w", assignment.Experiment, assignment.Variant, err)
+	}
+
+	// Tag the fixture (a copy) with its assignment
+	metadata := make(map[string]interface{}, len(fixture.Metadata)+2)
+	for k, v := range fixture.Metadata {
+		metadata[k] = v
+	}
+	metadata["experiment"] = assignment.Experiment
+	metadata["variant"] = assignment.Variant
+	fixture.Metadata = metadata
+
+	return fixture, assignment, nil
+}
+
+// SetExperiments enables A/B experiments for matched fixtures.
+func (m *Matcher) SetExperiments(experiments *Experiments) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.experiments = experiments
+}
+
+// SetSynthesizer enables response synthesis for unmatched prompts.
+func (m *Matcher) SetSynthesizer(synthesizer *generator.Synthesizer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.synthesizer = synthesizer
+}
+
+// synthesizedFixture wraps synthesized content in a fixture. The seed is
+// derived from the prompt text, so the same prompt gets the same content.
+func synthesizedFixture(synthesizer *generator.Synthesizer, messages []models.Message, project string, text string) *Fixture {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+
+	synthesis := synthesizer.Synthesize(messages, project, int64(h.Sum64()))
+
+	return &Fixture{
+		ID:           "synthesized-" + string(synthesis.Strategy),
+		Content:      synthesis.Content,
+		Role:         "assistant",
+		FinishReason: "stop",
+		Weight:       1.0,
+		Metadata: map[string]interface{}{
+			"synthesized": true,
+			"strategy":    string(synthesis.Strategy),
+		},
+	}
 }
 
 // MatchText matches plain text to a fixture.
@@ -128,7 +258,7 @@ func (m *Matcher) MatchWithCategory(messages []models.Message, preferredCategory
 
 	// Try to match patterns
 	if fixturePath := m.matchPatterns(text); fixturePath != "" {
-		return m.store.GetWeighted(fixturePath)
+		return m.store.GetWeightedForImages(fixturePath, models.HasImages(messages))
 	}
 
 	// Try preferred category
@@ -0,0 +1,201 @@
+package fixtures
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+const (
+	greetingPath = "responses/chat/greeting.yaml"
+	concisePath  = "responses/chat/greeting-concise.yaml"
+)
+
+// newGreetingMatcher creates a matcher answering greetings from a set of
+// weighted fixtures, with separate answers for prompts with images.
+func newGreetingMatcher(t *testing.T) *Matcher {
+	t.Helper()
+	withImages, withoutImages := true, false
+
+	store := NewStore()
+	store.Add(greetingPath, FixtureFile{Responses: []Fixture{
+		{ID: "hello", Content: "Hello!", Images: &withoutImages},
+		{ID: "hi", Content: "Hi there!", Images: &withoutImages},
+		{ID: "hey", Content: "Hey!", Images: &withoutImages},
+		{ID: "photo", Content: "Nice photo!", Images: &withImages},
+	}})
+	store.Add(concisePath, FixtureFile{Responses: []Fixture{{ID: "concise", Content: "Hi."}}})
+
+	matcher := NewMatcher(store, "")
+	if err := matcher.AddPattern(PatternConfig{Name: "greeting", Regex: `\bhello\b`, Fixture: greetingPath, CaseInsensitive: true}); err != nil {
+		t.Fatal(err)
+	}
+	return matcher
+}
+
+// userMessages decodes a user message with the given content (a string or
+// a content array).
+func userMessages(t *testing.T, content string) []models.Message {
+	t.Helper()
+	return decodeRequest(t, `{"messages": [{"role": "user", "content": `+content+`}]}`).Messages
+}
+
+func TestMatchImages(t *testing.T) {
+	matcher := newGreetingMatcher(t)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "text", content: `"Hello!"`, want: []string{"hello", "hi", "hey"}},
+		{name: "image", content: `[{"type": "text", "text": "Hello, what is this?"}, {"type": "image_url", "image_url": {"url": "https://example.com/cat.png"}}]`, want: []string{"photo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				fixture, err := matcher.Match(userMessages(t, tt.content))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !contains(tt.want, fixture.ID) {
+					t.Fatalf("matched %s, want one of %v", fixture.ID, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchSeeded(t *testing.T) {
+	matcher := newGreetingMatcher(t)
+	messages := userMessages(t, `"Hello!"`)
+
+	seen := map[string]bool{}
+	for seed := 0; seed < 50; seed++ {
+		first, _, err := matcher.MatchSeeded(messages, "", "", seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			again, _, err := matcher.MatchSeeded(messages, "", "", seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.ID != first.ID {
+				t.Fatalf("seed %d matched %s, then %s", seed, first.ID, again.ID)
+			}
+		}
+		seen[first.ID] = true
+	}
+
+	if len(seen) < 2 {
+		t.Errorf("50 seeds all matched %v", seen)
+	}
+}
+
+func TestMatchExperiment(t *testing.T) {
+	matcher := newGreetingMatcher(t)
+	experiments := NewExperiments()
+	if err := experiments.Add(Experiment{
+		Name:    "tone",
+		Fixture: greetingPath,
+		Variants: []Variant{
+			{Name: "control", Fixture: greetingPath},
+			{Name: "concise", Fixture: concisePath},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	matcher.SetExperiments(experiments)
+
+	messages := userMessages(t, `"Hello!"`)
+	variants := map[string]bool{}
+	for i := 0; i < 40; i++ {
+		unit := fmt.Sprintf("run-%d", i)
+		fixture, assignment, err := matcher.MatchForUnit(messages, unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if assignment == nil {
+			t.Fatal("no assignment for an experiment fixture")
+		}
+		variants[assignment.Variant] = true
+
+		if fixture.Metadata["experiment"] != "tone" || fixture.Metadata["variant"] != assignment.Variant {
+			t.Errorf("fixture metadata = %v, want the tone/%s assignment", fixture.Metadata, assignment.Variant)
+		}
+		if (assignment.Variant == "concise") != (fixture.ID == "concise") {
+			t.Errorf("variant %s served fixture %s", assignment.Variant, fixture.ID)
+		}
+	}
+	if len(variants) != 2 {
+		t.Errorf("40 units were assigned %v, want both variants", variants)
+	}
+
+	// Prompts outside the experiment are not assigned
+	matcher.SetDefaultPath(concisePath)
+	if _, assignment, err := matcher.MatchForUnit(userMessages(t, `"Goodbye"`), "run-1"); err != nil || assignment != nil {
+		t.Errorf("unmatched prompt: assignment %v, error %v", assignment, err)
+	}
+}
+
+func TestMatchSynthesized(t *testing.T) {
+	matcher := newGreetingMatcher(t)
+	matcher.SetSynthesizer(generator.NewSynthesizer(generator.SynthesizerConfig{
+		ProjectStrategies: map[string]generator.Strategy{"proj_refuse": generator.StrategyRefusal},
+	}))
+
+	tests := []struct {
+		name         string
+		project      string
+		prompt       string
+		wantID       string
+		wantStrategy string
+	}{
+		{name: "pattern match", prompt: `"Hello!"`},
+		{name: "project strategy", project: "proj_refuse", prompt: `"Explain quantum tunneling"`, wantStrategy: "refusal"},
+		{name: "classified", prompt: `"Write a Python function that reverses a string"`, wantStrategy: "code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := userMessages(t, tt.prompt)
+			fixture, _, err := matcher.MatchForProject(messages, "", tt.project)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantStrategy == "" {
+				if fixture.Metadata["synthesized"] == true {
+					t.Errorf("matched prompt was synthesized")
+				}
+				return
+			}
+
+			if fixture.Metadata["synthesized"] != true || fixture.Metadata["strategy"] != tt.wantStrategy {
+				t.Fatalf("metadata = %v, want synthesized with strategy %s", fixture.Metadata, tt.wantStrategy)
+			}
+			if fixture.ID != "synthesized-"+tt.wantStrategy || fixture.Content == "" {
+				t.Errorf("fixture %s has content %q", fixture.ID, fixture.Content)
+			}
+
+			// The same prompt is answered the same way
+			again, _, _ := matcher.MatchForProject(messages, "", tt.project)
+			if again.Content != fixture.Content {
+				t.Errorf("same prompt synthesized %q, then %q", fixture.Content, again.Content)
+			}
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
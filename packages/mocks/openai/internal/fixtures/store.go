@@ -5,6 +5,8 @@ package fixtures
 import (
 	"fmt"
 	"math/rand"
+	"regexp"
+	"sort"
 	"sync"
 
 	"github.com/sentra-lab/mocks/openai/internal/models"
@@ -18,6 +20,10 @@ type Fixture struct {
 	// Pattern is the regex pattern to match prompts (optional)
 	Pattern string `yaml:"pattern"`
 
+	// Images restricts the fixture to prompts with (true) or without
+	// (false) image inputs; unset serves both (optional)
+	Images *bool `yaml:"images,omitempty"`
+
 	// Content is the response content
 	Content string `yaml:"content"`
 
@@ -27,7 +33,12 @@ type Fixture struct {
 	// FunctionCall is an optional function call in the response
 	FunctionCall *models.FunctionCall `yaml:"function_call,omitempty"`
 
-	// FinishReason is the finish reason (default: "stop")
+	// ToolCalls are optional tool calls in the response; several calls are
+	// made in parallel unless the request disables parallel_tool_calls
+	ToolCalls []FixtureToolCall `yaml:"tool_calls,omitempty"`
+
+	// FinishReason is the finish reason (default: "stop", or "tool_calls"
+	// when the fixture has tool calls)
 	FinishReason string `yaml:"finish_reason"`
 
 	// Metadata contains additional fixture metadata
@@ -35,6 +46,11 @@ type Fixture struct {
 
 	// Weight for weighted random selection (default: 1.0)
 	Weight float64 `yaml:"weight"`
+
+	// SchemaViolation makes structured output (response_format
+	// json_schema) break the request's schema in the given way (see
+	// generator.SchemaViolations), to test how agents handle it (optional)
+	SchemaViolation string `yaml:"schema_violation,omitempty"`
 }
 
 // FixtureFile represents a YAML fixture file structure.
@@ -117,7 +133,7 @@ func (s *Store) Get(path string) (*Fixture, error) {
 		fixture.Role = "assistant"
 	}
 	if fixture.FinishReason == "" {
-		fixture.FinishReason = "stop"
+		fixture.FinishReason = fixture.defaultFinishReason()
 	}
 	if fixture.Weight == 0 {
 		fixture.Weight = 1.0
@@ -128,6 +144,12 @@ func (s *Store) Get(path string) (*Fixture, error) {
 
 // GetWeighted retrieves a fixture using weighted random selection.
 func (s *Store) GetWeighted(path string) (*Fixture, error) {
+	return s.GetWeightedWith(path, nil)
+}
+
+// GetWeightedWith is GetWeighted drawing from rng, so a seeded request gets
+// the same fixture every time. A nil rng uses the global source.
+func (s *Store) GetWeightedWith(path string, rng *rand.Rand) (*Fixture, error) {
 	s.mu.RLock()
 	fixtures, ok := s.fixtures[path]
 	s.mu.RUnlock()
@@ -154,7 +176,7 @@ func (s *Store) GetWeighted(path string) (*Fixture, error) {
 	}
 
 	// Weighted random selection
-	r := rand.Float64() * totalWeight
+	r := randFloat(rng) * totalWeight
 	cumulative := 0.0
 
 	for i, f := range fixtures {
@@ -172,7 +194,7 @@ func (s *Store) GetWeighted(path string) (*Fixture, error) {
 				fixture.Role = "assistant"
 			}
 			if fixture.FinishReason == "" {
-				fixture.FinishReason = "stop"
+				fixture.FinishReason = fixture.defaultFinishReason()
 			}
 			if fixture.Weight == 0 {
 				fixture.Weight = 1.0
@@ -186,6 +208,76 @@ func (s *Store) GetWeighted(path string) (*Fixture, error) {
 	return s.Get(path)
 }
 
+// GetWeightedForImages is GetWeighted among the fixtures that serve a
+// prompt with or without images. When none is restricted to the prompt's
+// kind, all of the path's fixtures are candidates.
+func (s *Store) GetWeightedForImages(path string, hasImages bool) (*Fixture, error) {
+	return s.GetWeightedForImagesWith(path, hasImages, nil)
+}
+
+// GetWeightedForImagesWith is GetWeightedForImages drawing from rng (nil:
+// the global source).
+func (s *Store) GetWeightedForImagesWith(path string, hasImages bool, rng *rand.Rand) (*Fixture, error) {
+	fixtures, err := s.GetAll(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Fixture
+	for _, f := range fixtures {
+		if f.Images == nil || *f.Images == hasImages {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 || len(candidates) == len(fixtures) {
+		return s.GetWeightedWith(path, rng)
+	}
+
+	s.recordQuery(path)
+
+	totalWeight := 0.0
+	for _, f := range candidates {
+		totalWeight += f.effectiveWeight()
+	}
+
+	fixture := candidates[len(candidates)-1]
+	r := randFloat(rng) * totalWeight
+	cumulative := 0.0
+	for _, f := range candidates {
+		cumulative += f.effectiveWeight()
+		if r <= cumulative {
+			fixture = f
+			break
+		}
+	}
+
+	if fixture.Role == "" {
+		fixture.Role = "assistant"
+	}
+	if fixture.FinishReason == "" {
+		fixture.FinishReason = fixture.defaultFinishReason()
+	}
+	fixture.Weight = fixture.effectiveWeight()
+
+	return &fixture, nil
+}
+
+// randFloat draws from rng, or the global source when it is nil.
+func randFloat(rng *rand.Rand) float64 {
+	if rng != nil {
+		return rng.Float64()
+	}
+	return rand.Float64()
+}
+
+// effectiveWeight is the fixture's weight, defaulting to 1.0.
+func (f Fixture) effectiveWeight() float64 {
+	if f.Weight == 0 {
+		return 1.0
+	}
+	return f.Weight
+}
+
 // GetByID retrieves a specific fixture by ID.
 func (s *Store) GetByID(path string, id string) (*Fixture, error) {
 	s.mu.RLock()
@@ -206,7 +298,7 @@ func (s *Store) GetByID(path string, id string) (*Fixture, error) {
 				fixture.Role = "assistant"
 			}
 			if fixture.FinishReason == "" {
-				fixture.FinishReason = "stop"
+				fixture.FinishReason = fixture.defaultFinishReason()
 			}
 
 			s.recordQuery(path)
@@ -234,6 +326,45 @@ func (s *Store) GetByCategory(category string) (*Fixture, error) {
 	return s.GetWeighted(path)
 }
 
+// MatchCategory returns the first fixture in a category whose pattern
+// matches text, checking files in path order and fixtures in file order.
+// A fixture without a pattern matches any text. Returns nil if none does.
+func (s *Store) MatchCategory(category string, text string) *Fixture {
+	s.mu.RLock()
+	paths := append([]string(nil), s.categories[category]...)
+	s.mu.RUnlock()
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fixtures, err := s.GetAll(path)
+		if err != nil {
+			continue
+		}
+
+		for _, f := range fixtures {
+			if f.Pattern != "" {
+				re, err := regexp.Compile(f.Pattern)
+				if err != nil || !re.MatchString(text) {
+					continue
+				}
+			}
+
+			fixture := f
+			if fixture.Role == "" {
+				fixture.Role = "assistant"
+			}
+			if fixture.FinishReason == "" {
+				fixture.FinishReason = fixture.defaultFinishReason()
+			}
+
+			s.recordQuery(path)
+			return &fixture
+		}
+	}
+
+	return nil
+}
+
 // GetAll retrieves all fixtures from a path.
 func (s *Store) GetAll(path string) ([]Fixture, error) {
 	s.mu.RLock()
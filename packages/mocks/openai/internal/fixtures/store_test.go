@@ -0,0 +1,157 @@
+package fixtures
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGetWeightedWith(t *testing.T) {
+	store := NewStore()
+	store.Add("weighted.yaml", FixtureFile{Responses: []Fixture{
+		{ID: "rare", Content: "Rare", Weight: 0.001},
+		{ID: "common", Content: "Common"},
+	}})
+	store.Add("empty.yaml", FixtureFile{})
+
+	tests := []struct {
+		name    string
+		path    string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "weighted", path: "weighted.yaml", wantID: "common"},
+		{name: "unknown path", path: "missing.yaml", wantErr: true},
+		{name: "no fixtures", path: "empty.yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, err := store.GetWeightedWith(tt.path, rand.New(rand.NewSource(1)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWeightedWith() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fixture.ID != tt.wantID {
+				t.Errorf("GetWeightedWith() = %s, want %s", fixture.ID, tt.wantID)
+			}
+			if fixture.Role != "assistant" || fixture.FinishReason != "stop" || fixture.Weight != 1.0 {
+				t.Errorf("defaults = %q, %q, %v, want assistant, stop, 1", fixture.Role, fixture.FinishReason, fixture.Weight)
+			}
+		})
+	}
+}
+
+func TestGetWeightedWithSeed(t *testing.T) {
+	store := NewStore()
+	store.Add("even.yaml", FixtureFile{Responses: []Fixture{
+		{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"},
+	}})
+
+	for seed := int64(0); seed < 10; seed++ {
+		first, err := store.GetWeightedWith("even.yaml", rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := store.GetWeightedWith("even.yaml", rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.ID != second.ID {
+			t.Errorf("seed %d: GetWeightedWith() = %s, then %s, want the same fixture", seed, first.ID, second.ID)
+		}
+	}
+}
+
+func TestGetWeightedForImagesWith(t *testing.T) {
+	withImages, withoutImages := true, false
+
+	tests := []struct {
+		name      string
+		fixtures  []Fixture
+		hasImages bool
+		want      []string
+	}{
+		{
+			name:     "text prompt",
+			fixtures: []Fixture{{ID: "text", Images: &withoutImages}, {ID: "photo", Images: &withImages}},
+			want:     []string{"text"},
+		},
+		{
+			name:      "image prompt",
+			fixtures:  []Fixture{{ID: "text", Images: &withoutImages}, {ID: "photo", Images: &withImages}, {ID: "any"}},
+			hasImages: true,
+			want:      []string{"photo", "any"},
+		},
+		{
+			name:      "no fixture for the prompt's kind",
+			fixtures:  []Fixture{{ID: "text", Images: &withoutImages}},
+			hasImages: true,
+			want:      []string{"text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			store.Add("images.yaml", FixtureFile{Responses: tt.fixtures})
+
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				fixture, err := store.GetWeightedForImagesWith("images.yaml", tt.hasImages, rng)
+				if err != nil {
+					t.Fatalf("GetWeightedForImagesWith() error = %v", err)
+				}
+				if !contains(tt.want, fixture.ID) {
+					t.Fatalf("GetWeightedForImagesWith() = %s, want one of %v", fixture.ID, tt.want)
+				}
+				if fixture.Role != "assistant" || fixture.Weight != 1.0 {
+					t.Errorf("defaults = %q, %v, want assistant, 1", fixture.Role, fixture.Weight)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchCategory(t *testing.T) {
+	store := NewStore()
+	store.Add("b.yaml", FixtureFile{Category: "support", Responses: []Fixture{
+		{ID: "fallback", Content: "How can I help?"},
+	}})
+	store.Add("a.yaml", FixtureFile{Category: "support", Responses: []Fixture{
+		{ID: "refund", Pattern: `(?i)\brefund\b`, Content: "Refunds take 5 days."},
+		{ID: "invalid", Pattern: `(`, Content: "Never matched."},
+	}})
+	store.Add("c.yaml", FixtureFile{Category: "sales", Responses: []Fixture{
+		{ID: "pricing", Pattern: `price`, Content: "It costs $10."},
+	}})
+
+	tests := []struct {
+		name     string
+		category string
+		text     string
+		want     string
+	}{
+		{name: "pattern match", category: "support", text: "I want a Refund", want: "refund"},
+		{name: "fixture without a pattern", category: "support", text: "Hello", want: "fallback"},
+		{name: "no match", category: "sales", text: "Hello"},
+		{name: "unknown category", category: "billing", text: "price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := store.MatchCategory(tt.category, tt.text)
+			var got string
+			if fixture != nil {
+				got = fixture.ID
+				if fixture.Role != "assistant" {
+					t.Errorf("Role = %q, want assistant", fixture.Role)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("MatchCategory(%q, %q) = %q, want %q", tt.category, tt.text, got, tt.want)
+			}
+		})
+	}
+}
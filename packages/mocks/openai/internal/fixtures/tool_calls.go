@@ -0,0 +1,212 @@
+// Package fixtures provides response fixture management.
+// This file converts fixture tool calls into response tool calls.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// FixtureToolCall is a tool call in a fixture.
+//
+//	tool_calls:
+//	  - name: get_weather
+//	    arguments: {location: "Paris"}
+//	  - name: get_weather
+//	    arguments: '{"location": "London"}'
+type FixtureToolCall struct {
+	// Name is the function to call
+	Name string `yaml:"name"`
+
+	// Arguments is a YAML mapping or a JSON object string (default {})
+	Arguments interface{} `yaml:"arguments,omitempty"`
+}
+
+// arguments returns the call's arguments as a JSON object string.
+func (c FixtureToolCall) arguments() (string, error) {
+	switch args := c.Arguments.(type) {
+	case nil:
+		return "{}", nil
+	case string:
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(args), &object); err != nil {
+			return "", fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+		return args, nil
+	case map[string]interface{}:
+		data, err := json.Marshal(args)
+		if err != nil {
+			return "", fmt.Errorf("arguments cannot be encoded as JSON: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("arguments must be a mapping or a JSON object string, got %T", args)
+	}
+}
+
+// defaultFinishReason is the finish reason of a fixture that sets none.
+func (f *Fixture) defaultFinishReason() string {
+	if len(f.ToolCalls) > 0 {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+// ToolCallsFor returns the fixture's tool calls for a request. When the
+// request declares tools, tool_choice "none" drops the calls and a named
+// function keeps only the calls to it. When the request disables
+// parallel_tool_calls, only the first call is returned. Returns nil if the
+// fixture has no tool calls.
+func (f *Fixture) ToolCallsFor(req *models.ChatCompletionRequest) ([]models.ToolCall, error) {
+	calls := f.ToolCalls
+	if len(req.Tools) > 0 {
+		switch mode, name := req.ToolChoiceMode(); mode {
+		case models.ToolChoiceNone:
+			calls = nil
+		case models.ToolChoiceFunction:
+			calls = nil
+			for _, call := range f.ToolCalls {
+				if call.Name == name {
+					calls = append(calls, call)
+				}
+			}
+		}
+	}
+	if len(calls) > 1 && !req.AllowsParallelToolCalls() {
+		calls = calls[:1]
+	}
+
+	var toolCalls []models.ToolCall
+	for i, call := range calls {
+		arguments, err := call.arguments()
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: tool_calls[%d]: %w", f.ID, i, err)
+		}
+
+		toolCalls = append(toolCalls, models.ToolCall{
+			Index: i,
+			ID:    models.NewToolCallID(),
+			Type:  "function",
+			Function: models.FunctionCall{
+				Name:      call.Name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	return toolCalls, nil
+}
+
+// ChatResponse builds the non-streaming chat completion for req from the
+// fixture. When tool_choice forces a call ("required" or a named function)
+// and the fixture makes none, the calls are generated from the tools'
+// parameter schemas. Synthesized fixtures also call tools under "auto",
+// until the conversation holds the tool results. With a seed, the response
+// is deterministic (see ChatCompletionResponse.ApplySeed).
+func (f *Fixture) ChatResponse(req *models.ChatCompletionRequest, usage models.Usage) (*models.ChatCompletionResponse, error) {
+	response, err := f.chatResponse(req, usage)
+	if err != nil {
+		return nil, err
+	}
+	if req.Seed != nil {
+		response.ApplySeed(*req.Seed)
+	}
+	return response, nil
+}
+
+// chatResponse builds ChatResponse's response.
+func (f *Fixture) chatResponse(req *models.ChatCompletionRequest, usage models.Usage) (*models.ChatCompletionResponse, error) {
+	toolCalls, err := f.ToolCallsFor(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(toolCalls) > 0 {
+		response := models.NewToolCallsResponse(req.Model, toolCalls, usage)
+		response.Choices[0].Message.Content = f.Content
+		return response, nil
+	}
+
+	if f.generatesToolCalls(req) {
+		toolCalls, err := generator.NewArgumentGeneratorForRequest(req).ToolCalls(req)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", f.ID, err)
+		}
+		if len(toolCalls) > 0 {
+			return models.NewToolCallsResponse(req.Model, toolCalls, usage), nil
+		}
+	}
+
+	role := f.Role
+	if role == "" {
+		role = "assistant"
+	}
+
+	// A fixture whose tool calls tool_choice dropped answers with content
+	finishReason := f.FinishReason
+	switch {
+	case f.FunctionCall != nil && finishReason == "":
+		finishReason = "function_call"
+	case finishReason == "" || finishReason == "tool_calls":
+		finishReason = "stop"
+	}
+
+	content := f.Content
+	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_schema" && f.FunctionCall == nil {
+		content, err = f.structuredContent(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	message := models.Message{Role: role, Content: content, FunctionCall: f.FunctionCall}
+	response := models.NewChatCompletionResponse(req.Model, message, usage)
+	response.Choices[0].FinishReason = finishReason
+	return response, nil
+}
+
+// structuredContent returns the fixture's answer to a json_schema request:
+// its content when that is JSON, otherwise output generated from the
+// schema, broken as the fixture's schema_violation says.
+func (f *Fixture) structuredContent(req *models.ChatCompletionRequest) (string, error) {
+	format := req.ResponseFormat.JSONSchema
+	if format == nil {
+		return f.Content, nil
+	}
+
+	content := f.Content
+	if !json.Valid([]byte(content)) {
+		generated, err := generator.NewArgumentGeneratorForRequest(req).StructuredOutput(format)
+		if err != nil {
+			return "", fmt.Errorf("fixture %s: %w", f.ID, err)
+		}
+		content = generated
+	}
+
+	if f.SchemaViolation != "" {
+		violated, err := generator.ViolateSchema(content, format.Schema, f.SchemaViolation)
+		if err != nil {
+			return "", fmt.Errorf("fixture %s: %w", f.ID, err)
+		}
+		content = violated
+	}
+
+	return content, nil
+}
+
+// generatesToolCalls reports whether tool calls are generated for req when
+// the fixture makes none.
+func (f *Fixture) generatesToolCalls(req *models.ChatCompletionRequest) bool {
+	switch mode, _ := req.ToolChoiceMode(); mode {
+	case models.ToolChoiceRequired, models.ToolChoiceFunction:
+		return true
+	case models.ToolChoiceAuto:
+		synthesized, _ := f.Metadata["synthesized"].(bool)
+		answered := len(req.Messages) > 0 && req.Messages[len(req.Messages)-1].Role == "tool"
+		return synthesized && !answered
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// decodeRequest decodes a chat completion request body.
+func decodeRequest(t *testing.T, body string) *models.ChatCompletionRequest {
+	t.Helper()
+	var req models.ChatCompletionRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	return &req
+}
+
+// weatherTools declares get_weather and get_time.
+const weatherTools = `"tools": [
+	{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object", "properties": {"location": {"type": "string"}}, "required": ["location"]}}},
+	{"type": "function", "function": {"name": "get_time", "parameters": {"type": "object", "properties": {}}}}
+]`
+
+func TestToolCallsFor(t *testing.T) {
+	fixture := &Fixture{
+		ID: "weather",
+		ToolCalls: []FixtureToolCall{
+			{Name: "get_weather", Arguments: map[string]interface{}{"location": "Paris"}},
+			{Name: "get_weather", Arguments: `{"location": "London"}`},
+			{Name: "get_time"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		body string
+		// want are the expected calls as name(arguments)
+		want []string
+	}{
+		{
+			name: "no tools declared",
+			body: `{"model": "gpt-4o", "messages": []}`,
+			want: []string{`get_weather({"location":"Paris"})`, `get_weather({"location": "London"})`, `get_time({})`},
+		},
+		{
+			name: "auto",
+			body: `{"model": "gpt-4o", "messages": [], ` + weatherTools + `}`,
+			want: []string{`get_weather({"location":"Paris"})`, `get_weather({"location": "London"})`, `get_time({})`},
+		},
+		{
+			name: "none",
+			body: `{"model": "gpt-4o", "messages": [], "tool_choice": "none", ` + weatherTools + `}`,
+		},
+		{
+			name: "named function",
+			body: `{"model": "gpt-4o", "messages": [], "tool_choice": {"type": "function", "function": {"name": "get_time"}}, ` + weatherTools + `}`,
+			want: []string{`get_time({})`},
+		},
+		{
+			name: "parallel calls disabled",
+			body: `{"model": "gpt-4o", "messages": [], "parallel_tool_calls": false, ` + weatherTools + `}`,
+			want: []string{`get_weather({"location":"Paris"})`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, err := fixture.ToolCallsFor(decodeRequest(t, tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(calls) != len(tt.want) {
+				t.Fatalf("got %d calls, want %d", len(calls), len(tt.want))
+			}
+			for i, call := range calls {
+				got := call.Function.Name + "(" + call.Function.Arguments + ")"
+				if got != tt.want[i] {
+					t.Errorf("call %d = %s, want %s", i, got, tt.want[i])
+				}
+				if call.Index != i || call.Type != "function" || call.ID == "" {
+					t.Errorf("call %d: index %d, type %q, id %q", i, call.Index, call.Type, call.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestToolCallsForInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments interface{}
+	}{
+		{name: "not JSON", arguments: "location=Paris"},
+		{name: "JSON array", arguments: `["Paris"]`},
+		{name: "list", arguments: []interface{}{"Paris"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := &Fixture{ID: "broken", ToolCalls: []FixtureToolCall{{Name: "get_weather", Arguments: tt.arguments}}}
+			if _, err := fixture.ToolCallsFor(&models.ChatCompletionRequest{}); err == nil {
+				t.Error("invalid arguments were accepted")
+			}
+		})
+	}
+}
+
+func TestChatResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		fixture      *Fixture
+		body         string
+		wantFinish   string
+		wantCalls    []string
+		wantContent  string
+		wantValidRaw bool
+	}{
+		{
+			name:        "content",
+			fixture:     &Fixture{ID: "hello", Content: "Hello!"},
+			body:        `{"model": "gpt-4o", "messages": []}`,
+			wantFinish:  "stop",
+			wantContent: "Hello!",
+		},
+		{
+			name:       "fixture tool calls",
+			fixture:    &Fixture{ID: "weather", ToolCalls: []FixtureToolCall{{Name: "get_time"}}},
+			body:       `{"model": "gpt-4o", "messages": [], ` + weatherTools + `}`,
+			wantFinish: "tool_calls",
+			wantCalls:  []string{"get_time"},
+		},
+		{
+			name:        "tool calls dropped by tool_choice none",
+			fixture:     &Fixture{ID: "weather", Content: "It is sunny.", ToolCalls: []FixtureToolCall{{Name: "get_time"}}},
+			body:        `{"model": "gpt-4o", "messages": [], "tool_choice": "none", ` + weatherTools + `}`,
+			wantFinish:  "stop",
+			wantContent: "It is sunny.",
+		},
+		{
+			name:       "required generates calls",
+			fixture:    &Fixture{ID: "hello", Content: "Hello!"},
+			body:       `{"model": "gpt-4o", "messages": [], "tool_choice": "required", "tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}]}`,
+			wantFinish: "tool_calls",
+			wantCalls:  []string{"get_weather"},
+		},
+		{
+			name:       "named function generates its call",
+			fixture:    &Fixture{ID: "hello", Content: "Hello!"},
+			body:       `{"model": "gpt-4o", "messages": [], "tool_choice": {"type": "function", "function": {"name": "get_time"}}, ` + weatherTools + `}`,
+			wantFinish: "tool_calls",
+			wantCalls:  []string{"get_time"},
+		},
+		{
+			name:        "synthesized answer after tool results",
+			fixture:     &Fixture{ID: "synthesized-echo", Content: "Done.", Metadata: map[string]interface{}{"synthesized": true}},
+			body:        `{"model": "gpt-4o", "messages": [{"role": "tool", "content": "22C", "tool_call_id": "call_1"}], ` + weatherTools + `}`,
+			wantFinish:  "stop",
+			wantContent: "Done.",
+		},
+		{
+			name:         "json_schema generated from the schema",
+			fixture:      &Fixture{ID: "hello", Content: "Hello!"},
+			body:         `{"model": "gpt-4o", "messages": [], "response_format": {"type": "json_schema", "json_schema": {"name": "greeting", "strict": true, "schema": {"type": "object", "properties": {"text": {"type": "string"}}, "required": ["text"], "additionalProperties": false}}}}`,
+			wantFinish:   "stop",
+			wantValidRaw: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := tt.fixture.ChatResponse(decodeRequest(t, tt.body), models.Usage{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			choice := response.Choices[0]
+			if choice.FinishReason != tt.wantFinish {
+				t.Errorf("finish_reason = %q, want %q", choice.FinishReason, tt.wantFinish)
+			}
+
+			var names []string
+			for _, call := range choice.Message.ToolCalls {
+				names = append(names, call.Function.Name)
+			}
+			if len(names) != len(tt.wantCalls) || (len(names) > 0 && names[0] != tt.wantCalls[0]) {
+				t.Errorf("tool calls = %v, want %v", names, tt.wantCalls)
+			}
+
+			if tt.wantValidRaw {
+				var object map[string]interface{}
+				if err := json.Unmarshal([]byte(choice.Message.Content), &object); err != nil {
+					t.Errorf("content %q is not a JSON object: %v", choice.Message.Content, err)
+				}
+				if _, ok := object["text"].(string); !ok {
+					t.Errorf("content %q lacks the required text property", choice.Message.Content)
+				}
+			} else if choice.Message.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", choice.Message.Content, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestChatResponseSeeded(t *testing.T) {
+	fixture := &Fixture{ID: "hello", Content: "Hello!"}
+	body := `{"model": "gpt-4o", "messages": [], "seed": 42}`
+
+	first, err := fixture.ChatResponse(decodeRequest(t, body), models.Usage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := fixture.ChatResponse(decodeRequest(t, body), models.Usage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.ID != second.ID {
+		t.Errorf("seeded responses have IDs %s and %s", first.ID, second.ID)
+	}
+	if first.SystemFingerprint == nil || second.SystemFingerprint == nil || *first.SystemFingerprint != *second.SystemFingerprint {
+		t.Error("seeded responses have different system fingerprints")
+	}
+}
@@ -5,7 +5,10 @@ package fixtures
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/sentra-lab/mocks/openai/internal/generator"
 )
 
 // Validator validates fixture files and individual fixtures.
@@ -116,11 +119,20 @@ func (v *Validator) ValidateFixture(fixture *Fixture) []ValidationError {
 		})
 	}
 
-	// Validate content or function_call
-	if fixture.Content == "" && fixture.FunctionCall == nil {
+	// Validate content, function_call, tool_calls or schema_violation
+	if fixture.Content == "" && fixture.FunctionCall == nil && len(fixture.ToolCalls) == 0 && fixture.SchemaViolation == "" {
 		errors = append(errors, ValidationError{
 			Field:   "content",
-			Message: "either content or function_call must be provided",
+			Message: "either content, function_call, tool_calls or schema_violation must be provided",
+			Severity: "error",
+		})
+	}
+
+	// Validate schema_violation (if present)
+	if fixture.SchemaViolation != "" && !slices.Contains(generator.SchemaViolations, fixture.SchemaViolation) {
+		errors = append(errors, ValidationError{
+			Field:   "schema_violation",
+			Message: fmt.Sprintf("invalid schema_violation '%s' (allowed: %s)", fixture.SchemaViolation, strings.Join(generator.SchemaViolations, ", ")),
 			Severity: "error",
 		})
 	}
@@ -182,10 +194,37 @@ func (v *Validator) ValidateFixture(fixture *Fixture) []ValidationError {
 		}
 	}
 
+	// Validate tool_calls (if present)
+	for i, call := range fixture.ToolCalls {
+		if call.Name == "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("tool_calls[%d].name", i),
+				Message: "function name is required",
+				Severity: "error",
+			})
+		}
+
+		if _, err := call.arguments(); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("tool_calls[%d].arguments", i),
+				Message: err.Error(),
+				Severity: "error",
+			})
+		}
+	}
+
+	if len(fixture.ToolCalls) > 0 && fixture.FinishReason != "" && fixture.FinishReason != "tool_calls" {
+		errors = append(errors, ValidationError{
+			Field:   "finish_reason",
+			Message: fmt.Sprintf("fixtures with tool_calls finish with 'tool_calls', not '%s'", fixture.FinishReason),
+			Severity: "warning",
+		})
+	}
+
 	// Strict mode validations
 	if v.strictMode {
 		// Check content length
-		if len(fixture.Content) == 0 && fixture.FunctionCall == nil {
+		if len(fixture.Content) == 0 && fixture.FunctionCall == nil && len(fixture.ToolCalls) == 0 {
 			errors = append(errors, ValidationError{
 				Field:   "content",
 				Message: "content is empty",
@@ -0,0 +1,110 @@
+package fixtures
+
+import "testing"
+
+func TestValidateFixture(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture Fixture
+		// wantErrors and wantWarnings are the fields reported
+		wantErrors   []string
+		wantWarnings []string
+	}{
+		{
+			name:    "content",
+			fixture: Fixture{ID: "hello", Content: "Hello!"},
+		},
+		{
+			name:       "empty",
+			fixture:    Fixture{ID: "empty"},
+			wantErrors: []string{"content"},
+		},
+		{
+			name: "tool calls only",
+			fixture: Fixture{ID: "weather", ToolCalls: []FixtureToolCall{
+				{Name: "get_weather", Arguments: map[string]interface{}{"location": "Paris"}},
+			}},
+		},
+		{
+			name: "invalid tool calls",
+			fixture: Fixture{ID: "weather", ToolCalls: []FixtureToolCall{
+				{Arguments: `{"location": "Paris"}`},
+				{Name: "get_weather", Arguments: "Paris"},
+			}},
+			wantErrors: []string{"tool_calls[0].name", "tool_calls[1].arguments"},
+		},
+		{
+			name: "tool calls finishing with stop",
+			fixture: Fixture{ID: "weather", FinishReason: "stop", ToolCalls: []FixtureToolCall{
+				{Name: "get_weather"},
+			}},
+			wantWarnings: []string{"finish_reason"},
+		},
+		{
+			name:    "schema violation only",
+			fixture: Fixture{ID: "broken", SchemaViolation: "missing_required"},
+		},
+		{
+			name:       "unknown schema violation",
+			fixture:    Fixture{ID: "broken", Content: "{}", SchemaViolation: "sideways"},
+			wantErrors: []string{"schema_violation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotErrors, gotWarnings []string
+			for _, e := range NewValidator(false).ValidateFixture(&tt.fixture) {
+				if e.IsError() {
+					gotErrors = append(gotErrors, e.Field)
+				} else if e.IsWarning() {
+					gotWarnings = append(gotWarnings, e.Field)
+				}
+			}
+
+			if !sameFields(gotErrors, tt.wantErrors) {
+				t.Errorf("errors on %v, want %v", gotErrors, tt.wantErrors)
+			}
+			if !sameFields(gotWarnings, tt.wantWarnings) {
+				t.Errorf("warnings on %v, want %v", gotWarnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestLoaderValidateFixtureFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture Fixture
+		wantErr bool
+	}{
+		{name: "content", fixture: Fixture{ID: "hello", Content: "Hello!"}},
+		{name: "tool calls", fixture: Fixture{ID: "weather", ToolCalls: []FixtureToolCall{{Name: "get_weather"}}}},
+		{name: "schema violation", fixture: Fixture{ID: "broken", SchemaViolation: "wrong_type"}},
+		{name: "unknown schema violation", fixture: Fixture{ID: "broken", SchemaViolation: "sideways"}, wantErr: true},
+		{name: "nothing to answer with", fixture: Fixture{ID: "empty"}, wantErr: true},
+	}
+
+	loader := NewLoader(NewStore(), t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loader.validateFixtureFile(&FixtureFile{Responses: []Fixture{tt.fixture}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFixtureFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// sameFields reports whether two lists of fields are equal.
+func sameFields(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,553 @@
+// Package generator provides response generation for requests no fixture covers.
+// This file generates function-call arguments that satisfy a JSON Schema.
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+const (
+	// maxSchemaDepth bounds recursion through nested and self-referencing schemas
+	maxSchemaDepth = 8
+
+	// defaultNumberRange is the span used when a numeric bound is missing
+	defaultNumberRange = 100
+
+	// defaultMaxItems caps generated arrays without maxItems
+	defaultMaxItems = 3
+)
+
+// sampleStrings maps property-name hints to plausible values, so a
+// get_weather(location) call gets "San Francisco" rather than random text.
+var sampleStrings = []struct {
+	hint   string
+	values []string
+}{
+	{"email", []string{"alex@example.com", "sam@example.org"}},
+	{"city", []string{"San Francisco", "London", "Tokyo"}},
+	{"location", []string{"San Francisco, CA", "London, UK", "Tokyo, Japan"}},
+	{"country", []string{"US", "GB", "JP"}},
+	{"name", []string{"Alex Smith", "Sam Lee", "Jordan Park"}},
+	{"query", []string{"latest order status", "refund policy", "weather tomorrow"}},
+	{"url", []string{"https://example.com", "https://example.org/docs"}},
+	{"id", []string{"id_8f3a2c", "id_41b9de", "id_c07e55"}},
+	{"date", []string{"2025-01-15", "2025-03-02"}},
+	{"unit", []string{"celsius", "fahrenheit"}},
+	{"currency", []string{"USD", "EUR", "GBP"}},
+	{"message", []string{"Hello, how can I help?", "Thanks for reaching out."}},
+}
+
+// ArgumentGenerator generates tool-call arguments from JSON Schemas.
+// Output is deterministic for a given seed and schema. Not safe for
+// concurrent use; create one per request.
+type ArgumentGenerator struct {
+	// rng is the seeded random source
+	rng *rand.Rand
+
+	// maxParallelToolCalls bounds the tool calls made in one response
+	maxParallelToolCalls int
+}
+
+// NewArgumentGenerator creates a generator seeded with seed.
+// It makes one tool call per response until SetMaxParallelToolCalls is used.
+func NewArgumentGenerator(seed int64) *ArgumentGenerator {
+	return &ArgumentGenerator{
+		rng:                  rand.New(rand.NewSource(seed)),
+		maxParallelToolCalls: 1,
+	}
+}
+
+// SetMaxParallelToolCalls lets ToolCalls make between 1 and n calls per
+// response when the request allows parallel tool calls.
+func (g *ArgumentGenerator) SetMaxParallelToolCalls(n int) {
+	g.maxParallelToolCalls = max(n, 1)
+}
+
+// NewArgumentGeneratorForRequest creates a generator seeded from the
+// request's seed parameter, or from the current time when it is unset.
+func NewArgumentGeneratorForRequest(req *models.ChatCompletionRequest) *ArgumentGenerator {
+	if req.Seed != nil {
+		return NewArgumentGenerator(int64(*req.Seed))
+	}
+	return NewArgumentGenerator(time.Now().UnixNano())
+}
+
+// Generate returns a JSON object string of arguments satisfying schema.
+// A nil schema produces "{}".
+func (g *ArgumentGenerator) Generate(schema map[string]interface{}) (string, error) {
+	if schema == nil {
+		return "{}", nil
+	}
+
+	value := g.value(schema, schema, "", 0)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// ToolCalls synthesizes the tool calls the model would make for req,
+// honoring tool_choice ("none", "auto", "required" or a named function).
+// With auto/required and parallel_tool_calls allowed, up to the configured
+// maximum calls are made (the same function may be called more than once,
+// like a model fetching the weather for several cities).
+// Returns nil when the request declares no tools or tool_choice is "none".
+func (g *ArgumentGenerator) ToolCalls(req *models.ChatCompletionRequest) ([]models.ToolCall, error) {
+	function, ok := g.chooseFunction(req.Tools, req.ToolChoice)
+	if !ok {
+		return nil, nil
+	}
+
+	count := 1
+	if _, named := req.ToolChoice.(map[string]interface{}); !named && req.AllowsParallelToolCalls() {
+		count += g.rng.Intn(g.maxParallelToolCalls)
+	}
+
+	toolCalls := make([]models.ToolCall, 0, count)
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			function = req.Tools[g.rng.Intn(len(req.Tools))].Function
+		}
+
+		arguments, err := g.Generate(function.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("function %q: %w", function.Name, err)
+		}
+
+		toolCalls = append(toolCalls, models.ToolCall{
+			Index: i,
+			ID:    "call_" + g.randomString(24),
+			Type:  "function",
+			Function: models.FunctionCall{
+				Name:      function.Name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	return toolCalls, nil
+}
+
+// FunctionCall synthesizes a legacy function_call for req (functions and
+// function_call parameters). Returns nil when no function applies.
+func (g *ArgumentGenerator) FunctionCall(req *models.ChatCompletionRequest) (*models.FunctionCall, error) {
+	tools := make([]models.Tool, len(req.Functions))
+	for i, function := range req.Functions {
+		tools[i] = models.Tool{Type: "function", Function: function}
+	}
+
+	function, ok := g.chooseFunction(tools, req.FunctionCall)
+	if !ok {
+		return nil, nil
+	}
+
+	arguments, err := g.Generate(function.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("function %q: %w", function.Name, err)
+	}
+
+	return &models.FunctionCall{Name: function.Name, Arguments: arguments}, nil
+}
+
+// chooseFunction picks the function to call from tools given a
+// tool_choice/function_call value. "auto" and "required" pick one at random.
+func (g *ArgumentGenerator) chooseFunction(tools []models.Tool, choice interface{}) (models.Function, bool) {
+	if len(tools) == 0 {
+		return models.Function{}, false
+	}
+
+	switch c := choice.(type) {
+	case string:
+		if c == "none" {
+			return models.Function{}, false
+		}
+	case map[string]interface{}:
+		// {"type": "function", "function": {"name": "..."}} or {"name": "..."}
+		name, _ := c["name"].(string)
+		if fn, ok := c["function"].(map[string]interface{}); ok {
+			name, _ = fn["name"].(string)
+		}
+		for _, tool := range tools {
+			if tool.Function.Name == name {
+				return tool.Function, true
+			}
+		}
+		return models.Function{}, false
+	}
+
+	return tools[g.rng.Intn(len(tools))].Function, true
+}
+
+// value generates a value for schema. root resolves local $refs and name
+// is the enclosing property name, used to pick realistic strings.
+func (g *ArgumentGenerator) value(schema map[string]interface{}, root map[string]interface{}, name string, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, ok := resolveRef(root, ref)
+		if !ok {
+			return nil
+		}
+		return g.value(resolved, root, name, depth+1)
+	}
+
+	if c, ok := schema["const"]; ok {
+		return c
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[g.rng.Intn(len(enum))]
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			if option, ok := options[g.rng.Intn(len(options))].(map[string]interface{}); ok {
+				return g.value(option, root, name, depth+1)
+			}
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok && len(all) > 0 {
+		return g.value(mergeSchemas(all), root, name, depth+1)
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return g.object(schema, root, depth)
+	case "array":
+		return g.array(schema, root, name, depth)
+	case "integer":
+		return g.integer(schema)
+	case "number":
+		return g.number(schema)
+	case "boolean":
+		return g.rng.Intn(2) == 0
+	case "null":
+		return nil
+	default:
+		return g.text(schema, name)
+	}
+}
+
+// object generates an object with every required property and a random
+// subset of the optional ones.
+func (g *ArgumentGenerator) object(schema map[string]interface{}, root map[string]interface{}, depth int) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, r := range list {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	// Sort so the random stream is consumed in a stable order
+	names := make([]string, 0, len(properties))
+	for propName := range properties {
+		names = append(names, propName)
+	}
+	sort.Strings(names)
+
+	result := make(map[string]interface{}, len(names))
+	for _, propName := range names {
+		if !required[propName] && g.rng.Intn(2) == 0 {
+			continue
+		}
+		propSchema, _ := properties[propName].(map[string]interface{})
+		if propSchema == nil {
+			propSchema = map[string]interface{}{}
+		}
+		result[propName] = g.value(propSchema, root, propName, depth+1)
+	}
+
+	return result
+}
+
+// array generates between minItems and maxItems items.
+func (g *ArgumentGenerator) array(schema map[string]interface{}, root map[string]interface{}, name string, depth int) []interface{} {
+	minItems := intKeyword(schema, "minItems", 1)
+	maxItems := intKeyword(schema, "maxItems", max(minItems, defaultMaxItems))
+	if maxItems < minItems {
+		maxItems = minItems
+	}
+
+	count := minItems + g.rng.Intn(maxItems-minItems+1)
+
+	items, _ := schema["items"].(map[string]interface{})
+	if items == nil {
+		items = map[string]interface{}{"type": "string"}
+	}
+
+	result := make([]interface{}, count)
+	for i := range result {
+		result[i] = g.value(items, root, strings.TrimSuffix(name, "s"), depth+1)
+	}
+
+	return result
+}
+
+// integer generates an integer within the schema's bounds.
+func (g *ArgumentGenerator) integer(schema map[string]interface{}) int64 {
+	low, high := bounds(schema)
+	lo := int64(math.Ceil(low))
+	hi := int64(math.Floor(high))
+	if exclusive(schema, "exclusiveMinimum", low) {
+		lo = int64(math.Floor(low)) + 1
+	}
+	if exclusive(schema, "exclusiveMaximum", high) {
+		hi = int64(math.Ceil(high)) - 1
+	}
+	if hi < lo {
+		return lo
+	}
+
+	value := lo + g.rng.Int63n(hi-lo+1)
+
+	if step, ok := numberKeyword(schema, "multipleOf"); ok && step >= 1 {
+		m := int64(step)
+		value = (value / m) * m
+		if value < lo {
+			value += m
+		}
+	}
+
+	return value
+}
+
+// number generates a number within the schema's bounds, rounded to two
+// decimal places.
+func (g *ArgumentGenerator) number(schema map[string]interface{}) float64 {
+	low, high := bounds(schema)
+	if high < low {
+		return low
+	}
+
+	value := math.Round((low+g.rng.Float64()*(high-low))*100) / 100
+	if value < low || (exclusive(schema, "exclusiveMinimum", low) && value == low) {
+		value = low + (high-low)/2
+	}
+	if value > high || (exclusive(schema, "exclusiveMaximum", high) && value == high) {
+		value = low + (high-low)/2
+	}
+
+	return value
+}
+
+// text generates a string honoring format, minLength and maxLength.
+func (g *ArgumentGenerator) text(schema map[string]interface{}, name string) string {
+	var value string
+
+	switch format, _ := schema["format"].(string); format {
+	case "date-time":
+		value = "2025-01-15T09:30:00Z"
+	case "date":
+		value = "2025-01-15"
+	case "time":
+		value = "09:30:00"
+	case "email":
+		value = "alex@example.com"
+	case "uri", "url":
+		value = "https://example.com"
+	case "uuid":
+		value = fmt.Sprintf("%08x-%04x-4%03x-a%03x-%012x",
+			g.rng.Uint32(), g.rng.Intn(0x10000), g.rng.Intn(0x1000), g.rng.Intn(0x1000), g.rng.Int63n(1<<48))
+	default:
+		value = g.sampleString(name)
+	}
+
+	minLength := intKeyword(schema, "minLength", 0)
+	if len(value) < minLength {
+		value += strings.Repeat("x", minLength-len(value))
+	}
+	if maxLength := intKeyword(schema, "maxLength", -1); maxLength >= 0 && len(value) > maxLength {
+		value = value[:maxLength]
+	}
+
+	return value
+}
+
+// sampleString picks a value matching the property name, or a generic one.
+func (g *ArgumentGenerator) sampleString(name string) string {
+	lower := strings.ToLower(name)
+	words := strings.FieldsFunc(lower, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	for _, sample := range sampleStrings {
+		// Short hints ("id") must be a whole word so "width" doesn't match
+		if slices.Contains(words, sample.hint) || (len(sample.hint) > 3 && strings.Contains(lower, sample.hint)) {
+			return sample.values[g.rng.Intn(len(sample.values))]
+		}
+	}
+
+	if name == "" {
+		return "sample"
+	}
+	return "sample " + strings.ReplaceAll(lower, "_", " ")
+}
+
+// randomString returns a random alphanumeric string from the seeded source.
+func (g *ArgumentGenerator) randomString(length int) string {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = charset[g.rng.Intn(len(charset))]
+	}
+	return string(b)
+}
+
+// schemaType returns the schema's type, picking the first non-null type
+// from a type array and inferring "object" from properties.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, option := range t {
+			if s, ok := option.(string); ok && s != "null" {
+				return s
+			}
+		}
+		return "null"
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return "string"
+}
+
+// resolveRef resolves a local reference such as "#/$defs/Address".
+func resolveRef(root map[string]interface{}, ref string) (map[string]interface{}, bool) {
+	path, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, false
+	}
+
+	current := root
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+
+	return current, true
+}
+
+// mergeSchemas combines allOf subschemas into one, unioning properties and
+// required lists. Later subschemas win for other keywords.
+func mergeSchemas(schemas []interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	var required []interface{}
+
+	for _, s := range schemas {
+		schema, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range schema {
+			switch key {
+			case "properties":
+				if props, ok := value.(map[string]interface{}); ok {
+					for name, prop := range props {
+						properties[name] = prop
+					}
+				}
+			case "required":
+				if list, ok := value.([]interface{}); ok {
+					required = append(required, list...)
+				}
+			default:
+				merged[key] = value
+			}
+		}
+	}
+
+	if len(properties) > 0 {
+		merged["properties"] = properties
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+
+	return merged
+}
+
+// bounds returns the numeric range for schema, widening a missing bound by
+// defaultNumberRange from the other.
+func bounds(schema map[string]interface{}) (float64, float64) {
+	low, hasLow := numberKeyword(schema, "minimum")
+	if v, ok := numberKeyword(schema, "exclusiveMinimum"); ok {
+		low, hasLow = v, true
+	}
+	high, hasHigh := numberKeyword(schema, "maximum")
+	if v, ok := numberKeyword(schema, "exclusiveMaximum"); ok {
+		high, hasHigh = v, true
+	}
+
+	switch {
+	case !hasLow && !hasHigh:
+		return 1, defaultNumberRange
+	case !hasLow:
+		return high - defaultNumberRange, high
+	case !hasHigh:
+		return low, low + defaultNumberRange
+	}
+	return low, high
+}
+
+// exclusive reports whether keyword makes bound exclusive, either as a
+// numeric bound equal to it or as a draft-04 boolean flag.
+func exclusive(schema map[string]interface{}, keyword string, bound float64) bool {
+	if flag, ok := schema[keyword].(bool); ok {
+		return flag
+	}
+	v, ok := numberKeyword(schema, keyword)
+	return ok && v == bound
+}
+
+// numberKeyword reads a numeric keyword decoded from JSON or YAML.
+func numberKeyword(schema map[string]interface{}, keyword string) (float64, bool) {
+	switch v := schema[keyword].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// intKeyword reads a non-negative integer keyword, or returns fallback.
+func intKeyword(schema map[string]interface{}, keyword string, fallback int) int {
+	if v, ok := numberKeyword(schema, keyword); ok && v >= 0 {
+		return int(v)
+	}
+	return fallback
+}
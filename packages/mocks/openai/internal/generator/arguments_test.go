@@ -0,0 +1,227 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// decodeSchema decodes a JSON Schema.
+func decodeSchema(t *testing.T, schema string) map[string]interface{} {
+	t.Helper()
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		// check validates the generated arguments
+		check func(args map[string]interface{}) bool
+	}{
+		{
+			name:   "required properties",
+			schema: `{"type": "object", "properties": {"location": {"type": "string"}, "unit": {"type": "string"}}, "required": ["location", "unit"]}`,
+			check: func(args map[string]interface{}) bool {
+				location, _ := args["location"].(string)
+				unit, _ := args["unit"].(string)
+				return strings.Contains(location, ",") && (unit == "celsius" || unit == "fahrenheit")
+			},
+		},
+		{
+			name:   "enum",
+			schema: `{"type": "object", "properties": {"size": {"enum": ["S", "M", "L"]}}, "required": ["size"]}`,
+			check: func(args map[string]interface{}) bool {
+				size := args["size"]
+				return size == "S" || size == "M" || size == "L"
+			},
+		},
+		{
+			name:   "const",
+			schema: `{"type": "object", "properties": {"kind": {"const": "refund"}}, "required": ["kind"]}`,
+			check:  func(args map[string]interface{}) bool { return args["kind"] == "refund" },
+		},
+		{
+			name:   "integer bounds",
+			schema: `{"type": "object", "properties": {"count": {"type": "integer", "minimum": 3, "exclusiveMaximum": 6, "multipleOf": 2}}, "required": ["count"]}`,
+			check: func(args map[string]interface{}) bool {
+				count, ok := args["count"].(float64)
+				return ok && count == 4
+			},
+		},
+		{
+			name:   "number bounds",
+			schema: `{"type": "object", "properties": {"ratio": {"type": "number", "minimum": 0, "maximum": 1}}, "required": ["ratio"]}`,
+			check: func(args map[string]interface{}) bool {
+				ratio, ok := args["ratio"].(float64)
+				return ok && ratio >= 0 && ratio <= 1
+			},
+		},
+		{
+			name:   "string formats and lengths",
+			schema: `{"type": "object", "properties": {"at": {"type": "string", "format": "date-time"}, "code": {"type": "string", "minLength": 10, "maxLength": 12}}, "required": ["at", "code"]}`,
+			check: func(args map[string]interface{}) bool {
+				code, _ := args["code"].(string)
+				return args["at"] == "2025-01-15T09:30:00Z" && len(code) >= 10 && len(code) <= 12
+			},
+		},
+		{
+			name:   "array items",
+			schema: `{"type": "object", "properties": {"cities": {"type": "array", "items": {"type": "string"}, "minItems": 2, "maxItems": 2}}, "required": ["cities"]}`,
+			check: func(args map[string]interface{}) bool {
+				cities, _ := args["cities"].([]interface{})
+				return len(cities) == 2
+			},
+		},
+		{
+			name:   "local ref",
+			schema: `{"type": "object", "properties": {"address": {"$ref": "#/$defs/address"}}, "required": ["address"], "$defs": {"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}}}`,
+			check: func(args map[string]interface{}) bool {
+				address, _ := args["address"].(map[string]interface{})
+				_, ok := address["city"].(string)
+				return ok
+			},
+		},
+		{
+			name:   "allOf",
+			schema: `{"allOf": [{"type": "object", "properties": {"a": {"type": "boolean"}}, "required": ["a"]}, {"properties": {"b": {"type": "null"}}, "required": ["b"]}]}`,
+			check: func(args map[string]interface{}) bool {
+				_, hasA := args["a"].(bool)
+				b, hasB := args["b"]
+				return hasA && hasB && b == nil
+			},
+		},
+		{
+			name:   "self reference",
+			schema: `{"type": "object", "properties": {"child": {"$ref": "#"}}, "required": ["child"]}`,
+			check:  func(args map[string]interface{}) bool { return args["child"] != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := decodeSchema(t, tt.schema)
+			for seed := int64(0); seed < 20; seed++ {
+				arguments, err := NewArgumentGenerator(seed).Generate(schema)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var args map[string]interface{}
+				if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+					t.Fatalf("seed %d: arguments %s are not a JSON object: %v", seed, arguments, err)
+				}
+				if !tt.check(args) {
+					t.Fatalf("seed %d: unexpected arguments %s", seed, arguments)
+				}
+
+				again, _ := NewArgumentGenerator(seed).Generate(schema)
+				if again != arguments {
+					t.Fatalf("seed %d generated %s, then %s", seed, arguments, again)
+				}
+			}
+		})
+	}
+
+	if arguments, err := NewArgumentGenerator(1).Generate(nil); err != nil || arguments != "{}" {
+		t.Errorf("Generate(nil) = %q, %v; want {}", arguments, err)
+	}
+}
+
+func TestToolCalls(t *testing.T) {
+	const tools = `"tools": [
+		{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}}},
+		{"type": "function", "function": {"name": "get_time", "parameters": {"type": "object", "properties": {}}}}
+	]`
+
+	tests := []struct {
+		name         string
+		body         string
+		maxParallel  int
+		wantNames    []string
+		wantMaxCalls int
+	}{
+		{name: "no tools", body: `{"messages": []}`},
+		{name: "none", body: `{"tool_choice": "none", ` + tools + `}`},
+		{name: "named", body: `{"tool_choice": {"type": "function", "function": {"name": "get_time"}}, ` + tools + `}`, maxParallel: 4, wantNames: []string{"get_time"}, wantMaxCalls: 1},
+		{name: "unknown name", body: `{"tool_choice": {"type": "function", "function": {"name": "get_stock"}}, ` + tools + `}`},
+		{name: "required", body: `{"tool_choice": "required", ` + tools + `}`, wantNames: []string{"get_weather", "get_time"}, wantMaxCalls: 1},
+		{name: "parallel", body: `{"tool_choice": "auto", ` + tools + `}`, maxParallel: 3, wantNames: []string{"get_weather", "get_time"}, wantMaxCalls: 3},
+		{name: "parallel disabled", body: `{"parallel_tool_calls": false, ` + tools + `}`, maxParallel: 3, wantNames: []string{"get_weather", "get_time"}, wantMaxCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req models.ChatCompletionRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+
+			for seed := int64(0); seed < 20; seed++ {
+				generator := NewArgumentGenerator(seed)
+				if tt.maxParallel > 0 {
+					generator.SetMaxParallelToolCalls(tt.maxParallel)
+				}
+
+				calls, err := generator.ToolCalls(&req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.wantMaxCalls == 0 {
+					if len(calls) != 0 {
+						t.Fatalf("seed %d: got %d calls, want none", seed, len(calls))
+					}
+					continue
+				}
+				if len(calls) == 0 || len(calls) > tt.wantMaxCalls {
+					t.Fatalf("seed %d: got %d calls, want 1 to %d", seed, len(calls), tt.wantMaxCalls)
+				}
+
+				for i, call := range calls {
+					if !containsString(tt.wantNames, call.Function.Name) {
+						t.Errorf("seed %d: called %s, want one of %v", seed, call.Function.Name, tt.wantNames)
+					}
+					if call.Index != i || !strings.HasPrefix(call.ID, "call_") || !json.Valid([]byte(call.Function.Arguments)) {
+						t.Errorf("seed %d: malformed call %+v", seed, call)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFunctionCall(t *testing.T) {
+	var req models.ChatCompletionRequest
+	body := `{"functions": [{"name": "lookup_order", "parameters": {"type": "object", "properties": {"order_id": {"type": "string"}}, "required": ["order_id"]}}], "function_call": {"name": "lookup_order"}}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+
+	call, err := NewArgumentGenerator(7).FunctionCall(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call == nil || call.Name != "lookup_order" || !strings.Contains(call.Arguments, `"order_id":"id_`) {
+		t.Errorf("FunctionCall() = %+v", call)
+	}
+
+	req.FunctionCall = "none"
+	if call, _ := NewArgumentGenerator(7).FunctionCall(&req); call != nil {
+		t.Errorf("function_call none made call %+v", call)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+// Package generator provides response generation for the OpenAI mock.
+// This file renders placeholder images: a solid background derived from
+// the prompt with the prompt text drawn on it, so clients receive real
+// image bytes to decode, resize and store.
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// Placeholder image layout.
+const (
+	// glyphWidth and glyphHeight are the bitmap font cell size in pixels
+	glyphWidth  = 5
+	glyphHeight = 7
+
+	// placeholderColumns is the number of characters per line of text
+	placeholderColumns = 24
+
+	// placeholderJPEGQuality is used for jpeg output without a compression level
+	placeholderJPEGQuality = 90
+)
+
+// PlaceholderImage describes a placeholder image to render.
+type PlaceholderImage struct {
+	// Prompt is drawn on the image and seeds the background color
+	Prompt string
+
+	// Width and Height are the image dimensions in pixels
+	Width  int
+	Height int
+
+	// Format is "png" (default), "jpeg" or "webp". There is no webp encoder
+	// in the standard library, so webp images are encoded as PNG.
+	Format string
+
+	// Compression is the jpeg compression level (0-100, optional)
+	Compression *int
+
+	// Transparent leaves the background transparent (png and webp only)
+	Transparent bool
+}
+
+// ParseImageSize parses a "WIDTHxHEIGHT" size such as "1024x1792".
+func ParseImageSize(size string) (width, height int, err error) {
+	w, h, ok := strings.Cut(size, "x")
+	if ok {
+		width, err = strconv.Atoi(w)
+		if err == nil {
+			height, err = strconv.Atoi(h)
+		}
+	}
+	if !ok || err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid image size %q (expected WIDTHxHEIGHT)", size)
+	}
+	return width, height, nil
+}
+
+// ContentType returns the MIME type of the encoded image.
+func (p PlaceholderImage) ContentType() string {
+	if p.Format == "jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// Encode renders and encodes the image. The same prompt and size always
+// produce the same bytes.
+func (p PlaceholderImage) Encode() ([]byte, error) {
+	if p.Width <= 0 || p.Height <= 0 {
+		return nil, fmt.Errorf("invalid image size %dx%d", p.Width, p.Height)
+	}
+
+	img := p.render()
+
+	var buf bytes.Buffer
+	switch p.Format {
+	case "", "png", "webp":
+		encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %w", err)
+		}
+	case "jpeg":
+		quality := placeholderJPEGQuality
+		if p.Compression != nil {
+			// output_compression is how much to compress, not quality
+			quality = max(100-*p.Compression, 1)
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image format %q", p.Format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// render draws the background and the wrapped, centered prompt text.
+func (p PlaceholderImage) render() *image.Paletted {
+	background := placeholderColor(p.Prompt)
+	palette := color.Palette{background, textColor(background)}
+	if p.Transparent && p.Format != "jpeg" {
+		palette[0] = color.RGBA{}
+	}
+	img := image.NewPaletted(image.Rect(0, 0, p.Width, p.Height), palette)
+
+	// Scale the font so a line of placeholderColumns characters fills
+	// most of the width (each cell is a glyph plus one pixel of spacing)
+	scale := max(p.Width*9/10/(placeholderColumns*(glyphWidth+1)), 1)
+	lineHeight := (glyphHeight + 3) * scale
+
+	lines := wrapText(strings.ToUpper(p.Prompt), placeholderColumns)
+	if maxLines := max(p.Height*9/10/lineHeight, 1); len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1]
+		if len(last) > placeholderColumns-3 {
+			last = last[:placeholderColumns-3]
+		}
+		lines[maxLines-1] = last + "..."
+	}
+
+	y := (p.Height - len(lines)*lineHeight) / 2
+	for _, line := range lines {
+		x := (p.Width - len(line)*(glyphWidth+1)*scale) / 2
+		for _, r := range line {
+			drawGlyph(img, r, x, y, scale)
+			x += (glyphWidth + 1) * scale
+		}
+		y += lineHeight
+	}
+
+	return img
+}
+
+// placeholderColor derives a stable background color from the prompt.
+func placeholderColor(prompt string) color.RGBA {
+	h := fnv.New32a()
+	h.Write([]byte(prompt))
+	sum := h.Sum32()
+	return color.RGBA{R: uint8(sum >> 16), G: uint8(sum >> 8), B: uint8(sum), A: 0xff}
+}
+
+// textColor returns black or white, whichever contrasts with background.
+func textColor(background color.RGBA) color.RGBA {
+	luma := 299*int(background.R) + 587*int(background.G) + 114*int(background.B)
+	if luma > 128*1000 {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+}
+
+// wrapText splits text into lines of at most columns characters, breaking
+// at spaces where possible.
+func wrapText(text string, columns int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		for len(word) > columns {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:columns])
+			word = word[columns:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= columns:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawGlyph draws r with its top-left corner at (x, y), scaled up.
+// Characters outside the font are drawn as '?'.
+func drawGlyph(img *image.Paletted, r rune, x, y, scale int) {
+	glyph, ok := font5x7[r]
+	if !ok {
+		glyph = font5x7['?']
+	}
+
+	for row, bits := range glyph {
+		for col := 0; col < glyphWidth; col++ {
+			if bits&(1<<(glyphWidth-1-col)) == 0 {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(x+col*scale+dx, y+row*scale+dy, 1)
+				}
+			}
+		}
+	}
+}
+
+// font5x7 is a 5x7 bitmap font; each row's low five bits are its pixels,
+// most significant bit leftmost.
+var font5x7 = map[rune][glyphHeight]uint8{
+	' ':  {},
+	'A':  {0x0E, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'B':  {0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E},
+	'C':  {0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E},
+	'D':  {0x1E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x1E},
+	'E':  {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F},
+	'F':  {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10},
+	'G':  {0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0F},
+	'H':  {0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'I':  {0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'J':  {0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C},
+	'K':  {0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11},
+	'L':  {0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F},
+	'M':  {0x11, 0x1B, 0x15, 0x15, 0x11, 0x11, 0x11},
+	'N':  {0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11},
+	'O':  {0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'P':  {0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10},
+	'Q':  {0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D},
+	'R':  {0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11},
+	'S':  {0x0F, 0x10, 0x10, 0x0E, 0x01, 0x01, 0x1E},
+	'T':  {0x1F, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'U':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'V':  {0x11, 0x11, 0x11, 0x11, 0x11, 0x0A, 0x04},
+	'W':  {0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0A},
+	'X':  {0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11},
+	'Y':  {0x11, 0x11, 0x11, 0x0A, 0x04, 0x04, 0x04},
+	'Z':  {0x1F, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1F},
+	'0':  {0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	'1':  {0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'2':  {0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F},
+	'3':  {0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E},
+	'4':  {0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	'5':  {0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	'6':  {0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	'7':  {0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8':  {0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	'9':  {0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+	'.':  {0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C},
+	',':  {0x00, 0x00, 0x00, 0x00, 0x0C, 0x04, 0x08},
+	'!':  {0x04, 0x04, 0x04, 0x04, 0x04, 0x00, 0x04},
+	'?':  {0x0E, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04},
+	'-':  {0x00, 0x00, 0x00, 0x1F, 0x00, 0x00, 0x00},
+	'\'': {0x04, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00},
+	':':  {0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x0C, 0x00},
+	'/':  {0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00},
+}
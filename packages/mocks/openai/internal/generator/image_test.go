@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"testing"
+)
+
+func TestParseImageSize(t *testing.T) {
+	tests := []struct {
+		size          string
+		width, height int
+		wantErr       bool
+	}{
+		{size: "1024x1792", width: 1024, height: 1792},
+		{size: "256x256", width: 256, height: 256},
+		{size: "1024", wantErr: true},
+		{size: "0x512", wantErr: true},
+		{size: "widexhigh", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		width, height, err := ParseImageSize(tt.size)
+		if (err != nil) != tt.wantErr || width != tt.width || height != tt.height {
+			t.Errorf("ParseImageSize(%q) = %d, %d, %v; want %d, %d, error %v", tt.size, width, height, err, tt.width, tt.height, tt.wantErr)
+		}
+	}
+}
+
+func TestPlaceholderImageEncode(t *testing.T) {
+	compression := 80
+
+	tests := []struct {
+		name        string
+		image       PlaceholderImage
+		wantFormat  string
+		contentType string
+		wantErr     bool
+	}{
+		{name: "png", image: PlaceholderImage{Prompt: "a red fox", Width: 64, Height: 48}, wantFormat: "png", contentType: "image/png"},
+		{name: "jpeg", image: PlaceholderImage{Prompt: "a red fox", Width: 64, Height: 48, Format: "jpeg", Compression: &compression}, wantFormat: "jpeg", contentType: "image/jpeg"},
+		{name: "webp as png", image: PlaceholderImage{Prompt: "a red fox", Width: 32, Height: 32, Format: "webp", Transparent: true}, wantFormat: "png", contentType: "image/png"},
+		{name: "unknown format", image: PlaceholderImage{Width: 32, Height: 32, Format: "gif"}, wantErr: true},
+		{name: "no size", image: PlaceholderImage{Prompt: "a red fox"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.image.Encode()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Encode() succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config, format, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.wantFormat || config.Width != tt.image.Width || config.Height != tt.image.Height {
+				t.Errorf("decoded a %dx%d %s, want %dx%d %s", config.Width, config.Height, format, tt.image.Width, tt.image.Height, tt.wantFormat)
+			}
+			if got := tt.image.ContentType(); got != tt.contentType {
+				t.Errorf("ContentType() = %q, want %q", got, tt.contentType)
+			}
+
+			again, _ := tt.image.Encode()
+			if !bytes.Equal(again, data) {
+				t.Error("the same image encoded differently")
+			}
+		})
+	}
+}
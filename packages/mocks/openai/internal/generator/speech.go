@@ -0,0 +1,156 @@
+// Package generator provides response generation for the OpenAI mock.
+// This file synthesizes text-to-speech audio: a sine tone pitched by the
+// voice and gated by the words of the input, so clients receive real audio
+// bytes to decode, play and store.
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"time"
+	"unicode"
+)
+
+// Synthetic speech parameters.
+const (
+	// speechSampleRate is the sample rate of wav and pcm output (24kHz
+	// 16-bit mono, like OpenAI's pcm)
+	speechSampleRate = 24000
+
+	// speechCharsPerSecond is the speaking rate at speed 1.0
+	speechCharsPerSecond = 15
+
+	// minSpeechDuration and maxSpeechDuration bound the audio length, so
+	// payloads stay small
+	minSpeechDuration = 250 * time.Millisecond
+	maxSpeechDuration = 5 * time.Second
+
+	// speechAmplitude is the tone's peak amplitude (of 1.0)
+	speechAmplitude = 0.3
+)
+
+// mp3SilentFrame is an MPEG-1 Layer III frame of silence: 48kHz, 32kbps,
+// mono, no CRC. It is 96 bytes long and plays for 1152 samples (24ms).
+var mp3SilentFrame = func() []byte {
+	frame := make([]byte, 96)
+	copy(frame, []byte{0xFF, 0xFB, 0x14, 0xC0})
+	return frame
+}()
+
+// mp3FrameDuration is how long one mp3 frame plays.
+const mp3FrameDuration = 24 * time.Millisecond
+
+// SyntheticSpeech describes speech to synthesize.
+type SyntheticSpeech struct {
+	// Text is the input; its length sets the duration and its words gate
+	// the tone
+	Text string
+
+	// Voice seeds the tone's pitch
+	Voice string
+
+	// Format is "mp3" (default), "opus", "aac", "flac", "wav" or "pcm".
+	// There are no opus, aac or flac encoders in the standard library, so
+	// those are encoded as WAV; mp3 is valid silent frames.
+	Format string
+
+	// Speed scales the speaking rate (default 1.0)
+	Speed float64
+}
+
+// Duration returns how long the audio plays.
+func (s SyntheticSpeech) Duration() time.Duration {
+	speed := s.Speed
+	if speed <= 0 {
+		speed = 1.0
+	}
+
+	characters := len([]rune(s.Text))
+	duration := time.Duration(float64(characters) / speechCharsPerSecond / speed * float64(time.Second))
+	return min(max(duration, minSpeechDuration), maxSpeechDuration)
+}
+
+// ContentType returns the MIME type of the encoded audio.
+func (s SyntheticSpeech) ContentType() string {
+	switch s.Format {
+	case "", "mp3":
+		return "audio/mpeg"
+	case "pcm":
+		return "audio/pcm"
+	}
+	return "audio/wav"
+}
+
+// Encode synthesizes and encodes the audio. The same input always produces
+// the same bytes.
+func (s SyntheticSpeech) Encode() ([]byte, error) {
+	switch s.Format {
+	case "", "mp3":
+		return s.encodeMP3(), nil
+	case "pcm":
+		return s.samples(), nil
+	case "wav", "opus", "aac", "flac":
+		return s.encodeWAV(), nil
+	}
+	return nil, fmt.Errorf("unsupported audio format %q", s.Format)
+}
+
+// encodeMP3 returns enough silent frames to play for the duration.
+func (s SyntheticSpeech) encodeMP3() []byte {
+	frames := int((s.Duration() + mp3FrameDuration - 1) / mp3FrameDuration)
+	return bytes.Repeat(mp3SilentFrame, frames)
+}
+
+// encodeWAV wraps the samples in a RIFF/WAVE header.
+func (s SyntheticSpeech) encodeWAV() []byte {
+	samples := s.samples()
+
+	var buf bytes.Buffer
+	buf.Grow(44 + len(samples))
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(samples)))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))                 // fmt chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))                  // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))                  // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(speechSampleRate))   // sample rate
+	binary.Write(&buf, binary.LittleEndian, uint32(speechSampleRate*2)) // byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(2))                  // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))                 // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(samples)))
+	buf.Write(samples)
+	return buf.Bytes()
+}
+
+// samples renders the audio as 16-bit little-endian mono samples. The tone
+// sounds while a letter or digit of the input would be spoken and is
+// silent for spaces and punctuation.
+func (s SyntheticSpeech) samples() []byte {
+	text := []rune(s.Text)
+	n := int(s.Duration().Seconds() * speechSampleRate)
+	frequency := voiceFrequency(s.Voice)
+
+	out := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		if len(text) > 0 {
+			r := text[i*len(text)/n]
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				continue
+			}
+		}
+		value := speechAmplitude * math.Sin(2*math.Pi*frequency*float64(i)/speechSampleRate)
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16(value*math.MaxInt16)))
+	}
+	return out
+}
+
+// voiceFrequency returns a voice's pitch, between 140 and 260 Hz.
+func voiceFrequency(voice string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(voice))
+	return 140 + float64(h.Sum32()%121)
+}
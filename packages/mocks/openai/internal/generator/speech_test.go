@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestSyntheticSpeech(t *testing.T) {
+	tests := []struct {
+		name         string
+		speech       SyntheticSpeech
+		wantDuration time.Duration
+		contentType  string
+		// magic is the expected start of the encoded audio
+		magic   []byte
+		wantErr bool
+	}{
+		{name: "mp3", speech: SyntheticSpeech{Text: "Hello there, how are you today?", Voice: "alloy"}, wantDuration: 2 * time.Second, contentType: "audio/mpeg", magic: []byte{0xFF, 0xFB}},
+		{name: "wav", speech: SyntheticSpeech{Text: "Hello there, how are you today?", Format: "wav"}, wantDuration: 2 * time.Second, contentType: "audio/wav", magic: []byte("RIFF")},
+		{name: "flac as wav", speech: SyntheticSpeech{Text: "Hi", Format: "flac"}, wantDuration: minSpeechDuration, contentType: "audio/wav", magic: []byte("RIFF")},
+		{name: "fast", speech: SyntheticSpeech{Text: "Hello there, how are you today?", Format: "pcm", Speed: 2}, wantDuration: time.Second, contentType: "audio/pcm"},
+		{name: "capped", speech: SyntheticSpeech{Text: string(bytes.Repeat([]byte("word "), 100))}, wantDuration: maxSpeechDuration, contentType: "audio/mpeg", magic: []byte{0xFF, 0xFB}},
+		{name: "unknown format", speech: SyntheticSpeech{Text: "Hi", Format: "ogg"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.speech.Encode()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Encode() succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			duration := tt.speech.Duration()
+			if diff := duration - tt.wantDuration; diff < -100*time.Millisecond || diff > 100*time.Millisecond {
+				t.Errorf("Duration() = %s, want about %s", duration, tt.wantDuration)
+			}
+			if got := tt.speech.ContentType(); got != tt.contentType {
+				t.Errorf("ContentType() = %q, want %q", got, tt.contentType)
+			}
+			if !bytes.HasPrefix(data, tt.magic) {
+				t.Errorf("audio starts with % x, want % x", data[:min(len(data), 4)], tt.magic)
+			}
+
+			switch tt.speech.Format {
+			case "pcm":
+				if want := int(duration.Seconds()*speechSampleRate) * 2; len(data) != want {
+					t.Errorf("pcm is %d bytes, want %d", len(data), want)
+				}
+			case "wav", "flac":
+				if size := binary.LittleEndian.Uint32(data[40:44]); int(size) != len(data)-44 {
+					t.Errorf("wav data chunk is %d bytes, file has %d", size, len(data)-44)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package generator provides response generation for requests no fixture covers.
+// This file generates structured outputs (response_format json_schema) and
+// the schema violations used to test how agents handle bad model output.
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Schema violations a fixture can inject into structured output.
+const (
+	// ViolationMissingRequired drops a required property
+	ViolationMissingRequired = "missing_required"
+
+	// ViolationWrongType gives a property a value of the wrong type
+	ViolationWrongType = "wrong_type"
+
+	// ViolationExtraProperty adds a property the schema does not declare
+	ViolationExtraProperty = "extra_property"
+
+	// ViolationInvalidJSON truncates the output so it does not parse
+	ViolationInvalidJSON = "invalid_json"
+)
+
+// SchemaViolations are the supported schema violations.
+var SchemaViolations = []string{ViolationMissingRequired, ViolationWrongType, ViolationExtraProperty, ViolationInvalidJSON}
+
+// StructuredOutput returns JSON content satisfying a json_schema response
+// format's schema.
+func (g *ArgumentGenerator) StructuredOutput(format *models.JSONSchemaFormat) (string, error) {
+	if format == nil {
+		return "{}", nil
+	}
+	content, err := g.Generate(format.Schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate %s output: %w", format.Name, err)
+	}
+	return content, nil
+}
+
+// ViolateSchema breaks content, a JSON object satisfying schema, in the
+// given way. Violations that do not apply to the schema (no required
+// property to drop, no property to retype) fall back to adding an
+// undeclared property, so the result never validates.
+func ViolateSchema(content string, schema map[string]interface{}, violation string) (string, error) {
+	if violation == ViolationInvalidJSON {
+		if len(content) < 2 {
+			return "{", nil
+		}
+		return content[:len(content)/2], nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &object); err != nil {
+		return "", fmt.Errorf("cannot apply %s to content that is not a JSON object", violation)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	applied := false
+	switch violation {
+	case ViolationMissingRequired:
+		if required, ok := schema["required"].([]interface{}); ok && len(required) > 0 {
+			if name, ok := required[0].(string); ok {
+				delete(object, name)
+				applied = true
+			}
+		}
+	case ViolationWrongType:
+		if len(names) > 0 {
+			property, _ := properties[names[0]].(map[string]interface{})
+			object[names[0]] = wrongTypeValue(schemaType(property))
+			applied = true
+		}
+	case ViolationExtraProperty:
+	default:
+		return "", fmt.Errorf("unknown schema violation %q", violation)
+	}
+
+	if !applied {
+		object["unexpected_property"] = true
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode output: %w", err)
+	}
+	return string(data), nil
+}
+
+// wrongTypeValue returns a value that is not of the given schema type.
+func wrongTypeValue(schemaType string) interface{} {
+	if schemaType == "string" {
+		return 42
+	}
+	return "invalid"
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestViolateSchema(t *testing.T) {
+	const content = `{"name":"Alex Smith","age":30}`
+	schema := `{"type": "object", "properties": {"age": {"type": "integer"}, "name": {"type": "string"}}, "required": ["name"]}`
+
+	tests := []struct {
+		name      string
+		schema    string
+		violation string
+		// check validates the broken object
+		check   func(object map[string]interface{}) bool
+		wantErr bool
+	}{
+		{
+			name:      "missing required",
+			schema:    schema,
+			violation: ViolationMissingRequired,
+			check: func(object map[string]interface{}) bool {
+				_, ok := object["name"]
+				return !ok && object["age"] == float64(30)
+			},
+		},
+		{
+			name:      "wrong type",
+			schema:    schema,
+			violation: ViolationWrongType,
+			check:     func(object map[string]interface{}) bool { return object["age"] == "invalid" },
+		},
+		{
+			name:      "extra property",
+			schema:    schema,
+			violation: ViolationExtraProperty,
+			check:     func(object map[string]interface{}) bool { return object["unexpected_property"] == true },
+		},
+		{
+			name:      "nothing required falls back to an extra property",
+			schema:    `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			violation: ViolationMissingRequired,
+			check: func(object map[string]interface{}) bool {
+				return object["unexpected_property"] == true && object["name"] != nil
+			},
+		},
+		{
+			name:      "unknown",
+			schema:    schema,
+			violation: "sideways",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken, err := ViolateSchema(content, decodeSchema(t, tt.schema), tt.violation)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ViolateSchema() = %s, want an error", broken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var object map[string]interface{}
+			if err := json.Unmarshal([]byte(broken), &object); err != nil {
+				t.Fatalf("broken output %s is not JSON: %v", broken, err)
+			}
+			if !tt.check(object) {
+				t.Errorf("unexpected output %s", broken)
+			}
+		})
+	}
+
+	broken, err := ViolateSchema(content, decodeSchema(t, schema), ViolationInvalidJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.Valid([]byte(broken)) {
+		t.Errorf("invalid_json output %s parses", broken)
+	}
+}
+
+func TestStructuredOutput(t *testing.T) {
+	format := &models.JSONSchemaFormat{
+		Name:   "person",
+		Schema: decodeSchema(t, `{"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0, "maximum": 120}}, "required": ["name", "age"], "additionalProperties": false}`),
+	}
+
+	output, err := NewArgumentGenerator(3).StructuredOutput(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var person struct {
+		Name string `json:"name"`
+		Age  *int   `json:"age"`
+	}
+	if err := json.Unmarshal([]byte(output), &person); err != nil {
+		t.Fatal(err)
+	}
+	if person.Name == "" || person.Age == nil || *person.Age < 0 || *person.Age > 120 {
+		t.Errorf("StructuredOutput() = %s", output)
+	}
+
+	if output, err := NewArgumentGenerator(3).StructuredOutput(nil); err != nil || output != "{}" {
+		t.Errorf("StructuredOutput(nil) = %q, %v", output, err)
+	}
+}
@@ -0,0 +1,349 @@
+// Package generator provides response generation for requests no fixture covers.
+// This file synthesizes realistic chat content from templates.
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Strategy selects how synthesized content is shaped.
+type Strategy string
+
+const (
+	// StrategyAuto classifies the prompt and picks one of the strategies below
+	StrategyAuto Strategy = "auto"
+
+	// StrategyEcho restates the request and elaborates on it
+	StrategyEcho Strategy = "echo"
+
+	// StrategyList answers with a numbered list
+	StrategyList Strategy = "list"
+
+	// StrategyCode answers with a fenced code block
+	StrategyCode Strategy = "code"
+
+	// StrategyRefusal declines the request
+	StrategyRefusal Strategy = "refusal"
+)
+
+// ParseStrategy parses a strategy name. An empty name is StrategyAuto.
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(strings.ToLower(strings.TrimSpace(name))); strategy {
+	case "":
+		return StrategyAuto, nil
+	case StrategyAuto, StrategyEcho, StrategyList, StrategyCode, StrategyRefusal:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown synthesis strategy %q (expected auto, echo, list, code or refusal)", name)
+	}
+}
+
+// SynthesizerConfig configures response synthesis.
+type SynthesizerConfig struct {
+	// DefaultStrategy applies to requests without a project override
+	DefaultStrategy Strategy
+
+	// ProjectStrategies overrides the strategy per OpenAI-Project ID
+	ProjectStrategies map[string]Strategy
+}
+
+// DefaultSynthesizerConfig returns default configuration.
+func DefaultSynthesizerConfig() SynthesizerConfig {
+	return SynthesizerConfig{
+		DefaultStrategy:   StrategyAuto,
+		ProjectStrategies: make(map[string]Strategy),
+	}
+}
+
+// ParseProjectStrategies parses "proj_a=code,proj_b=refusal" into a map.
+func ParseProjectStrategies(value string) (map[string]Strategy, error) {
+	strategies := make(map[string]Strategy)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		project, name, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(project) == "" {
+			return nil, fmt.Errorf("invalid project strategy %q (expected <project>=<strategy>)", pair)
+		}
+
+		strategy, err := ParseStrategy(name)
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", project, err)
+		}
+		strategies[strings.TrimSpace(project)] = strategy
+	}
+	return strategies, nil
+}
+
+// Synthesis is synthesized assistant content.
+type Synthesis struct {
+	// Strategy is the strategy that produced the content (never StrategyAuto)
+	Strategy Strategy
+
+	// Content is the assistant message content
+	Content string
+}
+
+// templateData is passed to content templates.
+type templateData struct {
+	// Topic is the user's request, trimmed to a short phrase
+	Topic string
+
+	// Language is the code block language (code strategy only)
+	Language string
+
+	// Points are list items (list strategy only)
+	Points []string
+}
+
+// contentTemplates holds the template variants for each strategy.
+var contentTemplates = map[Strategy][]*template.Template{
+	StrategyEcho: parseTemplates("echo",
+		"You're asking about {{.Topic}}. The short answer is that it depends on your context, but the key idea is straightforward: start with the simplest approach that works, then refine it as requirements become clearer.\n\nIf you share more details about your situation, I can give a more specific recommendation.",
+		"Good question about {{.Topic}}. In most cases, the best approach balances simplicity with the constraints you're working under. Consider what matters most here (speed, cost, or accuracy) and let that guide the decision.\n\nWould you like me to go deeper on any part of this?",
+	),
+	StrategyList: parseTemplates("list",
+		"Here are a few points on {{.Topic}}:\n\n{{range $i, $p := .Points}}{{inc $i}}. {{$p}}\n{{end}}\nLet me know if you'd like more detail on any of these.",
+		"Sure, here's a breakdown of {{.Topic}}:\n\n{{range $i, $p := .Points}}{{inc $i}}. {{$p}}\n{{end}}\nEach of these can be adjusted to fit your situation.",
+	),
+	StrategyCode: parseTemplates("code",
+		"Here's an example in {{.Language}} for {{.Topic}}:\n\n```{{.Language}}\n{{snippet .Language}}\n```\n\nThis is a minimal starting point; add input validation and error handling for production use.",
+		"Sure. Here's a {{.Language}} implementation:\n\n```{{.Language}}\n{{snippet .Language}}\n```\n\nIt keeps the logic in one function so it's easy to test and extend.",
+	),
+	StrategyRefusal: parseTemplates("refusal",
+		"I'm sorry, but I can't help with that request.",
+		"I can't assist with that. If you have a different question, I'm happy to help.",
+	),
+}
+
+// listPoints are the candidate items for the list strategy.
+var listPoints = []string{
+	"Start by clarifying the goal and the constraints you're working within.",
+	"Break the problem into smaller steps you can verify independently.",
+	"Compare the available options on cost, complexity, and maintenance.",
+	"Prototype the most promising option before committing to it.",
+	"Measure the results and iterate based on what you learn.",
+	"Document the decision so others understand the trade-offs.",
+}
+
+// codeSnippets are placeholder implementations per language.
+var codeSnippets = map[string]string{
+	"python":     "def solve(data):\n    \"\"\"Process the input and return the result.\"\"\"\n    result = []\n    for item in data:\n        result.append(item)\n    return result",
+	"javascript": "function solve(data) {\n  // Process the input and return the result\n  return data.map((item) => item);\n}",
+	"typescript": "function solve<T>(data: T[]): T[] {\n  // Process the input and return the result\n  return data.map((item) => item);\n}",
+	"go":         "func solve(data []string) []string {\n\t// Process the input and return the result\n\tresult := make([]string, 0, len(data))\n\tresult = append(result, data...)\n\treturn result\n}",
+	"java":       "public static List<String> solve(List<String> data) {\n    // Process the input and return the result\n    return new ArrayList<>(data);\n}",
+	"rust":       "fn solve(data: &[String]) -> Vec<String> {\n    // Process the input and return the result\n    data.to_vec()\n}",
+	"sql":        "SELECT id, name, created_at\nFROM items\nWHERE created_at >= NOW() - INTERVAL '7 days'\nORDER BY created_at DESC;",
+	"bash":       "#!/usr/bin/env bash\nset -euo pipefail\n\nfor file in \"$@\"; do\n  echo \"Processing $file\"\ndone",
+}
+
+// codeLanguages maps prompt keywords to code block languages, checked in order.
+var codeLanguages = []struct {
+	keyword  string
+	language string
+}{
+	{"typescript", "typescript"},
+	{"javascript", "javascript"},
+	{"node", "javascript"},
+	{"golang", "go"},
+	{" go ", "go"},
+	{"rust", "rust"},
+	{"java", "java"},
+	{"sql", "sql"},
+	{"query", "sql"},
+	{"bash", "bash"},
+	{"shell", "bash"},
+	{"python", "python"},
+}
+
+var (
+	// refusalPattern matches requests a production model would decline
+	refusalPattern = regexp.MustCompile(`(?i)\b(malware|ransomware|keylogger|exploit|steal|phishing|weapon|bomb|bypass (the )?(auth|security|paywall)|credit card numbers)\b`)
+
+	// codePattern matches code-ish prompts
+	codePattern = regexp.MustCompile("(?i)(```|\\b(code|function|implement|script|class|method|regex|bug|debug|compile|refactor|snippet|python|javascript|typescript|golang|rust|java|sql)\\b)")
+
+	// listPattern matches prompts asking for several items
+	listPattern = regexp.MustCompile(`(?i)\b(list|steps|ways|tips|options|ideas|examples|pros and cons|compare|top \d+|checklist|how (do|can|should) i)\b`)
+
+	// leadingPhrase matches conversational openers stripped from the topic
+	leadingPhrase = regexp.MustCompile(`(?i)^(please|hey|hi|can you|could you|would you|will you|i need you to|help me|tell me|explain|write|give me|show me|what is|what are|how do i|how can i)\b[\s,]*`)
+)
+
+// maxTopicWords bounds the topic phrase echoed back in content.
+const maxTopicWords = 12
+
+// Synthesizer builds realistic assistant content for prompts that no
+// fixture matches, instead of generic filler.
+type Synthesizer struct {
+	// config is the synthesis configuration (guarded by mu)
+	config SynthesizerConfig
+
+	// mu protects config
+	mu sync.RWMutex
+}
+
+// NewSynthesizer creates a new synthesizer.
+func NewSynthesizer(config SynthesizerConfig) *Synthesizer {
+	if config.DefaultStrategy == "" {
+		config.DefaultStrategy = StrategyAuto
+	}
+
+	projects := make(map[string]Strategy, len(config.ProjectStrategies))
+	for project, strategy := range config.ProjectStrategies {
+		projects[project] = strategy
+	}
+	config.ProjectStrategies = projects
+
+	return &Synthesizer{config: config}
+}
+
+// SetDefaultStrategy sets the strategy for requests without a project override.
+func (s *Synthesizer) SetDefaultStrategy(strategy Strategy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.DefaultStrategy = strategy
+}
+
+// SetProjectStrategy overrides the strategy for one project.
+func (s *Synthesizer) SetProjectStrategy(project string, strategy Strategy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.ProjectStrategies[project] = strategy
+}
+
+// StrategyFor returns the configured strategy for a project (may be StrategyAuto).
+func (s *Synthesizer) StrategyFor(project string) Strategy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if strategy, ok := s.config.ProjectStrategies[project]; ok && project != "" {
+		return strategy
+	}
+	return s.config.DefaultStrategy
+}
+
+// Synthesize builds assistant content for messages. project selects a
+// per-project strategy and seed makes template selection deterministic.
+func (s *Synthesizer) Synthesize(messages []models.Message, project string, seed int64) Synthesis {
+	prompt := lastUserMessage(messages)
+
+	strategy := s.StrategyFor(project)
+	if strategy == StrategyAuto {
+		strategy = ClassifyPrompt(prompt)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	data := templateData{
+		Topic:    topicOf(prompt),
+		Language: languageOf(prompt),
+		Points:   pickPoints(rng, 3),
+	}
+
+	variants := contentTemplates[strategy]
+	tmpl := variants[rng.Intn(len(variants))]
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		// Templates are static; fall back rather than fail the request
+		return Synthesis{Strategy: strategy, Content: "I understand. Let me help you with that."}
+	}
+
+	return Synthesis{Strategy: strategy, Content: strings.TrimSpace(builder.String())}
+}
+
+// ClassifyPrompt picks a content strategy from the prompt text.
+// Refusals take precedence, then code, then lists; anything else is echoed.
+func ClassifyPrompt(prompt string) Strategy {
+	switch {
+	case refusalPattern.MatchString(prompt):
+		return StrategyRefusal
+	case codePattern.MatchString(prompt):
+		return StrategyCode
+	case listPattern.MatchString(prompt):
+		return StrategyList
+	default:
+		return StrategyEcho
+	}
+}
+
+// lastUserMessage returns the content of the most recent user message.
+func lastUserMessage(messages []models.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// topicOf trims a prompt to a short phrase suitable for echoing back.
+func topicOf(prompt string) string {
+	topic := strings.TrimSpace(strings.SplitN(prompt, "\n", 2)[0])
+	for {
+		trimmed := leadingPhrase.ReplaceAllString(topic, "")
+		if trimmed == topic {
+			break
+		}
+		topic = trimmed
+	}
+
+	words := strings.Fields(topic)
+	if len(words) > maxTopicWords {
+		words = words[:maxTopicWords]
+	}
+	topic = strings.TrimRight(strings.Join(words, " "), "?.!,:;")
+
+	if topic == "" {
+		return "your request"
+	}
+	return topic
+}
+
+// languageOf picks the code block language named in the prompt (default python).
+func languageOf(prompt string) string {
+	lower := " " + strings.ToLower(prompt) + " "
+	for _, candidate := range codeLanguages {
+		if strings.Contains(lower, candidate.keyword) {
+			return candidate.language
+		}
+	}
+	return "python"
+}
+
+// pickPoints selects n distinct list points in a seeded order.
+func pickPoints(rng *rand.Rand, n int) []string {
+	order := rng.Perm(len(listPoints))
+	points := make([]string, 0, n)
+	for _, i := range order[:n] {
+		points = append(points, listPoints[i])
+	}
+	return points
+}
+
+// parseTemplates compiles the template variants for a strategy.
+func parseTemplates(name string, variants ...string) []*template.Template {
+	funcs := template.FuncMap{
+		"inc":     func(i int) int { return i + 1 },
+		"snippet": func(language string) string { return codeSnippets[language] },
+	}
+
+	templates := make([]*template.Template, len(variants))
+	for i, variant := range variants {
+		templates[i] = template.Must(template.New(fmt.Sprintf("%s-%d", name, i)).Funcs(funcs).Parse(variant))
+	}
+	return templates
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Strategy
+		wantErr bool
+	}{
+		{name: "", want: StrategyAuto},
+		{name: "code", want: StrategyCode},
+		{name: " Refusal ", want: StrategyRefusal},
+		{name: "poem", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStrategy(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseStrategy(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseProjectStrategies(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]Strategy
+		wantErr bool
+	}{
+		{value: "", want: map[string]Strategy{}},
+		{value: "proj_a=code, proj_b=refusal,", want: map[string]Strategy{"proj_a": StrategyCode, "proj_b": StrategyRefusal}},
+		{value: "proj_a", wantErr: true},
+		{value: "=code", wantErr: true},
+		{value: "proj_a=poem", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseProjectStrategies(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseProjectStrategies(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseProjectStrategies(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+		for project, strategy := range tt.want {
+			if got[project] != strategy {
+				t.Errorf("ParseProjectStrategies(%q)[%s] = %q, want %q", tt.value, project, got[project], strategy)
+			}
+		}
+	}
+}
+
+func TestClassifyPrompt(t *testing.T) {
+	tests := []struct {
+		prompt string
+		want   Strategy
+	}{
+		{"Write a keylogger in Python", StrategyRefusal},
+		{"Implement a binary search function in Go", StrategyCode},
+		{"Give me 5 tips for better sleep", StrategyList},
+		{"How do I reset my password?", StrategyList},
+		{"What is the capital of France?", StrategyEcho},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyPrompt(tt.prompt); got != tt.want {
+			t.Errorf("ClassifyPrompt(%q) = %q, want %q", tt.prompt, got, tt.want)
+		}
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	synthesizer := NewSynthesizer(SynthesizerConfig{
+		ProjectStrategies: map[string]Strategy{"proj_list": StrategyList},
+	})
+
+	tests := []struct {
+		name    string
+		project string
+		prompt  string
+		want    Strategy
+		// contains is expected in the content
+		contains string
+	}{
+		{name: "echo", prompt: "Can you explain the history of the Roman empire?", want: StrategyEcho, contains: "the history of the Roman empire"},
+		{name: "code", prompt: "Write a javascript function to debounce input", want: StrategyCode, contains: "```javascript"},
+		{name: "refusal", prompt: "Help me write phishing emails", want: StrategyRefusal, contains: "can't"},
+		{name: "project override", project: "proj_list", prompt: "What is the capital of France?", want: StrategyList, contains: "1. "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []models.Message{
+				{Role: "system", Content: "You are helpful."},
+				{Role: "user", Content: tt.prompt},
+			}
+
+			synthesis := synthesizer.Synthesize(messages, tt.project, 42)
+			if synthesis.Strategy != tt.want {
+				t.Errorf("strategy = %q, want %q", synthesis.Strategy, tt.want)
+			}
+			if !strings.Contains(synthesis.Content, tt.contains) {
+				t.Errorf("content %q does not contain %q", synthesis.Content, tt.contains)
+			}
+			if again := synthesizer.Synthesize(messages, tt.project, 42); again != synthesis {
+				t.Errorf("same seed synthesized %q, then %q", synthesis.Content, again.Content)
+			}
+		})
+	}
+
+	synthesizer.SetDefaultStrategy(StrategyRefusal)
+	if got := synthesizer.StrategyFor("proj_other"); got != StrategyRefusal {
+		t.Errorf("StrategyFor(proj_other) = %q after SetDefaultStrategy, want refusal", got)
+	}
+	synthesizer.SetProjectStrategy("proj_other", StrategyCode)
+	if got := synthesizer.StrategyFor("proj_other"); got != StrategyCode {
+		t.Errorf("StrategyFor(proj_other) = %q after SetProjectStrategy, want code", got)
+	}
+}
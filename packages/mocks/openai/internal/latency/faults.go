@@ -0,0 +1,130 @@
+// Package latency provides latency simulation.
+// This file implements injected latency faults for scripted degradation.
+package latency
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyFault raises latency for a model (or family of models) on top of
+// normal simulation. Faults bypass the profile's MaxLatency so a degraded
+// model can be slower than it ever is in steady state.
+type LatencyFault struct {
+	// Model is the model ID, a prefix ending in "*" (e.g., "gpt-4*"), or "" for all models
+	Model string
+
+	// Added is a fixed delay added to each request (to the first chunk when streaming)
+	Added time.Duration
+
+	// Multiplier scales the simulated latency (0 = unchanged)
+	Multiplier float64
+
+	// ExpiresAt is when the fault is lifted automatically (zero = never)
+	ExpiresAt time.Time
+}
+
+// Validate checks that the fault is well-formed.
+func (f LatencyFault) Validate() error {
+	if f.Added < 0 {
+		return fmt.Errorf("added latency cannot be negative")
+	}
+	if f.Multiplier < 0 {
+		return fmt.Errorf("multiplier cannot be negative")
+	}
+	if f.Added == 0 && f.Multiplier == 0 {
+		return fmt.Errorf("either added latency or a multiplier is required")
+	}
+	return nil
+}
+
+// matches returns true if the fault applies to the model.
+func (f LatencyFault) matches(model string) bool {
+	if f.Model == "" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(f.Model, "*"); ok {
+		return strings.HasPrefix(model, prefix)
+	}
+	return f.Model == model
+}
+
+// expired returns true if the fault has lapsed.
+func (f LatencyFault) expired(now time.Time) bool {
+	return !f.ExpiresAt.IsZero() && !now.Before(f.ExpiresAt)
+}
+
+// latencyFaults holds the active faults.
+type latencyFaults struct {
+	// faults are keyed by model pattern
+	faults map[string]LatencyFault
+
+	// mu protects faults
+	mu sync.RWMutex
+}
+
+// InjectLatency adds a latency fault, replacing any fault for the same model pattern.
+func (s *Simulator) InjectLatency(fault LatencyFault) error {
+	if err := fault.Validate(); err != nil {
+		return err
+	}
+
+	s.faults.mu.Lock()
+	defer s.faults.mu.Unlock()
+
+	if s.faults.faults == nil {
+		s.faults.faults = make(map[string]LatencyFault)
+	}
+	s.faults.faults[fault.Model] = fault
+
+	return nil
+}
+
+// ClearLatencyFaults removes all latency faults.
+func (s *Simulator) ClearLatencyFaults() {
+	s.faults.mu.Lock()
+	defer s.faults.mu.Unlock()
+
+	s.faults.faults = nil
+}
+
+// GetLatencyFaults returns the active (unexpired) latency faults.
+func (s *Simulator) GetLatencyFaults() []LatencyFault {
+	now := time.Now()
+
+	s.faults.mu.RLock()
+	defer s.faults.mu.RUnlock()
+
+	faults := make([]LatencyFault, 0, len(s.faults.faults))
+	for _, fault := range s.faults.faults {
+		if !fault.expired(now) {
+			faults = append(faults, fault)
+		}
+	}
+
+	return faults
+}
+
+// latencyFaultFor combines every active fault matching the model into one
+// multiplier and added delay. Multipliers compound; added delays sum.
+func (s *Simulator) latencyFaultFor(modelID string) (multiplier float64, added time.Duration) {
+	now := time.Now()
+	multiplier = 1.0
+
+	s.faults.mu.RLock()
+	defer s.faults.mu.RUnlock()
+
+	for _, fault := range s.faults.faults {
+		if fault.expired(now) || !fault.matches(modelID) {
+			continue
+		}
+		if fault.Multiplier > 0 {
+			multiplier *= fault.Multiplier
+		}
+		added += fault.Added
+	}
+
+	return multiplier, added
+}
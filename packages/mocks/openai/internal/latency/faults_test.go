@@ -0,0 +1,147 @@
+package latency
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyFaultValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   LatencyFault
+		wantErr bool
+	}{
+		{name: "added", fault: LatencyFault{Added: time.Second}},
+		{name: "multiplier", fault: LatencyFault{Multiplier: 2}},
+		{name: "both", fault: LatencyFault{Added: time.Second, Multiplier: 2}},
+		{name: "neither", fault: LatencyFault{Model: "gpt-4o"}, wantErr: true},
+		{name: "negative added", fault: LatencyFault{Added: -time.Second}, wantErr: true},
+		{name: "negative multiplier", fault: LatencyFault{Multiplier: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fault.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLatencyFaultMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		model   string
+		want    bool
+	}{
+		{pattern: "", model: "gpt-4o", want: true},
+		{pattern: "gpt-4o", model: "gpt-4o", want: true},
+		{pattern: "gpt-4o", model: "gpt-4o-mini", want: false},
+		{pattern: "gpt-4*", model: "gpt-4o-mini", want: true},
+		{pattern: "gpt-4*", model: "gpt-3.5-turbo", want: false},
+		{pattern: "*", model: "o1", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.model, func(t *testing.T) {
+			fault := LatencyFault{Model: tt.pattern}
+			if got := fault.matches(tt.model); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatencyFaultFor(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name           string
+		faults         []LatencyFault
+		model          string
+		wantMultiplier float64
+		wantAdded      time.Duration
+	}{
+		{
+			name:           "no faults",
+			model:          "gpt-4o",
+			wantMultiplier: 1,
+		},
+		{
+			name: "multipliers compound and delays sum",
+			faults: []LatencyFault{
+				{Multiplier: 2, Added: time.Second},
+				{Model: "gpt-4*", Multiplier: 3, Added: 2 * time.Second},
+			},
+			model:          "gpt-4o",
+			wantMultiplier: 6,
+			wantAdded:      3 * time.Second,
+		},
+		{
+			name: "other models are unaffected",
+			faults: []LatencyFault{
+				{Model: "gpt-4*", Multiplier: 3},
+			},
+			model:          "o1",
+			wantMultiplier: 1,
+		},
+		{
+			name: "expired faults are ignored",
+			faults: []LatencyFault{
+				{Multiplier: 2, ExpiresAt: expired},
+				{Model: "gpt-4o", Added: time.Second},
+			},
+			model:          "gpt-4o",
+			wantMultiplier: 1,
+			wantAdded:      time.Second,
+		},
+		{
+			name: "same pattern replaces the fault",
+			faults: []LatencyFault{
+				{Model: "gpt-4o", Multiplier: 2},
+				{Model: "gpt-4o", Multiplier: 5},
+			},
+			model:          "gpt-4o",
+			wantMultiplier: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSimulator(DefaultSimulatorConfig())
+			for _, fault := range tt.faults {
+				if err := s.InjectLatency(fault); err != nil {
+					t.Fatalf("InjectLatency() error = %v", err)
+				}
+			}
+
+			multiplier, added := s.latencyFaultFor(tt.model)
+			if multiplier != tt.wantMultiplier || added != tt.wantAdded {
+				t.Errorf("latencyFaultFor(%q) = (%v, %v), want (%v, %v)", tt.model, multiplier, added, tt.wantMultiplier, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestGetLatencyFaults(t *testing.T) {
+	s := NewSimulator(DefaultSimulatorConfig())
+	faults := []LatencyFault{
+		{Model: "gpt-4o", Multiplier: 2},
+		{Model: "o1", Multiplier: 2, ExpiresAt: time.Now().Add(-time.Minute)},
+	}
+	for _, fault := range faults {
+		if err := s.InjectLatency(fault); err != nil {
+			t.Fatalf("InjectLatency() error = %v", err)
+		}
+	}
+
+	active := s.GetLatencyFaults()
+	if len(active) != 1 || active[0].Model != "gpt-4o" {
+		t.Errorf("GetLatencyFaults() = %+v, want only the gpt-4o fault", active)
+	}
+
+	s.ClearLatencyFaults()
+	if active := s.GetLatencyFaults(); len(active) != 0 {
+		t.Errorf("GetLatencyFaults() after clear = %+v, want none", active)
+	}
+}
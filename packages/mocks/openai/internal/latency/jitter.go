@@ -3,6 +3,8 @@
 package latency
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
 	"time"
 )
@@ -31,8 +33,25 @@ const (
 
 	// ExponentialJitter applies exponential distribution (favor lower variance)
 	ExponentialJitter JitterDistribution = "exponential"
+
+	// LongTailJitter applies log-normal jitter: most requests land near the
+	// base latency, a few are much slower, as with real APIs. Use it when
+	// asserting on p95/p99 latency
+	LongTailJitter JitterDistribution = "longtail"
 )
 
+// ParseJitterDistribution parses a jitter distribution ("" = uniform).
+func ParseJitterDistribution(s string) (JitterDistribution, error) {
+	switch distribution := JitterDistribution(s); distribution {
+	case "":
+		return UniformJitter, nil
+	case UniformJitter, GaussianJitter, ExponentialJitter, LongTailJitter:
+		return distribution, nil
+	default:
+		return "", fmt.Errorf("unknown jitter distribution %q (expected uniform, gaussian, exponential or longtail)", s)
+	}
+}
+
 // NewJitterCalculator creates a new jitter calculator.
 func NewJitterCalculator(enabled bool, distribution JitterDistribution) *JitterCalculator {
 	if distribution == "" {
@@ -48,22 +67,35 @@ func NewJitterCalculator(enabled bool, distribution JitterDistribution) *JitterC
 // ApplyJitter applies jitter to a base latency.
 // jitterPercent is the maximum deviation as a percentage (e.g., 0.25 = ±25%).
 func (j *JitterCalculator) ApplyJitter(baseLatency time.Duration, jitterPercent float64) time.Duration {
+	return j.ApplyJitterWith(nil, baseLatency, jitterPercent)
+}
+
+// ApplyJitterWith is ApplyJitter drawing from rng, so a seeded request gets
+// the same jitter every time. A nil rng uses the global source.
+func (j *JitterCalculator) ApplyJitterWith(rng *rand.Rand, baseLatency time.Duration, jitterPercent float64) time.Duration {
 	if !j.enabled || jitterPercent == 0 {
 		return baseLatency
 	}
 
+	random := rand.Float64
+	if rng != nil {
+		random = rng.Float64
+	}
+
 	// Calculate jitter amount based on distribution
 	var jitterRatio float64
 
 	switch j.distribution {
 	case UniformJitter:
-		jitterRatio = j.uniformJitter(jitterPercent)
+		jitterRatio = j.uniformJitter(random, jitterPercent)
 	case GaussianJitter:
-		jitterRatio = j.gaussianJitter(jitterPercent)
+		jitterRatio = j.gaussianJitter(random, jitterPercent)
 	case ExponentialJitter:
-		jitterRatio = j.exponentialJitter(jitterPercent)
+		jitterRatio = j.exponentialJitter(random, jitterPercent)
+	case LongTailJitter:
+		jitterRatio = j.longTailJitter(random, jitterPercent)
 	default:
-		jitterRatio = j.uniformJitter(jitterPercent)
+		jitterRatio = j.uniformJitter(random, jitterPercent)
 	}
 
 	// Apply jitter
@@ -79,18 +111,18 @@ func (j *JitterCalculator) ApplyJitter(baseLatency time.Duration, jitterPercent
 }
 
 // uniformJitter generates uniform random jitter in range [-percent, +percent].
-func (j *JitterCalculator) uniformJitter(percent float64) float64 {
+func (j *JitterCalculator) uniformJitter(random func() float64, percent float64) float64 {
 	// Generate random value between -1 and +1
-	r := rand.Float64()*2 - 1
+	r := random()*2 - 1
 	return r * percent
 }
 
 // gaussianJitter generates Gaussian-distributed jitter.
 // This creates a bell curve where most values are near the center (zero jitter).
-func (j *JitterCalculator) gaussianJitter(percent float64) float64 {
+func (j *JitterCalculator) gaussianJitter(random func() float64, percent float64) float64 {
 	// Box-Muller transform for Gaussian distribution
-	u1 := rand.Float64()
-	u2 := rand.Float64()
+	u1 := random()
+	u2 := random()
 
 	// Generate standard normal (mean=0, stddev=1)
 	z := gaussianRandom(u1, u2)
@@ -111,9 +143,9 @@ func (j *JitterCalculator) gaussianJitter(percent float64) float64 {
 
 // exponentialJitter generates exponentially-distributed jitter.
 // This favors smaller deviations, creating more realistic network variance.
-func (j *JitterCalculator) exponentialJitter(percent float64) float64 {
+func (j *JitterCalculator) exponentialJitter(random func() float64, percent float64) float64 {
 	// Generate exponential random variable
-	u := rand.Float64()
+	u := random()
 	if u == 0 {
 		u = 0.0001 // Avoid log(0)
 	}
@@ -123,7 +155,7 @@ func (j *JitterCalculator) exponentialJitter(percent float64) float64 {
 	exp := -1.0 / lambda * (1.0 - u)
 
 	// Randomly make it positive or negative
-	if rand.Float64() < 0.5 {
+	if random() < 0.5 {
 		exp = -exp
 	}
 
@@ -140,27 +172,30 @@ func (j *JitterCalculator) exponentialJitter(percent float64) float64 {
 	return jitter
 }
 
-// gaussianRandom generates a Gaussian random variable using Box-Muller transform.
-func gaussianRandom(u1, u2 float64) float64 {
-	// Box-Muller transform
-	// z = sqrt(-2 * ln(u1)) * cos(2π * u2)
-	z := (-2.0 * logFloat(u1)) * cosFloat(2.0*3.14159265359*u2)
-	return z
-}
+// longTailJitter generates log-normal jitter with the median at zero.
+// percent is the standard deviation of the log, so about 84% of requests
+// are at most percent slower; the slowest are clamped to 4×percent slower,
+// the fastest to percent faster.
+func (j *JitterCalculator) longTailJitter(random func() float64, percent float64) float64 {
+	z := gaussianRandom(random(), random())
+	jitter := math.Exp(z*percent) - 1
 
-// logFloat computes natural logarithm safely.
-func logFloat(x float64) float64 {
-	if x <= 0 {
-		return -10 // Safe minimum
+	if jitter > 4*percent {
+		jitter = 4 * percent
+	} else if jitter < -percent {
+		jitter = -percent
 	}
-	// Simple approximation (use math.Log in production)
-	return 0 // Placeholder - actual implementation would use math.Log
+
+	return jitter
 }
 
-// cosFloat computes cosine safely.
-func cosFloat(x float64) float64 {
-	// Simple approximation (use math.Cos in production)
-	return 1.0 // Placeholder - actual implementation would use math.Cos
+// gaussianRandom generates a Gaussian random variable using Box-Muller transform.
+func gaussianRandom(u1, u2 float64) float64 {
+	if u1 <= 0 {
+		u1 = math.SmallestNonzeroFloat64
+	}
+	// z = sqrt(-2 * ln(u1)) * cos(2π * u2)
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
 }
 
 // CalculateJitterStats calculates statistics about applied jitter.
@@ -245,7 +280,13 @@ func (j *JitterCalculator) GetDistribution() JitterDistribution {
 
 // ApplyJitterRange applies jitter ensuring the result stays within a range.
 func (j *JitterCalculator) ApplyJitterRange(baseLatency time.Duration, jitterPercent float64, minLatency, maxLatency time.Duration) time.Duration {
-	jittered := j.ApplyJitter(baseLatency, jitterPercent)
+	return j.ApplyJitterRangeWith(nil, baseLatency, jitterPercent, minLatency, maxLatency)
+}
+
+// ApplyJitterRangeWith is ApplyJitterRange drawing from rng (nil: the
+// global source).
+func (j *JitterCalculator) ApplyJitterRangeWith(rng *rand.Rand, baseLatency time.Duration, jitterPercent float64, minLatency, maxLatency time.Duration) time.Duration {
+	jittered := j.ApplyJitterWith(rng, baseLatency, jitterPercent)
 
 	// Clamp to range
 	if jittered < minLatency {
@@ -0,0 +1,91 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestParseJitterDistribution(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    JitterDistribution
+		wantErr bool
+	}{
+		{input: "", want: UniformJitter},
+		{input: "uniform", want: UniformJitter},
+		{input: "gaussian", want: GaussianJitter},
+		{input: "exponential", want: ExponentialJitter},
+		{input: "longtail", want: LongTailJitter},
+		{input: "pareto", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseJitterDistribution(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJitterDistribution(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseJitterDistribution(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyJitterWith(t *testing.T) {
+	const base = 100 * time.Millisecond
+
+	distributions := []JitterDistribution{UniformJitter, GaussianJitter, ExponentialJitter, LongTailJitter}
+	for _, distribution := range distributions {
+		t.Run(string(distribution), func(t *testing.T) {
+			j := NewJitterCalculator(true, distribution)
+
+			for seed := 0; seed < 20; seed++ {
+				first := j.ApplyJitterWith(models.NewSeededRand(seed), base, 0.25)
+				second := j.ApplyJitterWith(models.NewSeededRand(seed), base, 0.25)
+				if first != second {
+					t.Errorf("seed %d gave %v and %v, want equal latencies", seed, first, second)
+				}
+				if first < 0 {
+					t.Errorf("seed %d gave negative latency %v", seed, first)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyJitterWithUniformBounds(t *testing.T) {
+	const base = 100 * time.Millisecond
+
+	j := NewJitterCalculator(true, UniformJitter)
+	low, high := j.PredictJitterRange(base, 0.25)
+	for seed := 0; seed < 100; seed++ {
+		if got := j.ApplyJitterWith(models.NewSeededRand(seed), base, 0.25); got < low || got > high {
+			t.Errorf("seed %d gave %v, want within [%v, %v]", seed, got, low, high)
+		}
+	}
+}
+
+func TestApplyJitterWithDisabled(t *testing.T) {
+	const base = 100 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		enabled bool
+		percent float64
+	}{
+		{name: "disabled", enabled: false, percent: 0.25},
+		{name: "zero percent", enabled: true, percent: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := NewJitterCalculator(tt.enabled, LongTailJitter)
+			if got := j.ApplyJitterWith(models.NewSeededRand(1), base, tt.percent); got != base {
+				t.Errorf("ApplyJitterWith() = %v, want %v", got, base)
+			}
+		})
+	}
+}
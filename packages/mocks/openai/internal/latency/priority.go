@@ -0,0 +1,46 @@
+// Package latency provides latency simulation.
+// This file implements per-service-tier latency multipliers.
+package latency
+
+import (
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// DefaultTierMultipliers returns latency multipliers per service tier.
+// Priority traffic is served faster than default traffic and flex traffic
+// is served markedly slower, matching OpenAI's published tier behavior.
+func DefaultTierMultipliers() map[models.ServiceTier]float64 {
+	return map[models.ServiceTier]float64{
+		models.ServiceTierPriority: 0.6,
+		models.ServiceTierScale:    0.8,
+		models.ServiceTierDefault:  1.0,
+		models.ServiceTierFlex:     2.5,
+	}
+}
+
+// SetTierMultiplier sets the latency multiplier for a service tier.
+func (s *Simulator) SetTierMultiplier(tier models.ServiceTier, multiplier float64) {
+	if multiplier <= 0 {
+		multiplier = 1.0
+	}
+
+	// Copy-on-write so readers never lock
+	current := s.tierMultipliers.Load().(map[models.ServiceTier]float64)
+	updated := make(map[models.ServiceTier]float64, len(current)+1)
+	for t, m := range current {
+		updated[t] = m
+	}
+	updated[tier.Resolve()] = multiplier
+
+	s.tierMultipliers.Store(updated)
+}
+
+// GetTierMultiplier returns the latency multiplier for a service tier.
+// Tiers without a configured multiplier return 1.0.
+func (s *Simulator) GetTierMultiplier(tier models.ServiceTier) float64 {
+	multipliers := s.tierMultipliers.Load().(map[models.ServiceTier]float64)
+	if multiplier, ok := multipliers[tier.Resolve()]; ok {
+		return multiplier
+	}
+	return 1.0
+}
@@ -0,0 +1,46 @@
+package latency
+
+import (
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestTierMultiplier(t *testing.T) {
+	tests := []struct {
+		name string
+		set  map[models.ServiceTier]float64
+		tier models.ServiceTier
+		want float64
+	}{
+		{name: "priority default", tier: models.ServiceTierPriority, want: 0.6},
+		{name: "flex default", tier: models.ServiceTierFlex, want: 2.5},
+		{name: "auto resolves to default", tier: models.ServiceTierAuto, want: 1.0},
+		{name: "unknown tier", tier: "batch", want: 1.0},
+		{
+			name: "override",
+			set:  map[models.ServiceTier]float64{models.ServiceTierFlex: 4},
+			tier: models.ServiceTierFlex,
+			want: 4,
+		},
+		{
+			name: "non-positive resets to 1",
+			set:  map[models.ServiceTier]float64{models.ServiceTierPriority: 0},
+			tier: models.ServiceTierPriority,
+			want: 1.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSimulator(DefaultSimulatorConfig())
+			for tier, multiplier := range tt.set {
+				s.SetTierMultiplier(tier, multiplier)
+			}
+
+			if got := s.GetTierMultiplier(tt.tier); got != tt.want {
+				t.Errorf("GetTierMultiplier(%q) = %v, want %v", tt.tier, got, tt.want)
+			}
+		})
+	}
+}
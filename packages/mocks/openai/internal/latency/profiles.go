@@ -191,6 +191,18 @@ func (r *ProfileRegistry) loadDefaultProfiles() {
 		P99Latency:      40 * time.Second,
 	}
 
+	r.profiles["gpt-image-1"] = Profile{
+		ModelID:         "gpt-image-1",
+		BaseLatency:     25 * time.Second,
+		PerTokenLatency: 0,
+		JitterPercent:   0.25,
+		MinLatency:      10 * time.Second,
+		MaxLatency:      90 * time.Second,
+		P50Latency:      30 * time.Second,
+		P95Latency:      60 * time.Second,
+		P99Latency:      80 * time.Second,
+	}
+
 	r.profiles["dall-e-2"] = Profile{
 		ModelID:         "dall-e-2",
 		BaseLatency:     10 * time.Second,
@@ -0,0 +1,309 @@
+// Package latency provides latency simulation.
+// This file records the latencies the simulator produces and checks their
+// percentiles against the profiles' documented P50/P95/P99 targets.
+package latency
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Latency report defaults.
+const (
+	// maxObservationsPerModel bounds the samples kept per model; the
+	// oldest are overwritten first
+	maxObservationsPerModel = 10000
+
+	// referenceTokens is the output size profile percentiles are given for
+	referenceTokens = 100
+
+	// minCheckSamples is the fewest samples a percentile check is made on
+	// (P99 needs at least 100 to be meaningful)
+	minCheckSamples = 100
+
+	// DefaultCheckTolerance is the relative deviation from a target that
+	// still passes (0.25 = within ±25%)
+	DefaultCheckTolerance = 0.25
+
+	// DefaultVerifySamples is the number of latencies VerifyProfile draws
+	DefaultVerifySamples = 10000
+)
+
+// CheckStatus is the outcome of a percentile check.
+type CheckStatus string
+
+const (
+	// CheckPass means every percentile is within tolerance of its target
+	CheckPass CheckStatus = "pass"
+
+	// CheckFail means at least one percentile is outside tolerance
+	CheckFail CheckStatus = "fail"
+
+	// CheckInsufficientSamples means too few samples were observed to check
+	CheckInsufficientSamples CheckStatus = "insufficient_samples"
+)
+
+// Percentiles summarizes a latency distribution.
+type Percentiles struct {
+	P50  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Mean time.Duration
+}
+
+// PercentileCheck compares one observed percentile with its target.
+type PercentileCheck struct {
+	// Percentile is 50, 95 or 99
+	Percentile int
+
+	// Observed and Target are the observed and documented latencies
+	Observed time.Duration
+	Target   time.Duration
+
+	// Deviation is (Observed - Target) / Target
+	Deviation float64
+
+	// Passed is true if |Deviation| <= tolerance
+	Passed bool
+}
+
+// ProfileCheck is the result of checking a model's latencies against its profile.
+type ProfileCheck struct {
+	// Model is the model checked
+	Model string
+
+	// Samples is the number of latencies the check was made on
+	Samples int
+
+	// Tolerance is the relative deviation allowed
+	Tolerance float64
+
+	// Status is the overall outcome
+	Status CheckStatus
+
+	// Percentiles are the per-percentile comparisons (empty if Status is
+	// CheckInsufficientSamples)
+	Percentiles []PercentileCheck
+}
+
+// ModelLatencyReport reports the latencies simulated for a model.
+type ModelLatencyReport struct {
+	// Model is the model ID
+	Model string
+
+	// Requests is the number of simulations recorded (including samples
+	// overwritten after maxObservationsPerModel)
+	Requests int64
+
+	// Observed is the distribution of the latencies actually applied,
+	// including load, service tier and injected faults
+	Observed Percentiles
+
+	// Normalized is the profile-driven latency alone (jitter and bounds,
+	// without load, tier or faults) scaled to referenceTokens output
+	// tokens, which is what the profile targets describe
+	Normalized Percentiles
+
+	// Check compares Normalized with the profile's targets
+	Check ProfileCheck
+}
+
+// latencySample is one recorded simulation.
+type latencySample struct {
+	observed   time.Duration
+	normalized time.Duration
+}
+
+// modelObservations is a ring of samples for one model.
+type modelObservations struct {
+	samples  []latencySample
+	next     int
+	requests int64
+}
+
+// latencyObservations records samples per model.
+type latencyObservations struct {
+	mu      sync.Mutex
+	byModel map[string]*modelObservations
+}
+
+// record adds a sample for a model.
+func (o *latencyObservations) record(modelID string, sample latencySample) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.byModel == nil {
+		o.byModel = make(map[string]*modelObservations)
+	}
+	obs, ok := o.byModel[modelID]
+	if !ok {
+		obs = &modelObservations{}
+		o.byModel[modelID] = obs
+	}
+
+	obs.requests++
+	if len(obs.samples) < maxObservationsPerModel {
+		obs.samples = append(obs.samples, sample)
+		return
+	}
+	obs.samples[obs.next] = sample
+	obs.next = (obs.next + 1) % maxObservationsPerModel
+}
+
+// recordSimulation records a simulation of outputTokens tokens: observed is
+// the latency applied, baseline the profile-driven part of it.
+func (s *Simulator) recordSimulation(profile Profile, outputTokens int, observed, baseline time.Duration) {
+	normalized := baseline
+	if estimate := profile.EstimateLatency(outputTokens); estimate > 0 {
+		normalized = time.Duration(float64(baseline) * float64(profile.EstimateLatency(referenceTokens)) / float64(estimate))
+	}
+
+	s.observations.record(profile.ModelID, latencySample{observed: observed, normalized: normalized})
+}
+
+// LatencyReport returns the percentiles of the latencies simulated per
+// model since the last ResetObservations, checked against each profile
+// with the given tolerance (0 = DefaultCheckTolerance). Sorted by model.
+func (s *Simulator) LatencyReport(tolerance float64) []ModelLatencyReport {
+	s.observations.mu.Lock()
+	snapshot := make(map[string]modelObservations, len(s.observations.byModel))
+	for modelID, obs := range s.observations.byModel {
+		snapshot[modelID] = modelObservations{
+			samples:  slices.Clone(obs.samples),
+			requests: obs.requests,
+		}
+	}
+	s.observations.mu.Unlock()
+
+	reports := make([]ModelLatencyReport, 0, len(snapshot))
+	for modelID, obs := range snapshot {
+		observed := make([]time.Duration, len(obs.samples))
+		normalized := make([]time.Duration, len(obs.samples))
+		for i, sample := range obs.samples {
+			observed[i] = sample.observed
+			normalized[i] = sample.normalized
+		}
+
+		report := ModelLatencyReport{
+			Model:      modelID,
+			Requests:   obs.requests,
+			Observed:   computePercentiles(observed),
+			Normalized: computePercentiles(normalized),
+		}
+		if profile, err := s.registry.GetProfile(modelID); err == nil {
+			report.Check = checkProfile(modelID, profile, normalized, tolerance)
+		}
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Model < reports[j].Model
+	})
+	return reports
+}
+
+// ResetObservations discards recorded latencies (e.g., at the start of a run).
+func (s *Simulator) ResetObservations() {
+	s.observations.mu.Lock()
+	defer s.observations.mu.Unlock()
+	s.observations.byModel = nil
+}
+
+// VerifyProfile draws samples latencies for referenceTokens output tokens
+// from a model's profile, without sleeping or recording them, and checks
+// their percentiles against the profile's targets. samples <= 0 uses
+// DefaultVerifySamples; tolerance <= 0 uses DefaultCheckTolerance.
+func (s *Simulator) VerifyProfile(modelID string, samples int, tolerance float64) (ProfileCheck, error) {
+	profile, err := s.registry.GetProfile(modelID)
+	if err != nil {
+		return ProfileCheck{}, fmt.Errorf("failed to get latency profile: %w", err)
+	}
+
+	if samples <= 0 {
+		samples = DefaultVerifySamples
+	}
+
+	latencies := make([]time.Duration, samples)
+	for i := range latencies {
+		latencies[i] = s.jitter.ApplyJitterRange(profile.EstimateLatency(referenceTokens), profile.JitterPercent, profile.MinLatency, profile.MaxLatency)
+	}
+
+	return checkProfile(modelID, profile, latencies, tolerance), nil
+}
+
+// checkProfile compares a model's latencies with its profile's P50/P95/P99
+// targets. The check reports modelID: profiles shared by several models
+// carry a generic ModelID.
+func checkProfile(modelID string, profile Profile, latencies []time.Duration, tolerance float64) ProfileCheck {
+	if tolerance <= 0 {
+		tolerance = DefaultCheckTolerance
+	}
+
+	check := ProfileCheck{
+		Model:     modelID,
+		Samples:   len(latencies),
+		Tolerance: tolerance,
+		Status:    CheckPass,
+	}
+	if len(latencies) < minCheckSamples {
+		check.Status = CheckInsufficientSamples
+		return check
+	}
+
+	observed := computePercentiles(latencies)
+	for _, p := range []struct {
+		percentile       int
+		observed, target time.Duration
+	}{
+		{50, observed.P50, profile.P50Latency},
+		{95, observed.P95, profile.P95Latency},
+		{99, observed.P99, profile.P99Latency},
+	} {
+		result := PercentileCheck{
+			Percentile: p.percentile,
+			Observed:   p.observed,
+			Target:     p.target,
+		}
+		if p.target > 0 {
+			result.Deviation = float64(p.observed-p.target) / float64(p.target)
+		}
+		result.Passed = math.Abs(result.Deviation) <= tolerance
+		if !result.Passed {
+			check.Status = CheckFail
+		}
+		check.Percentiles = append(check.Percentiles, result)
+	}
+
+	return check
+}
+
+// computePercentiles returns nearest-rank percentiles and the mean.
+func computePercentiles(latencies []time.Duration) Percentiles {
+	if len(latencies) == 0 {
+		return Percentiles{}
+	}
+
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+
+	rank := func(percentile float64) time.Duration {
+		i := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)]
+	}
+
+	return Percentiles{
+		P50:  rank(50),
+		P95:  rank(95),
+		P99:  rank(99),
+		Mean: total / time.Duration(len(sorted)),
+	}
+}
@@ -0,0 +1,195 @@
+package latency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// millis returns 1ms, 2ms, ..., n ms.
+func millis(n int) []time.Duration {
+	latencies := make([]time.Duration, n)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	return latencies
+}
+
+func TestComputePercentiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		want      Percentiles
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:      "single",
+			latencies: []time.Duration{5 * time.Millisecond},
+			want: Percentiles{
+				P50:  5 * time.Millisecond,
+				P95:  5 * time.Millisecond,
+				P99:  5 * time.Millisecond,
+				Mean: 5 * time.Millisecond,
+			},
+		},
+		{
+			name:      "nearest rank",
+			latencies: millis(100),
+			want: Percentiles{
+				P50:  50 * time.Millisecond,
+				P95:  95 * time.Millisecond,
+				P99:  99 * time.Millisecond,
+				Mean: 50500 * time.Microsecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computePercentiles(tt.latencies); got != tt.want {
+				t.Errorf("computePercentiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckProfile(t *testing.T) {
+	profile := Profile{
+		ModelID:    testModel,
+		P50Latency: 50 * time.Millisecond,
+		P95Latency: 95 * time.Millisecond,
+		P99Latency: 99 * time.Millisecond,
+	}
+	slow := profile
+	slow.P99Latency = 60 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		profile    Profile
+		latencies  []time.Duration
+		tolerance  float64
+		wantStatus CheckStatus
+		wantFailed []int
+	}{
+		{
+			name:       "pass",
+			profile:    profile,
+			latencies:  millis(100),
+			wantStatus: CheckPass,
+		},
+		{
+			name:       "fail",
+			profile:    slow,
+			latencies:  millis(100),
+			wantStatus: CheckFail,
+			wantFailed: []int{99},
+		},
+		{
+			name:       "within a wider tolerance",
+			profile:    slow,
+			latencies:  millis(100),
+			tolerance:  0.7,
+			wantStatus: CheckPass,
+		},
+		{
+			name:       "insufficient samples",
+			profile:    profile,
+			latencies:  millis(50),
+			wantStatus: CheckInsufficientSamples,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := checkProfile(tt.profile.ModelID, tt.profile, tt.latencies, tt.tolerance)
+			if check.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", check.Status, tt.wantStatus)
+			}
+
+			var failed []int
+			for _, p := range check.Percentiles {
+				if !p.Passed {
+					failed = append(failed, p.Percentile)
+				}
+			}
+			if len(failed) != len(tt.wantFailed) || (len(failed) > 0 && failed[0] != tt.wantFailed[0]) {
+				t.Errorf("failed percentiles = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestLatencyReport(t *testing.T) {
+	s := newTestSimulator(t, testProfile)
+	for i := 0; i < minCheckSamples; i++ {
+		if _, err := s.SimulateForTier(context.Background(), testModel, 10, models.ServiceTierFlex); err != nil {
+			t.Fatalf("SimulateForTier() error = %v", err)
+		}
+	}
+
+	reports := s.LatencyReport(0)
+	if len(reports) != 1 {
+		t.Fatalf("LatencyReport() returned %d reports, want 1", len(reports))
+	}
+
+	report := reports[0]
+	if report.Model != testModel || report.Requests != minCheckSamples {
+		t.Errorf("report = %s with %d requests, want %s with %d", report.Model, report.Requests, testModel, minCheckSamples)
+	}
+	// The flex tier is capped at the profile's max latency
+	if report.Observed.P50 != 40*time.Millisecond {
+		t.Errorf("Observed.P50 = %v, want 40ms", report.Observed.P50)
+	}
+	// Normalized to 100 output tokens, without the tier: 20ms * 110/20
+	if report.Normalized.P50 != 110*time.Millisecond {
+		t.Errorf("Normalized.P50 = %v, want 110ms", report.Normalized.P50)
+	}
+	if report.Check.Samples != minCheckSamples || report.Check.Tolerance != DefaultCheckTolerance {
+		t.Errorf("Check = %d samples at %v, want %d at %v", report.Check.Samples, report.Check.Tolerance, minCheckSamples, DefaultCheckTolerance)
+	}
+
+	s.ResetObservations()
+	if reports := s.LatencyReport(0); len(reports) != 0 {
+		t.Errorf("LatencyReport() after reset = %d reports, want none", len(reports))
+	}
+}
+
+func TestVerifyProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      string
+		samples    int
+		wantStatus CheckStatus
+		wantErr    bool
+	}{
+		{name: "matching targets", model: testModel, samples: 200, wantStatus: CheckPass},
+		{name: "too few samples", model: testModel, samples: 10, wantStatus: CheckInsufficientSamples},
+		{name: "unknown model", model: "no-such-model", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Without jitter every sample is the 100-token estimate, 110ms
+			s := newTestSimulator(t, Profile{
+				BaseLatency:     10 * time.Millisecond,
+				PerTokenLatency: time.Millisecond,
+				MaxLatency:      time.Second,
+				P50Latency:      110 * time.Millisecond,
+				P95Latency:      110 * time.Millisecond,
+				P99Latency:      110 * time.Millisecond,
+			})
+
+			check, err := s.VerifyProfile(tt.model, tt.samples, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if check.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", check.Status, tt.wantStatus)
+			}
+		})
+	}
+}
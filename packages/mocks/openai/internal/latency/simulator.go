@@ -5,10 +5,12 @@ package latency
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync/atomic"
 	"time"
 
 	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
 )
 
 // Simulator simulates production-realistic API latencies.
@@ -28,6 +30,18 @@ type Simulator struct {
 	// peakHours defines hours (UTC) when load is simulated as high
 	peakHours map[int]bool
 
+	// tierMultipliers scales latency per service tier
+	tierMultipliers atomic.Value // map[models.ServiceTier]float64
+
+	// nonStreamingTiming selects how non-streaming latency is applied
+	nonStreamingTiming atomic.Value // NonStreamingTiming
+
+	// faults are injected latency faults
+	faults latencyFaults
+
+	// observations are the simulated latencies per model (see LatencyReport)
+	observations latencyObservations
+
 	// stats tracks simulation statistics
 	totalSimulations atomic.Int64
 	totalDelay       atomic.Int64 // in milliseconds
@@ -52,6 +66,13 @@ type SimulatorConfig struct {
 
 	// PeakHours are the hours (UTC, 0-23) considered peak load
 	PeakHours []int
+
+	// TierMultipliers scales latency per service tier (e.g., 0.6 = 40% faster)
+	TierMultipliers map[models.ServiceTier]float64
+
+	// NonStreamingTiming selects whether non-streaming requests sleep the
+	// TTFT and generation time separately (split) or all at once (total)
+	NonStreamingTiming NonStreamingTiming
 }
 
 // DefaultSimulatorConfig returns default configuration.
@@ -63,6 +84,8 @@ func DefaultSimulatorConfig() SimulatorConfig {
 		EnableLoadSimulation: true,
 		LoadMultiplier:       1.3, // +30% during peak
 		PeakHours:            []int{9, 10, 11, 12, 13, 14, 15, 16, 17}, // 9 AM - 5 PM UTC
+		TierMultipliers:      DefaultTierMultipliers(),
+		NonStreamingTiming:   TimingSplit,
 	}
 }
 
@@ -76,6 +99,11 @@ func NewSimulator(config SimulatorConfig) *Simulator {
 
 	s.enabled.Store(config.Enabled)
 	s.loadMultiplier.Store(config.LoadMultiplier)
+	s.tierMultipliers.Store(make(map[models.ServiceTier]float64))
+	s.SetNonStreamingTiming(config.NonStreamingTiming)
+	for tier, multiplier := range config.TierMultipliers {
+		s.SetTierMultiplier(tier, multiplier)
+	}
 
 	// Index peak hours for O(1) lookup
 	for _, hour := range config.PeakHours {
@@ -87,6 +115,11 @@ func NewSimulator(config SimulatorConfig) *Simulator {
 
 // Simulate calculates and applies latency simulation for a request.
 func (s *Simulator) Simulate(ctx context.Context, modelID string, outputTokens int) (time.Duration, error) {
+	return s.SimulateForTier(ctx, modelID, outputTokens, models.ServiceTierDefault)
+}
+
+// SimulateForTier calculates latency for a request in the given service tier.
+func (s *Simulator) SimulateForTier(ctx context.Context, modelID string, outputTokens int, tier models.ServiceTier) (time.Duration, error) {
 	if !s.enabled.Load() {
 		return 0, nil // No simulation
 	}
@@ -101,7 +134,10 @@ func (s *Simulator) Simulate(ctx context.Context, modelID string, outputTokens i
 	baseLatency := profile.BaseLatency + profile.PerTokenLatency*time.Duration(outputTokens)
 
 	// Apply jitter
-	jitteredLatency := s.jitter.ApplyJitter(baseLatency, profile.JitterPercent)
+	jitteredLatency := s.jitter.ApplyJitterWith(seededRand(ctx), baseLatency, profile.JitterPercent)
+
+	// The profile-driven latency, before load, tier and faults
+	baselineLatency := min(max(jitteredLatency, profile.MinLatency), profile.MaxLatency)
 
 	// Apply load multiplier if in peak hours
 	finalLatency := jitteredLatency
@@ -110,6 +146,9 @@ func (s *Simulator) Simulate(ctx context.Context, modelID string, outputTokens i
 		finalLatency = time.Duration(float64(jitteredLatency) * multiplier)
 	}
 
+	// Apply service tier multiplier
+	finalLatency = time.Duration(float64(finalLatency) * s.GetTierMultiplier(tier))
+
 	// Enforce min/max bounds
 	if finalLatency < profile.MinLatency {
 		finalLatency = profile.MinLatency
@@ -117,9 +156,14 @@ func (s *Simulator) Simulate(ctx context.Context, modelID string, outputTokens i
 		finalLatency = profile.MaxLatency
 	}
 
+	// Apply injected faults (after bounds, so degradation can exceed MaxLatency)
+	faultMultiplier, faultAdded := s.latencyFaultFor(modelID)
+	finalLatency = time.Duration(float64(finalLatency)*faultMultiplier) + faultAdded
+
 	// Record statistics
 	s.totalSimulations.Add(1)
 	s.totalDelay.Add(finalLatency.Milliseconds())
+	s.recordSimulation(profile, outputTokens, finalLatency, baselineLatency)
 
 	// Record metrics
 	metrics.RecordSimulatedLatency(modelID, finalLatency.Seconds())
@@ -127,9 +171,23 @@ func (s *Simulator) Simulate(ctx context.Context, modelID string, outputTokens i
 	return finalLatency, nil
 }
 
+// seededRand returns the random source jitter is drawn from for a request
+// with a seed (models.WithSeed), or nil for the global source.
+func seededRand(ctx context.Context) *rand.Rand {
+	if seed, ok := models.SeedFromContext(ctx); ok {
+		return models.NewSeededRand(seed)
+	}
+	return nil
+}
+
 // SimulateAndSleep calculates latency and sleeps for that duration.
 func (s *Simulator) SimulateAndSleep(ctx context.Context, modelID string, outputTokens int) error {
-	latency, err := s.Simulate(ctx, modelID, outputTokens)
+	return s.SimulateAndSleepForTier(ctx, modelID, outputTokens, models.ServiceTierDefault)
+}
+
+// SimulateAndSleepForTier calculates latency for a service tier and sleeps for that duration.
+func (s *Simulator) SimulateAndSleepForTier(ctx context.Context, modelID string, outputTokens int, tier models.ServiceTier) error {
+	latency, err := s.SimulateForTier(ctx, modelID, outputTokens, tier)
 	if err != nil {
 		return err
 	}
@@ -149,6 +207,12 @@ func (s *Simulator) SimulateAndSleep(ctx context.Context, modelID string, output
 
 // SimulateStreaming calculates per-chunk delays for streaming responses.
 func (s *Simulator) SimulateStreaming(ctx context.Context, modelID string, numChunks int) ([]time.Duration, error) {
+	return s.SimulateStreamingForTier(ctx, modelID, numChunks, models.ServiceTierDefault)
+}
+
+// SimulateStreamingForTier calculates per-chunk delays for a streaming
+// response in the given service tier. The tier multiplier applies to every chunk.
+func (s *Simulator) SimulateStreamingForTier(ctx context.Context, modelID string, numChunks int, tier models.ServiceTier) ([]time.Duration, error) {
 	if !s.enabled.Load() {
 		return make([]time.Duration, numChunks), nil
 	}
@@ -160,16 +224,21 @@ func (s *Simulator) SimulateStreaming(ctx context.Context, modelID string, numCh
 	}
 
 	delays := make([]time.Duration, numChunks)
+	rng := seededRand(ctx)
 
 	// First chunk: base latency (TTFT)
 	baseFirstChunk := profile.BaseLatency
-	delays[0] = s.jitter.ApplyJitterRange(
+	delays[0] = s.jitter.ApplyJitterRangeWith(
+		rng,
 		baseFirstChunk,
 		profile.JitterPercent,
 		profile.MinLatency,
 		profile.MaxLatency,
 	)
 
+	// The profile-driven latency, before load, tier and faults
+	baselineLatency := delays[0]
+
 	// Apply load multiplier to first chunk if in peak hours
 	if s.isPeakHour() {
 		multiplier := s.loadMultiplier.Load().(float64)
@@ -181,12 +250,33 @@ func (s *Simulator) SimulateStreaming(ctx context.Context, modelID string, numCh
 		baseChunkDelay := profile.PerTokenLatency
 
 		// Add small random jitter to each chunk (±10%)
-		delays[i] = s.jitter.ApplyJitterRange(
+		delays[i] = s.jitter.ApplyJitterRangeWith(
+			rng,
 			baseChunkDelay,
 			0.10, // Small jitter for chunks
 			baseChunkDelay/2,
 			baseChunkDelay*2,
 		)
+		baselineLatency += delays[i]
+	}
+
+	// Apply service tier multiplier
+	if tierMultiplier := s.GetTierMultiplier(tier); tierMultiplier != 1.0 {
+		for i := range delays {
+			delays[i] = time.Duration(float64(delays[i]) * tierMultiplier)
+		}
+	}
+
+	// Apply injected faults: the multiplier slows every chunk, the added
+	// delay lands on the first chunk (TTFT)
+	faultMultiplier, faultAdded := s.latencyFaultFor(modelID)
+	if faultMultiplier != 1.0 {
+		for i := range delays {
+			delays[i] = time.Duration(float64(delays[i]) * faultMultiplier)
+		}
+	}
+	if numChunks > 0 {
+		delays[0] += faultAdded
 	}
 
 	// Record statistics
@@ -196,6 +286,7 @@ func (s *Simulator) SimulateStreaming(ctx context.Context, modelID string, numCh
 	}
 	s.totalSimulations.Add(1)
 	s.totalDelay.Add(totalDelay.Milliseconds())
+	s.recordSimulation(profile, numChunks, totalDelay, baselineLatency)
 
 	// Record metrics
 	metrics.RecordSimulatedLatency(modelID, totalDelay.Seconds())
@@ -252,6 +343,20 @@ func (s *Simulator) GetLoadMultiplier() float64 {
 	return s.loadMultiplier.Load().(float64)
 }
 
+// SetNonStreamingTiming sets how non-streaming latency is applied
+// ("" = TimingSplit).
+func (s *Simulator) SetNonStreamingTiming(timing NonStreamingTiming) {
+	if timing == "" {
+		timing = TimingSplit
+	}
+	s.nonStreamingTiming.Store(timing)
+}
+
+// NonStreamingTiming returns how non-streaming latency is applied.
+func (s *Simulator) NonStreamingTiming() NonStreamingTiming {
+	return s.nonStreamingTiming.Load().(NonStreamingTiming)
+}
+
 // SetPeakHours sets the peak hours for load simulation.
 func (s *Simulator) SetPeakHours(hours []int) {
 	s.peakHours = make(map[int]bool)
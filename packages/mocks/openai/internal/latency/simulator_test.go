@@ -0,0 +1,148 @@
+package latency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// testModel is the model the test profile is registered for.
+const testModel = "test-model"
+
+// testProfile takes 10ms to the first token and 1ms per output token,
+// bounded to [1ms, 40ms].
+var testProfile = Profile{
+	BaseLatency:     10 * time.Millisecond,
+	PerTokenLatency: time.Millisecond,
+	MinLatency:      time.Millisecond,
+	MaxLatency:      40 * time.Millisecond,
+}
+
+// newTestSimulator returns a simulator without jitter or peak hours, so
+// its latencies are exact, with profile registered for testModel.
+func newTestSimulator(t *testing.T, profile Profile) *Simulator {
+	t.Helper()
+
+	config := DefaultSimulatorConfig()
+	config.EnableJitter = false
+	config.PeakHours = nil
+
+	s := NewSimulator(config)
+	s.SetProfile(testModel, profile)
+	return s
+}
+
+func TestSimulateForTier(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens int
+		tier   models.ServiceTier
+		fault  *LatencyFault
+		want   time.Duration
+	}{
+		{
+			name:   "default tier",
+			tokens: 10,
+			tier:   models.ServiceTierDefault,
+			want:   20 * time.Millisecond,
+		},
+		{
+			name:   "priority tier is faster",
+			tokens: 10,
+			tier:   models.ServiceTierPriority,
+			want:   time.Duration(float64(20*time.Millisecond) * 0.6),
+		},
+		{
+			name:   "flex tier is capped at max latency",
+			tokens: 10,
+			tier:   models.ServiceTierFlex,
+			want:   40 * time.Millisecond,
+		},
+		{
+			name:   "auto resolves to default",
+			tokens: 10,
+			tier:   models.ServiceTierAuto,
+			want:   20 * time.Millisecond,
+		},
+		{
+			name:   "zero output tokens",
+			tokens: 0,
+			tier:   models.ServiceTierPriority,
+			want:   time.Duration(float64(10*time.Millisecond) * 0.6),
+		},
+		{
+			name:   "fault added delay",
+			tokens: 10,
+			tier:   models.ServiceTierDefault,
+			fault:  &LatencyFault{Model: testModel, Added: 5 * time.Millisecond},
+			want:   25 * time.Millisecond,
+		},
+		{
+			name:   "fault exceeds max latency",
+			tokens: 10,
+			tier:   models.ServiceTierFlex,
+			fault:  &LatencyFault{Multiplier: 2},
+			want:   80 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSimulator(t, testProfile)
+			if tt.fault != nil {
+				if err := s.InjectLatency(*tt.fault); err != nil {
+					t.Fatalf("InjectLatency() error = %v", err)
+				}
+			}
+
+			got, err := s.SimulateForTier(context.Background(), testModel, tt.tokens, tt.tier)
+			if err != nil {
+				t.Fatalf("SimulateForTier() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SimulateForTier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateForTierDisabled(t *testing.T) {
+	s := newTestSimulator(t, testProfile)
+	s.Disable()
+
+	got, err := s.SimulateForTier(context.Background(), "no-such-model", 10, models.ServiceTierDefault)
+	if err != nil {
+		t.Fatalf("SimulateForTier() error = %v", err)
+	}
+	if got != 0 {
+		t.Errorf("SimulateForTier() = %v, want 0", got)
+	}
+	if stats := s.GetStats(); stats.TotalSimulations != 0 {
+		t.Errorf("TotalSimulations = %d, want 0", stats.TotalSimulations)
+	}
+}
+
+func TestSimulateForTierSeeded(t *testing.T) {
+	s := NewSimulator(DefaultSimulatorConfig())
+	s.SetProfile(testModel, Profile{
+		BaseLatency:     10 * time.Millisecond,
+		PerTokenLatency: time.Millisecond,
+		JitterPercent:   0.5,
+		MaxLatency:      time.Second,
+	})
+
+	simulate := func(seed int) time.Duration {
+		t.Helper()
+		latency, err := s.SimulateForTier(models.WithSeed(context.Background(), seed), testModel, 10, models.ServiceTierDefault)
+		if err != nil {
+			t.Fatalf("SimulateForTier() error = %v", err)
+		}
+		return latency
+	}
+
+	if first, second := simulate(42), simulate(42); first != second {
+		t.Errorf("same seed gave %v and %v, want equal latencies", first, second)
+	}
+}
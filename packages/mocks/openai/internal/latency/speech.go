@@ -0,0 +1,49 @@
+// Package latency provides latency simulation.
+// This file implements text-to-speech latency, which scales with the input
+// characters and varies by voice.
+package latency
+
+import (
+	"context"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// voiceLatencyMultipliers scale speech latency per voice. The newer voices
+// take longer to synthesize than the original six.
+var voiceLatencyMultipliers = map[string]float64{
+	"alloy":   1.0,
+	"echo":    1.0,
+	"fable":   1.05,
+	"onyx":    1.1,
+	"nova":    0.95,
+	"shimmer": 1.0,
+	"ash":     1.15,
+	"coral":   1.15,
+	"sage":    1.2,
+	"ballad":  1.3,
+	"verse":   1.25,
+}
+
+// VoiceLatencyMultiplier returns the latency multiplier of a voice. Unknown
+// voices return 1.0.
+func VoiceLatencyMultiplier(voice string) float64 {
+	if multiplier, ok := voiceLatencyMultipliers[voice]; ok {
+		return multiplier
+	}
+	return 1.0
+}
+
+// SimulateSpeechAndSleep simulates synthesizing characters of input with a
+// voice and sleeps for that duration. A speech model's per-token latency
+// applies per input character. Returns the simulated latency.
+func (s *Simulator) SimulateSpeechAndSleep(ctx context.Context, modelID, voice string, characters int, tier models.ServiceTier) (time.Duration, error) {
+	latency, err := s.SimulateForTier(ctx, modelID, characters, tier)
+	if err != nil {
+		return 0, err
+	}
+
+	latency = time.Duration(float64(latency) * VoiceLatencyMultiplier(voice))
+	return latency, sleep(ctx, latency)
+}
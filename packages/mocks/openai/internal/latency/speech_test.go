@@ -0,0 +1,56 @@
+package latency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestVoiceLatencyMultiplier(t *testing.T) {
+	tests := []struct {
+		voice string
+		want  float64
+	}{
+		{voice: "alloy", want: 1.0},
+		{voice: "nova", want: 0.95},
+		{voice: "ballad", want: 1.3},
+		{voice: "unknown", want: 1.0},
+		{voice: "", want: 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.voice, func(t *testing.T) {
+			if got := VoiceLatencyMultiplier(tt.voice); got != tt.want {
+				t.Errorf("VoiceLatencyMultiplier(%q) = %v, want %v", tt.voice, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateSpeechAndSleep(t *testing.T) {
+	tests := []struct {
+		voice      string
+		characters int
+		want       time.Duration
+	}{
+		{voice: "alloy", characters: 10, want: 20 * time.Millisecond},
+		{voice: "ballad", characters: 10, want: time.Duration(float64(20*time.Millisecond) * 1.3)},
+		{voice: "nova", characters: 0, want: time.Duration(float64(10*time.Millisecond) * 0.95)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.voice, func(t *testing.T) {
+			s := newTestSimulator(t, testProfile)
+
+			got, err := s.SimulateSpeechAndSleep(context.Background(), testModel, tt.voice, tt.characters, models.ServiceTierDefault)
+			if err != nil {
+				t.Fatalf("SimulateSpeechAndSleep() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SimulateSpeechAndSleep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
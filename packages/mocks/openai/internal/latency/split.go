@@ -0,0 +1,195 @@
+// Package latency provides latency simulation.
+// This file splits the latency of non-streaming requests into time to first
+// token, slept before the response is generated, and generation time,
+// proportional to the output actually produced.
+package latency
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Latency split headers.
+const (
+	// HeaderLatencyTTFT is the simulated time to first token in milliseconds
+	HeaderLatencyTTFT = "X-Sentra-Latency-TTFT-Ms"
+
+	// HeaderLatencyGeneration is the simulated generation time in milliseconds
+	HeaderLatencyGeneration = "X-Sentra-Latency-Generation-Ms"
+)
+
+// NonStreamingTiming selects how non-streaming latency is applied.
+type NonStreamingTiming string
+
+const (
+	// TimingSplit sleeps the TTFT before generating the response and the
+	// per-token time for the tokens actually produced after it
+	TimingSplit NonStreamingTiming = "split"
+
+	// TimingTotal sleeps the whole latency at once after the response is
+	// generated (the behavior before the split)
+	TimingTotal NonStreamingTiming = "total"
+)
+
+// ParseNonStreamingTiming parses a timing name ("" = split).
+func ParseNonStreamingTiming(s string) (NonStreamingTiming, error) {
+	switch timing := NonStreamingTiming(s); timing {
+	case "":
+		return TimingSplit, nil
+	case TimingSplit, TimingTotal:
+		return timing, nil
+	default:
+		return "", fmt.Errorf("unknown non-streaming timing %q (expected split or total)", s)
+	}
+}
+
+// RequestLatency is the simulated latency of one non-streaming request.
+// WaitFirstToken is called before the response is generated and
+// WaitGeneration once its output tokens are known.
+type RequestLatency struct {
+	sim     *Simulator
+	profile Profile
+	tier    models.ServiceTier
+	enabled bool
+
+	// rng draws the jitter (nil: the global source)
+	rng *rand.Rand
+
+	// TTFT is the time to first token, including load, tier and faults
+	TTFT time.Duration
+
+	// Generation is the time spent producing the output tokens (set by
+	// WaitGeneration)
+	Generation time.Duration
+
+	// baseTTFT is the jittered TTFT before load, tier and faults
+	baseTTFT time.Duration
+
+	// scale is the load and tier multiplier applied to both phases
+	scale float64
+
+	// faultMultiplier slows both phases (the added fault delay is in TTFT)
+	faultMultiplier float64
+}
+
+// StartRequest begins simulating a non-streaming request in the given
+// service tier. The TTFT is drawn immediately; the generation time is
+// drawn by WaitGeneration. Jitter is derived from the seed in ctx, if any.
+func (s *Simulator) StartRequest(ctx context.Context, modelID string, tier models.ServiceTier) (*RequestLatency, error) {
+	r := &RequestLatency{sim: s, tier: tier, enabled: s.enabled.Load(), rng: seededRand(ctx)}
+	if !r.enabled {
+		return r, nil
+	}
+
+	profile, err := s.registry.GetProfile(modelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latency profile: %w", err)
+	}
+	r.profile = profile
+
+	if s.NonStreamingTiming() == TimingTotal {
+		return r, nil
+	}
+
+	r.baseTTFT = s.jitter.ApplyJitterWith(r.rng, profile.BaseLatency, profile.JitterPercent)
+
+	r.scale = s.GetTierMultiplier(tier)
+	if s.isPeakHour() {
+		r.scale *= s.loadMultiplier.Load().(float64)
+	}
+
+	// The TTFT alone may not exceed MaxLatency; MinLatency is enforced on
+	// the total once the generation time is known
+	r.TTFT = min(time.Duration(float64(r.baseTTFT)*r.scale), profile.MaxLatency)
+
+	var faultAdded time.Duration
+	r.faultMultiplier, faultAdded = s.latencyFaultFor(modelID)
+	r.TTFT = time.Duration(float64(r.TTFT)*r.faultMultiplier) + faultAdded
+
+	return r, nil
+}
+
+// WaitFirstToken sleeps for the TTFT. With TimingTotal it returns at once.
+func (r *RequestLatency) WaitFirstToken(ctx context.Context) error {
+	return sleep(ctx, r.TTFT)
+}
+
+// WaitGeneration draws the generation time for outputTokens tokens, records
+// the request's latency and sleeps for it. With TimingTotal it sleeps the
+// whole latency (reported as Generation).
+func (r *RequestLatency) WaitGeneration(ctx context.Context, outputTokens int) error {
+	if !r.enabled {
+		return nil
+	}
+
+	if r.sim.NonStreamingTiming() == TimingTotal {
+		latency, err := r.sim.SimulateForTier(ctx, r.profile.ModelID, outputTokens, r.tier)
+		if err != nil {
+			return err
+		}
+		r.Generation = latency
+		return sleep(ctx, latency)
+	}
+
+	profile := r.profile
+	baseGeneration := r.sim.jitter.ApplyJitterWith(r.rng, profile.PerTokenLatency*time.Duration(outputTokens), profile.JitterPercent)
+
+	// The profile-driven latency, before load, tier and faults
+	baselineLatency := min(max(r.baseTTFT+baseGeneration, profile.MinLatency), profile.MaxLatency)
+
+	// Enforce min/max bounds on the total; the TTFT has already been slept,
+	// so only the generation time is adjusted
+	ttft := min(time.Duration(float64(r.baseTTFT)*r.scale), profile.MaxLatency)
+	generation := time.Duration(float64(baseGeneration) * r.scale)
+	if total := ttft + generation; total < profile.MinLatency {
+		generation = profile.MinLatency - ttft
+	} else if total > profile.MaxLatency {
+		generation = profile.MaxLatency - ttft
+	}
+	r.Generation = time.Duration(float64(generation) * r.faultMultiplier)
+
+	// Record statistics
+	total := r.Total()
+	r.sim.totalSimulations.Add(1)
+	r.sim.totalDelay.Add(total.Milliseconds())
+	r.sim.recordSimulation(profile, outputTokens, total, baselineLatency)
+
+	// Record metrics
+	metrics.RecordSimulatedLatency(profile.ModelID, total.Seconds())
+
+	return sleep(ctx, r.Generation)
+}
+
+// Total returns the request's total simulated latency.
+func (r *RequestLatency) Total() time.Duration {
+	return r.TTFT + r.Generation
+}
+
+// SetHeaders exposes both latency components on a response for debugging.
+func (r *RequestLatency) SetHeaders(w http.ResponseWriter) {
+	if !r.enabled {
+		return
+	}
+	w.Header().Set(HeaderLatencyTTFT, fmt.Sprintf("%d", r.TTFT.Milliseconds()))
+	w.Header().Set(HeaderLatencyGeneration, fmt.Sprintf("%d", r.Generation.Milliseconds()))
+}
+
+// sleep waits for d, returning early if ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,178 @@
+package latency
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestParseNonStreamingTiming(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    NonStreamingTiming
+		wantErr bool
+	}{
+		{input: "", want: TimingSplit},
+		{input: "split", want: TimingSplit},
+		{input: "total", want: TimingTotal},
+		{input: "streaming", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNonStreamingTiming(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNonStreamingTiming(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNonStreamingTiming(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestLatency(t *testing.T) {
+	tests := []struct {
+		name           string
+		timing         NonStreamingTiming
+		profile        Profile
+		tokens         int
+		tier           models.ServiceTier
+		fault          *LatencyFault
+		wantTTFT       time.Duration
+		wantGeneration time.Duration
+	}{
+		{
+			name:           "split",
+			timing:         TimingSplit,
+			profile:        testProfile,
+			tokens:         10,
+			tier:           models.ServiceTierDefault,
+			wantTTFT:       10 * time.Millisecond,
+			wantGeneration: 10 * time.Millisecond,
+		},
+		{
+			name:           "split in the flex tier",
+			timing:         TimingSplit,
+			profile:        testProfile,
+			tokens:         4,
+			tier:           models.ServiceTierFlex,
+			wantTTFT:       25 * time.Millisecond,
+			wantGeneration: 10 * time.Millisecond,
+		},
+		{
+			name:           "generation is cut at max latency",
+			timing:         TimingSplit,
+			profile:        testProfile,
+			tokens:         100,
+			tier:           models.ServiceTierDefault,
+			wantTTFT:       10 * time.Millisecond,
+			wantGeneration: 30 * time.Millisecond,
+		},
+		{
+			name:   "generation is extended to min latency",
+			timing: TimingSplit,
+			profile: Profile{
+				BaseLatency:     10 * time.Millisecond,
+				PerTokenLatency: time.Millisecond,
+				MinLatency:      30 * time.Millisecond,
+				MaxLatency:      40 * time.Millisecond,
+			},
+			tokens:         0,
+			tier:           models.ServiceTierDefault,
+			wantTTFT:       10 * time.Millisecond,
+			wantGeneration: 20 * time.Millisecond,
+		},
+		{
+			name:           "fault slows both phases",
+			timing:         TimingSplit,
+			profile:        testProfile,
+			tokens:         10,
+			tier:           models.ServiceTierDefault,
+			fault:          &LatencyFault{Multiplier: 2, Added: 5 * time.Millisecond},
+			wantTTFT:       25 * time.Millisecond,
+			wantGeneration: 20 * time.Millisecond,
+		},
+		{
+			name:           "total",
+			timing:         TimingTotal,
+			profile:        testProfile,
+			tokens:         10,
+			tier:           models.ServiceTierDefault,
+			wantGeneration: 20 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSimulator(t, tt.profile)
+			s.SetNonStreamingTiming(tt.timing)
+			if tt.fault != nil {
+				if err := s.InjectLatency(*tt.fault); err != nil {
+					t.Fatalf("InjectLatency() error = %v", err)
+				}
+			}
+
+			r, err := s.StartRequest(context.Background(), testModel, tt.tier)
+			if err != nil {
+				t.Fatalf("StartRequest() error = %v", err)
+			}
+			if r.TTFT != tt.wantTTFT {
+				t.Errorf("TTFT = %v, want %v", r.TTFT, tt.wantTTFT)
+			}
+			if err := r.WaitFirstToken(context.Background()); err != nil {
+				t.Fatalf("WaitFirstToken() error = %v", err)
+			}
+			if err := r.WaitGeneration(context.Background(), tt.tokens); err != nil {
+				t.Fatalf("WaitGeneration() error = %v", err)
+			}
+			if r.Generation != tt.wantGeneration {
+				t.Errorf("Generation = %v, want %v", r.Generation, tt.wantGeneration)
+			}
+			if stats := s.GetStats(); stats.TotalSimulations != 1 {
+				t.Errorf("TotalSimulations = %d, want 1", stats.TotalSimulations)
+			}
+		})
+	}
+}
+
+func TestRequestLatencySetHeaders(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		wantTTFT       string
+		wantGeneration string
+	}{
+		{name: "enabled", enabled: true, wantTTFT: "10", wantGeneration: "10"},
+		{name: "disabled", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSimulator(t, testProfile)
+			if !tt.enabled {
+				s.Disable()
+			}
+
+			r, err := s.StartRequest(context.Background(), testModel, models.ServiceTierDefault)
+			if err != nil {
+				t.Fatalf("StartRequest() error = %v", err)
+			}
+			if err := r.WaitGeneration(context.Background(), 10); err != nil {
+				t.Fatalf("WaitGeneration() error = %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			r.SetHeaders(w)
+			if got := w.Header().Get(HeaderLatencyTTFT); got != tt.wantTTFT {
+				t.Errorf("%s = %q, want %q", HeaderLatencyTTFT, got, tt.wantTTFT)
+			}
+			if got := w.Header().Get(HeaderLatencyGeneration); got != tt.wantGeneration {
+				t.Errorf("%s = %q, want %q", HeaderLatencyGeneration, got, tt.wantGeneration)
+			}
+		})
+	}
+}
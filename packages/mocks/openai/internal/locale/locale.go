@@ -0,0 +1,281 @@
+// Package locale provides the mock's simulated locale: the language and
+// region, timezone and currency that localized output (formatted amounts and
+// timestamps) uses. Scenario steps change it (set_locale) so an agent's i18n
+// handling can be tested without changing the host machine.
+package locale
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Settings are the simulated locale settings.
+type Settings struct {
+	// Locale is a BCP 47 language tag (e.g., "de-DE")
+	Locale string `json:"locale"`
+
+	// Timezone is an IANA timezone (e.g., "Europe/Berlin")
+	Timezone string `json:"timezone"`
+
+	// Currency is an ISO 4217 currency code (e.g., "EUR")
+	Currency string `json:"currency"`
+}
+
+// Default returns the settings used until configured: en-US, UTC, USD.
+func Default() Settings {
+	return Settings{Locale: "en-US", Timezone: "UTC", Currency: "USD"}
+}
+
+var (
+	localePattern   = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z]{2})?$`)
+	currencyPattern = regexp.MustCompile(`^[a-zA-Z]{3}$`)
+)
+
+// normalize canonicalizes the settings' case ("de_de" → "de-DE",
+// "eur" → "EUR").
+func (s Settings) normalize() Settings {
+	if language, region, ok := strings.Cut(strings.ReplaceAll(s.Locale, "_", "-"), "-"); ok {
+		s.Locale = strings.ToLower(language) + "-" + strings.ToUpper(region)
+	} else {
+		s.Locale = strings.ToLower(s.Locale)
+	}
+	s.Currency = strings.ToUpper(s.Currency)
+	return s
+}
+
+// Validate checks each non-empty setting.
+func (s Settings) Validate() error {
+	if s.Locale != "" && !localePattern.MatchString(s.Locale) {
+		return fmt.Errorf("invalid locale %q (expected a language tag such as en-US)", s.Locale)
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q (expected an IANA timezone such as Europe/Berlin)", s.Timezone)
+		}
+	}
+	if s.Currency != "" && !currencyPattern.MatchString(s.Currency) {
+		return fmt.Errorf("invalid currency %q (expected an ISO 4217 code such as EUR)", s.Currency)
+	}
+	return nil
+}
+
+// merge returns s with the non-empty fields of update applied.
+func (s Settings) merge(update Settings) Settings {
+	if update.Locale != "" {
+		s.Locale = update.Locale
+	}
+	if update.Timezone != "" {
+		s.Timezone = update.Timezone
+	}
+	if update.Currency != "" {
+		s.Currency = update.Currency
+	}
+	return s
+}
+
+// Location returns the settings' timezone (UTC if unset or invalid).
+func (s Settings) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil || s.Timezone == "" {
+		return time.UTC
+	}
+	return location
+}
+
+var (
+	// mu guards configured and current
+	mu sync.RWMutex
+
+	// configured are the startup settings Reset returns to
+	configured = Default()
+
+	// current are the settings in effect
+	current = Default()
+)
+
+// Current returns the settings in effect.
+func Current() Settings {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Configure sets the startup settings (empty fields keep the defaults) and
+// makes them current.
+func Configure(settings Settings) error {
+	settings = settings.normalize()
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	configured = Default().merge(settings)
+	current = configured
+	return nil
+}
+
+// Set changes the settings in effect; empty fields are left unchanged.
+func Set(update Settings) (Settings, error) {
+	update = update.normalize()
+	if err := update.Validate(); err != nil {
+		return Settings{}, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = current.merge(update)
+	return current, nil
+}
+
+// Reset returns to the startup settings.
+func Reset() Settings {
+	mu.Lock()
+	defer mu.Unlock()
+	current = configured
+	return current
+}
+
+// numberFormat describes how a locale writes numbers, amounts and times.
+type numberFormat struct {
+	// decimal and group are the decimal and thousands separators
+	decimal string
+	group   string
+
+	// symbolAfter places the currency symbol after the amount ("12,50 €")
+	symbolAfter bool
+
+	// timeLayout is the date and time layout
+	timeLayout string
+}
+
+// formats are keyed by full tag, then by language.
+var formats = map[string]numberFormat{
+	"en":    {decimal: ".", group: ",", timeLayout: "01/02/2006, 3:04 PM"},
+	"en-GB": {decimal: ".", group: ",", timeLayout: "02/01/2006, 15:04"},
+	"en-IN": {decimal: ".", group: ",", timeLayout: "02/01/2006, 3:04 pm"},
+	"de":    {decimal: ",", group: ".", symbolAfter: true, timeLayout: "02.01.2006, 15:04"},
+	"de-CH": {decimal: ".", group: "’", timeLayout: "02.01.2006, 15:04"},
+	"fr":    {decimal: ",", group: " ", symbolAfter: true, timeLayout: "02/01/2006 15:04"},
+	"es":    {decimal: ",", group: ".", symbolAfter: true, timeLayout: "02/01/2006, 15:04"},
+	"it":    {decimal: ",", group: ".", symbolAfter: true, timeLayout: "02/01/2006, 15:04"},
+	"nl":    {decimal: ",", group: ".", timeLayout: "02-01-2006 15:04"},
+	"pt":    {decimal: ",", group: ".", timeLayout: "02/01/2006, 15:04"},
+	"pl":    {decimal: ",", group: " ", symbolAfter: true, timeLayout: "02.01.2006, 15:04"},
+	"sv":    {decimal: ",", group: " ", symbolAfter: true, timeLayout: "2006-01-02 15:04"},
+	"ja":    {decimal: ".", group: ",", timeLayout: "2006/01/02 15:04"},
+	"zh":    {decimal: ".", group: ",", timeLayout: "2006/01/02 15:04"},
+	"ko":    {decimal: ".", group: ",", timeLayout: "2006. 01. 02. 15:04"},
+}
+
+// format returns the number format for the settings' locale (en if unknown).
+func (s Settings) format() numberFormat {
+	if f, ok := formats[s.Locale]; ok {
+		return f
+	}
+	language, _, _ := strings.Cut(s.Locale, "-")
+	if f, ok := formats[language]; ok {
+		return f
+	}
+	return formats["en"]
+}
+
+// currencySymbols are the symbols of common currencies; others are written
+// as their code.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"INR": "₹",
+	"KRW": "₩",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"AUD": "A$",
+	"MXN": "MX$",
+}
+
+// zeroDecimalCurrencies have no minor unit; threeDecimalCurrencies have
+// three digits of it. Every other currency has two.
+var (
+	zeroDecimalCurrencies  = map[string]bool{"JPY": true, "KRW": true, "VND": true, "CLP": true, "ISK": true, "UGX": true}
+	threeDecimalCurrencies = map[string]bool{"BHD": true, "KWD": true, "OMR": true, "JOD": true, "TND": true}
+)
+
+// currencyDecimals returns the number of minor-unit digits of a currency
+// (e.g., 2 for USD, 0 for JPY).
+func currencyDecimals(currency string) int {
+	currency = strings.ToUpper(currency)
+	switch {
+	case zeroDecimalCurrencies[currency]:
+		return 0
+	case threeDecimalCurrencies[currency]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// FormatNumber formats a number with the locale's separators.
+func (s Settings) FormatNumber(value float64, decimals int) string {
+	f := s.format()
+
+	formatted := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteString(f.group)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String()
+	if fraction != "" {
+		result += f.decimal + fraction
+	}
+	if value < 0 && strings.Trim(formatted, "0.") != "" {
+		result = "-" + result
+	}
+	return result
+}
+
+// FormatAmount formats an amount in the currency's minor units (e.g.,
+// cents, as Stripe reports them): 123456 is "$1,234.56" in en-US/USD and
+// "1.234,56 €" in de-DE/EUR.
+func (s Settings) FormatAmount(minorUnits int64) string {
+	decimals := currencyDecimals(s.Currency)
+	value := float64(minorUnits) / math.Pow10(decimals)
+	number := s.FormatNumber(math.Abs(value), decimals)
+
+	symbol, ok := currencySymbols[s.Currency]
+	if !ok {
+		symbol = s.Currency
+	}
+
+	var amount string
+	switch {
+	case s.format().symbolAfter:
+		amount = number + " " + symbol
+	case !ok:
+		amount = symbol + " " + number
+	default:
+		amount = symbol + number
+	}
+	if minorUnits < 0 {
+		amount = "-" + amount
+	}
+	return amount
+}
+
+// FormatTime formats a time in the settings' timezone and locale.
+func (s Settings) FormatTime(t time.Time) string {
+	return t.In(s.Location()).Format(s.format().timeLayout)
+}
@@ -0,0 +1,119 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		settings   Settings
+		minorUnits int64
+		want       string
+	}{
+		{Settings{Locale: "en-US", Currency: "USD"}, 123456, "$1,234.56"},
+		{Settings{Locale: "de-DE", Currency: "EUR"}, 123456, "1.234,56\u00a0€"},
+		{Settings{Locale: "fr-FR", Currency: "EUR"}, -123456, "-1\u202f234,56\u00a0€"},
+		{Settings{Locale: "ja-JP", Currency: "JPY"}, 123456, "¥123,456"},
+		{Settings{Locale: "en-US", Currency: "KWD"}, 123456, "KWD\u00a0123.456"},
+		{Settings{Locale: "xx", Currency: "USD"}, 5, "$0.05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.settings.Locale+"/"+tt.settings.Currency, func(t *testing.T) {
+			if got := tt.settings.FormatAmount(tt.minorUnits); got != tt.want {
+				t.Errorf("FormatAmount(%d) = %q, want %q", tt.minorUnits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    float64
+		decimals int
+		want     string
+	}{
+		{"en-US", 1234567.891, 2, "1,234,567.89"},
+		{"de-DE", 0.00125, 6, "0,001250"},
+		{"de-CH", 1234.5, 1, "1’234.5"},
+		{"en-US", -0.0000001, 2, "0.00"},
+		{"en-US", 999, 0, "999"},
+	}
+
+	for _, tt := range tests {
+		if got := (Settings{Locale: tt.locale}).FormatNumber(tt.value, tt.decimals); got != tt.want {
+			t.Errorf("%s: FormatNumber(%v, %d) = %q, want %q", tt.locale, tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	at := time.Date(2026, 10, 15, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		settings Settings
+		want     string
+	}{
+		{Settings{Locale: "en-US", Timezone: "UTC"}, "10/15/2026, 12:30 PM"},
+		{Settings{Locale: "de-DE", Timezone: "Europe/Berlin"}, "15.10.2026, 14:30"},
+		{Settings{Locale: "ja-JP", Timezone: "Asia/Tokyo"}, "2026/10/15 21:30"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.settings.FormatTime(at); got != tt.want {
+			t.Errorf("%s: FormatTime() = %q, want %q", tt.settings.Locale, got, tt.want)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(Settings{}) })
+
+	if err := Configure(Settings{Locale: "en_gb", Currency: "gbp"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Current(), (Settings{Locale: "en-GB", Timezone: "UTC", Currency: "GBP"}); got != want {
+		t.Fatalf("Configure: current = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		update  Settings
+		want    Settings
+		wantErr bool
+	}{
+		{name: "timezone only", update: Settings{Timezone: "Europe/Berlin"}, want: Settings{Locale: "en-GB", Timezone: "Europe/Berlin", Currency: "GBP"}},
+		{name: "normalized", update: Settings{Locale: "DE_de", Currency: "eur"}, want: Settings{Locale: "de-DE", Timezone: "Europe/Berlin", Currency: "EUR"}},
+		{name: "invalid locale", update: Settings{Locale: "german"}, wantErr: true},
+		{name: "invalid timezone", update: Settings{Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "invalid currency", update: Settings{Currency: "EURO"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := Current()
+			got, err := Set(tt.update)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Set(%+v) succeeded", tt.update)
+				}
+				if Current() != before {
+					t.Errorf("rejected update changed the settings to %+v", Current())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Set(%+v) = %+v, want %+v", tt.update, got, tt.want)
+			}
+		})
+	}
+
+	if got, want := Reset(), (Settings{Locale: "en-GB", Timezone: "UTC", Currency: "GBP"}); got != want {
+		t.Errorf("Reset() = %+v, want %+v", got, want)
+	}
+}
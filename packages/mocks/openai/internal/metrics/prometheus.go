@@ -3,10 +3,31 @@
 package metrics
 
 import (
+	"os"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// InstanceID identifies this replica when running several mocks behind a
+// load balancer (cluster mode). It is attached to every metric as the
+// instance_id label. Set SENTRA_INSTANCE_ID to override the hostname.
+var InstanceID = resolveInstanceID()
+
+// instanceLabels are constant labels attached to every metric.
+var instanceLabels = prometheus.Labels{"instance_id": InstanceID}
+
+// resolveInstanceID returns SENTRA_INSTANCE_ID, the hostname, or "local".
+func resolveInstanceID() string {
+	if id := os.Getenv("SENTRA_INSTANCE_ID"); id != "" {
+		return id
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "local"
+}
+
 // Prometheus metrics for the OpenAI mock server.
 // These metrics match production monitoring requirements.
 
@@ -14,9 +35,10 @@ var (
 	// RequestsTotal counts total HTTP requests by model and status.
 	RequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "requests_total",
-			Help:      "Total number of API requests",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "requests_total",
+			Help:        "Total number of API requests",
 		},
 		[]string{"model", "endpoint", "status"},
 	)
@@ -24,10 +46,11 @@ var (
 	// RequestDuration measures request latency (including simulated delay).
 	RequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Namespace: "openai_mock",
-			Name:      "request_duration_seconds",
-			Help:      "Request duration in seconds (including simulated latency)",
-			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15), // 1ms to ~16s
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "request_duration_seconds",
+			Help:        "Request duration in seconds (including simulated latency)",
+			Buckets:     prometheus.ExponentialBuckets(0.001, 2, 15), // 1ms to ~16s
 		},
 		[]string{"model", "endpoint"},
 	)
@@ -35,10 +58,11 @@ var (
 	// ProcessingDuration measures actual processing time (excluding simulated delay).
 	ProcessingDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Namespace: "openai_mock",
-			Name:      "processing_duration_seconds",
-			Help:      "Actual processing duration in seconds (excluding simulated latency)",
-			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 15), // 0.1ms to ~3.2s
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "processing_duration_seconds",
+			Help:        "Actual processing duration in seconds (excluding simulated latency)",
+			Buckets:     prometheus.ExponentialBuckets(0.0001, 2, 15), // 0.1ms to ~3.2s
 		},
 		[]string{"model", "endpoint"},
 	)
@@ -46,9 +70,10 @@ var (
 	// TokensTotal counts total tokens processed.
 	TokensTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "tokens_total",
-			Help:      "Total tokens processed",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "tokens_total",
+			Help:        "Total tokens processed",
 		},
 		[]string{"model", "type"}, // type: input or output
 	)
@@ -56,9 +81,10 @@ var (
 	// CostUSDTotal tracks cumulative cost in USD.
 	CostUSDTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "cost_usd_total",
-			Help:      "Total cost in USD (simulated)",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "cost_usd_total",
+			Help:        "Total cost in USD (simulated)",
 		},
 		[]string{"model"},
 	)
@@ -66,9 +92,10 @@ var (
 	// ErrorsTotal counts errors by type.
 	ErrorsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "errors_total",
-			Help:      "Total number of errors",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "errors_total",
+			Help:        "Total number of errors",
 		},
 		[]string{"model", "error_type"},
 	)
@@ -76,9 +103,10 @@ var (
 	// RateLimitHits counts rate limit hits.
 	RateLimitHits = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "rate_limit_hits_total",
-			Help:      "Total number of rate limit hits",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "rate_limit_hits_total",
+			Help:        "Total number of rate limit hits",
 		},
 		[]string{"api_key", "limit_type"}, // limit_type: requests or tokens
 	)
@@ -86,9 +114,10 @@ var (
 	// RateLimitRemaining tracks remaining rate limit quota.
 	RateLimitRemaining = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "rate_limit_remaining",
-			Help:      "Remaining rate limit quota",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "rate_limit_remaining",
+			Help:        "Remaining rate limit quota",
 		},
 		[]string{"api_key", "limit_type"},
 	)
@@ -96,9 +125,10 @@ var (
 	// CacheHits counts cache hits.
 	CacheHits = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "cache_hits_total",
-			Help:      "Total number of cache hits",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "cache_hits_total",
+			Help:        "Total number of cache hits",
 		},
 		[]string{"cache_type"}, // cache_type: token, response, fixture
 	)
@@ -106,9 +136,10 @@ var (
 	// CacheMisses counts cache misses.
 	CacheMisses = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "cache_misses_total",
-			Help:      "Total number of cache misses",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "cache_misses_total",
+			Help:        "Total number of cache misses",
 		},
 		[]string{"cache_type"},
 	)
@@ -116,9 +147,10 @@ var (
 	// CacheSize tracks cache size.
 	CacheSize = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "cache_size",
-			Help:      "Current cache size (number of entries)",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "cache_size",
+			Help:        "Current cache size (number of entries)",
 		},
 		[]string{"cache_type"},
 	)
@@ -126,18 +158,20 @@ var (
 	// StreamingConnections tracks active streaming connections.
 	StreamingConnections = promauto.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "streaming_connections",
-			Help:      "Current number of active streaming connections",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "streaming_connections",
+			Help:        "Current number of active streaming connections",
 		},
 	)
 
 	// FixturesLoaded counts loaded fixtures.
 	FixturesLoaded = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "fixtures_loaded",
-			Help:      "Number of loaded fixtures",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "fixtures_loaded",
+			Help:        "Number of loaded fixtures",
 		},
 		[]string{"fixture_type"},
 	)
@@ -145,10 +179,11 @@ var (
 	// SimulatedLatency tracks simulated latency distribution.
 	SimulatedLatency = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Namespace: "openai_mock",
-			Name:      "simulated_latency_seconds",
-			Help:      "Simulated latency in seconds",
-			Buckets:   prometheus.ExponentialBuckets(0.01, 1.5, 15), // 10ms to ~4s
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "simulated_latency_seconds",
+			Help:        "Simulated latency in seconds",
+			Buckets:     prometheus.ExponentialBuckets(0.01, 1.5, 15), // 10ms to ~4s
 		},
 		[]string{"model"},
 	)
@@ -156,9 +191,10 @@ var (
 	// StorageOperations counts storage operations.
 	StorageOperations = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Namespace: "openai_mock",
-			Name:      "storage_operations_total",
-			Help:      "Total number of storage operations",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "storage_operations_total",
+			Help:        "Total number of storage operations",
 		},
 		[]string{"operation", "status"}, // operation: get, set, delete; status: success, error
 	)
@@ -166,10 +202,11 @@ var (
 	// StorageLatency measures storage operation latency.
 	StorageLatency = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Namespace: "openai_mock",
-			Name:      "storage_latency_seconds",
-			Help:      "Storage operation latency in seconds",
-			Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 15), // 10µs to ~320ms
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "storage_latency_seconds",
+			Help:        "Storage operation latency in seconds",
+			Buckets:     prometheus.ExponentialBuckets(0.00001, 2, 15), // 10µs to ~320ms
 		},
 		[]string{"operation"},
 	)
@@ -177,10 +214,11 @@ var (
 	// TokenizationLatency measures tokenization latency.
 	TokenizationLatency = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Namespace: "openai_mock",
-			Name:      "tokenization_latency_seconds",
-			Help:      "Tokenization latency in seconds",
-			Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 12), // 10µs to ~40ms
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "tokenization_latency_seconds",
+			Help:        "Tokenization latency in seconds",
+			Buckets:     prometheus.ExponentialBuckets(0.00001, 2, 12), // 10µs to ~40ms
 		},
 		[]string{"model"},
 	)
@@ -188,9 +226,10 @@ var (
 	// ActiveRequests tracks currently processing requests.
 	ActiveRequests = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "active_requests",
-			Help:      "Number of currently processing requests",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "active_requests",
+			Help:        "Number of currently processing requests",
 		},
 		[]string{"endpoint"},
 	)
@@ -198,9 +237,10 @@ var (
 	// RequestsPerSecond tracks request rate.
 	RequestsPerSecond = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "requests_per_second",
-			Help:      "Current requests per second",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "requests_per_second",
+			Help:        "Current requests per second",
 		},
 		[]string{"endpoint"},
 	)
@@ -208,9 +248,10 @@ var (
 	// MemoryUsage tracks memory usage.
 	MemoryUsage = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "memory_usage_bytes",
-			Help:      "Memory usage in bytes",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "memory_usage_bytes",
+			Help:        "Memory usage in bytes",
 		},
 		[]string{"component"}, // component: cache, storage, total
 	)
@@ -218,12 +259,36 @@ var (
 	// BuildInfo provides build information.
 	BuildInfo = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: "openai_mock",
-			Name:      "build_info",
-			Help:      "Build information",
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "build_info",
+			Help:        "Build information",
 		},
 		[]string{"version", "go_version", "build_time"},
 	)
+
+	// QueueDepth tracks requests waiting for simulated capacity per service tier.
+	QueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "queue_depth",
+			Help:        "Number of requests waiting for processing capacity",
+		},
+		[]string{"service_tier"},
+	)
+
+	// QueueWait measures how long requests waited for simulated capacity.
+	QueueWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace:   "openai_mock",
+			ConstLabels: instanceLabels,
+			Name:        "queue_wait_seconds",
+			Help:        "Time spent waiting for processing capacity in seconds",
+			Buckets:     prometheus.ExponentialBuckets(0.001, 2, 16), // 1ms to ~33s
+		},
+		[]string{"service_tier"},
+	)
 )
 
 // RecordRequest records a completed request.
@@ -334,4 +399,14 @@ func SetMemoryUsage(component string, bytes int64) {
 // SetBuildInfo sets build information (should be called once at startup).
 func SetBuildInfo(version, goVersion, buildTime string) {
 	BuildInfo.WithLabelValues(version, goVersion, buildTime).Set(1)
-}
\ No newline at end of file
+}
+
+// SetQueueDepth sets the number of queued requests for a service tier.
+func SetQueueDepth(serviceTier string, depth int) {
+	QueueDepth.WithLabelValues(serviceTier).Set(float64(depth))
+}
+
+// RecordQueueWait records how long a request waited for capacity.
+func RecordQueueWait(serviceTier string, wait float64) {
+	QueueWait.WithLabelValues(serviceTier).Observe(wait)
+}
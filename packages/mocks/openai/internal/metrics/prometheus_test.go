@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestResolveInstanceID(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "local"
+	}
+
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{name: "from environment", env: "replica-2", want: "replica-2"},
+		{name: "hostname", env: "", want: hostname},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SENTRA_INSTANCE_ID", tt.env)
+			if got := resolveInstanceID(); got != tt.want {
+				t.Errorf("resolveInstanceID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstanceLabel(t *testing.T) {
+	SetQueueDepth("flex", 3)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "openai_mock_queue_depth" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["service_tier"] != "flex" {
+				continue
+			}
+			if labels["instance_id"] != InstanceID {
+				t.Errorf("instance_id = %q, want %q", labels["instance_id"], InstanceID)
+			}
+			if got := metric.GetGauge().GetValue(); got != 3 {
+				t.Errorf("queue depth = %v, want 3", got)
+			}
+			return
+		}
+	}
+	t.Error("openai_mock_queue_depth{service_tier=\"flex\"} not gathered")
+}
@@ -5,12 +5,15 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
@@ -20,6 +23,10 @@ import (
 // Tracer is the global tracer instance.
 var Tracer trace.Tracer
 
+// tracerProvider is the SDK provider (nil when tracing is disabled).
+// Kept so buffered spans can be flushed on shutdown.
+var tracerProvider *sdktrace.TracerProvider
+
 // TracingConfig contains tracing configuration.
 type TracingConfig struct {
 	// Enabled indicates if tracing is enabled
@@ -54,6 +61,21 @@ func DefaultTracingConfig() TracingConfig {
 	}
 }
 
+// TracingConfigFromEnv returns the default configuration, with tracing
+// enabled when OTEL_EXPORTER_OTLP_ENDPOINT names a collector (host:port,
+// with or without an http:// scheme).
+func TracingConfigFromEnv() TracingConfig {
+	config := DefaultTracingConfig()
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		config.Enabled = true
+		config.Endpoint = strings.TrimPrefix(strings.TrimPrefix(endpoint, "http://"), "https://")
+	}
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		config.ServiceName = name
+	}
+	return config
+}
+
 // InitTracing initializes distributed tracing.
 func InitTracing(config TracingConfig) error {
 	if !config.Enabled {
@@ -94,8 +116,11 @@ func InitTracing(config TracingConfig) error {
 		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(config.SampleRate)),
 	)
 
-	// Set global trace provider
+	// Set global trace provider; incoming traceparent headers are read
+	// with the W3C trace-context propagator
 	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tracerProvider = tp
 
 	// Get tracer
 	Tracer = tp.Tracer(config.ServiceName)
@@ -103,6 +128,21 @@ func InitTracing(config TracingConfig) error {
 	return nil
 }
 
+// ShutdownTracing flushes buffered spans and closes the exporter.
+// It is a no-op when tracing is disabled.
+func ShutdownTracing(ctx context.Context) error {
+	if tracerProvider == nil {
+		return nil
+	}
+
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down tracer provider: %w", err)
+	}
+
+	tracerProvider = nil
+	return nil
+}
+
 // StartSpan starts a new span with the given name.
 func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
 	if Tracer == nil {
@@ -276,22 +316,22 @@ func RecordTokenUsage(ctx context.Context, inputTokens, outputTokens int) {
 	)
 }
 
-// RecordCost records cost on the current span.
-func RecordCost(ctx context.Context, cost float64) {
+// RecordSpanCost records cost on the current span.
+func RecordSpanCost(ctx context.Context, cost float64) {
 	AddSpanAttributes(ctx,
 		AttrCost.Float64(cost),
 	)
 }
 
-// RecordCacheHit records a cache hit/miss on the current span.
-func RecordCacheHit(ctx context.Context, hit bool) {
+// RecordSpanCacheHit records a cache hit/miss on the current span.
+func RecordSpanCacheHit(ctx context.Context, hit bool) {
 	AddSpanAttributes(ctx,
 		AttrCacheHit.Bool(hit),
 	)
 }
 
-// RecordRateLimitHit records a rate limit hit on the current span.
-func RecordRateLimitHit(ctx context.Context, hit bool) {
+// RecordSpanRateLimitHit records a rate limit hit on the current span.
+func RecordSpanRateLimitHit(ctx context.Context, hit bool) {
 	AddSpanAttributes(ctx,
 		AttrRateLimitHit.Bool(hit),
 	)
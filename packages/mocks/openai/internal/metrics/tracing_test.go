@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTracingConfigFromEnv(t *testing.T) {
+	defaults := DefaultTracingConfig()
+
+	tests := []struct {
+		name            string
+		endpoint        string
+		serviceName     string
+		wantEnabled     bool
+		wantEndpoint    string
+		wantServiceName string
+	}{
+		{
+			name:            "unset",
+			wantEndpoint:    defaults.Endpoint,
+			wantServiceName: defaults.ServiceName,
+		},
+		{
+			name:            "host and port",
+			endpoint:        "collector:4317",
+			wantEnabled:     true,
+			wantEndpoint:    "collector:4317",
+			wantServiceName: defaults.ServiceName,
+		},
+		{
+			name:            "http scheme",
+			endpoint:        "http://collector:4317",
+			wantEnabled:     true,
+			wantEndpoint:    "collector:4317",
+			wantServiceName: defaults.ServiceName,
+		},
+		{
+			name:            "https scheme",
+			endpoint:        "https://collector:4317",
+			wantEnabled:     true,
+			wantEndpoint:    "collector:4317",
+			wantServiceName: defaults.ServiceName,
+		},
+		{
+			name:            "service name",
+			serviceName:     "checkout-mock",
+			wantEndpoint:    defaults.Endpoint,
+			wantServiceName: "checkout-mock",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			t.Setenv("OTEL_SERVICE_NAME", tt.serviceName)
+
+			config := TracingConfigFromEnv()
+			if config.Enabled != tt.wantEnabled {
+				t.Errorf("Enabled = %v, want %v", config.Enabled, tt.wantEnabled)
+			}
+			if config.Endpoint != tt.wantEndpoint {
+				t.Errorf("Endpoint = %q, want %q", config.Endpoint, tt.wantEndpoint)
+			}
+			if config.ServiceName != tt.wantServiceName {
+				t.Errorf("ServiceName = %q, want %q", config.ServiceName, tt.wantServiceName)
+			}
+		})
+	}
+}
+
+func TestShutdownTracingDisabled(t *testing.T) {
+	if err := InitTracing(DefaultTracingConfig()); err != nil {
+		t.Fatalf("InitTracing() error = %v", err)
+	}
+	if err := ShutdownTracing(context.Background()); err != nil {
+		t.Errorf("ShutdownTracing() error = %v, want nil when tracing is disabled", err)
+	}
+}
@@ -0,0 +1,183 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines Assistants API assistant and tool types.
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Assistant limits (matching the API).
+const (
+	// MaxAssistantNameLength is the longest name an assistant may have
+	MaxAssistantNameLength = 256
+
+	// MaxAssistantTools is the most tools an assistant may have
+	MaxAssistantTools = 128
+)
+
+// AssistantToolTypes are the tool types an assistant can use.
+var AssistantToolTypes = []string{"function", "code_interpreter", "file_search"}
+
+// AssistantTool is a tool enabled on an assistant or run.
+type AssistantTool struct {
+	// Type is "function", "code_interpreter" or "file_search"
+	Type string `json:"type"`
+
+	// Function is the function definition (function tools only)
+	Function *Function `json:"function,omitempty"`
+}
+
+// Assistant is an assistant in the Assistants API.
+type Assistant struct {
+	// ID is the assistant identifier
+	ID string `json:"id"`
+
+	// Object is always "assistant"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the assistant was created
+	CreatedAt int64 `json:"created_at"`
+
+	// Name is the assistant's name
+	Name *string `json:"name"`
+
+	// Description describes the assistant
+	Description *string `json:"description"`
+
+	// Model is the model runs use unless they override it
+	Model string `json:"model"`
+
+	// Instructions are the system instructions of the assistant's runs
+	Instructions *string `json:"instructions"`
+
+	// Tools are the tools the assistant can call
+	Tools []AssistantTool `json:"tools"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata"`
+}
+
+// FunctionNames returns the names of the function tools.
+func FunctionNames(tools []AssistantTool) []string {
+	var names []string
+	for _, tool := range tools {
+		if tool.Type == "function" && tool.Function != nil {
+			names = append(names, tool.Function.Name)
+		}
+	}
+	return names
+}
+
+// CreateAssistantRequest is the body of POST /v1/assistants.
+type CreateAssistantRequest struct {
+	// Model is the model runs use
+	Model string `json:"model"`
+
+	// Name is the assistant's name (optional)
+	Name *string `json:"name,omitempty"`
+
+	// Description describes the assistant (optional)
+	Description *string `json:"description,omitempty"`
+
+	// Instructions are the system instructions (optional)
+	Instructions *string `json:"instructions,omitempty"`
+
+	// Tools are the tools the assistant can call (optional)
+	Tools []AssistantTool `json:"tools,omitempty"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate validates the assistant request.
+func (r *CreateAssistantRequest) Validate() error {
+	if r.Model == "" {
+		param := "model"
+		return NewBadRequestError("Missing required parameter: 'model'.", &param)
+	}
+	if err := validateAssistantName(r.Name); err != nil {
+		return err
+	}
+	if err := validateAssistantTools(r.Tools); err != nil {
+		return err
+	}
+	return validateMetadata(r.Metadata)
+}
+
+// ModifyAssistantRequest is the body of POST /v1/assistants/{assistant_id}.
+// Fields that are not set keep their value.
+type ModifyAssistantRequest struct {
+	// Model is the model runs use
+	Model string `json:"model,omitempty"`
+
+	// Name is the assistant's name
+	Name *string `json:"name,omitempty"`
+
+	// Description describes the assistant
+	Description *string `json:"description,omitempty"`
+
+	// Instructions are the system instructions
+	Instructions *string `json:"instructions,omitempty"`
+
+	// Tools replace the assistant's tools
+	Tools []AssistantTool `json:"tools,omitempty"`
+
+	// Metadata replaces the assistant's metadata
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate validates the modification request.
+func (r *ModifyAssistantRequest) Validate() error {
+	if err := validateAssistantName(r.Name); err != nil {
+		return err
+	}
+	if err := validateAssistantTools(r.Tools); err != nil {
+		return err
+	}
+	return validateMetadata(r.Metadata)
+}
+
+// NewAssistantID generates an assistant identifier.
+func NewAssistantID() string {
+	return generateID("asst")
+}
+
+// validateAssistantName enforces the API's name length limit.
+func validateAssistantName(name *string) error {
+	if name != nil && len(*name) > MaxAssistantNameLength {
+		param := "name"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'name': string too long. Expected a string with maximum length %d, but got a string with length %d instead.", MaxAssistantNameLength, len(*name)), &param)
+	}
+	return nil
+}
+
+// validateAssistantTools checks tool types and that function tools are
+// named uniquely.
+func validateAssistantTools(tools []AssistantTool) error {
+	if len(tools) > MaxAssistantTools {
+		param := "tools"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'tools': array too long. Expected an array with maximum length %d, but got an array with length %d instead.", MaxAssistantTools, len(tools)), &param)
+	}
+
+	seen := make(map[string]bool, len(tools))
+	for i, tool := range tools {
+		param := fmt.Sprintf("tools[%d].type", i)
+		if !slices.Contains(AssistantToolTypes, tool.Type) {
+			return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", tool.Type, quoteList(AssistantToolTypes)), &param)
+		}
+		if tool.Type != "function" {
+			continue
+		}
+
+		param = fmt.Sprintf("tools[%d].function.name", i)
+		if tool.Function == nil || tool.Function.Name == "" {
+			return NewBadRequestError("Missing required parameter: 'tools[].function.name'.", &param)
+		}
+		if seen[tool.Function.Name] {
+			return NewBadRequestError(fmt.Sprintf("Duplicate function name '%s' in 'tools'.", tool.Function.Name), &param)
+		}
+		seen[tool.Function.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFunctionNames(t *testing.T) {
+	tools := []AssistantTool{
+		{Type: "function", Function: &Function{Name: "get_weather"}},
+		{Type: "code_interpreter"},
+		{Type: "function"},
+		{Type: "function", Function: &Function{Name: "get_time"}},
+	}
+
+	if got, want := FunctionNames(tools), []string{"get_weather", "get_time"}; !slices.Equal(got, want) {
+		t.Errorf("FunctionNames() = %v, want %v", got, want)
+	}
+	if got := FunctionNames(nil); got != nil {
+		t.Errorf("FunctionNames(nil) = %v, want nil", got)
+	}
+}
+
+func TestAssistantRequestsValidate(t *testing.T) {
+	name := "Support bot"
+	longName := strings.Repeat("n", MaxAssistantNameLength+1)
+	function := func(name string) AssistantTool {
+		return AssistantTool{Type: "function", Function: &Function{Name: name}}
+	}
+	tooManyTools := make([]AssistantTool, MaxAssistantTools+1)
+	for i := range tooManyTools {
+		tooManyTools[i] = AssistantTool{Type: "file_search"}
+	}
+
+	tests := []struct {
+		name      string
+		req       interface{ Validate() error }
+		wantParam string
+	}{
+		{
+			name: "create",
+			req:  &CreateAssistantRequest{Model: "gpt-4o", Name: &name, Tools: []AssistantTool{function("a"), function("b"), {Type: "file_search"}}},
+		},
+		{name: "create without model", req: &CreateAssistantRequest{}, wantParam: "model"},
+		{name: "create name too long", req: &CreateAssistantRequest{Model: "gpt-4o", Name: &longName}, wantParam: "name"},
+		{name: "create too many tools", req: &CreateAssistantRequest{Model: "gpt-4o", Tools: tooManyTools}, wantParam: "tools"},
+		{name: "create unknown tool", req: &CreateAssistantRequest{Model: "gpt-4o", Tools: []AssistantTool{{Type: "retrieval"}}}, wantParam: "tools[0].type"},
+		{name: "create unnamed function", req: &CreateAssistantRequest{Model: "gpt-4o", Tools: []AssistantTool{{Type: "function"}}}, wantParam: "tools[0].function.name"},
+		{name: "create duplicate function", req: &CreateAssistantRequest{Model: "gpt-4o", Tools: []AssistantTool{function("a"), function("a")}}, wantParam: "tools[1].function.name"},
+		{name: "create metadata", req: &CreateAssistantRequest{Model: "gpt-4o", Metadata: metadataPairs(MaxThreadMetadataPairs + 1)}, wantParam: "metadata"},
+		{name: "modify nothing", req: &ModifyAssistantRequest{}},
+		{name: "modify name too long", req: &ModifyAssistantRequest{Name: &longName}, wantParam: "name"},
+		{name: "modify duplicate function", req: &ModifyAssistantRequest{Tools: []AssistantTool{function("a"), {Type: "code_interpreter"}, function("a")}}, wantParam: "tools[2].function.name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
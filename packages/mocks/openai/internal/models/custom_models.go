@@ -0,0 +1,187 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file implements registration of custom models (fine-tuned or
+// proprietary) from a models.yaml file.
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FineTunedPrefix starts fine-tuned model IDs ("ft:<base>:<org>:<name>:<id>").
+const FineTunedPrefix = "ft:"
+
+// CustomModel is a model defined in models.yaml. Fields left unset are
+// inherited from Base when given.
+type CustomModel struct {
+	// ID is the model identifier (e.g., "ft:gpt-4o-mini:acme:support:abc123")
+	ID string `yaml:"id"`
+
+	// Base is a registered model to inherit unset fields from (optional)
+	Base string `yaml:"base"`
+
+	// OwnedBy defaults to the organization of a fine-tuned ID, else "user"
+	OwnedBy string `yaml:"owned_by"`
+
+	// Type is chat, embedding, image, speech or realtime (default: the base model's, else chat)
+	Type ModelType `yaml:"type"`
+
+	// Context and tokenizer
+	ContextWindow   int    `yaml:"context_window"`
+	MaxOutputTokens int    `yaml:"max_output_tokens"`
+	Encoding        string `yaml:"encoding"`
+
+	// Capabilities (unset flags are inherited from Base)
+	SupportsVision          *bool `yaml:"supports_vision"`
+	SupportsFunctionCalling *bool `yaml:"supports_function_calling"`
+	SupportsJSON            *bool `yaml:"supports_json"`
+
+	// Latency profile (Go durations, e.g. "300ms")
+	BaseLatency     *time.Duration `yaml:"base_latency"`
+	PerTokenLatency *time.Duration `yaml:"per_token_latency"`
+	JitterPercent   *float64       `yaml:"jitter_percent"`
+
+	// Pricing (USD per 1 million tokens)
+	InputPer1M       *float64 `yaml:"input_per_1m"`
+	OutputPer1M      *float64 `yaml:"output_per_1m"`
+	CachedInputPer1M *float64 `yaml:"cached_input_per_1m"`
+}
+
+// ModelsFile is the format of models.yaml.
+type ModelsFile struct {
+	// Models are registered in order, so a model may use an earlier one as Base
+	Models []CustomModel `yaml:"models"`
+}
+
+// Config builds the model configuration, inheriting from Base.
+func (m CustomModel) Config() (ModelConfig, error) {
+	if m.ID == "" {
+		return ModelConfig{}, fmt.Errorf("model id is required")
+	}
+
+	var config ModelConfig
+	if m.Base != "" {
+		base, err := GetModelConfig(m.Base)
+		if err != nil {
+			return ModelConfig{}, fmt.Errorf("model %s: unknown base model %q", m.ID, m.Base)
+		}
+		config = base
+	}
+
+	config.ID = m.ID
+	config.Object = "model"
+	config.Created = time.Now().Unix()
+	config.OwnedBy = m.OwnedBy
+	if config.OwnedBy == "" {
+		config.OwnedBy = defaultOwner(m.ID)
+	}
+
+	if m.Type != "" {
+		config.Type = m.Type
+	}
+	if config.Type == "" {
+		config.Type = ModelTypeChat
+	}
+	if m.ContextWindow != 0 {
+		config.ContextWindow = m.ContextWindow
+	}
+	if m.MaxOutputTokens != 0 {
+		config.MaxOutputTokens = m.MaxOutputTokens
+	}
+	if m.Encoding != "" {
+		config.Encoding = m.Encoding
+	}
+	setCapability(&config.Capabilities, CapabilityVision, m.SupportsVision)
+	setCapability(&config.Capabilities, CapabilityTools, m.SupportsFunctionCalling)
+	setCapability(&config.Capabilities, CapabilityJSON, m.SupportsJSON)
+	setIfPresent(&config.BaseLatency, m.BaseLatency)
+	setIfPresent(&config.PerTokenLatency, m.PerTokenLatency)
+	setIfPresent(&config.JitterPercent, m.JitterPercent)
+	setIfPresent(&config.InputPer1M, m.InputPer1M)
+	setIfPresent(&config.OutputPer1M, m.OutputPer1M)
+	setIfPresent(&config.CachedInputPer1M, m.CachedInputPer1M)
+
+	if err := config.Validate(); err != nil {
+		return ModelConfig{}, fmt.Errorf("model %s: %w", m.ID, err)
+	}
+	return config, nil
+}
+
+// setIfPresent overwrites dst with *src when src is set.
+func setIfPresent[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// setCapability adds or removes a capability when the flag is set.
+func setCapability(capabilities *Capability, capability Capability, enabled *bool) {
+	if enabled == nil {
+		return
+	}
+	if *enabled {
+		*capabilities |= capability
+	} else {
+		*capabilities &^= capability
+	}
+}
+
+// defaultOwner returns the organization of a fine-tuned model ID, or "user".
+func defaultOwner(modelID string) string {
+	if parts := strings.Split(modelID, ":"); len(parts) >= 3 && parts[0]+":" == FineTunedPrefix && parts[2] != "" {
+		return parts[2]
+	}
+	return "user"
+}
+
+// fineTunedBase returns the base model of a fine-tuned ID ("" otherwise).
+func fineTunedBase(modelID string) string {
+	if !strings.HasPrefix(modelID, FineTunedPrefix) {
+		return ""
+	}
+	base, _, _ := strings.Cut(strings.TrimPrefix(modelID, FineTunedPrefix), ":")
+	return base
+}
+
+// RegisterModel adds (or replaces) a model in the global registry.
+func RegisterModel(config ModelConfig) error {
+	if err := Registry.Register(config); err != nil {
+		return fmt.Errorf("model %s: %w", config.ID, err)
+	}
+	return nil
+}
+
+// DeregisterModel removes a model from the global registry.
+// Returns false if it was not registered.
+func DeregisterModel(modelID string) bool {
+	return Registry.Deregister(modelID)
+}
+
+// LoadCustomModels registers the models defined in a models.yaml file.
+func LoadCustomModels(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file ModelsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return 0, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, model := range file.Models {
+		config, err := model.Config()
+		if err != nil {
+			return 0, err
+		}
+		if err := RegisterModel(config); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(file.Models), nil
+}
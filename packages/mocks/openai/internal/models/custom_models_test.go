@@ -0,0 +1,176 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCustomModelConfig(t *testing.T) {
+	no := false
+	yes := true
+	price := 0.5
+	latency := 250 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		model   CustomModel
+		check   func(t *testing.T, config ModelConfig)
+		wantErr bool
+	}{
+		{
+			name:  "inherits from base",
+			model: CustomModel{ID: "ft:gpt-4o-mini:acme:support:abc123", Base: "gpt-4o-mini"},
+			check: func(t *testing.T, config ModelConfig) {
+				base := builtinModelConfigs["gpt-4o-mini"]
+				if config.ContextWindow != base.ContextWindow || config.Encoding != base.Encoding || config.InputPer1M != base.InputPer1M {
+					t.Errorf("config = %+v, want gpt-4o-mini's context, encoding and pricing", config)
+				}
+				if config.OwnedBy != "acme" {
+					t.Errorf("OwnedBy = %q, want the fine-tuned organization acme", config.OwnedBy)
+				}
+			},
+		},
+		{
+			name: "overrides",
+			model: CustomModel{
+				ID:              "ft:gpt-4o-mini:acme:support:abc123",
+				Base:            "gpt-4o-mini",
+				OwnedBy:         "platform-team",
+				ContextWindow:   32000,
+				SupportsVision:  &no,
+				BaseLatency:     &latency,
+				InputPer1M:      &price,
+				MaxOutputTokens: 2048,
+			},
+			check: func(t *testing.T, config ModelConfig) {
+				if config.OwnedBy != "platform-team" || config.ContextWindow != 32000 || config.MaxOutputTokens != 2048 {
+					t.Errorf("config = %+v, want the overridden owner and limits", config)
+				}
+				if config.Capabilities.Has(CapabilityVision) || !config.Capabilities.Has(CapabilityTools) {
+					t.Errorf("Capabilities = %v, want tools without vision", config.Capabilities)
+				}
+				if config.BaseLatency != latency || config.InputPer1M != price {
+					t.Errorf("latency = %v, price = %v, want %v and %v", config.BaseLatency, config.InputPer1M, latency, price)
+				}
+			},
+		},
+		{
+			name: "without base",
+			model: CustomModel{
+				ID:                      "acme-llm",
+				ContextWindow:           16000,
+				MaxOutputTokens:         4096,
+				Encoding:                "cl100k_base",
+				SupportsFunctionCalling: &yes,
+			},
+			check: func(t *testing.T, config ModelConfig) {
+				if config.Type != ModelTypeChat || config.OwnedBy != "user" {
+					t.Errorf("Type = %q, OwnedBy = %q, want chat and user", config.Type, config.OwnedBy)
+				}
+				if config.Capabilities != CapabilityTools {
+					t.Errorf("Capabilities = %v, want tools", config.Capabilities)
+				}
+			},
+		},
+		{name: "missing id", model: CustomModel{Base: "gpt-4o"}, wantErr: true},
+		{name: "unknown base", model: CustomModel{ID: "custom", Base: "no-such-model"}, wantErr: true},
+		{name: "incomplete without base", model: CustomModel{ID: "custom"}, wantErr: true},
+		{name: "invalid type", model: CustomModel{ID: "custom", Base: "gpt-4o", Type: "audio"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.model.Config()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Config() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if config.ID != tt.model.ID || config.Object != "model" {
+				t.Errorf("ID = %q, Object = %q, want %q and model", config.ID, config.Object, tt.model.ID)
+			}
+			tt.check(t, config)
+		})
+	}
+}
+
+func TestDefaultOwner(t *testing.T) {
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{model: "ft:gpt-4o-mini:acme:support:abc123", want: "acme"},
+		{model: "ft:gpt-4o-mini:acme::abc123", want: "acme"},
+		{model: "ft:gpt-4o-mini::support:abc123", want: "user"},
+		{model: "acme-llm", want: "user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := defaultOwner(tt.model); got != tt.want {
+				t.Errorf("defaultOwner(%q) = %q, want %q", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCustomModels(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantIDs []string
+		wantErr bool
+	}{
+		{
+			name: "chained bases",
+			yaml: `models:
+  - id: ft:gpt-4o-mini:acme:support:abc123
+    base: gpt-4o-mini
+  - id: ft:gpt-4o-mini:acme:support-v2:def456
+    base: ft:gpt-4o-mini:acme:support:abc123
+    base_latency: 150ms
+`,
+			wantIDs: []string{"ft:gpt-4o-mini:acme:support:abc123", "ft:gpt-4o-mini:acme:support-v2:def456"},
+		},
+		{
+			name:    "invalid model",
+			yaml:    "models:\n  - id: custom\n    base: no-such-model\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			yaml:    "models: {",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "models.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				for _, id := range tt.wantIDs {
+					DeregisterModel(id)
+				}
+			})
+
+			n, err := LoadCustomModels(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadCustomModels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if n != len(tt.wantIDs) {
+				t.Errorf("LoadCustomModels() = %d, want %d", n, len(tt.wantIDs))
+			}
+			for _, id := range tt.wantIDs {
+				if !Registry.Has(id) {
+					t.Errorf("%s not registered", id)
+				}
+			}
+		})
+	}
+}
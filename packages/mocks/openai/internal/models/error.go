@@ -145,6 +145,41 @@ func NewModelNotFoundError(model string) APIError {
 	}
 }
 
+// NewNotFoundError creates the 404 returned for unknown resources
+// (e.g., "No thread found with id 'thread_abc'.").
+func NewNotFoundError(message string) APIError {
+	return APIError{
+		Type:       ErrorTypeBadRequest,
+		Message:    message,
+		StatusCode: 404,
+		RetryAfter: 0,
+	}
+}
+
+// ErrorCodeModelNotFound is the code returned for shut down models.
+const ErrorCodeModelNotFound = "model_not_found"
+
+// DeprecationsURL is linked from deprecated model errors.
+const DeprecationsURL = "https://platform.openai.com/docs/deprecations"
+
+// NewModelDeprecatedError creates the 404 returned once a model has been
+// shut down, matching the real API's deprecation error.
+func NewModelDeprecatedError(model, replacement string) APIError {
+	code := ErrorCodeModelNotFound
+	param := "model"
+	message := fmt.Sprintf("The model `%s` has been deprecated, learn more here: %s", model, DeprecationsURL)
+	if replacement != "" {
+		message += fmt.Sprintf(". Please use `%s` instead.", replacement)
+	}
+	return APIError{
+		Type:       ErrorTypeBadRequest,
+		Message:    message,
+		Param:      &param,
+		Code:       &code,
+		StatusCode: 404,
+	}
+}
+
 // NewContextLengthError creates a context length exceeded error.
 func NewContextLengthError(requestedTokens, maxTokens int) APIError {
 	return APIError{
@@ -159,6 +194,45 @@ func NewContextLengthError(requestedTokens, maxTokens int) APIError {
 	}
 }
 
+// ErrorCodeModelOverloaded is returned when a single model is overloaded (brownout).
+const ErrorCodeModelOverloaded = "model_overloaded"
+
+// NewModelOverloadedError creates the 503 returned when a specific model is
+// overloaded while other models stay healthy.
+func NewModelOverloadedError(model string, retryAfter int) APIError {
+	code := ErrorCodeModelOverloaded
+	return APIError{
+		Type: ErrorTypeServerError,
+		Message: fmt.Sprintf(
+			"The model `%s` is currently overloaded with other requests. You can retry your request, or contact us through our help center at help.openai.com if the error persists.",
+			model,
+		),
+		Code:       &code,
+		StatusCode: 503,
+		RetryAfter: retryAfter,
+	}
+}
+
+// ErrorCodeConcurrentStreams is returned when an API key has too many
+// streams open at once.
+const ErrorCodeConcurrentStreams = "concurrent_streams_exceeded"
+
+// NewConcurrentStreamsError creates the 429 returned when an API key already
+// has the maximum number of streaming connections open.
+func NewConcurrentStreamsError(limit int) APIError {
+	code := ErrorCodeConcurrentStreams
+	return APIError{
+		Type: ErrorTypeRateLimit,
+		Message: fmt.Sprintf(
+			"Too many concurrent streaming requests: this API key already has %d streams open, the maximum allowed. Wait for an open stream to finish and try again.",
+			limit,
+		),
+		Code:       &code,
+		StatusCode: 429,
+		RetryAfter: 1,
+	}
+}
+
 // ToJSON converts an APIError to a JSON ErrorResponse.
 func (e APIError) ToJSON() ([]byte, error) {
 	response := ErrorResponse{Error: e}
@@ -209,4 +283,46 @@ func (e APIError) Validate() error {
 		return fmt.Errorf("invalid HTTP status code: %d", e.StatusCode)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// HeaderIdempotencyKey makes retries of a POST request return the
+// original response instead of repeating it.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Error codes for requests sent with an Idempotency-Key header.
+const (
+	// ErrorCodeIdempotencyKeyInUse is returned while the first request
+	// with a key is still being processed
+	ErrorCodeIdempotencyKeyInUse = "idempotency_key_in_use"
+
+	// ErrorCodeIdempotencyKeyReused is returned when a key is reused with
+	// a different request body
+	ErrorCodeIdempotencyKeyReused = "idempotency_key_reused"
+)
+
+// NewIdempotencyKeyInUseError creates the 409 returned for a retry that
+// arrives while the original request is still in flight.
+func NewIdempotencyKeyInUseError() APIError {
+	code := ErrorCodeIdempotencyKeyInUse
+	return APIError{
+		Type:       ErrorTypeBadRequest,
+		Message:    "Another request with the same Idempotency-Key is still being processed. Retry once it has completed.",
+		Code:       &code,
+		StatusCode: 409,
+		RetryAfter: 1,
+	}
+}
+
+// NewIdempotencyKeyReusedError creates the 400 returned when an
+// Idempotency-Key is sent again with different parameters.
+func NewIdempotencyKeyReusedError() APIError {
+	code := ErrorCodeIdempotencyKeyReused
+	param := HeaderIdempotencyKey
+	return APIError{
+		Type:       ErrorTypeBadRequest,
+		Message:    "Keys for idempotent requests can only be used with the same parameters they were first used with.",
+		Param:      &param,
+		Code:       &code,
+		StatusCode: 400,
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            APIError
+		wantStatus     int
+		wantCode       string
+		wantParam      string
+		wantRetryAfter int
+		wantRetryable  bool
+		wantMessage    string
+	}{
+		{name: "not found", err: NewNotFoundError("No thread found with id 'thread_abc'."), wantStatus: 404, wantMessage: "thread_abc"},
+		{name: "deprecated", err: NewModelDeprecatedError("gpt-4-0314", ""), wantStatus: 404, wantCode: ErrorCodeModelNotFound, wantParam: "model", wantMessage: DeprecationsURL},
+		{name: "deprecated with replacement", err: NewModelDeprecatedError("gpt-4-0314", "gpt-4o"), wantStatus: 404, wantCode: ErrorCodeModelNotFound, wantParam: "model", wantMessage: "Please use `gpt-4o` instead."},
+		{name: "overloaded", err: NewModelOverloadedError("gpt-4o", 7), wantStatus: 503, wantCode: ErrorCodeModelOverloaded, wantRetryAfter: 7, wantRetryable: true, wantMessage: "`gpt-4o`"},
+		{name: "overloaded default retry", err: NewModelOverloadedError("gpt-4o", 0), wantStatus: 503, wantCode: ErrorCodeModelOverloaded, wantRetryAfter: 10, wantRetryable: true},
+		{name: "concurrent streams", err: NewConcurrentStreamsError(4), wantStatus: 429, wantCode: ErrorCodeConcurrentStreams, wantRetryAfter: 1, wantRetryable: true, wantMessage: "4 streams"},
+		{name: "idempotency key in use", err: NewIdempotencyKeyInUseError(), wantStatus: 409, wantCode: ErrorCodeIdempotencyKeyInUse},
+		{name: "idempotency key reused", err: NewIdempotencyKeyReusedError(), wantStatus: 400, wantCode: ErrorCodeIdempotencyKeyReused, wantParam: HeaderIdempotencyKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.err.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if tt.err.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", tt.err.StatusCode, tt.wantStatus)
+			}
+			param, code := errorParam(t, tt.err)
+			if code != tt.wantCode || param != tt.wantParam {
+				t.Errorf("code = %q, param = %q, want %q and %q", code, param, tt.wantCode, tt.wantParam)
+			}
+			if got := tt.err.IsRetryable(); got != tt.wantRetryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.wantRetryable)
+			}
+			if got := tt.err.GetRetryAfter(); got != tt.wantRetryAfter {
+				t.Errorf("GetRetryAfter() = %d, want %d", got, tt.wantRetryAfter)
+			}
+			if !strings.Contains(tt.err.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to contain %q", tt.err.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestAPIErrorToJSON(t *testing.T) {
+	data, err := NewModelDeprecatedError("gpt-4-0314", "gpt-4o").ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response struct {
+		Error map[string]interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error["code"] != ErrorCodeModelNotFound || response.Error["param"] != "model" {
+		t.Errorf("error = %v, want code %s and param model", response.Error, ErrorCodeModelNotFound)
+	}
+	if _, ok := response.Error["StatusCode"]; ok {
+		t.Error("StatusCode is serialized")
+	}
+}
@@ -0,0 +1,260 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines Files and Vector Stores API types.
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// File upload limits (matching the API).
+const (
+	// MaxFileBytes is the maximum size of an uploaded file (512 MB)
+	MaxFileBytes int64 = 512 << 20
+
+	// MaxBatchFileBytes is the maximum size of a batch input file (200 MB)
+	MaxBatchFileBytes int64 = 200 << 20
+)
+
+// FilePurposes are the purposes a file can be uploaded for.
+var FilePurposes = []string{"assistants", "batch", "fine-tune", "vision", "user_data", "evals"}
+
+// Vector store search limits (matching the API).
+const (
+	// DefaultVectorStoreSearchResults is the default max_num_results
+	DefaultVectorStoreSearchResults = 10
+
+	// MaxVectorStoreSearchResults is the maximum max_num_results
+	MaxVectorStoreSearchResults = 50
+)
+
+// File is an uploaded file.
+type File struct {
+	// ID is the file identifier
+	ID string `json:"id"`
+
+	// Object is always "file"
+	Object string `json:"object"`
+
+	// Bytes is the size of the file
+	Bytes int `json:"bytes"`
+
+	// CreatedAt is the Unix timestamp when the file was uploaded
+	CreatedAt int64 `json:"created_at"`
+
+	// Filename is the name the file was uploaded with
+	Filename string `json:"filename"`
+
+	// Purpose is what the file is for ("assistants", "fine-tune", ...)
+	Purpose string `json:"purpose"`
+
+	// Status is always "processed"
+	Status string `json:"status"`
+}
+
+// FileUploadRequest is the multipart form of POST /v1/files. The file
+// itself is read separately (see ValidateUpload).
+type FileUploadRequest struct {
+	// Purpose is what the file is for (see FilePurposes)
+	Purpose string `form:"purpose"`
+}
+
+// Validate validates the upload's form fields.
+func (r *FileUploadRequest) Validate() error {
+	param := "purpose"
+	if r.Purpose == "" {
+		return NewBadRequestError("Missing required parameter: 'purpose'.", &param)
+	}
+	if !slices.Contains(FilePurposes, r.Purpose) {
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", r.Purpose, quoteList(FilePurposes)), &param)
+	}
+	return nil
+}
+
+// ValidateSize checks an uploaded file's size against the purpose's limit
+// (maxBytes, or less for batch files).
+func (r *FileUploadRequest) ValidateSize(size, maxBytes int64) error {
+	param := "file"
+	if r.Purpose == "batch" {
+		maxBytes = min(maxBytes, MaxBatchFileBytes)
+	}
+	if size > maxBytes {
+		return NewBadRequestError(fmt.Sprintf("File is too large (%d bytes). The maximum size for purpose '%s' is %d bytes.", size, r.Purpose, maxBytes), &param)
+	}
+	if size == 0 {
+		return NewBadRequestError("File is empty.", &param)
+	}
+	return nil
+}
+
+// ValidateContent checks that fine-tune and batch files are JSONL: one
+// JSON object per line.
+func (r *FileUploadRequest) ValidateContent(filename string, data []byte) error {
+	param := "file"
+
+	if r.Purpose != "fine-tune" && r.Purpose != "batch" {
+		return nil
+	}
+	if !strings.HasSuffix(strings.ToLower(filename), ".jsonl") {
+		return NewBadRequestError(fmt.Sprintf("Invalid file format for purpose '%s'. Must be .jsonl", r.Purpose), &param)
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var object map[string]json.RawMessage
+		if json.Unmarshal(line, &object) != nil {
+			return NewBadRequestError(fmt.Sprintf("Invalid file format for purpose '%s'. Line %d is not a JSON object.", r.Purpose, i+1), &param)
+		}
+	}
+	return nil
+}
+
+// VectorStoreFileCounts counts a vector store's files by status.
+type VectorStoreFileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// VectorStore is a searchable collection of files.
+type VectorStore struct {
+	// ID is the vector store identifier
+	ID string `json:"id"`
+
+	// Object is always "vector_store"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the vector store was created
+	CreatedAt int64 `json:"created_at"`
+
+	// Name is the vector store's name
+	Name string `json:"name"`
+
+	// UsageBytes is the total size of the vector store's files
+	UsageBytes int `json:"usage_bytes"`
+
+	// FileCounts counts the vector store's files
+	FileCounts VectorStoreFileCounts `json:"file_counts"`
+
+	// Status is always "completed"
+	Status string `json:"status"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata"`
+}
+
+// VectorStoreFile is a file attached to a vector store.
+type VectorStoreFile struct {
+	// ID is the file identifier
+	ID string `json:"id"`
+
+	// Object is always "vector_store.file"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the file was attached
+	CreatedAt int64 `json:"created_at"`
+
+	// VectorStoreID is the vector store the file is attached to
+	VectorStoreID string `json:"vector_store_id"`
+
+	// UsageBytes is the size of the file
+	UsageBytes int `json:"usage_bytes"`
+
+	// Status is always "completed"
+	Status string `json:"status"`
+}
+
+// VectorStoreSearchRequest is the body of POST
+// /v1/vector_stores/{vector_store_id}/search.
+type VectorStoreSearchRequest struct {
+	// Query is the search query
+	Query string `json:"query"`
+
+	// MaxNumResults bounds the results (default 10, at most 50)
+	MaxNumResults *int `json:"max_num_results,omitempty"`
+}
+
+// Validate validates the search request.
+func (r *VectorStoreSearchRequest) Validate() error {
+	if strings.TrimSpace(r.Query) == "" {
+		param := "query"
+		return NewBadRequestError("Missing required parameter: 'query'.", &param)
+	}
+
+	if r.MaxNumResults != nil && (*r.MaxNumResults < 1 || *r.MaxNumResults > MaxVectorStoreSearchResults) {
+		param := "max_num_results"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'max_num_results': integer above maximum value. Expected a value between 1 and %d, but got %d instead.", MaxVectorStoreSearchResults, *r.MaxNumResults), &param)
+	}
+
+	return nil
+}
+
+// Limit returns the maximum number of results to return.
+func (r *VectorStoreSearchRequest) Limit() int {
+	if r.MaxNumResults == nil {
+		return DefaultVectorStoreSearchResults
+	}
+	return *r.MaxNumResults
+}
+
+// VectorStoreSearchContent is a matching chunk of a file.
+type VectorStoreSearchContent struct {
+	// Type is always "text"
+	Type string `json:"type"`
+
+	// Text is the chunk's text
+	Text string `json:"text"`
+}
+
+// VectorStoreSearchResult is a file matching a search.
+type VectorStoreSearchResult struct {
+	// FileID is the matching file
+	FileID string `json:"file_id"`
+
+	// Filename is the matching file's name
+	Filename string `json:"filename"`
+
+	// Score is the relevance of the match, from 0 to 1
+	Score float64 `json:"score"`
+
+	// Attributes are the file's attributes
+	Attributes map[string]string `json:"attributes"`
+
+	// Content holds the matching chunks
+	Content []VectorStoreSearchContent `json:"content"`
+}
+
+// VectorStoreSearchResponse is a page of search results.
+type VectorStoreSearchResponse struct {
+	// Object is always "vector_store.search_results.page"
+	Object string `json:"object"`
+
+	// SearchQuery is the query searched for
+	SearchQuery string `json:"search_query"`
+
+	// Data holds the results, best match first
+	Data []VectorStoreSearchResult `json:"data"`
+
+	// HasMore is always false
+	HasMore bool `json:"has_more"`
+
+	// NextPage is always null
+	NextPage *string `json:"next_page"`
+}
+
+// NewFileID generates a file identifier.
+func NewFileID() string {
+	return generateID("file")
+}
+
+// NewVectorStoreID generates a vector store identifier.
+func NewVectorStoreID() string {
+	return generateID("vs")
+}
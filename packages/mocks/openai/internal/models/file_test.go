@@ -0,0 +1,122 @@
+package models
+
+import "testing"
+
+func TestFileUploadRequestValidate(t *testing.T) {
+	tests := []struct {
+		purpose string
+		wantErr bool
+	}{
+		{purpose: "fine-tune"},
+		{purpose: "batch"},
+		{purpose: "user_data"},
+		{purpose: "", wantErr: true},
+		{purpose: "training", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purpose, func(t *testing.T) {
+			r := FileUploadRequest{Purpose: tt.purpose}
+			err := r.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if param, _ := errorParam(t, err); param != "purpose" {
+					t.Errorf("error param = %q, want purpose", param)
+				}
+			}
+		})
+	}
+}
+
+func TestFileUploadRequestValidateSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		purpose  string
+		size     int64
+		maxBytes int64
+		wantErr  bool
+	}{
+		{name: "within limit", purpose: "assistants", size: 1024, maxBytes: MaxFileBytes},
+		{name: "at limit", purpose: "assistants", size: MaxFileBytes, maxBytes: MaxFileBytes},
+		{name: "over limit", purpose: "assistants", size: MaxFileBytes + 1, maxBytes: MaxFileBytes, wantErr: true},
+		{name: "configured limit", purpose: "assistants", size: 2048, maxBytes: 1024, wantErr: true},
+		{name: "batch limit", purpose: "batch", size: MaxBatchFileBytes + 1, maxBytes: MaxFileBytes, wantErr: true},
+		{name: "batch under a lower limit", purpose: "batch", size: 2048, maxBytes: 1024, wantErr: true},
+		{name: "empty", purpose: "assistants", size: 0, maxBytes: MaxFileBytes, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := FileUploadRequest{Purpose: tt.purpose}
+			if err := r.ValidateSize(tt.size, tt.maxBytes); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSize(%d, %d) error = %v, wantErr %v", tt.size, tt.maxBytes, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileUploadRequestValidateContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		purpose  string
+		filename string
+		data     string
+		wantErr  bool
+	}{
+		{name: "jsonl", purpose: "fine-tune", filename: "train.jsonl", data: "{\"messages\": []}\n\n{\"messages\": []}\n"},
+		{name: "uppercase extension", purpose: "batch", filename: "BATCH.JSONL", data: "{}"},
+		{name: "other purposes are not checked", purpose: "assistants", filename: "notes.txt", data: "plain text"},
+		{name: "wrong extension", purpose: "fine-tune", filename: "train.json", data: "{}", wantErr: true},
+		{name: "array line", purpose: "batch", filename: "batch.jsonl", data: "{}\n[1, 2]\n", wantErr: true},
+		{name: "invalid json", purpose: "fine-tune", filename: "train.jsonl", data: "{\"messages\":", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := FileUploadRequest{Purpose: tt.purpose}
+			if err := r.ValidateContent(tt.filename, []byte(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVectorStoreSearchRequest(t *testing.T) {
+	n := func(i int) *int { return &i }
+
+	tests := []struct {
+		name      string
+		req       VectorStoreSearchRequest
+		wantLimit int
+		wantParam string
+	}{
+		{name: "default limit", req: VectorStoreSearchRequest{Query: "refund policy"}, wantLimit: DefaultVectorStoreSearchResults},
+		{name: "max results", req: VectorStoreSearchRequest{Query: "refund policy", MaxNumResults: n(MaxVectorStoreSearchResults)}, wantLimit: MaxVectorStoreSearchResults},
+		{name: "blank query", req: VectorStoreSearchRequest{Query: "  "}, wantParam: "query"},
+		{name: "zero results", req: VectorStoreSearchRequest{Query: "refund policy", MaxNumResults: n(0)}, wantParam: "max_num_results"},
+		{name: "too many results", req: VectorStoreSearchRequest{Query: "refund policy", MaxNumResults: n(MaxVectorStoreSearchResults + 1)}, wantParam: "max_num_results"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantParam != "" {
+				if err == nil {
+					t.Fatal("Validate() error = nil, want an error")
+				}
+				if param, _ := errorParam(t, err); param != tt.wantParam {
+					t.Errorf("error param = %q, want %q", param, tt.wantParam)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.req.Limit(); got != tt.wantLimit {
+				t.Errorf("Limit() = %d, want %d", got, tt.wantLimit)
+			}
+		})
+	}
+}
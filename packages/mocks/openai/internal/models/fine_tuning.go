@@ -0,0 +1,267 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines fine-tuning job, event and request types.
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxFineTuningSuffixLength is the longest suffix a fine-tuned model name may have.
+const MaxFineTuningSuffixLength = 64
+
+// MinFineTuningExamples is the fewest training examples a job accepts.
+const MinFineTuningExamples = 10
+
+// FineTunableModels are the base models fine-tuning jobs accept.
+var FineTunableModels = []string{"gpt-4o", "gpt-4o-mini", "gpt-3.5-turbo"}
+
+// FineTuningJobStatus is the lifecycle state of a fine-tuning job.
+type FineTuningJobStatus string
+
+const (
+	// FineTuningValidatingFiles means the training file is being validated
+	FineTuningValidatingFiles FineTuningJobStatus = "validating_files"
+
+	// FineTuningQueued means the job is waiting for capacity
+	FineTuningQueued FineTuningJobStatus = "queued"
+
+	// FineTuningRunning means the model is training
+	FineTuningRunning FineTuningJobStatus = "running"
+
+	// FineTuningSucceeded means the fine-tuned model is ready
+	FineTuningSucceeded FineTuningJobStatus = "succeeded"
+
+	// FineTuningFailed means the job stopped with an error
+	FineTuningFailed FineTuningJobStatus = "failed"
+
+	// FineTuningCancelled means the job was cancelled
+	FineTuningCancelled FineTuningJobStatus = "cancelled"
+)
+
+// IsTerminal returns true once a job can no longer change.
+func (s FineTuningJobStatus) IsTerminal() bool {
+	return s == FineTuningSucceeded || s == FineTuningFailed || s == FineTuningCancelled
+}
+
+// FineTuningHyperparameters are a job's training parameters. Each is "auto"
+// or a number.
+type FineTuningHyperparameters struct {
+	// NEpochs is the number of passes over the training file
+	NEpochs interface{} `json:"n_epochs,omitempty"`
+
+	// BatchSize is the number of examples per batch
+	BatchSize interface{} `json:"batch_size,omitempty"`
+
+	// LearningRateMultiplier scales the learning rate
+	LearningRateMultiplier interface{} `json:"learning_rate_multiplier,omitempty"`
+}
+
+// Epochs returns the number of epochs, resolving "auto" to autoEpochs.
+func (h FineTuningHyperparameters) Epochs(autoEpochs int) int {
+	if epochs, ok := h.NEpochs.(float64); ok && epochs >= 1 {
+		return int(epochs)
+	}
+	return autoEpochs
+}
+
+// validate checks that each parameter is "auto" or a positive number.
+func (h FineTuningHyperparameters) validate() error {
+	for _, parameter := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"n_epochs", h.NEpochs},
+		{"batch_size", h.BatchSize},
+		{"learning_rate_multiplier", h.LearningRateMultiplier},
+	} {
+		name := parameter.name
+		switch v := parameter.value.(type) {
+		case nil:
+		case string:
+			if v != "auto" {
+				param := "hyperparameters." + name
+				return NewBadRequestError(fmt.Sprintf("Invalid '%s': expected 'auto' or a number, got '%s'.", name, v), &param)
+			}
+		case float64:
+			if v <= 0 {
+				param := "hyperparameters." + name
+				return NewBadRequestError(fmt.Sprintf("Invalid '%s': must be greater than 0.", name), &param)
+			}
+		default:
+			param := "hyperparameters." + name
+			return NewBadRequestError(fmt.Sprintf("Invalid '%s': expected 'auto' or a number.", name), &param)
+		}
+	}
+	return nil
+}
+
+// FineTuningJobError explains why a job failed.
+type FineTuningJobError struct {
+	// Code is a machine-readable error code
+	Code string `json:"code"`
+
+	// Message is a human-readable description
+	Message string `json:"message"`
+
+	// Param is the request parameter at fault (if any)
+	Param *string `json:"param"`
+}
+
+// FineTuningJob is a fine-tuning job.
+type FineTuningJob struct {
+	// ID is the job identifier
+	ID string `json:"id"`
+
+	// Object is always "fine_tuning.job"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the job was created
+	CreatedAt int64 `json:"created_at"`
+
+	// FinishedAt is set once the job succeeds or fails
+	FinishedAt *int64 `json:"finished_at"`
+
+	// EstimatedFinish is when the job is expected to finish (until it does)
+	EstimatedFinish *int64 `json:"estimated_finish"`
+
+	// Model is the base model
+	Model string `json:"model"`
+
+	// FineTunedModel is the resulting model, set once the job succeeds
+	FineTunedModel *string `json:"fine_tuned_model"`
+
+	// OrganizationID owns the job
+	OrganizationID string `json:"organization_id"`
+
+	// Status is the job's lifecycle state
+	Status FineTuningJobStatus `json:"status"`
+
+	// Hyperparameters are the resolved training parameters
+	Hyperparameters FineTuningHyperparameters `json:"hyperparameters"`
+
+	// TrainingFile and ValidationFile are the uploaded file IDs
+	TrainingFile   string  `json:"training_file"`
+	ValidationFile *string `json:"validation_file"`
+
+	// ResultFiles hold training metrics (always empty in the mock)
+	ResultFiles []string `json:"result_files"`
+
+	// TrainedTokens is set once the job succeeds
+	TrainedTokens *int `json:"trained_tokens"`
+
+	// Error is set when the job failed
+	Error *FineTuningJobError `json:"error"`
+
+	// Seed makes training reproducible
+	Seed int `json:"seed"`
+
+	// Suffix is added to the fine-tuned model name
+	Suffix *string `json:"suffix,omitempty"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// FineTuningEvent is a status or metrics event of a job.
+type FineTuningEvent struct {
+	// ID is the event identifier
+	ID string `json:"id"`
+
+	// Object is always "fine_tuning.job.event"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp of the event
+	CreatedAt int64 `json:"created_at"`
+
+	// Level is "info", "warn" or "error"
+	Level string `json:"level"`
+
+	// Message describes the event
+	Message string `json:"message"`
+
+	// Type is "message", or "metrics" for training progress
+	Type string `json:"type"`
+
+	// Data holds the metrics of a metrics event
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// CreateFineTuningJobRequest is the body of POST /v1/fine_tuning/jobs.
+type CreateFineTuningJobRequest struct {
+	// Model is the base model to fine-tune
+	Model string `json:"model"`
+
+	// TrainingFile is the ID of an uploaded fine-tune file
+	TrainingFile string `json:"training_file"`
+
+	// ValidationFile is the ID of an uploaded fine-tune file (optional)
+	ValidationFile string `json:"validation_file,omitempty"`
+
+	// Suffix is added to the fine-tuned model name (up to 64 characters)
+	Suffix string `json:"suffix,omitempty"`
+
+	// Seed makes training reproducible (default: random)
+	Seed *int `json:"seed,omitempty"`
+
+	// Hyperparameters are the training parameters (default: all "auto")
+	Hyperparameters *FineTuningHyperparameters `json:"hyperparameters,omitempty"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate validates the fine-tuning job request.
+func (r *CreateFineTuningJobRequest) Validate() error {
+	if r.Model == "" {
+		param := "model"
+		return NewBadRequestError("Missing required parameter: 'model'.", &param)
+	}
+	if r.TrainingFile == "" {
+		param := "training_file"
+		return NewBadRequestError("Missing required parameter: 'training_file'.", &param)
+	}
+	if len(r.Suffix) > MaxFineTuningSuffixLength {
+		param := "suffix"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'suffix': string too long. Expected a string with maximum length %d, but got a string with length %d instead.", MaxFineTuningSuffixLength, len(r.Suffix)), &param)
+	}
+	if r.Hyperparameters != nil {
+		if err := r.Hyperparameters.validate(); err != nil {
+			return err
+		}
+	}
+	return validateMetadata(r.Metadata)
+}
+
+// ValidateModel checks that the base model can be fine-tuned. Dated
+// snapshots of fine-tunable models are accepted.
+func (r *CreateFineTuningJobRequest) ValidateModel() error {
+	configID, _ := ResolveModel(r.Model)
+	if !slices.Contains(FineTunableModels, configID) || strings.HasPrefix(r.Model, FineTunedPrefix) {
+		param := "model"
+		return NewBadRequestError(fmt.Sprintf("Model %s is not available for fine-tuning or does not exist. Fine-tunable models: %s.", r.Model, quoteList(FineTunableModels)), &param)
+	}
+	return nil
+}
+
+// FineTunedModelID builds the ID of a job's fine-tuned model
+// ("ft:<base>:<org>:<suffix>:<id>").
+func FineTunedModelID(base, organization, suffix, jobID string) string {
+	id := jobID[strings.LastIndex(jobID, "-")+1:]
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("%s%s:%s:%s:%s", FineTunedPrefix, base, organization, suffix, id)
+}
+
+// NewFineTuningJobID generates a fine-tuning job identifier.
+func NewFineTuningJobID() string {
+	return generateID("ftjob")
+}
+
+// FineTuningEventID returns the ID of a job's nth event. Events are derived
+// from the job's timeline, so their IDs are stable across reads.
+func FineTuningEventID(jobID string, n int) string {
+	return fmt.Sprintf("ftevent-%s-%d", strings.TrimPrefix(jobID, "ftjob-"), n)
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFineTuningJobStatusIsTerminal(t *testing.T) {
+	tests := []struct {
+		status FineTuningJobStatus
+		want   bool
+	}{
+		{status: FineTuningValidatingFiles, want: false},
+		{status: FineTuningQueued, want: false},
+		{status: FineTuningRunning, want: false},
+		{status: FineTuningSucceeded, want: true},
+		{status: FineTuningFailed, want: true},
+		{status: FineTuningCancelled, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsTerminal(); got != tt.want {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineTuningHyperparametersEpochs(t *testing.T) {
+	tests := []struct {
+		name    string
+		nEpochs interface{}
+		want    int
+	}{
+		{name: "unset", nEpochs: nil, want: 3},
+		{name: "auto", nEpochs: "auto", want: 3},
+		{name: "number", nEpochs: float64(5), want: 5},
+		{name: "fraction", nEpochs: 2.5, want: 2},
+		{name: "below one", nEpochs: 0.5, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := FineTuningHyperparameters{NEpochs: tt.nEpochs}
+			if got := h.Epochs(3); got != tt.want {
+				t.Errorf("Epochs(3) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateFineTuningJobRequestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       CreateFineTuningJobRequest
+		wantParam string
+	}{
+		{name: "minimal", req: CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc"}},
+		{
+			name: "hyperparameters",
+			req: CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc", Hyperparameters: &FineTuningHyperparameters{
+				NEpochs: float64(4), BatchSize: "auto", LearningRateMultiplier: 0.1,
+			}},
+		},
+		{name: "missing model", req: CreateFineTuningJobRequest{TrainingFile: "file-abc"}, wantParam: "model"},
+		{name: "missing training file", req: CreateFineTuningJobRequest{Model: "gpt-4o-mini"}, wantParam: "training_file"},
+		{name: "suffix too long", req: CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc", Suffix: strings.Repeat("s", MaxFineTuningSuffixLength+1)}, wantParam: "suffix"},
+		{
+			name:      "invalid string",
+			req:       CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc", Hyperparameters: &FineTuningHyperparameters{NEpochs: "many"}},
+			wantParam: "hyperparameters.n_epochs",
+		},
+		{
+			name:      "not positive",
+			req:       CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc", Hyperparameters: &FineTuningHyperparameters{BatchSize: float64(0)}},
+			wantParam: "hyperparameters.batch_size",
+		},
+		{
+			name:      "wrong type",
+			req:       CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc", Hyperparameters: &FineTuningHyperparameters{LearningRateMultiplier: true}},
+			wantParam: "hyperparameters.learning_rate_multiplier",
+		},
+		{
+			name:      "too much metadata",
+			req:       CreateFineTuningJobRequest{Model: "gpt-4o-mini", TrainingFile: "file-abc", Metadata: metadataPairs(MaxThreadMetadataPairs + 1)},
+			wantParam: "metadata",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestCreateFineTuningJobRequestValidateModel(t *testing.T) {
+	tests := []struct {
+		model   string
+		wantErr bool
+	}{
+		{model: "gpt-4o-mini"},
+		{model: "gpt-4o-2024-08-06"},
+		{model: "gpt-3.5-turbo"},
+		{model: "gpt-4", wantErr: true},
+		{model: "text-embedding-3-small", wantErr: true},
+		{model: "ft:gpt-4o-mini:acme::abc123", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			r := CreateFineTuningJobRequest{Model: tt.model}
+			if err := r.ValidateModel(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFineTunedModelID(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix string
+		jobID  string
+		want   string
+	}{
+		{name: "truncates the job id", suffix: "support", jobID: "ftjob-1700000000-abcdefghijklmnop", want: "ft:gpt-4o-mini:acme:support:abcdefgh"},
+		{name: "short job id", suffix: "", jobID: "ftjob-abc", want: "ft:gpt-4o-mini:acme::abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FineTunedModelID("gpt-4o-mini", "acme", tt.suffix, tt.jobID); got != tt.want {
+				t.Errorf("FineTunedModelID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineTuningEventID(t *testing.T) {
+	if got, want := FineTuningEventID("ftjob-1700000000-abc", 2), "ftevent-1700000000-abc-2"; got != want {
+		t.Errorf("FineTuningEventID() = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(NewFineTuningJobID(), "ftjob-") {
+		t.Error("NewFineTuningJobID() does not start with ftjob-")
+	}
+}
@@ -0,0 +1,347 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines image edit and variation requests and validates the
+// images and masks uploaded with them.
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register the jpeg decoder
+	_ "image/png"  // register the png decoder
+)
+
+// Uploaded image limits.
+const (
+	// MaxDallE2ImageBytes is the maximum size of a dall-e-2 image or mask
+	MaxDallE2ImageBytes = 4 << 20
+
+	// MaxGPTImageBytes is the maximum size of each gpt-image-1 input image
+	MaxGPTImageBytes = 50 << 20
+
+	// MaxGPTImageEditImages is the maximum number of gpt-image-1 input images
+	MaxGPTImageEditImages = 16
+
+	// MaxDallE2PromptChars is the maximum dall-e-2 edit prompt length
+	MaxDallE2PromptChars = 1000
+
+	// MaxGPTImagePromptChars is the maximum gpt-image-1 edit prompt length
+	MaxGPTImagePromptChars = 32000
+)
+
+// ImageFile is an uploaded image.
+type ImageFile struct {
+	// Filename is the name the client gave the file
+	Filename string
+
+	// Data is the file content
+	Data []byte
+}
+
+// Format returns "png", "jpeg" or "webp" by sniffing the file's magic
+// bytes, or "" for anything else.
+func (f ImageFile) Format() string {
+	switch {
+	case bytes.HasPrefix(f.Data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(f.Data, []byte("\xff\xd8\xff")):
+		return "jpeg"
+	case len(f.Data) >= 12 && string(f.Data[:4]) == "RIFF" && string(f.Data[8:12]) == "WEBP":
+		return "webp"
+	}
+	return ""
+}
+
+// Dimensions returns the image's width and height. ok is false for formats
+// the mock cannot decode (webp) and for corrupt files.
+func (f ImageFile) Dimensions() (width, height int, ok bool) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(f.Data))
+	if err != nil {
+		return 0, 0, false
+	}
+	return config.Width, config.Height, true
+}
+
+// ImageEditRequest is the multipart form of /v1/images/edits. The image
+// and mask files are read separately (see ValidateEditImages).
+type ImageEditRequest struct {
+	// Prompt describes the desired edit
+	Prompt string `form:"prompt"`
+
+	// Model is "dall-e-2" (default) or "gpt-image-1"
+	Model *string `form:"model"`
+
+	// N is the number of images to generate (1-10)
+	N *int `form:"n"`
+
+	// Size is the size of the generated images
+	Size *string `form:"size"`
+
+	// ResponseFormat is "url" or "b64_json" (dall-e-2 only)
+	ResponseFormat *string `form:"response_format"`
+
+	// Quality is "low", "medium", "high" or "auto" (gpt-image-1 only)
+	Quality *string `form:"quality"`
+
+	// Background is "transparent", "opaque" or "auto" (gpt-image-1 only)
+	Background *string `form:"background"`
+
+	// OutputFormat is "png", "jpeg" or "webp" (gpt-image-1 only)
+	OutputFormat *string `form:"output_format"`
+
+	// OutputCompression is the jpeg/webp compression level (gpt-image-1 only)
+	OutputCompression *int `form:"output_compression"`
+
+	// User is a unique identifier for the end-user
+	User *string `form:"user"`
+}
+
+// Generation returns the equivalent generation request, which resolves the
+// output options the same way.
+func (r *ImageEditRequest) Generation() ImageGenerationRequest {
+	model := r.GetEffectiveModel()
+	return ImageGenerationRequest{
+		Prompt:            r.Prompt,
+		Model:             &model,
+		N:                 r.N,
+		Quality:           r.Quality,
+		ResponseFormat:    r.ResponseFormat,
+		Size:              r.Size,
+		Background:        r.Background,
+		OutputFormat:      r.OutputFormat,
+		OutputCompression: r.OutputCompression,
+		User:              r.User,
+	}
+}
+
+// Validate validates the ImageEditRequest.
+func (r *ImageEditRequest) Validate() error {
+	model := r.GetEffectiveModel()
+	if model != "dall-e-2" && !IsGPTImageModel(model) {
+		return fmt.Errorf("image edits are only supported for dall-e-2 and gpt-image-1")
+	}
+
+	maxChars := MaxDallE2PromptChars
+	if IsGPTImageModel(model) {
+		maxChars = MaxGPTImagePromptChars
+	}
+	if len(r.Prompt) > maxChars {
+		return fmt.Errorf("prompt must be at most %d characters for %s", maxChars, model)
+	}
+
+	generation := r.Generation()
+	return generation.Validate()
+}
+
+// GetEffectiveModel returns the model to use (default "dall-e-2").
+func (r *ImageEditRequest) GetEffectiveModel() string {
+	if r.Model != nil {
+		return *r.Model
+	}
+	return "dall-e-2"
+}
+
+// ValidateModel checks the resolved model generates images.
+func (r *ImageEditRequest) ValidateModel(config ModelConfig) error {
+	return requireModelType(config, r.GetEffectiveModel(), ModelTypeImage, "/v1/images/edits")
+}
+
+// ImageVariationRequest is the multipart form of /v1/images/variations.
+// The image file is read separately (see ValidateVariationImage).
+type ImageVariationRequest struct {
+	// Model is the model to use (only "dall-e-2")
+	Model *string `form:"model"`
+
+	// N is the number of images to generate (1-10)
+	N *int `form:"n"`
+
+	// Size is the size of the generated images
+	Size *string `form:"size"`
+
+	// ResponseFormat is "url" or "b64_json"
+	ResponseFormat *string `form:"response_format"`
+
+	// User is a unique identifier for the end-user
+	User *string `form:"user"`
+}
+
+// Generation returns the equivalent generation request for prompt.
+func (r *ImageVariationRequest) Generation(prompt string) ImageGenerationRequest {
+	model := r.GetEffectiveModel()
+	return ImageGenerationRequest{
+		Prompt:         prompt,
+		Model:          &model,
+		N:              r.N,
+		ResponseFormat: r.ResponseFormat,
+		Size:           r.Size,
+		User:           r.User,
+	}
+}
+
+// Validate validates the ImageVariationRequest.
+func (r *ImageVariationRequest) Validate() error {
+	if model := r.GetEffectiveModel(); model != "dall-e-2" {
+		return fmt.Errorf("image variations are only supported for dall-e-2")
+	}
+
+	generation := r.Generation("variation")
+	return generation.Validate()
+}
+
+// GetEffectiveModel returns the model to use (default "dall-e-2").
+func (r *ImageVariationRequest) GetEffectiveModel() string {
+	if r.Model != nil {
+		return *r.Model
+	}
+	return "dall-e-2"
+}
+
+// ValidateModel checks the resolved model generates images.
+func (r *ImageVariationRequest) ValidateModel(config ModelConfig) error {
+	return requireModelType(config, r.GetEffectiveModel(), ModelTypeImage, "/v1/images/variations")
+}
+
+// ValidateEditImages checks the uploaded images and mask of an edit.
+// dall-e-2 takes one square PNG under 4 MB; without a mask, its
+// transparent areas mark what to edit. gpt-image-1 takes up to 16 PNG,
+// JPEG or WebP images under 50 MB each. A mask must be a PNG with an alpha
+// channel and the same dimensions as the (first) image.
+func ValidateEditImages(model string, images []ImageFile, mask *ImageFile) error {
+	param := "image"
+	if len(images) == 0 {
+		return NewBadRequestError("Missing required parameter: 'image'.", &param)
+	}
+
+	if IsGPTImageModel(model) {
+		if len(images) > MaxGPTImageEditImages {
+			return NewBadRequestError(fmt.Sprintf("Too many images: expected at most %d, got %d.", MaxGPTImageEditImages, len(images)), &param)
+		}
+		for _, img := range images {
+			if img.Format() == "" {
+				return NewBadRequestError(fmt.Sprintf("Invalid image file '%s': unsupported format. Supported formats are: 'png', 'jpeg' and 'webp'.", img.Filename), &param)
+			}
+			if len(img.Data) > MaxGPTImageBytes {
+				return NewBadRequestError(fmt.Sprintf("Invalid image file '%s': image must be less than 50 MB.", img.Filename), &param)
+			}
+		}
+	} else {
+		if len(images) > 1 {
+			return NewBadRequestError(fmt.Sprintf("%s accepts a single image.", model), &param)
+		}
+		if err := validateDallE2Image(images[0], param); err != nil {
+			return err
+		}
+		if mask == nil && !hasTransparency(images[0].decode()) {
+			return NewBadRequestError("Invalid input image - image must have transparent areas to edit when no mask is provided.", &param)
+		}
+	}
+
+	if mask == nil {
+		return nil
+	}
+
+	param = "mask"
+	if mask.Format() != "png" {
+		return NewBadRequestError("Invalid mask image - mask must be a PNG.", &param)
+	}
+	if len(mask.Data) > MaxDallE2ImageBytes {
+		return NewBadRequestError("Invalid mask image - mask must be less than 4 MB.", &param)
+	}
+	if !hasAlphaChannel(mask.decode()) {
+		return NewBadRequestError("Invalid mask image - mask must have an alpha channel.", &param)
+	}
+
+	maskWidth, maskHeight, _ := mask.Dimensions()
+	if width, height, ok := images[0].Dimensions(); ok && (width != maskWidth || height != maskHeight) {
+		return NewBadRequestError(fmt.Sprintf("Invalid mask image - mask size (%dx%d) must match image size (%dx%d).", maskWidth, maskHeight, width, height), &param)
+	}
+
+	return nil
+}
+
+// ValidateVariationImage checks the uploaded image of a variation: one
+// square PNG under 4 MB.
+func ValidateVariationImage(img *ImageFile) error {
+	param := "image"
+	if img == nil {
+		return NewBadRequestError("Missing required parameter: 'image'.", &param)
+	}
+	return validateDallE2Image(*img, param)
+}
+
+// validateDallE2Image checks a dall-e-2 input image is a square PNG under 4 MB.
+func validateDallE2Image(img ImageFile, param string) error {
+	if img.Format() != "png" {
+		return NewBadRequestError(fmt.Sprintf("Invalid image file '%s': image must be a PNG.", img.Filename), &param)
+	}
+	if len(img.Data) > MaxDallE2ImageBytes {
+		return NewBadRequestError(fmt.Sprintf("Invalid image file '%s': image must be less than 4 MB.", img.Filename), &param)
+	}
+
+	width, height, ok := img.Dimensions()
+	if !ok {
+		return NewBadRequestError(fmt.Sprintf("Invalid image file '%s': the image could not be decoded.", img.Filename), &param)
+	}
+	if width != height {
+		return NewBadRequestError(fmt.Sprintf("Invalid image file '%s': image must be square (got %dx%d).", img.Filename, width, height), &param)
+	}
+
+	return nil
+}
+
+// decode decodes the image, returning nil if it cannot be decoded.
+func (f ImageFile) decode() image.Image {
+	img, _, err := image.Decode(bytes.NewReader(f.Data))
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// hasAlphaChannel reports whether the image's color model carries alpha.
+// The png decoder returns *image.RGBA and *image.RGBA64 only for truecolor
+// files without an alpha channel, so those do not count.
+func hasAlphaChannel(img image.Image) bool {
+	switch img := img.(type) {
+	case *image.NRGBA, *image.NRGBA64, *image.Alpha, *image.Alpha16:
+		return true
+	case *image.Paletted:
+		for _, c := range img.Palette {
+			if _, _, _, a := c.RGBA(); a < 0xffff {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTransparency reports whether any pixel of the image is fully transparent.
+func hasTransparency(img image.Image) bool {
+	if !hasAlphaChannel(img) {
+		return false
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EditInputImageTokens estimates the input image tokens of an edit's
+// images (webp images, which cannot be decoded, count as 1024x1024).
+func EditInputImageTokens(images []ImageFile) int {
+	tokens := 0
+	for _, img := range images {
+		width, height, ok := img.Dimensions()
+		if !ok {
+			width, height = 1024, 1024
+		}
+		tokens += ImageInputTokens(width, height)
+	}
+	return tokens
+}
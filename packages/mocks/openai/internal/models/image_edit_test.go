@@ -0,0 +1,219 @@
+package models
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testPNG encodes a width x height PNG filled with alpha a. A fully opaque
+// image is written without an alpha channel.
+func testPNG(t *testing.T, width, height int, a uint8) ImageFile {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, A: a})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return ImageFile{Filename: "image.png", Data: buf.Bytes()}
+}
+
+func testJPEG(t *testing.T, width, height int) ImageFile {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return ImageFile{Filename: "image.jpg", Data: buf.Bytes()}
+}
+
+var testWebP = ImageFile{Filename: "image.webp", Data: []byte("RIFF\x00\x00\x00\x00WEBPVP8 ")}
+
+func TestImageFileFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       ImageFile
+		wantFormat string
+		wantWidth  int
+		wantOK     bool
+	}{
+		{name: "png", file: testPNG(t, 32, 16, 255), wantFormat: "png", wantWidth: 32, wantOK: true},
+		{name: "jpeg", file: testJPEG(t, 24, 24), wantFormat: "jpeg", wantWidth: 24, wantOK: true},
+		{name: "webp", file: testWebP, wantFormat: "webp"},
+		{name: "gif", file: ImageFile{Data: []byte("GIF89a")}},
+		{name: "empty", file: ImageFile{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.file.Format(); got != tt.wantFormat {
+				t.Errorf("Format() = %q, want %q", got, tt.wantFormat)
+			}
+			width, _, ok := tt.file.Dimensions()
+			if width != tt.wantWidth || ok != tt.wantOK {
+				t.Errorf("Dimensions() width = %d, ok = %v, want %d and %v", width, ok, tt.wantWidth, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestImageEditRequestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	n := func(i int) *int { return &i }
+
+	tests := []struct {
+		name    string
+		req     ImageEditRequest
+		wantErr bool
+	}{
+		{name: "dall-e-2 default", req: ImageEditRequest{Prompt: "Add a hat"}},
+		{name: "gpt-image-1 options", req: ImageEditRequest{Prompt: "Add a hat", Model: str("gpt-image-1"), Quality: str("low"), Background: str("transparent"), OutputFormat: str("webp")}},
+		{name: "dall-e-3", req: ImageEditRequest{Prompt: "Add a hat", Model: str("dall-e-3")}, wantErr: true},
+		{name: "missing prompt", req: ImageEditRequest{}, wantErr: true},
+		{name: "dall-e-2 prompt too long", req: ImageEditRequest{Prompt: string(make([]byte, MaxDallE2PromptChars+1))}, wantErr: true},
+		{name: "gpt-image-1 long prompt", req: ImageEditRequest{Prompt: string(make([]byte, MaxDallE2PromptChars+1)), Model: str("gpt-image-1")}},
+		{name: "too many images", req: ImageEditRequest{Prompt: "Add a hat", N: n(11)}, wantErr: true},
+		{name: "dall-e-2 quality", req: ImageEditRequest{Prompt: "Add a hat", Quality: str("low")}, wantErr: true},
+		{name: "gpt-image-1 response format", req: ImageEditRequest{Prompt: "Add a hat", Model: str("gpt-image-1"), ResponseFormat: str("url")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageVariationRequestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		req     ImageVariationRequest
+		wantErr bool
+	}{
+		{name: "default", req: ImageVariationRequest{}},
+		{name: "options", req: ImageVariationRequest{Size: str("256x256"), ResponseFormat: str("b64_json")}},
+		{name: "gpt-image-1", req: ImageVariationRequest{Model: str("gpt-image-1")}, wantErr: true},
+		{name: "invalid size", req: ImageVariationRequest{Size: str("1792x1024")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEditImages(t *testing.T) {
+	transparent := testPNG(t, 16, 16, 0)
+	opaque := testPNG(t, 16, 16, 255)
+	mask := testPNG(t, 16, 16, 128)
+	smallMask := testPNG(t, 8, 8, 128)
+	jpegMask := testJPEG(t, 16, 16)
+	gptImages := make([]ImageFile, MaxGPTImageEditImages+1)
+	for i := range gptImages {
+		gptImages[i] = opaque
+	}
+
+	tests := []struct {
+		name      string
+		model     string
+		images    []ImageFile
+		mask      *ImageFile
+		wantParam string
+	}{
+		{name: "dall-e-2 transparent image", model: "dall-e-2", images: []ImageFile{transparent}},
+		{name: "dall-e-2 with mask", model: "dall-e-2", images: []ImageFile{opaque}, mask: &mask},
+		{name: "gpt-image-1 formats", model: "gpt-image-1", images: []ImageFile{opaque, testJPEG(t, 8, 8), testWebP}},
+		{name: "gpt-image-1 with mask", model: "gpt-image-1", images: []ImageFile{opaque}, mask: &mask},
+		{name: "no images", model: "dall-e-2", wantParam: "image"},
+		{name: "dall-e-2 two images", model: "dall-e-2", images: []ImageFile{transparent, transparent}, wantParam: "image"},
+		{name: "dall-e-2 jpeg", model: "dall-e-2", images: []ImageFile{testJPEG(t, 8, 8)}, wantParam: "image"},
+		{name: "dall-e-2 not square", model: "dall-e-2", images: []ImageFile{testPNG(t, 16, 8, 0)}, wantParam: "image"},
+		{name: "dall-e-2 opaque without mask", model: "dall-e-2", images: []ImageFile{opaque}, wantParam: "image"},
+		{name: "dall-e-2 too large", model: "dall-e-2", images: []ImageFile{{Data: append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, MaxDallE2ImageBytes)...)}}, wantParam: "image"},
+		{name: "gpt-image-1 too many images", model: "gpt-image-1", images: gptImages, wantParam: "image"},
+		{name: "gpt-image-1 gif", model: "gpt-image-1", images: []ImageFile{{Data: []byte("GIF89a")}}, wantParam: "image"},
+		{name: "jpeg mask", model: "gpt-image-1", images: []ImageFile{opaque}, mask: &jpegMask, wantParam: "mask"},
+		{name: "mask without alpha", model: "dall-e-2", images: []ImageFile{transparent}, mask: &opaque, wantParam: "mask"},
+		{name: "mask size mismatch", model: "dall-e-2", images: []ImageFile{transparent}, mask: &smallMask, wantParam: "mask"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEditImages(tt.model, tt.images, tt.mask)
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("ValidateEditImages() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateEditImages() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestValidateVariationImage(t *testing.T) {
+	square := testPNG(t, 16, 16, 255)
+
+	tests := []struct {
+		name    string
+		img     *ImageFile
+		wantErr bool
+	}{
+		{name: "square png", img: &square},
+		{name: "missing", img: nil, wantErr: true},
+		{name: "webp", img: &testWebP, wantErr: true},
+		{name: "corrupt png", img: &ImageFile{Data: []byte("\x89PNG\r\n\x1a\nbroken")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateVariationImage(tt.img); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateVariationImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEditInputImageTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		images []ImageFile
+		want   int
+	}{
+		{name: "none", want: 0},
+		{name: "small png", images: []ImageFile{testPNG(t, 16, 16, 255)}, want: 85 + 170},
+		{name: "webp counts as 1024x1024", images: []ImageFile{testWebP}, want: 85 + 170*4},
+		{name: "sum", images: []ImageFile{testPNG(t, 16, 16, 255), testWebP}, want: 85 + 170 + 85 + 170*4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EditInputImageTokens(tt.images); got != tt.want {
+				t.Errorf("EditInputImageTokens() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines request size limits matching OpenAI's production API.
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Request limits enforced by OpenAI's production API.
+const (
+	// MaxRequestBodyBytes is the maximum accepted request body size (32 MiB)
+	MaxRequestBodyBytes int64 = 32 << 20
+
+	// MaxChatMessages is the maximum number of messages in a chat completion request
+	MaxChatMessages = 2048
+
+	// MaxChatTools is the maximum number of tools in a chat completion request
+	MaxChatTools = 128
+
+	// MaxChatFunctions is the maximum number of (legacy) functions in a chat completion request
+	MaxChatFunctions = 128
+
+	// MaxStopSequences is the maximum number of stop sequences
+	MaxStopSequences = 4
+
+	// MaxEmbeddingInputs is the maximum number of inputs in an embedding request
+	MaxEmbeddingInputs = 2048
+
+	// DefaultRequestTimeout is the server-side request timeout
+	DefaultRequestTimeout = 10 * time.Minute
+)
+
+// Error codes for limit violations.
+const (
+	// ErrorCodeArrayTooLong is returned when an array parameter exceeds its maximum length
+	ErrorCodeArrayTooLong = "array_above_max_length"
+
+	// ErrorCodeRequestTooLarge is returned when the request body exceeds the size limit
+	ErrorCodeRequestTooLarge = "request_too_large"
+
+	// ErrorCodeTimeout is returned when the server aborts a request that ran too long
+	ErrorCodeTimeout = "request_timeout"
+)
+
+// NewArrayTooLongError creates an error for an array parameter above its maximum length.
+func NewArrayTooLongError(param string, maxLength, length int) APIError {
+	code := ErrorCodeArrayTooLong
+	return APIError{
+		Type: ErrorTypeBadRequest,
+		Message: fmt.Sprintf(
+			"Invalid '%s': array too long. Expected an array with maximum length %d, but got an array with length %d instead.",
+			param,
+			maxLength,
+			length,
+		),
+		Param:      &param,
+		Code:       &code,
+		StatusCode: 400,
+		RetryAfter: 0,
+	}
+}
+
+// NewRequestTooLargeError creates an error for a request body above the size limit.
+func NewRequestTooLargeError(maxBytes int64) APIError {
+	code := ErrorCodeRequestTooLarge
+	return APIError{
+		Type:       ErrorTypeBadRequest,
+		Message:    fmt.Sprintf("Request body too large. The maximum request size is %d bytes.", maxBytes),
+		Code:       &code,
+		StatusCode: 413,
+		RetryAfter: 0,
+	}
+}
+
+// NewTimeoutError creates an error for a request aborted by the server timeout.
+func NewTimeoutError(timeout time.Duration) APIError {
+	code := ErrorCodeTimeout
+	return APIError{
+		Type:       ErrorTypeTimeout,
+		Message:    fmt.Sprintf("Request timed out after %s.", timeout),
+		Code:       &code,
+		StatusCode: 504,
+		RetryAfter: 0,
+	}
+}
+
+// NewDeadlineExceededError creates an error for a request cancelled because
+// the test run's deadline passed; level is the timeout that fired (e.g.
+// "scenario").
+func NewDeadlineExceededError(level string) APIError {
+	code := ErrorCodeTimeout
+	return APIError{
+		Type:       ErrorTypeTimeout,
+		Message:    fmt.Sprintf("Request cancelled: the test run's %s timeout expired.", level),
+		Code:       &code,
+		StatusCode: 504,
+		RetryAfter: 0,
+	}
+}
+
+// ValidateLimits checks array parameters against production maximum lengths.
+func (r *ChatCompletionRequest) ValidateLimits() error {
+	if len(r.Messages) > MaxChatMessages {
+		return NewArrayTooLongError("messages", MaxChatMessages, len(r.Messages))
+	}
+
+	if len(r.Tools) > MaxChatTools {
+		return NewArrayTooLongError("tools", MaxChatTools, len(r.Tools))
+	}
+
+	if len(r.Functions) > MaxChatFunctions {
+		return NewArrayTooLongError("functions", MaxChatFunctions, len(r.Functions))
+	}
+
+	if n := arrayLength(r.Stop); n > MaxStopSequences {
+		return NewArrayTooLongError("stop", MaxStopSequences, n)
+	}
+
+	return nil
+}
+
+// ValidateLimits checks the number of inputs against the production maximum.
+func (r *EmbeddingRequest) ValidateLimits() error {
+	if n := arrayLength(r.Input); n > MaxEmbeddingInputs {
+		return NewArrayTooLongError("input", MaxEmbeddingInputs, n)
+	}
+
+	return nil
+}
+
+// arrayLength returns the length of a string-or-array parameter.
+// Returns 0 for non-array values.
+func arrayLength(v interface{}) int {
+	switch arr := v.(type) {
+	case []interface{}:
+		return len(arr)
+	case []string:
+		return len(arr)
+	default:
+		return 0
+	}
+}
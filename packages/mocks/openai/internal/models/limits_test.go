@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// errorParam returns the param and code of an APIError ("" for none).
+func errorParam(t *testing.T, err error) (param, code string) {
+	t.Helper()
+
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is %T, want APIError", err, err)
+	}
+	if apiErr.Param != nil {
+		param = *apiErr.Param
+	}
+	if apiErr.Code != nil {
+		code = *apiErr.Code
+	}
+	return param, code
+}
+
+func TestChatCompletionRequestValidateLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       ChatCompletionRequest
+		wantParam string
+	}{
+		{
+			name: "within limits",
+			req: ChatCompletionRequest{
+				Messages: make([]Message, MaxChatMessages),
+				Tools:    make([]Tool, MaxChatTools),
+				Stop:     []interface{}{"a", "b", "c", "d"},
+			},
+		},
+		{
+			name:      "too many messages",
+			req:       ChatCompletionRequest{Messages: make([]Message, MaxChatMessages+1)},
+			wantParam: "messages",
+		},
+		{
+			name:      "too many tools",
+			req:       ChatCompletionRequest{Tools: make([]Tool, MaxChatTools+1)},
+			wantParam: "tools",
+		},
+		{
+			name:      "too many functions",
+			req:       ChatCompletionRequest{Functions: make([]Function, MaxChatFunctions+1)},
+			wantParam: "functions",
+		},
+		{
+			name:      "too many stop sequences",
+			req:       ChatCompletionRequest{Stop: []string{"a", "b", "c", "d", "e"}},
+			wantParam: "stop",
+		},
+		{
+			name: "single stop string",
+			req:  ChatCompletionRequest{Stop: "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.ValidateLimits()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("ValidateLimits() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateLimits() error = nil, want an error")
+			}
+			if param, code := errorParam(t, err); param != tt.wantParam || code != ErrorCodeArrayTooLong {
+				t.Errorf("error param = %q, code = %q, want %q, %q", param, code, tt.wantParam, ErrorCodeArrayTooLong)
+			}
+		})
+	}
+}
+
+func TestEmbeddingRequestValidateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		wantErr bool
+	}{
+		{name: "string", input: "hello"},
+		{name: "at the limit", input: make([]string, MaxEmbeddingInputs)},
+		{name: "above the limit", input: make([]interface{}, MaxEmbeddingInputs+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := EmbeddingRequest{Input: tt.input}
+			if err := req.ValidateLimits(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimitErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         APIError
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:        "array too long",
+			err:         NewArrayTooLongError("tools", 128, 130),
+			wantStatus:  400,
+			wantCode:    ErrorCodeArrayTooLong,
+			wantMessage: "maximum length 128, but got an array with length 130",
+		},
+		{
+			name:        "request too large",
+			err:         NewRequestTooLargeError(MaxRequestBodyBytes),
+			wantStatus:  413,
+			wantCode:    ErrorCodeRequestTooLarge,
+			wantMessage: "33554432 bytes",
+		},
+		{
+			name:        "timeout",
+			err:         NewTimeoutError(DefaultRequestTimeout),
+			wantStatus:  504,
+			wantCode:    ErrorCodeTimeout,
+			wantMessage: "10m0s",
+		},
+		{
+			name:        "deadline exceeded",
+			err:         NewDeadlineExceededError("scenario"),
+			wantStatus:  504,
+			wantCode:    ErrorCodeTimeout,
+			wantMessage: "scenario timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", tt.err.StatusCode, tt.wantStatus)
+			}
+			if _, code := errorParam(t, tt.err); code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", code, tt.wantCode)
+			}
+			if !strings.Contains(tt.err.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to contain %q", tt.err.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines model aliases and their dated snapshots, and scheduled
+// model deprecations.
+package models
+
+import (
+	"time"
+)
+
+// ModelAliases maps alias model IDs to the dated snapshot they currently
+// point to. Responses report the snapshot, as the real API does.
+var ModelAliases = map[string]string{
+	"gpt-4o":        "gpt-4o-2024-08-06",
+	"gpt-4o-mini":   "gpt-4o-mini-2024-07-18",
+	"gpt-4-turbo":   "gpt-4-turbo-2024-04-09",
+	"gpt-4":         "gpt-4-0613",
+	"gpt-3.5-turbo": "gpt-3.5-turbo-0125",
+}
+
+// ModelSnapshots maps dated snapshot IDs to the alias whose configuration
+// they use. Pinning a snapshot behaves like the alias.
+var ModelSnapshots = map[string]string{
+	"gpt-4o-2024-05-13":      "gpt-4o",
+	"gpt-4o-2024-08-06":      "gpt-4o",
+	"gpt-4o-2024-11-20":      "gpt-4o",
+	"gpt-4o-mini-2024-07-18": "gpt-4o-mini",
+	"gpt-4-turbo-2024-04-09": "gpt-4-turbo",
+	"gpt-4-0613":             "gpt-4",
+	"gpt-4-0314":             "gpt-4",
+	"gpt-3.5-turbo-0125":     "gpt-3.5-turbo",
+	"gpt-3.5-turbo-1106":     "gpt-3.5-turbo",
+}
+
+// ResolveModel returns the registry ID whose configuration a requested model
+// uses and the model ID to report in responses (the dated snapshot for an
+// alias). Fine-tuned IDs that were not registered use their base model's
+// configuration. Unknown models resolve to themselves.
+func ResolveModel(modelID string) (configID, responseID string) {
+	if snapshot, ok := ModelAliases[modelID]; ok {
+		return modelID, snapshot
+	}
+	if Registry.Has(modelID) {
+		return modelID, modelID
+	}
+	if alias, ok := ModelSnapshots[modelID]; ok {
+		return alias, modelID
+	}
+	if base := fineTunedBase(modelID); base != "" {
+		baseConfigID, _ := ResolveModel(base)
+		if Registry.Has(baseConfigID) {
+			return baseConfigID, modelID
+		}
+	}
+	return modelID, modelID
+}
+
+// ModelDeprecation schedules the shutdown of a model or snapshot.
+type ModelDeprecation struct {
+	// Model is the alias or snapshot ID being deprecated. Deprecating an
+	// alias also deprecates its snapshots.
+	Model string `json:"model" yaml:"model"`
+
+	// ShutdownDate is when requests start failing with model_not_found
+	// (compared against the virtual clock)
+	ShutdownDate time.Time `json:"shutdown_date" yaml:"shutdown_date"`
+
+	// Replacement is the recommended model (optional)
+	Replacement string `json:"replacement,omitempty" yaml:"replacement"`
+}
+
+// IsShutDown returns true once the shutdown date has passed.
+func (d ModelDeprecation) IsShutDown(now time.Time) bool {
+	return !now.Before(d.ShutdownDate)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		model        string
+		wantConfig   string
+		wantResponse string
+	}{
+		{model: "gpt-4o", wantConfig: "gpt-4o", wantResponse: "gpt-4o-2024-08-06"},
+		{model: "gpt-3.5-turbo", wantConfig: "gpt-3.5-turbo", wantResponse: "gpt-3.5-turbo-0125"},
+		{model: "gpt-4o-2024-05-13", wantConfig: "gpt-4o", wantResponse: "gpt-4o-2024-05-13"},
+		{model: "gpt-4-0314", wantConfig: "gpt-4", wantResponse: "gpt-4-0314"},
+		{model: "ft:gpt-4o-mini:acme::abc123", wantConfig: "gpt-4o-mini", wantResponse: "ft:gpt-4o-mini:acme::abc123"},
+		{model: "ft:gpt-4o-2024-08-06:acme::abc123", wantConfig: "gpt-4o", wantResponse: "ft:gpt-4o-2024-08-06:acme::abc123"},
+		{model: "ft:no-such-base:acme::abc123", wantConfig: "ft:no-such-base:acme::abc123", wantResponse: "ft:no-such-base:acme::abc123"},
+		{model: "no-such-model", wantConfig: "no-such-model", wantResponse: "no-such-model"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			config, response := ResolveModel(tt.model)
+			if config != tt.wantConfig || response != tt.wantResponse {
+				t.Errorf("ResolveModel(%q) = (%q, %q), want (%q, %q)", tt.model, config, response, tt.wantConfig, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestModelAliasesPointToSnapshots(t *testing.T) {
+	for alias, snapshot := range ModelAliases {
+		if got := ModelSnapshots[snapshot]; got != alias {
+			t.Errorf("alias %s points to %s, whose alias is %q", alias, snapshot, got)
+		}
+	}
+}
+
+func TestModelDeprecationIsShutDown(t *testing.T) {
+	shutdown := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	d := ModelDeprecation{Model: "gpt-4-0314", ShutdownDate: shutdown}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "before", now: shutdown.Add(-time.Second), want: false},
+		{name: "at", now: shutdown, want: true},
+		{name: "after", now: shutdown.Add(time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.IsShutDown(tt.now); got != tt.want {
+				t.Errorf("IsShutDown(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
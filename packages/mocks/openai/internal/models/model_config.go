@@ -31,14 +31,11 @@ type ModelConfig struct {
 	// Encoding is the tokenizer encoding to use (e.g., "cl100k_base", "o200k_base")
 	Encoding string
 
-	// SupportsVision indicates if the model supports vision inputs
-	SupportsVision bool
+	// Type is the API surface the model serves (chat, embedding, image, speech, realtime)
+	Type ModelType
 
-	// SupportsFunctionCalling indicates if the model supports function calling
-	SupportsFunctionCalling bool
-
-	// SupportsJSON indicates if the model supports JSON mode
-	SupportsJSON bool
+	// Capabilities are the optional features the model supports
+	Capabilities Capability
 
 	// Latency profile (for production-realistic simulation)
 	BaseLatency     time.Duration // Time To First Token (TTFT)
@@ -59,7 +56,10 @@ func (c ModelConfig) Validate() error {
 	if c.ContextWindow <= 0 {
 		return fmt.Errorf("context window must be positive")
 	}
-	if c.MaxOutputTokens <= 0 {
+	if !c.Type.IsValid() {
+		return fmt.Errorf("invalid model type %q (must be chat, embedding, image, speech or realtime)", c.Type)
+	}
+	if c.Type == ModelTypeChat && c.MaxOutputTokens <= 0 {
 		return fmt.Errorf("max output tokens must be positive")
 	}
 	if c.Encoding == "" {
@@ -98,271 +98,333 @@ func (c ModelConfig) IsValidContextLength(inputTokens, outputTokens int) bool {
 	return inputTokens+outputTokens <= c.ContextWindow
 }
 
-// ModelConfigs is the global registry of model configurations.
+// builtinModelConfigs are the models registered by default.
 // This matches OpenAI's actual models as of November 2025.
-var ModelConfigs = map[string]ModelConfig{
+var builtinModelConfigs = map[string]ModelConfig{
 	"gpt-4o": {
-		ID:                      "gpt-4o",
-		Object:                  "model",
-		Created:                 1715367049,
-		OwnedBy:                 "openai",
-		ContextWindow:           128000,
-		MaxOutputTokens:         16384,
-		Encoding:                "o200k_base",
-		SupportsVision:          true,
-		SupportsFunctionCalling: true,
-		SupportsJSON:            true,
-		BaseLatency:             500 * time.Millisecond,
-		PerTokenLatency:         20 * time.Millisecond,
-		JitterPercent:           0.25,
-		InputPer1M:              2.50,
-		OutputPer1M:             10.00,
-		CachedInputPer1M:        1.25,
+		ID:               "gpt-4o",
+		Object:           "model",
+		Created:          1715367049,
+		OwnedBy:          "openai",
+		ContextWindow:    128000,
+		MaxOutputTokens:  16384,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeChat,
+		Capabilities:     CapabilityVision | CapabilityTools | CapabilityJSON,
+		BaseLatency:      500 * time.Millisecond,
+		PerTokenLatency:  20 * time.Millisecond,
+		JitterPercent:    0.25,
+		InputPer1M:       2.50,
+		OutputPer1M:      10.00,
+		CachedInputPer1M: 1.25,
 	},
 	"gpt-4o-mini": {
-		ID:                      "gpt-4o-mini",
-		Object:                  "model",
-		Created:                 1721172717,
-		OwnedBy:                 "openai",
-		ContextWindow:           128000,
-		MaxOutputTokens:         16384,
-		Encoding:                "o200k_base",
-		SupportsVision:          true,
-		SupportsFunctionCalling: true,
-		SupportsJSON:            true,
-		BaseLatency:             200 * time.Millisecond,
-		PerTokenLatency:         34 * time.Millisecond,
-		JitterPercent:           0.20,
-		InputPer1M:              0.15,
-		OutputPer1M:             0.60,
-		CachedInputPer1M:        0.075,
+		ID:               "gpt-4o-mini",
+		Object:           "model",
+		Created:          1721172717,
+		OwnedBy:          "openai",
+		ContextWindow:    128000,
+		MaxOutputTokens:  16384,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeChat,
+		Capabilities:     CapabilityVision | CapabilityTools | CapabilityJSON,
+		BaseLatency:      200 * time.Millisecond,
+		PerTokenLatency:  34 * time.Millisecond,
+		JitterPercent:    0.20,
+		InputPer1M:       0.15,
+		OutputPer1M:      0.60,
+		CachedInputPer1M: 0.075,
 	},
 	"gpt-4-turbo": {
-		ID:                      "gpt-4-turbo",
-		Object:                  "model",
-		Created:                 1712361441,
-		OwnedBy:                 "openai",
-		ContextWindow:           128000,
-		MaxOutputTokens:         4096,
-		Encoding:                "cl100k_base",
-		SupportsVision:          true,
-		SupportsFunctionCalling: true,
-		SupportsJSON:            true,
-		BaseLatency:             800 * time.Millisecond,
-		PerTokenLatency:         50 * time.Millisecond,
-		JitterPercent:           0.30,
-		InputPer1M:              10.00,
-		OutputPer1M:             30.00,
-		CachedInputPer1M:        5.00,
+		ID:               "gpt-4-turbo",
+		Object:           "model",
+		Created:          1712361441,
+		OwnedBy:          "openai",
+		ContextWindow:    128000,
+		MaxOutputTokens:  4096,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeChat,
+		Capabilities:     CapabilityVision | CapabilityTools | CapabilityJSON,
+		BaseLatency:      800 * time.Millisecond,
+		PerTokenLatency:  50 * time.Millisecond,
+		JitterPercent:    0.30,
+		InputPer1M:       10.00,
+		OutputPer1M:      30.00,
+		CachedInputPer1M: 5.00,
 	},
 	"gpt-4": {
-		ID:                      "gpt-4",
-		Object:                  "model",
-		Created:                 1687882411,
-		OwnedBy:                 "openai",
-		ContextWindow:           8192,
-		MaxOutputTokens:         4096,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: true,
-		SupportsJSON:            false,
-		BaseLatency:             800 * time.Millisecond,
-		PerTokenLatency:         196 * time.Millisecond,
-		JitterPercent:           0.30,
-		InputPer1M:              30.00,
-		OutputPer1M:             60.00,
-		CachedInputPer1M:        0,
+		ID:               "gpt-4",
+		Object:           "model",
+		Created:          1687882411,
+		OwnedBy:          "openai",
+		ContextWindow:    8192,
+		MaxOutputTokens:  4096,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeChat,
+		Capabilities:     CapabilityTools,
+		BaseLatency:      800 * time.Millisecond,
+		PerTokenLatency:  196 * time.Millisecond,
+		JitterPercent:    0.30,
+		InputPer1M:       30.00,
+		OutputPer1M:      60.00,
+		CachedInputPer1M: 0,
 	},
 	"gpt-3.5-turbo": {
-		ID:                      "gpt-3.5-turbo",
-		Object:                  "model",
-		Created:                 1677610602,
-		OwnedBy:                 "openai",
-		ContextWindow:           16385,
-		MaxOutputTokens:         4096,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: true,
-		SupportsJSON:            true,
-		BaseLatency:             300 * time.Millisecond,
-		PerTokenLatency:         73 * time.Millisecond,
-		JitterPercent:           0.20,
-		InputPer1M:              0.50,
-		OutputPer1M:             1.50,
-		CachedInputPer1M:        0,
+		ID:               "gpt-3.5-turbo",
+		Object:           "model",
+		Created:          1677610602,
+		OwnedBy:          "openai",
+		ContextWindow:    16385,
+		MaxOutputTokens:  4096,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeChat,
+		Capabilities:     CapabilityTools | CapabilityJSON,
+		BaseLatency:      300 * time.Millisecond,
+		PerTokenLatency:  73 * time.Millisecond,
+		JitterPercent:    0.20,
+		InputPer1M:       0.50,
+		OutputPer1M:      1.50,
+		CachedInputPer1M: 0,
 	},
 	"gpt-3.5-turbo-16k": {
-		ID:                      "gpt-3.5-turbo-16k",
-		Object:                  "model",
-		Created:                 1683758102,
-		OwnedBy:                 "openai",
-		ContextWindow:           16385,
-		MaxOutputTokens:         4096,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: true,
-		SupportsJSON:            true,
-		BaseLatency:             300 * time.Millisecond,
-		PerTokenLatency:         73 * time.Millisecond,
-		JitterPercent:           0.20,
-		InputPer1M:              3.00,
-		OutputPer1M:             4.00,
-		CachedInputPer1M:        0,
+		ID:               "gpt-3.5-turbo-16k",
+		Object:           "model",
+		Created:          1683758102,
+		OwnedBy:          "openai",
+		ContextWindow:    16385,
+		MaxOutputTokens:  4096,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeChat,
+		Capabilities:     CapabilityTools | CapabilityJSON,
+		BaseLatency:      300 * time.Millisecond,
+		PerTokenLatency:  73 * time.Millisecond,
+		JitterPercent:    0.20,
+		InputPer1M:       3.00,
+		OutputPer1M:      4.00,
+		CachedInputPer1M: 0,
 	},
 	"text-embedding-3-small": {
-		ID:                      "text-embedding-3-small",
-		Object:                  "model",
-		Created:                 1705953180,
-		OwnedBy:                 "openai",
-		ContextWindow:           8191,
-		MaxOutputTokens:         0, // Embeddings don't generate text
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: false,
-		SupportsJSON:            false,
-		BaseLatency:             100 * time.Millisecond,
-		PerTokenLatency:         0,
-		JitterPercent:           0.15,
-		InputPer1M:              0.02,
-		OutputPer1M:             0,
-		CachedInputPer1M:        0,
+		ID:               "text-embedding-3-small",
+		Object:           "model",
+		Created:          1705953180,
+		OwnedBy:          "openai",
+		ContextWindow:    8191,
+		MaxOutputTokens:  0, // Embeddings don't generate text
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeEmbedding,
+		BaseLatency:      100 * time.Millisecond,
+		PerTokenLatency:  0,
+		JitterPercent:    0.15,
+		InputPer1M:       0.02,
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
 	},
 	"text-embedding-3-large": {
-		ID:                      "text-embedding-3-large",
-		Object:                  "model",
-		Created:                 1705953180,
-		OwnedBy:                 "openai",
-		ContextWindow:           8191,
-		MaxOutputTokens:         0,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: false,
-		SupportsJSON:            false,
-		BaseLatency:             200 * time.Millisecond,
-		PerTokenLatency:         0,
-		JitterPercent:           0.15,
-		InputPer1M:              0.13,
-		OutputPer1M:             0,
-		CachedInputPer1M:        0,
+		ID:               "text-embedding-3-large",
+		Object:           "model",
+		Created:          1705953180,
+		OwnedBy:          "openai",
+		ContextWindow:    8191,
+		MaxOutputTokens:  0,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeEmbedding,
+		BaseLatency:      200 * time.Millisecond,
+		PerTokenLatency:  0,
+		JitterPercent:    0.15,
+		InputPer1M:       0.13,
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
 	},
 	"text-embedding-ada-002": {
-		ID:                      "text-embedding-ada-002",
-		Object:                  "model",
-		Created:                 1671217299,
-		OwnedBy:                 "openai",
-		ContextWindow:           8191,
-		MaxOutputTokens:         0,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: false,
-		SupportsJSON:            false,
-		BaseLatency:             150 * time.Millisecond,
-		PerTokenLatency:         0,
-		JitterPercent:           0.15,
-		InputPer1M:              0.10,
-		OutputPer1M:             0,
-		CachedInputPer1M:        0,
+		ID:               "text-embedding-ada-002",
+		Object:           "model",
+		Created:          1671217299,
+		OwnedBy:          "openai",
+		ContextWindow:    8191,
+		MaxOutputTokens:  0,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeEmbedding,
+		BaseLatency:      150 * time.Millisecond,
+		PerTokenLatency:  0,
+		JitterPercent:    0.15,
+		InputPer1M:       0.10,
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
 	},
 	"dall-e-3": {
-		ID:                      "dall-e-3",
-		Object:                  "model",
-		Created:                 1698785189,
-		OwnedBy:                 "openai",
-		ContextWindow:           4000, // Prompt token limit
-		MaxOutputTokens:         0,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: false,
-		SupportsJSON:            false,
-		BaseLatency:             15 * time.Second, // Images take longer
-		PerTokenLatency:         0,
-		JitterPercent:           0.20,
-		InputPer1M:              0,    // Priced per image, not tokens
-		OutputPer1M:             0,
-		CachedInputPer1M:        0,
+		ID:               "dall-e-3",
+		Object:           "model",
+		Created:          1698785189,
+		OwnedBy:          "openai",
+		ContextWindow:    4000, // Prompt token limit
+		MaxOutputTokens:  0,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeImage,
+		BaseLatency:      15 * time.Second, // Images take longer
+		PerTokenLatency:  0,
+		JitterPercent:    0.20,
+		InputPer1M:       0, // Priced per image, not tokens
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
+	},
+	"gpt-image-1": {
+		ID:               "gpt-image-1",
+		Object:           "model",
+		Created:          1745517030,
+		OwnedBy:          "system",
+		ContextWindow:    32000,
+		MaxOutputTokens:  0,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeImage,
+		BaseLatency:      25 * time.Second, // Slower than DALL-E, scales with quality
+		PerTokenLatency:  0,
+		JitterPercent:    0.25,
+		InputPer1M:       5.00,  // Text prompt tokens
+		OutputPer1M:      40.00, // Image output tokens (see ImageOutputTokens)
+		CachedInputPer1M: 1.25,
 	},
 	"dall-e-2": {
-		ID:                      "dall-e-2",
-		Object:                  "model",
-		Created:                 1698785189,
-		OwnedBy:                 "openai",
-		ContextWindow:           1000,
-		MaxOutputTokens:         0,
-		Encoding:                "cl100k_base",
-		SupportsVision:          false,
-		SupportsFunctionCalling: false,
-		SupportsJSON:            false,
-		BaseLatency:             10 * time.Second,
-		PerTokenLatency:         0,
-		JitterPercent:           0.20,
-		InputPer1M:              0,
-		OutputPer1M:             0,
-		CachedInputPer1M:        0,
+		ID:               "dall-e-2",
+		Object:           "model",
+		Created:          1698785189,
+		OwnedBy:          "openai",
+		ContextWindow:    1000,
+		MaxOutputTokens:  0,
+		Encoding:         "cl100k_base",
+		Type:             ModelTypeImage,
+		BaseLatency:      10 * time.Second,
+		PerTokenLatency:  0,
+		JitterPercent:    0.20,
+		InputPer1M:       0,
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
+	},
+	"tts-1": {
+		ID:               "tts-1",
+		Object:           "model",
+		Created:          1681940951,
+		OwnedBy:          "openai-internal",
+		ContextWindow:    MaxSpeechInputChars, // Input character limit
+		MaxOutputTokens:  0,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeSpeech,
+		BaseLatency:      500 * time.Millisecond, // Per request, then per input character
+		PerTokenLatency:  1 * time.Millisecond,
+		JitterPercent:    0.20,
+		InputPer1M:       0, // Priced per character, not tokens
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
+	},
+	"tts-1-hd": {
+		ID:               "tts-1-hd",
+		Object:           "model",
+		Created:          1699046015,
+		OwnedBy:          "system",
+		ContextWindow:    MaxSpeechInputChars,
+		MaxOutputTokens:  0,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeSpeech,
+		BaseLatency:      900 * time.Millisecond, // Higher quality, slower
+		PerTokenLatency:  2 * time.Millisecond,
+		JitterPercent:    0.20,
+		InputPer1M:       0,
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
+	},
+	"gpt-4o-mini-tts": {
+		ID:               "gpt-4o-mini-tts",
+		Object:           "model",
+		Created:          1742403959,
+		OwnedBy:          "system",
+		ContextWindow:    MaxSpeechInputChars,
+		MaxOutputTokens:  0,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeSpeech,
+		BaseLatency:      700 * time.Millisecond,
+		PerTokenLatency:  1500 * time.Microsecond,
+		JitterPercent:    0.25,
+		InputPer1M:       0,
+		OutputPer1M:      0,
+		CachedInputPer1M: 0,
+	},
+	"gpt-4o-realtime-preview": {
+		ID:               "gpt-4o-realtime-preview",
+		Object:           "model",
+		Created:          1727659998,
+		OwnedBy:          "system",
+		ContextWindow:    128000,
+		MaxOutputTokens:  4096,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeRealtime,
+		BaseLatency:      450 * time.Millisecond, // Time to the first response delta
+		PerTokenLatency:  25 * time.Millisecond,
+		JitterPercent:    0.25,
+		InputPer1M:       5.00, // Text tokens
+		OutputPer1M:      20.00,
+		CachedInputPer1M: 2.50,
+	},
+	"gpt-4o-mini-realtime-preview": {
+		ID:               "gpt-4o-mini-realtime-preview",
+		Object:           "model",
+		Created:          1734387380,
+		OwnedBy:          "system",
+		ContextWindow:    128000,
+		MaxOutputTokens:  4096,
+		Encoding:         "o200k_base",
+		Type:             ModelTypeRealtime,
+		BaseLatency:      350 * time.Millisecond,
+		PerTokenLatency:  18 * time.Millisecond,
+		JitterPercent:    0.25,
+		InputPer1M:       0.60,
+		OutputPer1M:      2.40,
+		CachedInputPer1M: 0.30,
 	},
 }
 
-// GetModelConfig retrieves a model configuration by ID.
+// GetModelConfig retrieves a model configuration by ID. Dated snapshot IDs
+// (see ModelSnapshots) resolve to their alias's configuration.
 // Returns an error if the model doesn't exist.
 func GetModelConfig(modelID string) (ModelConfig, error) {
-	config, ok := ModelConfigs[modelID]
+	configID, _ := ResolveModel(modelID)
+	config, ok := Registry.Get(configID)
 	if !ok {
 		return ModelConfig{}, fmt.Errorf("model '%s' not found", modelID)
 	}
 	return config, nil
 }
 
-// GetAllModelConfigs returns a slice of all model configurations.
+// GetAllModelConfigs returns all registered model configurations, sorted by ID.
 func GetAllModelConfigs() []ModelConfig {
-	configs := make([]ModelConfig, 0, len(ModelConfigs))
-	for _, config := range ModelConfigs {
-		configs = append(configs, config)
-	}
-	return configs
+	return Registry.All()
 }
 
-// IsModelSupported checks if a model ID is supported.
+// IsModelSupported checks if a model ID (or dated snapshot) is supported.
 func IsModelSupported(modelID string) bool {
-	_, ok := ModelConfigs[modelID]
-	return ok
+	configID, _ := ResolveModel(modelID)
+	return Registry.Has(configID)
 }
 
 // GetChatModels returns only chat completion models.
 func GetChatModels() []ModelConfig {
-	var chatModels []ModelConfig
-	for _, config := range ModelConfigs {
-		if config.MaxOutputTokens > 0 && config.Encoding != "" {
-			// Chat models have output tokens and use tokenizers
-			if config.ID != "text-embedding-3-small" &&
-				config.ID != "text-embedding-3-large" &&
-				config.ID != "text-embedding-ada-002" &&
-				config.ID != "dall-e-3" &&
-				config.ID != "dall-e-2" {
-				chatModels = append(chatModels, config)
-			}
-		}
-	}
-	return chatModels
+	return Registry.ByType(ModelTypeChat)
 }
 
 // GetEmbeddingModels returns only embedding models.
 func GetEmbeddingModels() []ModelConfig {
-	var embeddingModels []ModelConfig
-	for _, config := range ModelConfigs {
-		if config.MaxOutputTokens == 0 && config.InputPer1M > 0 {
-			// Embedding models have no output tokens but have input pricing
-			embeddingModels = append(embeddingModels, config)
-		}
-	}
-	return embeddingModels
+	return Registry.ByType(ModelTypeEmbedding)
 }
 
 // GetImageModels returns only image generation models.
 func GetImageModels() []ModelConfig {
-	var imageModels []ModelConfig
-	for id, config := range ModelConfigs {
-		if id == "dall-e-3" || id == "dall-e-2" {
-			imageModels = append(imageModels, config)
-		}
-	}
-	return imageModels
-}
\ No newline at end of file
+	return Registry.ByType(ModelTypeImage)
+}
+
+// GetSpeechModels returns only text-to-speech models.
+func GetSpeechModels() []ModelConfig {
+	return Registry.ByType(ModelTypeSpeech)
+}
+
+// GetModelsWithCapability returns chat models supporting a capability.
+func GetModelsWithCapability(capability Capability) []ModelConfig {
+	return Registry.Query(ModelQuery{Type: ModelTypeChat, Capabilities: capability})
+}
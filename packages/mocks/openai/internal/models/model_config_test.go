@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+)
+
+// modelIDs returns the IDs of model configurations.
+func modelIDs(configs []ModelConfig) map[string]bool {
+	ids := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		ids[config.ID] = true
+	}
+	return ids
+}
+
+func TestModelConfigsValidate(t *testing.T) {
+	for _, config := range GetAllModelConfigs() {
+		t.Run(config.ID, func(t *testing.T) {
+			if err := config.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGetModelConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "model", model: "gpt-4o-mini", wantID: "gpt-4o-mini"},
+		{name: "dated snapshot", model: "gpt-4o-2024-08-06", wantID: "gpt-4o"},
+		{name: "unknown model", model: "no-such-model", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := GetModelConfig(tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetModelConfig(%q) error = %v, wantErr %v", tt.model, err, tt.wantErr)
+			}
+			if config.ID != tt.wantID {
+				t.Errorf("GetModelConfig(%q) = %s, want %s", tt.model, config.ID, tt.wantID)
+			}
+			if supported := IsModelSupported(tt.model); supported == tt.wantErr {
+				t.Errorf("IsModelSupported(%q) = %v, want %v", tt.model, supported, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModelsByType(t *testing.T) {
+	tests := []struct {
+		name     string
+		models   []ModelConfig
+		wantType ModelType
+		want     []string
+	}{
+		{name: "chat", models: GetChatModels(), wantType: ModelTypeChat, want: []string{"gpt-4o", "gpt-3.5-turbo"}},
+		{name: "embedding", models: GetEmbeddingModels(), wantType: ModelTypeEmbedding, want: []string{"text-embedding-3-small"}},
+		{name: "image", models: GetImageModels(), wantType: ModelTypeImage, want: []string{"dall-e-3", "gpt-image-1"}},
+		{name: "speech", models: GetSpeechModels(), wantType: ModelTypeSpeech, want: []string{"tts-1", "tts-1-hd", "gpt-4o-mini-tts"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, config := range tt.models {
+				if config.Type != tt.wantType {
+					t.Errorf("%s has type %q, want %q", config.ID, config.Type, tt.wantType)
+				}
+			}
+			ids := modelIDs(tt.models)
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("%s models are missing %s", tt.name, id)
+				}
+			}
+		})
+	}
+}
+
+func TestGetModelsWithCapability(t *testing.T) {
+	tests := []struct {
+		name       string
+		capability Capability
+		want       []string
+		wantAbsent []string
+	}{
+		{name: "vision", capability: CapabilityVision, want: []string{"gpt-4o", "gpt-4-turbo"}, wantAbsent: []string{"gpt-4", "gpt-3.5-turbo"}},
+		{name: "tools", capability: CapabilityTools, want: []string{"gpt-4", "gpt-3.5-turbo"}, wantAbsent: []string{"text-embedding-3-small"}},
+		{name: "vision and JSON", capability: CapabilityVision | CapabilityJSON, want: []string{"gpt-4o-mini"}, wantAbsent: []string{"gpt-4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := modelIDs(GetModelsWithCapability(tt.capability))
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("GetModelsWithCapability(%v) is missing %s", tt.capability, id)
+				}
+			}
+			for _, id := range tt.wantAbsent {
+				if ids[id] {
+					t.Errorf("GetModelsWithCapability(%v) includes %s", tt.capability, id)
+				}
+			}
+		})
+	}
+}
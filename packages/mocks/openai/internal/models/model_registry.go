@@ -0,0 +1,193 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines model types and capabilities, and the thread-safe
+// registry that model lookups and capability queries go through.
+package models
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ModelType is the API surface a model serves.
+type ModelType string
+
+const (
+	// ModelTypeChat models serve /v1/chat/completions and /v1/completions
+	ModelTypeChat ModelType = "chat"
+
+	// ModelTypeEmbedding models serve /v1/embeddings
+	ModelTypeEmbedding ModelType = "embedding"
+
+	// ModelTypeImage models serve /v1/images
+	ModelTypeImage ModelType = "image"
+
+	// ModelTypeSpeech models serve /v1/audio/speech
+	ModelTypeSpeech ModelType = "speech"
+
+	// ModelTypeRealtime models serve the /v1/realtime WebSocket API
+	ModelTypeRealtime ModelType = "realtime"
+)
+
+// IsValid returns true for a known model type.
+func (t ModelType) IsValid() bool {
+	switch t {
+	case ModelTypeChat, ModelTypeEmbedding, ModelTypeImage, ModelTypeSpeech, ModelTypeRealtime:
+		return true
+	}
+	return false
+}
+
+// Capability is an optional model feature. Capabilities combine with |
+// into a set (e.g., CapabilityVision | CapabilityTools).
+type Capability uint8
+
+const (
+	// CapabilityVision accepts image inputs
+	CapabilityVision Capability = 1 << iota
+
+	// CapabilityTools supports tool and function calling
+	CapabilityTools
+
+	// CapabilityJSON supports JSON mode (response_format json_object)
+	CapabilityJSON
+)
+
+// capabilityNames are the names used by String.
+var capabilityNames = []struct {
+	capability Capability
+	name       string
+}{
+	{CapabilityVision, "vision"},
+	{CapabilityTools, "tools"},
+	{CapabilityJSON, "json"},
+}
+
+// ParseCapability parses a capability name ("vision", "tools", "json").
+func ParseCapability(name string) (Capability, bool) {
+	for _, entry := range capabilityNames {
+		if entry.name == name {
+			return entry.capability, true
+		}
+	}
+	return 0, false
+}
+
+// Has returns true if every capability in other is present.
+func (c Capability) Has(other Capability) bool {
+	return c&other == other
+}
+
+// String lists the capabilities (e.g., "vision,tools").
+func (c Capability) String() string {
+	var names []string
+	for _, entry := range capabilityNames {
+		if c.Has(entry.capability) {
+			names = append(names, entry.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ModelQuery filters registered models. Zero fields match every model.
+type ModelQuery struct {
+	// Type restricts results to one model type
+	Type ModelType
+
+	// Capabilities must all be supported
+	Capabilities Capability
+}
+
+// Matches returns true if a model satisfies the query.
+func (q ModelQuery) Matches(config ModelConfig) bool {
+	if q.Type != "" && config.Type != q.Type {
+		return false
+	}
+	return config.Capabilities.Has(q.Capabilities)
+}
+
+// ModelRegistry holds model configurations. It is safe for concurrent use,
+// so models can be registered while requests are being served.
+type ModelRegistry struct {
+	// configs maps model IDs to configurations
+	configs map[string]ModelConfig
+
+	// mu protects configs
+	mu sync.RWMutex
+}
+
+// NewModelRegistry creates a registry holding the given models.
+func NewModelRegistry(configs map[string]ModelConfig) *ModelRegistry {
+	r := &ModelRegistry{configs: make(map[string]ModelConfig, len(configs))}
+	for id, config := range configs {
+		r.configs[id] = config
+	}
+	return r
+}
+
+// Registry is the global model registry, initialized with the built-in models.
+var Registry = NewModelRegistry(builtinModelConfigs)
+
+// Register adds or replaces a model after validating it.
+func (r *ModelRegistry) Register(config ModelConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[config.ID] = config
+	return nil
+}
+
+// Deregister removes a model. Returns false if it was not registered.
+func (r *ModelRegistry) Deregister(modelID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.configs[modelID]; !ok {
+		return false
+	}
+	delete(r.configs, modelID)
+	return true
+}
+
+// Get returns a model's configuration by exact ID.
+func (r *ModelRegistry) Get(modelID string) (ModelConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config, ok := r.configs[modelID]
+	return config, ok
+}
+
+// Has returns true if a model is registered under the exact ID.
+func (r *ModelRegistry) Has(modelID string) bool {
+	_, ok := r.Get(modelID)
+	return ok
+}
+
+// All returns every registered model, sorted by ID.
+func (r *ModelRegistry) All() []ModelConfig {
+	return r.Query(ModelQuery{})
+}
+
+// ByType returns the models of one type, sorted by ID.
+func (r *ModelRegistry) ByType(modelType ModelType) []ModelConfig {
+	return r.Query(ModelQuery{Type: modelType})
+}
+
+// Query returns the models matching a query, sorted by ID.
+func (r *ModelRegistry) Query(query ModelQuery) []ModelConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []ModelConfig
+	for _, config := range r.configs {
+		if query.Matches(config) {
+			result = append(result, config)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
@@ -0,0 +1,145 @@
+package models
+
+import "testing"
+
+func TestCapability(t *testing.T) {
+	tests := []struct {
+		name       string
+		capability Capability
+		other      Capability
+		wantHas    bool
+		wantString string
+	}{
+		{name: "none", capability: 0, other: 0, wantHas: true, wantString: ""},
+		{name: "single", capability: CapabilityVision, other: CapabilityVision, wantHas: true, wantString: "vision"},
+		{name: "subset", capability: CapabilityVision | CapabilityTools | CapabilityJSON, other: CapabilityTools | CapabilityJSON, wantHas: true, wantString: "vision,tools,json"},
+		{name: "missing", capability: CapabilityTools, other: CapabilityVision | CapabilityTools, wantHas: false, wantString: "tools"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.capability.Has(tt.other); got != tt.wantHas {
+				t.Errorf("Has(%v) = %v, want %v", tt.other, got, tt.wantHas)
+			}
+			if got := tt.capability.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestParseCapability(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Capability
+		wantOK bool
+	}{
+		{name: "vision", want: CapabilityVision, wantOK: true},
+		{name: "tools", want: CapabilityTools, wantOK: true},
+		{name: "json", want: CapabilityJSON, wantOK: true},
+		{name: "audio", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseCapability(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseCapability(%q) = (%v, %v), want (%v, %v)", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestModelRegistryQuery(t *testing.T) {
+	chat := func(id string, capabilities Capability) ModelConfig {
+		return ModelConfig{ID: id, Type: ModelTypeChat, ContextWindow: 8192, MaxOutputTokens: 1024, Encoding: "cl100k_base", Capabilities: capabilities}
+	}
+	r := NewModelRegistry(map[string]ModelConfig{
+		"b-vision": chat("b-vision", CapabilityVision|CapabilityTools),
+		"a-tools":  chat("a-tools", CapabilityTools),
+		"embed":    {ID: "embed", Type: ModelTypeEmbedding, ContextWindow: 8191, Encoding: "cl100k_base"},
+	})
+
+	tests := []struct {
+		name  string
+		query ModelQuery
+		want  []string
+	}{
+		{name: "all, sorted", query: ModelQuery{}, want: []string{"a-tools", "b-vision", "embed"}},
+		{name: "by type", query: ModelQuery{Type: ModelTypeChat}, want: []string{"a-tools", "b-vision"}},
+		{name: "by capability", query: ModelQuery{Capabilities: CapabilityTools}, want: []string{"a-tools", "b-vision"}},
+		{name: "all capabilities required", query: ModelQuery{Capabilities: CapabilityVision | CapabilityTools}, want: []string{"b-vision"}},
+		{name: "no match", query: ModelQuery{Type: ModelTypeImage}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, config := range r.Query(tt.query) {
+				got = append(got, config.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Query() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestModelRegistryRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ModelConfig
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			config: ModelConfig{ID: "custom", Type: ModelTypeChat, ContextWindow: 8192, MaxOutputTokens: 1024, Encoding: "cl100k_base"},
+		},
+		{
+			name:    "invalid type",
+			config:  ModelConfig{ID: "custom", Type: "audio", ContextWindow: 8192, Encoding: "cl100k_base"},
+			wantErr: true,
+		},
+		{
+			name:    "missing encoding",
+			config:  ModelConfig{ID: "custom", Type: ModelTypeEmbedding, ContextWindow: 8192},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewModelRegistry(nil)
+			err := r.Register(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if r.Has(tt.config.ID) == tt.wantErr {
+				t.Errorf("Has(%q) = %v after Register", tt.config.ID, r.Has(tt.config.ID))
+			}
+		})
+	}
+}
+
+func TestModelRegistryDeregister(t *testing.T) {
+	r := NewModelRegistry(map[string]ModelConfig{"gpt-4o": builtinModelConfigs["gpt-4o"]})
+
+	if !r.Deregister("gpt-4o") {
+		t.Error("Deregister(gpt-4o) = false, want true")
+	}
+	if r.Has("gpt-4o") {
+		t.Error("gpt-4o still registered after Deregister")
+	}
+	if r.Deregister("gpt-4o") {
+		t.Error("second Deregister(gpt-4o) = true, want false")
+	}
+	if !Registry.Has("gpt-4o") {
+		t.Error("Deregister on a copy removed gpt-4o from the global registry")
+	}
+}
@@ -0,0 +1,190 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines response types for the usage and costs APIs.
+package models
+
+// Object names used by the usage and costs APIs.
+const (
+	// ObjectPage is a paginated list of buckets
+	ObjectPage = "page"
+
+	// ObjectBucket is a time bucket of results
+	ObjectBucket = "bucket"
+
+	// ObjectCompletionsUsageResult is one result in a completions usage bucket
+	ObjectCompletionsUsageResult = "organization.usage.completions.result"
+
+	// ObjectCostsResult is one result in a costs bucket
+	ObjectCostsResult = "organization.costs.result"
+)
+
+// CompletionsUsagePage is the response of GET /v1/organization/usage/completions.
+type CompletionsUsagePage struct {
+	// Object is always "page"
+	Object string `json:"object"`
+
+	// Data is the list of time buckets
+	Data []CompletionsUsageBucket `json:"data"`
+
+	// HasMore is true if more buckets are available
+	HasMore bool `json:"has_more"`
+
+	// NextPage is the cursor for the next page (pass as ?page=)
+	NextPage *string `json:"next_page"`
+}
+
+// CompletionsUsageBucket is completions usage within one time bucket.
+type CompletionsUsageBucket struct {
+	// Object is always "bucket"
+	Object string `json:"object"`
+
+	// StartTime is the bucket start (Unix seconds, inclusive)
+	StartTime int64 `json:"start_time"`
+
+	// EndTime is the bucket end (Unix seconds, exclusive)
+	EndTime int64 `json:"end_time"`
+
+	// Results are the usage results, one per group
+	Results []CompletionsUsageResult `json:"results"`
+}
+
+// CompletionsUsageResult is aggregated completions usage for one group.
+// Group fields are null unless grouped by them.
+type CompletionsUsageResult struct {
+	// Object is always "organization.usage.completions.result"
+	Object string `json:"object"`
+
+	// InputTokens is the number of input tokens (including cached)
+	InputTokens int64 `json:"input_tokens"`
+
+	// OutputTokens is the number of output tokens
+	OutputTokens int64 `json:"output_tokens"`
+
+	// InputCachedTokens is the number of cached input tokens
+	InputCachedTokens int64 `json:"input_cached_tokens"`
+
+	// InputAudioTokens is the number of audio input tokens
+	InputAudioTokens int64 `json:"input_audio_tokens"`
+
+	// OutputAudioTokens is the number of audio output tokens
+	OutputAudioTokens int64 `json:"output_audio_tokens"`
+
+	// NumModelRequests is the number of requests
+	NumModelRequests int64 `json:"num_model_requests"`
+
+	// ProjectID is set when grouped by project_id
+	ProjectID *string `json:"project_id"`
+
+	// UserID is set when grouped by user_id (not tracked by the mock)
+	UserID *string `json:"user_id"`
+
+	// APIKeyID is set when grouped by api_key_id
+	APIKeyID *string `json:"api_key_id"`
+
+	// Model is set when grouped by model
+	Model *string `json:"model"`
+
+	// Batch is set when grouped by batch
+	Batch *bool `json:"batch"`
+}
+
+// CostsPage is the response of GET /v1/organization/costs.
+type CostsPage struct {
+	// Object is always "page"
+	Object string `json:"object"`
+
+	// Data is the list of time buckets
+	Data []CostsBucket `json:"data"`
+
+	// HasMore is true if more buckets are available
+	HasMore bool `json:"has_more"`
+
+	// NextPage is the cursor for the next page (pass as ?page=)
+	NextPage *string `json:"next_page"`
+}
+
+// CostsBucket is costs within one time bucket.
+type CostsBucket struct {
+	// Object is always "bucket"
+	Object string `json:"object"`
+
+	// StartTime is the bucket start (Unix seconds, inclusive)
+	StartTime int64 `json:"start_time"`
+
+	// EndTime is the bucket end (Unix seconds, exclusive)
+	EndTime int64 `json:"end_time"`
+
+	// Results are the cost results, one per group
+	Results []CostsResult `json:"results"`
+}
+
+// CostsResult is aggregated cost for one group.
+type CostsResult struct {
+	// Object is always "organization.costs.result"
+	Object string `json:"object"`
+
+	// Amount is the cost
+	Amount CostAmount `json:"amount"`
+
+	// LineItem is set when grouped by line_item (e.g., "gpt-4o, input")
+	LineItem *string `json:"line_item"`
+
+	// ProjectID is set when grouped by project_id
+	ProjectID *string `json:"project_id"`
+}
+
+// CostAmount is a monetary amount.
+type CostAmount struct {
+	// Value is the amount
+	Value float64 `json:"value"`
+
+	// Currency is always "usd"
+	Currency string `json:"currency"`
+}
+
+// LegacyUsageResponse is the response of GET /v1/usage?date=YYYY-MM-DD.
+type LegacyUsageResponse struct {
+	// Object is always "list"
+	Object string `json:"object"`
+
+	// Data is the completions usage for the day
+	Data []LegacyUsageEntry `json:"data"`
+
+	// FtData is fine-tuning usage (always empty)
+	FtData []interface{} `json:"ft_data"`
+
+	// DalleAPIData is image usage (always empty)
+	DalleAPIData []interface{} `json:"dalle_api_data"`
+
+	// WhisperAPIData is transcription usage (always empty)
+	WhisperAPIData []interface{} `json:"whisper_api_data"`
+
+	// TTSAPIData is speech usage (always empty)
+	TTSAPIData []interface{} `json:"tts_api_data"`
+}
+
+// LegacyUsageEntry is usage for one model, API key and project in the legacy usage API.
+type LegacyUsageEntry struct {
+	// AggregationTimestamp is the start of the aggregation window (Unix seconds)
+	AggregationTimestamp int64 `json:"aggregation_timestamp"`
+
+	// NRequests is the number of requests
+	NRequests int64 `json:"n_requests"`
+
+	// Operation is always "completion"
+	Operation string `json:"operation"`
+
+	// SnapshotID is the model
+	SnapshotID string `json:"snapshot_id"`
+
+	// NContextTokensTotal is the number of input tokens
+	NContextTokensTotal int64 `json:"n_context_tokens_total"`
+
+	// NGeneratedTokensTotal is the number of output tokens
+	NGeneratedTokensTotal int64 `json:"n_generated_tokens_total"`
+
+	// APIKeyID identifies the API key
+	APIKeyID string `json:"api_key_id"`
+
+	// ProjectID is the project (null if none)
+	ProjectID *string `json:"project_id"`
+}
@@ -0,0 +1,95 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file implements object pools that keep the request hot path allocation-free.
+package models
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize is the largest buffer returned to the pool.
+// Larger buffers (e.g., from huge embedding responses) are dropped so a
+// single outlier doesn't pin memory for the lifetime of the process.
+const maxPooledBufferSize = 64 << 10
+
+var (
+	// chatRequestPool reuses ChatCompletionRequest structs across requests
+	chatRequestPool = sync.Pool{
+		New: func() interface{} {
+			return new(ChatCompletionRequest)
+		},
+	}
+
+	// chatResponsePool reuses ChatCompletionResponse structs across requests
+	chatResponsePool = sync.Pool{
+		New: func() interface{} {
+			return &ChatCompletionResponse{
+				Choices: make([]Choice, 0, 1),
+			}
+		},
+	}
+
+	// bufferPool reuses buffers for JSON encoding and SSE frames
+	bufferPool = sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4096))
+		},
+	}
+)
+
+// AcquireChatCompletionRequest returns an empty ChatCompletionRequest from the pool.
+// Call ReleaseChatCompletionRequest when the request is no longer referenced.
+func AcquireChatCompletionRequest() *ChatCompletionRequest {
+	return chatRequestPool.Get().(*ChatCompletionRequest)
+}
+
+// ReleaseChatCompletionRequest resets a request and returns it to the pool.
+// The messages slice is kept (cleared and truncated) so its backing array
+// is reused.
+func ReleaseChatCompletionRequest(r *ChatCompletionRequest) {
+	if r == nil {
+		return
+	}
+
+	clear(r.Messages)
+	messages := r.Messages[:0]
+	*r = ChatCompletionRequest{}
+	r.Messages = messages
+
+	chatRequestPool.Put(r)
+}
+
+// AcquireChatCompletionResponse returns an empty ChatCompletionResponse from the pool.
+// Call ReleaseChatCompletionResponse after the response has been written.
+func AcquireChatCompletionResponse() *ChatCompletionResponse {
+	return chatResponsePool.Get().(*ChatCompletionResponse)
+}
+
+// ReleaseChatCompletionResponse resets a response and returns it to the pool.
+func ReleaseChatCompletionResponse(r *ChatCompletionResponse) {
+	if r == nil {
+		return
+	}
+
+	clear(r.Choices)
+	choices := r.Choices[:0]
+	*r = ChatCompletionResponse{}
+	r.Choices = choices
+
+	chatResponsePool.Put(r)
+}
+
+// AcquireBuffer returns an empty buffer from the pool.
+func AcquireBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// ReleaseBuffer returns a buffer to the pool.
+func ReleaseBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
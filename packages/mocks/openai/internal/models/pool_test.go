@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestReleaseChatCompletionRequest(t *testing.T) {
+	seed := 42
+	req := AcquireChatCompletionRequest()
+	req.Model = "gpt-4o"
+	req.Seed = &seed
+	req.Messages = append(req.Messages, Message{Role: "user", Content: "Hello"}, Message{Role: "assistant", Content: "Hi"})
+	ReleaseChatCompletionRequest(req)
+
+	if req.Model != "" || req.Seed != nil || len(req.Messages) != 0 {
+		t.Fatalf("released request = %+v, want empty", req)
+	}
+	if reused := req.Messages[:2]; reused[0].Content != "" || reused[1].Role != "" {
+		t.Errorf("released messages = %+v, want cleared", reused)
+	}
+}
+
+func TestNewChatCompletionResponsePooled(t *testing.T) {
+	tests := []struct {
+		name    string
+		message Message
+		usage   Usage
+	}{
+		{name: "content", message: Message{Role: "assistant", Content: "Hello"}, usage: Usage{PromptTokens: 5, CompletionTokens: 1, TotalTokens: 6}},
+		{name: "tool calls", message: Message{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A released response must not leak into the next one
+			previous := NewChatCompletionResponse("gpt-4", Message{Role: "assistant", Content: "stale"}, Usage{TotalTokens: 99})
+			ReleaseChatCompletionResponse(previous)
+
+			response := NewChatCompletionResponse("gpt-4o", tt.message, tt.usage)
+			defer ReleaseChatCompletionResponse(response)
+
+			if response.Model != "gpt-4o" || response.Object != "chat.completion" || response.Usage != tt.usage {
+				t.Errorf("response = %+v", response)
+			}
+			if len(response.Choices) != 1 {
+				t.Fatalf("choices = %d, want 1", len(response.Choices))
+			}
+			if got := response.Choices[0].Message; got.Content != tt.message.Content || len(got.ToolCalls) != len(tt.message.ToolCalls) {
+				t.Errorf("message = %+v, want %+v", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestReleaseBuffer(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "small", size: 128},
+		{name: "larger than the pool keeps", size: maxPooledBufferSize + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := AcquireBuffer()
+			buf.Write(make([]byte, tt.size))
+			ReleaseBuffer(buf)
+
+			if next := AcquireBuffer(); next.Len() != 0 {
+				t.Errorf("acquired buffer has %d bytes, want 0", next.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,540 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines the Realtime API (/v1/realtime) session and the events
+// exchanged over its WebSocket.
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Realtime session defaults (matching the API).
+const (
+	// RealtimeSessionTTL is how long a session may stay connected, in seconds
+	RealtimeSessionTTL = 30 * 60
+
+	// RealtimeDefaultVoice is the voice of a new session
+	RealtimeDefaultVoice = "alloy"
+
+	// RealtimeDefaultAudioFormat is the input and output audio format of a
+	// new session (16-bit PCM, 24kHz mono, little-endian)
+	RealtimeDefaultAudioFormat = "pcm16"
+)
+
+// RealtimeVoices are the voices Realtime models support.
+var RealtimeVoices = []string{"alloy", "ash", "ballad", "coral", "echo", "sage", "shimmer", "verse"}
+
+// RealtimeAudioFormats are the supported input and output audio formats.
+var RealtimeAudioFormats = []string{"pcm16", "g711_ulaw", "g711_alaw"}
+
+// RealtimeTurnDetection configures server-side voice activity detection.
+type RealtimeTurnDetection struct {
+	// Type is "server_vad"
+	Type string `json:"type"`
+
+	// Threshold is the activation threshold, 0.0 to 1.0
+	Threshold float64 `json:"threshold"`
+
+	// PrefixPaddingMs is the audio kept before detected speech
+	PrefixPaddingMs int `json:"prefix_padding_ms"`
+
+	// SilenceDurationMs is the silence that ends a turn
+	SilenceDurationMs int `json:"silence_duration_ms"`
+
+	// CreateResponse starts a response when a turn ends (default true)
+	CreateResponse *bool `json:"create_response,omitempty"`
+}
+
+// RealtimeTranscription configures transcription of input audio.
+type RealtimeTranscription struct {
+	// Model is the transcription model (e.g. "whisper-1")
+	Model string `json:"model"`
+}
+
+// RealtimeSession is a Realtime session's configuration.
+type RealtimeSession struct {
+	// ID is the session identifier
+	ID string `json:"id"`
+
+	// Object is always "realtime.session"
+	Object string `json:"object"`
+
+	// Model is the session's model
+	Model string `json:"model"`
+
+	// ExpiresAt is the Unix timestamp after which the session closes
+	ExpiresAt int64 `json:"expires_at"`
+
+	// Modalities are the response modalities: ["text"] or ["text", "audio"]
+	Modalities []string `json:"modalities"`
+
+	// Instructions are the system instructions for responses
+	Instructions string `json:"instructions"`
+
+	// Voice is the voice of audio responses
+	Voice string `json:"voice"`
+
+	// InputAudioFormat and OutputAudioFormat are "pcm16", "g711_ulaw" or
+	// "g711_alaw"
+	InputAudioFormat  string `json:"input_audio_format"`
+	OutputAudioFormat string `json:"output_audio_format"`
+
+	// InputAudioTranscription enables transcripts of input audio (nil = off)
+	InputAudioTranscription *RealtimeTranscription `json:"input_audio_transcription"`
+
+	// TurnDetection enables server VAD (nil = the client commits turns)
+	TurnDetection *RealtimeTurnDetection `json:"turn_detection"`
+
+	// Tools are the functions responses may call
+	Tools []interface{} `json:"tools"`
+
+	// ToolChoice is "auto", "none", "required" or a function
+	ToolChoice interface{} `json:"tool_choice"`
+
+	// Temperature is the sampling temperature
+	Temperature float64 `json:"temperature"`
+
+	// MaxResponseOutputTokens is a token count or "inf"
+	MaxResponseOutputTokens interface{} `json:"max_response_output_tokens"`
+}
+
+// NewRealtimeSession creates a session with the API's defaults.
+func NewRealtimeSession(model string, createdAt int64) RealtimeSession {
+	return RealtimeSession{
+		ID:                      NewRealtimeID("sess"),
+		Object:                  "realtime.session",
+		Model:                   model,
+		ExpiresAt:               createdAt + RealtimeSessionTTL,
+		Modalities:              []string{"text", "audio"},
+		Voice:                   RealtimeDefaultVoice,
+		InputAudioFormat:        RealtimeDefaultAudioFormat,
+		OutputAudioFormat:       RealtimeDefaultAudioFormat,
+		TurnDetection:           defaultTurnDetection(),
+		Tools:                   []interface{}{},
+		ToolChoice:              "auto",
+		Temperature:             0.8,
+		MaxResponseOutputTokens: "inf",
+	}
+}
+
+// defaultTurnDetection returns the server VAD settings of a new session.
+func defaultTurnDetection() *RealtimeTurnDetection {
+	createResponse := true
+	return &RealtimeTurnDetection{
+		Type:              "server_vad",
+		Threshold:         0.5,
+		PrefixPaddingMs:   300,
+		SilenceDurationMs: 200,
+		CreateResponse:    &createResponse,
+	}
+}
+
+// HasAudio returns true if responses include audio.
+func (s RealtimeSession) HasAudio() bool {
+	return slices.Contains(s.Modalities, "audio")
+}
+
+// RealtimeSessionUpdate is the session of a session.update event. Only
+// the fields present change; turn_detection and input_audio_transcription
+// may be set to null to turn them off.
+type RealtimeSessionUpdate struct {
+	Modalities              []string        `json:"modalities,omitempty"`
+	Instructions            *string         `json:"instructions,omitempty"`
+	Voice                   *string         `json:"voice,omitempty"`
+	InputAudioFormat        *string         `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       *string         `json:"output_audio_format,omitempty"`
+	InputAudioTranscription json.RawMessage `json:"input_audio_transcription,omitempty"`
+	TurnDetection           json.RawMessage `json:"turn_detection,omitempty"`
+	Tools                   []interface{}   `json:"tools,omitempty"`
+	ToolChoice              interface{}     `json:"tool_choice,omitempty"`
+	Temperature             *float64        `json:"temperature,omitempty"`
+	MaxResponseOutputTokens interface{}     `json:"max_response_output_tokens,omitempty"`
+}
+
+// Apply validates the update and applies it to a session. The session is
+// unchanged if the update is invalid.
+func (u *RealtimeSessionUpdate) Apply(session *RealtimeSession) error {
+	updated := *session
+
+	if u.Modalities != nil {
+		if err := validateRealtimeModalities(u.Modalities); err != nil {
+			return err
+		}
+		updated.Modalities = u.Modalities
+	}
+	if u.Instructions != nil {
+		updated.Instructions = *u.Instructions
+	}
+	if u.Voice != nil {
+		if !slices.Contains(RealtimeVoices, *u.Voice) {
+			param := "session.voice"
+			return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", *u.Voice, quoteList(RealtimeVoices)), &param)
+		}
+		updated.Voice = *u.Voice
+	}
+	if err := setAudioFormat(&updated.InputAudioFormat, u.InputAudioFormat, "session.input_audio_format"); err != nil {
+		return err
+	}
+	if err := setAudioFormat(&updated.OutputAudioFormat, u.OutputAudioFormat, "session.output_audio_format"); err != nil {
+		return err
+	}
+	if u.InputAudioTranscription != nil {
+		updated.InputAudioTranscription = nil
+		if !isJSONNull(u.InputAudioTranscription) {
+			var transcription RealtimeTranscription
+			if err := json.Unmarshal(u.InputAudioTranscription, &transcription); err != nil {
+				param := "session.input_audio_transcription"
+				return NewBadRequestError(fmt.Sprintf("Invalid 'input_audio_transcription': %v", err), &param)
+			}
+			updated.InputAudioTranscription = &transcription
+		}
+	}
+	if u.TurnDetection != nil {
+		turnDetection, err := u.turnDetection(session.TurnDetection)
+		if err != nil {
+			return err
+		}
+		updated.TurnDetection = turnDetection
+	}
+	if u.Tools != nil {
+		updated.Tools = u.Tools
+	}
+	if u.ToolChoice != nil {
+		updated.ToolChoice = u.ToolChoice
+	}
+	if u.Temperature != nil {
+		if *u.Temperature < 0.6 || *u.Temperature > 1.2 {
+			param := "session.temperature"
+			return NewBadRequestError(fmt.Sprintf("Invalid 'temperature': expected a value between 0.6 and 1.2, but got %g instead.", *u.Temperature), &param)
+		}
+		updated.Temperature = *u.Temperature
+	}
+	if u.MaxResponseOutputTokens != nil {
+		updated.MaxResponseOutputTokens = u.MaxResponseOutputTokens
+	}
+
+	*session = updated
+	return nil
+}
+
+// turnDetection parses the update's turn_detection over the current one:
+// null turns VAD off, omitted settings keep their current values.
+func (u *RealtimeSessionUpdate) turnDetection(current *RealtimeTurnDetection) (*RealtimeTurnDetection, error) {
+	if isJSONNull(u.TurnDetection) {
+		return nil, nil
+	}
+
+	turnDetection := defaultTurnDetection()
+	if current != nil {
+		copied := *current
+		turnDetection = &copied
+	}
+	param := "session.turn_detection"
+	if err := json.Unmarshal(u.TurnDetection, turnDetection); err != nil {
+		return nil, NewBadRequestError(fmt.Sprintf("Invalid 'turn_detection': %v", err), &param)
+	}
+	if turnDetection.Type != "server_vad" {
+		param += ".type"
+		return nil, NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: 'server_vad'.", turnDetection.Type), &param)
+	}
+	if turnDetection.Threshold < 0 || turnDetection.Threshold > 1 {
+		param += ".threshold"
+		return nil, NewBadRequestError(fmt.Sprintf("Invalid 'threshold': expected a value between 0 and 1, but got %g instead.", turnDetection.Threshold), &param)
+	}
+	return turnDetection, nil
+}
+
+// setAudioFormat sets an audio format if one is given and supported.
+func setAudioFormat(dst *string, format *string, param string) error {
+	if format == nil {
+		return nil
+	}
+	if !slices.Contains(RealtimeAudioFormats, *format) {
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", *format, quoteList(RealtimeAudioFormats)), &param)
+	}
+	*dst = *format
+	return nil
+}
+
+// validateRealtimeModalities accepts ["text"] or text and audio together.
+func validateRealtimeModalities(modalities []string) error {
+	text := slices.Contains(modalities, "text")
+	audio := slices.Contains(modalities, "audio")
+	if text && len(modalities) == 1 || text && audio && len(modalities) == 2 {
+		return nil
+	}
+	param := "session.modalities"
+	return NewBadRequestError("Invalid modalities: expected ['text'] or ['text', 'audio'].", &param)
+}
+
+// isJSONNull returns true if raw is the JSON literal null.
+func isJSONNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// RealtimeContent is a part of a conversation item.
+type RealtimeContent struct {
+	// Type is "input_text", "input_audio", "text" or "audio"
+	Type string `json:"type"`
+
+	// Text is the content of text parts
+	Text string `json:"text,omitempty"`
+
+	// Audio is base64 audio (input_audio parts sent by the client)
+	Audio string `json:"audio,omitempty"`
+
+	// Transcript is the text of audio parts
+	Transcript *string `json:"transcript,omitempty"`
+}
+
+// PlainText returns the part's text, or the transcript of an audio part.
+func (c RealtimeContent) PlainText() string {
+	if c.Text != "" {
+		return c.Text
+	}
+	if c.Transcript != nil {
+		return *c.Transcript
+	}
+	return ""
+}
+
+// RealtimeItem is a conversation item: a message, a function call or a
+// function call's output.
+type RealtimeItem struct {
+	// ID is the item identifier (assigned if the client omits it)
+	ID string `json:"id"`
+
+	// Object is always "realtime.item"
+	Object string `json:"object"`
+
+	// Type is "message", "function_call" or "function_call_output"
+	Type string `json:"type"`
+
+	// Status is "completed", "in_progress" or "incomplete"
+	Status string `json:"status,omitempty"`
+
+	// Role is "user", "assistant" or "system" (messages only)
+	Role string `json:"role,omitempty"`
+
+	// Content are the message's parts (messages only)
+	Content []RealtimeContent `json:"content,omitempty"`
+
+	// CallID, Name and Arguments describe function calls; CallID and
+	// Output describe their outputs
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+// Validate checks an item sent with conversation.item.create.
+func (i *RealtimeItem) Validate() error {
+	switch i.Type {
+	case "message":
+		if i.Role != "user" && i.Role != "assistant" && i.Role != "system" {
+			param := "item.role"
+			return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: 'user', 'assistant', and 'system'.", i.Role), &param)
+		}
+		if len(i.Content) == 0 {
+			param := "item.content"
+			return NewBadRequestError("Missing required parameter: 'item.content'.", &param)
+		}
+	case "function_call_output":
+		if i.CallID == "" {
+			param := "item.call_id"
+			return NewBadRequestError("Missing required parameter: 'item.call_id'.", &param)
+		}
+	case "function_call":
+		if i.CallID == "" || i.Name == "" {
+			param := "item.name"
+			return NewBadRequestError("Function call items require 'call_id' and 'name'.", &param)
+		}
+	default:
+		param := "item.type"
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: 'message', 'function_call', and 'function_call_output'.", i.Type), &param)
+	}
+	return nil
+}
+
+// PlainText returns the item's text: its parts, or a function call's
+// arguments or output.
+func (i RealtimeItem) PlainText() string {
+	switch i.Type {
+	case "function_call":
+		return i.Arguments
+	case "function_call_output":
+		return i.Output
+	}
+
+	var text []byte
+	for _, part := range i.Content {
+		if part.PlainText() == "" {
+			continue
+		}
+		if len(text) > 0 {
+			text = append(text, ' ')
+		}
+		text = append(text, part.PlainText()...)
+	}
+	return string(text)
+}
+
+// RealtimeResponseConfig overrides the session for one response
+// (response.create).
+type RealtimeResponseConfig struct {
+	// Modalities override the session's modalities
+	Modalities []string `json:"modalities,omitempty"`
+
+	// Instructions override the session's instructions
+	Instructions *string `json:"instructions,omitempty"`
+}
+
+// RealtimeUsage is a response's token usage.
+type RealtimeUsage struct {
+	TotalTokens        int                       `json:"total_tokens"`
+	InputTokens        int                       `json:"input_tokens"`
+	OutputTokens       int                       `json:"output_tokens"`
+	InputTokenDetails  RealtimeInputTokenDetails `json:"input_token_details"`
+	OutputTokenDetails RealtimeTokenDetails      `json:"output_token_details"`
+}
+
+// RealtimeInputTokenDetails splits input tokens by modality.
+type RealtimeInputTokenDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+	TextTokens   int `json:"text_tokens"`
+	AudioTokens  int `json:"audio_tokens"`
+}
+
+// RealtimeTokenDetails splits output tokens by modality.
+type RealtimeTokenDetails struct {
+	TextTokens  int `json:"text_tokens"`
+	AudioTokens int `json:"audio_tokens"`
+}
+
+// RealtimeResponse is a model response streamed over a session.
+type RealtimeResponse struct {
+	// ID is the response identifier
+	ID string `json:"id"`
+
+	// Object is always "realtime.response"
+	Object string `json:"object"`
+
+	// Status is "in_progress", "completed", "cancelled" or "incomplete"
+	Status string `json:"status"`
+
+	// StatusDetails explains a cancelled or incomplete response
+	StatusDetails *RealtimeStatusDetails `json:"status_details"`
+
+	// Output are the items the response produced
+	Output []RealtimeItem `json:"output"`
+
+	// Usage is set once the response is done
+	Usage *RealtimeUsage `json:"usage"`
+}
+
+// RealtimeStatusDetails explains why a response did not complete.
+type RealtimeStatusDetails struct {
+	// Type is the final status ("cancelled")
+	Type string `json:"type"`
+
+	// Reason is "client_cancelled" or "turn_detected"
+	Reason string `json:"reason"`
+}
+
+// RealtimeClientEvent is an event sent by the client. Which fields are set
+// depends on Type.
+type RealtimeClientEvent struct {
+	// EventID is the client's optional event identifier
+	EventID string `json:"event_id,omitempty"`
+
+	// Type is the event type (e.g. "input_audio_buffer.append")
+	Type string `json:"type"`
+
+	// Session is the update of session.update
+	Session *RealtimeSessionUpdate `json:"session,omitempty"`
+
+	// Audio is base64 audio (input_audio_buffer.append)
+	Audio string `json:"audio,omitempty"`
+
+	// Item is the item of conversation.item.create
+	Item *RealtimeItem `json:"item,omitempty"`
+
+	// PreviousItemID places a created item after another
+	// (conversation.item.create)
+	PreviousItemID *string `json:"previous_item_id,omitempty"`
+
+	// ItemID is the item of conversation.item.delete
+	ItemID string `json:"item_id,omitempty"`
+
+	// Response overrides the session for response.create
+	Response *RealtimeResponseConfig `json:"response,omitempty"`
+}
+
+// RealtimeError is the error of an error event.
+type RealtimeError struct {
+	APIError
+
+	// EventID is the client event that caused the error
+	EventID string `json:"event_id,omitempty"`
+}
+
+// RealtimeServerEvent is an event sent by the server. Which fields are set
+// depends on Type.
+type RealtimeServerEvent struct {
+	// EventID is the event identifier
+	EventID string `json:"event_id"`
+
+	// Type is the event type (e.g. "response.audio.delta")
+	Type string `json:"type"`
+
+	// Session is the configuration (session.created, session.updated)
+	Session *RealtimeSession `json:"session,omitempty"`
+
+	// Response is the response (response.created, response.done)
+	Response *RealtimeResponse `json:"response,omitempty"`
+
+	// ResponseID, OutputIndex and ContentIndex locate a streamed part
+	ResponseID   string `json:"response_id,omitempty"`
+	OutputIndex  *int   `json:"output_index,omitempty"`
+	ContentIndex *int   `json:"content_index,omitempty"`
+
+	// Item is the item created, added or done
+	Item *RealtimeItem `json:"item,omitempty"`
+
+	// ItemID is the item an event refers to
+	ItemID string `json:"item_id,omitempty"`
+
+	// PreviousItemID is the item before a created or committed item
+	PreviousItemID *string `json:"previous_item_id,omitempty"`
+
+	// Part is the content part added or done
+	Part *RealtimeContent `json:"part,omitempty"`
+
+	// Delta is a streamed text, transcript or base64 audio increment
+	Delta string `json:"delta,omitempty"`
+
+	// Text and Transcript are the full text of a done part
+	Text       string `json:"text,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+
+	// AudioStartMs and AudioEndMs locate detected speech in the input
+	// audio buffer
+	AudioStartMs *int `json:"audio_start_ms,omitempty"`
+	AudioEndMs   *int `json:"audio_end_ms,omitempty"`
+
+	// Error is the error of an error event
+	Error *RealtimeError `json:"error,omitempty"`
+}
+
+// NewRealtimeID generates a Realtime identifier ("sess", "event", "item",
+// "resp").
+func NewRealtimeID(prefix string) string {
+	return generateID(prefix)
+}
+
+// ValidateRealtimeModel checks a model serves the Realtime API.
+func ValidateRealtimeModel(config ModelConfig, model string) error {
+	return requireModelType(config, model, ModelTypeRealtime, "/v1/realtime")
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewRealtimeSession(t *testing.T) {
+	s := NewRealtimeSession("gpt-4o-realtime-preview", 1700000000)
+
+	if s.ExpiresAt != 1700000000+RealtimeSessionTTL {
+		t.Errorf("ExpiresAt = %d, want %d", s.ExpiresAt, 1700000000+RealtimeSessionTTL)
+	}
+	if s.Voice != RealtimeDefaultVoice || s.InputAudioFormat != RealtimeDefaultAudioFormat || s.OutputAudioFormat != RealtimeDefaultAudioFormat {
+		t.Errorf("session = %+v, want the default voice and audio formats", s)
+	}
+	if !s.HasAudio() {
+		t.Error("HasAudio() = false, want true")
+	}
+	if s.TurnDetection == nil || s.TurnDetection.Type != "server_vad" {
+		t.Errorf("TurnDetection = %+v, want server_vad", s.TurnDetection)
+	}
+}
+
+func TestRealtimeSessionUpdateApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		update    string
+		check     func(t *testing.T, s RealtimeSession)
+		wantParam string
+	}{
+		{
+			name:   "text only",
+			update: `{"modalities": ["text"], "instructions": "Be brief", "voice": "verse"}`,
+			check: func(t *testing.T, s RealtimeSession) {
+				if s.HasAudio() || s.Instructions != "Be brief" || s.Voice != "verse" {
+					t.Errorf("session = %+v, want text only, brief and verse", s)
+				}
+			},
+		},
+		{
+			name:   "turn detection off",
+			update: `{"turn_detection": null}`,
+			check: func(t *testing.T, s RealtimeSession) {
+				if s.TurnDetection != nil {
+					t.Errorf("TurnDetection = %+v, want nil", s.TurnDetection)
+				}
+			},
+		},
+		{
+			name:   "partial turn detection",
+			update: `{"turn_detection": {"silence_duration_ms": 500}}`,
+			check: func(t *testing.T, s RealtimeSession) {
+				if s.TurnDetection.SilenceDurationMs != 500 || s.TurnDetection.PrefixPaddingMs != 300 || s.TurnDetection.Type != "server_vad" {
+					t.Errorf("TurnDetection = %+v, want 500ms silence and the other defaults", s.TurnDetection)
+				}
+			},
+		},
+		{
+			name:   "transcription",
+			update: `{"input_audio_transcription": {"model": "whisper-1"}, "input_audio_format": "g711_ulaw", "temperature": 1.0}`,
+			check: func(t *testing.T, s RealtimeSession) {
+				if s.InputAudioTranscription == nil || s.InputAudioFormat != "g711_ulaw" || s.Temperature != 1.0 {
+					t.Errorf("session = %+v, want transcription, g711_ulaw and temperature 1", s)
+				}
+			},
+		},
+		{name: "audio only", update: `{"modalities": ["audio"]}`, wantParam: "session.modalities"},
+		{name: "unknown voice", update: `{"voice": "nova"}`, wantParam: "session.voice"},
+		{name: "unknown format", update: `{"output_audio_format": "mp3"}`, wantParam: "session.output_audio_format"},
+		{name: "semantic vad", update: `{"turn_detection": {"type": "semantic_vad"}}`, wantParam: "session.turn_detection.type"},
+		{name: "threshold", update: `{"turn_detection": {"threshold": 1.5}}`, wantParam: "session.turn_detection.threshold"},
+		{name: "temperature", update: `{"temperature": 0.2}`, wantParam: "session.temperature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update RealtimeSessionUpdate
+			if err := json.Unmarshal([]byte(tt.update), &update); err != nil {
+				t.Fatal(err)
+			}
+			session := NewRealtimeSession("gpt-4o-realtime-preview", 0)
+			before := session
+
+			err := update.Apply(&session)
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				tt.check(t, session)
+				return
+			}
+			if err == nil {
+				t.Fatal("Apply() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+			if session.Voice != before.Voice || session.Temperature != before.Temperature || session.TurnDetection != before.TurnDetection {
+				t.Errorf("invalid update changed the session to %+v", session)
+			}
+		})
+	}
+}
+
+func TestRealtimeItem(t *testing.T) {
+	transcript := "hello there"
+
+	tests := []struct {
+		name      string
+		item      RealtimeItem
+		wantText  string
+		wantParam string
+	}{
+		{
+			name:     "message",
+			item:     RealtimeItem{Type: "message", Role: "user", Content: []RealtimeContent{{Type: "input_text", Text: "Hi"}, {Type: "input_audio", Audio: "AAAA"}, {Type: "audio", Transcript: &transcript}}},
+			wantText: "Hi hello there",
+		},
+		{name: "function call", item: RealtimeItem{Type: "function_call", CallID: "call_1", Name: "get_weather", Arguments: `{"city":"Paris"}`}, wantText: `{"city":"Paris"}`},
+		{name: "function call output", item: RealtimeItem{Type: "function_call_output", CallID: "call_1", Output: "sunny"}, wantText: "sunny"},
+		{name: "tool role", item: RealtimeItem{Type: "message", Role: "tool", Content: []RealtimeContent{{Type: "input_text", Text: "Hi"}}}, wantParam: "item.role"},
+		{name: "no content", item: RealtimeItem{Type: "message", Role: "user"}, wantParam: "item.content"},
+		{name: "output without call", item: RealtimeItem{Type: "function_call_output", Output: "sunny"}, wantParam: "item.call_id"},
+		{name: "call without name", item: RealtimeItem{Type: "function_call", CallID: "call_1"}, wantParam: "item.name"},
+		{name: "unknown type", item: RealtimeItem{Type: "image"}, wantParam: "item.type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				if got := tt.item.PlainText(); got != tt.wantText {
+					t.Errorf("PlainText() = %q, want %q", got, tt.wantText)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestValidateRealtimeModel(t *testing.T) {
+	tests := []struct {
+		model   string
+		wantErr bool
+	}{
+		{model: "gpt-4o-realtime-preview"},
+		{model: "gpt-4o", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			config, err := GetModelConfig(tt.model)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ValidateRealtimeModel(config, tt.model); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRealtimeModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
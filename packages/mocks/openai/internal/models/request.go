@@ -3,24 +3,94 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 )
 
 // Message represents a chat message in the conversation.
 // This matches OpenAI's message format exactly.
 type Message struct {
-	// Role is the role of the message author (user, assistant, system, function)
-	Role string `json:"role"`
+	// Role is the role of the message author (user, assistant, system, tool, function)
+	Role string `json:"role" enum:"system,user,assistant,tool,function"`
 
-	// Content is the content of the message
+	// Content is the content of the message; for content arrays it is the
+	// text of the text parts
 	Content string `json:"content"`
 
+	// Parts are the parts of a content array (text and image_url), or nil
+	// when content is a string
+	Parts []ContentPart `json:"-"`
+
 	// Name is the name of the author (optional, for function calls)
 	Name *string `json:"name,omitempty"`
 
 	// FunctionCall is the function call made by the assistant (optional)
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+
+	// ToolCalls are the tool calls made by the assistant (optional)
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+
+	// ToolCallID is the tool call a tool message answers (tool role only)
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// MarshalJSON encodes an assistant message that only makes calls with
+// "content": null, as the API does, and content arrays as arrays.
+func (m Message) MarshalJSON() ([]byte, error) {
+	type message Message
+	if len(m.Parts) > 0 {
+		return json.Marshal(struct {
+			message
+			Content []ContentPart `json:"content"`
+		}{message: message(m), Content: m.Parts})
+	}
+	if m.Content != "" || (m.FunctionCall == nil && len(m.ToolCalls) == 0) {
+		return json.Marshal(message(m))
+	}
+
+	return json.Marshal(struct {
+		message
+		Content *string `json:"content"`
+	}{message: message(m)})
+}
+
+// UnmarshalJSON accepts content as a string, null or an array of parts.
+// An array is kept in Parts and its text parts, joined by newlines, in
+// Content.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type message Message
+	var decoded struct {
+		message
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*m = Message(decoded.message)
+
+	content := bytes.TrimSpace(decoded.Content)
+	switch {
+	case len(content) == 0 || bytes.Equal(content, []byte("null")):
+	case content[0] == '[':
+		if err := json.Unmarshal(content, &m.Parts); err != nil {
+			return fmt.Errorf("content: %w", err)
+		}
+		var texts []string
+		for _, part := range m.Parts {
+			if part.Type == "text" {
+				texts = append(texts, part.Text)
+			}
+		}
+		m.Content = strings.Join(texts, "\n")
+	default:
+		if err := json.Unmarshal(content, &m.Content); err != nil {
+			return fmt.Errorf("content: %w", err)
+		}
+	}
+	return nil
 }
 
 // FunctionCall represents a function call made by the assistant.
@@ -65,6 +135,9 @@ type ChatCompletionRequest struct {
 	// Stream enables streaming responses via Server-Sent Events
 	Stream bool `json:"stream,omitempty"`
 
+	// StreamOptions configures the stream (only allowed with stream)
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+
 	// Stop is a list of sequences where the API will stop generating
 	Stop interface{} `json:"stop,omitempty"` // Can be string or []string
 
@@ -100,18 +173,49 @@ type ChatCompletionRequest struct {
 
 	// ToolChoice controls which tool the model should use
 	ToolChoice interface{} `json:"tool_choice,omitempty"`
+
+	// ParallelToolCalls allows several tool calls in one response (default true)
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+
+	// ServiceTier selects the processing tier ("auto", "default", "flex", "priority")
+	ServiceTier string `json:"service_tier,omitempty" enum:"auto,default,flex,priority,scale"`
 }
 
 // ResponseFormat specifies the format of the model's output.
 type ResponseFormat struct {
-	// Type is the format type ("text" or "json_object")
-	Type string `json:"type"`
+	// Type is the format type ("text", "json_object" or "json_schema")
+	Type string `json:"type" enum:"text,json_object,json_schema"`
+
+	// JSONSchema is the schema the output follows (json_schema only)
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
 }
 
+// StreamOptions configures a streaming chat completion.
+type StreamOptions struct {
+	// IncludeUsage sends a final chunk with the request's usage and empty
+	// choices before [DONE]; every other chunk then carries "usage": null
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// Tool choice modes (see ChatCompletionRequest.ToolChoiceMode).
+const (
+	// ToolChoiceNone never calls tools
+	ToolChoiceNone = "none"
+
+	// ToolChoiceAuto lets the model decide (the default with tools)
+	ToolChoiceAuto = "auto"
+
+	// ToolChoiceRequired makes one or more tool calls
+	ToolChoiceRequired = "required"
+
+	// ToolChoiceFunction calls the function tool_choice names
+	ToolChoiceFunction = "function"
+)
+
 // Tool represents a tool that the model can use.
 type Tool struct {
 	// Type is the type of tool ("function")
-	Type string `json:"type"`
+	Type string `json:"type" enum:"function"`
 
 	// Function is the function definition
 	Function Function `json:"function"`
@@ -127,19 +231,45 @@ func (r *ChatCompletionRequest) Validate() error {
 		return fmt.Errorf("messages array cannot be empty")
 	}
 
+	// Validate array lengths
+	if err := r.ValidateLimits(); err != nil {
+		return err
+	}
+
+	// Validate service_tier
+	if _, err := ParseServiceTier(r.ServiceTier); err != nil {
+		return err
+	}
+
 	// Validate messages
-	for i, msg := range r.Messages {
-		if msg.Role == "" {
-			return fmt.Errorf("message[%d]: role is required", i)
-		}
-		if msg.Role != "user" && msg.Role != "assistant" && msg.Role != "system" && msg.Role != "function" {
-			return fmt.Errorf("message[%d]: invalid role '%s'", i, msg.Role)
-		}
-		if msg.Content == "" && msg.FunctionCall == nil {
-			return fmt.Errorf("message[%d]: content or function_call is required", i)
+	if err := r.validateMessages(); err != nil {
+		return err
+	}
+
+	// Validate tools and tool_choice
+	if err := r.validateTools(); err != nil {
+		return err
+	}
+
+	// Validate response_format
+	if r.ResponseFormat != nil {
+		if err := r.ResponseFormat.Validate(); err != nil {
+			return err
 		}
 	}
 
+	// Validate stream_options
+	if r.StreamOptions != nil && !r.Stream {
+		param := "stream_options"
+		return NewBadRequestError("The 'stream_options' parameter is only allowed when 'stream' is enabled.", &param)
+	}
+
+	// Validate parallel_tool_calls
+	if r.ParallelToolCalls != nil && len(r.Tools) == 0 {
+		param := "parallel_tool_calls"
+		return NewBadRequestError("Invalid value for 'parallel_tool_calls': 'parallel_tool_calls' is only allowed when 'tools' are specified.", &param)
+	}
+
 	// Validate temperature
 	if r.Temperature != nil {
 		if *r.Temperature < 0 || *r.Temperature > 2 {
@@ -183,6 +313,133 @@ func (r *ChatCompletionRequest) Validate() error {
 	return nil
 }
 
+// validateMessages checks roles and that every tool message answers a tool
+// call made earlier in the conversation.
+func (r *ChatCompletionRequest) validateMessages() error {
+	toolCallIDs := make(map[string]bool)
+
+	for i, msg := range r.Messages {
+		switch msg.Role {
+		case "":
+			return fmt.Errorf("message[%d]: role is required", i)
+		case "user", "assistant", "system", "function":
+		case "tool":
+			if msg.ToolCallID == "" {
+				param := fmt.Sprintf("messages.[%d].tool_call_id", i)
+				return NewBadRequestError(fmt.Sprintf("Missing required parameter: '%s'.", param), &param)
+			}
+			if !toolCallIDs[msg.ToolCallID] {
+				// The misspelling is the API's
+				param := fmt.Sprintf("messages.[%d].role", i)
+				return NewBadRequestError("Invalid parameter: messages with role 'tool' must be a response to a preceeding message with 'tool_calls'.", &param)
+			}
+		default:
+			return fmt.Errorf("message[%d]: invalid role '%s'", i, msg.Role)
+		}
+
+		if msg.Content == "" && len(msg.Parts) == 0 && msg.FunctionCall == nil && len(msg.ToolCalls) == 0 {
+			return fmt.Errorf("message[%d]: content, function_call or tool_calls is required", i)
+		}
+		for j, part := range msg.Parts {
+			if err := validateContentPart(i, j, msg.Role, part); err != nil {
+				return err
+			}
+		}
+
+		for _, call := range msg.ToolCalls {
+			toolCallIDs[call.ID] = true
+		}
+	}
+
+	return nil
+}
+
+// validateTools checks the tool definitions and that tool_choice selects
+// one of them.
+func (r *ChatCompletionRequest) validateTools() error {
+	for i, tool := range r.Tools {
+		if tool.Type != "function" {
+			param := fmt.Sprintf("tools[%d].type", i)
+			return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: 'function'.", tool.Type), &param)
+		}
+		if tool.Function.Name == "" {
+			param := fmt.Sprintf("tools[%d].function.name", i)
+			return NewBadRequestError(fmt.Sprintf("Missing required parameter: '%s'.", param), &param)
+		}
+	}
+
+	if r.ToolChoice == nil {
+		return nil
+	}
+
+	param := "tool_choice"
+	if len(r.Tools) == 0 {
+		return NewBadRequestError("Invalid value for 'tool_choice': 'tool_choice' is only allowed when 'tools' are specified.", &param)
+	}
+
+	switch choice := r.ToolChoice.(type) {
+	case string:
+		if choice != ToolChoiceNone && choice != ToolChoiceAuto && choice != ToolChoiceRequired {
+			return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: 'none', 'auto', and 'required'.", choice), &param)
+		}
+	case map[string]interface{}:
+		name, ok := toolChoiceName(choice)
+		if !ok {
+			param = "tool_choice.function.name"
+			return NewBadRequestError(fmt.Sprintf("Missing required parameter: '%s'.", param), &param)
+		}
+		for _, tool := range r.Tools {
+			if tool.Function.Name == name {
+				return nil
+			}
+		}
+		return NewBadRequestError(fmt.Sprintf("Invalid value for 'tool_choice': function '%s' is not one of the declared 'tools'.", name), &param)
+	default:
+		return NewBadRequestError("Invalid type for 'tool_choice': expected a string or an object.", &param)
+	}
+
+	return nil
+}
+
+// ToolChoiceMode returns how tool_choice lets the model use the declared
+// tools, and the function it names for ToolChoiceFunction. It defaults to
+// ToolChoiceAuto with tools and ToolChoiceNone without.
+func (r *ChatCompletionRequest) ToolChoiceMode() (string, string) {
+	if len(r.Tools) == 0 {
+		return ToolChoiceNone, ""
+	}
+
+	switch choice := r.ToolChoice.(type) {
+	case string:
+		return choice, ""
+	case map[string]interface{}:
+		if name, ok := toolChoiceName(choice); ok {
+			return ToolChoiceFunction, name
+		}
+	}
+	return ToolChoiceAuto, ""
+}
+
+// toolChoiceName reads the function named by a tool_choice object
+// ({"type": "function", "function": {"name": "..."}}).
+func toolChoiceName(choice map[string]interface{}) (string, bool) {
+	function, _ := choice["function"].(map[string]interface{})
+	name, _ := function["name"].(string)
+	return name, name != ""
+}
+
+// AllowsParallelToolCalls returns whether the response may contain more
+// than one tool call (parallel_tool_calls defaults to true).
+func (r *ChatCompletionRequest) AllowsParallelToolCalls() bool {
+	return r.ParallelToolCalls == nil || *r.ParallelToolCalls
+}
+
+// IncludesStreamUsage returns true if the stream ends with a usage chunk
+// (stream_options.include_usage).
+func (r *ChatCompletionRequest) IncludesStreamUsage() bool {
+	return r.Stream && r.StreamOptions != nil && r.StreamOptions.IncludeUsage
+}
+
 // GetEffectiveTemperature returns the temperature to use (default 1.0).
 func (r *ChatCompletionRequest) GetEffectiveTemperature() float64 {
 	if r.Temperature != nil {
@@ -305,6 +562,11 @@ func (r *EmbeddingRequest) Validate() error {
 		return fmt.Errorf("input is required")
 	}
 
+	// Validate number of inputs
+	if err := r.ValidateLimits(); err != nil {
+		return err
+	}
+
 	// Validate encoding format
 	if r.EncodingFormat != nil {
 		format := *r.EncodingFormat
@@ -321,16 +583,18 @@ type ImageGenerationRequest struct {
 	// Prompt is the text description of the desired image
 	Prompt string `json:"prompt"`
 
-	// Model is the model to use (e.g., "dall-e-3", "dall-e-2")
+	// Model is the model to use (e.g., "gpt-image-1", "dall-e-3", "dall-e-2")
 	Model *string `json:"model,omitempty"`
 
 	// N is the number of images to generate (1-10 for DALL-E 2, only 1 for DALL-E 3)
 	N *int `json:"n,omitempty"`
 
-	// Quality is the quality of the image ("standard" or "hd")
+	// Quality is the quality of the image ("standard" or "hd" for DALL-E,
+	// "low", "medium", "high" or "auto" for gpt-image-1)
 	Quality *string `json:"quality,omitempty"`
 
-	// ResponseFormat is the format of the response ("url" or "b64_json")
+	// ResponseFormat is the format of the response ("url" or "b64_json").
+	// gpt-image-1 always returns b64_json and rejects this parameter.
 	ResponseFormat *string `json:"response_format,omitempty"`
 
 	// Size is the size of the generated images
@@ -339,39 +603,115 @@ type ImageGenerationRequest struct {
 	// Style is the style of the generated images ("vivid" or "natural")
 	Style *string `json:"style,omitempty"`
 
+	// Background is "transparent", "opaque" or "auto" (gpt-image-1 only)
+	Background *string `json:"background,omitempty"`
+
+	// OutputFormat is "png", "jpeg" or "webp" (gpt-image-1 only)
+	OutputFormat *string `json:"output_format,omitempty"`
+
+	// OutputCompression is the jpeg/webp compression level 0-100 (gpt-image-1 only)
+	OutputCompression *int `json:"output_compression,omitempty"`
+
+	// Moderation is "low" or "auto" (gpt-image-1 only)
+	Moderation *string `json:"moderation,omitempty"`
+
 	// User is a unique identifier for the end-user
 	User *string `json:"user,omitempty"`
 }
 
+// imageSizes lists the sizes each image model supports.
+var imageSizes = map[string][]string{
+	"dall-e-2":    {"256x256", "512x512", "1024x1024"},
+	"dall-e-3":    {"1024x1024", "1792x1024", "1024x1792"},
+	"gpt-image-1": {"1024x1024", "1536x1024", "1024x1536", "auto"},
+}
+
+// IsGPTImageModel reports whether model is a GPT image model, which has
+// its own quality levels, returns b64_json only and reports token usage.
+func IsGPTImageModel(model string) bool {
+	return strings.HasPrefix(model, "gpt-image-")
+}
+
 // Validate validates the ImageGenerationRequest.
 func (r *ImageGenerationRequest) Validate() error {
 	if r.Prompt == "" {
 		return fmt.Errorf("prompt is required")
 	}
 
+	model := r.GetEffectiveModel()
+	gptImage := IsGPTImageModel(model)
+
 	// Validate n
 	if r.N != nil {
 		if *r.N < 1 || *r.N > 10 {
 			return fmt.Errorf("n must be between 1 and 10")
 		}
+		if model == "dall-e-3" && *r.N != 1 {
+			return fmt.Errorf("n must be 1 for dall-e-3")
+		}
 	}
 
 	// Validate quality
 	if r.Quality != nil {
 		quality := *r.Quality
-		if quality != "standard" && quality != "hd" {
+		if gptImage {
+			if quality != "low" && quality != "medium" && quality != "high" && quality != "auto" {
+				return fmt.Errorf("quality must be 'low', 'medium', 'high' or 'auto'")
+			}
+		} else if quality != "standard" && quality != "hd" {
 			return fmt.Errorf("quality must be 'standard' or 'hd'")
 		}
 	}
 
+	// Validate size
+	if r.Size != nil {
+		if sizes, ok := imageSizes[model]; ok && !slices.Contains(sizes, *r.Size) {
+			return fmt.Errorf("size must be one of %s for %s", strings.Join(sizes, ", "), model)
+		}
+	}
+
 	// Validate response_format
 	if r.ResponseFormat != nil {
+		if gptImage {
+			return fmt.Errorf("response_format is not supported for %s (images are always returned as b64_json)", model)
+		}
 		format := *r.ResponseFormat
 		if format != "url" && format != "b64_json" {
 			return fmt.Errorf("response_format must be 'url' or 'b64_json'")
 		}
 	}
 
+	// Validate gpt-image-1 output options
+	if !gptImage && (r.Background != nil || r.OutputFormat != nil || r.OutputCompression != nil || r.Moderation != nil) {
+		return fmt.Errorf("background, output_format, output_compression and moderation are only supported for gpt-image-1")
+	}
+	if r.Background != nil {
+		background := *r.Background
+		if background != "transparent" && background != "opaque" && background != "auto" {
+			return fmt.Errorf("background must be 'transparent', 'opaque' or 'auto'")
+		}
+	}
+	if r.OutputFormat != nil {
+		format := *r.OutputFormat
+		if format != "png" && format != "jpeg" && format != "webp" {
+			return fmt.Errorf("output_format must be 'png', 'jpeg' or 'webp'")
+		}
+		if format == "jpeg" && r.Background != nil && *r.Background == "transparent" {
+			return fmt.Errorf("transparent background is only supported for png and webp output formats")
+		}
+	}
+	if r.OutputCompression != nil {
+		if *r.OutputCompression < 0 || *r.OutputCompression > 100 {
+			return fmt.Errorf("output_compression must be between 0 and 100")
+		}
+		if r.GetEffectiveOutputFormat() == "png" {
+			return fmt.Errorf("output_compression is only supported for jpeg and webp output formats")
+		}
+	}
+	if r.Moderation != nil && *r.Moderation != "low" && *r.Moderation != "auto" {
+		return fmt.Errorf("moderation must be 'low' or 'auto'")
+	}
+
 	// Validate style
 	if r.Style != nil {
 		style := *r.Style
@@ -399,6 +739,101 @@ func (r *ImageGenerationRequest) GetEffectiveN() int {
 	return 1
 }
 
+// GetEffectiveSize returns the image size (default "1024x1024"; gpt-image-1
+// renders "auto" as a square).
+func (r *ImageGenerationRequest) GetEffectiveSize() string {
+	if r.Size != nil && *r.Size != "auto" {
+		return *r.Size
+	}
+	return "1024x1024"
+}
+
+// GetEffectiveQuality returns the image quality (default "standard", or
+// "high" for gpt-image-1, which is what "auto" resolves to).
+func (r *ImageGenerationRequest) GetEffectiveQuality() string {
+	if r.Quality != nil && *r.Quality != "auto" {
+		return *r.Quality
+	}
+	if IsGPTImageModel(r.GetEffectiveModel()) {
+		return "high"
+	}
+	return "standard"
+}
+
+// GetEffectiveResponseFormat returns how images are returned (default
+// "url"; always "b64_json" for gpt-image-1).
+func (r *ImageGenerationRequest) GetEffectiveResponseFormat() string {
+	if IsGPTImageModel(r.GetEffectiveModel()) {
+		return "b64_json"
+	}
+	if r.ResponseFormat != nil {
+		return *r.ResponseFormat
+	}
+	return "url"
+}
+
+// GetEffectiveOutputFormat returns the image file format (default "png").
+func (r *ImageGenerationRequest) GetEffectiveOutputFormat() string {
+	if r.OutputFormat != nil {
+		return *r.OutputFormat
+	}
+	return "png"
+}
+
+// ValidateModel checks the resolved model serves chat completions and
+// supports the features the request uses (tools, images, JSON mode).
+func (r *ChatCompletionRequest) ValidateModel(config ModelConfig) error {
+	if err := requireModelType(config, r.Model, ModelTypeChat, "/v1/chat/completions"); err != nil {
+		return err
+	}
+
+	if (len(r.Tools) > 0 || len(r.Functions) > 0) && !config.Capabilities.Has(CapabilityTools) {
+		param := "tools"
+		if len(r.Tools) == 0 {
+			param = "functions"
+		}
+		return NewBadRequestError(fmt.Sprintf("%s is not supported with the model `%s`.", param, r.Model), &param)
+	}
+
+	if !config.Capabilities.Has(CapabilityVision) {
+		for i, msg := range r.Messages {
+			for j, part := range msg.Parts {
+				if part.Type == "image_url" {
+					param := fmt.Sprintf("messages.[%d].content.[%d].type", i, j)
+					return NewBadRequestError("Invalid content type. image_url is only supported by certain models.", &param)
+				}
+			}
+		}
+	}
+
+	if r.ResponseFormat != nil && r.ResponseFormat.Type != "text" && !config.Capabilities.Has(CapabilityJSON) {
+		param := "response_format"
+		return NewBadRequestError(fmt.Sprintf("Invalid parameter: 'response_format' of type '%s' is not supported with the model `%s`.", r.ResponseFormat.Type, r.Model), &param)
+	}
+
+	return nil
+}
+
+// ValidateModel checks the resolved model serves embeddings.
+func (r *EmbeddingRequest) ValidateModel(config ModelConfig) error {
+	return requireModelType(config, r.Model, ModelTypeEmbedding, "/v1/embeddings")
+}
+
+// ValidateModel checks the resolved model generates images.
+func (r *ImageGenerationRequest) ValidateModel(config ModelConfig) error {
+	return requireModelType(config, r.GetEffectiveModel(), ModelTypeImage, "/v1/images/generations")
+}
+
+// requireModelType returns the API's error for a model used on an endpoint
+// it does not serve.
+func requireModelType(config ModelConfig, model string, want ModelType, endpoint string) error {
+	if config.Type == want {
+		return nil
+	}
+	param := "model"
+	return NewBadRequestError(fmt.Sprintf("The model `%s` does not support %s.", model, endpoint), &param)
+}
+
 // ParseRequest parses a JSON request body into the appropriate request type.
 func ParseRequest(data []byte, req interface{}) error {
 	if err := json.Unmarshal(data, req); err != nil {
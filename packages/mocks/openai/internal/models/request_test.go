@@ -0,0 +1,282 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessageUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		wantContent string
+		wantParts   int
+		wantErr     bool
+	}{
+		{name: "string", json: `{"role": "user", "content": "Hi"}`, wantContent: "Hi"},
+		{name: "null", json: `{"role": "assistant", "content": null, "tool_calls": []}`},
+		{name: "missing", json: `{"role": "assistant"}`},
+		{
+			name:        "parts",
+			json:        `{"role": "user", "content": [{"type": "text", "text": "What"}, {"type": "image_url", "image_url": {"url": "https://example.com/a.png"}}, {"type": "text", "text": "is this?"}]}`,
+			wantContent: "What\nis this?",
+			wantParts:   3,
+		},
+		{name: "number", json: `{"role": "user", "content": 42}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Message
+			err := json.Unmarshal([]byte(tt.json), &m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if m.Content != tt.wantContent || len(m.Parts) != tt.wantParts {
+				t.Errorf("Content = %q, %d parts, want %q and %d", m.Content, len(m.Parts), tt.wantContent, tt.wantParts)
+			}
+		})
+	}
+}
+
+func TestMessageMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     Message
+		wantContent string
+	}{
+		{name: "text", message: Message{Role: "user", Content: "Hi"}, wantContent: `"Hi"`},
+		{name: "empty text", message: Message{Role: "assistant"}, wantContent: `""`},
+		{name: "tool calls", message: Message{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function"}}}, wantContent: "null"},
+		{name: "parts", message: Message{Role: "user", Content: "Hi", Parts: []ContentPart{{Type: "text", Text: "Hi"}}}, wantContent: `[{"type":"text","text":"Hi"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(fields["content"]); got != tt.wantContent {
+				t.Errorf("content = %s, want %s", got, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestChatCompletionRequestValidateTools(t *testing.T) {
+	const tools = `"tools": [{"type": "function", "function": {"name": "get_weather"}}]`
+
+	tests := []struct {
+		name      string
+		body      string
+		wantErr   bool
+		wantParam string
+	}{
+		{name: "no tools", body: `"messages": [{"role": "user", "content": "Hi"}]`},
+		{name: "tool choice auto", body: `"messages": [{"role": "user", "content": "Hi"}], ` + tools + `, "tool_choice": "required"`},
+		{name: "tool choice function", body: `"messages": [{"role": "user", "content": "Hi"}], ` + tools + `, "tool_choice": {"type": "function", "function": {"name": "get_weather"}}`},
+		{
+			name: "tool reply",
+			body: `"messages": [{"role": "user", "content": "Hi"}, {"role": "assistant", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{}"}}]}, {"role": "tool", "tool_call_id": "call_1", "content": "sunny"}], ` + tools,
+		},
+		{name: "missing role", body: `"messages": [{"content": "Hi"}]`, wantErr: true},
+		{name: "unknown role", body: `"messages": [{"role": "developer2", "content": "Hi"}]`, wantErr: true},
+		{name: "empty message", body: `"messages": [{"role": "user"}]`, wantErr: true},
+		{name: "tool without call id", body: `"messages": [{"role": "tool", "content": "sunny"}]`, wantParam: "messages.[0].tool_call_id"},
+		{name: "tool without a call", body: `"messages": [{"role": "user", "content": "Hi"}, {"role": "tool", "tool_call_id": "call_1", "content": "sunny"}]`, wantParam: "messages.[1].role"},
+		{name: "image in a message", body: `"messages": [{"role": "user", "content": [{"type": "image_url", "image_url": {"url": "ftp://a"}}]}]`, wantParam: "messages.[0].content.[0].image_url.url"},
+		{name: "tool type", body: `"messages": [{"role": "user", "content": "Hi"}], "tools": [{"type": "retrieval", "function": {"name": "a"}}]`, wantParam: "tools[0].type"},
+		{name: "tool name", body: `"messages": [{"role": "user", "content": "Hi"}], "tools": [{"type": "function", "function": {}}]`, wantParam: "tools[0].function.name"},
+		{name: "tool choice without tools", body: `"messages": [{"role": "user", "content": "Hi"}], "tool_choice": "auto"`, wantParam: "tool_choice"},
+		{name: "unknown tool choice", body: `"messages": [{"role": "user", "content": "Hi"}], ` + tools + `, "tool_choice": "any"`, wantParam: "tool_choice"},
+		{name: "unnamed tool choice", body: `"messages": [{"role": "user", "content": "Hi"}], ` + tools + `, "tool_choice": {"type": "function"}`, wantParam: "tool_choice.function.name"},
+		{name: "undeclared tool choice", body: `"messages": [{"role": "user", "content": "Hi"}], ` + tools + `, "tool_choice": {"type": "function", "function": {"name": "get_time"}}`, wantParam: "tool_choice"},
+		{name: "tool choice type", body: `"messages": [{"role": "user", "content": "Hi"}], ` + tools + `, "tool_choice": 1`, wantParam: "tool_choice"},
+		{name: "stream options without stream", body: `"messages": [{"role": "user", "content": "Hi"}], "stream_options": {"include_usage": true}`, wantParam: "stream_options"},
+		{name: "parallel tool calls without tools", body: `"messages": [{"role": "user", "content": "Hi"}], "parallel_tool_calls": false`, wantParam: "parallel_tool_calls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r ChatCompletionRequest
+			if err := json.Unmarshal([]byte(`{"model": "gpt-4o", `+tt.body+`}`), &r); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.Validate()
+			wantErr := tt.wantErr || tt.wantParam != ""
+			if (err != nil) != wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, wantErr)
+			}
+			if tt.wantParam != "" {
+				if param, _ := errorParam(t, err); param != tt.wantParam {
+					t.Errorf("error param = %q, want %q", param, tt.wantParam)
+				}
+			}
+		})
+	}
+}
+
+func TestChatCompletionRequestToolOptions(t *testing.T) {
+	tools := []Tool{{Type: "function", Function: Function{Name: "get_weather"}}}
+	no := false
+
+	tests := []struct {
+		name         string
+		req          ChatCompletionRequest
+		wantMode     string
+		wantFunction string
+		wantParallel bool
+		wantUsage    bool
+	}{
+		{name: "no tools", req: ChatCompletionRequest{ToolChoice: "required"}, wantMode: ToolChoiceNone, wantParallel: true},
+		{name: "default", req: ChatCompletionRequest{Tools: tools}, wantMode: ToolChoiceAuto, wantParallel: true},
+		{name: "required", req: ChatCompletionRequest{Tools: tools, ToolChoice: "required", ParallelToolCalls: &no}, wantMode: ToolChoiceRequired},
+		{
+			name:         "function",
+			req:          ChatCompletionRequest{Tools: tools, ToolChoice: map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}}},
+			wantMode:     ToolChoiceFunction,
+			wantFunction: "get_weather",
+			wantParallel: true,
+		},
+		{name: "stream usage", req: ChatCompletionRequest{Stream: true, StreamOptions: &StreamOptions{IncludeUsage: true}}, wantMode: ToolChoiceNone, wantParallel: true, wantUsage: true},
+		{name: "stream without usage", req: ChatCompletionRequest{Stream: true, StreamOptions: &StreamOptions{}}, wantMode: ToolChoiceNone, wantParallel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, function := tt.req.ToolChoiceMode()
+			if mode != tt.wantMode || function != tt.wantFunction {
+				t.Errorf("ToolChoiceMode() = (%q, %q), want (%q, %q)", mode, function, tt.wantMode, tt.wantFunction)
+			}
+			if got := tt.req.AllowsParallelToolCalls(); got != tt.wantParallel {
+				t.Errorf("AllowsParallelToolCalls() = %v, want %v", got, tt.wantParallel)
+			}
+			if got := tt.req.IncludesStreamUsage(); got != tt.wantUsage {
+				t.Errorf("IncludesStreamUsage() = %v, want %v", got, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestChatCompletionRequestValidateModel(t *testing.T) {
+	image := Message{Role: "user", Parts: []ContentPart{{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/a.png"}}}}
+	text := Message{Role: "user", Content: "Hi"}
+	plain := ModelConfig{ID: "plain", Type: ModelTypeChat}
+
+	tests := []struct {
+		name      string
+		config    ModelConfig
+		req       ChatCompletionRequest
+		wantParam string
+	}{
+		{name: "vision model", config: builtinModelConfigs["gpt-4o"], req: ChatCompletionRequest{Messages: []Message{image}, ResponseFormat: &ResponseFormat{Type: "json_object"}}},
+		{name: "text response format", config: plain, req: ChatCompletionRequest{Messages: []Message{text}, ResponseFormat: &ResponseFormat{Type: "text"}}},
+		{name: "embedding model", config: builtinModelConfigs["text-embedding-3-small"], req: ChatCompletionRequest{Messages: []Message{text}}, wantParam: "model"},
+		{name: "tools", config: plain, req: ChatCompletionRequest{Messages: []Message{text}, Tools: []Tool{{Type: "function"}}}, wantParam: "tools"},
+		{name: "functions", config: plain, req: ChatCompletionRequest{Messages: []Message{text}, Functions: []Function{{Name: "a"}}}, wantParam: "functions"},
+		{name: "images", config: builtinModelConfigs["gpt-3.5-turbo"], req: ChatCompletionRequest{Messages: []Message{text, image}}, wantParam: "messages.[1].content.[0].type"},
+		{name: "json mode", config: builtinModelConfigs["gpt-4"], req: ChatCompletionRequest{Messages: []Message{text}, ResponseFormat: &ResponseFormat{Type: "json_object"}}, wantParam: "response_format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Model = tt.config.ID
+			err := tt.req.ValidateModel(tt.config)
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("ValidateModel() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateModel() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestImageGenerationRequestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	n := func(i int) *int { return &i }
+
+	tests := []struct {
+		name    string
+		req     ImageGenerationRequest
+		wantErr bool
+	}{
+		{name: "dall-e-2", req: ImageGenerationRequest{Prompt: "A cat", N: n(10), Size: str("256x256")}},
+		{name: "dall-e-3", req: ImageGenerationRequest{Prompt: "A cat", Model: str("dall-e-3"), Quality: str("hd"), Style: str("natural"), Size: str("1792x1024")}},
+		{
+			name: "gpt-image-1",
+			req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), Quality: str("medium"), Size: str("auto"),
+				Background: str("opaque"), OutputFormat: str("jpeg"), OutputCompression: n(80), Moderation: str("low")},
+		},
+		{name: "missing prompt", req: ImageGenerationRequest{}, wantErr: true},
+		{name: "n", req: ImageGenerationRequest{Prompt: "A cat", N: n(0)}, wantErr: true},
+		{name: "dall-e-3 n", req: ImageGenerationRequest{Prompt: "A cat", Model: str("dall-e-3"), N: n(2)}, wantErr: true},
+		{name: "dall-e quality", req: ImageGenerationRequest{Prompt: "A cat", Quality: str("high")}, wantErr: true},
+		{name: "gpt-image-1 quality", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), Quality: str("hd")}, wantErr: true},
+		{name: "size", req: ImageGenerationRequest{Prompt: "A cat", Size: str("1792x1024")}, wantErr: true},
+		{name: "gpt-image-1 response format", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), ResponseFormat: str("b64_json")}, wantErr: true},
+		{name: "response format", req: ImageGenerationRequest{Prompt: "A cat", ResponseFormat: str("png")}, wantErr: true},
+		{name: "dall-e background", req: ImageGenerationRequest{Prompt: "A cat", Background: str("transparent")}, wantErr: true},
+		{name: "background", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), Background: str("clear")}, wantErr: true},
+		{name: "output format", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), OutputFormat: str("gif")}, wantErr: true},
+		{name: "transparent jpeg", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), OutputFormat: str("jpeg"), Background: str("transparent")}, wantErr: true},
+		{name: "compression range", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), OutputFormat: str("webp"), OutputCompression: n(101)}, wantErr: true},
+		{name: "png compression", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), OutputCompression: n(50)}, wantErr: true},
+		{name: "moderation", req: ImageGenerationRequest{Prompt: "A cat", Model: str("gpt-image-1"), Moderation: str("high")}, wantErr: true},
+		{name: "style", req: ImageGenerationRequest{Prompt: "A cat", Model: str("dall-e-3"), Style: str("cartoon")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageGenerationRequestDefaults(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name               string
+		req                ImageGenerationRequest
+		wantSize           string
+		wantQuality        string
+		wantResponseFormat string
+	}{
+		{name: "dall-e-2", req: ImageGenerationRequest{}, wantSize: "1024x1024", wantQuality: "standard", wantResponseFormat: "url"},
+		{name: "dall-e-3 options", req: ImageGenerationRequest{Model: str("dall-e-3"), Size: str("1024x1792"), Quality: str("hd"), ResponseFormat: str("b64_json")}, wantSize: "1024x1792", wantQuality: "hd", wantResponseFormat: "b64_json"},
+		{name: "gpt-image-1", req: ImageGenerationRequest{Model: str("gpt-image-1"), Size: str("auto"), Quality: str("auto")}, wantSize: "1024x1024", wantQuality: "high", wantResponseFormat: "b64_json"},
+		{name: "gpt-image-1 low", req: ImageGenerationRequest{Model: str("gpt-image-1"), Quality: str("low")}, wantSize: "1024x1024", wantQuality: "low", wantResponseFormat: "b64_json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.GetEffectiveSize(); got != tt.wantSize {
+				t.Errorf("GetEffectiveSize() = %q, want %q", got, tt.wantSize)
+			}
+			if got := tt.req.GetEffectiveQuality(); got != tt.wantQuality {
+				t.Errorf("GetEffectiveQuality() = %q, want %q", got, tt.wantQuality)
+			}
+			if got := tt.req.GetEffectiveResponseFormat(); got != tt.wantResponseFormat {
+				t.Errorf("GetEffectiveResponseFormat() = %q, want %q", got, tt.wantResponseFormat)
+			}
+		})
+	}
+}
@@ -5,7 +5,10 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
 )
 
 // Usage represents token usage information in API responses.
@@ -29,7 +32,7 @@ type Choice struct {
 	Message Message `json:"message"`
 
 	// FinishReason indicates why the completion finished
-	// ("stop", "length", "content_filter", "function_call")
+	// ("stop", "length", "content_filter", "tool_calls", "function_call")
 	FinishReason string `json:"finish_reason"`
 
 	// LogProbs contains log probability information (optional)
@@ -87,6 +90,9 @@ type ChatCompletionResponse struct {
 	// SystemFingerprint is a fingerprint for the system configuration
 	SystemFingerprint *string `json:"system_fingerprint,omitempty"`
 
+	// ServiceTier is the tier the request was processed in
+	ServiceTier string `json:"service_tier,omitempty"`
+
 	// Choices is the list of completion choices
 	Choices []Choice `json:"choices"`
 
@@ -95,21 +101,29 @@ type ChatCompletionResponse struct {
 }
 
 // NewChatCompletionResponse creates a new ChatCompletionResponse with default values.
+// The response comes from the pool; once it has been written it may be
+// returned with ReleaseChatCompletionResponse.
 func NewChatCompletionResponse(model string, message Message, usage Usage) *ChatCompletionResponse {
-	return &ChatCompletionResponse{
-		ID:      generateID("chatcmpl"),
-		Object:  "chat.completion",
-		Created: time.Now().Unix(),
-		Model:   model,
-		Choices: []Choice{
-			{
-				Index:        0,
-				Message:      message,
-				FinishReason: "stop",
-			},
-		},
-		Usage: usage,
-	}
+	response := AcquireChatCompletionResponse()
+	response.ID = generateID("chatcmpl")
+	response.Object = "chat.completion"
+	response.Created = clock.Now().Unix()
+	response.Model = model
+	response.Choices = append(response.Choices, Choice{
+		Index:        0,
+		Message:      message,
+		FinishReason: "stop",
+	})
+	response.Usage = usage
+	return response
+}
+
+// NewToolCallsResponse creates a ChatCompletionResponse whose assistant
+// message makes the given tool calls (finish_reason "tool_calls").
+func NewToolCallsResponse(model string, toolCalls []ToolCall, usage Usage) *ChatCompletionResponse {
+	response := NewChatCompletionResponse(model, Message{Role: "assistant", ToolCalls: toolCalls}, usage)
+	response.Choices[0].FinishReason = "tool_calls"
+	return response
 }
 
 // ToJSON converts the response to JSON bytes.
@@ -149,7 +163,8 @@ type Delta struct {
 
 // ToolCall represents a tool call in the response.
 type ToolCall struct {
-	// Index is the index of the tool call
+	// Index is the position of the call among the response's tool calls;
+	// streamed deltas use it to tell parallel calls apart
 	Index int `json:"index"`
 
 	// ID is the identifier for the tool call
@@ -182,6 +197,10 @@ type StreamChunk struct {
 
 	// Choices is the list of streaming choices
 	Choices []StreamChoice `json:"choices"`
+
+	// Usage is the request's usage, only on the final chunk of a stream
+	// with stream_options.include_usage (whose choices are empty)
+	Usage *Usage `json:"usage,omitempty"`
 }
 
 // NewStreamChunk creates a new StreamChunk with the given parameters.
@@ -189,7 +208,7 @@ func NewStreamChunk(id string, model string, delta Delta, finishReason *string)
 	return &StreamChunk{
 		ID:      id,
 		Object:  "chat.completion.chunk",
-		Created: time.Now().Unix(),
+		Created: clock.Now().Unix(),
 		Model:   model,
 		Choices: []StreamChoice{
 			{
@@ -201,6 +220,19 @@ func NewStreamChunk(id string, model string, delta Delta, finishReason *string)
 	}
 }
 
+// NewStreamUsageChunk creates the final chunk of a stream with
+// stream_options.include_usage: no choices, only the usage.
+func NewStreamUsageChunk(id string, model string, usage Usage) *StreamChunk {
+	return &StreamChunk{
+		ID:      id,
+		Object:  "chat.completion.chunk",
+		Created: clock.Now().Unix(),
+		Model:   model,
+		Choices: []StreamChoice{},
+		Usage:   &usage,
+	}
+}
+
 // ToSSE converts the chunk to Server-Sent Event format.
 func (c *StreamChunk) ToSSE() (string, error) {
 	data, err := json.Marshal(c)
@@ -251,7 +283,7 @@ func NewCompletionResponse(model string, text string, usage Usage) *CompletionRe
 	return &CompletionResponse{
 		ID:      generateID("cmpl"),
 		Object:  "text_completion",
-		Created: time.Now().Unix(),
+		Created: clock.Now().Unix(),
 		Model:   model,
 		Choices: []CompletionChoice{
 			{
@@ -329,6 +361,90 @@ type ImageResponse struct {
 
 	// Data is the list of generated images
 	Data []ImageData `json:"data"`
+
+	// Background, OutputFormat, Quality and Size echo the resolved options
+	// (gpt-image-1 only)
+	Background   string `json:"background,omitempty"`
+	OutputFormat string `json:"output_format,omitempty"`
+	Quality      string `json:"quality,omitempty"`
+	Size         string `json:"size,omitempty"`
+
+	// Usage is the token usage (gpt-image-1 only)
+	Usage *ImageUsage `json:"usage,omitempty"`
+}
+
+// ImageUsage is the token usage of a gpt-image-1 request.
+type ImageUsage struct {
+	// InputTokens is the number of prompt tokens (text and input images)
+	InputTokens int `json:"input_tokens"`
+
+	// OutputTokens is the number of image tokens generated
+	OutputTokens int `json:"output_tokens"`
+
+	// TotalTokens is InputTokens + OutputTokens
+	TotalTokens int `json:"total_tokens"`
+
+	// InputTokensDetails splits the input tokens
+	InputTokensDetails ImageInputTokensDetails `json:"input_tokens_details"`
+}
+
+// ImageInputTokensDetails splits the input tokens of an image request.
+type ImageInputTokensDetails struct {
+	// TextTokens are prompt text tokens
+	TextTokens int `json:"text_tokens"`
+
+	// ImageTokens are input image tokens (edits)
+	ImageTokens int `json:"image_tokens"`
+}
+
+// imageOutputTokens is the number of image tokens gpt-image-1 generates
+// per image, by quality and size.
+var imageOutputTokens = map[string]map[string]int{
+	"low":    {"1024x1024": 272, "1024x1536": 408, "1536x1024": 400},
+	"medium": {"1024x1024": 1056, "1024x1536": 1584, "1536x1024": 1568},
+	"high":   {"1024x1024": 4160, "1024x1536": 6240, "1536x1024": 6208},
+}
+
+// ImageOutputTokens returns the image tokens one gpt-image-1 image of the
+// given quality and size costs (0 if unknown).
+func ImageOutputTokens(quality, size string) int {
+	return imageOutputTokens[quality][size]
+}
+
+// ImageInputTokens returns the tokens an input image of the given size
+// costs: the image is scaled to fit 2048x2048, then down so its shortest
+// side is at most 768px, and each 512px tile costs 170 tokens plus 85 base.
+func ImageInputTokens(width, height int) int {
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	w, h := float64(width), float64(height)
+	if scale := 2048 / max(w, h); scale < 1 {
+		w, h = w*scale, h*scale
+	}
+	if scale := 768 / min(w, h); scale < 1 {
+		w, h = w*scale, h*scale
+	}
+
+	tiles := int(math.Ceil(w/512)) * int(math.Ceil(h/512))
+	return 85 + 170*tiles
+}
+
+// NewImageUsage creates the usage for n images generated from a prompt of
+// textTokens tokens and input images of imageTokens tokens (edits).
+func NewImageUsage(textTokens, imageTokens, n int, quality, size string) *ImageUsage {
+	inputTokens := textTokens + imageTokens
+	outputTokens := ImageOutputTokens(quality, size) * n
+	return &ImageUsage{
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		TotalTokens:  inputTokens + outputTokens,
+		InputTokensDetails: ImageInputTokensDetails{
+			TextTokens:  textTokens,
+			ImageTokens: imageTokens,
+		},
+	}
 }
 
 // NewImageResponse creates a new ImageResponse.
@@ -341,7 +457,7 @@ func NewImageResponse(urls []string) *ImageResponse {
 	}
 
 	return &ImageResponse{
-		Created: time.Now().Unix(),
+		Created: clock.Now().Unix(),
 		Data:    data,
 	}
 }
@@ -388,11 +504,21 @@ func NewModelsResponse(configs []ModelConfig) *ModelsResponse {
 	}
 }
 
+// NewToolCallID generates a tool call identifier.
+func NewToolCallID() string {
+	return generateID("call")
+}
+
+// NewImageID generates a generated-image identifier.
+func NewImageID() string {
+	return generateID("img")
+}
+
 // generateID generates a unique ID with the given prefix.
 func generateID(prefix string) string {
 	// Format: prefix-<unix-timestamp>-<random-suffix>
 	// Example: chatcmpl-1234567890-abc123
-	timestamp := time.Now().Unix()
+	timestamp := clock.Now().Unix()
 	suffix := generateRandomString(16)
 	return fmt.Sprintf("%s-%d-%s", prefix, timestamp, suffix)
 }
@@ -0,0 +1,76 @@
+package models
+
+import "testing"
+
+func TestImageInputTokens(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          int
+	}{
+		{width: 0, height: 512, want: 0},
+		{width: 512, height: 512, want: 85 + 170},
+		{width: 1024, height: 1024, want: 85 + 170*4},
+		{width: 2048, height: 4096, want: 85 + 170*6},
+		{width: 1536, height: 1024, want: 85 + 170*6},
+	}
+
+	for _, tt := range tests {
+		if got := ImageInputTokens(tt.width, tt.height); got != tt.want {
+			t.Errorf("ImageInputTokens(%d, %d) = %d, want %d", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestNewImageUsage(t *testing.T) {
+	tests := []struct {
+		name        string
+		imageTokens int
+		n           int
+		quality     string
+		size        string
+		wantOutput  int
+	}{
+		{name: "one high square", n: 1, quality: "high", size: "1024x1024", wantOutput: 4160},
+		{name: "two low portraits", n: 2, quality: "low", size: "1024x1536", wantOutput: 2 * 408},
+		{name: "edit", imageTokens: 765, n: 1, quality: "medium", size: "1536x1024", wantOutput: 1568},
+		{name: "unknown size", n: 1, quality: "high", size: "256x256", wantOutput: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := NewImageUsage(12, tt.imageTokens, tt.n, tt.quality, tt.size)
+			if usage.OutputTokens != tt.wantOutput {
+				t.Errorf("OutputTokens = %d, want %d", usage.OutputTokens, tt.wantOutput)
+			}
+			if usage.InputTokens != 12+tt.imageTokens || usage.TotalTokens != usage.InputTokens+usage.OutputTokens {
+				t.Errorf("usage = %+v, want inputs summed into the total", usage)
+			}
+			if usage.InputTokensDetails.TextTokens != 12 || usage.InputTokensDetails.ImageTokens != tt.imageTokens {
+				t.Errorf("InputTokensDetails = %+v", usage.InputTokensDetails)
+			}
+		})
+	}
+}
+
+func TestNewToolCallsResponse(t *testing.T) {
+	calls := []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: "{}"}}}
+	response := NewToolCallsResponse("gpt-4o", calls, Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15})
+
+	if len(response.Choices) != 1 || response.Choices[0].FinishReason != "tool_calls" {
+		t.Fatalf("Choices = %+v, want one choice finishing with tool_calls", response.Choices)
+	}
+	if message := response.Choices[0].Message; message.Role != "assistant" || len(message.ToolCalls) != 1 {
+		t.Errorf("Message = %+v, want the assistant's tool call", message)
+	}
+}
+
+func TestNewStreamUsageChunk(t *testing.T) {
+	chunk := NewStreamUsageChunk("chatcmpl-1", "gpt-4o", Usage{TotalTokens: 15})
+
+	if chunk.Choices == nil || len(chunk.Choices) != 0 {
+		t.Errorf("Choices = %v, want an empty list", chunk.Choices)
+	}
+	if chunk.Usage == nil || chunk.Usage.TotalTokens != 15 {
+		t.Errorf("Usage = %+v, want 15 total tokens", chunk.Usage)
+	}
+}
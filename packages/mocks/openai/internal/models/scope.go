@@ -0,0 +1,101 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines the organization/project scope attached to each request.
+package models
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Header names used by OpenAI clients to select an organization and project.
+const (
+	// HeaderOrganization selects the organization a request is billed to
+	HeaderOrganization = "OpenAI-Organization"
+
+	// HeaderProject selects the project a request is billed to
+	HeaderProject = "OpenAI-Project"
+)
+
+var (
+	// organizationIDPattern matches OpenAI organization IDs (e.g., "org-abc123")
+	organizationIDPattern = regexp.MustCompile(`^org-[A-Za-z0-9]{1,64}$`)
+
+	// projectIDPattern matches OpenAI project IDs (e.g., "proj_abc123")
+	projectIDPattern = regexp.MustCompile(`^proj_[A-Za-z0-9]{1,64}$`)
+)
+
+// RequestScope identifies who a request is attributed to.
+// Usage tracking and rate limiting are scoped by API key, and additionally
+// by organization and project when the client sends those headers.
+type RequestScope struct {
+	// APIKey is the bearer token from the Authorization header
+	APIKey string
+
+	// Organization is the OpenAI-Organization header value (optional)
+	Organization string
+
+	// Project is the OpenAI-Project header value (optional)
+	Project string
+}
+
+// NewRequestScope creates a request scope and validates the organization and project IDs.
+func NewRequestScope(apiKey, organization, project string) (RequestScope, error) {
+	scope := RequestScope{
+		APIKey:       apiKey,
+		Organization: organization,
+		Project:      project,
+	}
+
+	if err := scope.Validate(); err != nil {
+		return RequestScope{}, err
+	}
+
+	return scope, nil
+}
+
+// Validate checks that the organization and project IDs are well-formed.
+// Empty values are valid and mean the header was not sent.
+func (s RequestScope) Validate() error {
+	if s.Organization != "" && !organizationIDPattern.MatchString(s.Organization) {
+		param := HeaderOrganization
+		return NewBadRequestError(
+			fmt.Sprintf("Invalid organization ID '%s'. Organization IDs look like 'org-...'.", s.Organization),
+			&param,
+		)
+	}
+
+	if s.Project != "" && !projectIDPattern.MatchString(s.Project) {
+		param := HeaderProject
+		return NewBadRequestError(
+			fmt.Sprintf("Invalid project ID '%s'. Project IDs look like 'proj_...'.", s.Project),
+			&param,
+		)
+	}
+
+	return nil
+}
+
+// HasOrganization returns true if the request is scoped to an organization.
+func (s RequestScope) HasOrganization() bool {
+	return s.Organization != ""
+}
+
+// HasProject returns true if the request is scoped to a project.
+func (s RequestScope) HasProject() bool {
+	return s.Project != ""
+}
+
+// Key returns the key used to scope per-tenant state (usage, rate limits).
+// Without organization/project headers this is just the API key, so
+// existing single-tenant state is unaffected.
+// Format: <apiKey>[|org:<organization>][|proj:<project>]
+func (s RequestScope) Key() string {
+	key := s.APIKey
+	if s.Organization != "" {
+		key += "|org:" + s.Organization
+	}
+	if s.Project != "" {
+		key += "|proj:" + s.Project
+	}
+	return key
+}
@@ -0,0 +1,53 @@
+package models
+
+import "testing"
+
+func TestNewRequestScope(t *testing.T) {
+	tests := []struct {
+		name         string
+		organization string
+		project      string
+		wantKey      string
+		wantParam    string
+	}{
+		{name: "api key only", wantKey: "sk-test"},
+		{name: "organization", organization: "org-abc123", wantKey: "sk-test|org:org-abc123"},
+		{name: "project", project: "proj_abc123", wantKey: "sk-test|proj:proj_abc123"},
+		{
+			name:         "organization and project",
+			organization: "org-abc123",
+			project:      "proj_abc123",
+			wantKey:      "sk-test|org:org-abc123|proj:proj_abc123",
+		},
+		{name: "invalid organization", organization: "acme", wantParam: HeaderOrganization},
+		{name: "invalid project", project: "proj-abc123", wantParam: HeaderProject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := NewRequestScope("sk-test", tt.organization, tt.project)
+			if tt.wantParam != "" {
+				if err == nil {
+					t.Fatal("NewRequestScope() error = nil, want an error")
+				}
+				if param, _ := errorParam(t, err); param != tt.wantParam {
+					t.Errorf("error param = %q, want %q", param, tt.wantParam)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewRequestScope() error = %v", err)
+			}
+
+			if got := scope.Key(); got != tt.wantKey {
+				t.Errorf("Key() = %q, want %q", got, tt.wantKey)
+			}
+			if scope.HasOrganization() != (tt.organization != "") {
+				t.Errorf("HasOrganization() = %v", scope.HasOrganization())
+			}
+			if scope.HasProject() != (tt.project != "") {
+				t.Errorf("HasProject() = %v", scope.HasProject())
+			}
+		})
+	}
+}
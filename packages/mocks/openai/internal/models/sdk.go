@@ -0,0 +1,185 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines SDK fingerprints derived from request headers.
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Known SDK names.
+const (
+	// SDKOpenAIPython is the official Python SDK (v1+)
+	SDKOpenAIPython = "openai-python"
+
+	// SDKOpenAIPythonLegacy is the pre-1.0 Python SDK ("PythonBindings")
+	SDKOpenAIPythonLegacy = "openai-python-legacy"
+
+	// SDKOpenAINode is the official Node/TypeScript SDK
+	SDKOpenAINode = "openai-node"
+
+	// SDKOpenAIGo is the official Go SDK
+	SDKOpenAIGo = "openai-go"
+
+	// SDKGoOpenAI is the community github.com/sashabaranov/go-openai client
+	SDKGoOpenAI = "go-openai"
+
+	// SDKLangChainPython is LangChain (Python) calling through openai-python
+	SDKLangChainPython = "langchain-python"
+
+	// SDKLangChainJS is LangChain.js calling through openai-node
+	SDKLangChainJS = "langchain-js"
+
+	// SDKUnknown is any other client (curl, raw HTTP libraries)
+	SDKUnknown = "unknown"
+)
+
+// HeaderSDK echoes the detected SDK fingerprint on responses.
+const HeaderSDK = "X-Sentra-SDK"
+
+// HeaderSDKDeprecated explains why the detected SDK is deprecated.
+const HeaderSDKDeprecated = "X-Sentra-SDK-Deprecated"
+
+// SDKFingerprint identifies the client library that sent a request.
+type SDKFingerprint struct {
+	// Name is one of the SDK* constants
+	Name string `json:"name"`
+
+	// Version is the SDK version, if reported
+	Version string `json:"version,omitempty"`
+
+	// UserAgent is the raw User-Agent header
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// String renders the fingerprint as "name/version".
+func (f SDKFingerprint) String() string {
+	if f.Version == "" {
+		return f.Name
+	}
+	return f.Name + "/" + f.Version
+}
+
+// userAgentPatterns map User-Agent formats to SDKs, most specific first.
+var userAgentPatterns = []struct {
+	pattern *regexp.Regexp
+	sdk     string
+}{
+	// LangChain (sdk resolved by langChainSDK)
+	{regexp.MustCompile(`(?i)langchain(?:js|-core)?(?:[/ ]v?(\d[\w.\-]*))?`), ""},
+	{regexp.MustCompile(`OpenAI/v1 PythonBindings/([\w.\-]+)`), SDKOpenAIPythonLegacy},
+	{regexp.MustCompile(`OpenAI/Python ([\w.\-]+)`), SDKOpenAIPython},
+	{regexp.MustCompile(`OpenAI/JS ([\w.\-]+)`), SDKOpenAINode},
+	// openai-node v3 (axios-based)
+	{regexp.MustCompile(`OpenAI/NodeJS/([\w.\-]+)`), SDKOpenAINode},
+	{regexp.MustCompile(`OpenAI/Go ([\w.\-]+)`), SDKOpenAIGo},
+	{regexp.MustCompile(`go-openai(?:/([\w.\-]+))?`), SDKGoOpenAI},
+}
+
+// FingerprintSDK identifies the SDK from the User-Agent and the
+// X-Stainless-* headers sent by the official SDKs. LangChain is detected
+// from the User-Agent and attributed to the language of the SDK underneath.
+func FingerprintSDK(header http.Header) SDKFingerprint {
+	userAgent := header.Get("User-Agent")
+	fingerprint := SDKFingerprint{Name: SDKUnknown, UserAgent: userAgent}
+
+	for _, p := range userAgentPatterns {
+		match := p.pattern.FindStringSubmatch(userAgent)
+		if match == nil {
+			continue
+		}
+
+		fingerprint.Version = match[1]
+		fingerprint.Name = p.sdk
+		if p.sdk == "" {
+			fingerprint.Name = langChainSDK(userAgent, header.Get("X-Stainless-Lang"))
+		}
+		return fingerprint
+	}
+
+	// Official SDKs with a custom User-Agent still send Stainless headers
+	version := header.Get("X-Stainless-Package-Version")
+	switch strings.ToLower(header.Get("X-Stainless-Lang")) {
+	case "python":
+		fingerprint.Name, fingerprint.Version = SDKOpenAIPython, version
+	case "js":
+		fingerprint.Name, fingerprint.Version = SDKOpenAINode, version
+	case "go":
+		fingerprint.Name, fingerprint.Version = SDKOpenAIGo, version
+	}
+
+	return fingerprint
+}
+
+// langChainSDK picks the LangChain flavor from the underlying SDK.
+func langChainSDK(userAgent, stainlessLang string) string {
+	if strings.EqualFold(stainlessLang, "js") || strings.Contains(userAgent, "OpenAI/JS") || strings.Contains(strings.ToLower(userAgent), "langchainjs") {
+		return SDKLangChainJS
+	}
+	return SDKLangChainPython
+}
+
+// MinSupportedSDKVersions is the oldest version of each SDK whose request
+// shape the mock matches. Older versions still work against the mock but
+// use endpoints or parameters the real API has removed.
+var MinSupportedSDKVersions = map[string]string{
+	SDKOpenAIPython:    "1.0.0",
+	SDKOpenAINode:      "4.0.0",
+	SDKGoOpenAI:        "1.20.0",
+	SDKLangChainPython: "0.1.0",
+	SDKLangChainJS:     "0.1.0",
+}
+
+// Deprecation explains why the SDK is deprecated ("" if it is supported
+// or its version is unknown).
+func (f SDKFingerprint) Deprecation() string {
+	if f.Name == SDKOpenAIPythonLegacy {
+		return "openai-python < 1.0 uses the removed v0 API (openai.ChatCompletion); upgrade to openai>=1.0"
+	}
+
+	minVersion, ok := MinSupportedSDKVersions[f.Name]
+	if !ok || f.Version == "" {
+		return ""
+	}
+	if compareVersions(f.Version, minVersion) < 0 {
+		return fmt.Sprintf("%s %s is older than the oldest supported version %s; upgrade to match the current request shape",
+			f.Name, f.Version, minVersion)
+	}
+	return ""
+}
+
+// compareVersions compares dotted numeric versions, ignoring pre-release
+// suffixes ("1.2.0-beta" compares as 1.2.0). Returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	a, _, _ = strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	b, _, _ = strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		na, nb := versionPart(partsA, i), versionPart(partsB, i)
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionPart returns the leading number of parts[i] (0 if absent).
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	digits := strings.IndexFunc(parts[i], func(r rune) bool { return r < '0' || r > '9' })
+	if digits < 0 {
+		digits = len(parts[i])
+	}
+	n, _ := strconv.Atoi(parts[i][:digits])
+	return n
+}
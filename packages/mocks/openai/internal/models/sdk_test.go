@@ -0,0 +1,141 @@
+package models
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFingerprintSDK(t *testing.T) {
+	tests := []struct {
+		name        string
+		userAgent   string
+		stainless   map[string]string
+		wantName    string
+		wantVersion string
+	}{
+		{name: "openai-python", userAgent: "OpenAI/Python 1.40.0", wantName: SDKOpenAIPython, wantVersion: "1.40.0"},
+		{name: "openai-python legacy", userAgent: "OpenAI/v1 PythonBindings/0.28.1", wantName: SDKOpenAIPythonLegacy, wantVersion: "0.28.1"},
+		{name: "openai-node", userAgent: "OpenAI/JS 4.52.0", wantName: SDKOpenAINode, wantVersion: "4.52.0"},
+		{name: "openai-node v3", userAgent: "OpenAI/NodeJS/3.3.0", wantName: SDKOpenAINode, wantVersion: "3.3.0"},
+		{name: "openai-go", userAgent: "OpenAI/Go 0.1.0-alpha.5", wantName: SDKOpenAIGo, wantVersion: "0.1.0-alpha.5"},
+		{name: "go-openai", userAgent: "go-openai/1.28.0", wantName: SDKGoOpenAI, wantVersion: "1.28.0"},
+		{
+			name:        "langchain python",
+			userAgent:   "langchain-core/0.2.10 OpenAI/Python 1.40.0",
+			wantName:    SDKLangChainPython,
+			wantVersion: "0.2.10",
+		},
+		{
+			name:        "langchain js",
+			userAgent:   "langchainjs/0.2.5",
+			wantName:    SDKLangChainJS,
+			wantVersion: "0.2.5",
+		},
+		{
+			name:      "langchain over openai-node",
+			userAgent: "LangChain OpenAI/JS 4.52.0",
+			wantName:  SDKLangChainJS,
+		},
+		{
+			name:        "custom user agent with stainless headers",
+			userAgent:   "my-app/1.0",
+			stainless:   map[string]string{"X-Stainless-Lang": "python", "X-Stainless-Package-Version": "1.35.0"},
+			wantName:    SDKOpenAIPython,
+			wantVersion: "1.35.0",
+		},
+		{name: "curl", userAgent: "curl/8.4.0", wantName: SDKUnknown},
+		{name: "no user agent", wantName: SDKUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.userAgent != "" {
+				header.Set("User-Agent", tt.userAgent)
+			}
+			for key, value := range tt.stainless {
+				header.Set(key, value)
+			}
+
+			got := FingerprintSDK(header)
+			if got.Name != tt.wantName || got.Version != tt.wantVersion {
+				t.Errorf("FingerprintSDK(%q) = %s (%q), want %s (%q)", tt.userAgent, got.Name, got.Version, tt.wantName, tt.wantVersion)
+			}
+			if got.UserAgent != tt.userAgent {
+				t.Errorf("UserAgent = %q, want %q", got.UserAgent, tt.userAgent)
+			}
+		})
+	}
+}
+
+func TestSDKFingerprintString(t *testing.T) {
+	tests := []struct {
+		fingerprint SDKFingerprint
+		want        string
+	}{
+		{fingerprint: SDKFingerprint{Name: SDKOpenAIPython, Version: "1.40.0"}, want: "openai-python/1.40.0"},
+		{fingerprint: SDKFingerprint{Name: SDKUnknown}, want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.fingerprint.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSDKFingerprintDeprecation(t *testing.T) {
+	tests := []struct {
+		name        string
+		fingerprint SDKFingerprint
+		wantContain string
+	}{
+		{name: "legacy python", fingerprint: SDKFingerprint{Name: SDKOpenAIPythonLegacy, Version: "0.28.1"}, wantContain: "openai>=1.0"},
+		{name: "old node", fingerprint: SDKFingerprint{Name: SDKOpenAINode, Version: "3.3.0"}, wantContain: "older than the oldest supported version 4.0.0"},
+		{name: "old go-openai", fingerprint: SDKFingerprint{Name: SDKGoOpenAI, Version: "1.9.1"}, wantContain: "1.20.0"},
+		{name: "supported", fingerprint: SDKFingerprint{Name: SDKOpenAIPython, Version: "1.40.0"}},
+		{name: "pre-release of the minimum", fingerprint: SDKFingerprint{Name: SDKOpenAINode, Version: "4.0.0-beta.1"}},
+		{name: "unknown version", fingerprint: SDKFingerprint{Name: SDKOpenAINode}},
+		{name: "unknown sdk", fingerprint: SDKFingerprint{Name: SDKUnknown, Version: "0.0.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fingerprint.Deprecation()
+			if tt.wantContain == "" {
+				if got != "" {
+					t.Errorf("Deprecation() = %q, want none", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.wantContain) {
+				t.Errorf("Deprecation() = %q, want it to contain %q", got, tt.wantContain)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "v1.10.0", b: "1.9.0", want: 1},
+		{a: "0.28.1", b: "1.0.0", want: -1},
+		{a: "4.0.0-rc.1", b: "4.0.0", want: 0},
+		{a: "2", b: "10", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := compareVersions(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file implements deterministic responses for requests with a seed:
+// the fixture, latency jitter and generated IDs are derived from the seed,
+// and responses carry a stable system_fingerprint, so identical requests
+// get identical responses.
+package models
+
+import (
+	"context"
+	"encoding/hex"
+	"hash/fnv"
+	"math/rand"
+)
+
+// seedContextKey stores the seed of the request being served
+type seedContextKey struct{}
+
+// seededIDLength is the length of the random part of a seeded ID
+const seededIDLength = 24
+
+// WithSeed returns a copy of ctx carrying the request's seed, which the
+// latency simulator derives jitter from.
+func WithSeed(ctx context.Context, seed int) context.Context {
+	return context.WithValue(ctx, seedContextKey{}, seed)
+}
+
+// SeedFromContext retrieves the request's seed from ctx, if it has one.
+func SeedFromContext(ctx context.Context) (int, bool) {
+	seed, ok := ctx.Value(seedContextKey{}).(int)
+	return seed, ok
+}
+
+// NewSeededRand returns a random source derived from a request's seed.
+// Each use creates its own source, so what one draws does not depend on
+// what another drew before it.
+func NewSeededRand(seed int) *rand.Rand {
+	return rand.New(rand.NewSource(int64(seed)))
+}
+
+// SeededID generates an ID with the given prefix from rng. Unlike
+// generateID it has no timestamp, so the same seed gives the same ID.
+func SeededID(prefix string, rng *rand.Rand) string {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	b := make([]byte, seededIDLength)
+	for i := range b {
+		b[i] = charset[rng.Intn(len(charset))]
+	}
+	return prefix + "-" + string(b)
+}
+
+// SystemFingerprint returns the model's system_fingerprint: "fp_" and 10
+// hex characters, stable for a model as production's is for a backend
+// configuration.
+func SystemFingerprint(model string) string {
+	h := fnv.New64a()
+	h.Write([]byte("sentra-mock:" + model))
+	return "fp_" + hex.EncodeToString(h.Sum(nil))[:10]
+}
+
+// ApplySeed makes the response deterministic for a request with seed: its
+// ID and tool call IDs are derived from the seed and it carries the
+// model's system_fingerprint. Only created still follows the clock.
+func (r *ChatCompletionResponse) ApplySeed(seed int) {
+	rng := NewSeededRand(seed)
+	r.ID = SeededID("chatcmpl", rng)
+	for i := range r.Choices {
+		for j := range r.Choices[i].Message.ToolCalls {
+			r.Choices[i].Message.ToolCalls[j].ID = SeededID("call", rng)
+		}
+	}
+
+	fingerprint := SystemFingerprint(r.Model)
+	r.SystemFingerprint = &fingerprint
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestSeedContext(t *testing.T) {
+	if _, ok := SeedFromContext(context.Background()); ok {
+		t.Error("SeedFromContext() found a seed in an empty context")
+	}
+
+	seed, ok := SeedFromContext(WithSeed(context.Background(), 42))
+	if !ok || seed != 42 {
+		t.Errorf("SeedFromContext() = (%d, %v), want (42, true)", seed, ok)
+	}
+}
+
+func TestSeededID(t *testing.T) {
+	pattern := regexp.MustCompile(`^chatcmpl-[A-Za-z0-9]{24}$`)
+
+	tests := []struct {
+		name      string
+		seedA     int
+		seedB     int
+		wantEqual bool
+	}{
+		{name: "same seed", seedA: 7, seedB: 7, wantEqual: true},
+		{name: "different seeds", seedA: 7, seedB: 8, wantEqual: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := SeededID("chatcmpl", NewSeededRand(tt.seedA))
+			b := SeededID("chatcmpl", NewSeededRand(tt.seedB))
+			if !pattern.MatchString(a) {
+				t.Errorf("SeededID() = %q, want it to match %s", a, pattern)
+			}
+			if (a == b) != tt.wantEqual {
+				t.Errorf("SeededID() = %q and %q, want equal %v", a, b, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestSystemFingerprint(t *testing.T) {
+	pattern := regexp.MustCompile(`^fp_[0-9a-f]{10}$`)
+
+	tests := []struct {
+		model string
+	}{
+		{model: "gpt-4o"},
+		{model: "gpt-4o-mini"},
+		{model: ""},
+	}
+
+	seen := make(map[string]string)
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			fingerprint := SystemFingerprint(tt.model)
+			if !pattern.MatchString(fingerprint) {
+				t.Errorf("SystemFingerprint(%q) = %q, want it to match %s", tt.model, fingerprint, pattern)
+			}
+			if again := SystemFingerprint(tt.model); again != fingerprint {
+				t.Errorf("SystemFingerprint(%q) = %q then %q, want stable", tt.model, fingerprint, again)
+			}
+			if other, ok := seen[fingerprint]; ok {
+				t.Errorf("SystemFingerprint(%q) = SystemFingerprint(%q)", tt.model, other)
+			}
+			seen[fingerprint] = tt.model
+		})
+	}
+}
+
+func TestApplySeed(t *testing.T) {
+	newResponse := func() *ChatCompletionResponse {
+		return &ChatCompletionResponse{
+			ID:    "chatcmpl-random",
+			Model: "gpt-4o",
+			Choices: []Choice{{Message: Message{
+				Role:      "assistant",
+				ToolCalls: []ToolCall{{ID: "call_random"}, {ID: "call_random2"}},
+			}}},
+		}
+	}
+
+	a, b := newResponse(), newResponse()
+	a.ApplySeed(42)
+	b.ApplySeed(42)
+
+	if a.ID != b.ID || a.ID == "chatcmpl-random" {
+		t.Errorf("IDs = %q and %q, want the same seeded ID", a.ID, b.ID)
+	}
+	for i, call := range a.Choices[0].Message.ToolCalls {
+		if call.ID != b.Choices[0].Message.ToolCalls[i].ID {
+			t.Errorf("tool call %d IDs = %q and %q, want equal", i, call.ID, b.Choices[0].Message.ToolCalls[i].ID)
+		}
+	}
+	if a.Choices[0].Message.ToolCalls[0].ID == a.Choices[0].Message.ToolCalls[1].ID {
+		t.Error("tool calls share an ID, want distinct IDs")
+	}
+	if a.SystemFingerprint == nil || *a.SystemFingerprint != SystemFingerprint("gpt-4o") {
+		t.Errorf("SystemFingerprint = %v, want %q", a.SystemFingerprint, SystemFingerprint("gpt-4o"))
+	}
+}
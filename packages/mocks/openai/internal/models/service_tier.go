@@ -0,0 +1,95 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines service tiers (processing priority classes).
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ServiceTier is the processing tier a request runs in.
+// OpenAI serves "priority" and "scale" traffic ahead of "default" traffic,
+// and "flex" traffic only with spare capacity (slower, may be rejected).
+type ServiceTier string
+
+const (
+	// ServiceTierAuto lets the server pick the tier (resolves to default)
+	ServiceTierAuto ServiceTier = "auto"
+
+	// ServiceTierDefault is standard processing
+	ServiceTierDefault ServiceTier = "default"
+
+	// ServiceTierFlex is low-priority processing with higher latency
+	ServiceTierFlex ServiceTier = "flex"
+
+	// ServiceTierPriority is low-latency processing ahead of default traffic
+	ServiceTierPriority ServiceTier = "priority"
+
+	// ServiceTierScale is reserved-capacity processing (treated like priority)
+	ServiceTierScale ServiceTier = "scale"
+)
+
+// HeaderServiceTier overrides the service tier for a request.
+// Useful for clients that cannot set service_tier in the request body.
+const HeaderServiceTier = "X-Sentra-Service-Tier"
+
+// ErrorCodeResourceUnavailable is returned when flex capacity is exhausted.
+const ErrorCodeResourceUnavailable = "resource_unavailable"
+
+// serviceTierRanks orders tiers for scheduling (lower runs first).
+var serviceTierRanks = map[ServiceTier]int{
+	ServiceTierPriority: 0,
+	ServiceTierScale:    0,
+	ServiceTierDefault:  1,
+	ServiceTierFlex:     2,
+}
+
+// ParseServiceTier parses a service tier name (case-insensitive).
+// An empty string parses as ServiceTierAuto.
+func ParseServiceTier(value string) (ServiceTier, error) {
+	if value == "" {
+		return ServiceTierAuto, nil
+	}
+
+	tier := ServiceTier(strings.ToLower(strings.TrimSpace(value)))
+	if tier == ServiceTierAuto {
+		return tier, nil
+	}
+	if _, ok := serviceTierRanks[tier]; !ok {
+		param := "service_tier"
+		return "", NewBadRequestError(
+			fmt.Sprintf("Invalid value for 'service_tier': '%s'. Supported values are: 'auto', 'default', 'flex', 'priority' and 'scale'.", value),
+			&param,
+		)
+	}
+
+	return tier, nil
+}
+
+// Resolve returns the tier a request actually runs in ("auto" and unknown
+// tiers run as default).
+func (t ServiceTier) Resolve() ServiceTier {
+	if _, ok := serviceTierRanks[t]; ok {
+		return t
+	}
+	return ServiceTierDefault
+}
+
+// Rank returns the scheduling rank of the tier (lower runs first).
+func (t ServiceTier) Rank() int {
+	return serviceTierRanks[t.Resolve()]
+}
+
+// NewResourceUnavailableError creates the error returned when a flex
+// request cannot be scheduled.
+func NewResourceUnavailableError(retryAfter time.Duration) APIError {
+	code := ErrorCodeResourceUnavailable
+	return APIError{
+		Type:       ErrorTypeRateLimit,
+		Message:    "Resource unavailable. Flex processing capacity is currently exhausted; please retry later or use the default service tier.",
+		Code:       &code,
+		StatusCode: 429,
+		RetryAfter: int(retryAfter.Seconds()),
+	}
+}
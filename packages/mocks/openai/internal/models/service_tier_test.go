@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseServiceTier(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ServiceTier
+		wantErr bool
+	}{
+		{input: "", want: ServiceTierAuto},
+		{input: "auto", want: ServiceTierAuto},
+		{input: "default", want: ServiceTierDefault},
+		{input: " Flex ", want: ServiceTierFlex},
+		{input: "PRIORITY", want: ServiceTierPriority},
+		{input: "scale", want: ServiceTierScale},
+		{input: "batch", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseServiceTier(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseServiceTier(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil {
+				if param, _ := errorParam(t, err); param != "service_tier" {
+					t.Errorf("error param = %q, want service_tier", param)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("ParseServiceTier(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceTierResolveAndRank(t *testing.T) {
+	tests := []struct {
+		tier        ServiceTier
+		wantResolve ServiceTier
+		wantRank    int
+	}{
+		{tier: ServiceTierPriority, wantResolve: ServiceTierPriority, wantRank: 0},
+		{tier: ServiceTierScale, wantResolve: ServiceTierScale, wantRank: 0},
+		{tier: ServiceTierDefault, wantResolve: ServiceTierDefault, wantRank: 1},
+		{tier: ServiceTierAuto, wantResolve: ServiceTierDefault, wantRank: 1},
+		{tier: "", wantResolve: ServiceTierDefault, wantRank: 1},
+		{tier: ServiceTierFlex, wantResolve: ServiceTierFlex, wantRank: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tier), func(t *testing.T) {
+			if got := tt.tier.Resolve(); got != tt.wantResolve {
+				t.Errorf("Resolve() = %q, want %q", got, tt.wantResolve)
+			}
+			if got := tt.tier.Rank(); got != tt.wantRank {
+				t.Errorf("Rank() = %d, want %d", got, tt.wantRank)
+			}
+		})
+	}
+}
+
+func TestNewResourceUnavailableError(t *testing.T) {
+	err := NewResourceUnavailableError(30 * time.Second)
+
+	if err.StatusCode != 429 || err.RetryAfter != 30 {
+		t.Errorf("status = %d, retry after = %d, want 429 and 30", err.StatusCode, err.RetryAfter)
+	}
+	if _, code := errorParam(t, err); code != ErrorCodeResourceUnavailable {
+		t.Errorf("code = %q, want %q", code, ErrorCodeResourceUnavailable)
+	}
+}
@@ -0,0 +1,139 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines text-to-speech requests (/v1/audio/speech).
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Speech request limits (matching the API).
+const (
+	// MaxSpeechInputChars is the maximum input length
+	MaxSpeechInputChars = 4096
+
+	// MinSpeechSpeed and MaxSpeechSpeed bound speed
+	MinSpeechSpeed = 0.25
+	MaxSpeechSpeed = 4.0
+)
+
+// SpeechVoices are the voices every speech model supports.
+var SpeechVoices = []string{"alloy", "ash", "coral", "echo", "fable", "nova", "onyx", "sage", "shimmer"}
+
+// GPT4oMiniTTSVoices are the voices only gpt-4o-mini-tts supports.
+var GPT4oMiniTTSVoices = []string{"ballad", "verse"}
+
+// SpeechFormats are the supported response formats.
+var SpeechFormats = []string{"mp3", "opus", "aac", "flac", "wav", "pcm"}
+
+// SpeechRequest is the body of POST /v1/audio/speech.
+type SpeechRequest struct {
+	// Model is tts-1, tts-1-hd or gpt-4o-mini-tts
+	Model string `json:"model"`
+
+	// Input is the text to speak (at most 4096 characters)
+	Input string `json:"input"`
+
+	// Voice is the voice to speak with
+	Voice string `json:"voice"`
+
+	// Instructions control the voice's tone (gpt-4o-mini-tts only)
+	Instructions *string `json:"instructions,omitempty"`
+
+	// ResponseFormat is the audio format (default "mp3")
+	ResponseFormat *string `json:"response_format,omitempty"`
+
+	// Speed is the playback speed, 0.25 to 4.0 (default 1.0)
+	Speed *float64 `json:"speed,omitempty"`
+}
+
+// Validate validates the speech request.
+func (r *SpeechRequest) Validate() error {
+	if r.Model == "" {
+		param := "model"
+		return NewBadRequestError("Missing required parameter: 'model'.", &param)
+	}
+
+	if r.Input == "" {
+		param := "input"
+		return NewBadRequestError("Missing required parameter: 'input'.", &param)
+	}
+	if n := utf8.RuneCountInString(r.Input); n > MaxSpeechInputChars {
+		param := "input"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'input': string too long. Expected a string with maximum length %d, but got a string with length %d instead.", MaxSpeechInputChars, n), &param)
+	}
+
+	if !slices.Contains(SpeechVoices, r.Voice) && !slices.Contains(GPT4oMiniTTSVoices, r.Voice) {
+		param := "voice"
+		voices := append(append([]string{}, SpeechVoices...), GPT4oMiniTTSVoices...)
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", r.Voice, quoteList(voices)), &param)
+	}
+
+	if r.ResponseFormat != nil && !slices.Contains(SpeechFormats, *r.ResponseFormat) {
+		param := "response_format"
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", *r.ResponseFormat, quoteList(SpeechFormats)), &param)
+	}
+
+	if r.Speed != nil && (*r.Speed < MinSpeechSpeed || *r.Speed > MaxSpeechSpeed) {
+		param := "speed"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'speed': expected a value between %g and %g, but got %g instead.", MinSpeechSpeed, MaxSpeechSpeed, *r.Speed), &param)
+	}
+
+	return nil
+}
+
+// ValidateModel checks the model serves /v1/audio/speech, and supports the
+// requested voice and instructions.
+func (r *SpeechRequest) ValidateModel(config ModelConfig) error {
+	if err := requireModelType(config, r.Model, ModelTypeSpeech, "/v1/audio/speech"); err != nil {
+		return err
+	}
+	if config.ID == "gpt-4o-mini-tts" {
+		return nil
+	}
+
+	if slices.Contains(GPT4oMiniTTSVoices, r.Voice) {
+		param := "voice"
+		return NewBadRequestError(fmt.Sprintf("Voice '%s' is not supported by %s. Use gpt-4o-mini-tts.", r.Voice, r.Model), &param)
+	}
+	if r.Instructions != nil && *r.Instructions != "" {
+		param := "instructions"
+		return NewBadRequestError(fmt.Sprintf("Instructions are not supported by %s. Use gpt-4o-mini-tts.", r.Model), &param)
+	}
+	return nil
+}
+
+// GetEffectiveResponseFormat returns the audio format (default "mp3").
+func (r *SpeechRequest) GetEffectiveResponseFormat() string {
+	if r.ResponseFormat != nil {
+		return *r.ResponseFormat
+	}
+	return "mp3"
+}
+
+// GetEffectiveSpeed returns the playback speed (default 1.0).
+func (r *SpeechRequest) GetEffectiveSpeed() float64 {
+	if r.Speed != nil {
+		return *r.Speed
+	}
+	return 1.0
+}
+
+// Characters returns the number of billed input characters.
+func (r *SpeechRequest) Characters() int {
+	return utf8.RuneCountInString(r.Input)
+}
+
+// quoteList formats values as "'a', 'b' and 'c'", like the API's errors.
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + value + "'"
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpeechRequestValidate(t *testing.T) {
+	format := func(s string) *string { return &s }
+	speed := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name      string
+		req       SpeechRequest
+		wantParam string
+	}{
+		{name: "valid", req: SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "alloy"}},
+		{name: "newer voice", req: SpeechRequest{Model: "gpt-4o-mini-tts", Input: "Hello", Voice: "ballad"}},
+		{name: "all options", req: SpeechRequest{Model: "tts-1-hd", Input: "Hello", Voice: "nova", ResponseFormat: format("wav"), Speed: speed(4)}},
+		{name: "missing model", req: SpeechRequest{Input: "Hello", Voice: "alloy"}, wantParam: "model"},
+		{name: "missing input", req: SpeechRequest{Model: "tts-1", Voice: "alloy"}, wantParam: "input"},
+		{name: "input too long", req: SpeechRequest{Model: "tts-1", Input: strings.Repeat("é", MaxSpeechInputChars+1), Voice: "alloy"}, wantParam: "input"},
+		{name: "input at the limit", req: SpeechRequest{Model: "tts-1", Input: strings.Repeat("é", MaxSpeechInputChars), Voice: "alloy"}},
+		{name: "unknown voice", req: SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "siri"}, wantParam: "voice"},
+		{name: "unknown format", req: SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "alloy", ResponseFormat: format("ogg")}, wantParam: "response_format"},
+		{name: "too slow", req: SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "alloy", Speed: speed(0.1)}, wantParam: "speed"},
+		{name: "too fast", req: SpeechRequest{Model: "tts-1", Input: "Hello", Voice: "alloy", Speed: speed(4.5)}, wantParam: "speed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestSpeechRequestValidateModel(t *testing.T) {
+	instructions := "Speak cheerfully"
+
+	tests := []struct {
+		name      string
+		req       SpeechRequest
+		wantParam string
+		wantErr   bool
+	}{
+		{name: "tts-1", req: SpeechRequest{Model: "tts-1", Voice: "alloy"}},
+		{name: "gpt-4o-mini-tts voice", req: SpeechRequest{Model: "gpt-4o-mini-tts", Voice: "verse", Instructions: &instructions}},
+		{name: "voice needs gpt-4o-mini-tts", req: SpeechRequest{Model: "tts-1", Voice: "ballad"}, wantParam: "voice", wantErr: true},
+		{name: "instructions need gpt-4o-mini-tts", req: SpeechRequest{Model: "tts-1-hd", Voice: "alloy", Instructions: &instructions}, wantParam: "instructions", wantErr: true},
+		{name: "chat model", req: SpeechRequest{Model: "gpt-4o", Voice: "alloy"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := GetModelConfig(tt.req.Model)
+			if err != nil {
+				t.Fatalf("GetModelConfig(%q) error = %v", tt.req.Model, err)
+			}
+
+			err = tt.req.ValidateModel(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateModel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantParam != "" {
+				if param, _ := errorParam(t, err); param != tt.wantParam {
+					t.Errorf("error param = %q, want %q", param, tt.wantParam)
+				}
+			}
+		})
+	}
+}
+
+func TestSpeechRequestDefaults(t *testing.T) {
+	req := SpeechRequest{Input: "Grüße"}
+	if got := req.GetEffectiveResponseFormat(); got != "mp3" {
+		t.Errorf("GetEffectiveResponseFormat() = %q, want mp3", got)
+	}
+	if got := req.GetEffectiveSpeed(); got != 1.0 {
+		t.Errorf("GetEffectiveSpeed() = %v, want 1", got)
+	}
+	if got := req.Characters(); got != 5 {
+		t.Errorf("Characters() = %d, want 5", got)
+	}
+}
+
+func TestQuoteList(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{values: nil, want: ""},
+		{values: []string{"a"}, want: "'a'"},
+		{values: []string{"a", "b"}, want: "'a' and 'b'"},
+		{values: []string{"a", "b", "c"}, want: "'a', 'b' and 'c'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := quoteList(tt.values); got != tt.want {
+				t.Errorf("quoteList(%v) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file implements a low-allocation encoder for streaming chunks.
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SSEDone is the final Server-Sent Event of every stream.
+var SSEDone = []byte("data: [DONE]\n\n")
+
+// Precomputed JSON fragments shared by every stream chunk.
+var (
+	chunkSuffixNull = []byte(`,"finish_reason":null}]}` + "\n\n")
+	chunkEmptyDelta = []byte(`{}`)
+
+	// With stream_options.include_usage every chunk but the last carries
+	// a null usage
+	chunkSuffixNullUsage = []byte(`,"finish_reason":null}],"usage":null}` + "\n\n")
+)
+
+// StreamEncoder writes chat completion chunks as SSE frames.
+// The fields that are constant for a stream (id, object, created, model,
+// system_fingerprint) are encoded once, so each content chunk only has to
+// append its delta. Output is byte-identical to StreamChunk.ToSSE, except
+// for the "usage":null IncludeUsage adds to every chunk.
+type StreamEncoder struct {
+	// head is everything up to and including `"choices":`; prefix
+	// continues it up to and including `"delta":`
+	head   []byte
+	prefix []byte
+
+	// suffixNull closes a chunk without a finish reason, end one with it
+	suffixNull []byte
+	end        []byte
+}
+
+// NewStreamEncoder creates an encoder for a single stream.
+func NewStreamEncoder(id string, model string, created int64, systemFingerprint *string) *StreamEncoder {
+	prefix := make([]byte, 0, 160)
+	prefix = append(prefix, `data: {"id":`...)
+	prefix = appendJSONString(prefix, id)
+	prefix = append(prefix, `,"object":"chat.completion.chunk","created":`...)
+	prefix = strconv.AppendInt(prefix, created, 10)
+	prefix = append(prefix, `,"model":`...)
+	prefix = appendJSONString(prefix, model)
+	if systemFingerprint != nil {
+		prefix = append(prefix, `,"system_fingerprint":`...)
+		prefix = appendJSONString(prefix, *systemFingerprint)
+	}
+	prefix = append(prefix, `,"choices":`...)
+	head := prefix[:len(prefix):len(prefix)]
+	prefix = append(prefix, `[{"index":0,"delta":`...)
+
+	return &StreamEncoder{
+		head:       head,
+		prefix:     prefix,
+		suffixNull: chunkSuffixNull,
+		end:        []byte("}]}\n\n"),
+	}
+}
+
+// IncludeUsage makes the chunks carry "usage": null, as production does
+// for streams with stream_options.include_usage. End the stream with
+// AppendUsage.
+func (e *StreamEncoder) IncludeUsage() {
+	e.suffixNull = chunkSuffixNullUsage
+	e.end = []byte(`}],"usage":null}` + "\n\n")
+}
+
+// AppendUsage appends the final chunk of a stream with
+// stream_options.include_usage: empty choices and the request's usage.
+func (e *StreamEncoder) AppendUsage(dst []byte, usage Usage) []byte {
+	dst = append(dst, e.head...)
+	dst = append(dst, `[],"usage":{"prompt_tokens":`...)
+	dst = strconv.AppendInt(dst, int64(usage.PromptTokens), 10)
+	dst = append(dst, `,"completion_tokens":`...)
+	dst = strconv.AppendInt(dst, int64(usage.CompletionTokens), 10)
+	dst = append(dst, `,"total_tokens":`...)
+	dst = strconv.AppendInt(dst, int64(usage.TotalTokens), 10)
+	return append(dst, "}}\n\n"...)
+}
+
+// AppendRole appends the opening chunk carrying the assistant role.
+func (e *StreamEncoder) AppendRole(dst []byte, role string) []byte {
+	dst = append(dst, e.prefix...)
+	dst = append(dst, `{"role":`...)
+	dst = appendJSONString(dst, role)
+	dst = append(dst, '}')
+	return append(dst, e.suffixNull...)
+}
+
+// AppendContent appends a chunk carrying a content delta.
+func (e *StreamEncoder) AppendContent(dst []byte, content string) []byte {
+	dst = append(dst, e.prefix...)
+	if content == "" {
+		dst = append(dst, chunkEmptyDelta...)
+	} else {
+		dst = append(dst, `{"content":`...)
+		dst = appendJSONString(dst, content)
+		dst = append(dst, '}')
+	}
+	return append(dst, e.suffixNull...)
+}
+
+// AppendFinish appends the final chunk with an empty delta and the finish reason.
+func (e *StreamEncoder) AppendFinish(dst []byte, finishReason string) []byte {
+	dst = append(dst, e.prefix...)
+	dst = append(dst, chunkEmptyDelta...)
+	dst = append(dst, `,"finish_reason":`...)
+	dst = appendJSONString(dst, finishReason)
+	return append(dst, e.end...)
+}
+
+// AppendToolCallStart appends the chunk opening a tool call: its index, ID,
+// type and function name, with empty arguments.
+func (e *StreamEncoder) AppendToolCallStart(dst []byte, index int, id string, name string) []byte {
+	dst = append(dst, e.prefix...)
+	dst = append(dst, `{"tool_calls":[{"index":`...)
+	dst = strconv.AppendInt(dst, int64(index), 10)
+	dst = append(dst, `,"id":`...)
+	dst = appendJSONString(dst, id)
+	dst = append(dst, `,"type":"function","function":{"name":`...)
+	dst = appendJSONString(dst, name)
+	dst = append(dst, `,"arguments":""}}]}`...)
+	return append(dst, e.suffixNull...)
+}
+
+// AppendToolCallArguments appends a chunk carrying a fragment of the
+// arguments of the tool call at index.
+func (e *StreamEncoder) AppendToolCallArguments(dst []byte, index int, fragment string) []byte {
+	dst = append(dst, e.prefix...)
+	dst = append(dst, `{"tool_calls":[{"index":`...)
+	dst = strconv.AppendInt(dst, int64(index), 10)
+	dst = append(dst, `,"function":{"arguments":`...)
+	dst = appendJSONString(dst, fragment)
+	dst = append(dst, `}}]}`...)
+	return append(dst, e.suffixNull...)
+}
+
+// AppendToolCalls appends the full delta sequence for toolCalls, one call
+// after another: each call's opening chunk, then its arguments split into
+// fragments of at most fragmentSize bytes (split on UTF-8 boundaries).
+// The caller appends AppendFinish(dst, "tool_calls") afterwards.
+func (e *StreamEncoder) AppendToolCalls(dst []byte, toolCalls []ToolCall, fragmentSize int) []byte {
+	if fragmentSize <= 0 {
+		fragmentSize = len(`{"location":`)
+	}
+
+	for i, call := range toolCalls {
+		dst = e.AppendToolCallStart(dst, i, call.ID, call.Function.Name)
+
+		arguments := call.Function.Arguments
+		for len(arguments) > 0 {
+			n := min(fragmentSize, len(arguments))
+			for n < len(arguments) && !utf8.RuneStart(arguments[n]) {
+				n++
+			}
+			dst = e.AppendToolCallArguments(dst, i, arguments[:n])
+			arguments = arguments[n:]
+		}
+	}
+
+	return dst
+}
+
+// AppendChunk appends an arbitrary chunk (e.g., tool call deltas).
+// This is the slow path and falls back to encoding/json.
+func (e *StreamEncoder) AppendChunk(dst []byte, chunk *StreamChunk) ([]byte, error) {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, "data: "...)
+	dst = append(dst, data...)
+	return append(dst, "\n\n"...), nil
+}
+
+// hexDigits is used for \u escapes.
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaping exactly like encoding/json
+// (including HTML-safe escaping of <, > and &).
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
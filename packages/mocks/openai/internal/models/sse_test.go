@@ -0,0 +1,159 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStreamEncoderMatchesToSSE(t *testing.T) {
+	fingerprint := "fp_0123456789"
+	stop := "stop"
+
+	tests := []struct {
+		name        string
+		fingerprint *string
+		encode      func(e *StreamEncoder) []byte
+		delta       Delta
+		finish      *string
+	}{
+		{
+			name:   "role",
+			encode: func(e *StreamEncoder) []byte { return e.AppendRole(nil, "assistant") },
+			delta:  Delta{Role: "assistant"},
+		},
+		{
+			name:   "content",
+			encode: func(e *StreamEncoder) []byte { return e.AppendContent(nil, "Hello, world") },
+			delta:  Delta{Content: "Hello, world"},
+		},
+		{
+			name:   "escaped content",
+			encode: func(e *StreamEncoder) []byte { return e.AppendContent(nil, "a \"quote\"\n<b>&\t\u2028\x01") },
+			delta:  Delta{Content: "a \"quote\"\n<b>&\t\u2028\x01"},
+		},
+		{
+			name:   "invalid utf-8",
+			encode: func(e *StreamEncoder) []byte { return e.AppendContent(nil, "bad \xff byte") },
+			delta:  Delta{Content: "bad \xff byte"},
+		},
+		{
+			name:   "empty content",
+			encode: func(e *StreamEncoder) []byte { return e.AppendContent(nil, "") },
+		},
+		{
+			name:   "finish",
+			encode: func(e *StreamEncoder) []byte { return e.AppendFinish(nil, "stop") },
+			finish: &stop,
+		},
+		{
+			name:        "system fingerprint",
+			fingerprint: &fingerprint,
+			encode:      func(e *StreamEncoder) []byte { return e.AppendContent(nil, "Hi") },
+			delta:       Delta{Content: "Hi"},
+		},
+		{
+			name:   "tool call start",
+			encode: func(e *StreamEncoder) []byte { return e.AppendToolCallStart(nil, 1, "call_abc", "get_weather") },
+			delta: Delta{ToolCalls: []ToolCall{{
+				Index:    1,
+				ID:       "call_abc",
+				Type:     "function",
+				Function: FunctionCall{Name: "get_weather"},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewStreamEncoder("chatcmpl-123", "gpt-4o", 1700000000, tt.fingerprint)
+			got := tt.encode(e)
+
+			chunk := &StreamChunk{
+				ID:                "chatcmpl-123",
+				Object:            "chat.completion.chunk",
+				Created:           1700000000,
+				Model:             "gpt-4o",
+				SystemFingerprint: tt.fingerprint,
+				Choices:           []StreamChoice{{Delta: tt.delta, FinishReason: tt.finish}},
+			}
+			want, err := chunk.ToSSE()
+			if err != nil {
+				t.Fatalf("ToSSE() error = %v", err)
+			}
+			if string(got) != want {
+				t.Errorf("encoder wrote\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestStreamEncoderIncludeUsage(t *testing.T) {
+	e := NewStreamEncoder("chatcmpl-123", "gpt-4o", 1700000000, nil)
+	e.IncludeUsage()
+
+	frames := [][]byte{
+		e.AppendContent(nil, "Hi"),
+		e.AppendFinish(nil, "stop"),
+	}
+	for _, frame := range frames {
+		var chunk map[string]json.RawMessage
+		if err := json.Unmarshal(bytes.TrimPrefix(bytes.TrimSpace(frame), []byte("data: ")), &chunk); err != nil {
+			t.Fatalf("frame %q is not JSON: %v", frame, err)
+		}
+		if string(chunk["usage"]) != "null" {
+			t.Errorf("frame %q usage = %s, want null", frame, chunk["usage"])
+		}
+	}
+
+	usage := e.AppendUsage(nil, Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15})
+	var chunk StreamChunk
+	if err := json.Unmarshal(bytes.TrimPrefix(bytes.TrimSpace(usage), []byte("data: ")), &chunk); err != nil {
+		t.Fatalf("usage frame %q is not JSON: %v", usage, err)
+	}
+	if len(chunk.Choices) != 0 || chunk.Usage == nil || chunk.Usage.TotalTokens != 15 {
+		t.Errorf("usage chunk = %+v, want empty choices and 15 total tokens", chunk)
+	}
+}
+
+func TestStreamEncoderAppendToolCalls(t *testing.T) {
+	tests := []struct {
+		name         string
+		arguments    string
+		fragmentSize int
+		wantFrames   int
+	}{
+		{name: "single fragment", arguments: `{"a":1}`, fragmentSize: 64, wantFrames: 2},
+		{name: "split", arguments: `{"location":"Paris"}`, fragmentSize: 5, wantFrames: 1 + 4},
+		{name: "utf-8 boundaries", arguments: `{"city":"Zürich"}`, fragmentSize: 11, wantFrames: 1 + 2},
+		{name: "default size", arguments: `{"location":"Paris"}`, fragmentSize: 0, wantFrames: 1 + 2},
+		{name: "no arguments", arguments: "", fragmentSize: 5, wantFrames: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewStreamEncoder("chatcmpl-123", "gpt-4o", 1700000000, nil)
+			calls := []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "f", Arguments: tt.arguments}}}
+			out := e.AppendToolCalls(nil, calls, tt.fragmentSize)
+
+			frames := strings.Split(strings.TrimSuffix(string(out), "\n\n"), "\n\n")
+			if len(frames) != tt.wantFrames {
+				t.Fatalf("got %d frames, want %d:\n%s", len(frames), tt.wantFrames, out)
+			}
+
+			// Reassembling the fragments gives the arguments back
+			var arguments strings.Builder
+			for _, frame := range frames {
+				var chunk StreamChunk
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(frame, "data: ")), &chunk); err != nil {
+					t.Fatalf("frame %q is not JSON: %v", frame, err)
+				}
+				arguments.WriteString(chunk.Choices[0].Delta.ToolCalls[0].Function.Arguments)
+			}
+			if arguments.String() != tt.arguments {
+				t.Errorf("reassembled arguments = %q, want %q", arguments.String(), tt.arguments)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines structured outputs (response_format json_schema) and
+// checks schemas against the API's strict mode rules.
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ResponseFormatTypes are the response_format types.
+var ResponseFormatTypes = []string{"text", "json_object", "json_schema"}
+
+// schemaNamePattern matches valid json_schema names
+var schemaNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// JSONSchemaFormat is the schema of a json_schema response format.
+type JSONSchemaFormat struct {
+	// Name identifies the schema (letters, digits, _ and -; up to 64)
+	Name string `json:"name"`
+
+	// Description tells the model what the output is for (optional)
+	Description string `json:"description,omitempty"`
+
+	// Schema is the JSON Schema the output follows
+	Schema map[string]interface{} `json:"schema,omitempty"`
+
+	// Strict enforces the schema exactly, which requires a schema in the
+	// supported subset (see validateStrictSchema)
+	Strict *bool `json:"strict,omitempty"`
+}
+
+// IsStrict returns true if strict mode is on.
+func (f *JSONSchemaFormat) IsStrict() bool {
+	return f != nil && f.Strict != nil && *f.Strict
+}
+
+// Validate checks the response format's type and, for json_schema, its
+// schema.
+func (f *ResponseFormat) Validate() error {
+	param := "response_format.type"
+	if !slices.Contains(ResponseFormatTypes, f.Type) {
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", f.Type, quoteList(ResponseFormatTypes)), &param)
+	}
+	if f.Type != "json_schema" {
+		return nil
+	}
+
+	if f.JSONSchema == nil {
+		param = "response_format.json_schema"
+		return NewBadRequestError("Missing required parameter: 'response_format.json_schema'.", &param)
+	}
+	if f.JSONSchema.Name == "" {
+		param = "response_format.json_schema.name"
+		return NewBadRequestError("Missing required parameter: 'response_format.json_schema.name'.", &param)
+	}
+	if !schemaNamePattern.MatchString(f.JSONSchema.Name) {
+		param = "response_format.json_schema.name"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'response_format.json_schema.name': string does not match pattern. Expected a string that matches the pattern '%s'.", schemaNamePattern), &param)
+	}
+
+	if f.JSONSchema.IsStrict() {
+		if err := validateStrictSchema(f.JSONSchema.Schema); err != nil {
+			param = "response_format"
+			return NewBadRequestError(fmt.Sprintf("Invalid schema for response_format '%s': %s", f.JSONSchema.Name, err), &param)
+		}
+	}
+	return nil
+}
+
+// validateStrictSchema checks the rules strict mode adds: the root is an
+// object, and every object lists all its properties as required and sets
+// additionalProperties to false.
+func validateStrictSchema(schema map[string]interface{}) error {
+	if schemaType, _ := schema["type"].(string); schemaType != "object" {
+		return fmt.Errorf("schema must be a JSON Schema of 'type: \"object\"', got 'type: \"%s\"'.", schemaType)
+	}
+	return validateStrictSubschema(schema, nil)
+}
+
+// validateStrictSubschema checks schema, found at path, and the schemas
+// nested in it.
+func validateStrictSubschema(schema map[string]interface{}, path []string) error {
+	if schemaType, _ := schema["type"].(string); schemaType == "object" || schema["properties"] != nil {
+		if additional, ok := schema["additionalProperties"].(bool); !ok || additional {
+			return fmt.Errorf("In context=%s, 'additionalProperties' is required to be supplied and to be false.", schemaContext(path))
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		required := make(map[string]bool)
+		if list, ok := schema["required"].([]interface{}); ok {
+			for _, name := range list {
+				if name, ok := name.(string); ok {
+					required[name] = true
+				}
+			}
+		}
+		for _, name := range sortedKeys(properties) {
+			if !required[name] {
+				return fmt.Errorf("In context=%s, 'required' is required to be supplied and to be an array including every key in properties. Missing '%s'.", schemaContext(path), name)
+			}
+		}
+
+		for _, name := range sortedKeys(properties) {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				if err := validateStrictSubschema(property, append(slices.Clip(path), "properties", name)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		if err := validateStrictSubschema(items, append(slices.Clip(path), "items")); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range []string{"anyOf", "$defs", "definitions"} {
+		switch nested := schema[key].(type) {
+		case []interface{}:
+			for i, option := range nested {
+				if option, ok := option.(map[string]interface{}); ok {
+					if err := validateStrictSubschema(option, append(slices.Clip(path), key, fmt.Sprintf("%d", i))); err != nil {
+						return err
+					}
+				}
+			}
+		case map[string]interface{}:
+			for _, name := range sortedKeys(nested) {
+				if definition, ok := nested[name].(map[string]interface{}); ok {
+					if err := validateStrictSubschema(definition, append(slices.Clip(path), key, name)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// schemaContext formats a schema path as the API does: a Python tuple,
+// e.g. ('properties', 'address').
+func schemaContext(path []string) string {
+	quoted := make([]string, len(path))
+	for i, segment := range path {
+		quoted[i] = "'" + segment + "'"
+	}
+	if len(quoted) == 1 {
+		return "(" + quoted[0] + ",)"
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// sortedKeys returns a map's keys in order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
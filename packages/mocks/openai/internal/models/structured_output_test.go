@@ -0,0 +1,158 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestResponseFormatValidate(t *testing.T) {
+	strict, lax := true, false
+
+	// schema decodes a JSON schema
+	schema := func(s string) map[string]interface{} {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			t.Fatalf("invalid schema %s: %v", s, err)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name        string
+		format      ResponseFormat
+		wantParam   string
+		wantMessage string
+	}{
+		{name: "text", format: ResponseFormat{Type: "text"}},
+		{name: "json object", format: ResponseFormat{Type: "json_object"}},
+		{name: "unknown type", format: ResponseFormat{Type: "xml"}, wantParam: "response_format.type"},
+		{name: "missing json_schema", format: ResponseFormat{Type: "json_schema"}, wantParam: "response_format.json_schema"},
+		{
+			name:      "missing name",
+			format:    ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{}},
+			wantParam: "response_format.json_schema.name",
+		},
+		{
+			name:      "invalid name",
+			format:    ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{Name: "my schema"}},
+			wantParam: "response_format.json_schema.name",
+		},
+		{
+			name: "non-strict schema is not checked",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &lax,
+				Schema: schema(`{"type":"object","properties":{"a":{"type":"string"}}}`),
+			}},
+		},
+		{
+			name: "strict schema",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"array","items":{"type":"object","properties":{"c":{"type":"integer"}},"required":["c"],"additionalProperties":false}}},"required":["a","b"],"additionalProperties":false}`),
+			}},
+		},
+		{
+			name: "strict root is not an object",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"array"}`),
+			}},
+			wantParam:   "response_format",
+			wantMessage: `got 'type: "array"'`,
+		},
+		{
+			name: "strict without additionalProperties",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"object","properties":{"a":{"type":"string"}},"required":["a"]}`),
+			}},
+			wantParam:   "response_format",
+			wantMessage: "In context=(), 'additionalProperties' is required",
+		},
+		{
+			name: "strict with a property not required",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"string"}},"required":["a"],"additionalProperties":false}`),
+			}},
+			wantParam:   "response_format",
+			wantMessage: "Missing 'b'",
+		},
+		{
+			name: "strict nested object",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"object","properties":{"address":{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}},"required":["address"],"additionalProperties":false}`),
+			}},
+			wantParam:   "response_format",
+			wantMessage: "In context=('properties', 'address')",
+		},
+		{
+			name: "strict definitions",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"object","properties":{},"additionalProperties":false,"$defs":{"step":{"type":"object","properties":{}}}}`),
+			}},
+			wantParam:   "response_format",
+			wantMessage: "In context=('$defs', 'step')",
+		},
+		{
+			name: "strict anyOf",
+			format: ResponseFormat{Type: "json_schema", JSONSchema: &JSONSchemaFormat{
+				Name:   "answer",
+				Strict: &strict,
+				Schema: schema(`{"type":"object","properties":{"v":{"anyOf":[{"type":"string"},{"type":"object","properties":{}}]}},"required":["v"],"additionalProperties":false}`),
+			}},
+			wantParam:   "response_format",
+			wantMessage: "In context=('properties', 'v', 'anyOf', '1')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.format.Validate()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestSchemaContext(t *testing.T) {
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{path: nil, want: "()"},
+		{path: []string{"items"}, want: "('items',)"},
+		{path: []string{"properties", "address"}, want: "('properties', 'address')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := schemaContext(tt.path); got != tt.want {
+				t.Errorf("schemaContext(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
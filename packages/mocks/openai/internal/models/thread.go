@@ -0,0 +1,308 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines Assistants API thread, message and run types.
+package models
+
+import (
+	"fmt"
+)
+
+// MaxThreadMetadataPairs is the maximum number of metadata key-value pairs.
+const MaxThreadMetadataPairs = 16
+
+// Thread is a conversation in the Assistants API.
+type Thread struct {
+	// ID is the thread identifier
+	ID string `json:"id"`
+
+	// Object is always "thread"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the thread was created
+	CreatedAt int64 `json:"created_at"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata"`
+}
+
+// ThreadMessageText is the text of a message content part.
+type ThreadMessageText struct {
+	// Value is the message text
+	Value string `json:"value"`
+
+	// Annotations are file citations (always empty in the mock)
+	Annotations []interface{} `json:"annotations"`
+}
+
+// ThreadMessageContent is one content part of a thread message.
+type ThreadMessageContent struct {
+	// Type is always "text"
+	Type string `json:"type"`
+
+	// Text is the text content
+	Text ThreadMessageText `json:"text"`
+}
+
+// ThreadMessage is a message in a thread.
+type ThreadMessage struct {
+	// ID is the message identifier
+	ID string `json:"id"`
+
+	// Object is always "thread.message"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the message was created
+	CreatedAt int64 `json:"created_at"`
+
+	// ThreadID is the thread the message belongs to
+	ThreadID string `json:"thread_id"`
+
+	// Role is "user" or "assistant"
+	Role string `json:"role"`
+
+	// Content holds the message text
+	Content []ThreadMessageContent `json:"content"`
+
+	// AssistantID is set on messages written by a run
+	AssistantID *string `json:"assistant_id"`
+
+	// RunID is set on messages written by a run
+	RunID *string `json:"run_id"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata"`
+}
+
+// NewThreadID generates a thread identifier.
+func NewThreadID() string {
+	return generateID("thread")
+}
+
+// NewRunID generates a run identifier.
+func NewRunID() string {
+	return generateID("run")
+}
+
+// NewThreadMessage creates a text message in a thread.
+func NewThreadMessage(threadID, role, text string, metadata map[string]string, createdAt int64) ThreadMessage {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	return ThreadMessage{
+		ID:        generateID("msg"),
+		Object:    "thread.message",
+		CreatedAt: createdAt,
+		ThreadID:  threadID,
+		Role:      role,
+		Content: []ThreadMessageContent{
+			{Type: "text", Text: ThreadMessageText{Value: text, Annotations: []interface{}{}}},
+		},
+		Metadata: metadata,
+	}
+}
+
+// Text returns the concatenated text of the message.
+func (m ThreadMessage) Text() string {
+	var text string
+	for _, part := range m.Content {
+		text += part.Text.Value
+	}
+	return text
+}
+
+// RunStatus is the lifecycle state of a run.
+type RunStatus string
+
+const (
+	// RunStatusRequiresAction means the run waits for the caller to submit
+	// the outputs of its tool calls
+	RunStatusRequiresAction RunStatus = "requires_action"
+
+	// RunStatusCompleted means the run finished and wrote its reply
+	RunStatusCompleted RunStatus = "completed"
+
+	// RunStatusCancelled means the run was cancelled before completing
+	RunStatusCancelled RunStatus = "cancelled"
+)
+
+// IsActive returns true while a run blocks its thread.
+func (s RunStatus) IsActive() bool {
+	return s == RunStatusRequiresAction
+}
+
+// RunRequiredAction is what a run needs from the caller to continue.
+type RunRequiredAction struct {
+	// Type is always "submit_tool_outputs"
+	Type string `json:"type"`
+
+	// SubmitToolOutputs holds the tool calls awaiting outputs
+	SubmitToolOutputs RunToolCalls `json:"submit_tool_outputs"`
+}
+
+// RunToolCalls are the tool calls a run made.
+type RunToolCalls struct {
+	// ToolCalls are the calls to answer
+	ToolCalls []ToolCall `json:"tool_calls"`
+}
+
+// Run executes an assistant on a thread.
+type Run struct {
+	// ID is the run identifier
+	ID string `json:"id"`
+
+	// Object is always "thread.run"
+	Object string `json:"object"`
+
+	// CreatedAt is the Unix timestamp when the run was created
+	CreatedAt int64 `json:"created_at"`
+
+	// ThreadID is the thread the run executed on
+	ThreadID string `json:"thread_id"`
+
+	// AssistantID is the assistant that ran
+	AssistantID string `json:"assistant_id"`
+
+	// Status is the run state
+	Status RunStatus `json:"status"`
+
+	// RequiredAction is set while the run requires action
+	RequiredAction *RunRequiredAction `json:"required_action"`
+
+	// Model is the model the run used
+	Model string `json:"model"`
+
+	// Instructions are the system instructions the run used
+	Instructions string `json:"instructions"`
+
+	// Tools are the tools the run could call
+	Tools []AssistantTool `json:"tools"`
+
+	// CompletedAt is set once the run completes
+	CompletedAt *int64 `json:"completed_at"`
+
+	// CancelledAt is set once the run is cancelled
+	CancelledAt *int64 `json:"cancelled_at"`
+
+	// Usage is the token usage of the run
+	Usage *Usage `json:"usage"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata"`
+}
+
+// ThreadMessageRequest is the body of POST /v1/threads/{thread_id}/messages,
+// and an entry of CreateThreadRequest.Messages.
+type ThreadMessageRequest struct {
+	// Role is "user" or "assistant"
+	Role string `json:"role"`
+
+	// Content is the message text
+	Content string `json:"content"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate validates the message request.
+func (r *ThreadMessageRequest) Validate() error {
+	if r.Role != "user" && r.Role != "assistant" {
+		param := "role"
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: 'user' and 'assistant'.", r.Role), &param)
+	}
+
+	if r.Content == "" {
+		param := "content"
+		return NewBadRequestError("Message content must not be empty.", &param)
+	}
+
+	return validateMetadata(r.Metadata)
+}
+
+// CreateThreadRequest is the body of POST /v1/threads.
+type CreateThreadRequest struct {
+	// Messages seed the thread (optional)
+	Messages []ThreadMessageRequest `json:"messages,omitempty"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate validates the thread request.
+func (r *CreateThreadRequest) Validate() error {
+	for i := range r.Messages {
+		if err := r.Messages[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return validateMetadata(r.Metadata)
+}
+
+// CreateRunRequest is the body of POST /v1/threads/{thread_id}/runs.
+type CreateRunRequest struct {
+	// AssistantID is the assistant to run
+	AssistantID string `json:"assistant_id"`
+
+	// Model overrides the assistant's model
+	Model string `json:"model,omitempty"`
+
+	// Instructions override the assistant's instructions
+	Instructions string `json:"instructions,omitempty"`
+
+	// Tools override the assistant's tools
+	Tools []AssistantTool `json:"tools,omitempty"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate validates the run request.
+func (r *CreateRunRequest) Validate() error {
+	if r.AssistantID == "" {
+		param := "assistant_id"
+		return NewBadRequestError("Missing required parameter: 'assistant_id'.", &param)
+	}
+	if err := validateAssistantTools(r.Tools); err != nil {
+		return err
+	}
+	return validateMetadata(r.Metadata)
+}
+
+// ToolOutput is the output of one of a run's tool calls.
+type ToolOutput struct {
+	// ToolCallID is the call answered
+	ToolCallID string `json:"tool_call_id"`
+
+	// Output is the call's result
+	Output string `json:"output"`
+}
+
+// SubmitToolOutputsRequest is the body of
+// POST /v1/threads/{thread_id}/runs/{run_id}/submit_tool_outputs.
+type SubmitToolOutputsRequest struct {
+	// ToolOutputs answer the run's tool calls
+	ToolOutputs []ToolOutput `json:"tool_outputs"`
+}
+
+// Validate validates the tool outputs request.
+func (r *SubmitToolOutputsRequest) Validate() error {
+	if len(r.ToolOutputs) == 0 {
+		param := "tool_outputs"
+		return NewBadRequestError("Missing required parameter: 'tool_outputs'.", &param)
+	}
+	for i, output := range r.ToolOutputs {
+		if output.ToolCallID == "" {
+			param := fmt.Sprintf("tool_outputs[%d].tool_call_id", i)
+			return NewBadRequestError("Missing required parameter: 'tool_outputs[].tool_call_id'.", &param)
+		}
+	}
+	return nil
+}
+
+// validateMetadata enforces the API's metadata limits.
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxThreadMetadataPairs {
+		param := "metadata"
+		return NewBadRequestError(fmt.Sprintf("Invalid 'metadata': too many properties. Expected an object with at most %d properties, but got an object with %d properties instead.", MaxThreadMetadataPairs, len(metadata)), &param)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+// metadataPairs returns metadata with n key-value pairs.
+func metadataPairs(n int) map[string]string {
+	metadata := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		metadata[fmt.Sprintf("key%d", i)] = "value"
+	}
+	return metadata
+}
+
+func TestNewThreadMessage(t *testing.T) {
+	m := NewThreadMessage("thread-1", "user", "Hello", nil, 1700000000)
+
+	if m.Object != "thread.message" || m.ThreadID != "thread-1" || m.Role != "user" || m.CreatedAt != 1700000000 {
+		t.Errorf("NewThreadMessage() = %+v", m)
+	}
+	if m.Metadata == nil {
+		t.Error("Metadata = nil, want an empty map")
+	}
+	if got := m.Text(); got != "Hello" {
+		t.Errorf("Text() = %q, want Hello", got)
+	}
+
+	m.Content = append(m.Content, ThreadMessageContent{Type: "text", Text: ThreadMessageText{Value: ", world"}})
+	if got := m.Text(); got != "Hello, world" {
+		t.Errorf("Text() = %q, want %q", got, "Hello, world")
+	}
+}
+
+func TestRunStatusIsActive(t *testing.T) {
+	tests := []struct {
+		status RunStatus
+		want   bool
+	}{
+		{status: RunStatusRequiresAction, want: true},
+		{status: RunStatusCompleted, want: false},
+		{status: RunStatusCancelled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsActive(); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThreadRequestsValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       interface{ Validate() error }
+		wantParam string
+	}{
+		{name: "message", req: &ThreadMessageRequest{Role: "user", Content: "Hi"}},
+		{name: "message metadata at limit", req: &ThreadMessageRequest{Role: "assistant", Content: "Hi", Metadata: metadataPairs(MaxThreadMetadataPairs)}},
+		{name: "message role", req: &ThreadMessageRequest{Role: "system", Content: "Hi"}, wantParam: "role"},
+		{name: "message content", req: &ThreadMessageRequest{Role: "user"}, wantParam: "content"},
+		{name: "message metadata", req: &ThreadMessageRequest{Role: "user", Content: "Hi", Metadata: metadataPairs(MaxThreadMetadataPairs + 1)}, wantParam: "metadata"},
+		{name: "empty thread", req: &CreateThreadRequest{}},
+		{name: "thread messages", req: &CreateThreadRequest{Messages: []ThreadMessageRequest{{Role: "user", Content: "Hi"}, {Role: "tool", Content: "Hi"}}}, wantParam: "role"},
+		{name: "thread metadata", req: &CreateThreadRequest{Metadata: metadataPairs(MaxThreadMetadataPairs + 1)}, wantParam: "metadata"},
+		{name: "run", req: &CreateRunRequest{AssistantID: "asst-1", Tools: []AssistantTool{{Type: "code_interpreter"}}}},
+		{name: "run assistant", req: &CreateRunRequest{}, wantParam: "assistant_id"},
+		{name: "run tools", req: &CreateRunRequest{AssistantID: "asst-1", Tools: []AssistantTool{{Type: "browser"}}}, wantParam: "tools[0].type"},
+		{name: "tool outputs", req: &SubmitToolOutputsRequest{ToolOutputs: []ToolOutput{{ToolCallID: "call_1", Output: "42"}}}},
+		{name: "no tool outputs", req: &SubmitToolOutputsRequest{}, wantParam: "tool_outputs"},
+		{name: "tool output call id", req: &SubmitToolOutputsRequest{ToolOutputs: []ToolOutput{{ToolCallID: "call_1"}, {Output: "42"}}}, wantParam: "tool_outputs[1].tool_call_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package models provides core data structures for the OpenAI mock server.
+// This file defines multi-part message content (text and image_url parts)
+// and counts image input tokens with OpenAI's tile formula.
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"  // registers GIF for image.DecodeConfig
+	_ "image/jpeg" // registers JPEG for image.DecodeConfig
+	_ "image/png"  // registers PNG for image.DecodeConfig
+	"slices"
+	"strings"
+)
+
+// ContentPart is one part of a message's content array.
+type ContentPart struct {
+	// Type is the part type
+	Type string `json:"type" enum:"text,image_url"`
+
+	// Text is the text (text parts only)
+	Text string `json:"text,omitempty"`
+
+	// ImageURL is the image (image_url parts only)
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL is an image input: an http(s) URL or a base64 data URL.
+type ImageURL struct {
+	// URL is the image location or a data:image/...;base64 URL
+	URL string `json:"url"`
+
+	// Detail is the resolution the model sees the image at (default "auto")
+	Detail string `json:"detail,omitempty" enum:"auto,low,high"`
+}
+
+// ContentPartTypes are the message content part types.
+var ContentPartTypes = []string{"text", "image_url"}
+
+// ImageDetails are the image_url detail levels.
+var ImageDetails = []string{"auto", "low", "high"}
+
+// Image tiling, per OpenAI's vision pricing: images are scaled to fit
+// 2048x2048, then so their shortest side is at most 768, and cost a base
+// amount plus an amount per 512x512 tile. Low detail costs the base only.
+const (
+	imageMaxSide       = 2048
+	imageShortSide     = 768
+	imageTileSide      = 512
+	imageBaseTokens    = 85
+	imageTokensPerTile = 170
+)
+
+// imageUnknownSide is the side assumed for images whose size the mock
+// cannot read: it never fetches URLs, so remote images count as
+// 1024x1024.
+const imageUnknownSide = 1024
+
+// imageTokenCosts overrides the base and per-tile cost for models that
+// price images differently ({base, per tile}).
+var imageTokenCosts = map[string][2]int{
+	"gpt-4o-mini": {2833, 5667},
+}
+
+// HasImages returns true if any message has an image part.
+func HasImages(messages []Message) bool {
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if part.Type == "image_url" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ImageTokens returns the input tokens the images in messages cost with
+// model.
+func ImageTokens(messages []Message, model string) int {
+	base, perTile := imageBaseTokens, imageTokensPerTile
+	if config, err := GetModelConfig(model); err == nil {
+		model = config.ID
+	}
+	if costs, ok := imageTokenCosts[model]; ok {
+		base, perTile = costs[0], costs[1]
+	}
+
+	tokens := 0
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if part.Type != "image_url" || part.ImageURL == nil {
+				continue
+			}
+			if part.ImageURL.Detail == "low" {
+				tokens += base
+				continue
+			}
+			width, height := part.ImageURL.Size()
+			tokens += base + perTile*imageTiles(width, height)
+		}
+	}
+	return tokens
+}
+
+// Size returns the image's dimensions, decoded from a data URL's header,
+// or 1024x1024 when they cannot be read.
+func (u ImageURL) Size() (int, int) {
+	if !strings.HasPrefix(u.URL, "data:") {
+		return imageUnknownSide, imageUnknownSide
+	}
+	_, data, ok := strings.Cut(u.URL, ";base64,")
+	if !ok {
+		return imageUnknownSide, imageUnknownSide
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return imageUnknownSide, imageUnknownSide
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(decoded))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return imageUnknownSide, imageUnknownSide
+	}
+	return config.Width, config.Height
+}
+
+// imageTiles returns the 512x512 tiles a high-detail image is split into.
+func imageTiles(width, height int) int {
+	w, h := float64(width), float64(height)
+	if longest := max(w, h); longest > imageMaxSide {
+		w, h = w*imageMaxSide/longest, h*imageMaxSide/longest
+	}
+	if shortest := min(w, h); shortest > imageShortSide {
+		w, h = w*imageShortSide/shortest, h*imageShortSide/shortest
+	}
+	return ceilDiv(int(w+0.5), imageTileSide) * ceilDiv(int(h+0.5), imageTileSide)
+}
+
+// ceilDiv divides rounding up.
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// validateContentPart checks a content part of message i.
+func validateContentPart(i, j int, role string, part ContentPart) error {
+	switch part.Type {
+	case "text":
+		return nil
+	case "image_url":
+	default:
+		param := fmt.Sprintf("messages.[%d].content.[%d].type", i, j)
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", part.Type, quoteList(ContentPartTypes)), &param)
+	}
+
+	if role != "user" {
+		param := fmt.Sprintf("messages.[%d].content.[%d].type", i, j)
+		return NewBadRequestError(fmt.Sprintf("Image URLs are only allowed for messages with role 'user', but this message with role '%s' contains an image URL.", role), &param)
+	}
+	if part.ImageURL == nil || part.ImageURL.URL == "" {
+		param := fmt.Sprintf("messages.[%d].content.[%d].image_url.url", i, j)
+		return NewBadRequestError(fmt.Sprintf("Missing required parameter: '%s'.", param), &param)
+	}
+
+	url := part.ImageURL.URL
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") &&
+		!(strings.HasPrefix(url, "data:image/") && strings.Contains(url, ";base64,")) {
+		param := fmt.Sprintf("messages.[%d].content.[%d].image_url.url", i, j)
+		return NewBadRequestError("Invalid image URL: expected an http(s) URL or a base64 data URL (data:image/...;base64,...).", &param)
+	}
+
+	if detail := part.ImageURL.Detail; detail != "" && !slices.Contains(ImageDetails, detail) {
+		param := fmt.Sprintf("messages.[%d].content.[%d].image_url.detail", i, j)
+		return NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Supported values are: %s.", detail, quoteList(ImageDetails)), &param)
+	}
+	return nil
+}
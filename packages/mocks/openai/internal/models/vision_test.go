@@ -0,0 +1,142 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngDataURL returns a data URL of a blank PNG of the given size.
+func pngDataURL(t *testing.T, width, height int) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestImageURLSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantWidth  int
+		wantHeight int
+	}{
+		{name: "data url", url: pngDataURL(t, 640, 480), wantWidth: 640, wantHeight: 480},
+		{name: "remote url", url: "https://example.com/cat.png", wantWidth: 1024, wantHeight: 1024},
+		{name: "not base64", url: "data:image/png,raw", wantWidth: 1024, wantHeight: 1024},
+		{name: "corrupt base64", url: "data:image/png;base64,!!!", wantWidth: 1024, wantHeight: 1024},
+		{name: "not an image", url: "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("text")), wantWidth: 1024, wantHeight: 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := ImageURL{URL: tt.url}.Size()
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("Size() = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestImageTiles(t *testing.T) {
+	// Examples from OpenAI's vision pricing documentation
+	tests := []struct {
+		width, height int
+		want          int
+	}{
+		{width: 1024, height: 1024, want: 4},
+		{width: 2048, height: 4096, want: 6},
+		{width: 512, height: 512, want: 1},
+		{width: 100, height: 100, want: 1},
+		{width: 4096, height: 8192, want: 6},
+		{width: 1920, height: 1080, want: 6},
+	}
+
+	for _, tt := range tests {
+		if got := imageTiles(tt.width, tt.height); got != tt.want {
+			t.Errorf("imageTiles(%d, %d) = %d, want %d", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestImageTokens(t *testing.T) {
+	image := func(url, detail string) Message {
+		return Message{Role: "user", Parts: []ContentPart{
+			{Type: "text", Text: "What is this?"},
+			{Type: "image_url", ImageURL: &ImageURL{URL: url, Detail: detail}},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		messages []Message
+		model    string
+		want     int
+	}{
+		{name: "no images", messages: []Message{{Role: "user", Content: "Hi"}}, model: "gpt-4o", want: 0},
+		{name: "low detail", messages: []Message{image("https://example.com/a.png", "low")}, model: "gpt-4o", want: 85},
+		{name: "remote image", messages: []Message{image("https://example.com/a.png", "high")}, model: "gpt-4o", want: 85 + 170*4},
+		{name: "small data url", messages: []Message{image(pngDataURL(t, 256, 256), "auto")}, model: "gpt-4o", want: 85 + 170},
+		{
+			name:     "two images",
+			messages: []Message{image("https://example.com/a.png", "low"), image("https://example.com/b.png", "")},
+			model:    "gpt-4o",
+			want:     85 + 85 + 170*4,
+		},
+		{name: "gpt-4o-mini costs", messages: []Message{image("https://example.com/a.png", "high")}, model: "gpt-4o-mini", want: 2833 + 5667*4},
+		{name: "gpt-4o-mini snapshot", messages: []Message{image("https://example.com/a.png", "low")}, model: "gpt-4o-mini-2024-07-18", want: 2833},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageTokens(tt.messages, tt.model); got != tt.want {
+				t.Errorf("ImageTokens() = %d, want %d", got, tt.want)
+			}
+			if got := HasImages(tt.messages); got != (tt.want > 0) {
+				t.Errorf("HasImages() = %v, want %v", got, tt.want > 0)
+			}
+		})
+	}
+}
+
+func TestValidateContentPart(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      string
+		part      ContentPart
+		wantParam string
+	}{
+		{name: "text", role: "system", part: ContentPart{Type: "text", Text: "Hi"}},
+		{name: "https image", role: "user", part: ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/a.png"}}},
+		{name: "data url image", role: "user", part: ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: "data:image/png;base64,AAAA", Detail: "low"}}},
+		{name: "unknown type", role: "user", part: ContentPart{Type: "audio"}, wantParam: "messages.[1].content.[2].type"},
+		{name: "image from assistant", role: "assistant", part: ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/a.png"}}, wantParam: "messages.[1].content.[2].type"},
+		{name: "missing url", role: "user", part: ContentPart{Type: "image_url"}, wantParam: "messages.[1].content.[2].image_url.url"},
+		{name: "ftp url", role: "user", part: ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: "ftp://example.com/a.png"}}, wantParam: "messages.[1].content.[2].image_url.url"},
+		{name: "data url without base64", role: "user", part: ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: "data:image/png,raw"}}, wantParam: "messages.[1].content.[2].image_url.url"},
+		{name: "invalid detail", role: "user", part: ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/a.png", Detail: "ultra"}}, wantParam: "messages.[1].content.[2].image_url.detail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateContentPart(1, 2, tt.role, tt.part)
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("validateContentPart() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("validateContentPart() error = nil, want an error")
+			}
+			if param, _ := errorParam(t, err); param != tt.wantParam {
+				t.Errorf("error param = %q, want %q", param, tt.wantParam)
+			}
+		})
+	}
+}
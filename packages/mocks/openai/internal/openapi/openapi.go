@@ -0,0 +1,423 @@
+// Package openapi builds OpenAPI 3.1 documents from the mock's Go request
+// and response models, so the published spec describes exactly the JSON the
+// handlers bind and return rather than a hand-maintained copy.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.1.0"
+
+// Document is an OpenAPI document. Fields are declared in the order they
+// are conventionally written.
+type Document struct {
+	OpenAPI    string                          `json:"openapi" yaml:"openapi"`
+	Info       Info                            `json:"info" yaml:"info"`
+	Servers    []Server                        `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Tags       []Tag                           `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Paths      map[string]map[string]*PathItem `json:"paths" yaml:"paths"`
+	Components Components                      `json:"components" yaml:"components"`
+}
+
+// YAML renders the document as YAML with two-space indentation.
+func (d *Document) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Server is a base URL the API is served at.
+type Server struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Tag groups operations.
+type Tag struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// PathItem is one operation (a method on a path).
+type PathItem struct {
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+	OperationID string               `json:"operationId" yaml:"operationId"`
+	Parameters  []Parameter          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses" yaml:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name     string  `json:"name" yaml:"name"`
+	In       string  `json:"in" yaml:"in"`
+	Required bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   *Schema `json:"schema" yaml:"schema"`
+}
+
+// RequestBody is an operation's body, by content type.
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// Response is an operation's response, by content type.
+type Response struct {
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+// Components holds the schemas referenced by operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas" yaml:"schemas"`
+}
+
+// Schema is a JSON Schema (the OpenAPI 3.1 dialect). The empty schema
+// accepts any value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+}
+
+// Operation describes an endpoint for Generator.Add. Request and Response are zero
+// values of the model types (nil for none); a request with form tags is
+// documented as multipart/form-data.
+type Operation struct {
+	// Method and Path identify the endpoint; Path uses gin syntax (:param)
+	Method string
+	Path   string
+
+	// Summary is a one-line description
+	Summary string
+
+	// Tag groups the operation
+	Tag string
+
+	// Query lists the query parameters the handler reads
+	Query []string
+
+	// Request is the body model
+	Request interface{}
+
+	// Files lists multipart file fields read outside Request
+	Files []string
+
+	// Response is the 200 response model
+	Response interface{}
+
+	// Streaming is set when the endpoint can also answer with server-sent
+	// events (stream=true)
+	Streaming bool
+}
+
+// Generator builds a document, collecting the component schemas of the
+// models that operations reference.
+type Generator struct {
+	doc     *Document
+	schemas map[reflect.Type]string
+}
+
+// NewGenerator starts a document.
+func NewGenerator(info Info, servers ...Server) *Generator {
+	return &Generator{
+		doc: &Document{
+			OpenAPI:    Version,
+			Info:       info,
+			Servers:    servers,
+			Paths:      make(map[string]map[string]*PathItem),
+			Components: Components{Schemas: make(map[string]*Schema)},
+		},
+		schemas: make(map[reflect.Type]string),
+	}
+}
+
+// Tag declares a tag, in the order tags should be listed.
+func (g *Generator) Tag(name, description string) {
+	g.doc.Tags = append(g.doc.Tags, Tag{Name: name, Description: description})
+}
+
+// Add documents an operation.
+func (g *Generator) Add(op Operation) {
+	path, params := convertPath(op.Path)
+	item := &PathItem{
+		Summary:     op.Summary,
+		OperationID: operationID(op.Method, op.Path),
+		Parameters:  params,
+		Responses:   map[string]*Response{},
+	}
+	if op.Tag != "" {
+		item.Tags = []string{op.Tag}
+	}
+	for _, name := range op.Query {
+		item.Parameters = append(item.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+	}
+
+	if op.Request != nil {
+		item.RequestBody = g.requestBody(op)
+	}
+
+	response := &Response{Description: "Successful response"}
+	if op.Response != nil {
+		response.Content = map[string]*MediaType{
+			"application/json": {Schema: g.schema(reflect.TypeOf(op.Response), "json")},
+		}
+		if op.Streaming {
+			response.Content["text/event-stream"] = &MediaType{Schema: &Schema{Type: "string"}}
+		}
+	}
+	item.Responses["200"] = response
+	item.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/ErrorResponse"}},
+		},
+	}
+
+	if g.doc.Paths[path] == nil {
+		g.doc.Paths[path] = make(map[string]*PathItem)
+	}
+	g.doc.Paths[path][strings.ToLower(op.Method)] = item
+}
+
+// Schema registers a model as a component schema. Callers must register
+// ErrorResponse, which every operation references for errors.
+func (g *Generator) Schema(model interface{}) {
+	g.schema(reflect.TypeOf(model), "json")
+}
+
+// Document returns the document built so far.
+func (g *Generator) Document() *Document {
+	return g.doc
+}
+
+func (g *Generator) requestBody(op Operation) *RequestBody {
+	t := reflect.TypeOf(op.Request)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !hasFormTags(t) {
+		return &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: g.schema(t, "json")},
+			},
+		}
+	}
+
+	// Multipart forms are documented inline: their models are not JSON
+	form := g.object(t, "form")
+	for _, name := range op.Files {
+		form.Properties[name] = &Schema{Type: "string", Format: "binary"}
+	}
+	return &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{
+			"multipart/form-data": {Schema: form},
+		},
+	}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema returns the schema of t. Named structs become components and are
+// referenced; tag is the struct tag that names fields ("json" or "form").
+func (g *Generator) schema(t reflect.Type, tag string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes byte slices as base64
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem(), tag)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem(), tag)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, tag)
+		}
+		return g.component(t, tag)
+	}
+	return &Schema{}
+}
+
+// component registers a named struct under components/schemas (once) and
+// returns a reference to it.
+func (g *Generator) component(t reflect.Type, tag string) *Schema {
+	name, ok := g.schemas[t]
+	if !ok {
+		name = g.componentName(t)
+		g.schemas[t] = name
+		// Registered before building so recursive types terminate
+		g.doc.Components.Schemas[name] = &Schema{}
+		*g.doc.Components.Schemas[name] = *g.object(t, tag)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName is t's name, capitalized, and prefixed with its package
+// when another package already registered the name.
+func (g *Generator) componentName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, taken := g.doc.Components.Schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+// object builds an object schema from a struct's exported, tagged fields.
+// Fields that are neither pointers nor omitempty are always present, so
+// they are listed as required. An enum tag lists the values a field
+// accepts: `json:"role" enum:"system,user,assistant"`.
+func (g *Generator) object(t reflect.Type, tag string) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			// Embedded structs contribute their fields
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := g.object(embedded, tag)
+				for key, value := range inner.Properties {
+					schema.Properties[key] = value
+				}
+				schema.Required = append(schema.Required, inner.Required...)
+				continue
+			}
+		}
+
+		if name == "" {
+			if tag == "form" {
+				// Untagged form fields (e.g. uploaded files) are read separately
+				continue
+			}
+			name = field.Name
+		}
+
+		property := g.schema(field.Type, tag)
+		if values := field.Tag.Get("enum"); values != "" && property.Type == "string" {
+			property.Enum = strings.Split(values, ",")
+		}
+		schema.Properties[name] = property
+		if field.Type.Kind() != reflect.Pointer && !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func hasFormTags(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("form"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// convertPath turns gin's /threads/:thread_id into /threads/{thread_id}
+// and returns the path parameters.
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a stable ID such as postV1ChatCompletions.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == ':' || r == '*' || r == '_' || r == '-' || r == '.'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
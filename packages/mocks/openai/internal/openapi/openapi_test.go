@@ -0,0 +1,383 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type message struct {
+	Role    string  `json:"role" enum:"system,user,assistant"`
+	Content string  `json:"content"`
+	Name    *string `json:"name"`
+}
+
+type chatRequest struct {
+	Model    string          `json:"model"`
+	Messages []message       `json:"messages"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	Stream   bool            `json:"stream,omitempty"`
+}
+
+type chatResponse struct {
+	ID      string            `json:"id"`
+	Created time.Time         `json:"created"`
+	Usage   map[string]int64  `json:"usage"`
+	Labels  map[string]string `json:"-"`
+	Data    []byte            `json:"data,omitempty"`
+}
+
+type uploadRequest struct {
+	Purpose string `form:"purpose"`
+	Model   string `form:"model,omitempty"`
+	File    []byte
+}
+
+type node struct {
+	Value    int     `json:"value"`
+	Children []*node `json:"children,omitempty"`
+}
+
+// Base is exported: object only follows exported embedded structs.
+type Base struct {
+	ID string `json:"id"`
+}
+
+type embedding struct {
+	Base
+	Index int `json:"index"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func TestConvertPath(t *testing.T) {
+	tests := []struct {
+		path       string
+		want       string
+		wantParams []string
+	}{
+		{path: "/v1/models", want: "/v1/models"},
+		{path: "/v1/models/:model", want: "/v1/models/{model}", wantParams: []string{"model"}},
+		{
+			path:       "/v1/threads/:thread_id/runs/:run_id",
+			want:       "/v1/threads/{thread_id}/runs/{run_id}",
+			wantParams: []string{"thread_id", "run_id"},
+		},
+		{path: "/files/*path", want: "/files/{path}", wantParams: []string{"path"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, params := convertPath(tt.path)
+			if got != tt.want {
+				t.Errorf("convertPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+
+			var names []string
+			for _, param := range params {
+				if param.In != "path" || !param.Required {
+					t.Errorf("parameter %q is %s (required %v), want a required path parameter", param.Name, param.In, param.Required)
+				}
+				names = append(names, param.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantParams) {
+				t.Errorf("convertPath(%q) parameters = %v, want %v", tt.path, names, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: "POST", path: "/v1/chat/completions", want: "postV1ChatCompletions"},
+		{method: "GET", path: "/v1/models/:model", want: "getV1ModelsModel"},
+		{method: "DELETE", path: "/v1/threads/:thread_id", want: "deleteV1ThreadsThreadId"},
+		{method: "GET", path: "/health", want: "getHealth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := operationID(tt.method, tt.path); got != tt.want {
+				t.Errorf("operationID(%q, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		name  string
+		model interface{}
+		want  *Schema
+	}{
+		{name: "bool", model: true, want: &Schema{Type: "boolean"}},
+		{name: "int", model: 0, want: &Schema{Type: "integer"}},
+		{name: "int64", model: int64(0), want: &Schema{Type: "integer", Format: "int64"}},
+		{name: "float", model: 0.5, want: &Schema{Type: "number"}},
+		{name: "string pointer", model: new(string), want: &Schema{Type: "string"}},
+		{name: "time", model: time.Time{}, want: &Schema{Type: "string", Format: "date-time"}},
+		{name: "raw message", model: json.RawMessage{}, want: &Schema{}},
+		{name: "bytes", model: []byte{}, want: &Schema{Type: "string", Format: "byte"}},
+		{name: "slice", model: []string{}, want: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{
+			name:  "map",
+			model: map[string]float64{},
+			want:  &Schema{Type: "object", AdditionalProperties: &Schema{Type: "number"}},
+		},
+		{name: "named struct", model: message{}, want: &Schema{Ref: "#/components/schemas/Message"}},
+		{
+			name:  "anonymous struct",
+			model: struct{ Count int }{},
+			want: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"Count": {Type: "integer"}},
+				Required:   []string{"Count"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Info{Title: "test", Version: "1"})
+			if got := g.schema(reflect.TypeOf(tt.model), "json"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("schema() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObject(t *testing.T) {
+	tests := []struct {
+		name           string
+		model          interface{}
+		tag            string
+		wantProperties []string
+		wantRequired   []string
+	}{
+		{
+			name:           "pointers and omitempty are optional",
+			model:          chatRequest{},
+			tag:            "json",
+			wantProperties: []string{"messages", "metadata", "model", "stream"},
+			wantRequired:   []string{"messages", "model"},
+		},
+		{
+			name:           "skipped fields",
+			model:          chatResponse{},
+			tag:            "json",
+			wantProperties: []string{"created", "data", "id", "usage"},
+			wantRequired:   []string{"created", "id", "usage"},
+		},
+		{
+			name:           "embedded struct",
+			model:          embedding{},
+			tag:            "json",
+			wantProperties: []string{"id", "index"},
+			wantRequired:   []string{"id", "index"},
+		},
+		{
+			name:           "untagged form fields",
+			model:          uploadRequest{},
+			tag:            "form",
+			wantProperties: []string{"model", "purpose"},
+			wantRequired:   []string{"purpose"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Info{Title: "test", Version: "1"})
+			schema := g.object(reflect.TypeOf(tt.model), tt.tag)
+
+			var properties []string
+			for name := range schema.Properties {
+				properties = append(properties, name)
+			}
+			if !sameStrings(properties, tt.wantProperties) {
+				t.Errorf("properties = %v, want %v", properties, tt.wantProperties)
+			}
+			if !reflect.DeepEqual(schema.Required, tt.wantRequired) {
+				t.Errorf("required = %v, want %v", schema.Required, tt.wantRequired)
+			}
+		})
+	}
+}
+
+func TestObjectEnum(t *testing.T) {
+	g := NewGenerator(Info{Title: "test", Version: "1"})
+	schema := g.object(reflect.TypeOf(message{}), "json")
+
+	want := []string{"system", "user", "assistant"}
+	if got := schema.Properties["role"].Enum; !reflect.DeepEqual(got, want) {
+		t.Errorf("role enum = %v, want %v", got, want)
+	}
+	if got := schema.Properties["content"].Enum; got != nil {
+		t.Errorf("content enum = %v, want none", got)
+	}
+}
+
+func TestRecursiveComponent(t *testing.T) {
+	g := NewGenerator(Info{Title: "test", Version: "1"})
+	g.Schema(node{})
+
+	schemas := g.Document().Components.Schemas
+	children := schemas["Node"].Properties["children"]
+	if children == nil || children.Items == nil || children.Items.Ref != "#/components/schemas/Node" {
+		t.Errorf("children = %+v, want an array of Node references", children)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name            string
+		op              Operation
+		path            string
+		method          string
+		wantRequestType string
+		wantResponse    []string
+		wantParameters  []string
+	}{
+		{
+			name: "json with streaming",
+			op: Operation{
+				Method:    "POST",
+				Path:      "/v1/chat/completions",
+				Tag:       "Chat",
+				Request:   chatRequest{},
+				Response:  chatResponse{},
+				Streaming: true,
+			},
+			path:            "/v1/chat/completions",
+			method:          "post",
+			wantRequestType: "application/json",
+			wantResponse:    []string{"application/json", "text/event-stream"},
+		},
+		{
+			name: "multipart form",
+			op: Operation{
+				Method:  "POST",
+				Path:    "/v1/files",
+				Request: &uploadRequest{},
+				Files:   []string{"file"},
+			},
+			path:            "/v1/files",
+			method:          "post",
+			wantRequestType: "multipart/form-data",
+		},
+		{
+			name: "path and query parameters",
+			op: Operation{
+				Method:   "GET",
+				Path:     "/v1/threads/:thread_id/messages",
+				Query:    []string{"limit", "after"},
+				Response: chatResponse{},
+			},
+			path:           "/v1/threads/{thread_id}/messages",
+			method:         "get",
+			wantResponse:   []string{"application/json"},
+			wantParameters: []string{"thread_id", "limit", "after"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(Info{Title: "test", Version: "1"})
+			g.Schema(errorResponse{})
+			g.Add(tt.op)
+
+			item := g.Document().Paths[tt.path][tt.method]
+			if item == nil {
+				t.Fatalf("no %s operation on %s", tt.method, tt.path)
+			}
+
+			if tt.wantRequestType == "" {
+				if item.RequestBody != nil {
+					t.Errorf("request body = %+v, want none", item.RequestBody)
+				}
+			} else if item.RequestBody == nil || item.RequestBody.Content[tt.wantRequestType] == nil {
+				t.Errorf("request body = %+v, want %s", item.RequestBody, tt.wantRequestType)
+			}
+
+			var response []string
+			for contentType := range item.Responses["200"].Content {
+				response = append(response, contentType)
+			}
+			if !sameStrings(response, tt.wantResponse) {
+				t.Errorf("200 response content = %v, want %v", response, tt.wantResponse)
+			}
+			if item.Responses["default"] == nil {
+				t.Error("no default error response")
+			}
+
+			var parameters []string
+			for _, param := range item.Parameters {
+				parameters = append(parameters, param.Name)
+			}
+			if !reflect.DeepEqual(parameters, tt.wantParameters) {
+				t.Errorf("parameters = %v, want %v", parameters, tt.wantParameters)
+			}
+		})
+	}
+}
+
+func TestAddMultipartFiles(t *testing.T) {
+	g := NewGenerator(Info{Title: "test", Version: "1"})
+	g.Add(Operation{Method: "POST", Path: "/v1/files", Request: uploadRequest{}, Files: []string{"file"}})
+
+	form := g.Document().Paths["/v1/files"]["post"].RequestBody.Content["multipart/form-data"].Schema
+	if file := form.Properties["file"]; file == nil || file.Format != "binary" {
+		t.Errorf("file property = %+v, want a binary string", file)
+	}
+	if _, ok := g.Document().Components.Schemas["UploadRequest"]; ok {
+		t.Error("multipart request registered as a component, want it inline")
+	}
+}
+
+func TestDocumentYAML(t *testing.T) {
+	g := NewGenerator(Info{Title: "Mock", Version: "1.0.0"}, Server{URL: "http://localhost:8080"})
+	g.Schema(errorResponse{})
+	g.Add(Operation{Method: "GET", Path: "/v1/models", Response: chatResponse{}})
+
+	out, err := g.Document().YAML()
+	if err != nil {
+		t.Fatalf("YAML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"openapi: " + Version,
+		"  title: Mock",
+		"  - url: http://localhost:8080",
+		"operationId: getV1Models",
+		"$ref: '#/components/schemas/ChatResponse'",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("YAML() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// sameStrings reports whether a and b hold the same strings in any order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		counts[s]--
+		if counts[s] < 0 {
+			return false
+		}
+	}
+	return true
+}
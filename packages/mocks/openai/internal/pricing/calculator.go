@@ -111,6 +111,13 @@ func (c *Calculator) CalculateWithCachedInput(ctx context.Context, modelID strin
 
 // CalculateImageCost calculates cost for image generation.
 func (c *Calculator) CalculateImageCost(ctx context.Context, modelID string, size string, quality string, numImages int) (ImageCost, error) {
+	return c.CalculateImageEditCost(ctx, modelID, size, quality, numImages, 0)
+}
+
+// CalculateImageEditCost calculates cost for image edits and variations:
+// the generated images plus the input image tokens (gpt-image-1 only;
+// dall-e-2 edits cost the same as generations).
+func (c *Calculator) CalculateImageEditCost(ctx context.Context, modelID string, size string, quality string, numImages int, inputImageTokens int) (ImageCost, error) {
 	// Get pricing
 	pricing, err := c.db.GetPricing(modelID)
 	if err != nil {
@@ -123,7 +130,9 @@ func (c *Calculator) CalculateImageCost(ctx context.Context, modelID string, siz
 
 	// Get price per image
 	var pricePerImage float64
-	if quality == "hd" && pricing.ImagePricing.HD != nil {
+	if pricing.ImagePricing.ByQuality != nil {
+		pricePerImage = pricing.ImagePricing.ByQuality[quality][size]
+	} else if quality == "hd" && pricing.ImagePricing.HD != nil {
 		pricePerImage = pricing.ImagePricing.HD[size]
 	} else {
 		pricePerImage = pricing.ImagePricing.Standard[size]
@@ -134,20 +143,50 @@ func (c *Calculator) CalculateImageCost(ctx context.Context, modelID string, siz
 	}
 
 	// Calculate total cost
-	totalCost := pricePerImage * float64(numImages)
+	inputImageCost := float64(inputImageTokens) * pricing.ImagePricing.InputImagePer1M / 1_000_000
+	totalCost := pricePerImage*float64(numImages) + inputImageCost
 
 	// Update statistics
 	c.addToTotal(totalCost)
 	c.totalRequests.Add(1)
 
 	return ImageCost{
-		Model:         modelID,
-		Size:          size,
-		Quality:       quality,
-		NumImages:     numImages,
-		PricePerImage: pricePerImage,
-		TotalCost:     totalCost,
-		Currency:      c.db.GetCurrency(),
+		Model:            modelID,
+		Size:             size,
+		Quality:          quality,
+		NumImages:        numImages,
+		PricePerImage:    pricePerImage,
+		InputImageTokens: inputImageTokens,
+		InputImageCost:   inputImageCost,
+		TotalCost:        totalCost,
+		Currency:         c.db.GetCurrency(),
+	}, nil
+}
+
+// CalculateSpeechCost calculates cost for text-to-speech, billed per input
+// character.
+func (c *Calculator) CalculateSpeechCost(ctx context.Context, modelID string, characters int) (SpeechCost, error) {
+	pricing, err := c.db.GetPricing(modelID)
+	if err != nil {
+		return SpeechCost{}, err
+	}
+
+	if pricing.SpeechPricing == nil {
+		return SpeechCost{}, fmt.Errorf("model %s does not support text-to-speech", modelID)
+	}
+
+	totalCost := float64(characters) * pricing.SpeechPricing.PerMillionCharacters / 1_000_000
+
+	// Update statistics
+	c.addToTotal(totalCost)
+	c.totalRequests.Add(1)
+
+	return SpeechCost{
+		Model:                modelID,
+		Characters:           characters,
+		PerMillionCharacters: pricing.SpeechPricing.PerMillionCharacters,
+		TotalCost:            totalCost,
+		Currency:             c.db.GetCurrency(),
 	}, nil
 }
 
@@ -273,6 +312,10 @@ type ImageCost struct {
 	PricePerImage float64
 	TotalCost     float64
 	Currency      string
+
+	// Input images of edits (optional)
+	InputImageTokens int
+	InputImageCost   float64
 }
 
 // FormatCost formats the image cost as a string.
@@ -292,6 +335,20 @@ func (ic ImageCost) FormatBreakdown() string {
 	)
 }
 
+// SpeechCost represents the cost for text-to-speech.
+type SpeechCost struct {
+	Model                string
+	Characters           int
+	PerMillionCharacters float64
+	TotalCost            float64
+	Currency             string
+}
+
+// FormatCost formats the speech cost as a string.
+func (sc SpeechCost) FormatCost() string {
+	return fmt.Sprintf("$%.6f", sc.TotalCost)
+}
+
 // CalculatorStats contains statistics about cost calculations.
 type CalculatorStats struct {
 	TotalCost         float64
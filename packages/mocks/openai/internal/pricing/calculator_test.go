@@ -0,0 +1,84 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// costEqual compares costs within floating-point error.
+func costEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCalculateImageEditCost(t *testing.T) {
+	tests := []struct {
+		name        string
+		model       string
+		size        string
+		quality     string
+		n           int
+		imageTokens int
+		wantPer     float64
+		wantTotal   float64
+		wantErr     bool
+	}{
+		{name: "dall-e-3 standard", model: "dall-e-3", size: "1024x1024", quality: "standard", n: 1, wantPer: 0.04, wantTotal: 0.04},
+		{name: "dall-e-3 hd", model: "dall-e-3", size: "1792x1024", quality: "hd", n: 1, wantPer: 0.12, wantTotal: 0.12},
+		{name: "dall-e-2 edit ignores input tokens", model: "dall-e-2", size: "1024x1024", quality: "standard", n: 2, imageTokens: 765, wantPer: 0.02, wantTotal: 0.04},
+		{name: "gpt-image-1 by quality", model: "gpt-image-1", size: "1024x1536", quality: "low", n: 2, wantPer: 0.016, wantTotal: 0.032},
+		{name: "gpt-image-1 edit", model: "gpt-image-1", size: "1024x1024", quality: "high", n: 1, imageTokens: 765, wantPer: 0.167, wantTotal: 0.167 + 765*10.0/1_000_000},
+		{name: "gpt-image-1 standard quality", model: "gpt-image-1", size: "1024x1024", quality: "standard", n: 1, wantErr: true},
+		{name: "unknown size", model: "dall-e-3", size: "256x256", quality: "standard", n: 1, wantErr: true},
+		{name: "chat model", model: "gpt-4o", size: "1024x1024", quality: "standard", n: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCalculator(NewPricingDB())
+			cost, err := c.CalculateImageEditCost(context.Background(), tt.model, tt.size, tt.quality, tt.n, tt.imageTokens)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateImageEditCost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if got := c.GetTotalCost(); got != 0 {
+					t.Errorf("GetTotalCost() = %v after an error, want 0", got)
+				}
+				return
+			}
+			if !costEqual(cost.PricePerImage, tt.wantPer) || !costEqual(cost.TotalCost, tt.wantTotal) {
+				t.Errorf("cost = %+v, want %v per image and %v in total", cost, tt.wantPer, tt.wantTotal)
+			}
+			if got := c.GetStats(context.Background()).TotalRequests; got != 1 {
+				t.Errorf("TotalRequests = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestCalculateSpeechCost(t *testing.T) {
+	tests := []struct {
+		model      string
+		characters int
+		want       float64
+		wantErr    bool
+	}{
+		{model: "tts-1", characters: 1000, want: 0.015},
+		{model: "tts-1-hd", characters: 1000, want: 0.03},
+		{model: "gpt-4o-mini-tts", characters: 4096, want: 4096 * 15.0 / 1_000_000},
+		{model: "gpt-4o", characters: 1000, wantErr: true},
+		{model: "no-such-model", characters: 1000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			cost, err := NewCalculator(NewPricingDB()).CalculateSpeechCost(context.Background(), tt.model, tt.characters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateSpeechCost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !costEqual(cost.TotalCost, tt.want) {
+				t.Errorf("TotalCost = %v, want %v", cost.TotalCost, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package pricing provides cost calculation.
+// This file implements shared usage counters for cluster mode.
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// In cluster mode, usage is accumulated in the shared storage backend so
+// that any replica can answer usage queries for any API key:
+//
+//	usage:shared:<scopeKey>:<counter>
+//	usage:shared:<scopeKey>:model:<model>:<counter>
+//	usage:shared:model:<model>:<counter>
+//
+// Counters are updated with atomic increments, so totals are exact. Cost is
+// stored in micro-USD because storage counters are integers; each replica
+// carries the fraction of a micro-USD it could not add into its next
+// increment, so totals stay within a micro-USD per replica of the exact cost.
+// The in-memory aggregations are still maintained and reflect only this
+// replica's traffic.
+const (
+	// sharedUsagePrefix prefixes all shared usage counters
+	sharedUsagePrefix = "usage:shared:"
+
+	// microUSD converts USD to integer micro-USD for storage counters
+	microUSD = 1_000_000
+)
+
+// Shared usage counter names.
+const (
+	counterRequests     = "requests"
+	counterInputTokens  = "input_tokens"
+	counterOutputTokens = "output_tokens"
+	counterCostMicros   = "cost_micros"
+	counterFirstRequest = "first_request"
+	counterLastRequest  = "last_request"
+)
+
+// EnableClusterMode makes usage tracking go through shared storage.
+func (t *Tracker) EnableClusterMode() {
+	t.clusterMode.Store(true)
+}
+
+// DisableClusterMode reverts to in-memory usage tracking.
+func (t *Tracker) DisableClusterMode() {
+	t.clusterMode.Store(false)
+}
+
+// IsClusterMode returns whether usage is tracked in shared storage.
+func (t *Tracker) IsClusterMode() bool {
+	return t.clusterMode.Load()
+}
+
+// trackShared adds a request's usage to the shared counters.
+func (t *Tracker) trackShared(ctx context.Context, scope models.RequestScope, model string, cost Cost, now time.Time) error {
+	scopePrefix := sharedUsagePrefix + scope.Key() + ":"
+
+	prefixes := []string{
+		scopePrefix,
+		scopePrefix + "model:" + model + ":",
+		sharedUsagePrefix + "model:" + model + ":",
+	}
+
+	for _, prefix := range prefixes {
+		if err := t.incrementShared(ctx, prefix, cost); err != nil {
+			return err
+		}
+	}
+
+	// Request timestamps for the scope
+	if _, err := t.storage.SetNX(ctx, scopePrefix+counterFirstRequest, now.Unix(), 0); err != nil {
+		return fmt.Errorf("failed to set %s%s: %w", scopePrefix, counterFirstRequest, err)
+	}
+	if err := t.storage.Set(ctx, scopePrefix+counterLastRequest, now.Unix(), 0); err != nil {
+		return fmt.Errorf("failed to set %s%s: %w", scopePrefix, counterLastRequest, err)
+	}
+
+	return nil
+}
+
+// incrementShared increments the counters under a prefix.
+func (t *Tracker) incrementShared(ctx context.Context, prefix string, cost Cost) error {
+	increments := map[string]int64{
+		counterRequests:     1,
+		counterInputTokens:  int64(cost.InputTokens),
+		counterOutputTokens: int64(cost.OutputTokens),
+		counterCostMicros:   t.costMicros(prefix, cost.TotalCost),
+	}
+
+	for counter, delta := range increments {
+		if _, err := t.storage.Increment(ctx, prefix+counter, delta); err != nil {
+			return fmt.Errorf("failed to increment %s%s: %w", prefix, counter, err)
+		}
+	}
+
+	return nil
+}
+
+// costMicros converts cost to whole micro-USD to add to the cost counter
+// under prefix, carrying the rounding error into the next call for prefix.
+func (t *Tracker) costMicros(prefix string, cost float64) int64 {
+	t.remaindersMu.Lock()
+	defer t.remaindersMu.Unlock()
+
+	micros := cost*microUSD + t.costRemainders[prefix]
+	whole := math.Round(micros)
+	t.costRemainders[prefix] = micros - whole
+	return int64(whole)
+}
+
+// getSharedUserUsage builds a UserUsage for a scope from shared counters.
+func (t *Tracker) getSharedUserUsage(ctx context.Context, scope models.RequestScope) (*UserUsage, error) {
+	scopePrefix := sharedUsagePrefix + scope.Key() + ":"
+
+	totals, err := t.readShared(ctx, scopePrefix)
+	if err != nil {
+		return nil, err
+	}
+	if totals.TotalRequests == 0 {
+		return nil, fmt.Errorf("no usage found for API key: %s", scope.Key())
+	}
+
+	usage := &UserUsage{
+		APIKey:            scope.APIKey,
+		Organization:      scope.Organization,
+		Project:           scope.Project,
+		TotalCost:         totals.TotalCost,
+		TotalRequests:     totals.TotalRequests,
+		TotalInputTokens:  totals.TotalInputTokens,
+		TotalOutputTokens: totals.TotalOutputTokens,
+		ModelBreakdown:    make(map[string]*ModelUsage),
+	}
+
+	timestamps, err := t.storage.GetMulti(ctx, []string{
+		scopePrefix + counterFirstRequest,
+		scopePrefix + counterLastRequest,
+	})
+	if err == nil {
+		usage.FirstRequest = time.Unix(counterValue(timestamps[scopePrefix+counterFirstRequest]), 0)
+		usage.LastRequest = time.Unix(counterValue(timestamps[scopePrefix+counterLastRequest]), 0)
+	}
+
+	// Discover models from the per-model request counters
+	modelPrefix := scopePrefix + "model:"
+	keys, err := t.storage.Keys(ctx, modelPrefix+"*:"+counterRequests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list model usage: %w", err)
+	}
+
+	for _, key := range keys {
+		model := strings.TrimSuffix(strings.TrimPrefix(key, modelPrefix), ":"+counterRequests)
+		modelUsage, err := t.readShared(ctx, modelPrefix+model+":")
+		if err != nil {
+			return nil, err
+		}
+		modelUsage.Model = model
+		usage.ModelBreakdown[model] = modelUsage
+	}
+
+	return usage, nil
+}
+
+// getSharedModelUsage builds a ModelUsage from shared counters.
+func (t *Tracker) getSharedModelUsage(ctx context.Context, model string) (*ModelUsage, error) {
+	usage, err := t.readShared(ctx, sharedUsagePrefix+"model:"+model+":")
+	if err != nil {
+		return nil, err
+	}
+	if usage.TotalRequests == 0 {
+		return nil, fmt.Errorf("no usage found for model: %s", model)
+	}
+
+	usage.Model = model
+	return usage, nil
+}
+
+// readShared reads the counters under a prefix.
+func (t *Tracker) readShared(ctx context.Context, prefix string) (*ModelUsage, error) {
+	keys := []string{
+		prefix + counterRequests,
+		prefix + counterInputTokens,
+		prefix + counterOutputTokens,
+		prefix + counterCostMicros,
+	}
+
+	values, err := t.storage.GetMulti(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shared usage: %w", err)
+	}
+
+	usage := &ModelUsage{
+		TotalRequests:     counterValue(values[keys[0]]),
+		TotalInputTokens:  counterValue(values[keys[1]]),
+		TotalOutputTokens: counterValue(values[keys[2]]),
+		TotalCost:         float64(counterValue(values[keys[3]])) / microUSD,
+	}
+	if usage.TotalRequests > 0 {
+		usage.AverageCost = usage.TotalCost / float64(usage.TotalRequests)
+	}
+
+	return usage, nil
+}
+
+// counterValue converts a stored counter to int64.
+// MemoryStore returns int64, while RedisStore returns JSON-decoded float64.
+func counterValue(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,138 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// newClusterTracker creates a replica's tracker in cluster mode.
+func newClusterTracker(storage store.Storage) *Tracker {
+	tracker := NewTracker(NewCalculator(NewPricingDB()), storage)
+	tracker.EnableClusterMode()
+	return tracker
+}
+
+func TestClusterModeSharesUsage(t *testing.T) {
+	ctx := context.Background()
+	shared := store.NewMemoryStore()
+	replicas := []*Tracker{newClusterTracker(shared), newClusterTracker(shared)}
+
+	scope := models.RequestScope{APIKey: "sk-test", Project: "proj_a"}
+	cost := Cost{InputTokens: 10, OutputTokens: 5, TotalTokens: 15, TotalCost: 0.25}
+	for i := 0; i < 3; i++ {
+		if err := replicas[i%2].TrackScoped(ctx, scope, "gpt-4o", cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, replica := range replicas {
+		usage, err := replica.GetScopedUsage(ctx, scope)
+		if err != nil {
+			t.Fatalf("replica %d: %v", i, err)
+		}
+		if usage.TotalRequests != 3 || usage.TotalInputTokens != 30 || usage.TotalOutputTokens != 15 || usage.TotalCost != 0.75 {
+			t.Errorf("replica %d: usage = %+v, want 3 requests, 30/15 tokens, $0.75", i, usage)
+		}
+		if model := usage.ModelBreakdown["gpt-4o"]; model == nil || model.TotalRequests != 3 {
+			t.Errorf("replica %d: model breakdown = %+v", i, usage.ModelBreakdown)
+		}
+
+		modelUsage, err := replica.GetModelUsage(ctx, "gpt-4o")
+		if err != nil || modelUsage.TotalRequests != 3 {
+			t.Errorf("replica %d: model usage = %+v, %v", i, modelUsage, err)
+		}
+	}
+}
+
+func TestClusterModeCostMatchesLocal(t *testing.T) {
+	ctx := context.Background()
+	shared := store.NewMemoryStore()
+	replicas := []*Tracker{newClusterTracker(shared), newClusterTracker(shared)}
+	local := NewTracker(NewCalculator(NewPricingDB()), store.NewMemoryStore())
+
+	// Requests costing fractions of a micro-USD more than a whole number
+	scope := models.RequestScope{APIKey: "sk-test"}
+	for i := 0; i < 10000; i++ {
+		cost := Cost{InputTokens: 10, OutputTokens: 1, TotalCost: 0.0000013 * float64(1+i%3)}
+		if err := replicas[i%2].TrackScoped(ctx, scope, "gpt-4o-mini", cost); err != nil {
+			t.Fatal(err)
+		}
+		if err := local.TrackScoped(ctx, scope, "gpt-4o-mini", cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := local.GetScopedUsage(ctx, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := replicas[0].GetScopedUsage(ctx, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each replica may hold back up to half a micro-USD
+	if diff := math.Abs(got.TotalCost - want.TotalCost); diff > float64(len(replicas))*0.5/microUSD {
+		t.Errorf("cluster cost = %.6f, local cost = %.6f; want them within a micro-USD", got.TotalCost, want.TotalCost)
+	}
+}
+
+// failingStore fails the operations named in fail.
+type failingStore struct {
+	store.Storage
+	fail string
+}
+
+func (s failingStore) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if s.fail == "Increment" {
+		return 0, errors.New("increment failed")
+	}
+	return s.Storage.Increment(ctx, key, delta)
+}
+
+func (s failingStore) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if s.fail == "SetNX" {
+		return false, errors.New("setnx failed")
+	}
+	return s.Storage.SetNX(ctx, key, value, ttl)
+}
+
+func (s failingStore) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if s.fail == "Set" {
+		return errors.New("set failed")
+	}
+	return s.Storage.Set(ctx, key, value, ttl)
+}
+
+func TestClusterModeStorageErrors(t *testing.T) {
+	tests := []struct {
+		fail    string
+		wantErr string
+	}{
+		{fail: "Increment", wantErr: "increment failed"},
+		{fail: "SetNX", wantErr: "setnx failed"},
+		{fail: "Set", wantErr: "set failed"},
+		{fail: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fail, func(t *testing.T) {
+			tracker := newClusterTracker(failingStore{Storage: store.NewMemoryStore(), fail: tt.fail})
+
+			err := tracker.TrackScoped(context.Background(), models.RequestScope{APIKey: "sk-test"}, "gpt-4o", Cost{TotalCost: 0.1})
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("TrackScoped() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("TrackScoped() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
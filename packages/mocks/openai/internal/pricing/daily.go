@@ -0,0 +1,194 @@
+// Package pricing provides cost calculation.
+// This file implements daily usage buckets for the usage and costs APIs.
+package pricing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// dailyRetention is how long daily buckets are kept in memory.
+const dailyRetention = 90 * 24 * time.Hour
+
+// DailyUsage is usage for one scope, run and model on one UTC day.
+type DailyUsage struct {
+	Day               time.Time
+	APIKey            string
+	Organization      string
+	Project           string
+	RunID             string
+	Model             string
+	Requests          int64
+	InputTokens       int64
+	CachedInputTokens int64
+	OutputTokens      int64
+	InputCost         float64
+	CachedInputCost   float64
+	OutputCost        float64
+	TotalCost         float64
+}
+
+// DailyUsageQuery filters daily usage buckets. Empty filters match everything.
+type DailyUsageQuery struct {
+	// Start is the inclusive start time (truncated to the day)
+	Start time.Time
+
+	// End is the exclusive end time (zero = now)
+	End time.Time
+
+	// Organization restricts results to one organization
+	Organization string
+
+	// Projects restricts results to these projects
+	Projects []string
+
+	// APIKeyIDs restricts results to these API key IDs (see APIKeyID)
+	APIKeyIDs []string
+
+	// Models restricts results to these models
+	Models []string
+
+	// RunIDs restricts results to these simulation runs
+	RunIDs []string
+}
+
+// runIDContextKey carries the simulation run usage is attributed to.
+type runIDContextKey struct{}
+
+// WithRunID returns a copy of ctx attributing usage tracked with it to a
+// simulation run, so costs can be broken down by scenario.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDContextKey{}, runID)
+}
+
+// RunIDFromContext returns the run set with WithRunID ("" if none).
+func RunIDFromContext(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDContextKey{}).(string)
+	return runID
+}
+
+// APIKeyID returns a stable, non-secret identifier for an API key
+// (e.g., "key_3f2a9c..."), used where OpenAI reports api_key_id.
+func APIKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key_" + hex.EncodeToString(sum[:8])
+}
+
+// dayStart returns the start of the UTC day containing t.
+func dayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// trackDaily adds a request's usage to its daily bucket.
+// Must be called with mu held.
+func (t *Tracker) trackDaily(scope models.RequestScope, runID, model string, cost Cost, now time.Time) {
+	day := dayStart(now)
+	key := day.Format("2006-01-02") + "|" + scope.Key() + "|" + runID + "|" + model
+
+	usage, ok := t.dailyUsage[key]
+	if !ok {
+		usage = &DailyUsage{
+			Day:          day,
+			APIKey:       scope.APIKey,
+			Organization: scope.Organization,
+			Project:      scope.Project,
+			RunID:        runID,
+			Model:        model,
+		}
+		t.dailyUsage[key] = usage
+	}
+
+	usage.Requests++
+	usage.InputTokens += int64(cost.InputTokens)
+	usage.CachedInputTokens += int64(cost.CachedInputTokens)
+	usage.OutputTokens += int64(cost.OutputTokens)
+	usage.InputCost += cost.InputCost
+	usage.CachedInputCost += cost.CachedInputCost
+	usage.OutputCost += cost.OutputCost
+	usage.TotalCost += cost.TotalCost
+}
+
+// GetDailyUsage returns daily buckets matching a query, ordered by day,
+// then model. Daily buckets are kept in memory for 90 days; in cluster mode
+// they reflect only this replica's traffic.
+func (t *Tracker) GetDailyUsage(ctx context.Context, query DailyUsageQuery) []*DailyUsage {
+	start := dayStart(query.Start)
+	end := query.End
+	if end.IsZero() {
+		end = clock.Now()
+	}
+
+	projects := toSet(query.Projects)
+	keyIDs := toSet(query.APIKeyIDs)
+	modelSet := toSet(query.Models)
+	runIDs := toSet(query.RunIDs)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var result []*DailyUsage
+	for _, usage := range t.dailyUsage {
+		if usage.Day.Before(start) || !usage.Day.Before(end) {
+			continue
+		}
+		if query.Organization != "" && usage.Organization != query.Organization {
+			continue
+		}
+		if projects != nil && !projects[usage.Project] {
+			continue
+		}
+		if keyIDs != nil && !keyIDs[APIKeyID(usage.APIKey)] {
+			continue
+		}
+		if modelSet != nil && !modelSet[usage.Model] {
+			continue
+		}
+		if runIDs != nil && !runIDs[usage.RunID] {
+			continue
+		}
+
+		usageCopy := *usage
+		result = append(result, &usageCopy)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Day.Equal(result[j].Day) {
+			return result[i].Day.Before(result[j].Day)
+		}
+		return result[i].Model < result[j].Model
+	})
+
+	return result
+}
+
+// cleanupOldDailyUsage removes daily buckets past retention.
+// Must be called with mu held.
+func (t *Tracker) cleanupOldDailyUsage(now time.Time) {
+	cutoff := dayStart(now.Add(-dailyRetention))
+
+	for key, usage := range t.dailyUsage {
+		if usage.Day.Before(cutoff) {
+			delete(t.dailyUsage, key)
+		}
+	}
+}
+
+// toSet converts a slice to a set (nil for an empty slice).
+func toSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,135 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestRunIDContext(t *testing.T) {
+	ctx := context.Background()
+	if got := RunIDFromContext(ctx); got != "" {
+		t.Errorf("RunIDFromContext() = %q, want empty", got)
+	}
+	if got := RunIDFromContext(WithRunID(ctx, "run-1")); got != "run-1" {
+		t.Errorf("RunIDFromContext() = %q, want run-1", got)
+	}
+}
+
+func TestAPIKeyID(t *testing.T) {
+	id := APIKeyID("sk-test")
+	if len(id) != len("key_")+16 || id[:4] != "key_" {
+		t.Errorf("APIKeyID() = %q, want key_ and 16 hex digits", id)
+	}
+	if APIKeyID("sk-test") != id {
+		t.Error("APIKeyID() is not stable")
+	}
+	if APIKeyID("sk-other") == id {
+		t.Error("APIKeyID() is the same for different keys")
+	}
+}
+
+func TestGetDailyUsage(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker(NewCalculator(NewPricingDB()), store.NewMemoryStore())
+
+	cost := Cost{InputTokens: 10, CachedInputTokens: 4, OutputTokens: 5, TotalCost: 0.5}
+	track := func(ctx context.Context, scope models.RequestScope, model string) {
+		t.Helper()
+		if err := tracker.TrackScoped(ctx, scope, model, cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+	acmeA := models.RequestScope{APIKey: "sk-a", Organization: "org-acme", Project: "proj-a"}
+	acmeB := models.RequestScope{APIKey: "sk-b", Organization: "org-acme", Project: "proj-b"}
+	other := models.RequestScope{APIKey: "sk-c", Organization: "org-other"}
+	track(ctx, acmeA, "gpt-4o")
+	track(ctx, acmeA, "gpt-4o")
+	track(ctx, acmeA, "gpt-4o-mini")
+	track(WithRunID(ctx, "run-1"), acmeB, "gpt-4o")
+	track(ctx, other, "gpt-4o")
+
+	today := dayStart(clock.Now())
+	tests := []struct {
+		name         string
+		query        DailyUsageQuery
+		wantBuckets  int
+		wantRequests int64
+	}{
+		{name: "all", query: DailyUsageQuery{Start: today}, wantBuckets: 4, wantRequests: 5},
+		{name: "organization", query: DailyUsageQuery{Start: today, Organization: "org-acme"}, wantBuckets: 3, wantRequests: 4},
+		{name: "project", query: DailyUsageQuery{Start: today, Projects: []string{"proj-a"}}, wantBuckets: 2, wantRequests: 3},
+		{name: "api key", query: DailyUsageQuery{Start: today, APIKeyIDs: []string{APIKeyID("sk-c")}}, wantBuckets: 1, wantRequests: 1},
+		{name: "model", query: DailyUsageQuery{Start: today, Models: []string{"gpt-4o-mini"}}, wantBuckets: 1, wantRequests: 1},
+		{name: "run", query: DailyUsageQuery{Start: today, RunIDs: []string{"run-1"}}, wantBuckets: 1, wantRequests: 1},
+		{name: "unattributed", query: DailyUsageQuery{Start: today, RunIDs: []string{""}}, wantBuckets: 3, wantRequests: 4},
+		{name: "before today", query: DailyUsageQuery{Start: today.AddDate(0, 0, -7), End: today}, wantBuckets: 0},
+		{name: "tomorrow", query: DailyUsageQuery{Start: today.AddDate(0, 0, 1)}, wantBuckets: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := tracker.GetDailyUsage(ctx, tt.query)
+			if len(buckets) != tt.wantBuckets {
+				t.Fatalf("GetDailyUsage() returned %d buckets, want %d", len(buckets), tt.wantBuckets)
+			}
+			var requests int64
+			for i, bucket := range buckets {
+				requests += bucket.Requests
+				if !bucket.Day.Equal(today) {
+					t.Errorf("Day = %v, want %v", bucket.Day, today)
+				}
+				if i > 0 && buckets[i-1].Model > bucket.Model {
+					t.Errorf("buckets not ordered by model: %s before %s", buckets[i-1].Model, bucket.Model)
+				}
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+
+	buckets := tracker.GetDailyUsage(ctx, DailyUsageQuery{Start: today, Projects: []string{"proj-a"}, Models: []string{"gpt-4o"}})
+	if len(buckets) != 1 {
+		t.Fatalf("GetDailyUsage() returned %d buckets, want 1", len(buckets))
+	}
+	if b := buckets[0]; b.InputTokens != 20 || b.CachedInputTokens != 8 || b.OutputTokens != 10 || !costEqual(b.TotalCost, 1.0) {
+		t.Errorf("bucket = %+v, want two requests summed", b)
+	}
+
+	// Returned buckets are copies
+	buckets[0].Requests = 100
+	if again := tracker.GetDailyUsage(ctx, DailyUsageQuery{Start: today, Projects: []string{"proj-a"}, Models: []string{"gpt-4o"}}); again[0].Requests != 2 {
+		t.Errorf("modifying a returned bucket changed the tracker: Requests = %d", again[0].Requests)
+	}
+}
+
+func TestCleanupOldDailyUsage(t *testing.T) {
+	t.Cleanup(clock.Reset)
+
+	ctx := context.Background()
+	tracker := NewTracker(NewCalculator(NewPricingDB()), store.NewMemoryStore())
+	if err := tracker.Track(ctx, "sk-test", "gpt-4o", Cost{TotalCost: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := clock.Advance(30 * 24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	tracker.CleanupOldHourlyUsage(ctx)
+	if got := tracker.GetDailyUsage(ctx, DailyUsageQuery{}); len(got) != 1 {
+		t.Fatalf("after 30 days: %d buckets, want 1", len(got))
+	}
+
+	if err := clock.Advance(dailyRetention); err != nil {
+		t.Fatal(err)
+	}
+	tracker.CleanupOldHourlyUsage(ctx)
+	if got := tracker.GetDailyUsage(ctx, DailyUsageQuery{}); len(got) != 0 {
+		t.Errorf("after retention: %d buckets, want 0", len(got))
+	}
+}
@@ -5,13 +5,25 @@ package pricing
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/sentra-lab/mocks/openai/internal/locale"
 )
 
+// costPrecision is the number of decimals costs are reported with.
+const costPrecision = 6
+
+// localizedCost formats a cost with the simulated locale's separators
+// ("0,001250 USD" in de-DE). The currency stays the one billed in.
+func localizedCost(amount float64, currency string) string {
+	return locale.Current().FormatNumber(amount, costPrecision) + " " + currency
+}
+
 // AddCostHeaders adds cost-related headers to HTTP response.
 // These are custom headers prefixed with X-Sentra- for the mock server.
 func AddCostHeaders(w http.ResponseWriter, cost Cost) {
 	// Total cost
 	w.Header().Set("X-Sentra-Cost-Total", fmt.Sprintf("%.6f", cost.TotalCost))
+	w.Header().Set("X-Sentra-Cost-Total-Localized", localizedCost(cost.TotalCost, cost.Currency))
 	w.Header().Set("X-Sentra-Cost-Currency", cost.Currency)
 
 	// Token breakdown
@@ -37,6 +49,7 @@ func AddCostHeaders(w http.ResponseWriter, cost Cost) {
 // AddImageCostHeaders adds cost headers for image generation.
 func AddImageCostHeaders(w http.ResponseWriter, cost ImageCost) {
 	w.Header().Set("X-Sentra-Cost-Total", fmt.Sprintf("%.6f", cost.TotalCost))
+	w.Header().Set("X-Sentra-Cost-Total-Localized", localizedCost(cost.TotalCost, cost.Currency))
 	w.Header().Set("X-Sentra-Cost-Currency", cost.Currency)
 	w.Header().Set("X-Sentra-Cost-Per-Image", fmt.Sprintf("%.4f", cost.PricePerImage))
 	w.Header().Set("X-Sentra-Images-Count", fmt.Sprintf("%d", cost.NumImages))
@@ -45,6 +58,15 @@ func AddImageCostHeaders(w http.ResponseWriter, cost ImageCost) {
 	w.Header().Set("X-Sentra-Model", cost.Model)
 }
 
+// AddSpeechCostHeaders adds cost headers for text-to-speech.
+func AddSpeechCostHeaders(w http.ResponseWriter, cost SpeechCost) {
+	w.Header().Set("X-Sentra-Cost-Total", fmt.Sprintf("%.6f", cost.TotalCost))
+	w.Header().Set("X-Sentra-Cost-Total-Localized", localizedCost(cost.TotalCost, cost.Currency))
+	w.Header().Set("X-Sentra-Cost-Currency", cost.Currency)
+	w.Header().Set("X-Sentra-Speech-Characters", fmt.Sprintf("%d", cost.Characters))
+	w.Header().Set("X-Sentra-Model", cost.Model)
+}
+
 // AddEstimatedCostHeaders adds estimated cost headers (for rate limiting).
 func AddEstimatedCostHeaders(w http.ResponseWriter, estimatedCost float64, model string) {
 	w.Header().Set("X-Sentra-Cost-Estimated", fmt.Sprintf("%.6f", estimatedCost))
@@ -0,0 +1,39 @@
+package pricing
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/locale"
+)
+
+func TestCostHeadersLocalized(t *testing.T) {
+	t.Cleanup(func() { locale.Reset() })
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"en-US", "1,234.001250 USD"},
+		{"de-DE", "1.234,001250 USD"},
+		{"fr-FR", "1\u202f234,001250 USD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if _, err := locale.Set(locale.Settings{Locale: tt.locale}); err != nil {
+				t.Fatal(err)
+			}
+
+			rec := httptest.NewRecorder()
+			AddCostHeaders(rec, Cost{TotalCost: 1234.00125, Currency: "USD"})
+
+			if got := rec.Header().Get("X-Sentra-Cost-Total"); got != "1234.001250" {
+				t.Errorf("X-Sentra-Cost-Total = %q, want 1234.001250", got)
+			}
+			if got := rec.Header().Get("X-Sentra-Cost-Total-Localized"); got != tt.want {
+				t.Errorf("X-Sentra-Cost-Total-Localized = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
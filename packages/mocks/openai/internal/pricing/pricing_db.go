@@ -42,6 +42,9 @@ type ModelPricing struct {
 	// ImagePricing contains pricing for image generation (if applicable)
 	ImagePricing *ImagePricing
 
+	// SpeechPricing contains pricing for text-to-speech (if applicable)
+	SpeechPricing *SpeechPricing
+
 	// SupportsCachedInput indicates if the model supports cached input pricing
 	SupportsCachedInput bool
 }
@@ -53,6 +56,20 @@ type ImagePricing struct {
 
 	// HD is the price per image for HD quality
 	HD map[string]float64 // size -> price
+
+	// ByQuality prices models with low/medium/high quality (gpt-image-1).
+	// When set, it is used instead of Standard and HD.
+	ByQuality map[string]map[string]float64 // quality -> size -> price
+
+	// InputImagePer1M is the cost per 1M input image tokens (edits)
+	InputImagePer1M float64
+}
+
+// SpeechPricing contains pricing for text-to-speech, billed per input
+// character.
+type SpeechPricing struct {
+	// PerMillionCharacters is the cost per 1M input characters in USD
+	PerMillionCharacters float64
 }
 
 // NewPricingDB creates a new pricing database with default pricing.
@@ -154,6 +171,34 @@ func (db *PricingDB) loadDefaultPricing() {
 		},
 	}
 
+	db.prices["gpt-image-1"] = ModelPricing{
+		ModelID:             "gpt-image-1",
+		InputPer1M:          5.00,  // Text prompt tokens
+		OutputPer1M:         40.00, // Image tokens; the per-image prices below include them
+		CachedInputPer1M:    1.25,
+		SupportsCachedInput: true,
+		ImagePricing: &ImagePricing{
+			ByQuality: map[string]map[string]float64{
+				"low": {
+					"1024x1024": 0.011,
+					"1024x1536": 0.016,
+					"1536x1024": 0.016,
+				},
+				"medium": {
+					"1024x1024": 0.042,
+					"1024x1536": 0.063,
+					"1536x1024": 0.063,
+				},
+				"high": {
+					"1024x1024": 0.167,
+					"1024x1536": 0.25,
+					"1536x1024": 0.25,
+				},
+			},
+			InputImagePer1M: 10.00,
+		},
+	}
+
 	db.prices["dall-e-2"] = ModelPricing{
 		ModelID:     "dall-e-2",
 		InputPer1M:  0,
@@ -166,19 +211,56 @@ func (db *PricingDB) loadDefaultPricing() {
 			},
 		},
 	}
+
+	// Text-to-speech models, priced per input character. gpt-4o-mini-tts
+	// is billed in tokens by OpenAI; $15/1M characters approximates it.
+	db.prices["tts-1"] = ModelPricing{
+		ModelID:       "tts-1",
+		SpeechPricing: &SpeechPricing{PerMillionCharacters: 15.00},
+	}
+
+	db.prices["tts-1-hd"] = ModelPricing{
+		ModelID:       "tts-1-hd",
+		SpeechPricing: &SpeechPricing{PerMillionCharacters: 30.00},
+	}
+
+	db.prices["gpt-4o-mini-tts"] = ModelPricing{
+		ModelID:       "gpt-4o-mini-tts",
+		SpeechPricing: &SpeechPricing{PerMillionCharacters: 15.00},
+	}
 }
 
-// GetPricing retrieves pricing for a model.
+// GetPricing retrieves pricing for a model. Models without their own entry
+// use the pricing of the model they resolve to (dated snapshots, fine-tunes
+// without registered pricing), then the prices of a registered custom model.
 func (db *PricingDB) GetPricing(modelID string) (ModelPricing, error) {
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 
 	pricing, ok := db.prices[modelID]
-	if !ok {
+	if ok {
+		return pricing, nil
+	}
+
+	if configID, _ := models.ResolveModel(modelID); configID != modelID {
+		if pricing, ok := db.prices[configID]; ok {
+			pricing.ModelID = modelID
+			return pricing, nil
+		}
+	}
+
+	config, err := models.GetModelConfig(modelID)
+	if err != nil || (config.InputPer1M == 0 && config.OutputPer1M == 0) {
 		return ModelPricing{}, fmt.Errorf("pricing not found for model: %s", modelID)
 	}
 
-	return pricing, nil
+	return ModelPricing{
+		ModelID:             modelID,
+		InputPer1M:          config.InputPer1M,
+		OutputPer1M:         config.OutputPer1M,
+		CachedInputPer1M:    config.CachedInputPer1M,
+		SupportsCachedInput: config.CachedInputPer1M > 0,
+	}, nil
 }
 
 // SetPricing updates pricing for a model.
@@ -259,7 +341,8 @@ func (db *PricingDB) ValidateModel(modelID string) error {
 	return nil
 }
 
-// GetModelsByType returns models of a specific type (chat, embedding, image).
+// GetModelsByType returns models of a specific type (chat, embedding, image,
+// speech).
 func (db *PricingDB) GetModelsByType(modelType string) []string {
 	db.mu.RLock()
 	defer db.mu.RUnlock()
@@ -273,13 +356,17 @@ func (db *PricingDB) GetModelsByType(modelType string) []string {
 				modelIDs = append(modelIDs, modelID)
 			}
 		case "embedding":
-			if pricing.OutputPer1M == 0 && pricing.ImagePricing == nil {
+			if pricing.OutputPer1M == 0 && pricing.ImagePricing == nil && pricing.SpeechPricing == nil {
 				modelIDs = append(modelIDs, modelID)
 			}
 		case "image":
 			if pricing.ImagePricing != nil {
 				modelIDs = append(modelIDs, modelID)
 			}
+		case "speech":
+			if pricing.SpeechPricing != nil {
+				modelIDs = append(modelIDs, modelID)
+			}
 		}
 	}
 
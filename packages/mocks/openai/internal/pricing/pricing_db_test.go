@@ -0,0 +1,54 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestPricingDBGetPricing(t *testing.T) {
+	custom := models.ModelConfig{
+		ID: "acme-llm", Type: models.ModelTypeChat, ContextWindow: 8192, MaxOutputTokens: 1024, Encoding: "cl100k_base",
+		InputPer1M: 1.00, OutputPer1M: 2.00, CachedInputPer1M: 0.50,
+	}
+	unpriced := models.ModelConfig{ID: "acme-free", Type: models.ModelTypeChat, ContextWindow: 8192, MaxOutputTokens: 1024, Encoding: "cl100k_base"}
+	for _, config := range []models.ModelConfig{custom, unpriced} {
+		if err := models.RegisterModel(config); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { models.DeregisterModel(config.ID) })
+	}
+
+	tests := []struct {
+		model      string
+		wantInput  float64
+		wantCached bool
+		wantErr    bool
+	}{
+		{model: "gpt-4o", wantInput: 2.50, wantCached: true},
+		{model: "gpt-4o-2024-05-13", wantInput: 2.50, wantCached: true},
+		{model: "ft:gpt-4o-mini:acme::abc123", wantInput: 0.15, wantCached: true},
+		{model: "acme-llm", wantInput: 1.00, wantCached: true},
+		{model: "acme-free", wantErr: true},
+		{model: "no-such-model", wantErr: true},
+	}
+
+	db := NewPricingDB()
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			pricing, err := db.GetPricing(tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetPricing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if pricing.ModelID != tt.model {
+				t.Errorf("ModelID = %q, want %q", pricing.ModelID, tt.model)
+			}
+			if pricing.InputPer1M != tt.wantInput || pricing.SupportsCachedInput != tt.wantCached {
+				t.Errorf("pricing = %+v, want input %v and cached %v", pricing, tt.wantInput, tt.wantCached)
+			}
+		})
+	}
+}
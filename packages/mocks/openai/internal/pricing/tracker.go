@@ -6,8 +6,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
 	"github.com/sentra-lab/mocks/openai/internal/store"
 )
 
@@ -30,11 +33,31 @@ type Tracker struct {
 
 	// hourlyUsage tracks usage per hour for rate limiting
 	hourlyUsage map[string]*HourlyUsage
+
+	// dailyUsage tracks usage per day, scope and model (see daily.go)
+	dailyUsage map[string]*DailyUsage
+
+	// clusterMode accumulates usage in shared storage (see cluster.go)
+	clusterMode atomic.Bool
+
+	// costRemainders carries the fraction of a micro-USD each shared cost
+	// counter has not been incremented by yet, per counter prefix
+	costRemainders map[string]float64
+
+	// remaindersMu protects costRemainders
+	remaindersMu sync.Mutex
+
+	// pending tracks in-flight persistUsage goroutines (see Flush)
+	pending sync.WaitGroup
 }
 
 // UserUsage tracks usage for a single user/API key.
+// When requests carry organization/project headers, usage is tracked per
+// API key + organization + project scope.
 type UserUsage struct {
 	APIKey           string
+	Organization     string
+	Project          string
 	TotalCost        float64
 	TotalRequests    int64
 	TotalInputTokens int64
@@ -70,18 +93,32 @@ func NewTracker(calculator *Calculator, storage store.Storage) *Tracker {
 		userCosts:   make(map[string]*UserUsage),
 		modelCosts:  make(map[string]*ModelUsage),
 		hourlyUsage: make(map[string]*HourlyUsage),
+		dailyUsage:  make(map[string]*DailyUsage),
+
+		costRemainders: make(map[string]float64),
 	}
 }
 
+// Calculator returns the calculator costs are computed with.
+func (t *Tracker) Calculator() *Calculator {
+	return t.calculator
+}
+
 // Track records usage for a request.
 func (t *Tracker) Track(ctx context.Context, apiKey string, model string, cost Cost) error {
+	return t.TrackScoped(ctx, models.RequestScope{APIKey: apiKey}, model, cost)
+}
+
+// TrackScoped records usage for a request attributed to an organization/project scope.
+func (t *Tracker) TrackScoped(ctx context.Context, scope models.RequestScope, model string, cost Cost) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	now := time.Now()
+	now := clock.Now()
+	apiKey := scope.Key()
 
 	// Track user usage
-	if err := t.trackUser(apiKey, model, cost, now); err != nil {
+	if err := t.trackUser(scope, model, cost, now); err != nil {
 		return fmt.Errorf("failed to track user usage: %w", err)
 	}
 
@@ -95,22 +132,40 @@ func (t *Tracker) Track(ctx context.Context, apiKey string, model string, cost C
 		return fmt.Errorf("failed to track hourly usage: %w", err)
 	}
 
+	// Track daily usage
+	t.trackDaily(scope, RunIDFromContext(ctx), model, cost, now)
+
+	// In cluster mode, shared counters are the source of truth
+	if t.clusterMode.Load() {
+		if err := t.trackShared(ctx, scope, model, cost, now); err != nil {
+			return fmt.Errorf("failed to track shared usage: %w", err)
+		}
+		return nil
+	}
+
 	// Persist to storage (async, best-effort)
-	go t.persistUsage(ctx, apiKey, model, cost)
+	t.pending.Add(1)
+	go func() {
+		defer t.pending.Done()
+		t.persistUsage(context.WithoutCancel(ctx), apiKey, model, cost)
+	}()
 
 	return nil
 }
 
 // trackUser tracks usage for a user.
-func (t *Tracker) trackUser(apiKey string, model string, cost Cost, now time.Time) error {
-	usage, ok := t.userCosts[apiKey]
+func (t *Tracker) trackUser(scope models.RequestScope, model string, cost Cost, now time.Time) error {
+	key := scope.Key()
+	usage, ok := t.userCosts[key]
 	if !ok {
 		usage = &UserUsage{
-			APIKey:         apiKey,
+			APIKey:         scope.APIKey,
+			Organization:   scope.Organization,
+			Project:        scope.Project,
 			FirstRequest:   now,
 			ModelBreakdown: make(map[string]*ModelUsage),
 		}
-		t.userCosts[apiKey] = usage
+		t.userCosts[key] = usage
 	}
 
 	// Update totals
@@ -174,12 +229,47 @@ func (t *Tracker) trackHourly(apiKey string, cost Cost, now time.Time) error {
 // persistUsage persists usage to storage.
 func (t *Tracker) persistUsage(ctx context.Context, apiKey string, model string, cost Cost) {
 	// Store in storage with TTL (7 days)
-	key := fmt.Sprintf("usage:%s:%s:%d", apiKey, model, time.Now().Unix())
+	key := fmt.Sprintf("usage:%s:%s:%d", apiKey, model, clock.Now().Unix())
 	t.storage.Set(ctx, key, cost, 7*24*time.Hour)
 }
 
+// Flush waits for pending usage writes and persists a snapshot of all
+// per-user usage to storage. It is called on shutdown so that usage from
+// the last requests isn't lost. Returns ctx.Err() if ctx expires first.
+func (t *Tracker) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for pending usage writes: %w", ctx.Err())
+	}
+
+	// Shared counters are written synchronously in cluster mode
+	if t.clusterMode.Load() {
+		return nil
+	}
+
+	snapshot := t.GetAllUserUsage(ctx)
+	for key, usage := range snapshot {
+		if err := t.storage.Set(ctx, "usage:summary:"+key, usage, 7*24*time.Hour); err != nil {
+			return fmt.Errorf("failed to flush usage for %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
 // GetUserUsage retrieves usage for a user.
 func (t *Tracker) GetUserUsage(ctx context.Context, apiKey string) (*UserUsage, error) {
+	if t.clusterMode.Load() {
+		return t.getSharedUserUsage(ctx, models.RequestScope{APIKey: apiKey})
+	}
+
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
@@ -199,8 +289,82 @@ func (t *Tracker) GetUserUsage(ctx context.Context, apiKey string) (*UserUsage,
 	return &usageCopy, nil
 }
 
+// GetScopedUsage retrieves usage for an organization/project scope.
+func (t *Tracker) GetScopedUsage(ctx context.Context, scope models.RequestScope) (*UserUsage, error) {
+	if t.clusterMode.Load() {
+		return t.getSharedUserUsage(ctx, scope)
+	}
+
+	return t.GetUserUsage(ctx, scope.Key())
+}
+
+// GetOrganizationUsage aggregates usage across all API keys and projects of an organization.
+func (t *Tracker) GetOrganizationUsage(ctx context.Context, organization string) (*UserUsage, error) {
+	return t.aggregateUsage(func(u *UserUsage) bool {
+		return u.Organization == organization
+	}, UserUsage{Organization: organization}, "organization: "+organization)
+}
+
+// GetProjectUsage aggregates usage across all API keys of a project.
+func (t *Tracker) GetProjectUsage(ctx context.Context, project string) (*UserUsage, error) {
+	return t.aggregateUsage(func(u *UserUsage) bool {
+		return u.Project == project
+	}, UserUsage{Project: project}, "project: "+project)
+}
+
+// aggregateUsage sums usage entries matching a filter into a single UserUsage.
+func (t *Tracker) aggregateUsage(match func(*UserUsage) bool, result UserUsage, scope string) (*UserUsage, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result.ModelBreakdown = make(map[string]*ModelUsage)
+	found := false
+
+	for _, usage := range t.userCosts {
+		if !match(usage) {
+			continue
+		}
+
+		if !found || usage.FirstRequest.Before(result.FirstRequest) {
+			result.FirstRequest = usage.FirstRequest
+		}
+		if usage.LastRequest.After(result.LastRequest) {
+			result.LastRequest = usage.LastRequest
+		}
+		found = true
+
+		result.TotalCost += usage.TotalCost
+		result.TotalRequests += usage.TotalRequests
+		result.TotalInputTokens += usage.TotalInputTokens
+		result.TotalOutputTokens += usage.TotalOutputTokens
+
+		for model, mu := range usage.ModelBreakdown {
+			agg, ok := result.ModelBreakdown[model]
+			if !ok {
+				agg = &ModelUsage{Model: model}
+				result.ModelBreakdown[model] = agg
+			}
+			agg.TotalCost += mu.TotalCost
+			agg.TotalRequests += mu.TotalRequests
+			agg.TotalInputTokens += mu.TotalInputTokens
+			agg.TotalOutputTokens += mu.TotalOutputTokens
+			agg.AverageCost = agg.TotalCost / float64(agg.TotalRequests)
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no usage found for %s", scope)
+	}
+
+	return &result, nil
+}
+
 // GetModelUsage retrieves aggregate usage for a model.
 func (t *Tracker) GetModelUsage(ctx context.Context, model string) (*ModelUsage, error) {
+	if t.clusterMode.Load() {
+		return t.getSharedModelUsage(ctx, model)
+	}
+
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
@@ -340,22 +504,46 @@ func (t *Tracker) ResetAllUsage(ctx context.Context) error {
 	t.userCosts = make(map[string]*UserUsage)
 	t.modelCosts = make(map[string]*ModelUsage)
 	t.hourlyUsage = make(map[string]*HourlyUsage)
+	t.dailyUsage = make(map[string]*DailyUsage)
+
+	return nil
+}
+
+// ResetUnattributedUsage resets usage tracking except the daily usage
+// attributed to a run, which is already isolated by run and is exported
+// after a test run finishes.
+func (t *Tracker) ResetUnattributedUsage(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.userCosts = make(map[string]*UserUsage)
+	t.modelCosts = make(map[string]*ModelUsage)
+	t.hourlyUsage = make(map[string]*HourlyUsage)
+	for key, usage := range t.dailyUsage {
+		if usage.RunID == "" {
+			delete(t.dailyUsage, key)
+		}
+	}
 
 	return nil
 }
 
-// CleanupOldHourlyUsage removes hourly usage older than 24 hours.
+// CleanupOldHourlyUsage removes hourly usage older than 24 hours
+// (and daily usage past its retention).
 func (t *Tracker) CleanupOldHourlyUsage(ctx context.Context) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	cutoff := time.Now().Add(-24 * time.Hour)
+	now := clock.Now()
+	cutoff := now.Add(-24 * time.Hour)
 
 	for key, usage := range t.hourlyUsage {
 		if usage.Hour.Before(cutoff) {
 			delete(t.hourlyUsage, key)
 		}
 	}
+
+	t.cleanupOldDailyUsage(now)
 }
 
 // StartCleanupJob starts a background job to clean up old hourly usage.
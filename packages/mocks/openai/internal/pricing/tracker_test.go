@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestTrackerScopedUsage(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker(NewCalculator(NewPricingDB()), store.NewMemoryStore())
+
+	scopes := []models.RequestScope{
+		{APIKey: "sk-a", Organization: "org-acme", Project: "proj-a"},
+		{APIKey: "sk-a", Organization: "org-acme", Project: "proj-b"},
+		{APIKey: "sk-b", Organization: "org-acme", Project: "proj-a"},
+		{APIKey: "sk-c", Organization: "org-other", Project: "proj-a"},
+	}
+	for i, scope := range scopes {
+		cost := Cost{InputTokens: 10, OutputTokens: 5, TotalCost: float64(i + 1)}
+		if err := tracker.TrackScoped(ctx, scope, "gpt-4o", cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		get          func() (*UserUsage, error)
+		wantCost     float64
+		wantRequests int64
+		wantErr      bool
+	}{
+		{name: "scope", get: func() (*UserUsage, error) { return tracker.GetScopedUsage(ctx, scopes[1]) }, wantCost: 2, wantRequests: 1},
+		{name: "unscoped key", get: func() (*UserUsage, error) { return tracker.GetScopedUsage(ctx, models.RequestScope{APIKey: "sk-a"}) }, wantErr: true},
+		{name: "organization", get: func() (*UserUsage, error) { return tracker.GetOrganizationUsage(ctx, "org-acme") }, wantCost: 6, wantRequests: 3},
+		{name: "project", get: func() (*UserUsage, error) { return tracker.GetProjectUsage(ctx, "proj-a") }, wantCost: 8, wantRequests: 3},
+		{name: "unknown organization", get: func() (*UserUsage, error) { return tracker.GetOrganizationUsage(ctx, "org-none") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := tt.get()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !costEqual(usage.TotalCost, tt.wantCost) || usage.TotalRequests != tt.wantRequests {
+				t.Errorf("usage = $%v over %d requests, want $%v over %d", usage.TotalCost, usage.TotalRequests, tt.wantCost, tt.wantRequests)
+			}
+			model := usage.ModelBreakdown["gpt-4o"]
+			if model == nil || model.TotalRequests != tt.wantRequests || !costEqual(model.AverageCost, tt.wantCost/float64(tt.wantRequests)) {
+				t.Errorf("ModelBreakdown[gpt-4o] = %+v", model)
+			}
+		})
+	}
+}
+
+func TestTrackerFlush(t *testing.T) {
+	ctx := context.Background()
+	storage := store.NewMemoryStore()
+	tracker := NewTracker(NewCalculator(NewPricingDB()), storage)
+
+	scope := models.RequestScope{APIKey: "sk-test", Project: "proj-a"}
+	if err := tracker.TrackScoped(ctx, scope, "gpt-4o", Cost{TotalCost: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if ok, err := storage.Exists(ctx, "usage:summary:"+scope.Key()); err != nil || !ok {
+		t.Errorf("usage summary not flushed: exists = %v, err = %v", ok, err)
+	}
+	if keys, err := storage.Keys(ctx, "usage:sk-test|proj:proj-a:gpt-4o:*"); err != nil || len(keys) != 1 {
+		t.Errorf("per-request usage keys = %v, %v, want the pending write to have finished", keys, err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	tracker.pending.Add(1)
+	defer tracker.pending.Done()
+	if err := tracker.Flush(canceled); err == nil {
+		t.Error("Flush() with a pending write and a canceled context = nil, want an error")
+	}
+}
+
+func TestTrackerResetUnattributedUsage(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker(NewCalculator(NewPricingDB()), store.NewMemoryStore())
+
+	if err := tracker.Track(ctx, "sk-test", "gpt-4o", Cost{TotalCost: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Track(WithRunID(ctx, "run-1"), "sk-test", "gpt-4o", Cost{TotalCost: 0.2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tracker.ResetUnattributedUsage(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tracker.GetUserUsage(ctx, "sk-test"); err == nil {
+		t.Error("GetUserUsage() after reset = nil error, want no usage")
+	}
+	buckets := tracker.GetDailyUsage(ctx, DailyUsageQuery{})
+	if len(buckets) != 1 || buckets[0].RunID != "run-1" {
+		t.Errorf("daily usage after reset = %+v, want only run-1's bucket", buckets)
+	}
+}
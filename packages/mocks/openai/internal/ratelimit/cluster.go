@@ -0,0 +1,216 @@
+// Package ratelimit provides rate limiting.
+// This file implements shared rate limit state for cluster mode.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+)
+
+// In cluster mode, N mock replicas sit behind a load balancer, so in-memory
+// token buckets would each allow the full limit. Instead, every replica
+// increments the same fixed-window counters in the shared storage backend:
+//
+//	ratelimit:<apiKey>:<model>:<window-unix>:requests
+//	ratelimit:<apiKey>:<model>:<window-unix>:tokens
+//
+// Consistency trade-offs (documented in ARCHITECTURE.md):
+//   - Fixed one-minute windows instead of token buckets: a client can burst
+//     up to 2x the limit across a window boundary.
+//   - Request and token counters are incremented separately (not in one
+//     transaction), so concurrent requests near the limit may briefly
+//     over-admit by the number of in-flight replicas.
+//   - Each check costs 2-4 storage round trips.
+const (
+	// sharedKeyPrefix prefixes all shared rate limit counters
+	sharedKeyPrefix = "ratelimit:"
+
+	// sharedTierPrefix prefixes per-key tier overrides
+	sharedTierPrefix = "ratelimit-tier:"
+
+	// sharedWindow is the length of a rate limit window
+	sharedWindow = time.Minute
+)
+
+// allowShared checks rate limits against counters in shared storage.
+func (l *Limiter) allowShared(ctx context.Context, apiKey string, modelID string, estimatedTokens int) (*LimitCheckResult, error) {
+	if l.storage == nil {
+		return nil, fmt.Errorf("cluster mode requires a storage backend")
+	}
+
+	tier := l.sharedTier(ctx, apiKey)
+	limits := l.tierRegistry.GetModelLimitOrDefault(tier, modelID)
+
+	now := clock.Now()
+	window := now.Truncate(sharedWindow)
+	resetIn := window.Add(sharedWindow).Sub(now)
+	requestsKey, tokensKey := sharedCounterKeys(apiKey, modelID, window)
+
+	result := &LimitCheckResult{
+		Allowed:         true,
+		RequestsResetIn: resetIn,
+		TokensResetIn:   resetIn,
+		LimitingFactor:  NoLimit,
+		APIKey:          apiKey,
+		ModelID:         modelID,
+		Tier:            tier,
+	}
+
+	// Count the request
+	requests, err := l.incrementShared(ctx, requestsKey, 1)
+	if err != nil {
+		return nil, err
+	}
+	if int(requests) > limits.RPM {
+		// Don't count rejected requests against the window
+		l.storage.Decrement(ctx, requestsKey, 1)
+
+		tokens, _ := l.storage.Get(ctx, tokensKey)
+		used := counterValue(tokens)
+
+		result.Allowed = false
+		result.LimitingFactor = RequestLimit
+		result.RequestsRemaining = 0
+		result.TokensRemaining = remaining(limits.TPM, used)
+		return result, nil
+	}
+
+	// Count the tokens
+	tokens, err := l.incrementShared(ctx, tokensKey, int64(estimatedTokens))
+	if err != nil {
+		return nil, err
+	}
+	if int(tokens) > limits.TPM {
+		l.storage.Decrement(ctx, requestsKey, 1)
+		l.storage.Decrement(ctx, tokensKey, int64(estimatedTokens))
+
+		result.Allowed = false
+		result.LimitingFactor = TokenLimit
+		result.RequestsRemaining = remaining(limits.RPM, requests-1)
+		result.TokensRemaining = remaining(limits.TPM, tokens-int64(estimatedTokens))
+		return result, nil
+	}
+
+	result.RequestsRemaining = remaining(limits.RPM, requests)
+	result.TokensRemaining = remaining(limits.TPM, tokens)
+	return result, nil
+}
+
+// incrementShared increments a window counter, setting its TTL on creation.
+func (l *Limiter) incrementShared(ctx context.Context, key string, delta int64) (int64, error) {
+	value, err := l.storage.Increment(ctx, key, delta)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment %s: %w", key, err)
+	}
+
+	// First writer in this window sets the expiry (2 windows, so late
+	// readers of the previous window still see it)
+	if value == delta {
+		l.storage.Expire(ctx, key, 2*sharedWindow)
+	}
+
+	return value, nil
+}
+
+// getSharedLimitInfo returns rate limit information from shared storage.
+func (l *Limiter) getSharedLimitInfo(ctx context.Context, apiKey string, modelID string) (*LimitInfo, error) {
+	if l.storage == nil {
+		return nil, fmt.Errorf("cluster mode requires a storage backend")
+	}
+
+	tier := l.sharedTier(ctx, apiKey)
+	limits := l.tierRegistry.GetModelLimitOrDefault(tier, modelID)
+	requestsKey, tokensKey := sharedCounterKeys(apiKey, modelID, clock.Now().Truncate(sharedWindow))
+
+	values, err := l.storage.GetMulti(ctx, []string{requestsKey, tokensKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+	requests := counterValue(values[requestsKey])
+	tokens := counterValue(values[tokensKey])
+
+	return &LimitInfo{
+		APIKey:            apiKey,
+		ModelID:           modelID,
+		Tier:              tier,
+		RequestLimit:      limits.RPM,
+		TokenLimit:        limits.TPM,
+		RequestsRemaining: remaining(limits.RPM, requests),
+		TokensRemaining:   remaining(limits.TPM, tokens),
+		RequestsFillRate:  limits.RPM,
+		TokensFillRate:    limits.TPM,
+	}, nil
+}
+
+// sharedTier returns the tier for an API key from shared storage.
+func (l *Limiter) sharedTier(ctx context.Context, apiKey string) string {
+	if value, err := l.storage.Get(ctx, sharedTierPrefix+apiKey); err == nil {
+		if tier, ok := value.(string); ok && tier != "" {
+			return tier
+		}
+	}
+	return l.determineTier(apiKey)
+}
+
+// setSharedTier stores a tier override for an API key in shared storage.
+func (l *Limiter) setSharedTier(ctx context.Context, apiKey string, tier string) error {
+	if l.storage == nil {
+		return fmt.Errorf("cluster mode requires a storage backend")
+	}
+
+	if err := l.storage.Set(ctx, sharedTierPrefix+apiKey, tier, 0); err != nil {
+		return fmt.Errorf("failed to set tier: %w", err)
+	}
+
+	return nil
+}
+
+// resetShared deletes shared rate limit counters matching a pattern.
+func (l *Limiter) resetShared(ctx context.Context, pattern string) error {
+	if l.storage == nil {
+		return nil
+	}
+
+	keys, err := l.storage.Keys(ctx, pattern)
+	if err != nil {
+		return fmt.Errorf("failed to list rate limit keys: %w", err)
+	}
+
+	if len(keys) > 0 {
+		return l.storage.DeleteMulti(ctx, keys)
+	}
+
+	return nil
+}
+
+// sharedCounterKeys returns the request and token counter keys for a window.
+func sharedCounterKeys(apiKey string, modelID string, window time.Time) (string, string) {
+	base := fmt.Sprintf("%s%s:%s:%d", sharedKeyPrefix, apiKey, modelID, window.Unix())
+	return base + ":requests", base + ":tokens"
+}
+
+// remaining returns limit - used, floored at 0.
+func remaining(limit int, used int64) int {
+	if left := limit - int(used); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// counterValue converts a stored counter to int64.
+// MemoryStore returns int64, while RedisStore returns JSON-decoded float64.
+func counterValue(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	default:
+		return 0
+	}
+}
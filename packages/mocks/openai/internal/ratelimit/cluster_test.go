@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestClusterModeSharesLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterMode bool
+		// want is whether each request, alternating between replicas, is allowed
+		want []bool
+	}{
+		{
+			name:        "cluster mode",
+			clusterMode: true,
+			want:        []bool{true, true, true, false, false},
+		},
+		{
+			name:        "per replica",
+			clusterMode: false,
+			want:        []bool{true, true, true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The free tier allows 3 gpt-4o requests per minute
+			shared := store.NewMemoryStore()
+			replicas := make([]*Limiter, 2)
+			for i := range replicas {
+				replicas[i] = NewLimiter(LimiterConfig{
+					Enabled:      true,
+					TierRegistry: NewTierRegistry("free"),
+					Storage:      shared,
+					DefaultTier:  "free",
+					ClusterMode:  tt.clusterMode,
+				})
+			}
+
+			for i, want := range tt.want {
+				result, err := replicas[i%2].Allow(context.Background(), "sk-test", "gpt-4o", 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Allowed != want {
+					t.Errorf("request %d: allowed = %v, want %v", i, result.Allowed, want)
+				}
+			}
+		})
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
 	"github.com/sentra-lab/mocks/openai/internal/store"
 )
 
@@ -28,6 +30,10 @@ type Limiter struct {
 	// enabled controls whether rate limiting is active
 	enabled atomic.Bool
 
+	// clusterMode keeps rate limit state in the shared storage backend
+	// instead of in-memory buckets (see cluster.go)
+	clusterMode atomic.Bool
+
 	// stats tracks rate limiting statistics
 	totalChecks atomic.Int64
 	totalAllowed atomic.Int64
@@ -60,6 +66,10 @@ type LimiterConfig struct {
 
 	// DefaultTier is used when API key has no specific tier
 	DefaultTier string
+
+	// ClusterMode keeps rate limit state in Storage so that all replicas
+	// behind a load balancer enforce the same limits (requires Storage)
+	ClusterMode bool
 }
 
 // NewLimiter creates a new rate limiter.
@@ -71,6 +81,7 @@ func NewLimiter(config LimiterConfig) *Limiter {
 	}
 
 	limiter.enabled.Store(config.Enabled)
+	limiter.clusterMode.Store(config.ClusterMode)
 
 	return limiter
 }
@@ -87,6 +98,38 @@ func (l *Limiter) Allow(ctx context.Context, apiKey string, modelID string, esti
 		}, nil
 	}
 
+	var checkResult *LimitCheckResult
+	var err error
+	if l.clusterMode.Load() {
+		checkResult, err = l.allowShared(ctx, apiKey, modelID, estimatedTokens)
+	} else {
+		checkResult, err = l.allowLocal(apiKey, modelID, estimatedTokens)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Update statistics
+	if checkResult.Allowed {
+		l.totalAllowed.Add(1)
+	} else {
+		l.totalDenied.Add(1)
+
+		// Record metrics
+		limitType := string(checkResult.LimitingFactor)
+		metrics.RecordRateLimitHit(apiKey, limitType, checkResult.RequestsRemaining)
+	}
+
+	// Log rate limit hit
+	if !checkResult.Allowed {
+		metrics.LogRateLimitExceeded(ctx, apiKey, string(checkResult.LimitingFactor), checkResult.RequestsResetIn)
+	}
+
+	return checkResult, nil
+}
+
+// allowLocal checks rate limits against the in-memory token buckets.
+func (l *Limiter) allowLocal(apiKey string, modelID string, estimatedTokens int) (*LimitCheckResult, error) {
 	// Get or create limiter for this API key
 	keyLim, err := l.getKeyLimiter(apiKey)
 	if err != nil {
@@ -102,9 +145,8 @@ func (l *Limiter) Allow(ctx context.Context, apiKey string, modelID string, esti
 	// Check rate limits
 	result := bucket.Allow(estimatedTokens)
 
-	// Build result
-	checkResult := &LimitCheckResult{
-		Allowed:          result.Allowed,
+	return &LimitCheckResult{
+		Allowed:           result.Allowed,
 		RequestsRemaining: result.RequestsResult.Remaining,
 		TokensRemaining:   result.TokensResult.Remaining,
 		RequestsResetIn:   result.RequestsResult.ResetIn,
@@ -113,25 +155,15 @@ func (l *Limiter) Allow(ctx context.Context, apiKey string, modelID string, esti
 		APIKey:            apiKey,
 		ModelID:           modelID,
 		Tier:              keyLim.tier,
-	}
-
-	// Update statistics
-	if result.Allowed {
-		l.totalAllowed.Add(1)
-	} else {
-		l.totalDenied.Add(1)
-
-		// Record metrics
-		limitType := string(result.LimitingFactor)
-		metrics.RecordRateLimitHit(apiKey, limitType, checkResult.RequestsRemaining)
-	}
-
-	// Log rate limit hit
-	if !result.Allowed {
-		metrics.LogRateLimitExceeded(ctx, apiKey, string(result.LimitingFactor), result.RequestsResult.ResetIn)
-	}
+	}, nil
+}
 
-	return checkResult, nil
+// AllowScoped checks rate limits for a request scope.
+// Requests sent with OpenAI-Organization/OpenAI-Project headers get their own
+// buckets, so each organization/project behind a shared API key is limited
+// independently.
+func (l *Limiter) AllowScoped(ctx context.Context, scope models.RequestScope, modelID string, estimatedTokens int) (*LimitCheckResult, error) {
+	return l.Allow(ctx, scope.Key(), modelID, estimatedTokens)
 }
 
 // getKeyLimiter retrieves or creates a limiter for an API key.
@@ -214,6 +246,10 @@ func (l *Limiter) SetTier(apiKey string, tier string) error {
 		return fmt.Errorf("invalid tier: %w", err)
 	}
 
+	if l.clusterMode.Load() {
+		return l.setSharedTier(context.Background(), apiKey, tier)
+	}
+
 	l.bucketsKey.Lock()
 	defer l.bucketsKey.Unlock()
 
@@ -228,8 +264,42 @@ func (l *Limiter) SetTier(apiKey string, tier string) error {
 	return nil
 }
 
+// SetDefaultTier changes the tier used for API keys without an explicit tier.
+// Keys on the previous default tier switch immediately with fresh buckets.
+// In cluster mode the default tier is per replica.
+func (l *Limiter) SetDefaultTier(tier string) error {
+	previous := l.tierRegistry.GetDefaultTier()
+	if err := l.tierRegistry.SetDefaultTier(tier); err != nil {
+		return fmt.Errorf("invalid tier: %w", err)
+	}
+
+	l.bucketsKey.Lock()
+	defer l.bucketsKey.Unlock()
+
+	for _, kl := range l.buckets {
+		if kl.tier != previous {
+			continue
+		}
+		kl.mu.Lock()
+		kl.tier = tier
+		kl.limiters = make(map[string]*DualTokenBucket)
+		kl.mu.Unlock()
+	}
+
+	return nil
+}
+
+// GetDefaultTier returns the tier used for API keys without an explicit tier.
+func (l *Limiter) GetDefaultTier() string {
+	return l.tierRegistry.GetDefaultTier()
+}
+
 // GetLimitInfo returns rate limit information for an API key and model.
 func (l *Limiter) GetLimitInfo(apiKey string, modelID string) (*LimitInfo, error) {
+	if l.clusterMode.Load() {
+		return l.getSharedLimitInfo(context.Background(), apiKey, modelID)
+	}
+
 	keyLim, err := l.getKeyLimiter(apiKey)
 	if err != nil {
 		return nil, err
@@ -256,8 +326,17 @@ func (l *Limiter) GetLimitInfo(apiKey string, modelID string) (*LimitInfo, error
 	}, nil
 }
 
+// GetScopedLimitInfo returns rate limit information for a request scope and model.
+func (l *Limiter) GetScopedLimitInfo(scope models.RequestScope, modelID string) (*LimitInfo, error) {
+	return l.GetLimitInfo(scope.Key(), modelID)
+}
+
 // Reset resets rate limits for an API key.
 func (l *Limiter) Reset(apiKey string) error {
+	if l.clusterMode.Load() {
+		return l.resetShared(context.Background(), sharedKeyPrefix+apiKey+":*")
+	}
+
 	l.bucketsKey.Lock()
 	defer l.bucketsKey.Unlock()
 
@@ -269,6 +348,10 @@ func (l *Limiter) Reset(apiKey string) error {
 
 // ResetAll resets all rate limits.
 func (l *Limiter) ResetAll() {
+	if l.clusterMode.Load() {
+		l.resetShared(context.Background(), sharedKeyPrefix+"*")
+	}
+
 	l.bucketsKey.Lock()
 	defer l.bucketsKey.Unlock()
 
@@ -293,6 +376,11 @@ func (l *Limiter) IsEnabled() bool {
 	return l.enabled.Load()
 }
 
+// IsClusterMode returns whether rate limit state is kept in shared storage.
+func (l *Limiter) IsClusterMode() bool {
+	return l.clusterMode.Load()
+}
+
 // GetStats returns rate limiter statistics.
 func (l *Limiter) GetStats() LimiterStats {
 	totalChecks := l.totalChecks.Load()
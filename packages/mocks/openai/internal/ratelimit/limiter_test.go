@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// newTestLimiter creates an in-memory limiter on the free tier (3 gpt-4o
+// requests per minute).
+func newTestLimiter() *Limiter {
+	return NewLimiter(LimiterConfig{
+		Enabled:      true,
+		TierRegistry: NewTierRegistry("free"),
+		Storage:      store.NewMemoryStore(),
+		DefaultTier:  "free",
+	})
+}
+
+func TestLimiterAllowScoped(t *testing.T) {
+	ctx := context.Background()
+	limiter := newTestLimiter()
+
+	projectA := models.RequestScope{APIKey: "sk-shared", Project: "proj-a"}
+	projectB := models.RequestScope{APIKey: "sk-shared", Project: "proj-b"}
+
+	tests := []struct {
+		name  string
+		scope models.RequestScope
+		want  bool
+	}{
+		{name: "project a 1", scope: projectA, want: true},
+		{name: "project a 2", scope: projectA, want: true},
+		{name: "project a 3", scope: projectA, want: true},
+		{name: "project a exhausted", scope: projectA, want: false},
+		{name: "project b has its own bucket", scope: projectB, want: true},
+		{name: "unscoped key has its own bucket", scope: models.RequestScope{APIKey: "sk-shared"}, want: true},
+	}
+
+	for _, tt := range tests {
+		result, err := limiter.AllowScoped(ctx, tt.scope, "gpt-4o", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != tt.want {
+			t.Errorf("%s: allowed = %v, want %v", tt.name, result.Allowed, tt.want)
+		}
+	}
+
+	info, err := limiter.GetScopedLimitInfo(projectA, "gpt-4o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.APIKey != projectA.Key() || info.RequestLimit != 3 || info.RequestsRemaining != 0 {
+		t.Errorf("GetScopedLimitInfo() = %+v, want project a's exhausted free tier bucket", info)
+	}
+
+	stats := limiter.GetStats()
+	if stats.TotalAllowed != 5 || stats.TotalDenied != 1 {
+		t.Errorf("stats = %+v, want 5 allowed and 1 denied", stats)
+	}
+}
+
+func TestLimiterSetDefaultTier(t *testing.T) {
+	ctx := context.Background()
+	limiter := newTestLimiter()
+
+	for _, key := range []string{"sk-default", "sk-pinned"} {
+		if _, err := limiter.Allow(ctx, key, "gpt-4o", 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := limiter.SetTier("sk-pinned", "tier2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := limiter.SetDefaultTier("no-such-tier"); err == nil {
+		t.Error("SetDefaultTier(no-such-tier) = nil, want an error")
+	}
+	if err := limiter.SetDefaultTier("tier1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := limiter.GetDefaultTier(); got != "tier1" {
+		t.Errorf("GetDefaultTier() = %q, want tier1", got)
+	}
+
+	tests := []struct {
+		apiKey        string
+		wantTier      string
+		wantLimit     int
+		wantRemaining int
+	}{
+		// Keys on the previous default switch with fresh buckets
+		{apiKey: "sk-default", wantTier: "tier1", wantLimit: 500, wantRemaining: 500},
+		{apiKey: "sk-new", wantTier: "tier1", wantLimit: 500, wantRemaining: 500},
+		// Keys on another tier keep it
+		{apiKey: "sk-pinned", wantTier: "tier2", wantLimit: 5000, wantRemaining: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.apiKey, func(t *testing.T) {
+			info, err := limiter.GetLimitInfo(tt.apiKey, "gpt-4o")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Tier != tt.wantTier || info.RequestLimit != tt.wantLimit || info.RequestsRemaining != tt.wantRemaining {
+				t.Errorf("GetLimitInfo() = %+v, want %s with %d/%d requests", info, tt.wantTier, tt.wantRemaining, tt.wantLimit)
+			}
+		})
+	}
+}
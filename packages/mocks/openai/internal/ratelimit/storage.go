@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/sentra-lab/mocks/openai/internal/clock"
 	"github.com/sentra-lab/mocks/openai/internal/store"
 )
 
@@ -97,7 +98,7 @@ func (pl *PersistentLimiter) loadState(ctx context.Context, apiKey string, model
 	// Get last refill time
 	lastRefill, err := pl.storage.GetLastRefill(ctx, key+":refill")
 	if err != nil {
-		lastRefill = time.Now() // Default to now if not found
+		lastRefill = clock.Now() // Default to now if not found
 	}
 
 	// Update in-memory bucket state
@@ -131,7 +132,7 @@ func (pl *PersistentLimiter) saveState(ctx context.Context, apiKey string, model
 	}
 
 	// Save last refill time
-	if err := pl.storage.SetLastRefill(ctx, key+":refill", time.Now()); err != nil {
+	if err := pl.storage.SetLastRefill(ctx, key+":refill", clock.Now()); err != nil {
 		return fmt.Errorf("failed to save refill time: %w", err)
 	}
 
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
 )
 
 // TokenBucket implements the token bucket rate limiting algorithm.
@@ -31,7 +33,7 @@ type TokenBucket struct {
 // capacity: maximum tokens the bucket can hold
 // refillPerMinute: how many tokens are added per minute
 func NewTokenBucket(capacity int, refillPerMinute int) *TokenBucket {
-	now := time.Now()
+	now := clock.Now()
 
 	return &TokenBucket{
 		capacity:   capacity,
@@ -94,7 +96,7 @@ func (tb *TokenBucket) AllowWithInfo(tokens int) AllowResult {
 // refill adds tokens based on elapsed time since last refill.
 // This implements continuous refill (not fixed-window).
 func (tb *TokenBucket) refill() {
-	now := time.Now()
+	now := clock.Now()
 	elapsed := now.Sub(tb.lastRefill)
 
 	// Calculate tokens to add
@@ -130,7 +132,7 @@ func (tb *TokenBucket) Reset() {
 	defer tb.mu.Unlock()
 
 	tb.tokens = float64(tb.capacity)
-	tb.lastRefill = time.Now()
+	tb.lastRefill = clock.Now()
 }
 
 // SetCapacity changes the bucket capacity.
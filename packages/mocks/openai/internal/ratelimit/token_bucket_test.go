@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+)
+
+func TestTokenBucketRefillsOnVirtualClock(t *testing.T) {
+	t.Cleanup(clock.Reset)
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    int
+	}{
+		{name: "no time", advance: 0, want: 0},
+		{name: "half a minute", advance: 30 * time.Second, want: 30},
+		{name: "a minute", advance: time.Minute, want: 60},
+		{name: "capped at capacity", advance: time.Hour, want: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := NewTokenBucket(60, 60)
+			if !bucket.Allow(60) {
+				t.Fatal("Allow(60) on a full bucket = false")
+			}
+
+			if err := clock.Advance(tt.advance); err != nil {
+				t.Fatal(err)
+			}
+			// Real time also passes while the test runs
+			if got := bucket.GetState().Available; got < tt.want || got > tt.want+1 {
+				t.Errorf("Available = %d after %s, want %d", got, tt.advance, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucketTimeUntilAvailable(t *testing.T) {
+	t.Cleanup(clock.Reset)
+
+	bucket := NewTokenBucket(10, 60)
+	if !bucket.Allow(10) {
+		t.Fatal("Allow(10) on a full bucket = false")
+	}
+
+	if got := bucket.TimeUntilAvailable(5); got <= 4*time.Second || got > 5*time.Second {
+		t.Errorf("TimeUntilAvailable(5) = %s, want about 5s", got)
+	}
+	if err := clock.Advance(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := bucket.TimeUntilAvailable(5); got != 0 {
+		t.Errorf("TimeUntilAvailable(5) after 5s = %s, want 0", got)
+	}
+
+	bucket.Reset()
+	if got := bucket.GetState().Available; got != 10 {
+		t.Errorf("Available after Reset() = %d, want 10", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the admin API used by the Sentra Lab engine to
+// inspect and control the mock during a test run.
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// adminPrefix is the path prefix for admin routes (not part of the OpenAI API).
+const adminPrefix = "/_sentra"
+
+// setupAdminRoutes registers the admin API.
+func (s *Server) setupAdminRoutes() {
+	admin := s.engine.Group(adminPrefix)
+
+	if s.experiments != nil {
+		admin.GET("/experiments", s.handleGetExperiments)
+		admin.POST("/experiments/:name/outcomes", s.handleRecordOutcome)
+		admin.DELETE("/experiments/results", s.handleResetExperiments)
+	}
+
+	s.setupFaultRoutes(admin)
+	s.setupOverrideRoutes(admin)
+	s.setupClockRoutes(admin)
+	s.setupLocaleRoutes(admin)
+	s.setupCaptureRoutes(admin)
+	s.setupReplayRoutes(admin)
+	s.setupSDKRoutes(admin)
+	s.setupDeprecationRoutes(admin)
+	s.setupRegistryRoutes(admin)
+	s.setupCalibrationRoutes(admin)
+	s.setupSynthesisRoutes(admin)
+	s.setupThreadAdminRoutes(admin)
+	s.setupLatencyReportRoutes(admin)
+	s.setupCostRoutes(admin)
+	s.setupContextRoutes(admin)
+	s.setupOpenAPIRoutes(admin)
+	s.setupStateRoutes(admin)
+	s.setupDeadlineRoutes(admin)
+	s.setupPreflightRoutes(admin)
+}
+
+// outcomeRequest is the body of POST /_sentra/experiments/:name/outcomes.
+type outcomeRequest struct {
+	// Variant is the variant the scenario ran against (or set Unit)
+	Variant string `json:"variant"`
+
+	// Unit is the experiment unit the scenario used; its variant is derived
+	Unit string `json:"unit"`
+
+	// Outcome is the scenario result (e.g., "passed", "failed")
+	Outcome string `json:"outcome" binding:"required"`
+}
+
+// handleGetExperiments reports per-variant assignments and outcomes.
+func (s *Server) handleGetExperiments(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   s.experiments.Report(),
+	})
+}
+
+// handleRecordOutcome records a scenario outcome against a variant.
+func (s *Server) handleRecordOutcome(c *gin.Context) {
+	var req outcomeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	experiment := c.Param("name")
+	variant := req.Variant
+	if variant == "" {
+		derived, err := s.experiments.VariantForUnit(experiment, req.Unit)
+		if err != nil {
+			abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+			return
+		}
+		variant = derived
+	}
+
+	if err := s.experiments.RecordOutcome(experiment, variant, req.Outcome); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"experiment": experiment,
+		"variant":    variant,
+		"outcome":    req.Outcome,
+	})
+}
+
+// handleResetExperiments clears assignment and outcome counts.
+func (s *Server) handleResetExperiments(c *gin.Context) {
+	s.experiments.ResetResults()
+	c.Status(http.StatusNoContent)
+}
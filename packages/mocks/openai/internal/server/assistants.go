@@ -0,0 +1,312 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the Assistants API's assistants: the model,
+// instructions and tools that runs on a thread use unless they override
+// them (see threads.go). Assistants are kept in the server's storage, so
+// replicas sharing Redis see the same assistants.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// Storage keys of assistants.
+const (
+	// assistantKeyPrefix prefixes each assistant's key
+	assistantKeyPrefix = "assistants:"
+
+	// assistantSeqKey orders assistants across replicas
+	assistantSeqKey = "assistants_seq"
+)
+
+// storedAssistant is the stored form of an assistant.
+type storedAssistant struct {
+	Assistant models.Assistant `json:"assistant"`
+	Seq       int64            `json:"seq"`
+}
+
+// assistantStore keeps assistants in storage (memory, or Redis shared by
+// replicas). Records are stored as JSON strings, so memory and Redis
+// storage return them alike.
+type assistantStore struct {
+	storage store.Storage
+}
+
+// newAssistantStore creates an assistant store. Without storage,
+// assistants are kept in memory.
+func newAssistantStore(storage store.Storage) *assistantStore {
+	if storage == nil {
+		storage = store.NewMemoryStore()
+	}
+	return &assistantStore{storage: storage}
+}
+
+// put stores a new assistant.
+func (st *assistantStore) put(ctx context.Context, assistant models.Assistant) error {
+	seq, err := st.storage.Increment(ctx, assistantSeqKey, 1)
+	if err != nil {
+		return err
+	}
+	return st.save(ctx, storedAssistant{Assistant: assistant, Seq: seq})
+}
+
+// save writes an assistant's record.
+func (st *assistantStore) save(ctx context.Context, record storedAssistant) error {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return st.storage.Set(ctx, assistantKeyPrefix+record.Assistant.ID, string(encoded), 0)
+}
+
+// load returns an assistant's record.
+func (st *assistantStore) load(ctx context.Context, assistantID string) (*storedAssistant, bool) {
+	value, err := st.storage.Get(ctx, assistantKeyPrefix+assistantID)
+	if err != nil {
+		return nil, false
+	}
+	return decodeStoredAssistant(value)
+}
+
+// get returns an assistant.
+func (st *assistantStore) get(ctx context.Context, assistantID string) (models.Assistant, bool) {
+	record, ok := st.load(ctx, assistantID)
+	if !ok {
+		return models.Assistant{}, false
+	}
+	return record.Assistant, true
+}
+
+// list returns the assistants, oldest first.
+func (st *assistantStore) list(ctx context.Context) ([]models.Assistant, error) {
+	keys, err := st.storage.Keys(ctx, assistantKeyPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	values, err := st.storage.GetMulti(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*storedAssistant, 0, len(values))
+	for _, value := range values {
+		if record, ok := decodeStoredAssistant(value); ok {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Seq < records[j].Seq
+	})
+
+	assistants := make([]models.Assistant, len(records))
+	for i, record := range records {
+		assistants[i] = record.Assistant
+	}
+	return assistants, nil
+}
+
+// delete removes an assistant. Returns false if it does not exist.
+func (st *assistantStore) delete(ctx context.Context, assistantID string) (bool, error) {
+	exists, err := st.storage.Exists(ctx, assistantKeyPrefix+assistantID)
+	if err != nil || !exists {
+		return false, err
+	}
+	return true, st.storage.Delete(ctx, assistantKeyPrefix+assistantID)
+}
+
+// reset discards every assistant and returns how many there were.
+func (st *assistantStore) reset(ctx context.Context) (int, error) {
+	keys, err := st.storage.Keys(ctx, assistantKeyPrefix+"*")
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	return len(keys), st.storage.DeleteMulti(ctx, keys)
+}
+
+// count returns the number of assistants.
+func (st *assistantStore) count(ctx context.Context) int {
+	keys, err := st.storage.Keys(ctx, assistantKeyPrefix+"*")
+	if err != nil {
+		return 0
+	}
+	return len(keys)
+}
+
+// decodeStoredAssistant decodes a stored assistant record.
+func decodeStoredAssistant(value interface{}) (*storedAssistant, bool) {
+	encoded, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var record storedAssistant
+	if json.Unmarshal([]byte(encoded), &record) != nil {
+		return nil, false
+	}
+	return &record, true
+}
+
+// setupAssistantRoutes registers the assistants API.
+func (s *Server) setupAssistantRoutes() {
+	s.api.POST("/assistants", s.handleCreateAssistant)
+	s.api.GET("/assistants", s.handleListAssistants)
+	s.api.GET("/assistants/:assistant_id", s.handleGetAssistant)
+	s.api.POST("/assistants/:assistant_id", s.handleModifyAssistant)
+	s.api.DELETE("/assistants/:assistant_id", s.handleDeleteAssistant)
+}
+
+// assistantNotFound aborts with the API's 404 for an unknown assistant.
+func assistantNotFound(c *gin.Context, assistantID string) {
+	abortWithError(c, models.NewNotFoundError(fmt.Sprintf("No assistant found with id '%s'.", assistantID)))
+}
+
+// handleCreateAssistant creates an assistant.
+func (s *Server) handleCreateAssistant(c *gin.Context) {
+	var req models.CreateAssistantRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	tools := req.Tools
+	if tools == nil {
+		tools = []models.AssistantTool{}
+	}
+	metadata := req.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	assistant := models.Assistant{
+		ID:           models.NewAssistantID(),
+		Object:       "assistant",
+		CreatedAt:    clock.Now().Unix(),
+		Name:         req.Name,
+		Description:  req.Description,
+		Model:        req.Model,
+		Instructions: req.Instructions,
+		Tools:        tools,
+		Metadata:     metadata,
+	}
+
+	ctx := c.Request.Context()
+	if err := s.assistants.put(ctx, assistant); err != nil {
+		metrics.Warn(ctx, "failed to store assistant", "error", err)
+		abortWithError(c, models.NewServerError("The assistant could not be stored."))
+		return
+	}
+
+	c.JSON(http.StatusOK, assistant)
+}
+
+// handleListAssistants lists the assistants (?order=asc|desc, ?limit=).
+func (s *Server) handleListAssistants(c *gin.Context) {
+	ctx := c.Request.Context()
+	assistants, err := s.assistants.list(ctx)
+	if err != nil {
+		metrics.Warn(ctx, "failed to list assistants", "error", err)
+	}
+
+	ids := make([]string, len(assistants))
+	for i, assistant := range assistants {
+		ids[i] = assistant.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(ids))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.Assistant, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, assistants[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// handleGetAssistant returns an assistant.
+func (s *Server) handleGetAssistant(c *gin.Context) {
+	assistantID := c.Param("assistant_id")
+	assistant, ok := s.assistants.get(c.Request.Context(), assistantID)
+	if !ok {
+		assistantNotFound(c, assistantID)
+		return
+	}
+	c.JSON(http.StatusOK, assistant)
+}
+
+// handleModifyAssistant updates the fields of an assistant the request sets.
+func (s *Server) handleModifyAssistant(c *gin.Context) {
+	var req models.ModifyAssistantRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	assistantID := c.Param("assistant_id")
+	ctx := c.Request.Context()
+	record, ok := s.assistants.load(ctx, assistantID)
+	if !ok {
+		assistantNotFound(c, assistantID)
+		return
+	}
+
+	assistant := &record.Assistant
+	if req.Model != "" {
+		assistant.Model = req.Model
+	}
+	if req.Name != nil {
+		assistant.Name = req.Name
+	}
+	if req.Description != nil {
+		assistant.Description = req.Description
+	}
+	if req.Instructions != nil {
+		assistant.Instructions = req.Instructions
+	}
+	if req.Tools != nil {
+		assistant.Tools = req.Tools
+	}
+	if req.Metadata != nil {
+		assistant.Metadata = req.Metadata
+	}
+
+	if err := s.assistants.save(ctx, *record); err != nil {
+		metrics.Warn(ctx, "failed to store assistant", "error", err)
+		abortWithError(c, models.NewServerError("The assistant could not be stored."))
+		return
+	}
+
+	c.JSON(http.StatusOK, assistant)
+}
+
+// handleDeleteAssistant deletes an assistant. Its runs are kept.
+func (s *Server) handleDeleteAssistant(c *gin.Context) {
+	assistantID := c.Param("assistant_id")
+	ctx := c.Request.Context()
+	deleted, err := s.assistants.delete(ctx, assistantID)
+	if err != nil {
+		metrics.Warn(ctx, "failed to delete assistant", "error", err)
+		abortWithError(c, models.NewServerError("The assistant could not be deleted."))
+		return
+	}
+	if !deleted {
+		assistantNotFound(c, assistantID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      assistantID,
+		"object":  "assistant.deleted",
+		"deleted": true,
+	})
+}
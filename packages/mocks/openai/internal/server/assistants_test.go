@@ -0,0 +1,168 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// createAssistant creates an assistant and returns it.
+func createAssistant(t *testing.T, s *Server, body string) models.Assistant {
+	t.Helper()
+
+	rec := serve(s, http.MethodPost, "/v1/assistants", body, nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var assistant models.Assistant
+	decodeJSON(t, rec, &assistant)
+	return assistant
+}
+
+func TestCreateAssistant(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		wantParam string
+	}{
+		{name: "minimal", body: `{"model":"gpt-4o"}`, status: http.StatusOK},
+		{
+			name:   "with tools",
+			body:   `{"model":"gpt-4o","name":"Weather","instructions":"Be brief.","tools":[{"type":"function","function":{"name":"get_weather"}}],"metadata":{"team":"core"}}`,
+			status: http.StatusOK,
+		},
+		{name: "missing model", body: `{"name":"Weather"}`, status: http.StatusBadRequest, wantParam: "model"},
+		{name: "name too long", body: `{"model":"gpt-4o","name":"` + strings.Repeat("a", models.MaxAssistantNameLength+1) + `"}`, status: http.StatusBadRequest, wantParam: "name"},
+		{name: "malformed body", body: `{"model":`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodPost, "/v1/assistants", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var assistant models.Assistant
+			decodeJSON(t, rec, &assistant)
+			if !strings.HasPrefix(assistant.ID, "asst") || assistant.Object != "assistant" {
+				t.Errorf("id = %q, object = %q", assistant.ID, assistant.Object)
+			}
+			if assistant.Tools == nil || assistant.Metadata == nil {
+				t.Errorf("tools = %v, metadata = %v, want empty rather than null", assistant.Tools, assistant.Metadata)
+			}
+
+			rec = serve(s, http.MethodGet, "/v1/assistants/"+assistant.ID, "", nil)
+			expectStatus(t, rec, http.StatusOK)
+			var got models.Assistant
+			decodeJSON(t, rec, &got)
+			if got.ID != assistant.ID || got.Model != assistant.Model || len(got.Tools) != len(assistant.Tools) {
+				t.Errorf("GET returned %+v, want %+v", got, assistant)
+			}
+		})
+	}
+}
+
+func TestModifyAssistant(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	assistant := createAssistant(t, s, `{"model":"gpt-4o","name":"Weather","instructions":"Be brief.","metadata":{"team":"core"}}`)
+
+	rec := serve(s, http.MethodPost, "/v1/assistants/"+assistant.ID, `{"model":"gpt-4o-mini","name":"Forecast"}`, nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var got models.Assistant
+	decodeJSON(t, rec, &got)
+	if got.Model != "gpt-4o-mini" || got.Name == nil || *got.Name != "Forecast" {
+		t.Errorf("model = %q, name = %v, want the updated values", got.Model, got.Name)
+	}
+	if got.Instructions == nil || *got.Instructions != "Be brief." || got.Metadata["team"] != "core" {
+		t.Errorf("instructions = %v, metadata = %v, want them unchanged", got.Instructions, got.Metadata)
+	}
+
+	expectStatus(t, serve(s, http.MethodPost, "/v1/assistants/asst_missing", `{"name":"Forecast"}`, nil), http.StatusNotFound)
+}
+
+func TestDeleteAssistant(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	assistant := createAssistant(t, s, `{"model":"gpt-4o"}`)
+
+	rec := serve(s, http.MethodDelete, "/v1/assistants/"+assistant.ID, "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var resp struct {
+		ID      string `json:"id"`
+		Object  string `json:"object"`
+		Deleted bool   `json:"deleted"`
+	}
+	decodeJSON(t, rec, &resp)
+	if resp.ID != assistant.ID || resp.Object != "assistant.deleted" || !resp.Deleted {
+		t.Errorf("delete response = %+v", resp)
+	}
+
+	expectStatus(t, serve(s, http.MethodGet, "/v1/assistants/"+assistant.ID, "", nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodDelete, "/v1/assistants/"+assistant.ID, "", nil), http.StatusNotFound)
+}
+
+func TestListAssistants(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, createAssistant(t, s, `{"model":"gpt-4o"}`).ID)
+	}
+	reversed := slices.Clone(ids)
+	slices.Reverse(reversed)
+
+	tests := []struct {
+		name        string
+		query       string
+		status      int
+		want        []string
+		wantHasMore bool
+		wantParam   string
+	}{
+		{name: "newest first", status: http.StatusOK, want: reversed},
+		{name: "oldest first", query: "?order=asc", status: http.StatusOK, want: ids},
+		{name: "limit", query: "?limit=2", status: http.StatusOK, want: reversed[:2], wantHasMore: true},
+		{name: "limit too large", query: "?limit=101", status: http.StatusBadRequest, wantParam: "limit"},
+		{name: "invalid order", query: "?order=newest", status: http.StatusBadRequest, wantParam: "order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, "/v1/assistants"+tt.query, "", nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var resp struct {
+				Data    []models.Assistant `json:"data"`
+				FirstID string             `json:"first_id"`
+				LastID  string             `json:"last_id"`
+				HasMore bool               `json:"has_more"`
+			}
+			decodeJSON(t, rec, &resp)
+			var got []string
+			for _, assistant := range resp.Data {
+				got = append(got, assistant.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("assistants = %v, want %v", got, tt.want)
+			}
+			if resp.FirstID != tt.want[0] || resp.LastID != tt.want[len(tt.want)-1] || resp.HasMore != tt.wantHasMore {
+				t.Errorf("first_id = %q, last_id = %q, has_more = %v", resp.FirstID, resp.LastID, resp.HasMore)
+			}
+		})
+	}
+}
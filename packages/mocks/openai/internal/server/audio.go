@@ -0,0 +1,94 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements text-to-speech (/v1/audio/speech), returning small
+// synthetic audio payloads after a latency that scales with the input and
+// varies by voice, billed per input character.
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+)
+
+// setupAudioRoutes registers the audio endpoints.
+func (s *Server) setupAudioRoutes() {
+	s.api.POST("/audio/speech", s.handleSpeech)
+}
+
+// handleSpeech synthesizes speech for the input text.
+func (s *Server) handleSpeech(c *gin.Context) {
+	var req models.SpeechRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	config, _, err := s.ResolveModel(c, req.Model)
+	if err == nil {
+		err = req.ValidateModel(config)
+	}
+	if err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	speech := generator.SyntheticSpeech{
+		Text:   req.Input,
+		Voice:  req.Voice,
+		Format: req.GetEffectiveResponseFormat(),
+		Speed:  req.GetEffectiveSpeed(),
+	}
+	data, err := speech.Encode()
+	if err != nil {
+		abortWithError(c, models.NewServerError(fmt.Sprintf("failed to synthesize speech: %v", err)))
+		return
+	}
+
+	if s.latency != nil {
+		ctx := c.Request.Context()
+		simulated, err := s.latency.SimulateSpeechAndSleep(ctx, config.ID, req.Voice, req.Characters(), GetServiceTier(c, ""))
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			metrics.Warn(ctx, "failed to simulate speech latency", "error", err)
+		} else {
+			c.Header(latency.HeaderLatencyTTFT, fmt.Sprintf("%d", simulated.Milliseconds()))
+		}
+	}
+
+	s.billSpeech(c, req.Model, req.Characters())
+
+	c.Header("X-Sentra-Audio-Duration-Ms", fmt.Sprintf("%d", speech.Duration().Milliseconds()))
+	c.Data(http.StatusOK, speech.ContentType(), data)
+}
+
+// billSpeech sets the speech cost headers and records the cost against the
+// request's scope. Models without speech pricing are not billed.
+func (s *Server) billSpeech(c *gin.Context, model string, characters int) {
+	if s.tracker == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	cost, err := s.tracker.Calculator().CalculateSpeechCost(ctx, model, characters)
+	if err != nil {
+		return
+	}
+	pricing.AddSpeechCostHeaders(c.Writer, cost)
+
+	tracked := pricing.Cost{
+		TotalCost: cost.TotalCost,
+		Currency:  cost.Currency,
+		Model:     model,
+	}
+	if err := s.tracker.TrackScoped(ctx, GetScope(c), model, tracked); err != nil {
+		metrics.Warn(ctx, "failed to track speech usage", "error", err)
+	}
+}
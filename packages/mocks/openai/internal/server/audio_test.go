@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestSpeech(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		status          int
+		wantContentType string
+		wantCharacters  string
+		wantParam       string
+	}{
+		{name: "mp3 by default", body: `{"model":"tts-1","input":"Hello there","voice":"alloy"}`, status: http.StatusOK, wantContentType: "audio/mpeg", wantCharacters: "11"},
+		{name: "wav", body: `{"model":"tts-1-hd","input":"Grüße","voice":"nova","response_format":"wav"}`, status: http.StatusOK, wantContentType: "audio/wav", wantCharacters: "5"},
+		{name: "pcm", body: `{"model":"gpt-4o-mini-tts","input":"Hi","voice":"ballad","response_format":"pcm"}`, status: http.StatusOK, wantContentType: "audio/pcm", wantCharacters: "2"},
+		{name: "unknown voice", body: `{"model":"tts-1","input":"Hi","voice":"siri"}`, status: http.StatusBadRequest, wantParam: "voice"},
+		{name: "voice needs gpt-4o-mini-tts", body: `{"model":"tts-1","input":"Hi","voice":"ballad"}`, status: http.StatusBadRequest, wantParam: "voice"},
+		{name: "chat model", body: `{"model":"gpt-4o","input":"Hi","voice":"alloy"}`, status: http.StatusBadRequest},
+		{name: "unknown model", body: `{"model":"tts-9","input":"Hi","voice":"alloy"}`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := pricing.NewTracker(pricing.NewCalculator(pricing.NewPricingDB()), store.NewMemoryStore())
+			s := newTestServer(t, Dependencies{Tracker: tracker})
+
+			rec := serve(s, http.MethodPost, "/v1/audio/speech", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if tt.wantParam != "" {
+					if got := errorParam(t, rec); got != tt.wantParam {
+						t.Errorf("error param = %q, want %q", got, tt.wantParam)
+					}
+				}
+				return
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if rec.Body.Len() == 0 {
+				t.Error("no audio in the response")
+			}
+			if ms, err := strconv.Atoi(rec.Header().Get("X-Sentra-Audio-Duration-Ms")); err != nil || ms <= 0 {
+				t.Errorf("X-Sentra-Audio-Duration-Ms = %q", rec.Header().Get("X-Sentra-Audio-Duration-Ms"))
+			}
+			if got := rec.Header().Get("X-Sentra-Speech-Characters"); got != tt.wantCharacters {
+				t.Errorf("X-Sentra-Speech-Characters = %q, want %s", got, tt.wantCharacters)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements model brownouts: while the API request rate is above
+// a model's threshold, a share of its requests fail with a 503
+// model_overloaded error and other models stay healthy.
+package server
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadMiddleware counts API requests towards the request rate brownouts
+// are triggered by. Without brownouts the rate is not measured.
+func (s *Server) LoadMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.brownouts != nil {
+			s.load.RecordRequest()
+		}
+		c.Next()
+	}
+}
+
+// CheckBrownout rejects a request for modelID with 503 model_overloaded
+// and returns false if the model is browned out at the current request
+// rate.
+func (s *Server) CheckBrownout(c *gin.Context, modelID string) bool {
+	if s.brownouts == nil {
+		return true
+	}
+
+	apiErr, overloaded := s.brownouts.ShouldBrownout(c.Request.Context(), modelID, s.load.GetCurrentRPS())
+	if !overloaded {
+		return true
+	}
+
+	c.Header("Retry-After", strconv.Itoa(apiErr.RetryAfter))
+	abortWithError(c, *apiErr)
+	return false
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestChatCompletionBrownout(t *testing.T) {
+	// gpt-4o always browns out; other models stay healthy
+	brownouts := behavior.NewBrownoutSimulator(behavior.BrownoutConfig{
+		Enabled: true,
+		Rules: []behavior.BrownoutRule{
+			{Model: "gpt-4o", LoadThreshold: 0, FailureRate: 1, RetryAfter: 20},
+			{Model: "gpt-4*", LoadThreshold: 1000, FailureRate: 1, RetryAfter: 20},
+		},
+	})
+	s := newTestServer(t, Dependencies{Brownouts: brownouts})
+
+	tests := []struct {
+		model  string
+		status int
+	}{
+		{model: "gpt-4o", status: http.StatusServiceUnavailable},
+		{model: "gpt-4", status: http.StatusOK},
+		{model: "gpt-3.5-turbo", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			body := `{"model":"` + tt.model + `","messages":[{"role":"user","content":"Hello"}]}`
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status == http.StatusOK {
+				return
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != "20" {
+				t.Errorf("Retry-After = %q, want 20", got)
+			}
+			var resp models.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error.Code == nil || *resp.Error.Code != models.ErrorCodeModelOverloaded {
+				t.Errorf("error code = %v, want %s", resp.Error.Code, models.ErrorCodeModelOverloaded)
+			}
+		})
+	}
+}
+
+func TestChatCompletionBrownoutLoadThreshold(t *testing.T) {
+	// Browns out from 1 RPS, i.e. 10 requests in the 10s window
+	brownouts := behavior.NewBrownoutSimulator(behavior.BrownoutConfig{
+		Enabled: true,
+		Rules:   []behavior.BrownoutRule{{Model: "gpt-4o", LoadThreshold: 1, FailureRate: 1, RetryAfter: 1}},
+	})
+	s := newTestServer(t, Dependencies{Brownouts: brownouts})
+
+	const body = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`
+	for i := 0; i < 9; i++ {
+		expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", body, nil), http.StatusOK)
+	}
+	expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", body, nil), http.StatusServiceUnavailable)
+}
@@ -0,0 +1,133 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements capability discovery: what this build of the mock
+// implements and which optional features are enabled, so clients can tell
+// an older or differently configured image apart from a broken one.
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// capabilitiesPath serves the capabilities under the API base URL, so
+// clients that only know the /v1 base URL can discover them. It is
+// registered outside the API middleware: it is not captured, rate limited
+// or faulted.
+const capabilitiesPath = "/v1/__sentra/capabilities"
+
+// capabilitiesResponse describes what the running mock provides.
+type capabilitiesResponse struct {
+	// Object is always "sentra.capabilities"
+	Object string `json:"object"`
+
+	// Mock is the mocked service ("openai")
+	Mock string `json:"mock"`
+
+	// Version is the mock's build version
+	Version string `json:"version"`
+
+	// Endpoints are the implemented API routes ("METHOD path")
+	Endpoints []string `json:"endpoints"`
+
+	// APIs are the implemented API families (the first path segment after
+	// /v1, e.g. "assistants", "fine_tuning")
+	APIs []string `json:"apis"`
+
+	// Admin are the implemented /_sentra admin API families (e.g.
+	// "capture", "clock")
+	Admin []string `json:"admin"`
+
+	// Models are the registered model IDs
+	Models []string `json:"models"`
+
+	// Features are the optional behaviors enabled at startup
+	Features []string `json:"features"`
+}
+
+// setupCapabilityRoutes registers capability discovery.
+func (s *Server) setupCapabilityRoutes() {
+	s.engine.GET(capabilitiesPath, s.handleGetCapabilities)
+}
+
+// handleGetCapabilities reports the mock's version, endpoints, models and
+// enabled features.
+func (s *Server) handleGetCapabilities(c *gin.Context) {
+	resp := capabilitiesResponse{
+		Object:    "sentra.capabilities",
+		Mock:      "openai",
+		Version:   s.config.Version,
+		Endpoints: []string{},
+		Models:    []string{},
+		Features:  s.features(),
+	}
+
+	apis := make(map[string]bool)
+	admin := make(map[string]bool)
+	for _, route := range s.engine.Routes() {
+		switch {
+		case route.Path == capabilitiesPath:
+		case strings.HasPrefix(route.Path, "/v1/"):
+			resp.Endpoints = append(resp.Endpoints, route.Method+" "+route.Path)
+			apis[firstSegment(strings.TrimPrefix(route.Path, "/v1/"))] = true
+		case strings.HasPrefix(route.Path, adminPrefix+"/"):
+			admin[firstSegment(strings.TrimPrefix(route.Path, adminPrefix+"/"))] = true
+		}
+	}
+	sort.Strings(resp.Endpoints)
+	resp.APIs = sortedSet(apis)
+	resp.Admin = sortedSet(admin)
+
+	for _, config := range models.GetAllModelConfigs() {
+		resp.Models = append(resp.Models, config.ID)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// features lists the optional behaviors that are enabled.
+func (s *Server) features() []string {
+	features := []string{}
+	if s.latency != nil && s.latency.IsEnabled() {
+		features = append(features, "latency")
+	}
+	if s.limiter != nil && s.limiter.IsEnabled() {
+		features = append(features, "rate_limits")
+	}
+	if s.errorInjector != nil && s.errorInjector.IsEnabled() {
+		features = append(features, "error_injection")
+	}
+	if s.experiments != nil {
+		features = append(features, "experiments")
+	}
+	if s.fixtures != nil {
+		features = append(features, "fixtures")
+	}
+	if s.replay.active() {
+		features = append(features, "replay")
+	}
+	if s.scheduler != nil {
+		features = append(features, "service_tiers")
+	}
+	return features
+}
+
+// firstSegment returns the first segment of a path.
+func firstSegment(path string) string {
+	segment, _, _ := strings.Cut(path, "/")
+	return segment
+}
+
+// sortedSet returns a set's members in order.
+func sortedSet(set map[string]bool) []string {
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
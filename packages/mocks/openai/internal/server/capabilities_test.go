@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	tests := []struct {
+		name         string
+		deps         Dependencies
+		wantFeatures []string
+	}{
+		{name: "no simulation", wantFeatures: []string{}},
+		{
+			name: "latency and error injection",
+			deps: Dependencies{
+				Latency:       latency.NewSimulator(latency.DefaultSimulatorConfig()),
+				ErrorInjector: behavior.NewErrorInjector(behavior.DefaultErrorInjectorConfig()),
+			},
+			wantFeatures: []string{"latency", "error_injection"},
+		},
+		{
+			name:         "experiments",
+			deps:         Dependencies{Experiments: fixtures.NewExperiments()},
+			wantFeatures: []string{"experiments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.deps)
+
+			rec := serve(s, http.MethodGet, capabilitiesPath, "", nil)
+			expectStatus(t, rec, http.StatusOK)
+
+			var resp capabilitiesResponse
+			decodeJSON(t, rec, &resp)
+			if resp.Object != "sentra.capabilities" || resp.Mock != "openai" {
+				t.Errorf("object = %q, mock = %q", resp.Object, resp.Mock)
+			}
+			if !slices.Equal(resp.Features, tt.wantFeatures) {
+				t.Errorf("features = %v, want %v", resp.Features, tt.wantFeatures)
+			}
+			if !slices.Contains(resp.Endpoints, "POST /v1/chat/completions") {
+				t.Errorf("endpoints %v do not include POST /v1/chat/completions", resp.Endpoints)
+			}
+			if slices.Contains(resp.Endpoints, "GET "+capabilitiesPath) {
+				t.Error("endpoints list the capabilities endpoint itself")
+			}
+			for _, want := range []string{"chat", "files", "threads"} {
+				if !slices.Contains(resp.APIs, want) {
+					t.Errorf("apis %v do not include %s", resp.APIs, want)
+				}
+			}
+			for _, want := range []string{"clock", "locale", "preflight"} {
+				if !slices.Contains(resp.Admin, want) {
+					t.Errorf("admin %v does not include %s", resp.Admin, want)
+				}
+			}
+			if !slices.IsSorted(resp.Endpoints) || !slices.IsSorted(resp.APIs) || !slices.IsSorted(resp.Admin) {
+				t.Error("capabilities are not sorted")
+			}
+			if !slices.Contains(resp.Models, "gpt-4o") {
+				t.Errorf("models %v do not include gpt-4o", resp.Models)
+			}
+		})
+	}
+}
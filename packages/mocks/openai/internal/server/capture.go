@@ -0,0 +1,385 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements full-trace capture: while a run is active, complete
+// API exchanges (headers, bodies, timing) are buffered so the engine can
+// append them to the run's recording when simulation.record_full_trace is on.
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// HeaderRunID attributes a request to a run, overriding the active run.
+const HeaderRunID = "X-Sentra-Run-Id"
+
+// redactedHeaders are recorded with their values masked.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Api-Key":       true,
+	"X-Api-Key":     true,
+	"Cookie":        true,
+}
+
+// CaptureConfig configures full-trace capture.
+type CaptureConfig struct {
+	// MaxBodyBytes is the most of each request/response body kept (longer bodies are truncated)
+	MaxBodyBytes int64
+
+	// MaxExchanges bounds the buffer; the oldest exchanges are dropped first
+	MaxExchanges int
+}
+
+// DefaultCaptureConfig returns default capture configuration.
+func DefaultCaptureConfig() CaptureConfig {
+	return CaptureConfig{
+		MaxBodyBytes: 1 << 20,
+		MaxExchanges: 10000,
+	}
+}
+
+// Exchange is one captured request/response pair.
+type Exchange struct {
+	// Seq orders exchanges within the buffer
+	Seq int64 `json:"seq"`
+
+	// RunID is the run the exchange belongs to
+	RunID string `json:"run_id"`
+
+	// RequestID is the x-request-id returned to the client
+	RequestID string `json:"request_id"`
+
+	// Method and Path identify the endpoint; Query is the raw query string
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+
+	// RequestHeaders are the request headers (credentials redacted)
+	RequestHeaders map[string]string `json:"request_headers"`
+
+	// RequestBody is the request body (see BodyEncoding)
+	RequestBody string `json:"request_body"`
+
+	// Status is the response status code
+	Status int `json:"status"`
+
+	// ResponseHeaders are the response headers
+	ResponseHeaders map[string]string `json:"response_headers"`
+
+	// ResponseBody is the complete response body, including every SSE chunk
+	ResponseBody string `json:"response_body"`
+
+	// BodyEncoding is "utf8", or "base64" when a body is not valid UTF-8
+	BodyEncoding string `json:"body_encoding"`
+
+	// Truncated is set when a body exceeded MaxBodyBytes
+	Truncated bool `json:"truncated,omitempty"`
+
+	// StartedAt is when the request arrived (Unix milliseconds, virtual clock)
+	StartedAt int64 `json:"started_at"`
+
+	// DurationMs is the time to the last response byte
+	DurationMs int64 `json:"duration_ms"`
+
+	// ProcessingMs is the openai-processing-ms returned to the client (the
+	// time to the first response byte)
+	ProcessingMs int64 `json:"processing_ms"`
+}
+
+// exchangeLog buffers exchanges for the active run.
+type exchangeLog struct {
+	// config bounds body sizes and the buffer
+	config CaptureConfig
+
+	// runID is the active run ("" = capture off)
+	runID string
+
+	// exchanges are buffered oldest first
+	exchanges []Exchange
+
+	// nextSeq is the next sequence number
+	nextSeq int64
+
+	// dropped counts exchanges evicted to stay within MaxExchanges
+	dropped int64
+
+	// mu guards the fields above
+	mu sync.Mutex
+}
+
+// newExchangeLog creates an empty log with capture off.
+func newExchangeLog(config CaptureConfig) *exchangeLog {
+	return &exchangeLog{config: config}
+}
+
+// activeRun returns the run being captured ("" if none).
+func (l *exchangeLog) activeRun() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.runID
+}
+
+// start begins capturing for a run, discarding any previous exchanges.
+func (l *exchangeLog) start(runID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.runID = runID
+	l.exchanges = nil
+	l.dropped = 0
+}
+
+// stop ends capture and discards buffered exchanges.
+func (l *exchangeLog) stop() {
+	l.start("")
+}
+
+// append buffers an exchange, evicting the oldest if the buffer is full.
+func (l *exchangeLog) append(exchange Exchange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextSeq++
+	exchange.Seq = l.nextSeq
+
+	if l.config.MaxExchanges > 0 && len(l.exchanges) >= l.config.MaxExchanges {
+		l.exchanges = l.exchanges[1:]
+		l.dropped++
+	}
+	l.exchanges = append(l.exchanges, exchange)
+}
+
+// since returns exchanges for a run with Seq greater than afterSeq.
+func (l *exchangeLog) since(runID string, afterSeq int64) ([]Exchange, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := []Exchange{}
+	for _, exchange := range l.exchanges {
+		if exchange.Seq > afterSeq && (runID == "" || exchange.RunID == runID) {
+			result = append(result, exchange)
+		}
+	}
+	return result, l.dropped
+}
+
+// captureWriter tees the response body into a bounded buffer.
+type captureWriter struct {
+	gin.ResponseWriter
+
+	// body holds up to limit bytes of the response
+	body bytes.Buffer
+
+	// limit is the maximum number of bytes kept
+	limit int64
+
+	// truncated is set once the response exceeds limit
+	truncated bool
+}
+
+// Write captures and forwards response bytes.
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString captures and forwards response bytes.
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// capture appends to the buffer up to the limit.
+func (w *captureWriter) capture(data []byte) {
+	remaining := w.limit - int64(w.body.Len())
+	if int64(len(data)) > remaining {
+		data = data[:max(remaining, 0)]
+		w.truncated = true
+	}
+	w.body.Write(data)
+}
+
+// CaptureMiddleware records complete exchanges while a run is active (or
+// when the request carries X-Sentra-Run-Id). Must run before LimitsMiddleware
+// so rejected requests are captured too; the body limit still applies because
+// the request body is restored unread past the capture limit.
+func (s *Server) CaptureMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		runID := c.GetHeader(HeaderRunID)
+		if runID == "" {
+			runID = s.exchanges.activeRun()
+		}
+		if runID == "" {
+			c.Next()
+			return
+		}
+
+		limit := s.exchanges.config.MaxBodyBytes
+		started := time.Now()
+		startedAt := clock.Now()
+
+		var requestBody []byte
+		truncated := false
+		if c.Request.Body != nil {
+			// Read one byte past the limit to detect truncation, then put
+			// everything back in front of the unread remainder
+			requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(requestBody), c.Request.Body), c.Request.Body}
+			if int64(len(requestBody)) > limit {
+				requestBody = requestBody[:limit]
+				truncated = true
+			}
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: limit}
+		c.Writer = writer
+
+		c.Next()
+
+		encoding := "utf8"
+		if !utf8.Valid(requestBody) || !utf8.Valid(writer.body.Bytes()) {
+			encoding = "base64"
+		}
+
+		processingMs, _ := strconv.ParseInt(writer.Header().Get(HeaderProcessingMs), 10, 64)
+
+		s.exchanges.append(Exchange{
+			RunID:           runID,
+			RequestID:       GetRequestID(c),
+			Method:          c.Request.Method,
+			Path:            c.Request.URL.Path,
+			Query:           c.Request.URL.RawQuery,
+			RequestHeaders:  flattenHeaders(c.Request.Header, true),
+			RequestBody:     encodeBody(requestBody, encoding),
+			Status:          writer.Status(),
+			ResponseHeaders: flattenHeaders(writer.Header(), false),
+			ResponseBody:    encodeBody(writer.body.Bytes(), encoding),
+			BodyEncoding:    encoding,
+			Truncated:       truncated || writer.truncated,
+			StartedAt:       startedAt.UnixMilli(),
+			DurationMs:      time.Since(started).Milliseconds(),
+			ProcessingMs:    processingMs,
+		})
+	}
+}
+
+// flattenHeaders joins multi-valued headers, optionally masking credentials.
+func flattenHeaders(header http.Header, redact bool) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if redact && redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = redactHeaderValue(value)
+		}
+		flat[name] = value
+	}
+	return flat
+}
+
+// redactHeaderValue keeps the scheme and a short prefix of a credential.
+func redactHeaderValue(value string) string {
+	scheme, token, found := strings.Cut(value, " ")
+	if !found {
+		scheme, token = "", value
+	} else {
+		scheme += " "
+	}
+	if len(token) > 8 {
+		token = token[:8]
+	}
+	return scheme + token + "***"
+}
+
+// encodeBody renders a body in the given encoding.
+func encodeBody(body []byte, encoding string) string {
+	if encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(body)
+	}
+	return string(body)
+}
+
+// setupCaptureRoutes registers the capture admin API.
+func (s *Server) setupCaptureRoutes(admin *gin.RouterGroup) {
+	admin.GET("/capture", s.handleGetCapture)
+	admin.PUT("/capture", s.handleStartCapture)
+	admin.DELETE("/capture", s.handleStopCapture)
+	admin.GET("/capture/exchanges", s.handleGetExchanges)
+}
+
+// startCaptureRequest is the body of PUT /_sentra/capture.
+type startCaptureRequest struct {
+	// RunID is the run subsequent requests are attributed to
+	RunID string `json:"run_id" binding:"required"`
+}
+
+// handleGetCapture reports the active run.
+func (s *Server) handleGetCapture(c *gin.Context) {
+	runID := s.exchanges.activeRun()
+	c.JSON(http.StatusOK, gin.H{
+		"active": runID != "",
+		"run_id": runID,
+	})
+}
+
+// handleStartCapture starts capturing exchanges for a run.
+func (s *Server) handleStartCapture(c *gin.Context) {
+	var req startCaptureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	s.exchanges.start(req.RunID)
+	if s.latency != nil {
+		// The latency report covers the current run
+		s.latency.ResetObservations()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active": true,
+		"run_id": req.RunID,
+	})
+}
+
+// handleStopCapture stops capturing and discards buffered exchanges.
+func (s *Server) handleStopCapture(c *gin.Context) {
+	s.exchanges.stop()
+	c.Status(http.StatusNoContent)
+}
+
+// handleGetExchanges returns buffered exchanges, optionally filtered by run
+// and to those after a sequence number (for incremental polling).
+func (s *Server) handleGetExchanges(c *gin.Context) {
+	var afterSeq int64
+	if raw := c.Query("after_seq"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 0 {
+			param := "after_seq"
+			abortWithError(c, models.NewBadRequestError("after_seq must be a non-negative integer", &param))
+			return
+		}
+		afterSeq = parsed
+	}
+
+	exchanges, dropped := s.exchanges.since(c.Query("run_id"), afterSeq)
+
+	c.JSON(http.StatusOK, gin.H{
+		"object":  "list",
+		"data":    exchanges,
+		"dropped": dropped,
+	})
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// exchangesResponse is the body of GET /_sentra/capture/exchanges.
+type exchangesResponse struct {
+	Data    []Exchange `json:"data"`
+	Dropped int64      `json:"dropped"`
+}
+
+// chatBody is a chat request the generic fixtures answer.
+const chatBody = `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`
+
+func TestCapture(t *testing.T) {
+	tests := []struct {
+		name string
+		// activeRun is the run started through the admin API ("" for none)
+		activeRun string
+		// headerRun is sent as X-Sentra-Run-Id
+		headerRun string
+		wantRun   string
+	}{
+		{name: "capture off"},
+		{name: "active run", activeRun: "run-1", wantRun: "run-1"},
+		{name: "header without active run", headerRun: "run-2", wantRun: "run-2"},
+		{name: "header overrides active run", activeRun: "run-1", headerRun: "run-2", wantRun: "run-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+			if tt.activeRun != "" {
+				expectStatus(t, serve(s, http.MethodPut, "/_sentra/capture", fmt.Sprintf(`{"run_id":%q}`, tt.activeRun), nil), http.StatusOK)
+			}
+
+			var headers map[string]string
+			if tt.headerRun != "" {
+				headers = map[string]string{HeaderRunID: tt.headerRun}
+			}
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", chatBody, headers)
+			expectStatus(t, rec, http.StatusOK)
+
+			var got exchangesResponse
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/capture/exchanges", "", nil), &got)
+			if tt.wantRun == "" {
+				if len(got.Data) != 0 {
+					t.Errorf("captured %d exchanges, want none", len(got.Data))
+				}
+				return
+			}
+			if len(got.Data) != 1 {
+				t.Fatalf("captured %d exchanges, want 1", len(got.Data))
+			}
+
+			exchange := got.Data[0]
+			if exchange.RunID != tt.wantRun {
+				t.Errorf("run_id = %q, want %q", exchange.RunID, tt.wantRun)
+			}
+			if exchange.Status != http.StatusOK || exchange.Path != "/v1/chat/completions" {
+				t.Errorf("exchange = %d %s, want 200 /v1/chat/completions", exchange.Status, exchange.Path)
+			}
+			if exchange.RequestBody != chatBody {
+				t.Errorf("request body = %q, want %q", exchange.RequestBody, chatBody)
+			}
+			if exchange.ResponseBody != rec.Body.String() {
+				t.Errorf("response body = %q, want %q", exchange.ResponseBody, rec.Body.String())
+			}
+			if got := exchange.RequestHeaders["Authorization"]; got != "Bearer sk-test***" {
+				t.Errorf("Authorization = %q, want it redacted", got)
+			}
+			if exchange.RequestID != rec.Header().Get(HeaderRequestID) {
+				t.Errorf("request_id = %q, want %q", exchange.RequestID, rec.Header().Get(HeaderRequestID))
+			}
+		})
+	}
+}
+
+func TestGetExchanges(t *testing.T) {
+	s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+	for _, run := range []string{"run-a", "run-b", "run-a"} {
+		expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, map[string]string{HeaderRunID: run}), http.StatusOK)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantSeqs   []int64
+	}{
+		{name: "all", wantStatus: http.StatusOK, wantSeqs: []int64{1, 2, 3}},
+		{name: "by run", query: "?run_id=run-a", wantStatus: http.StatusOK, wantSeqs: []int64{1, 3}},
+		{name: "after seq", query: "?after_seq=1", wantStatus: http.StatusOK, wantSeqs: []int64{2, 3}},
+		{name: "by run after seq", query: "?run_id=run-a&after_seq=1", wantStatus: http.StatusOK, wantSeqs: []int64{3}},
+		{name: "invalid after seq", query: "?after_seq=-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, "/_sentra/capture/exchanges"+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if got := errorParam(t, rec); got != "after_seq" {
+					t.Errorf("param = %q, want %q", got, "after_seq")
+				}
+				return
+			}
+
+			var got exchangesResponse
+			decodeJSON(t, rec, &got)
+			var seqs []int64
+			for _, exchange := range got.Data {
+				seqs = append(seqs, exchange.Seq)
+			}
+			if fmt.Sprint(seqs) != fmt.Sprint(tt.wantSeqs) {
+				t.Errorf("seqs = %v, want %v", seqs, tt.wantSeqs)
+			}
+		})
+	}
+}
+
+func TestCaptureLimits(t *testing.T) {
+	config := DefaultConfig()
+	config.Capture = CaptureConfig{MaxBodyBytes: 16, MaxExchanges: 2}
+	s := New(config, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/capture", `{"run_id":"run-1"}`, nil), http.StatusOK)
+
+	for i := 0; i < 3; i++ {
+		// the handler still reads the whole body past the capture limit
+		expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil), http.StatusOK)
+	}
+
+	var got exchangesResponse
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/capture/exchanges", "", nil), &got)
+	if len(got.Data) != 2 || got.Dropped != 1 {
+		t.Fatalf("captured %d exchanges and dropped %d, want 2 and 1", len(got.Data), got.Dropped)
+	}
+	if got.Data[0].Seq != 2 {
+		t.Errorf("oldest seq = %d, want 2", got.Data[0].Seq)
+	}
+	for _, exchange := range got.Data {
+		if !exchange.Truncated || exchange.RequestBody != chatBody[:16] || len(exchange.ResponseBody) != 16 {
+			t.Errorf("exchange %d: truncated = %v, bodies %q and %q, want both cut to 16 bytes",
+				exchange.Seq, exchange.Truncated, exchange.RequestBody, exchange.ResponseBody)
+		}
+	}
+
+	// stopping discards the buffer
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/capture", "", nil), http.StatusNoContent)
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/capture/exchanges", "", nil), &got)
+	if len(got.Data) != 0 {
+		t.Errorf("after stopping: %d exchanges, want none", len(got.Data))
+	}
+}
+
+func TestRedactHeaderValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Bearer sk-proj-1234567890", want: "Bearer sk-proj-***"},
+		{value: "Bearer sk-1", want: "Bearer sk-1***"},
+		{value: "sk-proj-1234567890", want: "sk-proj-***"},
+		{value: "", want: "***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := redactHeaderValue(tt.value); got != tt.want {
+				t.Errorf("redactHeaderValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinaryBodiesAreBase64(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	serve(s, http.MethodPost, "/v1/chat/completions", "\xff\xfe", map[string]string{HeaderRunID: "run-1"})
+
+	exchanges, _ := s.exchanges.since("run-1", 0)
+	if len(exchanges) != 1 {
+		t.Fatalf("captured %d exchanges, want 1", len(exchanges))
+	}
+	if exchanges[0].BodyEncoding != "base64" || strings.Contains(exchanges[0].RequestBody, "\xff") {
+		t.Errorf("encoding = %q, body %q, want base64", exchanges[0].BodyEncoding, exchanges[0].RequestBody)
+	}
+}
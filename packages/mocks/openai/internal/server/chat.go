@@ -0,0 +1,339 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements chat completions, answered from the fixture matching
+// the prompt, as JSON or as a stream of chat.completion.chunk events.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// genericChatContent answers prompts no fixture matches.
+const genericChatContent = "This is a mock response from the Sentra Lab OpenAI mock."
+
+// newChatMatcher creates the matcher for chat completions. Matched fixture
+// paths an experiment runs on serve the assigned variant; prompts no
+// pattern matches get synthesized content when synthesis is enabled.
+// Without a fixture store it matches against an empty one, so the other
+// prompts get the generic answer.
+func newChatMatcher(store *fixtures.Store, experiments *fixtures.Experiments, synthesizer *generator.Synthesizer) *fixtures.Matcher {
+	if store == nil {
+		store = fixtures.NewStore()
+	}
+
+	matcher := fixtures.NewMatcher(store, "")
+	if experiments != nil {
+		matcher.SetExperiments(experiments)
+	}
+	if synthesizer != nil {
+		matcher.SetSynthesizer(synthesizer)
+	}
+	return matcher
+}
+
+// setupChatRoutes registers the chat completions endpoint.
+func (s *Server) setupChatRoutes() {
+	s.api.POST("/chat/completions", s.handleChatCompletions)
+}
+
+// handleChatCompletions answers a chat completion request.
+func (s *Server) handleChatCompletions(c *gin.Context) {
+	req := models.AcquireChatCompletionRequest()
+	defer models.ReleaseChatCompletionRequest(req)
+	if !bindRequest(c, req) {
+		return
+	}
+
+	config, modelID, err := s.ResolveModel(c, req.Model)
+	if err == nil {
+		err = req.ValidateModel(config)
+	}
+	if err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	if !s.CheckBrownout(c, config.ID) {
+		return
+	}
+
+	// Responses report the dated snapshot of an alias
+	req.Model = modelID
+
+	if req.Stream {
+		endStream, ok := s.StartStream(c)
+		if !ok {
+			return
+		}
+		defer endStream()
+	}
+
+	// A seed pins the fixture, the latency jitter and the IDs
+	if req.Seed != nil {
+		c.Request = c.Request.WithContext(models.WithSeed(c.Request.Context(), *req.Seed))
+	}
+
+	ctx := c.Request.Context()
+	requestLatency := s.startChatLatency(c, req, config.ID)
+	if requestLatency != nil && requestLatency.WaitFirstToken(ctx) != nil {
+		return
+	}
+
+	fixture := s.chatFixture(c, req)
+
+	response, err := fixture.ChatResponse(req, models.Usage{})
+	if err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+	defer models.ReleaseChatCompletionResponse(response)
+	response.Usage = s.chatUsage(c, req, response)
+
+	if requestLatency != nil {
+		if requestLatency.WaitGeneration(ctx, response.Usage.CompletionTokens) != nil {
+			return
+		}
+		requestLatency.SetHeaders(c.Writer)
+	}
+
+	s.billChat(c, response)
+
+	if req.Stream {
+		s.streamChatCompletion(c, req, response, config.ID)
+		return
+	}
+
+	buf := models.AcquireBuffer()
+	defer models.ReleaseBuffer(buf)
+	if err := json.NewEncoder(buf).Encode(response); err != nil {
+		abortWithError(c, models.NewServerError(err.Error()))
+		return
+	}
+	c.Data(http.StatusOK, gin.MIMEJSON+"; charset=utf-8", buf.Bytes())
+}
+
+// startChatLatency starts simulating the latency of a non-streaming
+// request: the TTFT is slept before the response is generated and the
+// generation time once its output is known. Returns nil for streams, which
+// are delayed chunk by chunk (see streamChatDelays), and when latency is
+// not simulated.
+func (s *Server) startChatLatency(c *gin.Context, req *models.ChatCompletionRequest, modelID string) *latency.RequestLatency {
+	if s.latency == nil || req.Stream {
+		return nil
+	}
+
+	requestLatency, err := s.latency.StartRequest(c.Request.Context(), modelID, GetServiceTier(c, req.ServiceTier))
+	if err != nil {
+		metrics.Warn(c.Request.Context(), "failed to simulate latency", "error", err)
+		return nil
+	}
+	return requestLatency
+}
+
+// streamChatDelays returns the simulated delay before each of n stream
+// chunks: time to the first chunk, then per-token latency.
+func (s *Server) streamChatDelays(c *gin.Context, req *models.ChatCompletionRequest, modelID string, n int) []time.Duration {
+	if s.latency == nil {
+		return make([]time.Duration, n)
+	}
+
+	ctx := c.Request.Context()
+	delays, err := s.latency.SimulateStreamingForTier(ctx, modelID, n, GetServiceTier(c, req.ServiceTier))
+	if err != nil {
+		metrics.Warn(ctx, "failed to simulate latency", "error", err)
+		return make([]time.Duration, n)
+	}
+	return delays
+}
+
+// setStreamLatencyHeaders exposes a stream's simulated latency like that of
+// non-streaming requests: the first chunk's delay as the TTFT, the rest as
+// generation time. Nothing is set when latency is not simulated.
+func setStreamLatencyHeaders(c *gin.Context, delays []time.Duration) {
+	var generation time.Duration
+	for _, delay := range delays[1:] {
+		generation += delay
+	}
+	if delays[0] == 0 && generation == 0 {
+		return
+	}
+	c.Header(latency.HeaderLatencyTTFT, strconv.FormatInt(delays[0].Milliseconds(), 10))
+	c.Header(latency.HeaderLatencyGeneration, strconv.FormatInt(generation.Milliseconds(), 10))
+}
+
+// chatFixture returns the fixture answering req, or a generic answer when
+// none matches. Synthesized content follows the strategy of the request's
+// project. The X-Sentra-Experiment-Unit header (or else the seed) pins
+// experiment variants, which are reported in the response headers. With a
+// seed, identical requests get the same fixture.
+func (s *Server) chatFixture(c *gin.Context, req *models.ChatCompletionRequest) *fixtures.Fixture {
+	unit := c.GetHeader(fixtures.HeaderExperimentUnit)
+	project := GetScope(c).Project
+
+	var fixture *fixtures.Fixture
+	var assignment *fixtures.Assignment
+	var err error
+	switch {
+	case req.Seed != nil:
+		fixture, assignment, err = s.matcher.MatchSeeded(req.Messages, unit, project, *req.Seed)
+	case project != "":
+		fixture, assignment, err = s.matcher.MatchForProject(req.Messages, unit, project)
+	default:
+		fixture, assignment, err = s.matcher.MatchForUnit(req.Messages, unit)
+	}
+	if err != nil || fixture == nil {
+		return &fixtures.Fixture{ID: "generic", Content: genericChatContent, Role: "assistant"}
+	}
+
+	fixtures.SetExperimentHeaders(c.Writer, assignment)
+
+	if strategy, ok := fixture.Metadata["strategy"].(string); ok && fixture.Metadata["synthesized"] == true {
+		c.Header(headerSynthesisStrategy, strategy)
+	}
+	return fixture
+}
+
+// chatUsage counts the tokens of a chat completion: the prompt with
+// tiktoken, calibrated for the SDK that sent it, and the output the
+// response carries. Encodings that cannot be loaded fall back to the
+// estimate.
+func (s *Server) chatUsage(c *gin.Context, req *models.ChatCompletionRequest, response *models.ChatCompletionResponse) models.Usage {
+	ctx := c.Request.Context()
+	promptTokens, err := s.tokenizer.Count(ctx, req.Messages, req.Model)
+	if err != nil {
+		promptTokens = tokenizer.FastEstimateMessages(req.Messages)
+	}
+	promptTokens = s.CalibratePromptTokens(c, promptTokens, tokenizer.ShapeOf(req))
+
+	var output strings.Builder
+	for _, choice := range response.Choices {
+		output.WriteString(choice.Message.Content)
+		for _, call := range choice.Message.ToolCalls {
+			output.WriteString(call.Function.Name)
+			output.WriteString(call.Function.Arguments)
+		}
+		if call := choice.Message.FunctionCall; call != nil {
+			output.WriteString(call.Name)
+			output.WriteString(call.Arguments)
+		}
+	}
+
+	completionTokens, err := s.tokenizer.CountText(ctx, output.String(), req.Model)
+	if err != nil {
+		completionTokens = tokenizer.FastEstimate(output.String())
+	}
+
+	return models.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// billChat sets the cost headers and records the completion's cost against
+// the request's scope. Models without token pricing are not billed.
+func (s *Server) billChat(c *gin.Context, response *models.ChatCompletionResponse) {
+	if s.tracker == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	cost, err := s.tracker.Calculator().Calculate(ctx, response.Model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	if err != nil {
+		return
+	}
+	pricing.AddCostHeaders(c.Writer, cost)
+
+	if err := s.tracker.TrackScoped(ctx, GetScope(c), response.Model, cost); err != nil {
+		metrics.Warn(ctx, "failed to track chat usage", "error", err)
+	}
+}
+
+// streamChatCompletion streams response as chat.completion.chunk events:
+// the role, the content (or tool call) deltas, the finish reason and, with
+// stream_options.include_usage, a final chunk with the usage, whose earlier
+// chunks all carry "usage": null. The model's latency profile delays the
+// chunks: the TTFT before the role, per-token latency before the others.
+func (s *Server) streamChatCompletion(c *gin.Context, req *models.ChatCompletionRequest, response *models.ChatCompletionResponse, modelID string) {
+	encoder := models.NewStreamEncoder(response.ID, response.Model, response.Created, response.SystemFingerprint)
+	if req.IncludesStreamUsage() {
+		encoder.IncludeUsage()
+	}
+
+	choice := response.Choices[0]
+	var tokens []string
+	for _, token := range strings.SplitAfter(choice.Message.Content, " ") {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	// The role, the tool calls and each content token are chunks sent after
+	// their simulated delay
+	chunks := 1 + len(tokens)
+	if len(choice.Message.ToolCalls) > 0 {
+		chunks++
+	}
+	delays := s.streamChatDelays(c, req, modelID, chunks)
+	setStreamLatencyHeaders(c, delays)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	frame := make([]byte, 0, 512)
+	sent := 0
+	write := func() bool {
+		if sent < len(delays) && !waitFor(ctx, delays[sent]) {
+			return false
+		}
+		sent++
+		if _, err := c.Writer.Write(frame); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		frame = frame[:0]
+		return ctx.Err() == nil
+	}
+
+	frame = encoder.AppendRole(frame, choice.Message.Role)
+	if !write() {
+		return
+	}
+
+	if len(choice.Message.ToolCalls) > 0 {
+		frame = encoder.AppendToolCalls(frame, choice.Message.ToolCalls, 0)
+		if !write() {
+			return
+		}
+	}
+
+	for _, token := range tokens {
+		frame = encoder.AppendContent(frame, token)
+		if !write() {
+			return
+		}
+	}
+
+	frame = encoder.AppendFinish(frame, choice.FinishReason)
+	if req.IncludesStreamUsage() {
+		frame = encoder.AppendUsage(frame, response.Usage)
+	}
+	frame = append(frame, models.SSEDone...)
+	write()
+}
@@ -0,0 +1,429 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkoukk/tiktoken-go"
+
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// sseEvents splits a streamed body into the data of its events.
+func sseEvents(t *testing.T, body string) []string {
+	t.Helper()
+
+	var events []string
+	for _, frame := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		data, ok := strings.CutPrefix(frame, "data: ")
+		if !ok {
+			t.Fatalf("frame %q is not a data event", frame)
+		}
+		events = append(events, data)
+	}
+	return events
+}
+
+func TestChatCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		model   string
+		content string
+	}{
+		{
+			name:    "generic answer",
+			body:    `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello there"}]}`,
+			status:  http.StatusOK,
+			model:   "gpt-4o-mini",
+			content: genericChatContent,
+		},
+		{
+			name:   "unknown model",
+			body:   `{"model":"gpt-unknown","messages":[{"role":"user","content":"Hello"}]}`,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "no messages",
+			body:   `{"model":"gpt-4o-mini","messages":[]}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var resp models.ChatCompletionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Object != "chat.completion" || !strings.HasPrefix(resp.Model, tt.model) {
+				t.Errorf("object = %q, model = %q", resp.Object, resp.Model)
+			}
+			if got := resp.Choices[0].Message.Content; got != tt.content {
+				t.Errorf("content = %q, want %q", got, tt.content)
+			}
+			usage := resp.Usage
+			if usage.PromptTokens <= 0 || usage.CompletionTokens <= 0 || usage.TotalTokens != usage.PromptTokens+usage.CompletionTokens {
+				t.Errorf("usage = %+v", usage)
+			}
+		})
+	}
+}
+
+func TestChatCompletionTokenCache(t *testing.T) {
+	// Count with an encoding of one token per byte, so counts can be cached.
+	// tiktoken keeps it loaded; other tests do not depend on exact counts.
+	tiktoken.SetBpeLoader(byteBpeLoader{})
+	t.Cleanup(func() { tiktoken.SetBpeLoader(offlineBpeLoader{}) })
+
+	base, err := tokenizer.NewTokenizer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := tokenizer.NewCachedTokenizer(base, nil, tokenizer.DefaultCachedTokenizerConfig())
+	s := newTestServer(t, Dependencies{Tokenizer: cached})
+	body := `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello there"}]}`
+
+	var usages []models.Usage
+	for i := 0; i < 3; i++ {
+		rec := serve(s, http.MethodPost, "/v1/chat/completions", body, nil)
+		expectStatus(t, rec, http.StatusOK)
+		var resp models.ChatCompletionResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		usages = append(usages, resp.Usage)
+	}
+
+	if usages[1] != usages[0] || usages[2] != usages[0] {
+		t.Errorf("usages = %+v, want the same counts for the same request", usages)
+	}
+
+	// The first request counts the prompt and the output; the others hit the cache
+	stats, err := cached.GetStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.CacheMisses != 2 || stats.CacheHits != 4 {
+		t.Errorf("cache misses = %d, hits = %d; want 2 and 4", stats.CacheMisses, stats.CacheHits)
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		includeUsage bool
+	}{
+		{
+			name: "without usage",
+			body: `{"model":"gpt-4o-mini","stream":true,"messages":[{"role":"user","content":"Hello"}]}`,
+		},
+		{
+			name:         "usage not requested",
+			body:         `{"model":"gpt-4o-mini","stream":true,"stream_options":{"include_usage":false},"messages":[{"role":"user","content":"Hello"}]}`,
+			includeUsage: false,
+		},
+		{
+			name:         "include_usage",
+			body:         `{"model":"gpt-4o-mini","stream":true,"stream_options":{"include_usage":true},"messages":[{"role":"user","content":"Hello"}]}`,
+			includeUsage: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", tt.body, nil)
+			expectStatus(t, rec, http.StatusOK)
+			if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("Content-Type = %q", got)
+			}
+
+			events := sseEvents(t, rec.Body.String())
+			if last := events[len(events)-1]; last != "[DONE]" {
+				t.Fatalf("last event = %q, want [DONE]", last)
+			}
+			events = events[:len(events)-1]
+
+			chunks := events
+			if tt.includeUsage {
+				chunks = events[:len(events)-1]
+			}
+
+			var content strings.Builder
+			for i, data := range chunks {
+				var chunk map[string]json.RawMessage
+				if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+					t.Fatalf("chunk %d: %v", i, err)
+				}
+				usage, ok := chunk["usage"]
+				switch {
+				case tt.includeUsage && (!ok || string(usage) != "null"):
+					t.Errorf("chunk %d usage = %s, want null", i, usage)
+				case !tt.includeUsage && ok:
+					t.Errorf("chunk %d has usage %s", i, usage)
+				}
+
+				var decoded models.StreamChunk
+				if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+					t.Fatalf("chunk %d: %v", i, err)
+				}
+				content.WriteString(decoded.Choices[0].Delta.Content)
+			}
+			if content.String() != genericChatContent {
+				t.Errorf("streamed content = %q", content.String())
+			}
+
+			if !tt.includeUsage {
+				return
+			}
+			var final models.StreamChunk
+			if err := json.Unmarshal([]byte(events[len(events)-1]), &final); err != nil {
+				t.Fatalf("usage chunk: %v", err)
+			}
+			if len(final.Choices) != 0 || final.Usage == nil || final.Usage.TotalTokens != final.Usage.PromptTokens+final.Usage.CompletionTokens || final.Usage.CompletionTokens <= 0 {
+				t.Errorf("usage chunk = %s", events[len(events)-1])
+			}
+		})
+	}
+}
+
+func TestChatCompletionLatency(t *testing.T) {
+	const (
+		ttft     = 40 * time.Millisecond
+		perToken = 2 * time.Millisecond
+	)
+
+	tests := []struct {
+		name    string
+		timing  latency.NonStreamingTiming
+		enabled bool
+	}{
+		{name: "split", timing: latency.TimingSplit, enabled: true},
+		{name: "total", timing: latency.TimingTotal, enabled: true},
+		{name: "disabled", timing: latency.TimingSplit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := latency.DefaultSimulatorConfig()
+			config.Enabled = tt.enabled
+			config.EnableJitter = false
+			config.EnableLoadSimulation = false
+			config.NonStreamingTiming = tt.timing
+			simulator := latency.NewSimulator(config)
+			simulator.GetProfileRegistry().SetProfile("gpt-4o-mini", latency.Profile{
+				ModelID:         "gpt-4o-mini",
+				BaseLatency:     ttft,
+				PerTokenLatency: perToken,
+				MaxLatency:      time.Second,
+			})
+
+			s := newTestServer(t, Dependencies{Latency: simulator})
+			start := time.Now()
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`, nil)
+			elapsed := time.Since(start)
+			expectStatus(t, rec, http.StatusOK)
+
+			ttftHeader := rec.Header().Get(latency.HeaderLatencyTTFT)
+			generationHeader := rec.Header().Get(latency.HeaderLatencyGeneration)
+			if !tt.enabled {
+				if ttftHeader != "" || generationHeader != "" {
+					t.Errorf("latency headers set while disabled: %q, %q", ttftHeader, generationHeader)
+				}
+				return
+			}
+
+			var resp models.ChatCompletionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			generation := time.Duration(resp.Usage.CompletionTokens) * perToken
+
+			wantTTFT, wantGeneration := ttft, generation
+			if tt.timing == latency.TimingTotal {
+				wantTTFT, wantGeneration = 0, ttft+generation
+			}
+			if got, _ := strconv.ParseInt(ttftHeader, 10, 64); got != wantTTFT.Milliseconds() {
+				t.Errorf("%s = %q, want %d", latency.HeaderLatencyTTFT, ttftHeader, wantTTFT.Milliseconds())
+			}
+			if got, _ := strconv.ParseInt(generationHeader, 10, 64); got != wantGeneration.Milliseconds() {
+				t.Errorf("%s = %q, want %d", latency.HeaderLatencyGeneration, generationHeader, wantGeneration.Milliseconds())
+			}
+			if elapsed < ttft+generation {
+				t.Errorf("request took %s, want at least %s", elapsed, ttft+generation)
+			}
+		})
+	}
+}
+
+func TestChatCompletionStreamLatency(t *testing.T) {
+	const (
+		ttft     = 40 * time.Millisecond
+		perToken = 5 * time.Millisecond
+	)
+
+	config := latency.DefaultSimulatorConfig()
+	config.EnableJitter = false
+	config.EnableLoadSimulation = false
+	simulator := latency.NewSimulator(config)
+	simulator.GetProfileRegistry().SetProfile("gpt-4o-mini", latency.Profile{
+		ModelID:         "gpt-4o-mini",
+		BaseLatency:     ttft,
+		PerTokenLatency: perToken,
+		MaxLatency:      time.Second,
+	})
+
+	s := newTestServer(t, Dependencies{Latency: simulator})
+	start := time.Now()
+	rec := serve(s, http.MethodPost, "/v1/chat/completions", `{"model":"gpt-4o-mini","stream":true,"messages":[{"role":"user","content":"Hello"}]}`, nil)
+	elapsed := time.Since(start)
+	expectStatus(t, rec, http.StatusOK)
+
+	// The role chunk waits for the TTFT, each content chunk for one token
+	contentChunks := len(strings.Fields(genericChatContent))
+	generation := time.Duration(contentChunks) * perToken
+	if got := rec.Header().Get(latency.HeaderLatencyTTFT); got != strconv.FormatInt(ttft.Milliseconds(), 10) {
+		t.Errorf("%s = %q, want %d", latency.HeaderLatencyTTFT, got, ttft.Milliseconds())
+	}
+	if got := rec.Header().Get(latency.HeaderLatencyGeneration); got != strconv.FormatInt(generation.Milliseconds(), 10) {
+		t.Errorf("%s = %q, want %d", latency.HeaderLatencyGeneration, got, generation.Milliseconds())
+	}
+	if elapsed < ttft+generation {
+		t.Errorf("stream took %s, want at least %s", elapsed, ttft+generation)
+	}
+}
+
+// newGenericFixtures returns a store whose generic chat fixtures are n
+// equally weighted answers.
+func newGenericFixtures(t *testing.T, n int) *fixtures.Store {
+	t.Helper()
+
+	file := fixtures.FixtureFile{Category: "chat"}
+	for i := 0; i < n; i++ {
+		file.Responses = append(file.Responses, fixtures.Fixture{
+			ID:      fmt.Sprintf("generic-%d", i),
+			Content: fmt.Sprintf("Generic answer number %d.", i),
+		})
+	}
+
+	store := fixtures.NewStore()
+	if err := store.Add("responses/chat/generic.yaml", file); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestChatCompletionSeed(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "non-streaming",
+			body: `{"model":"gpt-4o-mini","seed":42,"messages":[{"role":"user","content":"Hello"}]}`,
+		},
+		{
+			name: "streaming",
+			body: `{"model":"gpt-4o-mini","seed":42,"stream":true,"stream_options":{"include_usage":true},"messages":[{"role":"user","content":"Hello"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := latency.DefaultSimulatorConfig()
+			config.EnableLoadSimulation = false
+			simulator := latency.NewSimulator(config)
+			simulator.GetProfileRegistry().SetProfile("gpt-4o-mini", latency.Profile{
+				ModelID:         "gpt-4o-mini",
+				BaseLatency:     10 * time.Millisecond,
+				PerTokenLatency: time.Millisecond,
+				JitterPercent:   0.5,
+				MaxLatency:      time.Second,
+			})
+
+			s := newTestServer(t, Dependencies{Latency: simulator, Fixtures: newGenericFixtures(t, 20)})
+
+			// created follows the clock: retry if the requests straddle a
+			// second boundary
+			var first, second *httptest.ResponseRecorder
+			for i := 0; i < 3; i++ {
+				first = serve(s, http.MethodPost, "/v1/chat/completions", tt.body, nil)
+				second = serve(s, http.MethodPost, "/v1/chat/completions", tt.body, nil)
+				if createdOf(first) == createdOf(second) {
+					break
+				}
+			}
+			expectStatus(t, first, http.StatusOK)
+			expectStatus(t, second, http.StatusOK)
+
+			if !bytes.Equal(first.Body.Bytes(), second.Body.Bytes()) {
+				t.Errorf("bodies differ:\n%s\n%s", first.Body, second.Body)
+			}
+			for _, header := range []string{latency.HeaderLatencyTTFT, latency.HeaderLatencyGeneration} {
+				if a, b := first.Header().Get(header), second.Header().Get(header); a != b {
+					t.Errorf("%s = %q and %q", header, a, b)
+				}
+			}
+
+			other := serve(s, http.MethodPost, "/v1/chat/completions", strings.Replace(tt.body, `"seed":42`, `"seed":7`, 1), nil)
+			expectStatus(t, other, http.StatusOK)
+			if bytes.Equal(first.Body.Bytes(), other.Body.Bytes()) {
+				t.Error("a different seed gave the same response")
+			}
+		})
+	}
+}
+
+// createdOf extracts the first created timestamp of a response body.
+func createdOf(rec *httptest.ResponseRecorder) string {
+	_, rest, _ := strings.Cut(rec.Body.String(), `"created":`)
+	created, _, _ := strings.Cut(rest, ",")
+	return created
+}
+
+// BenchmarkChatCompletion measures the processing overhead of a chat
+// completion with latency simulation disabled.
+func BenchmarkChatCompletion(b *testing.B) {
+	s := New(DefaultConfig(), Dependencies{})
+	const body = `{"model":"gpt-4o","messages":[{"role":"system","content":"You are a helpful assistant."},{"role":"user","content":"Hello"}]}`
+
+	for _, stream := range []bool{false, true} {
+		name := "json"
+		requestBody := body
+		if stream {
+			name = "stream"
+			requestBody = strings.Replace(body, "{", `{"stream":true,`, 1)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				rec := serve(s, http.MethodPost, "/v1/chat/completions", requestBody, nil)
+				if rec.Code != http.StatusOK {
+					b.Fatalf("status = %d (body: %s)", rec.Code, rec.Body.String())
+				}
+			}
+		})
+	}
+}
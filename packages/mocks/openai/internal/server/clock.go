@@ -0,0 +1,89 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the virtual clock admin API used by the advance_clock
+// scenario step.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/locale"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// setupClockRoutes registers the virtual clock admin API.
+func (s *Server) setupClockRoutes(admin *gin.RouterGroup) {
+	admin.GET("/clock", handleGetClock)
+	admin.POST("/clock/advance", handleAdvanceClock)
+	admin.DELETE("/clock", handleResetClock)
+}
+
+// advanceClockRequest is the body of POST /_sentra/clock/advance.
+type advanceClockRequest struct {
+	// Duration is a Go duration string (e.g., "25h", "90m")
+	Duration string `json:"duration" binding:"required"`
+}
+
+// clockResponse describes the virtual clock.
+type clockResponse struct {
+	// Now is the virtual time (Unix seconds)
+	Now int64 `json:"now"`
+
+	// OffsetMs is how far the virtual clock runs ahead of real time
+	OffsetMs int64 `json:"offset_ms"`
+
+	// LocalTime is the virtual time in the simulated timezone (RFC 3339)
+	LocalTime string `json:"local_time"`
+
+	// Timezone is the simulated timezone
+	Timezone string `json:"timezone"`
+}
+
+// newClockResponse returns the current virtual clock state.
+func newClockResponse() clockResponse {
+	now := clock.Now()
+	settings := locale.Current()
+	return clockResponse{
+		Now:       now.Unix(),
+		OffsetMs:  clock.Offset().Milliseconds(),
+		LocalTime: now.In(settings.Location()).Format(time.RFC3339),
+		Timezone:  settings.Timezone,
+	}
+}
+
+// handleGetClock returns the virtual clock state.
+func handleGetClock(c *gin.Context) {
+	c.JSON(http.StatusOK, newClockResponse())
+}
+
+// handleAdvanceClock moves the virtual clock forward.
+func handleAdvanceClock(c *gin.Context) {
+	var req advanceClockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	param := "duration"
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Invalid duration: %v", err), &param))
+		return
+	}
+	if err := clock.Advance(d); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), &param))
+		return
+	}
+
+	c.JSON(http.StatusOK, newClockResponse())
+}
+
+// handleResetClock returns the virtual clock to real time.
+func handleResetClock(c *gin.Context) {
+	clock.Reset()
+	c.JSON(http.StatusOK, newClockResponse())
+}
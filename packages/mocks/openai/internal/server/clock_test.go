@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+)
+
+func TestAdvanceClock(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		status     int
+		wantOffset time.Duration
+		wantParam  string
+	}{
+		{name: "hours", body: `{"duration":"25h"}`, status: http.StatusOK, wantOffset: 25 * time.Hour},
+		{name: "minutes", body: `{"duration":"90m"}`, status: http.StatusOK, wantOffset: 90 * time.Minute},
+		{name: "negative", body: `{"duration":"-1h"}`, status: http.StatusBadRequest, wantParam: "duration"},
+		{name: "not a duration", body: `{"duration":"tomorrow"}`, status: http.StatusBadRequest, wantParam: "duration"},
+		{name: "missing duration", body: `{}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(clock.Reset)
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodPost, "/_sentra/clock/advance", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				if got := clock.Offset(); got != 0 {
+					t.Errorf("clock offset = %v after a rejected advance, want 0", got)
+				}
+				return
+			}
+
+			var resp clockResponse
+			decodeJSON(t, rec, &resp)
+			if resp.OffsetMs != tt.wantOffset.Milliseconds() {
+				t.Errorf("offset_ms = %d, want %d", resp.OffsetMs, tt.wantOffset.Milliseconds())
+			}
+			if want := time.Now().Add(tt.wantOffset).Unix(); resp.Now < want-1 || resp.Now > want+1 {
+				t.Errorf("now = %d, want about %d", resp.Now, want)
+			}
+		})
+	}
+}
+
+func TestResetClock(t *testing.T) {
+	t.Cleanup(clock.Reset)
+	s := newTestServer(t, Dependencies{})
+
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/clock/advance", `{"duration":"1h"}`, nil), http.StatusOK)
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/clock/advance", `{"duration":"30m"}`, nil), http.StatusOK)
+
+	rec := serve(s, http.MethodGet, "/_sentra/clock", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var resp clockResponse
+	decodeJSON(t, rec, &resp)
+	if want := (90 * time.Minute).Milliseconds(); resp.OffsetMs != want {
+		t.Errorf("offset_ms = %d after two advances, want %d", resp.OffsetMs, want)
+	}
+
+	rec = serve(s, http.MethodDelete, "/_sentra/clock", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	decodeJSON(t, rec, &resp)
+	if resp.OffsetMs != 0 || clock.Offset() != 0 {
+		t.Errorf("offset_ms = %d, clock offset = %v after reset, want 0", resp.OffsetMs, clock.Offset())
+	}
+}
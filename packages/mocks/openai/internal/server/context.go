@@ -0,0 +1,87 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file defines request-scoped values stored on the request context.
+package server
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// contextKey is the type for context keys defined in this package.
+type contextKey string
+
+const (
+	// scopeContextKey stores the request's organization/project scope
+	scopeContextKey contextKey = "request_scope"
+
+	// serviceTierContextKey stores the request's resolved service tier
+	serviceTierContextKey contextKey = "service_tier"
+
+	// sdkContextKey stores the fingerprint of the SDK that sent the request
+	sdkContextKey contextKey = "sdk"
+)
+
+// WithScope returns a copy of ctx carrying the request scope.
+func WithScope(ctx context.Context, scope models.RequestScope) context.Context {
+	return context.WithValue(ctx, scopeContextKey, scope)
+}
+
+// ScopeFromContext retrieves the request scope from ctx.
+// Returns an empty scope if none was set.
+func ScopeFromContext(ctx context.Context) models.RequestScope {
+	if scope, ok := ctx.Value(scopeContextKey).(models.RequestScope); ok {
+		return scope
+	}
+	return models.RequestScope{}
+}
+
+// GetScope retrieves the request scope for a gin request.
+func GetScope(c *gin.Context) models.RequestScope {
+	return ScopeFromContext(c.Request.Context())
+}
+
+// WithServiceTier returns a copy of ctx carrying the service tier.
+func WithServiceTier(ctx context.Context, tier models.ServiceTier) context.Context {
+	return context.WithValue(ctx, serviceTierContextKey, tier)
+}
+
+// ServiceTierFromContext retrieves the service tier from ctx.
+// Returns ServiceTierDefault if none was set.
+func ServiceTierFromContext(ctx context.Context) models.ServiceTier {
+	if tier, ok := ctx.Value(serviceTierContextKey).(models.ServiceTier); ok {
+		return tier
+	}
+	return models.ServiceTierDefault
+}
+
+// GetServiceTier resolves the service tier for a gin request. A service_tier
+// from the request body (other than "auto") takes precedence over the tier
+// assigned by ServiceTierMiddleware.
+func GetServiceTier(c *gin.Context, requested string) models.ServiceTier {
+	if tier, err := models.ParseServiceTier(requested); err == nil && tier != models.ServiceTierAuto {
+		return tier
+	}
+	return ServiceTierFromContext(c.Request.Context())
+}
+
+// WithSDK returns a copy of ctx carrying the SDK fingerprint.
+func WithSDK(ctx context.Context, sdk models.SDKFingerprint) context.Context {
+	return context.WithValue(ctx, sdkContextKey, sdk)
+}
+
+// SDKFromContext retrieves the SDK fingerprint from ctx.
+// Returns an unknown SDK if none was set.
+func SDKFromContext(ctx context.Context) models.SDKFingerprint {
+	if sdk, ok := ctx.Value(sdkContextKey).(models.SDKFingerprint); ok {
+		return sdk
+	}
+	return models.SDKFingerprint{Name: models.SDKUnknown}
+}
+
+// GetSDK retrieves the SDK fingerprint for a gin request.
+func GetSDK(c *gin.Context) models.SDKFingerprint {
+	return SDKFromContext(c.Request.Context())
+}
@@ -0,0 +1,144 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the context utilization admin API, which counts the
+// prompt tokens of a run's captured chat requests with the tokenizer and
+// reports how much of the model's context window each one used, so the
+// calls that will start failing as prompts grow in production stand out.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// defaultContextWarning is the share of the context window at which a
+// request is flagged when warn_at is not given
+const defaultContextWarning = 0.8
+
+// contextUtilization is one captured request's context window usage.
+type contextUtilization struct {
+	// Seq and RequestID identify the captured exchange
+	Seq       int64  `json:"seq"`
+	RunID     string `json:"run_id"`
+	RequestID string `json:"request_id"`
+	Path      string `json:"path"`
+	Model     string `json:"model"`
+
+	// PromptTokens is counted with the tokenizer; CompletionTokens is taken
+	// from the response's usage (0 when it has none, e.g. streams)
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+
+	// ContextTokens is PromptTokens plus CompletionTokens
+	ContextTokens int `json:"context_tokens"`
+
+	// ContextWindow is the model's context window, and Utilization
+	// ContextTokens as a share of it
+	ContextWindow int     `json:"context_window"`
+	Utilization   float64 `json:"utilization"`
+
+	// NearLimit is set when Utilization reaches warn_at
+	NearLimit bool `json:"near_limit"`
+}
+
+// newTokenizer returns counter, or an uncached tokenizer if it is nil.
+// Encodings are loaded on first use.
+func newTokenizer(counter tokenizer.Counter) tokenizer.Counter {
+	if counter != nil {
+		return counter
+	}
+	t, _ := tokenizer.NewTokenizer()
+	return t
+}
+
+// setupContextRoutes registers the context utilization admin API.
+func (s *Server) setupContextRoutes(admin *gin.RouterGroup) {
+	admin.GET("/context", s.handleGetContextUtilization)
+}
+
+// handleGetContextUtilization reports the context window utilization of the
+// captured chat requests, filtered by run_id, flagging those at or above
+// warn_at (a share of the window; default 0.8).
+func (s *Server) handleGetContextUtilization(c *gin.Context) {
+	warnAt := defaultContextWarning
+	if raw := c.Query("warn_at"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			param := "warn_at"
+			abortWithError(c, models.NewBadRequestError("warn_at must be a number in (0, 1]", &param))
+			return
+		}
+		warnAt = parsed
+	}
+
+	exchanges, _ := s.exchanges.since(c.Query("run_id"), 0)
+
+	data := []contextUtilization{}
+	nearLimit := 0
+	for _, exchange := range exchanges {
+		usage, ok := s.contextUtilization(c, exchange)
+		if !ok {
+			continue
+		}
+		usage.NearLimit = usage.Utilization >= warnAt
+		if usage.NearLimit {
+			nearLimit++
+		}
+		data = append(data, usage)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object":     "list",
+		"data":       data,
+		"warn_at":    warnAt,
+		"near_limit": nearLimit,
+	})
+}
+
+// contextUtilization counts a captured chat request's context usage.
+// Returns false for exchanges that are not chat requests to a model with
+// a known context window.
+func (s *Server) contextUtilization(c *gin.Context, exchange Exchange) (contextUtilization, bool) {
+	var req models.ChatCompletionRequest
+	if json.Unmarshal(decodeBody(exchange.RequestBody, exchange.BodyEncoding), &req) != nil || req.Model == "" || len(req.Messages) == 0 {
+		return contextUtilization{}, false
+	}
+
+	config, err := models.GetModelConfig(req.Model)
+	if err != nil || config.ContextWindow <= 0 {
+		return contextUtilization{}, false
+	}
+
+	promptTokens, err := s.tokenizer.Count(c.Request.Context(), req.Messages, req.Model)
+	if err != nil {
+		// Encodings that cannot be loaded fall back to the estimate
+		promptTokens = tokenizer.FastEstimateMessages(req.Messages)
+	}
+
+	var resp struct {
+		Usage *models.Usage `json:"usage"`
+	}
+	completionTokens := 0
+	if json.Unmarshal(decodeBody(exchange.ResponseBody, exchange.BodyEncoding), &resp) == nil && resp.Usage != nil {
+		completionTokens = resp.Usage.CompletionTokens
+	}
+
+	usage := contextUtilization{
+		Seq:              exchange.Seq,
+		RunID:            exchange.RunID,
+		RequestID:        exchange.RequestID,
+		Path:             exchange.Path,
+		Model:            req.Model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		ContextTokens:    promptTokens + completionTokens,
+		ContextWindow:    config.ContextWindow,
+	}
+	usage.Utilization = float64(usage.ContextTokens) / float64(usage.ContextWindow)
+	return usage, true
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestContextUtilization(t *testing.T) {
+	long := strings.Repeat("lorem ipsum dolor sit amet ", 2000)
+
+	tests := []struct {
+		name string
+		// bodies are the chat requests captured for run-1
+		bodies        []string
+		query         string
+		wantStatus    int
+		wantCount     int
+		wantNearLimit int
+	}{
+		{name: "no requests", wantStatus: http.StatusOK},
+		{
+			name:       "short prompt",
+			bodies:     []string{chatBody},
+			wantStatus: http.StatusOK,
+			wantCount:  1,
+		},
+		{
+			name:          "long prompt near a low threshold",
+			bodies:        []string{chatBody, fmt.Sprintf(`{"model":"gpt-4o-mini","messages":[{"role":"user","content":%q}]}`, long)},
+			query:         "?warn_at=0.05",
+			wantStatus:    http.StatusOK,
+			wantCount:     2,
+			wantNearLimit: 1,
+		},
+		{
+			name:       "other runs filtered out",
+			bodies:     []string{chatBody},
+			query:      "?run_id=run-2",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "requests without messages skipped",
+			bodies:     []string{`{"model":"gpt-4o-mini","messages":[]}`},
+			wantStatus: http.StatusOK,
+		},
+		{name: "warn_at out of range", query: "?warn_at=0", wantStatus: http.StatusBadRequest},
+		{name: "warn_at not a number", query: "?warn_at=high", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+			for _, body := range tt.bodies {
+				serve(s, http.MethodPost, "/v1/chat/completions", body, map[string]string{HeaderRunID: "run-1"})
+			}
+
+			rec := serve(s, http.MethodGet, "/_sentra/context"+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if got := errorParam(t, rec); got != "warn_at" {
+					t.Errorf("param = %q, want %q", got, "warn_at")
+				}
+				return
+			}
+
+			var got struct {
+				Data      []contextUtilization `json:"data"`
+				NearLimit int                  `json:"near_limit"`
+			}
+			decodeJSON(t, rec, &got)
+			if len(got.Data) != tt.wantCount {
+				t.Fatalf("reported %d requests, want %d", len(got.Data), tt.wantCount)
+			}
+			if got.NearLimit != tt.wantNearLimit {
+				t.Errorf("near_limit = %d, want %d", got.NearLimit, tt.wantNearLimit)
+			}
+			for _, usage := range got.Data {
+				if usage.PromptTokens <= 0 || usage.ContextWindow <= 0 {
+					t.Errorf("seq %d: %d prompt tokens of %d, want both positive", usage.Seq, usage.PromptTokens, usage.ContextWindow)
+				}
+				if usage.ContextTokens != usage.PromptTokens+usage.CompletionTokens {
+					t.Errorf("seq %d: context_tokens = %d, want %d", usage.Seq, usage.ContextTokens, usage.PromptTokens+usage.CompletionTokens)
+				}
+				if want := float64(usage.ContextTokens) / float64(usage.ContextWindow); usage.Utilization != want {
+					t.Errorf("seq %d: utilization = %v, want %v", usage.Seq, usage.Utilization, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the run cost admin API, which reports tracked usage
+// by run so the CLI can export costs tagged with scenarios, and the totals
+// of an organization, project or API key.
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+)
+
+// setupCostRoutes registers the run cost admin API.
+func (s *Server) setupCostRoutes(admin *gin.RouterGroup) {
+	if s.tracker == nil {
+		return
+	}
+
+	admin.GET("/costs", s.handleGetRunCosts)
+	admin.GET("/costs/scope", s.handleGetScopeCosts)
+}
+
+// runCostEntry is a day's usage for one scope, run and model.
+type runCostEntry struct {
+	Day               string  `json:"day"`
+	StartTime         int64   `json:"start_time"`
+	EndTime           int64   `json:"end_time"`
+	RunID             string  `json:"run_id,omitempty"`
+	APIKeyID          string  `json:"api_key_id"`
+	Organization      string  `json:"organization,omitempty"`
+	Project           string  `json:"project,omitempty"`
+	Model             string  `json:"model"`
+	Requests          int64   `json:"requests"`
+	InputTokens       int64   `json:"input_tokens"`
+	CachedInputTokens int64   `json:"cached_input_tokens"`
+	OutputTokens      int64   `json:"output_tokens"`
+	InputCost         float64 `json:"input_cost"`
+	CachedInputCost   float64 `json:"cached_input_cost"`
+	OutputCost        float64 `json:"output_cost"`
+	TotalCost         float64 `json:"total_cost"`
+}
+
+// handleGetRunCosts returns daily usage and costs by run, filtered by
+// run_id, model (both repeatable) and start_time/end_time (Unix seconds;
+// default: everything retained).
+func (s *Server) handleGetRunCosts(c *gin.Context) {
+	query := pricing.DailyUsageQuery{
+		RunIDs: queryValues(c, "run_id"),
+		Models: queryValues(c, "model"),
+	}
+
+	for name, target := range map[string]*time.Time{"start_time": &query.Start, "end_time": &query.End} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			param := name
+			abortWithError(c, models.NewBadRequestError("'"+name+"' must be a Unix timestamp in seconds", &param))
+			return
+		}
+		*target = time.Unix(seconds, 0)
+	}
+
+	usage := s.tracker.GetDailyUsage(c.Request.Context(), query)
+
+	data := make([]runCostEntry, 0, len(usage))
+	for _, u := range usage {
+		data = append(data, runCostEntry{
+			Day:               u.Day.Format("2006-01-02"),
+			StartTime:         u.Day.Unix(),
+			EndTime:           u.Day.Add(bucketWidth).Unix(),
+			RunID:             u.RunID,
+			APIKeyID:          pricing.APIKeyID(u.APIKey),
+			Organization:      u.Organization,
+			Project:           u.Project,
+			Model:             u.Model,
+			Requests:          u.Requests,
+			InputTokens:       u.InputTokens,
+			CachedInputTokens: u.CachedInputTokens,
+			OutputTokens:      u.OutputTokens,
+			InputCost:         u.InputCost,
+			CachedInputCost:   u.CachedInputCost,
+			OutputCost:        u.OutputCost,
+			TotalCost:         u.TotalCost,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   data,
+	})
+}
+
+// scopeCostEntry is the total usage of an organization, project or API key.
+type scopeCostEntry struct {
+	APIKeyID     string                    `json:"api_key_id,omitempty"`
+	Organization string                    `json:"organization,omitempty"`
+	Project      string                    `json:"project,omitempty"`
+	Requests     int64                     `json:"requests"`
+	InputTokens  int64                     `json:"input_tokens"`
+	OutputTokens int64                     `json:"output_tokens"`
+	TotalCost    float64                   `json:"total_cost"`
+	FirstRequest int64                     `json:"first_request"`
+	LastRequest  int64                     `json:"last_request"`
+	Models       map[string]scopeModelCost `json:"models"`
+}
+
+// scopeModelCost is the part of a scope's usage spent on one model.
+type scopeModelCost struct {
+	Requests     int64   `json:"requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalCost    float64 `json:"total_cost"`
+}
+
+// handleGetScopeCosts returns the total usage of a scope. With api_key, the
+// usage of that key, narrowed to the organization and project parameters
+// it was sent with; otherwise the usage of all keys in the project or, without
+// one, the organization.
+func (s *Server) handleGetScopeCosts(c *gin.Context) {
+	scope, err := models.NewRequestScope(c.Query("api_key"), c.Query("organization"), c.Query("project"))
+	if err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var usage *pricing.UserUsage
+	switch {
+	case scope.APIKey != "":
+		usage, err = s.tracker.GetScopedUsage(ctx, scope)
+	case scope.HasProject():
+		usage, err = s.tracker.GetProjectUsage(ctx, scope.Project)
+	case scope.HasOrganization():
+		usage, err = s.tracker.GetOrganizationUsage(ctx, scope.Organization)
+	default:
+		abortWithError(c, models.NewBadRequestError("one of 'api_key', 'organization' or 'project' is required", nil))
+		return
+	}
+	if err != nil {
+		abortWithError(c, models.NewNotFoundError(err.Error()))
+		return
+	}
+
+	entry := scopeCostEntry{
+		Organization: usage.Organization,
+		Project:      usage.Project,
+		Requests:     usage.TotalRequests,
+		InputTokens:  usage.TotalInputTokens,
+		OutputTokens: usage.TotalOutputTokens,
+		TotalCost:    usage.TotalCost,
+		FirstRequest: usage.FirstRequest.Unix(),
+		LastRequest:  usage.LastRequest.Unix(),
+		Models:       make(map[string]scopeModelCost, len(usage.ModelBreakdown)),
+	}
+	if usage.APIKey != "" {
+		entry.APIKeyID = pricing.APIKeyID(usage.APIKey)
+	}
+	for model, m := range usage.ModelBreakdown {
+		entry.Models[model] = scopeModelCost{
+			Requests:     m.TotalRequests,
+			InputTokens:  m.TotalInputTokens,
+			OutputTokens: m.TotalOutputTokens,
+			TotalCost:    m.TotalCost,
+		}
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
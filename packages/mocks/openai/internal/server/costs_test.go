@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// newTrackedServer creates a server tracking usage, with three chat
+// requests already tracked:
+//
+//	run-a: sk-alpha in org-acme/proj_web, gpt-4o-mini and gpt-4o
+//	run-b: sk-beta without organization or project, gpt-4o-mini
+func newTrackedServer(t *testing.T) *Server {
+	t.Helper()
+	tracker := pricing.NewTracker(pricing.NewCalculator(pricing.NewPricingDB()), store.NewMemoryStore())
+	s := newTestServer(t, Dependencies{Tracker: tracker, Fixtures: newGenericFixtures(t, 5)})
+
+	alpha := map[string]string{
+		"Authorization":           "Bearer sk-alpha",
+		models.HeaderOrganization: "org-acme",
+		models.HeaderProject:      "proj_web",
+		HeaderRunID:               "run-a",
+	}
+	beta := map[string]string{
+		"Authorization": "Bearer sk-beta",
+		HeaderRunID:     "run-b",
+	}
+	for _, request := range []struct {
+		model   string
+		headers map[string]string
+	}{
+		{"gpt-4o-mini", alpha},
+		{"gpt-4o", alpha},
+		{"gpt-4o-mini", beta},
+	} {
+		body := fmt.Sprintf(`{"model":%q,"messages":[{"role":"user","content":"Hello"}]}`, request.model)
+		expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", body, request.headers), http.StatusOK)
+	}
+	return s
+}
+
+func TestRunCosts(t *testing.T) {
+	s := newTrackedServer(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantParam  string
+		// want are the run/model pairs reported
+		want map[string]bool
+	}{
+		{
+			name:       "everything",
+			wantStatus: http.StatusOK,
+			want:       map[string]bool{"run-a/gpt-4o-mini-2024-07-18": true, "run-a/gpt-4o-2024-08-06": true, "run-b/gpt-4o-mini-2024-07-18": true},
+		},
+		{
+			name:       "one run",
+			query:      "?run_id=run-b",
+			wantStatus: http.StatusOK,
+			want:       map[string]bool{"run-b/gpt-4o-mini-2024-07-18": true},
+		},
+		{
+			name:       "runs in brackets",
+			query:      "?run_id[]=run-a&run_id[]=run-b&model=gpt-4o-2024-08-06",
+			wantStatus: http.StatusOK,
+			want:       map[string]bool{"run-a/gpt-4o-2024-08-06": true},
+		},
+		{name: "unknown run", query: "?run_id=run-c", wantStatus: http.StatusOK, want: map[string]bool{}},
+		{name: "window before the requests", query: "?start_time=0&end_time=86400", wantStatus: http.StatusOK, want: map[string]bool{}},
+		{name: "invalid start", query: "?start_time=yesterday", wantStatus: http.StatusBadRequest, wantParam: "start_time"},
+		{name: "invalid end", query: "?end_time=1.5", wantStatus: http.StatusBadRequest, wantParam: "end_time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, "/_sentra/costs"+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var got struct {
+				Data []runCostEntry `json:"data"`
+			}
+			decodeJSON(t, rec, &got)
+			if len(got.Data) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d: %+v", len(got.Data), len(tt.want), got.Data)
+			}
+			for _, entry := range got.Data {
+				if !tt.want[entry.RunID+"/"+entry.Model] {
+					t.Errorf("unexpected entry %s/%s", entry.RunID, entry.Model)
+				}
+				if entry.Requests != 1 || entry.TotalCost <= 0 {
+					t.Errorf("%s/%s: %d requests costing %v, want 1 with a cost", entry.RunID, entry.Model, entry.Requests, entry.TotalCost)
+				}
+				if entry.EndTime-entry.StartTime != 86400 {
+					t.Errorf("%s/%s: bucket %d to %d, want one day", entry.RunID, entry.Model, entry.StartTime, entry.EndTime)
+				}
+			}
+		})
+	}
+}
+
+func TestCostRoutesNeedTracker(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodGet, "/_sentra/costs", "", nil), http.StatusNotFound)
+}
@@ -0,0 +1,209 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements test run deadlines: the runner tells the mock when
+// the running scenario (or the whole run) times out, and requests still in
+// flight then are cancelled, cutting simulated latency and queueing short so
+// a hung agent is not kept waiting on the mock.
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// errDeadlineExpired is the cause of requests cancelled by the deadline.
+var errDeadlineExpired = errors.New("test run deadline expired")
+
+// runDeadline is the deadline the runner set, if any.
+type runDeadline struct {
+	mu sync.Mutex
+
+	// at is the deadline (zero: none); level is the timeout it belongs to
+	// ("step", "scenario" or "run")
+	at    time.Time
+	level string
+
+	// expired is closed once the deadline passes. It is replaced when a new
+	// deadline is set after it closed.
+	expired chan struct{}
+	closed  bool
+
+	// timer closes expired; generation invalidates timers of replaced
+	// deadlines that already fired
+	timer      *time.Timer
+	generation int
+}
+
+// newRunDeadline creates a deadline that is not set.
+func newRunDeadline() *runDeadline {
+	return &runDeadline{expired: make(chan struct{})}
+}
+
+// set sets the deadline. A deadline in the past cancels requests in flight
+// immediately.
+func (d *runDeadline) set(at time.Time, level string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stop()
+	d.at, d.level = at, level
+
+	wait := time.Until(at)
+	if wait <= 0 {
+		d.expire()
+		return
+	}
+
+	generation := d.generation
+	d.timer = time.AfterFunc(wait, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.generation == generation {
+			d.expire()
+		}
+	})
+}
+
+// clear removes the deadline.
+func (d *runDeadline) clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stop()
+	d.at, d.level = time.Time{}, ""
+}
+
+// stop discards the pending timer and readies a fresh expired channel if
+// the last one closed. Must be called with mu held.
+func (d *runDeadline) stop() {
+	d.generation++
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.closed {
+		d.expired = make(chan struct{})
+		d.closed = false
+	}
+}
+
+// expire cancels requests in flight. Must be called with mu held.
+func (d *runDeadline) expire() {
+	if !d.closed {
+		close(d.expired)
+		d.closed = true
+	}
+}
+
+// watch returns the channel closed when the deadline passes.
+func (d *runDeadline) watch() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.expired
+}
+
+// snapshot returns the deadline, its level and whether it passed.
+func (d *runDeadline) snapshot() (time.Time, string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.at, d.level, d.closed
+}
+
+// DeadlineMiddleware cancels requests when the test run's deadline passes.
+// Simulated latency and queueing stop waiting, and requests that have not
+// responded yet are aborted with a 504 timeout error naming the timeout
+// that fired.
+func (s *Server) DeadlineMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expired := s.deadline.watch()
+
+		ctx, cancel := context.WithCancelCause(c.Request.Context())
+		defer cancel(nil)
+		c.Request = c.Request.WithContext(ctx)
+
+		go func() {
+			select {
+			case <-expired:
+				cancel(errDeadlineExpired)
+			case <-ctx.Done():
+			}
+		}()
+
+		c.Next()
+
+		if errors.Is(context.Cause(ctx), errDeadlineExpired) && !c.Writer.Written() {
+			_, level, _ := s.deadline.snapshot()
+			abortWithError(c, models.NewDeadlineExceededError(level))
+		}
+	}
+}
+
+// setDeadlineRequest is the body of PUT /_sentra/deadline.
+type setDeadlineRequest struct {
+	// DeadlineMs is the deadline (Unix milliseconds)
+	DeadlineMs int64 `json:"deadline_ms" binding:"required"`
+
+	// Level is the timeout the deadline belongs to (default "scenario")
+	Level string `json:"level"`
+}
+
+// deadlineResponse describes the deadline.
+type deadlineResponse struct {
+	// DeadlineMs is the deadline (Unix milliseconds, 0 if none)
+	DeadlineMs int64 `json:"deadline_ms"`
+
+	// Level is the timeout the deadline belongs to
+	Level string `json:"level,omitempty"`
+
+	// Expired is true once the deadline passed
+	Expired bool `json:"expired"`
+}
+
+// setupDeadlineRoutes registers the deadline admin API.
+func (s *Server) setupDeadlineRoutes(admin *gin.RouterGroup) {
+	admin.GET("/deadline", s.handleGetDeadline)
+	admin.PUT("/deadline", s.handleSetDeadline)
+	admin.DELETE("/deadline", s.handleClearDeadline)
+}
+
+// handleGetDeadline returns the deadline.
+func (s *Server) handleGetDeadline(c *gin.Context) {
+	c.JSON(http.StatusOK, s.deadlineResponse())
+}
+
+// handleSetDeadline sets the deadline.
+func (s *Server) handleSetDeadline(c *gin.Context) {
+	var req setDeadlineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+	if req.Level == "" {
+		req.Level = "scenario"
+	}
+
+	s.deadline.set(time.UnixMilli(req.DeadlineMs), req.Level)
+	c.JSON(http.StatusOK, s.deadlineResponse())
+}
+
+// handleClearDeadline removes the deadline.
+func (s *Server) handleClearDeadline(c *gin.Context) {
+	s.deadline.clear()
+	c.JSON(http.StatusOK, s.deadlineResponse())
+}
+
+// deadlineResponse describes the current deadline.
+func (s *Server) deadlineResponse() deadlineResponse {
+	at, level, expired := s.deadline.snapshot()
+	response := deadlineResponse{Level: level, Expired: expired}
+	if !at.IsZero() {
+		response.DeadlineMs = at.UnixMilli()
+	}
+	return response
+}
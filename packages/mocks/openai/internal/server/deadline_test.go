@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestSetDeadline(t *testing.T) {
+	past := time.Now().Add(-time.Minute).UnixMilli()
+	future := time.Now().Add(time.Hour).UnixMilli()
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantLevel   string
+		wantExpired bool
+	}{
+		{
+			name:        "past deadline expires at once",
+			body:        fmt.Sprintf(`{"deadline_ms":%d}`, past),
+			wantStatus:  http.StatusOK,
+			wantLevel:   "scenario",
+			wantExpired: true,
+		},
+		{
+			name:       "future deadline",
+			body:       fmt.Sprintf(`{"deadline_ms":%d,"level":"run"}`, future),
+			wantStatus: http.StatusOK,
+			wantLevel:  "run",
+		},
+		{
+			name:       "missing deadline",
+			body:       `{"level":"step"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodPut, "/_sentra/deadline", tt.body, nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got deadlineResponse
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/deadline", "", nil), &got)
+			if got.Level != tt.wantLevel {
+				t.Errorf("level = %q, want %q", got.Level, tt.wantLevel)
+			}
+			if got.Expired != tt.wantExpired {
+				t.Errorf("expired = %v, want %v", got.Expired, tt.wantExpired)
+			}
+
+			var cleared deadlineResponse
+			decodeJSON(t, serve(s, http.MethodDelete, "/_sentra/deadline", "", nil), &cleared)
+			if cleared != (deadlineResponse{}) {
+				t.Errorf("after clearing: %+v, want no deadline", cleared)
+			}
+		})
+	}
+}
+
+func TestDeadlineCancelsRequests(t *testing.T) {
+	const body = `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`
+
+	tests := []struct {
+		name string
+		// deadline is relative to the request; zero sets none
+		deadline   time.Duration
+		clear      bool
+		wantStatus int
+	}{
+		{name: "no deadline", wantStatus: http.StatusOK},
+		{name: "already expired", deadline: -time.Second, wantStatus: http.StatusGatewayTimeout},
+		{name: "expires in flight", deadline: 50 * time.Millisecond, wantStatus: http.StatusGatewayTimeout},
+		{name: "cleared", deadline: -time.Second, clear: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// requests take 300ms unless the deadline cuts them short
+			config := latency.DefaultSimulatorConfig()
+			config.EnableJitter = false
+			config.EnableLoadSimulation = false
+			simulator := latency.NewSimulator(config)
+			simulator.GetProfileRegistry().SetProfile("gpt-4o-mini", latency.Profile{
+				ModelID:     "gpt-4o-mini",
+				BaseLatency: 300 * time.Millisecond,
+				MaxLatency:  time.Second,
+			})
+			s := newTestServer(t, Dependencies{Latency: simulator, Fixtures: newGenericFixtures(t, 5)})
+
+			if tt.deadline != 0 {
+				at := time.Now().Add(tt.deadline).UnixMilli()
+				expectStatus(t, serve(s, http.MethodPut, "/_sentra/deadline", fmt.Sprintf(`{"deadline_ms":%d,"level":"step"}`, at), nil), http.StatusOK)
+			}
+			if tt.clear {
+				expectStatus(t, serve(s, http.MethodDelete, "/_sentra/deadline", "", nil), http.StatusOK)
+			}
+
+			start := time.Now()
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", body, nil)
+			elapsed := time.Since(start)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusGatewayTimeout {
+				return
+			}
+
+			if elapsed >= 300*time.Millisecond {
+				t.Errorf("request took %v, want it cut short", elapsed)
+			}
+			var got models.ErrorResponse
+			decodeJSON(t, rec, &got)
+			if got.Error.Code == nil || *got.Error.Code != models.ErrorCodeTimeout {
+				t.Errorf("code = %v, want %q", got.Error.Code, models.ErrorCodeTimeout)
+			}
+			if !strings.Contains(got.Error.Message, "step timeout") {
+				t.Errorf("message = %q, want it to name the step timeout", got.Error.Message)
+			}
+		})
+	}
+}
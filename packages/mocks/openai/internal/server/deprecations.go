@@ -0,0 +1,266 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements model alias resolution and simulated deprecations:
+// a deprecated model keeps working with warning headers until its shutdown
+// date on the virtual clock, then fails with model_not_found, so agents can
+// rehearse migrating off it.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+const (
+	// headerModelShutdown is the shutdown date of a deprecated model (RFC 3339)
+	headerModelShutdown = "X-Sentra-Model-Shutdown"
+
+	// headerModelReplacement is the recommended replacement for a deprecated model
+	headerModelReplacement = "X-Sentra-Model-Replacement"
+)
+
+// modelDeprecations holds scheduled model deprecations.
+type modelDeprecations struct {
+	// byModel maps alias or snapshot IDs to their deprecation
+	byModel map[string]models.ModelDeprecation
+
+	// mu guards byModel
+	mu sync.RWMutex
+}
+
+// newModelDeprecations creates an empty deprecation schedule.
+func newModelDeprecations() *modelDeprecations {
+	return &modelDeprecations{byModel: make(map[string]models.ModelDeprecation)}
+}
+
+// set schedules (or reschedules) a deprecation.
+func (d *modelDeprecations) set(deprecation models.ModelDeprecation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.byModel[deprecation.Model] = deprecation
+}
+
+// remove cancels a deprecation. Returns false if none was scheduled.
+func (d *modelDeprecations) remove(model string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.byModel[model]; !ok {
+		return false
+	}
+	delete(d.byModel, model)
+	return true
+}
+
+// clear cancels all deprecations.
+func (d *modelDeprecations) clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.byModel = make(map[string]models.ModelDeprecation)
+}
+
+// list returns all deprecations, earliest shutdown first.
+func (d *modelDeprecations) list() []models.ModelDeprecation {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	result := make([]models.ModelDeprecation, 0, len(d.byModel))
+	for _, deprecation := range d.byModel {
+		result = append(result, deprecation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].ShutdownDate.Equal(result[j].ShutdownDate) {
+			return result[i].ShutdownDate.Before(result[j].ShutdownDate)
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
+
+// lookup returns the deprecation affecting a requested model: one set on
+// the exact ID, else on the snapshot an alias points to, else on the alias
+// a snapshot belongs to.
+func (d *modelDeprecations) lookup(requested string) (models.ModelDeprecation, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	configID, responseID := models.ResolveModel(requested)
+	for _, id := range []string{requested, responseID, configID} {
+		if deprecation, ok := d.byModel[id]; ok {
+			return deprecation, true
+		}
+	}
+	return models.ModelDeprecation{}, false
+}
+
+// load schedules the deprecations in a YAML file (a list of deprecations).
+func (d *modelDeprecations) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var deprecations []models.ModelDeprecation
+	if err := yaml.Unmarshal(data, &deprecations); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, deprecation := range deprecations {
+		if err := validateDeprecation(deprecation); err != nil {
+			return err
+		}
+		d.set(deprecation)
+	}
+	return nil
+}
+
+// validateDeprecation checks a deprecation has a model and a shutdown date.
+func validateDeprecation(deprecation models.ModelDeprecation) error {
+	if deprecation.Model == "" {
+		return fmt.Errorf("deprecation model is required")
+	}
+	if deprecation.ShutdownDate.IsZero() {
+		return fmt.Errorf("deprecation of %s: shutdown_date is required", deprecation.Model)
+	}
+	return nil
+}
+
+// LoadModelDeprecations schedules the deprecations in a YAML file.
+func (s *Server) LoadModelDeprecations(path string) error {
+	return s.deprecations.load(path)
+}
+
+// ResolveModel resolves a requested model for an API handler. It returns
+// the model's configuration and the ID to report in the response (the
+// dated snapshot for an alias). A deprecated model gets shutdown and
+// replacement headers; once its shutdown date has passed on the virtual
+// clock the request fails with model_not_found.
+func (s *Server) ResolveModel(c *gin.Context, requested string) (models.ModelConfig, string, error) {
+	if deprecation, ok := s.deprecations.lookup(requested); ok {
+		if deprecation.IsShutDown(clock.Now()) {
+			return models.ModelConfig{}, "", models.NewModelDeprecatedError(requested, deprecation.Replacement)
+		}
+
+		c.Header(headerModelShutdown, deprecation.ShutdownDate.UTC().Format(time.RFC3339))
+		if deprecation.Replacement != "" {
+			c.Header(headerModelReplacement, deprecation.Replacement)
+		}
+	}
+
+	config, err := models.GetModelConfig(requested)
+	if err != nil {
+		return models.ModelConfig{}, "", models.NewModelNotFoundError(requested)
+	}
+
+	_, responseID := models.ResolveModel(requested)
+	return config, responseID, nil
+}
+
+// setupDeprecationRoutes registers the model deprecation admin API.
+func (s *Server) setupDeprecationRoutes(admin *gin.RouterGroup) {
+	admin.GET("/deprecations", s.handleGetDeprecations)
+	admin.PUT("/deprecations/:model", s.handleSetDeprecation)
+	admin.DELETE("/deprecations/:model", s.handleRemoveDeprecation)
+	admin.DELETE("/deprecations", s.handleClearDeprecations)
+}
+
+// deprecationResponse describes a deprecation and its state.
+type deprecationResponse struct {
+	models.ModelDeprecation
+
+	// ShutDown is true once requests to the model fail
+	ShutDown bool `json:"shut_down"`
+}
+
+// handleGetDeprecations lists scheduled deprecations.
+func (s *Server) handleGetDeprecations(c *gin.Context) {
+	now := clock.Now()
+
+	data := []deprecationResponse{}
+	for _, deprecation := range s.deprecations.list() {
+		data = append(data, deprecationResponse{
+			ModelDeprecation: deprecation,
+			ShutDown:         deprecation.IsShutDown(now),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   data,
+	})
+}
+
+// setDeprecationRequest is the body of PUT /_sentra/deprecations/:model.
+type setDeprecationRequest struct {
+	// ShutdownDate is an RFC 3339 timestamp
+	ShutdownDate time.Time `json:"shutdown_date" binding:"required"`
+
+	// Replacement is the recommended model (optional)
+	Replacement string `json:"replacement"`
+}
+
+// handleSetDeprecation schedules a model's shutdown.
+func (s *Server) handleSetDeprecation(c *gin.Context) {
+	var req setDeprecationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	model := c.Param("model")
+	config, err := models.GetModelConfig(model)
+	if err != nil {
+		abortWithError(c, models.NewModelNotFoundError(model))
+		return
+	}
+	if req.Replacement != "" {
+		param := "replacement"
+		replacement, err := models.GetModelConfig(req.Replacement)
+		if err != nil {
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("unknown replacement model %q", req.Replacement), &param))
+			return
+		}
+		if replacement.Type != config.Type {
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("replacement %s (%s) must be the same type as %s (%s)", req.Replacement, replacement.Type, model, config.Type), &param))
+			return
+		}
+	}
+
+	deprecation := models.ModelDeprecation{
+		Model:        model,
+		ShutdownDate: req.ShutdownDate,
+		Replacement:  req.Replacement,
+	}
+	s.deprecations.set(deprecation)
+
+	c.JSON(http.StatusOK, deprecationResponse{
+		ModelDeprecation: deprecation,
+		ShutDown:         deprecation.IsShutDown(clock.Now()),
+	})
+}
+
+// handleRemoveDeprecation cancels one model's deprecation.
+func (s *Server) handleRemoveDeprecation(c *gin.Context) {
+	model := c.Param("model")
+	if !s.deprecations.remove(model) {
+		abortWithError(c, models.NewAPIError(models.ErrorTypeBadRequest,
+			fmt.Sprintf("no deprecation scheduled for %s", model), http.StatusNotFound))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleClearDeprecations cancels all deprecations.
+func (s *Server) handleClearDeprecations(c *gin.Context) {
+	s.deprecations.clear()
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+)
+
+func TestSetDeprecation(t *testing.T) {
+	shutdown := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		model      string
+		body       string
+		wantStatus int
+		wantParam  string
+	}{
+		{
+			name:       "with replacement",
+			model:      "gpt-4",
+			body:       fmt.Sprintf(`{"shutdown_date":%q,"replacement":"gpt-4o"}`, shutdown),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "without replacement",
+			model:      "gpt-4o-2024-05-13",
+			body:       fmt.Sprintf(`{"shutdown_date":%q}`, shutdown),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing shutdown date",
+			model:      "gpt-4",
+			body:       `{"replacement":"gpt-4o"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown model",
+			model:      "gpt-2",
+			body:       fmt.Sprintf(`{"shutdown_date":%q}`, shutdown),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown replacement",
+			model:      "gpt-4",
+			body:       fmt.Sprintf(`{"shutdown_date":%q,"replacement":"gpt-5-ultra"}`, shutdown),
+			wantStatus: http.StatusBadRequest,
+			wantParam:  "replacement",
+		},
+		{
+			name:       "replacement of another type",
+			model:      "gpt-4",
+			body:       fmt.Sprintf(`{"shutdown_date":%q,"replacement":"text-embedding-3-small"}`, shutdown),
+			wantStatus: http.StatusBadRequest,
+			wantParam:  "replacement",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodPut, "/_sentra/deprecations/"+tt.model, tt.body, nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+			}
+
+			var list struct {
+				Data []deprecationResponse `json:"data"`
+			}
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/deprecations", "", nil), &list)
+			wantListed := 0
+			if tt.wantStatus == http.StatusOK {
+				wantListed = 1
+			}
+			if len(list.Data) != wantListed {
+				t.Fatalf("listed %d deprecations, want %d", len(list.Data), wantListed)
+			}
+			if wantListed == 1 && (list.Data[0].Model != tt.model || list.Data[0].ShutDown) {
+				t.Errorf("listed %+v, want %s not yet shut down", list.Data[0], tt.model)
+			}
+		})
+	}
+}
+
+func TestDeprecatedModelRequests(t *testing.T) {
+	t.Cleanup(clock.Reset)
+
+	tests := []struct {
+		name string
+		// deprecated is the model the deprecation is set on; requested is
+		// the model the request names
+		deprecated string
+		requested  string
+		// advance moves the virtual clock before the request
+		advance         time.Duration
+		wantStatus      int
+		wantReplacement string
+	}{
+		{name: "alias before shutdown", deprecated: "gpt-4o", requested: "gpt-4o", wantStatus: http.StatusOK, wantReplacement: "gpt-4o-mini"},
+		{name: "snapshot of deprecated alias", deprecated: "gpt-4o", requested: "gpt-4o-2024-08-06", wantStatus: http.StatusOK, wantReplacement: "gpt-4o-mini"},
+		{name: "alias of deprecated snapshot", deprecated: "gpt-4o-2024-08-06", requested: "gpt-4o", wantStatus: http.StatusOK, wantReplacement: "gpt-4o-mini"},
+		{name: "other snapshot unaffected", deprecated: "gpt-4o-2024-05-13", requested: "gpt-4o", wantStatus: http.StatusOK},
+		{name: "after shutdown", deprecated: "gpt-4o", requested: "gpt-4o", advance: 48 * time.Hour, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Reset()
+			s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+
+			shutdown := clock.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+			body := fmt.Sprintf(`{"shutdown_date":%q,"replacement":"gpt-4o-mini"}`, shutdown)
+			expectStatus(t, serve(s, http.MethodPut, "/_sentra/deprecations/"+tt.deprecated, body, nil), http.StatusOK)
+
+			if tt.advance > 0 {
+				if err := clock.Advance(tt.advance); err != nil {
+					t.Fatalf("Advance() error = %v", err)
+				}
+			}
+
+			rec := serve(s, http.MethodPost, "/v1/chat/completions",
+				fmt.Sprintf(`{"model":%q,"messages":[{"role":"user","content":"Hello"}]}`, tt.requested), nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if got := rec.Header().Get(headerModelReplacement); got != tt.wantReplacement {
+				t.Errorf("%s = %q, want %q", headerModelReplacement, got, tt.wantReplacement)
+			}
+			if wantShutdown := tt.wantReplacement != ""; (rec.Header().Get(headerModelShutdown) != "") != wantShutdown {
+				t.Errorf("%s = %q, want set: %v", headerModelShutdown, rec.Header().Get(headerModelShutdown), wantShutdown)
+			}
+			if tt.wantStatus == http.StatusNotFound {
+				if got := errorParam(t, rec); got != "model" {
+					t.Errorf("param = %q, want %q", got, "model")
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveDeprecations(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	body := fmt.Sprintf(`{"shutdown_date":%q}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	for _, model := range []string{"gpt-4", "gpt-4-turbo", "gpt-3.5-turbo"} {
+		expectStatus(t, serve(s, http.MethodPut, "/_sentra/deprecations/"+model, body, nil), http.StatusOK)
+	}
+
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/deprecations/gpt-4", "", nil), http.StatusNoContent)
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/deprecations/gpt-4", "", nil), http.StatusNotFound)
+	if got := len(s.deprecations.list()); got != 2 {
+		t.Errorf("after removing one: %d deprecations, want 2", got)
+	}
+
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/deprecations", "", nil), http.StatusNoContent)
+	if got := len(s.deprecations.list()); got != 0 {
+		t.Errorf("after clearing: %d deprecations, want 0", got)
+	}
+}
+
+func TestLoadModelDeprecations(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantErr   bool
+		wantOrder []string
+	}{
+		{
+			name: "sorted by shutdown date",
+			yaml: `
+- model: gpt-4
+  shutdown_date: 2025-06-01T00:00:00Z
+  replacement: gpt-4o
+- model: gpt-3.5-turbo
+  shutdown_date: 2025-01-01T00:00:00Z
+`,
+			wantOrder: []string{"gpt-3.5-turbo", "gpt-4"},
+		},
+		{
+			name:    "missing model",
+			yaml:    "- shutdown_date: 2025-06-01T00:00:00Z\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing shutdown date",
+			yaml:    "- model: gpt-4\n",
+			wantErr: true,
+		},
+		{
+			name:    "not a list",
+			yaml:    "model: gpt-4\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "deprecations.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			s := newTestServer(t, Dependencies{})
+			err := s.LoadModelDeprecations(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadModelDeprecations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, deprecation := range s.deprecations.list() {
+				got = append(got, deprecation.Model)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantOrder) {
+				t.Errorf("deprecations = %v, want %v", got, tt.wantOrder)
+			}
+		})
+	}
+}
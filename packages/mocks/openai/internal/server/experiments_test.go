@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// newExperimentServer serves the generic chat fixtures under an experiment
+// with a control and a concise variant.
+func newExperimentServer(t *testing.T) (*Server, *fixtures.Experiments) {
+	t.Helper()
+
+	store := fixtures.NewStore()
+	for path, content := range map[string]string{
+		"responses/chat/generic.yaml": "Generic answer.",
+		"responses/chat/control.yaml": "Control answer.",
+		"responses/chat/concise.yaml": "Concise answer.",
+	} {
+		if err := store.Add(path, fixtures.FixtureFile{Responses: []fixtures.Fixture{{ID: path, Content: content}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	experiments := fixtures.NewExperiments()
+	err := experiments.Add(fixtures.Experiment{
+		Name:    "tone",
+		Fixture: "responses/chat/generic.yaml",
+		Variants: []fixtures.Variant{
+			{Name: "control", Fixture: "responses/chat/control.yaml"},
+			{Name: "concise", Fixture: "responses/chat/concise.yaml"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newTestServer(t, Dependencies{Fixtures: store, Experiments: experiments}), experiments
+}
+
+func TestChatCompletionExperiments(t *testing.T) {
+	variantContent := map[string]string{
+		"control": "Control answer.",
+		"concise": "Concise answer.",
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+
+		// unit is the unit the variant is pinned to ("" if random)
+		unit string
+	}{
+		{
+			name:    "unit header",
+			body:    `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`,
+			headers: map[string]string{fixtures.HeaderExperimentUnit: "run-1"},
+			unit:    "run-1",
+		},
+		{
+			name:    "unit header with a project",
+			body:    `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`,
+			headers: map[string]string{fixtures.HeaderExperimentUnit: "run-2", models.HeaderProject: "proj_abc"},
+			unit:    "run-2",
+		},
+		{
+			name: "seed",
+			body: `{"model":"gpt-4o-mini","seed":3,"messages":[{"role":"user","content":"Hello"}]}`,
+			unit: "seed:3",
+		},
+		{
+			name: "random assignment",
+			body: `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, experiments := newExperimentServer(t)
+
+			for i := 0; i < 5; i++ {
+				rec := serve(s, http.MethodPost, "/v1/chat/completions", tt.body, tt.headers)
+				expectStatus(t, rec, http.StatusOK)
+
+				if got := rec.Header().Get(fixtures.HeaderExperiment); got != "tone" {
+					t.Fatalf("%s = %q, want tone", fixtures.HeaderExperiment, got)
+				}
+				variant := rec.Header().Get(fixtures.HeaderVariant)
+				if tt.unit != "" {
+					want, err := experiments.VariantForUnit("tone", tt.unit)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if variant != want {
+						t.Fatalf("%s = %q, want %q", fixtures.HeaderVariant, variant, want)
+					}
+				}
+
+				var resp models.ChatCompletionResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if want, ok := variantContent[variant]; !ok || resp.Choices[0].Message.Content != want {
+					t.Fatalf("variant %q served %q", variant, resp.Choices[0].Message.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestChatCompletionWithoutExperiment(t *testing.T) {
+	store := fixtures.NewStore()
+	if err := store.Add("responses/chat/generic.yaml", fixtures.FixtureFile{Responses: []fixtures.Fixture{{ID: "generic", Content: "Generic answer."}}}); err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer(t, Dependencies{Fixtures: store, Experiments: fixtures.NewExperiments()})
+
+	rec := serve(s, http.MethodPost, "/v1/chat/completions", `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`, map[string]string{fixtures.HeaderExperimentUnit: "run-1"})
+	expectStatus(t, rec, http.StatusOK)
+	for _, header := range []string{fixtures.HeaderExperiment, fixtures.HeaderVariant} {
+		if got := rec.Header().Get(header); got != "" {
+			t.Errorf("%s = %q without an experiment", header, got)
+		}
+	}
+}
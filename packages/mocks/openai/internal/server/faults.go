@@ -0,0 +1,136 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the fault injection admin API used by scenario steps
+// (inject_latency, clear_faults) to script degradation and recovery.
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// setupFaultRoutes registers the fault injection admin API.
+func (s *Server) setupFaultRoutes(admin *gin.RouterGroup) {
+	if s.latency == nil && s.errorInjector == nil {
+		return
+	}
+
+	admin.GET("/faults", s.handleGetFaults)
+	admin.DELETE("/faults", s.handleClearFaults)
+	if s.latency != nil {
+		admin.POST("/faults/latency", s.handleInjectLatency)
+	}
+}
+
+// latencyFaultRequest is the body of POST /_sentra/faults/latency.
+type latencyFaultRequest struct {
+	// Model is the model ID, a prefix ending in "*", or empty for all models
+	Model string `json:"model"`
+
+	// AddedMs is a fixed delay added to each request in milliseconds
+	AddedMs int64 `json:"added_ms"`
+
+	// Multiplier scales the simulated latency (e.g., 3.0 = 3x slower)
+	Multiplier float64 `json:"multiplier"`
+
+	// DurationMs lifts the fault automatically after this long (0 = until cleared)
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// latencyFaultResponse describes an active latency fault.
+type latencyFaultResponse struct {
+	// Model is the model pattern the fault applies to
+	Model string `json:"model"`
+
+	// AddedMs is the added delay in milliseconds
+	AddedMs int64 `json:"added_ms"`
+
+	// Multiplier is the latency multiplier (0 = unchanged)
+	Multiplier float64 `json:"multiplier"`
+
+	// ExpiresAt is when the fault lifts (Unix seconds, null = until cleared)
+	ExpiresAt *int64 `json:"expires_at"`
+}
+
+// newLatencyFaultResponse converts a latency fault for the admin API.
+func newLatencyFaultResponse(fault latency.LatencyFault) latencyFaultResponse {
+	response := latencyFaultResponse{
+		Model:      fault.Model,
+		AddedMs:    fault.Added.Milliseconds(),
+		Multiplier: fault.Multiplier,
+	}
+	if !fault.ExpiresAt.IsZero() {
+		expiresAt := fault.ExpiresAt.Unix()
+		response.ExpiresAt = &expiresAt
+	}
+	return response
+}
+
+// handleGetFaults lists active faults.
+func (s *Server) handleGetFaults(c *gin.Context) {
+	latencyFaults := []latencyFaultResponse{}
+	if s.latency != nil {
+		for _, fault := range s.latency.GetLatencyFaults() {
+			latencyFaults = append(latencyFaults, newLatencyFaultResponse(fault))
+		}
+	}
+
+	response := gin.H{"latency": latencyFaults}
+	if s.errorInjector != nil {
+		stats := s.errorInjector.GetStats()
+		response["errors"] = gin.H{
+			"enabled":          stats.Enabled,
+			"base_error_rate":  stats.BaseErrorRate,
+			"burst_error_rate": stats.BurstErrorRate,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleInjectLatency adds a latency fault, replacing any for the same model.
+func (s *Server) handleInjectLatency(c *gin.Context) {
+	var req latencyFaultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+	if req.DurationMs < 0 {
+		param := "duration_ms"
+		abortWithError(c, models.NewBadRequestError("duration_ms cannot be negative", &param))
+		return
+	}
+
+	fault := latency.LatencyFault{
+		Model:      req.Model,
+		Added:      time.Duration(req.AddedMs) * time.Millisecond,
+		Multiplier: req.Multiplier,
+	}
+	if req.DurationMs > 0 {
+		fault.ExpiresAt = time.Now().Add(time.Duration(req.DurationMs) * time.Millisecond)
+	}
+
+	if err := s.latency.InjectLatency(fault); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, newLatencyFaultResponse(fault))
+}
+
+// handleClearFaults removes latency faults and restores error injection
+// to its configured defaults.
+func (s *Server) handleClearFaults(c *gin.Context) {
+	if s.latency != nil {
+		s.latency.ClearLatencyFaults()
+	}
+	if s.errorInjector != nil {
+		s.errorInjector.RestoreDefaults()
+	}
+
+	c.Status(http.StatusNoContent)
+}
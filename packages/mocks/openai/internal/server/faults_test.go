@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+)
+
+func TestInjectLatencyFault(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantStatus    int
+		wantParam     string
+		wantExpiresAt bool
+	}{
+		{name: "added delay", body: `{"model":"gpt-4*","added_ms":500}`, wantStatus: http.StatusOK},
+		{name: "multiplier with duration", body: `{"multiplier":3,"duration_ms":60000}`, wantStatus: http.StatusOK, wantExpiresAt: true},
+		{name: "neither delay nor multiplier", body: `{"model":"gpt-4o"}`, wantStatus: http.StatusBadRequest},
+		{name: "negative delay", body: `{"added_ms":-1}`, wantStatus: http.StatusBadRequest},
+		{name: "negative duration", body: `{"added_ms":100,"duration_ms":-1}`, wantStatus: http.StatusBadRequest, wantParam: "duration_ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Latency: latency.NewSimulator(latency.DefaultSimulatorConfig())})
+
+			rec := serve(s, http.MethodPost, "/_sentra/faults/latency", tt.body, nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+			}
+
+			var faults struct {
+				Latency []latencyFaultResponse `json:"latency"`
+			}
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/faults", "", nil), &faults)
+			if tt.wantStatus != http.StatusOK {
+				if len(faults.Latency) != 0 {
+					t.Errorf("faults = %+v, want none", faults.Latency)
+				}
+				return
+			}
+			if len(faults.Latency) != 1 {
+				t.Fatalf("got %d faults, want 1", len(faults.Latency))
+			}
+			if got := faults.Latency[0].ExpiresAt != nil; got != tt.wantExpiresAt {
+				t.Errorf("expires_at set = %v, want %v", got, tt.wantExpiresAt)
+			}
+
+			expectStatus(t, serve(s, http.MethodDelete, "/_sentra/faults", "", nil), http.StatusNoContent)
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/faults", "", nil), &faults)
+			if len(faults.Latency) != 0 {
+				t.Errorf("after clearing: %+v, want no faults", faults.Latency)
+			}
+		})
+	}
+}
+
+func TestFaultRoutesNeedSimulators(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodGet, "/_sentra/faults", "", nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/faults/latency", `{"added_ms":100}`, nil), http.StatusNotFound)
+}
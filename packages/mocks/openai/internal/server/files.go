@@ -0,0 +1,327 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the Files API: uploads are validated against their
+// purpose and kept in the server's storage (memory, or Redis shared by
+// replicas) until deleted or reset, alongside the read-only seeded files.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// Storage keys of uploaded files.
+const (
+	// fileKeyPrefix prefixes each uploaded file's key
+	fileKeyPrefix = "files:"
+
+	// fileSeqKey orders uploads across replicas
+	fileSeqKey = "files_seq"
+)
+
+// FilesConfig configures file uploads.
+type FilesConfig struct {
+	// MaxFileBytes is the maximum size of an uploaded file. Uploads are
+	// also bounded by Limits.MaxBodyBytes.
+	MaxFileBytes int64
+}
+
+// DefaultFilesConfig returns default file configuration, matching the API's
+// 512 MB limit.
+func DefaultFilesConfig() FilesConfig {
+	return FilesConfig{
+		MaxFileBytes: models.MaxFileBytes,
+	}
+}
+
+// uploadedFile is the stored form of an uploaded file.
+type uploadedFile struct {
+	File    models.File `json:"file"`
+	Seq     int64       `json:"seq"`
+	Content []byte      `json:"content"`
+}
+
+// fileStore keeps uploaded files in storage. Records are stored as JSON
+// strings, so memory and Redis storage return them alike.
+type fileStore struct {
+	storage store.Storage
+	config  FilesConfig
+}
+
+// newFileStore creates a file store. Without storage, files are kept in
+// memory.
+func newFileStore(storage store.Storage, config FilesConfig) *fileStore {
+	if storage == nil {
+		storage = store.NewMemoryStore()
+	}
+	return &fileStore{storage: storage, config: config}
+}
+
+// put stores an uploaded file and returns it with its ID set.
+func (st *fileStore) put(ctx context.Context, filename, purpose string, content []byte) (models.File, error) {
+	seq, err := st.storage.Increment(ctx, fileSeqKey, 1)
+	if err != nil {
+		return models.File{}, err
+	}
+
+	record := uploadedFile{
+		File: models.File{
+			ID:        models.NewFileID(),
+			Object:    "file",
+			Bytes:     len(content),
+			CreatedAt: clock.Now().Unix(),
+			Filename:  filename,
+			Purpose:   purpose,
+			Status:    "processed",
+		},
+		Seq:     seq,
+		Content: content,
+	}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return models.File{}, err
+	}
+	if err := st.storage.Set(ctx, fileKeyPrefix+record.File.ID, string(encoded), 0); err != nil {
+		return models.File{}, err
+	}
+	return record.File, nil
+}
+
+// get returns an uploaded file.
+func (st *fileStore) get(ctx context.Context, fileID string) (*uploadedFile, bool) {
+	value, err := st.storage.Get(ctx, fileKeyPrefix+fileID)
+	if err != nil {
+		return nil, false
+	}
+	return decodeUploadedFile(value)
+}
+
+// list returns the uploaded files, oldest first.
+func (st *fileStore) list(ctx context.Context) ([]*uploadedFile, error) {
+	keys, err := st.storage.Keys(ctx, fileKeyPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	values, err := st.storage.GetMulti(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*uploadedFile, 0, len(values))
+	for _, value := range values {
+		if file, ok := decodeUploadedFile(value); ok {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Seq < files[j].Seq
+	})
+	return files, nil
+}
+
+// delete removes an uploaded file. Returns false if it does not exist.
+func (st *fileStore) delete(ctx context.Context, fileID string) (bool, error) {
+	exists, err := st.storage.Exists(ctx, fileKeyPrefix+fileID)
+	if err != nil || !exists {
+		return false, err
+	}
+	return true, st.storage.Delete(ctx, fileKeyPrefix+fileID)
+}
+
+// reset deletes every uploaded file and returns how many there were.
+func (st *fileStore) reset(ctx context.Context) (int, error) {
+	keys, err := st.storage.Keys(ctx, fileKeyPrefix+"*")
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	return len(keys), st.storage.DeleteMulti(ctx, keys)
+}
+
+// count returns the number of uploaded files.
+func (st *fileStore) count(ctx context.Context) int {
+	keys, err := st.storage.Keys(ctx, fileKeyPrefix+"*")
+	if err != nil {
+		return 0
+	}
+	return len(keys)
+}
+
+// decodeUploadedFile decodes a stored file record.
+func decodeUploadedFile(value interface{}) (*uploadedFile, bool) {
+	encoded, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var file uploadedFile
+	if json.Unmarshal([]byte(encoded), &file) != nil {
+		return nil, false
+	}
+	return &file, true
+}
+
+// setupFileRoutes registers the Files API.
+func (s *Server) setupFileRoutes() {
+	s.api.POST("/files", s.handleUploadFile)
+	s.api.GET("/files", s.handleListFiles)
+	s.api.GET("/files/:file_id", s.handleGetFile)
+	s.api.GET("/files/:file_id/content", s.handleGetFileContent)
+	s.api.DELETE("/files/:file_id", s.handleDeleteFile)
+}
+
+// handleUploadFile stores an uploaded file.
+func (s *Server) handleUploadFile(c *gin.Context) {
+	form, ok := parseImageForm(c)
+	if !ok {
+		return
+	}
+
+	var req models.FileUploadRequest
+	if !bindImageForm(c, &req) {
+		return
+	}
+
+	headers := form.File["file"]
+	if len(headers) == 0 {
+		param := "file"
+		abortWithError(c, models.NewBadRequestError("Missing required parameter: 'file'.", &param))
+		return
+	}
+	header := headers[0]
+	if err := req.ValidateSize(header.Size, s.files.config.MaxFileBytes); err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+	content, err := readFormFile(header)
+	if err != nil {
+		param := "file"
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Could not read file '%s': %v", header.Filename, err), &param))
+		return
+	}
+	if err := req.ValidateContent(header.Filename, content); err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	file, err := s.files.put(ctx, header.Filename, req.Purpose, content)
+	if err != nil {
+		metrics.Warn(ctx, "failed to store uploaded file", "error", err)
+		abortWithError(c, models.NewServerError("The file could not be stored."))
+		return
+	}
+
+	c.JSON(http.StatusOK, file)
+}
+
+// handleListFiles lists the seeded files, then the uploaded ones
+// (?purpose=, ?order=, ?limit=, ?after=).
+func (s *Server) handleListFiles(c *gin.Context) {
+	purpose := c.Query("purpose")
+
+	s.seeds.mu.RLock()
+	var files []models.File
+	for _, fileID := range s.seeds.fileOrder {
+		if file := s.seeds.files[fileID]; purpose == "" || file.file.Purpose == purpose {
+			files = append(files, file.file)
+		}
+	}
+	s.seeds.mu.RUnlock()
+
+	ctx := c.Request.Context()
+	uploaded, err := s.files.list(ctx)
+	if err != nil {
+		metrics.Warn(ctx, "failed to list uploaded files", "error", err)
+	}
+	for _, file := range uploaded {
+		if purpose == "" || file.File.Purpose == purpose {
+			files = append(files, file.File)
+		}
+	}
+
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(ids))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.File, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, files[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// handleGetFile returns a file.
+func (s *Server) handleGetFile(c *gin.Context) {
+	fileID := c.Param("file_id")
+	if file, ok := s.seeds.file(fileID); ok {
+		c.JSON(http.StatusOK, file.file)
+		return
+	}
+	if file, ok := s.files.get(c.Request.Context(), fileID); ok {
+		c.JSON(http.StatusOK, file.File)
+		return
+	}
+	fileNotFound(c, fileID)
+}
+
+// handleGetFileContent returns a file's content.
+func (s *Server) handleGetFileContent(c *gin.Context) {
+	fileID := c.Param("file_id")
+	if file, ok := s.seeds.file(fileID); ok {
+		c.Data(http.StatusOK, "application/octet-stream", []byte(file.content))
+		return
+	}
+	if file, ok := s.files.get(c.Request.Context(), fileID); ok {
+		c.Data(http.StatusOK, "application/octet-stream", file.Content)
+		return
+	}
+	fileNotFound(c, fileID)
+}
+
+// handleDeleteFile deletes an uploaded file. Seeded files are read-only.
+func (s *Server) handleDeleteFile(c *gin.Context) {
+	fileID := c.Param("file_id")
+	if _, ok := s.seeds.file(fileID); ok {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("File %s is seeded and cannot be deleted; change the seed files instead.", fileID), nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	deleted, err := s.files.delete(ctx, fileID)
+	if err != nil {
+		metrics.Warn(ctx, "failed to delete uploaded file", "error", err)
+		abortWithError(c, models.NewServerError("The file could not be deleted."))
+		return
+	}
+	if !deleted {
+		fileNotFound(c, fileID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      fileID,
+		"object":  "file",
+		"deleted": true,
+	})
+}
+
+// fileNotFound aborts with the API's error for an unknown file.
+func fileNotFound(c *gin.Context, fileID string) {
+	abortWithError(c, models.NewNotFoundError(fmt.Sprintf("No such File object: %s", fileID)))
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// uploadFile uploads a file and returns it.
+func uploadFile(t *testing.T, s *Server, purpose, filename, content string) models.File {
+	t.Helper()
+
+	rec := serveMultipart(t, s, "/v1/files", map[string]string{"purpose": purpose}, formFile{field: "file", filename: filename, data: []byte(content)})
+	expectStatus(t, rec, http.StatusOK)
+
+	var file models.File
+	decodeJSON(t, rec, &file)
+	return file
+}
+
+func TestUploadFile(t *testing.T) {
+	const trainingLine = `{"messages":[{"role":"user","content":"Hi"},{"role":"assistant","content":"Hello"}]}`
+
+	tests := []struct {
+		name      string
+		fields    map[string]string
+		files     []formFile
+		maxBytes  int64
+		status    int
+		wantParam string
+	}{
+		{
+			name:   "assistants document",
+			fields: map[string]string{"purpose": "assistants"},
+			files:  []formFile{{field: "file", filename: "notes.txt", data: []byte("Refunds take 5 days.")}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "fine-tune jsonl",
+			fields: map[string]string{"purpose": "fine-tune"},
+			files:  []formFile{{field: "file", filename: "train.jsonl", data: []byte(trainingLine + "\n\n" + trainingLine + "\n")}},
+			status: http.StatusOK,
+		},
+		{
+			name:      "fine-tune not jsonl",
+			fields:    map[string]string{"purpose": "fine-tune"},
+			files:     []formFile{{field: "file", filename: "train.csv", data: []byte("prompt,completion\n")}},
+			status:    http.StatusBadRequest,
+			wantParam: "file",
+		},
+		{
+			name:      "fine-tune line not an object",
+			fields:    map[string]string{"purpose": "fine-tune"},
+			files:     []formFile{{field: "file", filename: "train.jsonl", data: []byte(trainingLine + "\n[1, 2]\n")}},
+			status:    http.StatusBadRequest,
+			wantParam: "file",
+		},
+		{
+			name:      "missing purpose",
+			files:     []formFile{{field: "file", filename: "notes.txt", data: []byte("notes")}},
+			status:    http.StatusBadRequest,
+			wantParam: "purpose",
+		},
+		{
+			name:      "invalid purpose",
+			fields:    map[string]string{"purpose": "training"},
+			files:     []formFile{{field: "file", filename: "notes.txt", data: []byte("notes")}},
+			status:    http.StatusBadRequest,
+			wantParam: "purpose",
+		},
+		{
+			name:      "missing file",
+			fields:    map[string]string{"purpose": "assistants"},
+			status:    http.StatusBadRequest,
+			wantParam: "file",
+		},
+		{
+			name:      "empty file",
+			fields:    map[string]string{"purpose": "assistants"},
+			files:     []formFile{{field: "file", filename: "notes.txt"}},
+			status:    http.StatusBadRequest,
+			wantParam: "file",
+		},
+		{
+			name:      "too large",
+			fields:    map[string]string{"purpose": "assistants"},
+			files:     []formFile{{field: "file", filename: "notes.txt", data: []byte("0123456789")}},
+			maxBytes:  8,
+			status:    http.StatusBadRequest,
+			wantParam: "file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			if tt.maxBytes > 0 {
+				config.Files.MaxFileBytes = tt.maxBytes
+			}
+			s := New(config, Dependencies{})
+
+			rec := serveMultipart(t, s, "/v1/files", tt.fields, tt.files...)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var file models.File
+			decodeJSON(t, rec, &file)
+			upload := tt.files[0]
+			if file.Object != "file" || file.Filename != upload.filename || file.Bytes != len(upload.data) || file.Purpose != tt.fields["purpose"] {
+				t.Errorf("file = %+v", file)
+			}
+
+			rec = serve(s, http.MethodGet, "/v1/files/"+file.ID+"/content", "", nil)
+			expectStatus(t, rec, http.StatusOK)
+			if got := rec.Body.String(); got != string(upload.data) {
+				t.Errorf("content = %q, want %q", got, upload.data)
+			}
+		})
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/seed", `{"collections":{"files":[{"id":"file-seeded","filename":"faq.md","content":"FAQ"}]}}`, nil), http.StatusOK)
+	notes := uploadFile(t, s, "assistants", "notes.txt", "notes")
+	train := uploadFile(t, s, "fine-tune", "train.jsonl", `{"prompt":"Hi"}`)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "seeded then uploaded", query: "?order=asc", want: []string{"file-seeded", notes.ID, train.ID}},
+		{name: "newest first", want: []string{train.ID, notes.ID, "file-seeded"}},
+		{name: "by purpose", query: "?purpose=assistants&order=asc", want: []string{"file-seeded", notes.ID}},
+		{name: "limit", query: "?purpose=fine-tune&limit=1", want: []string{train.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, "/v1/files"+tt.query, "", nil)
+			expectStatus(t, rec, http.StatusOK)
+
+			var resp struct {
+				Data []models.File `json:"data"`
+			}
+			decodeJSON(t, rec, &resp)
+			var got []string
+			for _, file := range resp.Data {
+				got = append(got, file.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteFile(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/seed", `{"collections":{"files":[{"id":"file-seeded","filename":"faq.md","content":"FAQ"}]}}`, nil), http.StatusOK)
+	file := uploadFile(t, s, "assistants", "notes.txt", "notes")
+
+	tests := []struct {
+		name   string
+		fileID string
+		status int
+	}{
+		{name: "uploaded", fileID: file.ID, status: http.StatusOK},
+		{name: "already deleted", fileID: file.ID, status: http.StatusNotFound},
+		{name: "seeded", fileID: "file-seeded", status: http.StatusBadRequest},
+		{name: "unknown", fileID: "file-missing", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, serve(s, http.MethodDelete, "/v1/files/"+tt.fileID, "", nil), tt.status)
+		})
+	}
+
+	expectStatus(t, serve(s, http.MethodGet, "/v1/files/"+file.ID, "", nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodGet, "/v1/files/file-seeded", "", nil), http.StatusOK)
+}
@@ -0,0 +1,552 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the fine-tuning jobs API. Jobs move through
+// validating_files, queued and running to succeeded on a configurable
+// timeline measured on the virtual clock, so advancing the clock finishes
+// them instantly. Status and events are derived from the timeline when a
+// job is read, and a succeeded job's ft: model is registered so it can be
+// used like any other model.
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// Training defaults applied to "auto" hyperparameters.
+const (
+	autoFineTuningEpochs       = 3
+	autoFineTuningBatchSize    = 1
+	autoFineTuningLearningRate = 2.0
+
+	// fineTuningMetricsEvents is the number of metrics events a job emits
+	// while running
+	fineTuningMetricsEvents = 10
+)
+
+// FineTuningConfig configures the fine-tuning job timeline (on the virtual
+// clock).
+type FineTuningConfig struct {
+	// Validating is how long a job validates its files
+	Validating time.Duration
+
+	// Queued is how long a job waits before training
+	Queued time.Duration
+
+	// Running is how long training takes
+	Running time.Duration
+}
+
+// DefaultFineTuningConfig returns the default timeline: jobs finish about
+// a minute and a quarter after they are created.
+func DefaultFineTuningConfig() FineTuningConfig {
+	return FineTuningConfig{
+		Validating: 5 * time.Second,
+		Queued:     10 * time.Second,
+		Running:    time.Minute,
+	}
+}
+
+// ParseFineTuningTimeline parses "validating,queued,running" durations
+// (e.g. "1s,2s,10s").
+func ParseFineTuningTimeline(value string) (FineTuningConfig, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return FineTuningConfig{}, fmt.Errorf("expected validating,queued,running durations, got %q", value)
+	}
+
+	var durations [3]time.Duration
+	for i, part := range parts {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || duration < 0 {
+			return FineTuningConfig{}, fmt.Errorf("invalid duration %q", part)
+		}
+		durations[i] = duration
+	}
+	return FineTuningConfig{Validating: durations[0], Queued: durations[1], Running: durations[2]}, nil
+}
+
+// fineTuningJob is a job and the timeline its status is derived from.
+type fineTuningJob struct {
+	// job holds the fields fixed at creation
+	job models.FineTuningJob
+
+	// validatedAt, startedAt and finishedAt end the job's phases
+	createdAt   time.Time
+	validatedAt time.Time
+	startedAt   time.Time
+	finishedAt  time.Time
+
+	// cancelledAt is set when the job was cancelled
+	cancelledAt time.Time
+
+	// failure is set when validation will fail the job
+	failure *models.FineTuningJobError
+
+	// fineTunedModel is the model the job produces
+	fineTunedModel string
+
+	// totalSteps and trainedTokens describe the training
+	totalSteps    int
+	trainedTokens int
+
+	// registered is set once the fine-tuned model is registered
+	registered bool
+}
+
+// status returns the job's status at now.
+func (j *fineTuningJob) status(now time.Time) models.FineTuningJobStatus {
+	switch {
+	case !j.cancelledAt.IsZero() && !now.Before(j.cancelledAt):
+		return models.FineTuningCancelled
+	case now.Before(j.validatedAt):
+		return models.FineTuningValidatingFiles
+	case j.failure != nil:
+		return models.FineTuningFailed
+	case now.Before(j.startedAt):
+		return models.FineTuningQueued
+	case now.Before(j.finishedAt):
+		return models.FineTuningRunning
+	}
+	return models.FineTuningSucceeded
+}
+
+// view returns the job as the API reports it at now.
+func (j *fineTuningJob) view(now time.Time) models.FineTuningJob {
+	job := j.job
+	job.Status = j.status(now)
+
+	switch job.Status {
+	case models.FineTuningSucceeded:
+		finishedAt := j.finishedAt.Unix()
+		job.FinishedAt = &finishedAt
+		job.FineTunedModel = &j.fineTunedModel
+		job.TrainedTokens = &j.trainedTokens
+	case models.FineTuningFailed:
+		finishedAt := j.validatedAt.Unix()
+		job.FinishedAt = &finishedAt
+		job.Error = j.failure
+	case models.FineTuningCancelled:
+	default:
+		estimatedFinish := j.finishedAt.Unix()
+		job.EstimatedFinish = &estimatedFinish
+	}
+	return job
+}
+
+// events returns the job's events up to now, oldest first.
+func (j *fineTuningJob) events(now time.Time) []models.FineTuningEvent {
+	end := now
+	if !j.cancelledAt.IsZero() && j.cancelledAt.Before(end) {
+		end = j.cancelledAt
+	}
+
+	var events []models.FineTuningEvent
+	add := func(at time.Time, level, message string, data map[string]interface{}) {
+		if at.After(end) {
+			return
+		}
+		event := models.FineTuningEvent{
+			ID:        models.FineTuningEventID(j.job.ID, len(events)+1),
+			Object:    "fine_tuning.job.event",
+			CreatedAt: at.Unix(),
+			Level:     level,
+			Message:   message,
+			Type:      "message",
+		}
+		if data != nil {
+			event.Type = "metrics"
+			event.Data = data
+		}
+		events = append(events, event)
+	}
+
+	add(j.createdAt, "info", "Created fine-tuning job: "+j.job.ID, nil)
+	add(j.createdAt, "info", "Validating training file: "+j.job.TrainingFile, nil)
+	if j.failure != nil {
+		add(j.validatedAt, "error", j.failure.Message, nil)
+	} else {
+		add(j.validatedAt, "info", "Files validated, moving job to queued state", nil)
+		add(j.startedAt, "info", "Fine-tuning job started", nil)
+		for i := 1; i <= fineTuningMetricsEvents; i++ {
+			progress := float64(i) / fineTuningMetricsEvents
+			at := j.startedAt.Add(time.Duration(progress * float64(j.finishedAt.Sub(j.startedAt))))
+			step := max(int(progress*float64(j.totalSteps)), 1)
+			add(at, "info", fmt.Sprintf("Step %d/%d: training loss=%.4f", step, j.totalSteps, trainingLoss(progress)), map[string]interface{}{
+				"step":                      step,
+				"total_steps":               j.totalSteps,
+				"train_loss":                trainingLoss(progress),
+				"train_mean_token_accuracy": 1 - trainingLoss(progress)/3,
+			})
+		}
+		add(j.finishedAt, "info", "New fine-tuned model created: "+j.fineTunedModel, nil)
+		add(j.finishedAt, "info", "The job has successfully completed", nil)
+	}
+	if !j.cancelledAt.IsZero() && !j.cancelledAt.After(now) {
+		add(j.cancelledAt, "info", "Fine-tuning job cancelled", nil)
+	}
+	return events
+}
+
+// trainingLoss is the simulated loss after a share of training: it falls
+// from about 2.1 towards 0.1.
+func trainingLoss(progress float64) float64 {
+	return math.Round((0.1+2*math.Exp(-3*progress))*10000) / 10000
+}
+
+// fineTuningStore holds fine-tuning jobs.
+type fineTuningStore struct {
+	mu   sync.Mutex
+	jobs map[string]*fineTuningJob
+
+	// order is the creation order, oldest first
+	order []string
+}
+
+// newFineTuningStore creates an empty fine-tuning store.
+func newFineTuningStore() *fineTuningStore {
+	return &fineTuningStore{
+		jobs: make(map[string]*fineTuningJob),
+	}
+}
+
+// add stores a job.
+func (st *fineTuningStore) add(job *fineTuningJob) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.jobs[job.job.ID] = job
+	st.order = append(st.order, job.job.ID)
+}
+
+// view returns a job as the API reports it, registering its fine-tuned
+// model once it has succeeded.
+func (st *fineTuningStore) view(ctx context.Context, jobID string) (models.FineTuningJob, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	job, ok := st.jobs[jobID]
+	if !ok {
+		return models.FineTuningJob{}, false
+	}
+	return st.viewLocked(ctx, job), true
+}
+
+// viewLocked returns a job's view. Must be called with mu held.
+func (st *fineTuningStore) viewLocked(ctx context.Context, job *fineTuningJob) models.FineTuningJob {
+	view := job.view(clock.Now())
+	if view.Status == models.FineTuningSucceeded && !job.registered {
+		job.registered = true
+		if err := registerFineTunedModel(job); err != nil {
+			metrics.Warn(ctx, "failed to register fine-tuned model", "model", job.fineTunedModel, "error", err)
+		}
+	}
+	return view
+}
+
+// list returns every job, oldest first.
+func (st *fineTuningStore) list(ctx context.Context) []models.FineTuningJob {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	jobs := make([]models.FineTuningJob, 0, len(st.order))
+	for _, jobID := range st.order {
+		jobs = append(jobs, st.viewLocked(ctx, st.jobs[jobID]))
+	}
+	return jobs
+}
+
+// events returns a job's events, oldest first.
+func (st *fineTuningStore) events(jobID string) ([]models.FineTuningEvent, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	job, ok := st.jobs[jobID]
+	if !ok {
+		return nil, false
+	}
+	return job.events(clock.Now()), true
+}
+
+// cancel cancels a job. Returns an error if it has already finished.
+func (st *fineTuningStore) cancel(ctx context.Context, jobID string) (models.FineTuningJob, bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	job, ok := st.jobs[jobID]
+	if !ok {
+		return models.FineTuningJob{}, false, nil
+	}
+
+	now := clock.Now()
+	if status := job.status(now); status.IsTerminal() {
+		return models.FineTuningJob{}, true, models.NewBadRequestError(fmt.Sprintf("Job has already %s and cannot be cancelled.", status), nil)
+	}
+	job.cancelledAt = now
+	return st.viewLocked(ctx, job), true, nil
+}
+
+// reset discards every job, deregistering their fine-tuned models, and
+// returns how many there were.
+func (st *fineTuningStore) reset() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	count := len(st.jobs)
+	for _, job := range st.jobs {
+		if job.registered {
+			models.DeregisterModel(job.fineTunedModel)
+		}
+	}
+	st.jobs = make(map[string]*fineTuningJob)
+	st.order = nil
+	return count
+}
+
+// count returns the number of jobs.
+func (st *fineTuningStore) count() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return len(st.jobs)
+}
+
+// registerFineTunedModel registers a job's fine-tuned model, inheriting its
+// base model's configuration.
+func registerFineTunedModel(job *fineTuningJob) error {
+	config, err := models.CustomModel{
+		ID:      job.fineTunedModel,
+		Base:    job.job.Model,
+		OwnedBy: job.job.OrganizationID,
+	}.Config()
+	if err != nil {
+		return err
+	}
+	return models.RegisterModel(config)
+}
+
+// setupFineTuningRoutes registers the fine-tuning jobs API.
+func (s *Server) setupFineTuningRoutes() {
+	s.api.POST("/fine_tuning/jobs", s.handleCreateFineTuningJob)
+	s.api.GET("/fine_tuning/jobs", s.handleListFineTuningJobs)
+	s.api.GET("/fine_tuning/jobs/:job_id", s.handleGetFineTuningJob)
+	s.api.POST("/fine_tuning/jobs/:job_id/cancel", s.handleCancelFineTuningJob)
+	s.api.GET("/fine_tuning/jobs/:job_id/events", s.handleListFineTuningEvents)
+}
+
+// fineTuningJobNotFound aborts with the API's error for an unknown job.
+func fineTuningJobNotFound(c *gin.Context, jobID string) {
+	abortWithError(c, models.NewNotFoundError(fmt.Sprintf("Could not find fine-tune: %s", jobID)))
+}
+
+// handleCreateFineTuningJob creates a job for an uploaded (or seeded)
+// fine-tune file.
+func (s *Server) handleCreateFineTuningJob(c *gin.Context) {
+	var req models.CreateFineTuningJobRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+	if err := req.ValidateModel(); err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	content, ok := s.fineTuneFile(c, req.TrainingFile)
+	if !ok {
+		param := "training_file"
+		abortWithError(c, models.NewBadRequestError("invalid training_file: "+req.TrainingFile, &param))
+		return
+	}
+	if req.ValidationFile != "" {
+		if _, ok := s.fineTuneFile(c, req.ValidationFile); !ok {
+			param := "validation_file"
+			abortWithError(c, models.NewBadRequestError("invalid validation_file: "+req.ValidationFile, &param))
+			return
+		}
+	}
+
+	var hyperparameters models.FineTuningHyperparameters
+	if req.Hyperparameters != nil {
+		hyperparameters = *req.Hyperparameters
+	}
+	epochs := hyperparameters.Epochs(autoFineTuningEpochs)
+	resolved := models.FineTuningHyperparameters{
+		NEpochs:                epochs,
+		BatchSize:              autoFineTuningBatchSize,
+		LearningRateMultiplier: autoFineTuningLearningRate,
+	}
+	if batchSize, ok := hyperparameters.BatchSize.(float64); ok {
+		resolved.BatchSize = int(math.Max(batchSize, 1))
+	}
+	if multiplier, ok := hyperparameters.LearningRateMultiplier.(float64); ok {
+		resolved.LearningRateMultiplier = multiplier
+	}
+
+	now := clock.Now()
+	organization := GetScope(c).Organization
+	if organization == "" {
+		organization = "org-sentra"
+	}
+	seed := int(now.UnixNano() % math.MaxInt32)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	job := &fineTuningJob{
+		job: models.FineTuningJob{
+			ID:              models.NewFineTuningJobID(),
+			Object:          "fine_tuning.job",
+			CreatedAt:       now.Unix(),
+			Model:           req.Model,
+			OrganizationID:  organization,
+			Hyperparameters: resolved,
+			TrainingFile:    req.TrainingFile,
+			ResultFiles:     []string{},
+			Seed:            seed,
+			Metadata:        req.Metadata,
+		},
+		createdAt: now,
+	}
+	if req.ValidationFile != "" {
+		job.job.ValidationFile = &req.ValidationFile
+	}
+	if req.Suffix != "" {
+		job.job.Suffix = &req.Suffix
+	}
+
+	timeline := s.config.FineTuning
+	job.validatedAt = now.Add(timeline.Validating)
+	job.startedAt = job.validatedAt.Add(timeline.Queued)
+	job.finishedAt = job.startedAt.Add(timeline.Running)
+
+	examples := fineTuningExamples(content)
+	if examples < models.MinFineTuningExamples {
+		param := "training_file"
+		job.failure = &models.FineTuningJobError{
+			Code:    "invalid_training_file",
+			Message: fmt.Sprintf("The job failed due to an invalid training file. Training file has %d example(s), but must have at least %d examples.", examples, models.MinFineTuningExamples),
+			Param:   &param,
+		}
+	}
+	batchSize, _ := resolved.BatchSize.(int)
+	job.totalSteps = max((examples+batchSize-1)/batchSize, 1) * epochs
+	job.trainedTokens = tokenizer.FastEstimate(string(content)) * epochs
+	job.fineTunedModel = models.FineTunedModelID(req.Model, organization, req.Suffix, job.job.ID)
+
+	s.fineTuning.add(job)
+
+	view, _ := s.fineTuning.view(c.Request.Context(), job.job.ID)
+	c.JSON(http.StatusOK, view)
+}
+
+// fineTuneFile returns the content of an uploaded or seeded file with the
+// fine-tune purpose.
+func (s *Server) fineTuneFile(c *gin.Context, fileID string) ([]byte, bool) {
+	if file, ok := s.seeds.file(fileID); ok {
+		return []byte(file.content), file.file.Purpose == "fine-tune"
+	}
+	if file, ok := s.files.get(c.Request.Context(), fileID); ok {
+		return file.Content, file.File.Purpose == "fine-tune"
+	}
+	return nil, false
+}
+
+// fineTuningExamples counts the examples (non-empty lines) of a JSONL file.
+func fineTuningExamples(content []byte) int {
+	examples := 0
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) != "" {
+			examples++
+		}
+	}
+	return examples
+}
+
+// handleListFineTuningJobs lists jobs, newest first (?limit=, ?order=).
+func (s *Server) handleListFineTuningJobs(c *gin.Context) {
+	jobs := s.fineTuning.list(c.Request.Context())
+
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(jobs))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.FineTuningJob, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, jobs[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// handleGetFineTuningJob returns a job.
+func (s *Server) handleGetFineTuningJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	job, ok := s.fineTuning.view(c.Request.Context(), jobID)
+	if !ok {
+		fineTuningJobNotFound(c, jobID)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// handleCancelFineTuningJob cancels a job that has not finished.
+func (s *Server) handleCancelFineTuningJob(c *gin.Context) {
+	jobID := c.Param("job_id")
+	job, ok, err := s.fineTuning.cancel(c.Request.Context(), jobID)
+	if !ok {
+		fineTuningJobNotFound(c, jobID)
+		return
+	}
+	if err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// handleListFineTuningEvents lists a job's events, newest first (?limit=,
+// ?order=).
+func (s *Server) handleListFineTuningEvents(c *gin.Context) {
+	jobID := c.Param("job_id")
+	events, ok := s.fineTuning.events(jobID)
+	if !ok {
+		fineTuningJobNotFound(c, jobID)
+		return
+	}
+
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(events))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.FineTuningEvent, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, events[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
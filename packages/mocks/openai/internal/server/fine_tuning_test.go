@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// newFineTuningServer creates a server with seeded training files: one with
+// the minimum number of examples, one with too few, and an assistants
+// document.
+func newFineTuningServer(t *testing.T) *Server {
+	t.Helper()
+	t.Cleanup(clock.Reset)
+
+	example := `{"messages":[{"role":"user","content":"Hi"},{"role":"assistant","content":"Hello"}]}` + "\n"
+	files := []fileSeed{
+		{ID: "file-train", Filename: "train.jsonl", Purpose: "fine-tune", Content: strings.Repeat(example, models.MinFineTuningExamples)},
+		{ID: "file-short", Filename: "short.jsonl", Purpose: "fine-tune", Content: strings.Repeat(example, 2)},
+		{ID: "file-docs", Filename: "docs.md", Purpose: "assistants", Content: "Docs"},
+	}
+	body, err := json.Marshal(map[string]interface{}{"collections": map[string]interface{}{SeedFiles: files}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestServer(t, Dependencies{})
+	t.Cleanup(func() { s.fineTuning.reset() })
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/seed", string(body), nil), http.StatusOK)
+	return s
+}
+
+// createFineTuningJob creates a job and returns it.
+func createFineTuningJob(t *testing.T, s *Server, body string) models.FineTuningJob {
+	t.Helper()
+
+	rec := serve(s, http.MethodPost, "/v1/fine_tuning/jobs", body, nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var job models.FineTuningJob
+	decodeJSON(t, rec, &job)
+	return job
+}
+
+// getFineTuningJob returns a job.
+func getFineTuningJob(t *testing.T, s *Server, jobID string) models.FineTuningJob {
+	t.Helper()
+
+	rec := serve(s, http.MethodGet, "/v1/fine_tuning/jobs/"+jobID, "", nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var job models.FineTuningJob
+	decodeJSON(t, rec, &job)
+	return job
+}
+
+// fineTuningEvents lists a job's events, oldest first.
+func fineTuningEvents(t *testing.T, s *Server, jobID string) []models.FineTuningEvent {
+	t.Helper()
+
+	rec := serve(s, http.MethodGet, "/v1/fine_tuning/jobs/"+jobID+"/events?order=asc&limit=100", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var resp struct {
+		Data []models.FineTuningEvent `json:"data"`
+	}
+	decodeJSON(t, rec, &resp)
+	return resp.Data
+}
+
+func TestParseFineTuningTimeline(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    FineTuningConfig
+		wantErr bool
+	}{
+		{value: "5s,10s,1m", want: FineTuningConfig{Validating: 5 * time.Second, Queued: 10 * time.Second, Running: time.Minute}},
+		{value: "0s, 0s, 2h", want: FineTuningConfig{Running: 2 * time.Hour}},
+		{value: "5s,10s", wantErr: true},
+		{value: "5s,soon,1m", wantErr: true},
+		{value: "5s,-10s,1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFineTuningTimeline(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFineTuningTimeline(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFineTuningTimeline(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateFineTuningJobErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantParam string
+	}{
+		{name: "missing model", body: `{"training_file":"file-train"}`, wantParam: "model"},
+		{name: "missing training file", body: `{"model":"gpt-4o-mini"}`, wantParam: "training_file"},
+		{name: "model not fine-tunable", body: `{"model":"o1","training_file":"file-train"}`, wantParam: "model"},
+		{name: "unknown training file", body: `{"model":"gpt-4o-mini","training_file":"file-missing"}`, wantParam: "training_file"},
+		{name: "training file not for fine-tuning", body: `{"model":"gpt-4o-mini","training_file":"file-docs"}`, wantParam: "training_file"},
+		{name: "unknown validation file", body: `{"model":"gpt-4o-mini","training_file":"file-train","validation_file":"file-missing"}`, wantParam: "validation_file"},
+	}
+
+	s := newFineTuningServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodPost, "/v1/fine_tuning/jobs", tt.body, nil)
+			expectStatus(t, rec, http.StatusBadRequest)
+			if got := errorParam(t, rec); got != tt.wantParam {
+				t.Errorf("error param = %q, want %q", got, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestFineTuningJobTimeline(t *testing.T) {
+	s := newFineTuningServer(t)
+	job := createFineTuningJob(t, s, `{"model":"gpt-4o-mini","training_file":"file-train","suffix":"support","hyperparameters":{"n_epochs":2}}`)
+	if job.Hyperparameters.NEpochs != 2.0 || job.Hyperparameters.LearningRateMultiplier != autoFineTuningLearningRate {
+		t.Errorf("hyperparameters = %+v", job.Hyperparameters)
+	}
+
+	// The default timeline validates for 5s, queues for 10s and runs for 1m
+	tests := []struct {
+		advance    time.Duration
+		want       models.FineTuningJobStatus
+		wantEvents int
+	}{
+		{advance: 0, want: models.FineTuningValidatingFiles, wantEvents: 2},
+		{advance: 5 * time.Second, want: models.FineTuningQueued, wantEvents: 3},
+		{advance: 10 * time.Second, want: models.FineTuningRunning, wantEvents: 4},
+		{advance: 30 * time.Second, want: models.FineTuningRunning, wantEvents: 4 + fineTuningMetricsEvents/2},
+		{advance: 30 * time.Second, want: models.FineTuningSucceeded, wantEvents: 6 + fineTuningMetricsEvents},
+	}
+
+	for _, tt := range tests {
+		if err := clock.Advance(tt.advance); err != nil {
+			t.Fatal(err)
+		}
+
+		got := getFineTuningJob(t, s, job.ID)
+		if got.Status != tt.want {
+			t.Fatalf("status = %q, want %q", got.Status, tt.want)
+		}
+		if events := fineTuningEvents(t, s, job.ID); len(events) != tt.wantEvents {
+			t.Errorf("%s: %d events, want %d", tt.want, len(events), tt.wantEvents)
+		}
+		if tt.want != models.FineTuningSucceeded {
+			if got.EstimatedFinish == nil || got.FineTunedModel != nil {
+				t.Errorf("%s: estimated_finish = %v, fine_tuned_model = %v", tt.want, got.EstimatedFinish, got.FineTunedModel)
+			}
+			continue
+		}
+
+		if got.FineTunedModel == nil || !strings.HasPrefix(*got.FineTunedModel, "ft:gpt-4o-mini:org-sentra:support:") {
+			t.Fatalf("fine_tuned_model = %v", got.FineTunedModel)
+		}
+		if got.FinishedAt == nil || got.TrainedTokens == nil || *got.TrainedTokens == 0 {
+			t.Errorf("finished_at = %v, trained_tokens = %v", got.FinishedAt, got.TrainedTokens)
+		}
+		if _, err := models.GetModelConfig(*got.FineTunedModel); err != nil {
+			t.Errorf("fine-tuned model is not registered: %v", err)
+		}
+	}
+
+	expectStatus(t, serve(s, http.MethodPost, "/v1/fine_tuning/jobs/"+job.ID+"/cancel", "", nil), http.StatusBadRequest)
+}
+
+func TestFineTuningJobTooFewExamples(t *testing.T) {
+	s := newFineTuningServer(t)
+	job := createFineTuningJob(t, s, `{"model":"gpt-4o-mini","training_file":"file-short"}`)
+	if job.Status != models.FineTuningValidatingFiles {
+		t.Errorf("status = %q, want validation first", job.Status)
+	}
+
+	if err := clock.Advance(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	got := getFineTuningJob(t, s, job.ID)
+	if got.Status != models.FineTuningFailed || got.Error == nil || got.Error.Param == nil || *got.Error.Param != "training_file" {
+		t.Fatalf("job = %+v, want failed on training_file", got)
+	}
+	events := fineTuningEvents(t, s, job.ID)
+	if last := events[len(events)-1]; last.Level != "error" {
+		t.Errorf("last event = %+v, want the error", last)
+	}
+}
+
+func TestCancelFineTuningJob(t *testing.T) {
+	s := newFineTuningServer(t)
+	job := createFineTuningJob(t, s, `{"model":"gpt-4o-mini","training_file":"file-train"}`)
+
+	if err := clock.Advance(20 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	rec := serve(s, http.MethodPost, "/v1/fine_tuning/jobs/"+job.ID+"/cancel", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var cancelled models.FineTuningJob
+	decodeJSON(t, rec, &cancelled)
+	if cancelled.Status != models.FineTuningCancelled {
+		t.Errorf("status = %q, want cancelled", cancelled.Status)
+	}
+
+	// The job stays cancelled and stops producing events
+	if err := clock.Advance(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if got := getFineTuningJob(t, s, job.ID); got.Status != models.FineTuningCancelled || got.FineTunedModel != nil {
+		t.Errorf("job = %+v an hour later, want still cancelled", got)
+	}
+	events := fineTuningEvents(t, s, job.ID)
+	if last := events[len(events)-1]; last.Message != "Fine-tuning job cancelled" {
+		t.Errorf("last event = %q, want the cancellation", last.Message)
+	}
+
+	expectStatus(t, serve(s, http.MethodPost, "/v1/fine_tuning/jobs/"+job.ID+"/cancel", "", nil), http.StatusBadRequest)
+	expectStatus(t, serve(s, http.MethodPost, "/v1/fine_tuning/jobs/ftjob-missing/cancel", "", nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodGet, "/v1/fine_tuning/jobs/ftjob-missing/events", "", nil), http.StatusNotFound)
+}
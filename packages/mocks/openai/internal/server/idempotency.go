@@ -0,0 +1,158 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements idempotent requests: a POST sent again with the same
+// Idempotency-Key gets the original response instead of being processed
+// twice. Responses are kept in the server's storage, so a retry routed to
+// another replica sharing Redis is replayed too.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+const (
+	// idempotencyKeyPrefix prefixes the stored responses of idempotent requests
+	idempotencyKeyPrefix = "idempotency:"
+
+	// idempotencyTTL is how long a key's response is replayed
+	idempotencyTTL = 24 * time.Hour
+
+	// maxIdempotentResponseBytes bounds the responses that are kept;
+	// larger ones are not replayed
+	maxIdempotentResponseBytes = 1 << 20
+
+	// headerIdempotentReplayed marks a replayed response
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// newIdempotencyStore returns the storage idempotent responses are kept in:
+// the server's, or memory without one.
+func newIdempotencyStore(storage store.Storage) store.Storage {
+	if storage == nil {
+		return store.NewMemoryStore()
+	}
+	return storage
+}
+
+// idempotentResponse is the stored state of an Idempotency-Key: pending
+// while the first request runs, then its response.
+type idempotentResponse struct {
+	Pending     bool   `json:"pending"`
+	RequestHash string `json:"request_hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyMiddleware replays the response of a POST whose
+// Idempotency-Key was already used by the same scope. A retry arriving
+// while the original is in flight gets a 409, and reusing a key with a
+// different body a 400. Rate limit and server errors, streams and
+// responses over 1 MB are not kept, so they are processed again. Must run after ScopeMiddleware.
+func (s *Server) IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(models.HeaderIdempotencyKey)
+		if idempotencyKey == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				if IsBodyTooLarge(err) {
+					abortWithError(c, models.NewRequestTooLargeError(s.config.Limits.MaxBodyBytes))
+					return
+				}
+				abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		ctx := c.Request.Context()
+		key := idempotencyKeyPrefix + GetScope(c).Key() + ":" + idempotencyKey
+		sum := sha256.Sum256(append([]byte(c.Request.URL.Path+"\n"), body...))
+		requestHash := hex.EncodeToString(sum[:])
+
+		// Claim the key, or replay what its first request left
+		pending, _ := json.Marshal(idempotentResponse{Pending: true, RequestHash: requestHash})
+		claimed, err := s.idempotency.SetNX(ctx, key, string(pending), idempotencyTTL)
+		if err != nil {
+			metrics.Warn(ctx, "failed to claim idempotency key", "error", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			s.replayIdempotent(c, key, requestHash)
+			return
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: maxIdempotentResponseBytes}
+		c.Writer = writer
+
+		c.Next()
+
+		// Work on a context the client's disconnect does not cancel
+		ctx = context.WithoutCancel(ctx)
+		status := writer.Status()
+		contentType := writer.Header().Get("Content-Type")
+		if status == http.StatusTooManyRequests || status >= http.StatusInternalServerError || writer.truncated || strings.HasPrefix(contentType, "text/event-stream") {
+			if err := s.idempotency.Delete(ctx, key); err != nil {
+				metrics.Warn(ctx, "failed to release idempotency key", "error", err)
+			}
+			return
+		}
+
+		stored, _ := json.Marshal(idempotentResponse{
+			RequestHash: requestHash,
+			Status:      status,
+			ContentType: contentType,
+			Body:        writer.body.Bytes(),
+		})
+		if err := s.idempotency.Set(ctx, key, string(stored), idempotencyTTL); err != nil {
+			metrics.Warn(ctx, "failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+// replayIdempotent answers a request whose Idempotency-Key is already
+// taken: with the stored response, or an error while the first request is
+// pending or when the request differs from it.
+func (s *Server) replayIdempotent(c *gin.Context, key, requestHash string) {
+	value, err := s.idempotency.Get(c.Request.Context(), key)
+	encoded, ok := value.(string)
+	var stored idempotentResponse
+	if err != nil || !ok || json.Unmarshal([]byte(encoded), &stored) != nil {
+		// Expired or released since the claim failed
+		abortWithError(c, models.NewIdempotencyKeyInUseError())
+		return
+	}
+
+	switch {
+	case stored.RequestHash != requestHash:
+		abortWithError(c, models.NewIdempotencyKeyReusedError())
+	case stored.Pending:
+		c.Header("Retry-After", "1")
+		abortWithError(c, models.NewIdempotencyKeyInUseError())
+	default:
+		c.Header(headerIdempotentReplayed, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+		c.Abort()
+	}
+}
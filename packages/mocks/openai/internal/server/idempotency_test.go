@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestIdempotentRequests(t *testing.T) {
+	const body = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`
+
+	tests := []struct {
+		name string
+		// retryBody and retryHeaders are the retry's body and extra headers
+		retryBody    string
+		retryHeaders map[string]string
+		status       int
+		replayed     bool
+	}{
+		{name: "same key", retryBody: body, status: http.StatusOK, replayed: true},
+		{
+			name:      "same key, different body",
+			retryBody: `{"model":"gpt-4o","messages":[{"role":"user","content":"Bye"}]}`,
+			status:    http.StatusBadRequest,
+		},
+		{
+			name:         "same key, other project",
+			retryBody:    body,
+			retryHeaders: map[string]string{models.HeaderProject: "proj_other"},
+			status:       http.StatusOK,
+		},
+		{
+			name:         "other key",
+			retryBody:    body,
+			retryHeaders: map[string]string{models.HeaderIdempotencyKey: "key-2"},
+			status:       http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+			headers := map[string]string{models.HeaderIdempotencyKey: "key-1"}
+
+			first := serve(s, http.MethodPost, "/v1/chat/completions", body, headers)
+			expectStatus(t, first, http.StatusOK)
+
+			for name, value := range tt.retryHeaders {
+				headers[name] = value
+			}
+			retry := serve(s, http.MethodPost, "/v1/chat/completions", tt.retryBody, headers)
+			expectStatus(t, retry, tt.status)
+
+			replayed := retry.Header().Get(headerIdempotentReplayed) == "true"
+			if replayed != tt.replayed {
+				t.Fatalf("replayed = %v, want %v", replayed, tt.replayed)
+			}
+			if replayed && retry.Body.String() != first.Body.String() {
+				t.Errorf("replayed body = %s, want %s", retry.Body.String(), first.Body.String())
+			}
+			if !replayed && tt.status == http.StatusOK && retry.Body.String() == first.Body.String() {
+				t.Error("retry was replayed")
+			}
+		})
+	}
+}
+
+func TestIdempotentRequestsNotStored(t *testing.T) {
+	tests := []struct {
+		name   string
+		deps   Dependencies
+		body   string
+		status int
+	}{
+		{
+			name: "rate limited",
+			deps: Dependencies{Limiter: ratelimit.NewLimiter(ratelimit.LimiterConfig{
+				Enabled:      true,
+				TierRegistry: ratelimit.NewTierRegistry("free"),
+				DefaultTier:  "free",
+			})},
+			body:   `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`,
+			status: http.StatusTooManyRequests,
+		},
+		{
+			name:   "stream",
+			body:   `{"model":"gpt-4o","stream":true,"messages":[{"role":"user","content":"Hello"}]}`,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.deps)
+
+			// Exhaust the free tier's 3 RPM without the key
+			if tt.status == http.StatusTooManyRequests {
+				for i := 0; i < 3; i++ {
+					serve(s, http.MethodPost, "/v1/chat/completions", tt.body, nil)
+				}
+			}
+
+			headers := map[string]string{models.HeaderIdempotencyKey: "key-1"}
+			for i := 0; i < 2; i++ {
+				rec := serve(s, http.MethodPost, "/v1/chat/completions", tt.body, headers)
+				expectStatus(t, rec, tt.status)
+				if rec.Header().Get(headerIdempotentReplayed) != "" {
+					t.Fatalf("request %d was replayed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyInUse(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	const body = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`
+
+	// Claim the key as if the first request were still running
+	ctx := context.Background()
+	claim := serve(s, http.MethodPost, "/v1/chat/completions", body, map[string]string{models.HeaderIdempotencyKey: "key-1"})
+	expectStatus(t, claim, http.StatusOK)
+	keys, err := s.idempotency.Keys(ctx, idempotencyKeyPrefix+"*")
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("keys = %v, %v", keys, err)
+	}
+	value, _ := s.idempotency.Get(ctx, keys[0])
+	pending := strings.Replace(value.(string), `"pending":false`, `"pending":true`, 1)
+	if err := s.idempotency.Set(ctx, keys[0], pending, idempotencyTTL); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(s, http.MethodPost, "/v1/chat/completions", body, map[string]string{models.HeaderIdempotencyKey: "key-1"})
+	expectStatus(t, rec, http.StatusConflict)
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Retry-After = %q, want 1", rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestClusterModeSharesAssistantsAndIdempotency(t *testing.T) {
+	shared := store.NewMemoryStore()
+	replicas := []*Server{
+		newTestServer(t, Dependencies{Storage: shared}),
+		newTestServer(t, Dependencies{Storage: shared}),
+	}
+
+	created := serve(replicas[0], http.MethodPost, "/v1/assistants", `{"model":"gpt-4o","name":"Helper"}`, nil)
+	expectStatus(t, created, http.StatusOK)
+	var assistant models.Assistant
+	if err := json.Unmarshal(created.Body.Bytes(), &assistant); err != nil {
+		t.Fatal(err)
+	}
+
+	// Threads are per replica; the run uses the shared assistant
+	thread := serve(replicas[1], http.MethodPost, "/v1/threads", `{"messages":[{"role":"user","content":"Hello"}]}`, nil)
+	expectStatus(t, thread, http.StatusOK)
+	var threadObject struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(thread.Body.Bytes(), &threadObject); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "get", method: http.MethodGet, path: "/v1/assistants/" + assistant.ID, status: http.StatusOK},
+		{name: "modify", method: http.MethodPost, path: "/v1/assistants/" + assistant.ID, body: `{"name":"Renamed"}`, status: http.StatusOK},
+		{name: "run", method: http.MethodPost, path: "/v1/threads/" + threadObject.ID + "/runs", body: `{"assistant_id":"` + assistant.ID + `"}`, status: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, path: "/v1/assistants/" + assistant.ID, status: http.StatusOK},
+		{name: "deleted", method: http.MethodGet, path: "/v1/assistants/" + assistant.ID, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, serve(replicas[1], tt.method, tt.path, tt.body, nil), tt.status)
+		})
+	}
+
+	t.Run("idempotency", func(t *testing.T) {
+		const body = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`
+		headers := map[string]string{models.HeaderIdempotencyKey: "key-1"}
+
+		first := serve(replicas[0], http.MethodPost, "/v1/chat/completions", body, headers)
+		retry := serve(replicas[1], http.MethodPost, "/v1/chat/completions", body, headers)
+		expectStatus(t, retry, http.StatusOK)
+		if retry.Header().Get(headerIdempotentReplayed) != "true" || retry.Body.String() != first.Body.String() {
+			t.Errorf("retry on another replica was not replayed: %s", retry.Body.String())
+		}
+	})
+}
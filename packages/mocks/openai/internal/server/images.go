@@ -0,0 +1,421 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements image generation, edits and variations with real
+// placeholder image bytes, returned inline as b64_json or served from
+// expiring local URLs.
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// ImagesConfig configures generated image URLs.
+type ImagesConfig struct {
+	// URLTTL is how long image URLs stay valid (on the virtual clock)
+	URLTTL time.Duration
+
+	// MaxStored bounds the images kept for URLs; the oldest are dropped first
+	MaxStored int
+}
+
+// DefaultImagesConfig returns default image configuration. URLs expire
+// after an hour, like OpenAI's.
+func DefaultImagesConfig() ImagesConfig {
+	return ImagesConfig{
+		URLTTL:    time.Hour,
+		MaxStored: 500,
+	}
+}
+
+// storedImage is an image served from /images/{id}.
+type storedImage struct {
+	id          string
+	data        []byte
+	contentType string
+	expiresAt   time.Time
+}
+
+// imageStore holds generated images until their URLs expire.
+type imageStore struct {
+	mu     sync.Mutex
+	config ImagesConfig
+	images map[string]*storedImage
+
+	// order is the insertion order, oldest first
+	order []string
+}
+
+// newImageStore creates an empty image store.
+func newImageStore(config ImagesConfig) *imageStore {
+	return &imageStore{
+		config: config,
+		images: make(map[string]*storedImage),
+	}
+}
+
+// put stores an image and returns it with its ID and expiry set.
+func (st *imageStore) put(data []byte, extension, contentType string) *storedImage {
+	now := clock.Now()
+	image := &storedImage{
+		id:          models.NewImageID() + "." + extension,
+		data:        data,
+		contentType: contentType,
+		expiresAt:   now.Add(st.config.URLTTL),
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	// Drop expired images, then the oldest ones beyond the limit
+	kept := st.order[:0]
+	for _, id := range st.order {
+		if st.images[id].expiresAt.After(now) {
+			kept = append(kept, id)
+		} else {
+			delete(st.images, id)
+		}
+	}
+	for len(kept) > 0 && len(kept) >= st.config.MaxStored {
+		delete(st.images, kept[0])
+		kept = kept[1:]
+	}
+
+	st.order = append(kept, image.id)
+	st.images[image.id] = image
+	return image
+}
+
+// get returns a stored image, which may have expired.
+func (st *imageStore) get(id string) (*storedImage, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	image, ok := st.images[id]
+	return image, ok
+}
+
+// reset discards every stored image and returns how many there were.
+func (st *imageStore) reset() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	count := len(st.images)
+	st.images = make(map[string]*storedImage)
+	st.order = nil
+	return count
+}
+
+// count returns the number of stored images, including expired ones not
+// yet dropped.
+func (st *imageStore) count() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return len(st.images)
+}
+
+// setupImageRoutes registers the image endpoints and the image download route.
+func (s *Server) setupImageRoutes() {
+	s.api.POST("/images/generations", s.handleImageGeneration)
+	s.api.POST("/images/edits", s.handleImageEdit)
+	s.api.POST("/images/variations", s.handleImageVariation)
+	s.engine.GET("/images/:image_id", s.handleGetImage)
+}
+
+// handleImageGeneration generates placeholder images for a prompt.
+func (s *Server) handleImageGeneration(c *gin.Context) {
+	var req models.ImageGenerationRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	if !s.resolveImageModel(c, req.GetEffectiveModel(), req.ValidateModel) {
+		return
+	}
+
+	s.respondWithImages(c, req, req.Prompt, 0)
+}
+
+// handleImageEdit edits uploaded images. The result is a placeholder
+// showing the edit prompt; gpt-image-1 bills the input images as tokens.
+func (s *Server) handleImageEdit(c *gin.Context) {
+	form, ok := parseImageForm(c)
+	if !ok {
+		return
+	}
+
+	var req models.ImageEditRequest
+	if !bindImageForm(c, &req) {
+		return
+	}
+
+	images, ok := readImageFiles(c, form, "image", "image[]")
+	if !ok {
+		return
+	}
+	masks, ok := readImageFiles(c, form, "mask")
+	if !ok {
+		return
+	}
+	var mask *models.ImageFile
+	if len(masks) > 0 {
+		mask = &masks[0]
+	}
+
+	model := req.GetEffectiveModel()
+	if !s.resolveImageModel(c, model, req.ValidateModel) {
+		return
+	}
+	if err := models.ValidateEditImages(model, images, mask); err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	inputImageTokens := 0
+	if models.IsGPTImageModel(model) {
+		inputImageTokens = models.EditInputImageTokens(images)
+	}
+
+	s.respondWithImages(c, req.Generation(), "Edit: "+req.Prompt, inputImageTokens)
+}
+
+// handleImageVariation creates variations of an uploaded image.
+func (s *Server) handleImageVariation(c *gin.Context) {
+	form, ok := parseImageForm(c)
+	if !ok {
+		return
+	}
+
+	var req models.ImageVariationRequest
+	if !bindImageForm(c, &req) {
+		return
+	}
+
+	images, ok := readImageFiles(c, form, "image")
+	if !ok {
+		return
+	}
+	var img *models.ImageFile
+	if len(images) > 0 {
+		img = &images[0]
+	}
+
+	if !s.resolveImageModel(c, req.GetEffectiveModel(), req.ValidateModel) {
+		return
+	}
+	if err := models.ValidateVariationImage(img); err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	prompt := "Variation of " + img.Filename
+	s.respondWithImages(c, req.Generation(prompt), prompt, 0)
+}
+
+// resolveImageModel resolves the model of an image request and checks it
+// serves the endpoint, aborting on failure.
+func (s *Server) resolveImageModel(c *gin.Context, model string, validate func(models.ModelConfig) error) bool {
+	config, _, err := s.ResolveModel(c, model)
+	if err == nil {
+		err = validate(config)
+	}
+	if err != nil {
+		abortWithAPIError(c, err)
+		return false
+	}
+	return true
+}
+
+// respondWithImages renders the images req asks for, with text drawn on
+// them, bills them and writes the response.
+func (s *Server) respondWithImages(c *gin.Context, req models.ImageGenerationRequest, text string, inputImageTokens int) {
+	size := req.GetEffectiveSize()
+	width, height, err := generator.ParseImageSize(size)
+	if err != nil {
+		param := "size"
+		abortWithError(c, models.NewBadRequestError(err.Error(), &param))
+		return
+	}
+
+	model := req.GetEffectiveModel()
+	gptImage := models.IsGPTImageModel(model)
+	background := "opaque"
+	if req.Background != nil && *req.Background == "transparent" {
+		background = "transparent"
+	}
+
+	placeholder := generator.PlaceholderImage{
+		Prompt:      text,
+		Width:       width,
+		Height:      height,
+		Format:      req.GetEffectiveOutputFormat(),
+		Compression: req.OutputCompression,
+		Transparent: background == "transparent",
+	}
+	data, err := placeholder.Encode()
+	if err != nil {
+		abortWithError(c, models.NewServerError(fmt.Sprintf("failed to render image: %v", err)))
+		return
+	}
+
+	n := req.GetEffectiveN()
+	images := make([]models.ImageData, n)
+	for i := range images {
+		if req.GetEffectiveResponseFormat() == "b64_json" {
+			images[i].B64JSON = base64.StdEncoding.EncodeToString(data)
+		} else {
+			stored := s.images.put(data, req.GetEffectiveOutputFormat(), placeholder.ContentType())
+			images[i].URL = fmt.Sprintf("%s/images/%s", requestBaseURL(c), stored.id)
+		}
+		if model == "dall-e-3" {
+			images[i].RevisedPrompt = req.Prompt
+		}
+	}
+
+	resp := &models.ImageResponse{
+		Created: clock.Now().Unix(),
+		Data:    images,
+	}
+	if gptImage {
+		resp.Background = background
+		resp.OutputFormat = req.GetEffectiveOutputFormat()
+		resp.Quality = req.GetEffectiveQuality()
+		resp.Size = size
+		resp.Usage = models.NewImageUsage(tokenizer.FastEstimate(req.Prompt), inputImageTokens, n, resp.Quality, size)
+	}
+
+	s.billImages(c, model, size, req.GetEffectiveQuality(), n, inputImageTokens, resp.Usage)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// billImages sets the image cost headers and records the cost against the
+// request's scope. Models without image pricing are not billed.
+func (s *Server) billImages(c *gin.Context, model, size, quality string, n, inputImageTokens int, usage *models.ImageUsage) {
+	if s.tracker == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	cost, err := s.tracker.Calculator().CalculateImageEditCost(ctx, model, size, quality, n, inputImageTokens)
+	if err != nil {
+		return
+	}
+	pricing.AddImageCostHeaders(c.Writer, cost)
+
+	tracked := pricing.Cost{
+		TotalCost: cost.TotalCost,
+		Currency:  cost.Currency,
+		Model:     model,
+	}
+	if usage != nil {
+		tracked.InputTokens = usage.InputTokens
+		tracked.OutputTokens = usage.OutputTokens
+		tracked.TotalTokens = usage.TotalTokens
+	}
+	if err := s.tracker.TrackScoped(ctx, GetScope(c), model, tracked); err != nil {
+		metrics.Warn(ctx, "failed to track image usage", "error", err)
+	}
+}
+
+// parseImageForm parses a multipart image request, aborting with the
+// API's error if the body is not multipart/form-data.
+func parseImageForm(c *gin.Context) (*multipart.Form, bool) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		if errors.Is(err, http.ErrNotMultipart) {
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Invalid Content-Type header (%s), expected multipart/form-data.", c.ContentType()), nil))
+		} else {
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("We could not parse the multipart body of your request: %v", err), nil))
+		}
+		return nil, false
+	}
+	return form, true
+}
+
+// bindImageForm binds and validates the form fields of a multipart image
+// request, aborting on failure.
+func bindImageForm(c *gin.Context, req interface{ Validate() error }) bool {
+	if err := c.ShouldBindWith(req, binding.FormMultipart); err != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("We could not parse the form fields of your request: %v", err), nil))
+		return false
+	}
+	if err := req.Validate(); err != nil {
+		abortWithAPIError(c, err)
+		return false
+	}
+	return true
+}
+
+// readImageFiles reads the files uploaded under any of the given fields.
+func readImageFiles(c *gin.Context, form *multipart.Form, fields ...string) ([]models.ImageFile, bool) {
+	var files []models.ImageFile
+	for _, field := range fields {
+		for _, header := range form.File[field] {
+			data, err := readFormFile(header)
+			if err != nil {
+				param := strings.TrimSuffix(field, "[]")
+				abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Could not read file '%s': %v", header.Filename, err), &param))
+				return nil, false
+			}
+			files = append(files, models.ImageFile{Filename: header.Filename, Data: data})
+		}
+	}
+	return files, true
+}
+
+// readFormFile reads an uploaded file's content.
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// handleGetImage serves a generated image until its URL expires.
+func (s *Server) handleGetImage(c *gin.Context) {
+	image, ok := s.images.get(c.Param("image_id"))
+	if !ok {
+		c.String(http.StatusNotFound, "The specified image does not exist.")
+		return
+	}
+	if !clock.Now().Before(image.expiresAt) {
+		c.String(http.StatusForbidden, "The image URL expired at %s.", image.expiresAt.UTC().Format(time.RFC3339))
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=0")
+	c.Data(http.StatusOK, image.contentType, image.data)
+}
+
+// requestBaseURL returns the scheme and host the client used to reach the
+// mock, honoring X-Forwarded-Proto from a proxy.
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
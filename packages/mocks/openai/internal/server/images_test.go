@@ -0,0 +1,252 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// testPNG encodes a square PNG of the given size filled with alpha a.
+func testPNG(t *testing.T, size int, a uint8) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{B: 200, A: a})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageGeneration(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		status      int
+		wantImages  int
+		wantB64     bool
+		wantRevised bool
+		wantUsage   bool
+	}{
+		{name: "dall-e-2 url", body: `{"prompt":"A red fox"}`, status: http.StatusOK, wantImages: 1},
+		{name: "several images", body: `{"prompt":"A red fox","n":3,"size":"256x256"}`, status: http.StatusOK, wantImages: 3},
+		{name: "b64_json", body: `{"prompt":"A red fox","response_format":"b64_json"}`, status: http.StatusOK, wantImages: 1, wantB64: true},
+		{name: "dall-e-3 revised prompt", body: `{"model":"dall-e-3","prompt":"A red fox"}`, status: http.StatusOK, wantImages: 1, wantRevised: true},
+		{name: "gpt-image-1 usage", body: `{"model":"gpt-image-1","prompt":"A red fox","quality":"low"}`, status: http.StatusOK, wantImages: 1, wantB64: true, wantUsage: true},
+		{name: "missing prompt", body: `{"model":"dall-e-3"}`, status: http.StatusBadRequest},
+		{name: "chat model", body: `{"model":"gpt-4o","prompt":"A red fox"}`, status: http.StatusBadRequest},
+		{name: "unknown model", body: `{"model":"dall-e-9","prompt":"A red fox"}`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := pricing.NewTracker(pricing.NewCalculator(pricing.NewPricingDB()), store.NewMemoryStore())
+			s := newTestServer(t, Dependencies{Tracker: tracker})
+
+			rec := serve(s, http.MethodPost, "/v1/images/generations", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var resp models.ImageResponse
+			decodeJSON(t, rec, &resp)
+			if len(resp.Data) != tt.wantImages {
+				t.Fatalf("%d images, want %d", len(resp.Data), tt.wantImages)
+			}
+			for _, img := range resp.Data {
+				if (img.B64JSON != "") != tt.wantB64 || (img.URL != "") == tt.wantB64 {
+					t.Errorf("b64_json set = %v, url = %q, want b64_json %v", img.B64JSON != "", img.URL, tt.wantB64)
+				}
+				if (img.RevisedPrompt != "") != tt.wantRevised {
+					t.Errorf("revised_prompt = %q", img.RevisedPrompt)
+				}
+			}
+			if (resp.Usage != nil) != tt.wantUsage {
+				t.Errorf("usage = %+v, want usage %v", resp.Usage, tt.wantUsage)
+			}
+			if got := rec.Header().Get("X-Sentra-Cost-Total"); got == "" {
+				t.Error("X-Sentra-Cost-Total is not set")
+			}
+		})
+	}
+}
+
+func TestGetImage(t *testing.T) {
+	t.Cleanup(clock.Reset)
+	s := newTestServer(t, Dependencies{})
+
+	rec := serve(s, http.MethodPost, "/v1/images/generations", `{"prompt":"A red fox","size":"256x256"}`, nil)
+	expectStatus(t, rec, http.StatusOK)
+	var resp models.ImageResponse
+	decodeJSON(t, rec, &resp)
+
+	path := resp.Data[0].URL[strings.Index(resp.Data[0].URL, "/images/"):]
+	rec = serve(s, http.MethodGet, path, "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	if got := rec.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	if config, err := png.DecodeConfig(rec.Body); err != nil || config.Width != 256 {
+		t.Errorf("image width = %d, error = %v, want 256", config.Width, err)
+	}
+
+	// URLs expire after an hour
+	if err := clock.Advance(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	expectStatus(t, serve(s, http.MethodGet, path, "", nil), http.StatusForbidden)
+	expectStatus(t, serve(s, http.MethodGet, "/images/img-missing.png", "", nil), http.StatusNotFound)
+}
+
+func TestImageEdit(t *testing.T) {
+	transparent := testPNG(t, 64, 0)
+	opaque := testPNG(t, 64, 255)
+
+	tests := []struct {
+		name      string
+		fields    map[string]string
+		files     []formFile
+		status    int
+		wantParam string
+		wantUsage bool
+	}{
+		{
+			name:   "dall-e-2 transparent image",
+			fields: map[string]string{"prompt": "Add a hat"},
+			files:  []formFile{{field: "image", filename: "fox.png", data: transparent}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "dall-e-2 with mask",
+			fields: map[string]string{"prompt": "Add a hat"},
+			files:  []formFile{{field: "image", filename: "fox.png", data: opaque}, {field: "mask", filename: "mask.png", data: transparent}},
+			status: http.StatusOK,
+		},
+		{
+			name:      "gpt-image-1 several images",
+			fields:    map[string]string{"model": "gpt-image-1", "prompt": "Combine them"},
+			files:     []formFile{{field: "image[]", filename: "a.png", data: opaque}, {field: "image[]", filename: "b.png", data: opaque}},
+			status:    http.StatusOK,
+			wantUsage: true,
+		},
+		{
+			name:      "dall-e-2 opaque without mask",
+			fields:    map[string]string{"prompt": "Add a hat"},
+			files:     []formFile{{field: "image", filename: "fox.png", data: opaque}},
+			status:    http.StatusBadRequest,
+			wantParam: "image",
+		},
+		{
+			name:      "missing image",
+			fields:    map[string]string{"prompt": "Add a hat"},
+			status:    http.StatusBadRequest,
+			wantParam: "image",
+		},
+		{
+			name:      "mask without alpha",
+			fields:    map[string]string{"prompt": "Add a hat"},
+			files:     []formFile{{field: "image", filename: "fox.png", data: transparent}, {field: "mask", filename: "mask.png", data: opaque}},
+			status:    http.StatusBadRequest,
+			wantParam: "mask",
+		},
+		{
+			name:   "missing prompt",
+			files:  []formFile{{field: "image", filename: "fox.png", data: transparent}},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serveMultipart(t, s, "/v1/images/edits", tt.fields, tt.files...)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if tt.wantParam != "" {
+					if got := errorParam(t, rec); got != tt.wantParam {
+						t.Errorf("error param = %q, want %q", got, tt.wantParam)
+					}
+				}
+				return
+			}
+
+			var resp models.ImageResponse
+			decodeJSON(t, rec, &resp)
+			if len(resp.Data) != 1 {
+				t.Fatalf("%d images, want 1", len(resp.Data))
+			}
+			if (resp.Usage != nil) != tt.wantUsage {
+				t.Fatalf("usage = %+v, want usage %v", resp.Usage, tt.wantUsage)
+			}
+			if tt.wantUsage && resp.Usage.InputTokensDetails.ImageTokens == 0 {
+				t.Errorf("usage = %+v, want input image tokens", resp.Usage)
+			}
+		})
+	}
+
+	t.Run("not multipart", func(t *testing.T) {
+		s := newTestServer(t, Dependencies{})
+		expectStatus(t, serve(s, http.MethodPost, "/v1/images/edits", `{"prompt":"Add a hat"}`, nil), http.StatusBadRequest)
+	})
+}
+
+func TestImageVariation(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]string
+		files  []formFile
+		status int
+	}{
+		{name: "square png", files: []formFile{{field: "image", filename: "fox.png", data: testPNG(t, 64, 255)}}, status: http.StatusOK},
+		{name: "b64_json", fields: map[string]string{"response_format": "b64_json", "n": "2"}, files: []formFile{{field: "image", filename: "fox.png", data: testPNG(t, 64, 255)}}, status: http.StatusOK},
+		{name: "missing image", status: http.StatusBadRequest},
+		{name: "not a png", files: []formFile{{field: "image", filename: "fox.gif", data: []byte("GIF89a")}}, status: http.StatusBadRequest},
+		{name: "gpt-image-1", fields: map[string]string{"model": "gpt-image-1"}, files: []formFile{{field: "image", filename: "fox.png", data: testPNG(t, 64, 255)}}, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serveMultipart(t, s, "/v1/images/variations", tt.fields, tt.files...)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var resp models.ImageResponse
+			decodeJSON(t, rec, &resp)
+			for _, img := range resp.Data {
+				if img.B64JSON == "" {
+					continue
+				}
+				data, err := base64.StdEncoding.DecodeString(img.B64JSON)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := png.DecodeConfig(bytes.NewReader(data)); err != nil {
+					t.Errorf("b64_json is not a png: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the latency report admin API, which reports the
+// simulated latency percentiles of the current run and checks them (or a
+// synthetic sample) against the documented latency profiles.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// setupLatencyReportRoutes registers the latency report admin API.
+func (s *Server) setupLatencyReportRoutes(admin *gin.RouterGroup) {
+	if s.latency == nil {
+		return
+	}
+
+	admin.GET("/latency/report", s.handleGetLatencyReport)
+	admin.DELETE("/latency/report", s.handleResetLatencyReport)
+	admin.POST("/latency/verify", s.handleVerifyLatency)
+}
+
+// percentilesResponse is a latency distribution in milliseconds.
+type percentilesResponse struct {
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MeanMs float64 `json:"mean_ms"`
+}
+
+// percentileCheckResponse compares one percentile with its target.
+type percentileCheckResponse struct {
+	Percentile string  `json:"percentile"`
+	ObservedMs float64 `json:"observed_ms"`
+	TargetMs   float64 `json:"target_ms"`
+	Deviation  float64 `json:"deviation"`
+	Passed     bool    `json:"passed"`
+}
+
+// profileCheckResponse is a model's self-check against its profile.
+type profileCheckResponse struct {
+	Model       string                    `json:"model"`
+	Samples     int                       `json:"samples"`
+	Tolerance   float64                   `json:"tolerance"`
+	Status      latency.CheckStatus       `json:"status"`
+	Percentiles []percentileCheckResponse `json:"percentiles"`
+}
+
+// modelLatencyResponse reports a model's simulated latencies.
+type modelLatencyResponse struct {
+	Model      string               `json:"model"`
+	Requests   int64                `json:"requests"`
+	Observed   percentilesResponse  `json:"observed"`
+	Normalized percentilesResponse  `json:"normalized_100_tokens"`
+	Check      profileCheckResponse `json:"check"`
+}
+
+// milliseconds converts a duration to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// newPercentilesResponse converts percentiles for the admin API.
+func newPercentilesResponse(p latency.Percentiles) percentilesResponse {
+	return percentilesResponse{
+		P50Ms:  milliseconds(p.P50),
+		P95Ms:  milliseconds(p.P95),
+		P99Ms:  milliseconds(p.P99),
+		MeanMs: milliseconds(p.Mean),
+	}
+}
+
+// newProfileCheckResponse converts a profile check for the admin API.
+func newProfileCheckResponse(check latency.ProfileCheck) profileCheckResponse {
+	response := profileCheckResponse{
+		Model:       check.Model,
+		Samples:     check.Samples,
+		Tolerance:   check.Tolerance,
+		Status:      check.Status,
+		Percentiles: []percentileCheckResponse{},
+	}
+	for _, p := range check.Percentiles {
+		response.Percentiles = append(response.Percentiles, percentileCheckResponse{
+			Percentile: fmt.Sprintf("p%d", p.Percentile),
+			ObservedMs: milliseconds(p.Observed),
+			TargetMs:   milliseconds(p.Target),
+			Deviation:  p.Deviation,
+			Passed:     p.Passed,
+		})
+	}
+	return response
+}
+
+// parseTolerance parses the ?tolerance= query parameter (0 = default).
+func parseTolerance(c *gin.Context) (float64, bool) {
+	raw := c.Query("tolerance")
+	if raw == "" {
+		return 0, true
+	}
+
+	tolerance, err := strconv.ParseFloat(raw, 64)
+	if err != nil || tolerance <= 0 || tolerance > 1 {
+		param := "tolerance"
+		abortWithError(c, models.NewBadRequestError("tolerance must be a number between 0 and 1", &param))
+		return 0, false
+	}
+	return tolerance, true
+}
+
+// handleGetLatencyReport reports the simulated latency percentiles per model
+// since the report was last reset, with a self-check against each profile.
+func (s *Server) handleGetLatencyReport(c *gin.Context) {
+	tolerance, ok := parseTolerance(c)
+	if !ok {
+		return
+	}
+
+	data := []modelLatencyResponse{}
+	passed := true
+	for _, report := range s.latency.LatencyReport(tolerance) {
+		if report.Check.Status == latency.CheckFail {
+			passed = false
+		}
+		data = append(data, modelLatencyResponse{
+			Model:      report.Model,
+			Requests:   report.Requests,
+			Observed:   newPercentilesResponse(report.Observed),
+			Normalized: newPercentilesResponse(report.Normalized),
+			Check:      newProfileCheckResponse(report.Check),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object":  "latency.report",
+		"run_id":  s.exchanges.activeRun(),
+		"enabled": s.latency.IsEnabled(),
+		"passed":  passed,
+		"data":    data,
+	})
+}
+
+// handleResetLatencyReport discards recorded latencies.
+func (s *Server) handleResetLatencyReport(c *gin.Context) {
+	s.latency.ResetObservations()
+	c.Status(http.StatusNoContent)
+}
+
+// verifyLatencyRequest is the body of POST /_sentra/latency/verify.
+type verifyLatencyRequest struct {
+	// Models to verify (default: every model with a latency profile)
+	Models []string `json:"models"`
+
+	// Samples is the number of latencies drawn per model (default 10000)
+	Samples int `json:"samples"`
+
+	// Tolerance is the relative deviation allowed (default 0.25)
+	Tolerance float64 `json:"tolerance"`
+}
+
+// handleVerifyLatency samples each model's profile without sleeping and
+// checks the percentiles against the profile's targets.
+func (s *Server) handleVerifyLatency(c *gin.Context) {
+	var req verifyLatencyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+			return
+		}
+	}
+	if req.Samples < 0 || req.Samples > 1_000_000 {
+		param := "samples"
+		abortWithError(c, models.NewBadRequestError("samples must be between 0 and 1000000", &param))
+		return
+	}
+	if req.Tolerance < 0 || req.Tolerance > 1 {
+		param := "tolerance"
+		abortWithError(c, models.NewBadRequestError("tolerance must be between 0 and 1", &param))
+		return
+	}
+
+	modelIDs := req.Models
+	if len(modelIDs) == 0 {
+		modelIDs = s.latency.GetProfileRegistry().ListProfiles()
+		slices.Sort(modelIDs)
+	}
+
+	data := []profileCheckResponse{}
+	passed := true
+	for _, modelID := range modelIDs {
+		check, err := s.latency.VerifyProfile(modelID, req.Samples, req.Tolerance)
+		if err != nil {
+			abortWithError(c, models.NewModelNotFoundError(modelID))
+			return
+		}
+		if check.Status != latency.CheckPass {
+			passed = false
+		}
+		data = append(data, newProfileCheckResponse(check))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "latency.verification",
+		"passed": passed,
+		"data":   data,
+	})
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+)
+
+// newLatencyServer creates a server whose gpt-4o-mini requests take about
+// 5ms.
+func newLatencyServer(t *testing.T) *Server {
+	t.Helper()
+	config := latency.DefaultSimulatorConfig()
+	config.EnableLoadSimulation = false
+	simulator := latency.NewSimulator(config)
+	simulator.GetProfileRegistry().SetProfile("gpt-4o-mini", latency.Profile{
+		ModelID:     "gpt-4o-mini",
+		BaseLatency: 5 * time.Millisecond,
+		MaxLatency:  10 * time.Millisecond,
+		P50Latency:  5 * time.Millisecond,
+		P95Latency:  5 * time.Millisecond,
+		P99Latency:  5 * time.Millisecond,
+	})
+	return newTestServer(t, Dependencies{Latency: simulator, Fixtures: newGenericFixtures(t, 5)})
+}
+
+// latencyReport is the body of GET /_sentra/latency/report.
+type latencyReport struct {
+	Data   []modelLatencyResponse `json:"data"`
+	Passed bool                   `json:"passed"`
+}
+
+func TestLatencyReport(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		requests     int
+		wantStatus   int
+		wantRequests int64
+	}{
+		{name: "no requests", wantStatus: http.StatusOK},
+		{name: "requests", requests: 3, wantStatus: http.StatusOK, wantRequests: 3},
+		{name: "tolerance", query: "?tolerance=0.5", requests: 1, wantStatus: http.StatusOK, wantRequests: 1},
+		{name: "tolerance out of range", query: "?tolerance=1.5", wantStatus: http.StatusBadRequest},
+		{name: "tolerance not a number", query: "?tolerance=half", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLatencyServer(t)
+			for i := 0; i < tt.requests; i++ {
+				expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil), http.StatusOK)
+			}
+
+			rec := serve(s, http.MethodGet, "/_sentra/latency/report"+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if got := errorParam(t, rec); got != "tolerance" {
+					t.Errorf("param = %q, want %q", got, "tolerance")
+				}
+				return
+			}
+
+			var got latencyReport
+			decodeJSON(t, rec, &got)
+			if tt.wantRequests == 0 {
+				if len(got.Data) != 0 {
+					t.Errorf("reported %d models, want none", len(got.Data))
+				}
+				return
+			}
+			if len(got.Data) != 1 {
+				t.Fatalf("reported %d models, want 1", len(got.Data))
+			}
+			if report := got.Data[0]; report.Model != "gpt-4o-mini" || report.Requests != tt.wantRequests {
+				t.Errorf("report = %s with %d requests, want gpt-4o-mini with %d", report.Model, report.Requests, tt.wantRequests)
+			}
+			if got.Data[0].Observed.P50Ms <= 0 {
+				t.Errorf("p50 = %vms, want it positive", got.Data[0].Observed.P50Ms)
+			}
+		})
+	}
+}
+
+func TestResetLatencyReport(t *testing.T) {
+	s := newLatencyServer(t)
+	expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil), http.StatusOK)
+
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/latency/report", "", nil), http.StatusNoContent)
+
+	var got latencyReport
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/latency/report", "", nil), &got)
+	if len(got.Data) != 0 {
+		t.Errorf("after reset: %d models, want none", len(got.Data))
+	}
+
+	// starting a capture run also resets the report
+	expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil), http.StatusOK)
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/capture", `{"run_id":"run-1"}`, nil), http.StatusOK)
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/latency/report", "", nil), &got)
+	if len(got.Data) != 0 {
+		t.Errorf("after starting a run: %d models, want none", len(got.Data))
+	}
+}
+
+func TestVerifyLatency(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantParam  string
+		wantModels []string
+		wantPassed bool
+	}{
+		{name: "one model", body: `{"models":["gpt-4o-mini"],"samples":100}`, wantStatus: http.StatusOK, wantModels: []string{"gpt-4o-mini"}, wantPassed: true},
+		{name: "unknown model", body: `{"models":["gpt-2"]}`, wantStatus: http.StatusNotFound},
+		{name: "negative samples", body: `{"samples":-1}`, wantStatus: http.StatusBadRequest, wantParam: "samples"},
+		{name: "too many samples", body: `{"samples":2000000}`, wantStatus: http.StatusBadRequest, wantParam: "samples"},
+		{name: "tolerance out of range", body: `{"tolerance":2}`, wantStatus: http.StatusBadRequest, wantParam: "tolerance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLatencyServer(t)
+
+			rec := serve(s, http.MethodPost, "/_sentra/latency/verify", tt.body, nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Data   []profileCheckResponse `json:"data"`
+				Passed bool                   `json:"passed"`
+			}
+			decodeJSON(t, rec, &got)
+			var gotModels []string
+			for _, check := range got.Data {
+				gotModels = append(gotModels, check.Model)
+				if check.Samples != 100 || len(check.Percentiles) != 3 {
+					t.Errorf("%s: %d samples and %d percentiles, want 100 and 3", check.Model, check.Samples, len(check.Percentiles))
+				}
+			}
+			if len(gotModels) != len(tt.wantModels) || gotModels[0] != tt.wantModels[0] {
+				t.Errorf("models = %v, want %v", gotModels, tt.wantModels)
+			}
+			if got.Passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", got.Passed, tt.wantPassed)
+			}
+		})
+	}
+}
+
+func TestVerifyLatencyDefaultsToAllProfiles(t *testing.T) {
+	s := newLatencyServer(t)
+
+	// an empty body verifies every profile
+	rec := serve(s, http.MethodPost, "/_sentra/latency/verify", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var got struct {
+		Data []profileCheckResponse `json:"data"`
+	}
+	decodeJSON(t, rec, &got)
+	if want := len(s.latency.GetProfileRegistry().ListProfiles()); len(got.Data) != want {
+		t.Errorf("verified %d models, want %d", len(got.Data), want)
+	}
+	for i := 1; i < len(got.Data); i++ {
+		if got.Data[i-1].Model > got.Data[i].Model {
+			t.Errorf("models not sorted: %s before %s", got.Data[i-1].Model, got.Data[i].Model)
+		}
+	}
+}
+
+func TestLatencyRoutesNeedSimulator(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodGet, "/_sentra/latency/report", "", nil), http.StatusNotFound)
+}
@@ -0,0 +1,72 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the locale admin API used by the set_locale scenario
+// step to change the simulated locale, timezone and currency.
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/locale"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// localeExampleAmount is formatted in responses so the effect of the
+// settings is visible (1234.56 in two-decimal currencies).
+const localeExampleAmount = 123456
+
+// setupLocaleRoutes registers the locale admin API.
+func (s *Server) setupLocaleRoutes(admin *gin.RouterGroup) {
+	admin.GET("/locale", handleGetLocale)
+	admin.PUT("/locale", handleSetLocale)
+	admin.DELETE("/locale", handleResetLocale)
+}
+
+// localeResponse describes the simulated locale.
+type localeResponse struct {
+	locale.Settings
+
+	// LocalTime is the virtual time formatted in the locale and timezone
+	LocalTime string `json:"local_time"`
+
+	// ExampleAmount is 123456 minor units formatted in the locale and currency
+	ExampleAmount string `json:"example_amount"`
+}
+
+// newLocaleResponse describes the given settings.
+func newLocaleResponse(settings locale.Settings) localeResponse {
+	return localeResponse{
+		Settings:      settings,
+		LocalTime:     settings.FormatTime(clock.Now()),
+		ExampleAmount: settings.FormatAmount(localeExampleAmount),
+	}
+}
+
+// handleGetLocale returns the simulated locale.
+func handleGetLocale(c *gin.Context) {
+	c.JSON(http.StatusOK, newLocaleResponse(locale.Current()))
+}
+
+// handleSetLocale changes the simulated locale. Omitted fields are unchanged.
+func handleSetLocale(c *gin.Context) {
+	var req locale.Settings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	settings, err := locale.Set(req)
+	if err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, newLocaleResponse(settings))
+}
+
+// handleResetLocale returns to the startup locale.
+func handleResetLocale(c *gin.Context) {
+	c.JSON(http.StatusOK, newLocaleResponse(locale.Reset()))
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/locale"
+)
+
+func TestSetLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		status     int
+		want       locale.Settings
+		wantAmount string
+	}{
+		{
+			name:       "normalized locale and currency",
+			body:       `{"locale":"de_de","currency":"eur"}`,
+			status:     http.StatusOK,
+			want:       locale.Settings{Locale: "de-DE", Timezone: "UTC", Currency: "EUR"},
+			wantAmount: "1.234,56\u00a0€",
+		},
+		{
+			name:       "timezone only",
+			body:       `{"timezone":"Europe/Berlin"}`,
+			status:     http.StatusOK,
+			want:       locale.Settings{Locale: "en-US", Timezone: "Europe/Berlin", Currency: "USD"},
+			wantAmount: "$1,234.56",
+		},
+		{name: "invalid locale", body: `{"locale":"german"}`, status: http.StatusBadRequest},
+		{name: "invalid timezone", body: `{"timezone":"Mars/Olympus"}`, status: http.StatusBadRequest},
+		{name: "invalid currency", body: `{"currency":"EURO"}`, status: http.StatusBadRequest},
+		{name: "malformed body", body: `{`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { locale.Reset() })
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodPut, "/_sentra/locale", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := locale.Current(); got != locale.Default() {
+					t.Errorf("locale.Current() = %+v after a rejected update, want the default", got)
+				}
+				return
+			}
+
+			var resp localeResponse
+			decodeJSON(t, rec, &resp)
+			if resp.Settings != tt.want {
+				t.Errorf("settings = %+v, want %+v", resp.Settings, tt.want)
+			}
+			if resp.ExampleAmount != tt.wantAmount {
+				t.Errorf("example_amount = %q, want %q", resp.ExampleAmount, tt.wantAmount)
+			}
+			if got := locale.Current(); got != tt.want {
+				t.Errorf("locale.Current() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResetLocale(t *testing.T) {
+	t.Cleanup(func() { locale.Reset() })
+	s := newTestServer(t, Dependencies{})
+
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/locale", `{"locale":"fr-FR","currency":"EUR"}`, nil), http.StatusOK)
+
+	rec := serve(s, http.MethodDelete, "/_sentra/locale", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var resp localeResponse
+	decodeJSON(t, rec, &resp)
+	if resp.Settings != locale.Default() {
+		t.Errorf("settings = %+v after reset, want %+v", resp.Settings, locale.Default())
+	}
+}
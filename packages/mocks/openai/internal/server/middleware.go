@@ -0,0 +1,230 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements HTTP middleware shared by all API routes.
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Response headers echoing the organization/project a request was attributed to.
+// These match the lowercase headers returned by OpenAI's API.
+const (
+	// responseHeaderOrganization echoes the OpenAI-Organization request header
+	responseHeaderOrganization = "openai-organization"
+
+	// responseHeaderProject echoes the OpenAI-Project request header
+	responseHeaderProject = "openai-project"
+
+	// responseHeaderQueueTime reports how long a request waited for simulated capacity
+	responseHeaderQueueTime = "X-Sentra-Queue-Time-Ms"
+)
+
+// ScopeMiddleware parses the API key and the OpenAI-Organization and
+// OpenAI-Project headers into a models.RequestScope.
+// Malformed IDs are rejected with a 400 invalid_request_error. Valid values are
+// echoed in the response so multi-tenant clients can verify key routing.
+func ScopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope, err := models.NewRequestScope(
+			parseBearerToken(c.GetHeader("Authorization")),
+			strings.TrimSpace(c.GetHeader(models.HeaderOrganization)),
+			strings.TrimSpace(c.GetHeader(models.HeaderProject)),
+		)
+		if err != nil {
+			var apiErr models.APIError
+			if !errors.As(err, &apiErr) {
+				apiErr = models.NewServerError(err.Error())
+			}
+			abortWithError(c, apiErr)
+			return
+		}
+
+		// Echo organization/project in the response
+		if scope.HasOrganization() {
+			c.Header(responseHeaderOrganization, scope.Organization)
+		}
+		if scope.HasProject() {
+			c.Header(responseHeaderProject, scope.Project)
+		}
+
+		c.Request = c.Request.WithContext(WithScope(c.Request.Context(), scope))
+		c.Next()
+	}
+}
+
+// LimitsConfig configures request size and timeout enforcement.
+type LimitsConfig struct {
+	// MaxBodyBytes is the maximum request body size (0 disables the check)
+	MaxBodyBytes int64
+
+	// RequestTimeout is the server-side timeout for a request (0 disables the timeout)
+	RequestTimeout time.Duration
+}
+
+// DefaultLimitsConfig returns limits matching OpenAI's production API.
+func DefaultLimitsConfig() LimitsConfig {
+	return LimitsConfig{
+		MaxBodyBytes:   models.MaxRequestBodyBytes,
+		RequestTimeout: models.DefaultRequestTimeout,
+	}
+}
+
+// LimitsMiddleware enforces the maximum body size and the per-request timeout.
+// Oversized bodies are rejected with 413 before the handler runs when
+// Content-Length is known; otherwise reads fail once the limit is crossed
+// (see IsBodyTooLarge). Requests still running when the timeout fires are
+// aborted with a 504 timeout error if no response has been written yet.
+func LimitsMiddleware(config LimitsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if config.MaxBodyBytes > 0 {
+			if c.Request.ContentLength > config.MaxBodyBytes {
+				abortWithError(c, models.NewRequestTooLargeError(config.MaxBodyBytes))
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxBodyBytes)
+		}
+
+		if config.RequestTimeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), config.RequestTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			abortWithError(c, models.NewTimeoutError(config.RequestTimeout))
+		}
+	}
+}
+
+// IsBodyTooLarge returns true if err was caused by reading past the body size limit.
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// DrainMiddleware rejects new requests once the server is shutting down.
+// http.Server.Shutdown stops the listener, but a request can still arrive on
+// a kept-alive connection; it gets a 503 and "Connection: close" so the
+// client retries against another replica.
+func DrainMiddleware(s *Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.IsDraining() {
+			c.Header("Connection", "close")
+			abortWithError(c, models.NewServiceUnavailableError("The server is shutting down. Please retry your request.", 1))
+			return
+		}
+		c.Next()
+	}
+}
+
+// ServiceTierConfig configures how requests are mapped to service tiers.
+type ServiceTierConfig struct {
+	// KeyTiers maps API keys to service tiers (the X-Sentra-Service-Tier header takes precedence)
+	KeyTiers map[string]models.ServiceTier
+
+	// DefaultTier is used when neither the header nor the API key selects a tier
+	DefaultTier models.ServiceTier
+}
+
+// DefaultServiceTierConfig returns default service tier configuration.
+func DefaultServiceTierConfig() ServiceTierConfig {
+	return ServiceTierConfig{
+		KeyTiers:    make(map[string]models.ServiceTier),
+		DefaultTier: models.ServiceTierDefault,
+	}
+}
+
+// ServiceTierMiddleware assigns each request a service tier from the
+// X-Sentra-Service-Tier header or the API key's configured tier, and, when a
+// scheduler is given, waits for a simulated processing slot in priority order.
+// Requests that can't be scheduled in time are rejected: flex requests with
+// 429 resource_unavailable, other tiers with 503. Must run after ScopeMiddleware.
+func ServiceTierMiddleware(config ServiceTierConfig, scheduler *behavior.PriorityScheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tier, err := models.ParseServiceTier(c.GetHeader(models.HeaderServiceTier))
+		if err != nil {
+			var apiErr models.APIError
+			if !errors.As(err, &apiErr) {
+				apiErr = models.NewServerError(err.Error())
+			}
+			abortWithError(c, apiErr)
+			return
+		}
+		if tier == models.ServiceTierAuto {
+			if keyTier, ok := config.KeyTiers[GetScope(c).APIKey]; ok {
+				tier = keyTier
+			} else {
+				tier = config.DefaultTier
+			}
+		}
+		tier = tier.Resolve()
+
+		c.Header(models.HeaderServiceTier, string(tier))
+		c.Request = c.Request.WithContext(WithServiceTier(c.Request.Context(), tier))
+
+		if scheduler == nil {
+			c.Next()
+			return
+		}
+
+		release, wait, err := scheduler.Acquire(c.Request.Context(), tier)
+		if err != nil {
+			if !errors.Is(err, behavior.ErrQueueTimeout) && !errors.Is(err, behavior.ErrQueueFull) {
+				// Client went away or the request timed out (handled by LimitsMiddleware)
+				c.Abort()
+				return
+			}
+			if tier == models.ServiceTierFlex {
+				abortWithError(c, models.NewResourceUnavailableError(time.Second))
+			} else {
+				abortWithError(c, models.NewServiceUnavailableError("The server is currently overloaded with other requests. Please retry your request.", 1))
+			}
+			return
+		}
+		defer release()
+
+		c.Header(responseHeaderQueueTime, strconv.FormatInt(wait.Milliseconds(), 10))
+		c.Next()
+	}
+}
+
+// parseBearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func parseBearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// abortWithError writes an OpenAI-formatted error response and aborts the request.
+func abortWithError(c *gin.Context, apiErr models.APIError) {
+	metrics.LogError(c.Request.Context(), apiErr, GetRequestID(c))
+	c.AbortWithStatusJSON(apiErr.StatusCode, models.ErrorResponse{Error: apiErr})
+}
+
+// abortWithAPIError aborts with err, reported as a bad request unless it is
+// already an APIError (e.g., a plain validation error).
+func abortWithAPIError(c *gin.Context, err error) {
+	var apiErr models.APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = models.NewBadRequestError(err.Error(), nil)
+	}
+	abortWithError(c, apiErr)
+}
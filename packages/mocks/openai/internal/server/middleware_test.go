@@ -0,0 +1,242 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestScopeMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantStatus  int
+		wantParam   string
+		wantOrg     string
+		wantProject string
+	}{
+		{name: "no scope", wantStatus: http.StatusOK},
+		{
+			name:        "organization and project echoed",
+			headers:     map[string]string{models.HeaderOrganization: "org-acme", models.HeaderProject: " proj_web "},
+			wantStatus:  http.StatusOK,
+			wantOrg:     "org-acme",
+			wantProject: "proj_web",
+		},
+		{
+			name:       "invalid organization",
+			headers:    map[string]string{models.HeaderOrganization: "acme"},
+			wantStatus: http.StatusBadRequest,
+			wantParam:  models.HeaderOrganization,
+		},
+		{
+			name:       "invalid project",
+			headers:    map[string]string{models.HeaderProject: "web"},
+			wantStatus: http.StatusBadRequest,
+			wantParam:  models.HeaderProject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", chatBody, tt.headers)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+			}
+			if got := rec.Header().Get(responseHeaderOrganization); got != tt.wantOrg {
+				t.Errorf("%s = %q, want %q", responseHeaderOrganization, got, tt.wantOrg)
+			}
+			if got := rec.Header().Get(responseHeaderProject); got != tt.wantProject {
+				t.Errorf("%s = %q, want %q", responseHeaderProject, got, tt.wantProject)
+			}
+		})
+	}
+}
+
+func TestLimitsMiddleware(t *testing.T) {
+	large := `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"` + strings.Repeat("a", 256) + `"}]}`
+
+	tests := []struct {
+		name string
+		body string
+		// chunked hides the body's length from the server
+		chunked    bool
+		slow       bool
+		wantStatus int
+	}{
+		{name: "within limits", body: chatBody, wantStatus: http.StatusOK},
+		{name: "body too large", body: large, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "chunked body too large", body: large, chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "timeout", body: chatBody, slow: true, wantStatus: http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Limits = LimitsConfig{MaxBodyBytes: 256, RequestTimeout: 50 * time.Millisecond}
+			deps := Dependencies{Fixtures: newGenericFixtures(t, 5)}
+			if tt.slow {
+				simulatorConfig := latency.DefaultSimulatorConfig()
+				simulatorConfig.EnableJitter = false
+				simulatorConfig.EnableLoadSimulation = false
+				deps.Latency = latency.NewSimulator(simulatorConfig)
+				deps.Latency.GetProfileRegistry().SetProfile("gpt-4o-mini", latency.Profile{
+					ModelID:     "gpt-4o-mini",
+					BaseLatency: time.Second,
+					MaxLatency:  time.Second,
+				})
+			}
+			s := New(config, deps)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer sk-test")
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			s.Engine().ServeHTTP(rec, req)
+
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.slow && time.Since(start) >= time.Second {
+				t.Errorf("request took %v, want it cut short by the timeout", time.Since(start))
+			}
+		})
+	}
+}
+
+func TestDrainMiddleware(t *testing.T) {
+	s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+	expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil), http.StatusOK)
+
+	s.draining.Store(true)
+	rec := serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil)
+	expectStatus(t, rec, http.StatusServiceUnavailable)
+	if got := rec.Header().Get("Connection"); got != "close" {
+		t.Errorf("Connection = %q, want close", got)
+	}
+	expectStatus(t, serve(s, http.MethodGet, "/health", "", nil), http.StatusServiceUnavailable)
+}
+
+func TestServiceTierMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		header     string
+		wantStatus int
+		wantTier   models.ServiceTier
+	}{
+		{name: "default", apiKey: "sk-test", wantStatus: http.StatusOK, wantTier: models.ServiceTierDefault},
+		{name: "header", apiKey: "sk-test", header: "priority", wantStatus: http.StatusOK, wantTier: models.ServiceTierPriority},
+		{name: "key tier", apiKey: "sk-batch", wantStatus: http.StatusOK, wantTier: models.ServiceTierFlex},
+		{name: "auto uses the key tier", apiKey: "sk-batch", header: "auto", wantStatus: http.StatusOK, wantTier: models.ServiceTierFlex},
+		{name: "header over key tier", apiKey: "sk-batch", header: "scale", wantStatus: http.StatusOK, wantTier: models.ServiceTierScale},
+		{name: "invalid header", apiKey: "sk-test", header: "turbo", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.ServiceTiers.KeyTiers = map[string]models.ServiceTier{"sk-batch": models.ServiceTierFlex}
+			s := New(config, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+
+			headers := map[string]string{"Authorization": "Bearer " + tt.apiKey}
+			if tt.header != "" {
+				headers[models.HeaderServiceTier] = tt.header
+			}
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", chatBody, headers)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if got := errorParam(t, rec); got != "service_tier" {
+					t.Errorf("param = %q, want %q", got, "service_tier")
+				}
+				return
+			}
+			if got := rec.Header().Get(models.HeaderServiceTier); got != string(tt.wantTier) {
+				t.Errorf("%s = %q, want %q", models.HeaderServiceTier, got, tt.wantTier)
+			}
+		})
+	}
+}
+
+func TestServiceTierQueueing(t *testing.T) {
+	tests := []struct {
+		name       string
+		tier       models.ServiceTier
+		busy       bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "free slot", tier: models.ServiceTierFlex, wantStatus: http.StatusOK},
+		{name: "flex times out", tier: models.ServiceTierFlex, busy: true, wantStatus: http.StatusTooManyRequests, wantCode: models.ErrorCodeResourceUnavailable},
+		{name: "default times out", tier: models.ServiceTierDefault, busy: true, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := behavior.NewPriorityScheduler(behavior.SchedulerConfig{
+				Capacity: 1,
+				QueueTimeouts: map[models.ServiceTier]time.Duration{
+					models.ServiceTierFlex:    10 * time.Millisecond,
+					models.ServiceTierDefault: 10 * time.Millisecond,
+				},
+			})
+			s := newTestServer(t, Dependencies{Scheduler: scheduler, Fixtures: newGenericFixtures(t, 5)})
+
+			if tt.busy {
+				release, _, err := scheduler.Acquire(context.Background(), models.ServiceTierPriority)
+				if err != nil {
+					t.Fatalf("Acquire() error = %v", err)
+				}
+				defer release()
+			}
+
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", chatBody, map[string]string{models.HeaderServiceTier: string(tt.tier)})
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus == http.StatusOK && rec.Header().Get(responseHeaderQueueTime) == "" {
+				t.Errorf("%s is not set", responseHeaderQueueTime)
+			}
+			if tt.wantCode != "" {
+				var got models.ErrorResponse
+				decodeJSON(t, rec, &got)
+				if got.Error.Code == nil || *got.Error.Code != tt.wantCode {
+					t.Errorf("code = %v, want %q", got.Error.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "Bearer sk-test", want: "sk-test"},
+		{header: "bearer  sk-test ", want: "sk-test"},
+		{header: "Basic dXNlcjpwYXNz", want: ""},
+		{header: "Bearer", want: ""},
+		{header: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := parseBearerToken(tt.header); got != tt.want {
+				t.Errorf("parseBearerToken(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file publishes an OpenAPI 3.1 spec of the routes the server actually
+// registered, generated from the request and response models, for contract
+// testing and typed client generation.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/openapi"
+)
+
+// bucketQuery are the query parameters of the organization usage endpoints
+var bucketQuery = []string{"start_time", "end_time", "bucket_width", "limit", "page", "group_by", "project_ids"}
+
+// threadListQuery are the query parameters of the thread list endpoints
+var threadListQuery = []string{"limit", "order"}
+
+// assistantDeleted is the response of DELETE /v1/assistants/:assistant_id.
+type assistantDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// threadDeleted is the response of DELETE /v1/threads/:thread_id.
+type threadDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// fileDeleted is the response of DELETE /v1/files/:file_id.
+type fileDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// assistantList is a page of GET /v1/assistants.
+type assistantList struct {
+	Object  string             `json:"object"`
+	Data    []models.Assistant `json:"data"`
+	FirstID *string            `json:"first_id"`
+	LastID  *string            `json:"last_id"`
+	HasMore bool               `json:"has_more"`
+}
+
+// threadMessageList is a page of GET /v1/threads/:thread_id/messages.
+type threadMessageList struct {
+	Object  string                 `json:"object"`
+	Data    []models.ThreadMessage `json:"data"`
+	FirstID *string                `json:"first_id"`
+	LastID  *string                `json:"last_id"`
+	HasMore bool                   `json:"has_more"`
+}
+
+// runList is a page of GET /v1/threads/:thread_id/runs.
+type runList struct {
+	Object  string       `json:"object"`
+	Data    []models.Run `json:"data"`
+	FirstID *string      `json:"first_id"`
+	LastID  *string      `json:"last_id"`
+	HasMore bool         `json:"has_more"`
+}
+
+// fileList is a page of GET /v1/files.
+type fileList struct {
+	Object  string        `json:"object"`
+	Data    []models.File `json:"data"`
+	FirstID *string       `json:"first_id"`
+	LastID  *string       `json:"last_id"`
+	HasMore bool          `json:"has_more"`
+}
+
+// fineTuningJobList is a page of GET /v1/fine_tuning/jobs.
+type fineTuningJobList struct {
+	Object  string                 `json:"object"`
+	Data    []models.FineTuningJob `json:"data"`
+	FirstID *string                `json:"first_id"`
+	LastID  *string                `json:"last_id"`
+	HasMore bool                   `json:"has_more"`
+}
+
+// fineTuningEventList is a page of GET /v1/fine_tuning/jobs/:job_id/events.
+type fineTuningEventList struct {
+	Object  string                   `json:"object"`
+	Data    []models.FineTuningEvent `json:"data"`
+	FirstID *string                  `json:"first_id"`
+	LastID  *string                  `json:"last_id"`
+	HasMore bool                     `json:"has_more"`
+}
+
+// vectorStoreList is a page of GET /v1/vector_stores.
+type vectorStoreList struct {
+	Object  string               `json:"object"`
+	Data    []models.VectorStore `json:"data"`
+	FirstID *string              `json:"first_id"`
+	LastID  *string              `json:"last_id"`
+	HasMore bool                 `json:"has_more"`
+}
+
+// vectorStoreFileList is a page of GET /v1/vector_stores/:vector_store_id/files.
+type vectorStoreFileList struct {
+	Object  string                   `json:"object"`
+	Data    []models.VectorStoreFile `json:"data"`
+	FirstID *string                  `json:"first_id"`
+	LastID  *string                  `json:"last_id"`
+	HasMore bool                     `json:"has_more"`
+}
+
+// apiOperations documents the API routes' models, keyed by "METHOD path".
+// Registered routes without an entry are listed without schemas.
+var apiOperations = map[string]openapi.Operation{
+	"POST /v1/chat/completions": {
+		Summary:   "Create a chat completion",
+		Request:   models.ChatCompletionRequest{},
+		Response:  models.ChatCompletionResponse{},
+		Streaming: true,
+	},
+	"POST /v1/completions": {
+		Summary:   "Create a completion (legacy)",
+		Request:   models.CompletionRequest{},
+		Response:  models.CompletionResponse{},
+		Streaming: true,
+	},
+	"POST /v1/embeddings": {
+		Summary:  "Create embeddings",
+		Request:  models.EmbeddingRequest{},
+		Response: models.EmbeddingResponse{},
+	},
+	"GET /v1/models": {
+		Summary:  "List models",
+		Response: models.ModelsResponse{},
+	},
+	"GET /v1/models/:model": {
+		Summary:  "Retrieve a model",
+		Response: models.Model{},
+	},
+	"POST /v1/images/generations": {
+		Summary:  "Create images",
+		Request:  models.ImageGenerationRequest{},
+		Response: models.ImageResponse{},
+	},
+	"POST /v1/audio/speech": {
+		Summary: "Create speech (returns audio bytes)",
+		Request: models.SpeechRequest{},
+	},
+	"GET /v1/realtime": {
+		Summary: "Open a Realtime session (WebSocket upgrade)",
+		Query:   []string{"model"},
+	},
+	"POST /v1/images/edits": {
+		Summary:  "Edit an image",
+		Request:  models.ImageEditRequest{},
+		Files:    []string{"image", "mask"},
+		Response: models.ImageResponse{},
+	},
+	"POST /v1/images/variations": {
+		Summary:  "Create image variations",
+		Request:  models.ImageVariationRequest{},
+		Files:    []string{"image"},
+		Response: models.ImageResponse{},
+	},
+	"GET /images/:image_id": {
+		Summary: "Download a generated image (the URLs in image responses)",
+	},
+	"POST /v1/assistants": {
+		Summary:  "Create an assistant",
+		Request:  models.CreateAssistantRequest{},
+		Response: models.Assistant{},
+	},
+	"GET /v1/assistants": {
+		Summary:  "List assistants",
+		Query:    threadListQuery,
+		Response: assistantList{},
+	},
+	"GET /v1/assistants/:assistant_id": {
+		Summary:  "Retrieve an assistant",
+		Response: models.Assistant{},
+	},
+	"POST /v1/assistants/:assistant_id": {
+		Summary:  "Modify an assistant",
+		Request:  models.ModifyAssistantRequest{},
+		Response: models.Assistant{},
+	},
+	"DELETE /v1/assistants/:assistant_id": {
+		Summary:  "Delete an assistant",
+		Response: assistantDeleted{},
+	},
+	"POST /v1/threads": {
+		Summary:  "Create a thread",
+		Request:  models.CreateThreadRequest{},
+		Response: models.Thread{},
+	},
+	"GET /v1/threads/:thread_id": {
+		Summary:  "Retrieve a thread",
+		Response: models.Thread{},
+	},
+	"DELETE /v1/threads/:thread_id": {
+		Summary:  "Delete a thread",
+		Response: threadDeleted{},
+	},
+	"POST /v1/threads/:thread_id/messages": {
+		Summary:  "Add a message to a thread",
+		Request:  models.ThreadMessageRequest{},
+		Response: models.ThreadMessage{},
+	},
+	"GET /v1/threads/:thread_id/messages": {
+		Summary:  "List a thread's messages",
+		Query:    threadListQuery,
+		Response: threadMessageList{},
+	},
+	"POST /v1/threads/:thread_id/runs": {
+		Summary:  "Run a thread",
+		Request:  models.CreateRunRequest{},
+		Response: models.Run{},
+	},
+	"GET /v1/threads/:thread_id/runs": {
+		Summary:  "List a thread's runs",
+		Query:    threadListQuery,
+		Response: runList{},
+	},
+	"GET /v1/threads/:thread_id/runs/:run_id": {
+		Summary:  "Retrieve a run",
+		Response: models.Run{},
+	},
+	"POST /v1/threads/:thread_id/runs/:run_id/submit_tool_outputs": {
+		Summary:  "Submit the outputs of a run's tool calls",
+		Request:  models.SubmitToolOutputsRequest{},
+		Response: models.Run{},
+	},
+	"POST /v1/threads/:thread_id/runs/:run_id/cancel": {
+		Summary:  "Cancel a run awaiting tool outputs",
+		Response: models.Run{},
+	},
+	"POST /v1/files": {
+		Summary:  "Upload a file",
+		Request:  models.FileUploadRequest{},
+		Files:    []string{"file"},
+		Response: models.File{},
+	},
+	"GET /v1/files": {
+		Summary:  "List files",
+		Query:    append([]string{"purpose"}, threadListQuery...),
+		Response: fileList{},
+	},
+	"GET /v1/files/:file_id": {
+		Summary:  "Retrieve a file",
+		Response: models.File{},
+	},
+	"GET /v1/files/:file_id/content": {
+		Summary: "Retrieve a file's content",
+	},
+	"DELETE /v1/files/:file_id": {
+		Summary:  "Delete a file",
+		Response: fileDeleted{},
+	},
+	"POST /v1/fine_tuning/jobs": {
+		Summary:  "Create a fine-tuning job",
+		Request:  models.CreateFineTuningJobRequest{},
+		Response: models.FineTuningJob{},
+	},
+	"GET /v1/fine_tuning/jobs": {
+		Summary:  "List fine-tuning jobs",
+		Query:    threadListQuery,
+		Response: fineTuningJobList{},
+	},
+	"GET /v1/fine_tuning/jobs/:job_id": {
+		Summary:  "Retrieve a fine-tuning job",
+		Response: models.FineTuningJob{},
+	},
+	"POST /v1/fine_tuning/jobs/:job_id/cancel": {
+		Summary:  "Cancel a fine-tuning job",
+		Response: models.FineTuningJob{},
+	},
+	"GET /v1/fine_tuning/jobs/:job_id/events": {
+		Summary:  "List fine-tuning job events",
+		Query:    threadListQuery,
+		Response: fineTuningEventList{},
+	},
+	"GET /v1/vector_stores": {
+		Summary:  "List vector stores",
+		Query:    threadListQuery,
+		Response: vectorStoreList{},
+	},
+	"GET /v1/vector_stores/:vector_store_id": {
+		Summary:  "Retrieve a vector store",
+		Response: models.VectorStore{},
+	},
+	"GET /v1/vector_stores/:vector_store_id/files": {
+		Summary:  "List a vector store's files",
+		Query:    threadListQuery,
+		Response: vectorStoreFileList{},
+	},
+	"POST /v1/vector_stores/:vector_store_id/search": {
+		Summary:  "Search a vector store",
+		Request:  models.VectorStoreSearchRequest{},
+		Response: models.VectorStoreSearchResponse{},
+	},
+	"GET /v1/organization/usage/completions": {
+		Summary:  "Completions usage, bucketed by day",
+		Query:    append(append([]string{}, bucketQuery...), "api_key_ids", "models"),
+		Response: models.CompletionsUsagePage{},
+	},
+	"GET /v1/organization/costs": {
+		Summary:  "Costs, bucketed by day",
+		Query:    bucketQuery,
+		Response: models.CostsPage{},
+	},
+	"GET /v1/usage": {
+		Summary:  "Usage for a day (legacy)",
+		Query:    []string{"date"},
+		Response: models.LegacyUsageResponse{},
+	},
+}
+
+// setupOpenAPIRoutes registers the spec endpoint.
+func (s *Server) setupOpenAPIRoutes(admin *gin.RouterGroup) {
+	admin.GET("/openapi", s.handleGetOpenAPI)
+}
+
+// OpenAPI returns the spec of the routes this server registered: the
+// OpenAI-compatible API and, when includeAdmin is set, the /_sentra admin
+// API (whose endpoints are listed without schemas).
+func (s *Server) OpenAPI(includeAdmin bool) *openapi.Document {
+	gen := openapi.NewGenerator(
+		openapi.Info{
+			Title:       "Sentra Lab OpenAI mock",
+			Version:     "v1",
+			Description: "The subset of the OpenAI API the Sentra Lab mock implements, generated from its request and response models.",
+		},
+		openapi.Server{URL: fmt.Sprintf("http://localhost:%d", s.config.Port), Description: "Local mock"},
+	)
+	gen.Tag("openai", "OpenAI-compatible API")
+	if includeAdmin {
+		gen.Tag("sentra", "Sentra Lab admin API (not part of the OpenAI API)")
+	}
+	gen.Schema(models.ErrorResponse{})
+
+	routes := s.engine.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	for _, route := range routes {
+		tag := "openai"
+		switch {
+		case route.Path == "/health" || route.Path == "/metrics":
+			continue
+		case strings.HasPrefix(route.Path, adminPrefix+"/") || route.Path == capabilitiesPath:
+			if !includeAdmin {
+				continue
+			}
+			tag = "sentra"
+		}
+
+		op := apiOperations[route.Method+" "+route.Path]
+		op.Method = route.Method
+		op.Path = route.Path
+		op.Tag = tag
+		gen.Add(op)
+	}
+
+	return gen.Document()
+}
+
+// handleGetOpenAPI returns the spec as YAML, or JSON with ?format=json.
+// ?admin=true includes the admin API.
+func (s *Server) handleGetOpenAPI(c *gin.Context) {
+	doc := s.OpenAPI(c.Query("admin") == "true")
+
+	switch format := c.DefaultQuery("format", "yaml"); format {
+	case "json":
+		c.JSON(http.StatusOK, doc)
+	case "yaml":
+		data, err := doc.YAML()
+		if err != nil {
+			abortWithError(c, models.NewServerError(err.Error()))
+			return
+		}
+		c.Data(http.StatusOK, "application/yaml", data)
+	default:
+		param := "format"
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("format must be yaml or json, got %q", format), &param))
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentra-lab/mocks/openai/internal/openapi"
+)
+
+func TestOpenAPI(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+
+	tests := []struct {
+		name         string
+		includeAdmin bool
+		wantPaths    []string
+		wantAbsent   []string
+	}{
+		{
+			name:       "API only",
+			wantPaths:  []string{"/v1/chat/completions", "/v1/files/{file_id}", "/v1/threads/{thread_id}/runs/{run_id}"},
+			wantAbsent: []string{"/health", "/metrics", adminPrefix + "/openapi", capabilitiesPath},
+		},
+		{
+			name:         "with the admin API",
+			includeAdmin: true,
+			wantPaths:    []string{"/v1/chat/completions", adminPrefix + "/openapi", capabilitiesPath},
+			wantAbsent:   []string{"/health", "/metrics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := s.OpenAPI(tt.includeAdmin)
+			for _, path := range tt.wantPaths {
+				if doc.Paths[path] == nil {
+					t.Errorf("OpenAPI(%v) is missing %s", tt.includeAdmin, path)
+				}
+			}
+			for _, path := range tt.wantAbsent {
+				if doc.Paths[path] != nil {
+					t.Errorf("OpenAPI(%v) lists %s", tt.includeAdmin, path)
+				}
+			}
+			if _, ok := doc.Components.Schemas["ErrorResponse"]; !ok {
+				t.Error("OpenAPI() is missing the ErrorResponse schema")
+			}
+		})
+	}
+}
+
+func TestOpenAPIOperations(t *testing.T) {
+	doc := newTestServer(t, Dependencies{}).OpenAPI(true)
+
+	tests := []struct {
+		name        string
+		path        string
+		method      string
+		wantTag     string
+		wantRequest bool
+		wantParams  []string
+	}{
+		{name: "documented API route", path: "/v1/chat/completions", method: "post", wantTag: "openai", wantRequest: true},
+		{name: "path parameters", path: "/v1/threads/{thread_id}/runs/{run_id}", method: "get", wantTag: "openai", wantParams: []string{"thread_id", "run_id"}},
+		{name: "query parameters", path: "/v1/files", method: "get", wantTag: "openai", wantParams: []string{"purpose", "limit", "order"}},
+		{name: "admin route", path: adminPrefix + "/openapi", method: "get", wantTag: "sentra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := doc.Paths[tt.path][tt.method]
+			if item == nil {
+				t.Fatalf("%s %s is not documented", tt.method, tt.path)
+			}
+			if len(item.Tags) != 1 || item.Tags[0] != tt.wantTag {
+				t.Errorf("Tags = %v, want [%s]", item.Tags, tt.wantTag)
+			}
+			if (item.RequestBody != nil) != tt.wantRequest {
+				t.Errorf("RequestBody = %v, want request body %v", item.RequestBody, tt.wantRequest)
+			}
+
+			var params []string
+			for _, p := range item.Parameters {
+				params = append(params, p.Name)
+			}
+			if strings.Join(params, ",") != strings.Join(tt.wantParams, ",") {
+				t.Errorf("Parameters = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestHandleGetOpenAPI(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+
+	tests := []struct {
+		name            string
+		query           string
+		wantStatus      int
+		wantContentType string
+		wantAdmin       bool
+	}{
+		{name: "YAML by default", wantStatus: http.StatusOK, wantContentType: "application/yaml"},
+		{name: "JSON", query: "?format=json", wantStatus: http.StatusOK, wantContentType: "application/json"},
+		{name: "with the admin API", query: "?format=json&admin=true", wantStatus: http.StatusOK, wantContentType: "application/json", wantAdmin: true},
+		{name: "unknown format", query: "?format=xml", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, adminPrefix+"/openapi"+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if param := errorParam(t, rec); param != "format" {
+					t.Errorf("error param = %q, want format", param)
+				}
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantContentType) {
+				t.Errorf("Content-Type = %q, want %s", got, tt.wantContentType)
+			}
+
+			var doc openapi.Document
+			if tt.wantContentType == "application/yaml" {
+				if err := yaml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+					t.Fatalf("decode YAML spec: %v", err)
+				}
+			} else {
+				decodeJSON(t, rec, &doc)
+			}
+			if doc.Paths["/v1/chat/completions"] == nil {
+				t.Error("spec is missing /v1/chat/completions")
+			}
+			if got := doc.Paths[adminPrefix+"/openapi"] != nil; got != tt.wantAdmin {
+				t.Errorf("spec lists the admin API = %v, want %v", got, tt.wantAdmin)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements scenario-scoped configuration overrides: a scenario
+// applies overrides when it starts and reverts them when it ends, instead of
+// editing the global mock configuration.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// MockOverrides are configuration overrides applied for one scenario.
+// Nil fields are left unchanged.
+type MockOverrides struct {
+	// ErrorRate replaces the error injector's base error rate (0.0 to 1.0)
+	ErrorRate *float64 `json:"error_rate,omitempty"`
+
+	// RateLimit overrides rate limiting
+	RateLimit *RateLimitOverride `json:"rate_limit,omitempty"`
+}
+
+// RateLimitOverride overrides rate limiting.
+type RateLimitOverride struct {
+	// Tier replaces the default rate limit tier (e.g., "free", "tier1")
+	Tier string `json:"tier"`
+}
+
+// overrideState tracks active overrides and the configuration they replaced.
+type overrideState struct {
+	// active are the overrides currently applied (nil if none)
+	active *MockOverrides
+
+	// previousErrorRate is the base error rate before overrides
+	previousErrorRate float64
+
+	// previousErrorsEnabled is whether error injection was enabled before overrides
+	previousErrorsEnabled bool
+
+	// previousTier is the default rate limit tier before overrides
+	previousTier string
+
+	// mu serializes apply and revert
+	mu sync.Mutex
+}
+
+// setupOverrideRoutes registers the overrides admin API.
+func (s *Server) setupOverrideRoutes(admin *gin.RouterGroup) {
+	if s.errorInjector == nil && s.limiter == nil {
+		return
+	}
+
+	admin.GET("/overrides", s.handleGetOverrides)
+	admin.PUT("/overrides", s.handleApplyOverrides)
+	admin.DELETE("/overrides", s.handleRevertOverrides)
+}
+
+// handleGetOverrides returns the active overrides.
+func (s *Server) handleGetOverrides(c *gin.Context) {
+	s.overrides.mu.Lock()
+	defer s.overrides.mu.Unlock()
+
+	active := s.overrides.active
+	if active == nil {
+		active = &MockOverrides{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active":    s.overrides.active != nil,
+		"overrides": active,
+	})
+}
+
+// handleApplyOverrides applies overrides. Unknown fields are rejected so a
+// typo in a scenario fails loudly instead of silently testing the defaults.
+// Applying again replaces the active overrides; revert always restores the
+// configuration from before the first apply.
+func (s *Server) handleApplyOverrides(c *gin.Context) {
+	var overrides MockOverrides
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&overrides); err != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Invalid overrides: %v", err), nil))
+		return
+	}
+
+	if err := s.validateOverrides(overrides); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	s.overrides.mu.Lock()
+	defer s.overrides.mu.Unlock()
+
+	// Snapshot the configuration before the first apply, and start each
+	// apply from it so fields dropped from a re-apply are reverted
+	if s.overrides.active == nil {
+		if s.errorInjector != nil {
+			s.overrides.previousErrorRate = s.errorInjector.GetBaseErrorRate()
+			s.overrides.previousErrorsEnabled = s.errorInjector.IsEnabled()
+		}
+		if s.limiter != nil {
+			s.overrides.previousTier = s.limiter.GetDefaultTier()
+		}
+	} else {
+		s.restoreOverridden()
+	}
+
+	// The tier is applied first: it is the only override that can fail, and
+	// on failure the configuration is left as it was before the first apply
+	if overrides.RateLimit != nil {
+		if err := s.limiter.SetDefaultTier(overrides.RateLimit.Tier); err != nil {
+			s.overrides.active = nil
+			abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+			return
+		}
+	}
+	if overrides.ErrorRate != nil {
+		s.errorInjector.SetBaseErrorRate(*overrides.ErrorRate)
+		s.errorInjector.Enable()
+	}
+
+	s.overrides.active = &overrides
+
+	c.JSON(http.StatusOK, gin.H{
+		"active":    true,
+		"overrides": overrides,
+	})
+}
+
+// handleRevertOverrides restores the configuration from before the overrides.
+func (s *Server) handleRevertOverrides(c *gin.Context) {
+	s.overrides.mu.Lock()
+	defer s.overrides.mu.Unlock()
+
+	if s.overrides.active != nil {
+		s.restoreOverridden()
+		s.overrides.active = nil
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// validateOverrides checks that overrides are in range and that the
+// components they target are available.
+func (s *Server) validateOverrides(overrides MockOverrides) error {
+	if overrides.ErrorRate != nil {
+		if s.errorInjector == nil {
+			return fmt.Errorf("error_rate: error injection is not available")
+		}
+		if *overrides.ErrorRate < 0 || *overrides.ErrorRate > 1 {
+			return fmt.Errorf("error_rate must be between 0 and 1")
+		}
+	}
+	if overrides.RateLimit != nil {
+		if s.limiter == nil {
+			return fmt.Errorf("rate_limit: rate limiting is not available")
+		}
+		if overrides.RateLimit.Tier == "" {
+			return fmt.Errorf("rate_limit.tier is required")
+		}
+	}
+	return nil
+}
+
+// restoreOverridden restores every field the active overrides replaced.
+// Must be called with overrides.mu held.
+func (s *Server) restoreOverridden() {
+	active := s.overrides.active
+	if active == nil {
+		return
+	}
+
+	if active.ErrorRate != nil {
+		s.errorInjector.SetBaseErrorRate(s.overrides.previousErrorRate)
+		if !s.overrides.previousErrorsEnabled {
+			s.errorInjector.Disable()
+		}
+	}
+	if active.RateLimit != nil {
+		// The previous tier was valid when captured, so this cannot fail
+		_ = s.limiter.SetDefaultTier(s.overrides.previousTier)
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+)
+
+// newOverridableServer creates a server with error injection disabled at a
+// 0.5% base rate and the free rate limit tier.
+func newOverridableServer(t *testing.T) *Server {
+	t.Helper()
+	config := behavior.DefaultErrorInjectorConfig()
+	config.Enabled = false
+	return newTestServer(t, Dependencies{
+		ErrorInjector: behavior.NewErrorInjector(config),
+		Limiter: ratelimit.NewLimiter(ratelimit.LimiterConfig{
+			Enabled:      true,
+			TierRegistry: ratelimit.NewTierRegistry("free"),
+			DefaultTier:  "free",
+		}),
+	})
+}
+
+func TestApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantRate    float64
+		wantEnabled bool
+		wantTier    string
+		wantActive  bool
+	}{
+		{name: "error rate", body: `{"error_rate":0.5}`, wantStatus: http.StatusOK, wantRate: 0.5, wantEnabled: true, wantTier: "free", wantActive: true},
+		{name: "rate limit tier", body: `{"rate_limit":{"tier":"tier1"}}`, wantStatus: http.StatusOK, wantRate: 0.005, wantTier: "tier1", wantActive: true},
+		{name: "both", body: `{"error_rate":1,"rate_limit":{"tier":"tier2"}}`, wantStatus: http.StatusOK, wantRate: 1, wantEnabled: true, wantTier: "tier2", wantActive: true},
+		{name: "unknown field", body: `{"error_rate":0.5,"latency":2}`, wantStatus: http.StatusBadRequest, wantRate: 0.005, wantTier: "free"},
+		{name: "error rate out of range", body: `{"error_rate":1.5}`, wantStatus: http.StatusBadRequest, wantRate: 0.005, wantTier: "free"},
+		{name: "missing tier", body: `{"rate_limit":{}}`, wantStatus: http.StatusBadRequest, wantRate: 0.005, wantTier: "free"},
+		{name: "unknown tier", body: `{"error_rate":0.5,"rate_limit":{"tier":"platinum"}}`, wantStatus: http.StatusBadRequest, wantRate: 0.005, wantTier: "free"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newOverridableServer(t)
+
+			expectStatus(t, serve(s, http.MethodPut, "/_sentra/overrides", tt.body, nil), tt.wantStatus)
+			expectOverridden(t, s, tt.wantRate, tt.wantEnabled, tt.wantTier)
+
+			var got struct {
+				Active bool `json:"active"`
+			}
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/overrides", "", nil), &got)
+			if got.Active != tt.wantActive {
+				t.Errorf("active = %v, want %v", got.Active, tt.wantActive)
+			}
+
+			expectStatus(t, serve(s, http.MethodDelete, "/_sentra/overrides", "", nil), http.StatusNoContent)
+			expectOverridden(t, s, 0.005, false, "free")
+		})
+	}
+}
+
+func TestReapplyOverrides(t *testing.T) {
+	s := newOverridableServer(t)
+
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/overrides", `{"error_rate":0.5,"rate_limit":{"tier":"tier1"}}`, nil), http.StatusOK)
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/overrides", `{"error_rate":0.2}`, nil), http.StatusOK)
+
+	// the tier dropped from the second apply is reverted
+	expectOverridden(t, s, 0.2, true, "free")
+
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/overrides", "", nil), http.StatusNoContent)
+	expectOverridden(t, s, 0.005, false, "free")
+}
+
+func TestOverrideRoutesNeedComponents(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/overrides", `{"error_rate":0.5}`, nil), http.StatusNotFound)
+
+	// a component missing from the server cannot be overridden
+	s = newTestServer(t, Dependencies{ErrorInjector: behavior.NewErrorInjector(behavior.DefaultErrorInjectorConfig())})
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/overrides", `{"rate_limit":{"tier":"tier1"}}`, nil), http.StatusBadRequest)
+}
+
+// expectOverridden checks the error injector and limiter configuration.
+func expectOverridden(t *testing.T, s *Server, rate float64, enabled bool, tier string) {
+	t.Helper()
+	if got := s.errorInjector.GetBaseErrorRate(); got != rate {
+		t.Errorf("base error rate = %v, want %v", got, rate)
+	}
+	if got := s.errorInjector.IsEnabled(); got != enabled {
+		t.Errorf("error injection enabled = %v, want %v", got, enabled)
+	}
+	if got := s.limiter.GetDefaultTier(); got != tier {
+		t.Errorf("default tier = %q, want %q", got, tier)
+	}
+}
@@ -0,0 +1,83 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the preflight admin API, which reports the settings a
+// canary request cannot reveal on its own so `sentra lab test` can check the
+// mock is configured as lab.yaml expects before running a suite.
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+)
+
+// AuthModeAnyKey means any bearer token (or none) is accepted, as the mock
+// does not authenticate agents.
+const AuthModeAnyKey = "any_key"
+
+// preflightResponse describes the mock's effective configuration.
+type preflightResponse struct {
+	// Auth is how API keys are checked
+	Auth string `json:"auth"`
+
+	// Latency is whether responses are delayed like the real API's
+	Latency preflightLatency `json:"latency"`
+
+	// RateLimits is whether rate limits are enforced, and the default tier
+	RateLimits preflightRateLimits `json:"rate_limits"`
+
+	// MaxStreamsPerKey is the per-key streaming connection limit (0 = unlimited)
+	MaxStreamsPerKey int `json:"max_streams_per_key"`
+
+	// ErrorRate is the share of requests failed by error injection
+	// (0 when disabled)
+	ErrorRate float64 `json:"error_rate"`
+
+	// Replay is whether responses are replayed from a recorded run
+	Replay bool `json:"replay"`
+}
+
+type preflightLatency struct {
+	Enabled            bool                       `json:"enabled"`
+	NonStreamingTiming latency.NonStreamingTiming `json:"non_streaming_timing,omitempty"`
+}
+
+type preflightRateLimits struct {
+	Enabled bool   `json:"enabled"`
+	Tier    string `json:"tier,omitempty"`
+}
+
+// setupPreflightRoutes registers the preflight admin API.
+func (s *Server) setupPreflightRoutes(admin *gin.RouterGroup) {
+	admin.GET("/preflight", s.handleGetPreflight)
+}
+
+// handleGetPreflight reports the mock's effective configuration.
+func (s *Server) handleGetPreflight(c *gin.Context) {
+	resp := preflightResponse{
+		Auth:             AuthModeAnyKey,
+		MaxStreamsPerKey: s.config.Streams.MaxPerKey,
+		Replay:           s.replay.active(),
+	}
+
+	if s.latency != nil && s.latency.IsEnabled() {
+		resp.Latency = preflightLatency{
+			Enabled:            true,
+			NonStreamingTiming: s.latency.NonStreamingTiming(),
+		}
+	}
+
+	if s.limiter != nil && s.limiter.IsEnabled() {
+		resp.RateLimits = preflightRateLimits{
+			Enabled: true,
+			Tier:    s.limiter.GetDefaultTier(),
+		}
+	}
+
+	if s.errorInjector != nil && s.errorInjector.IsEnabled() {
+		resp.ErrorRate = s.errorInjector.GetBaseErrorRate()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+)
+
+func TestGetPreflight(t *testing.T) {
+	injector := behavior.NewErrorInjector(behavior.DefaultErrorInjectorConfig())
+	disabledInjector := behavior.NewErrorInjector(behavior.DefaultErrorInjectorConfig())
+	disabledInjector.Disable()
+
+	tests := []struct {
+		name string
+		deps Dependencies
+		want preflightResponse
+	}{
+		{
+			name: "no simulation",
+			want: preflightResponse{Auth: AuthModeAnyKey, MaxStreamsPerKey: DefaultStreamsConfig().MaxPerKey},
+		},
+		{
+			name: "everything enabled",
+			deps: Dependencies{
+				Latency:       latency.NewSimulator(latency.DefaultSimulatorConfig()),
+				Limiter:       ratelimit.NewLimiter(ratelimit.LimiterConfig{Enabled: true, TierRegistry: ratelimit.NewTierRegistry("tier2"), DefaultTier: "tier2"}),
+				ErrorInjector: injector,
+			},
+			want: preflightResponse{
+				Auth:             AuthModeAnyKey,
+				Latency:          preflightLatency{Enabled: true, NonStreamingTiming: latency.TimingSplit},
+				RateLimits:       preflightRateLimits{Enabled: true, Tier: "tier2"},
+				MaxStreamsPerKey: DefaultStreamsConfig().MaxPerKey,
+				ErrorRate:        behavior.DefaultErrorInjectorConfig().BaseErrorRate,
+			},
+		},
+		{
+			name: "disabled simulators",
+			deps: Dependencies{
+				Latency:       latency.NewSimulator(latency.SimulatorConfig{}),
+				Limiter:       ratelimit.NewLimiter(ratelimit.LimiterConfig{TierRegistry: ratelimit.NewTierRegistry("free"), DefaultTier: "free"}),
+				ErrorInjector: disabledInjector,
+			},
+			want: preflightResponse{Auth: AuthModeAnyKey, MaxStreamsPerKey: DefaultStreamsConfig().MaxPerKey},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.deps)
+
+			rec := serve(s, http.MethodGet, "/_sentra/preflight", "", nil)
+			expectStatus(t, rec, http.StatusOK)
+
+			var resp preflightResponse
+			decodeJSON(t, rec, &resp)
+			if resp != tt.want {
+				t.Errorf("preflight = %+v, want %+v", resp, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements request rate limiting: requests naming a model are
+// checked against the RPM/TPM buckets of their organization/project scope
+// and answered with the x-ratelimit-* headers, or rejected with a 429.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// rateLimitedRequest is the part of a JSON request body that is rate limited.
+type rateLimitedRequest struct {
+	Model     string           `json:"model"`
+	Messages  []models.Message `json:"messages"`
+	MaxTokens int              `json:"max_tokens"`
+}
+
+// RateLimitMiddleware checks requests against the limiter. Each
+// organization/project behind an API key has its own buckets, and a request
+// consumes its estimated prompt tokens plus max_tokens. Requests without a
+// JSON body naming a model are not limited; malformed bodies are left for
+// the handler to reject. Must run after ScopeMiddleware.
+func (s *Server) RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.limiter == nil || !s.limiter.IsEnabled() || c.Request.Body == nil || c.ContentType() != gin.MIMEJSON {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if IsBodyTooLarge(err) {
+				abortWithError(c, models.NewRequestTooLargeError(s.config.Limits.MaxBodyBytes))
+				return
+			}
+			abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req rateLimitedRequest
+		if json.Unmarshal(body, &req) != nil || req.Model == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		scope := ScopeFromContext(ctx)
+		estimatedTokens := tokenizer.EstimateRateLimitTokens(req.Messages, req.MaxTokens)
+
+		result, err := s.limiter.AllowScoped(ctx, scope, req.Model, estimatedTokens)
+		if err != nil {
+			metrics.Warn(ctx, "failed to check rate limits", "error", err)
+			c.Next()
+			return
+		}
+
+		info, err := s.limiter.GetScopedLimitInfo(scope, req.Model)
+		if err != nil {
+			metrics.Warn(ctx, "failed to get rate limit info", "error", err)
+			info = &ratelimit.LimitInfo{}
+		}
+
+		if result.Allowed {
+			ratelimit.AddRateLimitHeaders(c.Writer, result, info)
+			c.Next()
+			return
+		}
+
+		ratelimit.AddRateLimitExceededHeaders(c.Writer, result, info)
+		abortWithError(c, rateLimitExceededError(scope, result, info))
+	}
+}
+
+// rateLimitExceededError describes a rejected request the way OpenAI does,
+// naming the model, the scope and the exhausted limit.
+func rateLimitExceededError(scope models.RequestScope, result *ratelimit.LimitCheckResult, info *ratelimit.LimitInfo) models.APIError {
+	where := "your API key"
+	switch {
+	case scope.HasProject():
+		where = "project " + scope.Project
+	case scope.HasOrganization():
+		where = "organization " + scope.Organization
+	}
+
+	limit, unit, retryAfter := info.RequestLimit, "requests per min (RPM)", result.RequestsResetIn
+	if result.LimitingFactor == ratelimit.TokenLimit {
+		limit, unit, retryAfter = info.TokenLimit, "tokens per min (TPM)", result.TokensResetIn
+	}
+
+	message := fmt.Sprintf("Rate limit reached for %s in %s on %s: Limit %d. Please try again in %s.",
+		result.ModelID, where, unit, limit, retryAfter.Round(time.Millisecond))
+	return models.NewRateLimitError(message, int(math.Ceil(retryAfter.Seconds())))
+}
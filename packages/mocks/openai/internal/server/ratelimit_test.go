@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// newRateLimitedServer creates a server limiting requests to the free tier
+// (3 RPM for gpt-4o).
+func newRateLimitedServer(t *testing.T) *Server {
+	t.Helper()
+	return newTestServer(t, Dependencies{
+		Limiter: ratelimit.NewLimiter(ratelimit.LimiterConfig{
+			Enabled:      true,
+			TierRegistry: ratelimit.NewTierRegistry("free"),
+			DefaultTier:  "free",
+		}),
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	const body = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`
+
+	tests := []struct {
+		name string
+		// projects are the OpenAI-Project headers of the requests, in order
+		projects []string
+		// want are the expected statuses
+		want []int
+	}{
+		{
+			name:     "limit per key",
+			projects: []string{"", "", "", ""},
+			want:     []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:     "limit per project",
+			projects: []string{"proj_a", "proj_a", "proj_a", "proj_b", "proj_a"},
+			want:     []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRateLimitedServer(t)
+
+			for i, project := range tt.projects {
+				headers := map[string]string{}
+				if project != "" {
+					headers[models.HeaderProject] = project
+				}
+
+				rec := serve(s, http.MethodPost, "/v1/chat/completions", body, headers)
+				expectStatus(t, rec, tt.want[i])
+
+				if got := rec.Header().Get("x-ratelimit-limit-requests"); got != "3" {
+					t.Errorf("request %d: x-ratelimit-limit-requests = %q, want 3", i, got)
+				}
+				if tt.want[i] != http.StatusTooManyRequests {
+					continue
+				}
+
+				if rec.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d: missing Retry-After", i)
+				}
+				var resp models.ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Error.Type != models.ErrorTypeRateLimit {
+					t.Errorf("request %d: error type = %q, want %q", i, resp.Error.Type, models.ErrorTypeRateLimit)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitSkipsRequestsWithoutModel(t *testing.T) {
+	s := newRateLimitedServer(t)
+
+	for i := 0; i < 5; i++ {
+		rec := serve(s, http.MethodGet, "/v1/models", "", nil)
+		if rec.Header().Get("x-ratelimit-limit-requests") != "" {
+			t.Fatalf("request %d was rate limited", i)
+		}
+	}
+}
+
+func TestScopeCosts(t *testing.T) {
+	tracker := pricing.NewTracker(pricing.NewCalculator(pricing.NewPricingDB()), store.NewMemoryStore())
+	s := newTestServer(t, Dependencies{Tracker: tracker})
+
+	const body = `{"model":"gpt-4o","messages":[{"role":"user","content":"Hello"}]}`
+	for _, headers := range []map[string]string{
+		{models.HeaderOrganization: "org-a", models.HeaderProject: "proj_a"},
+		{models.HeaderOrganization: "org-a", models.HeaderProject: "proj_a"},
+		{models.HeaderOrganization: "org-a", models.HeaderProject: "proj_b"},
+	} {
+		expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", body, headers), http.StatusOK)
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		status       int
+		wantRequests int64
+	}{
+		{name: "organization", query: "organization=org-a", status: http.StatusOK, wantRequests: 3},
+		{name: "project", query: "project=proj_a", status: http.StatusOK, wantRequests: 2},
+		{name: "api key", query: "api_key=sk-test&organization=org-a&project=proj_b", status: http.StatusOK, wantRequests: 1},
+		{name: "unknown project", query: "project=proj_c", status: http.StatusNotFound},
+		{name: "invalid organization", query: "organization=acme", status: http.StatusBadRequest},
+		{name: "no scope", query: "", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, adminPrefix+"/costs/scope?"+tt.query, "", nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var entry scopeCostEntry
+			if err := json.Unmarshal(rec.Body.Bytes(), &entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry.Requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", entry.Requests, tt.wantRequests)
+			}
+			var modelRequests int64
+			for _, model := range entry.Models {
+				modelRequests += model.Requests
+			}
+			if modelRequests != tt.wantRequests {
+				t.Errorf("model requests = %d, want %d", modelRequests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,817 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the Realtime API (/v1/realtime): a WebSocket session
+// that buffers input audio, detects turns with a simulated server VAD, and
+// streams synthesized text and audio responses paced by the latency
+// simulator, one delay per delta event.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// realtimePath is registered outside the API middleware: a session outlives
+// the request timeout, and its frames are not captured.
+const realtimePath = "/v1/realtime"
+
+// realtimeMinCommitMs is the least audio input_audio_buffer.commit accepts
+const realtimeMinCommitMs = 100
+
+// realtimeSpeechPeak is the peak amplitude (of full scale) pcm16 audio must
+// exceed to count as speech at VAD threshold 1.0; the floor scales with the
+// session's threshold
+const realtimeSpeechPeak = 0.02
+
+// Milliseconds of input and output audio per audio token
+const (
+	realtimeInputAudioMsPerToken  = 100
+	realtimeOutputAudioMsPerToken = 50
+)
+
+// setupRealtimeRoutes registers the Realtime API.
+func (s *Server) setupRealtimeRoutes() {
+	s.engine.GET(realtimePath, ScopeMiddleware(), s.handleRealtime)
+}
+
+// handleRealtime validates the model and upgrades the request to a
+// Realtime session.
+func (s *Server) handleRealtime(c *gin.Context) {
+	model := c.Query("model")
+	if model == "" {
+		param := "model"
+		abortWithError(c, models.NewBadRequestError("Missing required parameter: 'model'.", &param))
+		return
+	}
+
+	config, responseModel, err := s.ResolveModel(c, model)
+	if err == nil {
+		err = models.ValidateRealtimeModel(config, model)
+	}
+	if err != nil {
+		abortWithAPIError(c, err)
+		return
+	}
+
+	if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		abortWithError(c, models.NewAPIError(models.ErrorTypeBadRequest, "The Realtime API requires a WebSocket connection.", http.StatusUpgradeRequired))
+		return
+	}
+
+	// A session holds a stream open for as long as it is connected
+	endStream, ok := s.StartStream(c)
+	if !ok {
+		return
+	}
+	defer endStream()
+
+	ws := websocket.Server{
+		Handshake: realtimeHandshake,
+		Handler: func(conn *websocket.Conn) {
+			newRealtimeSession(s, c, conn, config.ID, responseModel).serve()
+		},
+	}
+	ws.ServeHTTP(c.Writer, c.Request)
+}
+
+// realtimeHandshake accepts any origin and selects the "realtime"
+// subprotocol browsers offer alongside their credentials.
+func realtimeHandshake(config *websocket.Config, _ *http.Request) error {
+	offered := config.Protocol
+	config.Protocol = nil
+	if slices.Contains(offered, "realtime") {
+		config.Protocol = []string{"realtime"}
+	}
+	return nil
+}
+
+// realtimeResponse is a response being streamed.
+type realtimeResponse struct {
+	id     string
+	cancel context.CancelFunc
+
+	// reason is set when the response is cancelled ("client_cancelled",
+	// "turn_detected")
+	reason string
+}
+
+// realtimeSession is one Realtime WebSocket connection.
+type realtimeSession struct {
+	server *Server
+	c      *gin.Context
+	conn   *websocket.Conn
+
+	// modelID selects the latency profile
+	modelID string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// responses tracks streaming goroutines, so the connection outlives them
+	responses sync.WaitGroup
+
+	// writeMu serializes frames
+	writeMu sync.Mutex
+
+	mu     sync.Mutex
+	config models.RealtimeSession
+	items  []models.RealtimeItem
+	closed bool
+
+	// buffer is the uncommitted input audio
+	buffer []byte
+
+	// inputAudioMs is the committed input audio, billed as context
+	inputAudioMs int
+
+	// speechStartMs is where detected speech began in the buffer (-1 when
+	// no speech is in progress); bufferItemID is the item the buffer
+	// becomes when committed
+	speechStartMs int
+	bufferItemID  string
+
+	// silence ends the turn when no speech arrives for the VAD's silence
+	// duration; turn invalidates timers that were reset
+	silence *time.Timer
+	turn    int
+
+	// response is the active response, if any
+	response *realtimeResponse
+}
+
+// newRealtimeSession creates a session with the API's defaults.
+func newRealtimeSession(s *Server, c *gin.Context, conn *websocket.Conn, modelID, model string) *realtimeSession {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	return &realtimeSession{
+		server:        s,
+		c:             c,
+		conn:          conn,
+		modelID:       modelID,
+		ctx:           ctx,
+		cancel:        cancel,
+		config:        models.NewRealtimeSession(model, clock.Now().Unix()),
+		speechStartMs: -1,
+	}
+}
+
+// serve announces the session and handles client events until the client
+// disconnects.
+func (rs *realtimeSession) serve() {
+	defer rs.close()
+
+	rs.mu.Lock()
+	session := rs.config
+	rs.mu.Unlock()
+	rs.send(models.RealtimeServerEvent{Type: "session.created", Session: &session})
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(rs.conn, &data); err != nil {
+			return
+		}
+
+		var event models.RealtimeClientEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			rs.sendError("", models.NewBadRequestError(fmt.Sprintf("We could not parse the JSON of your event: %v", err), nil))
+			continue
+		}
+		rs.handle(event)
+	}
+}
+
+// close stops the session's timer and responses.
+func (rs *realtimeSession) close() {
+	rs.mu.Lock()
+	rs.closed = true
+	if rs.silence != nil {
+		rs.silence.Stop()
+	}
+	rs.mu.Unlock()
+
+	rs.cancel()
+	rs.responses.Wait()
+}
+
+// handle dispatches a client event.
+func (rs *realtimeSession) handle(event models.RealtimeClientEvent) {
+	switch event.Type {
+	case "session.update":
+		rs.updateSession(event)
+	case "input_audio_buffer.append":
+		rs.appendAudio(event)
+	case "input_audio_buffer.commit":
+		rs.commit(event.EventID)
+	case "input_audio_buffer.clear":
+		rs.clearAudio()
+	case "conversation.item.create":
+		rs.createItem(event)
+	case "conversation.item.delete":
+		rs.deleteItem(event)
+	case "response.create":
+		rs.startResponse(event.EventID, event.Response)
+	case "response.cancel":
+		rs.cancelResponse(event.EventID)
+	default:
+		param := "type"
+		rs.sendError(event.EventID, models.NewBadRequestError(fmt.Sprintf("Invalid value: '%s'. Unknown client event type.", event.Type), &param))
+	}
+}
+
+// updateSession applies session.update.
+func (rs *realtimeSession) updateSession(event models.RealtimeClientEvent) {
+	if event.Session == nil {
+		param := "session"
+		rs.sendError(event.EventID, models.NewBadRequestError("Missing required parameter: 'session'.", &param))
+		return
+	}
+
+	rs.mu.Lock()
+	err := event.Session.Apply(&rs.config)
+	session := rs.config
+	rs.mu.Unlock()
+
+	if err != nil {
+		rs.sendError(event.EventID, err)
+		return
+	}
+	rs.send(models.RealtimeServerEvent{Type: "session.updated", Session: &session})
+}
+
+// appendAudio adds audio to the input buffer. With server VAD, the first
+// speech starts a turn (interrupting an active response) and every speech
+// chunk pushes back the silence that ends it.
+func (rs *realtimeSession) appendAudio(event models.RealtimeClientEvent) {
+	audio, err := base64.StdEncoding.DecodeString(event.Audio)
+	if err != nil {
+		param := "audio"
+		rs.sendError(event.EventID, models.NewBadRequestError("Invalid 'audio'. Expected base64-encoded audio.", &param))
+		return
+	}
+
+	rs.mu.Lock()
+	startMs := audioDurationMs(rs.config.InputAudioFormat, len(rs.buffer))
+	rs.buffer = append(rs.buffer, audio...)
+
+	vad := rs.config.TurnDetection
+	if vad == nil || !containsSpeech(rs.config.InputAudioFormat, audio, vad.Threshold) {
+		rs.mu.Unlock()
+		return
+	}
+
+	started := rs.speechStartMs < 0
+	if started {
+		rs.speechStartMs = startMs
+		rs.bufferItemID = models.NewRealtimeID("item")
+		rs.interrupt("turn_detected")
+	}
+	itemID := rs.bufferItemID
+
+	if rs.silence != nil {
+		rs.silence.Stop()
+	}
+	rs.turn++
+	turn := rs.turn
+	rs.silence = time.AfterFunc(time.Duration(vad.SilenceDurationMs)*time.Millisecond, func() { rs.endTurn(turn) })
+	rs.mu.Unlock()
+
+	if started {
+		rs.send(models.RealtimeServerEvent{Type: "input_audio_buffer.speech_started", AudioStartMs: &startMs, ItemID: itemID})
+	}
+}
+
+// endTurn ends detected speech after the VAD's silence: the buffer is
+// committed and, unless the VAD is configured not to, a response starts.
+func (rs *realtimeSession) endTurn(turn int) {
+	rs.mu.Lock()
+	if rs.closed || turn != rs.turn || rs.speechStartMs < 0 {
+		rs.mu.Unlock()
+		return
+	}
+	endMs := audioDurationMs(rs.config.InputAudioFormat, len(rs.buffer))
+	itemID := rs.bufferItemID
+	rs.speechStartMs = -1
+	createResponse := rs.config.TurnDetection == nil || rs.config.TurnDetection.CreateResponse == nil || *rs.config.TurnDetection.CreateResponse
+	rs.mu.Unlock()
+
+	rs.send(models.RealtimeServerEvent{Type: "input_audio_buffer.speech_stopped", AudioEndMs: &endMs, ItemID: itemID})
+	if rs.commit("") && createResponse {
+		rs.startResponse("", nil)
+	}
+}
+
+// commit turns the input buffer into a user message, transcribed if the
+// session asks for transcripts. It returns false if the buffer holds too
+// little audio.
+func (rs *realtimeSession) commit(eventID string) bool {
+	rs.mu.Lock()
+	ms := audioDurationMs(rs.config.InputAudioFormat, len(rs.buffer))
+	if ms < realtimeMinCommitMs {
+		rs.mu.Unlock()
+		rs.sendError(eventID, realtimeError(fmt.Sprintf("Error committing input audio buffer: buffer too small. Expected at least %dms of audio, but buffer only has %dms of audio.", realtimeMinCommitMs, ms), "input_audio_buffer_commit_empty"))
+		return false
+	}
+
+	item := models.RealtimeItem{
+		ID:      rs.bufferItemID,
+		Object:  "realtime.item",
+		Type:    "message",
+		Status:  "completed",
+		Role:    "user",
+		Content: []models.RealtimeContent{{Type: "input_audio"}},
+	}
+	if item.ID == "" {
+		item.ID = models.NewRealtimeID("item")
+	}
+
+	var transcript string
+	if rs.config.InputAudioTranscription != nil {
+		transcript = fmt.Sprintf("[%.1fs of audio]", float64(ms)/1000)
+		item.Content[0].Transcript = &transcript
+	}
+
+	previous := rs.lastItemID()
+	rs.items = append(rs.items, item)
+	rs.inputAudioMs += ms
+	rs.resetBuffer()
+	rs.mu.Unlock()
+
+	rs.send(models.RealtimeServerEvent{Type: "input_audio_buffer.committed", PreviousItemID: previous, ItemID: item.ID})
+	rs.send(models.RealtimeServerEvent{Type: "conversation.item.created", PreviousItemID: previous, Item: &item})
+	if transcript != "" {
+		contentIndex := 0
+		rs.send(models.RealtimeServerEvent{
+			Type:         "conversation.item.input_audio_transcription.completed",
+			ItemID:       item.ID,
+			ContentIndex: &contentIndex,
+			Transcript:   transcript,
+		})
+	}
+	return true
+}
+
+// clearAudio discards the input buffer.
+func (rs *realtimeSession) clearAudio() {
+	rs.mu.Lock()
+	rs.resetBuffer()
+	rs.mu.Unlock()
+
+	rs.send(models.RealtimeServerEvent{Type: "input_audio_buffer.cleared"})
+}
+
+// resetBuffer empties the input buffer and ends any detected speech.
+// Callers must hold mu.
+func (rs *realtimeSession) resetBuffer() {
+	rs.buffer = nil
+	rs.bufferItemID = ""
+	rs.speechStartMs = -1
+	rs.turn++
+	if rs.silence != nil {
+		rs.silence.Stop()
+	}
+}
+
+// createItem adds an item to the conversation, after previous_item_id if
+// given ("root" inserts it first).
+func (rs *realtimeSession) createItem(event models.RealtimeClientEvent) {
+	if event.Item == nil {
+		param := "item"
+		rs.sendError(event.EventID, models.NewBadRequestError("Missing required parameter: 'item'.", &param))
+		return
+	}
+
+	item := *event.Item
+	if err := item.Validate(); err != nil {
+		rs.sendError(event.EventID, err)
+		return
+	}
+	if item.ID == "" {
+		item.ID = models.NewRealtimeID("item")
+	}
+	item.Object = "realtime.item"
+	if item.Status == "" {
+		item.Status = "completed"
+	}
+
+	rs.mu.Lock()
+	index := len(rs.items)
+	if event.PreviousItemID != nil {
+		index = 0
+		if *event.PreviousItemID != "root" {
+			index = rs.itemIndex(*event.PreviousItemID) + 1
+			if index == 0 {
+				rs.mu.Unlock()
+				rs.sendError(event.EventID, realtimeError(fmt.Sprintf("Previous item with id '%s' not found.", *event.PreviousItemID), "item_not_found"))
+				return
+			}
+		}
+	}
+
+	var previous *string
+	if index > 0 {
+		previous = &rs.items[index-1].ID
+	}
+	rs.items = slices.Insert(rs.items, index, item)
+	rs.mu.Unlock()
+
+	rs.send(models.RealtimeServerEvent{Type: "conversation.item.created", PreviousItemID: previous, Item: &item})
+}
+
+// deleteItem removes an item from the conversation.
+func (rs *realtimeSession) deleteItem(event models.RealtimeClientEvent) {
+	rs.mu.Lock()
+	index := rs.itemIndex(event.ItemID)
+	if index >= 0 {
+		rs.items = slices.Delete(rs.items, index, index+1)
+	}
+	rs.mu.Unlock()
+
+	if index < 0 {
+		rs.sendError(event.EventID, realtimeError(fmt.Sprintf("Item with item_id '%s' not found.", event.ItemID), "item_not_found"))
+		return
+	}
+	rs.send(models.RealtimeServerEvent{Type: "conversation.item.deleted", ItemID: event.ItemID})
+}
+
+// itemIndex returns the position of an item, or -1. Callers must hold mu.
+func (rs *realtimeSession) itemIndex(id string) int {
+	return slices.IndexFunc(rs.items, func(item models.RealtimeItem) bool { return item.ID == id })
+}
+
+// lastItemID returns the ID of the last item, or nil. Callers must hold mu.
+func (rs *realtimeSession) lastItemID() *string {
+	if len(rs.items) == 0 {
+		return nil
+	}
+	id := rs.items[len(rs.items)-1].ID
+	return &id
+}
+
+// startResponse streams a response to the conversation. Only one response
+// may be active at a time.
+func (rs *realtimeSession) startResponse(eventID string, override *models.RealtimeResponseConfig) {
+	rs.mu.Lock()
+	if rs.closed {
+		rs.mu.Unlock()
+		return
+	}
+	if rs.response != nil {
+		active := rs.response.id
+		rs.mu.Unlock()
+		rs.sendError(eventID, realtimeError(fmt.Sprintf("Conversation already has an active response: %s", active), "conversation_already_has_active_response"))
+		return
+	}
+
+	config := rs.config
+	if override != nil {
+		update := models.RealtimeSessionUpdate{Modalities: override.Modalities, Instructions: override.Instructions}
+		if err := update.Apply(&config); err != nil {
+			rs.mu.Unlock()
+			rs.sendError(eventID, err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(rs.ctx)
+	response := &realtimeResponse{id: models.NewRealtimeID("resp"), cancel: cancel}
+	rs.response = response
+	conversation := rs.conversation(config.Instructions)
+	inputAudioMs := rs.inputAudioMs
+	rs.responses.Add(1)
+	rs.mu.Unlock()
+
+	go func() {
+		defer rs.responses.Done()
+		defer cancel()
+		rs.respond(ctx, response, config, conversation, inputAudioMs)
+	}()
+}
+
+// cancelResponse cancels the active response (response.cancel).
+func (rs *realtimeSession) cancelResponse(eventID string) {
+	rs.mu.Lock()
+	cancelled := rs.interrupt("client_cancelled")
+	rs.mu.Unlock()
+
+	if !cancelled {
+		rs.sendError(eventID, realtimeError("Cancellation failed: no active response found", "response_cancel_not_active"))
+	}
+}
+
+// interrupt cancels the active response, returning false if there is none.
+// Callers must hold mu.
+func (rs *realtimeSession) interrupt(reason string) bool {
+	if rs.response == nil || rs.response.reason != "" {
+		return false
+	}
+	rs.response.reason = reason
+	rs.response.cancel()
+	return true
+}
+
+// conversation converts the conversation items to chat messages, with the
+// instructions as the system message. Callers must hold mu.
+func (rs *realtimeSession) conversation(instructions string) []models.Message {
+	conversation := make([]models.Message, 0, len(rs.items)+1)
+	if instructions != "" {
+		conversation = append(conversation, models.Message{Role: "system", Content: instructions})
+	}
+	for _, item := range rs.items {
+		role := item.Role
+		switch item.Type {
+		case "function_call":
+			role = "assistant"
+		case "function_call_output":
+			role = "tool"
+		}
+		conversation = append(conversation, models.Message{Role: role, Content: item.PlainText()})
+	}
+	return conversation
+}
+
+// respond streams one response: its output item and content part, a delta
+// per word (text, or audio with its transcript) after the simulated delay,
+// then the done events. A cancelled response ends early with the output
+// streamed so far.
+func (rs *realtimeSession) respond(ctx context.Context, response *realtimeResponse, config models.RealtimeSession, conversation []models.Message, inputAudioMs int) {
+	outputIndex, contentIndex := 0, 0
+	rs.send(models.RealtimeServerEvent{
+		Type:     "response.created",
+		Response: &models.RealtimeResponse{ID: response.id, Object: "realtime.response", Status: "in_progress", Output: []models.RealtimeItem{}},
+	})
+
+	item := models.RealtimeItem{
+		ID:      models.NewRealtimeID("item"),
+		Object:  "realtime.item",
+		Type:    "message",
+		Status:  "in_progress",
+		Role:    "assistant",
+		Content: []models.RealtimeContent{},
+	}
+	rs.mu.Lock()
+	previous := rs.lastItemID()
+	rs.items = append(rs.items, item)
+	rs.mu.Unlock()
+
+	rs.send(models.RealtimeServerEvent{Type: "response.output_item.added", ResponseID: response.id, OutputIndex: &outputIndex, Item: &item})
+	rs.send(models.RealtimeServerEvent{Type: "conversation.item.created", PreviousItemID: previous, Item: &item})
+
+	audio := config.HasAudio()
+	part := models.RealtimeContent{Type: "text"}
+	if audio {
+		transcript := ""
+		part = models.RealtimeContent{Type: "audio", Transcript: &transcript}
+	}
+	rs.send(models.RealtimeServerEvent{
+		Type:         "response.content_part.added",
+		ResponseID:   response.id,
+		ItemID:       item.ID,
+		OutputIndex:  &outputIndex,
+		ContentIndex: &contentIndex,
+		Part:         &part,
+	})
+
+	deltas := realtimeDeltas(rs.server.runReply(rs.c, conversation, nil))
+	delays := rs.delays(ctx, len(deltas))
+
+	var streamed strings.Builder
+	var outputAudioMs int
+	for i, delta := range deltas {
+		if !waitFor(ctx, delays[i]) {
+			break
+		}
+		streamed.WriteString(delta)
+
+		event := models.RealtimeServerEvent{
+			ResponseID:   response.id,
+			ItemID:       item.ID,
+			OutputIndex:  &outputIndex,
+			ContentIndex: &contentIndex,
+		}
+		if !audio {
+			event.Type, event.Delta = "response.text.delta", delta
+			rs.send(event)
+			continue
+		}
+
+		speech := generator.SyntheticSpeech{Text: delta, Voice: config.Voice, Format: "pcm"}
+		outputAudioMs += int(speech.Duration().Milliseconds())
+		event.Type, event.Delta = "response.audio.delta", base64.StdEncoding.EncodeToString(realtimeAudio(config.OutputAudioFormat, speech))
+		rs.send(event)
+		event.Type, event.Delta = "response.audio_transcript.delta", delta
+		rs.send(event)
+	}
+	text := streamed.String()
+
+	// The session closed: nobody is left to tell
+	if rs.ctx.Err() != nil {
+		return
+	}
+
+	rs.mu.Lock()
+	rs.response = nil
+	status, itemStatus := "completed", "completed"
+	var details *models.RealtimeStatusDetails
+	if response.reason != "" {
+		status, itemStatus = "cancelled", "incomplete"
+		details = &models.RealtimeStatusDetails{Type: "cancelled", Reason: response.reason}
+	}
+	if audio {
+		part.Transcript = &text
+	} else {
+		part.Text = text
+	}
+	item.Status = itemStatus
+	item.Content = []models.RealtimeContent{part}
+	if index := rs.itemIndex(item.ID); index >= 0 {
+		rs.items[index] = item
+	}
+	rs.mu.Unlock()
+
+	done := models.RealtimeServerEvent{
+		ResponseID:   response.id,
+		ItemID:       item.ID,
+		OutputIndex:  &outputIndex,
+		ContentIndex: &contentIndex,
+	}
+	if audio {
+		done.Type = "response.audio.done"
+		rs.send(done)
+		done.Type, done.Transcript = "response.audio_transcript.done", text
+		rs.send(done)
+	} else {
+		done.Type, done.Text = "response.text.done", text
+		rs.send(done)
+	}
+	rs.send(models.RealtimeServerEvent{
+		Type:         "response.content_part.done",
+		ResponseID:   response.id,
+		ItemID:       item.ID,
+		OutputIndex:  &outputIndex,
+		ContentIndex: &contentIndex,
+		Part:         &part,
+	})
+	rs.send(models.RealtimeServerEvent{Type: "response.output_item.done", ResponseID: response.id, OutputIndex: &outputIndex, Item: &item})
+
+	inputText := tokenizer.FastEstimateMessages(conversation)
+	inputAudio := inputAudioMs / realtimeInputAudioMsPerToken
+	outputText := tokenizer.FastEstimate(text)
+	outputAudio := outputAudioMs / realtimeOutputAudioMsPerToken
+	usage := models.RealtimeUsage{
+		InputTokens:        inputText + inputAudio,
+		OutputTokens:       outputText + outputAudio,
+		InputTokenDetails:  models.RealtimeInputTokenDetails{TextTokens: inputText, AudioTokens: inputAudio},
+		OutputTokenDetails: models.RealtimeTokenDetails{TextTokens: outputText, AudioTokens: outputAudio},
+	}
+	usage.TotalTokens = usage.InputTokens + usage.OutputTokens
+
+	rs.send(models.RealtimeServerEvent{
+		Type: "response.done",
+		Response: &models.RealtimeResponse{
+			ID:            response.id,
+			Object:        "realtime.response",
+			Status:        status,
+			StatusDetails: details,
+			Output:        []models.RealtimeItem{item},
+			Usage:         &usage,
+		},
+	})
+}
+
+// delays returns the simulated delay before each of n deltas: time to the
+// first delta, then per-token latency.
+func (rs *realtimeSession) delays(ctx context.Context, n int) []time.Duration {
+	if rs.server.latency == nil || n == 0 {
+		return make([]time.Duration, n)
+	}
+
+	delays, err := rs.server.latency.SimulateStreaming(ctx, rs.modelID, n)
+	if err != nil {
+		metrics.Warn(ctx, "failed to simulate realtime latency", "error", err)
+		return make([]time.Duration, n)
+	}
+	return delays
+}
+
+// send writes a server event with a fresh event ID. A failed write means
+// the client is gone, so streaming stops; the read loop then closes the
+// session.
+func (rs *realtimeSession) send(event models.RealtimeServerEvent) {
+	event.EventID = models.NewRealtimeID("event")
+
+	rs.writeMu.Lock()
+	defer rs.writeMu.Unlock()
+	if err := websocket.JSON.Send(rs.conn, event); err != nil {
+		rs.cancel()
+	}
+}
+
+// sendError sends an error event for a client event.
+func (rs *realtimeSession) sendError(eventID string, err error) {
+	var apiErr models.APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = models.NewBadRequestError(err.Error(), nil)
+	}
+	rs.send(models.RealtimeServerEvent{Type: "error", Error: &models.RealtimeError{APIError: apiErr, EventID: eventID}})
+}
+
+// realtimeError creates an invalid request error with a code.
+func realtimeError(message, code string) models.APIError {
+	apiErr := models.NewBadRequestError(message, nil)
+	apiErr.Code = &code
+	return apiErr
+}
+
+// realtimeDeltas splits a reply into word deltas, each keeping the space
+// that follows it.
+func realtimeDeltas(reply string) []string {
+	var deltas []string
+	for len(reply) > 0 {
+		end := strings.IndexByte(reply, ' ')
+		if end < 0 {
+			end = len(reply) - 1
+		}
+		deltas = append(deltas, reply[:end+1])
+		reply = reply[end+1:]
+	}
+	return deltas
+}
+
+// realtimeAudio encodes a delta's speech in the output format: synthetic
+// pcm16, or G.711 silence of the same length.
+func realtimeAudio(format string, speech generator.SyntheticSpeech) []byte {
+	samples, err := speech.Encode()
+	if err != nil {
+		return nil
+	}
+
+	// pcm16 is 48 bytes per millisecond, G.711 is 8
+	switch format {
+	case "g711_ulaw":
+		return bytes.Repeat([]byte{0xFF}, len(samples)/6)
+	case "g711_alaw":
+		return bytes.Repeat([]byte{0xD5}, len(samples)/6)
+	}
+	return samples
+}
+
+// audioDurationMs returns how long n bytes of audio play: pcm16 is 24kHz
+// 16-bit mono, G.711 is 8kHz 8-bit.
+func audioDurationMs(format string, n int) int {
+	if format == "pcm16" {
+		return n / 48
+	}
+	return n / 8
+}
+
+// containsSpeech reports whether appended audio counts as speech for the
+// simulated server VAD: pcm16 audio whose peak exceeds the threshold's
+// floor. G.711 audio always counts.
+func containsSpeech(format string, audio []byte, threshold float64) bool {
+	if format != "pcm16" {
+		return len(audio) > 0
+	}
+
+	floor := int(threshold * realtimeSpeechPeak * math.MaxInt16)
+	for i := 0; i+1 < len(audio); i += 2 {
+		sample := int(int16(binary.LittleEndian.Uint16(audio[i:])))
+		if sample > floor || sample < -floor {
+			return true
+		}
+	}
+	return false
+}
+
+// waitFor sleeps for d, returning false if ctx ends first.
+func waitFor(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
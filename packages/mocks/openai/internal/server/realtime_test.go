@@ -0,0 +1,373 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// realtimeModel is a model that supports the Realtime API.
+const realtimeModel = "gpt-4o-realtime-preview"
+
+// dialRealtime opens a Realtime session and reads its session.created event.
+func dialRealtime(t *testing.T, s *Server) *websocket.Conn {
+	t.Helper()
+
+	ts := httptest.NewServer(s.Engine())
+	t.Cleanup(ts.Close)
+
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(ts.URL, "http")+realtimePath+"?model="+realtimeModel, ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Header.Set("Authorization", "Bearer sk-test")
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if event := readRealtimeEvent(t, conn); event.Type != "session.created" || event.Session == nil {
+		t.Fatalf("first event = %s, want session.created", event.Type)
+	}
+	return conn
+}
+
+// sendRealtimeEvent sends a client event.
+func sendRealtimeEvent(t *testing.T, conn *websocket.Conn, event models.RealtimeClientEvent) {
+	t.Helper()
+	if err := websocket.JSON.Send(conn, event); err != nil {
+		t.Fatalf("send %s: %v", event.Type, err)
+	}
+}
+
+// readRealtimeEvent reads the next server event.
+func readRealtimeEvent(t *testing.T, conn *websocket.Conn) models.RealtimeServerEvent {
+	t.Helper()
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	var event models.RealtimeServerEvent
+	if err := websocket.JSON.Receive(conn, &event); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	return event
+}
+
+// readRealtimeUntil reads server events up to and including the first of
+// the given type, returning them all.
+func readRealtimeUntil(t *testing.T, conn *websocket.Conn, eventType string) []models.RealtimeServerEvent {
+	t.Helper()
+
+	var events []models.RealtimeServerEvent
+	for {
+		event := readRealtimeEvent(t, conn)
+		events = append(events, event)
+		if event.Type == eventType {
+			return events
+		}
+	}
+}
+
+// loudAudio returns ms milliseconds of full-scale pcm16 audio.
+func loudAudio(ms int) string {
+	audio := make([]byte, ms*48)
+	for i := 0; i+1 < len(audio); i += 2 {
+		binary.LittleEndian.PutUint16(audio[i:], uint16(math.MaxInt16))
+	}
+	return base64.StdEncoding.EncodeToString(audio)
+}
+
+func TestHandleRealtimeRejects(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantParam  string
+	}{
+		{name: "missing model", wantStatus: http.StatusBadRequest, wantParam: "model"},
+		{name: "not a realtime model", query: "?model=gpt-4o-mini", wantStatus: http.StatusBadRequest, wantParam: "model"},
+		{name: "unknown model", query: "?model=no-such-model", wantStatus: http.StatusNotFound},
+		{name: "not a WebSocket", query: "?model=" + realtimeModel, wantStatus: http.StatusUpgradeRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, realtimePath+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if param := errorParam(t, rec); param != tt.wantParam {
+					t.Errorf("error param = %q, want %q", param, tt.wantParam)
+				}
+			}
+		})
+	}
+}
+
+func TestRealtimeClientEvents(t *testing.T) {
+	conn := dialRealtime(t, newTestServer(t, Dependencies{}))
+
+	tests := []struct {
+		name     string
+		event    models.RealtimeClientEvent
+		wantType string
+		wantCode string
+	}{
+		{
+			name:     "unknown event",
+			event:    models.RealtimeClientEvent{EventID: "evt_1", Type: "session.destroy"},
+			wantType: "error",
+		},
+		{
+			name:     "session.update without a session",
+			event:    models.RealtimeClientEvent{Type: "session.update"},
+			wantType: "error",
+		},
+		{
+			name:     "session.update",
+			event:    models.RealtimeClientEvent{Type: "session.update", Session: &models.RealtimeSessionUpdate{Modalities: []string{"text"}}},
+			wantType: "session.updated",
+		},
+		{
+			name:     "invalid audio",
+			event:    models.RealtimeClientEvent{Type: "input_audio_buffer.append", Audio: "not base64!"},
+			wantType: "error",
+		},
+		{
+			name:     "commit of an empty buffer",
+			event:    models.RealtimeClientEvent{Type: "input_audio_buffer.commit"},
+			wantType: "error",
+			wantCode: "input_audio_buffer_commit_empty",
+		},
+		{
+			name:     "clear",
+			event:    models.RealtimeClientEvent{Type: "input_audio_buffer.clear"},
+			wantType: "input_audio_buffer.cleared",
+		},
+		{
+			name:     "create without an item",
+			event:    models.RealtimeClientEvent{Type: "conversation.item.create"},
+			wantType: "error",
+		},
+		{
+			name: "create after an unknown item",
+			event: models.RealtimeClientEvent{
+				Type:           "conversation.item.create",
+				Item:           &models.RealtimeItem{Type: "message", Role: "user", Content: []models.RealtimeContent{{Type: "input_text", Text: "Hi"}}},
+				PreviousItemID: stringPtr("item_missing"),
+			},
+			wantType: "error",
+			wantCode: "item_not_found",
+		},
+		{
+			name: "create",
+			event: models.RealtimeClientEvent{
+				Type: "conversation.item.create",
+				Item: &models.RealtimeItem{Type: "message", Role: "user", Content: []models.RealtimeContent{{Type: "input_text", Text: "Hi"}}},
+			},
+			wantType: "conversation.item.created",
+		},
+		{
+			name:     "delete an unknown item",
+			event:    models.RealtimeClientEvent{Type: "conversation.item.delete", ItemID: "item_missing"},
+			wantType: "error",
+			wantCode: "item_not_found",
+		},
+		{
+			name:     "cancel without a response",
+			event:    models.RealtimeClientEvent{Type: "response.cancel"},
+			wantType: "error",
+			wantCode: "response_cancel_not_active",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendRealtimeEvent(t, conn, tt.event)
+
+			event := readRealtimeEvent(t, conn)
+			if event.Type != tt.wantType {
+				t.Fatalf("event = %s, want %s", event.Type, tt.wantType)
+			}
+			if event.Type != "error" {
+				return
+			}
+			if event.Error.EventID != tt.event.EventID {
+				t.Errorf("error event_id = %q, want %q", event.Error.EventID, tt.event.EventID)
+			}
+			var code string
+			if event.Error.Code != nil {
+				code = *event.Error.Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("error code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRealtimeResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		modalities []string
+		wantDelta  string
+		wantDone   string
+	}{
+		{name: "text", modalities: []string{"text"}, wantDelta: "response.text.delta", wantDone: "response.text.done"},
+		{name: "audio", modalities: []string{"text", "audio"}, wantDelta: "response.audio_transcript.delta", wantDone: "response.audio_transcript.done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dialRealtime(t, newTestServer(t, Dependencies{}))
+
+			sendRealtimeEvent(t, conn, models.RealtimeClientEvent{
+				Type:    "session.update",
+				Session: &models.RealtimeSessionUpdate{Modalities: tt.modalities},
+			})
+			readRealtimeUntil(t, conn, "session.updated")
+			sendRealtimeEvent(t, conn, models.RealtimeClientEvent{
+				Type: "conversation.item.create",
+				Item: &models.RealtimeItem{Type: "message", Role: "user", Content: []models.RealtimeContent{{Type: "input_text", Text: "Hello"}}},
+			})
+			readRealtimeUntil(t, conn, "conversation.item.created")
+
+			sendRealtimeEvent(t, conn, models.RealtimeClientEvent{Type: "response.create"})
+			events := readRealtimeUntil(t, conn, "response.done")
+
+			var streamed, done string
+			for _, event := range events {
+				switch event.Type {
+				case tt.wantDelta:
+					streamed += event.Delta
+				case tt.wantDone:
+					done = event.Text + event.Transcript
+				}
+			}
+			if streamed == "" || streamed != done {
+				t.Errorf("streamed %q, done %q, want the same non-empty text", streamed, done)
+			}
+
+			response := events[len(events)-1].Response
+			if response.Status != "completed" {
+				t.Errorf("response status = %q, want completed", response.Status)
+			}
+			if response.Usage == nil || response.Usage.OutputTokens == 0 {
+				t.Errorf("response usage = %+v, want output tokens", response.Usage)
+			}
+			if len(response.Output) != 1 || response.Output[0].Content[0].PlainText() != done {
+				t.Errorf("response output = %+v, want the streamed text", response.Output)
+			}
+		})
+	}
+}
+
+func TestRealtimeServerVAD(t *testing.T) {
+	conn := dialRealtime(t, newTestServer(t, Dependencies{}))
+
+	sendRealtimeEvent(t, conn, models.RealtimeClientEvent{
+		Type:    "session.update",
+		Session: &models.RealtimeSessionUpdate{Modalities: []string{"text"}},
+	})
+	readRealtimeUntil(t, conn, "session.updated")
+
+	// Speech starts a turn; the VAD's silence ends it and starts a response
+	sendRealtimeEvent(t, conn, models.RealtimeClientEvent{Type: "input_audio_buffer.append", Audio: loudAudio(200)})
+	events := readRealtimeUntil(t, conn, "response.done")
+
+	var types []string
+	for _, event := range events {
+		switch event.Type {
+		case "input_audio_buffer.speech_started", "input_audio_buffer.speech_stopped", "input_audio_buffer.committed", "response.created":
+			types = append(types, event.Type)
+		}
+	}
+	want := "input_audio_buffer.speech_started,input_audio_buffer.speech_stopped,input_audio_buffer.committed,response.created"
+	if got := strings.Join(types, ","); got != want {
+		t.Errorf("turn events = %s, want %s", got, want)
+	}
+	if status := events[len(events)-1].Response.Status; status != "completed" {
+		t.Errorf("response status = %q, want completed", status)
+	}
+}
+
+func TestRealtimeDeltas(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  []string
+	}{
+		{reply: "", want: nil},
+		{reply: "Hello", want: []string{"Hello"}},
+		{reply: "Hello there, friend.", want: []string{"Hello ", "there, ", "friend."}},
+		{reply: "Trailing ", want: []string{"Trailing "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			got := realtimeDeltas(tt.reply)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("realtimeDeltas(%q) = %q, want %q", tt.reply, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAudioDurationMs(t *testing.T) {
+	tests := []struct {
+		format string
+		bytes  int
+		want   int
+	}{
+		{format: "pcm16", bytes: 4800, want: 100},
+		{format: "g711_ulaw", bytes: 800, want: 100},
+		{format: "g711_alaw", bytes: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := audioDurationMs(tt.format, tt.bytes); got != tt.want {
+				t.Errorf("audioDurationMs(%q, %d) = %d, want %d", tt.format, tt.bytes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsSpeech(t *testing.T) {
+	loud, err := base64.StdEncoding.DecodeString(loudAudio(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	silent := make([]byte, 480)
+
+	tests := []struct {
+		name   string
+		format string
+		audio  []byte
+		want   bool
+	}{
+		{name: "loud pcm16", format: "pcm16", audio: loud, want: true},
+		{name: "silent pcm16", format: "pcm16", audio: silent, want: false},
+		{name: "G.711", format: "g711_ulaw", audio: silent, want: true},
+		{name: "empty G.711", format: "g711_ulaw", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsSpeech(tt.format, tt.audio, 0.5); got != tt.want {
+				t.Errorf("containsSpeech() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the model registry admin API.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// setupRegistryRoutes registers the model registry admin API.
+func (s *Server) setupRegistryRoutes(admin *gin.RouterGroup) {
+	admin.GET("/models", s.handleGetRegisteredModels)
+	admin.DELETE("/models/:model", s.handleDeregisterModel)
+}
+
+// registeredModelResponse describes a registered model.
+type registeredModelResponse struct {
+	// ID is the model identifier
+	ID string `json:"id"`
+
+	// Type is chat, embedding, image, speech or realtime
+	Type models.ModelType `json:"type"`
+
+	// Capabilities lists supported features
+	Capabilities []string `json:"capabilities"`
+
+	// ContextWindow and MaxOutputTokens bound request size
+	ContextWindow   int `json:"context_window"`
+	MaxOutputTokens int `json:"max_output_tokens"`
+
+	// OwnedBy is the model owner
+	OwnedBy string `json:"owned_by"`
+}
+
+// handleGetRegisteredModels lists registered models, filtered by ?type= and
+// ?capability= (comma-separated; all must be supported).
+func (s *Server) handleGetRegisteredModels(c *gin.Context) {
+	var query models.ModelQuery
+
+	if raw := c.Query("type"); raw != "" {
+		query.Type = models.ModelType(raw)
+		if !query.Type.IsValid() {
+			param := "type"
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("invalid type %q (must be chat, embedding, image, speech or realtime)", raw), &param))
+			return
+		}
+	}
+
+	if raw := c.Query("capability"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			capability, ok := models.ParseCapability(strings.TrimSpace(name))
+			if !ok {
+				param := "capability"
+				abortWithError(c, models.NewBadRequestError(fmt.Sprintf("invalid capability %q (must be vision, tools or json)", name), &param))
+				return
+			}
+			query.Capabilities |= capability
+		}
+	}
+
+	data := []registeredModelResponse{}
+	for _, config := range models.Registry.Query(query) {
+		capabilities := []string{}
+		if names := config.Capabilities.String(); names != "" {
+			capabilities = strings.Split(names, ",")
+		}
+
+		data = append(data, registeredModelResponse{
+			ID:              config.ID,
+			Type:            config.Type,
+			Capabilities:    capabilities,
+			ContextWindow:   config.ContextWindow,
+			MaxOutputTokens: config.MaxOutputTokens,
+			OwnedBy:         config.OwnedBy,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   data,
+	})
+}
+
+// handleDeregisterModel removes a model from the registry.
+func (s *Server) handleDeregisterModel(c *gin.Context) {
+	model := c.Param("model")
+	if !models.DeregisterModel(model) {
+		abortWithError(c, models.NewModelNotFoundError(model))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestGetRegisteredModels(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		include   []string
+		exclude   []string
+		wantParam string
+	}{
+		{name: "all", status: http.StatusOK, include: []string{"gpt-4o", "text-embedding-3-small", "tts-1"}},
+		{name: "by type", query: "?type=speech", status: http.StatusOK, include: []string{"tts-1"}, exclude: []string{"gpt-4o"}},
+		{name: "by capability", query: "?capability=vision,tools", status: http.StatusOK, include: []string{"gpt-4o"}, exclude: []string{"gpt-3.5-turbo", "tts-1"}},
+		{name: "invalid type", query: "?type=video", status: http.StatusBadRequest, wantParam: "type"},
+		{name: "invalid capability", query: "?capability=vision,telepathy", status: http.StatusBadRequest, wantParam: "capability"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodGet, "/_sentra/models"+tt.query, "", nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var resp struct {
+				Data []registeredModelResponse `json:"data"`
+			}
+			decodeJSON(t, rec, &resp)
+			var ids []string
+			for _, model := range resp.Data {
+				ids = append(ids, model.ID)
+			}
+			for _, id := range tt.include {
+				if !slices.Contains(ids, id) {
+					t.Errorf("models %v do not include %s", ids, id)
+				}
+			}
+			for _, id := range tt.exclude {
+				if slices.Contains(ids, id) {
+					t.Errorf("models %v include %s", ids, id)
+				}
+			}
+		})
+	}
+}
+
+func TestDeregisterModel(t *testing.T) {
+	config := models.ModelConfig{ID: "acme-chat", Type: models.ModelTypeChat, ContextWindow: 8192, MaxOutputTokens: 1024, Encoding: "cl100k_base"}
+	if err := models.RegisterModel(config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { models.DeregisterModel(config.ID) })
+
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/models/acme-chat", "", nil), http.StatusNoContent)
+	expectStatus(t, serve(s, http.MethodDelete, "/_sentra/models/acme-chat", "", nil), http.StatusNotFound)
+
+	if _, err := models.GetModelConfig("acme-chat"); err == nil {
+		t.Error("GetModelConfig() found the deregistered model")
+	}
+}
@@ -0,0 +1,532 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements replay-source mode: requests are answered with the
+// responses of a recorded run (captured exchanges) instead of fixtures, so
+// an agent can re-execute against exactly the responses it originally saw.
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Replay response headers.
+const (
+	// HeaderReplay reports how a request was replayed: "exact", "fuzzy" or "miss"
+	HeaderReplay = "X-Sentra-Replay"
+
+	// HeaderReplaySeq is the sequence number of the recorded exchange served
+	HeaderReplaySeq = "X-Sentra-Replay-Seq"
+
+	// HeaderReplayDivergence is the kind of divergence detected, if any
+	HeaderReplayDivergence = "X-Sentra-Replay-Divergence"
+)
+
+// DefaultReplayMinSimilarity is the lowest similarity a fuzzy match accepts.
+const DefaultReplayMinSimilarity = 0.5
+
+// ReplayMatch selects how requests are matched to recorded exchanges.
+type ReplayMatch string
+
+const (
+	// ReplayMatchExact serves only exchanges whose request hash is identical
+	ReplayMatchExact ReplayMatch = "exact"
+
+	// ReplayMatchFuzzy falls back to the most similar recorded request for
+	// the same endpoint and model when no exchange matches exactly
+	ReplayMatchFuzzy ReplayMatch = "fuzzy"
+)
+
+// ParseReplayMatch parses a match mode ("" = exact).
+func ParseReplayMatch(s string) (ReplayMatch, error) {
+	switch match := ReplayMatch(s); match {
+	case "":
+		return ReplayMatchExact, nil
+	case ReplayMatchExact, ReplayMatchFuzzy:
+		return match, nil
+	default:
+		return "", fmt.Errorf("unknown replay match %q (expected exact or fuzzy)", s)
+	}
+}
+
+// replayEntry is a recorded exchange prepared for matching.
+type replayEntry struct {
+	// exchange is the recorded request/response
+	exchange Exchange
+
+	// hash identifies the request (see requestHash)
+	hash string
+
+	// model is the request's "model" field ("" if none)
+	model string
+
+	// words are the request body's words, for fuzzy matching
+	words map[string]struct{}
+
+	// served is set once the exchange has been replayed
+	served bool
+}
+
+// replaySource holds the recorded run being replayed.
+type replaySource struct {
+	// runID is the recorded run ("" = replay off)
+	runID string
+
+	// match is the match mode
+	match ReplayMatch
+
+	// onDivergence is what happens to requests that differ from the recording
+	onDivergence ReplayDivergencePolicy
+
+	// entries are the replayable exchanges in recorded order
+	entries []*replayEntry
+
+	// served and misses count replayed and unmatched requests
+	served int
+	misses int
+
+	// divergences are the first maxReplayDivergences divergent requests;
+	// divergenceCount counts all of them
+	divergences     []ReplayDivergence
+	divergenceCount int
+
+	// mu guards the fields above
+	mu sync.Mutex
+}
+
+// newReplaySource creates a replay source with replay off.
+func newReplaySource() *replaySource {
+	return &replaySource{}
+}
+
+// load replaces the recording being replayed. Exchanges with a truncated
+// body cannot be replayed faithfully and are skipped. Returns the number
+// of replayable exchanges.
+func (r *replaySource) load(runID string, match ReplayMatch, onDivergence ReplayDivergencePolicy, exchanges []Exchange) int {
+	entries := make([]*replayEntry, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		if exchange.Truncated {
+			continue
+		}
+		body := decodeBody(exchange.RequestBody, exchange.BodyEncoding)
+		entries = append(entries, &replayEntry{
+			exchange: exchange,
+			hash:     requestHash(exchange.Method, exchange.Path, exchange.Query, body),
+			model:    requestModel(body),
+			words:    requestWords(body),
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runID = runID
+	r.match = match
+	r.onDivergence = onDivergence
+	r.entries = entries
+	r.served = 0
+	r.misses = 0
+	r.divergences = nil
+	r.divergenceCount = 0
+	return len(entries)
+}
+
+// stop turns replay off.
+func (r *replaySource) stop() {
+	r.load("", "", "", nil)
+}
+
+// active reports whether a recording is being replayed.
+func (r *replaySource) active() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runID != ""
+}
+
+// replayResult is the outcome of matching a request to the recording.
+type replayResult struct {
+	// exchange is the recorded exchange to serve (nil = serve from fixtures)
+	exchange *Exchange
+
+	// match is how exchange was matched
+	match ReplayMatch
+
+	// divergence reports how the request differs from the recording (nil if
+	// it is exactly the next recorded request)
+	divergence *ReplayDivergence
+
+	// reject is set when the divergence policy is to fail fast
+	reject bool
+}
+
+// next matches a request to the recording: the first unserved exchange
+// with the same hash, or, with fuzzy matching, the most similar unserved
+// one for the same endpoint and model. A request that is not exactly the
+// next recorded one is reported as a divergence; with DivergenceFail it is
+// rejected and nothing is marked served.
+func (r *replaySource) next(method, path, query string, body []byte, requestID string) replayResult {
+	hash := requestHash(method, path, query, body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expected *replayEntry
+	for _, entry := range r.entries {
+		if !entry.served {
+			expected = entry
+			break
+		}
+	}
+
+	for _, entry := range r.entries {
+		if entry.served || entry.hash != hash {
+			continue
+		}
+
+		var divergence *ReplayDivergence
+		if entry != expected {
+			divergence = &ReplayDivergence{
+				Kind:           DivergenceOrder,
+				Method:         method,
+				Path:           path,
+				ExpectedSeq:    expected.exchange.Seq,
+				ExpectedMethod: expected.exchange.Method,
+				ExpectedPath:   expected.exchange.Path,
+				MatchedSeq:     entry.exchange.Seq,
+				Message:        fmt.Sprintf("%s %s matches recorded seq %d, but seq %d was expected first", method, path, entry.exchange.Seq, expected.exchange.Seq),
+			}
+		}
+		return r.serve(entry, ReplayMatchExact, divergence, requestID)
+	}
+
+	divergence := newDivergence(method, path, query, body, expected)
+
+	if r.match == ReplayMatchFuzzy {
+		model := requestModel(body)
+		words := requestWords(body)
+
+		var best *replayEntry
+		bestSimilarity := DefaultReplayMinSimilarity
+		for _, entry := range r.entries {
+			if entry.served || entry.exchange.Method != method || entry.exchange.Path != path || entry.model != model {
+				continue
+			}
+			if similarity := jaccard(words, entry.words); similarity >= bestSimilarity && (best == nil || similarity > bestSimilarity) {
+				best, bestSimilarity = entry, similarity
+			}
+		}
+		if best != nil {
+			divergence.MatchedSeq = best.exchange.Seq
+			return r.serve(best, ReplayMatchFuzzy, divergence, requestID)
+		}
+	}
+
+	if r.recordDivergence(divergence, requestID) {
+		return replayResult{divergence: divergence, reject: true}
+	}
+	r.misses++
+	return replayResult{divergence: divergence}
+}
+
+// serve marks an entry served, unless the divergence policy rejects the
+// request. Must be called with r.mu held.
+func (r *replaySource) serve(entry *replayEntry, match ReplayMatch, divergence *ReplayDivergence, requestID string) replayResult {
+	if divergence != nil && r.recordDivergence(divergence, requestID) {
+		return replayResult{divergence: divergence, reject: true}
+	}
+
+	entry.served = true
+	r.served++
+	return replayResult{exchange: &entry.exchange, match: match, divergence: divergence}
+}
+
+// recordDivergence records a divergence and reports whether the policy
+// rejects the request. Must be called with r.mu held.
+func (r *replaySource) recordDivergence(divergence *ReplayDivergence, requestID string) bool {
+	divergence.RequestID = requestID
+	r.divergenceCount++
+	if len(r.divergences) < maxReplayDivergences {
+		r.divergences = append(r.divergences, *divergence)
+	}
+	return r.onDivergence == DivergenceFail
+}
+
+// replayStatus reports the replay source's state.
+type replayStatus struct {
+	Active          bool                   `json:"active"`
+	RunID           string                 `json:"run_id"`
+	Match           ReplayMatch            `json:"match,omitempty"`
+	OnDivergence    ReplayDivergencePolicy `json:"on_divergence,omitempty"`
+	Exchanges       int                    `json:"exchanges"`
+	Served          int                    `json:"served"`
+	Remaining       int                    `json:"remaining"`
+	Misses          int                    `json:"misses"`
+	DivergenceCount int                    `json:"divergence_count"`
+	Divergences     []ReplayDivergence     `json:"divergences"`
+}
+
+// status returns the replay source's state.
+func (r *replaySource) status() replayStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return replayStatus{
+		Active:          r.runID != "",
+		RunID:           r.runID,
+		Match:           r.match,
+		OnDivergence:    r.onDivergence,
+		Exchanges:       len(r.entries),
+		Served:          r.served,
+		Remaining:       len(r.entries) - r.served,
+		Misses:          r.misses,
+		DivergenceCount: r.divergenceCount,
+		Divergences:     append([]ReplayDivergence{}, r.divergences...),
+	}
+}
+
+// requestHash identifies a request by method, path, query and body. JSON
+// bodies are canonicalized (keys sorted, whitespace removed) so semantically
+// identical requests hash the same.
+func requestHash(method, path, query string, body []byte) string {
+	var parsed any
+	if json.Unmarshal(body, &parsed) == nil {
+		if canonical, err := json.Marshal(parsed); err == nil {
+			body = canonical
+		}
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", method, path, query)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestModel returns the "model" field of a JSON request body.
+func requestModel(body []byte) string {
+	var request struct {
+		Model string `json:"model"`
+	}
+	_ = json.Unmarshal(body, &request)
+	return request.Model
+}
+
+// requestWords returns the set of lowercase words in a request body.
+func requestWords(body []byte) map[string]struct{} {
+	words := make(map[string]struct{})
+	for _, word := range strings.FieldsFunc(strings.ToLower(string(body)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = struct{}{}
+	}
+	return words
+}
+
+// jaccard returns the Jaccard similarity of two word sets.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for word := range a {
+		if _, ok := b[word]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// decodeBody reverses encodeBody.
+func decodeBody(body, encoding string) []byte {
+	if encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return []byte(body)
+}
+
+// replaySkippedHeaders are recorded response headers the transport sets.
+var replaySkippedHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Date":              true,
+	"Trailer":           true,
+}
+
+// ReplayMiddleware answers requests from the recorded run while replay is
+// active. Unmatched requests fall through to the normal handlers (fixtures),
+// or are rejected with a divergence report under DivergenceFail. Replayed
+// responses carry the recorded status, headers (including the original
+// x-request-id) and body. Must run after CaptureMiddleware so replayed
+// exchanges are captured too.
+func (s *Server) ReplayMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.replay.active() {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				if IsBodyTooLarge(err) {
+					abortWithError(c, models.NewRequestTooLargeError(s.config.Limits.MaxBodyBytes))
+					return
+				}
+				abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		result := s.replay.next(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, body, GetRequestID(c))
+		if result.divergence != nil {
+			c.Header(HeaderReplayDivergence, string(result.divergence.Kind))
+			if result.reject {
+				abortWithDivergence(c, *result.divergence)
+				return
+			}
+			metrics.Warn(c.Request.Context(), "request diverged from replayed recording",
+				"kind", result.divergence.Kind, "detail", result.divergence.Message)
+		}
+
+		exchange := result.exchange
+		if exchange == nil {
+			c.Header(HeaderReplay, "miss")
+			c.Next()
+			return
+		}
+
+		for name, value := range exchange.ResponseHeaders {
+			if replaySkippedHeaders[http.CanonicalHeaderKey(name)] || strings.HasPrefix(name, http.TrailerPrefix) {
+				continue
+			}
+			c.Header(name, value)
+		}
+		c.Header(HeaderReplay, string(result.match))
+		c.Header(HeaderReplaySeq, strconv.FormatInt(exchange.Seq, 10))
+
+		c.Status(exchange.Status)
+		_, _ = c.Writer.Write(decodeBody(exchange.ResponseBody, exchange.BodyEncoding))
+		c.Writer.Flush()
+		c.Abort()
+	}
+}
+
+// loadReplayFile reads exchanges from a JSON file: either the response of
+// GET /_sentra/capture/exchanges ({"data": [...]}) or a bare array.
+func loadReplayFile(path string) ([]Exchange, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay source: %w", err)
+	}
+
+	var exchanges []Exchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		var list struct {
+			Data []Exchange `json:"data"`
+		}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse replay source: %w", err)
+		}
+		exchanges = list.Data
+	}
+	if len(exchanges) == 0 {
+		return nil, fmt.Errorf("replay source %s has no exchanges", path)
+	}
+	return exchanges, nil
+}
+
+// LoadReplaySource replays the exchanges recorded in a JSON file. The run
+// ID is taken from the first exchange.
+func (s *Server) LoadReplaySource(path string, match ReplayMatch, onDivergence ReplayDivergencePolicy) error {
+	exchanges, err := loadReplayFile(path)
+	if err != nil {
+		return err
+	}
+
+	runID := exchanges[0].RunID
+	if runID == "" {
+		runID = path
+	}
+	s.replay.load(runID, match, onDivergence, exchanges)
+	return nil
+}
+
+// setupReplayRoutes registers the replay admin API.
+func (s *Server) setupReplayRoutes(admin *gin.RouterGroup) {
+	admin.GET("/replay", s.handleGetReplay)
+	admin.PUT("/replay", s.handleStartReplay)
+	admin.DELETE("/replay", s.handleStopReplay)
+}
+
+// startReplayRequest is the body of PUT /_sentra/replay.
+type startReplayRequest struct {
+	// RunID is the recorded run being replayed
+	RunID string `json:"run_id" binding:"required"`
+
+	// Match is "exact" (default) or "fuzzy"
+	Match string `json:"match"`
+
+	// OnDivergence is "fallback" (default) or "fail"
+	OnDivergence string `json:"on_divergence"`
+
+	// Exchanges are the run's recorded exchanges (as captured)
+	Exchanges []Exchange `json:"exchanges" binding:"required"`
+}
+
+// handleGetReplay reports the replay source's state.
+func (s *Server) handleGetReplay(c *gin.Context) {
+	c.JSON(http.StatusOK, s.replay.status())
+}
+
+// handleStartReplay starts replaying a recorded run.
+func (s *Server) handleStartReplay(c *gin.Context) {
+	var req startReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	match, err := ParseReplayMatch(req.Match)
+	if err != nil {
+		param := "match"
+		abortWithError(c, models.NewBadRequestError(err.Error(), &param))
+		return
+	}
+
+	onDivergence, err := ParseReplayDivergencePolicy(req.OnDivergence)
+	if err != nil {
+		param := "on_divergence"
+		abortWithError(c, models.NewBadRequestError(err.Error(), &param))
+		return
+	}
+
+	s.replay.load(req.RunID, match, onDivergence, req.Exchanges)
+	c.JSON(http.StatusOK, s.replay.status())
+}
+
+// handleStopReplay stops replaying; requests are served from fixtures again.
+func (s *Server) handleStopReplay(c *gin.Context) {
+	s.replay.stop()
+	c.Status(http.StatusNoContent)
+}
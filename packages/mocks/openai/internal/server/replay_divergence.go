@@ -0,0 +1,225 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements replay divergence detection: while replaying a
+// recorded run, requests that differ from the recording (prompt,
+// parameters, endpoint or order) are reported, and either fail fast or fall
+// back to fixtures.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// maxReplayDivergences bounds the divergences kept per replay; later ones
+// are only counted.
+const maxReplayDivergences = 1000
+
+// ReplayDivergencePolicy selects what happens when a request diverges.
+type ReplayDivergencePolicy string
+
+const (
+	// DivergenceFallback serves the divergent request anyway: the fuzzy
+	// match or out-of-order exchange if there is one, fixtures otherwise
+	DivergenceFallback ReplayDivergencePolicy = "fallback"
+
+	// DivergenceFail rejects the divergent request with 409 and a
+	// structured divergence report, without consuming any exchange
+	DivergenceFail ReplayDivergencePolicy = "fail"
+)
+
+// ParseReplayDivergencePolicy parses a divergence policy ("" = fallback).
+func ParseReplayDivergencePolicy(s string) (ReplayDivergencePolicy, error) {
+	switch policy := ReplayDivergencePolicy(s); policy {
+	case "":
+		return DivergenceFallback, nil
+	case DivergenceFallback, DivergenceFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown divergence policy %q (expected fallback or fail)", s)
+	}
+}
+
+// DivergenceKind classifies how a request differs from the recording.
+type DivergenceKind string
+
+const (
+	// DivergenceOrder means the request matches a recorded exchange exactly,
+	// but not the next one in recorded order
+	DivergenceOrder DivergenceKind = "order"
+
+	// DivergencePrompt means the prompt (messages, input, instructions)
+	// differs from the next recorded request
+	DivergencePrompt DivergenceKind = "prompt"
+
+	// DivergenceParams means other parameters differ from the next
+	// recorded request
+	DivergenceParams DivergenceKind = "params"
+
+	// DivergenceEndpoint means the request is for a different endpoint
+	// than the next recorded request
+	DivergenceEndpoint DivergenceKind = "endpoint"
+
+	// DivergenceExtraRequest means every recorded exchange has been served
+	DivergenceExtraRequest DivergenceKind = "extra_request"
+)
+
+// promptFields are the request fields that carry the prompt.
+var promptFields = map[string]bool{
+	"messages":     true,
+	"input":        true,
+	"prompt":       true,
+	"instructions": true,
+}
+
+// FieldDifference is a request field whose value differs from the recording.
+type FieldDifference struct {
+	// Field is the top-level request field ("query" for the query string)
+	Field string `json:"field"`
+
+	// Expected is the recorded value (absent if the recording lacks the field)
+	Expected json.RawMessage `json:"expected,omitempty"`
+
+	// Actual is the request's value (absent if the request lacks the field)
+	Actual json.RawMessage `json:"actual,omitempty"`
+}
+
+// ReplayDivergence reports a request that differs from the recording.
+type ReplayDivergence struct {
+	// Kind classifies the divergence
+	Kind DivergenceKind `json:"kind"`
+
+	// Method and Path identify the divergent request
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	// RequestID is the divergent request's x-request-id
+	RequestID string `json:"request_id,omitempty"`
+
+	// ExpectedSeq, ExpectedMethod and ExpectedPath identify the next
+	// exchange in recorded order (absent for extra_request)
+	ExpectedSeq    int64  `json:"expected_seq,omitempty"`
+	ExpectedMethod string `json:"expected_method,omitempty"`
+	ExpectedPath   string `json:"expected_path,omitempty"`
+
+	// MatchedSeq is the exchange served instead (order and fuzzy matches)
+	MatchedSeq int64 `json:"matched_seq,omitempty"`
+
+	// Differences are the fields that differ from the expected request
+	// (prompt and params divergences)
+	Differences []FieldDifference `json:"differences,omitempty"`
+
+	// Message summarizes the divergence
+	Message string `json:"message"`
+}
+
+// newDivergence compares a request with the next recorded exchange
+// (expected, nil if all have been served).
+func newDivergence(method, path, query string, body []byte, expected *replayEntry) *ReplayDivergence {
+	divergence := &ReplayDivergence{Method: method, Path: path}
+
+	if expected == nil {
+		divergence.Kind = DivergenceExtraRequest
+		divergence.Message = fmt.Sprintf("%s %s was not in the recording: every recorded exchange has been served", method, path)
+		return divergence
+	}
+
+	recorded := expected.exchange
+	divergence.ExpectedSeq = recorded.Seq
+	divergence.ExpectedMethod = recorded.Method
+	divergence.ExpectedPath = recorded.Path
+
+	if recorded.Method != method || recorded.Path != path {
+		divergence.Kind = DivergenceEndpoint
+		divergence.Message = fmt.Sprintf("expected %s %s (seq %d), got %s %s", recorded.Method, recorded.Path, recorded.Seq, method, path)
+		return divergence
+	}
+
+	divergence.Differences = compareRequestFields(decodeBody(recorded.RequestBody, recorded.BodyEncoding), body)
+	if recorded.Query != query {
+		divergence.Differences = append(divergence.Differences, FieldDifference{
+			Field:    "query",
+			Expected: jsonString(recorded.Query),
+			Actual:   jsonString(query),
+		})
+	}
+
+	divergence.Kind = DivergenceParams
+	fields := make([]string, 0, len(divergence.Differences))
+	for _, difference := range divergence.Differences {
+		if promptFields[difference.Field] {
+			divergence.Kind = DivergencePrompt
+		}
+		fields = append(fields, difference.Field)
+	}
+	divergence.Message = fmt.Sprintf("%s %s differs from the recording (seq %d) in %v", method, path, recorded.Seq, fields)
+	return divergence
+}
+
+// compareRequestFields returns the top-level JSON fields whose values
+// differ, sorted by field. Non-JSON bodies are compared whole as "body".
+func compareRequestFields(expected, actual []byte) []FieldDifference {
+	var expectedFields, actualFields map[string]json.RawMessage
+	if json.Unmarshal(expected, &expectedFields) != nil || json.Unmarshal(actual, &actualFields) != nil {
+		if string(expected) == string(actual) {
+			return nil
+		}
+		return []FieldDifference{{Field: "body"}}
+	}
+
+	var differences []FieldDifference
+	for field, expectedValue := range expectedFields {
+		actualValue, ok := actualFields[field]
+		if !ok || !jsonEqual(expectedValue, actualValue) {
+			differences = append(differences, FieldDifference{Field: field, Expected: expectedValue, Actual: actualValue})
+		}
+	}
+	for field, actualValue := range actualFields {
+		if _, ok := expectedFields[field]; !ok {
+			differences = append(differences, FieldDifference{Field: field, Actual: actualValue})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Field < differences[j].Field
+	})
+	return differences
+}
+
+// jsonEqual reports whether two JSON values are semantically equal.
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// jsonString encodes s as a JSON string.
+func jsonString(s string) json.RawMessage {
+	encoded, _ := json.Marshal(s)
+	return encoded
+}
+
+// replayDivergenceResponse is the body of a request rejected for diverging.
+type replayDivergenceResponse struct {
+	Error      models.APIError  `json:"error"`
+	Divergence ReplayDivergence `json:"divergence"`
+}
+
+// abortWithDivergence rejects a divergent request with 409 and the report.
+func abortWithDivergence(c *gin.Context, divergence ReplayDivergence) {
+	code := "replay_divergence"
+	apiErr := models.NewAPIError(models.ErrorTypeBadRequest, "Request diverged from the replayed recording: "+divergence.Message, http.StatusConflict)
+	apiErr.Code = &code
+
+	metrics.LogError(c.Request.Context(), apiErr, GetRequestID(c))
+	c.AbortWithStatusJSON(apiErr.StatusCode, replayDivergenceResponse{Error: apiErr, Divergence: divergence})
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseReplayDivergencePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ReplayDivergencePolicy
+		wantErr bool
+	}{
+		{in: "", want: DivergenceFallback},
+		{in: "fallback", want: DivergenceFallback},
+		{in: "fail", want: DivergenceFail},
+		{in: "ignore", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseReplayDivergencePolicy(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReplayDivergencePolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReplayDivergencePolicy(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareRequestFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		// want are the differing fields
+		want []string
+	}{
+		{name: "identical", expected: `{"a":1,"b":[1,2]}`, actual: `{"b": [1, 2], "a": 1.0}`},
+		{name: "changed", expected: `{"a":1,"b":2}`, actual: `{"a":1,"b":3}`, want: []string{"b"}},
+		{name: "added and removed", expected: `{"a":1,"c":3}`, actual: `{"b":2,"c":3}`, want: []string{"a", "b"}},
+		{name: "nested change", expected: `{"messages":[{"content":"hi"}]}`, actual: `{"messages":[{"content":"hello"}]}`, want: []string{"messages"}},
+		{name: "identical non-JSON", expected: "file=a", actual: "file=a"},
+		{name: "different non-JSON", expected: "file=a", actual: "file=b", want: []string{"body"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, difference := range compareRequestFields([]byte(tt.expected), []byte(tt.actual)) {
+				got = append(got, difference.Field)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("compareRequestFields() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDivergence(t *testing.T) {
+	expected := &replayEntry{exchange: Exchange{
+		Seq:         3,
+		Method:      "POST",
+		Path:        "/v1/chat/completions",
+		Query:       "",
+		RequestBody: `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`,
+	}}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		query    string
+		body     string
+		expected *replayEntry
+		want     DivergenceKind
+		// wantFields are the differing fields
+		wantFields []string
+	}{
+		{name: "nothing left", method: "POST", path: "/v1/chat/completions", want: DivergenceExtraRequest},
+		{name: "other endpoint", method: "POST", path: "/v1/images/generations", expected: expected, want: DivergenceEndpoint},
+		{name: "other method", method: "GET", path: "/v1/chat/completions", expected: expected, want: DivergenceEndpoint},
+		{
+			name: "prompt", method: "POST", path: "/v1/chat/completions", expected: expected,
+			body:       `{"model":"gpt-4o","messages":[{"role":"user","content":"hello"}]}`,
+			want:       DivergencePrompt,
+			wantFields: []string{"messages"},
+		},
+		{
+			name: "prompt and params", method: "POST", path: "/v1/chat/completions", expected: expected,
+			body:       `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"hello"}]}`,
+			want:       DivergencePrompt,
+			wantFields: []string{"messages", "model"},
+		},
+		{
+			name: "params", method: "POST", path: "/v1/chat/completions", expected: expected,
+			body:       `{"model":"gpt-4o","temperature":0,"messages":[{"role":"user","content":"hi"}]}`,
+			want:       DivergenceParams,
+			wantFields: []string{"temperature"},
+		},
+		{
+			name: "query", method: "POST", path: "/v1/chat/completions", query: "stream=true", expected: expected,
+			body:       `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`,
+			want:       DivergenceParams,
+			wantFields: []string{"query"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newDivergence(tt.method, tt.path, tt.query, []byte(tt.body), tt.expected)
+			if got.Kind != tt.want {
+				t.Errorf("Kind = %q, want %q", got.Kind, tt.want)
+			}
+			var fields []string
+			for _, difference := range got.Differences {
+				fields = append(fields, difference.Field)
+			}
+			if fmt.Sprint(fields) != fmt.Sprint(tt.wantFields) {
+				t.Errorf("Differences = %v, want %v", fields, tt.wantFields)
+			}
+			if tt.expected != nil && got.ExpectedSeq != tt.expected.exchange.Seq {
+				t.Errorf("ExpectedSeq = %d, want %d", got.ExpectedSeq, tt.expected.exchange.Seq)
+			}
+			if got.Message == "" {
+				t.Error("Message is empty")
+			}
+		})
+	}
+}
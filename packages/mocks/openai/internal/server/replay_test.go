@@ -0,0 +1,365 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Recorded prompts; the fuzzy prompts share most of their words.
+const (
+	parisPrompt = `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"What is the weather in Paris today"}]}`
+	jokePrompt  = `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Tell me a joke about cats"}]}`
+
+	// parisReordered is parisPrompt with its keys reordered and spaced
+	parisReordered = `{"messages": [{"content": "What is the weather in Paris today", "role": "user"}], "model": "gpt-4o-mini"}`
+
+	// londonPrompt is close enough to parisPrompt for fuzzy matching
+	londonPrompt = `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"What is the weather in London today"}]}`
+
+	// parisTemperature adds a parameter to parisPrompt
+	parisTemperature = `{"model":"gpt-4o-mini","temperature":0.2,"messages":[{"role":"user","content":"What is the weather in Paris today"}]}`
+)
+
+// recordRun captures the paris and joke requests as run "rec-1" and returns
+// the exchanges.
+func recordRun(t *testing.T) []Exchange {
+	t.Helper()
+	s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/capture", `{"run_id":"rec-1"}`, nil), http.StatusOK)
+	for _, body := range []string{parisPrompt, jokePrompt} {
+		expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", body, nil), http.StatusOK)
+	}
+
+	exchanges, _ := s.exchanges.since("rec-1", 0)
+	if len(exchanges) != 2 {
+		t.Fatalf("recorded %d exchanges, want 2", len(exchanges))
+	}
+	return exchanges
+}
+
+// startReplay replays exchanges on s.
+func startReplay(t *testing.T, s *Server, match, onDivergence string, exchanges []Exchange) {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"run_id":        "rec-1",
+		"match":         match,
+		"on_divergence": onDivergence,
+		"exchanges":     exchanges,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/replay", string(body), nil), http.StatusOK)
+}
+
+func TestReplay(t *testing.T) {
+	exchanges := recordRun(t)
+
+	type replayed struct {
+		body string
+		// status is the expected status (200 if zero)
+		status int
+		// replay and seq are the X-Sentra-Replay and X-Sentra-Replay-Seq
+		// headers; divergence is X-Sentra-Replay-Divergence
+		replay     string
+		seq        string
+		divergence DivergenceKind
+	}
+
+	tests := []struct {
+		name         string
+		match        string
+		onDivergence string
+		requests     []replayed
+		wantMisses   int
+	}{
+		{
+			name: "in order",
+			requests: []replayed{
+				{body: parisPrompt, replay: "exact", seq: "1"},
+				{body: jokePrompt, replay: "exact", seq: "2"},
+			},
+		},
+		{
+			name: "canonical JSON",
+			requests: []replayed{
+				{body: parisReordered, replay: "exact", seq: "1"},
+			},
+		},
+		{
+			name: "out of order",
+			requests: []replayed{
+				{body: jokePrompt, replay: "exact", seq: "2", divergence: DivergenceOrder},
+				{body: parisPrompt, replay: "exact", seq: "1"},
+			},
+		},
+		{
+			name: "changed prompt falls back to fixtures",
+			requests: []replayed{
+				{body: londonPrompt, replay: "miss", divergence: DivergencePrompt},
+			},
+			wantMisses: 1,
+		},
+		{
+			name:  "changed prompt matched fuzzily",
+			match: "fuzzy",
+			requests: []replayed{
+				{body: londonPrompt, replay: "fuzzy", seq: "1", divergence: DivergencePrompt},
+				{body: jokePrompt, replay: "exact", seq: "2"},
+			},
+		},
+		{
+			name: "changed parameters",
+			requests: []replayed{
+				{body: parisTemperature, replay: "miss", divergence: DivergenceParams},
+			},
+			wantMisses: 1,
+		},
+		{
+			name: "extra request",
+			requests: []replayed{
+				{body: parisPrompt, replay: "exact", seq: "1"},
+				{body: jokePrompt, replay: "exact", seq: "2"},
+				{body: jokePrompt, replay: "miss", divergence: DivergenceExtraRequest},
+			},
+			wantMisses: 1,
+		},
+		{
+			name:         "fail fast without consuming",
+			onDivergence: "fail",
+			requests: []replayed{
+				{body: londonPrompt, status: http.StatusConflict, divergence: DivergencePrompt},
+				{body: jokePrompt, status: http.StatusConflict, divergence: DivergenceOrder},
+				{body: parisPrompt, replay: "exact", seq: "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+			startReplay(t, s, tt.match, tt.onDivergence, exchanges)
+
+			divergences := 0
+			for i, request := range tt.requests {
+				rec := serve(s, http.MethodPost, "/v1/chat/completions", request.body, nil)
+				wantStatus := request.status
+				if wantStatus == 0 {
+					wantStatus = http.StatusOK
+				}
+				expectStatus(t, rec, wantStatus)
+
+				if got := rec.Header().Get(HeaderReplay); got != request.replay {
+					t.Errorf("request %d: %s = %q, want %q", i, HeaderReplay, got, request.replay)
+				}
+				if got := rec.Header().Get(HeaderReplaySeq); got != request.seq {
+					t.Errorf("request %d: %s = %q, want %q", i, HeaderReplaySeq, got, request.seq)
+				}
+				if got := rec.Header().Get(HeaderReplayDivergence); got != string(request.divergence) {
+					t.Errorf("request %d: %s = %q, want %q", i, HeaderReplayDivergence, got, request.divergence)
+				}
+				if request.divergence != "" {
+					divergences++
+				}
+
+				// replayed responses are the recorded ones, request ID included
+				if request.seq != "" {
+					recorded := exchanges[request.seq[0]-'1']
+					if rec.Body.String() != recorded.ResponseBody {
+						t.Errorf("request %d: body = %s, want the recorded %s", i, rec.Body, recorded.ResponseBody)
+					}
+					if got := rec.Header().Get(HeaderRequestID); got != recorded.RequestID {
+						t.Errorf("request %d: request ID = %q, want the recorded %q", i, got, recorded.RequestID)
+					}
+				}
+			}
+
+			var status replayStatus
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/replay", "", nil), &status)
+			if status.Misses != tt.wantMisses || status.DivergenceCount != divergences || len(status.Divergences) != divergences {
+				t.Errorf("status = %d misses and %d divergences (%d kept), want %d and %d",
+					status.Misses, status.DivergenceCount, len(status.Divergences), tt.wantMisses, divergences)
+			}
+			if status.Served+status.Remaining != status.Exchanges {
+				t.Errorf("served %d + remaining %d != %d exchanges", status.Served, status.Remaining, status.Exchanges)
+			}
+		})
+	}
+}
+
+func TestReplayDivergenceReport(t *testing.T) {
+	s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+	startReplay(t, s, "exact", "fail", recordRun(t))
+
+	rec := serve(s, http.MethodGet, "/v1/files", "", nil)
+	expectStatus(t, rec, http.StatusConflict)
+
+	var got replayDivergenceResponse
+	decodeJSON(t, rec, &got)
+	if got.Error.Code == nil || *got.Error.Code != "replay_divergence" {
+		t.Errorf("code = %v, want replay_divergence", got.Error.Code)
+	}
+	want := ReplayDivergence{
+		Kind:           DivergenceEndpoint,
+		Method:         http.MethodGet,
+		Path:           "/v1/files",
+		RequestID:      rec.Header().Get(HeaderRequestID),
+		ExpectedSeq:    1,
+		ExpectedMethod: http.MethodPost,
+		ExpectedPath:   "/v1/chat/completions",
+	}
+	got.Divergence.Message = ""
+	if fmt.Sprintf("%+v", got.Divergence) != fmt.Sprintf("%+v", want) {
+		t.Errorf("divergence = %+v, want %+v", got.Divergence, want)
+	}
+}
+
+func TestStartReplay(t *testing.T) {
+	exchanges := recordRun(t)
+	truncated := append([]Exchange{}, exchanges...)
+	truncated[1].Truncated = true
+
+	tests := []struct {
+		name          string
+		body          map[string]any
+		wantStatus    int
+		wantParam     string
+		wantExchanges int
+	}{
+		{name: "defaults", body: map[string]any{"run_id": "rec-1", "exchanges": exchanges}, wantStatus: http.StatusOK, wantExchanges: 2},
+		{name: "truncated exchanges skipped", body: map[string]any{"run_id": "rec-1", "exchanges": truncated}, wantStatus: http.StatusOK, wantExchanges: 1},
+		{name: "unknown match", body: map[string]any{"run_id": "rec-1", "match": "semantic", "exchanges": exchanges}, wantStatus: http.StatusBadRequest, wantParam: "match"},
+		{name: "unknown policy", body: map[string]any{"run_id": "rec-1", "on_divergence": "ignore", "exchanges": exchanges}, wantStatus: http.StatusBadRequest, wantParam: "on_divergence"},
+		{name: "missing run", body: map[string]any{"exchanges": exchanges}, wantStatus: http.StatusBadRequest},
+		{name: "missing exchanges", body: map[string]any{"run_id": "rec-1"}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := serve(s, http.MethodPut, "/_sentra/replay", string(body), nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+			}
+			if tt.wantStatus != http.StatusOK {
+				if s.replay.active() {
+					t.Error("replay started despite the error")
+				}
+				return
+			}
+
+			var status replayStatus
+			decodeJSON(t, rec, &status)
+			if !status.Active || status.Match != ReplayMatchExact || status.OnDivergence != DivergenceFallback || status.Exchanges != tt.wantExchanges {
+				t.Errorf("status = %+v, want exact fallback replay of %d exchanges", status, tt.wantExchanges)
+			}
+
+			expectStatus(t, serve(s, http.MethodDelete, "/_sentra/replay", "", nil), http.StatusNoContent)
+			if s.replay.active() {
+				t.Error("replay still active after stopping")
+			}
+		})
+	}
+}
+
+func TestLoadReplaySource(t *testing.T) {
+	exchanges := recordRun(t)
+	array, _ := json.Marshal(exchanges)
+	list, _ := json.Marshal(map[string]any{"object": "list", "data": exchanges})
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "array", data: string(array)},
+		{name: "exchanges response", data: string(list)},
+		{name: "empty", data: `[]`, wantErr: true},
+		{name: "not JSON", data: `exchanges`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "replay.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			s := newTestServer(t, Dependencies{})
+			err := s.LoadReplaySource(path, ReplayMatchExact, DivergenceFallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadReplaySource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if status := s.replay.status(); status.RunID != "rec-1" || status.Exchanges != 2 {
+				t.Errorf("status = run %q with %d exchanges, want rec-1 with 2", status.RunID, status.Exchanges)
+			}
+		})
+	}
+
+	s := newTestServer(t, Dependencies{})
+	if err := s.LoadReplaySource(filepath.Join(t.TempDir(), "missing.json"), ReplayMatchExact, DivergenceFallback); err == nil {
+		t.Error("LoadReplaySource() of a missing file succeeded")
+	}
+}
+
+func TestParseReplayMatch(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ReplayMatch
+		wantErr bool
+	}{
+		{in: "", want: ReplayMatchExact},
+		{in: "exact", want: ReplayMatchExact},
+		{in: "fuzzy", want: ReplayMatchFuzzy},
+		{in: "Fuzzy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseReplayMatch(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReplayMatch(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReplayMatch(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "identical", a: "what is the weather", b: "what is the weather", want: 1},
+		{name: "disjoint", a: "hello there", b: "goodbye now", want: 0},
+		{name: "half shared", a: "a b c", b: "b c d", want: 0.5},
+		{name: "case and punctuation ignored", a: "Hello, World!", b: "hello world", want: 1},
+		{name: "both empty", a: "", b: "", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jaccard(requestWords([]byte(tt.a)), requestWords([]byte(tt.b))); got != tt.want {
+				t.Errorf("jaccard(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the x-request-id and openai-processing-ms response
+// headers OpenAI returns on every response, which client telemetry and
+// support tooling rely on.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAI response headers.
+const (
+	// HeaderRequestID identifies the request (e.g., "req_3f2a...")
+	HeaderRequestID = "x-request-id"
+
+	// HeaderProcessingMs is the server-side processing time in milliseconds
+	HeaderProcessingMs = "openai-processing-ms"
+)
+
+// requestIDContextKey stores the request ID. The metrics logger reads the
+// same "request_id" key.
+const requestIDContextKey = "request_id"
+
+// NewRequestID generates an OpenAI-style request ID: "req_" followed by 32
+// hex characters.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "req_" + hex.EncodeToString(b)
+}
+
+// RequestIDFromContext retrieves the request ID from ctx ("" if none).
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// GetRequestID retrieves the request ID of a gin request.
+func GetRequestID(c *gin.Context) string {
+	return RequestIDFromContext(c.Request.Context())
+}
+
+// processingWriter stamps openai-processing-ms on the response just before
+// the headers are written.
+type processingWriter struct {
+	gin.ResponseWriter
+
+	// started is when the request arrived
+	started time.Time
+
+	// stamped is set once the header has been added
+	stamped bool
+}
+
+// stamp adds the processing time header if the headers are not yet sent
+// and no handler set it (replayed responses keep the recorded value).
+func (w *processingWriter) stamp() {
+	if w.stamped || w.ResponseWriter.Written() {
+		return
+	}
+	w.stamped = true
+	if w.Header().Get(HeaderProcessingMs) != "" {
+		return
+	}
+	w.Header().Set(HeaderProcessingMs, strconv.FormatInt(time.Since(w.started).Milliseconds(), 10))
+}
+
+// WriteHeaderNow stamps the header, then writes the headers.
+func (w *processingWriter) WriteHeaderNow() {
+	w.stamp()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write stamps the header, then writes response bytes.
+func (w *processingWriter) Write(data []byte) (int, error) {
+	w.stamp()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString stamps the header, then writes response bytes.
+func (w *processingWriter) WriteString(s string) (int, error) {
+	w.stamp()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush stamps the header, then flushes (streams flush their first chunk
+// before writing anything else).
+func (w *processingWriter) Flush() {
+	w.stamp()
+	w.ResponseWriter.Flush()
+}
+
+// RequestIDMiddleware assigns every request an ID, returned in x-request-id
+// and logged with errors, and reports the processing time up to the first
+// response byte in openai-processing-ms, as OpenAI does. A streamed response
+// also gets the total processing time as an openai-processing-ms trailer
+// once the stream ends. Must run first so error responses from other
+// middleware carry both headers.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := NewRequestID()
+		c.Header(HeaderRequestID, requestID)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDContextKey, requestID))
+
+		writer := &processingWriter{ResponseWriter: c.Writer, started: time.Now()}
+		c.Writer = writer
+
+		c.Next()
+
+		// Responses with no body (e.g., 204) are written by gin after
+		// every handler has returned
+		writer.stamp()
+
+		if strings.HasPrefix(writer.Header().Get("Content-Type"), "text/event-stream") {
+			writer.Header().Set(http.TrailerPrefix+HeaderProcessingMs, strconv.FormatInt(time.Since(writer.started).Milliseconds(), 10))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "operational endpoint", method: http.MethodGet, path: "/health", status: http.StatusOK},
+		{name: "api endpoint", method: http.MethodPost, path: "/v1/chat/completions", body: `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hi"}]}`, status: http.StatusOK},
+		{name: "error response", method: http.MethodPost, path: "/v1/chat/completions", body: `{"model":"gpt-4o-mini","messages":[]}`, status: http.StatusBadRequest},
+		{name: "not found", method: http.MethodGet, path: "/v1/nothing", status: http.StatusNotFound},
+	}
+
+	s := newTestServer(t, Dependencies{})
+	seen := make(map[string]bool)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, tt.method, tt.path, tt.body, nil)
+			expectStatus(t, rec, tt.status)
+
+			requestID := rec.Header().Get(HeaderRequestID)
+			if !strings.HasPrefix(requestID, "req_") || len(requestID) != len("req_")+32 {
+				t.Errorf("%s = %q, want req_ and 32 hex digits", HeaderRequestID, requestID)
+			}
+			if seen[requestID] {
+				t.Errorf("%s %q was reused", HeaderRequestID, requestID)
+			}
+			seen[requestID] = true
+
+			if _, err := strconv.Atoi(rec.Header().Get(HeaderProcessingMs)); err != nil {
+				t.Errorf("%s = %q, want milliseconds", HeaderProcessingMs, rec.Header().Get(HeaderProcessingMs))
+			}
+		})
+	}
+}
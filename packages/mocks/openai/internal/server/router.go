@@ -0,0 +1,64 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file registers middleware and routes.
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// setupRoutes registers middleware and the operational routes.
+// API handlers are registered on the /v1 group (see APIGroup).
+func (s *Server) setupRoutes() {
+	s.engine.Use(gin.Recovery())
+	s.engine.Use(RequestIDMiddleware())
+	s.engine.Use(TracingMiddleware())
+	s.engine.Use(DrainMiddleware(s))
+
+	// Operational endpoints
+	s.engine.GET("/health", s.handleHealth)
+	s.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	s.setupAdminRoutes()
+	s.setupCapabilityRoutes()
+	s.setupRealtimeRoutes()
+
+	// OpenAI-compatible API
+	s.api = s.engine.Group("/v1",
+		s.CaptureMiddleware(),
+		s.LoadMiddleware(),
+		s.DeadlineMiddleware(),
+		LimitsMiddleware(s.config.Limits),
+		s.ReplayMiddleware(),
+		ScopeMiddleware(),
+		s.SDKMiddleware(),
+		s.IdempotencyMiddleware(),
+		s.RateLimitMiddleware(),
+		ServiceTierMiddleware(s.config.ServiceTiers, s.scheduler),
+	)
+
+	s.setupChatRoutes()
+	s.setupUsageRoutes()
+	s.setupAssistantRoutes()
+	s.setupThreadRoutes()
+	s.setupImageRoutes()
+	s.setupAudioRoutes()
+	s.setupFileRoutes()
+	s.setupFineTuningRoutes()
+	s.setupSeedRoutes()
+}
+
+// APIGroup returns the /v1 route group with the API middleware applied.
+func (s *Server) APIGroup() *gin.RouterGroup {
+	return s.api
+}
+
+// handleHealth reports whether the server is accepting requests.
+func (s *Server) handleHealth(c *gin.Context) {
+	if s.IsDraining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,274 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements SDK fingerprinting, per-run SDK usage analytics and
+// per-SDK prompt token calibration, so usage matches what each client
+// library is billed.
+package server
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// maxTrackedRuns bounds SDK usage history; the oldest run is dropped first.
+const maxTrackedRuns = 1000
+
+// SDKUsage counts requests from one SDK version.
+type SDKUsage struct {
+	// Name and Version identify the SDK (see models.SDK*)
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+
+	// UserAgent is the most recent User-Agent seen for this SDK version
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// Requests is the number of /v1 requests made
+	Requests int64 `json:"requests"`
+
+	// FirstSeen and LastSeen bound the requests (virtual clock)
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+
+	// Deprecation is set when the mock no longer matches this SDK's
+	// request shape
+	Deprecation string `json:"deprecation,omitempty"`
+}
+
+// sdkTracker records SDK usage per run.
+type sdkTracker struct {
+	// runs maps run IDs ("" = unattributed) to usage keyed by name/version
+	runs map[string]map[string]*SDKUsage
+
+	// order lists run IDs oldest first, for eviction
+	order []string
+
+	// warned holds deprecated SDK versions already logged
+	warned map[string]bool
+
+	// mu guards the fields above
+	mu sync.Mutex
+}
+
+// newSDKTracker creates an empty tracker.
+func newSDKTracker() *sdkTracker {
+	return &sdkTracker{
+		runs:   make(map[string]map[string]*SDKUsage),
+		warned: make(map[string]bool),
+	}
+}
+
+// record counts a request and reports whether this is the first request
+// seen from a deprecated SDK version.
+func (t *sdkTracker) record(runID string, sdk models.SDKFingerprint, deprecation string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage, ok := t.runs[runID]
+	if !ok {
+		if len(t.order) >= maxTrackedRuns {
+			delete(t.runs, t.order[0])
+			t.order = t.order[1:]
+		}
+		usage = make(map[string]*SDKUsage)
+		t.runs[runID] = usage
+		t.order = append(t.order, runID)
+	}
+
+	now := clock.Now()
+	key := sdk.String()
+	entry, ok := usage[key]
+	if !ok {
+		entry = &SDKUsage{
+			Name:        sdk.Name,
+			Version:     sdk.Version,
+			FirstSeen:   now,
+			Deprecation: deprecation,
+		}
+		usage[key] = entry
+	}
+	entry.UserAgent = sdk.UserAgent
+	entry.Requests++
+	entry.LastSeen = now
+
+	if deprecation == "" || t.warned[key] {
+		return false
+	}
+	t.warned[key] = true
+	return true
+}
+
+// usage returns SDK usage for a run, or merged across all runs when
+// allRuns is set, most-used first.
+func (t *sdkTracker) usage(runID string, allRuns bool) []SDKUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	merged := make(map[string]*SDKUsage)
+	for id, usage := range t.runs {
+		if !allRuns && id != runID {
+			continue
+		}
+		for key, entry := range usage {
+			total, ok := merged[key]
+			if !ok {
+				copied := *entry
+				merged[key] = &copied
+				continue
+			}
+			total.Requests += entry.Requests
+			if entry.FirstSeen.Before(total.FirstSeen) {
+				total.FirstSeen = entry.FirstSeen
+			}
+			if entry.LastSeen.After(total.LastSeen) {
+				total.LastSeen = entry.LastSeen
+				total.UserAgent = entry.UserAgent
+			}
+		}
+	}
+
+	result := make([]SDKUsage, 0, len(merged))
+	for _, entry := range merged {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].Name+result[i].Version < result[j].Name+result[j].Version
+	})
+	return result
+}
+
+// reset discards all recorded usage.
+func (t *sdkTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.runs = make(map[string]map[string]*SDKUsage)
+	t.order = nil
+	t.warned = make(map[string]bool)
+}
+
+// SDKMiddleware fingerprints the client SDK from request headers, stores it
+// on the request context, echoes it in X-Sentra-SDK and records it for the
+// run (X-Sentra-Run-Id, else the run being captured), which the request's
+// usage is also attributed to. Deprecated SDKs get an
+// X-Sentra-SDK-Deprecated header and are logged once per version.
+func (s *Server) SDKMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sdk := models.FingerprintSDK(c.Request.Header)
+		deprecation := sdk.Deprecation()
+
+		c.Header(models.HeaderSDK, sdk.String())
+		if deprecation != "" {
+			c.Header(models.HeaderSDKDeprecated, deprecation)
+		}
+		runID := c.GetHeader(HeaderRunID)
+		if runID == "" {
+			runID = s.exchanges.activeRun()
+		}
+
+		ctx := WithSDK(c.Request.Context(), sdk)
+		if runID != "" {
+			ctx = pricing.WithRunID(ctx, runID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		if s.sdks.record(runID, sdk, deprecation) {
+			metrics.Warn(c.Request.Context(), "deprecated SDK detected",
+				"sdk", sdk.String(), "run_id", runID, "reason", deprecation)
+		}
+
+		c.Next()
+	}
+}
+
+// setupSDKRoutes registers the SDK usage admin API.
+func (s *Server) setupSDKRoutes(admin *gin.RouterGroup) {
+	admin.GET("/sdks", s.handleGetSDKs)
+	admin.DELETE("/sdks", s.handleResetSDKs)
+}
+
+// handleGetSDKs returns SDK usage for one run (run_id) or all runs.
+func (s *Server) handleGetSDKs(c *gin.Context) {
+	runID, filtered := c.GetQuery("run_id")
+	usage := s.sdks.usage(runID, !filtered)
+
+	deprecated := 0
+	for _, entry := range usage {
+		if entry.Deprecation != "" {
+			deprecated++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object":     "list",
+		"data":       usage,
+		"deprecated": deprecated,
+	})
+}
+
+// handleResetSDKs discards recorded SDK usage.
+func (s *Server) handleResetSDKs(c *gin.Context) {
+	s.sdks.reset()
+	c.Status(http.StatusNoContent)
+}
+
+// CalibratePromptTokens adjusts a tiktoken prompt count for the SDK that
+// sent the request. Without a calibration table the count is unchanged.
+func (s *Server) CalibratePromptTokens(c *gin.Context, promptTokens int, shape tokenizer.PromptShape) int {
+	if s.calibration == nil {
+		return promptTokens
+	}
+	return s.calibration.Adjust(promptTokens, GetSDK(c).Name, shape)
+}
+
+// setupCalibrationRoutes registers the calibration admin API.
+func (s *Server) setupCalibrationRoutes(admin *gin.RouterGroup) {
+	if s.calibration == nil {
+		return
+	}
+
+	admin.GET("/calibration", s.handleGetCalibration)
+	admin.PUT("/calibration/:sdk", s.handleSetCalibration)
+	admin.DELETE("/calibration", s.handleResetCalibration)
+}
+
+// handleGetCalibration returns the calibration table.
+func (s *Server) handleGetCalibration(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   s.calibration.Table(),
+	})
+}
+
+// handleSetCalibration replaces the overhead for one SDK.
+func (s *Server) handleSetCalibration(c *gin.Context) {
+	var overhead tokenizer.PromptOverhead
+	if err := c.ShouldBindJSON(&overhead); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	sdk := c.Param("sdk")
+	if err := s.calibration.Set(sdk, overhead); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenizer.SDKOverhead{SDK: sdk, PromptOverhead: overhead})
+}
+
+// handleResetCalibration restores the default calibration table.
+func (s *Server) handleResetCalibration(c *gin.Context) {
+	s.calibration.Reset()
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// calibrationRequest has two messages and two tools.
+const calibrationRequest = `{
+	"model": "gpt-4o-mini",
+	"messages": [
+		{"role": "system", "content": "You are a weather assistant."},
+		{"role": "user", "content": "What's the weather in Paris?"}
+	],
+	"tools": [
+		{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object", "properties": {"city": {"type": "string"}}}}},
+		{"type": "function", "function": {"name": "get_forecast", "parameters": {"type": "object", "properties": {"city": {"type": "string"}}}}}
+	]
+}`
+
+// promptTokens sends the calibration request with a User-Agent and returns
+// its prompt tokens.
+func promptTokens(t *testing.T, s *Server, userAgent string) int {
+	t.Helper()
+
+	rec := serve(s, http.MethodPost, "/v1/chat/completions", calibrationRequest, map[string]string{"User-Agent": userAgent})
+	expectStatus(t, rec, http.StatusOK)
+
+	var resp models.ChatCompletionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp.Usage.PromptTokens
+}
+
+func TestChatCompletionCalibration(t *testing.T) {
+	shape := tokenizer.PromptShape{Messages: 2, Tools: 2}
+	calibration := tokenizer.NewCalibration()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		sdk       string
+	}{
+		{name: "openai-python", userAgent: "OpenAI/Python 1.40.0", sdk: models.SDKOpenAIPython},
+		{name: "openai-python legacy", userAgent: "OpenAI/v1 PythonBindings/0.28.1", sdk: models.SDKOpenAIPythonLegacy},
+		{name: "go-openai", userAgent: "go-openai/1.24.0", sdk: models.SDKGoOpenAI},
+		{name: "langchain-python", userAgent: "langchain-core/0.2.10", sdk: models.SDKLangChainPython},
+		{name: "langchain-js", userAgent: "langchainjs/0.2.0 OpenAI/JS 4.52.0", sdk: models.SDKLangChainJS},
+	}
+
+	s := newTestServer(t, Dependencies{Calibration: calibration})
+	baseline := promptTokens(t, s, "curl/8.0")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := baseline + calibration.Overhead(tt.sdk).Apply(shape)
+			if got := promptTokens(t, s, tt.userAgent); got != want {
+				t.Errorf("prompt tokens = %d, want %d (baseline %d)", got, want, baseline)
+			}
+		})
+	}
+
+	t.Run("without calibration", func(t *testing.T) {
+		s := newTestServer(t, Dependencies{})
+		if got := promptTokens(t, s, "langchain-core/0.2.10"); got != baseline {
+			t.Errorf("prompt tokens = %d, want the uncalibrated %d", got, baseline)
+		}
+	})
+
+	t.Run("overhead set through the admin API", func(t *testing.T) {
+		s := newTestServer(t, Dependencies{Calibration: tokenizer.NewCalibration()})
+		expectStatus(t, serve(s, http.MethodPut, "/_sentra/calibration/openai-python", `{"per_request": 7, "tools_present": 5}`, nil), http.StatusOK)
+		if got := promptTokens(t, s, "OpenAI/Python 1.40.0"); got != baseline+12 {
+			t.Errorf("prompt tokens = %d, want %d", got, baseline+12)
+		}
+	})
+}
@@ -0,0 +1,506 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements seeded state: files and vector stores declared in a
+// project's seeds/ directory are loaded through the admin API and served
+// read-only by the Files and Vector Stores APIs, so retrieval agents can be
+// tested against known documents. API calls never change seeded state, so
+// state resets leave it in place and every scenario starts from the seeds;
+// files the agent uploads are kept apart (see files.go).
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Seed collections the mock accepts.
+const (
+	// SeedFiles is the files collection
+	SeedFiles = "files"
+
+	// SeedVectorStores is the vector stores collection
+	SeedVectorStores = "vector_stores"
+)
+
+// SeedCollections lists every seed collection.
+var SeedCollections = []string{SeedFiles, SeedVectorStores}
+
+// fileSeed is a record of the files collection.
+type fileSeed struct {
+	// ID is the file ID (generated if empty)
+	ID string `json:"id"`
+
+	// Filename is the file's name
+	Filename string `json:"filename"`
+
+	// Purpose is what the file is for (default "assistants")
+	Purpose string `json:"purpose"`
+
+	// Content is the file's text
+	Content string `json:"content"`
+
+	// Attributes are returned with search results
+	Attributes map[string]string `json:"attributes"`
+}
+
+// vectorStoreSeed is a record of the vector_stores collection.
+type vectorStoreSeed struct {
+	// ID is the vector store ID (generated if empty)
+	ID string `json:"id"`
+
+	// Name is the vector store's name
+	Name string `json:"name"`
+
+	// FileIDs are seeded files to attach
+	FileIDs []string `json:"file_ids"`
+
+	// Documents are files created for, and attached to, the vector store
+	Documents []fileSeed `json:"documents"`
+
+	// Metadata is caller-defined key-value data
+	Metadata map[string]string `json:"metadata"`
+}
+
+// seedStateRequest is the body of POST /_sentra/state/seed.
+type seedStateRequest struct {
+	// Collections maps seed collections to their records
+	Collections map[string]json.RawMessage `json:"collections"`
+}
+
+// seededFile is a file and its content.
+type seededFile struct {
+	file       models.File
+	content    string
+	attributes map[string]string
+}
+
+// seededVectorStore is a vector store and its files.
+type seededVectorStore struct {
+	store   models.VectorStore
+	fileIDs []string
+}
+
+// seedStore holds the seeded files and vector stores, in seed order.
+type seedStore struct {
+	mu           sync.RWMutex
+	files        map[string]*seededFile
+	fileOrder    []string
+	vectorStores map[string]*seededVectorStore
+	storeOrder   []string
+}
+
+// newSeedStore creates an empty seed store.
+func newSeedStore() *seedStore {
+	return &seedStore{
+		files:        make(map[string]*seededFile),
+		vectorStores: make(map[string]*seededVectorStore),
+	}
+}
+
+// replace swaps in new seeded state.
+func (st *seedStore) replace(next *seedStore) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.files, st.fileOrder = next.files, next.fileOrder
+	st.vectorStores, st.storeOrder = next.vectorStores, next.storeOrder
+}
+
+// file returns a seeded file.
+func (st *seedStore) file(fileID string) (*seededFile, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	file, ok := st.files[fileID]
+	return file, ok
+}
+
+// vectorStore returns a seeded vector store.
+func (st *seedStore) vectorStore(storeID string) (*seededVectorStore, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	store, ok := st.vectorStores[storeID]
+	return store, ok
+}
+
+// addFile adds a file seed and returns its ID.
+func (st *seedStore) addFile(seed fileSeed, createdAt int64) (string, error) {
+	if seed.Filename == "" {
+		return "", fmt.Errorf("filename is required")
+	}
+	if seed.ID == "" {
+		seed.ID = models.NewFileID()
+	}
+	if _, ok := st.files[seed.ID]; ok {
+		return "", fmt.Errorf("duplicate file id %q", seed.ID)
+	}
+	if seed.Purpose == "" {
+		seed.Purpose = "assistants"
+	}
+	if seed.Attributes == nil {
+		seed.Attributes = map[string]string{}
+	}
+
+	st.files[seed.ID] = &seededFile{
+		file: models.File{
+			ID:        seed.ID,
+			Object:    "file",
+			Bytes:     len(seed.Content),
+			CreatedAt: createdAt,
+			Filename:  seed.Filename,
+			Purpose:   seed.Purpose,
+			Status:    "processed",
+		},
+		content:    seed.Content,
+		attributes: seed.Attributes,
+	}
+	st.fileOrder = append(st.fileOrder, seed.ID)
+	return seed.ID, nil
+}
+
+// addVectorStore adds a vector store seed and its documents.
+func (st *seedStore) addVectorStore(seed vectorStoreSeed, createdAt int64) error {
+	if seed.ID == "" {
+		seed.ID = models.NewVectorStoreID()
+	}
+	if _, ok := st.vectorStores[seed.ID]; ok {
+		return fmt.Errorf("duplicate vector store id %q", seed.ID)
+	}
+	if seed.Metadata == nil {
+		seed.Metadata = map[string]string{}
+	}
+
+	fileIDs := append([]string(nil), seed.FileIDs...)
+	for i, document := range seed.Documents {
+		fileID, err := st.addFile(document, createdAt)
+		if err != nil {
+			return fmt.Errorf("documents[%d]: %w", i, err)
+		}
+		fileIDs = append(fileIDs, fileID)
+	}
+
+	usage := 0
+	for _, fileID := range fileIDs {
+		file, ok := st.files[fileID]
+		if !ok {
+			return fmt.Errorf("file_ids: no file with id %q", fileID)
+		}
+		usage += file.file.Bytes
+	}
+
+	st.vectorStores[seed.ID] = &seededVectorStore{
+		store: models.VectorStore{
+			ID:         seed.ID,
+			Object:     "vector_store",
+			CreatedAt:  createdAt,
+			Name:       seed.Name,
+			UsageBytes: usage,
+			FileCounts: models.VectorStoreFileCounts{Completed: len(fileIDs), Total: len(fileIDs)},
+			Status:     "completed",
+			Metadata:   seed.Metadata,
+		},
+		fileIDs: fileIDs,
+	}
+	st.storeOrder = append(st.storeOrder, seed.ID)
+	return nil
+}
+
+// setupSeedRoutes registers the seeded Vector Stores API. Seeded files are
+// served by the Files API (see setupFileRoutes).
+func (s *Server) setupSeedRoutes() {
+	s.api.GET("/vector_stores", s.handleListVectorStores)
+	s.api.GET("/vector_stores/:vector_store_id", s.handleGetVectorStore)
+	s.api.GET("/vector_stores/:vector_store_id/files", s.handleListVectorStoreFiles)
+	s.api.POST("/vector_stores/:vector_store_id/search", s.handleSearchVectorStore)
+}
+
+// handleSeedState replaces the seeded state. Collections that are not sent
+// are emptied.
+func (s *Server) handleSeedState(c *gin.Context) {
+	var req seedStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return
+	}
+
+	param := "collections"
+	for collection := range req.Collections {
+		if !slices.Contains(SeedCollections, collection) {
+			abortWithError(c, models.NewBadRequestError(
+				fmt.Sprintf("unknown seed collection %q (must be one of: %s)", collection, strings.Join(SeedCollections, ", ")), &param))
+			return
+		}
+	}
+
+	var files []fileSeed
+	var vectorStores []vectorStoreSeed
+	if err := decodeSeeds(req.Collections[SeedFiles], &files); err != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("files: %v", err), &param))
+		return
+	}
+	if err := decodeSeeds(req.Collections[SeedVectorStores], &vectorStores); err != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("vector_stores: %v", err), &param))
+		return
+	}
+
+	next := newSeedStore()
+	now := clock.Now().Unix()
+	for i, seed := range files {
+		if _, err := next.addFile(seed, now); err != nil {
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("files[%d]: %v", i, err), &param))
+			return
+		}
+	}
+	for i, seed := range vectorStores {
+		if err := next.addVectorStore(seed, now); err != nil {
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("vector_stores[%d]: %v", i, err), &param))
+			return
+		}
+	}
+
+	s.seeds.replace(next)
+
+	metrics.Info(c.Request.Context(), "mock state seeded",
+		"files", len(next.files),
+		"vector_stores", len(next.vectorStores),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"seeded": gin.H{
+			SeedFiles:        len(next.files),
+			SeedVectorStores: len(next.vectorStores),
+		},
+	})
+}
+
+// decodeSeeds decodes a collection's records, rejecting unknown fields so
+// typos in seed files are reported rather than ignored.
+func decodeSeeds(raw json.RawMessage, records interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(records)
+}
+
+// vectorStoreNotFound aborts with the API's error for an unknown vector store.
+func vectorStoreNotFound(c *gin.Context, storeID string) {
+	abortWithError(c, models.NewNotFoundError(fmt.Sprintf("No vector store found with id '%s'.", storeID)))
+}
+
+// handleListVectorStores lists the seeded vector stores (?order=, ?limit=).
+func (s *Server) handleListVectorStores(c *gin.Context) {
+	s.seeds.mu.RLock()
+	stores := make([]models.VectorStore, len(s.seeds.storeOrder))
+	for i, storeID := range s.seeds.storeOrder {
+		stores[i] = s.seeds.vectorStores[storeID].store
+	}
+	ids := append([]string(nil), s.seeds.storeOrder...)
+	s.seeds.mu.RUnlock()
+
+	page, apiErr := parseThreadListPage(c, len(ids))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.VectorStore, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, stores[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// handleGetVectorStore returns a vector store.
+func (s *Server) handleGetVectorStore(c *gin.Context) {
+	storeID := c.Param("vector_store_id")
+	store, ok := s.seeds.vectorStore(storeID)
+	if !ok {
+		vectorStoreNotFound(c, storeID)
+		return
+	}
+	c.JSON(http.StatusOK, store.store)
+}
+
+// handleListVectorStoreFiles lists a vector store's files (?order=, ?limit=).
+func (s *Server) handleListVectorStoreFiles(c *gin.Context) {
+	storeID := c.Param("vector_store_id")
+
+	s.seeds.mu.RLock()
+	store, ok := s.seeds.vectorStores[storeID]
+	var files []models.VectorStoreFile
+	if ok {
+		for _, fileID := range store.fileIDs {
+			file := s.seeds.files[fileID]
+			files = append(files, models.VectorStoreFile{
+				ID:            fileID,
+				Object:        "vector_store.file",
+				CreatedAt:     store.store.CreatedAt,
+				VectorStoreID: storeID,
+				UsageBytes:    file.file.Bytes,
+				Status:        "completed",
+			})
+		}
+	}
+	s.seeds.mu.RUnlock()
+
+	if !ok {
+		vectorStoreNotFound(c, storeID)
+		return
+	}
+
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(ids))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.VectorStoreFile, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, files[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// handleSearchVectorStore searches a vector store's files. Files are split
+// into paragraphs and scored by the share of query terms each contains, so
+// results are deterministic and follow the seeded documents' wording.
+func (s *Server) handleSearchVectorStore(c *gin.Context) {
+	var req models.VectorStoreSearchRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	storeID := c.Param("vector_store_id")
+	terms := searchTerms(req.Query)
+
+	s.seeds.mu.RLock()
+	store, ok := s.seeds.vectorStores[storeID]
+	var results []models.VectorStoreSearchResult
+	if ok {
+		for _, fileID := range store.fileIDs {
+			file := s.seeds.files[fileID]
+			if result, matched := searchFile(file, terms); matched {
+				results = append(results, result)
+			}
+		}
+	}
+	s.seeds.mu.RUnlock()
+
+	if !ok {
+		vectorStoreNotFound(c, storeID)
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > req.Limit() {
+		results = results[:req.Limit()]
+	}
+	if results == nil {
+		results = []models.VectorStoreSearchResult{}
+	}
+
+	c.JSON(http.StatusOK, models.VectorStoreSearchResponse{
+		Object:      "vector_store.search_results.page",
+		SearchQuery: req.Query,
+		Data:        results,
+	})
+}
+
+// searchFile scores a file's paragraphs against the query terms. The file
+// scores as its best paragraph and returns every matching paragraph, best
+// first.
+func searchFile(file *seededFile, terms []string) (models.VectorStoreSearchResult, bool) {
+	type chunk struct {
+		text  string
+		score float64
+	}
+
+	var chunks []chunk
+	for _, paragraph := range strings.Split(strings.ReplaceAll(file.content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if score := termOverlap(paragraph, terms); score > 0 {
+			chunks = append(chunks, chunk{text: paragraph, score: score})
+		}
+	}
+	if len(chunks) == 0 {
+		return models.VectorStoreSearchResult{}, false
+	}
+
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunks[i].score > chunks[j].score
+	})
+
+	result := models.VectorStoreSearchResult{
+		FileID:     file.file.ID,
+		Filename:   file.file.Filename,
+		Score:      math.Round(chunks[0].score*10000) / 10000,
+		Attributes: file.attributes,
+	}
+	for _, chunk := range chunks {
+		result.Content = append(result.Content, models.VectorStoreSearchContent{Type: "text", Text: chunk.text})
+	}
+	return result, true
+}
+
+// termOverlap returns the share of terms that appear in text.
+func termOverlap(text string, terms []string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	words := make(map[string]bool)
+	for _, word := range searchTerms(text) {
+		words[word] = true
+	}
+
+	matched := 0
+	for _, term := range terms {
+		if words[term] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(terms))
+}
+
+// searchTerms splits text into distinct lowercase words.
+func searchTerms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
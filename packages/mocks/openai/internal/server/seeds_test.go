@@ -0,0 +1,132 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// supportSeed seeds a vector store of two support documents.
+const supportSeed = `{"collections":{
+	"files":[{"id":"file-shipping","filename":"shipping.md","content":"Orders ship within 2 days.\n\nInternational orders ship within 7 days."}],
+	"vector_stores":[{
+		"id":"vs_support",
+		"name":"Support",
+		"file_ids":["file-shipping"],
+		"documents":[{"id":"file-refunds","filename":"refunds.md","content":"Refunds are issued within 5 days.","attributes":{"team":"billing"}}]
+	}]
+}}`
+
+func TestSeedState(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		wantParam string
+	}{
+		{name: "files and vector stores", body: supportSeed, status: http.StatusOK},
+		{name: "empty", body: `{"collections":{}}`, status: http.StatusOK},
+		{name: "unknown collection", body: `{"collections":{"assistants":[]}}`, status: http.StatusBadRequest, wantParam: "collections"},
+		{name: "unknown field", body: `{"collections":{"files":[{"filename":"a.md","body":"typo"}]}}`, status: http.StatusBadRequest, wantParam: "collections"},
+		{name: "missing filename", body: `{"collections":{"files":[{"content":"text"}]}}`, status: http.StatusBadRequest, wantParam: "collections"},
+		{name: "duplicate file", body: `{"collections":{"files":[{"id":"file-a","filename":"a.md"},{"id":"file-a","filename":"b.md"}]}}`, status: http.StatusBadRequest, wantParam: "collections"},
+		{name: "unknown file id", body: `{"collections":{"vector_stores":[{"file_ids":["file-missing"]}]}}`, status: http.StatusBadRequest, wantParam: "collections"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+			expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/seed", `{"collections":{"files":[{"id":"file-old","filename":"old.md"}]}}`, nil), http.StatusOK)
+
+			rec := serve(s, http.MethodPost, "/_sentra/state/seed", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				// A rejected seed leaves the previous one in place
+				expectStatus(t, serve(s, http.MethodGet, "/v1/files/file-old", "", nil), http.StatusOK)
+				return
+			}
+
+			// Seeding replaces the previous seed
+			expectStatus(t, serve(s, http.MethodGet, "/v1/files/file-old", "", nil), http.StatusNotFound)
+		})
+	}
+}
+
+func TestGetVectorStore(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/seed", supportSeed, nil), http.StatusOK)
+
+	rec := serve(s, http.MethodGet, "/v1/vector_stores/vs_support", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var store models.VectorStore
+	decodeJSON(t, rec, &store)
+	if store.Name != "Support" || store.FileCounts.Total != 2 || store.FileCounts.Completed != 2 || store.Status != "completed" {
+		t.Errorf("vector store = %+v", store)
+	}
+
+	rec = serve(s, http.MethodGet, "/v1/vector_stores/vs_support/files?order=asc", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var files struct {
+		Data []models.VectorStoreFile `json:"data"`
+	}
+	decodeJSON(t, rec, &files)
+	var ids []string
+	for _, file := range files.Data {
+		ids = append(ids, file.ID)
+	}
+	if want := []string{"file-shipping", "file-refunds"}; !slices.Equal(ids, want) {
+		t.Errorf("vector store files = %v, want %v", ids, want)
+	}
+
+	expectStatus(t, serve(s, http.MethodGet, "/v1/vector_stores/vs_missing", "", nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodGet, "/v1/vector_stores/vs_missing/files", "", nil), http.StatusNotFound)
+}
+
+func TestSearchVectorStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		status     int
+		wantFiles  []string
+		wantChunks int
+	}{
+		{name: "best file first", body: `{"query":"How long do international orders take to ship?"}`, status: http.StatusOK, wantFiles: []string{"file-shipping"}, wantChunks: 2},
+		{name: "matches several files", body: `{"query":"days"}`, status: http.StatusOK, wantFiles: []string{"file-shipping", "file-refunds"}, wantChunks: 2},
+		{name: "max results", body: `{"query":"days","max_num_results":1}`, status: http.StatusOK, wantFiles: []string{"file-shipping"}, wantChunks: 2},
+		{name: "no match", body: `{"query":"warranty"}`, status: http.StatusOK},
+		{name: "missing query", body: `{}`, status: http.StatusBadRequest},
+	}
+
+	s := newTestServer(t, Dependencies{})
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/seed", supportSeed, nil), http.StatusOK)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodPost, "/v1/vector_stores/vs_support/search", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var resp models.VectorStoreSearchResponse
+			decodeJSON(t, rec, &resp)
+			var files []string
+			for _, result := range resp.Data {
+				files = append(files, result.FileID)
+			}
+			if !slices.Equal(files, tt.wantFiles) {
+				t.Errorf("results = %v, want %v", files, tt.wantFiles)
+			}
+			if len(resp.Data) > 0 && len(resp.Data[0].Content) != tt.wantChunks {
+				t.Errorf("best result has %d chunks, want %d", len(resp.Data[0].Content), tt.wantChunks)
+			}
+		})
+	}
+
+	expectStatus(t, serve(s, http.MethodPost, "/v1/vector_stores/vs_missing/search", `{"query":"days"}`, nil), http.StatusNotFound)
+}
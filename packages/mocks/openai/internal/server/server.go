@@ -0,0 +1,463 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the server lifecycle, including graceful shutdown.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/behavior"
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/latency"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// Config contains HTTP server configuration.
+type Config struct {
+	// Version is the build version reported by capability discovery
+	Version string
+
+	// Host is the interface to listen on
+	Host string
+
+	// Port is the port to listen on
+	Port int
+
+	// ReadTimeout is the maximum duration for reading a request
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the maximum duration for writing a response.
+	// Must be long enough for the slowest simulated stream; 0 derives it
+	// from Limits.RequestTimeout (see EffectiveWriteTimeout).
+	WriteTimeout time.Duration
+
+	// IdleTimeout is how long keep-alive connections stay open
+	IdleTimeout time.Duration
+
+	// DrainTimeout is how long in-flight requests and SSE streams may run
+	// after shutdown starts before they are cut off
+	DrainTimeout time.Duration
+
+	// ShutdownTimeout bounds flushing usage and tracing after draining
+	ShutdownTimeout time.Duration
+
+	// Limits configures request size and timeout enforcement
+	Limits LimitsConfig
+
+	// ServiceTiers maps requests to service tiers
+	ServiceTiers ServiceTierConfig
+
+	// Capture bounds full-trace exchange capture
+	Capture CaptureConfig
+
+	// Images configures generated image URLs
+	Images ImagesConfig
+
+	// Files configures file uploads
+	Files FilesConfig
+
+	// FineTuning configures the fine-tuning job timeline
+	FineTuning FineTuningConfig
+
+	// Streams caps simultaneous SSE streams
+	Streams StreamsConfig
+}
+
+// DefaultConfig returns default server configuration.
+func DefaultConfig() Config {
+	return Config{
+		Version:         "dev",
+		Host:            "0.0.0.0",
+		Port:            8080,
+		ReadTimeout:     60 * time.Second,
+		WriteTimeout:    0,
+		IdleTimeout:     120 * time.Second,
+		DrainTimeout:    30 * time.Second,
+		ShutdownTimeout: 10 * time.Second,
+		Limits:          DefaultLimitsConfig(),
+		ServiceTiers:    DefaultServiceTierConfig(),
+		Capture:         DefaultCaptureConfig(),
+		Images:          DefaultImagesConfig(),
+		Files:           DefaultFilesConfig(),
+		FineTuning:      DefaultFineTuningConfig(),
+		Streams:         DefaultStreamsConfig(),
+	}
+}
+
+// writeTimeoutMargin is how much longer than the request timeout a response
+// may take to write, so the 504 of a request that hits the timeout (or the
+// end of a stream just under it) still reaches the client.
+const writeTimeoutMargin = 30 * time.Second
+
+// EffectiveWriteTimeout returns the HTTP server's write timeout: WriteTimeout
+// if set, otherwise Limits.RequestTimeout plus a margin. Without either,
+// writes are not time limited.
+func (c Config) EffectiveWriteTimeout() time.Duration {
+	if c.WriteTimeout > 0 {
+		return c.WriteTimeout
+	}
+	if c.Limits.RequestTimeout > 0 {
+		return c.Limits.RequestTimeout + writeTimeoutMargin
+	}
+	return 0
+}
+
+// Server is the OpenAI mock HTTP server.
+type Server struct {
+	// config is the server configuration
+	config Config
+
+	// engine is the gin router
+	engine *gin.Engine
+
+	// api is the /v1 route group
+	api *gin.RouterGroup
+
+	// httpServer is the underlying HTTP server
+	httpServer *http.Server
+
+	// tracker is flushed to storage on shutdown (optional)
+	tracker *pricing.Tracker
+
+	// storage is closed on shutdown (optional)
+	storage store.Storage
+
+	// scheduler queues requests by service tier (optional)
+	scheduler *behavior.PriorityScheduler
+
+	// experiments runs A/B fixture experiments (optional)
+	experiments *fixtures.Experiments
+
+	// latency receives injected latency faults (optional)
+	latency *latency.Simulator
+
+	// errorInjector is restored when faults are cleared (optional)
+	errorInjector *behavior.ErrorInjector
+
+	// limiter has its default tier overridden per scenario (optional)
+	limiter *ratelimit.Limiter
+
+	// brownouts degrade models under load (optional)
+	brownouts *behavior.BrownoutSimulator
+
+	// load measures the API request rate brownouts depend on
+	load *behavior.LoadSimulator
+
+	// calibration adjusts prompt tokens per SDK (optional)
+	calibration *tokenizer.Calibration
+
+	// tokenizer counts the tokens of chat requests and captured prompts
+	tokenizer tokenizer.Counter
+
+	// synthesizer builds responses for unmatched prompts (optional)
+	synthesizer *generator.Synthesizer
+
+	// fixtures has its usage statistics reset between scenarios (optional)
+	fixtures *fixtures.Store
+
+	// matcher picks the fixture answering a chat completion
+	matcher *fixtures.Matcher
+
+	// isolation tracks the scenario the mock state was last reset for
+	isolation isolationState
+
+	// overrides tracks scenario-scoped configuration overrides
+	overrides overrideState
+
+	// exchanges buffers captured exchanges for full-trace recording
+	exchanges *exchangeLog
+
+	// replay serves responses from a recorded run
+	replay *replaySource
+
+	// sdks records which client SDKs each run used
+	sdks *sdkTracker
+
+	// deprecations schedules simulated model shutdowns
+	deprecations *modelDeprecations
+
+	// assistants holds Assistants API assistants
+	assistants *assistantStore
+
+	// idempotency keeps the responses of requests sent with an Idempotency-Key
+	idempotency store.Storage
+
+	// threads holds Assistants API threads
+	threads *threadStore
+
+	// images holds generated images served from /images/{id}
+	images *imageStore
+
+	// seeds holds the seeded files and vector stores
+	seeds *seedStore
+
+	// files holds uploaded files
+	files *fileStore
+
+	// fineTuning holds fine-tuning jobs
+	fineTuning *fineTuningStore
+
+	// deadline cancels requests when the test run times out
+	deadline *runDeadline
+
+	// draining is set once shutdown starts; new requests are rejected
+	draining atomic.Bool
+
+	// streams tracks active SSE streams so shutdown can wait for them
+	streams       sync.WaitGroup
+	activeStreams atomic.Int64
+
+	// streamsPerKey caps simultaneous streams per API key
+	streamsPerKey *streamLimiter
+
+	// shutdownOnce makes Shutdown idempotent
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// Dependencies are the components the server owns during shutdown.
+type Dependencies struct {
+	// Tracker is flushed after streams drain (optional)
+	Tracker *pricing.Tracker
+
+	// Storage is closed last (optional)
+	Storage store.Storage
+
+	// Scheduler simulates limited capacity with priority queueing (optional)
+	Scheduler *behavior.PriorityScheduler
+
+	// Experiments are reported through the admin API (optional)
+	Experiments *fixtures.Experiments
+
+	// Latency receives latency faults through the admin API (optional)
+	Latency *latency.Simulator
+
+	// ErrorInjector is reset through the admin API (optional)
+	ErrorInjector *behavior.ErrorInjector
+
+	// Limiter has its default tier overridden through the admin API (optional)
+	Limiter *ratelimit.Limiter
+
+	// Brownouts reject requests for models overloaded at the current load (optional)
+	Brownouts *behavior.BrownoutSimulator
+
+	// Calibration adjusts prompt tokens per SDK (optional)
+	Calibration *tokenizer.Calibration
+
+	// Tokenizer counts tokens, e.g. a CachedTokenizer (optional; defaults
+	// to an uncached tokenizer)
+	Tokenizer tokenizer.Counter
+
+	// Synthesizer builds responses for prompts no fixture matches (optional)
+	Synthesizer *generator.Synthesizer
+
+	// Fixtures answers Assistants API runs and has its usage statistics
+	// reset between scenarios (optional)
+	Fixtures *fixtures.Store
+}
+
+// New creates a new server.
+func New(config Config, deps Dependencies) *Server {
+	engine := gin.New()
+
+	s := &Server{
+		config:        config,
+		engine:        engine,
+		tracker:       deps.Tracker,
+		storage:       deps.Storage,
+		scheduler:     deps.Scheduler,
+		experiments:   deps.Experiments,
+		latency:       deps.Latency,
+		errorInjector: deps.ErrorInjector,
+		limiter:       deps.Limiter,
+		brownouts:     deps.Brownouts,
+		load:          behavior.NewLoadSimulator(),
+		calibration:   deps.Calibration,
+		tokenizer:     newTokenizer(deps.Tokenizer),
+		synthesizer:   deps.Synthesizer,
+		fixtures:      deps.Fixtures,
+		matcher:       newChatMatcher(deps.Fixtures, deps.Experiments, deps.Synthesizer),
+		exchanges:     newExchangeLog(config.Capture),
+		replay:        newReplaySource(),
+		sdks:          newSDKTracker(),
+		deprecations:  newModelDeprecations(),
+		assistants:    newAssistantStore(deps.Storage),
+		idempotency:   newIdempotencyStore(deps.Storage),
+		threads:       newThreadStore(),
+		images:        newImageStore(config.Images),
+		seeds:         newSeedStore(),
+		files:         newFileStore(deps.Storage, config.Files),
+		fineTuning:    newFineTuningStore(),
+		deadline:      newRunDeadline(),
+		streamsPerKey: newStreamLimiter(config.Streams.MaxPerKey),
+	}
+
+	s.httpServer = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", config.Host, config.Port),
+		Handler:      engine,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.EffectiveWriteTimeout(),
+		IdleTimeout:  config.IdleTimeout,
+	}
+
+	s.setupRoutes()
+
+	return s
+}
+
+// Engine returns the gin router so handlers can be registered.
+func (s *Server) Engine() *gin.Engine {
+	return s.engine
+}
+
+// Synthesizer returns the response synthesizer handlers attach to their
+// fixture matcher, or nil if synthesis is disabled.
+func (s *Server) Synthesizer() *generator.Synthesizer {
+	return s.synthesizer
+}
+
+// Run starts the server and blocks until SIGINT/SIGTERM or ctx is done,
+// then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		metrics.LogStartup(s.config.Port, gin.Mode())
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("server failed: %w", err)
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err, ok := <-errCh:
+		if ok {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	metrics.LogShutdown("signal received")
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown stops the server gracefully:
+//  1. stop accepting new requests (new requests on kept-alive connections get 503)
+//  2. let in-flight requests and SSE streams finish, up to DrainTimeout
+//  3. cut off anything still running
+//  4. flush usage tracking to storage and close tracing exporters
+//  5. close storage
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = s.shutdown(ctx)
+	})
+	return s.shutdownErr
+}
+
+// shutdown implements Shutdown.
+func (s *Server) shutdown(ctx context.Context) error {
+	s.draining.Store(true)
+
+	// Drain in-flight requests and streams
+	drainCtx, cancel := context.WithTimeout(ctx, s.config.DrainTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.httpServer.Shutdown(drainCtx); err != nil {
+		metrics.Warn(ctx, "drain timeout exceeded, closing remaining connections",
+			"active_streams", s.activeStreams.Load(),
+			"drain_timeout", s.config.DrainTimeout.String(),
+		)
+		s.httpServer.Close()
+	}
+
+	if !s.waitForStreams(drainCtx) {
+		metrics.Warn(ctx, "streams still active after drain", "active_streams", s.activeStreams.Load())
+	}
+
+	// Flush usage and traces
+	flushCtx, cancelFlush := context.WithTimeout(ctx, s.config.ShutdownTimeout)
+	defer cancelFlush()
+
+	if s.tracker != nil {
+		if err := s.tracker.Flush(flushCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush usage: %w", err))
+		}
+	}
+
+	if err := metrics.ShutdownTracing(flushCtx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if s.storage != nil {
+		if err := s.storage.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close storage: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// waitForStreams waits until all tracked streams finish or ctx is done.
+// Returns false if ctx expired first.
+func (s *Server) waitForStreams(ctx context.Context) bool {
+	done := make(chan struct{})
+	go func() {
+		s.streams.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// BeginStream registers an active SSE stream. The returned function must be
+// called when the stream ends. Shutdown waits for registered streams to end
+// (up to DrainTimeout) so recordings aren't corrupted by cut-off streams.
+// Handlers use StartStream, which also enforces the per-key stream limit.
+func (s *Server) BeginStream() func() {
+	s.streams.Add(1)
+	s.activeStreams.Add(1)
+	metrics.IncrementStreamingConnections()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			metrics.DecrementStreamingConnections()
+			s.activeStreams.Add(-1)
+			s.streams.Done()
+		})
+	}
+}
+
+// ActiveStreams returns the number of active SSE streams.
+func (s *Server) ActiveStreams() int64 {
+	return s.activeStreams.Load()
+}
+
+// IsDraining returns true once shutdown has started.
+func (s *Server) IsDraining() bool {
+	return s.draining.Load()
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkoukk/tiktoken-go"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	// Count with the estimate rather than download encodings
+	tiktoken.SetBpeLoader(offlineBpeLoader{})
+
+	os.Exit(m.Run())
+}
+
+// offlineBpeLoader fails to load any encoding.
+type offlineBpeLoader struct{}
+
+func (offlineBpeLoader) LoadTiktokenBpe(string) (map[string]int, error) {
+	return nil, errors.New("encodings are not downloaded in tests")
+}
+
+// byteBpeLoader loads an encoding with one token per byte.
+type byteBpeLoader struct{}
+
+func (byteBpeLoader) LoadTiktokenBpe(string) (map[string]int, error) {
+	ranks := make(map[string]int, 256)
+	for b := 0; b < 256; b++ {
+		ranks[string([]byte{byte(b)})] = b
+	}
+	return ranks, nil
+}
+
+// newTestServer creates a server with the default configuration.
+func newTestServer(t *testing.T, deps Dependencies) *Server {
+	t.Helper()
+	return New(DefaultConfig(), deps)
+}
+
+// serve sends a request with a JSON body (empty for none) through the
+// server's router and returns the recorded response.
+func serve(s *Server, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer sk-test")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, req)
+	return rec
+}
+
+// formFile is a file part of a multipart request.
+type formFile struct {
+	field    string
+	filename string
+	data     []byte
+}
+
+// serveMultipart sends a multipart/form-data POST with the given fields and
+// files through the server's router and returns the recorded response.
+func serveMultipart(t *testing.T, s *Server, path string, fields map[string]string, files ...formFile) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.field, file.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write(file.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer sk-test")
+
+	rec := httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, req)
+	return rec
+}
+
+// expectStatus fails the test unless the response has the given status.
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, status, rec.Body.String())
+	}
+}
+
+// decodeJSON decodes the JSON body of a response into v.
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response: %v (body: %s)", err, rec.Body.String())
+	}
+}
+
+// errorParam returns the param of an error response, or "" if it has none.
+func errorParam(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var resp models.ErrorResponse
+	decodeJSON(t, rec, &resp)
+	if resp.Error.Param == nil {
+		return ""
+	}
+	return *resp.Error.Param
+}
+
+func TestEffectiveWriteTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		writeTimeout   time.Duration
+		requestTimeout time.Duration
+		want           time.Duration
+	}{
+		{name: "derived from the request timeout", requestTimeout: 10 * time.Minute, want: 10*time.Minute + writeTimeoutMargin},
+		{name: "explicit", writeTimeout: 2 * time.Hour, requestTimeout: 10 * time.Minute, want: 2 * time.Hour},
+		{name: "no request timeout", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.WriteTimeout = tt.writeTimeout
+			config.Limits.RequestTimeout = tt.requestTimeout
+
+			if got := config.EffectiveWriteTimeout(); got != tt.want {
+				t.Errorf("EffectiveWriteTimeout() = %v, want %v", got, tt.want)
+			}
+			if got := New(config, Dependencies{}).httpServer.WriteTimeout; got != tt.want {
+				t.Errorf("http.Server.WriteTimeout = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The default lets a request run its whole timeout
+	if config := DefaultConfig(); config.EffectiveWriteTimeout() <= config.Limits.RequestTimeout {
+		t.Errorf("default write timeout %v does not outlast the request timeout %v", config.EffectiveWriteTimeout(), config.Limits.RequestTimeout)
+	}
+}
@@ -0,0 +1,348 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements scenario isolation: state that builds up as a
+// scenario runs (usage, rate limit buckets, fixture statistics, assistants,
+// threads, images, uploaded files, fine-tuning jobs and the settings scenario steps change) is snapshotted and reset
+// before the next scenario, so tests do not depend on the order they run in.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/locale"
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// Kinds of mock state that can be reset.
+const (
+	// StateUsage is tracked usage and costs, except the daily usage
+	// attributed to a run (kept for cost exports)
+	StateUsage = "usage"
+
+	// StateRateLimits is the rate limit buckets and counters
+	StateRateLimits = "rate_limits"
+
+	// StateFixtures is the fixture usage statistics
+	StateFixtures = "fixtures"
+
+	// StateAssistants is the Assistants API assistants
+	StateAssistants = "assistants"
+
+	// StateThreads is the Assistants API threads
+	StateThreads = "threads"
+
+	// StateImages is the generated images
+	StateImages = "images"
+
+	// StateFiles is the uploaded files
+	StateFiles = "files"
+
+	// StateFineTuning is the fine-tuning jobs and their fine-tuned models
+	StateFineTuning = "fine_tuning"
+
+	// StateOverrides is the scenario-scoped configuration overrides
+	StateOverrides = "overrides"
+
+	// StateFaults is the injected latency faults and error injection settings
+	StateFaults = "faults"
+
+	// StateClock is the virtual clock
+	StateClock = "clock"
+
+	// StateLocale is the simulated locale
+	StateLocale = "locale"
+
+	// StateDeadline is the test run deadline
+	StateDeadline = "deadline"
+)
+
+// StateKinds lists every kind of resettable state, in reset order.
+var StateKinds = []string{
+	StateUsage,
+	StateRateLimits,
+	StateFixtures,
+	StateAssistants,
+	StateThreads,
+	StateImages,
+	StateFiles,
+	StateFineTuning,
+	StateOverrides,
+	StateFaults,
+	StateClock,
+	StateLocale,
+	StateDeadline,
+}
+
+// isolationState tracks the scenario the mock was last reset for.
+type isolationState struct {
+	// mu serializes resets
+	mu sync.Mutex
+
+	// scenario is the scenario named by the last reset
+	scenario string
+
+	// resetAt is when the last reset happened (zero if never)
+	resetAt time.Time
+}
+
+// StateSnapshot summarizes the mock state.
+type StateSnapshot struct {
+	// Scenario is the scenario the state was last reset for
+	Scenario string `json:"scenario,omitempty"`
+
+	// ResetAt is when the state was last reset (Unix seconds, 0 if never)
+	ResetAt int64 `json:"reset_at"`
+
+	// Usage is the usage tracked since the last reset (nil without tracking)
+	Usage *UsageSnapshot `json:"usage,omitempty"`
+
+	// RateLimits are the rate limit counters (nil without rate limiting)
+	RateLimits *RateLimitSnapshot `json:"rate_limits,omitempty"`
+
+	// Fixtures are the fixture usage statistics (nil without a fixture store)
+	Fixtures *FixtureSnapshot `json:"fixtures,omitempty"`
+
+	// Assistants is the number of Assistants API assistants
+	Assistants int `json:"assistants"`
+
+	// Threads is the number of Assistants API threads
+	Threads int `json:"threads"`
+
+	// Images is the number of stored generated images
+	Images int `json:"images"`
+
+	// Files is the number of uploaded files
+	Files int `json:"files"`
+
+	// FineTuningJobs is the number of fine-tuning jobs
+	FineTuningJobs int `json:"fine_tuning_jobs"`
+
+	// Overrides is whether scenario overrides are applied
+	Overrides bool `json:"overrides"`
+
+	// ClockOffsetSeconds is how far the virtual clock is ahead of real time
+	ClockOffsetSeconds float64 `json:"clock_offset_seconds"`
+}
+
+// UsageSnapshot is tracked usage summed over every model.
+type UsageSnapshot struct {
+	Requests     int64   `json:"requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalCost    float64 `json:"total_cost"`
+}
+
+// RateLimitSnapshot is the rate limiter's counters.
+type RateLimitSnapshot struct {
+	Checks      int64 `json:"checks"`
+	Denied      int64 `json:"denied"`
+	TrackedKeys int   `json:"tracked_keys"`
+}
+
+// FixtureSnapshot is the fixture usage statistics.
+type FixtureSnapshot struct {
+	Queries int64            `json:"queries"`
+	Hits    map[string]int64 `json:"hits"`
+}
+
+// resetStateRequest is the body of POST /_sentra/state/reset.
+type resetStateRequest struct {
+	// Scenario names the scenario about to run (for logs and snapshots)
+	Scenario string `json:"scenario"`
+
+	// State lists the kinds of state to reset (default: all of them)
+	State []string `json:"state"`
+}
+
+// resetStateResponse reports a reset.
+type resetStateResponse struct {
+	// Reset lists the kinds of state that were reset
+	Reset []string `json:"reset"`
+
+	// Previous is the state before the reset, i.e. what the previous
+	// scenario left behind
+	Previous StateSnapshot `json:"previous"`
+}
+
+// setupStateRoutes registers the state isolation admin API.
+func (s *Server) setupStateRoutes(admin *gin.RouterGroup) {
+	admin.GET("/state", s.handleGetState)
+	admin.POST("/state/reset", s.handleResetState)
+	admin.POST("/state/seed", s.handleSeedState)
+}
+
+// handleGetState returns a snapshot of the mock state.
+func (s *Server) handleGetState(c *gin.Context) {
+	s.isolation.mu.Lock()
+	defer s.isolation.mu.Unlock()
+
+	c.JSON(http.StatusOK, s.stateSnapshot(c))
+}
+
+// handleResetState snapshots the mock state, then resets the requested
+// kinds of it. State that belongs to components the mock runs without is
+// skipped.
+func (s *Server) handleResetState(c *gin.Context) {
+	var req resetStateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+			return
+		}
+	}
+
+	kinds := req.State
+	if len(kinds) == 0 {
+		kinds = StateKinds
+	}
+	for _, kind := range kinds {
+		if !slices.Contains(StateKinds, kind) {
+			param := "state"
+			abortWithError(c, models.NewBadRequestError(
+				fmt.Sprintf("unknown state %q (must be one of: %s)", kind, strings.Join(StateKinds, ", ")), &param))
+			return
+		}
+	}
+
+	s.isolation.mu.Lock()
+	defer s.isolation.mu.Unlock()
+
+	previous := s.stateSnapshot(c)
+
+	var reset []string
+	for _, kind := range StateKinds {
+		if slices.Contains(kinds, kind) && s.resetState(c, kind) {
+			reset = append(reset, kind)
+		}
+	}
+
+	s.isolation.scenario = req.Scenario
+	s.isolation.resetAt = time.Now()
+
+	metrics.Info(c.Request.Context(), "mock state reset",
+		"scenario", req.Scenario,
+		"state", strings.Join(reset, ","),
+	)
+
+	c.JSON(http.StatusOK, resetStateResponse{
+		Reset:    reset,
+		Previous: previous,
+	})
+}
+
+// resetState resets one kind of state. It returns false if the mock runs
+// without the component that holds it. Must be called with isolation.mu held.
+func (s *Server) resetState(c *gin.Context, kind string) bool {
+	switch kind {
+	case StateUsage:
+		if s.tracker == nil {
+			return false
+		}
+		s.tracker.ResetUnattributedUsage(c.Request.Context())
+	case StateRateLimits:
+		if s.limiter == nil {
+			return false
+		}
+		s.limiter.ResetAll()
+	case StateFixtures:
+		if s.fixtures == nil {
+			return false
+		}
+		s.fixtures.ResetStats()
+	case StateAssistants:
+		if _, err := s.assistants.reset(c.Request.Context()); err != nil {
+			metrics.Warn(c.Request.Context(), "failed to reset assistants", "error", err)
+		}
+	case StateThreads:
+		s.threads.reset()
+	case StateImages:
+		s.images.reset()
+	case StateFiles:
+		if _, err := s.files.reset(c.Request.Context()); err != nil {
+			metrics.Warn(c.Request.Context(), "failed to reset uploaded files", "error", err)
+		}
+	case StateFineTuning:
+		s.fineTuning.reset()
+	case StateOverrides:
+		s.overrides.mu.Lock()
+		s.restoreOverridden()
+		s.overrides.active = nil
+		s.overrides.mu.Unlock()
+	case StateFaults:
+		if s.latency == nil && s.errorInjector == nil {
+			return false
+		}
+		if s.latency != nil {
+			s.latency.ClearLatencyFaults()
+		}
+		if s.errorInjector != nil {
+			s.errorInjector.RestoreDefaults()
+		}
+	case StateClock:
+		clock.Reset()
+	case StateLocale:
+		locale.Reset()
+	case StateDeadline:
+		s.deadline.clear()
+	}
+	return true
+}
+
+// stateSnapshot summarizes the mock state. Must be called with
+// isolation.mu held.
+func (s *Server) stateSnapshot(c *gin.Context) StateSnapshot {
+	snapshot := StateSnapshot{
+		Scenario:           s.isolation.scenario,
+		Assistants:         s.assistants.count(c.Request.Context()),
+		Threads:            s.threads.count(),
+		Images:             s.images.count(),
+		Files:              s.files.count(c.Request.Context()),
+		FineTuningJobs:     s.fineTuning.count(),
+		ClockOffsetSeconds: clock.Offset().Seconds(),
+	}
+	if !s.isolation.resetAt.IsZero() {
+		snapshot.ResetAt = s.isolation.resetAt.Unix()
+	}
+
+	if s.tracker != nil {
+		usage := &UsageSnapshot{}
+		for _, model := range s.tracker.GetAllModelUsage(c.Request.Context()) {
+			usage.Requests += model.TotalRequests
+			usage.InputTokens += model.TotalInputTokens
+			usage.OutputTokens += model.TotalOutputTokens
+			usage.TotalCost += model.TotalCost
+		}
+		snapshot.Usage = usage
+	}
+
+	if s.limiter != nil {
+		stats := s.limiter.GetStats()
+		snapshot.RateLimits = &RateLimitSnapshot{
+			Checks:      stats.TotalChecks,
+			Denied:      stats.TotalDenied,
+			TrackedKeys: stats.TrackedKeys,
+		}
+	}
+
+	if s.fixtures != nil {
+		stats := s.fixtures.GetStats()
+		snapshot.Fixtures = &FixtureSnapshot{
+			Queries: stats.TotalQueries,
+			Hits:    stats.FixtureHits,
+		}
+	}
+
+	s.overrides.mu.Lock()
+	snapshot.Overrides = s.overrides.active != nil
+	s.overrides.mu.Unlock()
+
+	return snapshot
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+	"github.com/sentra-lab/mocks/openai/internal/ratelimit"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+// newScenarioServer creates a server with the state a scenario leaves
+// behind: a tracked request attributed to run-a and one that is not, an
+// assistant, applied overrides, an advanced clock and a deadline.
+func newScenarioServer(t *testing.T) *Server {
+	t.Helper()
+	t.Cleanup(clock.Reset)
+
+	s := newTestServer(t, Dependencies{
+		Tracker:  pricing.NewTracker(pricing.NewCalculator(pricing.NewPricingDB()), store.NewMemoryStore()),
+		Fixtures: newGenericFixtures(t, 5),
+		Limiter: ratelimit.NewLimiter(ratelimit.LimiterConfig{
+			Enabled:      true,
+			TierRegistry: ratelimit.NewTierRegistry("tier5"),
+			DefaultTier:  "tier5",
+		}),
+	})
+
+	expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, map[string]string{HeaderRunID: "run-a"}), http.StatusOK)
+	expectStatus(t, serve(s, http.MethodPost, "/v1/chat/completions", chatBody, nil), http.StatusOK)
+	createAssistant(t, s, `{"model":"gpt-4o","name":"Helper"}`)
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/overrides", `{"rate_limit":{"tier":"free"}}`, nil), http.StatusOK)
+	if err := clock.Advance(time.Hour); err != nil {
+		t.Fatalf("Advance() error = %v", err)
+	}
+	deadline := fmt.Sprintf(`{"deadline_ms":%d}`, time.Now().Add(time.Hour).UnixMilli())
+	expectStatus(t, serve(s, http.MethodPut, "/_sentra/deadline", deadline, nil), http.StatusOK)
+	return s
+}
+
+func TestResetState(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		// wantReset are the kinds reset (nil: all but faults, which the
+		// server runs without)
+		wantReset []string
+	}{
+		{name: "everything by default", wantStatus: http.StatusOK},
+		{name: "everything for a scenario", body: `{"scenario":"checkout"}`, wantStatus: http.StatusOK},
+		{name: "some kinds", body: `{"state":["clock","assistants"]}`, wantStatus: http.StatusOK, wantReset: []string{StateAssistants, StateClock}},
+		{name: "kind without its component", body: `{"state":["faults"]}`, wantStatus: http.StatusOK, wantReset: []string{}},
+		{name: "unknown kind", body: `{"state":["clock","cache"]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScenarioServer(t)
+
+			rec := serve(s, http.MethodPost, "/_sentra/state/reset", tt.body, nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if got := errorParam(t, rec); got != "state" {
+					t.Errorf("param = %q, want %q", got, "state")
+				}
+				if clock.Offset() == 0 {
+					t.Error("a rejected reset reset the clock")
+				}
+				return
+			}
+
+			var got resetStateResponse
+			decodeJSON(t, rec, &got)
+
+			wantReset := tt.wantReset
+			if wantReset == nil {
+				wantReset = slices.DeleteFunc(slices.Clone(StateKinds), func(kind string) bool { return kind == StateFaults })
+			}
+			if fmt.Sprint(got.Reset) != fmt.Sprint(wantReset) {
+				t.Errorf("reset = %v, want %v", got.Reset, wantReset)
+			}
+
+			// previous is what the scenario left behind
+			previous := got.Previous
+			if previous.Usage == nil || previous.Usage.Requests != 2 || previous.Assistants != 1 || !previous.Overrides || previous.ClockOffsetSeconds < 3600 {
+				t.Errorf("previous = %+v, want the scenario's state", previous)
+			}
+
+			var state StateSnapshot
+			decodeJSON(t, serve(s, http.MethodGet, "/_sentra/state", "", nil), &state)
+			reset := func(kind string) bool { return slices.Contains(got.Reset, kind) }
+			if got := state.Usage.Requests == 0; got != reset(StateUsage) {
+				t.Errorf("usage requests = %d, want reset: %v", state.Usage.Requests, reset(StateUsage))
+			}
+			if got := state.Assistants == 0; got != reset(StateAssistants) {
+				t.Errorf("assistants = %d, want reset: %v", state.Assistants, reset(StateAssistants))
+			}
+			if got := !state.Overrides; got != reset(StateOverrides) {
+				t.Errorf("overrides = %v, want reset: %v", state.Overrides, reset(StateOverrides))
+			}
+			if got := state.ClockOffsetSeconds == 0; got != reset(StateClock) {
+				t.Errorf("clock offset = %vs, want reset: %v", state.ClockOffsetSeconds, reset(StateClock))
+			}
+			if at, _, _ := s.deadline.snapshot(); at.IsZero() != reset(StateDeadline) {
+				t.Errorf("deadline = %v, want reset: %v", at, reset(StateDeadline))
+			}
+			if state.ResetAt == 0 {
+				t.Error("reset_at is not set")
+			}
+		})
+	}
+}
+
+func TestResetStateKeepsRunCosts(t *testing.T) {
+	s := newScenarioServer(t)
+
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/reset", `{"state":["usage"]}`, nil), http.StatusOK)
+
+	var costs struct {
+		Data []runCostEntry `json:"data"`
+	}
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/costs?run_id=run-a", "", nil), &costs)
+	if len(costs.Data) != 1 || costs.Data[0].Requests != 1 {
+		t.Errorf("run-a costs = %+v, want its request kept", costs.Data)
+	}
+}
+
+func TestGetState(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+
+	var state StateSnapshot
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/state", "", nil), &state)
+	if state.Usage != nil || state.RateLimits != nil || state.Fixtures != nil {
+		t.Errorf("state = %+v, want no usage, rate limits or fixtures without their components", state)
+	}
+	if state.ResetAt != 0 || state.Scenario != "" {
+		t.Errorf("state = %+v, want it never reset", state)
+	}
+
+	expectStatus(t, serve(s, http.MethodPost, "/_sentra/state/reset", `{"scenario":"checkout"}`, nil), http.StatusOK)
+	decodeJSON(t, serve(s, http.MethodGet, "/_sentra/state", "", nil), &state)
+	if state.Scenario != "checkout" || state.ResetAt == 0 {
+		t.Errorf("state = %+v, want it reset for checkout", state)
+	}
+}
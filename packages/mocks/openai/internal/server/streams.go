@@ -0,0 +1,91 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the concurrent streaming connection limit: like
+// production, an API key may only hold a limited number of SSE streams open
+// at once, and further streaming requests are rejected with a 429.
+package server
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// StreamsConfig caps simultaneous SSE streams.
+type StreamsConfig struct {
+	// MaxPerKey is the maximum number of streams an API key may have open
+	// at once (0 means unlimited)
+	MaxPerKey int
+}
+
+// DefaultStreamsConfig returns the default streaming configuration, which
+// does not limit streams.
+func DefaultStreamsConfig() StreamsConfig {
+	return StreamsConfig{}
+}
+
+// streamLimiter counts open streams per API key.
+type streamLimiter struct {
+	mu   sync.Mutex
+	max  int
+	open map[string]int
+}
+
+// newStreamLimiter creates a limiter allowing max streams per key
+// (0 = unlimited).
+func newStreamLimiter(max int) *streamLimiter {
+	return &streamLimiter{
+		max:  max,
+		open: make(map[string]int),
+	}
+}
+
+// acquire opens a stream for key, or returns false if the key is at the limit.
+func (l *streamLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && l.open[key] >= l.max {
+		return false
+	}
+	l.open[key]++
+	return true
+}
+
+// release closes a stream opened by acquire.
+func (l *streamLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.open[key] <= 1 {
+		delete(l.open, key)
+		return
+	}
+	l.open[key]--
+}
+
+// StartStream admits a streaming response for the request's API key. If
+// the key already has Streams.MaxPerKey streams open, it responds with a
+// 429 and returns false. Otherwise the stream is registered (see
+// BeginStream) and the returned function must be called when it ends.
+// Must run after ScopeMiddleware.
+func (s *Server) StartStream(c *gin.Context) (func(), bool) {
+	key := GetScope(c).APIKey
+	if !s.streamsPerKey.acquire(key) {
+		metrics.RecordRateLimitHit(key, "streams", 0)
+		c.Header("Retry-After", "1")
+		abortWithError(c, models.NewConcurrentStreamsError(s.streamsPerKey.max))
+		return nil, false
+	}
+
+	end := s.BeginStream()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			end()
+			s.streamsPerKey.release(key)
+		})
+	}, true
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestStreamLimitPerKey(t *testing.T) {
+	const maxStreams = 2
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+
+		// limited is whether the request counts against the limit
+		limited bool
+
+		// status is the status once the key's streams are released
+		status int
+	}{
+		{
+			name:    "streaming chat completion",
+			method:  http.MethodPost,
+			path:    "/v1/chat/completions",
+			body:    `{"model":"gpt-4o-mini","stream":true,"messages":[{"role":"user","content":"Hello"}]}`,
+			limited: true,
+			status:  http.StatusOK,
+		},
+		{
+			name:   "non-streaming chat completion",
+			method: http.MethodPost,
+			path:   "/v1/chat/completions",
+			body:   `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}]}`,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Streams.MaxPerKey = maxStreams
+			s := New(config, Dependencies{})
+
+			// Hold the key's streams open
+			for i := 0; i < maxStreams; i++ {
+				if !s.streamsPerKey.acquire("sk-test") {
+					t.Fatal("could not open the key's streams")
+				}
+			}
+
+			rec := serve(s, tt.method, tt.path, tt.body, nil)
+			if tt.limited {
+				expectStatus(t, rec, http.StatusTooManyRequests)
+				if got := rec.Header().Get("Retry-After"); got == "" {
+					t.Error("429 has no Retry-After")
+				}
+			} else {
+				expectStatus(t, rec, tt.status)
+			}
+
+			// Other keys are not affected
+			other := serve(s, tt.method, tt.path, tt.body, map[string]string{"Authorization": "Bearer sk-other"})
+			expectStatus(t, other, tt.status)
+
+			// Closing a stream admits the next one, which releases its slot
+			s.streamsPerKey.release("sk-test")
+			rec = serve(s, tt.method, tt.path, tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if s.ActiveStreams() != 0 {
+				t.Errorf("active streams = %d after the request ended", s.ActiveStreams())
+			}
+			if !s.streamsPerKey.acquire("sk-test") {
+				t.Error("the request did not release its stream")
+			}
+		})
+	}
+}
+
+func TestRealtimeStreamLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.Streams.MaxPerKey = 1
+	s := New(config, Dependencies{})
+
+	ts := httptest.NewServer(s.Engine())
+	defer ts.Close()
+
+	dial := func(key string) (*websocket.Conn, error) {
+		wsConfig, err := websocket.NewConfig("ws"+strings.TrimPrefix(ts.URL, "http")+realtimePath+"?model=gpt-4o-realtime-preview", ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wsConfig.Header.Set("Authorization", "Bearer "+key)
+		return websocket.DialConfig(wsConfig)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		ok   bool
+	}{
+		{name: "first session", key: "sk-test", ok: true},
+		{name: "second session of the key", key: "sk-test", ok: false},
+		{name: "session of another key", key: "sk-other", ok: true},
+	}
+
+	var open []*websocket.Conn
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := dial(tt.key)
+			if tt.ok != (err == nil) {
+				t.Fatalf("dial error = %v, want ok = %v", err, tt.ok)
+			}
+			if conn != nil {
+				open = append(open, conn)
+			}
+			if err != nil && !strings.Contains(err.Error(), "bad status") {
+				t.Errorf("dial error = %v, want the handshake rejected", err)
+			}
+		})
+	}
+
+	// Closing the sessions releases their streams
+	for _, conn := range open {
+		conn.Close()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for s.ActiveStreams() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if s.ActiveStreams() != 0 {
+		t.Fatalf("active streams = %d after the sessions closed", s.ActiveStreams())
+	}
+
+	conn, err := dial("sk-test")
+	if err != nil {
+		t.Fatalf("dial after release: %v", err)
+	}
+	conn.Close()
+}
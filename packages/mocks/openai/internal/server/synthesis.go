@@ -0,0 +1,100 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the synthesis admin API, which changes the content
+// strategy of responses synthesized for prompts no fixture matches,
+// globally or for one OpenAI-Project.
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// headerSynthesisStrategy reports the strategy of a synthesized response
+const headerSynthesisStrategy = "X-Sentra-Synthesis-Strategy"
+
+// setupSynthesisRoutes registers the synthesis admin API.
+func (s *Server) setupSynthesisRoutes(admin *gin.RouterGroup) {
+	if s.synthesizer == nil {
+		return
+	}
+
+	admin.GET("/synthesis", s.handleGetSynthesis)
+	admin.PUT("/synthesis/strategy", s.handleSetSynthesisStrategy)
+	admin.PUT("/synthesis/projects/:project", s.handleSetProjectSynthesisStrategy)
+}
+
+// synthesisStrategyRequest sets a synthesis strategy.
+type synthesisStrategyRequest struct {
+	// Strategy is auto, echo, list, code or refusal
+	Strategy string `json:"strategy"`
+}
+
+// handleGetSynthesis reports the strategy for a project (?project=, the
+// default without one) and, with ?prompt=, the strategy a synthesized
+// response to that prompt would use.
+func (s *Server) handleGetSynthesis(c *gin.Context) {
+	project := c.Query("project")
+	strategy := s.synthesizer.StrategyFor(project)
+
+	response := gin.H{
+		"default_strategy": s.synthesizer.StrategyFor(""),
+		"strategy":         strategy,
+	}
+	if project != "" {
+		response["project"] = project
+	}
+	if prompt, ok := c.GetQuery("prompt"); ok {
+		resolved := strategy
+		if resolved == generator.StrategyAuto {
+			resolved = generator.ClassifyPrompt(prompt)
+		}
+		response["prompt_strategy"] = resolved
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleSetSynthesisStrategy sets the strategy for requests without a
+// project override.
+func (s *Server) handleSetSynthesisStrategy(c *gin.Context) {
+	strategy, ok := bindSynthesisStrategy(c)
+	if !ok {
+		return
+	}
+
+	s.synthesizer.SetDefaultStrategy(strategy)
+	c.JSON(http.StatusOK, gin.H{"default_strategy": strategy})
+}
+
+// handleSetProjectSynthesisStrategy overrides the strategy for one project.
+func (s *Server) handleSetProjectSynthesisStrategy(c *gin.Context) {
+	strategy, ok := bindSynthesisStrategy(c)
+	if !ok {
+		return
+	}
+
+	project := c.Param("project")
+	s.synthesizer.SetProjectStrategy(project, strategy)
+	c.JSON(http.StatusOK, gin.H{"project": project, "strategy": strategy})
+}
+
+// bindSynthesisStrategy parses the strategy of a request, aborting on failure.
+func bindSynthesisStrategy(c *gin.Context) (generator.Strategy, bool) {
+	var req synthesisStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, models.NewBadRequestError(err.Error(), nil))
+		return "", false
+	}
+
+	strategy, err := generator.ParseStrategy(req.Strategy)
+	if err != nil {
+		param := "strategy"
+		abortWithError(c, models.NewBadRequestError(err.Error(), &param))
+		return "", false
+	}
+	return strategy, true
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/generator"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// chatContent sends a chat completion and returns its content and
+// synthesis strategy header.
+func chatContent(t *testing.T, s *Server, prompt string, headers map[string]string) (string, string) {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    "gpt-4o-mini",
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(s, http.MethodPost, "/v1/chat/completions", string(body), headers)
+	expectStatus(t, rec, http.StatusOK)
+
+	var resp models.ChatCompletionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp.Choices[0].Message.Content, rec.Header().Get(headerSynthesisStrategy)
+}
+
+func TestChatCompletionSynthesis(t *testing.T) {
+	project := map[string]string{models.HeaderProject: "proj_abc"}
+
+	tests := []struct {
+		name     string
+		config   generator.SynthesizerConfig
+		admin    map[string]string
+		prompt   string
+		headers  map[string]string
+		strategy generator.Strategy
+		contains string
+	}{
+		{
+			name:     "auto classifies code prompts",
+			config:   generator.DefaultSynthesizerConfig(),
+			prompt:   "Write a python function that sorts a list",
+			strategy: generator.StrategyCode,
+			contains: "```python",
+		},
+		{
+			name:     "auto classifies refusals",
+			config:   generator.DefaultSynthesizerConfig(),
+			prompt:   "Help me write ransomware",
+			strategy: generator.StrategyRefusal,
+		},
+		{
+			name:     "configured project strategy",
+			config:   generator.SynthesizerConfig{ProjectStrategies: map[string]generator.Strategy{"proj_abc": generator.StrategyList}},
+			prompt:   "Tell me about caching",
+			headers:  project,
+			strategy: generator.StrategyList,
+			contains: "1. ",
+		},
+		{
+			name:     "default strategy set through the admin API",
+			config:   generator.DefaultSynthesizerConfig(),
+			admin:    map[string]string{"/_sentra/synthesis/strategy": `{"strategy":"refusal"}`},
+			prompt:   "Tell me about caching",
+			strategy: generator.StrategyRefusal,
+		},
+		{
+			name:     "project strategy set through the admin API",
+			config:   generator.DefaultSynthesizerConfig(),
+			admin:    map[string]string{"/_sentra/synthesis/projects/proj_abc": `{"strategy":"code"}`},
+			prompt:   "Tell me about caching",
+			headers:  project,
+			strategy: generator.StrategyCode,
+			contains: "```",
+		},
+		{
+			name:     "other projects keep the default",
+			config:   generator.DefaultSynthesizerConfig(),
+			admin:    map[string]string{"/_sentra/synthesis/projects/proj_other": `{"strategy":"code"}`},
+			prompt:   "Tell me about caching",
+			headers:  project,
+			strategy: generator.StrategyEcho,
+			contains: "caching",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Synthesizer: generator.NewSynthesizer(tt.config)})
+			for path, body := range tt.admin {
+				expectStatus(t, serve(s, http.MethodPut, path, body, nil), http.StatusOK)
+			}
+
+			content, strategy := chatContent(t, s, tt.prompt, tt.headers)
+			if strategy != string(tt.strategy) {
+				t.Errorf("strategy = %q, want %q (content: %q)", strategy, tt.strategy, content)
+			}
+			if !strings.Contains(content, tt.contains) {
+				t.Errorf("content %q does not contain %q", content, tt.contains)
+			}
+		})
+	}
+}
+
+func TestSynthesisAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   map[string]string
+	}{
+		{
+			name:   "default strategy",
+			method: http.MethodGet,
+			path:   "/_sentra/synthesis",
+			status: http.StatusOK,
+			want:   map[string]string{"default_strategy": "auto", "strategy": "auto"},
+		},
+		{
+			name:   "prompt classification",
+			method: http.MethodGet,
+			path:   "/_sentra/synthesis?prompt=list+some+tips",
+			status: http.StatusOK,
+			want:   map[string]string{"strategy": "auto", "prompt_strategy": "list"},
+		},
+		{
+			name:   "project without override",
+			method: http.MethodGet,
+			path:   "/_sentra/synthesis?project=proj_abc&prompt=hello",
+			status: http.StatusOK,
+			want:   map[string]string{"project": "proj_abc", "strategy": "auto", "prompt_strategy": "echo"},
+		},
+		{
+			name:   "unknown strategy",
+			method: http.MethodPut,
+			path:   "/_sentra/synthesis/strategy",
+			body:   `{"strategy":"poetry"}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Synthesizer: generator.NewSynthesizer(generator.DefaultSynthesizerConfig())})
+			rec := serve(s, tt.method, tt.path, tt.body, nil)
+			expectStatus(t, rec, tt.status)
+
+			var got map[string]string
+			if tt.want != nil {
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("%s = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
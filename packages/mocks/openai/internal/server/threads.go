@@ -0,0 +1,823 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the Assistants threads API and the admin endpoint
+// that exposes a thread's state for debugging stateful agents. Runs are
+// answered from fixtures in the "assistants" category, matched against the
+// thread's latest user message; a fixture with tool calls to the run's
+// function tools stops the run in requires_action until the caller submits
+// the tool outputs.
+package server
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/tokenizer"
+)
+
+// Thread list limits (matching the Assistants API).
+const (
+	// defaultThreadListLimit is the default page size for list endpoints
+	defaultThreadListLimit = 20
+
+	// maxThreadListLimit is the maximum page size for list endpoints
+	maxThreadListLimit = 100
+
+	// assistantFixtureCategory is the fixture category runs are answered from
+	assistantFixtureCategory = "assistants"
+)
+
+// threadState is everything the mock knows about one thread.
+type threadState struct {
+	thread   models.Thread
+	messages []models.ThreadMessage
+	runs     []models.Run
+
+	// replies are the fixture replies of runs awaiting tool outputs, by run ID
+	replies map[string]string
+}
+
+// threadStore holds threads in memory.
+type threadStore struct {
+	mu      sync.RWMutex
+	threads map[string]*threadState
+}
+
+// newThreadStore creates an empty thread store.
+func newThreadStore() *threadStore {
+	return &threadStore{
+		threads: make(map[string]*threadState),
+	}
+}
+
+// snapshot returns a copy of a thread's state.
+func (ts *threadStore) snapshot(threadID string) (threadState, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	state, ok := ts.threads[threadID]
+	if !ok {
+		return threadState{}, false
+	}
+
+	return threadState{
+		thread:   state.thread,
+		messages: append([]models.ThreadMessage(nil), state.messages...),
+		runs:     append([]models.Run(nil), state.runs...),
+		replies:  maps.Clone(state.replies),
+	}, true
+}
+
+// reset discards every thread and returns how many there were.
+func (ts *threadStore) reset() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	count := len(ts.threads)
+	ts.threads = make(map[string]*threadState)
+	return count
+}
+
+// count returns the number of threads.
+func (ts *threadStore) count() int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return len(ts.threads)
+}
+
+// setupThreadRoutes registers the threads API and its admin inspection route.
+func (s *Server) setupThreadRoutes() {
+	s.api.POST("/threads", s.handleCreateThread)
+	s.api.GET("/threads/:thread_id", s.handleGetThread)
+	s.api.DELETE("/threads/:thread_id", s.handleDeleteThread)
+	s.api.POST("/threads/:thread_id/messages", s.handleCreateThreadMessage)
+	s.api.GET("/threads/:thread_id/messages", s.handleListThreadMessages)
+	s.api.POST("/threads/:thread_id/runs", s.handleCreateRun)
+	s.api.GET("/threads/:thread_id/runs", s.handleListRuns)
+	s.api.GET("/threads/:thread_id/runs/:run_id", s.handleGetRun)
+	s.api.POST("/threads/:thread_id/runs/:run_id/submit_tool_outputs", s.handleSubmitToolOutputs)
+	s.api.POST("/threads/:thread_id/runs/:run_id/cancel", s.handleCancelRun)
+}
+
+// setupThreadAdminRoutes registers the thread inspection admin API.
+func (s *Server) setupThreadAdminRoutes(admin *gin.RouterGroup) {
+	admin.GET("/threads/:thread_id/state", s.handleGetThreadState)
+}
+
+// bindRequest binds and validates a JSON body, aborting on failure.
+// An empty body is allowed (e.g., POST /v1/threads creates an empty thread).
+// A body cut off by the size limit is rejected with 413.
+func bindRequest(c *gin.Context, req interface{ Validate() error }) bool {
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortWithError(c, models.NewRequestTooLargeError(maxBytesErr.Limit))
+				return false
+			}
+			abortWithError(c, models.NewBadRequestError(fmt.Sprintf("We could not parse the JSON body of your request: %v", err), nil))
+			return false
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		abortWithAPIError(c, err)
+		return false
+	}
+
+	return true
+}
+
+// threadNotFound aborts with the API's 404 for an unknown thread.
+func threadNotFound(c *gin.Context, threadID string) {
+	abortWithError(c, models.NewNotFoundError(fmt.Sprintf("No thread found with id '%s'.", threadID)))
+}
+
+// runNotFound aborts with the API's 404 for an unknown run.
+func runNotFound(c *gin.Context, runID string) {
+	abortWithError(c, models.NewNotFoundError(fmt.Sprintf("No run found with id '%s'.", runID)))
+}
+
+// activeRun returns the run blocking a thread, or nil.
+func activeRun(runs []models.Run) *models.Run {
+	for i := range runs {
+		if runs[i].Status.IsActive() {
+			return &runs[i]
+		}
+	}
+	return nil
+}
+
+// findRun returns the index of a run, or -1.
+func findRun(runs []models.Run, runID string) int {
+	return slices.IndexFunc(runs, func(run models.Run) bool {
+		return run.ID == runID
+	})
+}
+
+// handleCreateThread creates a thread, optionally seeded with messages.
+func (s *Server) handleCreateThread(c *gin.Context) {
+	var req models.CreateThreadRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	now := clock.Now().Unix()
+	metadata := req.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	state := &threadState{
+		thread: models.Thread{
+			ID:        models.NewThreadID(),
+			Object:    "thread",
+			CreatedAt: now,
+			Metadata:  metadata,
+		},
+	}
+	for _, msg := range req.Messages {
+		state.messages = append(state.messages, models.NewThreadMessage(state.thread.ID, msg.Role, msg.Content, msg.Metadata, now))
+	}
+
+	s.threads.mu.Lock()
+	s.threads.threads[state.thread.ID] = state
+	s.threads.mu.Unlock()
+
+	c.JSON(http.StatusOK, state.thread)
+}
+
+// handleGetThread returns a thread.
+func (s *Server) handleGetThread(c *gin.Context) {
+	threadID := c.Param("thread_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+	c.JSON(http.StatusOK, state.thread)
+}
+
+// handleDeleteThread deletes a thread and its messages and runs.
+func (s *Server) handleDeleteThread(c *gin.Context) {
+	threadID := c.Param("thread_id")
+
+	s.threads.mu.Lock()
+	_, ok := s.threads.threads[threadID]
+	delete(s.threads.threads, threadID)
+	s.threads.mu.Unlock()
+
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      threadID,
+		"object":  "thread.deleted",
+		"deleted": true,
+	})
+}
+
+// handleCreateThreadMessage appends a message to a thread.
+func (s *Server) handleCreateThreadMessage(c *gin.Context) {
+	var req models.ThreadMessageRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	threadID := c.Param("thread_id")
+
+	s.threads.mu.Lock()
+	state, ok := s.threads.threads[threadID]
+	var msg models.ThreadMessage
+	var active *models.Run
+	if ok {
+		if active = activeRun(state.runs); active == nil {
+			msg = models.NewThreadMessage(threadID, req.Role, req.Content, req.Metadata, clock.Now().Unix())
+			state.messages = append(state.messages, msg)
+		}
+	}
+	s.threads.mu.Unlock()
+
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+	if active != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Can't add messages to %s while a run %s is active.", threadID, active.ID), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, msg)
+}
+
+// handleListThreadMessages lists a thread's messages (?order=asc|desc, ?limit=).
+func (s *Server) handleListThreadMessages(c *gin.Context) {
+	threadID := c.Param("thread_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+
+	ids := make([]string, len(state.messages))
+	for i, msg := range state.messages {
+		ids[i] = msg.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(ids))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.ThreadMessage, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, state.messages[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// handleCreateRun runs an assistant on a thread. The run uses the
+// assistant's model, instructions and tools unless the request overrides
+// them. It completes immediately with the matching fixture's reply (or a
+// synthesized one), or stops in requires_action when the fixture calls the
+// run's function tools.
+func (s *Server) handleCreateRun(c *gin.Context) {
+	var req models.CreateRunRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	assistant, ok := s.assistants.get(c.Request.Context(), req.AssistantID)
+	if !ok {
+		assistantNotFound(c, req.AssistantID)
+		return
+	}
+
+	threadID := c.Param("thread_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+	if active := activeRun(state.runs); active != nil {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Thread %s already has an active run %s.", threadID, active.ID), nil))
+		return
+	}
+
+	model := req.Model
+	if model == "" {
+		model = assistant.Model
+	}
+	instructions := req.Instructions
+	if instructions == "" && assistant.Instructions != nil {
+		instructions = *assistant.Instructions
+	}
+	tools := req.Tools
+	if tools == nil {
+		tools = assistant.Tools
+	}
+	metadata := req.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	fixture := s.runFixture(state.messages)
+	toolCalls, err := runToolCalls(fixture, tools)
+	if err != nil {
+		abortWithError(c, models.NewServerError(err.Error()))
+		return
+	}
+
+	now := clock.Now().Unix()
+	run := models.Run{
+		ID:           models.NewRunID(),
+		Object:       "thread.run",
+		CreatedAt:    now,
+		ThreadID:     threadID,
+		AssistantID:  assistant.ID,
+		Model:        model,
+		Instructions: instructions,
+		Tools:        tools,
+		Metadata:     metadata,
+	}
+
+	var msg *models.ThreadMessage
+	var pendingReply string
+	if len(toolCalls) > 0 {
+		run.Status = models.RunStatusRequiresAction
+		run.RequiredAction = &models.RunRequiredAction{
+			Type:              "submit_tool_outputs",
+			SubmitToolOutputs: models.RunToolCalls{ToolCalls: toolCalls},
+		}
+		pendingReply = fixture.Content
+	} else {
+		conversation := threadConversation(state.messages, instructions)
+		reply := s.runReply(c, conversation, fixture)
+		completeRun(&run, conversation, reply, now)
+		message := runMessage(run, reply, now)
+		msg = &message
+	}
+
+	s.threads.mu.Lock()
+	current, ok := s.threads.threads[threadID]
+	if ok {
+		current.runs = append(current.runs, run)
+		if msg != nil {
+			current.messages = append(current.messages, *msg)
+		}
+		if pendingReply != "" {
+			if current.replies == nil {
+				current.replies = make(map[string]string)
+			}
+			current.replies[run.ID] = pendingReply
+		}
+	}
+	s.threads.mu.Unlock()
+
+	if !ok {
+		// Deleted while the run was being built
+		threadNotFound(c, threadID)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// handleGetRun returns a run.
+func (s *Server) handleGetRun(c *gin.Context) {
+	threadID := c.Param("thread_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+
+	i := findRun(state.runs, c.Param("run_id"))
+	if i < 0 {
+		runNotFound(c, c.Param("run_id"))
+		return
+	}
+	c.JSON(http.StatusOK, state.runs[i])
+}
+
+// handleSubmitToolOutputs answers a run's tool calls and completes it. Every
+// call must be answered at once. The reply is the fixture's content, or is
+// synthesized from the conversation including the tool outputs.
+func (s *Server) handleSubmitToolOutputs(c *gin.Context) {
+	var req models.SubmitToolOutputsRequest
+	if !bindRequest(c, &req) {
+		return
+	}
+
+	threadID := c.Param("thread_id")
+	runID := c.Param("run_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+	i := findRun(state.runs, runID)
+	if i < 0 {
+		runNotFound(c, runID)
+		return
+	}
+	run := state.runs[i]
+	if run.Status != models.RunStatusRequiresAction {
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Runs in status \"%s\" do not accept tool outputs.", run.Status), nil))
+		return
+	}
+
+	var expected, got []string
+	for _, call := range run.RequiredAction.SubmitToolOutputs.ToolCalls {
+		expected = append(expected, call.ID)
+	}
+	for _, output := range req.ToolOutputs {
+		got = append(got, output.ToolCallID)
+	}
+	sortedExpected, sortedGot := slices.Clone(expected), slices.Clone(got)
+	slices.Sort(sortedExpected)
+	slices.Sort(sortedGot)
+	if !slices.Equal(sortedExpected, sortedGot) {
+		param := "tool_outputs"
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Expected tool outputs for call_ids %s, got %s", pythonList(expected), pythonList(got)), &param))
+		return
+	}
+
+	conversation := threadConversation(state.messages, run.Instructions)
+	for _, output := range req.ToolOutputs {
+		conversation = append(conversation, models.Message{Role: "tool", Content: output.Output})
+	}
+	reply := state.replies[runID]
+	if reply == "" {
+		reply = s.runReply(c, conversation, nil)
+	}
+
+	now := clock.Now().Unix()
+	completeRun(&run, conversation, reply, now)
+	msg := runMessage(run, reply, now)
+
+	s.threads.mu.Lock()
+	current, ok := s.threads.threads[threadID]
+	stillWaiting := false
+	if ok {
+		if i := findRun(current.runs, runID); i >= 0 && current.runs[i].Status == models.RunStatusRequiresAction {
+			stillWaiting = true
+			current.runs[i] = run
+			current.messages = append(current.messages, msg)
+			delete(current.replies, runID)
+		}
+	}
+	s.threads.mu.Unlock()
+
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+	if !stillWaiting {
+		// Cancelled or answered while the reply was being built
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Run %s no longer requires action.", runID), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// handleCancelRun cancels a run awaiting tool outputs.
+func (s *Server) handleCancelRun(c *gin.Context) {
+	threadID := c.Param("thread_id")
+	runID := c.Param("run_id")
+
+	s.threads.mu.Lock()
+	state, ok := s.threads.threads[threadID]
+	var run models.Run
+	found, cancelled := false, false
+	if ok {
+		if i := findRun(state.runs, runID); i >= 0 {
+			found = true
+			if state.runs[i].Status.IsActive() {
+				now := clock.Now().Unix()
+				state.runs[i].Status = models.RunStatusCancelled
+				state.runs[i].RequiredAction = nil
+				state.runs[i].CancelledAt = &now
+				delete(state.replies, runID)
+				cancelled = true
+			}
+			run = state.runs[i]
+		}
+	}
+	s.threads.mu.Unlock()
+
+	switch {
+	case !ok:
+		threadNotFound(c, threadID)
+	case !found:
+		runNotFound(c, runID)
+	case !cancelled:
+		abortWithError(c, models.NewBadRequestError(fmt.Sprintf("Cannot cancel run with status '%s'.", run.Status), nil))
+	default:
+		c.JSON(http.StatusOK, run)
+	}
+}
+
+// handleListRuns lists a thread's runs (?order=asc|desc, ?limit=).
+func (s *Server) handleListRuns(c *gin.Context) {
+	threadID := c.Param("thread_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+
+	ids := make([]string, len(state.runs))
+	for i, run := range state.runs {
+		ids[i] = run.ID
+	}
+
+	page, apiErr := parseThreadListPage(c, len(ids))
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	data := make([]models.Run, 0, len(page.indexes))
+	for _, i := range page.indexes {
+		data = append(data, state.runs[i])
+	}
+
+	c.JSON(http.StatusOK, page.response(data, ids))
+}
+
+// runFixture returns the assistants fixture matching the thread's latest
+// user message, or nil.
+func (s *Server) runFixture(messages []models.ThreadMessage) *fixtures.Fixture {
+	if s.fixtures == nil {
+		return nil
+	}
+
+	text := ""
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			text = messages[i].Text()
+			break
+		}
+	}
+	return s.fixtures.MatchCategory(assistantFixtureCategory, text)
+}
+
+// runToolCalls returns the fixture's tool calls to the run's function tools.
+// Calls to functions the run does not have are dropped, as the model could
+// not make them.
+func runToolCalls(fixture *fixtures.Fixture, tools []models.AssistantTool) ([]models.ToolCall, error) {
+	names := models.FunctionNames(tools)
+	if fixture == nil || len(fixture.ToolCalls) == 0 || len(names) == 0 {
+		return nil, nil
+	}
+
+	req := &models.ChatCompletionRequest{}
+	for _, tool := range tools {
+		if tool.Type == "function" && tool.Function != nil {
+			req.Tools = append(req.Tools, models.Tool{Type: "function", Function: *tool.Function})
+		}
+	}
+
+	calls, err := fixture.ToolCallsFor(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var toolCalls []models.ToolCall
+	for _, call := range calls {
+		if slices.Contains(names, call.Function.Name) {
+			call.Index = len(toolCalls)
+			toolCalls = append(toolCalls, call)
+		}
+	}
+	return toolCalls, nil
+}
+
+// completeRun marks a run completed with its reply's estimated usage.
+func completeRun(run *models.Run, conversation []models.Message, reply string, now int64) {
+	promptTokens := tokenizer.FastEstimateMessages(conversation)
+	completionTokens := tokenizer.FastEstimate(reply)
+
+	run.Status = models.RunStatusCompleted
+	run.RequiredAction = nil
+	run.CompletedAt = &now
+	run.Usage = &models.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	}
+}
+
+// runMessage builds the assistant message a run writes to its thread.
+func runMessage(run models.Run, reply string, now int64) models.ThreadMessage {
+	msg := models.NewThreadMessage(run.ThreadID, "assistant", reply, nil, now)
+	msg.AssistantID = &run.AssistantID
+	msg.RunID = &run.ID
+	return msg
+}
+
+// pythonList formats IDs the way the API's error messages list them
+// (['call_a', 'call_b']).
+func pythonList(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "'" + id + "'"
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// runReply builds the assistant reply for a run: the fixture's content, or
+// a synthesized reply when there is no fixture or it has no content.
+func (s *Server) runReply(c *gin.Context, conversation []models.Message, fixture *fixtures.Fixture) string {
+	if fixture != nil && fixture.Content != "" {
+		return fixture.Content
+	}
+	if s.synthesizer == nil {
+		return "I understand. Let me help you with that."
+	}
+
+	h := fnv.New64a()
+	for _, msg := range conversation {
+		h.Write([]byte(msg.Content))
+	}
+
+	synthesis := s.synthesizer.Synthesize(conversation, GetScope(c).Project, int64(h.Sum64()))
+	return synthesis.Content
+}
+
+// threadConversation converts thread messages to chat messages, with the
+// run's instructions as the system message.
+func threadConversation(messages []models.ThreadMessage, instructions string) []models.Message {
+	conversation := make([]models.Message, 0, len(messages)+1)
+	if instructions != "" {
+		conversation = append(conversation, models.Message{Role: "system", Content: instructions})
+	}
+	for _, msg := range messages {
+		conversation = append(conversation, models.Message{Role: msg.Role, Content: msg.Text()})
+	}
+	return conversation
+}
+
+// threadListPage is a parsed ?order=&limit= page over a thread's items.
+type threadListPage struct {
+	// indexes are the item positions on this page, in response order
+	indexes []int
+
+	// hasMore is true if items remain after this page
+	hasMore bool
+}
+
+// parseThreadListPage parses order (default "desc") and limit for n items.
+func parseThreadListPage(c *gin.Context, n int) (threadListPage, *models.APIError) {
+	limit := defaultThreadListLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxThreadListLimit {
+			param := "limit"
+			apiErr := models.NewBadRequestError(fmt.Sprintf("Invalid 'limit': expected an integer between 1 and %d.", maxThreadListLimit), &param)
+			return threadListPage{}, &apiErr
+		}
+		limit = parsed
+	}
+
+	order := c.DefaultQuery("order", "desc")
+	if order != "asc" && order != "desc" {
+		param := "order"
+		apiErr := models.NewBadRequestError(fmt.Sprintf("Invalid 'order': '%s'. Expected 'asc' or 'desc'.", order), &param)
+		return threadListPage{}, &apiErr
+	}
+
+	var page threadListPage
+	for i := 0; i < n; i++ {
+		index := i
+		if order == "desc" {
+			index = n - 1 - i
+		}
+		if len(page.indexes) == limit {
+			page.hasMore = true
+			break
+		}
+		page.indexes = append(page.indexes, index)
+	}
+
+	return page, nil
+}
+
+// response builds the list response for a page of data; ids are the IDs of
+// all items by position.
+func (p threadListPage) response(data interface{}, ids []string) gin.H {
+	var firstID, lastID *string
+	if len(p.indexes) > 0 {
+		firstID = &ids[p.indexes[0]]
+		lastID = &ids[p.indexes[len(p.indexes)-1]]
+	}
+
+	return gin.H{
+		"object":   "list",
+		"data":     data,
+		"first_id": firstID,
+		"last_id":  lastID,
+		"has_more": p.hasMore,
+	}
+}
+
+// threadTokenFootprint estimates how much context a thread occupies.
+type threadTokenFootprint struct {
+	// MessageTokens estimates the tokens of every message in the thread
+	MessageTokens int `json:"message_tokens"`
+
+	// ByRole splits MessageTokens by message role
+	ByRole map[string]int `json:"by_role"`
+
+	// ContextWindow is the context window of the latest run's model (0 without runs)
+	ContextWindow int `json:"context_window"`
+
+	// ContextUtilization is MessageTokens / ContextWindow (0 without runs)
+	ContextUtilization float64 `json:"context_utilization"`
+
+	// RunUsage sums the token usage of every run
+	RunUsage models.Usage `json:"run_usage"`
+}
+
+// threadStateResponse is the body of GET /_sentra/threads/:thread_id/state.
+type threadStateResponse struct {
+	// Thread is the thread, including its metadata
+	Thread models.Thread `json:"thread"`
+
+	// Messages are the thread's messages, oldest first
+	Messages []models.ThreadMessage `json:"messages"`
+
+	// Runs are the thread's runs, oldest first
+	Runs []models.Run `json:"runs"`
+
+	// TokenFootprint estimates the thread's token usage
+	TokenFootprint threadTokenFootprint `json:"token_footprint"`
+}
+
+// handleGetThreadState returns everything the mock believes about a thread:
+// messages, metadata, run history and token footprint.
+func (s *Server) handleGetThreadState(c *gin.Context) {
+	threadID := c.Param("thread_id")
+	state, ok := s.threads.snapshot(threadID)
+	if !ok {
+		threadNotFound(c, threadID)
+		return
+	}
+
+	footprint := threadTokenFootprint{ByRole: map[string]int{}}
+	for _, msg := range state.messages {
+		tokens := tokenizer.FastEstimateMessages([]models.Message{{Role: msg.Role, Content: msg.Text()}})
+		footprint.MessageTokens += tokens
+		footprint.ByRole[msg.Role] += tokens
+	}
+
+	for _, run := range state.runs {
+		if run.Usage == nil {
+			continue
+		}
+		footprint.RunUsage.PromptTokens += run.Usage.PromptTokens
+		footprint.RunUsage.CompletionTokens += run.Usage.CompletionTokens
+		footprint.RunUsage.TotalTokens += run.Usage.TotalTokens
+	}
+
+	if len(state.runs) > 0 {
+		if config, err := models.GetModelConfig(state.runs[len(state.runs)-1].Model); err == nil && config.ContextWindow > 0 {
+			footprint.ContextWindow = config.ContextWindow
+			footprint.ContextUtilization = float64(footprint.MessageTokens) / float64(config.ContextWindow)
+		}
+	}
+
+	messages := state.messages
+	if messages == nil {
+		messages = []models.ThreadMessage{}
+	}
+	runs := state.runs
+	if runs == nil {
+		runs = []models.Run{}
+	}
+
+	c.JSON(http.StatusOK, threadStateResponse{
+		Thread:         state.thread,
+		Messages:       messages,
+		Runs:           runs,
+		TokenFootprint: footprint,
+	})
+}
@@ -0,0 +1,367 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/fixtures"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// weatherTool is an assistant function tool the assistants fixtures call.
+const weatherTool = `{"type":"function","function":{"name":"get_weather","parameters":{"type":"object","properties":{"city":{"type":"string"}}}}}`
+
+// newAssistantFixtures returns a store whose assistants fixtures call
+// get_weather for weather questions and answer anything else directly.
+func newAssistantFixtures(t *testing.T) *fixtures.Store {
+	t.Helper()
+
+	store := fixtures.NewStore()
+	err := store.Add("responses/assistants/weather.yaml", fixtures.FixtureFile{
+		Category: assistantFixtureCategory,
+		Responses: []fixtures.Fixture{
+			{
+				ID:        "weather",
+				Pattern:   "(?i)weather",
+				Content:   "It is sunny in Paris.",
+				ToolCalls: []fixtures.FixtureToolCall{{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+			},
+			{ID: "default", Content: "Hello from the fixture."},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// createThread creates a thread and returns it.
+func createThread(t *testing.T, s *Server, body string) models.Thread {
+	t.Helper()
+
+	rec := serve(s, http.MethodPost, "/v1/threads", body, nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var thread models.Thread
+	decodeJSON(t, rec, &thread)
+	return thread
+}
+
+// createRun starts a run on a thread and returns it.
+func createRun(t *testing.T, s *Server, threadID, body string) models.Run {
+	t.Helper()
+
+	rec := serve(s, http.MethodPost, "/v1/threads/"+threadID+"/runs", body, nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var run models.Run
+	decodeJSON(t, rec, &run)
+	return run
+}
+
+// threadMessages lists a thread's messages, oldest first.
+func threadMessages(t *testing.T, s *Server, threadID string) []models.ThreadMessage {
+	t.Helper()
+
+	rec := serve(s, http.MethodGet, "/v1/threads/"+threadID+"/messages?order=asc", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+
+	var resp struct {
+		Data []models.ThreadMessage `json:"data"`
+	}
+	decodeJSON(t, rec, &resp)
+	return resp.Data
+}
+
+func TestCreateThread(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		status       int
+		wantMessages int
+		wantParam    string
+	}{
+		{name: "empty body", status: http.StatusOK},
+		{name: "with messages", body: `{"messages":[{"role":"user","content":"Hi"},{"role":"assistant","content":"Hello"}],"metadata":{"user":"42"}}`, status: http.StatusOK, wantMessages: 2},
+		{name: "invalid role", body: `{"messages":[{"role":"system","content":"Hi"}]}`, status: http.StatusBadRequest, wantParam: "role"},
+		{name: "malformed body", body: `{"messages":`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{})
+
+			rec := serve(s, http.MethodPost, "/v1/threads", tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var thread models.Thread
+			decodeJSON(t, rec, &thread)
+			if thread.Object != "thread" || thread.Metadata == nil {
+				t.Errorf("object = %q, metadata = %v", thread.Object, thread.Metadata)
+			}
+			expectStatus(t, serve(s, http.MethodGet, "/v1/threads/"+thread.ID, "", nil), http.StatusOK)
+			if got := threadMessages(t, s, thread.ID); len(got) != tt.wantMessages {
+				t.Errorf("thread has %d messages, want %d", len(got), tt.wantMessages)
+			}
+		})
+	}
+}
+
+func TestDeleteThread(t *testing.T) {
+	s := newTestServer(t, Dependencies{})
+	thread := createThread(t, s, "")
+
+	expectStatus(t, serve(s, http.MethodDelete, "/v1/threads/"+thread.ID, "", nil), http.StatusOK)
+	expectStatus(t, serve(s, http.MethodGet, "/v1/threads/"+thread.ID, "", nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodPost, "/v1/threads/"+thread.ID+"/messages", `{"role":"user","content":"Hi"}`, nil), http.StatusNotFound)
+	expectStatus(t, serve(s, http.MethodDelete, "/v1/threads/"+thread.ID, "", nil), http.StatusNotFound)
+}
+
+func TestCreateRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		fixtures      bool
+		assistant     string
+		message       string
+		run           string
+		wantStatus    models.RunStatus
+		wantReply     string
+		wantToolCalls int
+	}{
+		{
+			name:       "fixture reply",
+			fixtures:   true,
+			assistant:  `{"model":"gpt-4o","tools":[` + weatherTool + `]}`,
+			message:    "Hello",
+			wantStatus: models.RunStatusCompleted,
+			wantReply:  "Hello from the fixture.",
+		},
+		{
+			name:          "fixture tool call",
+			fixtures:      true,
+			assistant:     `{"model":"gpt-4o","tools":[` + weatherTool + `]}`,
+			message:       "What's the weather in Paris?",
+			wantStatus:    models.RunStatusRequiresAction,
+			wantToolCalls: 1,
+		},
+		{
+			name:       "tool the assistant does not have",
+			fixtures:   true,
+			assistant:  `{"model":"gpt-4o"}`,
+			message:    "What's the weather in Paris?",
+			wantStatus: models.RunStatusCompleted,
+			wantReply:  "It is sunny in Paris.",
+		},
+		{
+			name:          "tools set on the run",
+			fixtures:      true,
+			assistant:     `{"model":"gpt-4o"}`,
+			message:       "What's the weather in Paris?",
+			run:           `"tools":[` + weatherTool + `],`,
+			wantStatus:    models.RunStatusRequiresAction,
+			wantToolCalls: 1,
+		},
+		{
+			name:       "without fixtures",
+			assistant:  `{"model":"gpt-4o"}`,
+			message:    "Hello",
+			wantStatus: models.RunStatusCompleted,
+			wantReply:  "I understand. Let me help you with that.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := Dependencies{}
+			if tt.fixtures {
+				deps.Fixtures = newAssistantFixtures(t)
+			}
+			s := newTestServer(t, deps)
+			assistant := createAssistant(t, s, tt.assistant)
+			thread := createThread(t, s, `{"messages":[{"role":"user","content":"`+tt.message+`"}]}`)
+
+			run := createRun(t, s, thread.ID, `{`+tt.run+`"assistant_id":"`+assistant.ID+`"}`)
+			if run.Status != tt.wantStatus || run.Model != "gpt-4o" || run.AssistantID != assistant.ID {
+				t.Errorf("status = %q, model = %q, assistant_id = %q", run.Status, run.Model, run.AssistantID)
+			}
+
+			messages := threadMessages(t, s, thread.ID)
+			if tt.wantToolCalls > 0 {
+				if run.RequiredAction == nil || len(run.RequiredAction.SubmitToolOutputs.ToolCalls) != tt.wantToolCalls {
+					t.Fatalf("required_action = %+v, want %d tool calls", run.RequiredAction, tt.wantToolCalls)
+				}
+				if call := run.RequiredAction.SubmitToolOutputs.ToolCalls[0]; call.Function.Name != "get_weather" || call.Function.Arguments != `{"city":"Paris"}` {
+					t.Errorf("tool call = %+v", call.Function)
+				}
+				if len(messages) != 1 {
+					t.Errorf("thread has %d messages while the run requires action, want 1", len(messages))
+				}
+				return
+			}
+
+			if run.Usage == nil || run.Usage.TotalTokens == 0 || run.CompletedAt == nil {
+				t.Errorf("usage = %v, completed_at = %v", run.Usage, run.CompletedAt)
+			}
+			if len(messages) != 2 || messages[1].Text() != tt.wantReply || messages[1].RunID == nil || *messages[1].RunID != run.ID {
+				t.Errorf("messages = %+v, want the reply %q from run %s", messages, tt.wantReply, run.ID)
+			}
+		})
+	}
+}
+
+func TestCreateRunErrors(t *testing.T) {
+	s := newTestServer(t, Dependencies{Fixtures: newAssistantFixtures(t)})
+	assistant := createAssistant(t, s, `{"model":"gpt-4o","tools":[`+weatherTool+`]}`)
+	thread := createThread(t, s, `{"messages":[{"role":"user","content":"What's the weather?"}]}`)
+	createRun(t, s, thread.ID, `{"assistant_id":"`+assistant.ID+`"}`)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "unknown assistant", path: "/v1/threads/" + thread.ID + "/runs", body: `{"assistant_id":"asst_missing"}`, status: http.StatusNotFound},
+		{name: "unknown thread", path: "/v1/threads/thread_missing/runs", body: `{"assistant_id":"` + assistant.ID + `"}`, status: http.StatusNotFound},
+		{name: "active run", path: "/v1/threads/" + thread.ID + "/runs", body: `{"assistant_id":"` + assistant.ID + `"}`, status: http.StatusBadRequest},
+		{name: "message during an active run", path: "/v1/threads/" + thread.ID + "/messages", body: `{"role":"user","content":"Hello?"}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, serve(s, http.MethodPost, tt.path, tt.body, nil), tt.status)
+		})
+	}
+}
+
+func TestSubmitToolOutputs(t *testing.T) {
+	s := newTestServer(t, Dependencies{Fixtures: newAssistantFixtures(t)})
+	assistant := createAssistant(t, s, `{"model":"gpt-4o","tools":[`+weatherTool+`]}`)
+	thread := createThread(t, s, `{"messages":[{"role":"user","content":"What's the weather?"}]}`)
+	run := createRun(t, s, thread.ID, `{"assistant_id":"`+assistant.ID+`"}`)
+	callID := run.RequiredAction.SubmitToolOutputs.ToolCalls[0].ID
+	path := "/v1/threads/" + thread.ID + "/runs/" + run.ID + "/submit_tool_outputs"
+
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		wantParam string
+	}{
+		{name: "no outputs", body: `{"tool_outputs":[]}`, status: http.StatusBadRequest, wantParam: "tool_outputs"},
+		{name: "wrong call", body: `{"tool_outputs":[{"tool_call_id":"call_other","output":"22C"}]}`, status: http.StatusBadRequest, wantParam: "tool_outputs"},
+		{name: "extra call", body: `{"tool_outputs":[{"tool_call_id":"` + callID + `","output":"22C"},{"tool_call_id":"call_other","output":"22C"}]}`, status: http.StatusBadRequest, wantParam: "tool_outputs"},
+		{name: "answered", body: `{"tool_outputs":[{"tool_call_id":"` + callID + `","output":"22C"}]}`, status: http.StatusOK},
+		{name: "already answered", body: `{"tool_outputs":[{"tool_call_id":"` + callID + `","output":"22C"}]}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodPost, path, tt.body, nil)
+			expectStatus(t, rec, tt.status)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("error param = %q, want %q", got, tt.wantParam)
+				}
+			}
+		})
+	}
+
+	rec := serve(s, http.MethodGet, "/v1/threads/"+thread.ID+"/runs/"+run.ID, "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var got models.Run
+	decodeJSON(t, rec, &got)
+	if got.Status != models.RunStatusCompleted || got.RequiredAction != nil {
+		t.Errorf("status = %q, required_action = %+v, want completed", got.Status, got.RequiredAction)
+	}
+	if messages := threadMessages(t, s, thread.ID); len(messages) != 2 || messages[1].Text() != "It is sunny in Paris." {
+		t.Errorf("messages = %+v, want the fixture's reply", messages)
+	}
+}
+
+func TestCancelRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		status  int
+	}{
+		{name: "requires action", message: "What's the weather?", status: http.StatusOK},
+		{name: "completed", message: "Hello", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, Dependencies{Fixtures: newAssistantFixtures(t)})
+			assistant := createAssistant(t, s, `{"model":"gpt-4o","tools":[`+weatherTool+`]}`)
+			thread := createThread(t, s, `{"messages":[{"role":"user","content":"`+tt.message+`"}]}`)
+			run := createRun(t, s, thread.ID, `{"assistant_id":"`+assistant.ID+`"}`)
+
+			rec := serve(s, http.MethodPost, "/v1/threads/"+thread.ID+"/runs/"+run.ID+"/cancel", "", nil)
+			expectStatus(t, rec, tt.status)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var cancelled models.Run
+			decodeJSON(t, rec, &cancelled)
+			if cancelled.Status != models.RunStatusCancelled || cancelled.CancelledAt == nil || cancelled.RequiredAction != nil {
+				t.Errorf("run = %+v, want cancelled", cancelled)
+			}
+			// The thread accepts messages and runs again
+			expectStatus(t, serve(s, http.MethodPost, "/v1/threads/"+thread.ID+"/messages", `{"role":"user","content":"Never mind"}`, nil), http.StatusOK)
+			createRun(t, s, thread.ID, `{"assistant_id":"`+assistant.ID+`"}`)
+		})
+	}
+
+	t.Run("unknown run", func(t *testing.T) {
+		s := newTestServer(t, Dependencies{})
+		thread := createThread(t, s, "")
+		expectStatus(t, serve(s, http.MethodPost, "/v1/threads/"+thread.ID+"/runs/run_missing/cancel", "", nil), http.StatusNotFound)
+	})
+}
+
+func TestGetThreadState(t *testing.T) {
+	s := newTestServer(t, Dependencies{Fixtures: newAssistantFixtures(t)})
+	assistant := createAssistant(t, s, `{"model":"gpt-4o"}`)
+	thread := createThread(t, s, `{"messages":[{"role":"user","content":"Hello"}],"metadata":{"user":"42"}}`)
+
+	rec := serve(s, http.MethodGet, "/_sentra/threads/"+thread.ID+"/state", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	var state threadStateResponse
+	decodeJSON(t, rec, &state)
+	if len(state.Messages) != 1 || len(state.Runs) != 0 || state.TokenFootprint.ContextWindow != 0 {
+		t.Errorf("state before a run = %+v", state)
+	}
+
+	run := createRun(t, s, thread.ID, `{"assistant_id":"`+assistant.ID+`"}`)
+
+	rec = serve(s, http.MethodGet, "/_sentra/threads/"+thread.ID+"/state", "", nil)
+	expectStatus(t, rec, http.StatusOK)
+	decodeJSON(t, rec, &state)
+
+	footprint := state.TokenFootprint
+	if state.Thread.Metadata["user"] != "42" || len(state.Messages) != 2 || len(state.Runs) != 1 {
+		t.Fatalf("state = %+v", state)
+	}
+	if footprint.ByRole["user"] == 0 || footprint.ByRole["assistant"] == 0 || footprint.ByRole["user"]+footprint.ByRole["assistant"] != footprint.MessageTokens {
+		t.Errorf("by_role = %v, message_tokens = %d", footprint.ByRole, footprint.MessageTokens)
+	}
+	config, err := models.GetModelConfig("gpt-4o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if footprint.ContextWindow != config.ContextWindow || footprint.ContextUtilization <= 0 {
+		t.Errorf("context_window = %d, context_utilization = %v", footprint.ContextWindow, footprint.ContextUtilization)
+	}
+	if footprint.RunUsage != *run.Usage {
+		t.Errorf("run_usage = %+v, want %+v", footprint.RunUsage, *run.Usage)
+	}
+
+	expectStatus(t, serve(s, http.MethodGet, "/_sentra/threads/thread_missing/state", "", nil), http.StatusNotFound)
+}
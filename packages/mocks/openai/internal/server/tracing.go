@@ -0,0 +1,49 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file puts every request in an OpenTelemetry span and reports the
+// span in the traceresponse header, so the engine can link the scenario
+// step that made a call to the mock-side trace.
+package server
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+)
+
+// HeaderTraceResponse carries the request's span in W3C trace-context
+// form: "00-<trace id>-<span id>-<flags>". Only set when the span is
+// recorded, i.e. when tracing is enabled and the request was sampled.
+const HeaderTraceResponse = "traceresponse"
+
+// TracingMiddleware starts an http.request span per request. A traceparent
+// sent by the client makes the span its child; handler spans (chat
+// completion, tokenization, ...) nest under it through the request context.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := metrics.TraceHTTPRequest(ctx, c.Request.Method, c.Request.URL.Path)
+		defer span.End()
+
+		if requestID := GetRequestID(c); requestID != "" {
+			span.SetAttributes(metrics.AttrRequestID.String(requestID))
+		}
+
+		if sc := span.SpanContext(); span.IsRecording() && sc.IsValid() {
+			c.Header(HeaderTraceResponse, fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags()))
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(metrics.AttrStatusCode.Int(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+	}
+}
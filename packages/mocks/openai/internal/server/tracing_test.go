@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
+)
+
+// traceResponsePattern matches a sampled W3C trace-context header.
+var traceResponsePattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-01$`)
+
+func TestTracingMiddleware(t *testing.T) {
+	const parentTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name        string
+		sampled     bool
+		traceparent string
+		// wantTraceID is the trace the request span belongs to ("" for a new one)
+		wantTraceID string
+	}{
+		{name: "not recorded"},
+		{name: "new trace", sampled: true},
+		{name: "child of traceparent", sampled: true, traceparent: "00-" + parentTraceID + "-00f067aa0ba902b7-01", wantTraceID: parentTraceID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			sampler := sdktrace.NeverSample()
+			if tt.sampled {
+				sampler = sdktrace.AlwaysSample()
+			}
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+
+			previousTracer, previousPropagator := metrics.Tracer, otel.GetTextMapPropagator()
+			metrics.Tracer = provider.Tracer("test")
+			otel.SetTextMapPropagator(propagation.TraceContext{})
+			t.Cleanup(func() {
+				metrics.Tracer = previousTracer
+				otel.SetTextMapPropagator(previousPropagator)
+			})
+
+			s := newTestServer(t, Dependencies{Fixtures: newGenericFixtures(t, 5)})
+			var headers map[string]string
+			if tt.traceparent != "" {
+				headers = map[string]string{"traceparent": tt.traceparent}
+			}
+			rec := serve(s, http.MethodPost, "/v1/chat/completions", chatBody, headers)
+			expectStatus(t, rec, http.StatusOK)
+
+			header := rec.Header().Get(HeaderTraceResponse)
+			if !tt.sampled {
+				if header != "" {
+					t.Errorf("%s = %q, want none for an unrecorded span", HeaderTraceResponse, header)
+				}
+				return
+			}
+
+			match := traceResponsePattern.FindStringSubmatch(header)
+			if match == nil {
+				t.Fatalf("%s = %q, want a sampled trace-context", HeaderTraceResponse, header)
+			}
+			if tt.wantTraceID != "" && match[1] != tt.wantTraceID {
+				t.Errorf("trace ID = %s, want %s", match[1], tt.wantTraceID)
+			}
+
+			var request sdktrace.ReadOnlySpan
+			for _, span := range recorder.Ended() {
+				if span.Name() == "http.request" {
+					request = span
+				}
+			}
+			if request == nil {
+				t.Fatal("no http.request span was recorded")
+			}
+			if got := request.SpanContext().SpanID().String(); got != match[2] {
+				t.Errorf("span ID = %s, want the header's %s", got, match[2])
+			}
+			attributes := map[string]string{}
+			for _, attribute := range request.Attributes() {
+				attributes[string(attribute.Key)] = attribute.Value.Emit()
+			}
+			if got := attributes[string(metrics.AttrRequestID)]; got != rec.Header().Get(HeaderRequestID) {
+				t.Errorf("request ID attribute = %q, want %q", got, rec.Header().Get(HeaderRequestID))
+			}
+			if got := attributes[string(metrics.AttrStatusCode)]; got != "200" {
+				t.Errorf("status code attribute = %q, want 200", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+// Package server provides the HTTP server for the OpenAI mock.
+// This file implements the usage and costs APIs backed by the usage tracker.
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/pricing"
+)
+
+// Bucket limits for the usage and costs APIs (daily buckets only).
+const (
+	// defaultBucketLimit is the default number of buckets per page
+	defaultBucketLimit = 7
+
+	// maxBucketLimit is the maximum number of buckets per page
+	maxBucketLimit = 31
+
+	// bucketWidth is the only supported bucket width
+	bucketWidth = 24 * time.Hour
+)
+
+// setupUsageRoutes registers the usage and costs APIs.
+func (s *Server) setupUsageRoutes() {
+	if s.tracker == nil {
+		return
+	}
+
+	s.api.GET("/organization/usage/completions", s.handleCompletionsUsage)
+	s.api.GET("/organization/costs", s.handleCosts)
+	s.api.GET("/usage", s.handleLegacyUsage)
+}
+
+// bucketWindow is the parsed time range of a usage/costs request.
+type bucketWindow struct {
+	// starts are the bucket start times on this page
+	starts []time.Time
+
+	// nextPage is the cursor for the next page (nil if none)
+	nextPage *string
+}
+
+// end returns the exclusive end of the last bucket.
+func (w bucketWindow) end() time.Time {
+	if len(w.starts) == 0 {
+		return time.Time{}
+	}
+	return w.starts[len(w.starts)-1].Add(bucketWidth)
+}
+
+// parseBucketWindow parses start_time, end_time, bucket_width, limit and page.
+func parseBucketWindow(c *gin.Context) (bucketWindow, *models.APIError) {
+	param := func(name string) *string { return &name }
+
+	startUnix, err := strconv.ParseInt(c.Query("start_time"), 10, 64)
+	if err != nil {
+		apiErr := models.NewBadRequestError("Missing or invalid 'start_time': expected a Unix timestamp in seconds.", param("start_time"))
+		return bucketWindow{}, &apiErr
+	}
+
+	end := clock.Now()
+	if raw := c.Query("end_time"); raw != "" {
+		endUnix, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			apiErr := models.NewBadRequestError("Invalid 'end_time': expected a Unix timestamp in seconds.", param("end_time"))
+			return bucketWindow{}, &apiErr
+		}
+		end = time.Unix(endUnix, 0)
+	}
+
+	if width := c.DefaultQuery("bucket_width", "1d"); width != "1d" {
+		apiErr := models.NewBadRequestError(fmt.Sprintf("Invalid 'bucket_width': '%s'. The mock only supports '1d'.", width), param("bucket_width"))
+		return bucketWindow{}, &apiErr
+	}
+
+	limit := defaultBucketLimit
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxBucketLimit {
+			apiErr := models.NewBadRequestError(fmt.Sprintf("Invalid 'limit': expected an integer between 1 and %d.", maxBucketLimit), param("limit"))
+			return bucketWindow{}, &apiErr
+		}
+	}
+
+	start := time.Unix(startUnix, 0).UTC().Truncate(bucketWidth)
+
+	// The page cursor is the start of the next bucket
+	if raw := c.Query("page"); raw != "" {
+		pageUnix, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			apiErr := models.NewBadRequestError("Invalid 'page' cursor.", param("page"))
+			return bucketWindow{}, &apiErr
+		}
+		start = time.Unix(pageUnix, 0).UTC()
+	}
+
+	var window bucketWindow
+	for bucket := start; bucket.Before(end); bucket = bucket.Add(bucketWidth) {
+		if len(window.starts) == limit {
+			next := strconv.FormatInt(bucket.Unix(), 10)
+			window.nextPage = &next
+			break
+		}
+		window.starts = append(window.starts, bucket)
+	}
+
+	return window, nil
+}
+
+// queryValues returns a repeated query parameter, accepting both
+// "name=a&name=b" and "name[]=a&name[]=b".
+func queryValues(c *gin.Context, name string) []string {
+	return append(c.QueryArray(name), c.QueryArray(name+"[]")...)
+}
+
+// queryGroupBy returns the requested group_by fields.
+func queryGroupBy(c *gin.Context) map[string]bool {
+	groupBy := make(map[string]bool)
+	for _, field := range queryValues(c, "group_by") {
+		for _, f := range strings.Split(field, ",") {
+			groupBy[strings.TrimSpace(f)] = true
+		}
+	}
+	return groupBy
+}
+
+// dailyUsage fetches the tracker's daily buckets for a window, scoped to the
+// caller's organization when the OpenAI-Organization header is set.
+func (s *Server) dailyUsage(c *gin.Context, window bucketWindow) map[int64][]*pricing.DailyUsage {
+	byBucket := make(map[int64][]*pricing.DailyUsage, len(window.starts))
+	if len(window.starts) == 0 {
+		return byBucket
+	}
+
+	usage := s.tracker.GetDailyUsage(c.Request.Context(), pricing.DailyUsageQuery{
+		Start:        window.starts[0],
+		End:          window.end(),
+		Organization: GetScope(c).Organization,
+		Projects:     queryValues(c, "project_ids"),
+		APIKeyIDs:    queryValues(c, "api_key_ids"),
+		Models:       queryValues(c, "models"),
+	})
+
+	for _, u := range usage {
+		byBucket[u.Day.Unix()] = append(byBucket[u.Day.Unix()], u)
+	}
+
+	return byBucket
+}
+
+// handleCompletionsUsage serves GET /v1/organization/usage/completions.
+func (s *Server) handleCompletionsUsage(c *gin.Context) {
+	window, apiErr := parseBucketWindow(c)
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	groupBy := queryGroupBy(c)
+	byBucket := s.dailyUsage(c, window)
+
+	page := models.CompletionsUsagePage{
+		Object:   models.ObjectPage,
+		Data:     make([]models.CompletionsUsageBucket, 0, len(window.starts)),
+		HasMore:  window.nextPage != nil,
+		NextPage: window.nextPage,
+	}
+
+	for _, start := range window.starts {
+		groups := make(map[string]*models.CompletionsUsageResult)
+		var order []string
+
+		for _, u := range byBucket[start.Unix()] {
+			result := models.CompletionsUsageResult{Object: models.ObjectCompletionsUsageResult}
+			if groupBy["model"] {
+				result.Model = stringPtr(u.Model)
+			}
+			if groupBy["project_id"] && u.Project != "" {
+				result.ProjectID = stringPtr(u.Project)
+			}
+			if groupBy["api_key_id"] {
+				result.APIKeyID = stringPtr(pricing.APIKeyID(u.APIKey))
+			}
+
+			key := derefString(result.Model) + "|" + derefString(result.ProjectID) + "|" + derefString(result.APIKeyID)
+			group, ok := groups[key]
+			if !ok {
+				group = &result
+				groups[key] = group
+				order = append(order, key)
+			}
+
+			group.InputTokens += u.InputTokens
+			group.OutputTokens += u.OutputTokens
+			group.InputCachedTokens += u.CachedInputTokens
+			group.NumModelRequests += u.Requests
+		}
+
+		bucket := models.CompletionsUsageBucket{
+			Object:    models.ObjectBucket,
+			StartTime: start.Unix(),
+			EndTime:   start.Add(bucketWidth).Unix(),
+			Results:   make([]models.CompletionsUsageResult, 0, len(order)),
+		}
+		sort.Strings(order)
+		for _, key := range order {
+			bucket.Results = append(bucket.Results, *groups[key])
+		}
+
+		page.Data = append(page.Data, bucket)
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// handleCosts serves GET /v1/organization/costs.
+// Line items are "<model>, input", "<model>, cached input" and "<model>, output".
+func (s *Server) handleCosts(c *gin.Context) {
+	window, apiErr := parseBucketWindow(c)
+	if apiErr != nil {
+		abortWithError(c, *apiErr)
+		return
+	}
+
+	groupBy := queryGroupBy(c)
+	byBucket := s.dailyUsage(c, window)
+
+	page := models.CostsPage{
+		Object:   models.ObjectPage,
+		Data:     make([]models.CostsBucket, 0, len(window.starts)),
+		HasMore:  window.nextPage != nil,
+		NextPage: window.nextPage,
+	}
+
+	for _, start := range window.starts {
+		groups := make(map[string]*models.CostsResult)
+		var order []string
+
+		add := func(u *pricing.DailyUsage, lineItem string, amount float64) {
+			if amount == 0 {
+				return
+			}
+
+			result := models.CostsResult{
+				Object: models.ObjectCostsResult,
+				Amount: models.CostAmount{Currency: "usd"},
+			}
+			if groupBy["line_item"] {
+				result.LineItem = stringPtr(u.Model + ", " + lineItem)
+			}
+			if groupBy["project_id"] && u.Project != "" {
+				result.ProjectID = stringPtr(u.Project)
+			}
+
+			key := derefString(result.LineItem) + "|" + derefString(result.ProjectID)
+			group, ok := groups[key]
+			if !ok {
+				group = &result
+				groups[key] = group
+				order = append(order, key)
+			}
+			group.Amount.Value += amount
+		}
+
+		for _, u := range byBucket[start.Unix()] {
+			add(u, "input", u.InputCost)
+			add(u, "cached input", u.CachedInputCost)
+			add(u, "output", u.OutputCost)
+		}
+
+		bucket := models.CostsBucket{
+			Object:    models.ObjectBucket,
+			StartTime: start.Unix(),
+			EndTime:   start.Add(bucketWidth).Unix(),
+			Results:   make([]models.CostsResult, 0, len(order)),
+		}
+		sort.Strings(order)
+		for _, key := range order {
+			bucket.Results = append(bucket.Results, *groups[key])
+		}
+
+		page.Data = append(page.Data, bucket)
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// handleLegacyUsage serves GET /v1/usage?date=YYYY-MM-DD.
+func (s *Server) handleLegacyUsage(c *gin.Context) {
+	date, err := time.Parse("2006-01-02", c.Query("date"))
+	if err != nil {
+		param := "date"
+		abortWithError(c, models.NewBadRequestError("Missing or invalid 'date': expected YYYY-MM-DD.", &param))
+		return
+	}
+
+	usage := s.tracker.GetDailyUsage(c.Request.Context(), pricing.DailyUsageQuery{
+		Start:        date,
+		End:          date.Add(bucketWidth),
+		Organization: GetScope(c).Organization,
+	})
+
+	response := models.LegacyUsageResponse{
+		Object:         "list",
+		Data:           make([]models.LegacyUsageEntry, 0, len(usage)),
+		FtData:         []interface{}{},
+		DalleAPIData:   []interface{}{},
+		WhisperAPIData: []interface{}{},
+		TTSAPIData:     []interface{}{},
+	}
+
+	for _, u := range usage {
+		entry := models.LegacyUsageEntry{
+			AggregationTimestamp:  u.Day.Unix(),
+			NRequests:             u.Requests,
+			Operation:             "completion",
+			SnapshotID:            u.Model,
+			NContextTokensTotal:   u.InputTokens,
+			NGeneratedTokensTotal: u.OutputTokens,
+			APIKeyID:              pricing.APIKeyID(u.APIKey),
+		}
+		if u.Project != "" {
+			entry.ProjectID = stringPtr(u.Project)
+		}
+		response.Data = append(response.Data, entry)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// stringPtr returns a pointer to s.
+func stringPtr(s string) *string {
+	return &s
+}
+
+// derefString returns *s, or "" if s is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
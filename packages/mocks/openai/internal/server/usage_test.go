@@ -0,0 +1,247 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestCompletionsUsage(t *testing.T) {
+	s := newTrackedServer(t)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	twoDaysAgo := today.Add(-48 * time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantParam  string
+		// wantBuckets is the number of buckets on the page
+		wantBuckets int
+		// wantResults is the number of results in today's bucket
+		wantResults  int
+		wantRequests int64
+		wantHasMore  bool
+	}{
+		{
+			name:         "ungrouped",
+			query:        fmt.Sprintf("?start_time=%d", twoDaysAgo),
+			wantStatus:   http.StatusOK,
+			wantBuckets:  3,
+			wantResults:  1,
+			wantRequests: 3,
+		},
+		{
+			name:         "by model",
+			query:        fmt.Sprintf("?start_time=%d&group_by=model", twoDaysAgo),
+			wantStatus:   http.StatusOK,
+			wantBuckets:  3,
+			wantResults:  2,
+			wantRequests: 3,
+		},
+		{
+			name:         "by project and api key",
+			query:        fmt.Sprintf("?start_time=%d&group_by[]=project_id&group_by[]=api_key_id", twoDaysAgo),
+			wantStatus:   http.StatusOK,
+			wantBuckets:  3,
+			wantResults:  2,
+			wantRequests: 3,
+		},
+		{
+			name:         "filtered by model",
+			query:        fmt.Sprintf("?start_time=%d&models=gpt-4o-2024-08-06", twoDaysAgo),
+			wantStatus:   http.StatusOK,
+			wantBuckets:  3,
+			wantResults:  1,
+			wantRequests: 1,
+		},
+		{
+			name:        "first page",
+			query:       fmt.Sprintf("?start_time=%d&limit=2", twoDaysAgo),
+			wantStatus:  http.StatusOK,
+			wantBuckets: 2,
+			wantHasMore: true,
+		},
+		{name: "missing start", wantStatus: http.StatusBadRequest, wantParam: "start_time"},
+		{name: "invalid end", query: "?start_time=0&end_time=now", wantStatus: http.StatusBadRequest, wantParam: "end_time"},
+		{name: "hourly buckets", query: "?start_time=0&bucket_width=1h", wantStatus: http.StatusBadRequest, wantParam: "bucket_width"},
+		{name: "limit too high", query: "?start_time=0&limit=32", wantStatus: http.StatusBadRequest, wantParam: "limit"},
+		{name: "invalid page", query: "?start_time=0&page=next", wantStatus: http.StatusBadRequest, wantParam: "page"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, "/v1/organization/usage/completions"+tt.query, "", nil)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantParam != "" {
+				if got := errorParam(t, rec); got != tt.wantParam {
+					t.Errorf("param = %q, want %q", got, tt.wantParam)
+				}
+				return
+			}
+
+			var page models.CompletionsUsagePage
+			decodeJSON(t, rec, &page)
+			if len(page.Data) != tt.wantBuckets {
+				t.Fatalf("got %d buckets, want %d", len(page.Data), tt.wantBuckets)
+			}
+			if page.HasMore != tt.wantHasMore || (page.NextPage != nil) != tt.wantHasMore {
+				t.Errorf("has_more = %v, next_page = %v, want more: %v", page.HasMore, page.NextPage, tt.wantHasMore)
+			}
+			if tt.wantHasMore {
+				return
+			}
+
+			last := page.Data[len(page.Data)-1]
+			if last.StartTime != today.Unix() {
+				t.Fatalf("last bucket starts at %d, want today (%d)", last.StartTime, today.Unix())
+			}
+			if len(last.Results) != tt.wantResults {
+				t.Fatalf("today has %d results, want %d", len(last.Results), tt.wantResults)
+			}
+			var requests int64
+			for _, result := range last.Results {
+				requests += result.NumModelRequests
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			for _, bucket := range page.Data[:len(page.Data)-1] {
+				if len(bucket.Results) != 0 {
+					t.Errorf("bucket %d has %d results, want none", bucket.StartTime, len(bucket.Results))
+				}
+			}
+		})
+	}
+}
+
+func TestCompletionsUsagePages(t *testing.T) {
+	s := newTrackedServer(t)
+	start := time.Now().UTC().Truncate(24 * time.Hour).Add(-4 * 24 * time.Hour).Unix()
+
+	var starts []int64
+	query := fmt.Sprintf("?start_time=%d&limit=2", start)
+	for i := 0; i < 5; i++ {
+		var page models.CompletionsUsagePage
+		decodeJSON(t, serve(s, http.MethodGet, "/v1/organization/usage/completions"+query, "", nil), &page)
+		for _, bucket := range page.Data {
+			starts = append(starts, bucket.StartTime)
+		}
+		if !page.HasMore {
+			break
+		}
+		query = fmt.Sprintf("?start_time=%d&limit=2&page=%s", start, *page.NextPage)
+	}
+
+	if len(starts) != 5 {
+		t.Fatalf("paged through %d buckets, want 5", len(starts))
+	}
+	for i, got := range starts {
+		if want := start + int64(i)*86400; got != want {
+			t.Errorf("bucket %d starts at %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestCosts(t *testing.T) {
+	s := newTrackedServer(t)
+	today := time.Now().UTC().Truncate(24 * time.Hour).Unix()
+
+	tests := []struct {
+		name  string
+		query string
+		// wantLineItems are today's line items ("" when not grouped)
+		wantLineItems []string
+		wantProjects  int
+	}{
+		{name: "ungrouped", wantLineItems: []string{""}},
+		{
+			name:  "by line item",
+			query: "&group_by=line_item",
+			wantLineItems: []string{
+				"gpt-4o-2024-08-06, input", "gpt-4o-2024-08-06, output",
+				"gpt-4o-mini-2024-07-18, input", "gpt-4o-mini-2024-07-18, output",
+			},
+		},
+		{name: "by project", query: "&group_by=project_id", wantLineItems: []string{"", ""}, wantProjects: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, fmt.Sprintf("/v1/organization/costs?start_time=%d%s", today, tt.query), "", nil)
+			expectStatus(t, rec, http.StatusOK)
+
+			var page models.CostsPage
+			decodeJSON(t, rec, &page)
+			if len(page.Data) != 1 {
+				t.Fatalf("got %d buckets, want 1", len(page.Data))
+			}
+
+			results := page.Data[0].Results
+			if len(results) != len(tt.wantLineItems) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.wantLineItems))
+			}
+			projects := 0
+			for i, result := range results {
+				if got := derefString(result.LineItem); got != tt.wantLineItems[i] {
+					t.Errorf("line_item[%d] = %q, want %q", i, got, tt.wantLineItems[i])
+				}
+				if result.ProjectID != nil {
+					projects++
+				}
+				if result.Amount.Currency != "usd" || result.Amount.Value <= 0 {
+					t.Errorf("amount[%d] = %+v, want a positive usd amount", i, result.Amount)
+				}
+			}
+			if projects != tt.wantProjects {
+				t.Errorf("%d results have a project, want %d", projects, tt.wantProjects)
+			}
+		})
+	}
+}
+
+func TestLegacyUsage(t *testing.T) {
+	s := newTrackedServer(t)
+	today := time.Now().UTC().Format("2006-01-02")
+
+	tests := []struct {
+		name        string
+		query       string
+		headers     map[string]string
+		wantStatus  int
+		wantEntries int
+	}{
+		{name: "today", query: "?date=" + today, wantStatus: http.StatusOK, wantEntries: 3},
+		{name: "organization", query: "?date=" + today, headers: map[string]string{models.HeaderOrganization: "org-acme"}, wantStatus: http.StatusOK, wantEntries: 2},
+		{name: "another day", query: "?date=2020-01-01", wantStatus: http.StatusOK},
+		{name: "missing date", wantStatus: http.StatusBadRequest},
+		{name: "invalid date", query: "?date=01/01/2020", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, http.MethodGet, "/v1/usage"+tt.query, "", tt.headers)
+			expectStatus(t, rec, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				if got := errorParam(t, rec); got != "date" {
+					t.Errorf("param = %q, want %q", got, "date")
+				}
+				return
+			}
+
+			var got models.LegacyUsageResponse
+			decodeJSON(t, rec, &got)
+			if len(got.Data) != tt.wantEntries {
+				t.Errorf("got %d entries, want %d", len(got.Data), tt.wantEntries)
+			}
+			for _, entry := range got.Data {
+				if entry.NRequests != 1 || entry.Operation != "completion" {
+					t.Errorf("entry %+v, want one completion", entry)
+				}
+			}
+		})
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/sentra-lab/mocks/openai/internal/clock"
 )
 
 // item represents a stored value with expiration.
@@ -21,19 +24,25 @@ func (i *item) isExpired() bool {
 	if i.expiry.IsZero() {
 		return false // No expiration
 	}
-	return time.Now().After(i.expiry)
+	return clock.Now().After(i.expiry)
 }
 
 // MemoryStore implements Storage using an in-memory map.
 // This is suitable for local development and testing.
-// All operations are thread-safe using a RWMutex.
+// All operations are thread-safe: data is guarded by a RWMutex and hit/miss
+// counters are atomic, since they are updated by readers holding only RLock.
 type MemoryStore struct {
 	data      map[string]*item
 	mu        sync.RWMutex
 	startTime time.Time
-	hitCount  int64
-	missCount int64
+	hitCount  atomic.Int64
+	missCount atomic.Int64
 	closed    bool
+
+	// done stops the cleanup goroutine when the store is closed
+	done      chan struct{}
+	closeOnce sync.Once
+	cleanupWG sync.WaitGroup
 }
 
 // NewMemoryStore creates a new in-memory storage instance.
@@ -41,10 +50,12 @@ func NewMemoryStore() *MemoryStore {
 	store := &MemoryStore{
 		data:      make(map[string]*item),
 		startTime: time.Now(),
+		done:      make(chan struct{}),
 	}
 
-	// Start background cleanup goroutine
-	go store.cleanupExpired()
+	// Start background cleanup goroutine (stopped by Close)
+	store.cleanupWG.Add(1)
+	go store.cleanupExpired(1 * time.Minute)
 
 	return store
 }
@@ -60,16 +71,16 @@ func (m *MemoryStore) Get(ctx context.Context, key string) (interface{}, error)
 
 	item, ok := m.data[key]
 	if !ok {
-		m.missCount++
+		m.missCount.Add(1)
 		return nil, NewStorageError("Get", key, fmt.Errorf("key not found"))
 	}
 
 	if item.isExpired() {
-		m.missCount++
+		m.missCount.Add(1)
 		return nil, NewStorageError("Get", key, fmt.Errorf("key expired"))
 	}
 
-	m.hitCount++
+	m.hitCount.Add(1)
 	return item.value, nil
 }
 
@@ -84,7 +95,7 @@ func (m *MemoryStore) Set(ctx context.Context, key string, value interface{}, tt
 
 	var expiry time.Time
 	if ttl > 0 {
-		expiry = time.Now().Add(ttl)
+		expiry = clock.Now().Add(ttl)
 	}
 
 	m.data[key] = &item{
@@ -138,21 +149,21 @@ func (m *MemoryStore) Increment(ctx context.Context, key string, delta int64) (i
 		return 0, NewStorageError("Increment", key, fmt.Errorf("storage is closed"))
 	}
 
-	item, ok := m.data[key]
-	if !ok || item.isExpired() {
+	existing, ok := m.data[key]
+	if !ok || existing.isExpired() {
 		// Initialize to 0 if key doesn't exist
 		m.data[key] = &item{value: delta, expiry: time.Time{}}
 		return delta, nil
 	}
 
 	// Convert current value to int64
-	current, ok := item.value.(int64)
+	current, ok := existing.value.(int64)
 	if !ok {
 		return 0, NewStorageError("Increment", key, fmt.Errorf("value is not int64"))
 	}
 
 	newValue := current + delta
-	item.value = newValue
+	existing.value = newValue
 
 	return newValue, nil
 }
@@ -171,14 +182,14 @@ func (m *MemoryStore) SetNX(ctx context.Context, key string, value interface{},
 		return false, NewStorageError("SetNX", key, fmt.Errorf("storage is closed"))
 	}
 
-	item, ok := m.data[key]
-	if ok && !item.isExpired() {
+	existing, ok := m.data[key]
+	if ok && !existing.isExpired() {
 		return false, nil // Key already exists
 	}
 
 	var expiry time.Time
 	if ttl > 0 {
-		expiry = time.Now().Add(ttl)
+		expiry = clock.Now().Add(ttl)
 	}
 
 	m.data[key] = &item{
@@ -203,9 +214,9 @@ func (m *MemoryStore) GetMulti(ctx context.Context, keys []string) (map[string]i
 		item, ok := m.data[key]
 		if ok && !item.isExpired() {
 			result[key] = item.value
-			m.hitCount++
+			m.hitCount.Add(1)
 		} else {
-			m.missCount++
+			m.missCount.Add(1)
 		}
 	}
 
@@ -223,7 +234,7 @@ func (m *MemoryStore) SetMulti(ctx context.Context, items map[string]interface{}
 
 	var expiry time.Time
 	if ttl > 0 {
-		expiry = time.Now().Add(ttl)
+		expiry = clock.Now().Add(ttl)
 	}
 
 	for key, value := range items {
@@ -292,7 +303,7 @@ func (m *MemoryStore) Expire(ctx context.Context, key string, ttl time.Duration)
 	}
 
 	if ttl > 0 {
-		item.expiry = time.Now().Add(ttl)
+		item.expiry = clock.Now().Add(ttl)
 	} else {
 		item.expiry = time.Time{} // No expiration
 	}
@@ -322,7 +333,7 @@ func (m *MemoryStore) TTL(ctx context.Context, key string) (time.Duration, error
 		return -2 * time.Second, nil // Already expired
 	}
 
-	return time.Until(item.expiry), nil
+	return clock.Until(item.expiry), nil
 }
 
 // Flush removes all keys.
@@ -335,19 +346,26 @@ func (m *MemoryStore) Flush(ctx context.Context) error {
 	}
 
 	m.data = make(map[string]*item)
-	m.hitCount = 0
-	m.missCount = 0
+	m.hitCount.Store(0)
+	m.missCount.Store(0)
 
 	return nil
 }
 
-// Close releases resources.
+// Close releases resources and stops the cleanup goroutine.
+// It is safe to call Close more than once.
 func (m *MemoryStore) Close() error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.closeOnce.Do(func() {
+		m.mu.Lock()
+		m.closed = true
+		m.data = nil
+		m.mu.Unlock()
 
-	m.closed = true
-	m.data = nil
+		close(m.done)
+	})
+
+	// Wait for the cleanup goroutine to exit so Close leaves nothing running
+	m.cleanupWG.Wait()
 
 	return nil
 }
@@ -370,41 +388,54 @@ func (m *MemoryStore) GetStats(ctx context.Context) (StorageStats, error) {
 	defer m.mu.RUnlock()
 
 	totalKeys := int64(len(m.data))
+	hitCount := m.hitCount.Load()
+	missCount := m.missCount.Load()
 	hitRate := float64(0)
-	if m.hitCount+m.missCount > 0 {
-		hitRate = float64(m.hitCount) / float64(m.hitCount+m.missCount) * 100
+	if hitCount+missCount > 0 {
+		hitRate = float64(hitCount) / float64(hitCount+missCount) * 100
 	}
 
 	return StorageStats{
 		TotalKeys:   totalKeys,
-		HitCount:    m.hitCount,
-		MissCount:   m.missCount,
+		HitCount:    hitCount,
+		MissCount:   missCount,
 		HitRate:     hitRate,
 		MemoryUsage: 0, // Not implemented for memory store
 		Uptime:      time.Since(m.startTime),
 	}, nil
 }
 
-// cleanupExpired periodically removes expired items.
-func (m *MemoryStore) cleanupExpired() {
-	ticker := time.NewTicker(1 * time.Minute)
+// cleanupExpired periodically removes expired items until the store is closed.
+func (m *MemoryStore) cleanupExpired(interval time.Duration) {
+	defer m.cleanupWG.Done()
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		m.mu.Lock()
-		if m.closed {
-			m.mu.Unlock()
+	for {
+		select {
+		case <-m.done:
 			return
+		case <-ticker.C:
+			m.removeExpired()
 		}
+	}
+}
 
-		now := time.Now()
-		for key, item := range m.data {
-			if !item.expiry.IsZero() && now.After(item.expiry) {
-				delete(m.data, key)
-			}
-		}
+// removeExpired deletes all expired items.
+func (m *MemoryStore) removeExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-		m.mu.Unlock()
+	if m.closed {
+		return
+	}
+
+	now := clock.Now()
+	for key, item := range m.data {
+		if !item.expiry.IsZero() && now.After(item.expiry) {
+			delete(m.data, key)
+		}
 	}
 }
 
@@ -465,20 +496,20 @@ func (m *MemoryStore) IncrementTokens(ctx context.Context, key string, tokens fl
 		return 0, NewStorageError("IncrementTokens", key, fmt.Errorf("storage is closed"))
 	}
 
-	item, ok := m.data[key]
-	if !ok || item.isExpired() {
+	existing, ok := m.data[key]
+	if !ok || existing.isExpired() {
 		// Initialize if not exists
 		m.data[key] = &item{value: tokens, expiry: time.Time{}}
 		return tokens, nil
 	}
 
-	current, ok := item.value.(float64)
+	current, ok := existing.value.(float64)
 	if !ok {
 		return 0, NewStorageError("IncrementTokens", key, fmt.Errorf("value is not float64"))
 	}
 
 	newValue := current + tokens
-	item.value = newValue
+	existing.value = newValue
 
 	return newValue, nil
 }
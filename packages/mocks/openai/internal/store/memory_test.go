@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+
+	const workers, ops = 8, 200
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < ops; i++ {
+				key := fmt.Sprintf("key:%d", (w*ops+i)%32)
+				switch i % 4 {
+				case 0:
+					_ = m.Set(ctx, key, i, time.Minute)
+				case 1:
+					_, _ = m.Get(ctx, key)
+				case 2:
+					_ = m.Delete(ctx, key)
+				case 3:
+					_, _ = m.Increment(ctx, "counter", 1)
+				}
+			}
+			_, _ = m.GetStats(ctx)
+		}()
+	}
+	wg.Wait()
+
+	counter, err := m.Get(ctx, "counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(workers * ops / 4); counter != want {
+		t.Errorf("counter = %v, want %d", counter, want)
+	}
+
+	stats, err := m.GetStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every Get is a hit or a miss, including the one above
+	if got, want := stats.HitCount+stats.MissCount, int64(workers*ops/4+1); got != want {
+		t.Errorf("hits + misses = %d, want %d", got, want)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryStoreCloseDuringAccess(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		w := w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("key:%d", w)
+			for i := 0; i < 500; i++ {
+				_ = m.Set(ctx, key, w, 0)
+				_, _ = m.Get(ctx, key)
+				_ = m.Delete(ctx, key)
+			}
+		}()
+	}
+
+	// Concurrent closes are safe and all wait for the cleanup goroutine
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := m.Close(); err != nil {
+		t.Errorf("second Close() = %v", err)
+	}
+}
+
+func TestMemoryStoreClosed(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		op   func() error
+	}{
+		{"Get", func() error { _, err := m.Get(ctx, "key"); return err }},
+		{"Set", func() error { return m.Set(ctx, "key", 1, 0) }},
+		{"Delete", func() error { return m.Delete(ctx, "key") }},
+		{"Exists", func() error { _, err := m.Exists(ctx, "key"); return err }},
+		{"Increment", func() error { _, err := m.Increment(ctx, "key", 1); return err }},
+		{"SetNX", func() error { _, err := m.SetNX(ctx, "key", 1, 0); return err }},
+		{"Flush", func() error { return m.Flush(ctx) }},
+		{"Ping", func() error { return m.Ping(ctx) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.op(); err == nil {
+				t.Errorf("%s on a closed store succeeded", tt.name)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+	t.Cleanup(func() { _ = m.Close() })
+
+	if err := m.Set(ctx, "short", 1, time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set(ctx, "forever", 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if _, err := m.Get(ctx, "short"); err == nil {
+		t.Error("expired key was returned")
+	}
+
+	m.removeExpired()
+	stats, err := m.GetStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalKeys != 1 {
+		t.Errorf("keys after cleanup = %d, want 1", stats.TotalKeys)
+	}
+	if value, err := m.Get(ctx, "forever"); err != nil || value != 2 {
+		t.Errorf("Get(forever) = %v, %v; want 2", value, err)
+	}
+}
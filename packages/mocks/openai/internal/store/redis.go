@@ -6,6 +6,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -335,15 +337,13 @@ func (r *RedisStore) GetStats(ctx context.Context) (StorageStats, error) {
 		return StorageStats{}, NewStorageError("GetStats", "", err)
 	}
 
-	// Parse Redis INFO output (simplified)
-	// In production, you'd parse this more thoroughly
 	stats := StorageStats{
-		TotalKeys:   0, // Would parse from keyspace info
-		HitCount:    0, // Would parse from stats
-		MissCount:   0,
-		HitRate:     0,
-		MemoryUsage: 0, // Would get from INFO memory
-		Uptime:      time.Since(r.startTime),
+		HitCount:  parseInfoInt(info, "keyspace_hits"),
+		MissCount: parseInfoInt(info, "keyspace_misses"),
+		Uptime:    time.Since(r.startTime),
+	}
+	if total := stats.HitCount + stats.MissCount; total > 0 {
+		stats.HitRate = float64(stats.HitCount) / float64(total) * 100
 	}
 
 	// Get total keys
@@ -355,6 +355,22 @@ func (r *RedisStore) GetStats(ctx context.Context) (StorageStats, error) {
 	return stats, nil
 }
 
+// parseInfoInt returns the integer field name from Redis INFO output, or 0.
+func parseInfoInt(info, name string) int64 {
+	for _, line := range strings.Split(info, "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), name+":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+	return 0
+}
+
 // GetTokens retrieves the current token count (for rate limiting).
 func (r *RedisStore) GetTokens(ctx context.Context, key string) (float64, error) {
 	result, err := r.client.Get(ctx, key).Float64()
@@ -383,20 +399,35 @@ func (r *RedisStore) SetTokens(ctx context.Context, key string, tokens float64,
 	return nil
 }
 
+// decrementTokensScript takes tokens only if enough are left. Checking and
+// taking in one step means concurrent callers never see each other's
+// overdraft, as they would with a decrement and a rollback.
+var decrementTokensScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current then
+	return redis.error_reply("key not found")
+end
+if tonumber(current) < tonumber(ARGV[1]) then
+	return false
+end
+return redis.call("INCRBYFLOAT", KEYS[1], -tonumber(ARGV[1]))
+`)
+
 // DecrementTokens atomically decrements tokens.
 func (r *RedisStore) DecrementTokens(ctx context.Context, key string, tokens float64) (float64, error) {
-	result, err := r.client.IncrByFloat(ctx, key, -tokens).Result()
+	result, err := decrementTokensScript.Run(ctx, r.client, []string{key}, tokens).Text()
 	if err != nil {
+		if err == redis.Nil {
+			return 0, NewStorageError("DecrementTokens", key, fmt.Errorf("insufficient tokens"))
+		}
 		return 0, NewStorageError("DecrementTokens", key, err)
 	}
 
-	if result < 0 {
-		// Rollback
-		r.client.IncrByFloat(ctx, key, tokens)
-		return 0, NewStorageError("DecrementTokens", key, fmt.Errorf("insufficient tokens"))
+	left, err := strconv.ParseFloat(result, 64)
+	if err != nil {
+		return 0, NewStorageError("DecrementTokens", key, fmt.Errorf("value is not a number: %w", err))
 	}
-
-	return result, nil
+	return left, nil
 }
 
 // IncrementTokens atomically increments tokens.
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// storages are the implementations the stress tests run against; Redis is
+// served by an in-process miniredis.
+var storages = []struct {
+	name string
+	open func(t *testing.T) RateLimitStorage
+}{
+	{
+		name: "memory",
+		open: func(t *testing.T) RateLimitStorage { return NewMemoryStore() },
+	},
+	{
+		name: "redis",
+		open: func(t *testing.T) RateLimitStorage {
+			config := DefaultRedisConfig()
+			config.Addr = miniredis.RunT(t).Addr()
+			r, err := NewRedisStore(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r
+		},
+	},
+}
+
+func TestStorageConcurrentAccess(t *testing.T) {
+	for _, tt := range storages {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := tt.open(t)
+
+			const workers, ops = 8, 200
+			var claimed atomic.Int64
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				w := w
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < ops; i++ {
+						key := fmt.Sprintf("key:%d", (w*ops+i)%32)
+						switch i % 5 {
+						case 0:
+							if err := s.Set(ctx, key, i, time.Minute); err != nil {
+								t.Error(err)
+							}
+						case 1:
+							_, _ = s.Get(ctx, key)
+						case 2:
+							if err := s.Delete(ctx, key); err != nil {
+								t.Error(err)
+							}
+						case 3:
+							if _, err := s.Increment(ctx, "counter", 1); err != nil {
+								t.Error(err)
+							}
+						case 4:
+							set, err := s.SetNX(ctx, "lock", w, time.Minute)
+							if err != nil {
+								t.Error(err)
+							}
+							if set {
+								claimed.Add(1)
+							}
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			counter, err := s.Increment(ctx, "counter", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := int64(workers * ops / 5); counter != want {
+				t.Errorf("counter = %d, want %d", counter, want)
+			}
+			if got := claimed.Load(); got != 1 {
+				t.Errorf("SetNX(lock) succeeded %d times, want once", got)
+			}
+
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestStorageConcurrentTokens(t *testing.T) {
+	for _, tt := range storages {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := tt.open(t)
+			t.Cleanup(func() { _ = s.Close() })
+
+			const bucket, workers, attempts = 100, 8, 20
+			if err := s.SetTokens(ctx, "bucket", bucket, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			// More callers than tokens: every token is taken exactly once,
+			// and no caller is refused while tokens are left
+			var taken atomic.Int64
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < attempts; i++ {
+						if _, err := s.DecrementTokens(ctx, "bucket", 1); err == nil {
+							taken.Add(1)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := taken.Load(); got != bucket {
+				t.Errorf("tokens taken = %d, want %d", got, bucket)
+			}
+			left, err := s.GetTokens(ctx, "bucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if left != 0 {
+				t.Errorf("tokens left = %v, want 0", left)
+			}
+		})
+	}
+}
+
+func TestStorageDecrementTokens(t *testing.T) {
+	for _, tt := range storages {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := tt.open(t)
+			t.Cleanup(func() { _ = s.Close() })
+
+			if _, err := s.DecrementTokens(ctx, "missing", 1); err == nil {
+				t.Error("DecrementTokens(missing) succeeded")
+			}
+
+			if err := s.SetTokens(ctx, "bucket", 2.5, 0); err != nil {
+				t.Fatal(err)
+			}
+			if left, err := s.DecrementTokens(ctx, "bucket", 1.5); err != nil || left != 1 {
+				t.Errorf("DecrementTokens(1.5) = %v, %v; want 1", left, err)
+			}
+			if _, err := s.DecrementTokens(ctx, "bucket", 2); err == nil {
+				t.Error("DecrementTokens(2) with 1 left succeeded")
+			}
+			if left, err := s.GetTokens(ctx, "bucket"); err != nil || left != 1 {
+				t.Errorf("GetTokens() after a refused decrement = %v, %v; want 1", left, err)
+			}
+		})
+	}
+}
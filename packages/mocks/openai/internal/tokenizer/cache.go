@@ -10,17 +10,26 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/sentra-lab/mocks/openai/internal/metrics"
 	"github.com/sentra-lab/mocks/openai/internal/models"
 	"github.com/sentra-lab/mocks/openai/internal/store"
 )
 
+// tokenCacheType is the cache_type label for token cache metrics.
+const tokenCacheType = "token"
+
 // CachedTokenizer wraps a Tokenizer with caching to improve performance.
-// Cache keys are based on message content + model to ensure accuracy.
+// Lookups go through an in-process LRU first, then the storage backend.
+// Cache keys are based on encoding + content hash, so models sharing an
+// encoding share cache entries.
 type CachedTokenizer struct {
 	// tokenizer is the underlying tokenizer
 	tokenizer *Tokenizer
 
-	// cache is the storage backend for cached token counts
+	// lru is the in-process cache in front of storage
+	lru *lruCache
+
+	// cache is the storage backend for cached token counts (optional)
 	cache store.Storage
 
 	// ttl is the time-to-live for cache entries
@@ -36,7 +45,8 @@ type CachedTokenizerConfig struct {
 	// TTL is the cache entry time-to-live (default: 5 minutes)
 	TTL time.Duration
 
-	// CacheSize is the maximum number of cache entries (for memory store)
+	// CacheSize is the maximum number of entries in the in-process LRU
+	// (0 disables it; storage is still used)
 	CacheSize int
 }
 
@@ -54,8 +64,11 @@ func NewCachedTokenizer(tokenizer *Tokenizer, cache store.Storage, config Cached
 		config.TTL = 5 * time.Minute
 	}
 
+	metrics.SetCacheSize(tokenCacheType, 0)
+
 	return &CachedTokenizer{
 		tokenizer: tokenizer,
+		lru:       newLRUCache(config.CacheSize),
 		cache:     cache,
 		ttl:       config.TTL,
 	}
@@ -64,25 +77,23 @@ func NewCachedTokenizer(tokenizer *Tokenizer, cache store.Storage, config Cached
 // Count counts tokens with caching.
 func (c *CachedTokenizer) Count(ctx context.Context, messages []models.Message, model string) (int, error) {
 	// Generate cache key
-	cacheKey := c.generateCacheKey(messages, model)
+	cacheKey, err := c.generateCacheKey(messages, model)
+	if err != nil {
+		return 0, err
+	}
 
 	// Try to get from cache
-	if cached, err := c.cache.Get(ctx, cacheKey); err == nil {
-		if count, ok := cached.(int64); ok {
-			c.cacheHits.Add(1)
-			return int(count), nil
-		}
+	if count, ok := c.lookup(ctx, cacheKey); ok {
+		return count, nil
 	}
 
 	// Cache miss - count tokens
-	c.cacheMisses.Add(1)
 	count, err := c.tokenizer.Count(ctx, messages, model)
 	if err != nil {
 		return 0, err
 	}
 
-	// Store in cache (ignore errors - cache is best-effort)
-	c.cache.Set(ctx, cacheKey, int64(count), c.ttl)
+	c.store(ctx, cacheKey, count)
 
 	return count, nil
 }
@@ -90,29 +101,62 @@ func (c *CachedTokenizer) Count(ctx context.Context, messages []models.Message,
 // CountText counts tokens in text with caching.
 func (c *CachedTokenizer) CountText(ctx context.Context, text string, model string) (int, error) {
 	// Generate cache key
-	cacheKey := c.generateTextCacheKey(text, model)
+	cacheKey, err := c.generateTextCacheKey(text, model)
+	if err != nil {
+		return 0, err
+	}
 
 	// Try to get from cache
-	if cached, err := c.cache.Get(ctx, cacheKey); err == nil {
-		if count, ok := cached.(int64); ok {
-			c.cacheHits.Add(1)
-			return int(count), nil
-		}
+	if count, ok := c.lookup(ctx, cacheKey); ok {
+		return count, nil
 	}
 
 	// Cache miss - count tokens
-	c.cacheMisses.Add(1)
 	count, err := c.tokenizer.CountText(ctx, text, model)
 	if err != nil {
 		return 0, err
 	}
 
-	// Store in cache
-	c.cache.Set(ctx, cacheKey, int64(count), c.ttl)
+	c.store(ctx, cacheKey, count)
 
 	return count, nil
 }
 
+// lookup returns a cached count from the LRU or, failing that, storage.
+// Storage hits are promoted into the LRU.
+func (c *CachedTokenizer) lookup(ctx context.Context, cacheKey string) (int, bool) {
+	if count, ok := c.lru.Get(cacheKey); ok {
+		c.cacheHits.Add(1)
+		metrics.RecordCacheHit(tokenCacheType)
+		return count, true
+	}
+
+	if c.cache != nil {
+		if cached, err := c.cache.Get(ctx, cacheKey); err == nil {
+			if count, ok := countValue(cached); ok {
+				c.cacheHits.Add(1)
+				metrics.RecordCacheHit(tokenCacheType)
+				metrics.SetCacheSize(tokenCacheType, c.lru.Add(cacheKey, count))
+				return count, true
+			}
+		}
+	}
+
+	c.cacheMisses.Add(1)
+	metrics.RecordCacheMiss(tokenCacheType)
+	return 0, false
+}
+
+// store saves a count in the LRU and storage.
+func (c *CachedTokenizer) store(ctx context.Context, cacheKey string, count int) {
+	metrics.SetCacheSize(tokenCacheType, c.lru.Add(cacheKey, count))
+
+	// Ignore errors - cache is best-effort
+	if c.cache != nil {
+		c.cache.Set(ctx, cacheKey, int64(count), c.ttl)
+	}
+}
+
 // CountWithMetadata counts tokens and returns metadata (with caching).
 func (c *CachedTokenizer) CountWithMetadata(ctx context.Context, messages []models.Message, model string) (TokenCount, error) {
 	// Get cached count
@@ -166,6 +210,7 @@ func (c *CachedTokenizer) GetStats(ctx context.Context) (CounterStats, error) {
 
 	stats.CacheHits = cacheHits
 	stats.CacheMisses = cacheMisses
+	stats.CacheSize = int64(c.lru.Len())
 
 	if total > 0 {
 		stats.CacheHitRate = float64(cacheHits) / float64(total) * 100
@@ -183,6 +228,13 @@ func (c *CachedTokenizer) ResetStats(ctx context.Context) error {
 
 // ClearCache clears all cached token counts.
 func (c *CachedTokenizer) ClearCache(ctx context.Context) error {
+	c.lru.Clear()
+	metrics.SetCacheSize(tokenCacheType, 0)
+
+	if c.cache == nil {
+		return nil
+	}
+
 	// Get all cache keys with token prefix
 	keys, err := c.cache.Keys(ctx, "token:*")
 	if err != nil {
@@ -198,35 +250,65 @@ func (c *CachedTokenizer) ClearCache(ctx context.Context) error {
 }
 
 // generateCacheKey generates a cache key for messages.
-// Key format: token:<hash(messages+model)>
-func (c *CachedTokenizer) generateCacheKey(messages []models.Message, model string) string {
-	h := sha256.New()
+// Key format: token:<encoding>:<hash(messages)>, or
+// token:<encoding>:<model>:<hash(messages)> for messages with images, whose
+// token cost depends on the model and not only on its encoding.
+func (c *CachedTokenizer) generateCacheKey(messages []models.Message, model string) (string, error) {
+	config, err := models.GetModelConfig(model)
+	if err != nil {
+		return "", fmt.Errorf("unknown model: %w", err)
+	}
 
-	// Hash model
-	h.Write([]byte(model))
-	h.Write([]byte(":"))
+	h := sha256.New()
 
 	// Hash messages (role + content)
 	for _, msg := range messages {
 		h.Write([]byte(msg.Role))
 		h.Write([]byte(":"))
 		h.Write([]byte(msg.Content))
+		for _, part := range msg.Parts {
+			if part.ImageURL != nil {
+				h.Write([]byte(part.ImageURL.Detail + ":" + part.ImageURL.URL))
+			}
+		}
 		h.Write([]byte("|"))
 	}
 
-	hash := hex.EncodeToString(h.Sum(nil))
-	return "token:" + hash[:32] // Use first 32 chars of hash
+	hash := hex.EncodeToString(h.Sum(nil))[:32] // Use first 32 chars of hash
+	if models.HasImages(messages) {
+		return "token:" + config.Encoding + ":" + config.ID + ":" + hash, nil
+	}
+	return "token:" + config.Encoding + ":" + hash, nil
 }
 
 // generateTextCacheKey generates a cache key for plain text.
-func (c *CachedTokenizer) generateTextCacheKey(text string, model string) string {
+// Key format: token:text:<encoding>:<hash(text)>
+func (c *CachedTokenizer) generateTextCacheKey(text string, model string) (string, error) {
+	config, err := models.GetModelConfig(model)
+	if err != nil {
+		return "", fmt.Errorf("unknown model: %w", err)
+	}
+
 	h := sha256.New()
-	h.Write([]byte(model))
-	h.Write([]byte(":"))
 	h.Write([]byte(text))
 
 	hash := hex.EncodeToString(h.Sum(nil))
-	return "token:text:" + hash[:32]
+	return "token:text:" + config.Encoding + ":" + hash[:32], nil
+}
+
+// countValue converts a stored count to int.
+// MemoryStore returns int64, while RedisStore returns JSON-decoded float64.
+func countValue(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
 }
 
 // Close releases resources.
@@ -0,0 +1,105 @@
+package tokenizer
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pkoukk/tiktoken-go"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+	"github.com/sentra-lab/mocks/openai/internal/store"
+)
+
+func TestMain(m *testing.M) {
+	// Encode byte by byte rather than download encodings
+	tiktoken.SetBpeLoader(byteBpeLoader{})
+	os.Exit(m.Run())
+}
+
+// byteBpeLoader loads an encoding with one token per byte.
+type byteBpeLoader struct{}
+
+func (byteBpeLoader) LoadTiktokenBpe(string) (map[string]int, error) {
+	ranks := make(map[string]int, 256)
+	for b := 0; b < 256; b++ {
+		ranks[string([]byte{byte(b)})] = b
+	}
+	return ranks, nil
+}
+
+// imageMessages is a prompt with an image.
+var imageMessages = []models.Message{{
+	Role:    "user",
+	Content: "What is in this image?",
+	Parts: []models.ContentPart{
+		{Type: "text", Text: "What is in this image?"},
+		{Type: "image_url", ImageURL: &models.ImageURL{URL: "https://example.com/cat.png", Detail: "high"}},
+	},
+}}
+
+// textMessages is a prompt without images.
+var textMessages = []models.Message{{Role: "user", Content: "What is a cat?"}}
+
+func TestGenerateCacheKey(t *testing.T) {
+	c := NewCachedTokenizer(nil, nil, DefaultCachedTokenizerConfig())
+
+	tests := []struct {
+		name     string
+		messages []models.Message
+		a, b     string
+		shared   bool
+	}{
+		{name: "text shares the encoding's entries", messages: textMessages, a: "gpt-4o", b: "gpt-4o-mini", shared: true},
+		{name: "images are keyed by model", messages: imageMessages, a: "gpt-4o", b: "gpt-4o-mini", shared: false},
+		{name: "images of the same model", messages: imageMessages, a: "gpt-4o-mini", b: "gpt-4o-mini", shared: true},
+		{name: "text of different encodings", messages: textMessages, a: "gpt-4o", b: "gpt-4", shared: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyA, err := c.generateCacheKey(tt.messages, tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keyB, err := c.generateCacheKey(tt.messages, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (keyA == keyB) != tt.shared {
+				t.Errorf("keys %q and %q: shared = %v, want %v", keyA, keyB, keyA == keyB, tt.shared)
+			}
+		})
+	}
+}
+
+func TestCachedTokenizerImageCostsPerModel(t *testing.T) {
+	tok, err := NewTokenizer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := NewCachedTokenizer(tok, store.NewMemoryStore(), DefaultCachedTokenizerConfig())
+	ctx := context.Background()
+
+	// gpt-4o-mini bills images at a higher token cost than gpt-4o, which
+	// shares its encoding; a count cached for one must not serve the other
+	for _, model := range []string{"gpt-4o", "gpt-4o-mini", "gpt-4o"} {
+		want, err := tok.Count(ctx, imageMessages, model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := cached.Count(ctx, imageMessages, model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: cached count = %d, want %d", model, got, want)
+		}
+	}
+
+	if stats, err := cached.GetStats(ctx); err != nil {
+		t.Fatal(err)
+	} else if stats.CacheHits != 1 {
+		t.Errorf("cache hits = %d, want 1", stats.CacheHits)
+	}
+}
@@ -0,0 +1,173 @@
+// Package tokenizer provides token counting using tiktoken.
+// This file implements per-SDK prompt token calibration: SDKs serialize tool
+// definitions and system prompts differently, so the prompt tokens billed
+// for the same logical request vary by client library.
+package tokenizer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+// PromptOverhead is the prompt token adjustment for one SDK. OpenAI-style
+// overheads scale with messages and tool definitions; Anthropic-style
+// overheads are a fixed tool-use system prompt charged whenever tools are
+// present (ToolsPresent).
+type PromptOverhead struct {
+	// PerRequest is added to every request
+	PerRequest int `yaml:"per_request" json:"per_request"`
+
+	// PerMessage is added for each message (role framing, name fields)
+	PerMessage int `yaml:"per_message" json:"per_message"`
+
+	// PerTool is added for each tool or function definition (schema
+	// serialization differences such as generated titles/descriptions)
+	PerTool int `yaml:"per_tool" json:"per_tool"`
+
+	// ToolsPresent is added once when any tools are defined
+	ToolsPresent int `yaml:"tools_present" json:"tools_present"`
+}
+
+// PromptShape describes the parts of a request that overheads apply to.
+type PromptShape struct {
+	// Messages is the number of messages
+	Messages int
+
+	// Tools is the number of tool and function definitions
+	Tools int
+}
+
+// ShapeOf returns the prompt shape of a chat completion request.
+func ShapeOf(req *models.ChatCompletionRequest) PromptShape {
+	return PromptShape{
+		Messages: len(req.Messages),
+		Tools:    len(req.Tools) + len(req.Functions),
+	}
+}
+
+// Apply returns the adjustment for a prompt of the given shape.
+func (o PromptOverhead) Apply(shape PromptShape) int {
+	adjustment := o.PerRequest + o.PerMessage*shape.Messages + o.PerTool*shape.Tools
+	if shape.Tools > 0 {
+		adjustment += o.ToolsPresent
+	}
+	return adjustment
+}
+
+// CalibrationFile is the YAML format for calibration overrides.
+type CalibrationFile struct {
+	// SDKs maps SDK names (see models.SDK*) to overheads
+	SDKs map[string]PromptOverhead `yaml:"sdks"`
+}
+
+// Calibration maps SDK fingerprints to prompt token overheads.
+type Calibration struct {
+	// overheads maps SDK names to overheads
+	overheads map[string]PromptOverhead
+
+	// mu protects overheads
+	mu sync.RWMutex
+}
+
+// NewCalibration creates a calibration table with the default overheads.
+func NewCalibration() *Calibration {
+	return &Calibration{overheads: defaultOverheads()}
+}
+
+// defaultOverheads are measured against the official Python SDK, which
+// serializes requests exactly as documented and so has no overhead.
+func defaultOverheads() map[string]PromptOverhead {
+	return map[string]PromptOverhead{
+		models.SDKOpenAIPython:       {},
+		models.SDKOpenAINode:         {},
+		models.SDKOpenAIGo:           {},
+		models.SDKUnknown:            {},
+		models.SDKOpenAIPythonLegacy: {PerTool: 2},
+		models.SDKGoOpenAI:           {PerTool: 3},
+		// Pydantic/Zod-generated schemas add titles and descriptions
+		models.SDKLangChainPython: {PerMessage: 1, PerTool: 12},
+		models.SDKLangChainJS:     {PerMessage: 1, PerTool: 8},
+	}
+}
+
+// LoadCalibration merges overheads from a YAML file into the table.
+func (c *Calibration) LoadCalibration(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var file CalibrationFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for sdk, overhead := range file.SDKs {
+		if err := c.Set(sdk, overhead); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Set replaces the overhead for an SDK.
+func (c *Calibration) Set(sdk string, overhead PromptOverhead) error {
+	if sdk == "" {
+		return fmt.Errorf("sdk name is required")
+	}
+	if overhead.PerRequest < 0 || overhead.PerMessage < 0 || overhead.PerTool < 0 || overhead.ToolsPresent < 0 {
+		return fmt.Errorf("sdk %s: overheads cannot be negative", sdk)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overheads[sdk] = overhead
+	return nil
+}
+
+// Reset restores the default overheads.
+func (c *Calibration) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overheads = defaultOverheads()
+}
+
+// Overhead returns the overhead for an SDK (zero for unlisted SDKs).
+func (c *Calibration) Overhead(sdk string) PromptOverhead {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.overheads[sdk]
+}
+
+// Adjust returns calibrated prompt tokens for a request from an SDK.
+func (c *Calibration) Adjust(promptTokens int, sdk string, shape PromptShape) int {
+	return promptTokens + c.Overhead(sdk).Apply(shape)
+}
+
+// Table returns a copy of the table, sorted by SDK name.
+func (c *Calibration) Table() []SDKOverhead {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	table := make([]SDKOverhead, 0, len(c.overheads))
+	for sdk, overhead := range c.overheads {
+		table = append(table, SDKOverhead{SDK: sdk, PromptOverhead: overhead})
+	}
+	sort.Slice(table, func(i, j int) bool { return table[i].SDK < table[j].SDK })
+	return table
+}
+
+// SDKOverhead is a calibration table entry.
+type SDKOverhead struct {
+	// SDK is the SDK name
+	SDK string `json:"sdk"`
+
+	PromptOverhead
+}
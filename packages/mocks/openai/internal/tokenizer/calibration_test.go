@@ -0,0 +1,175 @@
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sentra-lab/mocks/openai/internal/models"
+)
+
+func TestPromptOverheadApply(t *testing.T) {
+	overhead := PromptOverhead{PerRequest: 3, PerMessage: 1, PerTool: 12, ToolsPresent: 100}
+
+	tests := []struct {
+		name  string
+		shape PromptShape
+		want  int
+	}{
+		{name: "empty", shape: PromptShape{}, want: 3},
+		{name: "messages", shape: PromptShape{Messages: 4}, want: 7},
+		{name: "tools", shape: PromptShape{Messages: 2, Tools: 2}, want: 3 + 2 + 24 + 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overhead.Apply(tt.shape); got != tt.want {
+				t.Errorf("Apply(%+v) = %d, want %d", tt.shape, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShapeOf(t *testing.T) {
+	req := &models.ChatCompletionRequest{
+		Messages:  []models.Message{{Role: "system"}, {Role: "user"}},
+		Tools:     []models.Tool{{Type: "function"}},
+		Functions: []models.Function{{Name: "legacy"}},
+	}
+
+	want := PromptShape{Messages: 2, Tools: 2}
+	if got := ShapeOf(req); got != want {
+		t.Errorf("ShapeOf() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCalibrationAdjust(t *testing.T) {
+	shape := PromptShape{Messages: 2, Tools: 3}
+
+	tests := []struct {
+		sdk  string
+		want int
+	}{
+		{sdk: models.SDKOpenAIPython, want: 100},
+		{sdk: models.SDKOpenAIPythonLegacy, want: 106},
+		{sdk: models.SDKGoOpenAI, want: 109},
+		{sdk: models.SDKLangChainPython, want: 100 + 2 + 36},
+		{sdk: models.SDKLangChainJS, want: 100 + 2 + 24},
+		{sdk: "unlisted-sdk", want: 100},
+	}
+
+	c := NewCalibration()
+	for _, tt := range tests {
+		t.Run(tt.sdk, func(t *testing.T) {
+			if got := c.Adjust(100, tt.sdk, shape); got != tt.want {
+				t.Errorf("Adjust(100, %q) = %d, want %d", tt.sdk, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalibrationSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		sdk      string
+		overhead PromptOverhead
+		wantErr  bool
+	}{
+		{name: "valid", sdk: "anthropic-python", overhead: PromptOverhead{ToolsPresent: 346}},
+		{name: "missing sdk", sdk: "", overhead: PromptOverhead{PerTool: 1}, wantErr: true},
+		{name: "negative", sdk: "custom", overhead: PromptOverhead{PerMessage: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCalibration()
+			err := c.Set(tt.sdk, tt.overhead)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && c.Overhead(tt.sdk) != tt.overhead {
+				t.Errorf("Overhead(%q) = %+v, want %+v", tt.sdk, c.Overhead(tt.sdk), tt.overhead)
+			}
+		})
+	}
+}
+
+func TestLoadCalibration(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    map[string]PromptOverhead
+		wantErr bool
+	}{
+		{
+			name: "overrides and additions",
+			yaml: "sdks:\n  go-openai:\n    per_tool: 5\n  anthropic-python:\n    tools_present: 346\n",
+			want: map[string]PromptOverhead{
+				models.SDKGoOpenAI:           {PerTool: 5},
+				"anthropic-python":           {ToolsPresent: 346},
+				models.SDKLangChainJS:        {PerMessage: 1, PerTool: 8},
+				models.SDKOpenAIPython:       {},
+				models.SDKOpenAIPythonLegacy: {PerTool: 2},
+			},
+		},
+		{
+			name:    "negative overhead",
+			yaml:    "sdks:\n  go-openai:\n    per_tool: -5\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			yaml:    "sdks: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "calibration.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			c := NewCalibration()
+			err := c.LoadCalibration(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadCalibration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for sdk, want := range tt.want {
+				if got := c.Overhead(sdk); got != want {
+					t.Errorf("Overhead(%q) = %+v, want %+v", sdk, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadCalibrationMissingFile(t *testing.T) {
+	c := NewCalibration()
+	if err := c.LoadCalibration(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadCalibration() error = nil, want an error for a missing file")
+	}
+}
+
+func TestCalibrationResetAndTable(t *testing.T) {
+	c := NewCalibration()
+	if err := c.Set(models.SDKGoOpenAI, PromptOverhead{PerTool: 50}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	c.Reset()
+
+	if got := c.Overhead(models.SDKGoOpenAI); got != (PromptOverhead{PerTool: 3}) {
+		t.Errorf("Overhead after Reset = %+v, want the default", got)
+	}
+
+	table := c.Table()
+	if len(table) != len(defaultOverheads()) {
+		t.Fatalf("Table() has %d entries, want %d", len(table), len(defaultOverheads()))
+	}
+	for i := 1; i < len(table); i++ {
+		if table[i-1].SDK >= table[i].SDK {
+			t.Errorf("Table() not sorted: %q before %q", table[i-1].SDK, table[i].SDK)
+		}
+	}
+}
@@ -68,6 +68,9 @@ type CounterStats struct {
 	// CacheHitRate is the cache hit rate as a percentage (0-100)
 	CacheHitRate float64
 
+	// CacheSize is the number of entries in the in-process token cache
+	CacheSize int64
+
 	// AverageTokensPerMessage is the average number of tokens per message
 	AverageTokensPerMessage float64
 
@@ -39,8 +39,8 @@ func (e *Estimator) Estimate(ctx context.Context, messages []models.Message, max
 		charCount += utf8.RuneCountInString(msg.Role) + 10 // Approximate formatting overhead
 	}
 
-	// Estimate input tokens (character_count / 4)
-	estimatedInput := charCount / 4
+	// Estimate input tokens (character_count / 4, plus images)
+	estimatedInput := charCount/4 + models.ImageTokens(messages, model)
 
 	// Estimate output tokens
 	estimatedOutput := maxTokens
@@ -75,7 +75,7 @@ func (e *Estimator) EstimateInputTokens(ctx context.Context, messages []models.M
 		charCount += utf8.RuneCountInString(msg.Role) + 10
 	}
 
-	estimatedTokens := charCount / 4
+	estimatedTokens := charCount/4 + models.ImageTokens(messages, model)
 
 	// Ensure at least 1 token for non-empty messages
 	if len(messages) > 0 && estimatedTokens == 0 {
@@ -188,7 +188,7 @@ func FastEstimateMessages(messages []models.Message) int {
 	for _, msg := range messages {
 		totalChars += len(msg.Content) + len(msg.Role) + 10
 	}
-	return totalChars / 4
+	return totalChars/4 + models.ImageTokens(messages, "")
 }
 
 // EstimateRateLimitTokens estimates tokens for rate limiting purposes.
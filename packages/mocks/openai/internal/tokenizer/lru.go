@@ -0,0 +1,103 @@
+// Package tokenizer provides token counting.
+// This file implements an in-process LRU cache for token counts.
+package tokenizer
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruCache is a fixed-capacity, least-recently-used cache of token counts.
+// It sits in front of the storage-backed cache so that hot inputs (e.g., a
+// long system prompt sent with every request) are never re-encoded and never
+// cost a storage round trip.
+type lruCache struct {
+	// capacity is the maximum number of entries (0 disables the cache)
+	capacity int
+
+	// order holds entries from most to least recently used
+	order *list.List
+
+	// entries maps keys to their element in order
+	entries map[string]*list.Element
+
+	// mu protects order and entries
+	mu sync.Mutex
+}
+
+// lruEntry is a single cached token count.
+type lruEntry struct {
+	key   string
+	count int
+}
+
+// newLRUCache creates an LRU cache holding at most capacity entries.
+func newLRUCache(capacity int) *lruCache {
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+// Get returns the cached count for key and marks it as recently used.
+func (c *lruCache) Get(key string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return 0, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).count, true
+}
+
+// Add stores a count, evicting the least recently used entry when full.
+// Returns the number of entries after the insert.
+func (c *lruCache) Add(key string, count int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity == 0 {
+		return 0
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).count = count
+		c.order.MoveToFront(elem)
+		return c.order.Len()
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, count: count})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+
+	return c.order.Len()
+}
+
+// Len returns the number of cached entries.
+func (c *lruCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Clear removes all entries.
+func (c *lruCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element, c.capacity)
+}
@@ -0,0 +1,103 @@
+package tokenizer
+
+import "testing"
+
+func TestLRUCache(t *testing.T) {
+	// op is a Get (count < 0) or an Add
+	type op struct {
+		key   string
+		count int
+	}
+
+	tests := []struct {
+		name     string
+		capacity int
+		ops      []op
+		wantHits map[string]int
+		wantMiss []string
+	}{
+		{
+			name:     "within capacity",
+			capacity: 3,
+			ops:      []op{{"a", 1}, {"b", 2}, {"c", 3}},
+			wantHits: map[string]int{"a": 1, "b": 2, "c": 3},
+		},
+		{
+			name:     "evicts least recently added",
+			capacity: 2,
+			ops:      []op{{"a", 1}, {"b", 2}, {"c", 3}},
+			wantHits: map[string]int{"b": 2, "c": 3},
+			wantMiss: []string{"a"},
+		},
+		{
+			name:     "get refreshes recency",
+			capacity: 2,
+			ops:      []op{{"a", 1}, {"b", 2}, {"a", -1}, {"c", 3}},
+			wantHits: map[string]int{"a": 1, "c": 3},
+			wantMiss: []string{"b"},
+		},
+		{
+			name:     "add updates in place",
+			capacity: 2,
+			ops:      []op{{"a", 1}, {"b", 2}, {"a", 10}, {"c", 3}},
+			wantHits: map[string]int{"a": 10, "c": 3},
+			wantMiss: []string{"b"},
+		},
+		{
+			name:     "zero capacity disables the cache",
+			capacity: 0,
+			ops:      []op{{"a", 1}},
+			wantMiss: []string{"a"},
+		},
+		{
+			name:     "negative capacity disables the cache",
+			capacity: -1,
+			ops:      []op{{"a", 1}},
+			wantMiss: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newLRUCache(tt.capacity)
+			for _, o := range tt.ops {
+				if o.count < 0 {
+					c.Get(o.key)
+				} else {
+					c.Add(o.key, o.count)
+				}
+			}
+
+			for key, want := range tt.wantHits {
+				if got, ok := c.Get(key); !ok || got != want {
+					t.Errorf("Get(%q) = (%d, %v), want (%d, true)", key, got, ok, want)
+				}
+			}
+			for _, key := range tt.wantMiss {
+				if got, ok := c.Get(key); ok {
+					t.Errorf("Get(%q) = %d, want a miss", key, got)
+				}
+			}
+			if got := c.Len(); got != len(tt.wantHits) {
+				t.Errorf("Len() = %d, want %d", got, len(tt.wantHits))
+			}
+		})
+	}
+}
+
+func TestLRUCacheClear(t *testing.T) {
+	c := newLRUCache(2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Clear()
+
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() after Clear = %d, want 0", got)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) after Clear hit, want a miss")
+	}
+	if got := c.Add("c", 3); got != 1 {
+		t.Errorf("Add() after Clear = %d entries, want 1", got)
+	}
+}
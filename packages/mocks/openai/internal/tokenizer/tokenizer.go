@@ -19,7 +19,12 @@ type Tokenizer struct {
 	// encodings maps encoding names to tiktoken instances
 	encodings map[string]*tiktoken.Tiktoken
 
-	// mu protects encodings map during lazy loading
+	// loadErrors maps encoding names to the error loading them failed with.
+	// Loading downloads the encoding, so a failure (e.g. offline) is not
+	// retried on every count.
+	loadErrors map[string]error
+
+	// mu protects encodings and loadErrors during lazy loading
 	mu sync.RWMutex
 
 	// stats tracks tokenization statistics
@@ -32,20 +37,26 @@ type Tokenizer struct {
 // Encodings are loaded lazily on first use to optimize startup time.
 func NewTokenizer() (*Tokenizer, error) {
 	return &Tokenizer{
-		encodings: make(map[string]*tiktoken.Tiktoken),
+		encodings:  make(map[string]*tiktoken.Tiktoken),
+		loadErrors: make(map[string]error),
 	}, nil
 }
 
-// getEncoding retrieves or loads a tiktoken encoding.
+// getEncoding retrieves or loads a tiktoken encoding. An encoding that
+// failed to load returns the same error without being loaded again.
 func (t *Tokenizer) getEncoding(encodingName string) (*tiktoken.Tiktoken, error) {
-	// Fast path: check if encoding is already loaded
+	// Fast path: check if encoding is already loaded (or failed to)
 	t.mu.RLock()
 	enc, ok := t.encodings[encodingName]
+	loadErr := t.loadErrors[encodingName]
 	t.mu.RUnlock()
 
 	if ok {
 		return enc, nil
 	}
+	if loadErr != nil {
+		return nil, loadErr
+	}
 
 	// Slow path: load encoding (with lock to prevent duplicate loads)
 	t.mu.Lock()
@@ -55,11 +66,16 @@ func (t *Tokenizer) getEncoding(encodingName string) (*tiktoken.Tiktoken, error)
 	if enc, ok := t.encodings[encodingName]; ok {
 		return enc, nil
 	}
+	if err := t.loadErrors[encodingName]; err != nil {
+		return nil, err
+	}
 
 	// Load the encoding
 	enc, err := tiktoken.GetEncoding(encodingName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load encoding %s: %w", encodingName, err)
+		err = fmt.Errorf("failed to load encoding %s: %w", encodingName, err)
+		t.loadErrors[encodingName] = err
+		return nil, err
 	}
 
 	t.encodings[encodingName] = enc
@@ -83,9 +99,9 @@ func (t *Tokenizer) Count(ctx context.Context, messages []models.Message, model
 	// Format messages with special tokens
 	formatted := t.formatMessages(messages, model)
 
-	// Encode and count tokens
+	// Encode and count tokens; images are priced by size, not encoded
 	tokens := enc.Encode(formatted, nil, nil)
-	tokenCount := len(tokens)
+	tokenCount := len(tokens) + models.ImageTokens(messages, model)
 
 	// Update statistics
 	t.totalCounts.Add(1)
@@ -0,0 +1,39 @@
+package tokenizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// failingBpeLoader fails to load any encoding, counting the attempts.
+type failingBpeLoader struct {
+	loads *int
+}
+
+func (l failingBpeLoader) LoadTiktokenBpe(string) (map[string]int, error) {
+	*l.loads++
+	return nil, errors.New("no network")
+}
+
+func TestGetEncodingRemembersLoadErrors(t *testing.T) {
+	var loads int
+	tiktoken.SetBpeLoader(failingBpeLoader{loads: &loads})
+	t.Cleanup(func() { tiktoken.SetBpeLoader(byteBpeLoader{}) })
+
+	tok, err := NewTokenizer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// r50k_base is not loaded by other tests, so tiktoken has not cached it
+	_, first := tok.getEncoding(tiktoken.MODEL_R50K_BASE)
+	_, second := tok.getEncoding(tiktoken.MODEL_R50K_BASE)
+	if first == nil || second != first {
+		t.Errorf("getEncoding() errors = %v, %v; want the same load error", first, second)
+	}
+	if loads != 1 {
+		t.Errorf("encoding loaded %d times, want 1", loads)
+	}
+}
@@ -35,12 +35,25 @@ func NewClient(baseURL string, apiKey string) *Client {
 		BaseURL: baseURL,
 		APIKey:  apiKey,
 		HTTPClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: newPooledTransport(),
 		},
 		Timeout: 60 * time.Second,
 	}
 }
 
+// newPooledTransport creates a transport that keeps enough idle connections
+// to the mock server for high-RPS load tests. The default transport keeps
+// only 2 idle connections per host, which forces a new TCP connection for
+// most requests under concurrency.
+func newPooledTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 1000
+	transport.MaxIdleConnsPerHost = 1000
+	transport.IdleConnTimeout = 90 * time.Second
+	return transport
+}
+
 // NewClientWithHTTPClient creates a new client with a custom HTTP client.
 func NewClientWithHTTPClient(baseURL string, apiKey string, httpClient *http.Client) *Client {
 	return &Client{
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewClientReusesConnections(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+	}{
+		{name: "sequential", concurrency: 1},
+		{name: "concurrent", concurrency: 16},
+		{name: "high concurrency", concurrency: 128},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each round's requests are held until all of them arrive, so a
+			// round needs concurrency connections at once
+			arrived := make(chan struct{})
+			release := make(chan struct{}, tt.concurrency)
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				arrived <- struct{}{}
+				<-release
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"object":"list","data":[]}`))
+			}))
+
+			var opened atomic.Int64
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					opened.Add(1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			c := NewClient(server.URL, "sk-test")
+			round := func() {
+				t.Helper()
+
+				var wg sync.WaitGroup
+				for i := 0; i < tt.concurrency; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						if _, err := c.ListModels(context.Background()); err != nil {
+							t.Errorf("ListModels() error = %v", err)
+						}
+					}()
+				}
+				for i := 0; i < tt.concurrency; i++ {
+					<-arrived
+				}
+				for i := 0; i < tt.concurrency; i++ {
+					release <- struct{}{}
+				}
+				wg.Wait()
+			}
+
+			round()
+			first := opened.Load()
+			round()
+
+			if reopened := opened.Load() - first; reopened != 0 {
+				t.Errorf("second round opened %d connections, want 0 (first round opened %d)", reopened, first)
+			}
+		})
+	}
+}
+
+func TestNewClientTransport(t *testing.T) {
+	c := NewClient("http://localhost:8080", "")
+
+	transport, ok := c.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Transport = %T, want *http.Transport", c.HTTPClient.Transport)
+	}
+	if transport == http.DefaultTransport {
+		t.Error("Transport is http.DefaultTransport, want a dedicated pool")
+	}
+	if transport.MaxIdleConnsPerHost < 100 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want a pool sized for load tests", transport.MaxIdleConnsPerHost)
+	}
+	if transport.Proxy == nil {
+		t.Error("Proxy = nil, want the default transport's proxy settings")
+	}
+}
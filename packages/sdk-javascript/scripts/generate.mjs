@@ -0,0 +1,38 @@
+// Copies the engine's gRPC contract into proto/ (loaded at runtime by
+// src/engine.ts and shipped in the package) and generates TypeScript types
+// for it into src/generated.
+import { execFileSync } from 'node:child_process';
+import { copyFileSync, existsSync, mkdirSync, readdirSync } from 'node:fs';
+import { dirname, join } from 'node:path';
+import { fileURLToPath } from 'node:url';
+
+const root = join(dirname(fileURLToPath(import.meta.url)), '..');
+const source = join(root, '..', 'engine', 'proto');
+const target = join(root, 'proto');
+
+if (!existsSync(source)) {
+  // Installed from the registry, which ships proto/ and dist/
+  process.exit(0);
+}
+
+mkdirSync(target, { recursive: true });
+const protos = readdirSync(source).filter((name) => name.endsWith('.proto'));
+for (const name of protos) {
+  copyFileSync(join(source, name), join(target, name));
+}
+
+// Must match the loader options in src/engine.ts
+execFileSync(
+  join(root, 'node_modules', '.bin', 'proto-loader-gen-types'),
+  [
+    '--longs=Number',
+    '--enums=String',
+    '--defaults',
+    '--oneofs',
+    '--grpcLib=@grpc/grpc-js',
+    `--includeDirs=${target}`,
+    `--outDir=${join(root, 'src', 'generated')}`,
+    ...protos,
+  ],
+  { stdio: 'inherit' },
+);
@@ -0,0 +1,145 @@
+// Drive scenarios through the engine's gRPC API. A thin layer over the
+// contract in proto/ (copied from packages/engine/proto) and the types
+// generated from it into src/generated: it fills in requests and waits for
+// runs, and returns the messages unchanged.
+
+import { join } from 'node:path';
+
+import * as grpc from '@grpc/grpc-js';
+import * as protoLoader from '@grpc/proto-loader';
+
+import type { ProtoGrpcType } from './generated/engine';
+import type { Event__Output } from './generated/sentra/lab/engine/v1/Event';
+import type { RunStatus__Output } from './generated/sentra/lab/engine/v1/RunStatus';
+import type { SimulationEngineClient } from './generated/sentra/lab/engine/v1/SimulationEngine';
+import type { StartScenarioResponse__Output } from './generated/sentra/lab/engine/v1/StartScenarioResponse';
+
+export type { Event__Output as Event, RunStatus__Output as RunStatus };
+
+/** Where 'sentra lab start' runs the engine */
+export const DEFAULT_ADDRESS = 'localhost:50051';
+
+/** Identifies runs started through this package */
+export const RUNNER = '@sentra-lab/client/0.1.0';
+
+/** States of a finished run */
+export const FINISHED_STATES = [
+  'RUN_STATE_PASSED',
+  'RUN_STATE_FAILED',
+  'RUN_STATE_ERRORED',
+  'RUN_STATE_CANCELLED',
+];
+
+export interface StartOptions {
+  /** Path of a scenario file, relative to projectDir */
+  scenarioPath?: string;
+  /** A scenario document, for scenarios built in code */
+  scenarioYAML?: string;
+  projectDir?: string;
+  variables?: Record<string, string>;
+  environment?: Record<string, string>;
+  timeoutSeconds?: number;
+  labels?: Record<string, string>;
+}
+
+function loadEngine(): ProtoGrpcType {
+  // Must match the options in scripts/generate.mjs
+  const definition = protoLoader.loadSync('engine.proto', {
+    includeDirs: [join(__dirname, '..', 'proto')],
+    longs: Number,
+    enums: String,
+    defaults: true,
+    oneofs: true,
+  });
+  return grpc.loadPackageDefinition(definition) as unknown as ProtoGrpcType;
+}
+
+/** Client for the simulation engine. */
+export class EngineClient {
+  readonly address: string;
+  private readonly client: SimulationEngineClient;
+
+  /** address defaults to SENTRA_ENGINE_ADDRESS, then localhost:50051. */
+  constructor(
+    address?: string,
+    private readonly timeoutMs = 10000,
+  ) {
+    this.address = address ?? process.env.SENTRA_ENGINE_ADDRESS ?? DEFAULT_ADDRESS;
+    const engine = loadEngine().sentra.lab.engine.v1;
+    this.client = new engine.SimulationEngine(this.address, grpc.credentials.createInsecure());
+  }
+
+  close(): void {
+    this.client.close();
+  }
+
+  /** Start a scenario and return without waiting for it. */
+  start(options: StartOptions): Promise<StartScenarioResponse__Output> {
+    if ((options.scenarioPath === undefined) === (options.scenarioYAML === undefined)) {
+      return Promise.reject(new Error('pass exactly one of scenarioPath and scenarioYAML'));
+    }
+
+    const request = {
+      scenario_path: options.scenarioPath,
+      scenario_yaml: options.scenarioYAML,
+      project_dir: options.projectDir ?? '',
+      variables: options.variables ?? {},
+      options: {
+        enable_cost_tracking: true,
+        timeout_seconds: options.timeoutSeconds ?? 0,
+        environment: options.environment ?? {},
+      },
+      runner: RUNNER,
+      labels: options.labels ?? {},
+    };
+    return new Promise((resolve, reject) => {
+      this.client.StartScenario(request, this.deadline(), (err, response) =>
+        err || !response ? reject(err) : resolve(response),
+      );
+    });
+  }
+
+  /** Stream a run's events until it finishes. */
+  async *events(runId: string, afterSequence = 0): AsyncGenerator<Event__Output> {
+    const stream = this.client.StreamEvents({ run_id: runId, after_sequence: afterSequence });
+    for await (const event of stream) {
+      yield event as Event__Output;
+    }
+  }
+
+  status(runId: string): Promise<RunStatus__Output> {
+    return new Promise((resolve, reject) => {
+      this.client.GetRunStatus({ run_id: runId }, this.deadline(), (err, response) =>
+        err || !response ? reject(err) : resolve(response),
+      );
+    });
+  }
+
+  stop(runId: string): Promise<boolean> {
+    return new Promise((resolve, reject) => {
+      this.client.StopRun({ run_id: runId }, this.deadline(), (err, response) =>
+        err || !response ? reject(err) : resolve(response.success),
+      );
+    });
+  }
+
+  /** Wait for a run to finish and return its final status. */
+  async wait(runId: string): Promise<RunStatus__Output> {
+    for await (const event of this.events(runId)) {
+      if (event.type === 'EVENT_TYPE_RUN_FINISHED' && event.final_status) {
+        return event.final_status;
+      }
+    }
+    return this.status(runId);
+  }
+
+  /** Run a scenario file to completion. */
+  async run(scenarioPath: string, options: Omit<StartOptions, 'scenarioPath'> = {}) {
+    const started = await this.start({ ...options, scenarioPath });
+    return this.wait(started.run_id);
+  }
+
+  private deadline(): grpc.CallOptions {
+    return { deadline: new Date(Date.now() + this.timeoutMs) };
+  }
+}
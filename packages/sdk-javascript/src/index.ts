@@ -0,0 +1,17 @@
+// @sentra-lab/client lets agent tests drive Sentra Lab and assert on mock
+// state. The engine client is generated from the engine's gRPC contract;
+// everything else talks to the mocks' HTTP admin API.
+
+export { Lab, ENV_FILE } from './lab';
+export {
+  ADMIN_PREFIX,
+  Costs,
+  MockClient,
+  MockError,
+  RUN_ID_HEADER,
+  requestJSON,
+  responseJSON,
+} from './mocks';
+export type { CostRow, Exchange, RequestFilter } from './mocks';
+export { DEFAULT_ADDRESS, EngineClient, FINISHED_STATES } from './engine';
+export type { Event, RunStatus, StartOptions } from './engine';
@@ -0,0 +1,125 @@
+// Find the running lab's mocks and engine. 'sentra lab start' writes the
+// mocks' addresses to .sentra-lab/env, and 'sentra lab run' passes them to
+// the agent as <MOCK>_API_BASE variables. Lab reads them from the
+// environment first and the file second, so tests work both under
+// 'sentra lab run' and when run directly from the project.
+
+import { readFileSync } from 'node:fs';
+import { join } from 'node:path';
+
+import { EngineClient } from './engine';
+import { MockClient } from './mocks';
+
+/** Where 'sentra lab start' writes the lab's environment */
+export const ENV_FILE = join('.sentra-lab', 'env');
+
+const SUFFIX = '_API_BASE';
+
+/** The mocks (and engine) of one lab project. */
+export class Lab {
+  private readonly mocks = new Map<string, MockClient>();
+  private engineClient?: EngineClient;
+
+  constructor(
+    private readonly bases: Record<string, string>,
+    private readonly engineAddress?: string,
+  ) {}
+
+  /**
+   * Read mock addresses from the environment and the project's
+   * .sentra-lab/env (the environment wins).
+   */
+  static discover(projectDir = '.'): Lab {
+    return new Lab({
+      ...mockBases(readEnvFile(join(projectDir, ENV_FILE))),
+      ...mockBases(process.env),
+    });
+  }
+
+  get mockNames(): string[] {
+    return Object.keys(this.bases).sort();
+  }
+
+  /** The client for a mock, by its lab.yaml name. */
+  mock(name: string): MockClient {
+    const key = identifier(name);
+    const base = this.bases[key];
+    if (base === undefined) {
+      const known = this.mockNames.join(', ') || 'none';
+      throw new Error(
+        `mock '${name}' is not running (found: ${known}); ` +
+          'is it enabled in lab.yaml and the lab started?',
+      );
+    }
+
+    let client = this.mocks.get(key);
+    if (!client) {
+      client = new MockClient(name, base);
+      this.mocks.set(key, client);
+    }
+    return client;
+  }
+
+  get engine(): EngineClient {
+    if (!this.engineClient) {
+      this.engineClient = new EngineClient(this.engineAddress);
+    }
+    return this.engineClient;
+  }
+
+  /** Start capturing on every mock under one run ID. */
+  async startCapture(runId?: string): Promise<string> {
+    const mocks = this.mockNames.map((name) => this.mock(name));
+    if (mocks.length === 0) {
+      throw new Error("no mocks found; start the lab with 'sentra lab start'");
+    }
+
+    const id = await mocks[0].startCapture(runId);
+    await Promise.all(mocks.slice(1).map((mock) => mock.startCapture(id)));
+    return id;
+  }
+
+  close(): void {
+    this.engineClient?.close();
+    this.engineClient = undefined;
+  }
+}
+
+function identifier(name: string): string {
+  // The same mapping 'sentra lab start' uses for variable names
+  return name
+    .toUpperCase()
+    .replace(/[^A-Z0-9]+/g, '_')
+    .replace(/^_+|_+$/g, '')
+    .toLowerCase();
+}
+
+function mockBases(env: Record<string, string | undefined>): Record<string, string> {
+  const bases: Record<string, string> = {};
+  for (const [name, value] of Object.entries(env)) {
+    if (name.endsWith(SUFFIX) && value?.startsWith('http')) {
+      bases[name.slice(0, -SUFFIX.length).toLowerCase()] = value;
+    }
+  }
+  return bases;
+}
+
+function readEnvFile(path: string): Record<string, string> {
+  let content: string;
+  try {
+    content = readFileSync(path, 'utf8');
+  } catch {
+    return {};
+  }
+
+  const env: Record<string, string> = {};
+  for (const raw of content.split('\n')) {
+    const line = raw.trim();
+    const eq = line.indexOf('=');
+    if (!line || line.startsWith('#') || eq < 0) {
+      continue;
+    }
+    env[line.slice(0, eq).trim()] = line.slice(eq + 1);
+  }
+  return env;
+}
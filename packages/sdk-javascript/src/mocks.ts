@@ -0,0 +1,285 @@
+// Read and assert on mock state through the mocks' /_sentra admin API. The
+// mocks record every request/response pair ("exchange") while a capture is
+// active and track what the calls would have cost.
+
+import { randomUUID } from 'node:crypto';
+
+/** Where the mocks serve their admin endpoints */
+export const ADMIN_PREFIX = '/_sentra';
+
+/** Attributes a single request to a run without an active capture */
+export const RUN_ID_HEADER = 'X-Sentra-Run-Id';
+
+/** A mock's admin API could not be reached or returned an error. */
+export class MockError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'MockError';
+  }
+}
+
+/** One captured request/response pair, as returned by the mock. */
+export interface Exchange {
+  seq: number;
+  run_id: string;
+  request_id: string;
+  method: string;
+  path: string;
+  query?: string;
+  request_headers: Record<string, string>;
+  request_body: string;
+  status: number;
+  response_headers: Record<string, string>;
+  response_body: string;
+  /** "utf8", or "base64" when a body is not valid UTF-8 */
+  body_encoding: string;
+  truncated?: boolean;
+  /** Unix milliseconds, on the mock's (possibly virtual) clock */
+  started_at: number;
+  duration_ms: number;
+  processing_ms: number;
+}
+
+/** Cost of the calls in one bucket (day, run, key and model). */
+export interface CostRow {
+  day: string;
+  run_id?: string;
+  model: string;
+  requests: number;
+  input_tokens: number;
+  cached_input_tokens: number;
+  output_tokens: number;
+  total_cost: number;
+}
+
+/** What the calls matching a query would have cost, in USD. */
+export class Costs {
+  constructor(readonly rows: CostRow[]) {}
+
+  get totalCost(): number {
+    return this.rows.reduce((sum, row) => sum + row.total_cost, 0);
+  }
+
+  get requests(): number {
+    return this.rows.reduce((sum, row) => sum + row.requests, 0);
+  }
+
+  get inputTokens(): number {
+    return this.rows.reduce((sum, row) => sum + row.input_tokens, 0);
+  }
+
+  get outputTokens(): number {
+    return this.rows.reduce((sum, row) => sum + row.output_tokens, 0);
+  }
+
+  byModel(): Record<string, number> {
+    const totals: Record<string, number> = {};
+    for (const row of this.rows) {
+      totals[row.model] = (totals[row.model] ?? 0) + row.total_cost;
+    }
+    return totals;
+  }
+}
+
+export interface RequestFilter {
+  /** Exact path, or a prefix when it ends with * */
+  path?: string;
+  method?: string;
+  /** Defaults to the run of the current capture */
+  runId?: string;
+  afterSeq?: number;
+}
+
+/** The request body parsed as JSON. */
+export function requestJSON(exchange: Exchange): unknown {
+  return JSON.parse(decodeBody(exchange.request_body, exchange.body_encoding));
+}
+
+/** The response body parsed as JSON (not for streamed responses). */
+export function responseJSON(exchange: Exchange): unknown {
+  return JSON.parse(decodeBody(exchange.response_body, exchange.body_encoding));
+}
+
+/**
+ * Client for one mock's admin API.
+ *
+ * baseURL is the mock's address; a trailing /v1 (as in OPENAI_API_BASE) is
+ * ignored. runId, once set by startCapture, is the default run for
+ * requests() and costs().
+ */
+export class MockClient {
+  readonly baseURL: string;
+  runId?: string;
+
+  constructor(
+    readonly name: string,
+    baseURL: string,
+    private readonly timeoutMs = 5000,
+  ) {
+    this.baseURL = adminBase(baseURL);
+  }
+
+  /** Start recording exchanges for a run, discarding earlier ones. */
+  async startCapture(runId?: string): Promise<string> {
+    const id = runId ?? `sentra-client-${randomUUID().slice(0, 12)}`;
+    await this.request('PUT', '/capture', undefined, { run_id: id });
+    this.runId = id;
+    return id;
+  }
+
+  /** Stop recording and discard the recorded exchanges. */
+  async stopCapture(): Promise<void> {
+    await this.request('DELETE', '/capture');
+    this.runId = undefined;
+  }
+
+  /** The run being captured, if any. */
+  async activeRun(): Promise<string | undefined> {
+    const status = (await this.request('GET', '/capture')) as { run_id?: string };
+    return status.run_id || undefined;
+  }
+
+  /** Captured exchanges, oldest first. */
+  async requests(filter: RequestFilter = {}): Promise<Exchange[]> {
+    const query: Record<string, string> = { after_seq: String(filter.afterSeq ?? 0) };
+    const runId = filter.runId ?? this.runId;
+    if (runId) {
+      query.run_id = runId;
+    }
+
+    const listing = (await this.request('GET', '/capture/exchanges', query)) as {
+      data?: Exchange[];
+    };
+    return (listing.data ?? []).filter(
+      (exchange) =>
+        pathMatches(filter.path, exchange.path) &&
+        (!filter.method || exchange.method.toUpperCase() === filter.method.toUpperCase()),
+    );
+  }
+
+  /**
+   * Costs of the calls so far, for the current run unless runId is given (or
+   * of every call when there is no run).
+   */
+  async costs(options: { runId?: string; model?: string } = {}): Promise<Costs> {
+    const query: Record<string, string> = {};
+    const runId = options.runId ?? this.runId;
+    if (runId) {
+      query.run_id = runId;
+    }
+    if (options.model) {
+      query.model = options.model;
+    }
+
+    const listing = (await this.request('GET', '/costs', query)) as { data?: CostRow[] };
+    return new Costs(listing.data ?? []);
+  }
+
+  /**
+   * Assert the mock received requests for path (at least one, or exactly
+   * times) and return them.
+   */
+  async assertCalled(
+    path: string,
+    options: { method?: string; times?: number; runId?: string } = {},
+  ): Promise<Exchange[]> {
+    const matched = await this.requests({ path, method: options.method, runId: options.runId });
+    const target = options.method ? `${options.method.toUpperCase()} ${path}` : path;
+
+    if (options.times === undefined && matched.length === 0) {
+      throw new Error(
+        `expected ${this.name} to receive ${target}, got none ` +
+          `(received: ${await this.summary(options.runId)})`,
+      );
+    }
+    if (options.times !== undefined && matched.length !== options.times) {
+      throw new Error(
+        `expected ${this.name} to receive ${target} ${options.times} time(s), ` +
+          `got ${matched.length} (received: ${await this.summary(options.runId)})`,
+      );
+    }
+    return matched;
+  }
+
+  /** Assert the mock received no requests for path. */
+  async assertNotCalled(path: string, options: { method?: string; runId?: string } = {}) {
+    await this.assertCalled(path, { ...options, times: 0 });
+  }
+
+  private async summary(runId?: string): Promise<string> {
+    const exchanges = await this.requests({ runId });
+    if (exchanges.length === 0) {
+      return 'nothing';
+    }
+    return exchanges.map((exchange) => `${exchange.method} ${exchange.path}`).join(', ');
+  }
+
+  private async request(
+    method: string,
+    path: string,
+    query?: Record<string, string>,
+    body?: unknown,
+  ): Promise<unknown> {
+    let url = this.baseURL + ADMIN_PREFIX + path;
+    if (query && Object.keys(query).length > 0) {
+      url += '?' + new URLSearchParams(query).toString();
+    }
+
+    let response: Response;
+    try {
+      response = await fetch(url, {
+        method,
+        headers: body === undefined ? {} : { 'Content-Type': 'application/json' },
+        body: body === undefined ? undefined : JSON.stringify(body),
+        signal: AbortSignal.timeout(this.timeoutMs),
+      });
+    } catch (err) {
+      throw new MockError(
+        `${this.name} is not reachable at ${this.baseURL} ` +
+          `(is 'sentra lab start' running?): ${(err as Error).message}`,
+      );
+    }
+
+    const text = await response.text();
+    if (!response.ok) {
+      throw new MockError(
+        `${this.name}: ${method} ${path} returned ${response.status}: ${errorMessage(text)}`,
+      );
+    }
+    return text ? JSON.parse(text) : {};
+  }
+}
+
+function adminBase(baseURL: string): string {
+  let base = baseURL.replace(/\/+$/, '');
+  if (base.endsWith('/v1')) {
+    base = base.slice(0, -'/v1'.length);
+  }
+  return base;
+}
+
+function pathMatches(pattern: string | undefined, path: string): boolean {
+  if (pattern === undefined) {
+    return true;
+  }
+  if (pattern.endsWith('*')) {
+    return path.startsWith(pattern.slice(0, -1));
+  }
+  return path === pattern;
+}
+
+function decodeBody(body: string, encoding: string): string {
+  return encoding === 'base64' ? Buffer.from(body, 'base64').toString('utf8') : body;
+}
+
+function errorMessage(text: string): string {
+  try {
+    const payload = JSON.parse(text);
+    if (payload.error && typeof payload.error === 'object') {
+      return payload.error.message ?? '';
+    }
+    return String(payload.error ?? text);
+  } catch {
+    return text;
+  }
+}
@@ -0,0 +1,123 @@
+import assert from 'node:assert/strict';
+import { mkdtempSync, mkdirSync, writeFileSync } from 'node:fs';
+import { createServer } from 'node:http';
+import { tmpdir } from 'node:os';
+import { join } from 'node:path';
+import { after, before, test } from 'node:test';
+
+import client from '../dist/index.js';
+
+const { Lab, MockClient, MockError, requestJSON, responseJSON } = client;
+
+const exchanges = [
+  {
+    seq: 1,
+    run_id: 'run-1',
+    method: 'POST',
+    path: '/v1/chat/completions',
+    request_body: '{"model": "gpt-4o-mini"}',
+    status: 200,
+    response_body: '{}',
+    body_encoding: 'utf8',
+  },
+  {
+    seq: 2,
+    run_id: 'run-1',
+    method: 'POST',
+    path: '/v1/embeddings',
+    request_body: 'e30=',
+    status: 200,
+    response_body: 'e30=',
+    body_encoding: 'base64',
+  },
+];
+
+const costs = [
+  { model: 'gpt-4o-mini', run_id: 'run-1', requests: 1, input_tokens: 10, output_tokens: 5, total_cost: 0.25 },
+  { model: 'text-embedding-3-small', run_id: 'run-1', requests: 1, input_tokens: 4, output_tokens: 0, total_cost: 0.5 },
+];
+
+let server;
+let baseURL;
+let activeRun = '';
+
+before(async () => {
+  server = createServer((req, res) => {
+    const url = new URL(req.url, 'http://mock');
+    const reply = (status, payload) => {
+      res.writeHead(status, { 'Content-Type': 'application/json' });
+      res.end(JSON.stringify(payload));
+    };
+
+    if (req.method === 'PUT' && url.pathname === '/_sentra/capture') {
+      let body = '';
+      req.on('data', (chunk) => (body += chunk));
+      req.on('end', () => {
+        activeRun = JSON.parse(body).run_id;
+        reply(200, { active: true, run_id: activeRun });
+      });
+    } else if (url.pathname === '/_sentra/capture') {
+      reply(200, { active: activeRun !== '', run_id: activeRun });
+    } else if (url.pathname === '/_sentra/capture/exchanges') {
+      const runId = url.searchParams.get('run_id');
+      reply(200, { object: 'list', data: exchanges.filter((e) => !runId || e.run_id === runId) });
+    } else if (url.pathname === '/_sentra/costs') {
+      reply(200, { object: 'list', data: costs });
+    } else {
+      reply(404, { error: { message: 'not found' } });
+    }
+  });
+  await new Promise((resolve) => server.listen(0, '127.0.0.1', resolve));
+  baseURL = `http://127.0.0.1:${server.address().port}/v1`;
+});
+
+after(() => server.close());
+
+test('requests and assertions', async () => {
+  const openai = new MockClient('openai', baseURL);
+  assert.equal(await openai.startCapture('run-1'), 'run-1');
+  assert.equal(await openai.activeRun(), 'run-1');
+
+  const received = await openai.requests();
+  assert.deepEqual(
+    received.map((e) => e.path),
+    ['/v1/chat/completions', '/v1/embeddings'],
+  );
+  assert.deepEqual(requestJSON(received[0]), { model: 'gpt-4o-mini' });
+  assert.deepEqual(responseJSON(received[1]), {});
+
+  assert.equal((await openai.requests({ path: '/v1/*', method: 'post' })).length, 2);
+  await openai.assertCalled('/v1/chat/completions', { times: 1 });
+  await openai.assertNotCalled('/v1/images/generations');
+  await assert.rejects(openai.assertCalled('/v1/embeddings', { times: 2 }), /2 time/);
+});
+
+test('costs', async () => {
+  const total = await new MockClient('openai', baseURL).costs({ runId: 'run-1' });
+  assert.equal(total.totalCost, 0.75);
+  assert.equal(total.requests, 2);
+  assert.equal(total.inputTokens, 14);
+  assert.deepEqual(total.byModel(), { 'gpt-4o-mini': 0.25, 'text-embedding-3-small': 0.5 });
+});
+
+test('unreachable mock', async () => {
+  await assert.rejects(new MockClient('openai', 'http://127.0.0.1:1').requests(), MockError);
+});
+
+test('discover', () => {
+  const dir = mkdtempSync(join(tmpdir(), 'sentra-client-'));
+  mkdirSync(join(dir, '.sentra-lab'));
+  writeFileSync(
+    join(dir, '.sentra-lab', 'env'),
+    "# Generated by 'sentra lab start'\n" +
+      'OPENAI_API_BASE=http://localhost:8080/v1\n' +
+      'MY_CRM_API_BASE=http://localhost:8090\n',
+  );
+  process.env.MY_CRM_API_BASE = 'http://localhost:9999';
+
+  const lab = Lab.discover(dir);
+  assert.deepEqual(lab.mockNames, ['my_crm', 'openai']);
+  assert.equal(lab.mock('openai').baseURL, 'http://localhost:8080');
+  assert.equal(lab.mock('my-crm').baseURL, 'http://localhost:9999');
+  assert.throws(() => lab.mock('stripe'), /not running/);
+});
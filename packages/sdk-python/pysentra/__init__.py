@@ -0,0 +1,20 @@
+"""pysentra lets agent tests drive Sentra Lab and assert on mock state.
+
+    from pysentra import Lab
+
+    lab = Lab.discover()
+    lab.start_capture()
+    run_agent()
+    lab.openai.assert_called("/v1/chat/completions", times=2)
+    print(lab.openai.costs().total_cost)
+
+The engine client (pysentra.engine) is generated from the engine's gRPC
+contract; everything else talks to the mocks' HTTP admin API.
+"""
+
+from pysentra.lab import Lab
+from pysentra.mocks import Costs, CostRow, Exchange, MockClient, MockError
+
+__version__ = "0.1.0"
+
+__all__ = ["Lab", "MockClient", "MockError", "Exchange", "Costs", "CostRow"]
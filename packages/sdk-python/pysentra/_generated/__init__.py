@@ -0,0 +1 @@
+"""Engine gRPC stubs, generated from packages/engine/proto at build time."""
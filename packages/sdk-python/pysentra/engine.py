@@ -0,0 +1,126 @@
+"""Drive scenarios through the engine's gRPC API.
+
+A thin layer over the stubs generated from packages/engine/proto (see
+pysentra._generated): it fills in request messages and waits for runs, and
+returns the generated messages unchanged so every field of the contract is
+available::
+
+    with EngineClient() as engine:
+        status = engine.run("scenarios/refund.yaml")
+        assert status.state == RunState.PASSED, status.failures
+"""
+
+from __future__ import annotations
+
+import os
+from typing import Dict, Iterator, Optional
+
+import grpc
+
+from pysentra._generated import engine_pb2, engine_pb2_grpc, events_pb2, state_pb2
+
+# DEFAULT_ADDRESS is where 'sentra lab start' runs the engine
+DEFAULT_ADDRESS = "localhost:50051"
+
+# RUNNER identifies runs started through pysentra
+RUNNER = "pysentra/0.1.0"
+
+
+class RunState:
+    """Run states, as state_pb2.RunState values."""
+
+    QUEUED = state_pb2.RUN_STATE_QUEUED
+    RUNNING = state_pb2.RUN_STATE_RUNNING
+    PASSED = state_pb2.RUN_STATE_PASSED
+    FAILED = state_pb2.RUN_STATE_FAILED
+    ERRORED = state_pb2.RUN_STATE_ERRORED
+    CANCELLED = state_pb2.RUN_STATE_CANCELLED
+
+    FINISHED = (PASSED, FAILED, ERRORED, CANCELLED)
+
+    @staticmethod
+    def name(state: int) -> str:
+        return state_pb2.RunState.Name(state).removeprefix("RUN_STATE_").lower()
+
+
+class EngineClient:
+    """Client for the simulation engine.
+
+    address defaults to SENTRA_ENGINE_ADDRESS, then localhost:50051.
+    """
+
+    def __init__(self, address: Optional[str] = None, timeout: float = 10.0) -> None:
+        self.address = address or os.environ.get("SENTRA_ENGINE_ADDRESS", DEFAULT_ADDRESS)
+        self.timeout = timeout
+        self._channel = grpc.insecure_channel(self.address)
+        self._stub = engine_pb2_grpc.SimulationEngineStub(self._channel)
+
+    def __enter__(self) -> "EngineClient":
+        return self
+
+    def __exit__(self, *exc_info: object) -> None:
+        self.close()
+
+    def close(self) -> None:
+        self._channel.close()
+
+    def ping(self) -> engine_pb2.PingResponse:
+        return self._stub.Ping(engine_pb2.PingRequest(), timeout=self.timeout)
+
+    def start(
+        self,
+        scenario_path: Optional[str] = None,
+        scenario_yaml: Optional[str] = None,
+        project_dir: str = "",
+        variables: Optional[Dict[str, str]] = None,
+        environment: Optional[Dict[str, str]] = None,
+        timeout_seconds: int = 0,
+        labels: Optional[Dict[str, str]] = None,
+    ) -> engine_pb2.StartScenarioResponse:
+        """Start a scenario, given as a file or a YAML document, and return
+        without waiting for it."""
+        if (scenario_path is None) == (scenario_yaml is None):
+            raise ValueError("pass exactly one of scenario_path and scenario_yaml")
+
+        request = engine_pb2.StartScenarioRequest(
+            project_dir=project_dir,
+            variables=variables or {},
+            options=engine_pb2.RunOptions(
+                enable_cost_tracking=True,
+                timeout_seconds=timeout_seconds,
+                environment=environment or {},
+            ),
+            runner=RUNNER,
+            labels=labels or {},
+        )
+        if scenario_path is not None:
+            request.scenario_path = scenario_path
+        else:
+            request.scenario_yaml = scenario_yaml
+        return self._stub.StartScenario(request, timeout=self.timeout)
+
+    def events(self, run_id: str, after_sequence: int = 0) -> Iterator[events_pb2.Event]:
+        """Stream a run's events until it finishes."""
+        request = engine_pb2.StreamEventsRequest(run_id=run_id, after_sequence=after_sequence)
+        yield from self._stub.StreamEvents(request)
+
+    def status(self, run_id: str) -> state_pb2.RunStatus:
+        return self._stub.GetRunStatus(
+            engine_pb2.GetRunStatusRequest(run_id=run_id), timeout=self.timeout
+        )
+
+    def stop(self, run_id: str) -> bool:
+        response = self._stub.StopRun(engine_pb2.StopRunRequest(run_id=run_id), timeout=self.timeout)
+        return response.success
+
+    def wait(self, run_id: str) -> state_pb2.RunStatus:
+        """Wait for a run to finish and return its final status."""
+        for event in self.events(run_id):
+            if event.type == events_pb2.EVENT_TYPE_RUN_FINISHED and event.HasField("final_status"):
+                return event.final_status
+        return self.status(run_id)
+
+    def run(self, scenario_path: str, **kwargs: object) -> state_pb2.RunStatus:
+        """Run a scenario to completion; takes start()'s arguments."""
+        started = self.start(scenario_path=scenario_path, **kwargs)  # type: ignore[arg-type]
+        return self.wait(started.run_id)
@@ -0,0 +1,121 @@
+"""Find the running lab's mocks and engine.
+
+'sentra lab start' writes the mocks' addresses to .sentra-lab/env, and
+'sentra lab run' passes them to the agent as <MOCK>_API_BASE variables. Lab
+reads them from the environment first and the file second, so tests work
+both under 'sentra lab run' and when run directly from the project.
+"""
+
+from __future__ import annotations
+
+import os
+import pathlib
+import re
+from typing import TYPE_CHECKING, Dict, Optional
+
+from pysentra.mocks import MockClient
+
+if TYPE_CHECKING:
+    from pysentra.engine import EngineClient
+
+# ENV_FILE is where 'sentra lab start' writes the lab's environment
+ENV_FILE = pathlib.Path(".sentra-lab") / "env"
+
+_SUFFIX = "_API_BASE"
+
+
+class Lab:
+    """The mocks (and engine) of one lab project."""
+
+    def __init__(self, bases: Dict[str, str], engine_address: Optional[str] = None) -> None:
+        self._bases = bases
+        self._mocks: Dict[str, MockClient] = {}
+        self._engine_address = engine_address
+        self._engine: Optional["EngineClient"] = None
+
+    @classmethod
+    def discover(cls, project_dir: str = ".") -> "Lab":
+        """Read mock addresses from the environment and the project's
+        .sentra-lab/env (the environment wins)."""
+        bases = _mock_bases(_read_env_file(pathlib.Path(project_dir) / ENV_FILE))
+        bases.update(_mock_bases(dict(os.environ)))
+        return cls(bases)
+
+    @property
+    def mock_names(self) -> list:
+        return sorted(self._bases)
+
+    def mock(self, name: str) -> MockClient:
+        """The client for a mock, by its lab.yaml name."""
+        key = _identifier(name)
+        if key not in self._bases:
+            known = ", ".join(self.mock_names) or "none"
+            raise KeyError(
+                f"mock {name!r} is not running (found: {known}); "
+                "is it enabled in lab.yaml and the lab started?"
+            )
+        if key not in self._mocks:
+            self._mocks[key] = MockClient(name, self._bases[key])
+        return self._mocks[key]
+
+    def __getattr__(self, name: str) -> MockClient:
+        # lab.openai, lab.stripe, ...
+        if name.startswith("_"):
+            raise AttributeError(name)
+        try:
+            return self.mock(name)
+        except KeyError as e:
+            raise AttributeError(str(e)) from None
+
+    @property
+    def engine(self) -> "EngineClient":
+        # Imported here so mock assertions work without the gRPC stubs
+        from pysentra.engine import EngineClient
+
+        if self._engine is None:
+            self._engine = EngineClient(self._engine_address)
+        return self._engine
+
+    def start_capture(self, run_id: Optional[str] = None) -> str:
+        """Start capturing on every mock under one run ID."""
+        mocks = [self.mock(name) for name in self.mock_names]
+        if not mocks:
+            raise RuntimeError("no mocks found; start the lab with 'sentra lab start'")
+        run_id = mocks[0].start_capture(run_id)
+        for mock in mocks[1:]:
+            mock.start_capture(run_id)
+        return run_id
+
+    def close(self) -> None:
+        if self._engine is not None:
+            self._engine.close()
+            self._engine = None
+
+
+def _identifier(name: str) -> str:
+    # The same mapping 'sentra lab start' uses for variable names
+    return re.sub(r"[^A-Z0-9]+", "_", name.upper()).strip("_").lower()
+
+
+def _mock_bases(env: Dict[str, str]) -> Dict[str, str]:
+    return {
+        name[: -len(_SUFFIX)].lower(): value
+        for name, value in env.items()
+        if name.endswith(_SUFFIX) and value.startswith("http")
+    }
+
+
+def _read_env_file(path: pathlib.Path) -> Dict[str, str]:
+    try:
+        lines = path.read_text().splitlines()
+    except OSError:
+        return {}
+
+    env = {}
+    for line in lines:
+        line = line.strip()
+        if not line or line.startswith("#") or "=" not in line:
+            continue
+        name, value = line.split("=", 1)
+        env[name.strip()] = value
+    return env
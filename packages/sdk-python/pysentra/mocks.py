@@ -0,0 +1,327 @@
+"""Read and assert on mock state through the mocks' /_sentra admin API.
+
+The mocks record every request/response pair ("exchange") while a capture is
+active and track what the calls would have cost. MockClient exposes both so
+tests can check what the agent actually sent::
+
+    openai = MockClient("openai", "http://localhost:8080")
+    with openai.capture("test-refund-flow"):
+        agent.run("refund order 1234")
+
+    openai.assert_called("/v1/chat/completions", times=1)
+    assert openai.costs().total_cost < 0.05
+"""
+
+from __future__ import annotations
+
+import base64
+import contextlib
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+import uuid
+from dataclasses import dataclass, field
+from typing import Any, Dict, Iterator, List, Optional
+
+# ADMIN_PREFIX is where the mocks serve their admin endpoints
+ADMIN_PREFIX = "/_sentra"
+
+# RUN_ID_HEADER attributes a single request to a run without an active capture
+RUN_ID_HEADER = "X-Sentra-Run-Id"
+
+
+class MockError(Exception):
+    """A mock's admin API could not be reached or returned an error."""
+
+
+@dataclass
+class Exchange:
+    """One captured request/response pair."""
+
+    seq: int
+    run_id: str
+    request_id: str
+    method: str
+    path: str
+    query: str
+    request_headers: Dict[str, str]
+    request_body: str
+    status: int
+    response_headers: Dict[str, str]
+    response_body: str
+    body_encoding: str
+    truncated: bool
+    started_at: int
+    duration_ms: int
+    processing_ms: int
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "Exchange":
+        return cls(
+            seq=data.get("seq", 0),
+            run_id=data.get("run_id", ""),
+            request_id=data.get("request_id", ""),
+            method=data.get("method", ""),
+            path=data.get("path", ""),
+            query=data.get("query", ""),
+            request_headers=data.get("request_headers") or {},
+            request_body=data.get("request_body", ""),
+            status=data.get("status", 0),
+            response_headers=data.get("response_headers") or {},
+            response_body=data.get("response_body", ""),
+            body_encoding=data.get("body_encoding", "utf8"),
+            truncated=data.get("truncated", False),
+            started_at=data.get("started_at", 0),
+            duration_ms=data.get("duration_ms", 0),
+            processing_ms=data.get("processing_ms", 0),
+        )
+
+    def request_bytes(self) -> bytes:
+        return _decode_body(self.request_body, self.body_encoding)
+
+    def response_bytes(self) -> bytes:
+        return _decode_body(self.response_body, self.body_encoding)
+
+    def request_json(self) -> Any:
+        """The request body parsed as JSON."""
+        return json.loads(self.request_bytes())
+
+    def response_json(self) -> Any:
+        """The response body parsed as JSON (not for streamed responses)."""
+        return json.loads(self.response_bytes())
+
+
+@dataclass
+class CostRow:
+    """Cost of the calls in one bucket (day, run, key and model)."""
+
+    day: str
+    run_id: str
+    model: str
+    requests: int
+    input_tokens: int
+    cached_input_tokens: int
+    output_tokens: int
+    total_cost: float
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "CostRow":
+        return cls(
+            day=data.get("day", ""),
+            run_id=data.get("run_id", ""),
+            model=data.get("model", ""),
+            requests=data.get("requests", 0),
+            input_tokens=data.get("input_tokens", 0),
+            cached_input_tokens=data.get("cached_input_tokens", 0),
+            output_tokens=data.get("output_tokens", 0),
+            total_cost=data.get("total_cost", 0.0),
+        )
+
+
+@dataclass
+class Costs:
+    """What the calls matching a query would have cost, in USD."""
+
+    rows: List[CostRow] = field(default_factory=list)
+
+    @property
+    def total_cost(self) -> float:
+        return sum(row.total_cost for row in self.rows)
+
+    @property
+    def requests(self) -> int:
+        return sum(row.requests for row in self.rows)
+
+    @property
+    def input_tokens(self) -> int:
+        return sum(row.input_tokens for row in self.rows)
+
+    @property
+    def output_tokens(self) -> int:
+        return sum(row.output_tokens for row in self.rows)
+
+    def by_model(self) -> Dict[str, float]:
+        totals: Dict[str, float] = {}
+        for row in self.rows:
+            totals[row.model] = totals.get(row.model, 0.0) + row.total_cost
+        return totals
+
+
+class MockClient:
+    """Client for one mock's admin API.
+
+    base_url is the mock's address; a trailing /v1 (as in OPENAI_API_BASE) is
+    ignored. run_id, once set by capture() or start_capture(), is the default
+    run for requests() and costs().
+    """
+
+    def __init__(self, name: str, base_url: str, timeout: float = 5.0) -> None:
+        self.name = name
+        self.base_url = _admin_base(base_url)
+        self.timeout = timeout
+        self.run_id: Optional[str] = None
+
+    def __repr__(self) -> str:
+        return f"MockClient({self.name!r}, {self.base_url!r})"
+
+    def start_capture(self, run_id: Optional[str] = None) -> str:
+        """Start recording exchanges for a run, discarding earlier ones."""
+        run_id = run_id or f"pysentra-{uuid.uuid4().hex[:12]}"
+        self._request("PUT", "/capture", body={"run_id": run_id})
+        self.run_id = run_id
+        return run_id
+
+    def stop_capture(self) -> None:
+        """Stop recording and discard the recorded exchanges."""
+        self._request("DELETE", "/capture")
+        self.run_id = None
+
+    @contextlib.contextmanager
+    def capture(self, run_id: Optional[str] = None) -> Iterator[str]:
+        """Record exchanges for the duration of a with block.
+
+        The exchanges stay readable after the block ends; they are discarded
+        when the next capture starts.
+        """
+        yield self.start_capture(run_id)
+
+    def active_run(self) -> Optional[str]:
+        """The run being captured, if any."""
+        status = self._request("GET", "/capture")
+        return status.get("run_id") or None
+
+    def requests(
+        self,
+        path: Optional[str] = None,
+        method: Optional[str] = None,
+        run_id: Optional[str] = None,
+        after_seq: int = 0,
+    ) -> List[Exchange]:
+        """Captured exchanges, oldest first, optionally filtered by path
+        (exact, or a prefix when it ends with *) and method."""
+        query = {"after_seq": str(after_seq)}
+        run_id = run_id or self.run_id
+        if run_id:
+            query["run_id"] = run_id
+
+        listing = self._request("GET", "/capture/exchanges", query=query)
+        exchanges = [Exchange.from_dict(item) for item in listing.get("data") or []]
+        return [
+            exchange
+            for exchange in exchanges
+            if _path_matches(path, exchange.path)
+            and (method is None or exchange.method.upper() == method.upper())
+        ]
+
+    def costs(self, run_id: Optional[str] = None, model: Optional[str] = None) -> Costs:
+        """Costs of the calls so far, for the current run unless run_id is
+        given (or of every call when there is no run)."""
+        query = {}
+        run_id = run_id or self.run_id
+        if run_id:
+            query["run_id"] = run_id
+        if model:
+            query["model"] = model
+
+        listing = self._request("GET", "/costs", query=query)
+        return Costs([CostRow.from_dict(item) for item in listing.get("data") or []])
+
+    def assert_called(
+        self,
+        path: str,
+        method: Optional[str] = None,
+        times: Optional[int] = None,
+        run_id: Optional[str] = None,
+    ) -> List[Exchange]:
+        """Assert the mock received requests for path (at least one, or
+        exactly times) and return them."""
+        matched = self.requests(path=path, method=method, run_id=run_id)
+        target = f"{method.upper()} {path}" if method else path
+        if times is None and not matched:
+            raise AssertionError(
+                f"expected {self.name} to receive {target}, got none "
+                f"(received: {self._summary(run_id)})"
+            )
+        if times is not None and len(matched) != times:
+            raise AssertionError(
+                f"expected {self.name} to receive {target} {times} time(s), "
+                f"got {len(matched)} (received: {self._summary(run_id)})"
+            )
+        return matched
+
+    def assert_not_called(
+        self, path: str, method: Optional[str] = None, run_id: Optional[str] = None
+    ) -> None:
+        """Assert the mock received no requests for path."""
+        self.assert_called(path, method=method, times=0, run_id=run_id)
+
+    def _summary(self, run_id: Optional[str]) -> str:
+        exchanges = self.requests(run_id=run_id)
+        if not exchanges:
+            return "nothing"
+        return ", ".join(f"{exchange.method} {exchange.path}" for exchange in exchanges)
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        query: Optional[Dict[str, str]] = None,
+        body: Optional[Dict[str, Any]] = None,
+    ) -> Dict[str, Any]:
+        url = self.base_url + ADMIN_PREFIX + path
+        if query:
+            url += "?" + urllib.parse.urlencode(query)
+
+        data = None
+        headers = {"Accept": "application/json"}
+        if body is not None:
+            data = json.dumps(body).encode()
+            headers["Content-Type"] = "application/json"
+
+        request = urllib.request.Request(url, data=data, method=method, headers=headers)
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                content = response.read()
+        except urllib.error.HTTPError as e:
+            raise MockError(f"{self.name}: {method} {path} returned {e.code}: {_error_message(e)}")
+        except urllib.error.URLError as e:
+            raise MockError(
+                f"{self.name} is not reachable at {self.base_url} "
+                f"(is 'sentra lab start' running?): {e.reason}"
+            )
+
+        if not content:
+            return {}
+        return json.loads(content)
+
+
+def _admin_base(base_url: str) -> str:
+    base_url = base_url.rstrip("/")
+    if base_url.endswith("/v1"):
+        base_url = base_url[: -len("/v1")]
+    return base_url
+
+
+def _path_matches(pattern: Optional[str], path: str) -> bool:
+    if pattern is None:
+        return True
+    if pattern.endswith("*"):
+        return path.startswith(pattern[:-1])
+    return path == pattern
+
+
+def _decode_body(body: str, encoding: str) -> bytes:
+    if encoding == "base64":
+        return base64.b64decode(body)
+    return body.encode()
+
+
+def _error_message(error: urllib.error.HTTPError) -> str:
+    try:
+        payload = json.loads(error.read())
+    except (ValueError, OSError):
+        return error.reason
+    if isinstance(payload.get("error"), dict):
+        return payload["error"].get("message", "")
+    return str(payload.get("error", ""))
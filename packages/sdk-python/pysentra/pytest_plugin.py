@@ -0,0 +1,52 @@
+"""pytest fixtures for agent tests, registered automatically when pysentra
+is installed.
+
+    def test_refund(sentra_lab):
+        run_agent("refund order 1234")
+        sentra_lab.openai.assert_called("/v1/chat/completions")
+
+sentra_lab starts a fresh capture on every mock before each test, so each
+test only sees its own requests and costs. Tests using it are skipped when
+the lab is not running, unless --sentra-required is passed.
+"""
+
+from __future__ import annotations
+
+from typing import Iterator
+
+import pytest
+
+from pysentra.lab import Lab
+from pysentra.mocks import MockError
+
+
+def pytest_addoption(parser: pytest.Parser) -> None:
+    group = parser.getgroup("sentra")
+    group.addoption(
+        "--sentra-project",
+        default=".",
+        help="Sentra Lab project directory (default: current directory)",
+    )
+    group.addoption(
+        "--sentra-required",
+        action="store_true",
+        help="Fail instead of skipping tests when the lab is not running",
+    )
+
+
+@pytest.fixture(scope="session")
+def sentra_lab_session(request: pytest.FixtureRequest) -> Iterator[Lab]:
+    lab = Lab.discover(request.config.getoption("--sentra-project"))
+    yield lab
+    lab.close()
+
+
+@pytest.fixture
+def sentra_lab(sentra_lab_session: Lab, request: pytest.FixtureRequest) -> Lab:
+    try:
+        sentra_lab_session.start_capture(f"pytest-{request.node.name}"[:120])
+    except (RuntimeError, MockError) as e:
+        if request.config.getoption("--sentra-required"):
+            pytest.fail(str(e))
+        pytest.skip(str(e))
+    return sentra_lab_session
@@ -0,0 +1,106 @@
+"""Build script for pysentra.
+
+The engine client is generated from the published gRPC contract in
+packages/engine/proto when the package is built; the mock state client is
+plain HTTP and has no generated code.
+"""
+
+import pathlib
+import re
+import shutil
+
+from setuptools import find_packages, setup
+from setuptools.command.build_py import build_py
+from setuptools.command.sdist import sdist
+
+HERE = pathlib.Path(__file__).parent
+PROTO_DIR = HERE.parent / "engine" / "proto"
+GENERATED_DIR = HERE / "pysentra" / "_generated"
+
+
+def generate_protos():
+    """Generate the engine stubs into pysentra/_generated.
+
+    protoc writes flat imports ("import state_pb2"), which only work when the
+    stubs are on sys.path, so they are rewritten as package-relative imports.
+    """
+    if not PROTO_DIR.is_dir():
+        # Building from an sdist, which ships the generated stubs
+        return
+
+    from grpc_tools import protoc
+
+    protos = sorted(str(path) for path in PROTO_DIR.glob("*.proto"))
+    include = pathlib.Path(protoc.__file__).parent / "_proto"
+    status = protoc.main(
+        [
+            "grpc_tools.protoc",
+            f"-I{PROTO_DIR}",
+            f"-I{include}",
+            f"--python_out={GENERATED_DIR}",
+            f"--pyi_out={GENERATED_DIR}",
+            f"--grpc_python_out={GENERATED_DIR}",
+            *protos,
+        ]
+    )
+    if status != 0:
+        raise RuntimeError(f"protoc failed with status {status}")
+
+    for path in GENERATED_DIR.glob("*_pb2*.py"):
+        source = path.read_text()
+        source = re.sub(r"^import (\w+_pb2) as", r"from . import \1 as", source, flags=re.M)
+        path.write_text(source)
+
+    for proto in PROTO_DIR.glob("*.proto"):
+        shutil.copy(proto, GENERATED_DIR / proto.name)
+
+
+class BuildPy(build_py):
+    def run(self):
+        generate_protos()
+        super().run()
+
+
+class Sdist(sdist):
+    def run(self):
+        generate_protos()
+        super().run()
+
+
+setup(
+    name="pysentra",
+    version="0.1.0",
+    description="Drive Sentra Lab scenarios and assert on mock state from Python tests",
+    long_description=(HERE / "README.md").read_text(),
+    long_description_content_type="text/markdown",
+    url="https://github.com/sentra-lab/sentra-lab",
+    license="MIT",
+    packages=find_packages(exclude=["tests"]),
+    package_data={"pysentra._generated": ["*.proto", "*.pyi"]},
+    python_requires=">=3.9",
+    install_requires=[
+        "grpcio>=1.60",
+        "protobuf>=4.25",
+    ],
+    extras_require={
+        "dev": [
+            "grpcio-tools>=1.60",
+            "pytest>=7",
+            "black",
+            "isort",
+            "flake8",
+            "mypy",
+            "twine",
+        ],
+    },
+    entry_points={
+        "pytest11": ["sentra = pysentra.pytest_plugin"],
+    },
+    cmdclass={"build_py": BuildPy, "sdist": Sdist},
+    classifiers=[
+        "Framework :: Pytest",
+        "License :: OSI Approved :: MIT License",
+        "Programming Language :: Python :: 3",
+        "Topic :: Software Development :: Testing",
+    ],
+)
@@ -0,0 +1,128 @@
+import json
+import threading
+from http.server import BaseHTTPRequestHandler, HTTPServer
+from urllib.parse import parse_qs, urlparse
+
+import pytest
+
+from pysentra import Lab, MockClient, MockError
+
+EXCHANGES = [
+    {
+        "seq": 1,
+        "run_id": "run-1",
+        "method": "POST",
+        "path": "/v1/chat/completions",
+        "request_body": '{"model": "gpt-4o-mini"}',
+        "status": 200,
+        "response_body": "{}",
+        "body_encoding": "utf8",
+    },
+    {
+        "seq": 2,
+        "run_id": "run-1",
+        "method": "POST",
+        "path": "/v1/embeddings",
+        "request_body": "e30=",
+        "status": 200,
+        "response_body": "e30=",
+        "body_encoding": "base64",
+    },
+]
+
+COSTS = [
+    {"model": "gpt-4o-mini", "run_id": "run-1", "requests": 1, "input_tokens": 10,
+     "output_tokens": 5, "total_cost": 0.25},
+    {"model": "text-embedding-3-small", "run_id": "run-1", "requests": 1, "input_tokens": 4,
+     "output_tokens": 0, "total_cost": 0.5},
+]
+
+
+class AdminHandler(BaseHTTPRequestHandler):
+    run_id = ""
+
+    def do_GET(self):
+        url = urlparse(self.path)
+        query = parse_qs(url.query)
+        if url.path == "/_sentra/capture/exchanges":
+            data = [e for e in EXCHANGES if query.get("run_id", [e["run_id"]])[0] == e["run_id"]]
+            self.reply(200, {"object": "list", "data": data, "dropped": 0})
+        elif url.path == "/_sentra/costs":
+            self.reply(200, {"object": "list", "data": COSTS})
+        elif url.path == "/_sentra/capture":
+            self.reply(200, {"active": bool(AdminHandler.run_id), "run_id": AdminHandler.run_id})
+        else:
+            self.reply(404, {"error": {"message": "not found"}})
+
+    def do_PUT(self):
+        body = json.loads(self.rfile.read(int(self.headers["Content-Length"])))
+        AdminHandler.run_id = body["run_id"]
+        self.reply(200, {"active": True, "run_id": body["run_id"]})
+
+    def reply(self, status, payload):
+        content = json.dumps(payload).encode()
+        self.send_response(status)
+        self.send_header("Content-Type", "application/json")
+        self.send_header("Content-Length", str(len(content)))
+        self.end_headers()
+        self.wfile.write(content)
+
+    def log_message(self, *args):
+        pass
+
+
+@pytest.fixture
+def mock_url():
+    server = HTTPServer(("127.0.0.1", 0), AdminHandler)
+    thread = threading.Thread(target=server.serve_forever, daemon=True)
+    thread.start()
+    yield f"http://127.0.0.1:{server.server_port}/v1"
+    server.shutdown()
+
+
+def test_requests_and_assertions(mock_url):
+    openai = MockClient("openai", mock_url)
+    assert openai.start_capture("run-1") == "run-1"
+    assert openai.active_run() == "run-1"
+
+    exchanges = openai.requests()
+    assert [e.path for e in exchanges] == ["/v1/chat/completions", "/v1/embeddings"]
+    assert exchanges[0].request_json() == {"model": "gpt-4o-mini"}
+    assert exchanges[1].response_json() == {}
+
+    assert len(openai.requests(path="/v1/*", method="post")) == 2
+    openai.assert_called("/v1/chat/completions", times=1)
+    openai.assert_not_called("/v1/images/generations")
+    with pytest.raises(AssertionError, match="2 time"):
+        openai.assert_called("/v1/embeddings", times=2)
+
+
+def test_costs(mock_url):
+    costs = MockClient("openai", mock_url).costs(run_id="run-1")
+    assert costs.total_cost == pytest.approx(0.75)
+    assert costs.requests == 2
+    assert costs.input_tokens == 14
+    assert costs.by_model() == {"gpt-4o-mini": 0.25, "text-embedding-3-small": 0.5}
+
+
+def test_errors():
+    with pytest.raises(MockError, match="not reachable"):
+        MockClient("openai", "http://127.0.0.1:1").requests()
+
+
+def test_discover(tmp_path, monkeypatch):
+    env = tmp_path / ".sentra-lab" / "env"
+    env.parent.mkdir()
+    env.write_text(
+        "# Generated by 'sentra lab start'\n"
+        "OPENAI_API_BASE=http://localhost:8080/v1\n"
+        "MY_CRM_API_BASE=http://localhost:8090\n"
+    )
+    monkeypatch.setenv("MY_CRM_API_BASE", "http://localhost:9999")
+
+    lab = Lab.discover(str(tmp_path))
+    assert lab.mock_names == ["my_crm", "openai"]
+    assert lab.openai.base_url == "http://localhost:8080"
+    assert lab.mock("my-crm").base_url == "http://localhost:9999"
+    with pytest.raises(KeyError):
+        lab.mock("stripe")